@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "List command aliases defined in the workspace config",
+	Long: `Aliases bake long, repetitive invocations into a short name. Define them in
+.ga4.yaml in the working directory (or the file named by $` + config.WorkspaceEnv + `):
+
+  aliases:
+    weekly: "gsc analytics run --config configs/site.yaml --days 7 --format markdown"
+    pricing: "gsc inspect url --site sc-domain:example.com --url https://example.com/pricing --save"
+
+Run an alias like any command. Extra arguments are appended, so flags given
+on the command line override the baked-in ones:
+
+  ga4 weekly
+  ga4 weekly --days 28
+
+Built-in commands always win: an alias named like one is ignored.`,
+	Args: cobra.NoArgs,
+	RunE: runAliasList,
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	ws, err := config.LoadWorkspaceConfig("")
+	if err != nil {
+		return err
+	}
+	if len(ws.Aliases) == 0 {
+		theme.Yellow("No aliases defined. Add an aliases: map to %s.", config.DefaultWorkspaceFile)
+		return nil
+	}
+	names := make([]string, 0, len(ws.Aliases))
+	for name := range ws.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		note := ""
+		if isBuiltinCommand(name) {
+			note = theme.YellowString("  (shadowed by built-in command)")
+		}
+		theme.Printf("%-16s ga4 %s%s\n", name, ws.Aliases[name], note)
+	}
+	return nil
+}
+
+// expandAlias rewrites args when their command word names a workspace alias.
+// Leading persistent flags (e.g. --theme plain-ascii) are kept in front.
+// Expansion happens once: an alias cannot refer to another alias.
+func expandAlias(args []string, aliases map[string]string) ([]string, error) {
+	i := commandWordIndex(args)
+	if i < 0 {
+		return args, nil
+	}
+	line, ok := aliases[args[i]]
+	if !ok || isBuiltinCommand(args[i]) {
+		return args, nil
+	}
+	words, err := config.SplitCommandLine(line)
+	if err != nil {
+		return nil, fmt.Errorf("alias %q: %w", args[i], err)
+	}
+	if len(words) > 0 && words[0] == "ga4" {
+		words = words[1:]
+	}
+	out := make([]string, 0, len(args)+len(words))
+	out = append(out, args[:i]...)
+	out = append(out, words...)
+	return append(out, args[i+1:]...), nil
+}
+
+// commandWordIndex returns the position of the first non-flag argument,
+// skipping the values of leading non-boolean persistent flags.
+func commandWordIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(a, "-") {
+			return i
+		}
+		if strings.Contains(a, "=") {
+			continue
+		}
+		var name string
+		if strings.HasPrefix(a, "--") {
+			name = strings.TrimPrefix(a, "--")
+		}
+		f := rootCmd.PersistentFlags().Lookup(name)
+		if f == nil && len(a) == 2 {
+			f = rootCmd.PersistentFlags().ShorthandLookup(a[1:])
+		}
+		if f != nil && f.Value.Type() != "bool" {
+			i++
+		}
+	}
+	return -1
+}
+
+func isBuiltinCommand(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	// help is added by cobra only once Execute runs.
+	return name == "help" || strings.HasPrefix(name, "__")
+}
+
+// applyWorkspaceAliases expands os.Args through the workspace aliases before
+// cobra parses them. A broken workspace file only matters when an alias is
+// actually used, so load errors are reported but do not block built-ins.
+func applyWorkspaceAliases() {
+	args := os.Args[1:]
+	if i := commandWordIndex(args); i < 0 || isBuiltinCommand(args[i]) {
+		return
+	}
+	ws, err := config.LoadWorkspaceConfig("")
+	if err != nil {
+		theme.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return
+	}
+	expanded, err := expandAlias(args, ws.Aliases)
+	if err != nil {
+		theme.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	rootCmd.SetArgs(expanded)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"weekly": `gsc analytics run --config configs/site.yaml --days 7`,
+		"report": `gsc whoami`, // shadowed by the built-in report command
+		"q":      `ga4 history --query "image compressor"`,
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"expands with trailing args", []string{"weekly", "--days", "28"},
+			[]string{"gsc", "analytics", "run", "--config", "configs/site.yaml", "--days", "7", "--days", "28"}},
+		{"keeps leading persistent flags", []string{"--theme", "plain-ascii", "weekly"},
+			[]string{"--theme", "plain-ascii", "gsc", "analytics", "run", "--config", "configs/site.yaml", "--days", "7"}},
+		{"strips leading ga4 and honours quotes", []string{"q"},
+			[]string{"history", "--query", "image compressor"}},
+		{"built-in wins", []string{"report", "--days", "7"}, []string{"report", "--days", "7"}},
+		{"unknown word untouched", []string{"nope"}, []string{"nope"}},
+		{"no command word", []string{"--help"}, []string{"--help"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAlias(tt.args, aliases)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
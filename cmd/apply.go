@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	applyConfigPath string
+	applyProject    string
+	applyPlanPath   string
+	applyRequireSig bool
+	applyPublicKeys []string
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a reviewed plan to a GA4 property",
+	Long: `Run setup for a config, but only the changes a reviewed plan approved.
+
+The plan file comes from ` + "`ga4 plan --output`" + `, signed or not. Before changing
+anything, apply rebuilds the plan against the live property and refuses when
+it would create or update anything the reviewed plan did not list, or when a
+planned change is no longer needed. With a signed plan it also refuses when
+the config was edited after signing. Only the GA4 resources a plan covers are
+applied; Search Console setup is left to ` + "`ga4 setup`" + `.
+
+--require-signed-plan, or setup.require_signed_plan in the config, makes the
+signature mandatory: the plan must verify against one of the --public-key
+files (Ed25519, PEM). Configs that set setup.require_signed_plan can only be
+changed through apply; ` + "`ga4 setup`" + ` refuses them except with --dry-run.
+
+With a multi-property config, apply runs on the property the plan was made
+for, from ` + "`ga4 plan --property`" + `.
+
+Examples:
+  ga4 plan  --config configs/prod.yaml --sign plan-signing.pem --output plan.json
+  ga4 apply --config configs/prod.yaml --plan plan.json --public-key plan-signing.pub.pem
+  ga4 apply --config configs/staging.yaml --plan plan.json`,
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringVarP(&applyConfigPath, "config", "c", "", "Path to configuration file")
+	applyCmd.Flags().StringVarP(&applyProject, "project", "p", "", "Config file name (looks in configs/ and configs/examples/)")
+	applyCmd.Flags().StringVar(&applyPlanPath, "plan", "", "Plan file from ga4 plan --output (required)")
+	applyCmd.Flags().BoolVar(&applyRequireSig, "require-signed-plan", false, "Refuse plans without a valid signature")
+	applyCmd.Flags().StringArrayVar(&applyPublicKeys, "public-key", nil, "Ed25519 public key (PEM) trusted to sign plans (repeatable)")
+	_ = applyCmd.MarkFlagRequired("plan")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	if applyConfigPath == "" && applyProject == "" {
+		return fmt.Errorf("--config or --project is required")
+	}
+	configs, paths, err := loadProjectConfigs(applyConfigPath, applyProject, false)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(applyPlanPath)
+	if err != nil {
+		return fmt.Errorf("read plan: %w", err)
+	}
+	plan, err := setup.ParseSignedPlan(data)
+	if err != nil {
+		return err
+	}
+	if configs, paths, err = planConfig(configs, paths, plan); err != nil {
+		return err
+	}
+	cfg := configs[0]
+	if !cfg.HasAnalytics() {
+		return fmt.Errorf("config %q has no analytics section", cfg.Project.Name)
+	}
+
+	requireSig := applyRequireSig || (cfg.Setup != nil && cfg.Setup.RequireSignedPlan)
+	switch {
+	case requireSig && len(applyPublicKeys) == 0:
+		return fmt.Errorf("a signed plan is required: pass the reviewers' keys with --public-key")
+	case len(applyPublicKeys) > 0:
+		keys, err := setup.LoadPublicKeys(applyPublicKeys)
+		if err != nil {
+			return err
+		}
+		if err := plan.Verify(keys); err != nil {
+			if errors.Is(err, setup.ErrUnsignedPlan) && !requireSig {
+				theme.Yellow("⚠ Plan is not signed; applying it as reviewed.")
+				break
+			}
+			return fmt.Errorf("refusing to apply: %w", err)
+		}
+		theme.Green("✓ Plan signature verified (key %s)", plan.Signature.KeyID)
+	}
+
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return err
+	}
+	live, err := setup.BuildDiff(cfg, client)
+	client.Close()
+	if err != nil {
+		return err
+	}
+	if err := plan.CheckCurrent(live, cfg); err != nil {
+		return fmt.Errorf("refusing to apply: %w; run ga4 plan again", err)
+	}
+	if !live.Pending() {
+		theme.Green("✓ Nothing to apply: the property already matches the plan.")
+		return nil
+	}
+	theme.Printf("Applying %d creates and %d updates from %s\n\n",
+		live.Summary[setup.DiffActionCreate], live.Summary[setup.DiffActionUpdate], applyPlanPath)
+
+	// Sitemaps and the rest of the Search Console block are not in the plan.
+	cfg.SearchConsole = nil
+	return runSetupConfigs(configs, paths, false)
+}
+
+// planConfig picks the config a plan was made for: of a multi-property
+// config, the property the plan names.
+func planConfig(configs []*config.ProjectConfig, paths []string, plan *setup.SignedPlan) ([]*config.ProjectConfig, []string, error) {
+	if len(configs) == 1 {
+		return configs, paths, nil
+	}
+	diff, err := plan.Diff()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, cfg := range configs {
+		if cfg.GetPropertyID() == diff.PropertyID {
+			return configs[i : i+1], paths[i : i+1], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("the plan is for property %s, which is not in the config's properties list", diff.PropertyID)
+}
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/approval"
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// approverEnv names the identity approvals are requested and signed under
+// when --as is not given, for teams sharing one credential.
+const approverEnv = "GA4_APPROVER"
+
+// Actions of an approval.Operation.
+const (
+	approvalActionDelete  = "delete"
+	approvalActionArchive = "archive"
+)
+
+// Commands a pending-operations file can be for.
+const (
+	approvalCommandCleanup        = "cleanup"
+	approvalCommandPrune          = "cleanup --prune"
+	approvalCommandUnlinkChannels = "link --unlink channels"
+)
+
+var (
+	approveAs  string
+	approveYes bool
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <file>",
+	Short: "Sign a pending-operations file so its destructive batch can run",
+	Long: `Approve the destructive operations listed in a pending-operations file.
+
+` + "`ga4 cleanup`" + ` and ` + "`ga4 link --unlink channels`" + ` with --require-approval FILE
+write the operations they would run to FILE instead of running them. A second
+person reviews them with this command, which appends an approval token to the
+file. The first command then runs them with --require-approval FILE --apply,
+and only if the operations it would run are still exactly those approved.
+
+The approver is --as, $GA4_APPROVER or the credential in use (service account
+email or signed-in account). The requester cannot approve their own file.
+
+Examples:
+  ga4 approve .ga4-state/approvals/cleanup.json
+  ga4 approve cleanup.json --as bob@example.com --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApprove,
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+	approveCmd.Flags().StringVar(&approveAs, "as", "", "Approve under this identity (default $"+approverEnv+" or the credential in use)")
+	approveCmd.Flags().BoolVarP(&approveYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	p, err := approval.Read(path)
+	if err != nil {
+		return err
+	}
+	if p.ExecutedAt != nil {
+		return fmt.Errorf("%s: %w", path, approval.ErrExecuted)
+	}
+	approver := approvalIdentity(approveAs)
+
+	theme.Printf("📝 %s requested by %s on %s\n\n", p.Command, p.RequestedBy, p.RequestedAt.Local().Format(time.DateTime))
+	if err := renderApprovalOperations(p.Operations); err != nil {
+		return err
+	}
+	if approvers := p.Approvers(); len(approvers) > 0 {
+		theme.Printf("\nAlready approved by: %s\n", strings.Join(approvers, ", "))
+	}
+
+	if !approveYes {
+		theme.Printf("\nApprove these %d operation(s) as %s? [y/N]: ", len(p.Operations), approver)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			theme.Println("Not approved.")
+			return nil
+		}
+	}
+
+	if err := p.Approve(approver, time.Now()); err != nil {
+		if errors.Is(err, approval.ErrSelfApproval) {
+			return fmt.Errorf("%w (%s); a second person must approve, or pass --as when sharing a credential", err, approver)
+		}
+		return err
+	}
+	if err := approval.Write(path, p); err != nil {
+		return err
+	}
+	theme.Green("✓ Approved by %s. Run the command again with --require-approval %s --apply.", approver, path)
+	return nil
+}
+
+// approvalIdentity is who requests or approves: as, else $GA4_APPROVER,
+// else the credential in use, else the OS user.
+func approvalIdentity(as string) string {
+	if as != "" {
+		return as
+	}
+	if env := os.Getenv(approverEnv); env != "" {
+		return env
+	}
+	if actor := auditActor(); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// approvalExecutor is the slice of the GA4 client approved operations run
+// with.
+type approvalExecutor interface {
+	pruneArchiver
+	DeleteChannelGroup(channelGroupName string) error
+}
+
+// gateApproval writes ops to the pending file at path or, with apply, checks
+// that the file approves exactly ops and runs them with client. It is what
+// --require-approval does in place of the confirmation prompt.
+func gateApproval(path string, apply bool, command string, ops []approval.Operation, client approvalExecutor) error {
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+
+	if len(ops) == 0 {
+		theme.Printf("\n%s Nothing to delete or archive; no approval needed.\n", yellow("ℹ️"))
+		return nil
+	}
+
+	if !apply {
+		return requestApproval(path, command, ops)
+	}
+
+	p, err := approval.Read(path)
+	if err != nil {
+		return err
+	}
+	if err := p.Check(command, ops); err != nil {
+		switch {
+		case errors.Is(err, approval.ErrNotApproved):
+			return fmt.Errorf("%s: %w; run `ga4 approve %s` as a second person first", path, err, path)
+		case errors.Is(err, approval.ErrPlanChanged):
+			return fmt.Errorf("%s: %w; run again without --apply to request a new approval", path, err)
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	theme.Printf("\n%s Approved by %s\n\n", theme.Color(color.FgGreen).Sprint("✓"), strings.Join(p.Approvers(), ", "))
+	if err := applyApprovedOperations(client, ops); err != nil {
+		// Not marked executed: the approval still covers a retry of the
+		// same operations.
+		return exitcode.New(exitcode.API, fmt.Errorf("%w; %s was not marked executed", err, path))
+	}
+
+	p.MarkExecuted(time.Now())
+	return approval.Write(path, p)
+}
+
+// requestApproval writes a pending file for ops, unless path already holds
+// one for the same operations, whose approvals are then kept.
+func requestApproval(path, command string, ops []approval.Operation) error {
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+
+	if existing, err := approval.Read(path); err == nil {
+		if existing.ExecutedAt == nil && existing.Command == command && existing.Digest == approval.Digest(command, ops) {
+			theme.Printf("\n%s %s already lists these operations (%d approval(s)); it was left as is.\n", yellow("ℹ️"), path, len(existing.Approvers()))
+			return nil
+		}
+		theme.Printf("\n%s %s is replaced; its approvals do not carry over.\n", yellow("⚠"), path)
+	}
+
+	p := approval.New(command, approvalIdentity(""), ops, time.Now())
+	if err := approval.Write(path, p); err != nil {
+		return err
+	}
+	theme.Printf("\n%s Nothing was changed. %d operation(s) are pending approval in %s.\n", yellow("⏸"), len(ops), path)
+	theme.Println("Next steps:")
+	theme.Printf("1. A second person runs: ga4 approve %s\n", path)
+	theme.Println("2. Run this command again with --apply added")
+	return nil
+}
+
+// applyApprovedOperations runs each operation, reporting the outcome as
+// cleanup does. It returns an error when any of them failed; a resource
+// already gone is not a failure.
+func applyApprovedOperations(client approvalExecutor, ops []approval.Operation) error {
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+
+	theme.Printf("%s Running approved operations...\n", red("🗑"))
+	failed := 0
+	for _, op := range ops {
+		var err error
+		switch op.Resource {
+		case setup.DiffResourceConversion:
+			err = client.DeleteConversion(op.Property, op.Name)
+		case setup.DiffResourceDimension:
+			err = client.DeleteDimension(op.Property, op.Name)
+		case setup.DiffResourceMetric:
+			err = client.DeleteMetric(op.Property, op.Name)
+		case setup.DiffResourceChannelGroup:
+			err = client.DeleteChannelGroup(op.Name)
+		default:
+			err = fmt.Errorf("unsupported resource %q", op.Resource)
+		}
+		label := approvalOperationLabel(op)
+		switch {
+		case err != nil && strings.Contains(err.Error(), "not found"):
+			theme.Printf("  %s %s %s (already gone)\n", yellow("○"), op.Resource, label)
+		case err != nil:
+			failed++
+			theme.Printf("  %s %s %s: %s\n", red("✗"), op.Resource, label, err)
+		default:
+			theme.Printf("  %s %s %s\n", green("✓"), op.Resource, label)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d approved operations failed", failed, len(ops))
+	}
+	return nil
+}
+
+// cleanupOperations lists what the config's cleanup section removes from
+// propertyID, for the resource types cType selects.
+func cleanupOperations(propertyID string, conversions, dimensions, metrics []string, cType string) []approval.Operation {
+	var ops []approval.Operation
+	add := func(resourceType, resource, action string, names []string) {
+		if cType != "all" && cType != resourceType {
+			return
+		}
+		for _, name := range names {
+			ops = append(ops, approval.Operation{Property: propertyID, Action: action, Resource: resource, Name: name})
+		}
+	}
+	add("conversions", setup.DiffResourceConversion, approvalActionDelete, conversions)
+	add("dimensions", setup.DiffResourceDimension, approvalActionArchive, dimensions)
+	add("metrics", setup.DiffResourceMetric, approvalActionArchive, metrics)
+	return ops
+}
+
+// pruneOperations lists the unprotected prune changes of propertyID.
+func pruneOperations(propertyID string, changes []setup.Change) []approval.Operation {
+	var ops []approval.Operation
+	for _, c := range pruneSelection(changes) {
+		action := approvalActionArchive
+		if c.Resource == setup.DiffResourceConversion {
+			action = approvalActionDelete
+		}
+		ops = append(ops, approval.Operation{Property: propertyID, Action: action, Resource: c.Resource, Name: c.Name})
+	}
+	return ops
+}
+
+func renderApprovalOperations(ops []approval.Operation) error {
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		approvalOperationColumns(), ops, approvalOperationRow,
+	); err != nil {
+		return fmt.Errorf("failed to render operations table: %w", err)
+	}
+	return nil
+}
+
+// approvalOperationColumns / approvalOperationRow project the operations
+// table of a pending file.
+func approvalOperationColumns() []string { return []string{"Property", "Action", "Type", "Name"} }
+
+func approvalOperationRow(op approval.Operation) []string {
+	return []string{op.Property, op.Action, op.Resource, approvalOperationLabel(op)}
+}
+
+// approvalOperationLabel names an operation's resource as people know it.
+func approvalOperationLabel(op approval.Operation) string {
+	if op.DisplayName != "" {
+		return op.DisplayName + " (" + op.Name + ")"
+	}
+	return op.Name
+}
+
+// checkApprovalFlags validates --require-approval and --apply together.
+func checkApprovalFlags(path string, apply, dryRun bool) error {
+	switch {
+	case apply && path == "":
+		return fmt.Errorf("--apply only applies with --require-approval")
+	case path != "" && dryRun:
+		return fmt.Errorf("--require-approval never changes anything without --apply; drop --dry-run")
+	}
+	return nil
+}
+
+// executeCleanupApproval is cleanup, or cleanup --prune, under
+// --require-approval: the items of every project are gathered into one
+// pending file, or removed together once it is approved.
+func executeCleanupApproval(cfgPath, projName string, all bool, cType string, prune bool, protect []string, path string, apply bool) error {
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+
+	theme.Println("🧹 GA4 Manager - Cleanup (two-person approval)")
+	theme.Println("═══════════════════════════════════════════════")
+
+	if err := validateCleanupType(cType); err != nil {
+		return err
+	}
+
+	projects, err := loadProjects(cfgPath, projName, all)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGA4Client(projects...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	command := approvalCommandCleanup
+	if prune {
+		command = approvalCommandPrune
+	}
+	var ops []approval.Operation
+	for _, cfg := range projects {
+		propertyID := cfg.GetPropertyID()
+		if !prune {
+			ops = append(ops, cleanupOperations(propertyID, cfg.Cleanup.ConversionsToRemove, cfg.Cleanup.DimensionsToRemove, cfg.Cleanup.MetricsToRemove, cType)...)
+			continue
+		}
+		if !cfg.HasAnalytics() {
+			continue
+		}
+		diff, err := setup.BuildPrune(cfg, client, slices.Concat(cfg.Cleanup.Protected, protect))
+		if err != nil {
+			return fmt.Errorf("%s: %w", cfg.Project.Name, err)
+		}
+		ops = append(ops, pruneOperations(propertyID, pruneChanges(diff.Changes, cType))...)
+	}
+
+	if len(ops) > 0 {
+		theme.Printf("\n%s Operations:\n", cyan("📋"))
+		if err := renderApprovalOperations(ops); err != nil {
+			return err
+		}
+	}
+	return gateApproval(path, apply, command, ops, client)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/approval"
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/setup"
+)
+
+// fakeApprovalExecutor also records the channel groups it deletes.
+type fakeApprovalExecutor struct{ fakeArchiver }
+
+func (f *fakeApprovalExecutor) DeleteChannelGroup(name string) error {
+	return f.archive("channel_group", name)
+}
+
+func TestCleanupOperations(t *testing.T) {
+	ops := cleanupOperations("123", []string{"old_lead"}, []string{"word_count"}, []string{"reading_time"}, "all")
+	assert.Equal(t, []approval.Operation{
+		{Property: "123", Action: approvalActionDelete, Resource: setup.DiffResourceConversion, Name: "old_lead"},
+		{Property: "123", Action: approvalActionArchive, Resource: setup.DiffResourceDimension, Name: "word_count"},
+		{Property: "123", Action: approvalActionArchive, Resource: setup.DiffResourceMetric, Name: "reading_time"},
+	}, ops)
+	assert.Len(t, cleanupOperations("123", []string{"old_lead"}, []string{"word_count"}, nil, "dimensions"), 1)
+
+	pruned := pruneOperations("123", pruneFixture())
+	require.Len(t, pruned, 3, "protected changes are left out")
+	assert.Equal(t, approvalActionDelete, pruned[0].Action)
+	assert.Equal(t, approvalActionArchive, pruned[1].Action)
+}
+
+func TestGateApproval(t *testing.T) {
+	t.Setenv(approverEnv, "alice@example.com")
+	path := filepath.Join(t.TempDir(), "cleanup.json")
+	ops := cleanupOperations("123", []string{"old_lead"}, []string{"word_count"}, nil, "all")
+	client := &fakeApprovalExecutor{}
+
+	require.NoError(t, gateApproval(path, false, approvalCommandCleanup, ops, client))
+	assert.Empty(t, client.archived, "requesting approval changes nothing")
+
+	err := gateApproval(path, true, approvalCommandCleanup, ops, client)
+	assert.ErrorIs(t, err, approval.ErrNotApproved)
+
+	p, err := approval.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", p.RequestedBy)
+	require.NoError(t, p.Approve("bob@example.com", time.Now()))
+	require.NoError(t, approval.Write(path, p))
+
+	err = gateApproval(path, true, approvalCommandCleanup, ops[:1], client)
+	assert.ErrorIs(t, err, approval.ErrPlanChanged)
+	assert.Empty(t, client.archived)
+
+	require.NoError(t, gateApproval(path, true, approvalCommandCleanup, ops, client))
+	assert.Equal(t, []string{"conversion old_lead", "dimension word_count"}, client.archived)
+
+	err = gateApproval(path, true, approvalCommandCleanup, ops, client)
+	assert.ErrorIs(t, err, approval.ErrExecuted, "an approval runs once")
+}
+
+// A partly failed run exits with the API code and leaves the file
+// unexecuted, so the same approval covers a retry.
+func TestGateApproval_PartialFailureIsNotExecuted(t *testing.T) {
+	t.Setenv(approverEnv, "alice@example.com")
+	path := filepath.Join(t.TempDir(), "cleanup.json")
+	ops := cleanupOperations("123", []string{"old_lead"}, []string{"word_count", "author"}, nil, "all")
+
+	require.NoError(t, gateApproval(path, false, approvalCommandCleanup, ops, &fakeApprovalExecutor{}))
+	p, err := approval.Read(path)
+	require.NoError(t, err)
+	require.NoError(t, p.Approve("bob@example.com", time.Now()))
+	require.NoError(t, approval.Write(path, p))
+
+	client := &fakeApprovalExecutor{fakeArchiver{errs: map[string]error{
+		"word_count": errors.New("permission denied"),
+		"author":     errors.New("custom dimension not found"),
+	}}}
+	err = gateApproval(path, true, approvalCommandCleanup, ops, client)
+	require.Error(t, err)
+	assert.Equal(t, exitcode.API, exitcode.Of(err))
+	assert.Contains(t, err.Error(), "1 of 3 approved operations failed")
+
+	p, err = approval.Read(path)
+	require.NoError(t, err)
+	assert.Nil(t, p.ExecutedAt)
+
+	client.errs = nil
+	require.NoError(t, gateApproval(path, true, approvalCommandCleanup, ops, client))
+	p, err = approval.Read(path)
+	require.NoError(t, err)
+	assert.NotNil(t, p.ExecutedAt)
+}
+
+func TestGateApproval_KeepsApprovalsOfTheSamePlan(t *testing.T) {
+	t.Setenv(approverEnv, "alice@example.com")
+	path := filepath.Join(t.TempDir(), "channels.json")
+	ops := []approval.Operation{{Property: "123", Action: approvalActionDelete, Resource: setup.DiffResourceChannelGroup, Name: "properties/123/channelGroups/9", DisplayName: "Paid"}}
+
+	require.NoError(t, gateApproval(path, false, approvalCommandUnlinkChannels, ops, &fakeApprovalExecutor{}))
+	p, err := approval.Read(path)
+	require.NoError(t, err)
+	require.NoError(t, p.Approve("bob@example.com", time.Now()))
+	require.NoError(t, approval.Write(path, p))
+
+	require.NoError(t, gateApproval(path, false, approvalCommandUnlinkChannels, ops, &fakeApprovalExecutor{}))
+	p, err = approval.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob@example.com"}, p.Approvers())
+
+	client := &fakeApprovalExecutor{}
+	require.NoError(t, gateApproval(path, true, approvalCommandUnlinkChannels, ops, client))
+	assert.Equal(t, []string{"channel_group properties/123/channelGroups/9"}, client.archived)
+}
+
+func TestCheckApprovalFlags(t *testing.T) {
+	assert.NoError(t, checkApprovalFlags("", false, true))
+	assert.NoError(t, checkApprovalFlags("f.json", true, false))
+	assert.ErrorContains(t, checkApprovalFlags("", true, false), "--require-approval")
+	assert.ErrorContains(t, checkApprovalFlags("f.json", false, true), "--dry-run")
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// auditLogOff is the --audit-log value that turns the audit log off.
+const auditLogOff = "off"
+
+// --audit-log / --audit-history, the audit log every command records its
+// changes in.
+var (
+	auditLogPath string
+	auditHistory bool
+)
+
+var (
+	auditSince    string
+	auditProperty string
+	auditType     string
+	auditResource string
+	auditFormat   string
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log of changes made to GA4 and Search Console",
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the creates, updates and deletes the tool has sent",
+	Long: `List entries of the audit log: every create, update and delete any command
+sent to the GA4 Admin API or Search Console, with the credential that sent it,
+what was sent and whether it succeeded.
+
+The log is append-only JSONL at .ga4-state/audit.jsonl; --audit-log moves it
+(or turns it off with --audit-log off) and --audit-history also mirrors each
+entry into the local history store.
+
+Examples:
+  # What changed this week
+  ga4 audit list --since 7d
+
+  # Who changed the counting method on purchase
+  ga4 audit list --property 123456789 --type conversion --resource purchase
+
+  # Entries since a date, for a compliance export
+  ga4 audit list --since 2026-01-01 --format json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditList,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&auditLogPath, "audit-log", "", "Audit log of every change sent to Google, or \""+auditLogOff+"\" (default .ga4-state/"+auditlog.FileName+")")
+	rootCmd.PersistentFlags().BoolVar(&auditHistory, "audit-history", false, "Also record audit log entries in the local history store")
+	cobra.OnInitialize(applyAuditLog)
+
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditListCmd)
+	auditListCmd.Flags().StringVar(&auditSince, "since", "", "Only entries recorded since then: 7d, 2w, 24h or 2026-01-31 (default all)")
+	auditListCmd.Flags().StringVarP(&auditProperty, "property", "p", "", "Only entries for this GA4 property ID or Search Console site")
+	auditListCmd.Flags().StringVarP(&auditType, "type", "t", "", "Only entries for this resource type (conversion, dimension, custom metric, sitemap, ...)")
+	auditListCmd.Flags().StringVarP(&auditResource, "resource", "r", "", "Only entries for this resource (event name, display or resource name, sitemap URL)")
+	output.FormatVar(auditListCmd.Flags(), &auditFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+// resolveAuditLogPath returns the audit file --audit-log names, or "" when
+// it is off.
+func resolveAuditLogPath() string {
+	switch auditLogPath {
+	case auditLogOff:
+		return ""
+	case "":
+		return filepath.Join(gscstate.ResolveStateDir(""), auditlog.FileName)
+	}
+	return auditLogPath
+}
+
+// applyAuditLog points the GA4 and GSC clients at the audit log once flags
+// are parsed.
+func applyAuditLog() {
+	path := resolveAuditLogPath()
+	if path == "" {
+		auditlog.SetDefault(nil)
+		return
+	}
+	opts := []auditlog.Option{auditlog.WithActor(auditActor)}
+	if auditHistory {
+		opts = append(opts, auditlog.WithHistory(store.New(gscstate.ResolveStateDir(""))))
+	}
+	auditlog.SetDefault(auditlog.New(path, opts...))
+}
+
+// auditActor names the credential changes are sent with: the service
+// account's email, the signed-in account, or where the credentials come from
+// when neither is known.
+func auditActor() string {
+	src, err := auth.Resolve()
+	if err != nil {
+		return ""
+	}
+	if src.Kind == auth.SourceUser {
+		if creds, err := auth.LoadToken(src.Path); err == nil && creds.Account != "" {
+			return creds.Account
+		}
+		return src.String()
+	}
+	if id := gsc.LoadServiceAccountIdentity(); id.ClientEmail != "" {
+		return id.ClientEmail
+	}
+	return src.String()
+}
+
+func runAuditList(cmd *cobra.Command, args []string) error {
+	filter := auditlog.Filter{Property: auditProperty, ResourceType: auditType, Resource: auditResource}
+	if auditSince != "" {
+		since, err := auditlog.ParseSince(auditSince, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+
+	path := resolveAuditLogPath()
+	if path == "" {
+		return fmt.Errorf("the audit log is off; pass --audit-log with its path")
+	}
+	entries, err := auditlog.Read(path, filter)
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == output.FormatJSON {
+		if entries == nil {
+			entries = []auditlog.Entry{}
+		}
+		return output.JSON(os.Stdout, entries)
+	}
+
+	if len(entries) == 0 {
+		theme.Yellow("No audit log entries in %s match.", path)
+		return nil
+	}
+	theme.Cyan("═══ Audit log: %s ═══", path)
+	theme.Println()
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		auditLogColumns(), entries, auditLogTableRow)
+}
+
+// auditLogColumns / auditLogTableRow project the audit list table.
+func auditLogColumns() []string {
+	return []string{"Time", "Actor", "Operation", "Type", "Resource", "Property", "Result"}
+}
+
+func auditLogTableRow(e auditlog.Entry) []string {
+	result := theme.GreenString("✓ ok")
+	if e.Result == auditlog.ResultError {
+		result = theme.RedString("✗ %s", e.Error)
+	}
+	return []string{
+		e.Time.Local().Format("2006-01-02 15:04:05"),
+		e.Actor,
+		e.Operation,
+		e.ResourceType,
+		e.Resource,
+		e.Property,
+		result,
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+)
+
+func TestResolveAuditLogPath(t *testing.T) {
+	defer func(v string) { auditLogPath = v }(auditLogPath)
+
+	auditLogPath = ""
+	assert.Equal(t, filepath.Join(".ga4-state", auditlog.FileName), resolveAuditLogPath())
+	auditLogPath = "/var/log/ga4/audit.jsonl"
+	assert.Equal(t, "/var/log/ga4/audit.jsonl", resolveAuditLogPath())
+	auditLogPath = auditLogOff
+	assert.Empty(t, resolveAuditLogPath())
+}
+
+func TestApplyAuditLog(t *testing.T) {
+	defer func(v string) { auditLogPath = v }(auditLogPath)
+	t.Cleanup(func() { auditlog.SetDefault(nil) })
+
+	auditLogPath = filepath.Join(t.TempDir(), auditlog.FileName)
+	applyAuditLog()
+	require.NotNil(t, auditlog.Default())
+	assert.Equal(t, auditLogPath, auditlog.Default().Path())
+
+	auditLogPath = auditLogOff
+	applyAuditLog()
+	assert.Nil(t, auditlog.Default())
+}
+
+func TestAuditLogTableRow(t *testing.T) {
+	row := auditLogTableRow(auditlog.Entry{
+		Operation: "update", ResourceType: "conversion", Resource: "purchase", Property: "123",
+		Actor: "ci@example.iam.gserviceaccount.com", Result: auditlog.ResultError, Error: "permission denied",
+	})
+	require.Len(t, row, len(auditLogColumns()))
+	assert.Equal(t, []string{"ci@example.iam.gserviceaccount.com", "update", "conversion", "purchase", "123"}, row[1:6])
+	assert.Contains(t, row[6], "permission denied")
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	authClientSecret string
+	authNoBrowser    bool
+	authTimeout      time.Duration
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Sign in with a Google account instead of a service account",
+	Long: `Manage the user login the GA4 and Search Console clients fall back on when
+GOOGLE_APPLICATION_CREDENTIALS is not set.
+
+A service account key, when set, always takes precedence.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Sign in through the browser and cache the credentials",
+	Long: `Run Google's installed-app OAuth flow: open the consent page in a browser,
+receive the answer on a local port and cache the resulting credentials in
+~/.config/ga4-manager/credentials.json (or under $XDG_CONFIG_HOME).
+
+The flow needs an OAuth client of type "Desktop app". Create one in Google
+Cloud Console → APIs & Services → Credentials, enable the Google Analytics
+Admin, Google Analytics Data and Search Console APIs in the same project, and
+download its JSON for --client-secret.
+
+The signed-in account needs Editor on the GA4 property for setup, and Owner or
+Full user on the Search Console site to submit sitemaps.`,
+	Example: `  ga4 auth login --client-secret ~/Downloads/client_secret.json
+
+  # On a machine without a browser: open the printed URL elsewhere
+  ga4 auth login --client-secret client_secret.json --no-browser`,
+	RunE: runAuthLogin,
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which credentials the API clients use",
+	RunE:  runAuthStatus,
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Delete the cached user credentials",
+	RunE:  runAuthLogout,
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd, authStatusCmd, authLogoutCmd)
+
+	authLoginCmd.Flags().StringVar(&authClientSecret, "client-secret", "", "OAuth client JSON of a Desktop app (required)")
+	authLoginCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "Print the sign-in URL without opening a browser")
+	authLoginCmd.Flags().DurationVar(&authTimeout, "timeout", 5*time.Minute, "How long to wait for the browser to return")
+	_ = authLoginCmd.MarkFlagRequired("client-secret")
+}
+
+func runAuthLogin(cmd *cobra.Command, args []string) error {
+	path, err := auth.TokenPath()
+	if err != nil {
+		return err
+	}
+	if err := loginTo(cmd, path); err != nil {
+		return err
+	}
+	if os.Getenv(auth.CredentialsEnvVar) != "" {
+		theme.Yellow("⚠️  %s is set and takes precedence; unset it to use this login", auth.CredentialsEnvVar)
+	}
+	return nil
+}
+
+// loginTo runs the browser login with the --client-secret client and caches
+// the credentials in path.
+func loginTo(cmd *cobra.Command, path string) error {
+	secret, err := os.ReadFile(authClientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to read OAuth client file: %w", err)
+	}
+
+	opts := auth.LoginOptions{ClientSecret: secret, Out: theme.NewWriter(os.Stdout)}
+	if !authNoBrowser {
+		opts.OpenBrowser = auth.OpenBrowser
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), authTimeout)
+	defer cancel()
+	oauthCfg, token, account, err := auth.Login(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := auth.SaveToken(path, oauthCfg, token, account); err != nil {
+		return err
+	}
+
+	green := theme.Color(color.FgGreen).SprintFunc()
+	if account != "" {
+		theme.Printf("\n%s Signed in as %s\n", green("✓"), account)
+	} else {
+		theme.Printf("\n%s Signed in\n", green("✓"))
+	}
+	theme.Printf("  Credentials cached in %s\n", path)
+	return nil
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	creds, err := auth.Resolve()
+	if errors.Is(err, auth.ErrNoCredentials) {
+		theme.Println("Not signed in and " + auth.CredentialsEnvVar + " is not set.")
+		theme.Println("Run `ga4 auth login` or set " + auth.CredentialsEnvVar + " to a service account key.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if name := auth.ActiveProfile(); name != "" {
+		theme.Printf("Profile: %s\n", name)
+	}
+	theme.Printf("Using %s\n", creds)
+	if creds.Kind == auth.SourceUser {
+		user, err := auth.LoadToken(creds.Path)
+		if err != nil {
+			return err
+		}
+		if user.Account != "" {
+			theme.Printf("Account: %s\n", user.Account)
+		}
+	}
+	return nil
+}
+
+func runAuthLogout(cmd *cobra.Command, args []string) error {
+	path, err := auth.TokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			theme.Println("Not signed in.")
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	theme.Printf("Deleted %s\n", path)
+	theme.Println("To revoke the access itself, remove ga4-manager at https://myaccount.google.com/permissions")
+	return nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	profileServiceAccount string
+	profileClearDefault   bool
+)
+
+var authProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Keep credentials for several Google accounts under names",
+	Long: `List the credential profiles saved in ~/.config/ga4-manager/profiles.yaml.
+
+A profile holds a service account key path or a user login. Pick one for a
+command with --profile NAME (every command accepts it) or $GA4_PROFILE, or set
+a default with ` + "`ga4 auth profiles default`" + `. A chosen profile replaces
+GOOGLE_APPLICATION_CREDENTIALS and the login of ` + "`ga4 auth login`" + `.`,
+	Example: `  ga4 auth profiles add client-a --service-account ~/keys/client-a.json
+  ga4 auth profiles add client-b --client-secret client_secret.json
+  ga4 auth profiles default client-a
+
+  ga4 report --project client-b --profile client-b`,
+	Args: cobra.NoArgs,
+	RunE: runAuthProfilesList,
+}
+
+var authProfilesAddCmd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "Save a service account key or sign in under a profile name",
+	Long: `Save a profile that uses a service account key (--service-account), or sign in
+through the browser as with ` + "`ga4 auth login`" + ` (--client-secret) and keep that
+login under the profile. Adding an existing name replaces it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuthProfilesAdd,
+}
+
+var authProfilesRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Delete a profile and its cached login",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthProfilesRemove,
+}
+
+var authProfilesDefaultCmd = &cobra.Command{
+	Use:   "default [NAME]",
+	Short: "Set the profile used when --profile is not given",
+	Long: `Set the profile commands use without --profile or $GA4_PROFILE. An exported
+GOOGLE_APPLICATION_CREDENTIALS still takes precedence over the default profile.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAuthProfilesDefault,
+}
+
+func init() {
+	authCmd.AddCommand(authProfilesCmd)
+	authProfilesCmd.AddCommand(authProfilesAddCmd, authProfilesRemoveCmd, authProfilesDefaultCmd)
+
+	authProfilesAddCmd.Flags().StringVar(&profileServiceAccount, "service-account", "", "Service account key file the profile uses")
+	authProfilesAddCmd.Flags().StringVar(&authClientSecret, "client-secret", "", "OAuth client JSON of a Desktop app to sign in with")
+	authProfilesAddCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "Print the sign-in URL without opening a browser")
+	authProfilesAddCmd.Flags().DurationVar(&authTimeout, "timeout", 5*time.Minute, "How long to wait for the browser to return")
+	authProfilesAddCmd.MarkFlagsOneRequired("service-account", "client-secret")
+	authProfilesAddCmd.MarkFlagsMutuallyExclusive("service-account", "client-secret")
+
+	authProfilesDefaultCmd.Flags().BoolVar(&profileClearDefault, "clear", false, "Remove the default profile")
+}
+
+func runAuthProfilesList(cmd *cobra.Command, args []string) error {
+	profiles, err := auth.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles.Profiles) == 0 {
+		theme.Println("No profiles saved. Add one with `ga4 auth profiles add NAME --service-account KEY`.")
+		return nil
+	}
+
+	green := theme.Color(color.FgGreen).SprintFunc()
+	for _, name := range profiles.Names() {
+		profile := profiles.Profiles[name]
+		marker := " "
+		if name == auth.ActiveProfile() {
+			marker = green("*")
+		}
+		label := name
+		if name == profiles.Default {
+			label += " (default)"
+		}
+		source := auth.Source{Kind: profile.Type, Path: profile.Path}
+		theme.Printf("%s %-24s %s\n", marker, label, source)
+	}
+	return nil
+}
+
+func runAuthProfilesAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := auth.ValidateProfileName(name); err != nil {
+		return err
+	}
+	profiles, err := auth.LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	var profile auth.Profile
+	if profileServiceAccount != "" {
+		path, err := filepath.Abs(profileServiceAccount)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("service account key: %w", err)
+		}
+		profile = auth.Profile{Type: auth.SourceServiceAccount, Path: path}
+	} else {
+		path, err := auth.ProfileTokenPath(name)
+		if err != nil {
+			return err
+		}
+		if err := loginTo(cmd, path); err != nil {
+			return err
+		}
+		profile = auth.Profile{Type: auth.SourceUser, Path: path}
+	}
+
+	profiles.Profiles[name] = profile
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+	green := theme.Color(color.FgGreen).SprintFunc()
+	theme.Printf("%s Saved profile %s: %s\n", green("✓"), name, auth.Source{Kind: profile.Type, Path: profile.Path})
+	theme.Printf("  Use it with --profile %s\n", name)
+	return nil
+}
+
+func runAuthProfilesRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	profiles, err := auth.LoadProfiles()
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	delete(profiles.Profiles, name)
+	if profiles.Default == name {
+		profiles.Default = ""
+	}
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+	// Only the logins kept by `profiles add` belong to the profile; a
+	// service account key stays where it is.
+	if profile.Type == auth.SourceUser {
+		if err := os.Remove(profile.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", profile.Path, err)
+		}
+	}
+	theme.Printf("Removed profile %s\n", name)
+	return nil
+}
+
+func runAuthProfilesDefault(cmd *cobra.Command, args []string) error {
+	profiles, err := auth.LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case profileClearDefault:
+		profiles.Default = ""
+	case len(args) == 0:
+		if profiles.Default == "" {
+			theme.Println("No default profile.")
+		} else {
+			theme.Println(profiles.Default)
+		}
+		return nil
+	default:
+		if _, ok := profiles.Profiles[args[0]]; !ok {
+			return fmt.Errorf("unknown profile %q", args[0])
+		}
+		profiles.Default = args[0]
+	}
+
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+	if profiles.Default == "" {
+		theme.Println("Cleared the default profile.")
+	} else {
+		theme.Printf("Default profile: %s\n", profiles.Default)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/bigquery"
+)
+
+// bigQueryLoader is the part of *bigquery.Loader the report commands use.
+type bigQueryLoader interface {
+	Append(ctx context.Context, t bigquery.Table, columns []string, rows [][]any, partitionBy string) error
+}
+
+// newBigQueryLoader returns the loader --output bq:// appends through,
+// authenticated like the GA4 and Search Console clients.
+var newBigQueryLoader = func() (bigQueryLoader, error) {
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return bigquery.NewLoader(context.Background(), creds.ClientOption())
+}
+
+// bigQueryWriter appends rows to a BigQuery table partitioned by
+// reportDateColumn, one load job per Write.
+type bigQueryWriter struct {
+	loader bigQueryLoader
+	table  bigquery.Table
+}
+
+func (w *bigQueryWriter) Write(rows tableRows) error {
+	return w.loader.Append(context.Background(), w.table, rows.Columns, rows.Values, reportDateColumn)
+}
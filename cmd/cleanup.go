@@ -7,7 +7,8 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,13 +20,27 @@ var (
 	cleanupProject     string
 	cleanupAllProjects bool
 	cleanupConfigPath  string
+	cleanupPrune       bool
+	cleanupProtect     []string
+	cleanupApproval    string
+	cleanupApply       bool
 )
 
 var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Remove unused events and dimensions from GA4",
 	Long: `Remove conversion events, custom dimensions, and custom metrics that are not implemented in your tracking code.
-This helps reduce noise, improve data quality, and free up GA4 property quota.`,
+This helps reduce noise, improve data quality, and free up GA4 property quota.
+
+By default the items come from the config's cleanup section. With --prune they
+come from drift instead: everything on the property the config does not
+declare, confirmed one by one unless --yes is given. purchase, the names in
+cleanup.protected and those given with --protect are never touched.
+
+With --require-approval FILE nothing is removed: the items are written to FILE
+for a second person to sign with ` + "`ga4 approve FILE`" + `. Running again with
+--require-approval FILE --apply removes them once approved, without a prompt,
+and refuses when the items have changed since.`,
 	Example: `  # Preview cleanup (dry-run)
   ga4 cleanup --config configs/my-blog.yaml --dry-run
 
@@ -42,7 +57,16 @@ This helps reduce noise, improve data quality, and free up GA4 property quota.`,
   ga4 cleanup --config configs/my-blog.yaml --yes
 
   # Cleanup all available config files
-  ga4 cleanup --all --dry-run`,
+  ga4 cleanup --all --dry-run
+
+  # Archive whatever the property has and the config does not declare
+  ga4 cleanup --config configs/my-blog.yaml --prune --dry-run
+  ga4 cleanup --config configs/my-blog.yaml --prune --protect 'legacy_*' --yes
+
+  # Two-person approval: request, approve (someone else), then apply
+  ga4 cleanup --config configs/my-blog.yaml --require-approval cleanup.json
+  ga4 approve cleanup.json
+  ga4 cleanup --config configs/my-blog.yaml --require-approval cleanup.json --apply`,
 	RunE: runCleanup,
 }
 
@@ -54,50 +78,66 @@ func init() {
 	cleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "Preview changes without applying them")
 	cleanupCmd.Flags().StringVarP(&cleanupType, "type", "t", "all", "What to cleanup: conversions, dimensions, metrics, all")
 	cleanupCmd.Flags().BoolVarP(&cleanupYes, "yes", "y", false, "Skip confirmation prompt")
+	cleanupCmd.Flags().BoolVar(&cleanupPrune, "prune", false, "Archive conversions, dimensions and metrics on the property that the config does not declare")
+	cleanupCmd.Flags().StringSliceVar(&cleanupProtect, "protect", nil, "With --prune, names or patterns (e.g. legacy_*) to never archive, on top of cleanup.protected")
+	cleanupCmd.Flags().StringVar(&cleanupApproval, "require-approval", "", "Write the items to this pending-operations file for a second person to approve instead of removing them")
+	cleanupCmd.Flags().BoolVar(&cleanupApply, "apply", false, "With --require-approval, remove the items once the file is approved")
 }
 
 // runCleanup is the Cobra RunE handler — reads flag variables and delegates to executeCleanup.
 func runCleanup(cmd *cobra.Command, args []string) error {
+	if err := checkApprovalFlags(cleanupApproval, cleanupApply, cleanupDryRun); err != nil {
+		return err
+	}
+	if len(cleanupProtect) > 0 && !cleanupPrune {
+		return fmt.Errorf("--protect only applies with --prune")
+	}
+	if cleanupApproval != "" {
+		return executeCleanupApproval(cleanupConfigPath, cleanupProject, cleanupAllProjects, cleanupType, cleanupPrune, cleanupProtect, cleanupApproval, cleanupApply)
+	}
+	if cleanupPrune {
+		return executePrune(cleanupConfigPath, cleanupProject, cleanupAllProjects, cleanupDryRun, cleanupType, cleanupYes, cleanupProtect)
+	}
 	return executeCleanup(cleanupConfigPath, cleanupProject, cleanupAllProjects, cleanupDryRun, cleanupType, cleanupYes)
 }
 
 // executeCleanup performs the cleanup with explicit parameters, avoiding reliance on global flag state.
 func executeCleanup(cfgPath, projName string, all, dryRun bool, cType string, yes bool) error {
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
 
-	fmt.Println("🧹 GA4 Manager - Cleanup")
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Println()
+	theme.Println("🧹 GA4 Manager - Cleanup")
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Println()
 
 	if err := validateCleanupType(cType); err != nil {
 		return err
 	}
 
-	// Create GA4 client
-	client, err := newGA4Client()
+	// Load projects based on flags
+	projects, err := loadProjects(cfgPath, projName, all)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
 
-	// Load projects based on flags
-	projects, err := loadProjects(cfgPath, projName, all)
+	// Create GA4 client
+	client, err := newGA4Client(projects...)
 	if err != nil {
 		return err
 	}
+	defer client.Close()
 
 	// Process each project
 	for _, cfg := range projects {
 		propertyID := cfg.GetPropertyID()
-		fmt.Printf("\n📦 %s: %s (Property: %s)\n", cyan("Project"), cfg.Project.Name, propertyID)
-		fmt.Println("───────────────────────────────────────────────")
+		theme.Printf("\n📦 %s: %s (Property: %s)\n", cyan("Project"), cfg.Project.Name, propertyID)
+		theme.Println("───────────────────────────────────────────────")
 
 		if cfg.Cleanup.Reason != "" {
-			fmt.Printf("%s %s\n\n", blue("ℹ️"), cfg.Cleanup.Reason)
+			theme.Printf("%s %s\n\n", blue("ℹ️"), cfg.Cleanup.Reason)
 		}
 
 		hasConversions := len(cfg.Cleanup.ConversionsToRemove) > 0 && (cType == "conversions" || cType == "all")
@@ -106,14 +146,14 @@ func executeCleanup(cfgPath, projName string, all, dryRun bool, cType string, ye
 		hasItems := hasConversions || hasDimensions || hasMetrics
 
 		if !hasItems {
-			fmt.Printf("%s No cleanup configured for this project\n", yellow("⚠"))
+			theme.Printf("%s No cleanup configured for this project\n", yellow("⚠"))
 			continue
 		}
 
 		// Show what will be removed
 		if hasConversions {
-			fmt.Printf("\n%s Conversion Events to Remove:\n", red("🗑"))
-			if err := render.Render(os.Stdout, render.FormatTable,
+			theme.Printf("\n%s Conversion Events to Remove:\n", red("🗑"))
+			if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
 				cleanupConversionsColumns(),
 				cfg.Cleanup.ConversionsToRemove,
 				cleanupConversionsTableRow,
@@ -123,8 +163,8 @@ func executeCleanup(cfgPath, projName string, all, dryRun bool, cType string, ye
 		}
 
 		if hasDimensions {
-			fmt.Printf("\n%s Custom Dimensions to Remove:\n", red("🗑"))
-			if err := render.Render(os.Stdout, render.FormatTable,
+			theme.Printf("\n%s Custom Dimensions to Remove:\n", red("🗑"))
+			if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
 				cleanupDimensionsColumns(),
 				cfg.Cleanup.DimensionsToRemove,
 				cleanupDimensionsTableRow,
@@ -134,8 +174,8 @@ func executeCleanup(cfgPath, projName string, all, dryRun bool, cType string, ye
 		}
 
 		if hasMetrics {
-			fmt.Printf("\n%s Custom Metrics to Remove:\n", red("🗑"))
-			if err := render.Render(os.Stdout, render.FormatTable,
+			theme.Printf("\n%s Custom Metrics to Remove:\n", red("🗑"))
+			if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
 				cleanupMetricsColumns(),
 				cfg.Cleanup.MetricsToRemove,
 				cleanupMetricsTableRow,
@@ -145,84 +185,84 @@ func executeCleanup(cfgPath, projName string, all, dryRun bool, cType string, ye
 		}
 
 		if dryRun {
-			fmt.Printf("\n%s Dry-run mode enabled - no changes applied\n", yellow("ℹ️"))
+			theme.Printf("\n%s Dry-run mode enabled - no changes applied\n", yellow("ℹ️"))
 			continue
 		}
 
 		if !shouldProceedWithCleanup(hasItems, yes, yellow) {
-			fmt.Println("Cleanup cancelled.")
+			theme.Println("Cleanup cancelled.")
 			continue
 		}
 
 		// Perform cleanup
-		fmt.Println()
+		theme.Println()
 		if hasConversions {
-			fmt.Printf("%s Removing conversion events...\n", red("🗑"))
+			theme.Printf("%s Removing conversion events...\n", red("🗑"))
 			for _, eventName := range cfg.Cleanup.ConversionsToRemove {
 				err := client.DeleteConversion(propertyID, eventName)
 				if err != nil {
 					if strings.Contains(err.Error(), "not found") {
-						fmt.Printf("  %s %s (already removed)\n", yellow("○"), eventName)
+						theme.Printf("  %s %s (already removed)\n", yellow("○"), eventName)
 					} else {
-						fmt.Printf("  %s %s: %s\n", red("✗"), eventName, err)
+						theme.Printf("  %s %s: %s\n", red("✗"), eventName, err)
 					}
 				} else {
-					fmt.Printf("  %s %s\n", green("✓"), eventName)
+					theme.Printf("  %s %s\n", green("✓"), eventName)
 				}
 			}
 		}
 
 		if hasDimensions {
-			fmt.Printf("\n%s Archiving custom dimensions...\n", red("🗑"))
+			theme.Printf("\n%s Archiving custom dimensions...\n", red("🗑"))
 			for _, paramName := range cfg.Cleanup.DimensionsToRemove {
 				err := client.DeleteDimension(propertyID, paramName)
 				if err != nil {
 					if strings.Contains(err.Error(), "not found") {
-						fmt.Printf("  %s %s (already archived)\n", yellow("○"), paramName)
+						theme.Printf("  %s %s (already archived)\n", yellow("○"), paramName)
 					} else {
-						fmt.Printf("  %s %s: %s\n", red("✗"), paramName, err)
+						theme.Printf("  %s %s: %s\n", red("✗"), paramName, err)
 					}
 				} else {
-					fmt.Printf("  %s %s\n", green("✓"), paramName)
+					theme.Printf("  %s %s\n", green("✓"), paramName)
 				}
 			}
 		}
 
 		if hasMetrics {
-			fmt.Printf("\n%s Archiving custom metrics...\n", red("🗑"))
+			theme.Printf("\n%s Archiving custom metrics...\n", red("🗑"))
 			for _, paramName := range cfg.Cleanup.MetricsToRemove {
 				err := client.DeleteMetric(propertyID, paramName)
 				if err != nil {
 					if strings.Contains(err.Error(), "not found") {
-						fmt.Printf("  %s %s (already archived)\n", yellow("○"), paramName)
+						theme.Printf("  %s %s (already archived)\n", yellow("○"), paramName)
 					} else {
-						fmt.Printf("  %s %s: %s\n", red("✗"), paramName, err)
+						theme.Printf("  %s %s: %s\n", red("✗"), paramName, err)
 					}
 				} else {
-					fmt.Printf("  %s %s\n", green("✓"), paramName)
+					theme.Printf("  %s %s\n", green("✓"), paramName)
 				}
 			}
 		}
 	}
 
-	fmt.Println()
-	fmt.Println("═══════════════════════════════════════════════")
+	theme.Println()
+	theme.Println("═══════════════════════════════════════════════")
 	if dryRun {
-		fmt.Printf("%s Dry-run complete! No changes were applied.\n", blue("ℹ️"))
+		theme.Printf("%s Dry-run complete! No changes were applied.\n", blue("ℹ️"))
 	} else {
-		fmt.Printf("%s Cleanup complete!\n", green("✅"))
+		theme.Printf("%s Cleanup complete!\n", green("✅"))
 	}
-	fmt.Println()
-	fmt.Println("Next steps:")
+	theme.Println()
+	theme.Println("Next steps:")
 	if dryRun {
-		fmt.Println("1. Review the changes above")
-		fmt.Println("2. Run without --dry-run to apply changes")
+		theme.Println("1. Review the changes above")
+		theme.Println("2. Run without --dry-run to apply changes")
 	} else {
-		fmt.Println("1. Verify in GA4: https://analytics.google.com")
-		fmt.Println("2. Run 'ga4 report' to see updated configuration")
-		fmt.Println("3. Historical data for removed events is preserved")
+		theme.Println("1. Verify in GA4: https://analytics.google.com")
+		theme.Println("2. Run 'ga4 report' to see updated configuration")
+		theme.Println("3. Historical data for removed events is preserved")
 	}
-	fmt.Println()
+	theme.Println()
 
 	return nil
 }
@@ -234,7 +274,7 @@ func handleCleanupAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
@@ -247,12 +287,12 @@ func handleCleanupAction() {
 		cfgPath = projectPath
 	}
 
-	fmt.Println("\n🧹 Running cleanup in dry-run mode (preview only)...")
-	fmt.Println("To apply changes, use: ga4 cleanup --config", projectPath)
-	fmt.Println()
+	theme.Println("\n🧹 Running cleanup in dry-run mode (preview only)...")
+	theme.Println("To apply changes, use: ga4 cleanup --config", projectPath)
+	theme.Println()
 
 	if err := executeCleanup(cfgPath, "", all, true, "all", false); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running cleanup: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error running cleanup: %v\n", err)
 	}
 }
 
@@ -277,8 +317,8 @@ func shouldProceedWithCleanup(hasItems, skipConfirmation bool, yellow func(a ...
 		return true
 	}
 
-	fmt.Printf("\n%s This will permanently remove/archive the items shown above.\n", yellow("⚠"))
-	fmt.Print("Do you want to continue? [y/N]: ")
+	theme.Printf("\n%s This will permanently remove/archive the items shown above.\n", yellow("⚠"))
+	theme.Print("Do you want to continue? [y/N]: ")
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// pruneArchiver is the slice of the GA4 client --prune archives with.
+type pruneArchiver interface {
+	DeleteConversion(propertyID, eventName string) error
+	DeleteDimension(propertyID, parameterName string) error
+	DeleteMetric(propertyID, parameterName string) error
+}
+
+// executePrune archives what each project's Property has and its config does
+// not declare, as setup.BuildPrune finds it. Without yes every resource is
+// confirmed one by one.
+func executePrune(cfgPath, projName string, all, dryRun bool, cType string, yes bool, protect []string) error {
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+
+	theme.Println("🧹 GA4 Manager - Cleanup (prune drift)")
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Println()
+
+	if err := validateCleanupType(cType); err != nil {
+		return err
+	}
+
+	projects, err := loadProjects(cfgPath, projName, all)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGA4Client(projects...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	in := bufio.NewReader(os.Stdin)
+	for _, cfg := range projects {
+		if !cfg.HasAnalytics() {
+			continue
+		}
+		propertyID := cfg.GetPropertyID()
+		theme.Printf("\n📦 %s: %s (Property: %s)\n", cyan("Project"), cfg.Project.Name, propertyID)
+		theme.Println("───────────────────────────────────────────────")
+
+		diff, err := setup.BuildPrune(cfg, client, slices.Concat(cfg.Cleanup.Protected, protect))
+		if err != nil {
+			return fmt.Errorf("%s: %w", cfg.Project.Name, err)
+		}
+		changes := pruneChanges(diff.Changes, cType)
+		if len(changes) == 0 {
+			theme.Printf("%s Nothing on the property is missing from the config\n", cyan("✓"))
+			continue
+		}
+
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			pruneColumns(), changes, pruneTableRow,
+		); err != nil {
+			return fmt.Errorf("failed to render prune table: %w", err)
+		}
+
+		if dryRun {
+			theme.Printf("\n%s Dry-run mode enabled - no changes applied\n", yellow("ℹ️"))
+			continue
+		}
+
+		selected := pruneSelection(changes)
+		if !yes && len(selected) > 0 {
+			theme.Printf("\n%s Archive each resource not in the config? [y]es, [N]o, [a]ll remaining, [q]uit\n", yellow("⚠"))
+			selected = confirmPrunes(in, os.Stdout, selected)
+		}
+		if len(selected) == 0 {
+			theme.Println("Nothing archived.")
+			continue
+		}
+		theme.Println()
+		applyPrunes(client, propertyID, selected)
+	}
+
+	theme.Println()
+	theme.Println("═══════════════════════════════════════════════")
+	if dryRun {
+		theme.Printf("%s Dry-run complete! No changes were applied.\n", blue("ℹ️"))
+		theme.Println("Protect what must stay with cleanup.protected in the config or --protect.")
+	} else {
+		theme.Printf("%s Prune complete! Historical data for archived resources is preserved.\n", blue("ℹ️"))
+	}
+	theme.Println()
+	return nil
+}
+
+// pruneChanges keeps the changes of the resource types cType selects.
+func pruneChanges(changes []setup.Change, cType string) []setup.Change {
+	var kept []setup.Change
+	for _, c := range changes {
+		if cType == "all" || cleanupResourceType(c.Resource) == cType {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// cleanupResourceType names a diff resource as --type does.
+func cleanupResourceType(resource string) string {
+	switch resource {
+	case setup.DiffResourceConversion:
+		return "conversions"
+	case setup.DiffResourceDimension:
+		return "dimensions"
+	case setup.DiffResourceMetric:
+		return "metrics"
+	}
+	return resource
+}
+
+// pruneSelection drops the protected changes.
+func pruneSelection(changes []setup.Change) []setup.Change {
+	var selected []setup.Change
+	for _, c := range changes {
+		if c.Action == setup.DiffActionPrune {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// confirmPrunes asks about each change on w and returns those confirmed. "a"
+// confirms the rest, "q" or the end of input stops asking; anything but "y"
+// skips a change.
+func confirmPrunes(in *bufio.Reader, w io.Writer, changes []setup.Change) []setup.Change {
+	var confirmed []setup.Change
+	for i, c := range changes {
+		_, _ = fmt.Fprintf(w, "  Archive %s %s? [y/N/a/q]: ", c.Resource, c.Name)
+		answer, err := in.ReadString('\n')
+		if err != nil && answer == "" {
+			_, _ = fmt.Fprintln(w)
+			return confirmed
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			confirmed = append(confirmed, c)
+		case "a", "all":
+			return append(confirmed, changes[i:]...)
+		case "q", "quit":
+			return confirmed
+		}
+	}
+	return confirmed
+}
+
+// applyPrunes archives each change, reporting the outcome as cleanup does.
+func applyPrunes(client pruneArchiver, propertyID string, changes []setup.Change) {
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+
+	theme.Printf("%s Archiving resources not in the config...\n", red("🗑"))
+	for _, c := range changes {
+		var err error
+		switch c.Resource {
+		case setup.DiffResourceConversion:
+			err = client.DeleteConversion(propertyID, c.Name)
+		case setup.DiffResourceDimension:
+			err = client.DeleteDimension(propertyID, c.Name)
+		case setup.DiffResourceMetric:
+			err = client.DeleteMetric(propertyID, c.Name)
+		}
+		switch {
+		case err != nil && strings.Contains(err.Error(), "not found"):
+			theme.Printf("  %s %s %s (already archived)\n", yellow("○"), c.Resource, c.Name)
+		case err != nil:
+			theme.Printf("  %s %s %s: %s\n", red("✗"), c.Resource, c.Name, err)
+		default:
+			theme.Printf("  %s %s %s\n", green("✓"), c.Resource, c.Name)
+		}
+	}
+}
+
+// pruneColumns / pruneTableRow project the prune preview table.
+func pruneColumns() []string { return []string{"Type", "Name", "Status"} }
+
+func pruneTableRow(c setup.Change) []string {
+	status := "Will be archived"
+	switch {
+	case c.Action == setup.DiffActionProtected:
+		status = "Protected"
+	case c.Resource == setup.DiffResourceConversion:
+		status = "Will be deleted"
+	}
+	return []string{c.Resource, c.Name, status}
+}
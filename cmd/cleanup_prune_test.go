@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/garbarok/ga4-manager/internal/setup"
+)
+
+// fakeArchiver records what applyPrunes archives.
+type fakeArchiver struct {
+	archived []string
+	errs     map[string]error
+}
+
+func (f *fakeArchiver) archive(kind, name string) error {
+	if err := f.errs[name]; err != nil {
+		return err
+	}
+	f.archived = append(f.archived, kind+" "+name)
+	return nil
+}
+
+func (f *fakeArchiver) DeleteConversion(_, name string) error { return f.archive("conversion", name) }
+func (f *fakeArchiver) DeleteDimension(_, name string) error  { return f.archive("dimension", name) }
+func (f *fakeArchiver) DeleteMetric(_, name string) error     { return f.archive("metric", name) }
+
+func pruneFixture() []setup.Change {
+	return []setup.Change{
+		{Resource: setup.DiffResourceConversion, Name: "old_lead", Action: setup.DiffActionPrune},
+		{Resource: setup.DiffResourceConversion, Name: "purchase", Action: setup.DiffActionProtected},
+		{Resource: setup.DiffResourceDimension, Name: "word_count", Action: setup.DiffActionPrune},
+		{Resource: setup.DiffResourceMetric, Name: "reading_time", Action: setup.DiffActionPrune},
+	}
+}
+
+func pruneNames(changes []setup.Change) []string {
+	var out []string
+	for _, c := range changes {
+		out = append(out, c.Name)
+	}
+	return out
+}
+
+func TestPruneSelection(t *testing.T) {
+	assert.Equal(t, []string{"old_lead", "word_count", "reading_time"}, pruneNames(pruneSelection(pruneFixture())))
+	assert.Equal(t, []string{"word_count"}, pruneNames(pruneChanges(pruneFixture(), "dimensions")))
+	assert.Len(t, pruneChanges(pruneFixture(), "all"), 4)
+}
+
+func TestConfirmPrunes(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers string
+		want    []string
+	}{
+		{"one by one", "y\nn\ny\n", []string{"old_lead", "reading_time"}},
+		{"default is no", "\n\n\n", nil},
+		{"all remaining", "n\na\n", []string{"word_count", "reading_time"}},
+		{"quit", "y\nq\n", []string{"old_lead"}},
+		{"end of input", "y\n", []string{"old_lead"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got := confirmPrunes(bufio.NewReader(strings.NewReader(tt.answers)), &out, pruneSelection(pruneFixture()))
+			assert.Equal(t, tt.want, pruneNames(got))
+			assert.Contains(t, out.String(), "Archive conversion old_lead? [y/N/a/q]: ")
+		})
+	}
+}
+
+func TestApplyPrunes(t *testing.T) {
+	client := &fakeArchiver{errs: map[string]error{"word_count": errors.New("custom dimension not found")}}
+
+	applyPrunes(client, "123", pruneSelection(pruneFixture()))
+
+	assert.Equal(t, []string{"conversion old_lead", "metric reading_time"}, client.archived)
+}
@@ -2,21 +2,118 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
 )
 
+// Run-wide timeout, retry, cache and paging flags, registered on rootCmd so every
+// command honours them.
+var (
+	runTimeout   time.Duration
+	opTimeoutRaw map[string]string
+	maxRetries   int
+	noCache      bool
+	cacheTTL     time.Duration
+	pageSize     int64
+)
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&runTimeout, "timeout", 0, "Overall deadline for the run, e.g. 10m (default: client default of 5m)")
+	rootCmd.PersistentFlags().StringToStringVar(&opTimeoutRaw, "op-timeout", nil, "Per-operation API timeouts, e.g. list=20s,create=1m (verbs: create, list, get, update, delete, query, inspect)")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", config.DefaultClientConfig().Retry.MaxRetries, "Retries of API calls failing with 429 or 5xx, with exponential backoff (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always list GA4 conversions, dimensions and metrics from the API")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 0, "Also keep GA4 list results on disk for this long, shared by consecutive commands, e.g. 10m")
+	rootCmd.PersistentFlags().Int64Var(&pageSize, "page-size", ga4.DefaultPageSize, fmt.Sprintf("Items per page of GA4 Admin API list calls, 1-%d; every page is always fetched", ga4.DefaultPageSize))
+}
+
+// clientRetry builds the retry configuration from --max-retries on top of
+// the client defaults.
+func clientRetry() (config.RetryConfig, error) {
+	r := config.DefaultClientConfig().Retry
+	if maxRetries < 0 {
+		return r, fmt.Errorf("--max-retries must not be negative, got %d", maxRetries)
+	}
+	r.MaxRetries = maxRetries
+	return r, nil
+}
+
+// clientTimeouts builds the timeout configuration from --timeout and
+// --op-timeout on top of the client defaults.
+func clientTimeouts() (config.TimeoutConfig, error) {
+	t := config.DefaultClientConfig().Timeouts
+	if runTimeout < 0 {
+		return t, fmt.Errorf("--timeout must not be negative, got %s", runTimeout)
+	}
+	if runTimeout > 0 {
+		t.ContextTimeout = runTimeout
+	}
+	if len(opTimeoutRaw) > 0 {
+		t.Operations = make(map[string]time.Duration, len(opTimeoutRaw))
+		for verb, raw := range opTimeoutRaw {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				return t, fmt.Errorf("invalid --op-timeout %s=%s: must be a positive duration like 30s", verb, raw)
+			}
+			t.Operations[strings.ToLower(verb)] = d
+		}
+	}
+	return t, nil
+}
+
 // newGA4Client constructs a GA4 Admin API client, wrapping construction failures
 // with a uniform message. Callers own the returned client's lifecycle and must
-// defer client.Close().
-//
-// (No GSC equivalent: every gsc.NewClient call site already constructs and
-// closes its client consistently, so a wrapper would add indirection without
-// removing duplication.)
-func newGA4Client() (*ga4.Client, error) {
-	client, err := ga4.NewClient()
+// defer client.Close(). Commands working on configs pass them all, so each
+// property's alpha surfaces are gated by its own analytics.features; a client
+// built without configs uses the registered feature defaults.
+func newGA4Client(configs ...*config.ProjectConfig) (*ga4.Client, error) {
+	timeouts, err := clientTimeouts()
+	if err != nil {
+		return nil, err
+	}
+	retry, err := clientRetry()
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.DefaultClientConfig()
+	cfg.Timeouts = timeouts
+	cfg.Retry = retry
+
+	if cacheTTL < 0 {
+		return nil, fmt.Errorf("--cache-ttl must not be negative, got %s", cacheTTL)
+	}
+	cache := ga4.WithCache(ga4.CacheConfig{Disabled: noCache, TTL: cacheTTL})
+	if pageSize < 1 || pageSize > ga4.DefaultPageSize {
+		return nil, fmt.Errorf("--page-size must be between 1 and %d, got %d", ga4.DefaultPageSize, pageSize)
+	}
+
+	opts := []ga4.ClientOption{ga4.WithConfig(cfg), cache, ga4.WithPageSize(pageSize), ga4.WithLogger(cliLogger)}
+	for _, project := range configs {
+		opts = append(opts, ga4.WithPropertyFeatures(project.GetPropertyID(), project.Features()))
+	}
+
+	client, err := ga4.NewClient(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GA4 client: %w", err)
 	}
 	return client, nil
 }
+
+// gscClientOptions returns the options every GSC command passes to
+// gsc.NewClient so --timeout, --op-timeout, --max-retries and --log-level
+// apply to Search Console calls too. Flag errors surface through gsc.NewClient like any other
+// option error.
+func gscClientOptions() []gsc.ClientOption {
+	timeouts, err := clientTimeouts()
+	if err != nil {
+		return []gsc.ClientOption{func(*gsc.Client) error { return err }}
+	}
+	retry, err := clientRetry()
+	if err != nil {
+		return []gsc.ClientOption{func(*gsc.Client) error { return err }}
+	}
+	return []gsc.ClientOption{gsc.WithTimeouts(timeouts), gsc.WithRetry(retry), gsc.WithLogger(cliLogger)}
+}
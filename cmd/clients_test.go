@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// A client built from configs gates each property by its own flags.
+func TestNewGA4Client_GatesConfigFeatures(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "user.json")
+	require.NoError(t, os.WriteFile(key, []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh"}`), 0o600))
+	t.Setenv(auth.CredentialsEnvVar, key)
+
+	off := &config.ProjectConfig{Analytics: &config.AnalyticsConfig{
+		PropertyID: "111",
+		Features:   map[string]bool{string(config.FeatureChannelGroups): false},
+	}}
+	client, err := newGA4Client(off)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.ListChannelGroups("111")
+	assert.ErrorIs(t, err, ga4.ErrFeatureDisabled)
+	assert.ErrorIs(t, client.DeleteChannelGroup("properties/111/channelGroups/1"), ga4.ErrFeatureDisabled)
+}
+
+// configLoaders are the calls through which a command gets its configs.
+var configLoaders = map[string]bool{
+	"LoadConfig":         true,
+	"LoadConfigByName":   true,
+	"loadProjects":       true,
+	"loadProjectConfigs": true,
+	"loadPromoteConfig":  true,
+}
+
+// A command that has a config in hand must hand it to newGA4Client, or the
+// config's analytics.features are silently ignored.
+func TestNewGA4Client_ConfigCommandsPassTheirConfigs(t *testing.T) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || name == "clients.go" {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(file, func(n ast.Node) bool {
+			var fnType *ast.FuncType
+			var body *ast.BlockStmt
+			switch fn := n.(type) {
+			case *ast.FuncDecl:
+				fnType, body = fn.Type, fn.Body
+			case *ast.FuncLit:
+				fnType, body = fn.Type, fn.Body
+			default:
+				return true
+			}
+			if body == nil || !hasConfig(fnType, body) {
+				return true
+			}
+			ast.Inspect(body, func(n ast.Node) bool {
+				if _, ok := n.(*ast.FuncLit); ok {
+					return false // checked on its own
+				}
+				if call, ok := n.(*ast.CallExpr); ok && calledName(call) == "newGA4Client" && len(call.Args) == 0 {
+					t.Errorf("%s: newGA4Client() without the config in scope; pass it so its feature flags apply", fset.Position(call.Pos()))
+				}
+				return true
+			})
+			return true
+		})
+	}
+}
+
+// hasConfig reports whether a function takes a *config.ProjectConfig or
+// loads one itself.
+func hasConfig(fnType *ast.FuncType, body *ast.BlockStmt) bool {
+	for _, field := range fnType.Params.List {
+		if star, ok := field.Type.(*ast.StarExpr); ok {
+			if sel, ok := star.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "ProjectConfig" {
+				return true
+			}
+		}
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok && configLoaders[calledName(call)] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func calledName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return ""
+}
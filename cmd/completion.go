@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate the shell completion script",
+	Long: `Print a completion script for your shell. Besides commands and flags it
+completes --config with the YAML files of the config directory, --project
+with config names and --format with the formats each command accepts.
+
+Bash (needs the bash-completion package):
+  source <(ga4 completion bash)
+  # every session:
+  ga4 completion bash > /etc/bash_completion.d/ga4
+
+Zsh:
+  ga4 completion zsh > "${fpath[1]}/_ga4"
+  # compinit must be enabled: echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+Fish:
+  ga4 completion fish > ~/.config/fish/completions/ga4.fish
+
+PowerShell:
+  ga4 completion powershell | Out-String | Invoke-Expression
+  # every session: add the line above to your $PROFILE
+
+Open a new shell for the completion to take effect.`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		return fmt.Errorf("unsupported shell %q", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// registerFlagCompletions gives every --config, --project and --format flag
+// under cmd dynamic completion. It runs once all commands are added, so new
+// commands get it without registering anything themselves.
+func registerFlagCompletions(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := cmd.GetFlagCompletionFunc(f.Name); ok {
+			return
+		}
+		var complete cobra.CompletionFunc
+		switch {
+		case f.Name == "format":
+			if formats := output.Formats(f); formats != nil {
+				complete = cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp)
+			}
+		case f.Name == "config" && f.Value.Type() == "string":
+			complete = completeConfigFiles
+		case f.Name == "project" && f.Value.Type() == "string":
+			complete = completeProjectNames
+		}
+		if complete != nil {
+			_ = cmd.RegisterFlagCompletionFunc(f.Name, complete)
+		}
+	})
+	for _, sub := range cmd.Commands() {
+		registerFlagCompletions(sub)
+	}
+}
+
+// completeConfigFiles lists the YAML files of the config directory and its
+// examples. With none there it falls back to the shell's own completion of
+// .yaml files.
+func completeConfigFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	files := configFiles(config.ConfigDir())
+	var matches []string
+	for _, f := range files {
+		if strings.HasPrefix(f, toComplete) {
+			matches = append(matches, f)
+		}
+	}
+	if len(matches) == 0 {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// configFiles returns the .yaml and .yml files of dir and dir/examples,
+// sorted.
+func configFiles(dir string) []string {
+	var files []string
+	for _, d := range []string{dir, filepath.Join(dir, "examples")} {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, filepath.Join(d, e.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// completeProjectNames lists the config names --project accepts.
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.DefaultRegistry().Names()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
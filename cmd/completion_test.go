@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+func TestRegisterFlagCompletions(t *testing.T) {
+	var format, cfg, logFormat string
+	root := &cobra.Command{Use: "ga4"}
+	sub := &cobra.Command{Use: "probe", Run: func(*cobra.Command, []string) {}}
+	output.FormatVar(sub.Flags(), &format, "f", output.FormatTable, output.FormatJSON)
+	sub.Flags().StringVar(&cfg, "config", "", "")
+	sub.Flags().StringVar(&logFormat, "log-format", "", "")
+	root.AddCommand(sub)
+
+	registerFlagCompletions(root)
+	registerFlagCompletions(root) // a second pass keeps the first functions
+
+	complete, ok := sub.GetFlagCompletionFunc("format")
+	require.True(t, ok)
+	formats, directive := complete(sub, nil, "")
+	assert.Equal(t, []string{"table", "json"}, formats)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	_, ok = sub.GetFlagCompletionFunc("config")
+	assert.True(t, ok)
+	_, ok = sub.GetFlagCompletionFunc("log-format")
+	assert.False(t, ok)
+}
+
+func TestCompleteConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "examples"), 0o755))
+	for _, f := range []string{"mysite.yaml", "shop.yml", "notes.txt", "examples/template.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o644))
+	}
+	config.SetConfigDir(dir)
+	t.Cleanup(func() { config.SetConfigDir("") })
+
+	files, directive := completeConfigFiles(nil, nil, "")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "examples", "template.yaml"),
+		filepath.Join(dir, "mysite.yaml"),
+		filepath.Join(dir, "shop.yml"),
+	}, files)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	files, directive = completeConfigFiles(nil, nil, "elsewhere/")
+	assert.Equal(t, []string{"yaml", "yml"}, files, "paths outside the config directory fall back to file completion")
+	assert.Equal(t, cobra.ShellCompDirectiveFilterFileExt, directive)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/theme"
+	"github.com/garbarok/ga4-manager/internal/tui"
+)
+
+var (
+	configInitName       string
+	configInitPropertyID string
+	configInitSiteURL    string
+	configInitType       string
+	configInitTier       string
+	configInitOutput     string
+	configInitForce      bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create project configs from a wizard or a live property",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold a starter project config with a wizard",
+	Long: `Ask for the GA4 property ID, Search Console site URL, site type and GA4 tier,
+and write a starter config to the config directory (configs/ unless
+--config-dir says otherwise).
+
+The site type (` + strings.Join(config.SiteTypes(), ", ") + `) picks the starter
+conversions, dimensions, metrics and enhanced measurement settings; the
+enhanced measurement recommendations for it are written as comments at the
+top of the file. GA4 Standard gets the high and medium priority resources,
+GA4 360 the low priority ones too, within each tier's limits.
+
+The flags prefill the wizard's answers. With --name, --type and a property
+ID or site URL the wizard is skipped, for scripts.`,
+	Example: `  # Answer the questions interactively
+  ga4 config init
+
+  # No prompts
+  ga4 config init --name "My Shop" --type ecommerce --property-id 123456789 --site-url https://shop.example.com/`,
+	RunE: runConfigInit,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd)
+
+	configInitCmd.Flags().StringVar(&configInitName, "name", "", "Project name")
+	configInitCmd.Flags().StringVar(&configInitPropertyID, "property-id", "", "GA4 property ID")
+	configInitCmd.Flags().StringVar(&configInitSiteURL, "site-url", "", "Search Console site URL (https://example.com/ or sc-domain:example.com)")
+	configInitCmd.Flags().StringVar(&configInitType, "type", "", "Site type: "+strings.Join(config.SiteTypes(), ", "))
+	configInitCmd.Flags().StringVar(&configInitTier, "tier", "", "GA4 tier: standard or 360 (default standard)")
+	configInitCmd.Flags().StringVarP(&configInitOutput, "output", "o", "", "Config file to write (default <config-dir>/<name>.yaml)")
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "Overwrite the output file if it exists")
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	answers := tui.ProjectAnswers{
+		Name:       configInitName,
+		PropertyID: configInitPropertyID,
+		SiteURL:    configInitSiteURL,
+		SiteType:   configInitType,
+		Tier:       configInitTier,
+		OutputPath: configInitOutput,
+	}
+	if answers.Name == "" || answers.SiteType == "" || (answers.PropertyID == "" && answers.SiteURL == "") {
+		if err := tui.RunConfigWizard(&answers, config.SiteTypes()); err != nil {
+			return err
+		}
+	}
+	if answers.OutputPath == "" {
+		answers.OutputPath = filepath.Join(config.ConfigDir(), configFileName(answers.Name)+".yaml")
+	}
+
+	cfg, err := config.Scaffold(config.ScaffoldOptions{
+		Name:       answers.Name,
+		PropertyID: answers.PropertyID,
+		SiteURL:    answers.SiteURL,
+		SiteType:   answers.SiteType,
+		Tier:       answers.Tier,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := renderStarterConfig(cfg, answers.SiteType)
+	if err != nil {
+		return err
+	}
+
+	if err := writeConfigFile(answers.OutputPath, data, configInitForce); err != nil {
+		return err
+	}
+
+	green := theme.Color(color.FgGreen).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	theme.Printf("\n%s Created %s: %d conversions, %d dimensions, %d metrics\n",
+		green("✓"), answers.OutputPath, len(cfg.Conversions), len(cfg.Dimensions), len(cfg.Metrics))
+	theme.Println("\n📚 Next steps:")
+	theme.Println("  1. Review and edit the config")
+	theme.Println("  2. " + cyan("ga4 validate "+answers.OutputPath))
+	theme.Println("  3. " + cyan("ga4 setup --config "+answers.OutputPath+" --dry-run"))
+	return nil
+}
+
+// renderStarterConfig encodes a scaffolded config behind a header with the
+// data stream recommendations for the site type.
+func renderStarterConfig(cfg *config.ProjectConfig, siteType string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s: starter %s config generated by `ga4 config init`.\n", cfg.Project.Name, siteType)
+	if cfg.HasAnalytics() {
+		fmt.Fprintf(&buf, "# Resources are sized for %s.\n", config.GetTierName(cfg.GA4.Tier))
+		buf.WriteString("#\n# Data stream recommendations:\n")
+		for _, r := range ga4.GetDataStreamRecommendations(siteType) {
+			fmt.Fprintf(&buf, "#   - %s\n", r)
+		}
+	}
+	buf.WriteString("#\n# Field reference: configs/examples/README.md\n\n")
+	if err := encodeConfig(&buf, cfg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeConfig appends cfg to buf as YAML with the two-space indent of the
+// example configs.
+func encodeConfig(buf *bytes.Buffer, cfg *config.ProjectConfig) error {
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	return nil
+}
+
+// writeConfigFile writes a generated config, refusing to replace an
+// existing file unless force is set.
+func writeConfigFile(path string, data []byte, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// configFileName turns a project name into a file name: "My Shop!" becomes
+// "my-shop".
+func configFileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if s := strings.TrimSuffix(b.String(), "-"); s != "" {
+		return s
+	}
+	return "project"
+}
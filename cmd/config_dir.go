@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// configDirFlag is the --config-dir flag; empty keeps $GA4_CONFIG_DIR or
+// configs/.
+var configDirFlag string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configDirFlag, "config-dir", "", "Directory project configs are looked up in by name (default $"+config.ConfigDirEnvVar+" or configs)")
+	cobra.OnInitialize(applyConfigDir)
+}
+
+// applyConfigDir points --project, --all and the project selector at the
+// chosen directory once flags are parsed.
+func applyConfigDir() {
+	if configDirFlag != "" {
+		config.SetConfigDir(configDirFlag)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/depgraph"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// Graph formats of ga4 config graph.
+const (
+	graphFormatMermaid = "mermaid"
+	graphFormatDOT     = "dot"
+)
+
+var (
+	configGraphConfig string
+	configGraphFormat string
+	configGraphOutput string
+)
+
+var configGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Draw the dependencies between the config's resources",
+	Long: `Draw how the resources of a config depend on each other:
+
+  event      → dimension, metric   the conversion sends the parameter
+  event      → audience            the audience is built on the event
+  dimension  → audience            the audience uses the parameter
+  metric     → calculated metric   the formula references the custom metric
+
+Events send the parameters in their conversion's parameters list; audiences
+are built on their events and parameters lists:
+
+  audiences:
+    - name: Pro buyers
+      duration: 30
+      events: [purchase]
+      parameters: [plan_type]
+
+References to resources the config does not define (an audience's event
+that is no conversion or automatically collected event, a parameter that is
+no custom dimension or metric, a custom metric in a formula) are drawn
+dashed and red and listed as warnings on stderr. ga4 setup's preflight
+reports the same warnings.
+
+--format mermaid (default) renders on GitHub and GitLab inside a mermaid
+code block; --format dot is for Graphviz.
+
+Examples:
+  ga4 config graph --config configs/mysite.yaml
+  ga4 config graph --config configs/mysite.yaml --format dot | dot -Tsvg > graph.svg`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runConfigGraph(configGraphParams{
+			ConfigPath: configGraphConfig,
+			Format:     configGraphFormat,
+			Output:     configGraphOutput,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		})
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGraphCmd)
+	configGraphCmd.Flags().StringVarP(&configGraphConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(configGraphCmd.Flags(), &configGraphFormat, "f", graphFormatMermaid, graphFormatDOT, output.FormatJSON)
+	configGraphCmd.Flags().StringVarP(&configGraphOutput, "output", "o", "", "Write the graph to this file instead of stdout")
+	_ = configGraphCmd.MarkFlagRequired("config")
+}
+
+type configGraphParams struct {
+	ConfigPath string
+	Format     string
+	Output     string
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+func runConfigGraph(p configGraphParams) error {
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	g := depgraph.Build(cfg)
+
+	out := p.Stdout
+	var f *os.File
+	if p.Output != "" {
+		if f, err = os.Create(p.Output); err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		out = f
+	}
+	switch p.Format {
+	case output.FormatJSON:
+		err = output.JSON(out, g)
+	case graphFormatDOT:
+		err = g.DOT(out)
+	default:
+		err = g.Mermaid(out)
+	}
+	if f != nil {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	for _, w := range g.Warnings() {
+		theme.Fprintf(p.Stderr, "%s %s\n", theme.YellowString("⚠"), w)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configGraphTestConfig = `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+    parameters: [value, plan_type]
+dimensions:
+  - parameter: plan_type
+    display_name: Plan Type
+    scope: EVENT
+audiences:
+  - name: Leads
+    description: Users who asked for a quote
+    duration: 30
+    events: [generate_lead]
+`
+
+func TestRunConfigGraph(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(t, runConfigGraph(configGraphParams{
+		ConfigPath: writeSummaryConfig(t, configGraphTestConfig),
+		Format:     graphFormatMermaid,
+		Stdout:     stdout,
+		Stderr:     stderr,
+	}))
+	assert.Contains(t, stdout.String(), "flowchart LR\n")
+	assert.Contains(t, stdout.String(), "  n0 --> n1\n")
+	assert.Contains(t, stderr.String(), `audience "Leads" is built on event generate_lead, which is not a conversion in the config`)
+
+	path := filepath.Join(t.TempDir(), "graph.dot")
+	require.NoError(t, runConfigGraph(configGraphParams{
+		ConfigPath: writeSummaryConfig(t, configGraphTestConfig),
+		Format:     graphFormatDOT,
+		Output:     path,
+		Stdout:     stdout,
+		Stderr:     &bytes.Buffer{},
+	}))
+	dot, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(dot), `"event:purchase" -> "dimension:plan_type";`)
+}
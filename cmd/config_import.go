@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	configImportPropertyID string
+	configImportSiteURL    string
+	configImportName       string
+	configImportOutput     string
+	configImportForce      bool
+)
+
+var configImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Write a live property's setup as a project config",
+	Long: `Read a GA4 property, and optionally its Search Console site, and write a project
+config that describes it: conversions (with default values), custom dimensions
+and metrics, calculated metrics, custom channel groups, data retention and
+enhanced measurement, plus the sitemaps submitted for the site.
+
+Running ` + "`ga4 setup`" + ` or ` + "`ga4 plan`" + ` with the result against the same property
+finds nothing to change, so a property set up by hand can be managed as code
+from there on. Audiences are not imported: the Admin API cannot create them.
+
+The config goes to stdout unless --output is given. The project name defaults
+to the property's display name.`,
+	Example: `  # Print the config
+  ga4 config import --property-id 123456789
+
+  # Property and Search Console site, to a file
+  ga4 config import --property-id 123456789 --site-url sc-domain:example.com -o configs/example.yaml`,
+	RunE: runConfigImport,
+}
+
+func init() {
+	configCmd.AddCommand(configImportCmd)
+
+	configImportCmd.Flags().StringVar(&configImportPropertyID, "property-id", "", "GA4 property ID to import")
+	configImportCmd.Flags().StringVar(&configImportSiteURL, "site-url", "", "Search Console site whose sitemaps to import")
+	configImportCmd.Flags().StringVar(&configImportName, "name", "", "Project name (default: the property's display name)")
+	configImportCmd.Flags().StringVarP(&configImportOutput, "output", "o", "", "Config file to write (default stdout)")
+	configImportCmd.Flags().BoolVar(&configImportForce, "force", false, "Overwrite the output file if it exists")
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	if configImportPropertyID == "" && configImportSiteURL == "" {
+		return fmt.Errorf("specify --property-id, --site-url, or both")
+	}
+	opts := setup.ImportOptions{
+		Name:       configImportName,
+		PropertyID: configImportPropertyID,
+		SiteURL:    configImportSiteURL,
+	}
+	if opts.Name == "" {
+		opts.Name = configImportSiteURL
+	}
+
+	var lister setup.DiffLister
+	currency := ""
+	if opts.PropertyID != "" {
+		client, err := newGA4Client()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		property, err := client.GetProperty(opts.PropertyID)
+		if err != nil {
+			return err
+		}
+		if configImportName == "" {
+			opts.Name = property.DisplayName
+		}
+		currency = property.CurrencyCode
+		lister = client
+	}
+	if opts.SiteURL != "" {
+		client, err := gsc.NewClient(gscClientOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to create GSC client: %w", err)
+		}
+		defer func() { _ = client.Close() }()
+		opts.Sitemaps = client
+	}
+
+	cfg, err := setup.ImportConfig(opts, lister)
+	if err != nil {
+		return err
+	}
+	cfg.GA4.Currency = currency
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Imported by `ga4 config import` on %s.\n", time.Now().Format("2006-01-02"))
+	if opts.PropertyID != "" {
+		fmt.Fprintf(&buf, "# GA4 property %s", opts.PropertyID)
+		if opts.SiteURL != "" {
+			fmt.Fprintf(&buf, ", Search Console site %s", opts.SiteURL)
+		}
+		buf.WriteString(".\n")
+	} else {
+		fmt.Fprintf(&buf, "# Search Console site %s.\n", opts.SiteURL)
+	}
+	buf.WriteString("\n")
+	if err := encodeConfig(&buf, cfg); err != nil {
+		return err
+	}
+
+	// A property can hold what the config format rejects, such as more
+	// definitions than its tier allows; the file is still written to fix up.
+	if _, err := config.ParseConfig(buf.Bytes()); err != nil {
+		theme.Fprintf(os.Stderr, "⚠️  The imported config does not validate: %v\n", err)
+	}
+
+	if configImportOutput == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := writeConfigFile(configImportOutput, buf.Bytes(), configImportForce); err != nil {
+		return err
+	}
+	theme.Fprintf(os.Stderr, "✓ Imported %d conversions, %d dimensions, %d metrics, %d calculated metrics and %d channel groups to %s\n",
+		len(cfg.Conversions), len(cfg.Dimensions), len(cfg.Metrics), len(cfg.CalculatedMetrics), len(cfg.ChannelGroups), configImportOutput)
+	return nil
+}
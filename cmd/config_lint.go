@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/lint"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	configLintConfig  string
+	configLintFormat  string
+	configLintDisable []string
+	configLintFailOn  string
+)
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the config against GA4 and Search Console best practices",
+	Long: `Check a config for things that load fine but that GA4 or Search Console
+will reject, hide or misreport:
+
+  event-name-case       event names are snake_case (warning)
+  reserved-event-name   conversions do not use names GA4 collects itself,
+                        such as session_start, or the google_, ga_ and
+                        firebase_ prefixes (error)
+  unused-dimensions     no more than 25% of custom dimensions are sent by no
+                        conversion and used by no audience (info)
+  audience-duration     audience membership lasts at most 540 days (error)
+  sitemap-domain        sitemaps are on the Search Console property (error)
+
+unused-dimensions reads the conversions' and audiences' parameters lists
+(see ga4 config graph) and is quiet for a config without them.
+
+Disable rules for a project in its config, or for one run with --disable:
+
+  lint:
+    disable: [event-name-case]
+
+Exits 4 when a finding is at least as serious as --fail-on (default
+warning).
+
+Examples:
+  ga4 config lint --config configs/mysite.yaml
+  ga4 config lint --config configs/mysite.yaml --disable unused-dimensions --fail-on error`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return exitWith(cmd, runConfigLint(configLintParams{
+			ConfigPath: configLintConfig,
+			Format:     configLintFormat,
+			Disable:    configLintDisable,
+			FailOn:     configLintFailOn,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		}))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configLintCmd)
+	configLintCmd.Flags().StringVarP(&configLintConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(configLintCmd.Flags(), &configLintFormat, "f", output.FormatTable, output.FormatJSON)
+	configLintCmd.Flags().StringSliceVar(&configLintDisable, "disable", nil, "Rule IDs to skip, on top of the config's lint.disable")
+	configLintCmd.Flags().StringVar(&configLintFailOn, "fail-on", lint.SeverityWarning, "Lowest severity that makes the command exit 4: error, warning or info")
+	_ = configLintCmd.MarkFlagRequired("config")
+}
+
+type configLintParams struct {
+	ConfigPath string
+	Format     string
+	Disable    []string
+	FailOn     string
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+func runConfigLint(p configLintParams) int {
+	switch p.FailOn {
+	case lint.SeverityError, lint.SeverityWarning, lint.SeverityInfo:
+	default:
+		return diagcmd.FailWith(p.Stderr, "invalid --fail-on %q: use error, warning or info", p.FailOn)
+	}
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to load config: %v", err)
+	}
+	findings, err := lint.Run(cfg, append(lint.Disabled(cfg), p.Disable...))
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	if p.Format == output.FormatJSON {
+		if err := output.JSON(p.Stdout, findings); err != nil {
+			return diagcmd.FailWith(p.Stderr, "%v", err)
+		}
+	} else {
+		displayLintFindings(p.Stdout, findings)
+	}
+
+	for _, f := range findings {
+		if lint.AtLeast(f.Severity, p.FailOn) {
+			return diagcmd.ExitIssues
+		}
+	}
+	return diagcmd.ExitClean
+}
+
+func displayLintFindings(w io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		theme.Fprintf(w, "%s No lint findings\n", theme.GreenString("✓"))
+		return
+	}
+	for _, f := range findings {
+		mark := theme.HiBlackString("•")
+		switch f.Severity {
+		case lint.SeverityError:
+			mark = theme.RedString("✗")
+		case lint.SeverityWarning:
+			mark = theme.YellowString("⚠")
+		}
+		theme.Fprintf(w, "%s %s: %s %s\n", mark, f.Resource, f.Message, theme.HiBlackString("[%s]", f.Rule))
+	}
+}
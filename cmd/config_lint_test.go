@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/lint"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+const configLintTestConfig = `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+  - name: Newsletter_Signup
+    counting_method: ONCE_PER_EVENT
+lint:
+  disable: [unused-dimensions]
+`
+
+func TestRunConfigLint(t *testing.T) {
+	path := writeSummaryConfig(t, configLintTestConfig)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := runConfigLint(configLintParams{ConfigPath: path, Format: output.FormatTable, FailOn: lint.SeverityWarning, Stdout: stdout, Stderr: stderr})
+	assert.Equal(t, diagcmd.ExitIssues, code, stderr.String())
+	assert.Contains(t, stdout.String(), "event Newsletter_Signup: Newsletter_Signup is not snake_case")
+	assert.Contains(t, stdout.String(), "[event-name-case]")
+
+	stdout.Reset()
+	code = runConfigLint(configLintParams{ConfigPath: path, Format: output.FormatJSON, FailOn: lint.SeverityError, Stdout: stdout, Stderr: stderr})
+	assert.Equal(t, diagcmd.ExitClean, code, "warnings pass --fail-on error")
+	var findings []lint.Finding
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &findings))
+	require.Len(t, findings, 1)
+	assert.Equal(t, lint.SeverityWarning, findings[0].Severity)
+
+	stdout.Reset()
+	code = runConfigLint(configLintParams{ConfigPath: path, Format: output.FormatTable, Disable: []string{"event-name-case"}, FailOn: lint.SeverityWarning, Stdout: stdout, Stderr: stderr})
+	assert.Equal(t, diagcmd.ExitClean, code)
+	assert.Contains(t, stdout.String(), "No lint findings")
+}
+
+func TestRunConfigLint_BadRule(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	code := runConfigLint(configLintParams{
+		ConfigPath: writeSummaryConfig(t, configLintTestConfig),
+		Disable:    []string{"no-such-rule"},
+		FailOn:     lint.SeverityWarning,
+		Stdout:     &bytes.Buffer{},
+		Stderr:     stderr,
+	})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), `unknown lint rule "no-such-rule"`)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestRenderStarterConfig(t *testing.T) {
+	cfg, err := config.Scaffold(config.ScaffoldOptions{Name: "My Shop", PropertyID: "123456789", SiteType: config.SiteTypeEcommerce})
+	require.NoError(t, err)
+
+	data, err := renderStarterConfig(cfg, config.SiteTypeEcommerce)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "#   - Enable Form Interactions to track checkout and contact forms")
+
+	parsed, err := config.ParseConfig(data)
+	require.NoError(t, err, "the written config loads back")
+	assert.Equal(t, cfg.Conversions, parsed.Conversions)
+}
+
+func TestConfigFileName(t *testing.T) {
+	assert.Equal(t, "my-shop", configFileName("My Shop!"))
+	assert.Equal(t, "site-2", configFileName("  Site #2 "))
+	assert.Equal(t, "project", configFileName("???"))
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	conversionValuesConfig string
+	conversionValuesApply  bool
+	conversionValuesFormat string
+)
+
+// Per-event outcomes of comparing configured default values with the property.
+const (
+	conversionValueOK         = "ok"
+	conversionValueUpdate     = "update"      // configured value differs from the property
+	conversionValueUpdated    = "updated"     // --apply patched it
+	conversionValueMissing    = "missing"     // value-bearing event with no default value anywhere
+	conversionValueNotCreated = "not_created" // configured, but the event is not a conversion yet
+)
+
+// errConversionValuesPending signals events still missing or out of sync; the
+// command exits with diagcmd.ExitIssues.
+var errConversionValuesPending = errors.New("conversion values need attention")
+
+var conversionValuesCmd = &cobra.Command{
+	Use:   "conversion-values",
+	Short: "Check and apply default values on conversion events",
+	Long: `Compare the default conversion values configured in YAML with those set on
+the property's conversion (key) events, and report value-bearing events that
+have no value at all.
+
+GA4 applies a conversion's default value whenever the event arrives without a
+value parameter. Lead and sign-up events rarely send one, so without a default
+they count as zero revenue and drag down ROAS-style reporting.
+
+Configure values per conversion; the currency falls back to analytics.currency:
+
+  analytics:
+    property_id: "123456789"
+    currency: EUR
+  conversions:
+    - name: generate_lead
+      counting_method: ONCE_PER_EVENT
+      default_value: 40
+    - name: demo_booked
+      counting_method: ONCE_PER_EVENT
+      value_required: true   # report it when no value is set
+
+Value-bearing events are purchase, in_app_purchase, sign_up, the lead events
+(generate_lead, qualify_lead, working_lead, close_convert_lead) and anything
+marked value_required.
+
+New conversions get their default value from ga4 setup; --apply sets it on
+conversions that already exist. Exits 4 when an event is missing a value or
+is out of sync with the config.
+
+Examples:
+  ga4 conversion-values --config configs/mysite.yaml
+  ga4 conversion-values --config configs/mysite.yaml --apply`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runConversionValues(cmd, args)
+		if errors.Is(err, errConversionValuesPending) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(conversionValuesCmd)
+	conversionValuesCmd.Flags().StringVarP(&conversionValuesConfig, "config", "c", "", "Path to configuration file")
+	conversionValuesCmd.Flags().BoolVar(&conversionValuesApply, "apply", false, "Set configured default values on existing conversions")
+	output.FormatVar(conversionValuesCmd.Flags(), &conversionValuesFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = conversionValuesCmd.MarkFlagRequired("config")
+}
+
+// conversionValueRow is one conversion event's default-value status.
+type conversionValueRow struct {
+	Event        string   `json:"event"`
+	Configured   bool     `json:"configured"`
+	ValueBearing bool     `json:"value_bearing"`
+	Current      *float64 `json:"current_value,omitempty"`
+	CurrentCur   string   `json:"current_currency,omitempty"`
+	Want         *float64 `json:"want_value,omitempty"`
+	WantCur      string   `json:"want_currency,omitempty"`
+	Status       string   `json:"status"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func runConversionValues(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(conversionValuesConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	propertyID := cfg.GetPropertyID()
+	if propertyID == "" {
+		return fmt.Errorf("no analytics.property_id in %s", conversionValuesConfig)
+	}
+
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	existing, err := client.ListConversions(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list conversions: %w", err)
+	}
+	rows := planConversionValues(cfg, existing)
+
+	if conversionValuesApply {
+		for i, r := range rows {
+			if r.Status != conversionValueUpdate {
+				continue
+			}
+			if err := client.SetConversionDefaultValue(propertyID, r.Event, *r.Want, r.WantCur); err != nil {
+				rows[i].Error = err.Error()
+				continue
+			}
+			rows[i].Status = conversionValueUpdated
+		}
+	}
+
+	if conversionValuesFormat == "json" {
+		if err := output.JSON(os.Stdout, rows); err != nil {
+			return err
+		}
+	} else if err := displayConversionValues(cfg, rows); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if r.Status == conversionValueMissing || r.Status == conversionValueUpdate {
+			return errConversionValuesPending
+		}
+	}
+	return nil
+}
+
+// planConversionValues compares every conversion in the config or the
+// property with its configured default value.
+func planConversionValues(cfg *config.ProjectConfig, existing []*admin.GoogleAnalyticsAdminV1alphaConversionEvent) []conversionValueRow {
+	byEvent := make(map[string]*conversionValueRow)
+	row := func(event string) *conversionValueRow {
+		r, ok := byEvent[event]
+		if !ok {
+			r = &conversionValueRow{Event: event, ValueBearing: cfg.IsValueBearingEvent(event)}
+			byEvent[event] = r
+		}
+		return r
+	}
+
+	inProperty := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		inProperty[e.EventName] = true
+		r := row(e.EventName)
+		if dv := e.DefaultConversionValue; dv != nil {
+			v := dv.Value
+			r.Current, r.CurrentCur = &v, dv.CurrencyCode
+		}
+	}
+	for _, conv := range cfg.Conversions {
+		r := row(conv.Name)
+		r.Configured = true
+		if conv.DefaultValue != nil {
+			r.Want, r.WantCur = conv.DefaultValue, cfg.ConversionCurrency(conv)
+		}
+	}
+
+	rows := make([]conversionValueRow, 0, len(byEvent))
+	for _, r := range byEvent {
+		switch {
+		case r.Configured && !inProperty[r.Event]:
+			r.Status = conversionValueNotCreated
+		case r.Want != nil && (r.Current == nil || *r.Current != *r.Want || r.CurrentCur != r.WantCur):
+			r.Status = conversionValueUpdate
+		case r.ValueBearing && r.Want == nil && r.Current == nil:
+			r.Status = conversionValueMissing
+		default:
+			r.Status = conversionValueOK
+		}
+		rows = append(rows, *r)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Event < rows[j].Event })
+	return rows
+}
+
+func displayConversionValues(cfg *config.ProjectConfig, rows []conversionValueRow) error {
+	theme.Cyan("═══ Conversion default values: %s (property %s) ═══", cfg.Project.Name, cfg.GetPropertyID())
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Event", "In Config", "Property Value", "Configured Value", "Status"},
+		rows, func(r conversionValueRow) []string {
+			inConfig := "no"
+			if r.Configured {
+				inConfig = "yes"
+			}
+			return []string{r.Event, inConfig, formatConversionValue(r.Current, r.CurrentCur), formatConversionValue(r.Want, r.WantCur), conversionValueStatusCell(r)}
+		}); err != nil {
+		return err
+	}
+	theme.Println()
+
+	var missing, update, failed int
+	for _, r := range rows {
+		switch {
+		case r.Error != "":
+			failed++
+			theme.Red("✗ %s: %s", r.Event, r.Error)
+		case r.Status == conversionValueMissing:
+			missing++
+		case r.Status == conversionValueUpdate:
+			update++
+		}
+	}
+	if missing > 0 {
+		theme.Yellow("⚠ %d value-bearing conversion(s) have no default value and count as 0 when the event sends none; set default_value in the config.", missing)
+	}
+	if update > 0 && failed == 0 {
+		theme.Yellow("⚠ %d conversion(s) differ from the configured default value; run with --apply to set them.", update)
+	}
+	if missing == 0 && update == 0 && failed == 0 {
+		theme.Green("✓ Conversion default values match the config.")
+	}
+	return nil
+}
+
+func formatConversionValue(v *float64, currency string) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f %s", *v, currency)
+}
+
+func conversionValueStatusCell(r conversionValueRow) string {
+	switch r.Status {
+	case conversionValueOK, conversionValueUpdated:
+		return theme.GreenString("%s", r.Status)
+	case conversionValueMissing:
+		return theme.RedString("%s", r.Status)
+	case conversionValueUpdate:
+		return theme.YellowString("%s", r.Status)
+	default:
+		return theme.HiBlackString("%s", r.Status)
+	}
+}
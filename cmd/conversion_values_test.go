@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestPlanConversionValues(t *testing.T) {
+	forty, ten := 40.0, 10.0
+	cfg := &config.ProjectConfig{
+		Analytics: &config.AnalyticsConfig{PropertyID: "123", Currency: "EUR"},
+		Conversions: []config.ConversionConfig{
+			{Name: "generate_lead", DefaultValue: &forty},
+			{Name: "demo_booked", DefaultValue: &ten, Currency: "USD"},
+			{Name: "newsletter_signup"},
+			{Name: "quote_sent", ValueRequired: true},
+		},
+	}
+	existing := []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		{EventName: "generate_lead", DefaultConversionValue: &admin.GoogleAnalyticsAdminV1alphaConversionEventDefaultConversionValue{Value: 40, CurrencyCode: "EUR"}},
+		{EventName: "demo_booked", DefaultConversionValue: &admin.GoogleAnalyticsAdminV1alphaConversionEventDefaultConversionValue{Value: 10, CurrencyCode: "EUR"}},
+		{EventName: "newsletter_signup"},
+		{EventName: "sign_up"},
+	}
+
+	rows := planConversionValues(cfg, existing)
+	status := make(map[string]string, len(rows))
+	for _, r := range rows {
+		status[r.Event] = r.Status
+	}
+
+	require.Len(t, rows, 5)
+	assert.Equal(t, "demo_booked", rows[0].Event, "rows are sorted by event")
+	assert.Equal(t, conversionValueOK, status["generate_lead"])
+	assert.Equal(t, conversionValueUpdate, status["demo_booked"], "currency differs")
+	assert.Equal(t, conversionValueOK, status["newsletter_signup"], "not value-bearing")
+	assert.Equal(t, conversionValueMissing, status["sign_up"], "recommended lead event, unmanaged, no value")
+	assert.Equal(t, conversionValueNotCreated, status["quote_sent"])
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+	"github.com/garbarok/ga4-manager/internal/validation"
+)
+
+var (
+	setCountingMatch    []string
+	setCountingMethod   string
+	setCountingProperty string
+	setCountingConfig   string
+	setCountingDryRun   bool
+	setCountingYes      bool
+	setCountingFormat   string
+)
+
+// Per-event outcomes of a counting-method change.
+const (
+	countingChange    = "change"
+	countingUnchanged = "unchanged"
+	countingUpdated   = "updated"
+	countingFailed    = "failed"
+)
+
+var conversionsCmd = &cobra.Command{
+	Use:   "conversions",
+	Short: "Manage conversion (key) events on a live property",
+}
+
+var conversionsSetCountingCmd = &cobra.Command{
+	Use:   "set-counting",
+	Short: "Change the counting method of matching conversions in bulk",
+	Long: `Set the counting method of every conversion event whose name matches a
+--match pattern. Patterns are globs: * matches any run of characters, ? one
+character, and [a-c] a class. Repeat --match to combine patterns.
+
+  ONCE_PER_EVENT    every occurrence of the event counts
+  ONCE_PER_SESSION  at most one conversion per session
+
+The property comes from --property, or from analytics.property_id in --config.
+The command lists the conversions once, prints the current and new method of
+each matching event, and asks for confirmation before patching. --dry-run stops
+after the diff; --yes skips the prompt.
+
+Examples:
+  ga4 conversions set-counting --property 123456789 --match "scroll_*" --method ONCE_PER_EVENT --dry-run
+  ga4 conversions set-counting --config configs/mysite.yaml --match "form_*" --match generate_lead --method ONCE_PER_SESSION --yes`,
+	RunE: runSetCounting,
+}
+
+func init() {
+	rootCmd.AddCommand(conversionsCmd)
+	conversionsCmd.AddCommand(conversionsSetCountingCmd)
+	conversionsSetCountingCmd.Flags().StringArrayVarP(&setCountingMatch, "match", "m", nil, "Event name glob (repeatable, required)")
+	conversionsSetCountingCmd.Flags().StringVar(&setCountingMethod, "method", "", "Counting method: ONCE_PER_EVENT or ONCE_PER_SESSION (required)")
+	conversionsSetCountingCmd.Flags().StringVar(&setCountingProperty, "property", "", "GA4 property ID")
+	conversionsSetCountingCmd.Flags().StringVarP(&setCountingConfig, "config", "c", "", "Config file to read analytics.property_id from")
+	conversionsSetCountingCmd.Flags().BoolVar(&setCountingDryRun, "dry-run", false, "Show the diff without changing anything")
+	conversionsSetCountingCmd.Flags().BoolVarP(&setCountingYes, "yes", "y", false, "Skip confirmation prompt")
+	output.FormatVar(conversionsSetCountingCmd.Flags(), &setCountingFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = conversionsSetCountingCmd.MarkFlagRequired("match")
+	_ = conversionsSetCountingCmd.MarkFlagRequired("method")
+}
+
+// countingRow is one matching conversion's counting-method change.
+type countingRow struct {
+	Event  string `json:"event"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	conv *admin.GoogleAnalyticsAdminV1alphaConversionEvent
+}
+
+func runSetCounting(cmd *cobra.Command, args []string) error {
+	if err := validation.ValidateCountingMethod(setCountingMethod); err != nil {
+		return err
+	}
+	for _, m := range setCountingMatch {
+		if _, err := path.Match(m, ""); err != nil {
+			return fmt.Errorf("invalid --match %q: %w", m, err)
+		}
+	}
+	if setCountingFormat == "json" && !setCountingDryRun && !setCountingYes {
+		return fmt.Errorf("--format json needs --dry-run or --yes; there is no prompt to answer")
+	}
+	propertyID, err := setCountingPropertyID()
+	if err != nil {
+		return err
+	}
+
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	existing, err := client.ListConversions(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list conversions: %w", err)
+	}
+	rows := planCountingMethod(existing, setCountingMatch, setCountingMethod)
+
+	pending := 0
+	for _, r := range rows {
+		if r.Status == countingChange {
+			pending++
+		}
+	}
+	if setCountingFormat == "table" {
+		if err := displayCountingPlan(propertyID, rows); err != nil {
+			return err
+		}
+	}
+
+	apply := pending > 0 && !setCountingDryRun
+	if apply && !setCountingYes {
+		apply = confirmCountingChange(pending)
+	}
+	if apply {
+		for i, r := range rows {
+			if r.Status != countingChange {
+				continue
+			}
+			if err := client.SetConversionCountingMethod(r.conv, r.To); err != nil {
+				rows[i].Status, rows[i].Error = countingFailed, err.Error()
+				continue
+			}
+			rows[i].Status = countingUpdated
+		}
+	}
+
+	failed := 0
+	for _, r := range rows {
+		if r.Status == countingFailed {
+			failed++
+		}
+	}
+	if setCountingFormat == "json" {
+		if err := output.JSON(os.Stdout, rows); err != nil {
+			return err
+		}
+	} else if apply {
+		theme.Println()
+		for _, r := range rows {
+			if r.Status == countingFailed {
+				theme.Red("✗ %s: %s", r.Event, r.Error)
+			}
+		}
+		theme.Green("✓ Updated %d of %d conversions", pending-failed, pending)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d counting-method updates failed", failed, pending)
+	}
+	return nil
+}
+
+// setCountingPropertyID resolves the property from --property or --config.
+func setCountingPropertyID() (string, error) {
+	return resolvePropertyID(setCountingProperty, setCountingConfig)
+}
+
+// resolvePropertyID returns property, or else the analytics.property_id of
+// the config at cfgPath, validated.
+func resolvePropertyID(property, cfgPath string) (string, error) {
+	propertyID := property
+	if propertyID == "" && cfgPath != "" {
+		cfg, err := config.LoadConfig(cfgPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to load config: %w", err)
+		}
+		propertyID = cfg.GetPropertyID()
+	}
+	if propertyID == "" {
+		return "", fmt.Errorf("no property: pass --property or a --config with analytics.property_id")
+	}
+	if err := validation.ValidatePropertyID(propertyID); err != nil {
+		return "", err
+	}
+	return propertyID, nil
+}
+
+// planCountingMethod returns the conversions whose event name matches any
+// pattern, sorted by name, each marked as a change or already set.
+func planCountingMethod(existing []*admin.GoogleAnalyticsAdminV1alphaConversionEvent, patterns []string, method string) []countingRow {
+	rows := []countingRow{}
+	for _, e := range existing {
+		if !matchesAnyGlob(e.EventName, patterns) {
+			continue
+		}
+		status := countingChange
+		if e.CountingMethod == method {
+			status = countingUnchanged
+		}
+		rows = append(rows, countingRow{Event: e.EventName, From: e.CountingMethod, To: method, Status: status, conv: e})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Event < rows[j].Event })
+	return rows
+}
+
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func displayCountingPlan(propertyID string, rows []countingRow) error {
+	theme.Cyan("═══ Counting method: property %s ═══", propertyID)
+	if len(rows) == 0 {
+		theme.Yellow("⚠ No conversion event matches %s", strings.Join(setCountingMatch, ", "))
+		return nil
+	}
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Event", "Current", "New"},
+		rows, func(r countingRow) []string {
+			if r.Status == countingUnchanged {
+				return []string{r.Event, r.From, theme.HiBlackString("%s", "(unchanged)")}
+			}
+			return []string{r.Event, r.From, theme.YellowString("%s", r.To)}
+		}); err != nil {
+		return err
+	}
+	if setCountingDryRun {
+		theme.HiBlack("ℹ Dry run: nothing was changed.")
+	}
+	return nil
+}
+
+func confirmCountingChange(n int) bool {
+	theme.Printf("\nSet the counting method of %d conversions to %s? [y/N]: ", n, setCountingMethod)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
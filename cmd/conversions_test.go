@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+func TestPlanCountingMethod(t *testing.T) {
+	existing := []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		{EventName: "scroll_90", CountingMethod: "ONCE_PER_SESSION"},
+		{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+		{EventName: "scroll_50", CountingMethod: "ONCE_PER_EVENT"},
+		{EventName: "generate_lead", CountingMethod: "ONCE_PER_SESSION"},
+	}
+
+	rows := planCountingMethod(existing, []string{"scroll_*", "generate_lead"}, "ONCE_PER_EVENT")
+
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Event+" "+r.From+" "+r.Status)
+	}
+	assert.Equal(t, []string{
+		"generate_lead ONCE_PER_SESSION change",
+		"scroll_50 ONCE_PER_EVENT unchanged",
+		"scroll_90 ONCE_PER_SESSION change",
+	}, got)
+	assert.Same(t, existing[0], rows[2].conv)
+
+	assert.Empty(t, planCountingMethod(existing, []string{"form_*"}, "ONCE_PER_EVENT"))
+}
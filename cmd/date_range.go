@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// addDateRangeFlags registers --start-date and --end-date on c, the explicit
+// alternative to its --days flag for ranges that do not end yesterday.
+func addDateRangeFlags(c *cobra.Command, start, end *string) {
+	c.Flags().StringVar(start, "start-date", "", "First day to query, YYYY-MM-DD (with --end-date, instead of --days)")
+	c.Flags().StringVar(end, "end-date", "", "Last day to query, YYYY-MM-DD (with --start-date)")
+	c.MarkFlagsRequiredTogether("start-date", "end-date")
+	c.MarkFlagsMutuallyExclusive("days", "start-date")
+}
+
+// resolveDateRange returns the dates to query: start and end, validated,
+// when --start-date and --end-date were given, otherwise the days days
+// ending yesterday.
+func resolveDateRange(start, end string, days int) (string, string, error) {
+	if start == "" && end == "" {
+		startDate, endDate := gsc.BuildDateRange(days)
+		return startDate, endDate, nil
+	}
+	if err := gsc.ValidateDateRange(start, end); err != nil {
+		return "", "", err
+	}
+	return start, end, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestResolveDateRange(t *testing.T) {
+	start, end, err := resolveDateRange("", "", 7)
+	require.NoError(t, err)
+	wantStart, wantEnd := gsc.BuildDateRange(7)
+	assert.Equal(t, wantStart, start)
+	assert.Equal(t, wantEnd, end)
+
+	weekAgo := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	start, end, err = resolveDateRange(weekAgo, yesterday, 28)
+	require.NoError(t, err)
+	assert.Equal(t, weekAgo, start, "an explicit range wins over --days")
+	assert.Equal(t, yesterday, end)
+
+	_, _, err = resolveDateRange(yesterday, weekAgo, 28)
+	assert.ErrorContains(t, err, "must be after start date")
+	_, _, err = resolveDateRange("2025/03/01", yesterday, 28)
+	assert.ErrorContains(t, err, "YYYY-MM-DD")
+}
+
+func TestAddDateRangeFlags(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		var days int
+		var start, end string
+		c := &cobra.Command{Use: "probe", RunE: func(*cobra.Command, []string) error { return nil }}
+		c.Flags().IntVar(&days, "days", 28, "")
+		addDateRangeFlags(c, &start, &end)
+		return c
+	}
+
+	c := newCmd()
+	c.SetArgs([]string{"--start-date", "2025-03-01"})
+	assert.ErrorContains(t, c.Execute(), "end-date", "both ends are required")
+
+	c = newCmd()
+	c.SetArgs([]string{"--days", "7", "--start-date", "2025-03-01", "--end-date", "2025-03-07"})
+	assert.ErrorContains(t, c.Execute(), "days", "--days cannot be combined with a range")
+
+	c = newCmd()
+	c.SetArgs([]string{"--start-date", "2025-03-01", "--end-date", "2025-03-07"})
+	assert.NoError(t, c.Execute())
+}
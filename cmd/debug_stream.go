@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// debugStreamMinInterval keeps polling within the Realtime API's token
+// quota; its data has minute granularity anyway.
+const debugStreamMinInterval = 10 * time.Second
+
+var (
+	debugStreamProperty string
+	debugStreamConfig   string
+	debugStreamInterval time.Duration
+	debugStreamSince    int
+	debugStreamEvents   []string
+	debugStreamFilters  []string
+	debugStreamShow     []string
+	debugStreamFormat   string
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Tools for checking event instrumentation during development",
+}
+
+var debugStreamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Stream a property's incoming events in the terminal, like DebugView",
+	Long: `Poll the GA4 Realtime API and print each event as it arrives, with its count
+per minute and the dimensions given by --show, until interrupted. Useful to
+check that a page fires the events and user properties you expect without
+opening the GA4 interface.
+
+Realtime data is per minute and covers the last 30 minutes: each poll prints
+only what is new since the previous one. The Realtime API reports user-scoped
+custom dimensions but no event parameters and no debug_mode flag, so to follow
+your own traffic set a user property on the development build (for example
+debug_id), register it as a user-scoped custom dimension and filter on it:
+--filter customUser:debug_id=<value>. Any Realtime dimension can be filtered
+on or shown: platform, streamId, deviceCategory, country, city,
+unifiedScreenName, appVersion, customUser:<parameter>.
+
+Examples:
+  ga4 debug stream --config configs/mysite.yaml
+  ga4 debug stream --property 123456789 --event sign_up --event purchase
+  ga4 debug stream --config configs/mysite.yaml --filter customUser:debug_id=dev-laptop --show unifiedScreenName,customUser:plan_type
+  ga4 debug stream --config configs/mysite.yaml --format ndjson > events.ndjson`,
+	Args: cobra.NoArgs,
+	RunE: runDebugStream,
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugStreamCmd)
+	debugStreamCmd.Flags().StringVarP(&debugStreamProperty, "property", "p", "", "GA4 property ID")
+	debugStreamCmd.Flags().StringVarP(&debugStreamConfig, "config", "c", "", "Read the property from analytics.property_id in this config")
+	debugStreamCmd.Flags().DurationVar(&debugStreamInterval, "interval", time.Minute, "How often to poll the Realtime API (at least 10s)")
+	debugStreamCmd.Flags().IntVar(&debugStreamSince, "since", 5, "Also print the events of the last N minutes on start (0-29)")
+	debugStreamCmd.Flags().StringSliceVarP(&debugStreamEvents, "event", "e", nil, "Only these event names (repeatable)")
+	debugStreamCmd.Flags().StringArrayVar(&debugStreamFilters, "filter", nil, "Only events whose Realtime dimension has this value, as dimension=value (repeatable)")
+	debugStreamCmd.Flags().StringSliceVar(&debugStreamShow, "show", []string{"unifiedScreenName", "deviceCategory", "country"}, "Realtime dimensions to print with each event")
+	output.FormatVar(debugStreamCmd.Flags(), &debugStreamFormat, "f", output.FormatTable, output.FormatNDJSON)
+}
+
+// realtimeAPI is the part of the GA4 client ga4 debug stream reads.
+type realtimeAPI interface {
+	RealtimeEvents(propertyID string, q ga4.RealtimeQuery) ([]ga4.RealtimeRow, error)
+}
+
+func runDebugStream(cmd *cobra.Command, args []string) error {
+	if debugStreamInterval < debugStreamMinInterval {
+		return fmt.Errorf("--interval must be at least %s, got %s", debugStreamMinInterval, debugStreamInterval)
+	}
+	if debugStreamSince < 0 || debugStreamSince >= ga4.RealtimeWindow {
+		return fmt.Errorf("--since must be between 0 and %d minutes, got %d", ga4.RealtimeWindow-1, debugStreamSince)
+	}
+	filters, err := parseDimensionFilters(debugStreamFilters)
+	if err != nil {
+		return err
+	}
+	propertyID, err := resolvePropertyID(debugStreamProperty, debugStreamConfig)
+	if err != nil {
+		return err
+	}
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	q := ga4.RealtimeQuery{Dimensions: debugStreamShow, Filters: filters, Events: debugStreamEvents, MinutesAgo: debugStreamSince}
+	theme.Fprintf(os.Stderr, "%s\n", theme.CyanString("Streaming events of property %s every %s (Ctrl-C to stop)", propertyID, debugStreamInterval))
+	return streamRealtime(ctx, client, propertyID, q, debugStreamInterval, time.Now, func(events []streamEvent) error {
+		return writeStreamEvents(os.Stdout, debugStreamFormat, events)
+	})
+}
+
+// parseDimensionFilters parses --filter dimension=value pairs.
+func parseDimensionFilters(pairs []string) (map[string]string, error) {
+	filters := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		dim, value, ok := strings.Cut(p, "=")
+		if !ok || dim == "" {
+			return nil, fmt.Errorf("invalid --filter %q: want dimension=value", p)
+		}
+		filters[dim] = value
+	}
+	return filters, nil
+}
+
+// streamEvent is an event logged Count more times in Minute than seen by
+// the previous poll.
+type streamEvent struct {
+	Minute     time.Time         `json:"minute"`
+	Event      string            `json:"event"`
+	Count      int64             `json:"count"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+}
+
+// eventStream turns successive realtime reports into what is new in each.
+// Rows are keyed by the wall-clock minute they fall in, so a minute reported
+// again by a later poll only yields the events added since.
+type eventStream struct {
+	seen map[string]int64
+	last map[string]time.Time
+}
+
+func newEventStream() *eventStream {
+	return &eventStream{seen: map[string]int64{}, last: map[string]time.Time{}}
+}
+
+func (s *eventStream) update(now time.Time, rows []ga4.RealtimeRow) []streamEvent {
+	current := now.Truncate(time.Minute)
+	var events []streamEvent
+	for _, r := range rows {
+		minute := current.Add(-time.Duration(r.MinutesAgo) * time.Minute)
+		key := streamKey(minute, r)
+		if delta := r.Count - s.seen[key]; delta > 0 {
+			events = append(events, streamEvent{Minute: minute, Event: r.EventName, Count: delta, Dimensions: r.Dimensions})
+			s.seen[key] = r.Count
+			s.last[key] = minute
+		}
+	}
+	// Forget minutes the Realtime API no longer reports.
+	horizon := current.Add(-ga4.RealtimeWindow * time.Minute)
+	for key, minute := range s.last {
+		if minute.Before(horizon) {
+			delete(s.seen, key)
+			delete(s.last, key)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Minute.Equal(events[j].Minute) {
+			return events[i].Minute.Before(events[j].Minute)
+		}
+		return events[i].Event < events[j].Event
+	})
+	return events
+}
+
+func streamKey(minute time.Time, r ga4.RealtimeRow) string {
+	parts := []string{strconv.FormatInt(minute.Unix(), 10), r.EventName}
+	for _, k := range sortedKeys(r.Dimensions) {
+		parts = append(parts, k+"="+r.Dimensions[k])
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// streamRealtime polls every interval until ctx is done, passing what is
+// new to emit. The first poll reads q.MinutesAgo back; later ones read far
+// enough back to cover the interval and late-arriving events.
+func streamRealtime(ctx context.Context, client realtimeAPI, propertyID string, q ga4.RealtimeQuery, interval time.Duration, now func() time.Time, emit func([]streamEvent) error) error {
+	stream := newEventStream()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		rows, err := client.RealtimeEvents(propertyID, q)
+		if err != nil {
+			return err
+		}
+		if err := emit(stream.update(now(), rows)); err != nil {
+			return err
+		}
+		q.MinutesAgo = min(int(interval/time.Minute)+2, ga4.RealtimeWindow-1)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeStreamEvents(w io.Writer, format string, events []streamEvent) error {
+	if format == output.FormatNDJSON {
+		enc := json.NewEncoder(w)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, e := range events {
+		var dims []string
+		for _, k := range sortedKeys(e.Dimensions) {
+			dims = append(dims, k+"="+e.Dimensions[k])
+		}
+		theme.Fprintf(w, "%s  %s ×%d  %s\n", theme.HiBlackString("%s", e.Minute.Local().Format("15:04")),
+			theme.CyanString("%s", e.Event), e.Count, strings.Join(dims, "  "))
+	}
+	return nil
+}
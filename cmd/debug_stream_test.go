@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+func TestEventStream_PrintsOnlyWhatIsNew(t *testing.T) {
+	s := newEventStream()
+	t0 := time.Date(2026, 10, 16, 14, 3, 20, 0, time.UTC)
+	pricing := map[string]string{"unifiedScreenName": "Pricing"}
+
+	got := s.update(t0, []ga4.RealtimeRow{
+		{MinutesAgo: 1, EventName: "page_view", Dimensions: pricing, Count: 2},
+		{MinutesAgo: 0, EventName: "sign_up", Dimensions: pricing, Count: 1},
+	})
+	assert.Equal(t, []streamEvent{
+		{Minute: time.Date(2026, 10, 16, 14, 2, 0, 0, time.UTC), Event: "page_view", Count: 2, Dimensions: pricing},
+		{Minute: time.Date(2026, 10, 16, 14, 3, 0, 0, time.UTC), Event: "sign_up", Count: 1, Dimensions: pricing},
+	}, got)
+
+	// A minute later the same rows are one minute older; only the extra
+	// page_view in 14:03 is new.
+	got = s.update(t0.Add(time.Minute), []ga4.RealtimeRow{
+		{MinutesAgo: 2, EventName: "page_view", Dimensions: pricing, Count: 2},
+		{MinutesAgo: 1, EventName: "sign_up", Dimensions: pricing, Count: 1},
+		{MinutesAgo: 1, EventName: "page_view", Dimensions: pricing, Count: 1},
+	})
+	assert.Equal(t, []streamEvent{
+		{Minute: time.Date(2026, 10, 16, 14, 3, 0, 0, time.UTC), Event: "page_view", Count: 1, Dimensions: pricing},
+	}, got)
+
+	s.update(t0.Add(time.Hour), nil)
+	assert.Empty(t, s.seen, "minutes past the realtime window are forgotten")
+}
+
+type fakeRealtime struct {
+	queries []ga4.RealtimeQuery
+	rows    []ga4.RealtimeRow
+}
+
+func (f *fakeRealtime) RealtimeEvents(_ string, q ga4.RealtimeQuery) ([]ga4.RealtimeRow, error) {
+	f.queries = append(f.queries, q)
+	return f.rows, nil
+}
+
+func TestStreamRealtime_PollsUntilCancelled(t *testing.T) {
+	fake := &fakeRealtime{rows: []ga4.RealtimeRow{{MinutesAgo: 0, EventName: "purchase", Count: 1}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2026, 10, 16, 14, 3, 0, 0, time.UTC)
+
+	var emitted [][]streamEvent
+	err := streamRealtime(ctx, fake, "123456789", ga4.RealtimeQuery{MinutesAgo: 5}, time.Millisecond,
+		func() time.Time { return now }, func(events []streamEvent) error {
+			emitted = append(emitted, events)
+			if len(emitted) == 2 {
+				cancel()
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, fake.queries, 2)
+	assert.Equal(t, 5, fake.queries[0].MinutesAgo, "first poll reads the --since backlog")
+	assert.Equal(t, 2, fake.queries[1].MinutesAgo)
+	assert.Len(t, emitted[0], 1)
+	assert.Empty(t, emitted[1], "nothing new on the second poll")
+}
+
+func TestWriteStreamEvents(t *testing.T) {
+	events := []streamEvent{{Minute: time.Date(2026, 10, 16, 14, 3, 0, 0, time.UTC), Event: "sign_up", Count: 2,
+		Dimensions: map[string]string{"country": "Spain", "deviceCategory": "mobile"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStreamEvents(&buf, output.FormatNDJSON, events))
+	assert.JSONEq(t, `{"minute":"2026-10-16T14:03:00Z","event":"sign_up","count":2,"dimensions":{"country":"Spain","deviceCategory":"mobile"}}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeStreamEvents(&buf, output.FormatTable, events))
+	assert.Contains(t, buf.String(), "sign_up ×2  country=Spain  deviceCategory=mobile")
+}
+
+func TestParseDimensionFilters(t *testing.T) {
+	got, err := parseDimensionFilters([]string{"customUser:debug_id=dev-42", "platform=web"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"customUser:debug_id": "dev-42", "platform": "web"}, got)
+
+	_, err = parseDimensionFilters([]string{"platform"})
+	assert.ErrorContains(t, err, "want dimension=value")
+}
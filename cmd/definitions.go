@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// The archive and restore commands of dimensions and metrics share their
+// flags; only one of them runs.
+var (
+	definitionsMatch    []string
+	definitionsProperty string
+	definitionsConfig   string
+	definitionsDryRun   bool
+	definitionsYes      bool
+	definitionsFormat   string
+	definitionsSnapshot string
+	definitionsFrom     string
+	definitionsSuffix   string
+)
+
+// Per-definition outcomes of a bulk archive or restore.
+const (
+	definitionArchive  = "archive"
+	definitionArchived = "archived"
+	definitionCreate   = "create"
+	definitionCreated  = "created"
+	definitionExists   = "exists"
+	definitionFailed   = "failed"
+)
+
+// definitionKind is the custom definition a command works on.
+type definitionKind struct {
+	name   string // dimension or metric
+	plural string
+}
+
+var (
+	dimensionKind = definitionKind{name: "dimension", plural: "dimensions"}
+	metricKind    = definitionKind{name: "metric", plural: "metrics"}
+)
+
+// definitionClient is the slice of the GA4 client bulk archive and restore
+// need; *ga4.Client satisfies it.
+type definitionClient interface {
+	setup.ResourceLister
+	DeleteDimension(propertyID, parameterName string) error
+	DeleteMetric(propertyID, parameterName string) error
+	CreateDimension(propertyID string, dim config.DimensionConfig) error
+	CreateCustomMetric(propertyID string, metric config.MetricConfig) error
+}
+
+var dimensionsCmd = &cobra.Command{
+	Use:   "dimensions",
+	Short: "Manage custom dimensions on a live property",
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Manage custom metrics on a live property",
+}
+
+var dimensionsArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive matching custom dimensions in bulk, saving their definitions first",
+	Long: `Archive every custom dimension whose parameter name matches a --match
+pattern. Patterns are globs: * matches any run of characters, ? one character,
+and [a-c] a class. Repeat --match to combine patterns.
+
+GA4 cannot unarchive a dimension, so before archiving anything the full
+definitions are written to a JSON snapshot, by default under
+.ga4-state/archives/. ` + "`ga4 dimensions restore --from <snapshot>`" + ` recreates them.
+
+The property comes from --property, or from analytics.property_id in --config.
+The command prints the matching dimensions and asks for confirmation before
+archiving. --dry-run stops after the list; --yes skips the prompt.
+
+Examples:
+  ga4 dimensions archive --property 123456789 --match "utm_*" --dry-run
+  ga4 dimensions archive --config configs/mysite.yaml --match "utm_*" --match legacy_id --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error { return runDefinitionsArchive(dimensionKind) },
+}
+
+var dimensionsRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Recreate custom dimensions from an archive snapshot",
+	Long: `Create the custom dimensions saved by ` + "`ga4 dimensions archive`" + ` again.
+
+Recreated dimensions are new definitions: data collected before the archive
+stays with the archived ones. When GA4 refuses a parameter name that is still
+taken, --suffix recreates every dimension under a new parameter name
+(author -> author_v2 with --suffix _v2). Dimensions whose parameter name
+exists on the property are skipped. --match restores only some of them.
+
+The property defaults to the one the snapshot was taken from; --property or
+--config restores into another.
+
+Examples:
+  ga4 dimensions restore --from .ga4-state/archives/123456789-dimensions-20261016-120000.json --dry-run
+  ga4 dimensions restore --from snapshot.json --match "utm_source" --suffix _v2 --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error { return runDefinitionsRestore(dimensionKind) },
+}
+
+var metricsArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive matching custom metrics in bulk, saving their definitions first",
+	Long: `Archive every custom metric whose parameter name matches a --match pattern,
+after writing the full definitions to a JSON snapshot. It works as
+` + "`ga4 dimensions archive`" + ` does; ` + "`ga4 metrics restore`" + ` recreates them.
+
+Examples:
+  ga4 metrics archive --property 123456789 --match "old_*" --dry-run
+  ga4 metrics archive --config configs/mysite.yaml --match "test_*" --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error { return runDefinitionsArchive(metricKind) },
+}
+
+var metricsRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Recreate custom metrics from an archive snapshot",
+	Long: `Create the custom metrics saved by ` + "`ga4 metrics archive`" + ` again. It works as
+` + "`ga4 dimensions restore`" + ` does.
+
+Examples:
+  ga4 metrics restore --from snapshot.json --dry-run
+  ga4 metrics restore --from snapshot.json --suffix _v2 --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error { return runDefinitionsRestore(metricKind) },
+}
+
+func init() {
+	rootCmd.AddCommand(dimensionsCmd, metricsCmd)
+	dimensionsCmd.AddCommand(dimensionsArchiveCmd, dimensionsRestoreCmd)
+	metricsCmd.AddCommand(metricsArchiveCmd, metricsRestoreCmd)
+
+	for _, c := range []*cobra.Command{dimensionsArchiveCmd, metricsArchiveCmd, dimensionsRestoreCmd, metricsRestoreCmd} {
+		c.Flags().StringVar(&definitionsProperty, "property", "", "GA4 property ID")
+		c.Flags().StringVarP(&definitionsConfig, "config", "c", "", "Config file to read analytics.property_id from")
+		c.Flags().BoolVar(&definitionsDryRun, "dry-run", false, "Show what would change without changing anything")
+		c.Flags().BoolVarP(&definitionsYes, "yes", "y", false, "Skip confirmation prompt")
+		output.FormatVar(c.Flags(), &definitionsFormat, "f", output.FormatTable, output.FormatJSON)
+	}
+	for _, c := range []*cobra.Command{dimensionsArchiveCmd, metricsArchiveCmd} {
+		c.Flags().StringArrayVarP(&definitionsMatch, "match", "m", nil, "Parameter name glob (repeatable, required)")
+		c.Flags().StringVar(&definitionsSnapshot, "snapshot", "", "Write the snapshot to this file (default .ga4-state/archives/<property>-<kind>-<time>.json)")
+		c.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+		_ = c.MarkFlagRequired("match")
+	}
+	for _, c := range []*cobra.Command{dimensionsRestoreCmd, metricsRestoreCmd} {
+		c.Flags().StringVar(&definitionsFrom, "from", "", "Archive snapshot to restore (required)")
+		c.Flags().StringArrayVarP(&definitionsMatch, "match", "m", nil, "Restore only parameter names matching this glob (repeatable)")
+		c.Flags().StringVar(&definitionsSuffix, "suffix", "", "Append this to every restored parameter name")
+		_ = c.MarkFlagRequired("from")
+	}
+}
+
+// definitionRow is one definition of a bulk archive or restore.
+type definitionRow struct {
+	Parameter   string `json:"parameter"`
+	DisplayName string `json:"display_name"`
+	Scope       string `json:"scope"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// definitionsReport is the JSON document of archive and restore.
+type definitionsReport struct {
+	PropertyID string          `json:"property_id"`
+	Snapshot   string          `json:"snapshot,omitempty"`
+	Rows       []definitionRow `json:"definitions"`
+}
+
+func runDefinitionsArchive(kind definitionKind) error {
+	if err := checkDefinitionsFlags(); err != nil {
+		return err
+	}
+	propertyID, err := resolvePropertyID(definitionsProperty, definitionsConfig)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	snap, err := matchDefinitions(client, kind, propertyID, definitionsMatch)
+	if err != nil {
+		return err
+	}
+	rows := snapshotRows(kind, snap, "", definitionArchive)
+	report := definitionsReport{PropertyID: propertyID, Rows: rows}
+	if definitionsFormat == output.FormatTable {
+		displayDefinitions(fmt.Sprintf("Archive %s: property %s", kind.plural, propertyID), rows)
+		if len(rows) == 0 {
+			theme.Yellow("⚠ No custom %s matches %s", kind.name, strings.Join(definitionsMatch, ", "))
+		}
+	}
+
+	apply := len(rows) > 0 && !definitionsDryRun
+	if apply && !definitionsYes {
+		apply = confirmDefinitions(fmt.Sprintf("Archive %d %s? Their definitions are saved first.", len(rows), kind.plural))
+	}
+	if apply {
+		report.Snapshot = definitionsSnapshot
+		if report.Snapshot == "" {
+			report.Snapshot = filepath.Join(gscstate.ResolveStateDir(historyStateDir), "archives",
+				fmt.Sprintf("%s-%s-%s.json", propertyID, kind.plural, snap.TakenAt.Format("20060102-150405")))
+		}
+		if err := setup.SaveArchiveSnapshot(report.Snapshot, snap); err != nil {
+			return err
+		}
+		report.Rows = archiveDefinitions(client, kind, snap)
+	}
+	return finishDefinitions(kind, report, apply, definitionArchived)
+}
+
+func runDefinitionsRestore(kind definitionKind) error {
+	if err := checkDefinitionsFlags(); err != nil {
+		return err
+	}
+	snap, err := setup.LoadArchiveSnapshot(definitionsFrom)
+	if err != nil {
+		return err
+	}
+	propertyID := snap.PropertyID
+	if definitionsProperty != "" || definitionsConfig != "" {
+		if propertyID, err = resolvePropertyID(definitionsProperty, definitionsConfig); err != nil {
+			return err
+		}
+	}
+
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	snap = filterSnapshot(snap, definitionsMatch)
+	rows, err := planRestore(client, kind, propertyID, snap, definitionsSuffix)
+	if err != nil {
+		return err
+	}
+	report := definitionsReport{PropertyID: propertyID, Snapshot: definitionsFrom, Rows: rows}
+	pending := 0
+	for _, r := range rows {
+		if r.Status == definitionCreate {
+			pending++
+		}
+	}
+	if definitionsFormat == output.FormatTable {
+		displayDefinitions(fmt.Sprintf("Restore %s: property %s", kind.plural, propertyID), rows)
+		if len(rows) == 0 {
+			theme.Yellow("⚠ The snapshot holds no matching %s", kind.plural)
+		}
+	}
+
+	apply := pending > 0 && !definitionsDryRun
+	if apply && !definitionsYes {
+		apply = confirmDefinitions(fmt.Sprintf("Create %d %s?", pending, kind.plural))
+	}
+	if apply {
+		restoreDefinitions(client, kind, propertyID, snap, report.Rows, definitionsSuffix)
+	}
+	return finishDefinitions(kind, report, apply, definitionCreated)
+}
+
+func checkDefinitionsFlags() error {
+	for _, m := range definitionsMatch {
+		if _, err := path.Match(m, ""); err != nil {
+			return fmt.Errorf("invalid --match %q: %w", m, err)
+		}
+	}
+	if definitionsFormat == output.FormatJSON && !definitionsDryRun && !definitionsYes {
+		return fmt.Errorf("--format json needs --dry-run or --yes; there is no prompt to answer")
+	}
+	return nil
+}
+
+// matchDefinitions lists the property's definitions of kind and returns a
+// snapshot of those whose parameter name matches a pattern, sorted by name.
+func matchDefinitions(client setup.ResourceLister, kind definitionKind, propertyID string, patterns []string) (*setup.ArchiveSnapshot, error) {
+	snap := &setup.ArchiveSnapshot{SchemaVersion: setup.ArchiveSnapshotVersion, PropertyID: propertyID, TakenAt: time.Now().UTC()}
+	switch kind {
+	case dimensionKind:
+		list, err := client.ListDimensions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dimensions: %w", err)
+		}
+		for _, d := range list {
+			if matchesAnyGlob(d.ParameterName, patterns) {
+				snap.Dimensions = append(snap.Dimensions, d)
+			}
+		}
+		sort.Slice(snap.Dimensions, func(i, j int) bool { return snap.Dimensions[i].ParameterName < snap.Dimensions[j].ParameterName })
+	case metricKind:
+		list, err := client.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics: %w", err)
+		}
+		for _, m := range list {
+			if matchesAnyGlob(m.ParameterName, patterns) {
+				snap.Metrics = append(snap.Metrics, m)
+			}
+		}
+		sort.Slice(snap.Metrics, func(i, j int) bool { return snap.Metrics[i].ParameterName < snap.Metrics[j].ParameterName })
+	}
+	return snap, nil
+}
+
+// snapshotRows returns a row per definition of kind in snap, its parameter
+// name followed by suffix.
+func snapshotRows(kind definitionKind, snap *setup.ArchiveSnapshot, suffix, status string) []definitionRow {
+	rows := []definitionRow{}
+	if kind == dimensionKind {
+		for _, d := range snap.DimensionConfigs() {
+			rows = append(rows, definitionRow{Parameter: d.ParameterName + suffix, DisplayName: d.DisplayName, Scope: d.Scope, Status: status})
+		}
+		return rows
+	}
+	for _, m := range snap.MetricConfigs() {
+		rows = append(rows, definitionRow{Parameter: m.ParameterName + suffix, DisplayName: m.DisplayName, Scope: m.Scope, Status: status})
+	}
+	return rows
+}
+
+// archiveDefinitions archives every definition of kind in snap.
+func archiveDefinitions(client definitionClient, kind definitionKind, snap *setup.ArchiveSnapshot) []definitionRow {
+	rows := snapshotRows(kind, snap, "", definitionArchived)
+	for i, r := range rows {
+		var err error
+		if kind == dimensionKind {
+			err = client.DeleteDimension(snap.PropertyID, r.Parameter)
+		} else {
+			err = client.DeleteMetric(snap.PropertyID, r.Parameter)
+		}
+		if err != nil {
+			rows[i].Status, rows[i].Error = definitionFailed, err.Error()
+		}
+	}
+	return rows
+}
+
+// filterSnapshot returns the definitions of snap whose parameter name matches
+// a pattern; without patterns, snap itself.
+func filterSnapshot(snap *setup.ArchiveSnapshot, patterns []string) *setup.ArchiveSnapshot {
+	if len(patterns) == 0 {
+		return snap
+	}
+	filtered := *snap
+	filtered.Dimensions, filtered.Metrics = nil, nil
+	for _, d := range snap.Dimensions {
+		if matchesAnyGlob(d.ParameterName, patterns) {
+			filtered.Dimensions = append(filtered.Dimensions, d)
+		}
+	}
+	for _, m := range snap.Metrics {
+		if matchesAnyGlob(m.ParameterName, patterns) {
+			filtered.Metrics = append(filtered.Metrics, m)
+		}
+	}
+	return &filtered
+}
+
+// planRestore marks each definition of kind in snap as to create, or as
+// existing when its parameter name, with suffix, is taken on the property.
+func planRestore(client setup.ResourceLister, kind definitionKind, propertyID string, snap *setup.ArchiveSnapshot, suffix string) ([]definitionRow, error) {
+	existing := map[string]bool{}
+	if kind == dimensionKind {
+		list, err := client.ListDimensions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dimensions: %w", err)
+		}
+		for _, d := range list {
+			existing[d.ParameterName] = true
+		}
+	} else {
+		list, err := client.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics: %w", err)
+		}
+		for _, m := range list {
+			existing[m.ParameterName] = true
+		}
+	}
+
+	rows := snapshotRows(kind, snap, suffix, definitionCreate)
+	for i, r := range rows {
+		if existing[r.Parameter] {
+			rows[i].Status = definitionExists
+		}
+	}
+	return rows, nil
+}
+
+// restoreDefinitions creates the definitions planRestore marked to create,
+// updating their rows.
+func restoreDefinitions(client definitionClient, kind definitionKind, propertyID string, snap *setup.ArchiveSnapshot, rows []definitionRow, suffix string) {
+	dims, metrics := snap.DimensionConfigs(), snap.MetricConfigs()
+	for i := range rows {
+		if rows[i].Status != definitionCreate {
+			continue
+		}
+		var err error
+		if kind == dimensionKind {
+			d := dims[i]
+			d.ParameterName += suffix
+			err = client.CreateDimension(propertyID, d)
+		} else {
+			m := metrics[i]
+			m.ParameterName += suffix
+			err = client.CreateCustomMetric(propertyID, m)
+		}
+		if err != nil {
+			rows[i].Status, rows[i].Error = definitionFailed, err.Error()
+			continue
+		}
+		rows[i].Status = definitionCreated
+	}
+}
+
+func displayDefinitions(title string, rows []definitionRow) {
+	theme.Cyan("═══ %s ═══", title)
+	if len(rows) == 0 {
+		return
+	}
+	_ = output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Parameter", "Display Name", "Scope", "Action"},
+		rows, func(r definitionRow) []string {
+			if r.Status == definitionExists {
+				return []string{r.Parameter, r.DisplayName, r.Scope, theme.HiBlackString("%s", "(exists)")}
+			}
+			return []string{r.Parameter, r.DisplayName, r.Scope, theme.YellowString("%s", r.Status)}
+		})
+	if definitionsDryRun {
+		theme.HiBlack("ℹ Dry run: nothing was changed.")
+	}
+}
+
+// finishDefinitions prints the outcome and fails when a definition failed.
+func finishDefinitions(kind definitionKind, report definitionsReport, applied bool, done string) error {
+	total, failed := 0, 0
+	for _, r := range report.Rows {
+		switch r.Status {
+		case done:
+			total++
+		case definitionFailed:
+			total++
+			failed++
+		}
+	}
+	if definitionsFormat == output.FormatJSON {
+		if err := output.JSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else if applied {
+		theme.Println()
+		for _, r := range report.Rows {
+			if r.Status == definitionFailed {
+				theme.Red("✗ %s: %s", r.Parameter, r.Error)
+			}
+		}
+		theme.Green("✓ %s %d of %d %s", strings.ToUpper(done[:1])+done[1:], total-failed, total, kind.plural)
+		if done == definitionArchived {
+			theme.HiBlack("ℹ Definitions saved to %s; restore with: ga4 %s restore --from %s", report.Snapshot, kind.plural, report.Snapshot)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, total, kind.plural)
+	}
+	return nil
+}
+
+func confirmDefinitions(question string) bool {
+	theme.Printf("\n%s [y/N]: ", question)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
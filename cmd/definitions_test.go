@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/ga4/ga4fake"
+)
+
+func definitionsFake() *ga4fake.Admin {
+	return &ga4fake.Admin{
+		Dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+			{ParameterName: "utm_source", DisplayName: "UTM Source", Scope: "EVENT", Description: "Campaign source"},
+			{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"},
+			{ParameterName: "utm_medium", DisplayName: "UTM Medium", Scope: "EVENT"},
+		},
+		Metrics: []*admin.GoogleAnalyticsAdminV1alphaCustomMetric{
+			{ParameterName: "utm_cost", DisplayName: "UTM Cost", MeasurementUnit: "CURRENCY", Scope: "EVENT", RestrictedMetricType: []string{"COST_DATA"}},
+		},
+	}
+}
+
+func TestArchiveDefinitions(t *testing.T) {
+	fake := definitionsFake()
+
+	snap, err := matchDefinitions(fake, dimensionKind, "123", []string{"utm_*"})
+	require.NoError(t, err)
+	require.Len(t, snap.Dimensions, 2)
+	assert.Equal(t, "utm_medium", snap.Dimensions[0].ParameterName, "sorted by parameter name")
+	assert.Empty(t, snap.Metrics, "only the kind asked for is listed")
+
+	rows := archiveDefinitions(fake, dimensionKind, snap)
+
+	assert.Equal(t, []string{"utm_medium", "utm_source"}, fake.Called("DeleteDimension"))
+	assert.Equal(t, definitionArchived, rows[0].Status)
+	require.Len(t, fake.Dimensions, 1)
+	assert.Equal(t, "author", fake.Dimensions[0].ParameterName)
+}
+
+func TestArchiveDefinitions_Failure(t *testing.T) {
+	fake := definitionsFake()
+	fake.Errs = map[string]error{"DeleteMetric": errors.New("permission denied")}
+
+	snap, err := matchDefinitions(fake, metricKind, "123", []string{"utm_*"})
+	require.NoError(t, err)
+	rows := archiveDefinitions(fake, metricKind, snap)
+
+	assert.Equal(t, []definitionRow{{Parameter: "utm_cost", DisplayName: "UTM Cost", Scope: "EVENT", Status: definitionFailed, Error: "permission denied"}}, rows)
+}
+
+func TestRestoreDefinitions(t *testing.T) {
+	fake := definitionsFake()
+	snap, err := matchDefinitions(fake, dimensionKind, "123", []string{"utm_*", "author"})
+	require.NoError(t, err)
+
+	// author was never archived, so it exists; the suffix gives the utm
+	// dimensions new parameter names.
+	fake.Dimensions = fake.Dimensions[1:2]
+	snap = filterSnapshot(snap, []string{"author", "utm_source"})
+	rows, err := planRestore(fake, dimensionKind, "123", snap, "")
+	require.NoError(t, err)
+	assert.Equal(t, definitionExists, rows[0].Status)
+	assert.Equal(t, definitionCreate, rows[1].Status)
+
+	rows, err = planRestore(fake, dimensionKind, "123", snap, "_v2")
+	require.NoError(t, err)
+	restoreDefinitions(fake, dimensionKind, "123", snap, rows, "_v2")
+
+	assert.Equal(t, []string{"author_v2", "utm_source_v2"}, fake.Called("CreateDimension"))
+	assert.Equal(t, definitionCreated, rows[1].Status)
+	restored := fake.Dimensions[len(fake.Dimensions)-1]
+	assert.Equal(t, "UTM Source", restored.DisplayName)
+	assert.Equal(t, "Campaign source", restored.Description)
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/bigquery"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/sheets"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// reportDateColumn is the DATE column of every table destination: the last
+// day the report covers. BigQuery tables are partitioned by it.
+const reportDateColumn = "report_date"
+
+// sheetMode is --sheet-mode: whether a sheets:// destination keeps the rows
+// already in the tab.
+var sheetMode string
+
+// addTableOutputFlags registers the flags of the --output table destinations.
+func addTableOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sheetMode, "sheet-mode", sheets.ModeAppend,
+		"With --output sheets://: append below the rows in the tab, or replace them ("+strings.Join(sheets.Modes(), ", ")+")")
+}
+
+// tableRows is a batch of rows for a table destination, values in column
+// order.
+type tableRows struct {
+	Columns []string
+	Values  [][]any
+}
+
+// tableWriter appends rows to the table an --output URL names.
+type tableWriter interface {
+	Write(rows tableRows) error
+}
+
+// tableDestination is an --output naming a table rather than a file: a
+// BigQuery table (bq://project.dataset.table) or a Google Sheet tab
+// (sheets://<spreadsheetId>/<tab>). open creates the client, so a command can
+// parse --output before authenticating.
+type tableDestination struct {
+	name string
+	open func() (tableWriter, error)
+}
+
+func (d *tableDestination) String() string {
+	return d.name
+}
+
+// parseTableDestination returns the destination --output names, or nil when
+// it names a file (or nothing).
+func parseTableDestination(outputPath string) (*tableDestination, error) {
+	switch {
+	case bigquery.IsURL(outputPath):
+		t, err := bigquery.ParseURL(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		return &tableDestination{name: t.String(), open: func() (tableWriter, error) {
+			loader, err := newBigQueryLoader()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+			}
+			return &bigQueryWriter{loader: loader, table: t}, nil
+		}}, nil
+	case sheets.IsURL(outputPath):
+		t, err := sheets.ParseURL(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(sheets.Modes(), sheetMode) {
+			return nil, fmt.Errorf("invalid --sheet-mode %q (want %s)", sheetMode, strings.Join(sheets.Modes(), " or "))
+		}
+		return &tableDestination{name: t.String(), open: func() (tableWriter, error) {
+			w, err := newSheetsWriter(t, sheetMode)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create Sheets client: %w", err)
+			}
+			return w, nil
+		}}, nil
+	}
+	return nil, nil
+}
+
+// analyticsTableRows converts Search Analytics rows of query. Columns are
+// named as in the Looker Studio export; the site and date range are added
+// so runs appended to one table stay apart.
+func analyticsTableRows(query *gsc.SearchAnalyticsQuery, rows []gsc.SearchAnalyticsRow) (tableRows, error) {
+	out := tableRows{Columns: make([]string, 0, len(query.Dimensions)+8)}
+	for _, d := range query.Dimensions {
+		f, ok := analyticsLookerDimensions[d]
+		if !ok {
+			return tableRows{}, fmt.Errorf("no table column for dimension %q", d)
+		}
+		out.Columns = append(out.Columns, f.Name)
+	}
+	out.Columns = append(out.Columns, "site", reportDateColumn, "start_date", "clicks", "impressions", "ctr", "position")
+
+	for _, r := range rows {
+		values := make([]any, 0, len(out.Columns))
+		for i, key := range r.Keys {
+			if i < len(query.Dimensions) && query.Dimensions[i] == "date" {
+				values = append(values, bigquery.Date(key))
+				continue
+			}
+			values = append(values, key)
+		}
+		values = append(values, query.SiteURL, bigquery.Date(query.EndDate), bigquery.Date(query.StartDate),
+			r.Clicks, r.Impressions, r.CTR, r.Position)
+		out.Values = append(out.Values, values)
+	}
+	return out, nil
+}
+
+// coverageTableRows converts the coverage page sample.
+func coverageTableRows(site, startDate, endDate string, pages []gsc.PageCoverage) tableRows {
+	out := tableRows{Columns: []string{
+		"site", reportDateColumn, "start_date", "page", "status", "impressions", "clicks", "ctr", "position",
+	}}
+	for _, p := range pages {
+		out.Values = append(out.Values, []any{
+			site, bigquery.Date(endDate), bigquery.Date(startDate), p.URL, p.Status, p.Impressions, p.Clicks, p.CTR, p.Position,
+		})
+	}
+	return out
+}
+
+// reportTableRows flattens a config report into one row per resource, so
+// the property's setup can be tracked over time in one table.
+func reportTableRows(data *ReportData, exportedAt time.Time) tableRows {
+	out := tableRows{Columns: []string{
+		reportDateColumn, "exported_at", "project", "property_id", "resource_type", "name", "parameter", "scope", "unit", "detail",
+	}}
+	reportDate := bigquery.Date(exportedAt.Format("2006-01-02"))
+	add := func(kind, name string, parameter, scope, unit, detail any) {
+		out.Values = append(out.Values, []any{
+			reportDate, exportedAt, data.ProjectName, data.PropertyID, kind, name, parameter, scope, unit, detail,
+		})
+	}
+	for _, c := range data.Conversions {
+		add("conversion", c.EventName, nil, nil, nil, c.CountingMethod)
+	}
+	for _, d := range data.Dimensions {
+		add("custom_dimension", d.DisplayName, d.ParameterName, d.Scope, nil, nil)
+	}
+	for _, m := range data.Metrics {
+		add("custom_metric", m.DisplayName, m.ParameterName, m.Scope, m.MeasurementUnit, nil)
+	}
+	for _, c := range data.CalculatedMetrics {
+		add("calculated_metric", c.DisplayName, nil, nil, c.MetricUnit, c.Formula)
+	}
+	for _, a := range data.Audiences {
+		add("audience", a.Name, nil, nil, nil, fmt.Sprintf("%s, %d days", a.Category, a.MembershipDuration))
+	}
+	if data.DataRetention.EventDataRetention != "" {
+		add("data_retention", "event_data_retention", nil, nil, nil, data.DataRetention.EventDataRetention)
+	}
+	return out
+}
+
+// runAnalyticsTable writes the rows of query to dest: every page as it
+// arrives with --all-rows, else the rows of one query, which are also saved
+// to the local history like any other run.
+func runAnalyticsTable(client *gsc.Client, query *gsc.SearchAnalyticsQuery, dest *tableDestination) error {
+	w, err := dest.open()
+	if err != nil {
+		theme.Red("✗ %v", err)
+		return err
+	}
+	if gscAnalyticsAllRows {
+		return streamAnalyticsTable(w, client, query, dest)
+	}
+
+	report, err := client.QuerySearchAnalytics(query)
+	if err != nil {
+		theme.Red("✗ Failed to query search analytics: %v", err)
+		return err
+	}
+	if err := saveHistory(store.KindAnalytics, query.SiteURL, analyticsRecords(report)); err != nil {
+		return err
+	}
+	rows, err := analyticsTableRows(query, report.Rows)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(rows); err != nil {
+		return err
+	}
+	theme.Printf("✓ Appended %d rows to %s\n", len(rows.Values), dest)
+	return nil
+}
+
+// streamAnalyticsTable writes each page of query to w as it arrives. Pages
+// already written stay written when a later one fails.
+func streamAnalyticsTable(w tableWriter, client analyticsStreamer, query *gsc.SearchAnalyticsQuery, dest *tableDestination) error {
+	written := 0
+	stats, err := client.StreamSearchAnalytics(query, func(page []gsc.SearchAnalyticsRow) error {
+		rows, err := analyticsTableRows(query, page)
+		if err != nil {
+			return err
+		}
+		if err := w.Write(rows); err != nil {
+			return err
+		}
+		written += len(rows.Values)
+		return nil
+	})
+	if stats != nil {
+		theme.Fprintf(os.Stderr, "📄 %d rows in %d page(s), %d quota units; %d used today\n", stats.Rows, stats.Pages, stats.Pages, stats.QuotaUsed)
+	}
+	if err != nil {
+		return err
+	}
+	theme.Printf("✓ Appended %d rows to %s\n", written, dest)
+	return nil
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/bigquery"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/sheets"
+)
+
+// fakeBigQueryLoader records every load, failing when err is set.
+type fakeBigQueryLoader struct {
+	loads       []tableRows
+	partitionBy string
+	err         error
+}
+
+func (f *fakeBigQueryLoader) Append(_ context.Context, _ bigquery.Table, columns []string, rows [][]any, partitionBy string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.partitionBy = partitionBy
+	f.loads = append(f.loads, tableRows{Columns: columns, Values: rows})
+	return nil
+}
+
+// fakeSheetsRowWriter records every write.
+type fakeSheetsRowWriter struct {
+	writes [][][]any
+}
+
+func (f *fakeSheetsRowWriter) Write(_ context.Context, _ []string, rows [][]any) error {
+	f.writes = append(f.writes, rows)
+	return nil
+}
+
+func TestParseTableDestination_BigQuery(t *testing.T) {
+	dest, err := parseTableDestination("report.csv")
+	require.NoError(t, err)
+	assert.Nil(t, dest)
+
+	loader := &fakeBigQueryLoader{}
+	orig := newBigQueryLoader
+	newBigQueryLoader = func() (bigQueryLoader, error) { return loader, nil }
+	t.Cleanup(func() { newBigQueryLoader = orig })
+
+	dest, err = parseTableDestination("bq://my-project.seo.search_analytics")
+	require.NoError(t, err)
+	require.NotNil(t, dest)
+	assert.Equal(t, "my-project.seo.search_analytics", dest.String())
+
+	w, err := dest.open()
+	require.NoError(t, err)
+	require.NoError(t, w.Write(tableRows{Columns: []string{"page"}, Values: [][]any{{"/a"}}}))
+	assert.Equal(t, reportDateColumn, loader.partitionBy)
+
+	_, err = parseTableDestination("bq://my-project.seo")
+	assert.Error(t, err)
+}
+
+func TestParseTableDestination_Sheets(t *testing.T) {
+	rows := &fakeSheetsRowWriter{}
+	var gotMode string
+	orig := newSheetsWriter
+	newSheetsWriter = func(_ sheets.Target, mode string) (tableWriter, error) {
+		gotMode = mode
+		return &sheetsTableWriter{w: rows}, nil
+	}
+	t.Cleanup(func() { newSheetsWriter = orig; sheetMode = sheets.ModeAppend })
+
+	sheetMode = sheets.ModeReplace
+	dest, err := parseTableDestination("sheets://1AbC/Search%20Analytics")
+	require.NoError(t, err)
+	require.NotNil(t, dest)
+	assert.Equal(t, "sheets://1AbC/Search Analytics", dest.String())
+
+	w, err := dest.open()
+	require.NoError(t, err)
+	require.NoError(t, w.Write(tableRows{Columns: []string{"page"}, Values: [][]any{{"/a"}}}))
+	assert.Equal(t, sheets.ModeReplace, gotMode)
+	assert.Len(t, rows.writes, 1)
+
+	sheetMode = "overwrite"
+	_, err = parseTableDestination("sheets://1AbC/Data")
+	assert.ErrorContains(t, err, "invalid --sheet-mode")
+}
+
+func TestAnalyticsTableRows(t *testing.T) {
+	query := &gsc.SearchAnalyticsQuery{
+		SiteURL: "sc-domain:example.com", StartDate: "2026-09-01", EndDate: "2026-09-28",
+		Dimensions: []string{"date", "searchAppearance"},
+	}
+	rows, err := analyticsTableRows(query, []gsc.SearchAnalyticsRow{
+		{Keys: []string{"2026-09-02", "VIDEO"}, Clicks: 3, Impressions: 40, CTR: 0.075, Position: 4.5},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"date", "search_appearance", "site", "report_date", "start_date", "clicks", "impressions", "ctr", "position"}, rows.Columns)
+	assert.Equal(t, [][]any{{
+		bigquery.Date("2026-09-02"), "VIDEO", "sc-domain:example.com", bigquery.Date("2026-09-28"), bigquery.Date("2026-09-01"),
+		int64(3), int64(40), 0.075, 4.5,
+	}}, rows.Values)
+
+	schema, err := bigquery.InferSchema(rows.Columns, rows.Values)
+	require.NoError(t, err)
+	assert.Equal(t, bigquery.Column{Name: "report_date", Type: bigquery.TypeDate}, schema[3])
+	assert.Equal(t, bigquery.Column{Name: "clicks", Type: bigquery.TypeInteger}, schema[5])
+}
+
+func TestAnalyticsTableRows_UnknownDimension(t *testing.T) {
+	_, err := analyticsTableRows(&gsc.SearchAnalyticsQuery{Dimensions: []string{"hour"}}, nil)
+	assert.ErrorContains(t, err, `"hour"`)
+}
+
+func TestCoverageTableRows(t *testing.T) {
+	rows := coverageTableRows("sc-domain:example.com", "2026-09-01", "2026-09-30", []gsc.PageCoverage{
+		{URL: "https://example.com/a", Impressions: 12, Clicks: 1, CTR: 0.083, Position: 7, Status: "indexed"},
+	})
+
+	require.Len(t, rows.Values, 1)
+	assert.Equal(t, []any{
+		"sc-domain:example.com", bigquery.Date("2026-09-30"), bigquery.Date("2026-09-01"),
+		"https://example.com/a", "indexed", int64(12), int64(1), 0.083, 7.0,
+	}, rows.Values[0])
+	_, err := bigquery.InferSchema(rows.Columns, rows.Values)
+	assert.NoError(t, err)
+}
+
+func TestReportTableRows(t *testing.T) {
+	exportedAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	rows := reportTableRows(&ReportData{
+		ProjectName:   "Example",
+		PropertyID:    "123456789",
+		Conversions:   []ConversionData{{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"}},
+		Dimensions:    []DimensionData{{DisplayName: "Plan", ParameterName: "plan", Scope: "USER"}},
+		Audiences:     []AudienceData{{Name: "Buyers", Category: "Purchasers", MembershipDuration: 30}},
+		DataRetention: DataRetentionData{EventDataRetention: "FOURTEEN_MONTHS"},
+	}, exportedAt)
+
+	require.Len(t, rows.Values, 4)
+	assert.Equal(t, []any{
+		bigquery.Date("2026-10-16"), exportedAt, "Example", "123456789", "custom_dimension", "Plan", "plan", "USER", nil, nil,
+	}, rows.Values[1])
+	assert.Equal(t, "Purchasers, 30 days", rows.Values[2][9])
+
+	schema, err := bigquery.InferSchema(rows.Columns, rows.Values)
+	require.NoError(t, err)
+	assert.Equal(t, bigquery.TypeTimestamp, schema[1].Type)
+	assert.Equal(t, bigquery.TypeString, schema[8].Type, "an all-null column is STRING")
+}
+
+func TestStreamAnalyticsTable_WritesEachPage(t *testing.T) {
+	query := &gsc.SearchAnalyticsQuery{SiteURL: "sc-domain:example.com", StartDate: "2026-09-01", EndDate: "2026-09-28", Dimensions: []string{"query"}}
+	streamer := &fakeStreamer{
+		pages: [][]gsc.SearchAnalyticsRow{{streamRow("a", 1), streamRow("b", 2)}, {streamRow("c", 3)}},
+		out:   &bytes.Buffer{},
+	}
+	loader := &fakeBigQueryLoader{}
+
+	err := streamAnalyticsTable(&bigQueryWriter{loader: loader}, streamer, query, &tableDestination{name: "p.d.t"})
+	require.NoError(t, err)
+	require.Len(t, loader.loads, 2)
+	assert.Len(t, loader.loads[0].Values, 2)
+	assert.Equal(t, "c", loader.loads[1].Values[0][0])
+}
+
+func TestStreamAnalyticsTable_StopsOnWriteError(t *testing.T) {
+	query := &gsc.SearchAnalyticsQuery{Dimensions: []string{"query"}}
+	streamer := &fakeStreamer{
+		pages: [][]gsc.SearchAnalyticsRow{{streamRow("a", 1)}, {streamRow("b", 2)}},
+		out:   &bytes.Buffer{},
+	}
+
+	err := streamAnalyticsTable(&bigQueryWriter{loader: &fakeBigQueryLoader{err: errors.New("quota")}}, streamer, query, &tableDestination{name: "p.d.t"})
+	assert.EqualError(t, err, "quota")
+	assert.Len(t, streamer.written, 1, "no page is fetched after a failed write")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var docsManDir string
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate reference documentation for the CLI",
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generate man pages for every command",
+	Long: `Write a man page per command (ga4.1, ga4-gsc.1, ga4-gsc-monitor-run.1, ...)
+into --dir, from the same help text ` + "`--help`" + ` prints.
+
+Examples:
+  ga4 docs man
+  ga4 docs man --dir /usr/local/share/man/man1
+  man ./man/ga4-setup.1`,
+	Args: cobra.NoArgs,
+	RunE: runDocsMan,
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsManCmd.Flags().StringVarP(&docsManDir, "dir", "d", "man", "Directory to write the man pages to (created if missing)")
+}
+
+func runDocsMan(cmd *cobra.Command, args []string) error {
+	if err := os.MkdirAll(docsManDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", docsManDir, err)
+	}
+	header := &doc.GenManHeader{
+		Title:   "GA4",
+		Section: "1",
+		Source:  "ga4-manager " + Version,
+		Manual:  "GA4 Manager",
+	}
+	// The footer would otherwise date every page, so regenerating them
+	// changes every file.
+	rootCmd.DisableAutoGenTag = true
+	if err := doc.GenManTree(rootCmd, header, docsManDir); err != nil {
+		return fmt.Errorf("failed to generate man pages: %w", err)
+	}
+	theme.Green("✓ Man pages written to %s", docsManDir)
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	doctorConfig string
+	doctorFormat string
+)
+
+// errDoctorFailures signals a failed check; the command exits with
+// diagcmd.ExitIssues.
+var errDoctorFailures = errors.New("doctor found problems")
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment: network, clock, credentials and APIs",
+	Long: `Check everything ga4-manager needs before it can talk to Google, and say how
+to fix what is wrong:
+
+  Network             the Google API hosts answer over HTTPS
+  Clock               the system clock is within 5 minutes of Google's, or
+                      service account tokens are rejected
+  Credentials         GOOGLE_APPLICATION_CREDENTIALS or a ` + "`ga4 auth login`" + `
+  Credential scopes   a token carries every OAuth scope needed
+  Admin API, Data API,
+  Search Console API  each API is enabled in the credential's Cloud project
+
+With --config, the checks are limited to the services the config uses, and
+the pre-flight checks of ` + "`ga4 setup`" + ` run too (config schema, property and
+site access, roles, tier limits, quota) without changing anything.
+
+Exits 4 when a check fails.
+
+Examples:
+  ga4 doctor
+  ga4 doctor --config configs/mysite.yaml
+  ga4 doctor --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runDoctor(cmd, args)
+		if errors.Is(err, errDoctorFailures) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().StringVarP(&doctorConfig, "config", "c", "", "Path to configuration file (also runs the setup pre-flight checks)")
+	output.FormatVar(doctorCmd.Flags(), &doctorFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+// doctorCheck is one check in the JSON report.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+	Details string `json:"details,omitempty"`
+}
+
+type doctorReport struct {
+	Checks   []doctorCheck `json:"checks"`
+	Failed   int           `json:"failed"`
+	Warnings int           `json:"warnings"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	logger := cliLogger
+
+	var cfg *config.ProjectConfig
+	var ga4Client ga4.GA4Admin
+	var gscClient gsc.SearchConsole
+	if doctorConfig != "" {
+		var err error
+		cfg, err = config.LoadConfig(doctorConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// A client that cannot be created leaves its checks skipped; the
+		// credentials check says why.
+		if cfg.HasAnalytics() {
+			if client, err := newGA4Client(cfg); err == nil {
+				defer client.Close()
+				ga4Client = client
+			} else {
+				logger.Debug("GA4 client unavailable", "error", err)
+			}
+		}
+		if cfg.HasSearchConsole() {
+			if client, err := gsc.NewClient(gscClientOptions()...); err == nil {
+				defer func() { _ = client.Close() }()
+				gscClient = client
+			} else {
+				logger.Debug("GSC client unavailable", "error", err)
+			}
+		}
+	}
+
+	results := setup.NewDoctor(cfg, ga4Client, gscClient, logger).RunAll()
+
+	var report doctorReport
+	for _, r := range results {
+		check := doctorCheck{Name: r.Name, Status: r.Status.String(), Warning: r.Warning, Details: r.Details}
+		if r.Error != nil {
+			check.Error = r.Error.Error()
+		}
+		switch r.Status {
+		case setup.ValidationFailed:
+			report.Failed++
+		case setup.ValidationWarning:
+			report.Warnings++
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	if doctorFormat == output.FormatJSON {
+		if err := output.JSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else {
+		theme.Cyan("═══ ga4 doctor ═══")
+		theme.Println()
+		setup.PrintValidationResults(results)
+		theme.Println()
+		switch {
+		case report.Failed > 0:
+			theme.Red("✗ %d checks failed, %d warnings. Fix the errors above and run ga4 doctor again.", report.Failed, report.Warnings)
+		case report.Warnings > 0:
+			theme.Yellow("⚠ All checks passed, %d warnings.", report.Warnings)
+		default:
+			theme.Green("✓ All checks passed.")
+		}
+	}
+
+	if report.Failed > 0 {
+		return errDoctorFailures
+	}
+	return nil
+}
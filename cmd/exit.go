@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// quietMode and jsonMode are the --quiet and --json flags.
+var (
+	quietMode bool
+	jsonMode  bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&quietMode, "quiet", false, "Print nothing but errors; the exit code carries the result")
+	rootCmd.PersistentFlags().BoolVar(&jsonMode, "json", false, "Print one JSON object as the result: the command's --format json report, or {command, ok, exit_code, error}")
+	cobra.OnInitialize(applyOutputMode)
+}
+
+// applyOutputMode silences human output under --quiet and --json once flags
+// are parsed. --json also switches every command that has a JSON report to
+// --format json, unless --format was given.
+func applyOutputMode() {
+	if !quietMode && !jsonMode {
+		return
+	}
+	theme.SetQuiet(true)
+	if jsonMode {
+		color.NoColor = true
+		useJSONFormat(rootCmd)
+	}
+}
+
+func useJSONFormat(c *cobra.Command) {
+	output.UseFormat(c.Flags(), output.FormatJSON)
+	for _, sub := range c.Commands() {
+		useJSONFormat(sub)
+	}
+}
+
+// exitWith ends a command that has already reported its outcome with code,
+// without cobra printing an error or usage on top.
+func exitWith(cmd *cobra.Command, code int) error {
+	if code == exitcode.OK {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return exitcode.New(code, nil)
+}
+
+// commandResult is the --json object of a command with no JSON report of its
+// own, or one that failed before writing it.
+type commandResult struct {
+	Command  string `json:"command"`
+	OK       bool   `json:"ok"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// writeResult writes the --json result of cmd, which ended with err, to w
+// unless the command's own report already is the result.
+func writeResult(w io.Writer, cmd *cobra.Command, err error) {
+	code := exitcode.Of(err)
+	if f := cmd.Flags().Lookup("format"); f != nil && f.Value.String() == output.FormatJSON {
+		if code == exitcode.OK || code == exitcode.Alerts {
+			return
+		}
+	}
+	res := commandResult{Command: cmd.CommandPath(), OK: code == exitcode.OK, ExitCode: code}
+	if err != nil && !exitcode.Silent(err) {
+		res.Error = err.Error()
+	}
+	if err := output.JSON(w, res); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+}
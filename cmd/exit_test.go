@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+func resultCommand(formats ...string) *cobra.Command {
+	c := &cobra.Command{Use: "probe"}
+	if len(formats) > 0 {
+		var format string
+		output.FormatVar(c.Flags(), &format, "", formats...)
+		output.UseFormat(c.Flags(), output.FormatJSON)
+	}
+	return c
+}
+
+func decodeResult(t *testing.T, buf *bytes.Buffer) commandResult {
+	t.Helper()
+	var res commandResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &res))
+	return res
+}
+
+func TestWriteResult_NoJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+	writeResult(&buf, resultCommand(), nil)
+	assert.Equal(t, commandResult{Command: "probe", OK: true, ExitCode: exitcode.OK}, decodeResult(t, &buf))
+
+	buf.Reset()
+	writeResult(&buf, resultCommand(), &googleapi.Error{Code: 500, Message: "backend"})
+	res := decodeResult(t, &buf)
+	assert.False(t, res.OK)
+	assert.Equal(t, exitcode.API, res.ExitCode)
+	assert.Contains(t, res.Error, "backend")
+}
+
+func TestWriteResult_OwnJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+	writeResult(&buf, resultCommand(output.FormatTable, output.FormatJSON), nil)
+	assert.Empty(t, buf.String(), "the command's report is the result")
+
+	writeResult(&buf, resultCommand(output.FormatTable, output.FormatJSON), exitcode.New(exitcode.Alerts, nil))
+	assert.Empty(t, buf.String(), "findings are in the report")
+
+	writeResult(&buf, resultCommand(output.FormatTable, output.FormatJSON), errors.New("invalid --days 0"))
+	res := decodeResult(t, &buf)
+	assert.Equal(t, exitcode.Validation, res.ExitCode)
+	assert.Equal(t, "invalid --days 0", res.Error)
+}
+
+func TestExitWith(t *testing.T) {
+	c := resultCommand()
+	assert.NoError(t, exitWith(c, exitcode.OK))
+	assert.False(t, c.SilenceErrors)
+
+	err := exitWith(c, exitcode.Alerts)
+	assert.Equal(t, exitcode.Alerts, exitcode.Of(err))
+	assert.True(t, exitcode.Silent(err))
+	assert.True(t, c.SilenceErrors)
+	assert.True(t, c.SilenceUsage)
+}
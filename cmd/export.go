@@ -1,8 +1,7 @@
 package cmd
 
 import (
-	"encoding/csv"
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +10,8 @@ import (
 
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // ReportData holds all the data collected from a project report
@@ -130,15 +131,13 @@ func collectReportData(client *ga4.Client, cfg *config.ProjectConfig) (*ReportDa
 
 	// Collect audiences
 	audienceCategories := ga4.ListAudiencesByCategory(cfg)
-	for _, category := range []string{"SEO", "Conversion", "Content", "Behavioral"} {
-		if audiences, ok := audienceCategories[category]; ok {
-			for _, aud := range audiences {
-				data.Audiences = append(data.Audiences, AudienceData{
-					Name:               aud.Name,
-					Category:           aud.Category,
-					MembershipDuration: aud.MembershipDuration,
-				})
-			}
+	for _, category := range ga4.AudienceCategories(cfg) {
+		for _, aud := range audienceCategories[category] {
+			data.Audiences = append(data.Audiences, AudienceData{
+				Name:               aud.Name,
+				Category:           aud.Category,
+				MembershipDuration: aud.MembershipDuration,
+			})
 		}
 	}
 
@@ -165,21 +164,21 @@ func collectReportData(client *ga4.Client, cfg *config.ProjectConfig) (*ReportDa
 
 // exportToJSON exports report data to JSON format
 func exportToJSON(data *ReportData, outputPath string) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
+	var jsonData bytes.Buffer
+	if err := output.JSON(&jsonData, data); err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
 	if outputPath == "" {
-		fmt.Println(string(jsonData))
-		return nil
+		_, err := os.Stdout.Write(jsonData.Bytes())
+		return err
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	if err := os.WriteFile(outputPath, jsonData.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write JSON file: %w", err)
 	}
 
-	fmt.Printf("✓ Report exported to: %s\n", outputPath)
+	theme.Printf("✓ Report exported to: %s\n", outputPath)
 	return nil
 }
 
@@ -201,7 +200,7 @@ func exportToCSV(data *ReportData, outputPath string) error {
 		if err := writeCSV(convPath, []string{"Event Name", "Counting Method"}, data.Conversions); err != nil {
 			return err
 		}
-		fmt.Printf("✓ Conversions exported to: %s\n", convPath)
+		theme.Printf("✓ Conversions exported to: %s\n", convPath)
 	}
 
 	// Export dimensions
@@ -210,7 +209,7 @@ func exportToCSV(data *ReportData, outputPath string) error {
 		if err := writeCSV(dimPath, []string{"Display Name", "Parameter", "Scope"}, data.Dimensions); err != nil {
 			return err
 		}
-		fmt.Printf("✓ Dimensions exported to: %s\n", dimPath)
+		theme.Printf("✓ Dimensions exported to: %s\n", dimPath)
 	}
 
 	// Export metrics
@@ -219,7 +218,7 @@ func exportToCSV(data *ReportData, outputPath string) error {
 		if err := writeCSV(metricPath, []string{"Display Name", "Parameter", "Unit", "Scope"}, data.Metrics); err != nil {
 			return err
 		}
-		fmt.Printf("✓ Metrics exported to: %s\n", metricPath)
+		theme.Printf("✓ Metrics exported to: %s\n", metricPath)
 	}
 
 	// Export calculated metrics
@@ -228,7 +227,7 @@ func exportToCSV(data *ReportData, outputPath string) error {
 		if err := writeCSV(calcPath, []string{"Display Name", "Formula", "Unit"}, data.CalculatedMetrics); err != nil {
 			return err
 		}
-		fmt.Printf("✓ Calculated metrics exported to: %s\n", calcPath)
+		theme.Printf("✓ Calculated metrics exported to: %s\n", calcPath)
 	}
 
 	// Export audiences
@@ -237,7 +236,7 @@ func exportToCSV(data *ReportData, outputPath string) error {
 		if err := writeCSV(audPath, []string{"Name", "Category", "Duration (days)"}, data.Audiences); err != nil {
 			return err
 		}
-		fmt.Printf("✓ Audiences exported to: %s\n", audPath)
+		theme.Printf("✓ Audiences exported to: %s\n", audPath)
 	}
 
 	return nil
@@ -255,49 +254,47 @@ func writeCSV(filepath string, headers []string, data interface{}) (err error) {
 		}
 	}()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write header
-	if err := writer.Write(headers); err != nil {
+	r, err := output.NewRenderer(output.FormatCSV, output.Options{})
+	if err != nil {
 		return err
 	}
+	return r.Render(file, headers, exportCells(data))
+}
 
-	// Write data based on type
+// exportCells flattens a slice of report structs into cells, one row per item
+func exportCells(data interface{}) [][]string {
+	var rows [][]string
 	switch v := data.(type) {
 	case []ConversionData:
 		for _, item := range v {
-			if err := writer.Write([]string{item.EventName, item.CountingMethod}); err != nil {
-				return err
-			}
+			rows = append(rows, []string{item.EventName, item.CountingMethod})
 		}
 	case []DimensionData:
 		for _, item := range v {
-			if err := writer.Write([]string{item.DisplayName, item.ParameterName, item.Scope}); err != nil {
-				return err
-			}
+			rows = append(rows, []string{item.DisplayName, item.ParameterName, item.Scope})
 		}
 	case []MetricData:
 		for _, item := range v {
-			if err := writer.Write([]string{item.DisplayName, item.ParameterName, item.MeasurementUnit, item.Scope}); err != nil {
-				return err
-			}
+			rows = append(rows, []string{item.DisplayName, item.ParameterName, item.MeasurementUnit, item.Scope})
 		}
 	case []CalculatedMetricData:
 		for _, item := range v {
-			if err := writer.Write([]string{item.DisplayName, item.Formula, item.MetricUnit}); err != nil {
-				return err
-			}
+			rows = append(rows, []string{item.DisplayName, item.Formula, item.MetricUnit})
 		}
 	case []AudienceData:
 		for _, item := range v {
-			if err := writer.Write([]string{item.Name, item.Category, fmt.Sprintf("%d", item.MembershipDuration)}); err != nil {
-				return err
-			}
+			rows = append(rows, []string{item.Name, item.Category, fmt.Sprintf("%d", item.MembershipDuration)})
 		}
 	}
+	return rows
+}
 
-	return nil
+// writeMarkdownSection writes a titled markdown table of report structs
+func writeMarkdownSection(md *strings.Builder, title string, headers []string, data interface{}) {
+	r, _ := output.NewRenderer(output.FormatMarkdown, output.Options{})
+	fmt.Fprintf(md, "## %s\n\n", title)
+	_ = r.Render(md, headers, exportCells(data))
+	md.WriteString("\n")
 }
 
 // exportToMarkdown exports report data to Markdown format
@@ -311,60 +308,25 @@ func exportToMarkdown(data *ReportData, outputPath string) error {
 	fmt.Fprintf(&md, "**Generated:** %s  \n\n", data.Timestamp)
 	md.WriteString("---\n\n")
 
-	// Conversions
 	if len(data.Conversions) > 0 {
-		md.WriteString("## 🎯 Conversions\n\n")
-		md.WriteString("| Event Name | Counting Method |\n")
-		md.WriteString("|------------|----------------|\n")
-		for _, conv := range data.Conversions {
-			fmt.Fprintf(&md, "| %s | %s |\n", conv.EventName, conv.CountingMethod)
-		}
-		md.WriteString("\n")
+		writeMarkdownSection(&md, "🎯 Conversions", []string{"Event Name", "Counting Method"}, data.Conversions)
 	}
-
-	// Dimensions
 	if len(data.Dimensions) > 0 {
-		md.WriteString("## 📊 Custom Dimensions\n\n")
-		md.WriteString("| Display Name | Parameter | Scope |\n")
-		md.WriteString("|--------------|-----------|-------|\n")
-		for _, dim := range data.Dimensions {
-			fmt.Fprintf(&md, "| %s | %s | %s |\n", dim.DisplayName, dim.ParameterName, dim.Scope)
-		}
-		md.WriteString("\n")
+		writeMarkdownSection(&md, "📊 Custom Dimensions", []string{"Display Name", "Parameter", "Scope"}, data.Dimensions)
 	}
-
-	// Metrics
 	if len(data.Metrics) > 0 {
-		md.WriteString("## 📈 Custom Metrics\n\n")
-		md.WriteString("| Display Name | Parameter | Unit | Scope |\n")
-		md.WriteString("|--------------|-----------|------|-------|\n")
-		for _, metric := range data.Metrics {
-			fmt.Fprintf(&md, "| %s | %s | %s | %s |\n",
-				metric.DisplayName, metric.ParameterName, metric.MeasurementUnit, metric.Scope)
-		}
-		md.WriteString("\n")
+		writeMarkdownSection(&md, "📈 Custom Metrics", []string{"Display Name", "Parameter", "Unit", "Scope"}, data.Metrics)
 	}
-
-	// Calculated Metrics
 	if len(data.CalculatedMetrics) > 0 {
-		md.WriteString("## 🧮 Calculated Metrics\n\n")
-		md.WriteString("| Display Name | Formula | Unit |\n")
-		md.WriteString("|--------------|---------|------|\n")
-		for _, calc := range data.CalculatedMetrics {
-			fmt.Fprintf(&md, "| %s | `%s` | %s |\n", calc.DisplayName, calc.Formula, calc.MetricUnit)
+		formulas := make([]CalculatedMetricData, len(data.CalculatedMetrics))
+		for i, calc := range data.CalculatedMetrics {
+			calc.Formula = "`" + calc.Formula + "`"
+			formulas[i] = calc
 		}
-		md.WriteString("\n")
+		writeMarkdownSection(&md, "🧮 Calculated Metrics", []string{"Display Name", "Formula", "Unit"}, formulas)
 	}
-
-	// Audiences
 	if len(data.Audiences) > 0 {
-		md.WriteString("## 👥 Audiences\n\n")
-		md.WriteString("| Name | Category | Duration (days) |\n")
-		md.WriteString("|------|----------|----------------|\n")
-		for _, aud := range data.Audiences {
-			fmt.Fprintf(&md, "| %s | %s | %d |\n", aud.Name, aud.Category, aud.MembershipDuration)
-		}
-		md.WriteString("\n")
+		writeMarkdownSection(&md, "👥 Audiences", []string{"Name", "Category", "Duration (days)"}, data.Audiences)
 	}
 
 	// Data Retention
@@ -377,7 +339,7 @@ func exportToMarkdown(data *ReportData, outputPath string) error {
 	content := md.String()
 
 	if outputPath == "" {
-		fmt.Println(content)
+		theme.Println(content)
 		return nil
 	}
 
@@ -385,7 +347,7 @@ func exportToMarkdown(data *ReportData, outputPath string) error {
 		return fmt.Errorf("failed to write Markdown file: %w", err)
 	}
 
-	fmt.Printf("✓ Report exported to: %s\n", outputPath)
+	theme.Printf("✓ Report exported to: %s\n", outputPath)
 	return nil
 }
 
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	featuresConfig string
+	featuresDetect bool
+	featuresFormat string
+)
+
+// errFeatureMismatch signals that --detect found a flag switched on for a
+// surface the API does not serve; the command exits with diagcmd.ExitIssues.
+var errFeatureMismatch = errors.New("enabled feature not available")
+
+var featuresCmd = &cobra.Command{
+	Use:   "features",
+	Short: "Show which alpha Admin API features a property has enabled",
+	Long: `Show the per-property feature flags that gate Admin API surfaces only
+available in v1alpha, and optionally probe the API to see which of them the
+property can actually use.
+
+Google changes v1alpha surfaces without notice. Keeping them behind flags means
+a change there breaks only the configs that opted in, never the stable
+conversions/dimensions/metrics workflow.
+
+Flags live under analytics.features (or the legacy ga4.features):
+
+  analytics:
+    property_id: "123456789"
+    features:
+      audiences: true
+      channel_groups: false
+
+--detect makes one read-only list call per feature and reports:
+  available     the API answered
+  unavailable   404/501, usually a v1alpha removal or rename
+  forbidden     403, the credentials lack access
+  unsupported   400, the property cannot use it (e.g. 360-only)
+  error         anything else (timeout, quota, 5xx)
+
+With --detect the command exits 4 when an enabled feature is not available.
+
+Examples:
+  ga4 features --config configs/mysite.yaml
+  ga4 features --config configs/mysite.yaml --detect
+  ga4 features --config configs/mysite.yaml --detect --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runFeatures(cmd, args)
+		if errors.Is(err, errFeatureMismatch) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(featuresCmd)
+	featuresCmd.Flags().StringVarP(&featuresConfig, "config", "c", "", "Path to configuration file")
+	featuresCmd.Flags().BoolVar(&featuresDetect, "detect", false, "Probe the Admin API for each feature (read-only)")
+	output.FormatVar(featuresCmd.Flags(), &featuresFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = featuresCmd.MarkFlagRequired("config")
+}
+
+func runFeatures(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(featuresConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	features := cfg.Features()
+
+	var caps []ga4.Capability
+	if featuresDetect {
+		if cfg.GetPropertyID() == "" {
+			return fmt.Errorf("--detect needs analytics.property_id in %s", featuresConfig)
+		}
+		client, err := newGA4Client(cfg)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		caps = client.DetectCapabilities(cfg.GetPropertyID())
+	} else {
+		for _, info := range config.KnownFeatures() {
+			caps = append(caps, ga4.Capability{
+				Feature:  info.Name,
+				Enabled:  features.Enabled(info.Name),
+				Explicit: features.Explicit(info.Name),
+			})
+		}
+	}
+
+	if featuresFormat == "json" {
+		if err := output.JSON(os.Stdout, caps); err != nil {
+			return err
+		}
+	} else if err := displayFeatures(cfg, caps); err != nil {
+		return err
+	}
+
+	for _, c := range caps {
+		if featuresDetect && c.Mismatch() {
+			return errFeatureMismatch
+		}
+	}
+	return nil
+}
+
+func displayFeatures(cfg *config.ProjectConfig, caps []ga4.Capability) error {
+	theme.Cyan("═══ Alpha Admin API features: %s (property %s) ═══", cfg.Project.Name, cfg.GetPropertyID())
+	theme.Println()
+
+	columns := []string{"Feature", "Flag", "Description"}
+	if featuresDetect {
+		columns = append(columns, "API", "Detail")
+	}
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, columns, caps, func(c ga4.Capability) []string {
+		info, _ := config.LookupFeature(string(c.Feature))
+		row := []string{string(c.Feature), featureFlagCell(c), info.Description}
+		if featuresDetect {
+			row = append(row, capabilityCell(c), c.Detail)
+		}
+		return row
+	}); err != nil {
+		return err
+	}
+
+	if !featuresDetect {
+		theme.Println()
+		theme.HiBlack("Run with --detect to check which features the API serves for this property.")
+		return nil
+	}
+	for _, c := range caps {
+		switch {
+		case c.Mismatch():
+			theme.Red("✗ %s is enabled but the API reports %s — set analytics.features.%s: false until it is available", c.Feature, c.Status, c.Feature)
+		case !c.Enabled && c.Status == ga4.CapabilityAvailable:
+			theme.HiBlack("ℹ %s is available; set analytics.features.%s: true to use it", c.Feature, c.Feature)
+		}
+	}
+	return nil
+}
+
+func featureFlagCell(c ga4.Capability) string {
+	state := "off"
+	if c.Enabled {
+		state = "on"
+	}
+	if !c.Explicit {
+		state += " (default)"
+	}
+	return state
+}
+
+func capabilityCell(c ga4.Capability) string {
+	switch c.Status {
+	case ga4.CapabilityAvailable:
+		return theme.GreenString("%s", c.Status)
+	case ga4.CapabilityError:
+		return theme.YellowString("%s", c.Status)
+	default:
+		if c.Enabled {
+			return theme.RedString("%s", c.Status)
+		}
+		return theme.HiBlackString("%s", c.Status)
+	}
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gtm"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/plandoc"
+	"github.com/garbarok/ga4-manager/internal/theme"
+	"github.com/garbarok/ga4-manager/internal/tracking"
+)
+
+var (
+	generateConfig     string
+	generateFramework  string
+	generateLang       string
+	generateOutDir     string
+	generateGTMConfig  string
+	generateGTMOutput  string
+	generatePlanConfig string
+	generatePlanFormat string
+	generatePlanOutput string
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate site code from a configuration",
+}
+
+var generateTrackingCmd = &cobra.Command{
+	Use:   "tracking",
+	Short: "Generate the gtag.js snippet and typed event helpers",
+	Long: `Generate the tracking code for the property in the config: the gtag.js
+snippet for analytics.measurement_id, and a helpers module with a function
+per conversion (trackPurchase, trackGenerateLead, ...) whose parameters are
+typed from the custom dimensions and metrics, plus setUserProperties for
+user-scoped dimensions.
+
+Conversions that carry a value (purchase and lead events, value_required or
+default_value in the config) take value and currency, the currency
+defaulting to the config's. Regenerate after changing the config so the
+site sends the events and parameters ga4 setup registers.
+
+Files by --framework:
+  nextjs  components/GoogleAnalytics.tsx (next/script), lib/analytics.ts
+  astro   src/components/GoogleAnalytics.astro, src/lib/analytics.ts
+  plain   gtag.html, analytics.js
+
+--lang js writes JavaScript with JSDoc types instead of TypeScript. Without
+--out-dir the files are printed; with it they are written under it,
+replacing earlier versions.
+
+Examples:
+  ga4 generate tracking --config configs/mysite.yaml --framework nextjs
+  ga4 generate tracking --config configs/mysite.yaml --framework astro --out-dir .
+  ga4 generate tracking --config configs/mysite.yaml --framework plain --lang ts`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runGenerateTracking(generateTrackingParams{
+			ConfigPath: generateConfig,
+			Framework:  generateFramework,
+			Lang:       generateLang,
+			OutDir:     generateOutDir,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+		})
+	},
+}
+
+var generateGTMCmd = &cobra.Command{
+	Use:   "gtm",
+	Short: "Generate a GTM container export for the configured events",
+	Long: `Generate a Google Tag Manager container export (JSON) that sends the
+conversions in the config to GA4, to load with Admin → Import Container:
+
+  - a constant variable "GA4 Measurement ID" with analytics.measurement_id
+  - a Google tag for it on All Pages
+  - per conversion, a custom event trigger on the dataLayer event of that
+    name and a GA4 Event tag forwarding the event-scoped custom dimension
+    and metric parameters (plus value and currency for value-bearing
+    conversions) and the user-scoped dimensions as user properties
+  - a dataLayer variable per parameter
+
+The site then pushes the events to the dataLayer, for example:
+
+  dataLayer.push({event: 'purchase', value: 9.99, currency: 'EUR', plan_type: 'pro'});
+
+Import into a new workspace and choose Merge to keep the container's other
+tags. ga4 gtm audit reports every conversion as wired once it is published.
+
+Examples:
+  ga4 generate gtm --config configs/mysite.yaml > container.json
+  ga4 generate gtm --config configs/mysite.yaml --output container.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runGenerateGTM(generateGTMParams{
+			ConfigPath: generateGTMConfig,
+			Output:     generateGTMOutput,
+			Stdout:     os.Stdout,
+			Stderr:     os.Stderr,
+			Now:        time.Now,
+		})
+	},
+}
+
+var generatePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Generate the measurement plan document",
+	Long: `Write the measurement plan of the config as a document to share with the
+people who implement and use the tracking, instead of a spreadsheet kept by
+hand:
+
+  Events      every conversion: counting method, value, the parameters it
+              sends, the audiences that use it, priority and owner
+  Parameters  every custom dimension and metric and each parameter an event
+              lists: what it is registered as, its scope, the events that
+              send it, the audiences and calculated metrics that use it
+  Audiences   definition, and the events and parameters it names
+
+Which event sends which parameter comes from the conversions' parameters
+lists; a listed parameter that is no custom dimension or metric (other than
+value, currency and GA4's other built-in ones) is flagged as not registered.
+Owners come from the owner fields:
+
+  conversions:
+    - name: purchase
+      counting_method: ONCE_PER_EVENT
+      parameters: [value, currency, plan_type]
+      owner: checkout team
+
+Examples:
+  ga4 generate plan --config configs/mysite.yaml > PLAN.md
+  ga4 generate plan --config configs/mysite.yaml --format html -o plan.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runGeneratePlan(generatePlanParams{
+			ConfigPath: generatePlanConfig,
+			Format:     generatePlanFormat,
+			Output:     generatePlanOutput,
+			Stdout:     os.Stdout,
+			Now:        time.Now,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(generateTrackingCmd, generateGTMCmd, generatePlanCmd)
+	generateTrackingCmd.Flags().StringVarP(&generateConfig, "config", "c", "", "Path to configuration file (required)")
+	generateTrackingCmd.Flags().StringVar(&generateFramework, "framework", tracking.FrameworkPlain, "Site framework: "+strings.Join(tracking.Frameworks(), ", "))
+	generateTrackingCmd.Flags().StringVar(&generateLang, "lang", "", "Helpers language: ts or js (default ts, js for plain)")
+	generateTrackingCmd.Flags().StringVarP(&generateOutDir, "out-dir", "o", "", "Write the files under this directory instead of printing them")
+	_ = generateTrackingCmd.MarkFlagRequired("config")
+
+	generateGTMCmd.Flags().StringVarP(&generateGTMConfig, "config", "c", "", "Path to configuration file (required)")
+	generateGTMCmd.Flags().StringVarP(&generateGTMOutput, "output", "o", "", "Write the container export to this file instead of stdout")
+	_ = generateGTMCmd.MarkFlagRequired("config")
+
+	generatePlanCmd.Flags().StringVarP(&generatePlanConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(generatePlanCmd.Flags(), &generatePlanFormat, "f", output.FormatMarkdown, output.FormatHTML, output.FormatJSON)
+	generatePlanCmd.Flags().StringVarP(&generatePlanOutput, "output", "o", "", "Write the plan to this file instead of stdout")
+	_ = generatePlanCmd.MarkFlagRequired("config")
+}
+
+type generateTrackingParams struct {
+	ConfigPath string
+	Framework  string
+	Lang       string
+	OutDir     string
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+func runGenerateTracking(p generateTrackingParams) error {
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	lang := p.Lang
+	if lang == "" {
+		lang = tracking.DefaultLang(p.Framework)
+	}
+	files, err := tracking.Generate(cfg, filepath.Base(p.ConfigPath), p.Framework, lang)
+	if err != nil {
+		return err
+	}
+	if cfg.GetMeasurementID() == "" {
+		theme.Fprintf(p.Stderr, "%s no analytics.measurement_id in %s: replace %s in the generated code\n",
+			theme.YellowString("⚠"), p.ConfigPath, tracking.PlaceholderMeasurementID)
+	}
+	if len(cfg.Conversions) == 0 {
+		theme.Fprintf(p.Stderr, "%s %s has no conversions: the helpers only set up gtag\n", theme.YellowString("⚠"), p.ConfigPath)
+	}
+
+	if p.OutDir == "" {
+		for i, f := range files {
+			if i > 0 {
+				fmt.Fprintln(p.Stdout)
+			}
+			fmt.Fprintf(p.Stdout, "==> %s <==\n%s", f.Path, f.Content)
+		}
+		return nil
+	}
+	for _, f := range files {
+		path := filepath.Join(p.OutDir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(f.Content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		theme.Fprintf(p.Stdout, "%s Wrote %s\n", theme.GreenString("✓"), path)
+	}
+	return nil
+}
+
+type generateGTMParams struct {
+	ConfigPath string
+	Output     string
+	Stdout     io.Writer
+	Stderr     io.Writer
+	Now        func() time.Time
+}
+
+func runGenerateGTM(p generateGTMParams) error {
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Conversions) == 0 {
+		return fmt.Errorf("%s has no conversions to send", p.ConfigPath)
+	}
+	spec := gtmExportSpec(cfg)
+	if spec.MeasurementID == "" {
+		spec.MeasurementID = tracking.PlaceholderMeasurementID
+		theme.Fprintf(p.Stderr, "%s no analytics.measurement_id in %s: set the %q variable after importing\n",
+			theme.YellowString("⚠"), p.ConfigPath, "GA4 Measurement ID")
+	}
+	export := gtm.NewExport(spec, p.Now())
+
+	if p.Output == "" {
+		return output.JSON(p.Stdout, export)
+	}
+	f, err := os.Create(p.Output)
+	if err != nil {
+		return err
+	}
+	if err := output.JSON(f, export); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	theme.Fprintf(p.Stdout, "%s Wrote %s: %d tag(s), %d trigger(s), %d variable(s)\n", theme.GreenString("✓"), p.Output,
+		len(export.ContainerVersion.Tag), len(export.ContainerVersion.Trigger), len(export.ContainerVersion.Variable))
+	return nil
+}
+
+// gtmExportSpec lists the config's conversions with the parameters their
+// tags forward: every event-scoped dimension and metric, as the config does
+// not say which event carries which, and value and currency on
+// value-bearing conversions.
+func gtmExportSpec(cfg *config.ProjectConfig) gtm.ExportSpec {
+	spec := gtm.ExportSpec{Name: cfg.Project.Name, MeasurementID: cfg.GetMeasurementID()}
+	var eventParams []string
+	for _, d := range cfg.Dimensions {
+		if strings.EqualFold(d.Scope, "USER") {
+			spec.UserProperties = append(spec.UserProperties, d.ParameterName)
+		} else {
+			eventParams = append(eventParams, d.ParameterName)
+		}
+	}
+	for _, m := range cfg.Metrics {
+		eventParams = append(eventParams, m.ParameterName)
+	}
+	for _, c := range cfg.Conversions {
+		params := slices.Clone(eventParams)
+		if cfg.IsValueBearingEvent(c.Name) || c.DefaultValue != nil {
+			params = append(params, "value", "currency")
+		}
+		spec.Events = append(spec.Events, gtm.ExportEvent{Name: c.Name, Params: params})
+	}
+	return spec
+}
+
+type generatePlanParams struct {
+	ConfigPath string
+	Format     string
+	Output     string
+	Stdout     io.Writer
+	Now        func() time.Time
+}
+
+func runGeneratePlan(p generatePlanParams) error {
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	plan := plandoc.Build(cfg, p.Now())
+
+	out, closeOutput, err := openOutput(p.Output, p.Format)
+	if err != nil {
+		return err
+	}
+	if p.Output == "" {
+		out = p.Stdout
+	}
+	switch p.Format {
+	case output.FormatJSON:
+		err = output.JSON(out, plan)
+	case output.FormatHTML:
+		err = plandoc.HTML(out, plan)
+	default:
+		err = plandoc.Markdown(out, plan)
+	}
+	if closeErr := closeOutput(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
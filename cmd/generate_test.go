@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gtm"
+)
+
+const generateTestConfig = `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+conversions:
+  - name: generate_lead
+    counting_method: ONCE_PER_EVENT
+dimensions:
+  - parameter: plan_type
+    display_name: Plan Type
+    scope: EVENT
+`
+
+func TestRunGenerateTracking_WritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(t, runGenerateTracking(generateTrackingParams{
+		ConfigPath: writeSummaryConfig(t, generateTestConfig),
+		Framework:  "nextjs",
+		OutDir:     dir,
+		Stdout:     stdout,
+		Stderr:     stderr,
+	}))
+
+	helpers, err := os.ReadFile(filepath.Join(dir, "lib", "analytics.ts"))
+	require.NoError(t, err)
+	assert.Contains(t, string(helpers), "export function trackGenerateLead(params: EventParams & ValueParams = {}): void {")
+	assert.FileExists(t, filepath.Join(dir, "components", "GoogleAnalytics.tsx"))
+	assert.Contains(t, stdout.String(), filepath.Join(dir, "lib", "analytics.ts"))
+	assert.Contains(t, stderr.String(), "no analytics.measurement_id")
+}
+
+func TestRunGenerateTracking_PrintsFiles(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	require.NoError(t, runGenerateTracking(generateTrackingParams{
+		ConfigPath: writeSummaryConfig(t, generateTestConfig),
+		Framework:  "plain",
+		Stdout:     stdout,
+		Stderr:     &bytes.Buffer{},
+	}))
+	assert.Contains(t, stdout.String(), "==> gtag.html <==\n")
+	assert.Contains(t, stdout.String(), "==> analytics.js <==\n")
+
+	err := runGenerateTracking(generateTrackingParams{
+		ConfigPath: writeSummaryConfig(t, generateTestConfig),
+		Framework:  "gatsby",
+		Stdout:     stdout,
+		Stderr:     &bytes.Buffer{},
+	})
+	assert.ErrorContains(t, err, `unknown framework "gatsby"`)
+}
+
+func TestRunGenerateGTM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.json")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	require.NoError(t, runGenerateGTM(generateGTMParams{
+		ConfigPath: writeSummaryConfig(t, generateTestConfig+"  - parameter: customer_tier\n    display_name: Customer Tier\n    scope: USER\n"),
+		Output:     path,
+		Stdout:     stdout,
+		Stderr:     stderr,
+		Now:        func() time.Time { return time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) },
+	}))
+	assert.Contains(t, stdout.String(), "2 tag(s), 1 trigger(s), 5 variable(s)")
+	assert.Contains(t, stderr.String(), "no analytics.measurement_id")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var export gtm.Export
+	require.NoError(t, json.Unmarshal(data, &export))
+	assert.Equal(t, "Example", export.ContainerVersion.Container.Name)
+	lead := export.ContainerVersion.Tag[1]
+	assert.Equal(t, "GA4 Event - generate_lead", lead.Name)
+	var keys []string
+	for _, p := range lead.Parameter {
+		keys = append(keys, p.Key)
+	}
+	assert.Equal(t, []string{"eventName", "measurementIdOverride", "eventSettingsTable", "userProperties"}, keys)
+	assert.Len(t, lead.Parameter[2].List, 3, "plan_type, value and currency")
+}
+
+func TestRunGenerateGTM_RequiresConversions(t *testing.T) {
+	err := runGenerateGTM(generateGTMParams{
+		ConfigPath: writeSummaryConfig(t, "project:\n  name: Example\nanalytics:\n  property_id: \"1\"\n"),
+		Stdout:     &bytes.Buffer{},
+		Stderr:     &bytes.Buffer{},
+		Now:        time.Now,
+	})
+	assert.ErrorContains(t, err, "has no conversions")
+}
+
+func TestRunGeneratePlan(t *testing.T) {
+	cfg := writeSummaryConfig(t, `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+    parameters: [value, currency, plan_type]
+    owner: checkout team
+dimensions:
+  - parameter: plan_type
+    display_name: Plan Type
+    scope: EVENT
+audiences:
+  - name: Buyers
+    description: Users with a purchase
+    duration: 30
+`)
+	stdout := &bytes.Buffer{}
+	require.NoError(t, runGeneratePlan(generatePlanParams{
+		ConfigPath: cfg,
+		Format:     "markdown",
+		Stdout:     stdout,
+		Now:        time.Now,
+	}))
+	assert.Contains(t, stdout.String(), "| purchase | — | ONCE_PER_EVENT | required | value, currency, plan_type | Buyers | — | checkout team |")
+
+	path := filepath.Join(t.TempDir(), "plan.html")
+	require.NoError(t, runGeneratePlan(generatePlanParams{
+		ConfigPath: cfg,
+		Format:     "html",
+		Output:     path,
+		Stdout:     stdout,
+		Now:        time.Now,
+	}))
+	html, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "<title>Measurement plan: Example</title>")
+}
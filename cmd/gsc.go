@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
 )
 
 var gscCmd = &cobra.Command{
@@ -18,17 +16,13 @@ Available operations:
 - Inspect URLs for indexing status
 - Generate search analytics reports
 
-Requires a verified site in Google Search Console and proper authentication.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Check for credentials
-		if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") == "" {
-			color.Red("✗ GOOGLE_APPLICATION_CREDENTIALS environment variable not set")
-			fmt.Println("\nPlease set the path to your service account credentials:")
-			fmt.Println("  export GOOGLE_APPLICATION_CREDENTIALS=/path/to/credentials.json")
-			fmt.Println("\nOr add it to your .env file:")
-			fmt.Println("  GOOGLE_APPLICATION_CREDENTIALS=/path/to/credentials.json")
-			os.Exit(1)
-		}
+Requires a verified site in Google Search Console and credentials: a service
+account key in GOOGLE_APPLICATION_CREDENTIALS, or ` + "`ga4 auth login`" + `.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The clients authenticate with whatever auth.Resolve picks: the
+		// service account key, or the `ga4 auth login` of the active profile.
+		_, err := auth.Resolve()
+		return err
 	},
 }
 
@@ -1,29 +1,44 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"google.golang.org/api/searchconsole/v1"
 
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/gsc"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/gsc/contentgroup"
+	"github.com/garbarok/ga4-manager/internal/looker"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/parquet"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
 	gscAnalyticsSite       string
 	gscAnalyticsConfig     string
 	gscAnalyticsDays       int
+	gscAnalyticsStartDate  string
+	gscAnalyticsEndDate    string
 	gscAnalyticsDimensions string
+	gscAnalyticsFilters    []string
 	gscAnalyticsFormat     string
 	gscAnalyticsDryRun     bool
 	gscAnalyticsRowLimit   int
+	gscAnalyticsByIntent   bool
+	gscAnalyticsGroupBy    string
+	gscAnalyticsAllRows    bool
+	gscAnalyticsOutput     string
+	gscAnalyticsManifest   string
+	gscAnalyticsCompress   string
 )
 
 var gscAnalyticsCmd = &cobra.Command{
@@ -43,7 +58,13 @@ Output Formats:
   - table (default): Color-coded table view in terminal
   - json: Machine-readable JSON output for automation
   - csv: CSV format for spreadsheet analysis
+  - ndjson: One JSON object per row, for streaming into other tools
   - markdown: Human-readable markdown report
+  - looker: CSV plus schema manifest for Looker Studio (see below)
+  - parquet: Typed columnar file for DuckDB, Spark or pandas (see below)
+
+  --output FILE writes the report (not table) to FILE instead of stdout.
+  When stdout carries csv, json, ndjson or looker, progress notes go to stderr.
 
 Data Availability:
   - Up to 16 months of historical data
@@ -62,6 +83,15 @@ Examples:
   # Report with specific dimensions
   ga4 gsc analytics run --site sc-domain:example.com --days 7 --dimensions query,page,country
 
+  # Blog pages only, from Spain
+  ga4 gsc analytics run --site sc-domain:example.com --filter "page contains /blog/" --filter "country equals esp"
+
+  # Questions, by regex
+  ga4 gsc analytics run --site sc-domain:example.com --filter "query includingRegex ^(how|what|why)\b"
+
+  # The week before a site migration
+  ga4 gsc analytics run --site sc-domain:example.com --start-date 2025-03-03 --end-date 2025-03-09
+
   # Generate from config file (recommended)
   ga4 gsc analytics run --config configs/mysite.yaml
 
@@ -71,16 +101,93 @@ Examples:
   # Export as JSON for automation
   ga4 gsc analytics run --config configs/mysite.yaml --format json
 
+  # Large export straight to a file, one JSON row per line
+  ga4 gsc analytics run --config configs/mysite.yaml --limit 100000 --format ndjson --output rows.ndjson
+
   # Dry-run to preview query
   ga4 gsc analytics run --config configs/mysite.yaml --dry-run
 
+  # Clicks and positions by query intent
+  ga4 gsc analytics run --config configs/mysite.yaml --by-intent
+
+  # Clicks and positions per site section (content_groups in the config)
+  ga4 gsc analytics run --config configs/mysite.yaml --group-by content_group
+
+  # Every row, however many, streamed to a CSV file as pages arrive
+  ga4 gsc analytics run --config configs/mysite.yaml --all-rows --format csv > all.csv
+
+  # Looker Studio export: report.csv plus its schema in report.schema.json
+  ga4 gsc analytics run --config configs/mysite.yaml --format looker --output report.csv
+
+  # Every row as a gzip-compressed Parquet file, for DuckDB
+  ga4 gsc analytics run --config configs/mysite.yaml --all-rows --format parquet --compression gzip -o rows.parquet
+
+  # Append every row to a BigQuery table, kept past the 16-month window
+  ga4 gsc analytics run --config configs/mysite.yaml --all-rows --output bq://my-project.seo.search_analytics
+
+  # Replace the rows of a Google Sheet tab with this month's report
+  ga4 gsc analytics run --config configs/mysite.yaml --output sheets://1AbC...xyz/Search --sheet-mode replace
+
 Valid Dimensions (max 3):
   - query: Search queries
   - page: Landing pages
   - country: Country codes (e.g., usa, gbr, fra)
   - device: Device types (desktop, mobile, tablet)
   - searchAppearance: How the result appeared (e.g., organic, news)
-  - date: Date for trend analysis`,
+  - date: Date for trend analysis
+
+All Rows (--all-rows):
+  --limit stops at 100,000 rows. --all-rows pages through 25,000-row pages
+  until Search Console returns an empty one and writes each page as it
+  arrives, so memory use stays flat. csv, ndjson, looker and parquet are
+  supported. Every page costs one query against the daily quota; the pages
+  used are reported on stderr.
+
+Looker Studio (--format looker):
+  A CSV with stable snake_case columns (query, page, start_date, clicks,
+  ctr…), dates as YYYYMMDD and metrics unformatted, plus a JSON manifest of
+  each column's Looker Studio type, concept and aggregation, written to
+  --manifest or next to --output. The columns only change with
+  --dimensions, so a Google Sheet or BigQuery table fed by scheduled runs
+  keeps its Looker Studio data source valid.
+
+BigQuery (--output bq://project.dataset.table):
+  Rows are appended to the table through a load job, with the Looker Studio
+  column names plus site and report_date (the last day of the range). The
+  table is created on the first run, its schema inferred from the rows and
+  partitioned by report_date; --format is ignored. Scheduled runs build up
+  history Search Console itself drops after 16 months. The credentials need
+  the BigQuery Data Editor and Job User roles on the project.
+
+Google Sheets (--output sheets://<spreadsheetId>/<tab>):
+  Rows, with the same columns as BigQuery, go to the tab of the spreadsheet
+  (the ID is in its URL; percent-encode spaces in the tab name). The tab is
+  created with a header row when missing. --sheet-mode append (default)
+  adds the rows below those already there and refuses a tab whose header
+  differs; --sheet-mode replace clears the tab first. Share the spreadsheet
+  with the service account, or log in again with ga4 auth login.
+
+Parquet (--format parquet):
+  A Parquet file with the columns of the BigQuery export, typed: clicks and
+  impressions int64, ctr and position double, date, report_date and
+  start_date DATE. Rows are written in row groups of 100,000, compressed
+  with --compression snappy (default), gzip or none. Needs --output.
+  It is one file, not a hive-style report_date=YYYY-MM-DD/ directory tree:
+  filter or partition on the report_date column, e.g. with DuckDB's
+  COPY ... TO 'dir' (FORMAT parquet, PARTITION_BY (report_date)).
+  For example: duckdb -c "SELECT query, SUM(clicks) FROM 'rows.parquet' GROUP BY 1"
+
+Query Intent (--by-intent):
+  Queries are classified as navigational, transactional, informational or
+  unclassified by keyword rules and the report is aggregated per intent.
+  Add brand names and site-specific keywords under
+  search_console.search_analytics.intent in the config.
+
+Content Groups (--group-by content_group):
+  Pages are grouped into the site sections listed under content_groups in
+  the config, by URL path prefix or regex, and the report is aggregated per
+  group. A page belongs to the first group that matches it; the rest are
+  reported as (other).`,
 }
 
 var gscAnalyticsRunCmd = &cobra.Command{
@@ -103,17 +210,47 @@ func init() {
 	// Days flag (default: 30 days)
 	gscAnalyticsRunCmd.Flags().IntVarP(&gscAnalyticsDays, "days", "d", 28, "Number of days to query (1-180); 28 aligns with the diagnostic-command default")
 
+	// Explicit date range, e.g. the weeks either side of a migration
+	addDateRangeFlags(gscAnalyticsRunCmd, &gscAnalyticsStartDate, &gscAnalyticsEndDate)
+
 	// Dimensions flag (default: query,page)
 	gscAnalyticsRunCmd.Flags().StringVar(&gscAnalyticsDimensions, "dimensions", "query,page", "Dimensions to include (comma-separated, max 3)")
 
+	// Filter flag, repeatable; every filter must match
+	gscAnalyticsRunCmd.Flags().StringArrayVar(&gscAnalyticsFilters, "filter", nil, `Dimension filter "DIMENSION OPERATOR EXPRESSION", e.g. "page contains /blog/" (repeatable; operators: equals, notEquals, contains, notContains, includingRegex, excludingRegex)`)
+
 	// Row limit flag. Values above 25000 are fetched by paginating with StartRow.
 	gscAnalyticsRunCmd.Flags().IntVarP(&gscAnalyticsRowLimit, "limit", "l", 1000, "Maximum rows to return (1-100000; auto-paginated in 25000-row pages)")
 
 	// Format flag (default: table)
-	gscAnalyticsRunCmd.Flags().StringVarP(&gscAnalyticsFormat, "format", "f", "table", "Output format: table, json, csv, or markdown")
+	output.FormatVar(gscAnalyticsRunCmd.Flags(), &gscAnalyticsFormat, "f", output.FormatTable, output.FormatJSON, output.FormatCSV, output.FormatNDJSON, output.FormatMarkdown, looker.Format, parquet.Format)
+
+	// Output flag: write the report to a file instead of stdout
+	gscAnalyticsRunCmd.Flags().StringVarP(&gscAnalyticsOutput, "output", "o", "", "Write the report to this file instead of stdout (csv, json, ndjson, markdown, looker or parquet), or append the rows to bq://project.dataset.table or sheets://<spreadsheetId>/<tab>")
+
+	// Manifest flag: the schema written alongside a Looker Studio export
+	gscAnalyticsRunCmd.Flags().StringVar(&gscAnalyticsCompress, "compression", parquet.CompressionSnappy, "With --format parquet, the compression: "+strings.Join(parquet.Compressions(), ", "))
+	gscAnalyticsRunCmd.Flags().StringVar(&gscAnalyticsManifest, "manifest", "", "With --format looker, write the schema manifest to this file (default: next to --output, as NAME.schema.json)")
 
 	// Dry-run flag
 	gscAnalyticsRunCmd.Flags().BoolVar(&gscAnalyticsDryRun, "dry-run", false, "Preview query without making API call")
+
+	// Intent flag: aggregate the query rows by intent
+	gscAnalyticsRunCmd.Flags().BoolVar(&gscAnalyticsByIntent, "by-intent", false, "Aggregate clicks and positions by query intent (needs the query dimension)")
+
+	// Group-by flag: aggregate the page rows by content group
+	gscAnalyticsRunCmd.Flags().StringVar(&gscAnalyticsGroupBy, "group-by", "", "Aggregate clicks and positions by content_group (needs the page dimension and content_groups in the config)")
+
+	// All-rows flag: page until the API runs out of rows, streaming the output
+	gscAnalyticsRunCmd.Flags().BoolVar(&gscAnalyticsAllRows, "all-rows", false, "Fetch every row, paging until the API returns none, and stream them (csv or ndjson)")
+
+	addSaveFlags(gscAnalyticsRunCmd)
+	addTableOutputFlags(gscAnalyticsRunCmd)
+	gscAnalyticsRunCmd.MarkFlagsMutuallyExclusive("all-rows", "limit")
+	gscAnalyticsRunCmd.MarkFlagsMutuallyExclusive("all-rows", "by-intent")
+	gscAnalyticsRunCmd.MarkFlagsMutuallyExclusive("all-rows", "group-by")
+	gscAnalyticsRunCmd.MarkFlagsMutuallyExclusive("by-intent", "group-by")
+	gscAnalyticsRunCmd.MarkFlagsMutuallyExclusive("all-rows", "save")
 }
 
 func runGSCAnalytics(cmd *cobra.Command, args []string) error {
@@ -126,19 +263,24 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 	days := gscAnalyticsDays
 	dimensions := strings.Split(gscAnalyticsDimensions, ",")
 	rowLimit := gscAnalyticsRowLimit
+	var filterConfigs []config.SearchFilterConfig
+	var intentConfig *config.QueryIntentConfig
+	var contentGroups []config.ContentGroupConfig
 
 	if gscAnalyticsConfig != "" {
 		cfg, err := config.LoadConfig(gscAnalyticsConfig)
 		if err != nil {
-			color.Red("✗ Failed to load config: %v", err)
+			theme.Red("✗ Failed to load config: %v", err)
 			return err
 		}
 
 		if cfg.SearchConsole == nil {
-			color.Red("✗ No search_console configuration found in %s", gscAnalyticsConfig)
+			theme.Red("✗ No search_console configuration found in %s", gscAnalyticsConfig)
 			return fmt.Errorf("missing search_console config")
 		}
 
+		contentGroups = cfg.ContentGroups
+
 		// Site comes from config unless --site was given explicitly.
 		if !cmd.Flags().Changed("site") {
 			siteURL = cfg.SearchConsole.SiteURL
@@ -151,11 +293,15 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 			if !cmd.Flags().Changed("dimensions") && len(sa.Dimensions) > 0 {
 				dimensions = sa.Dimensions
 			}
+			if !cmd.Flags().Changed("filter") {
+				filterConfigs = sa.Filters
+			}
 			// Row limit has no config field; the flag value (default or
 			// explicit) always applies.
+			intentConfig = sa.Intent
 		}
 	} else if siteURL == "" {
-		color.Red("✗ Either --site or --config must be provided")
+		theme.Red("✗ Either --site or --config must be provided")
 		return fmt.Errorf("missing site URL or config file")
 	}
 
@@ -166,12 +312,70 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 
 	// Validate inputs
 	if err := gsc.ValidateAnalyticsParams(siteURL, days, dimensions, rowLimit); err != nil {
-		color.Red("✗ Validation failed: %v", err)
+		theme.Red("✗ Validation failed: %v", err)
+		return err
+	}
+	if gscAnalyticsByIntent && !slices.Contains(dimensions, "query") {
+		return fmt.Errorf("--by-intent needs the query dimension (got %s)", strings.Join(dimensions, ","))
+	}
+	var grouper *contentgroup.Grouper
+	if gscAnalyticsGroupBy != "" {
+		if gscAnalyticsGroupBy != groupByContentGroup {
+			return fmt.Errorf("invalid --group-by %q (want %s)", gscAnalyticsGroupBy, groupByContentGroup)
+		}
+		if !slices.Contains(dimensions, "page") {
+			return fmt.Errorf("--group-by %s needs the page dimension (got %s)", groupByContentGroup, strings.Join(dimensions, ","))
+		}
+		g, err := contentGrouper(contentGroups)
+		if err != nil {
+			return err
+		}
+		grouper = g
+	}
+	if gscAnalyticsAllRows && !slices.Contains([]string{output.FormatCSV, output.FormatNDJSON, looker.Format, parquet.Format}, gscAnalyticsFormat) {
+		return fmt.Errorf("--all-rows streams csv, ndjson, looker or parquet, not %s", gscAnalyticsFormat)
+	}
+	if gscAnalyticsFormat == parquet.Format {
+		switch {
+		case gscAnalyticsByIntent || grouper != nil:
+			return fmt.Errorf("--format %s exports rows, not the --by-intent or --group-by summaries", parquet.Format)
+		case gscAnalyticsOutput == "":
+			return fmt.Errorf("--format %s is binary: give --output", parquet.Format)
+		case !slices.Contains(parquet.Compressions(), gscAnalyticsCompress):
+			return fmt.Errorf("invalid --compression %q (want %s)", gscAnalyticsCompress, strings.Join(parquet.Compressions(), ", "))
+		}
+	}
+	dest, err := parseTableDestination(gscAnalyticsOutput)
+	if err != nil {
 		return err
 	}
+	if dest != nil && (gscAnalyticsByIntent || grouper != nil) {
+		return fmt.Errorf("--output %s takes rows, not the --by-intent or --group-by summaries", dest)
+	}
+	var manifestPath string
+	if gscAnalyticsFormat == looker.Format {
+		if gscAnalyticsByIntent || grouper != nil {
+			return fmt.Errorf("--format %s exports rows, not the --by-intent or --group-by summaries", looker.Format)
+		}
+		path, err := analyticsLookerManifestPath(gscAnalyticsManifest, gscAnalyticsOutput)
+		if err != nil {
+			return err
+		}
+		manifestPath = path
+	}
 
 	// Build date range
-	startDate, endDate := gsc.BuildDateRange(days)
+	startDate, endDate, err := resolveDateRange(gscAnalyticsStartDate, gscAnalyticsEndDate, days)
+	if err != nil {
+		theme.Red("✗ Validation failed: %v", err)
+		return err
+	}
+
+	filters, err := analyticsFilters(gscAnalyticsFilters, filterConfigs)
+	if err != nil {
+		theme.Red("✗ Validation failed: %v", err)
+		return err
+	}
 
 	// Build query
 	query := &gsc.SearchAnalyticsQuery{
@@ -180,6 +384,7 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 		EndDate:    endDate,
 		Dimensions: dimensions,
 		RowLimit:   rowLimit,
+		Filters:    filters,
 		DataState:  "final",
 	}
 
@@ -190,37 +395,95 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
-	// Execute query
-	color.Cyan("📊 Querying search analytics for %s...", siteURL)
-	color.Cyan("📅 Date range: %s to %s (%d days)", startDate, endDate, days)
-	color.Cyan("📈 Dimensions: %s", strings.Join(dimensions, ", "))
-	fmt.Println()
+	if dest != nil {
+		return runAnalyticsTable(client, query, dest)
+	}
+
+	// Open the output before spending quota, so a bad --output path fails fast
+	out, closeOutput, err := openOutput(gscAnalyticsOutput, gscAnalyticsFormat)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closeOutput() }()
+
+	if gscAnalyticsAllRows {
+		if err := streamAnalytics(out, client, query, gscAnalyticsFormat); err != nil {
+			return err
+		}
+		if err := closeOutput(); err != nil {
+			return err
+		}
+		return writeAnalyticsLookerManifest(manifestPath, query, gscAnalyticsOutput)
+	}
+
+	// Execute query. Progress goes to stderr when stdout carries the export.
+	status := statusWriter(gscAnalyticsFormat, gscAnalyticsOutput)
+	theme.Fprintf(status, "%s\n", theme.CyanString("📊 Querying search analytics for %s...", siteURL))
+	theme.Fprintf(status, "%s\n", theme.CyanString("📅 Date range: %s to %s (%d days)", startDate, endDate, gsc.DateRangeDays(startDate, endDate)))
+	theme.Fprintf(status, "%s\n", theme.CyanString("📈 Dimensions: %s", strings.Join(dimensions, ", ")))
+	for _, f := range filters {
+		theme.Fprintf(status, "%s\n", theme.CyanString("🔎 Filter: %s", gsc.FormatFilter(f)))
+	}
+	theme.Fprintln(status)
 
 	report, err := client.QuerySearchAnalytics(query)
 	if err != nil {
-		color.Red("✗ Failed to query search analytics: %v", err)
+		theme.Red("✗ Failed to query search analytics: %v", err)
+		return err
+	}
+	if err := saveHistory(store.KindAnalytics, siteURL, analyticsRecords(report)); err != nil {
 		return err
 	}
 
+	if gscAnalyticsByIntent {
+		if err := displayAnalyticsByIntent(out, report, queryIntentClassifier(intentConfig)); err != nil {
+			return err
+		}
+		return closeOutput()
+	}
+	if grouper != nil {
+		if err := displayAnalyticsByContentGroup(out, report, grouper); err != nil {
+			return err
+		}
+		return closeOutput()
+	}
+
 	// Display results based on format
 	switch gscAnalyticsFormat {
 	case "json":
-		displayAnalyticsJSON(report)
-	case "csv":
-		displayAnalyticsCSV(report)
+		err = displayAnalyticsJSON(out, report)
+	case output.FormatCSV, output.FormatNDJSON:
+		err = writeAnalyticsRows(out, gscAnalyticsFormat, report.Metadata.Dimensions, report.Rows)
+	case looker.Format:
+		err = writeAnalyticsLooker(out, query, report.Rows)
+	case parquet.Format:
+		err = writeAnalyticsParquet(out, query, report.Rows, gscAnalyticsCompress)
 	case "markdown":
-		displayAnalyticsMarkdown(report)
+		err = displayAnalyticsMarkdown(out, report)
 	default:
-		if err := displayAnalyticsTable(report); err != nil {
-			return err
-		}
+		err = displayAnalyticsTable(report)
+	}
+	if err == nil {
+		err = closeOutput()
+	}
+	if err != nil {
+		return err
+	}
+	if gscAnalyticsOutput != "" {
+		theme.Fprintf(status, "✓ Wrote %d rows to %s\n", report.TotalRows, gscAnalyticsOutput)
+	}
+	if err := writeAnalyticsLookerManifest(manifestPath, query, gscAnalyticsOutput); err != nil {
+		return err
+	}
+	if manifestPath != "" {
+		theme.Fprintf(status, "✓ Wrote the Looker Studio schema to %s\n", manifestPath)
 	}
 
 	// Display summary and quota status
@@ -232,50 +495,98 @@ func runGSCAnalytics(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func displayAnalyticsDryRun(query *gsc.SearchAnalyticsQuery) {
-	color.Cyan("🔍 Dry-run mode - Preview of search analytics query")
-	fmt.Println()
+// analyticsStreamer is the part of *gsc.Client --all-rows needs.
+type analyticsStreamer interface {
+	StreamSearchAnalytics(query *gsc.SearchAnalyticsQuery, fn func(rows []gsc.SearchAnalyticsRow) error) (*gsc.SearchAnalyticsStats, error)
+}
+
+// streamAnalytics writes every row of query to w as the pages arrive, in
+// csv or ndjson. Rows already written stay written when a later page fails.
+// The totals and the quota the extra pages cost go to stderr, keeping w
+// machine-readable.
+func streamAnalytics(w io.Writer, client analyticsStreamer, query *gsc.SearchAnalyticsQuery, format string) error {
+	rw, err := analyticsRowWriter(w, format, query)
+	if err != nil {
+		return err
+	}
+	stats, err := client.StreamSearchAnalytics(query, func(rows []gsc.SearchAnalyticsRow) error {
+		return rw.Write(rows...)
+	})
+	if err == nil {
+		err = rw.Close()
+	}
+	if stats != nil {
+		theme.Fprintf(os.Stderr, "📄 %d rows in %d page(s), %d quota units; %d used today\n", stats.Rows, stats.Pages, stats.Pages, stats.QuotaUsed)
+	}
+	return err
+}
 
-	color.White("Site URL:     %s", query.SiteURL)
-	color.White("Date Range:   %s to %s", query.StartDate, query.EndDate)
-	color.White("Dimensions:   %s", strings.Join(query.Dimensions, ", "))
-	color.White("Row Limit:    %d", query.RowLimit)
-	color.White("Data State:   %s", query.DataState)
+func displayAnalyticsDryRun(query *gsc.SearchAnalyticsQuery) {
+	theme.Cyan("🔍 Dry-run mode - Preview of search analytics query")
+	theme.Println()
+
+	theme.White("Site URL:     %s", query.SiteURL)
+	theme.White("Date Range:   %s to %s", query.StartDate, query.EndDate)
+	theme.White("Dimensions:   %s", strings.Join(query.Dimensions, ", "))
+	if gscAnalyticsAllRows {
+		theme.White("Row Limit:    all (paged until empty, one quota unit per 25000 rows)")
+	} else {
+		theme.White("Row Limit:    %d", query.RowLimit)
+	}
+	theme.White("Data State:   %s", query.DataState)
 
 	if len(query.Filters) > 0 {
-		fmt.Println()
-		color.Yellow("Filters:")
+		theme.Println()
+		theme.Yellow("Filters:")
 		for i, filter := range query.Filters {
-			color.Yellow("  %d. %s %s '%s'", i+1, filter.Dimension, filter.Operator, filter.Expression)
+			theme.Yellow("  %d. %s %s '%s'", i+1, filter.Dimension, filter.Operator, filter.Expression)
 		}
 	}
 
-	fmt.Println()
-	color.Blue("ℹ️  No API call made. Remove --dry-run to execute query.")
+	theme.Println()
+	theme.Blue("ℹ️  No API call made. Remove --dry-run to execute query.")
+}
+
+// analyticsFilters parses the --filter values, or converts the config's
+// search_analytics.filters when none were given.
+func analyticsFilters(flags []string, configured []config.SearchFilterConfig) ([]*searchconsole.ApiDimensionFilter, error) {
+	if len(flags) == 0 {
+		return gsc.FiltersFromConfig(configured)
+	}
+	filters := make([]*searchconsole.ApiDimensionFilter, 0, len(flags))
+	for _, s := range flags {
+		f, err := gsc.ParseFilter(s)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
 }
 
 // analyticsColumns builds the column list from the report's dimensions plus
 // the four fixed metric columns. Title-casing the dimension names matches the
 // previous hand-rolled headers.
 func analyticsColumns(report *gsc.SearchAnalyticsReport) []string {
-	columns := make([]string, 0, len(report.Metadata.Dimensions)+4)
-	for _, dim := range report.Metadata.Dimensions {
+	return analyticsColumnsFor(report.Metadata.Dimensions)
+}
+
+func analyticsColumnsFor(dimensions []string) []string {
+	columns := make([]string, 0, len(dimensions)+4)
+	for _, dim := range dimensions {
 		columns = append(columns, cases.Title(language.English).String(dim))
 	}
 	return append(columns, "Clicks", "Impressions", "CTR", "Position")
 }
 
-// analyticsTableRow truncates long dimension values for terminal display and
-// uses one-decimal precision on CTR and position.
+// analyticsTableOptions caps long dimension values (URLs, queries) for
+// terminal display.
+var analyticsTableOptions = output.Options{MaxWidth: 60}
+
+// analyticsTableRow uses one-decimal precision on CTR and position.
 func analyticsTableRow(row gsc.SearchAnalyticsRow) []string {
 	cells := make([]string, 0, len(row.Keys)+4)
-	for _, k := range row.Keys {
-		if len(k) > 60 {
-			cells = append(cells, k[:57]+"...")
-		} else {
-			cells = append(cells, k)
-		}
-	}
+	cells = append(cells, row.Keys...)
 	return append(cells,
 		fmt.Sprintf("%d", row.Clicks),
 		fmt.Sprintf("%d", row.Impressions),
@@ -298,7 +609,7 @@ func analyticsCSVRow(row gsc.SearchAnalyticsRow) []string {
 }
 
 // analyticsMarkdownRow matches the table-mode precision; pipe escaping is
-// handled inside the render package.
+// handled inside the output package.
 func analyticsMarkdownRow(row gsc.SearchAnalyticsRow) []string {
 	cells := make([]string, 0, len(row.Keys)+4)
 	cells = append(cells, row.Keys...)
@@ -312,71 +623,93 @@ func analyticsMarkdownRow(row gsc.SearchAnalyticsRow) []string {
 
 func displayAnalyticsTable(report *gsc.SearchAnalyticsReport) error {
 	if report.TotalRows == 0 {
-		color.Yellow("⚠ No data found for this query")
+		theme.Yellow("⚠ No data found for this query")
 		return nil
 	}
-	return render.Render(os.Stdout, render.FormatTable, analyticsColumns(report), report.Rows, analyticsTableRow)
+	return output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, analyticsTableOptions, analyticsColumns(report), report.Rows, analyticsTableRow)
 }
 
-func displayAnalyticsJSON(report *gsc.SearchAnalyticsReport) {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	_ = encoder.Encode(report)
+func displayAnalyticsJSON(w io.Writer, report *gsc.SearchAnalyticsReport) error {
+	return output.JSON(w, report)
 }
 
-func displayAnalyticsCSV(report *gsc.SearchAnalyticsReport) {
-	_ = render.Render(os.Stdout, render.FormatCSV, analyticsColumns(report), report.Rows, analyticsCSVRow)
+// analyticsRowWriter returns the streaming writer of format: csv, ndjson,
+// looker or parquet (compressed as --compression says).
+func analyticsRowWriter(w io.Writer, format string, query *gsc.SearchAnalyticsQuery) (output.ReportWriter[gsc.SearchAnalyticsRow], error) {
+	switch format {
+	case looker.Format:
+		return newAnalyticsLookerWriter(w, query)
+	case parquet.Format:
+		return newAnalyticsParquetWriter(w, query, gscAnalyticsCompress)
+	}
+	return output.NewReportWriter(w, format, analyticsColumnsFor(query.Dimensions), analyticsCSVRow)
+}
+
+// writeAnalyticsRows writes rows through a streaming csv or ndjson writer,
+// so the export is never copied into a second in-memory representation.
+func writeAnalyticsRows(w io.Writer, format string, dimensions []string, rows []gsc.SearchAnalyticsRow) error {
+	rw, err := output.NewReportWriter(w, format, analyticsColumnsFor(dimensions), analyticsCSVRow)
+	if err != nil {
+		return err
+	}
+	if err := rw.Write(rows...); err != nil {
+		return err
+	}
+	return rw.Close()
 }
 
-func displayAnalyticsMarkdown(report *gsc.SearchAnalyticsReport) {
-	fmt.Println("# Search Analytics Report")
-	fmt.Println()
-	fmt.Printf("**Site:** %s  \n", report.SiteURL)
-	fmt.Printf("**Period:** %s  \n", report.Period)
-	fmt.Printf("**Dimensions:** %s  \n", strings.Join(report.Metadata.Dimensions, ", "))
-	fmt.Printf("**Generated:** %s  \n", report.Metadata.QueryDate.Format("2006-01-02 15:04:05"))
-	fmt.Println()
+func displayAnalyticsMarkdown(w io.Writer, report *gsc.SearchAnalyticsReport) error {
+	theme.Fprintln(w, "# Search Analytics Report")
+	theme.Fprintln(w)
+	theme.Fprintf(w, "**Site:** %s  \n", report.SiteURL)
+	theme.Fprintf(w, "**Period:** %s  \n", report.Period)
+	theme.Fprintf(w, "**Dimensions:** %s  \n", strings.Join(report.Metadata.Dimensions, ", "))
+	theme.Fprintf(w, "**Generated:** %s  \n", report.Metadata.QueryDate.Format("2006-01-02 15:04:05"))
+	theme.Fprintln(w)
 
 	if report.TotalRows == 0 {
-		fmt.Println("*No data found for this query*")
-		return
+		theme.Fprintln(w, "*No data found for this query*")
+		return nil
 	}
 
-	fmt.Println("## Summary")
-	fmt.Println()
-	fmt.Printf("- **Total Rows:** %d\n", report.TotalRows)
-	fmt.Printf("- **Total Clicks:** %d\n", report.Aggregates.TotalClicks)
-	fmt.Printf("- **Total Impressions:** %d\n", report.Aggregates.TotalImpressions)
-	fmt.Printf("- **Average CTR:** %.2f%%\n", report.Aggregates.AverageCTR*100)
-	fmt.Printf("- **Average Position:** %.1f\n", report.Aggregates.AveragePosition)
-	fmt.Println()
+	theme.Fprintln(w, "## Summary")
+	theme.Fprintln(w)
+	theme.Fprintf(w, "- **Total Rows:** %d\n", report.TotalRows)
+	theme.Fprintf(w, "- **Total Clicks:** %d\n", report.Aggregates.TotalClicks)
+	theme.Fprintf(w, "- **Total Impressions:** %d\n", report.Aggregates.TotalImpressions)
+	theme.Fprintf(w, "- **Average CTR:** %.2f%%\n", report.Aggregates.AverageCTR*100)
+	theme.Fprintf(w, "- **Average Position:** %.1f\n", report.Aggregates.AveragePosition)
+	theme.Fprintln(w)
 
-	fmt.Println("## Results")
-	fmt.Println()
+	theme.Fprintln(w, "## Results")
+	theme.Fprintln(w)
 
 	// Limit to top 50 rows for markdown readability.
 	rows := report.Rows
 	if len(rows) > 50 {
 		rows = rows[:50]
 	}
-	_ = render.Render(os.Stdout, render.FormatMarkdown, analyticsColumns(report), rows, analyticsMarkdownRow)
+	if err := output.Render(w, output.FormatMarkdown, analyticsColumns(report), rows, analyticsMarkdownRow); err != nil {
+		return err
+	}
 
 	if report.TotalRows > 50 {
-		fmt.Println()
-		fmt.Printf("*Showing top 50 of %d total rows*\n", report.TotalRows)
+		theme.Fprintln(w)
+		theme.Fprintf(w, "*Showing top 50 of %d total rows*\n", report.TotalRows)
 	}
+	return nil
 }
 
 func displayAnalyticsSummary(report *gsc.SearchAnalyticsReport) {
-	fmt.Println()
-	color.Cyan("═══ Report Summary ═══")
-	fmt.Printf("Period:         %s\n", report.Period)
-	fmt.Printf("Total Rows:     %d\n", report.TotalRows)
-	fmt.Printf("Total Clicks:   %s\n", color.GreenString("%d", report.Aggregates.TotalClicks))
-	fmt.Printf("Total Impressions: %s\n", color.BlueString("%d", report.Aggregates.TotalImpressions))
-	fmt.Printf("Average CTR:    %s\n", color.YellowString("%.2f%%", report.Aggregates.AverageCTR*100))
-	fmt.Printf("Avg Position:   %s\n", formatPosition(report.Aggregates.AveragePosition))
-	fmt.Println()
+	theme.Println()
+	theme.Cyan("═══ Report Summary ═══")
+	theme.Printf("Period:         %s\n", report.Period)
+	theme.Printf("Total Rows:     %d\n", report.TotalRows)
+	theme.Printf("Total Clicks:   %s\n", theme.GreenString("%d", report.Aggregates.TotalClicks))
+	theme.Printf("Total Impressions: %s\n", theme.BlueString("%d", report.Aggregates.TotalImpressions))
+	theme.Printf("Average CTR:    %s\n", theme.YellowString("%.2f%%", report.Aggregates.AverageCTR*100))
+	theme.Printf("Avg Position:   %s\n", formatPosition(report.Aggregates.AveragePosition))
+	theme.Println()
 }
 
 func displayAnalyticsQuotaStatus(client *gsc.Client) {
@@ -384,28 +717,28 @@ func displayAnalyticsQuotaStatus(client *gsc.Client) {
 	percentage := float64(used) / float64(limit) * 100
 	remaining := limit - used
 
-	color.Cyan("═══ Daily Quota Status ═══")
-	fmt.Printf("Date:           %s\n", date)
-	fmt.Printf("Queries Used:   %d / %d (%.1f%%)\n", used, limit, percentage)
-	fmt.Printf("Remaining:      %d\n", remaining)
-	fmt.Println()
+	theme.Cyan("═══ Daily Quota Status ═══")
+	theme.Printf("Date:           %s\n", date)
+	theme.Printf("Queries Used:   %d / %d (%.1f%%)\n", used, limit, percentage)
+	theme.Printf("Remaining:      %d\n", remaining)
+	theme.Println()
 
 	if percentage >= 95.0 {
-		color.Red("🛑 Critical: Approaching daily quota limit!")
+		theme.Red("🛑 Critical: Approaching daily quota limit!")
 	} else if percentage >= 75.0 {
-		color.Yellow("⚠️  Warning: %.0f%% of daily quota used", percentage)
+		theme.Yellow("⚠️  Warning: %.0f%% of daily quota used", percentage)
 	} else {
-		color.Green("✓ Quota usage healthy")
+		theme.Green("✓ Quota usage healthy")
 	}
-	fmt.Println()
+	theme.Println()
 }
 
 func formatPosition(pos float64) string {
 	// Color-code position (1-3 = green, 4-10 = yellow, 10+ = red)
 	if pos <= 3.0 {
-		return color.GreenString("%.1f", pos)
+		return theme.GreenString("%.1f", pos)
 	} else if pos <= 10.0 {
-		return color.YellowString("%.1f", pos)
+		return theme.YellowString("%.1f", pos)
 	}
-	return color.RedString("%.1f", pos)
+	return theme.RedString("%.1f", pos)
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/contentgroup"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// groupByContentGroup is the one --group-by value.
+const groupByContentGroup = "content_group"
+
+// contentGroupReport is the --group-by content_group output.
+type contentGroupReport struct {
+	Site   string                 `json:"site"`
+	Period string                 `json:"period"`
+	Groups []contentgroup.Summary `json:"groups"`
+}
+
+var contentGroupColumns = []string{"Group", "Pages", "Clicks", "Click Share", "Impressions", "CTR", "Position"}
+
+// contentGrouper builds the grouper from the config's content_groups.
+func contentGrouper(groups []config.ContentGroupConfig) (*contentgroup.Grouper, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("--group-by %s needs content_groups in the config", groupByContentGroup)
+	}
+	rules := make([]contentgroup.Rule, len(groups))
+	for i, g := range groups {
+		rules[i] = contentgroup.Rule{Name: g.Name, Prefix: g.Prefix, Pattern: g.Pattern}
+	}
+	return contentgroup.New(rules)
+}
+
+// contentGroupRows folds the report into one row per page, summing clicks
+// and impressions across the other dimensions and weighting position by
+// impressions.
+func contentGroupRows(report *gsc.SearchAnalyticsReport) []contentgroup.Row {
+	col := slices.Index(report.Metadata.Dimensions, "page")
+	if col < 0 {
+		return nil
+	}
+	index := make(map[string]int)
+	var rows []contentgroup.Row
+	var weighted []float64
+	for _, r := range report.Rows {
+		if col >= len(r.Keys) {
+			continue
+		}
+		p := r.Keys[col]
+		i, ok := index[p]
+		if !ok {
+			i = len(rows)
+			index[p] = i
+			rows = append(rows, contentgroup.Row{Page: p})
+			weighted = append(weighted, 0)
+		}
+		rows[i].Clicks += r.Clicks
+		rows[i].Impressions += r.Impressions
+		weighted[i] += r.Position * float64(r.Impressions)
+	}
+	for i := range rows {
+		if rows[i].Impressions > 0 {
+			rows[i].Position = weighted[i] / float64(rows[i].Impressions)
+		}
+	}
+	return rows
+}
+
+func displayAnalyticsByContentGroup(w io.Writer, report *gsc.SearchAnalyticsReport, g *contentgroup.Grouper) error {
+	r := contentGroupReport{Site: report.SiteURL, Period: report.Period, Groups: g.Summarise(contentGroupRows(report))}
+
+	switch gscAnalyticsFormat {
+	case "json":
+		return output.JSON(w, r)
+	case output.FormatCSV, output.FormatNDJSON:
+		rw, err := output.NewReportWriter(w, gscAnalyticsFormat, contentGroupColumns, func(s contentgroup.Summary) []string {
+			return []string{s.Group, fmt.Sprintf("%d", s.Pages), fmt.Sprintf("%d", s.Clicks), fmt.Sprintf("%.6f", s.ClickShare),
+				fmt.Sprintf("%d", s.Impressions), fmt.Sprintf("%.6f", s.CTR), fmt.Sprintf("%.2f", s.Position)}
+		})
+		if err != nil {
+			return err
+		}
+		if err := rw.Write(r.Groups...); err != nil {
+			return err
+		}
+		return rw.Close()
+	}
+
+	row := func(s contentgroup.Summary) []string {
+		return []string{s.Group, fmt.Sprintf("%d", s.Pages), fmt.Sprintf("%d", s.Clicks), fmt.Sprintf("%.1f%%", s.ClickShare*100),
+			fmt.Sprintf("%d", s.Impressions), fmt.Sprintf("%.1f%%", s.CTR*100), fmt.Sprintf("%.1f", s.Position)}
+	}
+	if gscAnalyticsFormat == "markdown" {
+		theme.Fprintln(w, "# Search Analytics by Content Group")
+		theme.Fprintln(w)
+		theme.Fprintf(w, "**Site:** %s  \n", r.Site)
+		theme.Fprintf(w, "**Period:** %s  \n", r.Period)
+		theme.Fprintln(w)
+		return output.Render(w, output.FormatMarkdown, contentGroupColumns, r.Groups, row)
+	}
+
+	if len(r.Groups) == 0 {
+		theme.Yellow("⚠ No data found for this query")
+		return nil
+	}
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, contentGroupColumns, r.Groups, row); err != nil {
+		return err
+	}
+	displayAnalyticsSummary(report)
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestContentGroupRows(t *testing.T) {
+	report := &gsc.SearchAnalyticsReport{
+		Metadata: gsc.ReportMetadata{Dimensions: []string{"query", "page"}},
+		Rows: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"tent", "/blog/a"}, Clicks: 4, Impressions: 100, Position: 2},
+			{Keys: []string{"tarp", "/blog/a"}, Clicks: 1, Impressions: 300, Position: 6},
+			{Keys: []string{"tent", "/tools/b"}, Clicks: 0, Impressions: 10, Position: 30},
+		},
+	}
+	rows := contentGroupRows(report)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "/blog/a", rows[0].Page)
+	assert.Equal(t, int64(5), rows[0].Clicks)
+	assert.Equal(t, int64(400), rows[0].Impressions)
+	assert.InDelta(t, 5, rows[0].Position, 1e-9)
+
+	report.Metadata.Dimensions = []string{"query"}
+	assert.Nil(t, contentGroupRows(report))
+}
+
+func TestContentGrouper(t *testing.T) {
+	_, err := contentGrouper(nil)
+	assert.ErrorContains(t, err, "needs content_groups in the config")
+
+	g, err := contentGrouper([]config.ContentGroupConfig{{Name: "Blog", Prefix: "/blog/"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Blog", g.Group("https://example.com/blog/a"))
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/intent"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// intentReport is the --by-intent output.
+type intentReport struct {
+	Site    string           `json:"site"`
+	Period  string           `json:"period"`
+	Intents []intent.Summary `json:"intents"`
+}
+
+var intentColumns = []string{"Intent", "Queries", "Clicks", "Click Share", "Impressions", "CTR", "Position"}
+
+// queryIntentClassifier builds the classifier from the built-in keyword
+// lists plus the config's search_analytics.intent block.
+func queryIntentClassifier(cfg *config.QueryIntentConfig) *intent.Classifier {
+	if cfg == nil {
+		return intent.New(intent.DefaultRules())
+	}
+	rules := intent.Rules{}
+	if !cfg.NoDefaults {
+		rules = intent.DefaultRules()
+	}
+	return intent.New(rules.Merge(intent.Rules{
+		Navigational:  cfg.Navigational,
+		Transactional: cfg.Transactional,
+		Informational: cfg.Informational,
+	}))
+}
+
+// intentRows folds the report into one row per query, summing clicks and
+// impressions across the other dimensions and weighting position by
+// impressions.
+func intentRows(report *gsc.SearchAnalyticsReport) []intent.Row {
+	col := slices.Index(report.Metadata.Dimensions, "query")
+	if col < 0 {
+		return nil
+	}
+	index := make(map[string]int)
+	var rows []intent.Row
+	var weighted []float64
+	for _, r := range report.Rows {
+		if col >= len(r.Keys) {
+			continue
+		}
+		q := r.Keys[col]
+		i, ok := index[q]
+		if !ok {
+			i = len(rows)
+			index[q] = i
+			rows = append(rows, intent.Row{Query: q})
+			weighted = append(weighted, 0)
+		}
+		rows[i].Clicks += r.Clicks
+		rows[i].Impressions += r.Impressions
+		weighted[i] += r.Position * float64(r.Impressions)
+	}
+	for i := range rows {
+		if rows[i].Impressions > 0 {
+			rows[i].Position = weighted[i] / float64(rows[i].Impressions)
+		}
+	}
+	return rows
+}
+
+func displayAnalyticsByIntent(w io.Writer, report *gsc.SearchAnalyticsReport, c *intent.Classifier) error {
+	r := intentReport{Site: report.SiteURL, Period: report.Period, Intents: c.Summarise(intentRows(report))}
+
+	switch gscAnalyticsFormat {
+	case "json":
+		return output.JSON(w, r)
+	case output.FormatCSV, output.FormatNDJSON:
+		rw, err := output.NewReportWriter(w, gscAnalyticsFormat, intentColumns, func(s intent.Summary) []string {
+			return []string{s.Intent, fmt.Sprintf("%d", s.Queries), fmt.Sprintf("%d", s.Clicks), fmt.Sprintf("%.6f", s.ClickShare),
+				fmt.Sprintf("%d", s.Impressions), fmt.Sprintf("%.6f", s.CTR), fmt.Sprintf("%.2f", s.Position)}
+		})
+		if err != nil {
+			return err
+		}
+		if err := rw.Write(r.Intents...); err != nil {
+			return err
+		}
+		return rw.Close()
+	}
+
+	row := func(s intent.Summary) []string {
+		return []string{s.Intent, fmt.Sprintf("%d", s.Queries), fmt.Sprintf("%d", s.Clicks), fmt.Sprintf("%.1f%%", s.ClickShare*100),
+			fmt.Sprintf("%d", s.Impressions), fmt.Sprintf("%.1f%%", s.CTR*100), fmt.Sprintf("%.1f", s.Position)}
+	}
+	if gscAnalyticsFormat == "markdown" {
+		theme.Fprintln(w, "# Search Analytics by Query Intent")
+		theme.Fprintln(w)
+		theme.Fprintf(w, "**Site:** %s  \n", r.Site)
+		theme.Fprintf(w, "**Period:** %s  \n", r.Period)
+		theme.Fprintln(w)
+		return output.Render(w, output.FormatMarkdown, intentColumns, r.Intents, row)
+	}
+
+	if len(r.Intents) == 0 {
+		theme.Yellow("⚠ No data found for this query")
+		return nil
+	}
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, intentColumns, r.Intents, row); err != nil {
+		return err
+	}
+	displayAnalyticsSummary(report)
+	return nil
+}
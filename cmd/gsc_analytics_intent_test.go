@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/intent"
+)
+
+func TestIntentRows(t *testing.T) {
+	report := &gsc.SearchAnalyticsReport{
+		Metadata: gsc.ReportMetadata{Dimensions: []string{"page", "query"}},
+		Rows: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"/a", "tent"}, Clicks: 4, Impressions: 100, Position: 2},
+			{Keys: []string{"/b", "tent"}, Clicks: 1, Impressions: 300, Position: 6},
+			{Keys: []string{"/a", "tarp"}, Clicks: 0, Impressions: 10, Position: 30},
+		},
+	}
+	rows := intentRows(report)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "tent", rows[0].Query)
+	assert.Equal(t, int64(5), rows[0].Clicks)
+	assert.Equal(t, int64(400), rows[0].Impressions)
+	assert.InDelta(t, 5, rows[0].Position, 1e-9)
+
+	report.Metadata.Dimensions = []string{"page"}
+	assert.Nil(t, intentRows(report))
+}
+
+func TestQueryIntentClassifier(t *testing.T) {
+	assert.Equal(t, intent.Informational, queryIntentClassifier(nil).Classify("how to pitch a tent"))
+
+	c := queryIntentClassifier(&config.QueryIntentConfig{Navigational: []string{"acme"}})
+	assert.Equal(t, intent.Navigational, c.Classify("acme tents"))
+	assert.Equal(t, intent.Transactional, c.Classify("buy tent"))
+
+	c = queryIntentClassifier(&config.QueryIntentConfig{Transactional: []string{"rent*"}, NoDefaults: true})
+	assert.Equal(t, intent.Transactional, c.Classify("tent rental"))
+	assert.Equal(t, intent.Unclassified, c.Classify("how to pitch a tent"))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/looker"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+// analyticsLookerSource names Search Analytics exports in the manifest.
+const analyticsLookerSource = "search_console.search_analytics"
+
+// analyticsLookerDimensions maps Search Console dimensions to their Looker
+// Studio fields. The names never change with the report's display headers.
+var analyticsLookerDimensions = map[string]looker.Field{
+	"query":            {Name: "query", Label: "Query", Type: looker.TypeText},
+	"page":             {Name: "page", Label: "Landing page", Type: looker.TypeURL},
+	"country":          {Name: "country", Label: "Country", Type: looker.TypeText, Description: "ISO 3166-1 alpha-3 code, lower case"},
+	"device":           {Name: "device", Label: "Device", Type: looker.TypeText},
+	"searchAppearance": {Name: "search_appearance", Label: "Search appearance", Type: looker.TypeText},
+	"date":             {Name: "date", Label: "Date", Type: looker.TypeDate},
+}
+
+// analyticsLookerFields is the schema of a Search Analytics export over
+// dimensions: the dimensions, the report's date range (so scheduled runs
+// appended to one sheet or table stay apart) and the four metrics.
+func analyticsLookerFields(dimensions []string) ([]looker.Field, error) {
+	fields := make([]looker.Field, 0, len(dimensions)+6)
+	for _, d := range dimensions {
+		f, ok := analyticsLookerDimensions[d]
+		if !ok {
+			return nil, fmt.Errorf("no Looker Studio field for dimension %q", d)
+		}
+		f.Concept = looker.ConceptDimension
+		fields = append(fields, f)
+	}
+	return append(fields,
+		looker.Field{Name: "start_date", Label: "Report start date", Type: looker.TypeDate, Concept: looker.ConceptDimension},
+		looker.Field{Name: "end_date", Label: "Report end date", Type: looker.TypeDate, Concept: looker.ConceptDimension},
+		looker.Field{Name: "clicks", Label: "Clicks", Type: looker.TypeNumber, Concept: looker.ConceptMetric, Aggregation: looker.AggregationSum},
+		looker.Field{Name: "impressions", Label: "Impressions", Type: looker.TypeNumber, Concept: looker.ConceptMetric, Aggregation: looker.AggregationSum},
+		looker.Field{Name: "ctr", Label: "CTR", Type: looker.TypePercent, Concept: looker.ConceptMetric, Aggregation: looker.AggregationAvg,
+			Description: "Row CTR as a ratio; SUM(clicks)/SUM(impressions) gives the weighted CTR of a selection"},
+		looker.Field{Name: "position", Label: "Average position", Type: looker.TypeNumber, Concept: looker.ConceptMetric, Aggregation: looker.AggregationAvg},
+	), nil
+}
+
+// analyticsLookerManifest describes the export of query written to dataPath
+// ("" for stdout).
+func analyticsLookerManifest(query *gsc.SearchAnalyticsQuery, dataPath string, now time.Time) (looker.Manifest, error) {
+	fields, err := analyticsLookerFields(query.Dimensions)
+	if err != nil {
+		return looker.Manifest{}, err
+	}
+	m := looker.Manifest{
+		Source:      analyticsLookerSource,
+		Site:        query.SiteURL,
+		StartDate:   query.StartDate,
+		EndDate:     query.EndDate,
+		GeneratedAt: now.UTC(),
+		Fields:      fields,
+	}
+	if dataPath != "" {
+		m.Data = filepath.Base(dataPath)
+	}
+	return m, nil
+}
+
+// analyticsLookerManifestPath is where --format looker writes its manifest:
+// --manifest, else next to --output.
+func analyticsLookerManifestPath(manifest, outputPath string) (string, error) {
+	switch {
+	case manifest != "":
+		return manifest, nil
+	case outputPath != "":
+		return looker.ManifestPath(outputPath), nil
+	default:
+		return "", fmt.Errorf("--format %s writes a schema manifest: give --manifest or --output", looker.Format)
+	}
+}
+
+// newAnalyticsLookerWriter streams rows of query as Looker Studio CSV: dates
+// as YYYYMMDD, metrics at full precision.
+func newAnalyticsLookerWriter(w io.Writer, query *gsc.SearchAnalyticsQuery) (output.ReportWriter[gsc.SearchAnalyticsRow], error) {
+	fields, err := analyticsLookerFields(query.Dimensions)
+	if err != nil {
+		return nil, err
+	}
+	columns := looker.Manifest{Fields: fields}.Columns()
+	start, end := looker.Date(query.StartDate), looker.Date(query.EndDate)
+	return output.NewReportWriter(w, output.FormatCSV, columns, func(row gsc.SearchAnalyticsRow) []string {
+		cells := make([]string, 0, len(columns))
+		for i, key := range row.Keys {
+			if i < len(query.Dimensions) && query.Dimensions[i] == "date" {
+				key = looker.Date(key)
+			}
+			cells = append(cells, key)
+		}
+		cells = append(cells, start, end)
+		return append(cells, analyticsCSVRow(gsc.SearchAnalyticsRow{
+			Clicks: row.Clicks, Impressions: row.Impressions, CTR: row.CTR, Position: row.Position,
+		})...)
+	})
+}
+
+// writeAnalyticsLooker writes rows of query as Looker Studio CSV.
+func writeAnalyticsLooker(w io.Writer, query *gsc.SearchAnalyticsQuery, rows []gsc.SearchAnalyticsRow) error {
+	rw, err := newAnalyticsLookerWriter(w, query)
+	if err != nil {
+		return err
+	}
+	if err := rw.Write(rows...); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// writeAnalyticsLookerManifest writes to path the manifest of the export of
+// query written to dataPath. An empty path (not a looker export) writes
+// nothing.
+func writeAnalyticsLookerManifest(path string, query *gsc.SearchAnalyticsQuery, dataPath string) error {
+	if path == "" {
+		return nil
+	}
+	m, err := analyticsLookerManifest(query, dataPath, time.Now())
+	if err != nil {
+		return err
+	}
+	return looker.WriteManifest(path, m)
+}
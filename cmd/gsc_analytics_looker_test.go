@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/looker"
+)
+
+func TestStreamAnalytics_Looker(t *testing.T) {
+	var out bytes.Buffer
+	row := gsc.SearchAnalyticsRow{Keys: []string{"2026-10-09", "https://example.com/"}, Clicks: 3, Impressions: 40, CTR: 0.075, Position: 4.25}
+	fake := &fakeStreamer{pages: [][]gsc.SearchAnalyticsRow{{row}}, out: &out}
+	query := &gsc.SearchAnalyticsQuery{Dimensions: []string{"date", "page"}, StartDate: "2026-10-01", EndDate: "2026-10-14"}
+
+	require.NoError(t, streamAnalytics(&out, fake, query, looker.Format))
+	assert.Equal(t,
+		"date,page,start_date,end_date,clicks,impressions,ctr,position\n"+
+			"20261009,https://example.com/,20261001,20261014,3,40,0.075000,4.25\n",
+		out.String())
+}
+
+func TestAnalyticsLookerFields_MatchCSVColumns(t *testing.T) {
+	fields, err := analyticsLookerFields([]string{"query", "searchAppearance"})
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"query", "search_appearance", "start_date", "end_date", "clicks", "impressions", "ctr", "position"},
+		looker.Manifest{Fields: fields}.Columns())
+	assert.Equal(t, looker.ConceptDimension, fields[1].Concept)
+	assert.Equal(t, looker.TypePercent, fields[6].Type)
+	assert.Equal(t, looker.AggregationSum, fields[4].Aggregation)
+
+	_, err = analyticsLookerFields([]string{"hour"})
+	assert.ErrorContains(t, err, `dimension "hour"`)
+}
+
+func TestWriteAnalyticsLookerManifest(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "report.csv")
+	path, err := analyticsLookerManifestPath("", data)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "report.schema.json"), path)
+
+	query := &gsc.SearchAnalyticsQuery{SiteURL: "sc-domain:example.com", Dimensions: []string{"query"}, StartDate: "2026-10-01", EndDate: "2026-10-14"}
+	require.NoError(t, writeAnalyticsLookerManifest(path, query, data))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var m looker.Manifest
+	require.NoError(t, json.Unmarshal(raw, &m))
+	assert.Equal(t, looker.SchemaVersion, m.SchemaVersion)
+	assert.Equal(t, analyticsLookerSource, m.Source)
+	assert.Equal(t, "report.csv", m.Data)
+	assert.Equal(t, "2026-10-01", m.StartDate)
+	assert.Len(t, m.Fields, 7)
+
+	_, err = analyticsLookerManifestPath("", "")
+	assert.ErrorContains(t, err, "--manifest")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/parquet"
+)
+
+// analyticsParquetColumns is the schema of a Search Analytics Parquet export
+// over dimensions. The columns are those of the BigQuery and Sheets
+// destinations, typed: dates as DATE, clicks and impressions as int64, CTR
+// and position as double.
+func analyticsParquetColumns(dimensions []string) ([]parquet.Column, error) {
+	columns := make([]parquet.Column, 0, len(dimensions)+7)
+	for _, d := range dimensions {
+		f, ok := analyticsLookerDimensions[d]
+		if !ok {
+			return nil, fmt.Errorf("no Parquet column for dimension %q", d)
+		}
+		typ := parquet.String
+		if d == "date" {
+			typ = parquet.Date
+		}
+		columns = append(columns, parquet.Column{Name: f.Name, Type: typ})
+	}
+	return append(columns,
+		parquet.Column{Name: "site", Type: parquet.String},
+		parquet.Column{Name: reportDateColumn, Type: parquet.Date},
+		parquet.Column{Name: "start_date", Type: parquet.Date},
+		parquet.Column{Name: "clicks", Type: parquet.Int64},
+		parquet.Column{Name: "impressions", Type: parquet.Int64},
+		parquet.Column{Name: "ctr", Type: parquet.Double},
+		parquet.Column{Name: "position", Type: parquet.Double},
+	), nil
+}
+
+// analyticsParquetWriter streams rows of query into a Parquet file.
+type analyticsParquetWriter struct {
+	pw    *parquet.Writer
+	query *gsc.SearchAnalyticsQuery
+}
+
+// newAnalyticsParquetWriter starts a Parquet export of query to w,
+// compressed with compression.
+func newAnalyticsParquetWriter(w io.Writer, query *gsc.SearchAnalyticsQuery, compression string) (output.ReportWriter[gsc.SearchAnalyticsRow], error) {
+	columns, err := analyticsParquetColumns(query.Dimensions)
+	if err != nil {
+		return nil, err
+	}
+	pw, err := parquet.NewWriter(w, columns, compression)
+	if err != nil {
+		return nil, err
+	}
+	return &analyticsParquetWriter{pw: pw, query: query}, nil
+}
+
+func (w *analyticsParquetWriter) Write(rows ...gsc.SearchAnalyticsRow) error {
+	t, err := analyticsTableRows(w.query, rows)
+	if err != nil {
+		return err
+	}
+	return w.pw.Write(t.Values...)
+}
+
+func (w *analyticsParquetWriter) Close() error {
+	return w.pw.Close()
+}
+
+// writeAnalyticsParquet writes rows of query as a Parquet file.
+func writeAnalyticsParquet(w io.Writer, query *gsc.SearchAnalyticsQuery, rows []gsc.SearchAnalyticsRow, compression string) error {
+	pw, err := newAnalyticsParquetWriter(w, query, compression)
+	if err != nil {
+		return err
+	}
+	if err := pw.Write(rows...); err != nil {
+		return err
+	}
+	return pw.Close()
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/parquet"
+)
+
+func TestAnalyticsParquetColumns(t *testing.T) {
+	columns, err := analyticsParquetColumns([]string{"date", "page"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []parquet.Column{
+		{Name: "date", Type: parquet.Date},
+		{Name: "page", Type: parquet.String},
+		{Name: "site", Type: parquet.String},
+		{Name: "report_date", Type: parquet.Date},
+		{Name: "start_date", Type: parquet.Date},
+		{Name: "clicks", Type: parquet.Int64},
+		{Name: "impressions", Type: parquet.Int64},
+		{Name: "ctr", Type: parquet.Double},
+		{Name: "position", Type: parquet.Double},
+	}, columns)
+
+	_, err = analyticsParquetColumns([]string{"hour"})
+	assert.Error(t, err)
+}
+
+func TestWriteAnalyticsParquet(t *testing.T) {
+	query := &gsc.SearchAnalyticsQuery{
+		SiteURL: "sc-domain:example.com", StartDate: "2026-09-01", EndDate: "2026-09-28",
+		Dimensions: []string{"date", "query"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeAnalyticsParquet(&buf, query, []gsc.SearchAnalyticsRow{
+		{Keys: []string{"2026-09-02", "compress png"}, Clicks: 3, Impressions: 40, CTR: 0.075, Position: 4.5},
+	}, parquet.CompressionSnappy))
+
+	data := buf.Bytes()
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	assert.Contains(t, string(data), "report_date")
+}
+
+func TestStreamAnalytics_Parquet(t *testing.T) {
+	out := &bytes.Buffer{}
+	streamer := &fakeStreamer{pages: [][]gsc.SearchAnalyticsRow{{streamRow("a", 1)}, {streamRow("b", 2)}}, out: out}
+	query := &gsc.SearchAnalyticsQuery{SiteURL: "sc-domain:example.com", StartDate: "2026-09-01", EndDate: "2026-09-28", Dimensions: []string{"query"}}
+
+	require.NoError(t, streamAnalytics(out, streamer, query, parquet.Format))
+	assert.Equal(t, "PAR1", string(out.Bytes()[:4]))
+	assert.Equal(t, "PAR1", string(out.Bytes()[out.Len()-4:]))
+}
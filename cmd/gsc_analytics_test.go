@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+// fakeStreamer hands out pages of rows, failing after them when err is set.
+// It records what the writer had received when each later page was due.
+type fakeStreamer struct {
+	pages   [][]gsc.SearchAnalyticsRow
+	err     error
+	out     *bytes.Buffer
+	written []string
+}
+
+func (f *fakeStreamer) StreamSearchAnalytics(_ *gsc.SearchAnalyticsQuery, fn func([]gsc.SearchAnalyticsRow) error) (*gsc.SearchAnalyticsStats, error) {
+	stats := &gsc.SearchAnalyticsStats{}
+	for _, page := range f.pages {
+		f.written = append(f.written, f.out.String())
+		stats.Pages++
+		stats.Rows += len(page)
+		if err := fn(page); err != nil {
+			return stats, err
+		}
+	}
+	return stats, f.err
+}
+
+func streamRow(query string, clicks int64) gsc.SearchAnalyticsRow {
+	return gsc.SearchAnalyticsRow{Keys: []string{query}, Clicks: clicks, Impressions: 10, CTR: 0.1, Position: 3}
+}
+
+func TestStreamAnalytics_CSVWritesEachPageAsItArrives(t *testing.T) {
+	var out bytes.Buffer
+	fake := &fakeStreamer{
+		pages: [][]gsc.SearchAnalyticsRow{{streamRow("a", 1)}, {streamRow("b", 2)}},
+		err:   errors.New("quota exceeded"),
+		out:   &out,
+	}
+	query := &gsc.SearchAnalyticsQuery{Dimensions: []string{"query"}}
+
+	err := streamAnalytics(&out, fake, query, "csv")
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{
+		"Query,Clicks,Impressions,CTR,Position",
+		"a,1,10,0.100000,3.00",
+		"b,2,10,0.100000,3.00",
+	}, lines, "rows written before the failure are kept")
+	assert.Contains(t, fake.written[1], "a,1,", "the first page was flushed before the second was fetched")
+}
+
+func TestStreamAnalytics_NDJSON(t *testing.T) {
+	var out bytes.Buffer
+	fake := &fakeStreamer{pages: [][]gsc.SearchAnalyticsRow{{streamRow("a", 1), streamRow("b", 2)}}, out: &out}
+
+	require.NoError(t, streamAnalytics(&out, fake, &gsc.SearchAnalyticsQuery{Dimensions: []string{"query"}}, "ndjson"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"Keys":["a"],"Clicks":1,"Impressions":10,"CTR":0.1,"Position":3}`, lines[0])
+}
+
+func TestStreamAnalytics_RejectsTable(t *testing.T) {
+	err := streamAnalytics(&bytes.Buffer{}, &fakeStreamer{}, &gsc.SearchAnalyticsQuery{}, "table")
+	assert.ErrorIs(t, err, output.ErrUnknownFormat)
+}
+
+func TestOpenOutput(t *testing.T) {
+	w, closeOutput, err := openOutput("", "table")
+	require.NoError(t, err)
+	assert.Equal(t, os.Stdout, w)
+	require.NoError(t, closeOutput())
+
+	_, _, err = openOutput(filepath.Join(t.TempDir(), "report.txt"), "table")
+	assert.ErrorContains(t, err, "--output needs --format")
+
+	path := filepath.Join(t.TempDir(), "report.csv")
+	w, closeOutput, err = openOutput(path, "csv")
+	require.NoError(t, err)
+	require.NoError(t, writeAnalyticsRows(w, "csv", []string{"query"}, []gsc.SearchAnalyticsRow{streamRow("a", 1)}))
+	require.NoError(t, closeOutput())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Query,Clicks,Impressions,CTR,Position\na,1,10,0.100000,3.00\n", string(data))
+}
+
+func TestStatusWriter(t *testing.T) {
+	assert.Equal(t, os.Stderr, statusWriter("csv", ""), "stdout carries the export")
+	assert.Equal(t, os.Stderr, statusWriter("ndjson", ""))
+	assert.Equal(t, os.Stdout, statusWriter("csv", "out.csv"), "the export goes to a file")
+	assert.Equal(t, os.Stdout, statusWriter("table", ""))
+	assert.Equal(t, os.Stdout, statusWriter("markdown", ""))
+}
+
+func TestAnalyticsFilters_FlagsReplaceConfig(t *testing.T) {
+	configured := []config.SearchFilterConfig{{Dimension: "device", Operator: "equals", Expression: "MOBILE"}}
+
+	filters, err := analyticsFilters(nil, configured)
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	assert.Equal(t, "device", filters[0].Dimension)
+
+	filters, err = analyticsFilters([]string{"page contains /blog/", "country equals esp"}, configured)
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+	assert.Equal(t, "page contains /blog/", gsc.FormatFilter(filters[0]))
+	assert.Equal(t, "country equals esp", gsc.FormatFilter(filters[1]))
+
+	_, err = analyticsFilters([]string{"page"}, nil)
+	assert.Error(t, err)
+}
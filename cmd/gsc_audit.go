@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -11,13 +10,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/garbarok/ga4-manager/internal/audit"
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 const (
@@ -67,8 +66,10 @@ still answer; override with --user-agent.
 
 Exit codes:
   0  no broken/error URLs
-  2  at least one broken or error URL
-  1  command failed (config/sitemap/API error before probing)
+  4  at least one broken or error URL
+  1  command failed: bad flags, config or sitemap
+  2  Google API error
+  3  quota exhausted
 
 Examples:
   ga4 gsc audit-urls --config configs/mysite.yaml
@@ -90,12 +91,11 @@ func init() {
 	gscAuditCmd.Flags().StringVar(&gscAuditUserAgent, "user-agent", audit.DefaultUserAgent, "User-Agent for probes (defaults to Googlebot)")
 	gscAuditCmd.Flags().IntVar(&gscAuditTimeout, "timeout", 15, "Per-request timeout in seconds")
 	gscAuditCmd.Flags().IntVar(&gscAuditConcurrency, "concurrency", 8, "Number of concurrent probes")
-	gscAuditCmd.Flags().StringVarP(&gscAuditFormat, "format", "f", "table", "Output format: table or json")
+	output.FormatVar(gscAuditCmd.Flags(), &gscAuditFormat, "f", output.FormatTable, output.FormatJSON)
 }
 
-func gscAuditRunE(_ *cobra.Command, _ []string) error {
-	os.Exit(runAuditCommand())
-	return nil
+func gscAuditRunE(cmd *cobra.Command, _ []string) error {
+	return exitWith(cmd, runAuditCommand())
 }
 
 type auditSource struct {
@@ -121,9 +121,6 @@ type auditOutput struct {
 }
 
 func runAuditCommand() int {
-	if gscAuditFormat != "table" && gscAuditFormat != diagcmd.FormatJSON {
-		return diagcmd.FailWith(os.Stderr, "invalid --format %q: must be table or json", gscAuditFormat)
-	}
 	switch gscAuditSource {
 	case auditSourceBoth, auditSourceSitemap, auditSourceGSC:
 	default:
@@ -156,14 +153,14 @@ func runAuditCommand() int {
 			if gscAuditSource == auditSourceSitemap {
 				return diagcmd.FailWith(os.Stderr, "no sitemap URL: pass --sitemap or use a config with search_console.sitemaps")
 			}
-			fmt.Fprintln(os.Stderr, "⚠ No sitemap URL available (sc-domain property without --sitemap); skipping sitemap source")
+			theme.Fprintln(os.Stderr, "⚠ No sitemap URL available (sc-domain property without --sitemap); skipping sitemap source")
 		} else {
 			urls, err := prober.FetchSitemapURLs(ctx, sitemapURL)
 			if err != nil {
 				if gscAuditSource == auditSourceSitemap {
 					return diagcmd.FailWith(os.Stderr, "failed to fetch sitemap: %v", err)
 				}
-				fmt.Fprintf(os.Stderr, "⚠ Could not fetch sitemap (%v); continuing with GSC pages only\n", err)
+				theme.Fprintf(os.Stderr, "⚠ Could not fetch sitemap (%v); continuing with GSC pages only\n", err)
 			}
 			for _, u := range urls {
 				addAuditSource(collected, u, auditSourceSitemap, 0)
@@ -178,7 +175,7 @@ func runAuditCommand() int {
 	urls := sortedAuditURLs(collected, gscAuditLimit)
 
 	// Progress goes to stderr so --format json keeps stdout pure JSON.
-	fmt.Fprintf(os.Stderr, "🔎 Auditing %d URL(s) for %s...\n", len(urls), site)
+	theme.Fprintf(os.Stderr, "🔎 Auditing %d URL(s) for %s...\n", len(urls), site)
 	results := probeAll(ctx, prober, urls, collected, gscAuditConcurrency)
 	sortAuditResults(results)
 
@@ -191,9 +188,7 @@ func runAuditCommand() int {
 	}
 
 	if gscAuditFormat == diagcmd.FormatJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(out); err != nil {
+		if err := output.JSON(os.Stdout, out); err != nil {
 			return diagcmd.FailWith(os.Stderr, "failed to encode JSON: %v", err)
 		}
 	} else {
@@ -220,8 +215,10 @@ func resolveAuditTargets() (site, sitemapURL string, err error) {
 		if site == "" {
 			site = s
 		}
-		if sitemapURL == "" && cfg.SearchConsole != nil && len(cfg.SearchConsole.Sitemaps) > 0 {
-			sitemapURL = cfg.SearchConsole.Sitemaps[0].URL
+		if sitemapURL == "" && cfg.SearchConsole != nil {
+			if active := cfg.SearchConsole.ActiveSitemaps(); len(active) > 0 {
+				sitemapURL = active[0].URL
+			}
 		}
 	}
 
@@ -236,7 +233,7 @@ func resolveAuditTargets() (site, sitemapURL string, err error) {
 }
 
 func collectGSCPages(site string, collected map[string]*auditSource) error {
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
 		return fmt.Errorf("failed to create GSC client: %w", err)
 	}
@@ -371,23 +368,24 @@ func summarize(results []audit.URLAudit) auditSummary {
 
 func renderAuditTable(out auditOutput) {
 	cols := []string{"status", "http", "impr", "sources", "url", "detail"}
-	_ = render.Render(os.Stdout, render.FormatTable, cols, out.Results, auditTableRow)
-
-	fmt.Println()
-	color.Cyan("═══ Audit Summary ═══")
-	fmt.Printf("Total: %d   ", out.Summary.Total)
-	fmt.Printf("%s   ", color.GreenString("ok: %d", out.Summary.OK))
-	fmt.Printf("%s   ", color.YellowString("redirect: %d", out.Summary.Redirect))
-	fmt.Printf("%s   ", color.YellowString("blocked: %d", out.Summary.Blocked))
-	fmt.Printf("%s   ", color.RedString("broken: %d", out.Summary.Broken))
-	fmt.Printf("%s\n", color.RedString("error: %d", out.Summary.Error))
+	opts := output.Options{Widths: map[string]int{"url": 70, "detail": 60}}
+	_ = output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, opts, cols, out.Results, auditTableRow)
+
+	theme.Println()
+	theme.Cyan("═══ Audit Summary ═══")
+	theme.Printf("Total: %d   ", out.Summary.Total)
+	theme.Printf("%s   ", theme.GreenString("ok: %d", out.Summary.OK))
+	theme.Printf("%s   ", theme.YellowString("redirect: %d", out.Summary.Redirect))
+	theme.Printf("%s   ", theme.YellowString("blocked: %d", out.Summary.Blocked))
+	theme.Printf("%s   ", theme.RedString("broken: %d", out.Summary.Broken))
+	theme.Printf("%s\n", theme.RedString("error: %d", out.Summary.Error))
 	if out.Summary.Broken == 0 && out.Summary.Error == 0 {
-		color.Green("✓ No broken or errored URLs")
+		theme.Green("✓ No broken or errored URLs")
 	} else {
-		color.Red("✗ %d URL(s) need attention", out.Summary.Broken+out.Summary.Error)
+		theme.Red("✗ %d URL(s) need attention", out.Summary.Broken+out.Summary.Error)
 	}
 	if out.Summary.Blocked > 0 {
-		color.Yellow("ℹ %d URL(s) returned 401/403/429 — likely CDN bot protection/rate-limiting; re-run with --concurrency 1 to confirm.", out.Summary.Blocked)
+		theme.Yellow("ℹ %d URL(s) returned 401/403/429 — likely CDN bot protection/rate-limiting; re-run with --concurrency 1 to confirm.", out.Summary.Blocked)
 	}
 }
 
@@ -395,15 +393,15 @@ func auditTableRow(r audit.URLAudit) []string {
 	var statusCell string
 	switch r.Classification {
 	case audit.ClassOK:
-		statusCell = color.GreenString("ok")
+		statusCell = theme.GreenString("ok")
 	case audit.ClassRedirect:
-		statusCell = color.YellowString("redirect")
+		statusCell = theme.YellowString("redirect")
 	case audit.ClassBlocked:
-		statusCell = color.YellowString("blocked")
+		statusCell = theme.YellowString("blocked")
 	case audit.ClassBroken:
-		statusCell = color.RedString("broken")
+		statusCell = theme.RedString("broken")
 	default:
-		statusCell = color.RedString("error")
+		statusCell = theme.RedString("error")
 	}
 
 	httpCell := strconv.Itoa(r.FinalStatus)
@@ -423,13 +421,5 @@ func auditTableRow(r audit.URLAudit) []string {
 		detail = r.Issues[0]
 	}
 
-	url := r.URL
-	if len(url) > 70 {
-		url = url[:67] + "..."
-	}
-	if len(detail) > 60 {
-		detail = detail[:57] + "..."
-	}
-
-	return []string{statusCell, httpCell, impr, strings.Join(r.Sources, "+"), url, detail}
+	return []string{statusCell, httpCell, impr, strings.Join(r.Sources, "+"), r.URL, detail}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
 )
 
 const (
@@ -95,8 +96,10 @@ Pass --include-cross-language to show the excluded translation findings.
 
 Exit codes:
   0  no actionable cannibalising queries (after --only-actionable filter)
-  2  at least one cannibalising query in the result set
-  1  command failed (API error, malformed config, etc.)
+  4  at least one cannibalising query in the result set
+  1  command failed: bad flags or config
+  2  Google API error
+  3  quota exhausted
 
 Examples:
   ga4 gsc cannibalization --config configs/mysite.yaml
@@ -113,7 +116,7 @@ func init() {
 
 	gscCannibalizationCmd.Flags().StringVarP(&gscCannibalizationConfig, "config", "c", "", "Path to configuration file (required)")
 	gscCannibalizationCmd.Flags().Int64Var(&gscCannibalizationMinImpressions, "min-impressions", diagnostics.DefaultMinImpressions, "Per-page impression threshold for the cannibalisation predicate")
-	gscCannibalizationCmd.Flags().StringVar(&gscCannibalizationFormat, "format", diagcmd.FormatTable, "Output format: table or json")
+	output.FormatVar(gscCannibalizationCmd.Flags(), &gscCannibalizationFormat, "", output.FormatTable, output.FormatJSON)
 	gscCannibalizationCmd.Flags().IntVar(&gscCannibalizationDays, "days", cannibalizationDaysDefault, "Lookback window in days (1–485)")
 	gscCannibalizationCmd.Flags().BoolVar(&gscCannibalizationWithCoverageState, "with-coverage-state", false, "Inspect each candidate page via URL Inspection and emit a severity tier per finding")
 	gscCannibalizationCmd.Flags().BoolVar(&gscCannibalizationOnlyActionable, "only-actionable", false, "Drop consolidating findings from the result set (implies --with-coverage-state)")
@@ -130,7 +133,7 @@ type cannibalizationClient interface {
 
 // gscClientFactory returns a live GSC client. Tests substitute a fake.
 var gscClientFactory = func() (cannibalizationClient, func(), error) {
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
 		return nil, func() {}, err
 	}
@@ -164,7 +167,7 @@ type CannibalizationResultRow struct {
 // CannibalizationOutput is the JSON envelope emitted under --format json.
 type CannibalizationOutput = diagcmd.Envelope[CannibalizationResultRow]
 
-func cannibalizationRunE(_ *cobra.Command, _ []string) error {
+func cannibalizationRunE(cmd *cobra.Command, _ []string) error {
 	status := runCannibalizationCommand(cannibalizationParams{
 		ConfigPath:           gscCannibalizationConfig,
 		MinImpressions:       gscCannibalizationMinImpressions,
@@ -178,8 +181,7 @@ func cannibalizationRunE(_ *cobra.Command, _ []string) error {
 		Stderr:               os.Stderr,
 		Now:                  time.Now().UTC(),
 	})
-	os.Exit(status)
-	return nil
+	return exitWith(cmd, status)
 }
 
 type cannibalizationParams struct {
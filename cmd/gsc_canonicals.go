@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gscCanonicalsSite   string
+	gscCanonicalsConfig string
+	gscCanonicalsDays   int
+	gscCanonicalsLimit  int
+	gscCanonicalsDepth  int
+	gscCanonicalsFormat string
+)
+
+// errCanonicalMismatches signals that the audit found mismatches;
+// `gsc canonicals` exits with diagcmd.ExitIssues.
+var errCanonicalMismatches = errors.New("canonical mismatches found")
+
+var gscCanonicalsCmd = &cobra.Command{
+	Use:   "canonicals",
+	Short: "Audit the top pages for canonicals Google overrode",
+	Long: `Inspect the pages with the most impressions and report every one whose
+Google-selected canonical is not the intended one:
+
+  overridden   the page declares a canonical and Google picked another URL
+  undeclared   the page declares none and Google canonicalised it to another
+               URL, treating it as a duplicate
+
+Either way the page's impressions go to a URL the site did not choose, one of
+the most common causes of silent traffic loss. Pages that declare another URL
+as canonical and have Google agree (intended alternates) are not reported.
+
+Mismatches are grouped by the first matching url_inspection.patterns entry of
+--config, else by path prefix (--depth segments, e.g. /blog/), the groups
+with the most mismatches first.
+
+Quota cost: one Search Analytics query and one URL Inspection per page
+(--limit). The run is refused up front if that is more than the quota left
+today.
+
+Exit codes: 0 no mismatches, 4 mismatches found, 1-3 failure.
+
+Examples:
+  ga4 gsc canonicals --config configs/mysite.yaml
+  ga4 gsc canonicals --site sc-domain:example.com --limit 200 --depth 2
+  ga4 gsc canonicals --config configs/mysite.yaml --format json`,
+}
+
+func init() {
+	gscCmd.AddCommand(gscCanonicalsCmd)
+
+	gscCanonicalsCmd.Flags().StringVarP(&gscCanonicalsSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	gscCanonicalsCmd.Flags().StringVarP(&gscCanonicalsConfig, "config", "c", "", "Read the site and url_inspection.patterns from this config")
+	gscCanonicalsCmd.Flags().IntVarP(&gscCanonicalsDays, "days", "d", 28, "Days of Search Analytics traffic used to pick the top pages")
+	gscCanonicalsCmd.Flags().IntVarP(&gscCanonicalsLimit, "limit", "l", 100, "Top pages by impressions to inspect")
+	gscCanonicalsCmd.Flags().IntVar(&gscCanonicalsDepth, "depth", sampling.DefaultDepth, "Path segments that form a group when no pattern matches")
+	output.FormatVar(gscCanonicalsCmd.Flags(), &gscCanonicalsFormat, "f", output.FormatTable, output.FormatJSON)
+
+	gscCanonicalsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runGSCCanonicals(cmd, args)
+		if errors.Is(err, errCanonicalMismatches) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	}
+}
+
+// canonicalsResult is the JSON output of `gsc canonicals`.
+type canonicalsResult struct {
+	Site       string                       `json:"site"`
+	Days       int                          `json:"days"`
+	Inspected  int                          `json:"inspected"`
+	Mismatches int                          `json:"mismatches"`
+	Groups     []diagnostics.CanonicalGroup `json:"groups"`
+}
+
+func runGSCCanonicals(cmd *cobra.Command, args []string) error {
+	if gscCanonicalsDays < 1 {
+		return fmt.Errorf("--days must be at least 1, got %d", gscCanonicalsDays)
+	}
+	if gscCanonicalsLimit < 1 {
+		return fmt.Errorf("--limit must be at least 1, got %d", gscCanonicalsLimit)
+	}
+	site, err := siteFromFlags(gscCanonicalsSite, gscCanonicalsConfig)
+	if err != nil {
+		return err
+	}
+	var patterns []diagnostics.URLPattern
+	if gscCanonicalsConfig != "" {
+		cfg, err := config.LoadConfig(gscCanonicalsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if cfg.SearchConsole != nil {
+			patterns = urlPatterns(cfg.SearchConsole.URLInspection)
+		}
+	}
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	start, end := gsc.BuildDateRange(gscCanonicalsDays)
+	query := &gsc.SearchAnalyticsQuery{
+		SiteURL:    site,
+		StartDate:  start,
+		EndDate:    end,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	}
+	if err := checkQuotaEstimate(os.Stderr, client.Estimate(
+		gsc.SearchAnalyticsStep("Fetch page traffic", query),
+		gsc.InspectionStep("Inspect top pages", gscCanonicalsLimit),
+	)); err != nil {
+		return err
+	}
+
+	report, err := client.QuerySearchAnalytics(query)
+	if err != nil {
+		return fmt.Errorf("failed to fetch page traffic: %w", err)
+	}
+	traffic := make(map[string]diagnostics.PageTraffic, len(report.Rows))
+	for _, row := range report.Rows {
+		if len(row.Keys) > 0 {
+			traffic[row.Keys[0]] = diagnostics.PageTraffic{Clicks: row.Clicks, Impressions: row.Impressions}
+		}
+	}
+	urls := topPagesByImpressions(traffic, gscCanonicalsLimit)
+	if len(urls) == 0 {
+		theme.Yellow("⚠ No pages with impressions for %s in the last %d days", site, gscCanonicalsDays)
+		return nil
+	}
+
+	if gscCanonicalsFormat == "table" {
+		theme.Fprintf(os.Stderr, "Inspecting the top %d pages for %s...\n", len(urls), site)
+	}
+	results, err := client.InspectMultipleURLs(site, urls)
+	if err != nil {
+		return err
+	}
+
+	groups := diagnostics.CanonicalAudit(results, traffic, canonicalGroupOf(patterns, gscCanonicalsDepth))
+	res := canonicalsResult{Site: site, Days: gscCanonicalsDays, Inspected: len(results), Groups: groups}
+	for _, g := range groups {
+		res.Mismatches += len(g.Mismatches)
+	}
+	if gscCanonicalsFormat == "json" {
+		if err := output.JSON(os.Stdout, res); err != nil {
+			return err
+		}
+	} else if err := displayCanonicals(res); err != nil {
+		return err
+	}
+
+	if res.Mismatches > 0 {
+		return errCanonicalMismatches
+	}
+	return nil
+}
+
+// topPagesByImpressions returns up to limit pages, most impressions first.
+func topPagesByImpressions(traffic map[string]diagnostics.PageTraffic, limit int) []string {
+	urls := make([]string, 0, len(traffic))
+	for u, t := range traffic {
+		if t.Impressions > 0 {
+			urls = append(urls, u)
+		}
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		a, b := traffic[urls[i]], traffic[urls[j]]
+		if a.Impressions != b.Impressions {
+			return a.Impressions > b.Impressions
+		}
+		return urls[i] < urls[j]
+	})
+	if len(urls) > limit {
+		urls = urls[:limit]
+	}
+	return urls
+}
+
+// canonicalGroupOf groups a URL under the first pattern it matches, else
+// its path prefix at depth.
+func canonicalGroupOf(patterns []diagnostics.URLPattern, depth int) func(string) string {
+	return func(rawURL string) string {
+		for _, p := range patterns {
+			if p.Matches(rawURL) {
+				return p.Pattern
+			}
+		}
+		return sampling.GroupOf(rawURL, depth)
+	}
+}
+
+func canonicalMismatchColumns() []string {
+	return []string{"URL", "Kind", "Declared", "Google Selected", "Impressions"}
+}
+
+func canonicalMismatchTableRow(m diagnostics.CanonicalMismatch) []string {
+	declared := m.UserCanonical
+	if declared == "" {
+		declared = theme.HiBlackString("none")
+	}
+	return []string{m.URL, m.Kind, declared, theme.YellowString("%s", m.GoogleCanonical), fmt.Sprintf("%d", m.Impressions)}
+}
+
+func displayCanonicals(res canonicalsResult) error {
+	theme.Cyan("═══ Canonical Audit: %s (top %d pages, last %d days) ═══", res.Site, res.Inspected, res.Days)
+	theme.Println()
+	if res.Mismatches == 0 {
+		theme.Green("✓ Google selected the intended canonical for all %d inspected pages", res.Inspected)
+		return nil
+	}
+
+	opts := output.Options{Widths: map[string]int{"URL": 50, "Declared": 50, "Google Selected": 50}}
+	for _, g := range res.Groups {
+		if len(g.Mismatches) == 0 {
+			continue
+		}
+		theme.Printf("%s  %s  %d clicks / %d impressions affected\n", g.Pattern,
+			theme.RedString("%d of %d mismatched", len(g.Mismatches), g.Inspected), g.Clicks, g.Impressions)
+		if err := output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, opts, canonicalMismatchColumns(), g.Mismatches, canonicalMismatchTableRow); err != nil {
+			return fmt.Errorf("failed to render canonicals table: %w", err)
+		}
+		theme.Println()
+	}
+
+	theme.Red("✗ %d of %d pages have a canonical Google overrode", res.Mismatches, res.Inspected)
+	theme.HiBlack("ℹ Check for duplicate content, conflicting canonical/hreflang/sitemap signals and redirects to the declared canonical.")
+	return nil
+}
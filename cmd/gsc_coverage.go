@@ -1,28 +1,42 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/gsc"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
 	gscCoverageSite      string
 	gscCoverageConfig    string
 	gscCoverageDays      int
+	gscCoverageStartDate string
+	gscCoverageEndDate   string
 	gscCoverageFormat    string
 	gscCoverageState     string
 	gscCoverageTopIssues int
 	gscCoverageDryRun    bool
+	gscCoverageCompareTo string
+	gscCoverageOutput    string
 )
 
+// errCoverageRegressed signals that --compare-to found regressions; the
+// command exits with diagcmd.ExitIssues instead of printing an error.
+var errCoverageRegressed = errors.New("coverage regressed")
+
 var gscCoverageCmd = &cobra.Command{
 	Use:   "coverage",
 	Short: "Index coverage and indexing statistics",
@@ -41,8 +55,16 @@ Output Formats:
   - table (default): Color-coded table view in terminal
   - json: Machine-readable JSON output for automation
   - csv: CSV format for spreadsheet analysis
+  - ndjson: One JSON object per sampled page, for streaming into other tools
   - markdown: Human-readable markdown report
 
+  --output FILE writes the report (not table) to FILE instead of stdout.
+  --output bq://project.dataset.table appends the page sample to a BigQuery
+  table, created on the first run and partitioned by report_date (the last
+  day of the range), so coverage history outlives Search Console's 16 months.
+  --output sheets://<spreadsheetId>/<tab> writes it to a Google Sheet tab,
+  created when missing; --sheet-mode append (default) or replace.
+
 Rate Limits:
   - Shares quota with URL inspection (2,000/day)
   - 600 requests per minute per property
@@ -52,6 +74,9 @@ Examples:
   # Generate coverage report for last 30 days
   ga4 gsc coverage --site sc-domain:example.com --days 30
 
+  # Coverage for an explicit date range
+  ga4 gsc coverage --site sc-domain:example.com --start-date 2025-03-01 --end-date 2025-03-31
+
   # Show only low impression pages
   ga4 gsc coverage --site sc-domain:example.com --state low_impressions
 
@@ -61,12 +86,29 @@ Examples:
   # Export as JSON
   ga4 gsc coverage --config configs/mysite.yaml --format json
 
+  # Page sample as CSV, written to a file
+  ga4 gsc coverage --config configs/mysite.yaml --format csv --output pages.csv
+
   # Limit top issues to 5
   ga4 gsc coverage --site sc-domain:example.com --top-issues 5
 
   # Dry-run to preview query
   ga4 gsc coverage --site sc-domain:example.com --dry-run
 
+  # CI regression check against the last saved run (exit 4 on regression)
+  ga4 gsc coverage --config configs/mysite.yaml --compare-to last --save
+
+  # Compare against an earlier JSON export
+  ga4 gsc coverage --config configs/mysite.yaml --format json > week1.json
+  ga4 gsc coverage --config configs/mysite.yaml --compare-to week1.json
+
+Comparing Runs (--compare-to):
+  Diffs per-page status against a previous run: a JSON file written by
+  --format json, or "last" for the most recent run saved with --save. Reports
+  pages that dropped from indexed to low/no impressions, recovered, newly
+  appeared or disappeared. Exit codes: 0 no regressions, 4 regressions
+  (dropped or disappeared pages), 1-3 failure.
+
 Valid States (for filtering):
   - all: Show all pages (default)
   - indexed: Show only indexed pages (impressions >= 10)
@@ -86,6 +128,9 @@ func init() {
 	// Days flag (default: 30 days)
 	gscCoverageCmd.Flags().IntVarP(&gscCoverageDays, "days", "d", 30, "Number of days to analyze (1-180)")
 
+	// Explicit date range
+	addDateRangeFlags(gscCoverageCmd, &gscCoverageStartDate, &gscCoverageEndDate)
+
 	// State filter flag
 	gscCoverageCmd.Flags().StringVar(&gscCoverageState, "state", "all", "Filter by state: all, indexed, low_impressions, no_impressions")
 
@@ -93,12 +138,27 @@ func init() {
 	gscCoverageCmd.Flags().IntVar(&gscCoverageTopIssues, "top-issues", 10, "Number of top issues to display")
 
 	// Format flag (default: table)
-	gscCoverageCmd.Flags().StringVarP(&gscCoverageFormat, "format", "f", "table", "Output format: table, json, csv, or markdown")
+	output.FormatVar(gscCoverageCmd.Flags(), &gscCoverageFormat, "f", output.FormatTable, output.FormatJSON, output.FormatCSV, output.FormatNDJSON, output.FormatMarkdown)
+
+	// Output flag: write the report to a file instead of stdout
+	gscCoverageCmd.Flags().StringVarP(&gscCoverageOutput, "output", "o", "", "Write the report to this file instead of stdout (csv, json, ndjson or markdown), or append the page sample to bq://project.dataset.table or sheets://<spreadsheetId>/<tab>")
 
 	// Dry-run flag
 	gscCoverageCmd.Flags().BoolVar(&gscCoverageDryRun, "dry-run", false, "Preview query without making API call")
 
-	gscCoverageCmd.RunE = runGSCCoverage
+	// Comparison flag
+	gscCoverageCmd.Flags().StringVar(&gscCoverageCompareTo, "compare-to", "", `Previous run to diff against: a --format json file, or "last" (saved with --save)`)
+
+	addSaveFlags(gscCoverageCmd)
+	addTableOutputFlags(gscCoverageCmd)
+
+	gscCoverageCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runGSCCoverage(cmd, args)
+		if errors.Is(err, errCoverageRegressed) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	}
 }
 
 func runGSCCoverage(cmd *cobra.Command, args []string) error {
@@ -109,12 +169,12 @@ func runGSCCoverage(cmd *cobra.Command, args []string) error {
 	if gscCoverageConfig != "" {
 		cfg, err := config.LoadConfig(gscCoverageConfig)
 		if err != nil {
-			color.Red("✗ Failed to load config: %v", err)
+			theme.Red("✗ Failed to load config: %v", err)
 			return err
 		}
 
 		if cfg.SearchConsole == nil {
-			color.Red("✗ No search_console configuration found in %s", gscCoverageConfig)
+			theme.Red("✗ No search_console configuration found in %s", gscCoverageConfig)
 			return fmt.Errorf("missing search_console config")
 		}
 
@@ -129,7 +189,7 @@ func runGSCCoverage(cmd *cobra.Command, args []string) error {
 	} else {
 		// Use flags directly
 		if gscCoverageSite == "" {
-			color.Red("✗ Either --site or --config must be provided")
+			theme.Red("✗ Either --site or --config must be provided")
 			return fmt.Errorf("missing site URL or config file")
 		}
 
@@ -139,12 +199,20 @@ func runGSCCoverage(cmd *cobra.Command, args []string) error {
 
 	// Validate inputs
 	if err := gsc.ValidateCoverageParams(siteURL, days, gscCoverageState); err != nil {
-		color.Red("✗ Validation failed: %v", err)
+		theme.Red("✗ Validation failed: %v", err)
+		return err
+	}
+	dest, err := parseTableDestination(gscCoverageOutput)
+	if err != nil {
 		return err
 	}
 
-	// Build date range for dry-run display
-	startDate, endDate := gsc.BuildDateRange(days)
+	// Build date range
+	startDate, endDate, err := resolveDateRange(gscCoverageStartDate, gscCoverageEndDate, days)
+	if err != nil {
+		theme.Red("✗ Validation failed: %v", err)
+		return err
+	}
 
 	// Dry-run mode
 	if gscCoverageDryRun {
@@ -153,37 +221,94 @@ func runGSCCoverage(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
-	// Execute coverage report
-	color.Cyan("📊 Generating index coverage report for %s...", siteURL)
-	color.Cyan("📅 Analyzing last %d days (%s to %s)", days, startDate, endDate)
+	// Open the output before spending quota, so a bad --output path fails fast
+	var table tableWriter
+	out, closeOutput := io.Writer(os.Stdout), func() error { return nil }
+	if dest != nil {
+		table, err = dest.open()
+		if err != nil {
+			theme.Red("✗ %v", err)
+			return err
+		}
+	} else {
+		out, closeOutput, err = openOutput(gscCoverageOutput, gscCoverageFormat)
+		if err != nil {
+			return err
+		}
+	}
+	defer func() { _ = closeOutput() }()
+
+	// Execute coverage report. Progress goes to stderr for json/csv so stdout
+	// stays parseable (and a JSON export can be fed back to --compare-to).
+	status := coverageStatusWriter()
+	theme.Fprintf(status, "%s\n", theme.CyanString("📊 Generating index coverage report for %s...", siteURL))
+	theme.Fprintf(status, "%s\n", theme.CyanString("📅 Analyzing %d days (%s to %s)", gsc.DateRangeDays(startDate, endDate), startDate, endDate))
 	if gscCoverageState != "all" {
-		color.Cyan("🔍 Filtering by state: %s", gscCoverageState)
+		theme.Fprintf(status, "%s\n", theme.CyanString("🔍 Filtering by state: %s", gscCoverageState))
+	}
+	theme.Fprintln(status)
+
+	// Load the baseline before saving, so "last" means the previous run.
+	var previous *gsc.IndexCoverageReport
+	if gscCoverageCompareTo != "" {
+		previous, err = loadPreviousCoverage(gscCoverageCompareTo, siteURL)
+		if err != nil {
+			theme.Red("✗ %v", err)
+			return err
+		}
 	}
-	fmt.Println()
 
-	report, err := client.GetIndexCoverageReportFiltered(siteURL, days, gscCoverageState, gscCoverageTopIssues)
+	report, err := client.GetIndexCoverageReportRange(siteURL, startDate, endDate)
 	if err != nil {
-		color.Red("✗ Failed to generate coverage report: %v", err)
+		theme.Red("✗ Failed to generate coverage report: %v", err)
+		return err
+	}
+
+	// Diff and save the unfiltered page sample; --state only narrows display.
+	var diff *gsc.CoverageDiff
+	if previous != nil {
+		diff = gsc.CompareCoverage(previous, report)
+	}
+	if err := saveHistory(store.KindCoverage, siteURL, coverageRecords(report)); err != nil {
 		return err
 	}
+	gsc.FilterCoverageReport(report, gscCoverageState, gscCoverageTopIssues)
 
 	// Display results based on format
-	switch gscCoverageFormat {
-	case "json":
-		displayCoverageJSON(report)
-	case "csv":
-		displayCoverageCSV(report)
-	case "markdown":
-		displayCoverageMarkdown(report)
+	switch {
+	case table != nil:
+		rows := coverageTableRows(siteURL, startDate, endDate, report.PagesSample)
+		if err = table.Write(rows); err == nil {
+			theme.Fprintf(status, "✓ Appended %d pages to %s\n", len(rows.Values), dest)
+		}
+	case gscCoverageFormat == "json":
+		err = displayCoverageJSON(out, report, diff)
+	case gscCoverageFormat == output.FormatCSV, gscCoverageFormat == output.FormatNDJSON:
+		err = writeCoveragePages(out, gscCoverageFormat, report.PagesSample)
+	case gscCoverageFormat == "markdown":
+		err = displayCoverageMarkdown(out, report)
 	default:
-		if err := displayCoverageTable(report); err != nil {
+		err = displayCoverageTable(report)
+	}
+	if err == nil {
+		err = closeOutput()
+	}
+	if err != nil {
+		return err
+	}
+	if gscCoverageOutput != "" && dest == nil {
+		theme.Fprintf(status, "✓ Wrote the coverage report to %s\n", gscCoverageOutput)
+	}
+
+	if diff != nil && gscCoverageFormat != "json" {
+		if err := displayCoverageDiff(status, diff); err != nil {
 			return err
 		}
 	}
@@ -194,27 +319,146 @@ func runGSCCoverage(cmd *cobra.Command, args []string) error {
 		displayCoverageQuotaStatus(client)
 	}
 
+	if diff != nil && diff.HasRegressions() {
+		return errCoverageRegressed
+	}
+	return nil
+}
+
+// coverageStatusWriter is where progress and comparison notes go: stdout for
+// human formats, stderr when stdout carries json/csv/ndjson.
+func coverageStatusWriter() io.Writer {
+	return statusWriter(gscCoverageFormat, gscCoverageOutput)
+}
+
+// loadPreviousCoverage reads the baseline for --compare-to: a coverage JSON
+// export, or "last" for the most recent run in the history store.
+func loadPreviousCoverage(ref, siteURL string) (*gsc.IndexCoverageReport, error) {
+	if ref == "last" {
+		dir := gscstate.ResolveStateDir(historyStateDir)
+		records, err := store.New(dir).LatestRun(context.Background(), store.KindCoverage, siteURL)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("no saved coverage run for %s in %s: run once with --save to create a baseline", siteURL, dir)
+		}
+		previous := &gsc.IndexCoverageReport{SiteURL: siteURL, TotalPages: len(records)}
+		for _, r := range records {
+			previous.PagesSample = append(previous.PagesSample, gsc.PageCoverage{
+				URL:         r.Dimensions["page"],
+				Impressions: int64(r.Metrics["impressions"]),
+				Clicks:      int64(r.Metrics["clicks"]),
+				CTR:         r.Metrics["ctr"],
+				Position:    r.Metrics["position"],
+				Status:      r.State["status"],
+			})
+		}
+		return previous, nil
+	}
+
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --compare-to file: %w", err)
+	}
+	var previous gsc.IndexCoverageReport
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return nil, fmt.Errorf("failed to parse %s (expected `gsc coverage --format json` output): %w", ref, err)
+	}
+	if previous.SiteURL != "" && previous.SiteURL != siteURL {
+		return nil, fmt.Errorf("%s is a coverage report for %s, not %s", ref, previous.SiteURL, siteURL)
+	}
+	return &previous, nil
+}
+
+// coverageRecords converts the page sample into history records for --save.
+func coverageRecords(report *gsc.IndexCoverageReport) []store.Record {
+	records := make([]store.Record, 0, len(report.PagesSample))
+	for _, p := range report.PagesSample {
+		records = append(records, store.Record{
+			Dimensions: map[string]string{"page": p.URL},
+			Metrics: map[string]float64{
+				"impressions": float64(p.Impressions),
+				"clicks":      float64(p.Clicks),
+				"ctr":         p.CTR,
+				"position":    p.Position,
+			},
+			State: map[string]string{"status": p.Status},
+		})
+	}
+	return records
+}
+
+// coverageChangeRow is one line of the comparison table.
+type coverageChangeRow struct {
+	change, url, before, after string
+}
+
+func coverageChangeColumns() []string {
+	return []string{"Change", "URL", "Before", "After"}
+}
+
+func coverageChangeTableRow(r coverageChangeRow) []string {
+	return []string{r.change, r.url, r.before, r.after}
+}
+
+func coverageChangeRows(diff *gsc.CoverageDiff) []coverageChangeRow {
+	pageState := func(status string, impressions int64) string {
+		return fmt.Sprintf("%s (%d imp.)", status, impressions)
+	}
+	var rows []coverageChangeRow
+	for _, c := range diff.Regressed {
+		rows = append(rows, coverageChangeRow{theme.RedString("regressed"), c.URL,
+			pageState(c.PreviousStatus, c.PreviousImpressions), pageState(c.CurrentStatus, c.CurrentImpressions)})
+	}
+	for _, p := range diff.Disappeared {
+		rows = append(rows, coverageChangeRow{theme.RedString("disappeared"), p.URL, pageState(p.Status, p.Impressions), "-"})
+	}
+	for _, c := range diff.Recovered {
+		rows = append(rows, coverageChangeRow{theme.GreenString("recovered"), c.URL,
+			pageState(c.PreviousStatus, c.PreviousImpressions), pageState(c.CurrentStatus, c.CurrentImpressions)})
+	}
+	for _, p := range diff.Appeared {
+		rows = append(rows, coverageChangeRow{theme.BlueString("appeared"), p.URL, "-", pageState(p.Status, p.Impressions)})
+	}
+	return rows
+}
+
+func displayCoverageDiff(w io.Writer, diff *gsc.CoverageDiff) error {
+	tw := theme.NewWriter(w)
+	theme.Fprintln(w, theme.CyanString("═══ Changes Since Previous Run ═══"))
+	theme.Fprintf(w, "Regressed: %d  Disappeared: %d  Recovered: %d  Appeared: %d\n",
+		len(diff.Regressed), len(diff.Disappeared), len(diff.Recovered), len(diff.Appeared))
+	if diff.Partial {
+		theme.Fprintln(w, theme.YellowString("⚠️  Page samples were truncated; appeared/disappeared pages may be outside the sample."))
+	}
+	if rows := coverageChangeRows(diff); len(rows) > 0 {
+		if err := output.Render(tw, output.FormatTable, coverageChangeColumns(), rows, coverageChangeTableRow); err != nil {
+			return fmt.Errorf("failed to render comparison table: %w", err)
+		}
+	}
+	theme.Fprintln(w)
 	return nil
 }
 
 func displayCoverageDryRun(siteURL, startDate, endDate, state string, topIssues int) {
-	color.Cyan("🔍 Dry-run mode - Preview of coverage report query")
-	fmt.Println()
-
-	color.White("Site URL:     %s", siteURL)
-	color.White("Date Range:   %s to %s", startDate, endDate)
-	color.White("State Filter: %s", state)
-	color.White("Top Issues:   %d", topIssues)
-
-	fmt.Println()
-	color.Yellow("Query Details:")
-	color.Yellow("  - Will query Search Analytics with 'page' dimension")
-	color.Yellow("  - Maximum 25,000 pages will be analyzed")
-	color.Yellow("  - Pages categorized by impression count")
-	color.Yellow("  - Results are estimates based on search performance")
-
-	fmt.Println()
-	color.Blue("ℹ️  No API call made. Remove --dry-run to execute query.")
+	theme.Cyan("🔍 Dry-run mode - Preview of coverage report query")
+	theme.Println()
+
+	theme.White("Site URL:     %s", siteURL)
+	theme.White("Date Range:   %s to %s", startDate, endDate)
+	theme.White("State Filter: %s", state)
+	theme.White("Top Issues:   %d", topIssues)
+
+	theme.Println()
+	theme.Yellow("Query Details:")
+	theme.Yellow("  - Will query Search Analytics with 'page' dimension")
+	theme.Yellow("  - Maximum 25,000 pages will be analyzed")
+	theme.Yellow("  - Pages categorized by impression count")
+	theme.Yellow("  - Results are estimates based on search performance")
+
+	theme.Println()
+	theme.Blue("ℹ️  No API call made. Remove --dry-run to execute query.")
 }
 
 // issueRow pairs a coverage issue with the report's total page count so the
@@ -252,15 +496,13 @@ func coveragePagesColumns() []string {
 	return []string{"URL", "Status", "Impressions", "Clicks", "CTR", "Position"}
 }
 
-// coveragePagesTableRow truncates the URL for terminal display and uses
-// one-decimal precision for CTR / position.
+// coveragePagesTableOptions shortens URLs for terminal display.
+var coveragePagesTableOptions = output.Options{Widths: map[string]int{"URL": 50}}
+
+// coveragePagesTableRow uses one-decimal precision for CTR / position.
 func coveragePagesTableRow(p gsc.PageCoverage) []string {
-	url := p.URL
-	if len(url) > 50 {
-		url = url[:47] + "..."
-	}
 	return []string{
-		url,
+		p.URL,
 		p.Status,
 		fmt.Sprintf("%d", p.Impressions),
 		fmt.Sprintf("%d", p.Clicks),
@@ -283,7 +525,7 @@ func coveragePagesCSVRow(p gsc.PageCoverage) []string {
 }
 
 // coveragePagesMarkdownRow keeps full URLs and matches the table-mode
-// precision; pipe escaping is handled inside the render package.
+// precision; pipe escaping is handled inside the output package.
 func coveragePagesMarkdownRow(p gsc.PageCoverage) []string {
 	return []string{
 		p.URL,
@@ -297,133 +539,149 @@ func coveragePagesMarkdownRow(p gsc.PageCoverage) []string {
 
 func displayCoverageTable(report *gsc.IndexCoverageReport) error {
 	// Display coverage summary
-	color.Cyan("═══ Index Coverage Summary ═══")
-	fmt.Printf("Total Pages Found:    %s\n", color.BlueString("%d", report.TotalPages))
-	fmt.Printf("Indexed Pages:        %s\n", color.GreenString("%d", report.IndexedPages))
+	theme.Cyan("═══ Index Coverage Summary ═══")
+	theme.Printf("Total Pages Found:    %s\n", theme.BlueString("%d", report.TotalPages))
+	theme.Printf("Indexed Pages:        %s\n", theme.GreenString("%d", report.IndexedPages))
 
 	if report.TotalPages > 0 {
 		indexedPercent := float64(report.IndexedPages) / float64(report.TotalPages) * 100
-		fmt.Printf("Indexed Percentage:   %s\n", color.YellowString("%.1f%%", indexedPercent))
+		theme.Printf("Indexed Percentage:   %s\n", theme.YellowString("%.1f%%", indexedPercent))
 	}
-	fmt.Println()
+	theme.Println()
 
 	// Display top issues
 	if len(report.TopIssues) > 0 {
-		color.Cyan("═══ Coverage Issues ═══")
+		theme.Cyan("═══ Coverage Issues ═══")
 		issueRows := make([]issueRow, len(report.TopIssues))
 		for i, issue := range report.TopIssues {
 			issueRows[i] = issueRow{issue: issue, totalPages: report.TotalPages}
 		}
-		if err := render.Render(os.Stdout, render.FormatTable, coverageIssuesColumns(), issueRows, coverageIssuesTableRow); err != nil {
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, coverageIssuesColumns(), issueRows, coverageIssuesTableRow); err != nil {
 			return fmt.Errorf("failed to render issues table: %w", err)
 		}
-		fmt.Println()
+		theme.Println()
 	}
 
 	// Display page samples (limit to first 20 for table view)
 	if len(report.PagesSample) > 0 {
-		color.Cyan("═══ Page Samples (Top 20) ═══")
+		theme.Cyan("═══ Page Samples (Top 20) ═══")
 		displayLimit := len(report.PagesSample)
 		if displayLimit > 20 {
 			displayLimit = 20
 		}
-		if err := render.Render(os.Stdout, render.FormatTable, coveragePagesColumns(), report.PagesSample[:displayLimit], coveragePagesTableRow); err != nil {
+		if err := output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, coveragePagesTableOptions, coveragePagesColumns(), report.PagesSample[:displayLimit], coveragePagesTableRow); err != nil {
 			return fmt.Errorf("failed to render pages table: %w", err)
 		}
 
 		if len(report.PagesSample) > 20 {
-			fmt.Println()
-			color.Blue("  Showing 20 of %d total pages", len(report.PagesSample))
+			theme.Println()
+			theme.Blue("  Showing 20 of %d total pages", len(report.PagesSample))
 		}
-		fmt.Println()
+		theme.Println()
 	}
 	return nil
 }
 
-func displayCoverageJSON(report *gsc.IndexCoverageReport) {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	_ = encoder.Encode(report)
+// displayCoverageJSON emits the report; with --compare-to the diff is added
+// as a comparison field, so the output still parses as a plain report.
+func displayCoverageJSON(w io.Writer, report *gsc.IndexCoverageReport, diff *gsc.CoverageDiff) error {
+	return output.JSON(w, struct {
+		*gsc.IndexCoverageReport
+		Comparison *gsc.CoverageDiff `json:"comparison,omitempty"`
+	}{report, diff})
 }
 
-func displayCoverageCSV(report *gsc.IndexCoverageReport) {
-	_ = render.Render(os.Stdout, render.FormatCSV, coveragePagesColumns(), report.PagesSample, coveragePagesCSVRow)
+// writeCoveragePages writes the page sample as csv or ndjson rows.
+func writeCoveragePages(w io.Writer, format string, pages []gsc.PageCoverage) error {
+	rw, err := output.NewReportWriter(w, format, coveragePagesColumns(), coveragePagesCSVRow)
+	if err != nil {
+		return err
+	}
+	if err := rw.Write(pages...); err != nil {
+		return err
+	}
+	return rw.Close()
 }
 
-func displayCoverageMarkdown(report *gsc.IndexCoverageReport) {
-	fmt.Println("# Index Coverage Report")
-	fmt.Println()
-	fmt.Printf("**Site:** %s  \n", report.SiteURL)
-	fmt.Printf("**Period:** %s  \n", report.Period)
-	fmt.Println()
+func displayCoverageMarkdown(w io.Writer, report *gsc.IndexCoverageReport) error {
+	theme.Fprintln(w, "# Index Coverage Report")
+	theme.Fprintln(w)
+	theme.Fprintf(w, "**Site:** %s  \n", report.SiteURL)
+	theme.Fprintf(w, "**Period:** %s  \n", report.Period)
+	theme.Fprintln(w)
 
 	// Summary
-	fmt.Println("## Summary")
-	fmt.Println()
-	fmt.Printf("- **Total Pages:** %d\n", report.TotalPages)
-	fmt.Printf("- **Indexed Pages:** %d\n", report.IndexedPages)
+	theme.Fprintln(w, "## Summary")
+	theme.Fprintln(w)
+	theme.Fprintf(w, "- **Total Pages:** %d\n", report.TotalPages)
+	theme.Fprintf(w, "- **Indexed Pages:** %d\n", report.IndexedPages)
 
 	if report.TotalPages > 0 {
 		indexedPercent := float64(report.IndexedPages) / float64(report.TotalPages) * 100
-		fmt.Printf("- **Indexed Percentage:** %.1f%%\n", indexedPercent)
+		theme.Fprintf(w, "- **Indexed Percentage:** %.1f%%\n", indexedPercent)
 	}
-	fmt.Println()
+	theme.Fprintln(w)
 
 	// Top Issues
 	if len(report.TopIssues) > 0 {
-		fmt.Println("## Coverage Issues")
-		fmt.Println()
+		theme.Fprintln(w, "## Coverage Issues")
+		theme.Fprintln(w)
 		issueRows := make([]issueRow, len(report.TopIssues))
 		for i, issue := range report.TopIssues {
 			issueRows[i] = issueRow{issue: issue, totalPages: report.TotalPages}
 		}
-		_ = render.Render(os.Stdout, render.FormatMarkdown, coverageIssuesColumns(), issueRows, coverageIssuesMarkdownRow)
-		fmt.Println()
+		if err := output.Render(w, output.FormatMarkdown, coverageIssuesColumns(), issueRows, coverageIssuesMarkdownRow); err != nil {
+			return err
+		}
+		theme.Fprintln(w)
 	}
 
 	// Page Samples
 	if len(report.PagesSample) > 0 {
-		fmt.Println("## Page Samples")
-		fmt.Println()
+		theme.Fprintln(w, "## Page Samples")
+		theme.Fprintln(w)
 
 		// Limit to top 50 for markdown
 		displayLimit := len(report.PagesSample)
 		if displayLimit > 50 {
 			displayLimit = 50
 		}
-		_ = render.Render(os.Stdout, render.FormatMarkdown, coveragePagesColumns(), report.PagesSample[:displayLimit], coveragePagesMarkdownRow)
+		if err := output.Render(w, output.FormatMarkdown, coveragePagesColumns(), report.PagesSample[:displayLimit], coveragePagesMarkdownRow); err != nil {
+			return err
+		}
 
 		if len(report.PagesSample) > 50 {
-			fmt.Println()
-			fmt.Printf("*Showing top 50 of %d total pages*\n", len(report.PagesSample))
+			theme.Fprintln(w)
+			theme.Fprintf(w, "*Showing top 50 of %d total pages*\n", len(report.PagesSample))
 		}
 	}
+	return nil
 }
 
 func displayCoverageSummary(report *gsc.IndexCoverageReport) {
-	fmt.Println()
-	color.Cyan("═══ Coverage Report Summary ═══")
-	fmt.Printf("Site:           %s\n", report.SiteURL)
-	fmt.Printf("Period:         %s\n", report.Period)
-	fmt.Printf("Total Pages:    %s\n", color.BlueString("%d", report.TotalPages))
-	fmt.Printf("Indexed Pages:  %s\n", color.GreenString("%d", report.IndexedPages))
+	theme.Println()
+	theme.Cyan("═══ Coverage Report Summary ═══")
+	theme.Printf("Site:           %s\n", report.SiteURL)
+	theme.Printf("Period:         %s\n", report.Period)
+	theme.Printf("Total Pages:    %s\n", theme.BlueString("%d", report.TotalPages))
+	theme.Printf("Indexed Pages:  %s\n", theme.GreenString("%d", report.IndexedPages))
 
 	if report.TotalPages > 0 {
 		indexedPercent := float64(report.IndexedPages) / float64(report.TotalPages) * 100
 		var percentColor func(format string, a ...interface{}) string
 		if indexedPercent >= 90.0 {
-			percentColor = color.GreenString
+			percentColor = theme.GreenString
 		} else if indexedPercent >= 70.0 {
-			percentColor = color.YellowString
+			percentColor = theme.YellowString
 		} else {
-			percentColor = color.RedString
+			percentColor = theme.RedString
 		}
-		fmt.Printf("Indexed %%:      %s\n", percentColor("%.1f%%", indexedPercent))
+		theme.Printf("Indexed %%:      %s\n", percentColor("%.1f%%", indexedPercent))
 	}
 
-	fmt.Println()
-	color.Yellow("ℹ️  Note: This is an estimate based on Search Analytics data, not real-time coverage.")
-	fmt.Println()
+	theme.Println()
+	theme.Yellow("ℹ️  Note: This is an estimate based on Search Analytics data, not real-time coverage.")
+	theme.Println()
 }
 
 func displayCoverageQuotaStatus(client *gsc.Client) {
@@ -431,18 +689,18 @@ func displayCoverageQuotaStatus(client *gsc.Client) {
 	percentage := float64(used) / float64(limit) * 100
 	remaining := limit - used
 
-	color.Cyan("═══ Daily Quota Status ═══")
-	fmt.Printf("Date:           %s\n", date)
-	fmt.Printf("Queries Used:   %d / %d (%.1f%%)\n", used, limit, percentage)
-	fmt.Printf("Remaining:      %d\n", remaining)
-	fmt.Println()
+	theme.Cyan("═══ Daily Quota Status ═══")
+	theme.Printf("Date:           %s\n", date)
+	theme.Printf("Queries Used:   %d / %d (%.1f%%)\n", used, limit, percentage)
+	theme.Printf("Remaining:      %d\n", remaining)
+	theme.Println()
 
 	if percentage >= 95.0 {
-		color.Red("🛑 Critical: Approaching daily quota limit!")
+		theme.Red("🛑 Critical: Approaching daily quota limit!")
 	} else if percentage >= 75.0 {
-		color.Yellow("⚠️  Warning: %.0f%% of daily quota used", percentage)
+		theme.Yellow("⚠️  Warning: %.0f%% of daily quota used", percentage)
 	} else {
-		color.Green("✓ Quota usage healthy")
+		theme.Green("✓ Quota usage healthy")
 	}
-	fmt.Println()
+	theme.Println()
 }
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/accesslog"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gscCrawlStatsLogs   []string
+	gscCrawlStatsFormat string
+)
+
+var gscCrawlStatsCmd = &cobra.Command{
+	Use:   "crawl-stats",
+	Short: "Googlebot crawl stats estimated from server access logs",
+	Long: `Rebuild the Search Console Crawl Stats report from your own access logs.
+
+The Search Console API has no crawl stats endpoint; the report is only in the
+web UI. This command reads Combined Log Format files (the Apache and nginx
+default) and counts the requests whose user agent is one of Google's crawlers:
+
+  - requests and bytes per day, and the mean response size
+  - responses by status class and by exact status code
+  - file type (HTML, image, JavaScript, CSS, ...)
+  - fetch purpose: the first request for a URL within the logs counts as
+    discovery, later ones as refresh (Search Console also knows about
+    crawls from before the logs begin)
+  - Googlebot type (smartphone, desktop, image, AdsBot, ...)
+
+User agents can be spoofed, so counts include anything claiming to be
+Googlebot. Files ending in .gz are decompressed. Pass rotated logs oldest
+first so fetch purposes come out right.
+
+Examples:
+  ga4 gsc crawl-stats --log /var/log/nginx/access.log
+  ga4 gsc crawl-stats --log access.log.2.gz --log access.log.1 --log access.log --format json`,
+	// Only local files are read, so the gsc credential check does not apply.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE:             runGSCCrawlStats,
+}
+
+func init() {
+	gscCmd.AddCommand(gscCrawlStatsCmd)
+	gscCrawlStatsCmd.Flags().StringArrayVarP(&gscCrawlStatsLogs, "log", "l", nil, "Access log in Combined Log Format, .gz allowed (repeatable, required)")
+	output.FormatVar(gscCrawlStatsCmd.Flags(), &gscCrawlStatsFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = gscCrawlStatsCmd.MarkFlagRequired("log")
+}
+
+// crawlStatsResult is the `gsc crawl-stats` output.
+type crawlStatsResult struct {
+	Logs    []string `json:"logs"`
+	Lines   int      `json:"lines"`
+	Skipped int      `json:"skipped"`
+	accesslog.CrawlStats
+}
+
+func runGSCCrawlStats(cmd *cobra.Command, args []string) error {
+	builder := accesslog.NewCrawlStatsBuilder()
+	res := crawlStatsResult{Logs: gscCrawlStatsLogs}
+	for _, path := range gscCrawlStatsLogs {
+		lines, skipped, err := scanLogFile(path, accesslog.FormatCombined, func(e accesslog.Entry) { builder.Add(e) })
+		if err != nil {
+			return err
+		}
+		res.Lines += lines
+		res.Skipped += skipped
+	}
+	if res.Lines > 0 && res.Skipped == res.Lines {
+		return fmt.Errorf("no line of %s is in Combined Log Format", strings.Join(gscCrawlStatsLogs, ", "))
+	}
+	res.CrawlStats = builder.Stats()
+
+	if gscCrawlStatsFormat == "json" {
+		return output.JSON(os.Stdout, res)
+	}
+	return displayCrawlStats(res)
+}
+
+// scanLogFile opens an access log, gunzipping .gz files, and scans it.
+func scanLogFile(path, format string, fn func(accesslog.Entry)) (lines, skipped int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+	lines, skipped, err = accesslog.Scan(r, format, fn)
+	if err != nil {
+		return lines, skipped, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return lines, skipped, nil
+}
+
+func displayCrawlStats(r crawlStatsResult) error {
+	theme.Cyan("═══ Crawl Stats (from access logs) ═══")
+	theme.Printf("Lines: %d, unparseable: %d, Google crawler requests: %d\n", r.Lines, r.Skipped, r.Requests)
+	if r.Requests == 0 {
+		theme.Println()
+		theme.Yellow("⚠ No requests from Google's crawlers in the logs.")
+		return nil
+	}
+	theme.Printf("Download size: %s total, %s per request on average\n", formatBytes(float64(r.Bytes)), formatBytes(r.AvgBytes))
+	if n := len(r.Days); n > 0 {
+		theme.Printf("Period: %s to %s (%d days), %.0f requests per day\n", r.Days[0].Date, r.Days[n-1].Date, n, float64(r.Requests)/float64(n))
+	}
+
+	theme.Println()
+	theme.Cyan("Requests per day:")
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Date", "Requests", "Download Size"},
+		r.Days, func(d accesslog.Day) []string {
+			return []string{d.Date, fmt.Sprintf("%d", d.Requests), formatBytes(float64(d.Bytes))}
+		}); err != nil {
+		return err
+	}
+
+	for _, b := range []struct {
+		column string
+		counts []accesslog.Count
+	}{
+		{"Response", r.Responses},
+		{"Status Code", r.Statuses},
+		{"File Type", r.FileTypes},
+		{"Purpose", r.Purposes},
+		{"Googlebot Type", r.BotTypes},
+	} {
+		theme.Println()
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{b.column, "Requests", "Share"},
+			b.counts, func(c accesslog.Count) []string {
+				return []string{c.Key, fmt.Sprintf("%d", c.Requests), fmt.Sprintf("%.1f%%", c.Share*100)}
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit.
+func formatBytes(n float64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", n/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", n/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", n/(1<<10))
+	default:
+		return fmt.Sprintf("%.0f B", n)
+	}
+}
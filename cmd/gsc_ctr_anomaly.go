@@ -12,6 +12,8 @@ import (
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
 )
 
 const (
@@ -43,7 +45,9 @@ Predicate (see CONTEXT.md "SEO Diagnostics"):
   |position_delta| < 1.0 AND ctr_delta ≤ -30%
 
 Two Search Analytics calls per run (current window + prior window of the
-same length). Stateless — no state files written.
+same length). Stateless — no state files written. Markers added with
+` + "`ga4 marker add`" + ` and Google algorithm updates that fall in either window
+are listed under the results.
 
 Each result carries the per-window numbers needed for an LLM consumer to
 prioritise and rewrite:
@@ -64,8 +68,10 @@ Filter knobs:
 
 Exit codes:
   0  no CTR anomalies detected
-  2  at least one CTR anomaly detected
-  1  command failed
+  4  at least one CTR anomaly detected
+  1  command failed: bad flags or config
+  2  Google API error
+  3  quota exhausted
 
 Examples:
   ga4 gsc ctr-anomaly --config configs/mysite.yaml
@@ -77,14 +83,14 @@ Examples:
 func init() {
 	gscCmd.AddCommand(gscCTRAnomalyCmd)
 	gscCTRAnomalyCmd.Flags().StringVarP(&gscCTRAnomalyConfig, "config", "c", "", "Path to configuration file (required)")
-	gscCTRAnomalyCmd.Flags().StringVar(&gscCTRAnomalyFormat, "format", diagcmd.FormatTable, "Output format: table or json")
+	output.FormatVar(gscCTRAnomalyCmd.Flags(), &gscCTRAnomalyFormat, "", output.FormatTable, output.FormatJSON)
 	gscCTRAnomalyCmd.Flags().IntVar(&gscCTRAnomalyDays, "days", ctrAnomalyDaysDefault, "Length of each comparison window in days")
 	gscCTRAnomalyCmd.Flags().Int64Var(&gscCTRAnomalyMinClicksPrior, "min-clicks-prior", 5, "Drop pairs whose prior-window clicks are below this floor")
 	gscCTRAnomalyCmd.Flags().Int64Var(&gscCTRAnomalyMinClicksLost, "min-clicks-lost", 0, "Drop pairs that lost fewer than this many clicks")
 }
 
 var gscCTRAnomalyClientFactory = func() (gsc.SearchAPI, func(), error) {
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
 		return nil, func() {}, err
 	}
@@ -110,7 +116,7 @@ type CTRAnomalyResultRow struct {
 
 type CTRAnomalyOutput = diagcmd.Envelope[CTRAnomalyResultRow]
 
-func ctrAnomalyRunE(_ *cobra.Command, _ []string) error {
+func ctrAnomalyRunE(cmd *cobra.Command, _ []string) error {
 	status := runCTRAnomalyCommand(ctrAnomalyParams{
 		ConfigPath:     gscCTRAnomalyConfig,
 		Format:         gscCTRAnomalyFormat,
@@ -118,12 +124,12 @@ func ctrAnomalyRunE(_ *cobra.Command, _ []string) error {
 		MinClicksPrior: gscCTRAnomalyMinClicksPrior,
 		MinClicksLost:  gscCTRAnomalyMinClicksLost,
 		Factory:        gscCTRAnomalyClientFactory,
+		StateDir:       gscstate.ResolveStateDir(historyStateDir),
 		Stdout:         os.Stdout,
 		Stderr:         os.Stderr,
 		Now:            time.Now().UTC(),
 	})
-	os.Exit(status)
-	return nil
+	return exitWith(cmd, status)
 }
 
 type ctrAnomalyParams struct {
@@ -133,6 +139,7 @@ type ctrAnomalyParams struct {
 	MinClicksPrior int64
 	MinClicksLost  int64
 	Factory        func() (gsc.SearchAPI, func(), error)
+	StateDir       string
 	Stdout         io.Writer
 	Stderr         io.Writer
 	Now            time.Time
@@ -166,6 +173,10 @@ func runCTRAnomalyCommand(p ctrAnomalyParams) int {
 	if err != nil {
 		return diagcmd.FailWith(p.Stderr, "%v", err)
 	}
+	_, curEnd, priorStart, _ := ctrAnomalyWindows(p.Now, p.Days)
+	if env.Markers, err = reportMarkers(p.StateDir, site, priorStart, curEnd); err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to read markers: %v", err)
+	}
 
 	if err := diagcmd.Render(p.Stdout, env, p.Format, ctrAnomalyColumns, ctrAnomalyTextRow); err != nil {
 		return diagcmd.FailWith(p.Stderr, "failed to render output: %v", err)
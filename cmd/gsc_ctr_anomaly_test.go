@@ -60,6 +60,7 @@ func newCTRAnomalyParams(t *testing.T, fake *fakeCTRAnomalyClient, format string
 		Days:           ctrAnomalyDaysDefault,
 		MinClicksPrior: 5,
 		Factory:        func() (gsc.SearchAPI, func(), error) { return fake, func() {}, nil },
+		StateDir:       t.TempDir(),
 		Stdout:         stdout,
 		Stderr:         stderr,
 		Now:            time.Date(2026, 6, 5, 12, 0, 0, 0, time.UTC),
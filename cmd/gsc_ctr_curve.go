@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/seo"
+)
+
+const (
+	ctrCurveDaysDefault = 28
+	ctrCurveDaysMin     = 1
+	ctrCurveDaysMax     = 485
+	ctrCurveRowLimit    = 25000
+	ctrCurveCommandName = "gsc_ctr_curve"
+)
+
+var (
+	gscCTRCurveConfig         string
+	gscCTRCurveFormat         string
+	gscCTRCurveDays           int
+	gscCTRCurveStartDate      string
+	gscCTRCurveEndDate        string
+	gscCTRCurveBenchmark      string
+	gscCTRCurveTolerance      float64
+	gscCTRCurveMinImpressions int64
+	gscCTRCurveMaxPages       int
+	gscCTRCurveAudit          bool
+	gscCTRCurveUserAgent      string
+)
+
+var gscCTRCurveCmd = &cobra.Command{
+	Use:   "ctr-curve",
+	Short: "Compare the site's CTR per ranking position against a benchmark curve",
+	Long: `Bucket Search Console queries by average position (1, 2, 3, 4-5, 6-10,
+11-20), compute the site's actual CTR in each bucket — total clicks over
+total impressions — and compare it against a benchmark position-CTR curve.
+
+A bucket whose CTR is more than --tolerance below the benchmark (default
+0.2: under 80% of it) underperforms: the site ranks there but its titles
+and meta descriptions do not win the click. For each such bucket the pages
+converting below the benchmark are listed, most potential clicks first.
+With --audit those pages are fetched and run through the on-page SEO audit
+(see ga4 seo audit), so the title and meta description problems show next
+to the clicks they cost.
+
+Benchmarks:
+  industry  built-in curve averaged from public CTR studies (default)
+  NAME      a curve under search_console.ctr_benchmarks in the config;
+            buckets it leaves out take the industry value
+
+Buckets with fewer than --min-impressions impressions are shown but never
+flagged — too little data to judge a snippet.
+
+Exit codes:
+  0  no bucket underperforms
+  4  at least one bucket underperforms
+  1  command failed: bad flags or config
+  2  Google API error
+  3  quota exhausted
+
+Examples:
+  ga4 gsc ctr-curve --config configs/mysite.yaml
+  ga4 gsc ctr-curve --config configs/mysite.yaml --benchmark ecommerce --tolerance 0.3
+  ga4 gsc ctr-curve --config configs/mysite.yaml --start-date 2026-01-01 --end-date 2026-01-31
+  ga4 gsc ctr-curve --config configs/mysite.yaml --audit --format json`,
+	RunE: ctrCurveRunE,
+}
+
+func init() {
+	gscCmd.AddCommand(gscCTRCurveCmd)
+	gscCTRCurveCmd.Flags().StringVarP(&gscCTRCurveConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(gscCTRCurveCmd.Flags(), &gscCTRCurveFormat, "", output.FormatTable, output.FormatJSON)
+	gscCTRCurveCmd.Flags().IntVar(&gscCTRCurveDays, "days", ctrCurveDaysDefault, "Lookback window in days (1–485)")
+	addDateRangeFlags(gscCTRCurveCmd, &gscCTRCurveStartDate, &gscCTRCurveEndDate)
+	gscCTRCurveCmd.Flags().StringVar(&gscCTRCurveBenchmark, "benchmark", diagnostics.IndustryBenchmark, "Benchmark curve: industry, or a name under search_console.ctr_benchmarks")
+	gscCTRCurveCmd.Flags().Float64Var(&gscCTRCurveTolerance, "tolerance", 0.2, "How far below the benchmark a bucket may fall before it is flagged (0.2 = 20%)")
+	gscCTRCurveCmd.Flags().Int64Var(&gscCTRCurveMinImpressions, "min-impressions", 100, "Minimum impressions for a bucket to be judged")
+	gscCTRCurveCmd.Flags().IntVar(&gscCTRCurveMaxPages, "max-pages", 10, "Pages listed (and audited) per underperforming bucket")
+	gscCTRCurveCmd.Flags().BoolVar(&gscCTRCurveAudit, "audit", false, "Run the on-page SEO audit on the listed pages")
+	gscCTRCurveCmd.Flags().StringVar(&gscCTRCurveUserAgent, "user-agent", "", "User-Agent header for --audit (default: Go's HTTP client)")
+}
+
+var gscCTRCurveClientFactory = func() (gsc.SearchAPI, func(), error) {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, func() { _ = client.Close() }, nil
+}
+
+// CTRCurveResultRow is one position bucket of the gsc_ctr_curve JSON results.
+type CTRCurveResultRow struct {
+	Bucket          string            `json:"bucket"`
+	Queries         int               `json:"queries"`
+	Clicks          int64             `json:"clicks"`
+	Impressions     int64             `json:"impressions"`
+	CTR             float64           `json:"ctr"`
+	BenchmarkCTR    float64           `json:"benchmark_ctr"`
+	Ratio           float64           `json:"ctr_ratio"`
+	Underperforming bool              `json:"underperforming"`
+	PotentialClicks int64             `json:"potential_clicks"`
+	Pages           []CTRCurvePageRow `json:"pages,omitempty"`
+}
+
+// CTRCurvePageRow is a page converting below the benchmark in an
+// underperforming bucket.
+type CTRCurvePageRow struct {
+	Page            string  `json:"page"`
+	Queries         int     `json:"queries"`
+	Clicks          int64   `json:"clicks"`
+	Impressions     int64   `json:"impressions"`
+	CTR             float64 `json:"ctr"`
+	Position        float64 `json:"position"`
+	PotentialClicks int64   `json:"potential_clicks"`
+	// Audit is the on-page audit of the page, under --audit.
+	Audit *CTRCurveAudit `json:"audit,omitempty"`
+}
+
+// CTRCurveAudit is the part of a page audit a snippet rewrite needs. Error
+// is set, and the rest empty, when the page could not be fetched.
+type CTRCurveAudit struct {
+	Status      int         `json:"status,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"meta_description,omitempty"`
+	Issues      []seo.Issue `json:"issues,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// CTRCurveOutput is the JSON envelope under --format json.
+type CTRCurveOutput = diagcmd.Envelope[CTRCurveResultRow]
+
+func ctrCurveRunE(cmd *cobra.Command, _ []string) error {
+	p := ctrCurveParams{
+		ConfigPath:     gscCTRCurveConfig,
+		Format:         gscCTRCurveFormat,
+		Days:           gscCTRCurveDays,
+		StartDate:      gscCTRCurveStartDate,
+		EndDate:        gscCTRCurveEndDate,
+		Benchmark:      gscCTRCurveBenchmark,
+		Tolerance:      gscCTRCurveTolerance,
+		MinImpressions: gscCTRCurveMinImpressions,
+		MaxPages:       gscCTRCurveMaxPages,
+		Factory:        gscCTRCurveClientFactory,
+		Stdout:         os.Stdout,
+		Stderr:         os.Stderr,
+		Now:            time.Now().UTC(),
+	}
+	if gscCTRCurveAudit {
+		p.Audit = seo.NewPageAuditor(30*time.Second, gscCTRCurveUserAgent).Audit
+	}
+	return exitWith(cmd, runCTRCurveCommand(p))
+}
+
+type ctrCurveParams struct {
+	ConfigPath     string
+	Format         string
+	Days           int
+	StartDate      string
+	EndDate        string
+	Benchmark      string
+	Tolerance      float64
+	MinImpressions int64
+	MaxPages       int
+	Factory        func() (gsc.SearchAPI, func(), error)
+	// Audit fetches and audits a page; nil skips the audit.
+	Audit  func(ctx context.Context, url string) (*seo.Page, error)
+	Stdout io.Writer
+	Stderr io.Writer
+	Now    time.Time
+}
+
+func runCTRCurveCommand(p ctrCurveParams) int {
+	if err := diagcmd.ValidateFormat(p.Format); err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	if p.Days < ctrCurveDaysMin || p.Days > ctrCurveDaysMax {
+		return diagcmd.FailWith(p.Stderr, "invalid --days %d: must be in [%d, %d]", p.Days, ctrCurveDaysMin, ctrCurveDaysMax)
+	}
+	if p.Tolerance < 0 || p.Tolerance >= 1 {
+		return diagcmd.FailWith(p.Stderr, "invalid --tolerance %g: must be in [0, 1)", p.Tolerance)
+	}
+	if p.MaxPages < 1 {
+		return diagcmd.FailWith(p.Stderr, "invalid --max-pages %d: must be at least 1", p.MaxPages)
+	}
+	startDate, endDate, err := resolveDateRange(p.StartDate, p.EndDate, p.Days)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	site, cfg, err := diagcmd.LoadSite(p.ConfigPath)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	curve, err := diagnostics.BenchmarkCurve(p.Benchmark, cfg.SearchConsole.CTRBenchmarks)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	client, cleanup, err := p.Factory()
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to create GSC client: %v", err)
+	}
+	defer cleanup()
+
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    site,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Dimensions: []string{"query", "page"},
+		RowLimit:   ctrCurveRowLimit,
+		DataState:  "final",
+	})
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "search analytics query failed: %v", err)
+	}
+
+	rows, underperforming := buildCTRCurveRows(report.Rows, curve, p.Tolerance, p.MinImpressions, p.MaxPages)
+	if p.Audit != nil {
+		auditCTRCurvePages(context.Background(), rows, p.Audit)
+	}
+	env := diagcmd.NewEnvelope(ctrCurveCommandName, site, p.Now, rows, report.QuotaUsed)
+
+	if err := renderCTRCurve(p.Stdout, env, p.Format); err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to render output: %v", err)
+	}
+	return diagcmd.ExitCode(nil, underperforming)
+}
+
+// buildCTRCurveRows runs the curve analysis and keeps the first maxPages
+// pages of each underperforming bucket. It reports whether any bucket
+// underperforms.
+func buildCTRCurveRows(rows []gsc.SearchAnalyticsRow, curve diagnostics.CTRCurve, tolerance float64, minImpressions int64, maxPages int) ([]CTRCurveResultRow, bool) {
+	diag := diagnostics.CTRCurveAnalysis(rows, curve, tolerance, minImpressions)
+	out := make([]CTRCurveResultRow, 0, len(diag))
+	underperforming := false
+	for _, r := range diag {
+		row := CTRCurveResultRow{
+			Bucket:          r.Bucket,
+			Queries:         r.Queries,
+			Clicks:          r.Clicks,
+			Impressions:     r.Impressions,
+			CTR:             r.CTR,
+			BenchmarkCTR:    r.BenchmarkCTR,
+			Ratio:           r.Ratio,
+			Underperforming: r.Underperforming,
+			PotentialClicks: r.PotentialClicks,
+		}
+		underperforming = underperforming || r.Underperforming
+		for i, pg := range r.Pages {
+			if i == maxPages {
+				break
+			}
+			row.Pages = append(row.Pages, CTRCurvePageRow{
+				Page:            pg.Page,
+				Queries:         pg.Queries,
+				Clicks:          pg.Clicks,
+				Impressions:     pg.Impressions,
+				CTR:             pg.CTR,
+				Position:        pg.Position,
+				PotentialClicks: pg.PotentialClicks,
+			})
+		}
+		out = append(out, row)
+	}
+	return out, underperforming
+}
+
+// auditCTRCurvePages attaches an on-page audit to every listed page. A page
+// listed in two buckets is fetched once.
+func auditCTRCurvePages(ctx context.Context, rows []CTRCurveResultRow, audit func(context.Context, string) (*seo.Page, error)) {
+	audits := make(map[string]*CTRCurveAudit)
+	for i := range rows {
+		for j := range rows[i].Pages {
+			page := &rows[i].Pages[j]
+			a, ok := audits[page.Page]
+			if !ok {
+				a = ctrCurveAudit(ctx, page.Page, audit)
+				audits[page.Page] = a
+			}
+			page.Audit = a
+		}
+	}
+}
+
+func ctrCurveAudit(ctx context.Context, url string, audit func(context.Context, string) (*seo.Page, error)) *CTRCurveAudit {
+	p, err := audit(ctx, url)
+	if err != nil {
+		return &CTRCurveAudit{Error: err.Error()}
+	}
+	a := &CTRCurveAudit{Status: p.Status, Description: p.Description, Issues: p.Issues}
+	if len(p.Titles) > 0 {
+		a.Title = p.Titles[0]
+	}
+	return a
+}
+
+// renderCTRCurve writes the envelope as JSON, or as the bucket table followed
+// by the pages of the underperforming buckets and the quota footer.
+func renderCTRCurve(w io.Writer, env CTRCurveOutput, format string) error {
+	if format == diagcmd.FormatJSON {
+		return output.JSON(w, env)
+	}
+	if len(env.Results) > 0 {
+		if err := output.Render(w, output.FormatTable, ctrCurveColumns, env.Results, ctrCurveTextRow); err != nil {
+			return err
+		}
+	}
+
+	var pages []ctrCurvePageLine
+	audited := false
+	for _, r := range env.Results {
+		for _, pg := range r.Pages {
+			pages = append(pages, ctrCurvePageLine{bucket: r.Bucket, benchmark: r.BenchmarkCTR, page: pg})
+			audited = audited || pg.Audit != nil
+		}
+	}
+	if len(pages) > 0 {
+		columns := ctrCurvePageColumns
+		if audited {
+			columns = append(columns[:len(columns):len(columns)], "audit")
+		}
+		if _, err := fmt.Fprintf(w, "\npages below the benchmark:\n"); err != nil {
+			return err
+		}
+		if err := output.RenderWith(w, output.FormatTable, ctrCurvePagesTableOptions, columns, pages, func(l ctrCurvePageLine) []string {
+			cells := ctrCurvePageTextRow(l)
+			if audited {
+				cells = append(cells, ctrCurveAuditSummary(l.page.Audit))
+			}
+			return cells
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "quota used: %d\n", env.QuotaUsed)
+	return err
+}
+
+var ctrCurveColumns = []string{
+	"bucket", "queries", "impr", "clicks", "ctr", "benchmark", "ratio", "potential_clicks", "status",
+}
+
+func ctrCurveTextRow(r CTRCurveResultRow) []string {
+	status := "ok"
+	switch {
+	case r.Underperforming:
+		status = "UNDERPERFORMING"
+	case r.Ratio < 1:
+		status = "below"
+	}
+	return []string{
+		r.Bucket,
+		strconv.Itoa(r.Queries),
+		strconv.FormatInt(r.Impressions, 10),
+		strconv.FormatInt(r.Clicks, 10),
+		formatCTRPercent(r.CTR),
+		formatCTRPercent(r.BenchmarkCTR),
+		strconv.FormatFloat(r.Ratio, 'f', 2, 64),
+		strconv.FormatInt(r.PotentialClicks, 10),
+		status,
+	}
+}
+
+// ctrCurvePageLine is a page row of the table with its bucket.
+type ctrCurvePageLine struct {
+	bucket    string
+	benchmark float64
+	page      CTRCurvePageRow
+}
+
+var ctrCurvePageColumns = []string{"bucket", "page", "pos", "impr", "ctr", "benchmark", "potential_clicks"}
+
+var ctrCurvePagesTableOptions = output.Options{Widths: map[string]int{"page": 70, "audit": 60}}
+
+func ctrCurvePageTextRow(l ctrCurvePageLine) []string {
+	return []string{
+		l.bucket,
+		l.page.Page,
+		strconv.FormatFloat(l.page.Position, 'f', 1, 64),
+		strconv.FormatInt(l.page.Impressions, 10),
+		formatCTRPercent(l.page.CTR),
+		formatCTRPercent(l.benchmark),
+		strconv.FormatInt(l.page.PotentialClicks, 10),
+	}
+}
+
+// ctrCurveAuditSummary names the rules a page's audit flagged at warning or
+// error severity, snippet rules first.
+func ctrCurveAuditSummary(a *CTRCurveAudit) string {
+	if a == nil {
+		return ""
+	}
+	if a.Error != "" {
+		return "fetch failed: " + a.Error
+	}
+	var snippet, other []string
+	seen := make(map[string]bool)
+	for _, issue := range a.Issues {
+		if issue.Severity == seo.SeverityInfo || seen[issue.Rule] {
+			continue
+		}
+		seen[issue.Rule] = true
+		if issue.Rule == "title" || issue.Rule == "meta_description" {
+			snippet = append(snippet, issue.Rule)
+		} else {
+			other = append(other, issue.Rule)
+		}
+	}
+	if len(snippet)+len(other) == 0 {
+		return "ok"
+	}
+	return strings.Join(append(snippet, other...), ", ")
+}
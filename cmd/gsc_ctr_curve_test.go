@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/seo"
+)
+
+func newCTRCurveParams(t *testing.T, rows []gsc.SearchAnalyticsRow, format string) (ctrCurveParams, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	fake := &fakeOpportunitiesClient{rows: rows}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	return ctrCurveParams{
+		ConfigPath:     writeConfig(t, "sc-domain:example.com"),
+		Format:         format,
+		Days:           ctrCurveDaysDefault,
+		Benchmark:      "industry",
+		Tolerance:      0.2,
+		MinImpressions: 100,
+		MaxPages:       10,
+		Factory:        func() (gsc.SearchAPI, func(), error) { return fake, func() {}, nil },
+		Stdout:         stdout,
+		Stderr:         stderr,
+		Now:            time.Date(2026, 6, 5, 12, 0, 0, 0, time.UTC),
+	}, stdout, stderr
+}
+
+func TestRunCTRCurveCommand_CleanWhenAtBenchmark(t *testing.T) {
+	params, stdout, _ := newCTRCurveParams(t, []gsc.SearchAnalyticsRow{
+		opportunityRow("q1", "https://example.com/a", 30, 100, 0.30, 1.0),
+		opportunityRow("q2", "https://example.com/b", 5, 100, 0.05, 7.0),
+	}, diagcmd.FormatTable)
+	if status := runCTRCurveCommand(params); status != diagcmd.ExitClean {
+		t.Fatalf("status = %d, want %d", status, diagcmd.ExitClean)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "6-10") || strings.Contains(out, "pages below the benchmark") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "quota used: 1\n") {
+		t.Errorf("missing quota footer:\n%s", out)
+	}
+}
+
+func TestRunCTRCurveCommand_AuditsUnderperformingPages(t *testing.T) {
+	params, stdout, _ := newCTRCurveParams(t, []gsc.SearchAnalyticsRow{
+		opportunityRow("q1", "https://example.com/a", 5, 200, 0.025, 1.0),
+		opportunityRow("q2", "https://example.com/a", 5, 200, 0.025, 2.0),
+	}, diagcmd.FormatJSON)
+	var audited []string
+	params.Audit = func(_ context.Context, url string) (*seo.Page, error) {
+		audited = append(audited, url)
+		return &seo.Page{URL: url, Status: 200, Titles: []string{"A"}, Issues: []seo.Issue{
+			{Rule: "title", Severity: seo.SeverityWarning, Message: "title is 1 characters; aim for 30–60"},
+		}}, nil
+	}
+	if status := runCTRCurveCommand(params); status != diagcmd.ExitIssues {
+		t.Fatalf("status = %d, want %d", status, diagcmd.ExitIssues)
+	}
+	if len(audited) != 1 {
+		t.Errorf("audited %v, want the page once across both buckets", audited)
+	}
+
+	var got CTRCurveOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if got.Command != ctrCurveCommandName || len(got.Results) != 2 {
+		t.Fatalf("envelope = %+v", got)
+	}
+	for _, r := range got.Results {
+		if !r.Underperforming || len(r.Pages) != 1 {
+			t.Fatalf("bucket %s = %+v, want underperforming with one page", r.Bucket, r)
+		}
+		if a := r.Pages[0].Audit; a == nil || a.Title != "A" || len(a.Issues) != 1 {
+			t.Errorf("bucket %s audit = %+v", r.Bucket, a)
+		}
+	}
+}
+
+func TestRunCTRCurveCommand_ConfiguredBenchmark(t *testing.T) {
+	params, _, stderr := newCTRCurveParams(t, []gsc.SearchAnalyticsRow{
+		opportunityRow("q1", "https://example.com/a", 5, 200, 0.025, 1.0),
+	}, diagcmd.FormatJSON)
+	body := "project:\n  name: example\nsearch_console:\n  site_url: sc-domain:example.com\n  ctr_benchmarks:\n    niche:\n      \"1\": 0.02\n"
+	params.ConfigPath = filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(params.ConfigPath, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	params.Benchmark = "niche"
+	if status := runCTRCurveCommand(params); status != diagcmd.ExitClean {
+		t.Errorf("status = %d, want %d against the 2%% niche curve", status, diagcmd.ExitClean)
+	}
+	params.Benchmark = "travel"
+	if status := runCTRCurveCommand(params); status != diagcmd.ExitFailure {
+		t.Errorf("status = %d, want %d for an unknown benchmark", status, diagcmd.ExitFailure)
+	}
+	if !strings.Contains(stderr.String(), `unknown CTR benchmark "travel"`) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestCTRCurveAuditSummary(t *testing.T) {
+	a := &CTRCurveAudit{Issues: []seo.Issue{
+		{Rule: "h1", Severity: seo.SeverityWarning},
+		{Rule: "meta_description", Severity: seo.SeverityWarning},
+		{Rule: "open_graph", Severity: seo.SeverityInfo},
+		{Rule: "title", Severity: seo.SeverityError},
+		{Rule: "title", Severity: seo.SeverityWarning},
+	}}
+	if got := ctrCurveAuditSummary(a); got != "meta_description, title, h1" {
+		t.Errorf("summary = %q", got)
+	}
+	if got := ctrCurveAuditSummary(&CTRCurveAudit{}); got != "ok" {
+		t.Errorf("clean summary = %q", got)
+	}
+	if got := ctrCurveAudit(context.Background(), "https://example.com/", func(context.Context, string) (*seo.Page, error) {
+		return nil, errors.New("timeout")
+	}); got.Error != "timeout" {
+		t.Errorf("failed audit = %+v", got)
+	}
+}
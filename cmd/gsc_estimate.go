@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// checkQuotaEstimate prints the calls each step of a run will make and
+// refuses the run when the daily quota would run out partway through.
+func checkQuotaEstimate(w io.Writer, e gsc.Estimate) error {
+	theme.Fprintln(w, theme.CyanString("═══ Quota Estimate ═══"))
+	if err := output.Render(theme.NewWriter(w), output.FormatTable,
+		[]string{"Step", "API", "Calls", "Basis"},
+		e.Steps, func(s gsc.Step) []string {
+			return []string{s.Name, s.API, fmt.Sprintf("%d", s.Calls), s.Basis}
+		}); err != nil {
+		return err
+	}
+	theme.Fprintf(w, "Total: %d calls, %d left today\n\n", e.Calls, e.Remaining)
+	if err := e.Check(); err != nil {
+		return fmt.Errorf("refusing to start: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -13,9 +14,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/garbarok/ga4-manager/internal/audit"
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
 	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
 )
 
 const (
@@ -26,12 +29,17 @@ const (
 	// into this set is treated as a recovery.
 	healthCoverageStateIndexed = "Submitted and indexed"
 
+	// Coverage state Google reports for a URL that redirects. A permanent
+	// redirect moves monitoring to the destination (see urlLineage).
+	healthCoverageStateRedirect = "Page with redirect"
+
 	// Rich-results statuses considered passing.
 	healthRichPass = "PASS"
 
 	healthChangeRegression = "regression"
 	healthChangeRecovery   = "recovery"
 	healthChangeBaseline   = "baseline"
+	healthChangeMoved      = "moved"
 )
 
 var (
@@ -39,6 +47,7 @@ var (
 	gscHealthFormat   string
 	gscHealthStateDir string
 	gscHealthDryRun   bool
+	gscHealthNoFollow bool
 )
 
 var gscHealthCmd = &cobra.Command{
@@ -70,17 +79,29 @@ Moves in the opposite direction are surfaced as recoveries (good news).
 First-time URLs are surfaced as "baseline" entries — informational,
 they don't count as regressions for exit-code purposes.
 
+Redirects: when a monitored URL is reported as "Page with redirect" and
+answers with a permanent (301/308) redirect chain to a live page, the
+destination is inspected instead and tracked from then on. The move is
+recorded as lineage in the state file and surfaced once as a "moved" entry
+(informational, like baseline); later runs inspect the destination directly
+and diff it against the original URL's history, so a slug change does not
+reset monitoring. --no-follow-redirects disables this.
+
 Each result carries the full current state of the URL so an LLM consumer
 can decide how to triage without re-inspecting.
 
 Quota cost: one URL Inspection request per priority URL. URL Inspection
 has a 2000/day budget; the command does NOT deduplicate across runs (each
-run inspects every priority URL fresh — that is the point).
+run inspects every priority URL fresh — that is the point). The estimate is
+printed to stderr before inspecting, and the run fails without calling the
+API when it exceeds the quota left today.
 
 Exit codes:
   0  no regressions (clean — silent on stdout aside from quota footer)
-  2  at least one regression detected
-  1  command failed (API error, malformed config, state write failure)
+  4  at least one regression detected
+  1  command failed: bad flags, config or state write failure
+  2  Google API error
+  3  quota exhausted
 
 Examples:
   ga4 gsc health --config configs/mysite.yaml
@@ -93,13 +114,44 @@ Examples:
 func init() {
 	gscCmd.AddCommand(gscHealthCmd)
 	gscHealthCmd.Flags().StringVarP(&gscHealthConfig, "config", "c", "", "Path to configuration file (required)")
-	gscHealthCmd.Flags().StringVar(&gscHealthFormat, "format", diagcmd.FormatTable, "Output format: table or json")
+	output.FormatVar(gscHealthCmd.Flags(), &gscHealthFormat, "", output.FormatTable, output.FormatJSON)
 	gscHealthCmd.Flags().StringVar(&gscHealthStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
 	gscHealthCmd.Flags().BoolVar(&gscHealthDryRun, "dry-run", false, "Inspect and diff but do not write a new snapshot")
+	gscHealthCmd.Flags().BoolVar(&gscHealthNoFollow, "no-follow-redirects", false, "Keep inspecting monitored URLs that permanently redirect instead of tracking the destination")
+}
+
+// redirectResolver reports where rawURL permanently redirects to, and false
+// when it does not (or the redirect is temporary, broken or unreachable).
+type redirectResolver func(ctx context.Context, rawURL string) (string, bool)
+
+// healthRedirectResolver returns the production resolver, or nil when
+// --no-follow-redirects is set.
+func healthRedirectResolver() redirectResolver {
+	if gscHealthNoFollow {
+		return nil
+	}
+	return permanentRedirectTarget(audit.NewProber(10*time.Second, ""))
+}
+
+// permanentRedirectTarget follows rawURL over HTTP and returns the final URL
+// only if every hop is a 301/308 and the destination answers 2xx.
+func permanentRedirectTarget(prober *audit.Prober) redirectResolver {
+	return func(ctx context.Context, rawURL string) (string, bool) {
+		res := prober.Probe(ctx, rawURL)
+		if res.Classification != audit.ClassRedirect || len(res.RedirectChain) == 0 {
+			return "", false
+		}
+		for _, hop := range res.RedirectChain {
+			if hop.Status != http.StatusMovedPermanently && hop.Status != http.StatusPermanentRedirect {
+				return "", false
+			}
+		}
+		return res.FinalURL, true
+	}
 }
 
 var gscHealthClientFactory = func() (gsc.InspectAPI, func(), error) {
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
 		return nil, func() {}, err
 	}
@@ -120,6 +172,9 @@ type healthURLState struct {
 	MobileUsable           bool   `json:"mobile_usable"`
 	MobileUsabilityChecked bool   `json:"mobile_usability_checked"`
 	RichResultsStatus      string `json:"rich_results_status"`
+	// TrackedURL is the URL actually inspected when it differs from the
+	// monitored one, i.e. after following a permanent redirect.
+	TrackedURL string `json:"tracked_url,omitempty"`
 }
 
 // healthFieldChange is one field-level diff inside a URL's result entry.
@@ -145,21 +200,46 @@ type HealthOutput = diagcmd.Envelope[HealthResultRow]
 // stateData is the body of the snapshot's `data` field.
 type stateData struct {
 	URLs map[string]healthURLState `json:"urls"`
+	// Lineage maps a monitored URL to the chain of permanent redirects
+	// followed from it. Only URLs that have moved appear.
+	Lineage map[string]urlLineage `json:"lineage,omitempty"`
+}
+
+// urlLineage is the redirect history of one monitored URL. Current is the
+// URL inspected on the next run.
+type urlLineage struct {
+	Current string        `json:"current"`
+	Moves   []lineageMove `json:"moves"`
+}
+
+// lineageMove records one detected permanent redirect.
+type lineageMove struct {
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	DetectedAt time.Time `json:"detected_at"`
 }
 
-func healthRunE(_ *cobra.Command, _ []string) error {
+// tracked returns the URL to inspect for monitored URL u.
+func (d stateData) tracked(u string) string {
+	if l, ok := d.Lineage[u]; ok && l.Current != "" {
+		return l.Current
+	}
+	return u
+}
+
+func healthRunE(cmd *cobra.Command, _ []string) error {
 	status := runHealthCommand(healthParams{
 		ConfigPath: gscHealthConfig,
 		Format:     gscHealthFormat,
 		StateDir:   gscstate.ResolveStateDir(gscHealthStateDir),
 		DryRun:     gscHealthDryRun,
 		Factory:    gscHealthClientFactory,
+		Resolve:    healthRedirectResolver(),
 		Stdout:     os.Stdout,
 		Stderr:     os.Stderr,
 		Now:        time.Now().UTC(),
 	})
-	os.Exit(status)
-	return nil
+	return exitWith(cmd, status)
 }
 
 type healthParams struct {
@@ -168,9 +248,11 @@ type healthParams struct {
 	StateDir   string
 	DryRun     bool
 	Factory    func() (gsc.InspectAPI, func(), error)
-	Stdout     io.Writer
-	Stderr     io.Writer
-	Now        time.Time
+	// Resolve follows permanent redirects; nil disables lineage tracking.
+	Resolve redirectResolver
+	Stdout  io.Writer
+	Stderr  io.Writer
+	Now     time.Time
 }
 
 func runHealthCommand(p healthParams) int {
@@ -192,18 +274,25 @@ func runHealthCommand(p healthParams) int {
 	}
 	defer cleanup()
 
+	if q, ok := client.(gsc.QuotaEstimator); ok {
+		if err := checkQuotaEstimate(p.Stderr, q.Estimate(gsc.InspectionStep("Inspect priority URLs", len(urls)))); err != nil {
+			return diagcmd.FailWith(p.Stderr, "%v", err)
+		}
+	}
+
 	store := gscstate.NewStore(p.StateDir)
 	prior, hasPrior, err := loadHealthSnapshot(store, site)
 	if err != nil {
 		return diagcmd.FailWith(p.Stderr, "%v", err)
 	}
 
-	currentByURL, inspections, err := inspectAllHealth(client, site, urls)
+	current, inspections, err := inspectAllHealth(client, p.Resolve, site, urls, prior, p.Now)
 	if err != nil {
 		return diagcmd.FailWith(p.Stderr, "%v", err)
 	}
+	currentByURL := current.URLs
 
-	rows := diffHealth(prior, currentByURL, hasPrior)
+	rows := diffHealth(prior.URLs, currentByURL, hasPrior)
 	hasRegression := false
 	for _, r := range rows {
 		if r.Change == healthChangeRegression {
@@ -213,7 +302,7 @@ func runHealthCommand(p healthParams) int {
 	}
 
 	if !p.DryRun {
-		if err := writeHealthSnapshot(store, site, currentByURL, p.Now); err != nil {
+		if err := writeHealthSnapshot(store, site, current, p.Now); err != nil {
 			return diagcmd.FailWith(p.Stderr, "failed to write state: %v", err)
 		}
 	}
@@ -226,29 +315,28 @@ func runHealthCommand(p healthParams) int {
 	return diagcmd.ExitCode(nil, hasRegression)
 }
 
-// loadHealthSnapshot returns the prior state map plus a flag indicating
+// loadHealthSnapshot returns the prior state plus a flag indicating
 // whether any prior state existed (false on the very first run). A missing
 // snapshot is NOT an error — first-run is the baseline case.
-func loadHealthSnapshot(store *gscstate.Store, site string) (map[string]healthURLState, bool, error) {
+func loadHealthSnapshot(store *gscstate.Store, site string) (stateData, bool, error) {
 	snap, err := store.Read(context.Background(), healthCommandName, site)
 	if err != nil {
 		if errors.Is(err, gscstate.ErrSnapshotMissing) {
-			return map[string]healthURLState{}, false, nil
+			return stateData{URLs: map[string]healthURLState{}}, false, nil
 		}
-		return nil, false, fmt.Errorf("read state: %w", err)
+		return stateData{}, false, fmt.Errorf("read state: %w", err)
 	}
 	var body stateData
 	if err := json.Unmarshal(snap.Data, &body); err != nil {
-		return nil, false, fmt.Errorf("parse state payload: %w", err)
+		return stateData{}, false, fmt.Errorf("parse state payload: %w", err)
 	}
 	if body.URLs == nil {
 		body.URLs = map[string]healthURLState{}
 	}
-	return body.URLs, true, nil
+	return body, true, nil
 }
 
-func writeHealthSnapshot(store *gscstate.Store, site string, urls map[string]healthURLState, now time.Time) error {
-	body := stateData{URLs: urls}
+func writeHealthSnapshot(store *gscstate.Store, site string, body stateData, now time.Time) error {
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("marshal state payload: %w", err)
@@ -257,7 +345,12 @@ func writeHealthSnapshot(store *gscstate.Store, site string, urls map[string]hea
 	return store.Write(context.Background(), healthCommandName, site, payload)
 }
 
-func inspectAllHealth(client gsc.InspectAPI, site string, urls []string) (map[string]healthURLState, int, error) {
+// inspectAllHealth inspects every monitored URL — or, for URLs that have
+// moved, the destination recorded in prior's lineage — keyed by the monitored
+// URL so diffs follow the content across slug changes. With a resolver, a URL
+// newly reported as "Page with redirect" is probed and, on a permanent
+// redirect, the destination is inspected and added to the lineage.
+func inspectAllHealth(client gsc.InspectAPI, resolve redirectResolver, site string, urls []string, prior stateData, now time.Time) (stateData, int, error) {
 	// Deduplicate within this run, in case the config repeats a URL.
 	seen := make(map[string]struct{}, len(urls))
 	ordered := make([]string, 0, len(urls))
@@ -269,24 +362,55 @@ func inspectAllHealth(client gsc.InspectAPI, site string, urls []string) (map[st
 		ordered = append(ordered, u)
 	}
 
-	state := make(map[string]healthURLState, len(ordered))
+	current := stateData{URLs: make(map[string]healthURLState, len(ordered))}
+	inspections := 0
 	for _, u := range ordered {
-		r, err := client.InspectURL(site, u)
+		tracked := prior.tracked(u)
+		lineage, hasLineage := prior.Lineage[u]
+
+		r, err := client.InspectURL(site, tracked)
 		if err != nil {
-			return nil, 0, fmt.Errorf("inspect %s: %w", u, err)
+			return stateData{}, 0, fmt.Errorf("inspect %s: %w", tracked, err)
+		}
+		inspections++
+
+		if resolve != nil && r.CoverageState == healthCoverageStateRedirect {
+			if dest, ok := resolve(context.Background(), tracked); ok && dest != tracked && dest != u {
+				destResult, err := client.InspectURL(site, dest)
+				if err != nil {
+					return stateData{}, 0, fmt.Errorf("inspect %s (redirect target of %s): %w", dest, tracked, err)
+				}
+				inspections++
+				lineage.Moves = append(lineage.Moves, lineageMove{From: tracked, To: dest, DetectedAt: now})
+				lineage.Current = dest
+				hasLineage = true
+				tracked, r = dest, destResult
+			}
 		}
-		state[u] = healthURLState{
-			CoverageState:     r.CoverageState,
-			GoogleCanonical:   r.GoogleCanonical,
-			UserCanonical:     r.UserCanonical,
-			RobotsBlocked:     r.RobotsBlocked,
-			IndexingAllowed:   r.IndexingAllowed,
+
+		if hasLineage {
+			if current.Lineage == nil {
+				current.Lineage = map[string]urlLineage{}
+			}
+			current.Lineage[u] = lineage
+		}
+
+		st := healthURLState{
+			CoverageState:          r.CoverageState,
+			GoogleCanonical:        r.GoogleCanonical,
+			UserCanonical:          r.UserCanonical,
+			RobotsBlocked:          r.RobotsBlocked,
+			IndexingAllowed:        r.IndexingAllowed,
 			MobileUsable:           r.MobileUsable,
 			MobileUsabilityChecked: r.MobileUsabilityChecked,
 			RichResultsStatus:      r.RichResultsStatus,
 		}
+		if tracked != u {
+			st.TrackedURL = tracked
+		}
+		current.URLs[u] = st
 	}
-	return state, len(ordered), nil
+	return current, inspections, nil
 }
 
 // diffHealth produces one HealthResultRow per URL that materially changed
@@ -359,6 +483,7 @@ func compareHealthStates(before, after healthURLState) []healthFieldChange {
 	// mobile_usable is intentionally NOT diffed: Google deprecated the signal
 	// (Dec 2023) and the API returns no verdict, so any movement is noise.
 	add("rich_results_status", before.RichResultsStatus, after.RichResultsStatus)
+	add("tracked_url", before.TrackedURL, after.TrackedURL)
 	return changes
 }
 
@@ -384,6 +509,10 @@ func classifyHealthChanges(changes []healthFieldChange, before, after healthURLS
 	if anyChangeIsGood(changes, before, after) {
 		return healthChangeRecovery
 	}
+	if before.TrackedURL != after.TrackedURL {
+		// Followed a permanent redirect and the destination is no worse.
+		return healthChangeMoved
+	}
 	// All changes were lateral (e.g. canonical text changed without changing
 	// indexability). Treat as a regression so the Operator inspects.
 	return healthChangeRegression
@@ -414,7 +543,11 @@ func anyChangeIsBad(changes []healthFieldChange, before, after healthURLState) b
 		case "google_canonical":
 			// Any canonical change is worth surfacing — could be Google
 			// re-canonicalising to a different page than the operator
-			// declared.
+			// declared. The exception is a move: the destination being its
+			// own canonical is the expected outcome of a redirect.
+			if before.TrackedURL != after.TrackedURL && after.GoogleCanonical == after.TrackedURL {
+				continue
+			}
 			return true
 		}
 	}
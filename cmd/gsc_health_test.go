@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
 )
 
 type fakeHealthClient struct {
@@ -245,6 +247,95 @@ func TestRunHealthCommand_DryRunDoesNotWriteState(t *testing.T) {
 	}
 }
 
+func TestRunHealthCommand_FollowsPermanentRedirect(t *testing.T) {
+	const oldURL, newURL = "https://example.com/old-slug", "https://example.com/new-slug"
+	urls := []string{oldURL}
+
+	fake := &fakeHealthClient{}
+	params, _, _ := newHealthParams(t, fake, urls, diagcmd.FormatJSON)
+	if status := runHealthCommand(params); status != diagcmd.ExitClean {
+		t.Fatalf("first run status = %d, want clean", status)
+	}
+
+	// Second run: the old slug now 301s to the new one.
+	fake2 := &fakeHealthClient{results: map[string]gsc.URLInspectionResult{
+		oldURL: {URL: oldURL, CoverageState: "Page with redirect"},
+	}}
+	resolved := 0
+	params2 := params
+	params2.Factory = func() (gsc.InspectAPI, func(), error) { return fake2, func() {}, nil }
+	params2.Resolve = func(_ context.Context, u string) (string, bool) {
+		resolved++
+		return newURL, u == oldURL
+	}
+	stdout := &bytes.Buffer{}
+	params2.Stdout = stdout
+	if status := runHealthCommand(params2); status != diagcmd.ExitClean {
+		t.Fatalf("move run status = %d, want clean", status)
+	}
+	if fake2.inspectCalls != 2 {
+		t.Errorf("inspectCalls = %d, want 2 (old URL + destination)", fake2.inspectCalls)
+	}
+
+	var got HealthOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(got.Results))
+	}
+	r := got.Results[0]
+	if r.URL != oldURL || r.Change != healthChangeMoved {
+		t.Errorf("row = %s %q, want %s moved", r.URL, r.Change, oldURL)
+	}
+	if r.CurrentState.TrackedURL != newURL {
+		t.Errorf("tracked_url = %q, want %q", r.CurrentState.TrackedURL, newURL)
+	}
+
+	body, hasPrior, err := loadHealthSnapshot(gscstate.NewStore(params.StateDir), "sc-domain:example.com")
+	if err != nil || !hasPrior {
+		t.Fatalf("load snapshot: hasPrior=%v err=%v", hasPrior, err)
+	}
+	lineage := body.Lineage[oldURL]
+	if lineage.Current != newURL || len(lineage.Moves) != 1 || lineage.Moves[0].From != oldURL {
+		t.Errorf("lineage = %+v", lineage)
+	}
+
+	// Third run: the destination is inspected directly and, unchanged,
+	// produces no row.
+	fake3 := &fakeHealthClient{}
+	params3 := params2
+	params3.Factory = func() (gsc.InspectAPI, func(), error) { return fake3, func() {}, nil }
+	stdout3 := &bytes.Buffer{}
+	params3.Stdout = stdout3
+	if status := runHealthCommand(params3); status != diagcmd.ExitClean {
+		t.Fatalf("third run status = %d, want clean", status)
+	}
+	if fake3.inspectCalls != 1 || resolved != 1 {
+		t.Errorf("inspectCalls = %d, resolved = %d; want 1 and 1", fake3.inspectCalls, resolved)
+	}
+	if err := json.Unmarshal(stdout3.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Results) != 0 {
+		t.Errorf("expected no rows after the move settled, got %+v", got.Results)
+	}
+}
+
+func TestRunHealthCommand_RedirectWithoutResolverStaysPut(t *testing.T) {
+	urls := []string{"https://example.com/a"}
+	fake := &fakeHealthClient{results: map[string]gsc.URLInspectionResult{
+		"https://example.com/a": {URL: "https://example.com/a", CoverageState: "Page with redirect"},
+	}}
+	params, _, _ := newHealthParams(t, fake, urls, diagcmd.FormatJSON)
+	if status := runHealthCommand(params); status != diagcmd.ExitClean {
+		t.Fatalf("status = %d", status)
+	}
+	if fake.inspectCalls != 1 {
+		t.Errorf("inspectCalls = %d, want 1", fake.inspectCalls)
+	}
+}
+
 func TestRunHealthCommand_FailureModes(t *testing.T) {
 	t.Run("invalid format", func(t *testing.T) {
 		fake := &fakeHealthClient{}
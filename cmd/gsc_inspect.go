@@ -5,11 +5,12 @@ import (
 	"os"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/garbarok/ga4-manager/internal/gsc"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
@@ -76,25 +77,30 @@ func init() {
 
 	// Rich results only flag (optional)
 	gscInspectURLCmd.Flags().BoolVarP(&gscRichResultsOnly, "rich-results-only", "r", false, "Show only rich results information")
+
+	addSaveFlags(gscInspectURLCmd)
 }
 
 func runGSCInspectURL(cmd *cobra.Command, args []string) error {
 	// Create GSC client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
 	// Display progress
-	color.Cyan("🔍 Inspecting URL: %s", gscInspectURL)
-	fmt.Println()
+	theme.Cyan("🔍 Inspecting URL: %s", gscInspectURL)
+	theme.Println()
 
 	// Call API
 	result, err := client.InspectURL(gscSiteURL, gscInspectURL)
 	if err != nil {
-		color.Red("✗ Failed to inspect URL: %v", err)
+		theme.Red("✗ Failed to inspect URL: %v", err)
+		return err
+	}
+	if err := saveHistory(store.KindInspection, gscSiteURL, inspectionRecords([]gsc.URLInspectionResult{*result})); err != nil {
 		return err
 	}
 
@@ -114,15 +120,15 @@ func runGSCInspectURL(cmd *cobra.Command, args []string) error {
 func displayInspectionResult(result *gsc.URLInspectionResult, richResultsOnly bool) error {
 	// Header
 	if richResultsOnly {
-		color.Cyan("═══ Rich Results Validation ═══")
+		theme.Cyan("═══ Rich Results Validation ═══")
 	} else {
-		color.Cyan("═══ URL Inspection Results ═══")
+		theme.Cyan("═══ URL Inspection Results ═══")
 	}
-	fmt.Println()
+	theme.Println()
 
 	// URL
-	fmt.Printf("URL: %s\n", result.URL)
-	fmt.Println()
+	theme.Printf("URL: %s\n", result.URL)
+	theme.Println()
 
 	// If rich-results-only mode, skip to rich results section
 	if richResultsOnly {
@@ -131,96 +137,116 @@ func displayInspectionResult(result *gsc.URLInspectionResult, richResultsOnly bo
 	}
 
 	// Index Status
-	color.Cyan("Index Status:")
+	theme.Cyan("Index Status:")
 	status := result.IndexStatus
 	switch status {
 	case "PASS":
-		color.Green("  ✓ Indexed (%s)", status)
+		theme.Green("  ✓ Indexed (%s)", status)
 	case "PARTIAL":
-		color.Yellow("  ⚠ Partially Indexed (%s)", status)
+		theme.Yellow("  ⚠ Partially Indexed (%s)", status)
 	case "FAIL":
-		color.Red("  ✗ Not Indexed (%s)", status)
+		theme.Red("  ✗ Not Indexed (%s)", status)
 	default:
-		fmt.Printf("  Status: %s\n", status)
+		theme.Printf("  Status: %s\n", status)
 	}
 
 	// Coverage State
 	if result.CoverageState != "" {
-		fmt.Printf("  Coverage: %s\n", result.CoverageState)
+		theme.Printf("  Coverage: %s\n", result.CoverageState)
 	}
-	fmt.Println()
+	theme.Println()
 
 	// Crawl Information
 	if result.LastCrawlTime != "" {
-		color.Cyan("Crawl Information:")
+		theme.Cyan("Crawl Information:")
 		// Parse and format time
 		if t, err := time.Parse(time.RFC3339, result.LastCrawlTime); err == nil {
-			fmt.Printf("  Last Crawl: %s\n", t.Format("2006-01-02 15:04:05 MST"))
+			theme.Printf("  Last Crawl: %s\n", t.Format("2006-01-02 15:04:05 MST"))
 		} else {
-			fmt.Printf("  Last Crawl: %s\n", result.LastCrawlTime)
+			theme.Printf("  Last Crawl: %s\n", result.LastCrawlTime)
 		}
-		fmt.Println()
+		theme.Println()
 	}
 
 	// Canonical URLs
 	if result.GoogleCanonical != "" || result.UserCanonical != "" {
-		color.Cyan("Canonical URLs:")
+		theme.Cyan("Canonical URLs:")
 		if result.GoogleCanonical != "" {
-			fmt.Printf("  Google Canonical: %s\n", result.GoogleCanonical)
+			theme.Printf("  Google Canonical: %s\n", result.GoogleCanonical)
 		}
 		if result.UserCanonical != "" {
-			fmt.Printf("  User Canonical: %s\n", result.UserCanonical)
+			theme.Printf("  User Canonical: %s\n", result.UserCanonical)
 		}
-		fmt.Println()
+		theme.Println()
 	}
 
 	// Indexing Allowed
-	color.Cyan("Indexing Status:")
+	theme.Cyan("Indexing Status:")
 	if result.IndexingAllowed {
-		color.Green("  ✓ Indexing Allowed")
+		theme.Green("  ✓ Indexing Allowed")
 	} else {
-		color.Red("  ✗ Indexing Not Allowed")
+		theme.Red("  ✗ Indexing Not Allowed")
 	}
 
 	if result.RobotsBlocked {
-		color.Red("  ✗ Blocked by robots.txt")
+		theme.Red("  ✗ Blocked by robots.txt")
 	}
-	fmt.Println()
+	theme.Println()
 
 	// Mobile Usability
-	color.Cyan("Mobile Usability:")
+	theme.Cyan("Mobile Usability:")
 	switch {
 	case !result.MobileUsabilityChecked:
 		// Google deprecated the Mobile Usability report/API field (Dec 2023);
 		// no verdict is returned, so this is "unknown", not a failure.
-		color.HiBlack("  – Not reported (Google deprecated this signal in Dec 2023)")
+		theme.HiBlack("  – Not reported (Google deprecated this signal in Dec 2023)")
 	case result.MobileUsable:
-		color.Green("  ✓ Mobile Usable")
+		theme.Green("  ✓ Mobile Usable")
 	default:
-		color.Red("  ✗ Not Mobile Usable")
+		theme.Red("  ✗ Not Mobile Usable")
 	}
 
 	if len(result.MobileIssues) > 0 {
-		color.Yellow("  Mobile Issues:")
+		theme.Yellow("  Mobile Issues:")
 		for _, issue := range result.MobileIssues {
-			fmt.Printf("    - %s\n", issue)
+			theme.Printf("    - %s\n", issue)
+		}
+	}
+	theme.Println()
+
+	// AMP (only for pages serving or linking an AMP version)
+	if amp := result.AMP; amp != nil {
+		theme.Cyan("AMP:")
+		theme.Printf("  AMP URL: %s\n", amp.URL)
+		theme.Printf("  Status: %s\n", getAMPStatus(amp))
+		if amp.IndexingState != "" {
+			theme.Printf("  Indexing State: %s\n", amp.IndexingState)
+		}
+		if amp.PageFetchState != "" {
+			theme.Printf("  Page Fetch: %s\n", amp.PageFetchState)
+		}
+		if len(amp.Issues) > 0 {
+			theme.Yellow("  AMP Issues:")
+			for _, issue := range amp.Issues {
+				theme.Printf("    - [%s] %s\n", issue.Severity, issue.Message)
+			}
 		}
+		theme.Println()
 	}
-	fmt.Println()
 
 	// Rich Results
 	displayRichResults(result)
 
 	// Indexing Issues Summary
 	if len(result.IndexingIssues) > 0 {
-		color.Cyan("Issues Found:")
-		if err := render.Render(os.Stdout, render.FormatTable, inspectIssuesColumns(), result.IndexingIssues, inspectIssuesTableRow); err != nil {
+		theme.Cyan("Issues Found:")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, inspectIssuesColumns(), result.IndexingIssues, inspectIssuesTableRow); err != nil {
 			return fmt.Errorf("failed to render issues table: %w", err)
 		}
-		fmt.Println()
+		theme.Println()
 	} else {
-		color.Green("✓ No issues detected")
-		fmt.Println()
+		theme.Green("✓ No issues detected")
+		theme.Println()
 	}
 	return nil
 }
@@ -237,9 +263,9 @@ func inspectIssuesTableRow(issue gsc.IndexingIssue) []string {
 	var severity string
 	switch issue.Severity {
 	case "ERROR":
-		severity = color.RedString("ERROR")
+		severity = theme.RedString("ERROR")
 	case "WARNING":
-		severity = color.YellowString("WARNING")
+		severity = theme.YellowString("WARNING")
 	default:
 		severity = issue.Severity
 	}
@@ -249,43 +275,43 @@ func inspectIssuesTableRow(issue gsc.IndexingIssue) []string {
 // displayRichResults shows rich results information including types and detected items
 func displayRichResults(result *gsc.URLInspectionResult) {
 	if result.RichResultsStatus == "" {
-		color.Yellow("ℹ No rich results data available")
-		fmt.Println()
+		theme.Yellow("ℹ No rich results data available")
+		theme.Println()
 		return
 	}
 
-	color.Cyan("Rich Results:")
+	theme.Cyan("Rich Results:")
 
 	// Display verdict
 	switch result.RichResultsStatus {
 	case "PASS":
-		color.Green("  ✓ Valid (%s)", result.RichResultsStatus)
+		theme.Green("  ✓ Valid (%s)", result.RichResultsStatus)
 	case "FAIL":
-		color.Red("  ✗ Invalid (%s)", result.RichResultsStatus)
+		theme.Red("  ✗ Invalid (%s)", result.RichResultsStatus)
 	default:
-		fmt.Printf("  Status: %s\n", result.RichResultsStatus)
+		theme.Printf("  Status: %s\n", result.RichResultsStatus)
 	}
 
 	// Display detected types
 	if len(result.RichResultTypes) > 0 {
-		fmt.Printf("  Detected Types: %s\n", formatRichResultTypes(result.RichResultTypes))
+		theme.Printf("  Detected Types: %s\n", formatRichResultTypes(result.RichResultTypes))
 	}
 
 	// Display detected items with details
 	if len(result.RichResultItems) > 0 {
-		color.Cyan("\n  Detected Items:")
+		theme.Cyan("\n  Detected Items:")
 		for i, item := range result.RichResultItems {
-			fmt.Printf("    %d. %s", i+1, item.Type)
+			theme.Printf("    %d. %s", i+1, item.Type)
 			if item.Name != "" {
-				fmt.Printf(" - %s", item.Name)
+				theme.Printf(" - %s", item.Name)
 			}
-			fmt.Println()
+			theme.Println()
 
 			// Show item-specific issues
 			if len(item.Issues) > 0 {
-				color.Yellow("       Issues:")
+				theme.Yellow("       Issues:")
 				for _, issue := range item.Issues {
-					fmt.Printf("         - %s\n", issue)
+					theme.Printf("         - %s\n", issue)
 				}
 			}
 		}
@@ -293,13 +319,13 @@ func displayRichResults(result *gsc.URLInspectionResult) {
 
 	// Display legacy flat issues list (for backward compatibility)
 	if len(result.RichResultsIssues) > 0 && len(result.RichResultItems) == 0 {
-		color.Yellow("  Rich Results Issues:")
+		theme.Yellow("  Rich Results Issues:")
 		for _, issue := range result.RichResultsIssues {
-			fmt.Printf("    - %s\n", issue)
+			theme.Printf("    - %s\n", issue)
 		}
 	}
 
-	fmt.Println()
+	theme.Println()
 }
 
 // formatRichResultTypes formats the types array for display
@@ -322,18 +348,18 @@ func displayInspectQuotaStatus(client *gsc.Client) {
 	used, limit, date := client.GetQuotaStatus()
 	percentage := float64(used) / float64(limit) * 100
 
-	color.Cyan("═══ Daily Quota Status ═══")
-	fmt.Println()
+	theme.Cyan("═══ Daily Quota Status ═══")
+	theme.Println()
 
-	fmt.Printf("Date: %s\n", date)
-	fmt.Printf("Inspections: %d / %d (%.1f%% used, %d remaining)\n",
+	theme.Printf("Date: %s\n", date)
+	theme.Printf("Inspections: %d / %d (%.1f%% used, %d remaining)\n",
 		used, limit, percentage, limit-used)
 
 	// Show warning if approaching limits
 	if percentage >= 95 {
-		color.Red("⚠ CRITICAL: Approaching daily limit!")
+		theme.Red("⚠ CRITICAL: Approaching daily limit!")
 	} else if percentage >= 75 {
-		color.Yellow("⚠ WARNING: %.0f%% of daily quota used", percentage)
+		theme.Yellow("⚠ WARNING: %.0f%% of daily quota used", percentage)
 	}
-	fmt.Println()
+	theme.Println()
 }
@@ -1,22 +1,28 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/gsc"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
-	gscMonitorConfig string
-	gscMonitorDryRun bool
-	gscMonitorFormat string
+	gscMonitorConfig         string
+	gscMonitorDryRun         bool
+	gscMonitorFormat         string
+	gscMonitorChanges        bool
+	gscMonitorFailOnNewIssue bool
+	gscMonitorRichResults    bool
 )
 
 var gscMonitorCmd = &cobra.Command{
@@ -36,9 +42,11 @@ Rate Limits:
   - 2,000 URL inspections per day
   - 600 inspections per minute per property
   - Rate limiting is automatic
+  - The run is refused up front if the URLs exceed the quota left today
 
 Examples:
-  # Dry-run to preview which URLs will be inspected (RECOMMENDED first step)
+  # Dry-run to preview which URLs will be inspected and the quota they use
+  # (RECOMMENDED first step)
   ga4 gsc monitor run --config configs/mysite.yaml --dry-run
 
   # Inspect all priority URLs (table output)
@@ -50,6 +58,9 @@ Examples:
   # Inspect with Markdown report (for documentation)
   ga4 gsc monitor run --config configs/mysite.yaml --format markdown
 
+  # Daily CI check: report only what changed since yesterday, exit 4 on new issues
+  ga4 gsc monitor run --config configs/mysite.yaml --save --fail-on-new-issue
+
 Note: Your config should use domain properties (sc-domain:) for best results.
 Example config:
   search_console:
@@ -57,14 +68,45 @@ Example config:
     url_inspection:
       priority_urls:
         - "https://example.com/"
-        - "https://example.com/about"`,
+        - "https://example.com/about"
+      patterns:
+        - pattern: "/blog/*"
+          sample: 20
+      expected_alternates:
+        - pattern: "/amp/*"
+          kind: amp
+
+Patterns are expanded against the URLs of search_console.sitemaps (or, with
+no sitemaps, the pages with impressions in the last 28 days); up to sample
+URLs per pattern (default 10), spread evenly through the matches, are
+inspected alongside the priority URLs, and a per-pattern health table is
+added to the report. With patterns configured, --format json emits
+{"results": [...], "patterns": [...]} instead of the bare results array.
+
+URLs matching expected_alternates do not get the "alternate page with proper
+canonical tag" warning. Use "ga4 gsc monitor alternates" to check that the
+canonicals of those alternates are indexed and receive the traffic.`,
 }
 
 var gscMonitorRunCmd = &cobra.Command{
 	Use:   "run",
-	Short: "Inspect priority URLs from config file",
-	Long:  "Load priority URLs from a configuration file and inspect each one for indexing status.",
-	RunE:  runGSCMonitor,
+	Short: "Inspect priority URLs and URL patterns from config file",
+	Long: `Load priority URLs and URL patterns from a configuration file and inspect each URL for indexing status.
+
+With --changes the run is compared with the last monitor run saved with
+--save, and only transitions are reported: URLs with new issue types or that
+dropped out of the index, and URLs whose issues cleared or that were
+reindexed. The first run has nothing to compare with and becomes the
+baseline. --fail-on-new-issue implies --changes and exits 4 when anything
+got worse, for CI; its --format json output (new_issues, resolved) is meant
+to be piped to a notifier.
+
+--show-rich-results adds a structured-data deep-dive: the rich result items
+detected on each URL with their errors and warnings, and a per-type table of
+the structured-data types failing most across the monitored set. With
+--format json the aggregate is emitted as "rich_results".
+
+Exit codes: 0 success, 4 new issues with --fail-on-new-issue, 1-3 failure.`,
 }
 
 func init() {
@@ -78,73 +120,218 @@ func init() {
 	// Dry-run flag
 	gscMonitorRunCmd.Flags().BoolVar(&gscMonitorDryRun, "dry-run", false, "Preview URLs without making API calls")
 
+	gscMonitorRunCmd.Flags().BoolVar(&gscMonitorChanges, "changes", false, "Report only new and resolved issues since the last run saved with --save")
+	gscMonitorRunCmd.Flags().BoolVar(&gscMonitorFailOnNewIssue, "fail-on-new-issue", false, "Exit 4 when a URL has a new issue or dropped out of the index (implies --changes)")
+
+	gscMonitorRunCmd.Flags().BoolVar(&gscMonitorRichResults, "show-rich-results", false, "List detected structured-data items and issues per URL and the most failing types")
+
 	// Format flag
-	gscMonitorRunCmd.Flags().StringVar(&gscMonitorFormat, "format", "table", "Output format: table, json, or markdown")
+	output.FormatVar(gscMonitorRunCmd.Flags(), &gscMonitorFormat, "", output.FormatTable, output.FormatJSON, output.FormatMarkdown)
+
+	addSaveFlags(gscMonitorRunCmd)
+
+	gscMonitorRunCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runGSCMonitor(cmd, args)
+		if errors.Is(err, errMonitorNewIssues) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	}
+}
+
+// gscMonitorClientFactory creates the client `gsc monitor run` inspects
+// with; tests replace it with a gscfake.SearchConsole.
+var gscMonitorClientFactory = func() (gsc.SearchConsole, func(), error) {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, func() { _ = client.Close() }, nil
 }
 
 func runGSCMonitor(cmd *cobra.Command, args []string) error {
 	// Load configuration
 	cfg, err := config.LoadConfig(gscMonitorConfig)
 	if err != nil {
-		color.Red("✗ Failed to load config: %v", err)
+		theme.Red("✗ Failed to load config: %v", err)
 		return err
 	}
 
 	// Validate SearchConsole config exists
 	if cfg.SearchConsole == nil {
-		color.Red("✗ No search_console configuration found in %s", gscMonitorConfig)
+		theme.Red("✗ No search_console configuration found in %s", gscMonitorConfig)
 		return fmt.Errorf("missing search_console config")
 	}
 
 	// Validate URLInspection config exists
 	if cfg.SearchConsole.URLInspection == nil {
-		color.Yellow("⚠ No url_inspection configuration found in %s", gscMonitorConfig)
-		color.Yellow("Add url_inspection.priority_urls to your config file")
+		theme.Yellow("⚠ No url_inspection configuration found in %s", gscMonitorConfig)
+		theme.Yellow("Add url_inspection.priority_urls or url_inspection.patterns to your config file")
 		return nil
 	}
 
-	// Get priority URLs
+	// Get priority URLs and patterns
 	priorityURLs := cfg.SearchConsole.URLInspection.PriorityURLs
-	if len(priorityURLs) == 0 {
-		color.Yellow("⚠ No priority URLs configured in url_inspection.priority_urls")
+	patterns := urlPatterns(cfg.SearchConsole.URLInspection)
+	if len(priorityURLs) == 0 && len(patterns) == 0 {
+		theme.Yellow("⚠ No priority URLs or patterns configured in url_inspection")
 		return nil
 	}
 
 	siteURL := cfg.SearchConsole.SiteURL
 
-	// Dry-run mode
+	// Dry-run mode: patterns are expanded from the sitemaps only, since the
+	// Search Analytics fallback is an API call.
 	if gscMonitorDryRun {
-		return displayDryRunPreview(siteURL, priorityURLs)
+		var samples []diagnostics.PatternSample
+		if len(patterns) > 0 {
+			discovered, source, err := discoverPatternURLs(nil, cfg.SearchConsole)
+			if err != nil {
+				return err
+			}
+			if source == "" {
+				theme.Yellow("⚠ No sitemap URLs to preview url_inspection.patterns with; a real run takes them from Search Analytics pages")
+			} else {
+				samples = diagnostics.ExpandPatterns(patterns, discovered)
+			}
+		}
+		urls := mergeURLs(priorityURLs, diagnostics.SampledURLs(samples))
+		if err := displayDryRunPreview(siteURL, urls); err != nil {
+			return err
+		}
+		for _, s := range samples {
+			theme.Printf("Pattern %s: %d URLs matched, %d sampled\n", s.Pattern, s.Matched, len(s.URLs))
+		}
+		inspectStep := gsc.InspectionStep("Inspect priority and pattern URLs", len(urls))
+		return checkQuotaEstimate(os.Stdout, gsc.NewEstimate(gsc.QuotaBudget(gsc.DefaultDailyLimit), inspectStep))
 	}
 
 	// Create client
-	client, err := gsc.NewClient()
+	client, closeClient, err := gscMonitorClientFactory()
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
+		return err
+	}
+	defer closeClient()
+
+	var samples []diagnostics.PatternSample
+	if len(patterns) > 0 {
+		discovered, source, err := discoverPatternURLs(client, cfg.SearchConsole)
+		if err != nil {
+			theme.Red("✗ %v", err)
+			return err
+		}
+		samples = diagnostics.ExpandPatterns(patterns, discovered)
+		theme.Cyan("🧭 Expanded %d URL pattern(s) against %d URLs from %s", len(patterns), len(discovered), source)
+	}
+	urls := mergeURLs(priorityURLs, diagnostics.SampledURLs(samples))
+
+	inspectStep := gsc.InspectionStep("Inspect priority and pattern URLs", len(urls))
+	if err := checkQuotaEstimate(os.Stderr, client.Estimate(inspectStep)); err != nil {
 		return err
 	}
-	defer func() { _ = client.Close() }()
 
 	// Inspect URLs with progress
-	color.Cyan("🔍 Inspecting %d priority URLs for %s...", len(priorityURLs), siteURL)
-	fmt.Println()
+	theme.Cyan("🔍 Inspecting %d URLs for %s...", len(urls), siteURL)
+	theme.Println()
 
-	results, err := client.InspectMultipleURLs(siteURL, priorityURLs)
+	results, err := client.InspectMultipleURLs(siteURL, urls)
 	if err != nil {
-		color.Red("✗ Failed to inspect URLs: %v", err)
+		theme.Red("✗ Failed to inspect URLs: %v", err)
+		return err
+	}
+	diagnostics.SuppressExpectedAlternates(results, expectedAlternates(cfg.SearchConsole.URLInspection))
+
+	// Load the baseline before this run is saved over it.
+	var changes monitorChangesReport
+	if gscMonitorChanges || gscMonitorFailOnNewIssue {
+		changes, err = monitorChanges(os.Stderr, siteURL, results)
+		if err != nil {
+			return err
+		}
+	}
+	if err := saveHistory(store.KindInspection, siteURL, monitorRecords(results)); err != nil {
 		return err
 	}
+	var health []diagnostics.PatternHealth
+	if len(patterns) > 0 {
+		health = diagnostics.PatternHealthReport(samples, results)
+	}
+	var richResults []diagnostics.RichResultTypeSummary
+	if gscMonitorRichResults {
+		richResults = diagnostics.SummariseRichResults(results)
+	}
+
+	if changes.InspectionDiff != nil {
+		changes.Patterns = health
+		changes.RichResults = richResults
+		switch gscMonitorFormat {
+		case "json":
+			if err := output.JSON(os.Stdout, changes); err != nil {
+				return err
+			}
+		case "markdown":
+			displayMarkdownMonitorChanges(os.Stdout, changes)
+			if len(health) > 0 {
+				displayMarkdownPatternHealth(health)
+			}
+			if gscMonitorRichResults {
+				displayMarkdownRichResults(results, richResults)
+			}
+		default:
+			if err := displayMonitorChanges(os.Stdout, changes); err != nil {
+				return err
+			}
+			if len(health) > 0 {
+				if err := displayPatternHealth(health); err != nil {
+					return err
+				}
+			}
+			if gscMonitorRichResults {
+				if err := displayRichResultsDeepDive(results, richResults); err != nil {
+					return err
+				}
+			}
+			displayQuotaStatus(client)
+		}
+		if gscMonitorFailOnNewIssue && changes.HasNewIssues() {
+			return errMonitorNewIssues
+		}
+		return nil
+	}
 
 	// Display results based on format
 	switch gscMonitorFormat {
 	case "json":
-		displayJSONResults(results)
+		var report any = results
+		if len(patterns) > 0 || gscMonitorRichResults {
+			report = monitorReport{Results: results, Patterns: health, RichResults: richResults}
+		}
+		if err := output.JSON(os.Stdout, report); err != nil {
+			return err
+		}
 	case "markdown":
 		displayMarkdownResults(results, siteURL)
+		if len(health) > 0 {
+			displayMarkdownPatternHealth(health)
+		}
+		if gscMonitorRichResults {
+			displayMarkdownRichResults(results, richResults)
+		}
 	default:
 		if err := displayTableResults(results); err != nil {
 			return err
 		}
+		if len(health) > 0 {
+			if err := displayPatternHealth(health); err != nil {
+				return err
+			}
+		}
+		if gscMonitorRichResults {
+			if err := displayRichResultsDeepDive(results, richResults); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Summary
@@ -166,24 +353,25 @@ func dryRunColumns() []string             { return []string{"#", "URL"} }
 func dryRunTableRow(r dryRunRow) []string { return []string{fmt.Sprintf("%d", r.index), r.url} }
 
 func displayDryRunPreview(siteURL string, priorityURLs []string) error {
-	color.Cyan("═══ Dry-Run Mode ═══")
-	fmt.Println()
+	theme.Cyan("═══ Dry-Run Mode ═══")
+	theme.Println()
 
-	color.Cyan("Site: %s", siteURL)
-	color.Cyan("URLs to inspect: %d", len(priorityURLs))
-	fmt.Println()
+	theme.Cyan("Site: %s", siteURL)
+	theme.Cyan("URLs to inspect: %d", len(priorityURLs))
+	theme.Println()
 
 	rows := make([]dryRunRow, len(priorityURLs))
 	for i, url := range priorityURLs {
 		rows[i] = dryRunRow{index: i + 1, url: url}
 	}
-	if err := render.Render(os.Stdout, render.FormatTable, dryRunColumns(), rows, dryRunTableRow); err != nil {
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, dryRunColumns(), rows, dryRunTableRow); err != nil {
 		return fmt.Errorf("failed to render dry-run table: %w", err)
 	}
-	fmt.Println()
+	theme.Println()
 
-	color.Yellow("ℹ️  Dry-run mode enabled - no API calls will be made")
-	color.Yellow("ℹ️  Remove --dry-run flag to perform actual inspection")
+	theme.Yellow("ℹ️  Dry-run mode enabled - no API calls will be made")
+	theme.Yellow("ℹ️  Remove --dry-run flag to perform actual inspection")
+	theme.Println()
 	return nil
 }
 
@@ -191,58 +379,68 @@ func displayDryRunPreview(siteURL string, priorityURLs []string) error {
 // inspection result for the monitor command. The table-mode cells retain
 // fatih/color escape codes so the in-terminal output keeps its colour cues;
 // the markdown projection emits plain text for portable rendering.
-func monitorColumns() []string {
-	return []string{"URL", "Index Status", "Coverage", "Mobile", "Issues"}
+// The AMP column is only added when a result has an AMP version.
+func monitorColumns(amp bool) []string {
+	cols := []string{"URL", "Index Status", "Coverage", "Mobile"}
+	if amp {
+		cols = append(cols, "AMP")
+	}
+	return append(cols, "Issues")
 }
 
-func monitorTableRow(r gsc.URLInspectionResult) []string {
-	status := getColoredStatus(r.IndexStatus)
-	mobile := getMobileStatus(r.MobileUsabilityChecked, r.MobileUsable, r.MobileIssues)
+func monitorTableRow(amp bool) func(gsc.URLInspectionResult) []string {
+	return func(r gsc.URLInspectionResult) []string {
+		status := getColoredStatus(r.IndexStatus)
+		mobile := getMobileStatus(r.MobileUsabilityChecked, r.MobileUsable, r.MobileIssues)
 
-	var issues string
-	if len(r.IndexingIssues) > 0 {
-		issues = color.RedString("%d", len(r.IndexingIssues))
-	} else {
-		issues = color.GreenString("0")
+		var issues string
+		if len(r.IndexingIssues) > 0 {
+			issues = theme.RedString("%d", len(r.IndexingIssues))
+		} else {
+			issues = theme.GreenString("0")
+		}
+
+		row := []string{r.URL, status, r.CoverageState, mobile}
+		if amp {
+			row = append(row, getAMPStatus(r.AMP))
+		}
+		return append(row, issues)
 	}
+}
 
-	url := r.URL
-	if len(url) > 60 {
-		url = url[:57] + "..."
+// hasAMP reports whether any result has an AMP version.
+func hasAMP(results []gsc.URLInspectionResult) bool {
+	for _, r := range results {
+		if r.AMP != nil {
+			return true
+		}
 	}
-	return []string{url, status, r.CoverageState, mobile, issues}
+	return false
 }
 
 func displayTableResults(results []gsc.URLInspectionResult) error {
-	color.Cyan("═══ Inspection Results ═══")
-	fmt.Println()
-	if err := render.Render(os.Stdout, render.FormatTable, monitorColumns(), results, monitorTableRow); err != nil {
+	theme.Cyan("═══ Inspection Results ═══")
+	theme.Println()
+	amp := hasAMP(results)
+	if err := output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, output.Options{Widths: map[string]int{"URL": 60}}, monitorColumns(amp), results, monitorTableRow(amp)); err != nil {
 		return fmt.Errorf("failed to render results table: %w", err)
 	}
-	fmt.Println()
+	theme.Println()
 	return nil
 }
 
-func displayJSONResults(results []gsc.URLInspectionResult) {
-	data, err := json.MarshalIndent(results, "", "  ")
-	if err != nil {
-		color.Red("✗ Failed to marshal JSON: %v", err)
-		return
-	}
-	fmt.Println(string(data))
-}
-
 func displayMarkdownResults(results []gsc.URLInspectionResult, siteURL string) {
-	fmt.Println("# URL Inspection Report")
-	fmt.Println()
-	fmt.Printf("**Site**: %s\n", siteURL)
-	fmt.Printf("**Total URLs**: %d\n", len(results))
-	fmt.Println()
+	theme.Println("# URL Inspection Report")
+	theme.Println()
+	theme.Printf("**Site**: %s\n", siteURL)
+	theme.Printf("**Total URLs**: %d\n", len(results))
+	theme.Println()
 
 	// Summary stats
 	indexed := 0
 	notIndexed := 0
 	hasIssues := 0
+	ampIssues := 0
 
 	for _, r := range results {
 		switch r.IndexStatus {
@@ -254,41 +452,50 @@ func displayMarkdownResults(results []gsc.URLInspectionResult, siteURL string) {
 		if len(r.IndexingIssues) > 0 {
 			hasIssues++
 		}
+		if r.AMP != nil && len(r.AMP.Issues) > 0 {
+			ampIssues++
+		}
 	}
 
-	fmt.Println("## Summary")
-	fmt.Println()
-	fmt.Printf("- ✓ Indexed: %d\n", indexed)
-	fmt.Printf("- ✗ Not Indexed: %d\n", notIndexed)
-	fmt.Printf("- ⚠ With Issues: %d\n", hasIssues)
-	fmt.Println()
+	theme.Println("## Summary")
+	theme.Println()
+	theme.Printf("- ✓ Indexed: %d\n", indexed)
+	theme.Printf("- ✗ Not Indexed: %d\n", notIndexed)
+	theme.Printf("- ⚠ With Issues: %d\n", hasIssues)
+	if hasAMP(results) {
+		theme.Printf("- ⚡ With AMP Issues: %d\n", ampIssues)
+	}
+	theme.Println()
 
 	// Detailed results
-	fmt.Println("## Detailed Results")
-	fmt.Println()
+	theme.Println("## Detailed Results")
+	theme.Println()
 
 	for i, r := range results {
-		fmt.Printf("### %d. %s\n", i+1, r.URL)
-		fmt.Println()
+		theme.Printf("### %d. %s\n", i+1, r.URL)
+		theme.Println()
 
-		fmt.Printf("- **Index Status**: %s\n", r.IndexStatus)
-		fmt.Printf("- **Coverage State**: %s\n", r.CoverageState)
+		theme.Printf("- **Index Status**: %s\n", r.IndexStatus)
+		theme.Printf("- **Coverage State**: %s\n", r.CoverageState)
 		if r.MobileUsabilityChecked {
-			fmt.Printf("- **Mobile Usable**: %t\n", r.MobileUsable)
+			theme.Printf("- **Mobile Usable**: %t\n", r.MobileUsable)
 		} else {
-			fmt.Printf("- **Mobile Usable**: n/a (Google deprecated this signal in Dec 2023)\n")
+			theme.Printf("- **Mobile Usable**: n/a (Google deprecated this signal in Dec 2023)\n")
+		}
+		if r.AMP != nil {
+			theme.Printf("- **AMP**: %s (%s)\n", r.AMP.Status, r.AMP.URL)
 		}
 
 		if len(r.IndexingIssues) > 0 {
-			fmt.Printf("- **Issues**: %d\n", len(r.IndexingIssues))
+			theme.Printf("- **Issues**: %d\n", len(r.IndexingIssues))
 			for _, issue := range r.IndexingIssues {
-				fmt.Printf("  - [%s] %s: %s\n", issue.Severity, issue.IssueType, issue.Message)
+				theme.Printf("  - [%s] %s: %s\n", issue.Severity, issue.IssueType, issue.Message)
 			}
 		} else {
-			fmt.Println("- **Issues**: None")
+			theme.Println("- **Issues**: None")
 		}
 
-		fmt.Println()
+		theme.Println()
 	}
 }
 
@@ -297,6 +504,7 @@ func displaySummary(results []gsc.URLInspectionResult) {
 	notIndexed := 0
 	partial := 0
 	totalIssues := 0
+	ampIssues := 0
 
 	for _, r := range results {
 		switch r.IndexStatus {
@@ -308,36 +516,42 @@ func displaySummary(results []gsc.URLInspectionResult) {
 			partial++
 		}
 		totalIssues += len(r.IndexingIssues)
+		if r.AMP != nil {
+			ampIssues += len(r.AMP.Issues)
+		}
 	}
 
-	color.Cyan("═══ Summary ═══")
-	fmt.Println()
+	theme.Cyan("═══ Summary ═══")
+	theme.Println()
 
 	if indexed > 0 {
-		color.Green("✓ Indexed: %d", indexed)
+		theme.Green("✓ Indexed: %d", indexed)
 	}
 	if partial > 0 {
-		color.Yellow("⚠ Partially Indexed: %d", partial)
+		theme.Yellow("⚠ Partially Indexed: %d", partial)
 	}
 	if notIndexed > 0 {
-		color.Red("✗ Not Indexed: %d", notIndexed)
+		theme.Red("✗ Not Indexed: %d", notIndexed)
+	}
+	if ampIssues > 0 {
+		theme.Yellow("⚡ AMP Issues: %d (included in the total)", ampIssues)
 	}
 	if totalIssues > 0 {
-		color.Red("⚠ Total Issues: %d", totalIssues)
+		theme.Red("⚠ Total Issues: %d", totalIssues)
 	} else {
-		color.Green("✓ No Issues Found")
+		theme.Green("✓ No Issues Found")
 	}
-	fmt.Println()
+	theme.Println()
 }
 
 func getColoredStatus(status string) string {
 	switch status {
 	case "PASS":
-		return color.GreenString("✓ INDEXED")
+		return theme.GreenString("✓ INDEXED")
 	case "FAIL":
-		return color.RedString("✗ NOT INDEXED")
+		return theme.RedString("✗ NOT INDEXED")
 	case "PARTIAL":
-		return color.YellowString("⚠ PARTIAL")
+		return theme.YellowString("⚠ PARTIAL")
 	default:
 		return status
 	}
@@ -347,36 +561,54 @@ func getMobileStatus(checked, usable bool, issues []string) string {
 	// Google deprecated the Mobile Usability signal in Dec 2023; when no verdict
 	// is returned, report it as not-applicable rather than a false failure.
 	if !checked {
-		return color.HiBlackString("– n/a (deprecated)")
+		return theme.HiBlackString("– n/a (deprecated)")
 	}
 	if usable {
-		return color.GreenString("✓ Usable")
+		return theme.GreenString("✓ Usable")
 	}
 	if len(issues) > 0 {
-		return color.RedString("✗ Issues (%d)", len(issues))
+		return theme.RedString("✗ Issues (%d)", len(issues))
+	}
+	return theme.RedString("✗ Not usable")
+}
+
+// getAMPStatus summarises the AMP verdict of a result; "-" when the page has
+// no AMP version.
+func getAMPStatus(amp *gsc.AMPResult) string {
+	if amp == nil {
+		return "-"
+	}
+	switch amp.Status {
+	case "PASS":
+		return theme.GreenString("✓ Valid")
+	case "FAIL":
+		return theme.RedString("✗ Invalid (%d)", len(amp.Issues))
+	case "NEUTRAL":
+		return theme.YellowString("⚠ Excluded")
+	default:
+		return amp.Status
 	}
-	return color.RedString("✗ Not usable")
 }
 
-func displayQuotaStatus(client *gsc.Client) {
+func displayQuotaStatus(client gsc.SearchConsole) {
 	used, limit, date := client.GetQuotaStatus()
 	percentage := float64(used) / float64(limit) * 100
 
-	color.Cyan("═══ Daily Quota Status ═══")
-	fmt.Println()
+	theme.Cyan("═══ Daily Quota Status ═══")
+	theme.Println()
 
-	fmt.Printf("Date: %s\n", date)
-	fmt.Printf("Inspections Used: %d / %d (%.1f%%)\n", used, limit, percentage)
-	fmt.Printf("Remaining: %d\n", limit-used)
-	fmt.Println()
+	theme.Printf("Date: %s\n", date)
+	theme.Printf("Inspections Used: %d / %d (%.1f%%)\n", used, limit, percentage)
+	theme.Printf("Remaining: %d\n", limit-used)
+	theme.Println()
 
 	// Visual quota bar
 	if percentage >= 95 {
-		color.Red("⚠ CRITICAL: Approaching daily limit!")
+		theme.Red("⚠ CRITICAL: Approaching daily limit!")
 	} else if percentage >= 75 {
-		color.Yellow("⚠ WARNING: %.0f%% of daily quota used", percentage)
+		theme.Yellow("⚠ WARNING: %.0f%% of daily quota used", percentage)
 	} else {
-		color.Green("✓ Quota usage healthy")
+		theme.Green("✓ Quota usage healthy")
 	}
-	fmt.Println()
+	theme.Println()
 }
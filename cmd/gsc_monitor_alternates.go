@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gscMonitorAltConfig string
+	gscMonitorAltDays   int
+	gscMonitorAltFormat string
+)
+
+// errAlternatesUnhealthy signals that an alternate group needs attention;
+// `monitor alternates` exits with diagcmd.ExitIssues.
+var errAlternatesUnhealthy = errors.New("alternate pages need attention")
+
+var gscMonitorAlternatesCmd = &cobra.Command{
+	Use:   "alternates",
+	Short: "Reconcile alternate pages with their canonicals",
+	Long: `Inspect the priority URLs from the config file and group those Google reports
+as "Alternate page with proper canonical tag" under the canonical they defer
+to. Each canonical is inspected too (when it is not a priority URL itself) and
+its Search Analytics traffic is compared with its alternates'.
+
+A group is flagged when:
+  - the canonical is not indexed
+  - the canonical has no impressions, or fewer than its alternates together
+  - an alternate matches no url_inspection.expected_alternates rule
+
+Expected alternates are the mobile, AMP or language versions you mean to
+canonicalise to another page:
+
+  search_console:
+    url_inspection:
+      expected_alternates:
+        - pattern: "https://m.example.com/*"
+          kind: mobile
+        - pattern: "/amp/*"
+          kind: amp
+
+Quota cost: one URL Inspection per priority URL plus one per canonical that
+is not a priority URL, and one Search Analytics query.
+
+Exit codes: 0 all groups healthy, 4 at least one group flagged, 1-3 failure.
+
+Examples:
+  ga4 gsc monitor alternates --config configs/mysite.yaml
+  ga4 gsc monitor alternates --config configs/mysite.yaml --days 90 --format json`,
+}
+
+func init() {
+	gscMonitorCmd.AddCommand(gscMonitorAlternatesCmd)
+
+	gscMonitorAlternatesCmd.Flags().StringVarP(&gscMonitorAltConfig, "config", "c", "", "Path to configuration file (required)")
+	gscMonitorAlternatesCmd.Flags().IntVarP(&gscMonitorAltDays, "days", "d", 28, "Days of Search Analytics traffic to compare")
+	output.FormatVar(gscMonitorAlternatesCmd.Flags(), &gscMonitorAltFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = gscMonitorAlternatesCmd.MarkFlagRequired("config")
+
+	gscMonitorAlternatesCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runGSCMonitorAlternates(cmd, args)
+		if errors.Is(err, errAlternatesUnhealthy) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	}
+}
+
+// alternatesResult is the JSON output of `monitor alternates`.
+type alternatesResult struct {
+	Site      string                       `json:"site"`
+	Days      int                          `json:"days"`
+	Inspected int                          `json:"inspected"`
+	Groups    []diagnostics.AlternateGroup `json:"groups"`
+}
+
+func runGSCMonitorAlternates(cmd *cobra.Command, args []string) error {
+	if gscMonitorAltDays < 1 {
+		return fmt.Errorf("--days must be at least 1, got %d", gscMonitorAltDays)
+	}
+	cfg, err := config.LoadConfig(gscMonitorAltConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.SearchConsole == nil || cfg.SearchConsole.URLInspection == nil || len(cfg.SearchConsole.URLInspection.PriorityURLs) == 0 {
+		return fmt.Errorf("no url_inspection.priority_urls in %s", gscMonitorAltConfig)
+	}
+	site := cfg.SearchConsole.SiteURL
+	urls := cfg.SearchConsole.URLInspection.PriorityURLs
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if gscMonitorAltFormat == "table" {
+		theme.Fprintf(os.Stderr, "Inspecting %d priority URLs for %s...\n", len(urls), site)
+	}
+	results, err := client.InspectMultipleURLs(site, urls)
+	if err != nil {
+		return err
+	}
+
+	var groups []diagnostics.AlternateGroup
+	if hasAlternates(results) {
+		if canonicals := diagnostics.UninspectedCanonicals(results); len(canonicals) > 0 {
+			if gscMonitorAltFormat == "table" {
+				theme.Fprintf(os.Stderr, "Inspecting %d canonicals...\n", len(canonicals))
+			}
+			more, err := client.InspectMultipleURLs(site, canonicals)
+			if err != nil {
+				return err
+			}
+			results = append(results, more...)
+		}
+		traffic, err := alternatesPageTraffic(client, site, gscMonitorAltDays)
+		if err != nil {
+			return err
+		}
+		groups = diagnostics.AlternateGroups(results, expectedAlternates(cfg.SearchConsole.URLInspection), traffic)
+	}
+
+	res := alternatesResult{Site: site, Days: gscMonitorAltDays, Inspected: len(results), Groups: groups}
+	if res.Groups == nil {
+		res.Groups = []diagnostics.AlternateGroup{}
+	}
+	if gscMonitorAltFormat == "json" {
+		if err := output.JSON(os.Stdout, res); err != nil {
+			return err
+		}
+	} else if err := displayAlternates(res); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		if !g.OK() {
+			return errAlternatesUnhealthy
+		}
+	}
+	return nil
+}
+
+func hasAlternates(results []gsc.URLInspectionResult) bool {
+	for _, r := range results {
+		if diagnostics.IsAlternate(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedAlternates converts the config rules; a nil config has none.
+func expectedAlternates(cfg *config.URLInspectionConfig) []diagnostics.ExpectedAlternate {
+	if cfg == nil {
+		return nil
+	}
+	out := make([]diagnostics.ExpectedAlternate, 0, len(cfg.ExpectedAlternates))
+	for _, a := range cfg.ExpectedAlternates {
+		out = append(out, diagnostics.ExpectedAlternate{Pattern: a.Pattern, Kind: a.Kind})
+	}
+	return out
+}
+
+func alternatesPageTraffic(client *gsc.Client, site string, days int) (map[string]diagnostics.PageTraffic, error) {
+	start, end := gsc.BuildDateRange(days)
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    site,
+		StartDate:  start,
+		EndDate:    end,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page data: %w", err)
+	}
+	traffic := make(map[string]diagnostics.PageTraffic, len(report.Rows))
+	for _, row := range report.Rows {
+		if len(row.Keys) > 0 {
+			traffic[row.Keys[0]] = diagnostics.PageTraffic{Clicks: row.Clicks, Impressions: row.Impressions}
+		}
+	}
+	return traffic, nil
+}
+
+func displayAlternates(res alternatesResult) error {
+	theme.Cyan("═══ Alternate Pages: %s (last %d days) ═══", res.Site, res.Days)
+	theme.Println()
+	if len(res.Groups) == 0 {
+		theme.Green("✓ None of the %d inspected URLs is an alternate page", res.Inspected)
+		return nil
+	}
+
+	flagged := 0
+	for _, g := range res.Groups {
+		if !g.OK() {
+			flagged++
+		}
+		status := theme.GreenString("✓ indexed")
+		switch {
+		case !g.CanonicalInspected:
+			status = theme.HiBlackString("not inspected")
+		case !g.CanonicalIndexed:
+			status = theme.RedString("✗ not indexed")
+		}
+		theme.Printf("%s  %s  %d clicks / %d impressions\n", g.Canonical, status, g.Clicks, g.Impressions)
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"Alternate", "Kind", "Clicks", "Impressions"},
+			g.Alternates, func(a diagnostics.Alternate) []string {
+				kind := a.Kind
+				if !a.Expected {
+					kind = theme.YellowString("unexpected")
+				}
+				return []string{a.URL, kind, fmt.Sprintf("%d", a.Clicks), fmt.Sprintf("%d", a.Impressions)}
+			}); err != nil {
+			return err
+		}
+		for _, p := range g.Problems {
+			theme.Yellow("  ⚠ %s", p)
+		}
+		theme.Println()
+	}
+
+	if flagged == 0 {
+		theme.Green("✓ All %d canonicals are indexed and take the traffic of their alternates", len(res.Groups))
+	} else {
+		theme.Red("✗ %d of %d canonicals need attention", flagged, len(res.Groups))
+		theme.HiBlack("ℹ Declare intended mobile, AMP and language alternates under url_inspection.expected_alternates.")
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// monitorSource tags the inspection records saved by `gsc monitor run`, so
+// --changes compares against the previous monitor run rather than a single
+// `gsc inspect url --save`.
+const monitorSource = "monitor"
+
+// errMonitorNewIssues signals that --fail-on-new-issue found new issues; the
+// command exits with diagcmd.ExitIssues instead of printing an error.
+var errMonitorNewIssues = errors.New("new indexing issues")
+
+// monitorChangesReport is the JSON output of `gsc monitor run --changes`.
+type monitorChangesReport struct {
+	// PreviousRun is when the baseline was saved; null on the first run.
+	PreviousRun *time.Time `json:"previous_run"`
+	*gsc.InspectionDiff
+	Patterns    []diagnostics.PatternHealth         `json:"patterns,omitempty"`
+	RichResults []diagnostics.RichResultTypeSummary `json:"rich_results,omitempty"`
+}
+
+// monitorRecords are inspectionRecords tagged with monitorSource.
+func monitorRecords(results []gsc.URLInspectionResult) []store.Record {
+	records := inspectionRecords(results)
+	for i := range records {
+		records[i].Dimensions["source"] = monitorSource
+	}
+	return records
+}
+
+// loadMonitorBaseline returns the last monitor run saved for site with its
+// time, or nil records when there is none.
+func loadMonitorBaseline(site string) ([]gsc.InspectionSnapshot, time.Time, error) {
+	dir := gscstate.ResolveStateDir(historyStateDir)
+	records, err := store.New(dir).LastRun(context.Background(), store.Query{
+		Kind:  store.KindInspection,
+		Site:  site,
+		Match: map[string]string{"source": monitorSource},
+	})
+	if err != nil || len(records) == 0 {
+		return nil, time.Time{}, err
+	}
+	return inspectionSnapshots(records), records[0].RecordedAt, nil
+}
+
+// inspectionSnapshots converts saved inspection records. Records saved
+// before issue types were recorded have no "issues" state.
+func inspectionSnapshots(records []store.Record) []gsc.InspectionSnapshot {
+	out := make([]gsc.InspectionSnapshot, 0, len(records))
+	for _, r := range records {
+		issues, known := r.State["issues"]
+		snap := gsc.InspectionSnapshot{
+			URL:           r.Dimensions["page"],
+			IndexStatus:   r.State["index_status"],
+			CoverageState: r.State["coverage_state"],
+			IssuesKnown:   known,
+		}
+		if issues != "" {
+			snap.Issues = strings.Split(issues, ",")
+		}
+		out = append(out, snap)
+	}
+	return out
+}
+
+// monitorChanges diffs results against the saved baseline. Without one,
+// nothing counts as changed: the first run only establishes the baseline.
+func monitorChanges(w io.Writer, site string, results []gsc.URLInspectionResult) (monitorChangesReport, error) {
+	current := make([]gsc.InspectionSnapshot, 0, len(results))
+	for _, r := range results {
+		current = append(current, gsc.SnapshotOf(r))
+	}
+
+	previous, at, err := loadMonitorBaseline(site)
+	if err != nil {
+		return monitorChangesReport{}, err
+	}
+	if previous == nil {
+		theme.Fprintf(w, "%s\n", theme.YellowString("⚠ No saved monitor run for %s: this run is the baseline.", site))
+		if !historySave {
+			theme.Fprintln(w, "   Pass --save to record it, so the next run can report changes.")
+		}
+		return monitorChangesReport{InspectionDiff: &gsc.InspectionDiff{
+			NewIssues: []gsc.InspectionTransition{},
+			Resolved:  []gsc.InspectionTransition{},
+			Unchanged: len(current),
+		}}, nil
+	}
+	return monitorChangesReport{PreviousRun: &at, InspectionDiff: gsc.CompareInspections(previous, current)}, nil
+}
+
+// monitorChangeRow is one line of the changes table.
+type monitorChangeRow struct {
+	change, url, before, after, issues string
+}
+
+func monitorChangeColumns() []string {
+	return []string{"Change", "URL", "Before", "After", "Issues"}
+}
+
+func monitorChangeTableRow(r monitorChangeRow) []string {
+	return []string{r.change, r.url, r.before, r.after, r.issues}
+}
+
+func monitorChangeRows(diff *gsc.InspectionDiff) []monitorChangeRow {
+	state := func(status, coverage string) string {
+		if status == "" {
+			return "-"
+		}
+		if coverage == "" {
+			return status
+		}
+		return fmt.Sprintf("%s (%s)", status, coverage)
+	}
+	var rows []monitorChangeRow
+	for _, t := range diff.NewIssues {
+		change := theme.RedString("new issue")
+		switch {
+		case t.FirstSeen:
+			change = theme.RedString("new URL")
+		case t.Deindexed:
+			change = theme.RedString("deindexed")
+		}
+		rows = append(rows, monitorChangeRow{change, t.URL,
+			state(t.PreviousStatus, t.PreviousCoverage), state(t.CurrentStatus, t.CurrentCoverage), joinIssues("+", t.Issues)})
+	}
+	for _, t := range diff.Resolved {
+		change := theme.GreenString("resolved")
+		if t.Reindexed {
+			change = theme.GreenString("reindexed")
+		}
+		rows = append(rows, monitorChangeRow{change, t.URL,
+			state(t.PreviousStatus, t.PreviousCoverage), state(t.CurrentStatus, t.CurrentCoverage), joinIssues("-", t.Issues)})
+	}
+	return rows
+}
+
+// joinIssues lists issue types, each marked with sign.
+func joinIssues(sign string, issues []string) string {
+	marked := make([]string, len(issues))
+	for i, issue := range issues {
+		marked[i] = sign + issue
+	}
+	return strings.Join(marked, ", ")
+}
+
+func displayMonitorChanges(w io.Writer, r monitorChangesReport) error {
+	title := "═══ Changes Since Previous Run ═══"
+	if r.PreviousRun != nil {
+		title = fmt.Sprintf("═══ Changes Since %s ═══", r.PreviousRun.Local().Format("2006-01-02 15:04"))
+	}
+	theme.Fprintln(w, theme.CyanString(title))
+	theme.Fprintf(w, "New issues: %d  Resolved: %d  Unchanged: %d\n",
+		len(r.NewIssues), len(r.Resolved), r.Unchanged)
+	if rows := monitorChangeRows(r.InspectionDiff); len(rows) > 0 {
+		opts := output.Options{Widths: map[string]int{"URL": 60, "Before": 40, "After": 40}}
+		if err := output.RenderWith(theme.NewWriter(w), output.FormatTable, opts, monitorChangeColumns(), rows, monitorChangeTableRow); err != nil {
+			return fmt.Errorf("failed to render changes table: %w", err)
+		}
+	} else {
+		theme.Fprintln(w, theme.GreenString("✓ No changes"))
+	}
+	theme.Fprintln(w)
+	return nil
+}
+
+func displayMarkdownMonitorChanges(w io.Writer, r monitorChangesReport) {
+	theme.Fprintln(w, "# URL Inspection Changes")
+	theme.Fprintln(w)
+	if r.PreviousRun != nil {
+		theme.Fprintf(w, "**Compared with**: run saved %s\n", r.PreviousRun.Local().Format("2006-01-02 15:04"))
+	} else {
+		theme.Fprintln(w, "**Compared with**: no saved run (this run is the baseline)")
+	}
+	theme.Fprintf(w, "**Unchanged URLs**: %d\n", r.Unchanged)
+	theme.Fprintln(w)
+	section := func(title string, rows []monitorChangeRow) {
+		theme.Fprintf(w, "## %s (%d)\n", title, len(rows))
+		theme.Fprintln(w)
+		for _, row := range rows {
+			theme.Fprintf(w, "- **%s** %s: %s → %s", output.StripANSI(row.change), row.url, row.before, row.after)
+			if row.issues != "" {
+				theme.Fprintf(w, " (%s)", row.issues)
+			}
+			theme.Fprintln(w)
+		}
+		theme.Fprintln(w)
+	}
+	rows := monitorChangeRows(r.InspectionDiff)
+	section("New Issues", rows[:len(r.NewIssues)])
+	section("Resolved", rows[len(r.NewIssues):])
+}
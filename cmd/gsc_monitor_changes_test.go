@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestMonitorChanges(t *testing.T) {
+	dir := t.TempDir()
+	historyStateDir = dir
+	t.Cleanup(func() { historyStateDir = "" })
+	site := "sc-domain:example.com"
+
+	yesterday := []gsc.URLInspectionResult{
+		{URL: "https://example.com/a", IndexStatus: "PASS", CoverageState: "Submitted and indexed"},
+		{URL: "https://example.com/b", IndexStatus: "FAIL", CoverageState: "Not found (404)",
+			IndexingIssues: []gsc.IndexingIssue{{Severity: "ERROR", IssueType: "NOT_FOUND"}}},
+	}
+	today := []gsc.URLInspectionResult{
+		{URL: "https://example.com/a", IndexStatus: "FAIL", CoverageState: "Crawled - currently not indexed"},
+		{URL: "https://example.com/b", IndexStatus: "PASS", CoverageState: "Submitted and indexed"},
+	}
+
+	var status bytes.Buffer
+	first, err := monitorChanges(&status, site, yesterday)
+	require.NoError(t, err)
+	assert.Nil(t, first.PreviousRun)
+	assert.Equal(t, 2, first.Unchanged)
+	assert.Contains(t, status.String(), "this run is the baseline")
+
+	st := store.New(dir)
+	require.NoError(t, st.Append(context.Background(), store.KindInspection, site, monitorRecords(yesterday)))
+	// A single `inspect url --save` afterwards is not a monitor baseline.
+	require.NoError(t, st.Append(context.Background(), store.KindInspection, site, inspectionRecords(today[:1])))
+
+	changes, err := monitorChanges(&status, site, today)
+	require.NoError(t, err)
+	require.NotNil(t, changes.PreviousRun)
+	require.Len(t, changes.NewIssues, 1)
+	assert.True(t, changes.NewIssues[0].Deindexed)
+	require.Len(t, changes.Resolved, 1)
+	assert.Equal(t, []string{"NOT_FOUND"}, changes.Resolved[0].Issues)
+
+	rows := monitorChangeRows(changes.InspectionDiff)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "-NOT_FOUND", rows[1].issues)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// patternDiscoveryDays is the Search Analytics window pattern URLs are
+// discovered from when no sitemap is configured.
+const patternDiscoveryDays = 28
+
+// monitorReport is the monitor's JSON output when url_inspection.patterns
+// is configured or --show-rich-results is set; otherwise the output stays the
+// bare results array.
+type monitorReport struct {
+	Results     []gsc.URLInspectionResult           `json:"results"`
+	Patterns    []diagnostics.PatternHealth         `json:"patterns,omitempty"`
+	RichResults []diagnostics.RichResultTypeSummary `json:"rich_results,omitempty"`
+}
+
+// urlPatterns converts the config patterns; a nil config has none.
+func urlPatterns(cfg *config.URLInspectionConfig) []diagnostics.URLPattern {
+	if cfg == nil {
+		return nil
+	}
+	out := make([]diagnostics.URLPattern, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		out = append(out, diagnostics.URLPattern{Pattern: p.Pattern, Description: p.Description, Sample: p.Sample})
+	}
+	return out
+}
+
+// discoverPatternURLs lists the site's URLs for pattern expansion: those of
+// the configured sitemaps, else the pages with impressions over the last
+// patternDiscoveryDays. A nil client (dry-run) skips the Search Analytics
+// fallback. The second result names the source.
+func discoverPatternURLs(client gsc.SearchAPI, sc *config.SearchConsoleConfig) ([]string, string, error) {
+	if sitemaps := sc.ActiveSitemaps(); len(sitemaps) > 0 {
+		validator := sitemap.NewValidator(30*time.Second, audit.DefaultUserAgent)
+		var urls []string
+		for _, sm := range sitemaps {
+			report, err := validator.Validate(context.Background(), sc.SiteURL, sm.URL)
+			if err != nil {
+				theme.Yellow("⚠ Skipping sitemap %s: %v", sm.URL, err)
+				continue
+			}
+			urls = append(urls, report.URLs...)
+		}
+		if len(urls) > 0 {
+			return urls, "sitemaps", nil
+		}
+	}
+	if client == nil {
+		return nil, "", nil
+	}
+
+	start, end := gsc.BuildDateRange(patternDiscoveryDays)
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    sc.SiteURL,
+		StartDate:  start,
+		EndDate:    end,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch pages for url_inspection.patterns: %w", err)
+	}
+	urls := make([]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		if len(row.Keys) > 0 {
+			urls = append(urls, row.Keys[0])
+		}
+	}
+	return urls, fmt.Sprintf("Search Analytics pages, last %d days", patternDiscoveryDays), nil
+}
+
+// mergeURLs appends extra to urls, skipping URLs already present.
+func mergeURLs(urls, extra []string) []string {
+	seen := make(map[string]bool, len(urls)+len(extra))
+	out := make([]string, 0, len(urls)+len(extra))
+	for _, u := range append(append([]string(nil), urls...), extra...) {
+		if !seen[u] {
+			seen[u] = true
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func patternHealthColumns() []string {
+	return []string{"Pattern", "Matched", "Inspected", "Indexed", "Not Indexed", "Issues", "Top Reason"}
+}
+
+func patternHealthTableRow(h diagnostics.PatternHealth) []string {
+	indexed := fmt.Sprintf("%d (%.0f%%)", h.Indexed, h.IndexedShare*100)
+	switch {
+	case h.Inspected == 0:
+		indexed = "-"
+	case h.Indexed == h.Inspected:
+		indexed = theme.GreenString("%s", indexed)
+	case h.IndexedShare < 0.5:
+		indexed = theme.RedString("%s", indexed)
+	default:
+		indexed = theme.YellowString("%s", indexed)
+	}
+	reason := "-"
+	if len(h.NotIndexedStates) > 0 {
+		reason = h.NotIndexedStates[0].State
+	}
+	return []string{
+		h.Pattern,
+		fmt.Sprintf("%d", h.Matched),
+		fmt.Sprintf("%d", h.Inspected),
+		indexed,
+		fmt.Sprintf("%d", h.NotIndexed),
+		fmt.Sprintf("%d", h.WithIssues),
+		reason,
+	}
+}
+
+func displayPatternHealth(health []diagnostics.PatternHealth) error {
+	theme.Cyan("═══ URL Pattern Health ═══")
+	theme.Println()
+	if err := output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, output.Options{Widths: map[string]int{"Top Reason": 40}}, patternHealthColumns(), health, patternHealthTableRow); err != nil {
+		return fmt.Errorf("failed to render pattern health table: %w", err)
+	}
+	for _, h := range health {
+		if h.Matched == 0 {
+			theme.Yellow("⚠ %s matched no discovered URLs", h.Pattern)
+		}
+	}
+	theme.Println()
+	return nil
+}
+
+func displayMarkdownPatternHealth(health []diagnostics.PatternHealth) {
+	theme.Println("## URL Patterns")
+	theme.Println()
+	for _, h := range health {
+		title := h.Pattern
+		if h.Description != "" {
+			title += " — " + h.Description
+		}
+		theme.Printf("### %s\n", title)
+		theme.Println()
+		theme.Printf("- **Matched URLs**: %d\n", h.Matched)
+		theme.Printf("- **Inspected**: %d\n", h.Inspected)
+		if h.Inspected > 0 {
+			theme.Printf("- **Indexed**: %d (%.0f%%)\n", h.Indexed, h.IndexedShare*100)
+			theme.Printf("- **Partially Indexed**: %d\n", h.Partial)
+			theme.Printf("- **Not Indexed**: %d\n", h.NotIndexed)
+			theme.Printf("- **With Issues**: %d\n", h.WithIssues)
+		}
+		for _, s := range h.NotIndexedStates {
+			theme.Printf("  - %s: %d\n", s.State, s.URLs)
+		}
+		theme.Println()
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// richResultVerdict colours a rich results verdict.
+func richResultVerdict(status string) string {
+	switch status {
+	case "PASS":
+		return theme.GreenString("✓ Valid")
+	case "FAIL":
+		return theme.RedString("✗ Invalid")
+	case "PARTIAL":
+		return theme.YellowString("⚠ Valid with warnings")
+	default:
+		return status
+	}
+}
+
+// richResultIssueLabel prefixes an item issue with its severity.
+func richResultIssueLabel(issue gsc.RichResultIssue) string {
+	if issue.Severity == "ERROR" {
+		return theme.RedString("[ERROR]") + " " + issue.Message
+	}
+	return theme.YellowString("[%s]", issue.Severity) + " " + issue.Message
+}
+
+func richResultTypeColumns() []string {
+	return []string{"Type", "URLs", "Failing URLs", "Items", "Errors", "Warnings", "Top Issue"}
+}
+
+func richResultTypeTableRow(s diagnostics.RichResultTypeSummary) []string {
+	errs := fmt.Sprintf("%d", s.ItemsWithErrors)
+	if s.ItemsWithErrors > 0 {
+		errs = theme.RedString("%s", errs)
+	}
+	warnings := fmt.Sprintf("%d", s.ItemsWithWarnings)
+	if s.ItemsWithWarnings > 0 {
+		warnings = theme.YellowString("%s", warnings)
+	}
+	top := "-"
+	if len(s.Issues) > 0 {
+		top = fmt.Sprintf("%s (%d)", s.Issues[0].Message, s.Issues[0].Items)
+	}
+	return []string{
+		s.Type,
+		fmt.Sprintf("%d", s.URLs),
+		fmt.Sprintf("%d", s.FailingURLs),
+		fmt.Sprintf("%d", s.Items),
+		errs,
+		warnings,
+		top,
+	}
+}
+
+// displayRichResultsDeepDive lists each URL's detected structured-data items
+// with their issues, then the per-type aggregate.
+func displayRichResultsDeepDive(results []gsc.URLInspectionResult, summary []diagnostics.RichResultTypeSummary) error {
+	theme.Cyan("═══ Rich Results ═══")
+	theme.Println()
+	without := 0
+	for _, r := range results {
+		if r.RichResultsStatus == "" && len(r.RichResultItems) == 0 {
+			without++
+			continue
+		}
+		theme.Printf("%s  %s\n", r.URL, richResultVerdict(r.RichResultsStatus))
+		for _, item := range r.RichResultItems {
+			label := item.Type
+			if item.Name != "" {
+				label += " - " + item.Name
+			}
+			theme.Printf("  %s\n", label)
+			for _, issue := range diagnostics.ItemIssues(item) {
+				theme.Printf("    %s\n", richResultIssueLabel(issue))
+			}
+		}
+	}
+	if without > 0 {
+		theme.Printf("URLs without detected structured data: %d\n", without)
+	}
+	theme.Println()
+
+	if len(summary) == 0 {
+		return nil
+	}
+	theme.Cyan("═══ Structured Data Types (most failing first) ═══")
+	theme.Println()
+	if err := output.RenderWith(theme.NewWriter(os.Stdout), output.FormatTable, output.Options{Widths: map[string]int{"Top Issue": 50}}, richResultTypeColumns(), summary, richResultTypeTableRow); err != nil {
+		return fmt.Errorf("failed to render rich results table: %w", err)
+	}
+	theme.Println()
+	return nil
+}
+
+func displayMarkdownRichResults(results []gsc.URLInspectionResult, summary []diagnostics.RichResultTypeSummary) {
+	theme.Println("## Rich Results")
+	theme.Println()
+	if len(summary) > 0 {
+		theme.Println("| Type | URLs | Failing URLs | Items | Errors | Warnings |")
+		theme.Println("|------|------|--------------|-------|--------|----------|")
+		for _, s := range summary {
+			theme.Printf("| %s | %d | %d | %d | %d | %d |\n", s.Type, s.URLs, s.FailingURLs, s.Items, s.ItemsWithErrors, s.ItemsWithWarnings)
+		}
+		theme.Println()
+	}
+	for _, r := range results {
+		if len(r.RichResultItems) == 0 {
+			continue
+		}
+		theme.Printf("### %s (%s)\n", r.URL, r.RichResultsStatus)
+		theme.Println()
+		for _, item := range r.RichResultItems {
+			label := item.Type
+			if item.Name != "" {
+				label += " - " + item.Name
+			}
+			theme.Printf("- **%s**\n", label)
+			for _, issue := range diagnostics.ItemIssues(item) {
+				theme.Printf("  - [%s] %s\n", issue.Severity, issue.Message)
+			}
+		}
+		theme.Println()
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/gscfake"
+)
+
+// withMonitorFake points `gsc monitor run` at fake and at a config listing
+// urls, and resets the command's flags afterwards.
+func withMonitorFake(t *testing.T, fake *gscfake.SearchConsole, urls []string) {
+	t.Helper()
+	saved := gscMonitorClientFactory
+	gscMonitorClientFactory = func() (gsc.SearchConsole, func(), error) { return fake, func() {}, nil }
+	gscMonitorConfig = writeHealthConfig(t, "sc-domain:example.com", urls)
+	historyStateDir = t.TempDir()
+	t.Cleanup(func() {
+		gscMonitorClientFactory = saved
+		gscMonitorConfig, gscMonitorFormat = "", ""
+		gscMonitorChanges, gscMonitorFailOnNewIssue, gscMonitorRichResults = false, false, false
+		historySave, historyStateDir = false, ""
+	})
+}
+
+// captureMonitorStdout runs `gsc monitor run` and returns what it printed to
+// stdout.
+func captureMonitorStdout(t *testing.T) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := runGSCMonitor(gscMonitorRunCmd, nil)
+	os.Stdout = stdout
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out), runErr
+}
+
+// decodeMonitorJSON decodes the JSON document in out. The progress line
+// before it and the summary after it are printed to stdout too, which the MCP
+// tool relies on.
+func decodeMonitorJSON(t *testing.T, out string, v any) {
+	t.Helper()
+	start := strings.IndexAny(out, "[{")
+	require.GreaterOrEqual(t, start, 0, out)
+	require.NoError(t, json.NewDecoder(strings.NewReader(out[start:])).Decode(v), out)
+}
+
+func TestRunGSCMonitor_JSON(t *testing.T) {
+	fake := &gscfake.SearchConsole{Inspections: map[string]gsc.URLInspectionResult{
+		"https://example.com/b": {IndexStatus: "FAIL", CoverageState: "Not found (404)"},
+	}}
+	withMonitorFake(t, fake, []string{"https://example.com/a", "https://example.com/b"})
+	gscMonitorFormat = "json"
+
+	out, err := captureMonitorStdout(t)
+
+	require.NoError(t, err)
+	var results []gsc.URLInspectionResult
+	decodeMonitorJSON(t, out, &results)
+	require.Len(t, results, 2)
+	assert.Equal(t, "PASS", results[0].IndexStatus)
+	assert.Equal(t, "Not found (404)", results[1].CoverageState)
+	assert.Equal(t, []string{"https://example.com/a", "https://example.com/b"}, fake.Called("InspectURL"))
+	assert.Equal(t, 2, fake.QuotaUsed)
+}
+
+func TestRunGSCMonitor_RefusesRunOverQuota(t *testing.T) {
+	fake := &gscfake.SearchConsole{QuotaUsed: 9, QuotaLimit: 10}
+	withMonitorFake(t, fake, []string{"https://example.com/a", "https://example.com/b"})
+	gscMonitorFormat = "json"
+
+	_, err := captureMonitorStdout(t)
+
+	require.ErrorIs(t, err, gsc.ErrQuotaExceeded)
+	assert.Empty(t, fake.Called("InspectURL"))
+}
+
+func TestRunGSCMonitor_FailOnNewIssue(t *testing.T) {
+	fake := &gscfake.SearchConsole{}
+	withMonitorFake(t, fake, []string{"https://example.com/a", "https://example.com/b"})
+	gscMonitorFormat = "json"
+	gscMonitorFailOnNewIssue = true
+	historySave = true
+
+	// The first saved run is the baseline.
+	_, err := captureMonitorStdout(t)
+	require.NoError(t, err)
+
+	fake.Inspections = map[string]gsc.URLInspectionResult{
+		"https://example.com/a": {IndexStatus: "FAIL", CoverageState: "Crawled - currently not indexed"},
+	}
+	out, err := captureMonitorStdout(t)
+
+	require.ErrorIs(t, err, errMonitorNewIssues)
+	var changes monitorChangesReport
+	decodeMonitorJSON(t, out, &changes)
+	require.Len(t, changes.NewIssues, 1)
+	assert.Equal(t, "https://example.com/a", changes.NewIssues[0].URL)
+	assert.True(t, changes.NewIssues[0].Deindexed)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/garbarok/ga4-manager/internal/gsc"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
 	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+	"github.com/garbarok/ga4-manager/internal/output"
 )
 
 const (
@@ -61,8 +62,10 @@ Stateless: one Search Analytics API call per run. No state files written.
 
 Exit codes:
   0  no opportunities detected
-  2  at least one opportunity detected
-  1  command failed (API error, malformed config, etc.)
+  4  at least one opportunity detected
+  1  command failed: bad flags or config
+  2  Google API error
+  3  quota exhausted
 
 Examples:
   ga4 gsc opportunities --config configs/mysite.yaml
@@ -75,7 +78,7 @@ Examples:
 func init() {
 	gscCmd.AddCommand(gscOpportunitiesCmd)
 	gscOpportunitiesCmd.Flags().StringVarP(&gscOpportunitiesConfig, "config", "c", "", "Path to configuration file (required)")
-	gscOpportunitiesCmd.Flags().StringVar(&gscOpportunitiesFormat, "format", diagcmd.FormatTable, "Output format: table or json")
+	output.FormatVar(gscOpportunitiesCmd.Flags(), &gscOpportunitiesFormat, "", output.FormatTable, output.FormatJSON)
 	gscOpportunitiesCmd.Flags().IntVar(&gscOpportunitiesDays, "days", opportunitiesDaysDefault, "Lookback window in days (1–485)")
 	gscOpportunitiesCmd.Flags().Int64Var(&gscOpportunitiesMinImpressions, "min-impressions", 5, "Minimum impressions for a query to be considered (drops noise; default 5 — small sites need a low floor)")
 	gscOpportunitiesCmd.Flags().Int64Var(&gscOpportunitiesMinPotentialClick, "min-potential-clicks", 1, "Drop opportunities below this projected click gain (default 1 — suppresses 0-click rounding-error findings)")
@@ -83,7 +86,7 @@ func init() {
 
 // gscOpportunitiesClientFactory returns a live GSC client. Tests substitute.
 var gscOpportunitiesClientFactory = func() (gsc.SearchAPI, func(), error) {
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
 		return nil, func() {}, err
 	}
@@ -111,7 +114,7 @@ type OpportunityResultRow struct {
 // OpportunitiesOutput is the JSON envelope under --format json.
 type OpportunitiesOutput = diagcmd.Envelope[OpportunityResultRow]
 
-func opportunitiesRunE(_ *cobra.Command, _ []string) error {
+func opportunitiesRunE(cmd *cobra.Command, _ []string) error {
 	status := runOpportunitiesCommand(opportunitiesParams{
 		ConfigPath:         gscOpportunitiesConfig,
 		Format:             gscOpportunitiesFormat,
@@ -123,8 +126,7 @@ func opportunitiesRunE(_ *cobra.Command, _ []string) error {
 		Stderr:             os.Stderr,
 		Now:                time.Now().UTC(),
 	})
-	os.Exit(status)
-	return nil
+	return exitWith(cmd, status)
 }
 
 type opportunitiesParams struct {
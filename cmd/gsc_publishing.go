@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/publishing"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+const publishingSinceMax = 485 // GSC retains roughly 16 months of search-analytics data
+
+var (
+	gscPublishingSite     string
+	gscPublishingConfig   string
+	gscPublishingSitemaps []string
+	gscPublishingCSV      string
+	gscPublishingSince    int
+	gscPublishingDepth    int
+	gscPublishingLimit    int
+	gscPublishingFormat   string
+)
+
+var gscPublishingCmd = &cobra.Command{
+	Use:   "publishing",
+	Short: "Track how fast newly published pages get impressions and clicks",
+	Long: `Report the search impact of recently published pages: the first day each
+got an impression and a click, and its clicks and impressions over its first
+28 days. The headline KPI is the median time to index per page group — days
+from publication to first impression, since Search Console does not expose
+the indexing date itself.
+
+Published dates come from --csv (url,published rows, YYYY-MM-DD) or, without
+it, from the <lastmod> of sitemap URLs: --sitemap, else
+search_console.sitemaps in --config, else the sitemaps submitted for the site.
+lastmod is the last modification, so edits to older pages reset their date;
+prefer a CSV exported from the CMS when one is available.
+
+Pages published in the last --since days are reported. Run it weekly (from
+cron or CI) with --format json to track the pipeline over time.
+
+Examples:
+  ga4 gsc publishing --config configs/mysite.yaml
+  ga4 gsc publishing --site sc-domain:example.com --csv published.csv --since 30
+  ga4 gsc publishing --config configs/mysite.yaml --depth 2 --format json`,
+	RunE: runGSCPublishing,
+}
+
+func init() {
+	gscCmd.AddCommand(gscPublishingCmd)
+	gscPublishingCmd.Flags().StringVarP(&gscPublishingSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	gscPublishingCmd.Flags().StringVarP(&gscPublishingConfig, "config", "c", "", "Read the site and sitemaps from this config")
+	gscPublishingCmd.Flags().StringArrayVarP(&gscPublishingSitemaps, "sitemap", "u", nil, "Sitemap URL to read lastmod from (repeatable)")
+	gscPublishingCmd.Flags().StringVar(&gscPublishingCSV, "csv", "", "CSV of url,published rows (overrides sitemaps)")
+	gscPublishingCmd.Flags().IntVar(&gscPublishingSince, "since", 90, "Report pages published in the last N days")
+	gscPublishingCmd.Flags().IntVar(&gscPublishingDepth, "depth", 1, "Path segments per group")
+	gscPublishingCmd.Flags().IntVarP(&gscPublishingLimit, "limit", "l", 25, "Pages to list (0 = all)")
+	output.FormatVar(gscPublishingCmd.Flags(), &gscPublishingFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+// publishingResult is the `gsc publishing` output.
+type publishingResult struct {
+	Site      string `json:"site"`
+	Source    string `json:"source"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	publishing.Report
+	// Truncated is set when page data hit the row limit, so some pages may
+	// be missing traffic.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func runGSCPublishing(cmd *cobra.Command, args []string) error {
+	if gscPublishingSince < 1 || gscPublishingSince > publishingSinceMax {
+		return fmt.Errorf("--since must be between 1 and %d days, got %d", publishingSinceMax, gscPublishingSince)
+	}
+	if gscPublishingDepth < 1 {
+		return fmt.Errorf("--depth must be at least 1, got %d", gscPublishingDepth)
+	}
+	site, err := siteFromFlags(gscPublishingSite, gscPublishingConfig)
+	if err != nil {
+		return err
+	}
+	progress := func(format string, args ...any) {
+		if gscPublishingFormat == "table" {
+			theme.Fprintf(os.Stderr, format, args...)
+		}
+	}
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	result := publishingResult{Site: site}
+	var all []publishing.Page
+	if gscPublishingCSV != "" {
+		result.Source = gscPublishingCSV
+		all, err = readPublishingCSV(gscPublishingCSV)
+	} else {
+		var sitemaps []string
+		sitemaps, err = publishingSitemaps(client, site)
+		if err == nil {
+			result.Source = fmt.Sprintf("lastmod of %d sitemap(s)", len(sitemaps))
+			all, err = pagesFromSitemaps(site, sitemaps, progress)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Search Analytics lags a day or two; the window ends yesterday.
+	end := time.Now().AddDate(0, 0, -1)
+	pages := publishing.Since(all, end.AddDate(0, 0, -gscPublishingSince))
+	if len(pages) == 0 {
+		return fmt.Errorf("no pages published in the last %d days (of %d with a date)", gscPublishingSince, len(all))
+	}
+	result.StartDate, result.EndDate = gsc.BuildDateRangeExact(pages[len(pages)-1].Published, end)
+
+	progress("Fetching daily page data for %d pages (%s to %s)...\n", len(pages), result.StartDate, result.EndDate)
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    site,
+		StartDate:  result.StartDate,
+		EndDate:    result.EndDate,
+		Dimensions: []string{"page", "date"},
+		RowLimit:   gsc.MaxRowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch page data: %w", err)
+	}
+	result.Truncated = len(report.Rows) >= gsc.MaxRowLimit
+	result.Report = publishing.Measure(pages, dailyPageTraffic(report.Rows, pages), end, gscPublishingDepth)
+
+	if gscPublishingFormat == "json" {
+		return output.JSON(os.Stdout, result)
+	}
+	return displayPublishing(result)
+}
+
+func readPublishingCSV(path string) ([]publishing.Page, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	pages, err := publishing.ReadCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pages, nil
+}
+
+// publishingSitemaps resolves the sitemap list: --sitemap, then the config,
+// then whatever is submitted to Search Console.
+func publishingSitemaps(client *gsc.Client, site string) ([]string, error) {
+	if len(gscPublishingSitemaps) > 0 {
+		return gscPublishingSitemaps, nil
+	}
+	var out []string
+	if gscPublishingConfig != "" {
+		cfg, err := config.LoadConfig(gscPublishingConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		if cfg.SearchConsole != nil {
+			for _, sm := range cfg.SearchConsole.ActiveSitemaps() {
+				out = append(out, sm.URL)
+			}
+		}
+	}
+	if len(out) == 0 {
+		submitted, err := client.ListSitemaps(site)
+		if err != nil {
+			return nil, err
+		}
+		for _, sm := range submitted {
+			out = append(out, sm.Path)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no sitemaps for %s: pass --csv or --sitemap, or submit one", site)
+	}
+	return out, nil
+}
+
+// pagesFromSitemaps returns the sitemap URLs that have a valid lastmod. A
+// URL in several sitemaps keeps its latest date.
+func pagesFromSitemaps(site string, sitemaps []string, progress func(string, ...any)) ([]publishing.Page, error) {
+	validator := sitemap.NewValidator(30*time.Second, audit.DefaultUserAgent)
+	latest := make(map[string]time.Time)
+	var order []string
+	for _, sm := range sitemaps {
+		progress("Fetching %s...\n", sm)
+		report, err := validator.Validate(context.Background(), site, sm)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range report.URLs {
+			t, ok := report.LastMods[u]
+			if !ok {
+				continue
+			}
+			prev, seen := latest[u]
+			if !seen {
+				order = append(order, u)
+			}
+			if !seen || t.After(prev) {
+				latest[u] = t
+			}
+		}
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no sitemap URL has a <lastmod>; pass --csv with published dates")
+	}
+	pages := make([]publishing.Page, 0, len(order))
+	for _, u := range order {
+		pages = append(pages, publishing.Page{URL: u, Published: latest[u]})
+	}
+	return pages, nil
+}
+
+// dailyPageTraffic groups page,date rows by page, keeping only the pages
+// being measured.
+func dailyPageTraffic(rows []gsc.SearchAnalyticsRow, pages []publishing.Page) map[string][]publishing.Day {
+	wanted := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		wanted[p.URL] = true
+	}
+	out := make(map[string][]publishing.Day)
+	for _, row := range rows {
+		if len(row.Keys) < 2 || !wanted[row.Keys[0]] {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", row.Keys[1])
+		if err != nil {
+			continue
+		}
+		out[row.Keys[0]] = append(out[row.Keys[0]], publishing.Day{Date: date, Clicks: row.Clicks, Impressions: row.Impressions})
+	}
+	return out
+}
+
+func displayPublishing(r publishingResult) error {
+	theme.Cyan("═══ Publishing impact: %s ═══", r.Site)
+	theme.Printf("Source: %s, %s to %s\n", r.Source, r.StartDate, r.EndDate)
+	theme.Println()
+
+	o := r.Overall
+	theme.Printf("Pages published: %d, with impressions: %d, with clicks: %d\n", o.Pages, o.Indexed, o.Clicked)
+	theme.Printf("Median time to index: %s, to first click: %s\n", formatMedianDays(o.MedianDaysToIndex), formatMedianDays(o.MedianDaysToClick))
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Group", "Pages", "Indexed", "Clicked", "Median Days to Index", "Median Days to Click", "Clicks 28d", "Impr. 28d"},
+		r.Groups, func(g publishing.GroupSummary) []string {
+			return []string{g.Name, fmt.Sprintf("%d", g.Pages), fmt.Sprintf("%d", g.Indexed), fmt.Sprintf("%d", g.Clicked),
+				formatMedianDays(g.MedianDaysToIndex), formatMedianDays(g.MedianDaysToClick),
+				fmt.Sprintf("%d", g.Clicks), fmt.Sprintf("%d", g.Impressions)}
+		}); err != nil {
+		return err
+	}
+
+	pages := r.Pages
+	if gscPublishingLimit > 0 && len(pages) > gscPublishingLimit {
+		pages = pages[:gscPublishingLimit]
+	}
+	theme.Println()
+	theme.Cyan("Newest %d of %d pages:", len(pages), len(r.Pages))
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"URL", "Published", "First Impression", "First Click", "Clicks 28d", "Impr. 28d"},
+		pages, func(p publishing.Impact) []string {
+			first := p.FirstImpression
+			if first == "" {
+				first = theme.YellowString("%s", "pending")
+			}
+			clicks := fmt.Sprintf("%d", p.Clicks)
+			impressions := fmt.Sprintf("%d", p.Impressions)
+			if !p.Complete {
+				clicks, impressions = clicks+"*", impressions+"*"
+			}
+			return []string{p.URL, p.Published, first, p.FirstClick, clicks, impressions}
+		}); err != nil {
+		return err
+	}
+
+	theme.Println()
+	theme.HiBlack("* window still open: fewer than 28 days of data since publication.")
+	if r.Truncated {
+		theme.Yellow("⚠ Page data hit the %d-row limit; some pages may show no impressions. Use a shorter --since.", gsc.MaxRowLimit)
+	}
+	return nil
+}
+
+func formatMedianDays(m *float64) string {
+	if m == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *m)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/publishing"
+)
+
+func TestDailyPageTraffic(t *testing.T) {
+	rows := []gsc.SearchAnalyticsRow{
+		{Keys: []string{"https://example.com/new", "2026-05-02"}, Clicks: 1, Impressions: 7},
+		{Keys: []string{"https://example.com/new", "2026-05-03"}, Impressions: 3},
+		{Keys: []string{"https://example.com/old", "2026-05-02"}, Clicks: 40, Impressions: 900},
+		{Keys: []string{"https://example.com/new", "not a date"}, Impressions: 1},
+	}
+	pages := []publishing.Page{{URL: "https://example.com/new"}}
+
+	got := dailyPageTraffic(rows, pages)
+	require.Len(t, got, 1)
+	assert.Equal(t, []publishing.Day{
+		{Date: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), Clicks: 1, Impressions: 7},
+		{Date: time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC), Impressions: 3},
+	}, got["https://example.com/new"])
+}
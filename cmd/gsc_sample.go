@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// samplePlanCommand is the state-store key the persisted plan lives under.
+const samplePlanCommand = "sample-plan"
+
+var (
+	gscSampleSite   string
+	gscSampleConfig string
+	gscSampleFormat string
+
+	gscSampleBudget      int
+	gscSampleDays        int
+	gscSampleDepth       int
+	gscSampleMinPerGroup int
+	gscSampleMetric      string
+	gscSampleFresh       bool
+)
+
+var gscSampleCmd = &cobra.Command{
+	Use:   "sample",
+	Short: "Traffic-weighted URL sampling for sites too large to inspect in full",
+	Long: `Plan and run a stable, traffic-weighted sample of URLs for sites with more
+pages than the URL Inspection quota (2,000 per property per day) can cover.
+
+plan   fetches every page with search traffic, groups pages by path prefix
+       (--depth), splits --budget across groups in proportion to their
+       impressions (or clicks), and saves the sample to the state directory.
+       Re-planning keeps previously sampled URLs where they are still eligible,
+       so history and trends follow the same pages.
+
+run    inspects the saved sample and estimates the share of all pages that are
+       indexed, per group and site-wide, with a 95% margin of error.
+
+Both print the API calls they will make first, and refuse to start when that
+is more than the quota left today.
+
+Confidence: each group's margin is the 95% half-width for a proportion with
+finite-population correction; the site-wide margin combines groups weighted by
+page count. At plan time the margin is worst case (50% indexed); at run time it
+uses the observed share. Pages Search Console reports no traffic for are
+outside the sampling frame and not covered by the estimate.
+
+Examples:
+  # Plan 500 inspections across top-level directories
+  ga4 gsc sample plan --config configs/mysite.yaml --budget 500
+
+  # Inspect the saved sample and estimate coverage
+  ga4 gsc sample run --config configs/mysite.yaml --save`,
+}
+
+var gscSamplePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Build and save a traffic-weighted URL sample",
+	RunE:  runGSCSamplePlan,
+}
+
+var gscSampleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Inspect the saved sample and estimate site-wide coverage",
+	RunE:  runGSCSampleRun,
+}
+
+func init() {
+	gscCmd.AddCommand(gscSampleCmd)
+	gscSampleCmd.AddCommand(gscSamplePlanCmd)
+	gscSampleCmd.AddCommand(gscSampleRunCmd)
+
+	for _, c := range []*cobra.Command{gscSamplePlanCmd, gscSampleRunCmd} {
+		c.Flags().StringVarP(&gscSampleSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+		c.Flags().StringVarP(&gscSampleConfig, "config", "c", "", "Read the site from search_console.site_url in this config")
+		output.FormatVar(c.Flags(), &gscSampleFormat, "f", output.FormatTable, output.FormatJSON)
+	}
+	gscSamplePlanCmd.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+
+	gscSamplePlanCmd.Flags().IntVar(&gscSampleBudget, "budget", sampling.DefaultBudget, "URLs to inspect per run")
+	gscSamplePlanCmd.Flags().IntVarP(&gscSampleDays, "days", "d", 28, "Traffic window used for allocation")
+	gscSamplePlanCmd.Flags().IntVar(&gscSampleDepth, "depth", sampling.DefaultDepth, "Path segments that form a group (1: /blog/, 2: /blog/2024/)")
+	gscSamplePlanCmd.Flags().IntVar(&gscSampleMinPerGroup, "min-per-group", sampling.DefaultMinPerGroup, "Minimum URLs per group before proportional allocation")
+	gscSamplePlanCmd.Flags().StringVar(&gscSampleMetric, "metric", sampling.MetricImpressions, "Traffic measure for allocation: impressions or clicks")
+	gscSamplePlanCmd.Flags().BoolVar(&gscSampleFresh, "fresh", false, "Ignore the saved sample and draw a new one")
+
+	addSaveFlags(gscSampleRunCmd)
+}
+
+func runGSCSamplePlan(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(gscSampleSite, gscSampleConfig)
+	if err != nil {
+		return err
+	}
+	if gscSampleBudget <= 0 {
+		return fmt.Errorf("--budget must be positive, got %d", gscSampleBudget)
+	}
+	if err := gsc.ValidateCoverageParams(site, gscSampleDays, "all"); err != nil {
+		return err
+	}
+
+	stateStore := gscstate.NewStore(gscstate.ResolveStateDir(historyStateDir))
+	var previous *sampling.Plan
+	if !gscSampleFresh {
+		previous, err = loadSamplePlan(stateStore, site)
+		if err != nil && !errors.Is(err, gscstate.ErrSnapshotMissing) {
+			return err
+		}
+	}
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	startDate, endDate := gsc.BuildDateRange(gscSampleDays)
+	query := &gsc.SearchAnalyticsQuery{
+		SiteURL:    site,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	}
+	if err := checkQuotaEstimate(os.Stderr, client.Estimate(gsc.SearchAnalyticsStep("Fetch page traffic", query))); err != nil {
+		return err
+	}
+	report, err := client.QuerySearchAnalytics(query)
+	if err != nil {
+		return fmt.Errorf("failed to fetch page traffic: %w", err)
+	}
+
+	pages := make([]sampling.Page, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		if len(row.Keys) == 0 {
+			continue
+		}
+		pages = append(pages, sampling.Page{URL: row.Keys[0], Clicks: row.Clicks, Impressions: row.Impressions})
+	}
+
+	plan, err := sampling.Build(site, pages, sampling.Options{
+		Budget:      gscSampleBudget,
+		Depth:       gscSampleDepth,
+		MinPerGroup: gscSampleMinPerGroup,
+		Metric:      gscSampleMetric,
+	}, previous)
+	if err != nil {
+		return err
+	}
+	plan.StartDate, plan.EndDate = startDate, endDate
+
+	payload, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("marshal sample plan: %w", err)
+	}
+	if err := stateStore.Write(context.Background(), samplePlanCommand, site, payload); err != nil {
+		return fmt.Errorf("failed to save sample plan: %w", err)
+	}
+
+	if gscSampleFormat == "json" {
+		return output.JSON(os.Stdout, plan)
+	}
+	return displaySamplePlan(plan, len(pages) >= gsc.MaxRowLimit)
+}
+
+func runGSCSampleRun(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(gscSampleSite, gscSampleConfig)
+	if err != nil {
+		return err
+	}
+	plan, err := loadSamplePlan(gscstate.NewStore(gscstate.ResolveStateDir(historyStateDir)), site)
+	if errors.Is(err, gscstate.ErrSnapshotMissing) {
+		return fmt.Errorf("no saved sample for %s: run `ga4 gsc sample plan` first", site)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	urls := plan.URLs()
+	quota := client.Estimate(gsc.InspectionStep("Inspect sampled URLs", len(urls)))
+	if err := checkQuotaEstimate(os.Stderr, quota); err != nil {
+		return fmt.Errorf("%w; re-plan with --budget %d or lower", err, quota.Remaining)
+	}
+
+	theme.Fprintf(os.Stderr, "Inspecting %d sampled URLs for %s...\n", len(urls), site)
+	results, err := client.InspectMultipleURLs(site, urls)
+	if err != nil {
+		return err
+	}
+	if err := saveHistory(store.KindInspection, site, inspectionRecords(results)); err != nil {
+		return err
+	}
+
+	indexed := make(map[string]bool, len(results))
+	for _, r := range results {
+		indexed[r.URL] = r.IndexStatus == "PASS"
+	}
+	estimate := sampling.Estimate(plan, indexed)
+
+	if gscSampleFormat == "json" {
+		return output.JSON(os.Stdout, estimate)
+	}
+	return displaySampleEstimate(plan, estimate)
+}
+
+// loadSamplePlan reads the saved plan; a missing plan is returned as
+// gscstate.ErrSnapshotMissing for the caller to decide on.
+func loadSamplePlan(s *gscstate.Store, site string) (*sampling.Plan, error) {
+	snap, err := s.Read(context.Background(), samplePlanCommand, site)
+	if err != nil {
+		if errors.Is(err, gscstate.ErrSnapshotMissing) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("read sample plan: %w", err)
+	}
+	var plan sampling.Plan
+	if err := json.Unmarshal(snap.Data, &plan); err != nil {
+		return nil, fmt.Errorf("parse sample plan: %w", err)
+	}
+	return &plan, nil
+}
+
+func displaySamplePlan(plan *sampling.Plan, truncated bool) error {
+	theme.Cyan("═══ Sample Plan: %s ═══", plan.Site)
+	theme.Println()
+	theme.Printf("Pages with traffic (%s to %s): %d\n", plan.StartDate, plan.EndDate, plan.TotalPages)
+	theme.Printf("Sampled: %d of %d budget, %d kept from the previous plan\n", plan.SampledURLs, plan.Budget, plan.Retained)
+	theme.Printf("Site-wide margin of error: ±%.1f%% (95%%, worst case)\n", plan.MarginOfError*100)
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Group", "Pages", "Traffic Share", "Sampled", "± (95%)"},
+		plan.Groups, func(g sampling.Group) []string {
+			return []string{
+				g.Name,
+				fmt.Sprintf("%d", g.Pages),
+				fmt.Sprintf("%.1f%%", g.TrafficShare*100),
+				fmt.Sprintf("%d", g.Allocated),
+				fmt.Sprintf("%.1f%%", g.MarginOfError*100),
+			}
+		}); err != nil {
+		return err
+	}
+	theme.Println()
+
+	if plan.TotalPages <= sampling.LargeSiteThreshold && plan.SampledURLs < plan.TotalPages {
+		theme.HiBlack("ℹ %d pages is within one Search Analytics page; a full inspection over several days may be preferable to sampling.", plan.TotalPages)
+	}
+	if truncated {
+		theme.Yellow("⚠ Page list hit the %d-row fetch limit; pages beyond it are outside the sample.", gsc.MaxRowLimit)
+	}
+	theme.Green("✓ Sample saved. Run `ga4 gsc sample run` to inspect it.")
+	return nil
+}
+
+func displaySampleEstimate(plan *sampling.Plan, est sampling.SiteEstimate) error {
+	theme.Cyan("═══ Estimated Index Coverage: %s ═══", plan.Site)
+	theme.Println()
+	theme.Printf("Indexed: %.1f%% ±%.1f%% of %d pages (≈%d pages, 95%% confidence)\n",
+		est.Share*100, est.MarginOfError*100, est.TotalPages, est.EstimatedPages)
+	theme.Println()
+
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Group", "Pages", "Inspected", "Indexed", "Share", "± (95%)"},
+		est.Groups, func(g sampling.GroupEstimate) []string {
+			share := fmt.Sprintf("%.1f%%", g.Share*100)
+			switch {
+			case g.Inspected == 0:
+				share = theme.HiBlackString("n/a")
+			case g.Share < 0.8:
+				share = theme.RedString("%s", share)
+			}
+			return []string{
+				g.Name,
+				fmt.Sprintf("%d", g.Pages),
+				fmt.Sprintf("%d", g.Inspected),
+				fmt.Sprintf("%d", g.Matched),
+				share,
+				fmt.Sprintf("%.1f%%", g.MarginOfError*100),
+			}
+		})
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gscSitemapAuditConfig  string
+	gscSitemapAuditURLs    []string
+	gscSitemapAuditDays    int
+	gscSitemapAuditDepth   int
+	gscSitemapAuditInspect int
+	gscSitemapAuditFormat  string
+)
+
+var gscSitemapsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Measure how many sitemap URLs actually appear in search",
+	Long: `Parse sitemaps and check every URL against Search Analytics page data to
+report the share of submitted URLs that get impressions — the "submitted vs
+indexed" gap Search Console only shows in its UI.
+
+Sitemaps come from --url, else search_console.sitemaps in --config, else the
+sitemaps submitted for --site in Search Console.
+
+A URL with impressions is indexed. A URL without may be unindexed or simply
+not ranking; --inspect N runs URL inspection on N of those (spread across the
+site) to tell the two apart. Inspections count against the daily quota.
+
+Examples:
+  ga4 gsc sitemaps audit --site sc-domain:example.com --config configs/mysite.yaml
+  ga4 gsc sitemaps audit --site sc-domain:example.com --days 90 --depth 2
+  ga4 gsc sitemaps audit --site sc-domain:example.com --inspect 50 --format json`,
+	RunE: runGSCSitemapsAudit,
+}
+
+func init() {
+	gscSitemapsCmd.AddCommand(gscSitemapsAuditCmd)
+	gscSitemapsAuditCmd.Flags().StringVarP(&gscSitemapAuditConfig, "config", "c", "", "Audit the sitemaps listed under search_console.sitemaps in this config")
+	gscSitemapsAuditCmd.Flags().StringArrayVarP(&gscSitemapAuditURLs, "url", "u", nil, "Sitemap URL to audit (repeatable; overrides --config)")
+	gscSitemapsAuditCmd.Flags().IntVarP(&gscSitemapAuditDays, "days", "d", 28, "Search Analytics window in days")
+	gscSitemapsAuditCmd.Flags().IntVar(&gscSitemapAuditDepth, "depth", 1, "Path segments per group in the breakdown")
+	gscSitemapsAuditCmd.Flags().IntVar(&gscSitemapAuditInspect, "inspect", 0, "Inspect this many URLs without impressions")
+	output.FormatVar(gscSitemapsAuditCmd.Flags(), &gscSitemapAuditFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+// sitemapAuditResult is the `sitemaps audit` output.
+type sitemapAuditResult struct {
+	Site      string   `json:"site"`
+	StartDate string   `json:"start_date"`
+	EndDate   string   `json:"end_date"`
+	Sitemaps  []string `json:"sitemaps"`
+	// SitemapErrors lists sitemaps that could not be fetched or parsed.
+	SitemapErrors []string `json:"sitemap_errors,omitempty"`
+	sitemap.Gap
+	Inspected *sitemapAuditInspection `json:"inspected,omitempty"`
+}
+
+// sitemapAuditInspection summarises URL inspection of unseen sitemap URLs.
+type sitemapAuditInspection struct {
+	URLs    int            `json:"urls"`
+	Indexed int            `json:"indexed"`
+	ByState map[string]int `json:"by_coverage_state"`
+}
+
+func runGSCSitemapsAudit(cmd *cobra.Command, args []string) error {
+	if err := gsc.ValidateCoverageParams(gscSiteURL, gscSitemapAuditDays, "all"); err != nil {
+		return err
+	}
+	if gscSitemapAuditInspect < 0 {
+		return fmt.Errorf("--inspect must not be negative, got %d", gscSitemapAuditInspect)
+	}
+	progress := func(format string, args ...any) {
+		if gscSitemapAuditFormat == "table" {
+			theme.Fprintf(os.Stderr, format, args...)
+		}
+	}
+
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	sitemaps, err := sitemapsToAudit(client)
+	if err != nil {
+		return err
+	}
+	if len(sitemaps) == 0 {
+		return fmt.Errorf("no sitemaps to audit: pass --url, list them under search_console.sitemaps, or submit one for %s", gscSiteURL)
+	}
+
+	result := sitemapAuditResult{Site: gscSiteURL, Sitemaps: sitemaps}
+	validator := sitemap.NewValidator(30*time.Second, audit.DefaultUserAgent)
+	seen := make(map[string]bool)
+	var urls []string
+	for _, sm := range sitemaps {
+		progress("Fetching %s...\n", sm)
+		report, err := validator.Validate(context.Background(), gscSiteURL, sm)
+		if err != nil {
+			result.SitemapErrors = append(result.SitemapErrors, err.Error())
+			continue
+		}
+		for _, u := range report.URLs {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("no URLs found in %d sitemap(s)", len(sitemaps))
+	}
+
+	progress("Fetching page data for %d days...\n", gscSitemapAuditDays)
+	result.StartDate, result.EndDate = gsc.BuildDateRange(gscSitemapAuditDays)
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    gscSiteURL,
+		StartDate:  result.StartDate,
+		EndDate:    result.EndDate,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch page data: %w", err)
+	}
+	traffic := make(map[string]sitemap.Traffic, len(report.Rows))
+	for _, row := range report.Rows {
+		if len(row.Keys) > 0 {
+			traffic[row.Keys[0]] = sitemap.Traffic{Clicks: row.Clicks, Impressions: row.Impressions}
+		}
+	}
+	result.Gap = sitemap.MeasureGap(urls, traffic, gscSitemapAuditDepth)
+
+	if sample := sitemap.SpreadSample(result.Unseen, gscSitemapAuditInspect); len(sample) > 0 {
+		used, limit, _ := client.GetQuotaStatus()
+		if remaining := limit - used; len(sample) > remaining {
+			return fmt.Errorf("--inspect %d exceeds the %d inspections remaining today", len(sample), remaining)
+		}
+		progress("Inspecting %d URLs without impressions...\n", len(sample))
+		inspected, err := client.InspectMultipleURLs(gscSiteURL, sample)
+		if err != nil {
+			return err
+		}
+		result.Inspected = summariseSitemapInspection(inspected)
+	}
+
+	if gscSitemapAuditFormat == "json" {
+		return output.JSON(os.Stdout, result)
+	}
+	return displaySitemapAudit(result, len(report.Rows) >= gsc.MaxRowLimit)
+}
+
+// sitemapsToAudit resolves the sitemap list: --url, then the config, then
+// whatever is submitted to Search Console.
+func sitemapsToAudit(client *gsc.Client) ([]string, error) {
+	if len(gscSitemapAuditURLs) > 0 {
+		return gscSitemapAuditURLs, nil
+	}
+	if gscSitemapAuditConfig != "" {
+		cfg, err := config.LoadConfig(gscSitemapAuditConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		var out []string
+		if cfg.SearchConsole != nil {
+			for _, sm := range cfg.SearchConsole.ActiveSitemaps() {
+				out = append(out, sm.URL)
+			}
+		}
+		return out, nil
+	}
+	submitted, err := client.ListSitemaps(gscSiteURL)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(submitted))
+	for _, sm := range submitted {
+		out = append(out, sm.Path)
+	}
+	return out, nil
+}
+
+func summariseSitemapInspection(results []gsc.URLInspectionResult) *sitemapAuditInspection {
+	s := &sitemapAuditInspection{URLs: len(results), ByState: make(map[string]int)}
+	for _, r := range results {
+		if r.IndexStatus == "PASS" {
+			s.Indexed++
+		}
+		state := r.CoverageState
+		if state == "" {
+			state = "unknown"
+		}
+		s.ByState[state]++
+	}
+	return s
+}
+
+func displaySitemapAudit(r sitemapAuditResult, truncated bool) error {
+	theme.Cyan("═══ Sitemap Audit: %s ═══", r.Site)
+	theme.Println()
+	theme.Printf("Sitemaps: %d, URLs: %d (%s to %s)\n", len(r.Sitemaps), r.SitemapURLs, r.StartDate, r.EndDate)
+	theme.Printf("With impressions: %d (%.1f%%), with clicks: %d\n", r.WithImpressions, r.Share*100, r.WithClicks)
+	theme.Printf("Pages with impressions not in any sitemap: %d\n", r.NotInSitemap)
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Group", "URLs", "With Impressions", "With Clicks", "Share"},
+		r.Groups, func(g sitemap.GapGroup) []string {
+			pct := fmt.Sprintf("%.1f%%", g.Share*100)
+			if g.Share < 0.5 {
+				pct = theme.RedString("%s", pct)
+			}
+			return []string{g.Name, fmt.Sprintf("%d", g.URLs), fmt.Sprintf("%d", g.WithImpressions), fmt.Sprintf("%d", g.WithClicks), pct}
+		}); err != nil {
+		return err
+	}
+
+	if in := r.Inspected; in != nil {
+		theme.Println()
+		theme.Cyan("Inspected %d URLs without impressions: %d indexed, %d not", in.URLs, in.Indexed, in.URLs-in.Indexed)
+		states := make([]string, 0, len(in.ByState))
+		for s := range in.ByState {
+			states = append(states, s)
+		}
+		sort.Slice(states, func(i, j int) bool { return in.ByState[states[i]] > in.ByState[states[j]] })
+		for _, s := range states {
+			theme.Printf("   %-45s %d\n", s, in.ByState[s])
+		}
+	}
+
+	theme.Println()
+	for _, e := range r.SitemapErrors {
+		theme.Red("✗ %s", e)
+	}
+	if truncated {
+		theme.Yellow("⚠ Page data hit the %d-row limit; some sitemap URLs with impressions may be counted as unseen.", gsc.MaxRowLimit)
+	}
+	if len(r.Unseen) > 0 && r.Inspected == nil {
+		theme.HiBlack("ℹ %d URLs had no impressions. Run with --inspect N to check whether they are indexed.", len(r.Unseen))
+	}
+	return nil
+}
@@ -1,26 +1,40 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/garbarok/ga4-manager/internal/audit"
 	"github.com/garbarok/ga4-manager/internal/gsc"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
 	gscSiteURL    string
 	gscSitemapURL string
+
+	gscSitemapSkipValidation bool
+	gscSitemapSkipGSC        bool
+	gscSitemapFormat         string
 )
 
+// errSitemapInvalid signals that validation found errors; `sitemaps
+// validate` exits with diagcmd.ExitIssues.
+var errSitemapInvalid = errors.New("sitemap has errors")
+
 var gscSitemapsCmd = &cobra.Command{
-	Use:   "sitemaps",
-	Short: "Manage sitemaps in Google Search Console",
-	Long: `List, submit, and delete sitemaps in Google Search Console.
+	Use:     "sitemaps",
+	Aliases: []string{"sitemap"},
+	Short:   "Manage sitemaps in Google Search Console",
+	Long: `List, validate, submit, resubmit, and delete sitemaps in Google Search Console.
 
 Property Types:
   - Domain property: sc-domain:example.com (covers all subdomains and protocols)
@@ -33,9 +47,15 @@ Examples:
   # List all sitemaps (URL prefix)
   ga4 gsc sitemaps list --site https://example.com/
 
-  # Submit a sitemap
+  # Check a sitemap (or sitemap index) before submitting it
+  ga4 gsc sitemaps validate --site sc-domain:example.com --url https://example.com/sitemap.xml
+
+  # Submit a sitemap (validated first; --skip-validation to bypass)
   ga4 gsc sitemaps submit --site sc-domain:example.com --url https://example.com/sitemap.xml
 
+  # Ask Google to fetch an already submitted sitemap again
+  ga4 gsc sitemaps resubmit --site sc-domain:example.com --url https://example.com/sitemap.xml
+
   # Delete a sitemap
   ga4 gsc sitemaps delete --site sc-domain:example.com --url https://example.com/old-sitemap.xml
 
@@ -57,11 +77,56 @@ var gscSitemapsSubmitCmd = &cobra.Command{
 	RunE:  runGSCSitemapsSubmit,
 }
 
+var gscSitemapsResubmitCmd = &cobra.Command{
+	Use:   "resubmit",
+	Short: "Resubmit an already submitted sitemap",
+	Long: `Submit a sitemap that Search Console already knows again, so Google fetches
+it anew — after a large content update, or once the errors it last reported
+are fixed. The sitemap is validated first, as with submit.
+
+To retire a sitemap instead, use 'sitemaps delete', or mark it remove: true
+under search_console.sitemaps and run setup.`,
+	RunE: runGSCSitemapsResubmit,
+}
+
+var gscSitemapsValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Fetch a sitemap and check it before submitting",
+	Long: `Fetch a sitemap over HTTP and check it against the rules Search Console
+applies after submission. A sitemap index has each child sitemap fetched too;
+gzip-compressed files are decompressed.
+
+Errors (Google rejects the file or the entry):
+  - not well-formed XML, or a root other than <urlset>/<sitemapindex>
+  - more than 50,000 entries or 50 MB uncompressed in one file
+  - <loc> that is empty, relative, not http(s) or longer than 2,048 characters
+  - <loc> outside the --site property
+  - <lastmod> that is not a W3C datetime (e.g. 2024-05-01 or 2024-05-01T10:00:00+00:00)
+  - a sitemap index nested inside another index
+
+Warnings: duplicate URLs, <lastmod> in the future, empty files.
+
+If the sitemap has already been submitted, the errors and warnings Search
+Console last reported for it are shown alongside (--skip-gsc to skip).
+
+Exits 4 when any error is found.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runGSCSitemapsValidate(cmd, args)
+		if errors.Is(err, errSitemapInvalid) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
 var gscSitemapsDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a sitemap from Google Search Console",
-	Long:  "Remove a sitemap from Google Search Console. This does not delete the sitemap file itself.",
-	RunE:  runGSCSitemapsDelete,
+	Long: `Remove a sitemap from Google Search Console. This does not delete the sitemap file itself.
+
+To retire sitemaps declaratively, mark them remove: true under
+search_console.sitemaps; setup then deletes any that are still submitted.`,
+	RunE: runGSCSitemapsDelete,
 }
 
 var gscSitemapsGetCmd = &cobra.Command{
@@ -74,7 +139,9 @@ var gscSitemapsGetCmd = &cobra.Command{
 func init() {
 	gscCmd.AddCommand(gscSitemapsCmd)
 	gscSitemapsCmd.AddCommand(gscSitemapsListCmd)
+	gscSitemapsCmd.AddCommand(gscSitemapsValidateCmd)
 	gscSitemapsCmd.AddCommand(gscSitemapsSubmitCmd)
+	gscSitemapsCmd.AddCommand(gscSitemapsResubmitCmd)
 	gscSitemapsCmd.AddCommand(gscSitemapsDeleteCmd)
 	gscSitemapsCmd.AddCommand(gscSitemapsGetCmd)
 
@@ -84,8 +151,18 @@ func init() {
 
 	// Sitemap URL flag (required for submit, delete, get)
 	gscSitemapsSubmitCmd.Flags().StringVarP(&gscSitemapURL, "url", "u", "", "Sitemap URL (e.g., https://example.com/sitemap.xml)")
+	gscSitemapsSubmitCmd.Flags().BoolVar(&gscSitemapSkipValidation, "skip-validation", false, "Submit without fetching and validating the sitemap first")
 	_ = gscSitemapsSubmitCmd.MarkFlagRequired("url")
 
+	gscSitemapsResubmitCmd.Flags().StringVarP(&gscSitemapURL, "url", "u", "", "Sitemap URL to resubmit")
+	gscSitemapsResubmitCmd.Flags().BoolVar(&gscSitemapSkipValidation, "skip-validation", false, "Resubmit without fetching and validating the sitemap first")
+	_ = gscSitemapsResubmitCmd.MarkFlagRequired("url")
+
+	gscSitemapsValidateCmd.Flags().StringVarP(&gscSitemapURL, "url", "u", "", "Sitemap or sitemap index URL to validate")
+	gscSitemapsValidateCmd.Flags().BoolVar(&gscSitemapSkipGSC, "skip-gsc", false, "Do not look up what Search Console reports for the sitemap")
+	output.FormatVar(gscSitemapsValidateCmd.Flags(), &gscSitemapFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = gscSitemapsValidateCmd.MarkFlagRequired("url")
+
 	gscSitemapsDeleteCmd.Flags().StringVarP(&gscSitemapURL, "url", "u", "", "Sitemap URL to delete")
 	_ = gscSitemapsDeleteCmd.MarkFlagRequired("url")
 
@@ -95,30 +172,30 @@ func init() {
 
 func runGSCSitemapsList(cmd *cobra.Command, args []string) error {
 	// Create GSC client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
 	// List sitemaps
-	color.Cyan("📍 Listing sitemaps for %s", gscSiteURL)
+	theme.Cyan("📍 Listing sitemaps for %s", gscSiteURL)
 	sitemaps, err := client.ListSitemaps(gscSiteURL)
 	if err != nil {
-		color.Red("✗ Failed to list sitemaps: %v", err)
+		theme.Red("✗ Failed to list sitemaps: %v", err)
 		return err
 	}
 
 	if len(sitemaps) == 0 {
-		color.Yellow("No sitemaps found for this site")
+		theme.Yellow("No sitemaps found for this site")
 		return nil
 	}
 
-	if err := render.Render(os.Stdout, render.FormatTable, sitemapsListColumns(), sitemaps, sitemapsListTableRow); err != nil {
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, sitemapsListColumns(), sitemaps, sitemapsListTableRow); err != nil {
 		return fmt.Errorf("failed to render sitemaps table: %w", err)
 	}
-	color.Green("\n✓ Found %d sitemap(s)", len(sitemaps))
+	theme.Green("\n✓ Found %d sitemap(s)", len(sitemaps))
 	return nil
 }
 
@@ -133,23 +210,18 @@ func sitemapsListColumns() []string {
 func sitemapsListTableRow(sm gsc.SitemapInfo) []string {
 	var status string
 	if sm.Errors > 0 {
-		status = color.RedString("Errors: %d", sm.Errors)
+		status = theme.RedString("Errors: %d", sm.Errors)
 	} else if sm.Warnings > 0 {
-		status = color.YellowString("Warnings: %d", sm.Warnings)
+		status = theme.YellowString("Warnings: %d", sm.Warnings)
 	} else if sm.IsPending {
-		status = color.YellowString("Pending")
+		status = theme.YellowString("Pending")
 	} else {
-		status = color.GreenString("OK")
+		status = theme.GreenString("OK")
 	}
 
 	lastSubmitted := "Never"
 	if sm.LastSubmitted != "" {
-		t, err := time.Parse(time.RFC3339, sm.LastSubmitted)
-		if err == nil {
-			lastSubmitted = t.Format("2006-01-02 15:04")
-		} else {
-			lastSubmitted = sm.LastSubmitted
-		}
+		lastSubmitted = formatSitemapTime(sm.LastSubmitted)
 	}
 
 	sitemapType := sm.Path
@@ -169,9 +241,9 @@ func sitemapsListTableRow(sm gsc.SitemapInfo) []string {
 
 func runGSCSitemapsSubmit(cmd *cobra.Command, args []string) error {
 	// Create GSC client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
@@ -182,27 +254,107 @@ func runGSCSitemapsSubmit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if !gscSitemapSkipValidation {
+		if err := validateBeforeSubmit(gscSiteURL, gscSitemapURL); err != nil {
+			return err
+		}
+	}
+
 	// Submit sitemap
-	color.Cyan("📤 Submitting sitemap to Google Search Console...")
-	color.Cyan("   Site: %s", gscSiteURL)
-	color.Cyan("   Sitemap: %s", gscSitemapURL)
+	theme.Cyan("📤 Submitting sitemap to Google Search Console...")
+	theme.Cyan("   Site: %s", gscSiteURL)
+	theme.Cyan("   Sitemap: %s", gscSitemapURL)
+
+	if err := client.SubmitSitemap(gscSiteURL, gscSitemapURL); err != nil {
+		theme.Red("✗ Failed to submit sitemap: %v", err)
+		return err
+	}
+
+	theme.Green("✓ Sitemap submitted successfully")
+	theme.Cyan("\nNote: It may take a few hours for Google to process the sitemap.")
+	theme.Cyan("Use 'ga4 gsc sitemaps get' to check the status later.")
+	return nil
+}
+
+// validateBeforeSubmit validates a sitemap ahead of a submit or resubmit and
+// reports its errors, if any.
+func validateBeforeSubmit(site, sitemapURL string) error {
+	theme.Cyan("🔎 Validating %s...", sitemapURL)
+	report, err := validateSitemap(site, sitemapURL)
+	if err != nil {
+		theme.Red("✗ %v", err)
+		theme.Println("   Fix the sitemap, or pass --skip-validation to submit anyway.")
+		return err
+	}
+	if n := report.Errors(); n > 0 {
+		displaySitemapIssues(report)
+		theme.Println("   Fix the sitemap, or pass --skip-validation to submit anyway.")
+		return fmt.Errorf("sitemap %s has %d error(s)", sitemapURL, n)
+	}
+	theme.Green("✓ %d URL(s) in %d file(s), no errors", report.TotalURLs, len(report.Documents))
+	return nil
+}
+
+func runGSCSitemapsResubmit(cmd *cobra.Command, args []string) error {
+	// Create GSC client
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		theme.Red("✗ Failed to create GSC client: %v", err)
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := preflightWritable(client, gscSiteURL); err != nil {
+		return err
+	}
+
+	// Resubmit only what Search Console already has: a typo in --url would
+	// otherwise quietly submit a new sitemap.
+	previous, err := client.GetSitemap(gscSiteURL, gscSitemapURL)
+	if err != nil {
+		theme.Red("✗ %s is not a submitted sitemap of %s: %v", gscSitemapURL, gscSiteURL, err)
+		theme.Println("   Use 'ga4 gsc sitemaps submit' for a new sitemap.")
+		return err
+	}
+
+	if !gscSitemapSkipValidation {
+		if err := validateBeforeSubmit(gscSiteURL, gscSitemapURL); err != nil {
+			return err
+		}
+	}
+
+	theme.Cyan("📤 Resubmitting sitemap to Google Search Console...")
+	theme.Cyan("   Site: %s", gscSiteURL)
+	theme.Cyan("   Sitemap: %s", gscSitemapURL)
+	if previous.LastSubmitted != "" {
+		theme.Cyan("   Last submitted: %s", formatSitemapTime(previous.LastSubmitted))
+	}
 
 	if err := client.SubmitSitemap(gscSiteURL, gscSitemapURL); err != nil {
-		color.Red("✗ Failed to submit sitemap: %v", err)
+		theme.Red("✗ Failed to resubmit sitemap: %v", err)
 		return err
 	}
 
-	color.Green("✓ Sitemap submitted successfully")
-	color.Cyan("\nNote: It may take a few hours for Google to process the sitemap.")
-	color.Cyan("Use 'ga4 gsc sitemaps get' to check the status later.")
+	theme.Green("✓ Sitemap resubmitted successfully")
+	theme.Cyan("\nUse 'ga4 gsc sitemaps get' to check when Google downloads it again.")
 	return nil
 }
 
+// formatSitemapTime formats an RFC 3339 timestamp from the sitemaps API for
+// display, or returns it unchanged if it does not parse.
+func formatSitemapTime(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
 func runGSCSitemapsDelete(cmd *cobra.Command, args []string) error {
 	// Create GSC client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
@@ -213,91 +365,91 @@ func runGSCSitemapsDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete sitemap
-	color.Cyan("🗑️  Deleting sitemap from Google Search Console...")
-	color.Cyan("   Site: %s", gscSiteURL)
-	color.Cyan("   Sitemap: %s", gscSitemapURL)
+	theme.Cyan("🗑️  Deleting sitemap from Google Search Console...")
+	theme.Cyan("   Site: %s", gscSiteURL)
+	theme.Cyan("   Sitemap: %s", gscSitemapURL)
 
 	if err := client.DeleteSitemap(gscSiteURL, gscSitemapURL); err != nil {
-		color.Red("✗ Failed to delete sitemap: %v", err)
+		theme.Red("✗ Failed to delete sitemap: %v", err)
 		return err
 	}
 
-	color.Green("✓ Sitemap deleted successfully")
-	color.Cyan("\nNote: This only removes the sitemap from Search Console.")
-	color.Cyan("The sitemap file itself is still hosted on your server.")
+	theme.Green("✓ Sitemap deleted successfully")
+	theme.Cyan("\nNote: This only removes the sitemap from Search Console.")
+	theme.Cyan("The sitemap file itself is still hosted on your server.")
 	return nil
 }
 
 func runGSCSitemapsGet(cmd *cobra.Command, args []string) error {
 	// Create GSC client
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
 	// Get sitemap
-	color.Cyan("📍 Retrieving sitemap details...")
+	theme.Cyan("📍 Retrieving sitemap details...")
 	sm, err := client.GetSitemap(gscSiteURL, gscSitemapURL)
 	if err != nil {
-		color.Red("✗ Failed to get sitemap: %v", err)
+		theme.Red("✗ Failed to get sitemap: %v", err)
 		return err
 	}
 
 	// Display sitemap details
-	fmt.Println()
-	color.Cyan("═══ Sitemap Details ═══")
-	fmt.Printf("URL: %s\n", sm.Path)
+	theme.Println()
+	theme.Cyan("═══ Sitemap Details ═══")
+	theme.Printf("URL: %s\n", sm.Path)
 
 	if sm.IsSitemapsIndex {
-		color.Cyan("Type: Sitemap Index")
+		theme.Cyan("Type: Sitemap Index")
 	} else {
-		color.Cyan("Type: Regular Sitemap")
+		theme.Cyan("Type: Regular Sitemap")
 	}
 
 	if sm.LastSubmitted != "" {
 		t, err := time.Parse(time.RFC3339, sm.LastSubmitted)
 		if err == nil {
-			fmt.Printf("Last Submitted: %s\n", t.Format("2006-01-02 15:04:05"))
+			theme.Printf("Last Submitted: %s\n", t.Format("2006-01-02 15:04:05"))
 		} else {
-			fmt.Printf("Last Submitted: %s\n", sm.LastSubmitted)
+			theme.Printf("Last Submitted: %s\n", sm.LastSubmitted)
 		}
 	}
 
 	if sm.LastDownloaded != "" {
 		t, err := time.Parse(time.RFC3339, sm.LastDownloaded)
 		if err == nil {
-			fmt.Printf("Last Downloaded: %s\n", t.Format("2006-01-02 15:04:05"))
+			theme.Printf("Last Downloaded: %s\n", t.Format("2006-01-02 15:04:05"))
 		} else {
-			fmt.Printf("Last Downloaded: %s\n", sm.LastDownloaded)
+			theme.Printf("Last Downloaded: %s\n", sm.LastDownloaded)
 		}
 	}
 
 	if sm.IsPending {
-		color.Yellow("Status: Pending (Google is processing)")
+		theme.Yellow("Status: Pending (Google is processing)")
 	} else {
-		color.Green("Status: Processed")
+		theme.Green("Status: Processed")
 	}
 
 	// Display errors and warnings
 	if sm.Errors > 0 {
-		color.Red("Errors: %d", sm.Errors)
+		theme.Red("Errors: %d", sm.Errors)
 	} else {
-		color.Green("Errors: 0")
+		theme.Green("Errors: 0")
 	}
 
 	if sm.Warnings > 0 {
-		color.Yellow("Warnings: %d", sm.Warnings)
+		theme.Yellow("Warnings: %d", sm.Warnings)
 	} else {
-		color.Green("Warnings: 0")
+		theme.Green("Warnings: 0")
 	}
 
 	// Display contents
 	if len(sm.Contents) > 0 {
-		fmt.Println()
-		color.Cyan("═══ Content Breakdown ═══")
-		if err := render.Render(os.Stdout, render.FormatTable, sitemapsContentsColumns(), sm.Contents, sitemapsContentsTableRow); err != nil {
+		theme.Println()
+		theme.Cyan("═══ Content Breakdown ═══")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, sitemapsContentsColumns(), sm.Contents, sitemapsContentsTableRow); err != nil {
 			return fmt.Errorf("failed to render contents table: %w", err)
 		}
 	}
@@ -324,6 +476,144 @@ func sitemapsContentsTableRow(content gsc.SitemapContentInfo) []string {
 	}
 }
 
+// sitemapValidation is the `sitemaps validate` result: the local checks plus,
+// when the sitemap has been submitted before, what Search Console reported.
+type sitemapValidation struct {
+	*sitemap.Report
+	GSC *sitemapGSCStatus `json:"gsc,omitempty"`
+}
+
+// sitemapGSCStatus is the part of gsc.SitemapInfo relevant to validation.
+type sitemapGSCStatus struct {
+	LastDownloaded string `json:"last_downloaded,omitempty"`
+	IsPending      bool   `json:"is_pending"`
+	Submitted      int64  `json:"submitted"`
+	Errors         int64  `json:"errors"`
+	Warnings       int64  `json:"warnings"`
+}
+
+func validateSitemap(site, sitemapURL string) (*sitemap.Report, error) {
+	return sitemap.NewValidator(30*time.Second, audit.DefaultUserAgent).Validate(context.Background(), site, sitemapURL)
+}
+
+func runGSCSitemapsValidate(cmd *cobra.Command, args []string) error {
+	report, err := validateSitemap(gscSiteURL, gscSitemapURL)
+	if err != nil {
+		return err
+	}
+	result := sitemapValidation{Report: report}
+
+	// Cross-reference with Search Console. A lookup failure usually means the
+	// sitemap was never submitted, which is not a validation problem.
+	var gscErr error
+	if !gscSitemapSkipGSC {
+		client, err := gsc.NewClient(gscClientOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to create GSC client: %w", err)
+		}
+		var sm *gsc.SitemapInfo
+		sm, gscErr = client.GetSitemap(gscSiteURL, gscSitemapURL)
+		_ = client.Close()
+		if gscErr == nil {
+			result.GSC = &sitemapGSCStatus{
+				LastDownloaded: sm.LastDownloaded,
+				IsPending:      sm.IsPending,
+				Submitted:      sm.ContentsCount,
+				Errors:         sm.Errors,
+				Warnings:       sm.Warnings,
+			}
+		}
+	}
+
+	if gscSitemapFormat == "json" {
+		if err := output.JSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else if err := displaySitemapValidation(result, gscErr); err != nil {
+		return err
+	}
+
+	if report.Errors() > 0 {
+		return errSitemapInvalid
+	}
+	return nil
+}
+
+func displaySitemapValidation(v sitemapValidation, gscErr error) error {
+	theme.Cyan("═══ Sitemap Validation: %s ═══", v.Sitemap)
+	theme.Println()
+	kind := "Sitemap"
+	if v.Kind == sitemap.KindIndex {
+		kind = "Sitemap index"
+	}
+	theme.Printf("%s for %s: %d URL(s) in %d file(s)\n", kind, v.Site, v.TotalURLs, len(v.Documents))
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"File", "Type", "Entries", "Size", "Errors", "Warnings"},
+		v.Documents, func(d sitemap.Document) []string {
+			size := fmt.Sprintf("%.1f KB", float64(d.Bytes)/1024)
+			if d.Gzipped {
+				size += " (gz)"
+			}
+			errs := fmt.Sprintf("%d", d.Errors)
+			if d.Errors > 0 {
+				errs = theme.RedString("%s", errs)
+			}
+			return []string{d.URL, d.Kind, fmt.Sprintf("%d", d.Entries), size, errs, fmt.Sprintf("%d", d.Warnings)}
+		}); err != nil {
+		return err
+	}
+	theme.Println()
+
+	displaySitemapIssues(v.Report)
+
+	switch {
+	case v.GSC != nil:
+		theme.Println()
+		theme.Cyan("Search Console (last download %s):", orUnknownValue(v.GSC.LastDownloaded))
+		theme.Printf("   Submitted URLs: %d, errors: %d, warnings: %d\n", v.GSC.Submitted, v.GSC.Errors, v.GSC.Warnings)
+		if v.GSC.Errors > 0 && v.Errors() == 0 {
+			theme.Yellow("⚠ Search Console reports errors the local checks did not find; the live file may have changed since Google last downloaded it, or the errors are in fetching (robots.txt, server errors).")
+		}
+	case gscErr != nil:
+		theme.Println()
+		theme.HiBlack("ℹ Not found in Search Console (not submitted yet?): %v", gscErr)
+	}
+
+	if v.Errors() == 0 {
+		theme.Println()
+		theme.Green("✓ No errors; ready to submit with `ga4 gsc sitemaps submit`.")
+	}
+	return nil
+}
+
+// maxSitemapIssuesShown caps issue lines in table output; --format json has
+// them all.
+const maxSitemapIssuesShown = 50
+
+func displaySitemapIssues(r *sitemap.Report) {
+	if len(r.Issues) == 0 {
+		return
+	}
+	theme.Printf("%d error(s), %d warning(s):\n", r.Errors(), r.Warnings())
+	for i, issue := range r.Issues {
+		if i == maxSitemapIssuesShown {
+			theme.HiBlack("   … %d more (use --format json for the full list)", len(r.Issues)-i)
+			break
+		}
+		where := issue.Sitemap
+		if issue.Loc != "" {
+			where = issue.Loc
+		}
+		if issue.Severity == sitemap.SeverityError {
+			theme.Red("   ✗ %s: %s", where, issue.Message)
+		} else {
+			theme.Yellow("   ⚠ %s: %s", where, issue.Message)
+		}
+	}
+}
+
 // preflightWritable verifies the authenticated account can write to the
 // property before a sitemap submit/delete. A definitively read-only account
 // returns an actionable error so the caller never hits a bare 403. If the
@@ -332,7 +622,7 @@ func sitemapsContentsTableRow(content gsc.SitemapContentInfo) []string {
 func preflightWritable(client *gsc.Client, site string) error {
 	perm, err := client.GetSitePermission(site)
 	if err != nil {
-		color.Yellow("⚠ Could not verify write permission (%v); attempting anyway...", err)
+		theme.Yellow("⚠ Could not verify write permission (%v); attempting anyway...", err)
 		return nil
 	}
 	if perm.CanWrite {
@@ -340,11 +630,11 @@ func preflightWritable(client *gsc.Client, site string) error {
 	}
 
 	id := gsc.LoadServiceAccountIdentity()
-	color.Red("✗ Write blocked: this account has read-only access to %s", site)
-	fmt.Printf("   Identity:   %s\n", orUnknownValue(id.ClientEmail))
-	fmt.Printf("   Permission: %s (need siteOwner or siteFullUser to submit/delete sitemaps)\n", perm.PermissionLevel)
-	fmt.Println("   Fix: Search Console → Settings → Users and permissions → grant this account 'Full' access,")
-	fmt.Println("        or point GOOGLE_APPLICATION_CREDENTIALS at a key that has write access.")
-	fmt.Println("   Tip: run 'ga4 gsc whoami' to see permissions for every accessible property.")
+	theme.Red("✗ Write blocked: this account has read-only access to %s", site)
+	theme.Printf("   Identity:   %s\n", orUnknownValue(id.ClientEmail))
+	theme.Printf("   Permission: %s (need siteOwner or siteFullUser to submit/delete sitemaps)\n", perm.PermissionLevel)
+	theme.Println("   Fix: Search Console → Settings → Users and permissions → grant this account 'Full' access,")
+	theme.Println("        or point GOOGLE_APPLICATION_CREDENTIALS at a key that has write access.")
+	theme.Println("   Tip: run 'ga4 gsc whoami' to see permissions for every accessible property.")
 	return fmt.Errorf("read-only access to %s (permission: %s)", site, perm.PermissionLevel)
 }
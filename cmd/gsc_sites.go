@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gscSitesSite   string
+	gscSitesFormat string
+)
+
+var gscSitesCmd = &cobra.Command{
+	Use:     "sites",
+	Aliases: []string{"site"},
+	Short:   "List and add Search Console properties",
+	Long: `List the Search Console properties the authenticated account can see, or add
+one to it.
+
+Run 'sites list' before writing a config for a new site: a property that is
+missing from the list, or listed as unverified, cannot be queried, and every
+gsc command against it will fail.
+
+Verification:
+  A property is verified when the account holds siteOwner, siteFullUser or
+  siteRestrictedUser on it. 'sites add' puts a property on the account's list
+  but cannot verify it — it stays siteUnverifiedUser until ownership is
+  verified in Search Console, or an owner adds the account's email under
+  Settings → Users and permissions.
+
+Examples:
+  # Every property this account can see, with its verification state
+  ga4 gsc sites list
+
+  # JSON for automation
+  ga4 gsc sites list --format json
+
+  # Add a domain property (then verify it in Search Console)
+  ga4 gsc sites add --site sc-domain:example.com
+
+  # Add a URL-prefix property
+  ga4 gsc sites add --site https://example.com/`,
+}
+
+var gscSitesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List accessible properties and their verification state",
+	RunE:  runGSCSitesList,
+}
+
+var gscSitesAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a property to the account",
+	Long: `Add a property to the authenticated account's Search Console properties and
+report its verification state afterwards. Adding a property that is already
+on the account leaves it unchanged.`,
+	RunE: runGSCSitesAdd,
+}
+
+func init() {
+	gscCmd.AddCommand(gscSitesCmd)
+	gscSitesCmd.AddCommand(gscSitesListCmd)
+	gscSitesCmd.AddCommand(gscSitesAddCmd)
+
+	output.FormatVar(gscSitesCmd.PersistentFlags(), &gscSitesFormat, "f", output.FormatTable, output.FormatJSON)
+
+	gscSitesAddCmd.Flags().StringVarP(&gscSitesSite, "site", "s", "", "Property to add: domain property (sc-domain:example.com) or URL prefix (https://example.com/)")
+	_ = gscSitesAddCmd.MarkFlagRequired("site")
+}
+
+func runGSCSitesList(cmd *cobra.Command, args []string) error {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		theme.Red("✗ Failed to create GSC client: %v", err)
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	sites, err := client.ListSitePermissions()
+	if err != nil {
+		theme.Red("✗ Failed to list properties: %v", err)
+		return err
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].SiteURL < sites[j].SiteURL })
+
+	if gscSitesFormat == output.FormatJSON {
+		return output.JSON(os.Stdout, sites)
+	}
+
+	if len(sites) == 0 {
+		theme.Yellow("No properties found for this account.")
+		theme.Println("   Add one with 'ga4 gsc sites add --site <url>', or ask an owner to add")
+		theme.Println("   the account's email under Settings → Users and permissions.")
+		return nil
+	}
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, sitesListColumns(), sites, sitesListTableRow); err != nil {
+		return fmt.Errorf("failed to render properties table: %w", err)
+	}
+
+	verified := 0
+	for _, s := range sites {
+		if s.Verified {
+			verified++
+		}
+	}
+	theme.Green("\n✓ Found %d property(ies), %d verified", len(sites), verified)
+	if unverified := len(sites) - verified; unverified > 0 {
+		theme.Yellow("⚠ %d unverified property(ies) cannot be queried until ownership is verified", unverified)
+	}
+	return nil
+}
+
+func sitesListColumns() []string {
+	return []string{"Property", "Type", "Permission", "Verified", "Write"}
+}
+
+func sitesListTableRow(s gsc.SitePermission) []string {
+	verified := theme.GreenString("yes")
+	if !s.Verified {
+		verified = theme.YellowString("no")
+	}
+	write := "no"
+	if s.CanWrite {
+		write = "yes"
+	}
+	return []string{s.SiteURL, siteType(s.SiteURL), s.PermissionLevel, verified, write}
+}
+
+// siteType names the kind of property a site URL refers to.
+func siteType(siteURL string) string {
+	if strings.HasPrefix(siteURL, "sc-domain:") {
+		return "domain"
+	}
+	return "url-prefix"
+}
+
+func runGSCSitesAdd(cmd *cobra.Command, args []string) error {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		theme.Red("✗ Failed to create GSC client: %v", err)
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if gscSitesFormat == output.FormatTable {
+		theme.Cyan("➕ Adding %s to Search Console...", gscSitesSite)
+	}
+	perm, err := client.AddSite(gscSitesSite)
+	if err != nil {
+		theme.Red("✗ Failed to add property: %v", err)
+		return err
+	}
+
+	if gscSitesFormat == output.FormatJSON {
+		return output.JSON(os.Stdout, perm)
+	}
+
+	theme.Green("✓ Added %s", perm.SiteURL)
+	theme.Printf("    Permission: %s\n", perm.PermissionLevel)
+	if perm.Verified {
+		theme.Green("✓ Verified — the property can be queried")
+		return nil
+	}
+	id := gsc.LoadServiceAccountIdentity()
+	theme.Yellow("○ Not verified — gsc commands against this property will fail until it is")
+	theme.Println("   Verify ownership in Search Console, then add this account as a user:")
+	theme.Printf("   Settings → Users and permissions → Add user → %s\n", orUnknownValue(id.ClientEmail))
+	return nil
+}
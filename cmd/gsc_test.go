@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+)
+
+// A gsc command runs on the login of a --profile user profile alone: no
+// GOOGLE_APPLICATION_CREDENTIALS and no default `ga4 auth login`.
+func TestGSCCommand_ProfileLoginOnly(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv(auth.CredentialsEnvVar, "")
+	t.Setenv(auth.ProfileEnvVar, "")
+	t.Cleanup(func() { profileFlag, gscSubmitURLs = "", nil })
+
+	token, err := auth.ProfileTokenPath("client")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(token), 0o700))
+	require.NoError(t, os.WriteFile(token, []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh"}`), 0o600))
+	profiles := &auth.Profiles{Profiles: map[string]auth.Profile{"client": {Type: auth.SourceUser, Path: token}}}
+	require.NoError(t, profiles.Save())
+
+	_, err = auth.Resolve()
+	require.ErrorIs(t, err, auth.ErrNoCredentials, "only the profile holds credentials")
+
+	// An invalid URL fails before any request, after the credential check
+	// and client creation have passed.
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	t.Cleanup(func() { rootCmd.SetOut(nil); rootCmd.SetErr(nil) })
+	rootCmd.SetArgs([]string{"gsc", "submit-url", "--profile", "client", "--url", "not-a-url", "--audit-log", "off"})
+	err = rootCmd.Execute()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrNoCredentials)
+	assert.Contains(t, err.Error(), "1 of 1 URL notifications failed")
+
+	creds, err := auth.Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, auth.Source{Kind: auth.SourceUser, Path: token}, creds)
+}
@@ -1,15 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 var (
@@ -56,7 +56,7 @@ func init() {
 	gscCmd.AddCommand(gscWhoamiCmd)
 	gscWhoamiCmd.Flags().StringVarP(&gscWhoamiSite, "site", "s", "", "Site URL to check (sc-domain:example.com or https://example.com/)")
 	gscWhoamiCmd.Flags().StringVarP(&gscWhoamiConfig, "config", "c", "", "Path to configuration file (uses search_console.site_url)")
-	gscWhoamiCmd.Flags().StringVarP(&gscWhoamiFormat, "format", "f", "table", "Output format: table or json")
+	output.FormatVar(gscWhoamiCmd.Flags(), &gscWhoamiFormat, "f", output.FormatTable, output.FormatJSON)
 }
 
 type whoamiReport struct {
@@ -71,7 +71,7 @@ func runGSCWhoami(cmd *cobra.Command, args []string) error {
 	if site == "" && gscWhoamiConfig != "" {
 		cfg, err := config.LoadConfig(gscWhoamiConfig)
 		if err != nil {
-			color.Red("✗ Failed to load config: %v", err)
+			theme.Red("✗ Failed to load config: %v", err)
 			return err
 		}
 		if cfg.SearchConsole == nil || cfg.SearchConsole.SiteURL == "" {
@@ -82,9 +82,9 @@ func runGSCWhoami(cmd *cobra.Command, args []string) error {
 
 	id := gsc.LoadServiceAccountIdentity()
 
-	client, err := gsc.NewClient()
+	client, err := gsc.NewClient(gscClientOptions()...)
 	if err != nil {
-		color.Red("✗ Failed to create GSC client: %v", err)
+		theme.Red("✗ Failed to create GSC client: %v", err)
 		return err
 	}
 	defer func() { _ = client.Close() }()
@@ -93,14 +93,14 @@ func runGSCWhoami(cmd *cobra.Command, args []string) error {
 	if site != "" {
 		perm, err := client.GetSitePermission(site)
 		if err != nil {
-			color.Red("✗ Failed to read permission for %s: %v", site, err)
+			theme.Red("✗ Failed to read permission for %s: %v", site, err)
 			return err
 		}
 		sites = []gsc.SitePermission{*perm}
 	} else {
 		sites, err = client.ListSitePermissions()
 		if err != nil {
-			color.Red("✗ Failed to list accessible properties: %v", err)
+			theme.Red("✗ Failed to list accessible properties: %v", err)
 			return err
 		}
 	}
@@ -113,9 +113,7 @@ func runGSCWhoami(cmd *cobra.Command, args []string) error {
 	}
 
 	if gscWhoamiFormat == "json" {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(report)
+		return output.JSON(os.Stdout, report)
 	}
 
 	displayWhoamiTable(report)
@@ -123,24 +121,24 @@ func runGSCWhoami(cmd *cobra.Command, args []string) error {
 }
 
 func displayWhoamiTable(r whoamiReport) {
-	color.Cyan("═══ Authenticated Identity ═══")
-	fmt.Printf("Service account: %s\n", orUnknownValue(r.ClientEmail))
-	fmt.Printf("GCP project:     %s\n", orUnknownValue(r.ProjectID))
-	fmt.Printf("Credentials:     %s\n", orUnknownValue(r.CredentialPath))
-	fmt.Println()
+	theme.Cyan("═══ Authenticated Identity ═══")
+	theme.Printf("Service account: %s\n", orUnknownValue(r.ClientEmail))
+	theme.Printf("GCP project:     %s\n", orUnknownValue(r.ProjectID))
+	theme.Printf("Credentials:     %s\n", orUnknownValue(r.CredentialPath))
+	theme.Println()
 
-	color.Cyan("═══ Property Permissions ═══")
+	theme.Cyan("═══ Property Permissions ═══")
 	if len(r.Sites) == 0 {
-		color.Yellow("No accessible properties found for this account.")
+		theme.Yellow("No accessible properties found for this account.")
 		return
 	}
 	for _, s := range r.Sites {
 		if s.CanWrite {
-			color.Green("✓ %s", s.SiteURL)
-			fmt.Printf("    %s — read + write (sitemap submit/delete allowed)\n", s.PermissionLevel)
+			theme.Green("✓ %s", s.SiteURL)
+			theme.Printf("    %s — read + write (sitemap submit/delete allowed)\n", s.PermissionLevel)
 		} else {
-			color.Yellow("○ %s", s.SiteURL)
-			fmt.Printf("    %s — read-only (sitemap submit/delete will 403)\n", s.PermissionLevel)
+			theme.Yellow("○ %s", s.SiteURL)
+			theme.Printf("    %s — read-only (sitemap submit/delete will 403)\n", s.PermissionLevel)
 		}
 	}
 }
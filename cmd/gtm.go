@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gtmCmd = &cobra.Command{
+	Use:   "gtm",
+	Short: "Google Tag Manager checks",
+	Long: `Read the GTM container named in the config's tag_manager block and check
+it against the GA4 property it feeds:
+
+  tag_manager:
+    account_id: "6001234567"     # numeric IDs from the container URL in GTM
+    container_id: "190123456"
+    workspace_id: "12"           # optional: audit a draft instead of the live version
+
+Access is read-only: add the service account to the container with Read
+permission (GTM → Admin → User Management).`,
+}
+
+func init() {
+	rootCmd.AddCommand(gtmCmd)
+}
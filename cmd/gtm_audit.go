@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gtm"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	gtmAuditConfig    string
+	gtmAuditWorkspace string
+	gtmAuditSkipGA4   bool
+	gtmAuditFormat    string
+)
+
+// errGTMEventsUnwired signals conversions no active GTM tag sends; the
+// command exits with diagcmd.ExitIssues.
+var errGTMEventsUnwired = errors.New("conversions not wired in GTM")
+
+var gtmAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check that every conversion has a GA4 event tag in GTM",
+	Long: `List the tags, triggers and variables of the configured GTM container and
+check that every conversion GA4 expects is sent by a GA4 Event tag.
+
+Expected events are the conversions in the config plus the key events
+already marked on the property (--skip-ga4 to use the config only). Each gets
+a status:
+
+  wired       an active GA4 Event tag with a firing trigger sends it
+  automatic   sent by the Google tag or enhanced measurement (page_view,
+              scroll, file_download, form_submit, ...)
+  dynamic     no tag names it, but a tag sends a variable event name such
+              as {{Event}}, so it may be pushed through the dataLayer
+  paused      only paused tags send it
+  no_trigger  only tags without a firing trigger send it
+  missing     no tag sends it
+
+Event names written as constant variables are resolved. Active GA4 Event tags
+for events that are not conversions are listed for reference.
+
+The published (live) version is audited unless --workspace or
+tag_manager.workspace_id names a draft workspace.
+
+Exits 4 when an event is missing, paused or has no trigger.
+
+Examples:
+  ga4 gtm audit --config configs/mysite.yaml
+  ga4 gtm audit --config configs/mysite.yaml --workspace 12 --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runGTMAudit(cmd, args)
+		if errors.Is(err, errGTMEventsUnwired) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	gtmCmd.AddCommand(gtmAuditCmd)
+	gtmAuditCmd.Flags().StringVarP(&gtmAuditConfig, "config", "c", "", "Path to configuration file (tag_manager block, conversions)")
+	gtmAuditCmd.Flags().StringVarP(&gtmAuditWorkspace, "workspace", "w", "", "Audit this workspace ID instead of the live version")
+	gtmAuditCmd.Flags().BoolVar(&gtmAuditSkipGA4, "skip-ga4", false, "Do not read key events from the GA4 property")
+	output.FormatVar(gtmAuditCmd.Flags(), &gtmAuditFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = gtmAuditCmd.MarkFlagRequired("config")
+}
+
+func runGTMAudit(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(gtmAuditConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var propertyEvents []string
+	if propertyID := cfg.GetPropertyID(); propertyID != "" && !gtmAuditSkipGA4 {
+		client, err := newGA4Client(cfg)
+		if err != nil {
+			return err
+		}
+		conversions, err := client.ListConversions(propertyID)
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("failed to list conversions: %w", err)
+		}
+		for _, c := range conversions {
+			propertyEvents = append(propertyEvents, c.EventName)
+		}
+	}
+	expected := expectedGTMEvents(cfg, propertyEvents)
+	if len(expected) == 0 {
+		return fmt.Errorf("no conversions to check: %s lists none and the property has no key events", gtmAuditConfig)
+	}
+
+	container, err := readGTMContainer(cfg, gtmAuditWorkspace)
+	if err != nil {
+		return err
+	}
+	report := gtm.Audit(container, expected)
+
+	if gtmAuditFormat == "json" {
+		if err := output.JSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else if err := displayGTMAudit(report, container); err != nil {
+		return err
+	}
+
+	if report.Problems() > 0 {
+		return errGTMEventsUnwired
+	}
+	return nil
+}
+
+// gtmClientFactory builds the Tag Manager client. Tests substitute it to
+// see which credentials a command resolved.
+var gtmClientFactory = gtm.NewClient
+
+// readGTMContainer reads the container from the config's tag_manager block.
+// A non-empty workspace overrides tag_manager.workspace_id.
+func readGTMContainer(cfg *config.ProjectConfig, workspace string) (*gtm.Container, error) {
+	tm := cfg.TagManager
+	if tm == nil {
+		return nil, fmt.Errorf("the config has no tag_manager block (account_id, container_id)")
+	}
+	if workspace == "" {
+		workspace = tm.WorkspaceID
+	}
+	timeouts, err := clientTimeouts()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.ContextTimeout)
+	defer cancel()
+
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	client, err := gtmClientFactory(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	return client.Container(ctx, tm.AccountID, tm.ContainerID, workspace)
+}
+
+// expectedGTMEvents merges the configured conversions with the property's
+// key events: configured ones first, in config order, then property-only
+// events alphabetically.
+func expectedGTMEvents(cfg *config.ProjectConfig, propertyEvents []string) []gtm.ExpectedEvent {
+	var out []gtm.ExpectedEvent
+	index := make(map[string]int)
+	for _, c := range cfg.Conversions {
+		if _, ok := index[c.Name]; ok {
+			continue
+		}
+		index[c.Name] = len(out)
+		out = append(out, gtm.ExpectedEvent{Name: c.Name, Sources: []string{gtm.SourceConfig}})
+	}
+	extra := append([]string(nil), propertyEvents...)
+	sort.Strings(extra)
+	for _, name := range extra {
+		if i, ok := index[name]; ok {
+			if len(out[i].Sources) == 1 {
+				out[i].Sources = append(out[i].Sources, gtm.SourceProperty)
+			}
+			continue
+		}
+		index[name] = len(out)
+		out = append(out, gtm.ExpectedEvent{Name: name, Sources: []string{gtm.SourceProperty}})
+	}
+	return out
+}
+
+func displayGTMAudit(r *gtm.AuditReport, c *gtm.Container) error {
+	theme.Cyan("═══ GTM audit: %s (%s) ═══", r.Container, r.Source)
+	theme.Printf("%d tags, %d triggers, %d variables\n", len(c.Tags), len(c.Triggers), len(c.Variables))
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Event", "Declared In", "Status", "Tags"},
+		r.Events, func(e gtm.EventCheck) []string {
+			return []string{e.Name, strings.Join(e.Sources, ", "), gtmStatusLabel(e.Status), strings.Join(e.Tags, ", ")}
+		}); err != nil {
+		return err
+	}
+
+	if len(r.Unexpected) > 0 {
+		theme.Println()
+		theme.Cyan("GA4 Event tags for events that are not conversions:")
+		for _, t := range r.Unexpected {
+			name, _ := c.Resolve(t.EventName)
+			theme.HiBlack("ℹ %s sends %s", t.Name, name)
+		}
+	}
+
+	theme.Println()
+	if n := r.Problems(); n > 0 {
+		theme.Red("✗ %d of %d events are not sent by an active GA4 Event tag.", n, len(r.Events))
+	} else {
+		theme.Green("✓ Every expected event is sent.")
+	}
+	return nil
+}
+
+func gtmStatusLabel(status string) string {
+	switch status {
+	case gtm.StatusWired, gtm.StatusAutomatic:
+		return theme.GreenString("%s", status)
+	case gtm.StatusDynamic:
+		return theme.YellowString("%s", status)
+	default:
+		return theme.RedString("%s", status)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gtm"
+)
+
+func TestExpectedGTMEvents_MergesConfigAndProperty(t *testing.T) {
+	cfg := &config.ProjectConfig{Conversions: []config.ConversionConfig{
+		{Name: "sign_up"}, {Name: "purchase"}, {Name: "sign_up"},
+	}}
+
+	got := expectedGTMEvents(cfg, []string{"purchase", "file_download", "add_to_cart"})
+	assert.Equal(t, []gtm.ExpectedEvent{
+		{Name: "sign_up", Sources: []string{gtm.SourceConfig}},
+		{Name: "purchase", Sources: []string{gtm.SourceConfig, gtm.SourceProperty}},
+		{Name: "add_to_cart", Sources: []string{gtm.SourceProperty}},
+		{Name: "file_download", Sources: []string{gtm.SourceProperty}},
+	}, got)
+}
+
+// The GTM client runs as the --profile identity, not ambient ADC.
+func TestGTMAudit_ProfileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv(auth.CredentialsEnvVar, "")
+	t.Setenv(auth.ProfileEnvVar, "")
+
+	token, err := auth.ProfileTokenPath("client")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(token), 0o700))
+	require.NoError(t, os.WriteFile(token, []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh"}`), 0o600))
+	profiles := &auth.Profiles{Profiles: map[string]auth.Profile{"client": {Type: auth.SourceUser, Path: token}}}
+	require.NoError(t, profiles.Save())
+
+	cfgPath := filepath.Join(dir, "site.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`project:
+  name: Site
+ga4:
+  property_id: "123"
+conversions:
+  - name: sign_up
+    counting_method: ONCE_PER_EVENT
+tag_manager:
+  account_id: "1"
+  container_id: "2"
+`), 0o600))
+
+	errStop := errors.New("stop before any request")
+	var got auth.Source
+	gtmClientFactory = func(_ context.Context, creds auth.Source, _ ...option.ClientOption) (*gtm.Client, error) {
+		got = creds
+		return nil, errStop
+	}
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	t.Cleanup(func() {
+		gtmClientFactory = gtm.NewClient
+		profileFlag, gtmAuditSkipGA4 = "", false
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	rootCmd.SetArgs([]string{"gtm", "audit", "--profile", "client", "--config", cfgPath, "--skip-ga4"})
+	require.ErrorIs(t, rootCmd.Execute(), errStop)
+	assert.Equal(t, auth.Source{Kind: auth.SourceUser, Path: token}, got)
+}
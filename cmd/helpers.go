@@ -2,53 +2,65 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/garbarok/ga4-manager/internal/config"
 )
 
-// loadProjects loads projects based on command flags
+// loadProjects loads projects based on command flags, with multi-property
+// configs expanded to one project per property.
 // Supports --config and --project flags
 func loadProjects(configPath, projectName string, loadAll bool) ([]*config.ProjectConfig, error) {
-	// Priority 1: Load from config file path
-	if configPath != "" {
-		cfg, err := config.LoadConfig(configPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load config: %w", err)
-		}
-		return []*config.ProjectConfig{cfg}, nil
+	projects, err := loadProjectFiles(configPath, projectName, loadAll)
+	if err != nil {
+		return nil, err
 	}
+	projects, _, err = expandProperties(projects, nil)
+	return projects, err
+}
 
-	// Priority 2: Load all available configs (for --all flag)
-	if loadAll {
-		availableConfigs, err := config.ListAvailableConfigs()
-		if err != nil || len(availableConfigs) == 0 {
-			return nil, fmt.Errorf("no config files found in configs/ or configs/examples/")
-		}
+func loadProjectFiles(configPath, projectName string, loadAll bool) ([]*config.ProjectConfig, error) {
+	projects, _, err := loadProjectConfigFiles(configPath, projectName, loadAll)
+	return projects, err
+}
 
-		var projects []*config.ProjectConfig
-		for _, name := range availableConfigs {
-			cfg, err := config.LoadConfigByName(name)
-			if err != nil {
-				continue // Skip configs that fail to load
-			}
-			projects = append(projects, cfg)
+// expandProperties replaces each multi-property config with its per-property
+// configs. paths, when given, holds each config's file and is expanded along.
+func expandProperties(configs []*config.ProjectConfig, paths []string) ([]*config.ProjectConfig, []string, error) {
+	var expanded []*config.ProjectConfig
+	var expandedPaths []string
+	for i, cfg := range configs {
+		targets, err := cfg.ExpandProperties()
+		if err != nil {
+			return nil, nil, fmt.Errorf("config %q: %w", cfg.Project.Name, err)
 		}
-
-		if len(projects) == 0 {
-			return nil, fmt.Errorf("no valid config files found")
+		expanded = append(expanded, targets...)
+		for range targets {
+			if paths != nil {
+				expandedPaths = append(expandedPaths, paths[i])
+			}
 		}
-
-		return projects, nil
 	}
+	return expanded, expandedPaths, nil
+}
 
-	// Priority 3: Load by project name from config files
-	if projectName != "" {
-		cfg, err := config.LoadConfigByName(projectName)
-		if err != nil {
-			return nil, fmt.Errorf("config file not found: %s (use --config to specify a YAML config file)", projectName)
+// selectProperty narrows the configs of a multi-property config to the one
+// for the named property. An empty name keeps them all.
+func selectProperty(configs []*config.ProjectConfig, paths []string, name string) ([]*config.ProjectConfig, []string, error) {
+	if name == "" {
+		return configs, paths, nil
+	}
+	var names []string
+	for i, cfg := range configs {
+		if cfg.PropertyName == name {
+			return configs[i : i+1], paths[i : i+1], nil
+		}
+		if cfg.PropertyName != "" {
+			names = append(names, cfg.PropertyName)
 		}
-		return []*config.ProjectConfig{cfg}, nil
 	}
-
-	return nil, fmt.Errorf("specify --project <name>, --config <path>, or --all")
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("--property %q: the config has no properties list", name)
+	}
+	return nil, nil, fmt.Errorf("--property %q not found (properties: %s)", name, strings.Join(names, ", "))
 }
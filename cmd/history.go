@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// --save / --state-dir, shared by every command that can record history.
+var (
+	historySave     bool
+	historyStateDir string
+)
+
+// addSaveFlags registers --save and --state-dir on a reporting command.
+func addSaveFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&historySave, "save", false, "Record results in the local history store (see `ga4 history`)")
+	cmd.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+}
+
+var (
+	historySite   string
+	historyConfig string
+	historyURL    string
+	historyQuery  string
+	historyDays   int
+	historyFormat string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show how a URL's coverage or a query's position changed over time",
+	Long: `Read results saved with --save from the local history store.
+
+Search Console only keeps 16 months of performance data and shows the current
+inspection state only. Running reports with --save (e.g. from cron) builds a
+local history that outlives both.
+
+Saved by:
+  ga4 gsc analytics run --save    one record per report row
+  ga4 gsc inspect url --save      one record per inspection
+  ga4 gsc monitor run --save      one record per inspected URL
+
+History is stored in a SQLite database, .ga4-state/history.db (or --state-dir).
+
+Examples:
+  # Coverage state of a page across saved inspections
+  ga4 history --site sc-domain:example.com --url https://example.com/pricing
+
+  # Position of a query across saved analytics runs, last 180 days
+  ga4 history --config configs/mysite.yaml --query "image compressor" --days 180
+
+  # Raw records for scripting
+  ga4 history --site sc-domain:example.com --url https://example.com/ --format json`,
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVarP(&historySite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	historyCmd.Flags().StringVarP(&historyConfig, "config", "c", "", "Read the site from search_console.site_url in this config")
+	historyCmd.Flags().StringVarP(&historyURL, "url", "u", "", "Page URL: show its saved inspection states")
+	historyCmd.Flags().StringVarP(&historyQuery, "query", "q", "", "Search query: show its saved analytics rows")
+	historyCmd.Flags().IntVarP(&historyDays, "days", "d", 0, "Only show records saved in the last N days (0 = all)")
+	output.FormatVar(historyCmd.Flags(), &historyFormat, "f", output.FormatTable, output.FormatJSON)
+	historyCmd.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+	historyCmd.MarkFlagsMutuallyExclusive("url", "query")
+	historyCmd.MarkFlagsOneRequired("url", "query")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(historySite, historyConfig)
+	if err != nil {
+		return err
+	}
+	if historyDays < 0 {
+		return fmt.Errorf("--days must not be negative, got %d", historyDays)
+	}
+	q := store.Query{Site: site}
+	if historyURL != "" {
+		q.Kind = store.KindInspection
+		q.Match = map[string]string{"page": historyURL}
+	} else {
+		q.Kind = store.KindAnalytics
+		q.Match = map[string]string{"query": historyQuery}
+	}
+	if historyDays > 0 {
+		q.Since = time.Now().AddDate(0, 0, -historyDays)
+	}
+
+	records, err := store.New(gscstate.ResolveStateDir(historyStateDir)).Query(context.Background(), q)
+	if err != nil {
+		return err
+	}
+
+	if historyFormat == "json" {
+		if records == nil {
+			records = []store.Record{}
+		}
+		return output.JSON(os.Stdout, records)
+	}
+
+	if len(records) == 0 {
+		theme.Yellow("No saved history for %s on %s. Run the report with --save first.", historySubject(), site)
+		return nil
+	}
+	theme.Cyan("═══ History: %s ═══", historySubject())
+	theme.Println()
+	if q.Kind == store.KindInspection {
+		return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"Saved", "Index Status", "Coverage", "Last Crawl", "Change"},
+			inspectionTimeline(records), func(r timelineRow) []string { return r.cells })
+	}
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Saved", "Period", "Page", "Position", "Δ", "Clicks", "Impressions", "CTR"},
+		analyticsTimeline(records), func(r timelineRow) []string { return r.cells })
+}
+
+// siteFromFlags resolves the GSC site from --site, falling back to
+// search_console.site_url in --config.
+func siteFromFlags(site, configPath string) (string, error) {
+	if site != "" {
+		return site, nil
+	}
+	if configPath != "" {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to load config: %w", err)
+		}
+		if cfg.SearchConsole != nil && cfg.SearchConsole.SiteURL != "" {
+			return cfg.SearchConsole.SiteURL, nil
+		}
+		return "", fmt.Errorf("%s has no search_console.site_url", configPath)
+	}
+	return "", fmt.Errorf("missing site: pass --site or --config")
+}
+
+func historySubject() string {
+	if historyURL != "" {
+		return historyURL
+	}
+	return strconv.Quote(historyQuery)
+}
+
+type timelineRow struct {
+	cells []string
+}
+
+// inspectionTimeline flags each record whose coverage state or verdict differs
+// from the one saved before it.
+func inspectionTimeline(records []store.Record) []timelineRow {
+	rows := make([]timelineRow, 0, len(records))
+	var prev store.Record
+	for i, r := range records {
+		change := ""
+		if i > 0 && (r.State["coverage_state"] != prev.State["coverage_state"] || r.State["index_status"] != prev.State["index_status"]) {
+			change = theme.YellowString("⚠ was %s", orUnknownValue(prev.State["coverage_state"]))
+		}
+		rows = append(rows, timelineRow{cells: []string{
+			r.RecordedAt.Local().Format("2006-01-02 15:04"),
+			getColoredStatus(r.State["index_status"]),
+			r.State["coverage_state"],
+			r.State["last_crawl_time"],
+			change,
+		}})
+		prev = r
+	}
+	return rows
+}
+
+// analyticsTimeline lists each saved row with its position change against the
+// previous record for the same page (lower position is better).
+func analyticsTimeline(records []store.Record) []timelineRow {
+	rows := make([]timelineRow, 0, len(records))
+	lastPos := make(map[string]float64)
+	for _, r := range records {
+		page := r.Dimensions["page"]
+		pos := r.Metrics["position"]
+		delta := ""
+		if before, ok := lastPos[page]; ok {
+			switch d := pos - before; {
+			case d < -0.05:
+				delta = theme.GreenString("↑%.1f", -d)
+			case d > 0.05:
+				delta = theme.RedString("↓%.1f", d)
+			default:
+				delta = "="
+			}
+		}
+		lastPos[page] = pos
+
+		period := r.StartDate
+		if r.EndDate != "" {
+			period += "…" + r.EndDate
+		}
+		if page == "" {
+			page = "-"
+		}
+		rows = append(rows, timelineRow{cells: []string{
+			r.RecordedAt.Local().Format("2006-01-02 15:04"),
+			period,
+			page,
+			fmt.Sprintf("%.1f", pos),
+			delta,
+			strconv.FormatFloat(r.Metrics["clicks"], 'f', 0, 64),
+			strconv.FormatFloat(r.Metrics["impressions"], 'f', 0, 64),
+			fmt.Sprintf("%.2f%%", r.Metrics["ctr"]*100),
+		}})
+	}
+	return rows
+}
+
+// analyticsRecords turns a report's rows into history records keyed by the
+// report's dimensions.
+func analyticsRecords(report *gsc.SearchAnalyticsReport) []store.Record {
+	dims := report.Metadata.Dimensions
+	records := make([]store.Record, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		keys := make(map[string]string, len(dims))
+		for i, d := range dims {
+			if i < len(row.Keys) {
+				keys[d] = row.Keys[i]
+			}
+		}
+		records = append(records, store.Record{
+			StartDate:  report.Metadata.StartDate,
+			EndDate:    report.Metadata.EndDate,
+			Dimensions: keys,
+			Metrics: map[string]float64{
+				"clicks":      float64(row.Clicks),
+				"impressions": float64(row.Impressions),
+				"ctr":         row.CTR,
+				"position":    row.Position,
+			},
+		})
+	}
+	return records
+}
+
+// inspectionRecords keeps the inspection fields worth tracking over time.
+func inspectionRecords(results []gsc.URLInspectionResult) []store.Record {
+	records := make([]store.Record, 0, len(results))
+	for _, r := range results {
+		records = append(records, store.Record{
+			Dimensions: map[string]string{"page": r.URL},
+			Metrics:    map[string]float64{"indexing_issues": float64(len(r.IndexingIssues))},
+			State: map[string]string{
+				"index_status":     r.IndexStatus,
+				"coverage_state":   r.CoverageState,
+				"last_crawl_time":  r.LastCrawlTime,
+				"google_canonical": r.GoogleCanonical,
+				"user_canonical":   r.UserCanonical,
+				"issues":           strings.Join(gsc.IssueTypes(r.IndexingIssues), ","),
+			},
+		})
+	}
+	return records
+}
+
+// saveHistory appends records when --save is set. The note goes to stderr so
+// JSON/CSV on stdout stays parseable.
+func saveHistory(kind, site string, records []store.Record) error {
+	if !historySave {
+		return nil
+	}
+	dir := gscstate.ResolveStateDir(historyStateDir)
+	if err := store.New(dir).Append(context.Background(), kind, site, records); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	theme.Fprintf(os.Stderr, "💾 Saved %d %s record(s) to %s\n", len(records), kind, filepath.Join(dir, "history"))
+	return nil
+}
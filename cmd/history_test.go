@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestAnalyticsRecords_KeysByDimension(t *testing.T) {
+	report := &gsc.SearchAnalyticsReport{
+		Rows: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"image compressor", "https://example.com/"}, Clicks: 12, Impressions: 300, CTR: 0.04, Position: 6.5},
+		},
+		Metadata: gsc.ReportMetadata{StartDate: "2026-05-01", EndDate: "2026-05-28", Dimensions: []string{"query", "page"}},
+	}
+
+	records := analyticsRecords(report)
+	require.Len(t, records, 1)
+	assert.Equal(t, "image compressor", records[0].Dimensions["query"])
+	assert.Equal(t, "https://example.com/", records[0].Dimensions["page"])
+	assert.Equal(t, 6.5, records[0].Metrics["position"])
+	assert.Equal(t, "2026-05-28", records[0].EndDate)
+}
+
+func TestInspectionTimeline_FlagsStateChanges(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2026, 6, day, 9, 0, 0, 0, time.UTC) }
+	records := []store.Record{
+		{RecordedAt: at(1), State: map[string]string{"index_status": "PASS", "coverage_state": "Submitted and indexed"}},
+		{RecordedAt: at(8), State: map[string]string{"index_status": "PASS", "coverage_state": "Submitted and indexed"}},
+		{RecordedAt: at(15), State: map[string]string{"index_status": "NEUTRAL", "coverage_state": "Crawled - currently not indexed"}},
+	}
+
+	rows := inspectionTimeline(records)
+	require.Len(t, rows, 3)
+	assert.Empty(t, rows[1].cells[4])
+	assert.True(t, strings.Contains(rows[2].cells[4], "was Submitted and indexed"), rows[2].cells[4])
+}
+
+func TestAnalyticsTimeline_PositionDeltaPerPage(t *testing.T) {
+	records := []store.Record{
+		{Dimensions: map[string]string{"page": "/a"}, Metrics: map[string]float64{"position": 8}},
+		{Dimensions: map[string]string{"page": "/b"}, Metrics: map[string]float64{"position": 2}},
+		{Dimensions: map[string]string{"page": "/a"}, Metrics: map[string]float64{"position": 5}},
+	}
+
+	rows := analyticsTimeline(records)
+	assert.Empty(t, rows[1].cells[4], "first record for a page has no delta")
+	assert.Contains(t, rows[2].cells[4], "3.0")
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/fatih/color"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 	"google.golang.org/api/analyticsadmin/v1alpha"
@@ -37,44 +38,44 @@ func runInitWizard() {
 	// Run the setup wizard
 	config, err := tui.RunSetupWizard()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error running setup wizard: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error running setup wizard: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Save to .env if requested
 	if config.SaveToEnv {
-		fmt.Println("\n💾 Saving configuration to .env file...")
+		theme.Println("\n💾 Saving configuration to .env file...")
 		if err := tui.SaveToEnvFile(config); err != nil {
-			color.Red("✗ Failed to save .env file: %v", err)
+			theme.Red("✗ Failed to save .env file: %v", err)
 			os.Exit(1)
 		}
-		color.Green("✓ Configuration saved to .env")
+		theme.Green("✓ Configuration saved to .env")
 	}
 
 	// Test credentials
-	fmt.Println("\n🔍 Testing credentials...")
+	theme.Println("\n🔍 Testing credentials...")
 	if err := testCredentials(config); err != nil {
-		color.Red("✗ Credential test failed: %v", err)
-		fmt.Println("\nPlease verify:")
-		fmt.Println("  1. The credentials file exists and is valid JSON")
-		fmt.Println("  2. The service account has the required permissions:")
-		fmt.Println("     - Analytics Admin API access")
-		fmt.Println("     - roles/analytics.admin or similar")
-		fmt.Println("\nFor help, see: https://github.com/garbarok/ga4-manager#installation")
+		theme.Red("✗ Credential test failed: %v", err)
+		theme.Println("\nPlease verify:")
+		theme.Println("  1. The credentials file exists and is valid JSON")
+		theme.Println("  2. The service account has the required permissions:")
+		theme.Println("     - Analytics Admin API access")
+		theme.Println("     - roles/analytics.admin or similar")
+		theme.Println("\nFor help, see: https://github.com/garbarok/ga4-manager#installation")
 		os.Exit(1)
 	}
-	color.Green("✓ Credentials are valid!")
+	theme.Green("✓ Credentials are valid!")
 
 	// Print shell instructions
 	tui.PrintShellInstructions(config)
 
 	// Success message
-	successStyle := color.New(color.FgGreen, color.Bold)
-	fmt.Println()
+	successStyle := theme.Color(color.FgGreen, color.Bold)
+	theme.Println()
 	_, _ = successStyle.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	_, _ = successStyle.Println("🎉 Setup Complete!")
 	_, _ = successStyle.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
+	theme.Println()
 }
 
 // testCredentials tests the credentials by attempting to create an Analytics Admin client
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // Embedded template configurations
@@ -136,7 +139,7 @@ metrics:
 // ensureConfigDirectoryExists checks if the configs directory exists
 // and creates it with example templates if it doesn't
 func ensureConfigDirectoryExists() error {
-	configsDir := "configs"
+	configsDir := config.ConfigDir()
 	examplesDir := filepath.Join(configsDir, "examples")
 
 	// Check if configs directory exists
@@ -154,16 +157,16 @@ func ensureConfigDirectoryExists() error {
 
 // setupFirstRun creates the initial directory structure and templates
 func setupFirstRun(configsDir, examplesDir string) error {
-	green := color.New(color.FgGreen).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
 
-	fmt.Println()
-	fmt.Println(cyan("🎉 Welcome to GA4 Manager!"))
-	fmt.Println(strings.Repeat("═", 50))
-	fmt.Println()
-	fmt.Println(yellow("📁 First-time setup: Creating configuration directories..."))
-	fmt.Println()
+	theme.Println()
+	theme.Println(cyan("🎉 Welcome to GA4 Manager!"))
+	theme.Println(strings.Repeat("═", 50))
+	theme.Println()
+	theme.Println(yellow("📁 First-time setup: Creating configuration directories..."))
+	theme.Println()
 
 	// Create directories
 	if err := os.MkdirAll(examplesDir, 0755); err != nil {
@@ -182,7 +185,7 @@ func setupFirstRun(configsDir, examplesDir string) error {
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 			return fmt.Errorf("failed to create %s: %w", filename, err)
 		}
-		fmt.Printf("  %s Created: %s\n", green("✓"), path)
+		theme.Printf("  %s Created: %s\n", green("✓"), path)
 	}
 
 	// Create README in configs directory
@@ -251,25 +254,25 @@ For more information:
 	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
 		return fmt.Errorf("failed to create README: %w", err)
 	}
-	fmt.Printf("  %s Created: %s\n", green("✓"), readmePath)
-
-	fmt.Println()
-	fmt.Println(green("✅ Setup complete!"))
-	fmt.Println()
-	fmt.Println("📚 Next steps:")
-	fmt.Println("  1. Copy a template:")
-	fmt.Println("     " + cyan("cp configs/examples/template.yaml configs/my-project.yaml"))
-	fmt.Println()
-	fmt.Println("  2. Edit your config:")
-	fmt.Println("     " + cyan("nano configs/my-project.yaml"))
-	fmt.Println("     (or use your preferred editor)")
-	fmt.Println()
-	fmt.Println("  3. Update your GA4 Property ID in the config file")
-	fmt.Println()
-	fmt.Println("  4. Run the interactive mode:")
-	fmt.Println("     " + cyan("./ga4"))
-	fmt.Println()
-	fmt.Println(yellow("Press Enter to continue to the main menu..."))
+	theme.Printf("  %s Created: %s\n", green("✓"), readmePath)
+
+	theme.Println()
+	theme.Println(green("✅ Setup complete!"))
+	theme.Println()
+	theme.Println("📚 Next steps:")
+	theme.Println("  1. Copy a template:")
+	theme.Println("     " + cyan("cp configs/examples/template.yaml configs/my-project.yaml"))
+	theme.Println()
+	theme.Println("  2. Edit your config:")
+	theme.Println("     " + cyan("nano configs/my-project.yaml"))
+	theme.Println("     (or use your preferred editor)")
+	theme.Println()
+	theme.Println("  3. Update your GA4 Property ID in the config file")
+	theme.Println()
+	theme.Println("  4. Run the interactive mode:")
+	theme.Println("     " + cyan("./ga4"))
+	theme.Println()
+	theme.Println(yellow("Press Enter to continue to the main menu..."))
 	_, _ = fmt.Scanln()
 
 	return nil
@@ -277,12 +280,12 @@ For more information:
 
 // createExamplesDirectory creates just the examples directory with templates
 func createExamplesDirectory(examplesDir string) error {
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
 
-	fmt.Println()
-	fmt.Println(yellow("📁 Creating examples directory with templates..."))
-	fmt.Println()
+	theme.Println()
+	theme.Println(yellow("📁 Creating examples directory with templates..."))
+	theme.Println()
 
 	if err := os.MkdirAll(examplesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create examples directory: %w", err)
@@ -299,12 +302,12 @@ func createExamplesDirectory(examplesDir string) error {
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 			return fmt.Errorf("failed to create %s: %w", filename, err)
 		}
-		fmt.Printf("  %s Created: %s\n", green("✓"), path)
+		theme.Printf("  %s Created: %s\n", green("✓"), path)
 	}
 
-	fmt.Println()
-	fmt.Println(green("✅ Examples directory created!"))
-	fmt.Println()
+	theme.Println()
+	theme.Println(green("✅ Examples directory created!"))
+	theme.Println()
 
 	return nil
 }
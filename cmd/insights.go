@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/contentgroup"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/insights"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/notify"
+	"github.com/garbarok/ga4-manager/internal/output"
+)
+
+const (
+	insightsCommandName = "insights"
+	insightsRowLimit    = 25000
+)
+
+var (
+	insightsConfig   string
+	insightsFormat   string
+	insightsRules    []string
+	insightsNoNotify bool
+)
+
+var insightsCmd = &cobra.Command{
+	Use:   "insights",
+	Short: "Evaluate the config's custom insight rules",
+}
+
+var insightsRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Evaluate the insights: rules against Search Console data and notify",
+	Long: `Evaluate the rules under insights: in the config against Search Analytics
+data, and list what they flag, most urgent first (severity, then clicks lost).
+The findings are also sent to each insights.notify sink.
+
+Each rule compares the last days (default 28) with the days before them, over
+the rows its pages and queries patterns keep, in total or per page, query or
+content group (by):
+
+  insights:
+    notify:
+      - webhook: ${SLACK_WEBHOOK_URL}
+      - file: reports/insights.jsonl
+    rules:
+      - name: tools-position-drop
+        description: Tools pages are slipping
+        pages: /tools/*
+        when: position_delta > 2
+        severity: high
+      - name: query-collapse
+        by: query
+        when: clicks_change <= -50% and clicks_prior >= 20
+
+Conditions compare clicks, impressions, ctr and position, each also as
+_prior (previous window), _delta (current − prior) and _change (relative,
+-20% is -0.2), with and, or, not, + - * / and parentheses. A higher position
+is worse: position_delta > 0 is a drop.
+
+Markers added with ` + "`ga4 marker add`" + ` and Google algorithm updates that fall
+in the compared windows are listed under the findings and included in the
+JSON output.
+
+Exit codes:
+  0  no rule flagged anything
+  4  at least one finding
+  1  command failed: bad flags, config or rule
+  2  Google API error
+  3  quota exhausted
+
+Examples:
+  ga4 insights run --config configs/mysite.yaml
+  ga4 insights run --config configs/mysite.yaml --rule tools-position-drop --no-notify
+  ga4 insights run --config configs/mysite.yaml --format json`,
+	Args: cobra.NoArgs,
+	RunE: insightsRunE,
+}
+
+func init() {
+	rootCmd.AddCommand(insightsCmd)
+	insightsCmd.AddCommand(insightsRunCmd)
+	insightsRunCmd.Flags().StringVarP(&insightsConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(insightsRunCmd.Flags(), &insightsFormat, "f", output.FormatTable, output.FormatJSON)
+	insightsRunCmd.Flags().StringSliceVar(&insightsRules, "rule", nil, "Only evaluate these rules (repeatable)")
+	insightsRunCmd.Flags().BoolVar(&insightsNoNotify, "no-notify", false, "Print the findings without sending them to insights.notify")
+	_ = insightsRunCmd.MarkFlagRequired("config")
+}
+
+var insightsClientFactory = func() (gsc.SearchAPI, func(), error) {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, func() { _ = client.Close() }, nil
+}
+
+// InsightsOutput is the --format json document and the notification payload.
+type InsightsOutput = diagcmd.Envelope[insights.Finding]
+
+func insightsRunE(cmd *cobra.Command, _ []string) error {
+	return exitWith(cmd, runInsightsCommand(insightsParams{
+		ConfigPath: insightsConfig,
+		Format:     insightsFormat,
+		Rules:      insightsRules,
+		Notify:     !insightsNoNotify,
+		Factory:    insightsClientFactory,
+		StateDir:   gscstate.ResolveStateDir(historyStateDir),
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+		Now:        time.Now().UTC(),
+	}))
+}
+
+type insightsParams struct {
+	ConfigPath string
+	Format     string
+	Rules      []string
+	Notify     bool
+	Factory    func() (gsc.SearchAPI, func(), error)
+	StateDir   string
+	Stdout     io.Writer
+	Stderr     io.Writer
+	Now        time.Time
+}
+
+func runInsightsCommand(p insightsParams) int {
+	if err := diagcmd.ValidateFormat(p.Format); err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	site, cfg, err := diagcmd.LoadSite(p.ConfigPath)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	rules, grouper, err := insightsRulesFor(cfg, p.Rules)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	client, cleanup, err := p.Factory()
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to create GSC client: %v", err)
+	}
+	defer cleanup()
+
+	findings, quota, err := evaluateInsights(client, site, rules, grouper, p.Now)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	env := diagcmd.NewEnvelope(insightsCommandName, site, p.Now, findings, quota)
+	if env.Markers, err = insightsMarkers(p.StateDir, site, rules, p.Now); err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to read markers: %v", err)
+	}
+
+	if err := diagcmd.Render(p.Stdout, env, p.Format, insightsColumns, insightsTextRow); err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to render output: %v", err)
+	}
+
+	if p.Notify && len(findings) > 0 && len(cfg.Insights.Notify) > 0 {
+		sinks := notify.FromConfig(cfg.Insights.Notify)
+		if err := notify.SendAll(context.Background(), sinks, insightsMessage(cfg.Project.Name, env)); err != nil {
+			return diagcmd.FailWith(p.Stderr, "failed to send insights: %v", err)
+		}
+	}
+	return diagcmd.ExitCode(nil, len(findings) > 0)
+}
+
+// insightsRulesFor compiles the config's rules, or those named, and builds
+// the content grouper when a rule groups by content group.
+func insightsRulesFor(cfg *config.ProjectConfig, names []string) ([]insights.Rule, *contentgroup.Grouper, error) {
+	if cfg.Insights == nil || len(cfg.Insights.Rules) == 0 {
+		return nil, nil, fmt.Errorf("no insights.rules in the config")
+	}
+	selected := cfg.Insights.Rules
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			i := slices.IndexFunc(cfg.Insights.Rules, func(r config.InsightRuleConfig) bool { return r.Name == name })
+			if i < 0 {
+				return nil, nil, fmt.Errorf("no insight rule named %q", name)
+			}
+			selected = append(selected, cfg.Insights.Rules[i])
+		}
+	}
+	rules, err := insights.CompileRules(selected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var grouper *contentgroup.Grouper
+	for _, r := range rules {
+		if r.By != config.InsightByContentGroup {
+			continue
+		}
+		if len(cfg.ContentGroups) == 0 {
+			return nil, nil, fmt.Errorf("insight %q groups by content_group but the config has no content_groups", r.Name)
+		}
+		if grouper, err = contentGrouper(cfg.ContentGroups); err != nil {
+			return nil, nil, err
+		}
+		break
+	}
+	return rules, grouper, nil
+}
+
+// evaluateInsights queries each window a rule needs once, whatever the
+// number of rules sharing it, and returns the prioritised findings and the
+// quota used.
+func evaluateInsights(client gsc.SearchAPI, site string, rules []insights.Rule, grouper *contentgroup.Grouper, now time.Time) ([]insights.Finding, int, error) {
+	reports := map[string]*gsc.SearchAnalyticsReport{}
+	quota := 0
+	fetch := func(start, end string, dims []string) ([]gsc.SearchAnalyticsRow, error) {
+		key := start + "|" + end + "|" + strings.Join(dims, ",")
+		if r, ok := reports[key]; ok {
+			return r.Rows, nil
+		}
+		r, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+			SiteURL:    site,
+			StartDate:  start,
+			EndDate:    end,
+			Dimensions: dims,
+			RowLimit:   insightsRowLimit,
+			DataState:  "final",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("search analytics %s to %s failed: %w", start, end, err)
+		}
+		reports[key] = r
+		quota = r.QuotaUsed
+		return r.Rows, nil
+	}
+
+	var findings []insights.Finding
+	for _, r := range rules {
+		curStart, curEnd, priorStart, priorEnd := ctrAnomalyWindows(now, r.Days)
+		current, err := fetch(curStart, curEnd, r.Dimensions())
+		if err != nil {
+			return nil, 0, err
+		}
+		prior, err := fetch(priorStart, priorEnd, r.Dimensions())
+		if err != nil {
+			return nil, 0, err
+		}
+		findings = append(findings, r.Evaluate(r.Groups(current, prior, grouper))...)
+	}
+	insights.Prioritize(findings)
+	return findings, quota, nil
+}
+
+// insightsMarkers returns the site's markers in the windows the rules
+// compared, from the start of the longest prior window to yesterday.
+func insightsMarkers(stateDir, site string, rules []insights.Rule, now time.Time) ([]markers.Marker, error) {
+	days := 0
+	for _, r := range rules {
+		days = max(days, r.Days)
+	}
+	_, end, start, _ := ctrAnomalyWindows(now, days)
+	return reportMarkers(stateDir, site, start, end)
+}
+
+// insightsMessage summarises the findings for the notification sinks, one
+// line per finding.
+func insightsMessage(project string, env InsightsOutput) notify.Message {
+	var b strings.Builder
+	for _, f := range env.Results {
+		fmt.Fprintf(&b, "[%s] %s: %s (%s)", f.Severity, f.Rule, f.Subject, f.When)
+		if f.Description != "" {
+			fmt.Fprintf(&b, " — %s", f.Description)
+		}
+		b.WriteByte('\n')
+	}
+	return notify.Message{
+		Title:   fmt.Sprintf("%s: %d insight(s) for %s", project, len(env.Results), env.Site),
+		Text:    b.String(),
+		Payload: env,
+	}
+}
+
+var insightsColumns = []string{"severity", "rule", "subject", "when", "values", "clicks_lost"}
+
+func insightsTextRow(f insights.Finding) []string {
+	keys := make([]string, 0, len(f.Values))
+	for k := range f.Values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = k + "=" + strconv.FormatFloat(f.Values[k], 'f', 2, 64)
+	}
+	return []string{
+		f.Severity,
+		f.Rule,
+		f.Subject,
+		f.When,
+		strings.Join(values, " "),
+		strconv.FormatInt(f.ClicksLost, 10),
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/insights"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+// fakeInsightsClient answers the current window with current and the prior
+// one with prior, telling them apart by start date.
+type fakeInsightsClient struct {
+	currentStart string
+	current      []gsc.SearchAnalyticsRow
+	prior        []gsc.SearchAnalyticsRow
+	queries      []gsc.SearchAnalyticsQuery
+}
+
+func (f *fakeInsightsClient) QuerySearchAnalytics(q *gsc.SearchAnalyticsQuery) (*gsc.SearchAnalyticsReport, error) {
+	f.queries = append(f.queries, *q)
+	rows := f.prior
+	if q.StartDate == f.currentStart {
+		rows = f.current
+	}
+	return &gsc.SearchAnalyticsReport{Rows: rows, QuotaUsed: len(f.queries)}, nil
+}
+
+func writeInsightsConfig(t *testing.T, notifyFile string) string {
+	t.Helper()
+	body := `project:
+  name: example
+search_console:
+  site_url: sc-domain:example.com
+insights:
+  notify:
+    - file: ` + notifyFile + `
+  rules:
+    - name: tools-position-drop
+      pages: /tools/*
+      when: position_delta > 2
+      severity: high
+    - name: page-clicks-drop
+      by: page
+      pages: /tools/*
+      when: clicks_change <= -50%
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestRunInsights_FindsNotifiesAndExitsFour(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	curStart, _, _, _ := ctrAnomalyWindows(now, insights.DefaultDays)
+	fake := &fakeInsightsClient{
+		currentStart: curStart,
+		current: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"https://example.com/tools/a"}, Clicks: 4, Impressions: 100, Position: 9},
+			{Keys: []string{"https://example.com/tools/b"}, Clicks: 30, Impressions: 100, Position: 3},
+		},
+		prior: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"https://example.com/tools/a"}, Clicks: 20, Impressions: 100, Position: 4},
+			{Keys: []string{"https://example.com/tools/b"}, Clicks: 30, Impressions: 100, Position: 3},
+		},
+	}
+	notifyFile := filepath.Join(t.TempDir(), "insights.jsonl")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	stateDir := t.TempDir()
+	history := store.New(stateDir)
+	deploy, err := markers.Add(context.Background(), history, "sc-domain:example.com", markers.Marker{Date: "2026-06-10", Label: "tools redesign"})
+	require.NoError(t, err)
+	_, err = markers.Add(context.Background(), history, "sc-domain:example.com", markers.Marker{Date: "2026-04-01", Label: "too old"})
+	require.NoError(t, err)
+
+	code := runInsightsCommand(insightsParams{
+		ConfigPath: writeInsightsConfig(t, notifyFile),
+		Format:     diagcmd.FormatJSON,
+		Notify:     true,
+		Factory:    func() (gsc.SearchAPI, func(), error) { return fake, func() {}, nil },
+		StateDir:   stateDir,
+		Stdout:     stdout,
+		Stderr:     stderr,
+		Now:        now,
+	})
+
+	require.Equal(t, diagcmd.ExitIssues, code, stderr.String())
+	assert.Len(t, fake.queries, 2, "both rules share the same windows and dimensions")
+
+	var env InsightsOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &env))
+	require.Len(t, env.Results, 2)
+	assert.Equal(t, "tools-position-drop", env.Results[0].Rule, "high severity first")
+	assert.Equal(t, "page-clicks-drop", env.Results[1].Rule)
+	assert.Equal(t, "https://example.com/tools/a", env.Results[1].Subject)
+	assert.Equal(t, []markers.Marker{deploy}, env.Markers)
+
+	data, err := os.ReadFile(notifyFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "2 insight(s) for sc-domain:example.com")
+}
+
+func TestRunInsights_RuleSelectionAndNoNotify(t *testing.T) {
+	fake := &fakeInsightsClient{}
+	notifyFile := filepath.Join(t.TempDir(), "insights.jsonl")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	params := insightsParams{
+		ConfigPath: writeInsightsConfig(t, notifyFile),
+		Format:     diagcmd.FormatTable,
+		Rules:      []string{"page-clicks-drop"},
+		Factory:    func() (gsc.SearchAPI, func(), error) { return fake, func() {}, nil },
+		StateDir:   t.TempDir(),
+		Stdout:     stdout,
+		Stderr:     stderr,
+		Now:        time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, diagcmd.ExitClean, runInsightsCommand(params))
+	assert.Equal(t, "quota used: 2\n", stdout.String())
+	_, err := os.Stat(notifyFile)
+	assert.True(t, os.IsNotExist(err))
+
+	params.Rules = []string{"missing"}
+	assert.Equal(t, diagcmd.ExitFailure, runInsightsCommand(params))
+	assert.True(t, strings.Contains(stderr.String(), `no insight rule named "missing"`))
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // offerAutoInstall checks if the binary is running from a non-standard location
@@ -76,26 +78,26 @@ func isInGitRepo(execPath string) bool {
 
 // promptAutoInstall displays the auto-install prompt and handles user choice
 func promptAutoInstall(execPath string) {
-	cyan := color.New(color.FgCyan).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-
-	fmt.Println()
-	fmt.Println(cyan("📦 Installation Options"))
-	fmt.Println(strings.Repeat("═", 50))
-	fmt.Println()
-	fmt.Println("To run GA4 Manager from anywhere without " + yellow("./") + ", you need to install it to your system PATH.")
-	fmt.Println()
-	fmt.Println(yellow("Option 1: Automatic Installation (Recommended)"))
-	fmt.Println("  • Installs to: " + green("/usr/local/bin/ga4"))
-	fmt.Println("  • Requires: sudo password (for system directory access)")
-	fmt.Println("  • After install: Run " + cyan("ga4") + " from anywhere")
-	fmt.Println()
-	fmt.Println(yellow("Option 2: Manual Installation"))
-	fmt.Println("  • You handle installation yourself")
-	fmt.Println("  • Continue using: " + cyan("./ga4"))
-	fmt.Println()
-	fmt.Print("Would you like to install automatically? (Y/n): ")
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+
+	theme.Println()
+	theme.Println(cyan("📦 Installation Options"))
+	theme.Println(strings.Repeat("═", 50))
+	theme.Println()
+	theme.Println("To run GA4 Manager from anywhere without " + yellow("./") + ", you need to install it to your system PATH.")
+	theme.Println()
+	theme.Println(yellow("Option 1: Automatic Installation (Recommended)"))
+	theme.Println("  • Installs to: " + green("/usr/local/bin/ga4"))
+	theme.Println("  • Requires: sudo password (for system directory access)")
+	theme.Println("  • After install: Run " + cyan("ga4") + " from anywhere")
+	theme.Println()
+	theme.Println(yellow("Option 2: Manual Installation"))
+	theme.Println("  • You handle installation yourself")
+	theme.Println("  • Continue using: " + cyan("./ga4"))
+	theme.Println()
+	theme.Print("Would you like to install automatically? (Y/n): ")
 
 	reader := bufio.NewReader(os.Stdin)
 	response, _ := reader.ReadString('\n')
@@ -103,30 +105,30 @@ func promptAutoInstall(execPath string) {
 
 	// Default to yes if empty or 'y'
 	if response == "" || response == "y" || response == "yes" {
-		fmt.Println()
+		theme.Println()
 		performAutoInstall(execPath)
 	} else {
-		fmt.Println()
+		theme.Println()
 		showManualInstallInstructions(execPath)
 	}
 }
 
 // performAutoInstall attempts to install the binary to /usr/local/bin
 func performAutoInstall(execPath string) {
-	cyan := color.New(color.FgCyan).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
 
 	installPath := "/usr/local/bin/ga4"
 
-	fmt.Println(yellow("🔒 Why sudo is needed:"))
-	fmt.Println("   /usr/local/bin is a system directory that requires administrator")
-	fmt.Println("   privileges to write files. This ensures only authorized users can")
-	fmt.Println("   install system-wide commands.")
-	fmt.Println()
-	fmt.Println(cyan("Installing..."))
-	fmt.Println()
+	theme.Println(yellow("🔒 Why sudo is needed:"))
+	theme.Println("   /usr/local/bin is a system directory that requires administrator")
+	theme.Println("   privileges to write files. This ensures only authorized users can")
+	theme.Println("   install system-wide commands.")
+	theme.Println()
+	theme.Println(cyan("Installing..."))
+	theme.Println()
 
 	// Check if /usr/local/bin exists, create if not
 	binDir := "/usr/local/bin"
@@ -136,8 +138,8 @@ func performAutoInstall(execPath string) {
 		cmd.Stderr = os.Stderr
 		cmd.Stdin = os.Stdin
 		if err := cmd.Run(); err != nil {
-			fmt.Println(red("✗ Failed to create /usr/local/bin directory"))
-			fmt.Println()
+			theme.Println(red("✗ Failed to create /usr/local/bin directory"))
+			theme.Println()
 			showManualInstallInstructions(execPath)
 			return
 		}
@@ -150,13 +152,13 @@ func performAutoInstall(execPath string) {
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
-		fmt.Println()
-		fmt.Println(red("✗ Installation failed"))
-		fmt.Println()
-		fmt.Println("This could be because:")
-		fmt.Println("  • Sudo password was incorrect or cancelled")
-		fmt.Println("  • Insufficient permissions")
-		fmt.Println()
+		theme.Println()
+		theme.Println(red("✗ Installation failed"))
+		theme.Println()
+		theme.Println("This could be because:")
+		theme.Println("  • Sudo password was incorrect or cancelled")
+		theme.Println("  • Insufficient permissions")
+		theme.Println()
 		showManualInstallInstructions(execPath)
 		return
 	}
@@ -165,86 +167,86 @@ func performAutoInstall(execPath string) {
 	cmd = exec.Command("sudo", "chmod", "+x", installPath)
 	_ = cmd.Run() // Ignore errors, likely already executable
 
-	fmt.Println()
-	fmt.Println(green("✓ Successfully installed to " + installPath))
-	fmt.Println()
-	fmt.Println(cyan("🎉 You can now run:"))
-	fmt.Println("   " + green("ga4") + " --version")
-	fmt.Println("   " + green("ga4") + " report --all")
-	fmt.Println("   " + green("ga4") + " setup --config configs/my-project.yaml")
-	fmt.Println()
-	fmt.Println(yellow("Note: ") + "You may need to open a new terminal for PATH changes to take effect.")
-	fmt.Println()
-	fmt.Println(yellow("Press Enter to continue..."))
+	theme.Println()
+	theme.Println(green("✓ Successfully installed to " + installPath))
+	theme.Println()
+	theme.Println(cyan("🎉 You can now run:"))
+	theme.Println("   " + green("ga4") + " --version")
+	theme.Println("   " + green("ga4") + " report --all")
+	theme.Println("   " + green("ga4") + " setup --config configs/my-project.yaml")
+	theme.Println()
+	theme.Println(yellow("Note: ") + "You may need to open a new terminal for PATH changes to take effect.")
+	theme.Println()
+	theme.Println(yellow("Press Enter to continue..."))
 	_, _ = fmt.Scanln()
 }
 
 // showManualInstallInstructions displays manual installation steps
 func showManualInstallInstructions(execPath string) {
-	cyan := color.New(color.FgCyan).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-
-	fmt.Println(yellow("📝 Manual Installation Instructions"))
-	fmt.Println(strings.Repeat("─", 50))
-	fmt.Println()
-	fmt.Println("To install GA4 Manager manually, choose one of these options:")
-	fmt.Println()
-
-	fmt.Println(cyan("Option 1: Install to system PATH (Recommended)"))
-	fmt.Println("────────────────────────────────────────────────")
-	fmt.Println(green("sudo cp " + execPath + " /usr/local/bin/ga4"))
-	fmt.Println(green("sudo chmod +x /usr/local/bin/ga4"))
-	fmt.Println()
-	fmt.Println("Then run: " + cyan("ga4") + " from anywhere")
-	fmt.Println()
-
-	fmt.Println(cyan("Option 2: Add current location to PATH"))
-	fmt.Println("────────────────────────────────────────────────")
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+
+	theme.Println(yellow("📝 Manual Installation Instructions"))
+	theme.Println(strings.Repeat("─", 50))
+	theme.Println()
+	theme.Println("To install GA4 Manager manually, choose one of these options:")
+	theme.Println()
+
+	theme.Println(cyan("Option 1: Install to system PATH (Recommended)"))
+	theme.Println("────────────────────────────────────────────────")
+	theme.Println(green("sudo cp " + execPath + " /usr/local/bin/ga4"))
+	theme.Println(green("sudo chmod +x /usr/local/bin/ga4"))
+	theme.Println()
+	theme.Println("Then run: " + cyan("ga4") + " from anywhere")
+	theme.Println()
+
+	theme.Println(cyan("Option 2: Add current location to PATH"))
+	theme.Println("────────────────────────────────────────────────")
 	execDir := filepath.Dir(execPath)
 
 	// Detect shell and provide appropriate instructions
 	shell := os.Getenv("SHELL")
 
 	if strings.Contains(shell, "fish") {
-		fmt.Println("For Fish shell (detected), add to " + cyan("~/.config/fish/config.fish") + ":")
-		fmt.Println(green("fish_add_path " + execDir))
-		fmt.Println()
-		fmt.Println("Or create an alias:")
-		fmt.Println(green("alias ga4='" + execPath + "'"))
+		theme.Println("For Fish shell (detected), add to " + cyan("~/.config/fish/config.fish") + ":")
+		theme.Println(green("fish_add_path " + execDir))
+		theme.Println()
+		theme.Println("Or create an alias:")
+		theme.Println(green("alias ga4='" + execPath + "'"))
 	} else if strings.Contains(shell, "zsh") {
-		fmt.Println("For Zsh (detected), add to " + cyan("~/.zshrc") + ":")
-		fmt.Println(green("export PATH=\"" + execDir + ":$PATH\""))
-		fmt.Println()
-		fmt.Println("Or create an alias:")
-		fmt.Println(green("alias ga4='" + execPath + "'"))
+		theme.Println("For Zsh (detected), add to " + cyan("~/.zshrc") + ":")
+		theme.Println(green("export PATH=\"" + execDir + ":$PATH\""))
+		theme.Println()
+		theme.Println("Or create an alias:")
+		theme.Println(green("alias ga4='" + execPath + "'"))
 	} else {
 		// Default to bash
-		fmt.Println("For Bash, add to " + cyan("~/.bashrc") + " or " + cyan("~/.bash_profile") + ":")
-		fmt.Println(green("export PATH=\"" + execDir + ":$PATH\""))
-		fmt.Println()
-		fmt.Println("Or create an alias:")
-		fmt.Println(green("alias ga4='" + execPath + "'"))
+		theme.Println("For Bash, add to " + cyan("~/.bashrc") + " or " + cyan("~/.bash_profile") + ":")
+		theme.Println(green("export PATH=\"" + execDir + ":$PATH\""))
+		theme.Println()
+		theme.Println("Or create an alias:")
+		theme.Println(green("alias ga4='" + execPath + "'"))
 	}
 
-	fmt.Println()
-	fmt.Println("Then reload your shell:")
+	theme.Println()
+	theme.Println("Then reload your shell:")
 	if strings.Contains(shell, "fish") {
-		fmt.Println(green("source ~/.config/fish/config.fish"))
+		theme.Println(green("source ~/.config/fish/config.fish"))
 	} else if strings.Contains(shell, "zsh") {
-		fmt.Println(green("source ~/.zshrc"))
+		theme.Println(green("source ~/.zshrc"))
 	} else {
-		fmt.Println(green("source ~/.bashrc"))
+		theme.Println(green("source ~/.bashrc"))
 	}
-	fmt.Println()
+	theme.Println()
 
-	fmt.Println(cyan("Option 3: Continue using ./ga4"))
-	fmt.Println("────────────────────────────────────────────────")
-	fmt.Println("Run from current directory:")
-	fmt.Println(green("./ga4") + " --version")
-	fmt.Println(green("./ga4") + " report --all")
-	fmt.Println()
+	theme.Println(cyan("Option 3: Continue using ./ga4"))
+	theme.Println("────────────────────────────────────────────────")
+	theme.Println("Run from current directory:")
+	theme.Println(green("./ga4") + " --version")
+	theme.Println(green("./ga4") + " report --all")
+	theme.Println()
 
-	fmt.Println(yellow("Press Enter to continue..."))
+	theme.Println(yellow("Press Enter to continue..."))
 	_, _ = fmt.Scanln()
 }
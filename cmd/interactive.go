@@ -5,6 +5,7 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -27,7 +28,7 @@ func init() {
 func RunInteractive() {
 	// Ensure config directory exists on first run
 	if err := ensureConfigDirectoryExists(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error setting up config directory: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error setting up config directory: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -41,14 +42,14 @@ func RunInteractive() {
 
 		finalModel, err := p.Run()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error running interactive menu: %v\n", err)
+			theme.Fprintf(os.Stderr, "Error running interactive menu: %v\n", err)
 			os.Exit(1)
 		}
 
 		// Get the selected action
 		menuModel, ok := finalModel.(tui.MenuModel)
 		if !ok {
-			fmt.Fprintln(os.Stderr, "Error: unexpected model type")
+			theme.Fprintln(os.Stderr, "Error: unexpected model type")
 			os.Exit(1)
 		}
 
@@ -60,8 +61,8 @@ func RunInteractive() {
 		}
 
 		// Pause before returning to menu
-		if selected != "link" && selected != "exit" {
-			fmt.Println("\nPress Enter to return to menu...")
+		if selected != "link" && selected != "dashboard" && selected != "exit" {
+			theme.Println("\nPress Enter to return to menu...")
 			_, _ = fmt.Scanln()
 		}
 	}
@@ -76,6 +77,8 @@ func routeAction(action string) bool {
 		runInitWizard()
 	case "report":
 		handleReportAction()
+	case "dashboard":
+		handleDashboardAction()
 	case "export":
 		handleExportAction()
 	case "setup":
@@ -89,7 +92,7 @@ func routeAction(action string) bool {
 	case "exit":
 		return false
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown action: %s\n", action)
+		theme.Fprintf(os.Stderr, "Unknown action: %s\n", action)
 	}
 	return true
 }
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/garbarok/ga4-manager/internal/approval"
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +23,8 @@ var (
 	linkDataset    string
 	listLinks      bool
 	unlinkService  string
+	linkApproval   string
+	linkApply      bool
 )
 
 var linkCmd = &cobra.Command{
@@ -29,11 +35,15 @@ var linkCmd = &cobra.Command{
 Supported services for linking:
   - search-console: Provides a setup guide for linking Google Search Console.
   - bigquery: Creates a BigQuery export link.
-  - channels: Sets up default channel groupings.
+  - channels: Sets up the config's channel_groups, or the default groupings.
 
 Supported services for unlinking:
   - bigquery: Deletes a BigQuery export link.
-  - channels: Deletes a custom channel group.`,
+  - channels: Deletes every custom channel group.
+
+With --unlink channels --require-approval FILE the channel groups are written
+to FILE for a second person to sign with ` + "`ga4 approve FILE`" + ` instead of
+being deleted; adding --apply deletes them once approved.`,
 	RunE: runLink,
 }
 
@@ -46,18 +56,21 @@ func init() {
 	linkCmd.Flags().StringVar(&linkDataset, "dataset", "", "BigQuery dataset ID")
 	linkCmd.Flags().BoolVarP(&listLinks, "list", "l", false, "List existing links")
 	linkCmd.Flags().StringVar(&unlinkService, "unlink", "", "Service to unlink (e.g., bigquery, channels)")
+	linkCmd.Flags().StringVar(&linkApproval, "require-approval", "", "With --unlink channels, write the groups to this pending-operations file for a second person to approve instead of deleting them")
+	linkCmd.Flags().BoolVar(&linkApply, "apply", false, "With --require-approval, delete the groups once the file is approved")
 	_ = linkCmd.MarkFlagRequired("project")
 }
 
 func runLink(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔗 GA4 Manager - Link External Services")
-	fmt.Println("═══════════════════════════════════════════════")
-
-	client, err := newGA4Client()
-	if err != nil {
+	if err := checkApprovalFlags(linkApproval, linkApply, false); err != nil {
 		return err
 	}
-	defer client.Close()
+	if linkApproval != "" && unlinkService != "channels" {
+		return fmt.Errorf("--require-approval only applies with --unlink channels")
+	}
+
+	theme.Println("🔗 GA4 Manager - Link External Services")
+	theme.Println("═══════════════════════════════════════════════")
 
 	// Load project from config file
 	cfg, err := config.LoadConfigByName(projectName)
@@ -65,13 +78,23 @@ func runLink(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w (use --project to specify a config file name)", err)
 	}
 
-	fmt.Printf("📦 Project: %s (Property: %s)\n", cfg.Project.Name, cfg.GetPropertyID())
-	fmt.Println("───────────────────────────────────────────────")
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	theme.Printf("📦 Project: %s (Property: %s)\n", cfg.Project.Name, cfg.GetPropertyID())
+	theme.Println("───────────────────────────────────────────────")
 
 	if listLinks {
 		return listExistingLinks(client, cfg)
 	}
 
+	if linkApproval != "" {
+		return unlinkChannelGroupsWithApproval(client, cfg, linkApproval, linkApply)
+	}
+
 	if unlinkService != "" {
 		return unlinkExternalService(client, cfg, unlinkService)
 	}
@@ -99,28 +122,28 @@ func handleLinkAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
 	// Validate single project selection
 	if projectPath == "--all" {
-		fmt.Println("\n⚠️  Link management requires a specific project.")
-		fmt.Println("Please select a single project instead of 'All Projects'.")
+		theme.Println("\n⚠️  Link management requires a specific project.")
+		theme.Println("Please select a single project instead of 'All Projects'.")
 		return
 	}
 
 	// Load project configuration
 	cfg, err := config.LoadConfig(projectPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		return
 	}
 
 	// Create GA4 client
-	client, err := newGA4Client()
+	client, err := newGA4Client(cfg)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		theme.Fprintln(os.Stderr, err)
 		return
 	}
 	defer client.Close()
@@ -131,17 +154,17 @@ func handleLinkAction() {
 
 // showLinkManagementMenu displays and handles the link management submenu.
 func showLinkManagementMenu(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Printf("\n🔗 Link Management - %s (Property: %s)\n", cfg.Project.Name, cfg.GetPropertyID())
-	fmt.Println(strings.Repeat("━", 50))
-
-	fmt.Println("\n📋 What would you like to do?")
-	fmt.Println("  1. View existing links and connections")
-	fmt.Println("  2. Setup channel groups")
-	fmt.Println("  3. Get Search Console setup guide")
-	fmt.Println("  4. Get BigQuery setup guide")
-	fmt.Println("  5. Delete channel groups")
-	fmt.Println("  6. Back to main menu")
-	fmt.Print("\nSelect option (1-6): ")
+	theme.Printf("\n🔗 Link Management - %s (Property: %s)\n", cfg.Project.Name, cfg.GetPropertyID())
+	theme.Println(strings.Repeat("━", 50))
+
+	theme.Println("\n📋 What would you like to do?")
+	theme.Println("  1. View existing links and connections")
+	theme.Println("  2. Setup channel groups")
+	theme.Println("  3. Get Search Console setup guide")
+	theme.Println("  4. Get BigQuery setup guide")
+	theme.Println("  5. Delete channel groups")
+	theme.Println("  6. Back to main menu")
+	theme.Print("\nSelect option (1-6): ")
 
 	var choice string
 	_, _ = fmt.Scanln(&choice)
@@ -165,73 +188,73 @@ func routeLinkOperation(client *ga4.Client, cfg *config.ProjectConfig, choice st
 	case "6", "":
 		return
 	default:
-		fmt.Println("\n⚠️  Invalid choice.")
+		theme.Println("\n⚠️  Invalid choice.")
 	}
 }
 
 // handleViewLinks displays existing links and connections.
 func handleViewLinks(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Println()
-	fmt.Println("🔍 Checking existing links...")
-	fmt.Println()
+	theme.Println()
+	theme.Println("🔍 Checking existing links...")
+	theme.Println()
 	if err := listExistingLinks(client, cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "Error listing links: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error listing links: %v\n", err)
 	}
 }
 
-// handleSetupChannels sets up default channel groups.
+// handleSetupChannels sets up the config's channel groups, or the defaults.
 func handleSetupChannels(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Println("\n📡 Setting up default channel groups...")
-	fmt.Println("\nThis will create the following channel groups:")
+	theme.Println("\n📡 Setting up channel groups...")
+	theme.Println("\nThis will create the following channel groups:")
 
-	defaultGroups := ga4.DefaultChannelGroups()
-	for i, group := range defaultGroups {
-		fmt.Printf("  %d. %s - %s\n", i+1, group.DisplayName, group.Description)
+	groups := ga4.ChannelGroupsFor(cfg)
+	for i, group := range groups {
+		theme.Printf("  %d. %s - %s\n", i+1, group.DisplayName, group.Description)
 	}
 
 	if !confirmAction("\nProceed with setup? (y/n): ") {
-		fmt.Println("\n❌ Setup cancelled.")
+		theme.Println("\n❌ Setup cancelled.")
 		return
 	}
 
-	if err := client.SetupDefaultChannelGroups(cfg.GetPropertyID()); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error setting up channel groups: %v\n", err)
+	if err := client.SetupChannelGroups(cfg.GetPropertyID(), groups); err != nil {
+		theme.Fprintf(os.Stderr, "\n❌ Error setting up channel groups: %v\n", err)
 	} else {
-		fmt.Println("\n✅ Channel groups setup completed!")
+		theme.Println("\n✅ Channel groups setup completed!")
 	}
 }
 
 // handleSearchConsoleGuide generates Search Console setup guide.
 func handleSearchConsoleGuide(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Print("\n🔗 Enter your website URL (e.g., https://example.com): ")
+	theme.Print("\n🔗 Enter your website URL (e.g., https://example.com): ")
 	var siteURL string
 	_, _ = fmt.Scanln(&siteURL)
 
 	if siteURL == "" {
-		fmt.Println("\n⚠️  No URL provided.")
+		theme.Println("\n⚠️  No URL provided.")
 		return
 	}
 
-	fmt.Println()
+	theme.Println()
 	guide := client.GenerateSearchConsoleSetupGuide(cfg.GetPropertyID(), siteURL)
-	fmt.Println(guide)
-	fmt.Printf("\nℹ️  The GA4 Admin API does not support programmatic Search Console linking.\n")
-	fmt.Println("Please follow the manual steps above.")
+	theme.Println(guide)
+	theme.Printf("\nℹ️  The GA4 Admin API does not support programmatic Search Console linking.\n")
+	theme.Println("Please follow the manual steps above.")
 }
 
 // handleBigQueryGuide generates BigQuery setup guide.
 func handleBigQueryGuide(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Print("\n📊 Enter GCP Project ID: ")
+	theme.Print("\n📊 Enter GCP Project ID: ")
 	var gcpProject string
 	_, _ = fmt.Scanln(&gcpProject)
 
 	if gcpProject == "" {
-		fmt.Println("\n⚠️  No GCP Project ID provided.")
+		theme.Println("\n⚠️  No GCP Project ID provided.")
 		return
 	}
 
 	propertyID := cfg.GetPropertyID()
-	fmt.Printf("Enter BigQuery Dataset ID (default: analytics_%s): ", propertyID)
+	theme.Printf("Enter BigQuery Dataset ID (default: analytics_%s): ", propertyID)
 	var dataset string
 	_, _ = fmt.Scanln(&dataset)
 
@@ -241,22 +264,22 @@ func handleBigQueryGuide(client *ga4.Client, cfg *config.ProjectConfig) {
 
 	bqConfig := ga4.GetDefaultBigQueryConfig(propertyID, gcpProject, dataset)
 	guide := client.GenerateBigQuerySetupGuide(bqConfig)
-	fmt.Println(guide)
-	fmt.Printf("\nℹ️  BigQuery links must be created manually in the GA4 UI.\n")
+	theme.Println(guide)
+	theme.Printf("\nℹ️  BigQuery links must be created manually in the GA4 UI.\n")
 }
 
 // handleDeleteChannels manages channel group deletion.
 func handleDeleteChannels(client *ga4.Client, cfg *config.ProjectConfig) {
-	fmt.Println("\n🗑️  Listing custom channel groups...")
+	theme.Println("\n🗑️  Listing custom channel groups...")
 
 	groups, err := client.ListCustomChannelGroups(cfg.GetPropertyID())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listing channel groups: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error listing channel groups: %v\n", err)
 		return
 	}
 
 	if len(groups) == 0 {
-		fmt.Println("\n✓ No custom channel groups found to delete.")
+		theme.Println("\n✓ No custom channel groups found to delete.")
 		return
 	}
 
@@ -277,20 +300,20 @@ type channelGroupInfo struct {
 
 // displayCustomChannelGroups displays the list of custom channel groups.
 func displayCustomChannelGroups(groups []channelGroupInfo) {
-	fmt.Println("\nCustom Channel Groups:")
+	theme.Println("\nCustom Channel Groups:")
 	for i, g := range groups {
-		fmt.Printf("  %d. %s\n", i+1, g.DisplayName)
+		theme.Printf("  %d. %s\n", i+1, g.DisplayName)
 	}
 }
 
 // executeChannelGroupDeletion executes the channel group deletion.
 func executeChannelGroupDeletion(client *ga4.Client, groups []channelGroupInfo) {
-	fmt.Print("\nEnter number to delete (or 'all' to delete all, 'cancel' to abort): ")
+	theme.Print("\nEnter number to delete (or 'all' to delete all, 'cancel' to abort): ")
 	var choice string
 	_, _ = fmt.Scanln(&choice)
 
 	if choice == "cancel" || choice == "" {
-		fmt.Println("\n❌ Delete cancelled.")
+		theme.Println("\n❌ Delete cancelled.")
 		return
 	}
 
@@ -305,41 +328,41 @@ func executeChannelGroupDeletion(client *ga4.Client, groups []channelGroupInfo)
 // deleteAllChannelGroups deletes all custom channel groups.
 func deleteAllChannelGroups(client *ga4.Client, groups []channelGroupInfo) {
 	if !confirmDangerous(fmt.Sprintf("\n⚠️  Are you sure you want to delete ALL %d custom channel groups? (yes/no): ", len(groups))) {
-		fmt.Println("\n❌ Delete cancelled.")
+		theme.Println("\n❌ Delete cancelled.")
 		return
 	}
 
 	for _, g := range groups {
-		fmt.Printf("Deleting '%s'...\n", g.DisplayName)
+		theme.Printf("Deleting '%s'...\n", g.DisplayName)
 		if err := client.DeleteChannelGroup(g.Name); err != nil {
-			fmt.Fprintf(os.Stderr, "  ❌ Error: %v\n", err)
+			theme.Fprintf(os.Stderr, "  ❌ Error: %v\n", err)
 		} else {
-			fmt.Println("  ✓ Deleted")
+			theme.Println("  ✓ Deleted")
 		}
 	}
-	fmt.Println("\n✅ Batch delete completed.")
+	theme.Println("\n✅ Batch delete completed.")
 }
 
 // deleteSingleChannelGroup deletes a single channel group.
 func deleteSingleChannelGroup(client *ga4.Client, groups []channelGroupInfo, choice string) {
 	var idx int
 	if _, err := fmt.Sscanf(choice, "%d", &idx); err != nil || idx < 1 || idx > len(groups) {
-		fmt.Println("\n⚠️  Invalid choice.")
+		theme.Println("\n⚠️  Invalid choice.")
 		return
 	}
 
 	selected := groups[idx-1]
-	fmt.Printf("\nDeleting '%s'...\n", selected.DisplayName)
+	theme.Printf("\nDeleting '%s'...\n", selected.DisplayName)
 	if err := client.DeleteChannelGroup(selected.Name); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		theme.Fprintf(os.Stderr, "❌ Error: %v\n", err)
 	} else {
-		fmt.Println("✅ Successfully deleted!")
+		theme.Println("✅ Successfully deleted!")
 	}
 }
 
 // confirmAction prompts for yes/no confirmation.
 func confirmAction(prompt string) bool {
-	fmt.Print(prompt)
+	theme.Print(prompt)
 	var confirm string
 	_, _ = fmt.Scanln(&confirm)
 	return confirm == "y" || confirm == "Y" || confirm == "yes"
@@ -347,50 +370,52 @@ func confirmAction(prompt string) bool {
 
 // confirmDangerous prompts for explicit "yes" confirmation for dangerous operations.
 func confirmDangerous(prompt string) bool {
-	fmt.Print(prompt)
+	theme.Print(prompt)
 	var confirm string
 	_, _ = fmt.Scanln(&confirm)
 	return confirm == "yes"
 }
 
 func listExistingLinks(client *ga4.Client, cfg *config.ProjectConfig) error {
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
 
-	fmt.Printf("\n%s Existing Links and Configurations\n", cyan("🔍"))
+	theme.Printf("\n%s Existing Links and Configurations\n", cyan("🔍"))
 
 	// Search Console
-	fmt.Println("\nSearch Console:")
-	fmt.Printf("  %s Manual check required. The Admin API cannot list Search Console links.\n", yellow("○"))
+	theme.Println("\nSearch Console:")
+	theme.Printf("  %s Manual check required. The Admin API cannot list Search Console links.\n", yellow("○"))
 
 	// BigQuery
-	fmt.Println("\nBigQuery Export:")
+	theme.Println("\nBigQuery Export:")
 	bqLinks, err := client.ListBigQueryLinks(cfg.GetPropertyID())
 	if err != nil {
-		fmt.Printf("  %s Error: %v\n", color.New(color.FgRed).Sprint("✗"), err)
+		theme.Printf("  %s Error: %v\n", theme.Color(color.FgRed).Sprint("✗"), err)
 	} else if len(bqLinks) == 0 {
-		fmt.Printf("  %s No BigQuery export configured.\n", yellow("○"))
+		theme.Printf("  %s No BigQuery export configured.\n", yellow("○"))
 	} else {
 		for _, link := range bqLinks {
-			fmt.Printf("  %s Project: %s\n", green("✓"), link.Project)
-			fmt.Printf("    Daily: %v, Streaming: %v\n", link.DailyExportEnabled, link.StreamingExportEnabled)
+			theme.Printf("  %s Project: %s\n", green("✓"), link.Project)
+			theme.Printf("    Daily: %v, Streaming: %v\n", link.DailyExportEnabled, link.StreamingExportEnabled)
 		}
 	}
 
 	// Channel Groups
-	fmt.Println("\nChannel Groups:")
+	theme.Println("\nChannel Groups:")
 	channelGroups, err := client.ListChannelGroups(cfg.GetPropertyID())
-	if err != nil {
-		fmt.Printf("  %s Error: %v\n", color.New(color.FgRed).Sprint("✗"), err)
+	if errors.Is(err, ga4.ErrFeatureDisabled) {
+		theme.Printf("  %s Skipped: analytics.features.channel_groups is off.\n", yellow("○"))
+	} else if err != nil {
+		theme.Printf("  %s Error: %v\n", theme.Color(color.FgRed).Sprint("✗"), err)
 	} else if len(channelGroups) == 0 {
-		fmt.Printf("  %s No custom channel groups found.\n", yellow("○"))
+		theme.Printf("  %s No custom channel groups found.\n", yellow("○"))
 	} else {
 		for _, group := range channelGroups {
-			fmt.Printf("  %s %s\n", green("✓"), group.DisplayName)
+			theme.Printf("  %s %s\n", green("✓"), group.DisplayName)
 		}
 	}
-	fmt.Println()
+	theme.Println()
 	return nil
 }
 
@@ -399,10 +424,10 @@ func linkSearchConsole(client *ga4.Client, cfg *config.ProjectConfig) error {
 		return fmt.Errorf("the --url flag is required for the Search Console service")
 	}
 
-	fmt.Printf("\n%s Search Console Link Setup Guide\n", color.New(color.FgCyan).SprintFunc()("🔗"))
+	theme.Printf("\n%s Search Console Link Setup Guide\n", theme.Color(color.FgCyan).SprintFunc()("🔗"))
 	guide := client.GenerateSearchConsoleSetupGuide(cfg.GetPropertyID(), linkURL)
-	fmt.Println(guide)
-	fmt.Printf("%s The GA4 Admin API does not support programmatic Search Console linking. Please follow the manual steps above.\n", color.New(color.FgYellow).SprintFunc()("ℹ"))
+	theme.Println(guide)
+	theme.Printf("%s The GA4 Admin API does not support programmatic Search Console linking. Please follow the manual steps above.\n", theme.Color(color.FgYellow).SprintFunc()("ℹ"))
 	return nil
 }
 
@@ -411,7 +436,7 @@ func linkBigQuery(client *ga4.Client, cfg *config.ProjectConfig) error {
 		return fmt.Errorf("both --gcp-project and --dataset flags are required for BigQuery linking")
 	}
 
-	fmt.Printf("\n%s Linking BigQuery...\n", color.New(color.FgCyan).SprintFunc()("📊"))
+	theme.Printf("\n%s Linking BigQuery...\n", theme.Color(color.FgCyan).SprintFunc()("📊"))
 
 	propertyID := cfg.GetPropertyID()
 	exists, err := client.BigQueryLinkExists(propertyID)
@@ -419,7 +444,7 @@ func linkBigQuery(client *ga4.Client, cfg *config.ProjectConfig) error {
 		return fmt.Errorf("could not check for existing BigQuery links: %w", err)
 	}
 	if exists {
-		_, _ = color.New(color.FgYellow).Println("✓ A BigQuery link already exists for this property. No action taken.")
+		_, _ = theme.Color(color.FgYellow).Println("✓ A BigQuery link already exists for this property. No action taken.")
 		return nil
 	}
 
@@ -429,25 +454,25 @@ func linkBigQuery(client *ga4.Client, cfg *config.ProjectConfig) error {
 		return fmt.Errorf("could not create BigQuery link: %w", err)
 	}
 
-	_, _ = color.New(color.FgGreen).Printf("✓ Successfully created BigQuery link: %s\n", createdLink.Name)
+	_, _ = theme.Color(color.FgGreen).Printf("✓ Successfully created BigQuery link: %s\n", createdLink.Name)
 	return nil
 }
 
 func setupChannelGroups(client *ga4.Client, cfg *config.ProjectConfig) error {
-	fmt.Printf("\n%s Setting up default Channel Groups...\n", color.New(color.FgCyan).SprintFunc()("📡"))
+	theme.Printf("\n%s Setting up Channel Groups...\n", theme.Color(color.FgCyan).SprintFunc()("📡"))
 
-	if err := client.SetupDefaultChannelGroups(cfg.GetPropertyID()); err != nil {
-		_, _ = color.New(color.FgRed).Printf("✗ An error occurred during channel group setup: %v\n", err)
+	if err := client.SetupChannelGroups(cfg.GetPropertyID(), ga4.ChannelGroupsFor(cfg)); err != nil {
+		_, _ = theme.Color(color.FgRed).Printf("✗ An error occurred during channel group setup: %v\n", err)
 		return err
 	}
 
-	_, _ = color.New(color.FgGreen).Println("✓ Channel group setup process completed.")
-	fmt.Println("Please check the output above for the status of each channel group.")
+	_, _ = theme.Color(color.FgGreen).Println("✓ Channel group setup process completed.")
+	theme.Println("Please check the output above for the status of each channel group.")
 	return nil
 }
 
 func unlinkExternalService(client *ga4.Client, cfg *config.ProjectConfig, service string) error {
-	fmt.Printf("\n%s Unlinking service: %s\n", color.New(color.FgYellow).SprintFunc()("🔓"), service)
+	theme.Printf("\n%s Unlinking service: %s\n", theme.Color(color.FgYellow).SprintFunc()("🔓"), service)
 
 	deleted, err := client.UnlinkService(cfg.GetPropertyID(), service)
 	if err != nil {
@@ -455,12 +480,41 @@ func unlinkExternalService(client *ga4.Client, cfg *config.ProjectConfig, servic
 	}
 
 	if len(deleted) == 0 {
-		_, _ = color.New(color.FgYellow).Println("No links found to unlink.")
+		_, _ = theme.Color(color.FgYellow).Println("No links found to unlink.")
 		return nil
 	}
 
 	for _, name := range deleted {
-		_, _ = color.New(color.FgGreen).Printf("✓ Successfully deleted %s\n", name)
+		_, _ = theme.Color(color.FgGreen).Printf("✓ Successfully deleted %s\n", name)
 	}
 	return nil
 }
+
+// unlinkChannelGroupsWithApproval is --unlink channels under
+// --require-approval: the custom channel groups go to a pending file, or are
+// deleted once it is approved.
+func unlinkChannelGroupsWithApproval(client *ga4.Client, cfg *config.ProjectConfig, path string, apply bool) error {
+	propertyID := cfg.GetPropertyID()
+	groups, err := client.ListCustomChannelGroups(propertyID)
+	if err != nil {
+		return fmt.Errorf("could not list channel groups to unlink: %w", err)
+	}
+
+	ops := make([]approval.Operation, len(groups))
+	for i, g := range groups {
+		ops[i] = approval.Operation{
+			Property:    propertyID,
+			Action:      approvalActionDelete,
+			Resource:    setup.DiffResourceChannelGroup,
+			Name:        g.Name,
+			DisplayName: g.DisplayName,
+		}
+	}
+	if len(ops) > 0 {
+		theme.Printf("\n%s Custom channel groups to delete:\n", theme.Color(color.FgRed).Sprint("🗑"))
+		if err := renderApprovalOperations(ops); err != nil {
+			return err
+		}
+	}
+	return gateApproval(path, apply, approvalCommandUnlinkChannels, ops, client)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/logging"
+)
+
+// logLevel and logFormat are the --log-level and --log-format flags.
+var (
+	logLevel  string
+	logFormat string
+)
+
+// cliLogger is the logger shared by every command and the GA4 and GSC
+// clients they create. Diagnostics go to stderr so stdout stays the
+// command's report.
+var cliLogger = slog.Default()
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "Diagnostic log level: "+strings.Join(logging.Levels, ", ")+" (debug logs every API call with its status, for troubleshooting 403 and quota errors)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "Diagnostic log format: "+strings.Join(logging.Formats, ", "))
+	cobra.OnInitialize(applyLogging)
+}
+
+// applyLogging builds cliLogger once flags are parsed and makes it the slog
+// default, so packages logging through slog.Default follow the flags too.
+func applyLogging() {
+	logger, err := logging.New(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	cliLogger = logger
+	slog.SetDefault(logger)
+}
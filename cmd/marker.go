@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/algoupdates"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	markerSite     string
+	markerConfig   string
+	markerStateDir string
+	markerDate     string
+	markerEnd      string
+	markerLabel    string
+	markerFormat   string
+
+	markerUpdatesFetch  bool
+	markerUpdatesURL    string
+	markerUpdatesFormat string
+)
+
+var markerCmd = &cobra.Command{
+	Use:   "marker",
+	Short: "Annotate dates with deployments, migrations and other events",
+	Long: `Keep dated markers for a site, the annotations GA4 does not have.
+
+Markers are stored in the local history store and shown alongside the reports
+that follow or compare periods, next to the runs or windows they fall in:
+  ga4 trend                  a Markers column on the run covering the date
+  ga4 gsc ctr-anomaly        listed under the results
+  ga4 insights run           listed under the findings
+  ga4 summary weekly         a Markers section
+
+Confirmed Google algorithm updates are shown the same way, from a calendar
+that ships with the tool (see ` + "`ga4 marker updates`" + `), so a change that
+overlaps a core or spam update rollout is easy to tell apart.
+
+Examples:
+  ga4 marker add --config configs/mysite.yaml --date 2025-02-07 --label "site migration"
+  ga4 marker add --site sc-domain:example.com --date 2025-03-13 --end 2025-03-27 --label "March core update"
+  ga4 marker list --config configs/mysite.yaml
+  ga4 marker remove --config configs/mysite.yaml 3f9a1c02`,
+}
+
+var markerAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a marker on a date or date range",
+	Args:  cobra.NoArgs,
+	RunE:  runMarkerAdd,
+}
+
+var markerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List a site's markers",
+	Args:  cobra.NoArgs,
+	RunE:  runMarkerList,
+}
+
+var markerRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a marker by its ID (see `ga4 marker list`)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMarkerRemove,
+}
+
+var markerUpdatesCmd = &cobra.Command{
+	Use:   "updates",
+	Short: "List, or fetch a newer copy of, the Google algorithm update calendar",
+	Long: `List the confirmed Google Search ranking updates reports annotate, with
+their rollout windows, from the Search Status Dashboard.
+
+A calendar ships with the tool. --fetch downloads the latest one (from the
+project repository unless --url names another copy of the same JSON) into
+the state directory, where it replaces the built-in one.
+
+Examples:
+  ga4 marker updates
+  ga4 marker updates --fetch
+  ga4 marker updates --fetch --url https://intranet.example.com/seo/algorithm-updates.json`,
+	Args: cobra.NoArgs,
+	RunE: runMarkerUpdates,
+}
+
+func init() {
+	rootCmd.AddCommand(markerCmd)
+	markerCmd.AddCommand(markerAddCmd, markerListCmd, markerRemoveCmd, markerUpdatesCmd)
+	markerCmd.PersistentFlags().StringVarP(&markerSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	markerCmd.PersistentFlags().StringVarP(&markerConfig, "config", "c", "", "Read the site from search_console.site_url in this config")
+	markerCmd.PersistentFlags().StringVar(&markerStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+
+	markerAddCmd.Flags().StringVar(&markerDate, "date", "", "Day of the event, YYYY-MM-DD (required)")
+	markerAddCmd.Flags().StringVar(&markerEnd, "end", "", "Last day, YYYY-MM-DD, for an event spanning several days")
+	markerAddCmd.Flags().StringVarP(&markerLabel, "label", "l", "", "What happened (required)")
+	_ = markerAddCmd.MarkFlagRequired("date")
+	_ = markerAddCmd.MarkFlagRequired("label")
+
+	output.FormatVar(markerListCmd.Flags(), &markerFormat, "f", output.FormatTable, output.FormatJSON)
+
+	markerUpdatesCmd.Flags().BoolVar(&markerUpdatesFetch, "fetch", false, "Download the latest calendar into the state directory first")
+	markerUpdatesCmd.Flags().StringVar(&markerUpdatesURL, "url", algoupdates.DefaultURL, "Where --fetch downloads the calendar from")
+	output.FormatVar(markerUpdatesCmd.Flags(), &markerUpdatesFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+func runMarkerAdd(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(markerSite, markerConfig)
+	if err != nil {
+		return err
+	}
+	m, err := markers.Add(context.Background(), store.New(gscstate.ResolveStateDir(markerStateDir)), site,
+		markers.Marker{Date: markerDate, End: markerEnd, Label: markerLabel})
+	if err != nil {
+		return err
+	}
+	theme.Green("✓ Added marker %s: %s", m.ID, m)
+	return nil
+}
+
+func runMarkerList(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(markerSite, markerConfig)
+	if err != nil {
+		return err
+	}
+	ms, err := markers.List(context.Background(), store.New(gscstate.ResolveStateDir(markerStateDir)), site)
+	if err != nil {
+		return err
+	}
+	if markerFormat == output.FormatJSON {
+		return output.JSON(os.Stdout, ms)
+	}
+	if len(ms) == 0 {
+		theme.Yellow("No markers for %s. Add one with `ga4 marker add`.", site)
+		return nil
+	}
+	theme.Cyan("═══ Markers: %s ═══", site)
+	theme.Println()
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable, markerColumns(), ms, markerTableRow)
+}
+
+func runMarkerRemove(cmd *cobra.Command, args []string) error {
+	site, err := siteFromFlags(markerSite, markerConfig)
+	if err != nil {
+		return err
+	}
+	if err := markers.Remove(context.Background(), store.New(gscstate.ResolveStateDir(markerStateDir)), site, args[0]); err != nil {
+		return err
+	}
+	theme.Green("✓ Removed marker %s", args[0])
+	return nil
+}
+
+func runMarkerUpdates(cmd *cobra.Command, args []string) error {
+	stateDir := gscstate.ResolveStateDir(markerStateDir)
+	var (
+		calendar algoupdates.Calendar
+		from     string
+		err      error
+	)
+	if markerUpdatesFetch {
+		client := &http.Client{Timeout: 30 * time.Second}
+		if calendar, err = algoupdates.Fetch(context.Background(), client, markerUpdatesURL, stateDir); err != nil {
+			return err
+		}
+		from = markerUpdatesURL
+	} else if calendar, from, err = algoupdates.Load(stateDir); err != nil {
+		return err
+	}
+	if from == "" {
+		from = "built-in calendar"
+	}
+
+	if markerUpdatesFormat == output.FormatJSON {
+		return output.JSON(os.Stdout, calendar)
+	}
+	theme.Cyan("═══ Google algorithm updates ═══")
+	theme.HiBlack("%d updates, as of %s, from %s", len(calendar.Updates), calendar.Updated, from)
+	theme.Println()
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Update", "Type", "Start", "End"}, calendar.Updates, func(u algoupdates.Update) []string {
+			return []string{u.Name, u.Type, u.Start, u.End}
+		})
+}
+
+func markerColumns() []string {
+	return []string{"ID", "Date", "End", "Label", "Source"}
+}
+
+func markerTableRow(m markers.Marker) []string {
+	return []string{m.ID, m.Date, m.End, m.Label, m.Source}
+}
+
+// reportMarkers returns the site's markers and the algorithm updates
+// overlapping start to end, for the reports that show them.
+func reportMarkers(stateDir, site, start, end string) ([]markers.Marker, error) {
+	ms, err := allMarkers(stateDir, site)
+	if err != nil {
+		return nil, err
+	}
+	return markers.Within(ms, start, end), nil
+}
+
+// allMarkers returns the site's markers and the algorithm updates, by date.
+func allMarkers(stateDir, site string) ([]markers.Marker, error) {
+	ms, err := markers.List(context.Background(), store.New(stateDir), site)
+	if err != nil {
+		return nil, err
+	}
+	calendar, _, err := algoupdates.Load(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	ms = append(ms, calendar.Markers()...)
+	markers.Sort(ms)
+	return ms, nil
+}
+
+// markerLabels joins the labels of ms for a table cell.
+func markerLabels(ms []markers.Marker) string {
+	labels := make([]string, len(ms))
+	for i, m := range ms {
+		labels[i] = m.Label
+	}
+	return strings.Join(labels, "; ")
+}
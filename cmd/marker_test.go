@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/algoupdates"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestReportMarkers_KeepsThoseInTheRange(t *testing.T) {
+	stateDir := t.TempDir()
+	st := store.New(stateDir)
+	site := "sc-domain:example.com"
+	ctx := context.Background()
+
+	migration, err := markers.Add(ctx, st, site, markers.Marker{Date: "2025-02-07", Label: "site migration"})
+	require.NoError(t, err)
+	update, err := markers.Add(ctx, st, site, markers.Marker{Date: "2025-01-28", End: "2025-02-02", Label: "core update"})
+	require.NoError(t, err)
+	_, err = markers.Add(ctx, st, site, markers.Marker{Date: "2025-03-01", Label: "redesign"})
+	require.NoError(t, err)
+
+	got, err := reportMarkers(stateDir, site, "2025-02-01", "2025-02-28")
+	require.NoError(t, err)
+	assert.Equal(t, []markers.Marker{update, migration}, got)
+	assert.Equal(t, "core update; site migration", markerLabels(got))
+
+	got, err = reportMarkers(t.TempDir(), site, "2025-02-01", "2025-02-28")
+	require.NoError(t, err)
+	assert.Empty(t, got, "no history yet")
+}
+
+func TestReportMarkers_IncludesAlgorithmUpdates(t *testing.T) {
+	stateDir := t.TempDir()
+	site := "sc-domain:example.com"
+	deploy, err := markers.Add(context.Background(), store.New(stateDir), site, markers.Marker{Date: "2025-03-20", Label: "new templates"})
+	require.NoError(t, err)
+
+	got, err := reportMarkers(stateDir, site, "2025-03-15", "2025-03-25")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "March 2025 core update", got[0].Label)
+	assert.Equal(t, algoupdates.Source, got[0].Source)
+	assert.Equal(t, deploy, got[1])
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/mp"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// mpSecretEnv is read when --secret is not given.
+const mpSecretEnv = "GA4_MP_SECRET"
+
+var (
+	mpMeasurementID  string
+	mpSecret         string
+	mpConfig         string
+	mpEvent          string
+	mpParams         []string
+	mpClientID       string
+	mpUserID         string
+	mpUserProperties []string
+	mpFile           string
+	mpValidate       bool
+	mpDebugView      bool
+)
+
+var mpCmd = &cobra.Command{
+	Use:   "mp",
+	Short: "Send events through the Measurement Protocol",
+}
+
+var mpSendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send test events to a web data stream",
+	Long: `Send events to a GA4 web data stream through the Measurement Protocol, to
+check end to end that the conversions and custom dimensions ga4 setup created
+register: send the event, then watch it arrive with ga4 debug stream, or
+check the conversion counts with ga4 verify events the next day.
+
+The stream is --measurement-id, or analytics.measurement_id from --config.
+The API secret is created under Admin > Data streams > Measurement Protocol
+API secrets; pass it with --secret or $` + mpSecretEnv + `.
+
+Parameters are name=value; numbers and true/false are sent as such, so
+value=9.99 counts as revenue. Send several events with --file, a YAML batch:
+
+  measurement_id: G-XXXXXXX   # optional
+  client_id: "555.1"          # optional
+  user_properties:
+    plan: pro
+  events:
+    - name: sign_up
+      params: {method: email}
+    - name: purchase
+      params: {value: 9.99, currency: EUR}
+
+--validate sends to the validation server instead, which records nothing
+and reports malformed events. The collection endpoint accepts anything
+well-formed without saying whether it was recorded, so validate first.
+
+Exit codes:
+  0  events sent, or valid
+  4  the validation server reported problems
+  1  command failed: bad flags, config or HTTP error
+
+Examples:
+  ga4 mp send --measurement-id G-XXXXXXX --secret s3cret --event purchase --params value=9.99 --params currency=EUR
+  ga4 mp send --config configs/mysite.yaml --event sign_up --debug-view
+  ga4 mp send --config configs/mysite.yaml --file events.yaml --validate`,
+	Args: cobra.NoArgs,
+	RunE: mpSendRunE,
+}
+
+func init() {
+	rootCmd.AddCommand(mpCmd)
+	mpCmd.AddCommand(mpSendCmd)
+	mpSendCmd.Flags().StringVarP(&mpMeasurementID, "measurement-id", "m", "", "Web stream measurement ID (G-XXXXXXX)")
+	mpSendCmd.Flags().StringVar(&mpSecret, "secret", "", "Measurement Protocol API secret (default $"+mpSecretEnv+")")
+	mpSendCmd.Flags().StringVarP(&mpConfig, "config", "c", "", "Read the measurement ID from this configuration file")
+	mpSendCmd.Flags().StringVarP(&mpEvent, "event", "e", "", "Event name")
+	mpSendCmd.Flags().StringArrayVar(&mpParams, "params", nil, "Event parameter as name=value (repeatable)")
+	mpSendCmd.Flags().StringVar(&mpClientID, "client-id", "", "Client ID (default: a new one per run)")
+	mpSendCmd.Flags().StringVar(&mpUserID, "user-id", "", "User ID")
+	mpSendCmd.Flags().StringArrayVar(&mpUserProperties, "user-property", nil, "User property as name=value (repeatable)")
+	mpSendCmd.Flags().StringVar(&mpFile, "file", "", "YAML batch of events to send instead of --event")
+	mpSendCmd.Flags().BoolVar(&mpValidate, "validate", false, "Check the events with the validation server without recording them")
+	mpSendCmd.Flags().BoolVar(&mpDebugView, "debug-view", false, "Add debug_mode to every event so it shows in DebugView")
+	mpSendCmd.MarkFlagsMutuallyExclusive("event", "file")
+	mpSendCmd.MarkFlagsOneRequired("event", "file")
+}
+
+func mpSendRunE(cmd *cobra.Command, _ []string) error {
+	secret := mpSecret
+	if secret == "" {
+		secret = os.Getenv(mpSecretEnv)
+	}
+	return exitWith(cmd, runMPSend(mpSendParams{
+		MeasurementID:  mpMeasurementID,
+		Secret:         secret,
+		ConfigPath:     mpConfig,
+		Event:          mpEvent,
+		Params:         mpParams,
+		ClientID:       mpClientID,
+		UserID:         mpUserID,
+		UserProperties: mpUserProperties,
+		File:           mpFile,
+		Validate:       mpValidate,
+		DebugView:      mpDebugView,
+		Endpoint:       mp.DefaultEndpoint,
+		Stdout:         os.Stdout,
+		Stderr:         os.Stderr,
+		Now:            time.Now,
+	}))
+}
+
+type mpSendParams struct {
+	MeasurementID  string
+	Secret         string
+	ConfigPath     string
+	Event          string
+	Params         []string
+	ClientID       string
+	UserID         string
+	UserProperties []string
+	File           string
+	Validate       bool
+	DebugView      bool
+	Endpoint       string
+	Stdout         io.Writer
+	Stderr         io.Writer
+	Now            func() time.Time
+}
+
+func runMPSend(p mpSendParams) int {
+	payload, measurementID, err := mpPayload(p)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	if measurementID == "" {
+		return diagcmd.FailWith(p.Stderr, "no measurement ID: pass --measurement-id, or --config with analytics.measurement_id")
+	}
+	if p.Secret == "" {
+		return diagcmd.FailWith(p.Stderr, "no API secret: pass --secret or set $%s", mpSecretEnv)
+	}
+	if err := payload.Validate(); err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	client := mp.New(measurementID, p.Secret, mp.WithEndpoint(p.Endpoint))
+	ctx := context.Background()
+	if p.Validate {
+		messages, err := client.Validate(ctx, payload)
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "%v", err)
+		}
+		if len(messages) == 0 {
+			theme.Fprintf(p.Stdout, "%s %d event(s) for %s are valid\n", theme.GreenString("✓"), len(payload.Events), measurementID)
+			return diagcmd.ExitClean
+		}
+		for _, m := range messages {
+			theme.Fprintf(p.Stdout, "%s %s %s: %s\n", theme.RedString("✗"), m.ValidationCode, m.FieldPath, m.Description)
+		}
+		return diagcmd.ExitIssues
+	}
+
+	if err := client.Send(ctx, payload); err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	names := make([]string, len(payload.Events))
+	for i, e := range payload.Events {
+		names[i] = e.Name
+	}
+	theme.Fprintf(p.Stdout, "%s Sent %s to %s as client %s\n", theme.GreenString("✓"), strings.Join(names, ", "), measurementID, payload.ClientID)
+	theme.Fprintln(p.Stdout, theme.HiBlackString("Watch it arrive with ga4 debug stream; conversions show in ga4 verify events within a day."))
+	return diagcmd.ExitClean
+}
+
+// mpPayload builds the payload from --file or --event and returns it with
+// the measurement ID to send to: the flag, then the batch file, then the
+// config.
+func mpPayload(p mpSendParams) (mp.Payload, string, error) {
+	var payload mp.Payload
+	measurementID := p.MeasurementID
+	if p.File != "" {
+		batch, err := mp.LoadBatch(p.File)
+		if err != nil {
+			return mp.Payload{}, "", err
+		}
+		payload = batch.Payload
+		if measurementID == "" {
+			measurementID = batch.MeasurementID
+		}
+	} else {
+		params, err := mp.ParseParams(p.Params)
+		if err != nil {
+			return mp.Payload{}, "", err
+		}
+		payload.Events = []mp.Event{{Name: p.Event, Params: params}}
+	}
+	if measurementID == "" && p.ConfigPath != "" {
+		cfg, err := config.LoadConfig(p.ConfigPath)
+		if err != nil {
+			return mp.Payload{}, "", fmt.Errorf("failed to load config: %w", err)
+		}
+		measurementID = cfg.GetMeasurementID()
+	}
+
+	if p.ClientID != "" {
+		payload.ClientID = p.ClientID
+	}
+	if payload.ClientID == "" {
+		// The gtag.js format: a random number and the first-visit time.
+		payload.ClientID = fmt.Sprintf("%d.%d", rand.Uint32(), p.Now().Unix())
+	}
+	if p.UserID != "" {
+		payload.UserID = p.UserID
+	}
+	if len(p.UserProperties) > 0 {
+		props, err := mp.ParseParams(p.UserProperties)
+		if err != nil {
+			return mp.Payload{}, "", err
+		}
+		if payload.UserProperties == nil {
+			payload.UserProperties = map[string]any{}
+		}
+		for k, v := range props {
+			payload.UserProperties[k] = v
+		}
+	}
+	if p.DebugView {
+		for i := range payload.Events {
+			if payload.Events[i].Params == nil {
+				payload.Events[i].Params = map[string]any{}
+			}
+			payload.Events[i].Params["debug_mode"] = true
+		}
+	}
+	return payload, measurementID, nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+)
+
+// mpTestServer records the query and body of each request and answers the
+// validation server with validation.
+func mpTestServer(t *testing.T, validation string) (*httptest.Server, *[]map[string]any, *[]string) {
+	t.Helper()
+	var bodies []map[string]any
+	var ids []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		ids = append(ids, r.URL.Query().Get("measurement_id"))
+		if r.URL.Path == "/debug/mp/collect" {
+			_, _ = io.WriteString(w, validation)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies, &ids
+}
+
+func TestRunMPSend_SingleEventFromConfig(t *testing.T) {
+	srv, bodies, ids := mpTestServer(t, "")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runMPSend(mpSendParams{
+		Secret:         "s3cret",
+		ConfigPath:     writeSummaryConfig(t, "project:\n  name: Example\nanalytics:\n  property_id: \"123\"\n  measurement_id: G-FROMCFG\n"),
+		Event:          "purchase",
+		Params:         []string{"value=9.99", "currency=EUR"},
+		UserProperties: []string{"plan=pro"},
+		DebugView:      true,
+		Endpoint:       srv.URL + "/mp/collect",
+		Stdout:         stdout,
+		Stderr:         stderr,
+		Now:            func() time.Time { return time.Unix(1760000000, 0) },
+	})
+	require.Equal(t, diagcmd.ExitClean, code, stderr.String())
+
+	require.Len(t, *bodies, 1)
+	assert.Equal(t, []string{"G-FROMCFG"}, *ids)
+	body := (*bodies)[0]
+	assert.Regexp(t, `^\d+\.1760000000$`, body["client_id"])
+	assert.Equal(t, map[string]any{"plan": map[string]any{"value": "pro"}}, body["user_properties"])
+	assert.Equal(t, []any{map[string]any{
+		"name":   "purchase",
+		"params": map[string]any{"value": 9.99, "currency": "EUR", "debug_mode": true},
+	}}, body["events"])
+	assert.Contains(t, stdout.String(), "Sent purchase to G-FROMCFG")
+}
+
+func TestRunMPSend_BatchFileValidate(t *testing.T) {
+	srv, bodies, ids := mpTestServer(t, `{"validationMessages":[{"fieldPath":"events.params.value","description":"Invalid value","validationCode":"VALUE_INVALID"}]}`)
+	file := filepath.Join(t.TempDir(), "events.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("measurement_id: G-BATCH\nclient_id: \"555.1\"\nevents:\n  - name: sign_up\n  - name: purchase\n    params:\n      value: 9.99\n"), 0o644))
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runMPSend(mpSendParams{
+		Secret:   "s3cret",
+		File:     file,
+		Validate: true,
+		Endpoint: srv.URL + "/mp/collect",
+		Stdout:   stdout,
+		Stderr:   stderr,
+		Now:      time.Now,
+	})
+	require.Equal(t, diagcmd.ExitIssues, code, stderr.String())
+	assert.Equal(t, []string{"G-BATCH"}, *ids)
+	assert.Equal(t, "555.1", (*bodies)[0]["client_id"])
+	assert.Len(t, (*bodies)[0]["events"], 2)
+	assert.Contains(t, stdout.String(), "VALUE_INVALID events.params.value: Invalid value")
+}
+
+func TestRunMPSend_RequiresMeasurementIDAndSecret(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	code := runMPSend(mpSendParams{Secret: "s3cret", Event: "purchase", Stdout: io.Discard, Stderr: stderr, Now: time.Now})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), "no measurement ID")
+
+	stderr.Reset()
+	code = runMPSend(mpSendParams{MeasurementID: "G-X", Event: "purchase", Stdout: io.Discard, Stderr: stderr, Now: time.Now})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), "$"+mpSecretEnv)
+
+	stderr.Reset()
+	code = runMPSend(mpSendParams{MeasurementID: "G-X", Secret: "s", Event: "add-to-cart", Stdout: io.Discard, Stderr: stderr, Now: time.Now})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), `invalid event name "add-to-cart"`)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/garbarok/ga4-manager/internal/looker"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/parquet"
+)
+
+// fileFormats are the report formats --output can write: the machine
+// formats plus markdown, HTML, the Looker Studio CSV and Parquet. The table
+// format is for terminals only.
+var fileFormats = []string{output.FormatCSV, output.FormatJSON, output.FormatNDJSON, output.FormatMarkdown, output.FormatHTML, looker.Format, parquet.Format}
+
+// openOutput returns where a report command writes its report: the --output
+// file, created or truncated, or stdout when path is empty. The returned
+// close function must run once the report is written; it reports a failed
+// final write to the file.
+func openOutput(path, format string) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	if !slices.Contains(fileFormats, format) {
+		return nil, nil, fmt.Errorf("--output needs --format csv, json, ndjson, markdown, html, looker or parquet, not %s", format)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return f, f.Close, nil
+}
+
+// statusWriter is where a report command's progress notes go: stdout, unless
+// stdout carries a machine-readable report that they would corrupt.
+func statusWriter(format, path string) io.Writer {
+	if path == "" && format != output.FormatTable && format != output.FormatMarkdown {
+		return os.Stderr
+	}
+	return os.Stdout
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	permissionsConfig string
+	permissionsMode   string
+	permissionsFormat string
+)
+
+// What the credential will be used for: reporting only, or setup too.
+const (
+	permissionsModeReport = "report"
+	permissionsModeSetup  = "setup"
+)
+
+// Per-service outcomes of comparing the credential's role with the minimum.
+const (
+	accessOK           = "ok"
+	accessInsufficient = "insufficient" // some configured operation will 403
+	accessExcessive    = "excessive"    // more than the config needs
+	accessUnverified   = "unverified"   // the API would not reveal enough to tell
+)
+
+// errPermissionsInsufficient signals a service whose role is too low; the
+// command exits with diagcmd.ExitIssues.
+var errPermissionsInsufficient = errors.New("credential lacks required permissions")
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Recommend the minimum roles the credential needs for a config",
+	Long: `Inspect the credential's effective access on the config's GA4 property and
+Search Console site, and recommend the least-privileged role set that covers
+the operations the config uses.
+
+  --mode report   reports, exports and inspections only
+  --mode setup    also creating conversions, dimensions, metrics, settings,
+                  cleanup and sitemap submission (default)
+
+Minimum roles:
+  GA4             viewer for reads, editor for anything that writes
+  Search Console  siteRestrictedUser for reads, siteFullUser to submit sitemaps
+
+Neither service ever needs GA4 admin or siteOwner for config-driven work, so
+those are flagged as excessive.
+
+GA4 only shows access bindings to administrators. For any other role the
+advisor proves read access with a probe and reports the role as a range
+(viewer–editor); a setup run is the only read-free way to confirm editor.
+
+Exits 4 when a service's role is insufficient for the chosen mode.
+
+Examples:
+  ga4 permissions --config configs/mysite.yaml
+  ga4 permissions --config configs/mysite.yaml --mode report --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runPermissions(cmd, args)
+		if errors.Is(err, errPermissionsInsufficient) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.Flags().StringVarP(&permissionsConfig, "config", "c", "", "Path to configuration file")
+	permissionsCmd.Flags().StringVarP(&permissionsMode, "mode", "m", permissionsModeSetup, "What the credential is for: report or setup")
+	output.FormatVar(permissionsCmd.Flags(), &permissionsFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = permissionsCmd.MarkFlagRequired("config")
+}
+
+// permissionNeed is one configured operation and the role it requires.
+type permissionNeed struct {
+	Operation string `json:"operation"`
+	Role      string `json:"role"`
+	SetupOnly bool   `json:"setup_only"`
+}
+
+// serviceAccess is the advice for one service.
+type serviceAccess struct {
+	Service  string           `json:"service"`
+	Resource string           `json:"resource"`
+	Needs    []permissionNeed `json:"needs"`
+	Required string           `json:"required"`
+	AtLeast  string           `json:"current_at_least"`
+	AtMost   string           `json:"current_at_most"`
+	Verdict  string           `json:"verdict"`
+	Bindings []string         `json:"bindings,omitempty"`
+	Detail   string           `json:"detail,omitempty"`
+}
+
+type permissionsReport struct {
+	Principal string          `json:"principal"`
+	Mode      string          `json:"mode"`
+	Services  []serviceAccess `json:"services"`
+}
+
+func runPermissions(cmd *cobra.Command, args []string) error {
+	if permissionsMode != permissionsModeReport && permissionsMode != permissionsModeSetup {
+		return fmt.Errorf("invalid --mode %q (want report or setup)", permissionsMode)
+	}
+	cfg, err := config.LoadConfig(permissionsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	id := gsc.LoadServiceAccountIdentity()
+	if id.ClientEmail == "" {
+		return fmt.Errorf("cannot determine the service-account email from GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	report := permissionsReport{Principal: id.ClientEmail, Mode: permissionsMode}
+
+	if propertyID := cfg.GetPropertyID(); propertyID != "" {
+		client, err := newGA4Client(cfg)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		access, err := client.PropertyAccess(propertyID, id.ClientEmail)
+		if err != nil {
+			return err
+		}
+		svc := adviseAccess("ga4", propertyID, ga4PermissionNeeds(cfg), permissionsMode, access.AtLeast, access.AtMost, ga4.RoleRank)
+		svc.Bindings, svc.Detail = access.Bindings, access.Detail
+		report.Services = append(report.Services, svc)
+	}
+
+	if cfg.SearchConsole != nil && cfg.SearchConsole.SiteURL != "" {
+		site := cfg.SearchConsole.SiteURL
+		client, err := gsc.NewClient(gscClientOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to create GSC client: %w", err)
+		}
+		defer func() { _ = client.Close() }()
+
+		var level, detail string
+		perm, err := client.GetSitePermission(site)
+		var apiErr *googleapi.Error
+		switch {
+		case err == nil:
+			level = perm.PermissionLevel
+		case errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden:
+			detail = "the credential has no access to the site"
+		default:
+			return err
+		}
+		svc := adviseAccess("search_console", site, gscPermissionNeeds(cfg), permissionsMode, level, level, gsc.PermissionRank)
+		svc.Detail = detail
+		report.Services = append(report.Services, svc)
+	}
+
+	if len(report.Services) == 0 {
+		return fmt.Errorf("%s configures neither analytics.property_id nor search_console.site_url", permissionsConfig)
+	}
+
+	if permissionsFormat == "json" {
+		if err := output.JSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else if err := displayPermissions(report); err != nil {
+		return err
+	}
+
+	for _, s := range report.Services {
+		if s.Verdict == accessInsufficient {
+			return errPermissionsInsufficient
+		}
+	}
+	return nil
+}
+
+// ga4PermissionNeeds lists the GA4 operations the config implies.
+func ga4PermissionNeeds(cfg *config.ProjectConfig) []permissionNeed {
+	needs := []permissionNeed{{Operation: "reports, exports and drift checks", Role: ga4.RoleViewer}}
+	write := func(n int, what string) {
+		if n > 0 {
+			needs = append(needs, permissionNeed{Operation: fmt.Sprintf("create %d %s", n, what), Role: ga4.RoleEditor, SetupOnly: true})
+		}
+	}
+	write(len(cfg.Conversions), "conversions")
+	write(len(cfg.Dimensions), "custom dimensions")
+	write(len(cfg.Metrics), "custom metrics")
+	write(len(cfg.CalculatedMetrics), "calculated metrics")
+	if cfg.DataRetention != nil {
+		needs = append(needs, permissionNeed{Operation: "update data retention", Role: ga4.RoleEditor, SetupOnly: true})
+	}
+	if cfg.EnhancedMeasurement != nil {
+		needs = append(needs, permissionNeed{Operation: "update enhanced measurement", Role: ga4.RoleEditor, SetupOnly: true})
+	}
+	if n := len(cfg.Cleanup.ConversionsToRemove) + len(cfg.Cleanup.DimensionsToRemove) + len(cfg.Cleanup.MetricsToRemove); n > 0 {
+		needs = append(needs, permissionNeed{Operation: fmt.Sprintf("clean up %d resources", n), Role: ga4.RoleEditor, SetupOnly: true})
+	}
+	return needs
+}
+
+// gscPermissionNeeds lists the Search Console operations the config implies.
+func gscPermissionNeeds(cfg *config.ProjectConfig) []permissionNeed {
+	needs := []permissionNeed{{Operation: "search analytics, URL inspection and sitemap status", Role: gsc.PermissionRestricted}}
+	var submit, remove int
+	for _, sm := range cfg.SearchConsole.Sitemaps {
+		switch {
+		case sm.Remove:
+			remove++
+		case sm.AutoSubmit:
+			submit++
+		}
+	}
+	if submit > 0 {
+		needs = append(needs, permissionNeed{Operation: fmt.Sprintf("submit %d sitemaps", submit), Role: gsc.PermissionFull, SetupOnly: true})
+	}
+	if remove > 0 {
+		needs = append(needs, permissionNeed{Operation: fmt.Sprintf("delete %d sitemaps", remove), Role: gsc.PermissionFull, SetupOnly: true})
+	}
+	return needs
+}
+
+// adviseAccess compares the credential's role range [atLeast, atMost] with
+// the highest role the mode's needs call for.
+func adviseAccess(service, resource string, needs []permissionNeed, mode, atLeast, atMost string, rank func(string) int) serviceAccess {
+	s := serviceAccess{Service: service, Resource: resource, Needs: needs, AtLeast: atLeast, AtMost: atMost}
+	for _, n := range needs {
+		if n.SetupOnly && mode != permissionsModeSetup {
+			continue
+		}
+		if rank(n.Role) > rank(s.Required) {
+			s.Required = n.Role
+		}
+	}
+	switch required := rank(s.Required); {
+	case rank(atMost) < required:
+		s.Verdict = accessInsufficient
+	case rank(atLeast) < required:
+		s.Verdict = accessUnverified
+	case rank(atLeast) > required:
+		s.Verdict = accessExcessive
+	default:
+		s.Verdict = accessOK
+	}
+	return s
+}
+
+func displayPermissions(r permissionsReport) error {
+	theme.Cyan("═══ Permissions advisor: %s (%s mode) ═══", r.Principal, r.Mode)
+	theme.Println()
+
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Service", "Resource", "Current", "Minimum", "Verdict"},
+		r.Services, func(s serviceAccess) []string {
+			return []string{s.Service, s.Resource, formatRoleRange(s.AtLeast, s.AtMost), s.Required, accessVerdictCell(s.Verdict)}
+		}); err != nil {
+		return err
+	}
+
+	for _, s := range r.Services {
+		theme.Println()
+		theme.Cyan("%s operations:", s.Service)
+		for _, n := range s.Needs {
+			scope := ""
+			if n.SetupOnly {
+				scope = " (setup)"
+			}
+			theme.Printf("   %-55s %s%s\n", n.Operation, n.Role, scope)
+		}
+		for _, b := range s.Bindings {
+			theme.HiBlack("   granted %s", b)
+		}
+		if s.Detail != "" {
+			theme.HiBlack("   ℹ %s", s.Detail)
+		}
+	}
+
+	theme.Println()
+	for _, s := range r.Services {
+		switch s.Verdict {
+		case accessInsufficient:
+			theme.Red("✗ %s: grant %s on %s.", s.Service, s.Required, s.Resource)
+		case accessExcessive:
+			theme.Yellow("⚠ %s: %s is more than the config needs; %s is enough.", s.Service, s.AtLeast, s.Required)
+		case accessUnverified:
+			theme.Yellow("⚠ %s: could not confirm %s; make sure the credential holds it.", s.Service, s.Required)
+		default:
+			theme.Green("✓ %s: %s is the minimum the config needs.", s.Service, s.Required)
+		}
+	}
+	return nil
+}
+
+func formatRoleRange(atLeast, atMost string) string {
+	switch {
+	case atMost == "":
+		return "none"
+	case atLeast == atMost:
+		return atLeast
+	default:
+		return atLeast + "–" + atMost
+	}
+}
+
+func accessVerdictCell(verdict string) string {
+	switch verdict {
+	case accessOK:
+		return theme.GreenString("%s", verdict)
+	case accessInsufficient:
+		return theme.RedString("%s", verdict)
+	default:
+		return theme.YellowString("%s", verdict)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestPermissionNeeds(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Analytics:   &config.AnalyticsConfig{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "purchase"}},
+		Cleanup:     config.CleanupConfig{DimensionsToRemove: []string{"old"}},
+		SearchConsole: &config.SearchConsoleConfig{
+			SiteURL:  "sc-domain:example.com",
+			Sitemaps: []config.SitemapConfig{{URL: "https://example.com/a.xml"}, {URL: "https://example.com/b.xml", AutoSubmit: true}},
+		},
+	}
+
+	ga := ga4PermissionNeeds(cfg)
+	assert.Len(t, ga, 3, "read, conversions, cleanup")
+	assert.Equal(t, ga4.RoleViewer, ga[0].Role)
+	assert.Equal(t, "clean up 1 resources", ga[2].Operation)
+
+	sc := gscPermissionNeeds(cfg)
+	assert.Equal(t, []permissionNeed{
+		{Operation: "search analytics, URL inspection and sitemap status", Role: gsc.PermissionRestricted},
+		{Operation: "submit 1 sitemaps", Role: gsc.PermissionFull, SetupOnly: true},
+	}, sc)
+
+	cfg.SearchConsole.Sitemaps = append(cfg.SearchConsole.Sitemaps, config.SitemapConfig{URL: "https://example.com/old.xml", Remove: true})
+	sc = gscPermissionNeeds(cfg)
+	assert.Len(t, sc, 3)
+	assert.Equal(t, "delete 1 sitemaps", sc[2].Operation)
+
+	cfg.SearchConsole.Sitemaps = nil
+	assert.Len(t, gscPermissionNeeds(cfg), 1)
+}
+
+func TestAdviseAccess(t *testing.T) {
+	needs := []permissionNeed{
+		{Operation: "read", Role: ga4.RoleViewer},
+		{Operation: "write", Role: ga4.RoleEditor, SetupOnly: true},
+	}
+	cases := []struct {
+		name, mode, atLeast, atMost string
+		required, verdict           string
+	}{
+		{"editor for setup", permissionsModeSetup, ga4.RoleEditor, ga4.RoleEditor, ga4.RoleEditor, accessOK},
+		{"admin for setup", permissionsModeSetup, ga4.RoleAdmin, ga4.RoleAdmin, ga4.RoleEditor, accessExcessive},
+		{"viewer for setup", permissionsModeSetup, ga4.RoleViewer, ga4.RoleViewer, ga4.RoleEditor, accessInsufficient},
+		{"range for setup", permissionsModeSetup, ga4.RoleViewer, ga4.RoleEditor, ga4.RoleEditor, accessUnverified},
+		{"range for reports", permissionsModeReport, ga4.RoleViewer, ga4.RoleEditor, ga4.RoleViewer, accessOK},
+		{"editor for reports", permissionsModeReport, ga4.RoleEditor, ga4.RoleEditor, ga4.RoleViewer, accessExcessive},
+		{"no access", permissionsModeReport, "", "", ga4.RoleViewer, accessInsufficient},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := adviseAccess("ga4", "123", needs, c.mode, c.atLeast, c.atMost, ga4.RoleRank)
+			assert.Equal(t, c.required, got.Required)
+			assert.Equal(t, c.verdict, got.Verdict)
+		})
+	}
+
+	t.Run("gsc owner", func(t *testing.T) {
+		got := adviseAccess("search_console", "sc-domain:example.com", []permissionNeed{{Role: gsc.PermissionRestricted}},
+			permissionsModeSetup, gsc.PermissionOwner, gsc.PermissionOwner, gsc.PermissionRank)
+		assert.Equal(t, accessExcessive, got.Verdict)
+	})
+}
+
+func TestFormatRoleRange(t *testing.T) {
+	assert.Equal(t, "none", formatRoleRange("", ""))
+	assert.Equal(t, "editor", formatRoleRange("editor", "editor"))
+	assert.Equal(t, "viewer–editor", formatRoleRange("viewer", "editor"))
+}
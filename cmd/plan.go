@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	planConfigPath string
+	planProject    string
+	planFormat     string
+	planOutput     string
+	planSignKey    string
+	planTarget     string
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what setup would change on a GA4 property",
+	Long: `Compare a config with its GA4 property and list every conversion, dimension,
+metric, calculated metric and channel group, and the data retention and
+enhanced measurement settings, with what ` + "`ga4 setup`" + ` would do to it:
+
+  create   the resource does not exist and would be created
+  update   a channel group's description or rules, or a setting, differ, or
+           a conversion, dimension or metric with on_conflict: update does
+  noop     the resource exists and matches the config
+  drift    any other resource exists but differs; setup leaves it as it is
+  ignored  the resource is outside the config's setup scope
+
+--format json emits the diff document external approval tools and chat-ops
+bots consume: each change carries its resource type, name, action, and the
+before and after states. docs/PLAN_DIFF.md documents the schema.
+--output writes the document to a file instead of stdout.
+
+--sign signs the plan with an Ed25519 private key in PEM, for properties whose
+config sets setup.require_signed_plan. The signed document wraps the diff with
+the digest of the config and the signature; ` + "`ga4 apply`" + ` checks both before
+changing anything. Create a key pair with openssl:
+
+  openssl genpkey -algorithm ed25519 -out plan-signing.pem
+  openssl pkey -in plan-signing.pem -pubout -out plan-signing.pub.pem
+
+A config with a properties list is compared with each of its properties,
+followed by a summary table; --format json then emits an array of diff
+documents. --property picks one property, which --sign requires.
+
+Nothing is changed on the property.
+
+Examples:
+  ga4 plan --config configs/my-blog.yaml
+  ga4 plan --config configs/my-blog.yaml --format json --output plan.json
+  ga4 plan --config configs/my-blog.yaml --sign plan-signing.pem --output plan.json`,
+	RunE: runPlan,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().StringVarP(&planConfigPath, "config", "c", "", "Path to configuration file")
+	planCmd.Flags().StringVarP(&planProject, "project", "p", "", "Config file name (looks in configs/ and configs/examples/)")
+	output.FormatVar(planCmd.Flags(), &planFormat, "f", output.FormatTable, output.FormatJSON)
+	planCmd.Flags().StringVarP(&planOutput, "output", "o", "", "Write the JSON diff to this file")
+	planCmd.Flags().StringVar(&planSignKey, "sign", "", "Sign the plan with this Ed25519 private key (PEM)")
+	planCmd.Flags().StringVar(&planTarget, "property", "", "Plan only this entry of a multi-property config's properties list")
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	if planConfigPath == "" && planProject == "" {
+		return fmt.Errorf("--config or --project is required")
+	}
+	if planSignKey != "" && planOutput == "" && planFormat != "json" {
+		return fmt.Errorf("--sign writes a JSON document: add --output or --format json")
+	}
+	configs, paths, err := loadProjectConfigs(planConfigPath, planProject, false)
+	if err != nil {
+		return err
+	}
+	if configs, _, err = selectProperty(configs, paths, planTarget); err != nil {
+		return err
+	}
+	if len(configs) > 1 && planSignKey != "" {
+		return fmt.Errorf("--sign signs the plan of one property: pick it with --property")
+	}
+	var analytics []*config.ProjectConfig
+	for _, cfg := range configs {
+		if cfg.HasAnalytics() {
+			analytics = append(analytics, cfg)
+		}
+	}
+	if len(analytics) == 0 {
+		return fmt.Errorf("config %q has no analytics section", configs[0].Project.Name)
+	}
+
+	client, err := newGA4Client(analytics...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	diffs := make([]*setup.Diff, 0, len(analytics))
+	for _, cfg := range analytics {
+		diff, err := setup.BuildDiff(cfg, client)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cfg.Project.Name, err)
+		}
+		diffs = append(diffs, diff)
+	}
+
+	var doc any = diffs
+	if len(diffs) == 1 {
+		doc = diffs[0]
+	}
+	if planSignKey != "" {
+		signed, err := signPlan(diffs[0], analytics[0], planSignKey)
+		if err != nil {
+			return err
+		}
+		doc = signed
+	}
+
+	if planOutput != "" {
+		if err := writePlanFile(planOutput, doc); err != nil {
+			return err
+		}
+		if planFormat == "json" {
+			return nil
+		}
+	} else if planFormat == "json" {
+		return output.JSON(os.Stdout, doc)
+	}
+	for i, diff := range diffs {
+		if i > 0 {
+			theme.Println()
+		}
+		if err := displayPlan(diff); err != nil {
+			return err
+		}
+	}
+	if len(diffs) > 1 {
+		return displayPlanSummary(diffs)
+	}
+	return nil
+}
+
+// writePlanFile writes a plan document for ga4 apply to path.
+func writePlanFile(path string, doc any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write plan: %w", err)
+	}
+	theme.Fprintf(os.Stderr, "Plan written to %s\n", path)
+	return nil
+}
+
+// signPlan wraps the diff with the config digest and signs it with the key
+// at keyPath.
+func signPlan(diff *setup.Diff, cfg *config.ProjectConfig, keyPath string) (*setup.SignedPlan, error) {
+	key, err := setup.LoadPrivateKey(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load signing key: %w", err)
+	}
+	signed, err := setup.NewSignedPlan(diff, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := signed.Sign(key); err != nil {
+		return nil, err
+	}
+	theme.Fprintf(os.Stderr, "Plan signed with key %s\n", signed.Signature.KeyID)
+	return signed, nil
+}
+
+func displayPlan(d *setup.Diff) error {
+	theme.Cyan("═══ Plan: %s (property %s) ═══", d.Project, d.PropertyID)
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Resource", "Name", "Action", "Differs"},
+		d.Changes, func(c setup.Change) []string {
+			return []string{c.Resource, c.Name, c.Action, strings.Join(changedFields(c), ", ")}
+		}); err != nil {
+		return err
+	}
+	theme.Printf("\n%d to create, %d to update, %d unchanged, %d drifted, %d ignored, %d dropped\n",
+		d.Summary[setup.DiffActionCreate], d.Summary[setup.DiffActionUpdate], d.Summary[setup.DiffActionNoop],
+		d.Summary[setup.DiffActionDrift], d.Summary[setup.DiffActionIgnored], d.Summary[setup.DiffActionDropped])
+	if d.Summary[setup.DiffActionDrift] > 0 {
+		theme.Yellow("⚠ Drifted resources differ from the config; setup does not change them.")
+	}
+	if d.Summary[setup.DiffActionDropped] > 0 {
+		theme.Yellow("⚠ Dropped resources would go past the tier limits; setup leaves them out. Raise their priority or remove others to keep them.")
+	}
+	return nil
+}
+
+// displayPlanSummary tallies the actions of each property's plan.
+func displayPlanSummary(diffs []*setup.Diff) error {
+	theme.Println()
+	theme.Cyan("═══ Plan summary ═══")
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Project", "Property", "Create", "Update", "Unchanged", "Drift", "Ignored", "Dropped"},
+		diffs, func(d *setup.Diff) []string {
+			return []string{d.Project, d.PropertyID,
+				fmt.Sprint(d.Summary[setup.DiffActionCreate]), fmt.Sprint(d.Summary[setup.DiffActionUpdate]),
+				fmt.Sprint(d.Summary[setup.DiffActionNoop]), fmt.Sprint(d.Summary[setup.DiffActionDrift]),
+				fmt.Sprint(d.Summary[setup.DiffActionIgnored]), fmt.Sprint(d.Summary[setup.DiffActionDropped])}
+		})
+}
+
+// changedFields lists the fields of c whose before and after differ, for
+// resources that exist.
+func changedFields(c setup.Change) []string {
+	if c.Before == nil || c.Action == setup.DiffActionNoop {
+		return nil
+	}
+	keys := make([]string, 0, len(c.After))
+	for k := range c.After {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var fields []string
+	for _, k := range keys {
+		before, after := c.Before[k], c.After[k]
+		if k == "rules" {
+			// Existing rules come back without their expressions.
+			before, after = ruleNames(before), ruleNames(after)
+		}
+		if fmt.Sprint(before) != fmt.Sprint(after) {
+			fields = append(fields, k)
+		}
+	}
+	if len(fields) == 0 && c.Action == setup.DiffActionUpdate {
+		// Same rule names, so an expression changed.
+		fields = append(fields, "rules")
+	}
+	return fields
+}
+
+func ruleNames(v any) []string {
+	rules, _ := v.([]map[string]any)
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, fmt.Sprint(r["display_name"]))
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+)
+
+// profileFlag is the --profile flag; empty keeps $GA4_PROFILE or the default
+// profile of `ga4 auth profiles default`.
+var profileFlag string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Credentials profile to use (default $"+auth.ProfileEnvVar+" or the default profile, see `ga4 auth profiles`)")
+	cobra.OnInitialize(applyProfile)
+}
+
+// applyProfile switches to the --profile credentials once flags are parsed.
+func applyProfile() {
+	if profileFlag == "" {
+		return
+	}
+	if err := auth.UseProfile(profileFlag); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// activateEnvProfile applies $GA4_PROFILE, else the default profile, before
+// flags are parsed so the credential checks of init already see it. An
+// explicit GOOGLE_APPLICATION_CREDENTIALS beats the default profile but not
+// $GA4_PROFILE.
+func activateEnvProfile() error {
+	name := os.Getenv(auth.ProfileEnvVar)
+	if name == "" {
+		if os.Getenv(auth.CredentialsEnvVar) != "" {
+			return nil
+		}
+		profiles, err := auth.LoadProfiles()
+		if err != nil {
+			return err
+		}
+		name = profiles.Default
+	}
+	if name == "" {
+		return nil
+	}
+	return auth.UseProfile(name)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	promoteFrom    string
+	promoteTo      string
+	promoteWrite   bool
+	promoteOutput  string
+	promoteSignKey string
+	promoteFormat  string
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Copy what a staging property has to production through plan and apply",
+	Long: `Compare the live GA4 properties of two configs, typically staging and
+production, and list what the first has that the second lacks: conversions,
+custom dimensions and metrics, calculated metrics and custom channel groups.
+Changes can then be tried on the staging property before they reach
+production.
+
+  copy     only the staging property has the resource, and the production
+           config does not define it yet
+  differs  both properties have it with different definitions; it is listed
+           for review and left alone
+
+--write adds the resources to copy to the production config, taking each
+from the staging config when it defines it (so owners, parameters and
+default values come along) and from the staging property otherwise. It then
+plans the production config against its property, as ` + "`ga4 plan`" + ` does, and
+--output writes that plan for ` + "`ga4 apply`" + `. Production is only changed by
+apply, so the promotion can be reviewed, and signed with --sign, first.
+
+Configs with a properties list are not supported; promote compares one
+property with another.
+
+Examples:
+  ga4 promote --from configs/staging.yaml --to configs/prod.yaml
+  ga4 promote --from configs/staging.yaml --to configs/prod.yaml --write --output plan.json
+  ga4 apply --config configs/prod.yaml --plan plan.json`,
+	Args: cobra.NoArgs,
+	RunE: runPromote,
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().StringVar(&promoteFrom, "from", "", "Config of the property to promote from, such as staging (required)")
+	promoteCmd.Flags().StringVar(&promoteTo, "to", "", "Config of the property to promote to, such as production (required)")
+	promoteCmd.Flags().BoolVar(&promoteWrite, "write", false, "Add the resources to copy to the --to config and plan it")
+	promoteCmd.Flags().StringVarP(&promoteOutput, "output", "o", "", "Write the production plan to this file for ga4 apply (with --write)")
+	promoteCmd.Flags().StringVar(&promoteSignKey, "sign", "", "Sign the production plan with this Ed25519 private key (PEM)")
+	output.FormatVar(promoteCmd.Flags(), &promoteFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = promoteCmd.MarkFlagRequired("from")
+	_ = promoteCmd.MarkFlagRequired("to")
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	if !promoteWrite && (promoteOutput != "" || promoteSignKey != "") {
+		return fmt.Errorf("--output and --sign plan the production config: add --write")
+	}
+	if promoteSignKey != "" && promoteOutput == "" {
+		return fmt.Errorf("--sign writes a plan file: add --output")
+	}
+	from, err := loadPromoteConfig(promoteFrom)
+	if err != nil {
+		return err
+	}
+	to, err := loadPromoteConfig(promoteTo)
+	if err != nil {
+		return err
+	}
+
+	client, err := newGA4Client(from, to)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	promotion, err := setup.BuildPromotion(from, to, client)
+	if err != nil {
+		return err
+	}
+	if promoteFormat == output.FormatJSON {
+		if err := output.JSON(os.Stdout, promotion); err != nil {
+			return err
+		}
+	} else if err := displayPromotion(promotion); err != nil {
+		return err
+	}
+
+	if !promotion.Pending() {
+		theme.Fprintf(os.Stderr, "%s Nothing to promote: %s has everything %s has.\n", theme.GreenString("✓"), to.Project.Name, from.Project.Name)
+		return nil
+	}
+	if !promoteWrite {
+		theme.Fprintf(os.Stderr, "Run again with --write to add them to %s and plan the change.\n", promoteTo)
+		return nil
+	}
+
+	if err := config.AppendDefinitions(promoteTo, &promotion.Copy); err != nil {
+		return err
+	}
+	theme.Fprintf(os.Stderr, "%s Added %d resources to %s\n", theme.GreenString("✓"), countCopied(promotion), promoteTo)
+	if to, err = config.LoadConfig(promoteTo); err != nil {
+		return fmt.Errorf("failed to load promoted config: %w", err)
+	}
+	diff, err := setup.BuildDiff(to, client)
+	if err != nil {
+		return err
+	}
+
+	if promoteOutput != "" {
+		var doc any = diff
+		if promoteSignKey != "" {
+			if doc, err = signPlan(diff, to, promoteSignKey); err != nil {
+				return err
+			}
+		}
+		if err := writePlanFile(promoteOutput, doc); err != nil {
+			return err
+		}
+	}
+	if promoteFormat != output.FormatJSON {
+		theme.Println()
+		if err := displayPlan(diff); err != nil {
+			return err
+		}
+	}
+	if promoteOutput != "" {
+		theme.Fprintf(os.Stderr, "Review the plan, then apply it with: ga4 apply --config %s --plan %s\n", promoteTo, promoteOutput)
+	} else {
+		theme.Fprintf(os.Stderr, "Save the plan for ga4 apply with: ga4 plan --config %s --output plan.json\n", promoteTo)
+	}
+	return nil
+}
+
+// loadPromoteConfig loads a config promote compares: one GA4 property.
+func loadPromoteConfig(path string) (*config.ProjectConfig, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+	if !cfg.HasAnalytics() {
+		return nil, fmt.Errorf("config %s has no analytics section", path)
+	}
+	if len(cfg.Properties) > 0 {
+		return nil, fmt.Errorf("config %s has a properties list; promote compares single-property configs", path)
+	}
+	return cfg, nil
+}
+
+func displayPromotion(p *setup.Promotion) error {
+	theme.Cyan("═══ Promote: property %s → %s ═══", p.From, p.To)
+	if len(p.Changes) == 0 {
+		theme.Println("The properties define the same resources.")
+		return nil
+	}
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Resource", "Name", "Action", "Differs"},
+		p.Changes, func(c setup.Change) []string {
+			fields := changedFields(c)
+			if len(fields) == 0 && c.Action == setup.PromoteActionDiffers {
+				// Same rule names, so an expression differs.
+				fields = []string{"rules"}
+			}
+			return []string{c.Resource, c.Name, c.Action, strings.Join(fields, ", ")}
+		}); err != nil {
+		return err
+	}
+	differs := len(p.Changes) - countCopied(p)
+	theme.Printf("\n%d to copy, %d differ\n", countCopied(p), differs)
+	if differs > 0 {
+		theme.Yellow("⚠ Resources that differ are not copied; change them in the production config.")
+	}
+	return nil
+}
+
+func countCopied(p *setup.Promotion) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == setup.PromoteActionCopy {
+			n++
+		}
+	}
+	return n
+}
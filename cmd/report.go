@@ -3,13 +3,15 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"os"
 
 	"github.com/fatih/color"
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 	admin "google.golang.org/api/analyticsadmin/v1alpha"
@@ -35,7 +37,8 @@ func init() {
 	reportCmd.Flags().BoolVarP(&reportAll, "all", "a", false, "Report on all projects")
 	reportCmd.Flags().StringVarP(&reportConfigPath, "config", "c", "", "Path to configuration file")
 	reportCmd.Flags().StringVarP(&reportExport, "export", "e", "", "Export format: csv, json, or markdown (no aliases)")
-	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file path (default: stdout or auto-generated filename)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file path (default: stdout or auto-generated filename), or bq://project.dataset.table / sheets://<spreadsheetId>/<tab> to append the report to BigQuery or a Google Sheet")
+	addTableOutputFlags(reportCmd)
 }
 
 // runReport is the Cobra RunE handler — reads flag variables and delegates to executeReport.
@@ -44,15 +47,13 @@ func runReport(cmd *cobra.Command, args []string) error {
 }
 
 // executeReport performs the report with explicit parameters, avoiding reliance on global flag state.
-func executeReport(cfgPath, projName string, all bool, export, output string) error {
-	cyan := color.New(color.FgCyan).SprintFunc()
+func executeReport(cfgPath, projName string, all bool, export, outputPath string) error {
+	cyan := theme.Color(color.FgCyan).SprintFunc()
 
-	// Create GA4 client
-	client, err := newGA4Client()
+	dest, err := parseTableDestination(outputPath)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
 
 	// Load projects based on flags
 	projects, err := loadProjects(cfgPath, projName, all)
@@ -60,31 +61,70 @@ func executeReport(cfgPath, projName string, all bool, export, output string) er
 		return err
 	}
 
+	// Create GA4 client
+	client, err := newGA4Client(projects...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
 	// Handle export mode
+	if dest != nil {
+		return exportReportsToTable(client, projects, dest)
+	}
 	if export != "" {
-		return exportReports(client, projects, export, output)
+		return exportReports(client, projects, export, outputPath)
 	}
 
 	// Normal display mode
-	fmt.Printf("%s GA4 Configuration Report\n", cyan("📊"))
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Println()
+	theme.Printf("%s GA4 Configuration Report\n", cyan("📊"))
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Println()
 
 	// Report on each project
+	totals := make([]reportTotals, 0, len(projects))
 	for i, project := range projects {
 		if i > 0 {
-			fmt.Println()
-			fmt.Println()
+			theme.Println()
+			theme.Println()
 		}
 
-		if err := reportProject(client, project); err != nil {
+		t, err := reportProject(client, project)
+		if err != nil {
 			return err
 		}
+		totals = append(totals, t)
 	}
 
+	if len(projects) > 1 {
+		theme.Println()
+		theme.Println()
+		theme.Printf("%s Summary\n", cyan("📋"))
+		theme.Println("───────────────────────────────────────────────")
+		return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"Project", "Property", "Conversions", "Dimensions", "Metrics"},
+			totals, func(t reportTotals) []string {
+				metrics := "?"
+				if t.metrics >= 0 {
+					metrics = fmt.Sprint(t.metrics)
+				}
+				return []string{t.project, t.propertyID, fmt.Sprint(t.conversions), fmt.Sprint(t.dimensions), metrics}
+			})
+	}
 	return nil
 }
 
+// reportTotals counts what reportProject found on a property, for the
+// summary table of multi-project reports. metrics is -1 when they could not
+// be listed.
+type reportTotals struct {
+	project     string
+	propertyID  string
+	conversions int
+	dimensions  int
+	metrics     int
+}
+
 // handleReportAction handles the "View Reports" menu action in interactive mode.
 func handleReportAction() {
 	projectPath, err := tui.RunProjectSelector()
@@ -92,7 +132,7 @@ func handleReportAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
@@ -101,15 +141,15 @@ func handleReportAction() {
 
 	if projectPath == "--all" {
 		all = true
-		fmt.Println("\n📊 Loading reports for all projects...")
+		theme.Println("\n📊 Loading reports for all projects...")
 	} else {
 		cfgPath = projectPath
-		fmt.Printf("\n📊 Loading report for %s...\n", projectPath)
+		theme.Printf("\n📊 Loading report for %s...\n", projectPath)
 	}
-	fmt.Println()
+	theme.Println()
 
 	if err := executeReport(cfgPath, "", all, "", ""); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error running report: %v\n", err)
+		theme.Fprintf(os.Stderr, "\n❌ Error running report: %v\n", err)
 		return
 	}
 
@@ -124,16 +164,16 @@ func handleExportAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
 	var all bool
 	if projectPath == "--all" {
 		all = true
-		fmt.Println("\n💾 Exporting reports for all projects...")
+		theme.Println("\n💾 Exporting reports for all projects...")
 	} else {
-		fmt.Printf("\n💾 Preparing to export report for %s...\n", projectPath)
+		theme.Printf("\n💾 Preparing to export report for %s...\n", projectPath)
 	}
 
 	format := promptFormatSelection()
@@ -144,13 +184,13 @@ func handleExportAction() {
 
 // promptFormatSelection prompts user to select export format.
 func promptFormatSelection() string {
-	fmt.Println("\n" + strings.Repeat("─", 50))
-	fmt.Println("\n📦 Select export format:")
-	fmt.Println("  1. JSON - Single file with all data")
-	fmt.Println("  2. CSV - Multiple files (conversions, dimensions, metrics, etc.)")
-	fmt.Println("  3. Markdown - Formatted report with tables")
-	fmt.Println("  4. Cancel (return to menu)")
-	fmt.Print("\nSelect option (1-4): ")
+	theme.Println("\n" + strings.Repeat("─", 50))
+	theme.Println("\n📦 Select export format:")
+	theme.Println("  1. JSON - Single file with all data")
+	theme.Println("  2. CSV - Multiple files (conversions, dimensions, metrics, etc.)")
+	theme.Println("  3. Markdown - Formatted report with tables")
+	theme.Println("  4. Cancel (return to menu)")
+	theme.Print("\nSelect option (1-4): ")
 
 	var choice string
 	_, _ = fmt.Scanln(&choice)
@@ -163,23 +203,23 @@ func promptFormatSelection() string {
 	case "3":
 		return "markdown"
 	case "4", "":
-		fmt.Println("\nExport cancelled.")
+		theme.Println("\nExport cancelled.")
 		return ""
 	default:
-		fmt.Println("\nInvalid choice. Export cancelled.")
+		theme.Println("\nInvalid choice. Export cancelled.")
 		return ""
 	}
 }
 
 // promptReportExport prompts the user to export the report after viewing.
 func promptReportExport(projectPath string, all bool) {
-	fmt.Println("\n" + strings.Repeat("─", 50))
-	fmt.Println("\n💾 Would you like to export this report?")
-	fmt.Println("  1. Export as JSON")
-	fmt.Println("  2. Export as CSV (multiple files)")
-	fmt.Println("  3. Export as Markdown")
-	fmt.Println("  4. Skip export (return to menu)")
-	fmt.Print("\nSelect option (1-4): ")
+	theme.Println("\n" + strings.Repeat("─", 50))
+	theme.Println("\n💾 Would you like to export this report?")
+	theme.Println("  1. Export as JSON")
+	theme.Println("  2. Export as CSV (multiple files)")
+	theme.Println("  3. Export as Markdown")
+	theme.Println("  4. Skip export (return to menu)")
+	theme.Print("\nSelect option (1-4): ")
 
 	var choice string
 	_, _ = fmt.Scanln(&choice)
@@ -195,32 +235,32 @@ func promptReportExport(projectPath string, all bool) {
 		// Skip export, return to menu
 		return
 	default:
-		fmt.Println("Invalid choice, skipping export.")
+		theme.Println("Invalid choice, skipping export.")
 	}
 }
 
 // executeExport performs the actual export operation.
 func executeExport(projectPath string, all bool, format string) {
-	fmt.Printf("\n📤 Exporting as %s...\n\n", strings.ToUpper(format))
+	theme.Printf("\n📤 Exporting as %s...\n\n", strings.ToUpper(format))
 
-	// Create GA4 client
-	client, err := newGA4Client()
+	// Load projects
+	projects, err := loadProjects(projectPath, "", all)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		theme.Fprintf(os.Stderr, "Error loading projects: %v\n", err)
 		return
 	}
-	defer client.Close()
 
-	// Load projects
-	projects, err := loadProjects(projectPath, "", all)
+	// Create GA4 client
+	client, err := newGA4Client(projects...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading projects: %v\n", err)
+		theme.Fprintln(os.Stderr, err)
 		return
 	}
+	defer client.Close()
 
 	// Export with auto-generated filename
 	if err := exportReports(client, projects, format, ""); err != nil {
-		fmt.Fprintf(os.Stderr, "Error exporting report: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error exporting report: %v\n", err)
 	}
 }
 
@@ -235,11 +275,11 @@ func exportReports(client *ga4.Client, projects []*config.ProjectConfig, format,
 		return fmt.Errorf("invalid export format: %s (supported: csv, json, markdown)", format)
 	}
 
-	fmt.Printf("📤 Exporting reports in %s format...\n\n", strings.ToUpper(format))
+	theme.Printf("📤 Exporting reports in %s format...\n\n", strings.ToUpper(format))
 
 	// Export each project
 	for _, project := range projects {
-		fmt.Printf("Collecting data for %s...\n", project.Project.Name)
+		theme.Printf("Collecting data for %s...\n", project.Project.Name)
 
 		data, err := collectReportData(client, project)
 		if err != nil {
@@ -270,124 +310,150 @@ func exportReports(client *ga4.Client, projects []*config.ProjectConfig, format,
 			}
 		}
 
-		fmt.Println()
+		theme.Println()
 	}
 
-	fmt.Println("✓ Export completed successfully!")
+	theme.Println("✓ Export completed successfully!")
 	return nil
 }
 
-func reportProject(client *ga4.Client, cfg *config.ProjectConfig) error {
-	blue := color.New(color.FgBlue, color.Bold).SprintFunc()
+// exportReportsToTable appends each project's configuration to dest, one row
+// per resource, so changes to the properties can be followed over time.
+func exportReportsToTable(client *ga4.Client, projects []*config.ProjectConfig, dest *tableDestination) error {
+	w, err := dest.open()
+	if err != nil {
+		return err
+	}
+	exportedAt := time.Now().UTC()
+	for _, project := range projects {
+		theme.Printf("Collecting data for %s...\n", project.Project.Name)
 
-	fmt.Printf("%s %s (Property: %s)\n", blue("📦"), cfg.Project.Name, cfg.GetPropertyID())
-	fmt.Println("───────────────────────────────────────────────")
-	fmt.Println()
+		data, err := collectReportData(client, project)
+		if err != nil {
+			return fmt.Errorf("failed to collect report data for %s: %w", project.Project.Name, err)
+		}
+		rows := reportTableRows(data, exportedAt)
+		if err := w.Write(rows); err != nil {
+			return err
+		}
+		theme.Printf("✓ Appended %d rows to %s\n", len(rows.Values), dest)
+	}
+	return nil
+}
+
+func reportProject(client *ga4.Client, cfg *config.ProjectConfig) (reportTotals, error) {
+	blue := theme.Color(color.FgBlue, color.Bold).SprintFunc()
+
+	theme.Printf("%s %s (Property: %s)\n", blue("📦"), cfg.Project.Name, cfg.GetPropertyID())
+	theme.Println("───────────────────────────────────────────────")
+	theme.Println()
 
 	propertyID := cfg.GetPropertyID()
+	totals := reportTotals{project: cfg.Project.Name, propertyID: propertyID, metrics: -1}
 
 	// List conversions
-	fmt.Println("🎯 Conversions")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println("🎯 Conversions")
+	theme.Println("───────────────────────────────────────────────")
 	conversions, err := client.ListConversions(propertyID)
 	if err != nil {
-		return fmt.Errorf("failed to list conversions: %w", err)
+		return totals, fmt.Errorf("failed to list conversions: %w", err)
 	}
+	totals.conversions = len(conversions)
 
-	if err := render.Render(os.Stdout, render.FormatTable, reportConversionsColumns(), conversions, reportConversionsTableRow); err != nil {
-		return fmt.Errorf("failed to render conversions table: %w", err)
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, reportConversionsColumns(), conversions, reportConversionsTableRow); err != nil {
+		return totals, fmt.Errorf("failed to render conversions table: %w", err)
 	}
 
 	// List dimensions
-	fmt.Println()
-	fmt.Println("📊 Custom Dimensions")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("📊 Custom Dimensions")
+	theme.Println("───────────────────────────────────────────────")
 	dimensions, err := client.ListDimensions(propertyID)
 	if err != nil {
-		return fmt.Errorf("failed to list dimensions: %w", err)
+		return totals, fmt.Errorf("failed to list dimensions: %w", err)
 	}
+	totals.dimensions = len(dimensions)
 
-	if err := render.Render(os.Stdout, render.FormatTable, reportDimensionsColumns(), dimensions, reportDimensionsTableRow); err != nil {
-		return fmt.Errorf("failed to render dimensions table: %w", err)
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, reportDimensionsColumns(), dimensions, reportDimensionsTableRow); err != nil {
+		return totals, fmt.Errorf("failed to render dimensions table: %w", err)
 	}
 
 	// List custom metrics
-	fmt.Println()
-	fmt.Println("📈 Custom Metrics")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("📈 Custom Metrics")
+	theme.Println("───────────────────────────────────────────────")
 	metrics, err := client.ListCustomMetrics(propertyID)
 	if err != nil {
-		fmt.Printf("Warning: failed to list custom metrics: %v\n", err)
+		theme.Printf("Warning: failed to list custom metrics: %v\n", err)
 	} else {
-		if err := render.Render(os.Stdout, render.FormatTable, reportMetricsColumns(), metrics, reportMetricsTableRow); err != nil {
-			return fmt.Errorf("failed to render metrics table: %w", err)
+		totals.metrics = len(metrics)
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, reportMetricsColumns(), metrics, reportMetricsTableRow); err != nil {
+			return totals, fmt.Errorf("failed to render metrics table: %w", err)
 		}
 	}
 
 	// List calculated metrics (recommended)
-	fmt.Println()
-	fmt.Println("🧮 Recommended Calculated Metrics (create manually in GA4 UI)")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("🧮 Recommended Calculated Metrics (create manually in GA4 UI)")
+	theme.Println("───────────────────────────────────────────────")
 	calculatedMetrics, err := client.ListCalculatedMetrics(propertyID)
 	if err != nil {
-		fmt.Printf("Warning: failed to list calculated metrics: %v\n", err)
+		theme.Printf("Warning: failed to list calculated metrics: %v\n", err)
 	} else {
-		if err := render.Render(os.Stdout, render.FormatTable, reportCalculatedColumns(), calculatedMetrics, reportCalculatedTableRow); err != nil {
-			return fmt.Errorf("failed to render calculated metrics table: %w", err)
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, reportCalculatedColumns(), calculatedMetrics, reportCalculatedTableRow); err != nil {
+			return totals, fmt.Errorf("failed to render calculated metrics table: %w", err)
 		}
 	}
 
 	// List audiences
-	fmt.Println()
-	fmt.Println("👥 Configured Audiences")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("👥 Configured Audiences")
+	theme.Println("───────────────────────────────────────────────")
 	audienceSummary := ga4.GetAudienceSummary(cfg)
-	fmt.Println(audienceSummary)
+	theme.Println(audienceSummary)
 
 	audienceCategories := ga4.ListAudiencesByCategory(cfg)
 	audienceRows := make([]config.EnhancedAudience, 0)
-	for _, category := range []string{"SEO", "Conversion", "Content", "Behavioral"} {
-		if audiences, ok := audienceCategories[category]; ok {
-			audienceRows = append(audienceRows, audiences...)
-		}
+	for _, category := range ga4.AudienceCategories(cfg) {
+		audienceRows = append(audienceRows, audienceCategories[category]...)
 	}
-	if err := render.Render(os.Stdout, render.FormatTable, reportAudiencesColumns(), audienceRows, reportAudiencesTableRow); err != nil {
-		return fmt.Errorf("failed to render audiences table: %w", err)
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, reportAudiencesColumns(), audienceRows, reportAudiencesTableRow); err != nil {
+		return totals, fmt.Errorf("failed to render audiences table: %w", err)
 	}
 
-	fmt.Println()
-	fmt.Printf("Note: Audiences must be created manually in GA4 UI. Use './ga4 export --audiences' to generate setup guides.\n")
+	theme.Println()
+	theme.Printf("Note: Audiences must be created manually in GA4 UI. Use './ga4 export --audiences' to generate setup guides.\n")
 
 	// Data retention settings
-	fmt.Println()
-	fmt.Println("🗄️  Data Retention Settings")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("🗄️  Data Retention Settings")
+	theme.Println("───────────────────────────────────────────────")
 	retentionSettings, err := client.GetDataRetention(propertyID)
 	if err != nil {
-		fmt.Printf("Warning: failed to get data retention settings: %v\n", err)
+		theme.Printf("Warning: failed to get data retention settings: %v\n", err)
 	} else {
 		retentionMonths := ga4.GetDataRetentionMonths(retentionSettings.EventDataRetention)
-		fmt.Printf("Event Data Retention: %d months (%s)\n", retentionMonths, retentionSettings.EventDataRetention)
-		fmt.Printf("Reset on New Activity: %t\n", retentionSettings.ResetUserDataOnNewActivity)
+		theme.Printf("Event Data Retention: %d months (%s)\n", retentionMonths, retentionSettings.EventDataRetention)
+		theme.Printf("Reset on New Activity: %t\n", retentionSettings.ResetUserDataOnNewActivity)
 	}
 
 	// Enhanced measurement settings
-	fmt.Println()
-	fmt.Println("⚡ Enhanced Measurement")
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("⚡ Enhanced Measurement")
+	theme.Println("───────────────────────────────────────────────")
 	emSummary, err := client.GetEnhancedMeasurementSummary(propertyID)
 	if err != nil {
-		fmt.Printf("Warning: failed to get enhanced measurement settings: %v\n", err)
+		theme.Printf("Warning: failed to get enhanced measurement settings: %v\n", err)
 	} else {
-		fmt.Print(emSummary)
+		theme.Print(emSummary)
 	}
 
-	return nil
+	return totals, nil
 }
 
 // reportConversionsColumns / reportConversionsTableRow project a conversion
 // event for the report's conversions section. The previous tablewriter
-// output had borderless styling; the new render.Render output uses plain
+// output had borderless styling; the new output.Render output uses plain
 // tabwriter alignment which keeps the same column order and contents.
 func reportConversionsColumns() []string {
 	return []string{"Event Name", "Counting Method"}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	reportAsOfSite   string
+	reportAsOfConfig string
+	reportAsOfLimit  int
+	reportAsOfFormat string
+)
+
+var reportAsOfCmd = &cobra.Command{
+	Use:   "as-of DATE",
+	Short: "Rebuild the saved GSC reports as they stood on a past date",
+	Long: `Render the search performance, index coverage and URL inspection reports
+from the local history store, using only records saved on or before DATE
+(YYYY-MM-DD, end of day in local time). Nothing is fetched from Google.
+
+Use it to see what the reports showed before an incident, when the live data
+has since moved on or aged out of Search Console.
+
+  - Search performance: the last "gsc analytics run --save" before the date
+  - Index coverage: the last "gsc coverage --save" before the date
+  - URL inspection: each page's last saved inspection before the date
+
+Examples:
+  ga4 report as-of 2025-01-15 --config configs/mysite.yaml
+  ga4 report as-of 2025-01-15 --site sc-domain:example.com --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReportAsOf,
+}
+
+func init() {
+	reportCmd.AddCommand(reportAsOfCmd)
+	reportAsOfCmd.Flags().StringVarP(&reportAsOfSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	reportAsOfCmd.Flags().StringVarP(&reportAsOfConfig, "config", "c", "", "Read the site from search_console.site_url in this config")
+	reportAsOfCmd.Flags().IntVarP(&reportAsOfLimit, "limit", "l", 25, "Rows per table (0 = all)")
+	output.FormatVar(reportAsOfCmd.Flags(), &reportAsOfFormat, "f", output.FormatTable, output.FormatJSON)
+	reportAsOfCmd.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+}
+
+// savedRun is one history run: when it was saved and its records.
+type savedRun struct {
+	SavedAt time.Time      `json:"saved_at"`
+	Records []store.Record `json:"records"`
+}
+
+// asOfReport is the `report as-of` output. A nil section had no run saved
+// by the cut-off.
+type asOfReport struct {
+	Site        string         `json:"site"`
+	AsOf        string         `json:"as_of"`
+	Analytics   *savedRun      `json:"analytics"`
+	Coverage    *savedRun      `json:"coverage"`
+	Inspections []store.Record `json:"inspections"`
+}
+
+func runReportAsOf(cmd *cobra.Command, args []string) error {
+	day, err := time.ParseInLocation("2006-01-02", args[0], time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid date %q (want YYYY-MM-DD)", args[0])
+	}
+	if reportAsOfLimit < 0 {
+		return fmt.Errorf("--limit must not be negative, got %d", reportAsOfLimit)
+	}
+	site, err := siteFromFlags(reportAsOfSite, reportAsOfConfig)
+	if err != nil {
+		return err
+	}
+
+	st := store.New(gscstate.ResolveStateDir(historyStateDir))
+	report, err := buildAsOfReport(context.Background(), st, site, day)
+	if err != nil {
+		return err
+	}
+
+	if reportAsOfFormat == "json" {
+		return output.JSON(os.Stdout, report)
+	}
+	return displayAsOfReport(report, reportAsOfLimit)
+}
+
+// buildAsOfReport collects what the history store held for site at the end
+// of day.
+func buildAsOfReport(ctx context.Context, st *store.Store, site string, day time.Time) (*asOfReport, error) {
+	cutoff := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	report := &asOfReport{Site: site, AsOf: day.Format("2006-01-02"), Inspections: []store.Record{}}
+
+	for _, section := range []struct {
+		kind string
+		dst  **savedRun
+	}{
+		{store.KindAnalytics, &report.Analytics},
+		{store.KindCoverage, &report.Coverage},
+	} {
+		records, err := st.RunAsOf(ctx, section.kind, site, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 {
+			*section.dst = &savedRun{SavedAt: records[0].RecordedAt, Records: records}
+		}
+	}
+
+	inspections, err := st.Query(ctx, store.Query{Kind: store.KindInspection, Site: site, Until: cutoff})
+	if err != nil {
+		return nil, err
+	}
+	// Records come oldest first, so the last one per page is its state at
+	// the cut-off.
+	latest := make(map[string]int)
+	for _, r := range inspections {
+		page := r.Dimensions["page"]
+		if i, ok := latest[page]; ok {
+			report.Inspections[i] = r
+			continue
+		}
+		latest[page] = len(report.Inspections)
+		report.Inspections = append(report.Inspections, r)
+	}
+	sort.SliceStable(report.Inspections, func(i, j int) bool {
+		return report.Inspections[i].Dimensions["page"] < report.Inspections[j].Dimensions["page"]
+	})
+	return report, nil
+}
+
+func displayAsOfReport(r *asOfReport, limit int) error {
+	theme.Cyan("═══ Reports for %s as of %s ═══", r.Site, r.AsOf)
+	theme.HiBlack("Rebuilt from the local history store; only runs saved with --save appear.")
+
+	theme.Println()
+	theme.Println("🔍 Search Performance")
+	theme.Println("───────────────────────────────────────────────")
+	if r.Analytics == nil {
+		theme.Yellow("No analytics run saved on or before %s.", r.AsOf)
+	} else {
+		first := r.Analytics.Records[0]
+		theme.Printf("Saved %s, period %s to %s, %d rows\n",
+			r.Analytics.SavedAt.Local().Format("2006-01-02 15:04"), first.StartDate, first.EndDate, len(r.Analytics.Records))
+		rows := append([]store.Record(nil), r.Analytics.Records...)
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Metrics["clicks"] > rows[j].Metrics["clicks"] })
+		dims := recordDimensions(rows)
+		columns := make([]string, 0, len(dims)+4)
+		for _, d := range dims {
+			columns = append(columns, cases.Title(language.English).String(d))
+		}
+		columns = append(columns, "Clicks", "Impressions", "CTR", "Position")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, columns,
+			limitRecords(rows, limit), func(rec store.Record) []string {
+				cells := make([]string, 0, len(columns))
+				for _, d := range dims {
+					cells = append(cells, rec.Dimensions[d])
+				}
+				return append(cells,
+					strconv.FormatFloat(rec.Metrics["clicks"], 'f', 0, 64),
+					strconv.FormatFloat(rec.Metrics["impressions"], 'f', 0, 64),
+					fmt.Sprintf("%.2f%%", rec.Metrics["ctr"]*100),
+					fmt.Sprintf("%.1f", rec.Metrics["position"]))
+			}); err != nil {
+			return err
+		}
+	}
+
+	theme.Println()
+	theme.Println("📑 Index Coverage")
+	theme.Println("───────────────────────────────────────────────")
+	if r.Coverage == nil {
+		theme.Yellow("No coverage run saved on or before %s.", r.AsOf)
+	} else {
+		theme.Printf("Saved %s, %d pages\n", r.Coverage.SavedAt.Local().Format("2006-01-02 15:04"), len(r.Coverage.Records))
+		counts := make(map[string]int)
+		for _, rec := range r.Coverage.Records {
+			counts[rec.State["status"]]++
+		}
+		statuses := make([]string, 0, len(counts))
+		for s := range counts {
+			statuses = append(statuses, s)
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			if counts[statuses[i]] != counts[statuses[j]] {
+				return counts[statuses[i]] > counts[statuses[j]]
+			}
+			return statuses[i] < statuses[j]
+		})
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, []string{"Status", "Pages"},
+			statuses, func(s string) []string {
+				return []string{orUnknownValue(s), strconv.Itoa(counts[s])}
+			}); err != nil {
+			return err
+		}
+	}
+
+	theme.Println()
+	theme.Println("🧭 URL Inspection")
+	theme.Println("───────────────────────────────────────────────")
+	if len(r.Inspections) == 0 {
+		theme.Yellow("No inspections saved on or before %s.", r.AsOf)
+		return nil
+	}
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Page", "Index Status", "Coverage", "Last Crawl", "Saved"},
+		limitRecords(r.Inspections, limit), func(rec store.Record) []string {
+			return []string{
+				rec.Dimensions["page"],
+				getColoredStatus(rec.State["index_status"]),
+				rec.State["coverage_state"],
+				rec.State["last_crawl_time"],
+				rec.RecordedAt.Local().Format("2006-01-02"),
+			}
+		})
+}
+
+// recordDimensions lists the dimension keys present in records, in the order
+// Search Console reports use and then alphabetically.
+func recordDimensions(records []store.Record) []string {
+	order := map[string]int{"query": 1, "page": 2, "country": 3, "device": 4, "date": 5, "searchAppearance": 6}
+	seen := make(map[string]bool)
+	var dims []string
+	for _, r := range records {
+		for k := range r.Dimensions {
+			if !seen[k] {
+				seen[k] = true
+				dims = append(dims, k)
+			}
+		}
+	}
+	sort.Slice(dims, func(i, j int) bool {
+		oi, oj := order[dims[i]], order[dims[j]]
+		if oi == 0 {
+			oi = len(order) + 1
+		}
+		if oj == 0 {
+			oj = len(order) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return dims[i] < dims[j]
+	})
+	return dims
+}
+
+func limitRecords(records []store.Record, limit int) []store.Record {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestBuildAsOfReport_UsesOnlyEarlierRuns(t *testing.T) {
+	ctx := context.Background()
+	st := store.New(t.TempDir())
+	site := "sc-domain:example.com"
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.Local) }
+
+	require.NoError(t, st.Append(ctx, store.KindAnalytics, site, []store.Record{
+		{RecordedAt: at(10, 6), Dimensions: map[string]string{"query": "old"}},
+	}))
+	require.NoError(t, st.Append(ctx, store.KindAnalytics, site, []store.Record{
+		{RecordedAt: at(15, 23), Dimensions: map[string]string{"query": "on the day"}},
+		{RecordedAt: at(15, 23), Dimensions: map[string]string{"query": "also on the day"}},
+	}))
+	require.NoError(t, st.Append(ctx, store.KindAnalytics, site, []store.Record{
+		{RecordedAt: at(16, 0), Dimensions: map[string]string{"query": "too late"}},
+	}))
+	require.NoError(t, st.Append(ctx, store.KindInspection, site, []store.Record{
+		{RecordedAt: at(1, 9), Dimensions: map[string]string{"page": "/b"}, State: map[string]string{"coverage_state": "Submitted and indexed"}},
+		{RecordedAt: at(1, 9), Dimensions: map[string]string{"page": "/a"}, State: map[string]string{"coverage_state": "Discovered"}},
+		{RecordedAt: at(12, 9), Dimensions: map[string]string{"page": "/b"}, State: map[string]string{"coverage_state": "Crawled - currently not indexed"}},
+		{RecordedAt: at(20, 9), Dimensions: map[string]string{"page": "/b"}, State: map[string]string{"coverage_state": "Submitted and indexed"}},
+	}))
+
+	report, err := buildAsOfReport(ctx, st, site, at(15, 0))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-01-15", report.AsOf)
+	require.NotNil(t, report.Analytics)
+	require.Len(t, report.Analytics.Records, 2)
+	assert.Equal(t, "on the day", report.Analytics.Records[0].Dimensions["query"])
+	assert.Nil(t, report.Coverage)
+
+	require.Len(t, report.Inspections, 2)
+	assert.Equal(t, "/a", report.Inspections[0].Dimensions["page"])
+	assert.Equal(t, "Crawled - currently not indexed", report.Inspections[1].State["coverage_state"])
+}
+
+func TestRecordDimensions_SearchConsoleOrder(t *testing.T) {
+	records := []store.Record{
+		{Dimensions: map[string]string{"page": "/", "query": "q", "custom": "x"}},
+		{Dimensions: map[string]string{"device": "MOBILE"}},
+	}
+	assert.Equal(t, []string{"query", "page", "device", "custom"}, recordDimensions(records))
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
-	"fmt"
 	"log"
 	"os"
 	"slices"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // Version is set via ldflags during build
@@ -33,18 +37,39 @@ Configure your projects using YAML config files.`,
 	},
 }
 
+// Execute runs the command line and exits with the code internal/exitcode
+// assigns to its outcome.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	applyWorkspaceAliases()
+	registerFlagCompletions(rootCmd)
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && !exitcode.Silent(err) {
+		theme.Fprintln(os.Stderr, err)
+	}
+	if jsonMode && cmd != nil {
+		writeResult(os.Stdout, cmd, err)
+	}
+	if err != nil {
+		os.Exit(exitcode.Of(err))
 	}
 }
 
 func init() {
 	rootCmd.Version = Version
 	loadEnvironmentConfig()
+	// Apply $GA4_THEME now so the credential warnings below are themed too;
+	// an invalid value is reported once flags are parsed (see applyTheme).
+	_ = theme.Set(theme.Resolve(""))
+	if err := activateEnvProfile(); err != nil {
+		theme.Fprintln(os.Stderr, "⚠️  "+err.Error())
+	}
 	validateCredentials()
 
+	// $GA4_CONFIG_DIR, which .env may set, is where the first run lays out
+	// the config directory; --config-dir only takes effect once flags are
+	// parsed (see applyConfigDir).
+	config.SetConfigDir(os.Getenv(config.ConfigDirEnvVar))
+
 	// Ensure config directory exists for all commands
 	// This is especially important for users who download the binary
 	// without cloning the repository
@@ -69,32 +94,47 @@ func validateCredentials() {
 	credsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
 
-	// Check if GOOGLE_APPLICATION_CREDENTIALS is set and not empty
+	// Check if GOOGLE_APPLICATION_CREDENTIALS is set and not empty; a
+	// cached `ga4 auth login` stands in for it, and so may a profile chosen
+	// with --profile
 	if credsPath == "" {
-		fmt.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS not set")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   To use GA4 Manager, set your Google Cloud credentials:")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   Option 1: Add to your shell config (recommended)")
-		fmt.Fprintln(os.Stderr, "   -------------------------------------------------")
-		fmt.Fprintln(os.Stderr, "   # For Bash (~/.bashrc or ~/.bash_profile)")
-		fmt.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
-		fmt.Fprintln(os.Stderr, "   export GOOGLE_CLOUD_PROJECT=\"your-project-id\"")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   # For Zsh (~/.zshrc)")
-		fmt.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
-		fmt.Fprintln(os.Stderr, "   export GOOGLE_CLOUD_PROJECT=\"your-project-id\"")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   # For Fish (~/.config/fish/config.fish)")
-		fmt.Fprintln(os.Stderr, "   set -gx GOOGLE_APPLICATION_CREDENTIALS /path/to/credentials.json")
-		fmt.Fprintln(os.Stderr, "   set -gx GOOGLE_CLOUD_PROJECT your-project-id")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   Option 2: Set for current session only")
-		fmt.Fprintln(os.Stderr, "   ----------------------------------------")
-		fmt.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
-		fmt.Fprintln(os.Stderr, "   ga4 report --config configs/my-project.yaml")
-		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintln(os.Stderr, "   📖 Full setup guide: https://github.com/garbarok/ga4-manager#installation")
+		if creds, err := auth.Resolve(); err == nil && creds.Kind == auth.SourceUser {
+			return
+		}
+		if profiles, err := auth.LoadProfiles(); err == nil && len(profiles.Profiles) > 0 {
+			return
+		}
+		theme.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS not set")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   To use GA4 Manager, set your Google Cloud credentials:")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   Option 1: Add to your shell config (recommended)")
+		theme.Fprintln(os.Stderr, "   -------------------------------------------------")
+		theme.Fprintln(os.Stderr, "   # For Bash (~/.bashrc or ~/.bash_profile)")
+		theme.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
+		theme.Fprintln(os.Stderr, "   export GOOGLE_CLOUD_PROJECT=\"your-project-id\"")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   # For Zsh (~/.zshrc)")
+		theme.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
+		theme.Fprintln(os.Stderr, "   export GOOGLE_CLOUD_PROJECT=\"your-project-id\"")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   # For Fish (~/.config/fish/config.fish)")
+		theme.Fprintln(os.Stderr, "   set -gx GOOGLE_APPLICATION_CREDENTIALS /path/to/credentials.json")
+		theme.Fprintln(os.Stderr, "   set -gx GOOGLE_CLOUD_PROJECT your-project-id")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   Option 2: Set for current session only")
+		theme.Fprintln(os.Stderr, "   ----------------------------------------")
+		theme.Fprintln(os.Stderr, "   export GOOGLE_APPLICATION_CREDENTIALS=\"/path/to/credentials.json\"")
+		theme.Fprintln(os.Stderr, "   ga4 report --config configs/my-project.yaml")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   Option 3: Sign in with your Google account instead")
+		theme.Fprintln(os.Stderr, "   ------------------------------------------------")
+		theme.Fprintln(os.Stderr, "   ga4 auth login --client-secret client_secret.json")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   Working across several Google accounts? Save each as a profile:")
+		theme.Fprintln(os.Stderr, "   ga4 auth profiles add client-a --service-account /path/to/client-a.json")
+		theme.Fprintln(os.Stderr, "")
+		theme.Fprintln(os.Stderr, "   📖 Full setup guide: https://github.com/garbarok/ga4-manager#installation")
 		return
 	}
 
@@ -106,17 +146,17 @@ func validateCredentials() {
 		"path/to/credentials",
 	}
 	if slices.Contains(placeholders, credsPath) {
-		fmt.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS contains a placeholder value")
-		fmt.Fprintf(os.Stderr, "   Found: %s\n", credsPath)
-		fmt.Fprintln(os.Stderr, "   Please set the actual path to your credentials file")
+		theme.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS contains a placeholder value")
+		theme.Fprintf(os.Stderr, "   Found: %s\n", credsPath)
+		theme.Fprintln(os.Stderr, "   Please set the actual path to your credentials file")
 		return
 	}
 
 	// Check if the credentials file exists
 	if _, err := os.Stat(credsPath); os.IsNotExist(err) {
-		fmt.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS file does not exist")
-		fmt.Fprintf(os.Stderr, "   Path: %s\n", credsPath)
-		fmt.Fprintln(os.Stderr, "   Please verify the path to your credentials file")
+		theme.Fprintln(os.Stderr, "⚠️  GOOGLE_APPLICATION_CREDENTIALS file does not exist")
+		theme.Fprintf(os.Stderr, "   Path: %s\n", credsPath)
+		theme.Fprintln(os.Stderr, "   Please verify the path to your credentials file")
 	}
 
 	// Validate project ID is not a placeholder
@@ -127,9 +167,9 @@ func validateCredentials() {
 			"project-id",
 		}
 		if slices.Contains(projectPlaceholders, projectID) {
-			fmt.Fprintln(os.Stderr, "⚠️  GOOGLE_CLOUD_PROJECT contains a placeholder value")
-			fmt.Fprintf(os.Stderr, "   Found: %s\n", projectID)
-			fmt.Fprintln(os.Stderr, "   Please set your actual GCP project ID")
+			theme.Fprintln(os.Stderr, "⚠️  GOOGLE_CLOUD_PROJECT contains a placeholder value")
+			theme.Fprintf(os.Stderr, "   Found: %s\n", projectID)
+			theme.Fprintln(os.Stderr, "   Please set your actual GCP project ID")
 		}
 	}
 }
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"gopkg.in/yaml.v3"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	sandboxFrom    string
+	sandboxOutput  string
+	sandboxAccount string
+	sandboxAll     bool
+	sandboxYes     bool
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:   "sandbox",
+	Short: "Rehearse config changes on a throwaway GA4 property",
+	Long: `Create a throwaway GA4 property from a config, apply the config to it, and
+delete it when done, so risky changes can be rehearsed end-to-end without
+touching the real property.
+
+Sandbox properties are created in the same account as the config's property.
+Their display name starts with "` + ga4.SandboxLabel + `", which is how
+` + "`ga4 sandbox destroy`" + ` tells them from real properties: it refuses anything else.
+The credential needs Editor access on the account to create properties.`,
+}
+
+var sandboxCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a sandbox property and apply a config to it",
+	Long: `Create a sandbox property with the time zone, currency and web stream of the
+config's property, then run setup against it with the config's conversions,
+dimensions, metrics, calculated metrics and channel groups.
+
+The Search Console and Tag Manager blocks are dropped: sitemaps would be
+submitted for the real site. setup.require_signed_plan is dropped too, since
+the sandbox is not the property the plan gate protects.
+
+--output writes the config retargeted at the sandbox, so plan, setup and
+cleanup can be run against it again. The property is kept when setup fails,
+for inspection.
+
+Examples:
+  ga4 sandbox create --from configs/prod.yaml
+  ga4 sandbox create --from configs/prod.yaml --output configs/prod-sandbox.yaml`,
+	RunE: runSandboxCreate,
+}
+
+var sandboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sandbox properties in an account",
+	Long: `List the sandbox properties in the account given with --account, or in the
+account of the --from config's property.
+
+Examples:
+  ga4 sandbox list --from configs/prod.yaml
+  ga4 sandbox list --account 123456`,
+	RunE: runSandboxList,
+}
+
+var sandboxDestroyCmd = &cobra.Command{
+	Use:   "destroy [PROPERTY_ID...]",
+	Short: "Delete sandbox properties",
+	Long: `Delete sandbox properties by ID, or every sandbox in an account with --all.
+Properties without the sandbox label are refused. Deleted properties go to the
+GA4 trash and can be restored there for 35 days.
+
+Examples:
+  ga4 sandbox destroy 412345678
+  ga4 sandbox destroy --all --from configs/prod.yaml --yes`,
+	RunE: runSandboxDestroy,
+}
+
+func init() {
+	rootCmd.AddCommand(sandboxCmd)
+	sandboxCmd.AddCommand(sandboxCreateCmd, sandboxListCmd, sandboxDestroyCmd)
+
+	sandboxCreateCmd.Flags().StringVar(&sandboxFrom, "from", "", "Config to clone and apply (required)")
+	sandboxCreateCmd.Flags().StringVarP(&sandboxOutput, "output", "o", "", "Write the config retargeted at the sandbox to this file")
+	_ = sandboxCreateCmd.MarkFlagRequired("from")
+
+	for _, c := range []*cobra.Command{sandboxListCmd, sandboxDestroyCmd} {
+		c.Flags().StringVar(&sandboxFrom, "from", "", "Use the account of this config's property")
+		c.Flags().StringVar(&sandboxAccount, "account", "", "GA4 account ID")
+	}
+	sandboxDestroyCmd.Flags().BoolVar(&sandboxAll, "all", false, "Delete every sandbox in the account")
+	sandboxDestroyCmd.Flags().BoolVarP(&sandboxYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+func runSandboxCreate(cmd *cobra.Command, args []string) error {
+	configs, paths, err := loadProjectConfigs(sandboxFrom, "", false)
+	if err != nil {
+		return err
+	}
+	// A multi-property config is rehearsed on a copy of its first property.
+	configs, paths = configs[:1], paths[:1]
+	cfg := configs[0]
+	if !cfg.HasAnalytics() {
+		return fmt.Errorf("config %q has no analytics section", cfg.Project.Name)
+	}
+
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return err
+	}
+	sandbox, err := client.CreateSandbox(cfg.GetPropertyID(), cfg.Project.Name)
+	client.Close()
+	if err != nil {
+		if sandbox != nil {
+			theme.Yellow("⚠ Sandbox property %s was created; remove it with: ga4 sandbox destroy %s", sandbox.PropertyID, sandbox.PropertyID)
+		}
+		return err
+	}
+	theme.Green("✓ Created sandbox property %s (%s)", sandbox.PropertyID, sandbox.DisplayName)
+	if sandbox.MeasurementID != "" {
+		theme.Printf("  Web stream %s, measurement ID %s\n", sandbox.DataStreamID, sandbox.MeasurementID)
+	}
+
+	retargetSandboxConfig(cfg, sandbox)
+	if sandboxOutput != "" {
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("encode sandbox config: %w", err)
+		}
+		if err := os.WriteFile(sandboxOutput, data, 0o644); err != nil {
+			return fmt.Errorf("write sandbox config: %w", err)
+		}
+		theme.Printf("  Sandbox config written to %s\n", sandboxOutput)
+		paths[0] = sandboxOutput
+	}
+	theme.Println()
+
+	if err := runSetupConfigs(configs, paths, false); err != nil {
+		theme.Yellow("\n⚠ Setup failed; the sandbox is kept for inspection. Remove it with: ga4 sandbox destroy %s", sandbox.PropertyID)
+		return err
+	}
+	theme.Printf("\nRemove the sandbox when done: ga4 sandbox destroy %s\n", sandbox.PropertyID)
+	return nil
+}
+
+// retargetSandboxConfig points cfg at the sandbox and drops the parts that
+// must not run against it.
+func retargetSandboxConfig(cfg *config.ProjectConfig, sandbox *ga4.Sandbox) {
+	analytics := cfg.Analytics
+	if analytics == nil {
+		analytics = &cfg.GA4
+	}
+	analytics.PropertyID = sandbox.PropertyID
+	analytics.MeasurementID = sandbox.MeasurementID
+	analytics.DataStreamID = sandbox.DataStreamID
+
+	cfg.Project.Name += " (sandbox)"
+	cfg.SearchConsole = nil
+	cfg.TagManager = nil
+	if cfg.Setup != nil {
+		cfg.Setup.RequireSignedPlan = false
+	}
+}
+
+func runSandboxList(cmd *cobra.Command, args []string) error {
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	account, err := sandboxAccountID(client)
+	if err != nil {
+		return err
+	}
+	sandboxes, err := client.ListSandboxes(account)
+	if err != nil {
+		return err
+	}
+	if len(sandboxes) == 0 {
+		theme.Green("✓ No sandbox properties in %s", account)
+		return nil
+	}
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Property", "Display Name", "Created"},
+		sandboxes, func(p *admin.GoogleAnalyticsAdminV1alphaProperty) []string {
+			return []string{strings.TrimPrefix(p.Name, "properties/"), p.DisplayName, p.CreateTime}
+		})
+}
+
+func runSandboxDestroy(cmd *cobra.Command, args []string) error {
+	if sandboxAll == (len(args) > 0) {
+		return fmt.Errorf("give the property IDs to delete, or --all")
+	}
+
+	client, err := newGA4Client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ids := args
+	if sandboxAll {
+		account, err := sandboxAccountID(client)
+		if err != nil {
+			return err
+		}
+		sandboxes, err := client.ListSandboxes(account)
+		if err != nil {
+			return err
+		}
+		if len(sandboxes) == 0 {
+			theme.Green("✓ No sandbox properties in %s", account)
+			return nil
+		}
+		for _, p := range sandboxes {
+			ids = append(ids, strings.TrimPrefix(p.Name, "properties/"))
+			theme.Printf("  %s  %s\n", strings.TrimPrefix(p.Name, "properties/"), p.DisplayName)
+		}
+	}
+	if !sandboxYes && !confirmSandboxDestroy(len(ids)) {
+		theme.Println("Cancelled.")
+		return nil
+	}
+
+	var failed int
+	for _, id := range ids {
+		if err := client.DeleteSandbox(id); err != nil {
+			theme.Red("✗ %s: %v", id, err)
+			failed++
+			continue
+		}
+		theme.Green("✓ Deleted sandbox property %s", id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sandbox properties could not be deleted", failed, len(ids))
+	}
+	return nil
+}
+
+// sandboxAccountID resolves --account, or the account of the --from
+// config's property.
+func sandboxAccountID(client *ga4.Client) (string, error) {
+	if sandboxAccount != "" {
+		return sandboxAccount, nil
+	}
+	if sandboxFrom == "" {
+		return "", fmt.Errorf("--account or --from is required")
+	}
+	cfg, err := config.LoadConfig(sandboxFrom)
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	return client.PropertyAccount(cfg.GetPropertyID())
+}
+
+func confirmSandboxDestroy(n int) bool {
+	theme.Printf("\nDelete %d sandbox properties? [y/N]: ", n)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var seoCmd = &cobra.Command{
+	Use:   "seo",
+	Short: "On-site SEO checks",
+	Long: `Check what a crawler sees when it fetches the site itself: robots.txt rules,
+internal links, hreflang annotations, on-page signals and page speed. logs
+works the other way round and reads what Googlebot fetched from the server's
+access logs.
+
+These commands fetch the site over plain HTTP; pagespeed calls the public
+PageSpeed Insights API. Only the comparisons against Search Console data need
+GOOGLE_APPLICATION_CREDENTIALS.`,
+}
+
+func init() {
+	rootCmd.AddCommand(seoCmd)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/seo"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	seoAuditURLs      []string
+	seoAuditUserAgent string
+	seoAuditFormat    string
+)
+
+// errSEOAuditIssues signals that at least one audited page has an
+// error-severity issue; `seo audit` exits with diagcmd.ExitIssues.
+var errSEOAuditIssues = errors.New("on-page audit found errors")
+
+var seoAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit on-page SEO signals of one or more URLs",
+	Long: `Fetch each URL and check the HTML a crawler sees:
+
+  - redirects: followed by hand (up to 5 hops, HTTP and meta refresh) and
+    flagged for loops, chains over 3 hops, cross-domain hops, and 302/307
+    redirects that only normalise the URL (https, www, trailing slash)
+  - title and meta description: present, single, and a sensible length
+  - canonical: exactly one absolute rel=canonical
+  - h1: exactly one non-empty heading
+  - robots: noindex / nofollow in meta robots or the X-Robots-Tag header
+  - hreflang: valid codes, absolute hrefs, a self-reference and x-default
+  - Open Graph: og:title, og:description and an absolute og:image
+  - images: alt attribute coverage (alt="" counts as decorative)
+
+Each finding is an issue with a rule name and a severity (error, warning,
+info).
+
+Exits 4 when any page has an error-severity issue.
+
+Examples:
+  ga4 seo audit --url https://example.com/
+  ga4 seo audit --url https://example.com/ --url https://example.com/pricing --format markdown
+  ga4 seo audit --url https://example.com/ --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runSEOAudit(cmd, args)
+		if errors.Is(err, errSEOAuditIssues) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	seoCmd.AddCommand(seoAuditCmd)
+	seoAuditCmd.Flags().StringArrayVarP(&seoAuditURLs, "url", "u", nil, "URL to audit (repeatable, required)")
+	seoAuditCmd.Flags().StringVar(&seoAuditUserAgent, "user-agent", "", "User-Agent header to send (default: Go's HTTP client)")
+	output.FormatVar(seoAuditCmd.Flags(), &seoAuditFormat, "f", output.FormatTable, output.FormatJSON, output.FormatMarkdown)
+	_ = seoAuditCmd.MarkFlagRequired("url")
+}
+
+func runSEOAudit(cmd *cobra.Command, args []string) error {
+	auditor := seo.NewPageAuditor(30*time.Second, seoAuditUserAgent)
+	pages := make([]*seo.Page, 0, len(seoAuditURLs))
+	for _, u := range seoAuditURLs {
+		page, err := auditor.Audit(context.Background(), u)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, page)
+	}
+
+	switch seoAuditFormat {
+	case "json":
+		if err := output.JSON(os.Stdout, pages); err != nil {
+			return err
+		}
+	case "markdown":
+		if err := displaySEOAuditMarkdown(pages); err != nil {
+			return err
+		}
+	default:
+		if err := displaySEOAudit(pages); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range pages {
+		if p.Errors() > 0 {
+			return errSEOAuditIssues
+		}
+	}
+	return nil
+}
+
+var seoAuditColumns = []string{"Severity", "Rule", "Message"}
+
+func displaySEOAudit(pages []*seo.Page) error {
+	for i, p := range pages {
+		if i > 0 {
+			theme.Println()
+		}
+		theme.Cyan("═══ %s ═══", p.URL)
+		theme.Printf("HTTP %d", p.Status)
+		if p.FinalURL != "" {
+			theme.Printf(" after redirect to %s", p.FinalURL)
+		}
+		theme.Println()
+		if p.Redirects != nil {
+			for _, h := range p.Redirects.Hops {
+				theme.HiBlack("  %s", formatHop(h))
+			}
+		}
+		if len(p.Titles) > 0 {
+			theme.Printf("Title: %s\n", p.Titles[0])
+		}
+		theme.Println()
+
+		if len(p.Issues) == 0 {
+			theme.Green("✓ No issues.")
+			continue
+		}
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, seoAuditColumns,
+			p.Issues, func(issue seo.Issue) []string {
+				return []string{severityLabel(issue.Severity), issue.Rule, issue.Message}
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func displaySEOAuditMarkdown(pages []*seo.Page) error {
+	theme.Println("# On-page SEO Audit")
+	for _, p := range pages {
+		theme.Println()
+		theme.Printf("## %s\n", p.URL)
+		theme.Println()
+		theme.Printf("**Status:** %d  \n", p.Status)
+		if p.FinalURL != "" {
+			theme.Printf("**Final URL:** %s  \n", p.FinalURL)
+		}
+		theme.Println()
+		if p.Redirects != nil {
+			for i, h := range p.Redirects.Hops {
+				theme.Printf("%d. %s\n", i+1, formatHop(h))
+			}
+			theme.Println()
+		}
+		if len(p.Issues) == 0 {
+			theme.Println("*No issues found*")
+			continue
+		}
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatMarkdown, seoAuditColumns,
+			p.Issues, func(issue seo.Issue) []string {
+				return []string{issue.Severity, issue.Rule, issue.Message}
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatHop renders one redirect hop as "301 from → to".
+func formatHop(h seo.Hop) string {
+	kind := fmt.Sprintf("%d", h.Status)
+	if h.MetaRefresh {
+		kind = "meta refresh"
+	}
+	return fmt.Sprintf("%s %s → %s", kind, h.URL, h.Location)
+}
+
+// severityLabel colours an issue severity for the terminal table.
+func severityLabel(severity string) string {
+	switch severity {
+	case seo.SeverityError:
+		return theme.RedString("%s", strings.ToUpper(severity))
+	case seo.SeverityWarning:
+		return theme.YellowString("%s", strings.ToUpper(severity))
+	default:
+		return theme.HiBlackString("%s", strings.ToUpper(severity))
+	}
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/seo"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	seoCrawlStart        string
+	seoCrawlMaxPages     int
+	seoCrawlConcurrency  int
+	seoCrawlDeep         int
+	seoCrawlSitemaps     []string
+	seoCrawlSite         string
+	seoCrawlDays         int
+	seoCrawlUserAgent    string
+	seoCrawlIgnoreRobots bool
+	seoCrawlFormat       string
+)
+
+// errSEOCrawlBroken signals broken internal links; `seo crawl` exits with
+// diagcmd.ExitIssues.
+var errSEOCrawlBroken = errors.New("crawl found broken internal links")
+
+var seoCrawlCmd = &cobra.Command{
+	Use:   "crawl",
+	Short: "Crawl internal links to find orphan, deep and broken pages",
+	Long: `Crawl the start page's origin breadth first through <a href> links and
+redirects, build the internal link graph, and report:
+
+  - broken internal links: URLs that fail or return 4xx/5xx, with the pages
+    linking to them
+  - deep pages: more than --deep clicks from the start page
+  - orphan pages: URLs in the sitemaps (or with Search Console impressions
+    when --site is set) that no crawled page links to
+
+Sitemaps come from --sitemap, else the Sitemap lines of robots.txt. With
+--site, orphans are ranked by impressions over --days so the pages search
+already values are fixed first; this needs GOOGLE_APPLICATION_CREDENTIALS.
+
+URLs robots.txt disallows for Googlebot are not fetched (--ignore-robots to
+crawl them anyway). rel=nofollow links are not followed. The crawl stops
+after --max-pages URLs; orphans are then only candidates.
+
+Exits 4 when an internal link is broken.
+
+Examples:
+  ga4 seo crawl --start https://example.com/
+  ga4 seo crawl --start https://example.com/ --max-pages 2000 --site sc-domain:example.com
+  ga4 seo crawl --start https://example.com/ --sitemap https://example.com/sitemap.xml --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runSEOCrawl(cmd, args)
+		if errors.Is(err, errSEOCrawlBroken) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	seoCmd.AddCommand(seoCrawlCmd)
+	seoCrawlCmd.Flags().StringVar(&seoCrawlStart, "start", "", "Start URL; only its origin is crawled (required)")
+	seoCrawlCmd.Flags().IntVar(&seoCrawlMaxPages, "max-pages", 500, "Stop after this many URLs")
+	seoCrawlCmd.Flags().IntVar(&seoCrawlConcurrency, "concurrency", 4, "Requests in flight")
+	seoCrawlCmd.Flags().IntVar(&seoCrawlDeep, "deep", seo.DeepPageDepth, "Report pages more than this many clicks deep")
+	seoCrawlCmd.Flags().StringArrayVarP(&seoCrawlSitemaps, "sitemap", "u", nil, "Sitemap listing the pages that should be linked (repeatable)")
+	seoCrawlCmd.Flags().StringVarP(&seoCrawlSite, "site", "s", "", "Search Console property to rank orphans by impressions")
+	seoCrawlCmd.Flags().IntVarP(&seoCrawlDays, "days", "d", 28, "Search Analytics window in days (with --site)")
+	seoCrawlCmd.Flags().StringVar(&seoCrawlUserAgent, "user-agent", "", "User-Agent header to send (default: Go's HTTP client)")
+	seoCrawlCmd.Flags().BoolVar(&seoCrawlIgnoreRobots, "ignore-robots", false, "Crawl URLs robots.txt disallows")
+	output.FormatVar(seoCrawlCmd.Flags(), &seoCrawlFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = seoCrawlCmd.MarkFlagRequired("start")
+}
+
+// orphanPage is a known URL no crawled page links to.
+type orphanPage struct {
+	URL         string `json:"url"`
+	InSitemap   bool   `json:"in_sitemap"`
+	Clicks      int64  `json:"clicks"`
+	Impressions int64  `json:"impressions"`
+}
+
+// crawlResult is the `seo crawl` output.
+type crawlResult struct {
+	*seo.LinkGraph
+	Sitemaps      []string          `json:"sitemaps"`
+	SitemapErrors []string          `json:"sitemap_errors,omitempty"`
+	GSCError      string            `json:"gsc_error,omitempty"`
+	Broken        []seo.BrokenLink  `json:"broken"`
+	Deep          []seo.CrawledPage `json:"deep"`
+	Orphans       []orphanPage      `json:"orphans"`
+}
+
+func runSEOCrawl(cmd *cobra.Command, args []string) error {
+	if seoCrawlMaxPages < 1 {
+		return fmt.Errorf("--max-pages must be at least 1, got %d", seoCrawlMaxPages)
+	}
+	if seoCrawlSite != "" {
+		if err := gsc.ValidateCoverageParams(seoCrawlSite, seoCrawlDays, "all"); err != nil {
+			return err
+		}
+	}
+	start, err := url.Parse(seoCrawlStart)
+	if err != nil || (start.Scheme != "http" && start.Scheme != "https") || start.Host == "" {
+		return fmt.Errorf("invalid --start %q: want an absolute URL such as https://example.com/", seoCrawlStart)
+	}
+	origin := start.Scheme + "://" + start.Host
+	progress := func(format string, args ...any) {
+		if seoCrawlFormat == "table" {
+			theme.Fprintf(os.Stderr, format, args...)
+		}
+	}
+	ctx := context.Background()
+
+	robots, err := seo.NewRobotsFetcher(15*time.Second, seoCrawlUserAgent).Fetch(ctx, origin)
+	if err != nil {
+		return err
+	}
+	rules := robots
+	if seoCrawlIgnoreRobots {
+		rules = nil
+	}
+
+	progress("Crawling %s (up to %d pages)...\n", origin, seoCrawlMaxPages)
+	graph, err := seo.NewCrawler(30*time.Second, seoCrawlUserAgent, seoCrawlMaxPages, seoCrawlConcurrency).
+		Crawl(ctx, seoCrawlStart, rules, seo.DefaultCrawler)
+	if err != nil {
+		return err
+	}
+	result := crawlResult{LinkGraph: graph, Broken: graph.BrokenLinks(), Deep: graph.Deep(seoCrawlDeep)}
+
+	result.Sitemaps = seoCrawlSitemaps
+	if len(result.Sitemaps) == 0 {
+		result.Sitemaps = robots.Sitemaps
+	}
+	inSitemap := make(map[string]bool)
+	var known []string
+	validator := sitemap.NewValidator(30*time.Second, seoCrawlUserAgent)
+	for _, sm := range result.Sitemaps {
+		progress("Fetching %s...\n", sm)
+		report, err := validator.Validate(ctx, origin+"/", sm)
+		if err != nil {
+			result.SitemapErrors = append(result.SitemapErrors, err.Error())
+			continue
+		}
+		for _, u := range report.URLs {
+			inSitemap[u] = true
+			known = append(known, u)
+		}
+	}
+
+	traffic := make(map[string]sitemap.Traffic)
+	if seoCrawlSite != "" {
+		progress("Fetching page data for %d days...\n", seoCrawlDays)
+		if err := crawlPageTraffic(traffic); err != nil {
+			result.GSCError = err.Error()
+		}
+		for u := range traffic {
+			known = append(known, u)
+		}
+	}
+	result.Orphans = rankOrphans(graph.Orphans(known), inSitemap, traffic)
+
+	if seoCrawlFormat == "json" {
+		if err := output.JSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else if err := displaySEOCrawl(result); err != nil {
+		return err
+	}
+
+	if len(result.Broken) > 0 {
+		return errSEOCrawlBroken
+	}
+	return nil
+}
+
+// crawlPageTraffic fills traffic with the --site pages that had impressions.
+func crawlPageTraffic(traffic map[string]sitemap.Traffic) error {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	start, end := gsc.BuildDateRange(seoCrawlDays)
+	report, err := client.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+		SiteURL:    seoCrawlSite,
+		StartDate:  start,
+		EndDate:    end,
+		Dimensions: []string{"page"},
+		RowLimit:   gsc.MaxRowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch page data: %w", err)
+	}
+	for _, row := range report.Rows {
+		if len(row.Keys) > 0 && row.Impressions > 0 {
+			traffic[row.Keys[0]] = sitemap.Traffic{Clicks: row.Clicks, Impressions: row.Impressions}
+		}
+	}
+	return nil
+}
+
+// rankOrphans attaches sitemap membership and traffic to each orphan and
+// sorts by impressions, then clicks, then URL.
+func rankOrphans(urls []string, inSitemap map[string]bool, traffic map[string]sitemap.Traffic) []orphanPage {
+	out := make([]orphanPage, 0, len(urls))
+	for _, u := range urls {
+		t := traffic[u]
+		out = append(out, orphanPage{URL: u, InSitemap: inSitemap[u], Clicks: t.Clicks, Impressions: t.Impressions})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Impressions != out[j].Impressions {
+			return out[i].Impressions > out[j].Impressions
+		}
+		if out[i].Clicks != out[j].Clicks {
+			return out[i].Clicks > out[j].Clicks
+		}
+		return out[i].URL < out[j].URL
+	})
+	return out
+}
+
+func displaySEOCrawl(r crawlResult) error {
+	theme.Cyan("═══ Crawl: %s ═══", r.Start)
+	theme.Printf("Pages: %d, broken: %d, deeper than %d clicks: %d, orphans: %d\n",
+		len(r.Pages), len(r.Broken), seoCrawlDeep, len(r.Deep), len(r.Orphans))
+
+	if len(r.Broken) > 0 {
+		theme.Println()
+		theme.Cyan("Broken internal links:")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"URL", "Status", "Linked From"},
+			r.Broken, func(b seo.BrokenLink) []string {
+				status := b.Error
+				if b.Status != 0 {
+					status = fmt.Sprintf("%d", b.Status)
+				}
+				from := fmt.Sprintf("%d pages", len(b.Sources))
+				if len(b.Sources) == 1 {
+					from = b.Sources[0]
+				}
+				return []string{b.URL, theme.RedString("%s", status), from}
+			}); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Deep) > 0 {
+		theme.Println()
+		theme.Cyan("Deep pages:")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"URL", "Depth", "Inlinks"},
+			r.Deep, func(p seo.CrawledPage) []string {
+				return []string{p.URL, fmt.Sprintf("%d", p.Depth), fmt.Sprintf("%d", p.Inlinks)}
+			}); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Orphans) > 0 {
+		theme.Println()
+		theme.Cyan("Orphan pages (no internal links found):")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"URL", "In Sitemap", "Impressions", "Clicks"},
+			r.Orphans, func(o orphanPage) []string {
+				inSitemap := "no"
+				if o.InSitemap {
+					inSitemap = "yes"
+				}
+				return []string{o.URL, inSitemap, fmt.Sprintf("%d", o.Impressions), fmt.Sprintf("%d", o.Clicks)}
+			}); err != nil {
+			return err
+		}
+	}
+
+	theme.Println()
+	for _, e := range r.SitemapErrors {
+		theme.Red("✗ %s", e)
+	}
+	if r.GSCError != "" {
+		theme.Yellow("⚠ Search Console: %s", r.GSCError)
+	}
+	if r.Truncated {
+		theme.Yellow("⚠ Stopped at --max-pages %d; some orphans may be linked from pages not crawled.", seoCrawlMaxPages)
+	}
+	if len(r.Sitemaps) == 0 && r.GSCError == "" && seoCrawlSite == "" {
+		theme.HiBlack("ℹ No sitemaps found; pass --sitemap or --site to detect orphan pages.")
+	}
+	if len(r.Broken) == 0 {
+		theme.Green("✓ No broken internal links.")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+)
+
+func TestRankOrphans(t *testing.T) {
+	got := rankOrphans(
+		[]string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		map[string]bool{"https://example.com/a": true, "https://example.com/c": true},
+		map[string]sitemap.Traffic{
+			"https://example.com/b": {Clicks: 2, Impressions: 300},
+			"https://example.com/c": {Impressions: 300},
+		},
+	)
+	assert.Equal(t, []orphanPage{
+		{URL: "https://example.com/b", Clicks: 2, Impressions: 300},
+		{URL: "https://example.com/c", InSitemap: true, Impressions: 300},
+		{URL: "https://example.com/a", InSitemap: true},
+	}, got)
+}
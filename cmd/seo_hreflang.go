@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/seo"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	seoHreflangSitemaps    []string
+	seoHreflangMaxPages    int
+	seoHreflangConcurrency int
+	seoHreflangUserAgent   string
+	seoHreflangFormat      string
+)
+
+// errSEOHreflangErrors signals error-severity hreflang findings; `seo
+// hreflang` exits with diagcmd.ExitIssues.
+var errSEOHreflangErrors = errors.New("hreflang errors found")
+
+var seoHreflangCmd = &cobra.Command{
+	Use:   "hreflang",
+	Short: "Check hreflang annotations are reciprocal across the site",
+	Long: `Fetch every page in the sitemaps, then every alternate those pages declare
+with <link rel="alternate" hreflang>, and check the annotations against each
+other:
+
+  - every alternate answers 200 without redirecting
+  - every alternate links back with a return tag (Google ignores one-way
+    annotations)
+  - every annotated page declares an x-default
+  - the hreflang code agrees with the target's <html lang> and with a
+    language folder in its URL (/es/, /en-gb/)
+
+Exits 4 when a target is broken or a return tag is missing.
+
+Examples:
+  ga4 seo hreflang --sitemap https://example.com/sitemap.xml
+  ga4 seo hreflang --sitemap https://example.com/sitemap-es.xml --sitemap https://example.com/sitemap-en.xml --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runSEOHreflang(cmd, args)
+		if errors.Is(err, errSEOHreflangErrors) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	seoCmd.AddCommand(seoHreflangCmd)
+	seoHreflangCmd.Flags().StringArrayVarP(&seoHreflangSitemaps, "sitemap", "u", nil, "Sitemap listing the pages to check (repeatable, required)")
+	seoHreflangCmd.Flags().IntVar(&seoHreflangMaxPages, "max-pages", 500, "Stop after fetching this many pages")
+	seoHreflangCmd.Flags().IntVar(&seoHreflangConcurrency, "concurrency", 4, "Requests in flight")
+	seoHreflangCmd.Flags().StringVar(&seoHreflangUserAgent, "user-agent", "", "User-Agent header to send (default: Go's HTTP client)")
+	output.FormatVar(seoHreflangCmd.Flags(), &seoHreflangFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = seoHreflangCmd.MarkFlagRequired("sitemap")
+}
+
+// hreflangResult is the `seo hreflang` output.
+type hreflangResult struct {
+	Sitemaps      []string              `json:"sitemaps"`
+	SitemapErrors []string              `json:"sitemap_errors,omitempty"`
+	Pages         int                   `json:"pages"`
+	Annotated     int                   `json:"annotated"`
+	Truncated     bool                  `json:"truncated,omitempty"`
+	Findings      []seo.HreflangFinding `json:"findings"`
+}
+
+func runSEOHreflang(cmd *cobra.Command, args []string) error {
+	if seoHreflangMaxPages < 1 {
+		return fmt.Errorf("--max-pages must be at least 1, got %d", seoHreflangMaxPages)
+	}
+	progress := func(format string, args ...any) {
+		if seoHreflangFormat == "table" {
+			theme.Fprintf(os.Stderr, format, args...)
+		}
+	}
+	ctx := context.Background()
+
+	result := hreflangResult{Sitemaps: seoHreflangSitemaps}
+	var urls []string
+	validator := sitemap.NewValidator(30*time.Second, seoHreflangUserAgent)
+	for _, sm := range seoHreflangSitemaps {
+		u, err := url.Parse(sm)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid --sitemap %q: want an absolute URL", sm)
+		}
+		progress("Fetching %s...\n", sm)
+		report, err := validator.Validate(ctx, u.Scheme+"://"+u.Host+"/", sm)
+		if err != nil {
+			result.SitemapErrors = append(result.SitemapErrors, err.Error())
+			continue
+		}
+		urls = append(urls, report.URLs...)
+	}
+
+	progress("Fetching %d pages and their alternates...\n", len(urls))
+	pages, truncated := seo.FetchHreflang(ctx, seo.NewPageAuditor(30*time.Second, seoHreflangUserAgent), urls, seoHreflangMaxPages, seoHreflangConcurrency)
+	result.Pages, result.Truncated = len(pages), truncated
+	for _, p := range pages {
+		if len(p.Alternates) > 0 {
+			result.Annotated++
+		}
+	}
+	result.Findings = seo.CheckHreflangSet(pages)
+	if result.Findings == nil {
+		result.Findings = []seo.HreflangFinding{}
+	}
+
+	if seoHreflangFormat == "json" {
+		if err := output.JSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else if err := displaySEOHreflang(result); err != nil {
+		return err
+	}
+
+	for _, f := range result.Findings {
+		if f.Severity == seo.SeverityError {
+			return errSEOHreflangErrors
+		}
+	}
+	return nil
+}
+
+func displaySEOHreflang(r hreflangResult) error {
+	theme.Cyan("═══ Hreflang ═══")
+	theme.Printf("Pages: %d, with hreflang: %d, findings: %d\n", r.Pages, r.Annotated, len(r.Findings))
+
+	if len(r.Findings) > 0 {
+		theme.Println()
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"URL", "Severity", "Rule", "Message"},
+			r.Findings, func(f seo.HreflangFinding) []string {
+				return []string{f.URL, severityLabel(f.Severity), f.Rule, f.Message}
+			}); err != nil {
+			return err
+		}
+	}
+
+	theme.Println()
+	for _, e := range r.SitemapErrors {
+		theme.Red("✗ %s", e)
+	}
+	if r.Truncated {
+		theme.Yellow("⚠ Stopped at --max-pages %d; annotations pointing at pages not fetched were not checked.", seoHreflangMaxPages)
+	}
+	switch {
+	case r.Annotated == 0:
+		theme.HiBlack("ℹ No page declares hreflang alternates.")
+	case len(r.Findings) == 0:
+		theme.Green("✓ All hreflang annotations are reciprocal and consistent.")
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/accesslog"
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	seoLogsFiles       []string
+	seoLogsFormat      string
+	seoLogsVerify      bool
+	seoLogsConcurrency int
+	seoLogsDepth       int
+	seoLogsTop         int
+	seoLogsOutput      string
+)
+
+var seoLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Analyse Googlebot crawling from server access logs",
+	Long: `Read web server access logs and report where Googlebot spends its crawl
+budget:
+
+  - crawl frequency per directory (--depth path segments), with the number of
+    distinct URLs, requests per day and the status mix of each
+  - the status code breakdown across all crawler requests
+  - wasted budget: requests for URLs that answered 404 or 410, and for URLs
+    with query parameters, with the URLs that cost the most
+
+--format is the log format: combined (Apache and nginx default) or common.
+In combined logs Googlebot is recognised by its user agent, which anyone can
+send. --verify also checks each client address the way Google documents it:
+reverse DNS must give a host under googlebot.com, google.com or
+googleusercontent.com, and that host must resolve back to the address.
+Requests that fail the check are reported as unverified and left out.
+Common logs have no user agent, so they need --verify, and every address in
+the log is looked up.
+
+Files ending in .gz are decompressed.
+
+Examples:
+  ga4 seo logs --file /var/log/nginx/access.log
+  ga4 seo logs --file access.log --format combined --verify --output json
+  ga4 seo logs --file access.log.1.gz --file access.log --depth 2 --output csv > crawl.csv`,
+	RunE: runSEOLogs,
+}
+
+func init() {
+	seoCmd.AddCommand(seoLogsCmd)
+	seoLogsCmd.Flags().StringArrayVar(&seoLogsFiles, "file", nil, "Access log file, .gz allowed (repeatable, required)")
+	seoLogsCmd.Flags().StringVar(&seoLogsFormat, "format", accesslog.FormatCombined, "Log format: combined or common")
+	seoLogsCmd.Flags().BoolVar(&seoLogsVerify, "verify", false, "Verify Googlebot addresses with reverse and forward DNS")
+	seoLogsCmd.Flags().IntVar(&seoLogsConcurrency, "concurrency", 8, "DNS lookups in flight with --verify")
+	seoLogsCmd.Flags().IntVar(&seoLogsDepth, "depth", sampling.DefaultDepth, "Path segments that form a directory (1: /blog/, 2: /blog/2024/)")
+	seoLogsCmd.Flags().IntVar(&seoLogsTop, "top", 20, "Wasted-budget URLs to list")
+	seoLogsCmd.Flags().StringVarP(&seoLogsOutput, "output", "o", "table", "Output format: table, json or csv")
+	_ = seoLogsCmd.MarkFlagRequired("file")
+}
+
+// seoLogsResult is the `seo logs` output.
+type seoLogsResult struct {
+	Files   []string `json:"files"`
+	Lines   int      `json:"lines"`
+	Skipped int      `json:"skipped"`
+	// Verified is set with --verify; Unverified then counts the requests
+	// left out and LookupFailures the addresses DNS could not answer for.
+	Verified       bool `json:"verified"`
+	Unverified     int  `json:"unverified"`
+	LookupFailures int  `json:"lookup_failures"`
+	accesslog.BudgetReport
+}
+
+func runSEOLogs(cmd *cobra.Command, args []string) error {
+	if !accesslog.ValidFormat(seoLogsFormat) {
+		return fmt.Errorf("invalid --format %q (want combined or common)", seoLogsFormat)
+	}
+	if seoLogsOutput != "table" && seoLogsOutput != "json" && seoLogsOutput != "csv" {
+		return fmt.Errorf("invalid --output %q (want table, json or csv)", seoLogsOutput)
+	}
+	if seoLogsFormat == accesslog.FormatCommon && !seoLogsVerify {
+		return fmt.Errorf("common logs have no user agent: pass --verify to find Googlebot by reverse DNS")
+	}
+
+	// In common logs every request is a candidate until DNS says otherwise.
+	candidate := func(e accesslog.Entry) bool {
+		return seoLogsFormat == accesslog.FormatCommon || accesslog.GooglebotType(e.UserAgent) != ""
+	}
+
+	res := seoLogsResult{Files: seoLogsFiles, Verified: seoLogsVerify}
+	var verified map[string]bool
+	if seoLogsVerify {
+		hosts := map[string]bool{}
+		for _, path := range seoLogsFiles {
+			if _, _, err := scanLogFile(path, seoLogsFormat, func(e accesslog.Entry) {
+				if candidate(e) {
+					hosts[e.Host] = true
+				}
+			}); err != nil {
+				return err
+			}
+		}
+		list := make([]string, 0, len(hosts))
+		for h := range hosts {
+			list = append(list, h)
+		}
+		sort.Strings(list)
+		theme.Fprintf(os.Stderr, "Verifying %d addresses with reverse DNS...\n", len(list))
+		verified, res.LookupFailures = accesslog.VerifyHosts(context.Background(), net.DefaultResolver, list, seoLogsConcurrency)
+	}
+
+	builder := accesslog.NewBudgetBuilder(seoLogsDepth)
+	for _, path := range seoLogsFiles {
+		lines, skipped, err := scanLogFile(path, seoLogsFormat, func(e accesslog.Entry) {
+			if !candidate(e) {
+				return
+			}
+			if seoLogsVerify && !verified[e.Host] {
+				res.Unverified++
+				return
+			}
+			builder.Add(e)
+		})
+		if err != nil {
+			return err
+		}
+		res.Lines += lines
+		res.Skipped += skipped
+	}
+	if res.Lines > 0 && res.Skipped == res.Lines {
+		return fmt.Errorf("no line of %s is in %s log format", strings.Join(seoLogsFiles, ", "), seoLogsFormat)
+	}
+	res.BudgetReport = builder.Report(seoLogsTop)
+
+	switch seoLogsOutput {
+	case "json":
+		return output.JSON(os.Stdout, res)
+	case "csv":
+		return output.Render(os.Stdout, output.FormatCSV, seoLogsDirectoryColumns(), res.Directories, seoLogsDirectoryRow)
+	}
+	return displaySEOLogs(res)
+}
+
+func seoLogsDirectoryColumns() []string {
+	return []string{"Directory", "Requests", "Share", "URLs", "Per Day", "2xx", "3xx", "4xx", "5xx", "Not Found", "Parameterized", "Last Crawl"}
+}
+
+func seoLogsDirectoryRow(d accesslog.Directory) []string {
+	return []string{
+		d.Directory,
+		fmt.Sprintf("%d", d.Requests),
+		fmt.Sprintf("%.1f%%", d.Share*100),
+		fmt.Sprintf("%d", d.URLs),
+		fmt.Sprintf("%.1f", d.PerDay),
+		fmt.Sprintf("%d", d.Success),
+		fmt.Sprintf("%d", d.Redirects),
+		fmt.Sprintf("%d", d.ClientErrors),
+		fmt.Sprintf("%d", d.ServerErrors),
+		fmt.Sprintf("%d", d.NotFound),
+		fmt.Sprintf("%d", d.Parameterized),
+		d.LastCrawl.Format("2006-01-02 15:04"),
+	}
+}
+
+func displaySEOLogs(r seoLogsResult) error {
+	theme.Cyan("═══ Googlebot in access logs ═══")
+	theme.Printf("Lines: %d, unparseable: %d, Googlebot requests: %d\n", r.Lines, r.Skipped, r.Requests)
+	if r.Verified {
+		theme.Printf("Unverified requests left out: %d\n", r.Unverified)
+		if r.LookupFailures > 0 {
+			theme.Yellow("⚠ DNS lookups failed for %d addresses; their requests count as unverified.", r.LookupFailures)
+		}
+	} else {
+		theme.HiBlack("ℹ Identified by user agent only; add --verify to rule out spoofed requests.")
+	}
+	if r.Requests == 0 {
+		return nil
+	}
+	theme.Printf("Period: %s to %s (%d days), %.0f requests per day\n",
+		r.From.Format("2006-01-02"), r.To.Format("2006-01-02"), r.Days, float64(r.Requests)/float64(r.Days))
+
+	theme.Println()
+	theme.Cyan("Crawl frequency by directory:")
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable, seoLogsDirectoryColumns(), r.Directories, seoLogsDirectoryRow); err != nil {
+		return err
+	}
+
+	theme.Println()
+	theme.Cyan("Status codes:")
+	if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"Status", "Requests", "Share"},
+		r.Statuses, func(c accesslog.Count) []string {
+			return []string{c.Key, fmt.Sprintf("%d", c.Requests), fmt.Sprintf("%.1f%%", c.Share*100)}
+		}); err != nil {
+		return err
+	}
+
+	theme.Println()
+	if r.WastedRequests == 0 {
+		theme.Green("✓ No crawl requests went to 404s or parameterized URLs.")
+		return nil
+	}
+	theme.Yellow("⚠ Wasted crawl budget: %d requests (%.1f%%)", r.WastedRequests, r.WastedShare*100)
+	for _, c := range r.Wasted {
+		theme.Printf("  %s: %d (%.1f%%)\n", c.Key, c.Requests, c.Share*100)
+	}
+	theme.Println()
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+		[]string{"URL", "Reason", "Requests", "Last Status"},
+		r.TopWasted, func(w accesslog.WastedURL) []string {
+			return []string{w.Path, w.Reason, fmt.Sprintf("%d", w.Requests), fmt.Sprintf("%d", w.LastStatus)}
+		})
+}
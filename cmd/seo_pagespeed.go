@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/pagespeed"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// pagespeedKeyEnv is read when --api-key is not given.
+const pagespeedKeyEnv = "GA4_PAGESPEED_API_KEY"
+
+// pagespeedRunTimeout bounds one PSI run; Lighthouse itself can take close
+// to a minute on a slow page.
+const pagespeedRunTimeout = 2 * time.Minute
+
+var (
+	seoPagespeedURLs          []string
+	seoPagespeedStrategy      string
+	seoPagespeedAPIKey        string
+	seoPagespeedRate          int
+	seoPagespeedOpportunities int
+	seoPagespeedFormat        string
+)
+
+var seoPagespeedCmd = &cobra.Command{
+	Use:   "pagespeed",
+	Short: "Run PageSpeed Insights lab audits with CrUX field data alongside",
+	Long: `Run each URL through PageSpeed Insights and print its Lighthouse scores
+(performance, SEO, accessibility, best practices), the top opportunities by
+estimated saving, and a lab vs field table of web vitals.
+
+Lab values come from one simulated Lighthouse load. Field values are the 75th
+percentile of real Chrome users over 28 days from the Chrome UX Report; when
+the URL has too little traffic, the origin's data is shown instead and marked
+as such. INP has no lab value (TBT is its lab proxy) and TBT has no field
+value.
+
+Without an API key PSI allows only a few requests. Set --api-key or
+` + pagespeedKeyEnv + ` to a key with the PageSpeed Insights API enabled.
+Requests are spaced to --rate per minute.
+
+Examples:
+  ga4 seo pagespeed --url https://example.com/
+  ga4 seo pagespeed --url https://example.com/ --strategy desktop
+  ga4 seo pagespeed --url https://example.com/ --url https://example.com/blog/ --format json`,
+	RunE: runSEOPagespeed,
+}
+
+func init() {
+	seoCmd.AddCommand(seoPagespeedCmd)
+	seoPagespeedCmd.Flags().StringArrayVarP(&seoPagespeedURLs, "url", "u", nil, "URL to test (repeatable, required)")
+	seoPagespeedCmd.Flags().StringVar(&seoPagespeedStrategy, "strategy", pagespeed.StrategyMobile, "Device to emulate: mobile or desktop")
+	seoPagespeedCmd.Flags().StringVar(&seoPagespeedAPIKey, "api-key", "", "PageSpeed Insights API key (default $"+pagespeedKeyEnv+")")
+	seoPagespeedCmd.Flags().IntVar(&seoPagespeedRate, "rate", pagespeed.DefaultRequestsPerMinute, "Maximum requests per minute")
+	seoPagespeedCmd.Flags().IntVar(&seoPagespeedOpportunities, "opportunities", 5, "Opportunities to list per URL (0 = all)")
+	output.FormatVar(seoPagespeedCmd.Flags(), &seoPagespeedFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = seoPagespeedCmd.MarkFlagRequired("url")
+}
+
+func runSEOPagespeed(cmd *cobra.Command, args []string) error {
+	if seoPagespeedStrategy != pagespeed.StrategyMobile && seoPagespeedStrategy != pagespeed.StrategyDesktop {
+		return fmt.Errorf("invalid --strategy %q (want mobile or desktop)", seoPagespeedStrategy)
+	}
+	if seoPagespeedRate < 1 {
+		return fmt.Errorf("--rate must be at least 1 request per minute, got %d", seoPagespeedRate)
+	}
+	key := seoPagespeedAPIKey
+	if key == "" {
+		key = os.Getenv(pagespeedKeyEnv)
+	}
+
+	client, err := pagespeed.NewClient(context.Background(), key, seoPagespeedRate)
+	if err != nil {
+		return err
+	}
+	results := make([]*pagespeed.Result, 0, len(seoPagespeedURLs))
+	for _, u := range seoPagespeedURLs {
+		if seoPagespeedFormat == "table" {
+			theme.Fprintf(os.Stderr, "Running Lighthouse (%s) on %s...\n", seoPagespeedStrategy, u)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), pagespeedRunTimeout)
+		r, err := client.Run(ctx, u, seoPagespeedStrategy)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if n := seoPagespeedOpportunities; n > 0 && len(r.Opportunities) > n {
+			r.Opportunities = r.Opportunities[:n]
+		}
+		results = append(results, r)
+	}
+
+	if seoPagespeedFormat == "json" {
+		return output.JSON(os.Stdout, results)
+	}
+	return displayPagespeed(results)
+}
+
+func displayPagespeed(results []*pagespeed.Result) error {
+	for i, r := range results {
+		if i > 0 {
+			theme.Println()
+		}
+		theme.Cyan("═══ %s (%s) ═══", r.URL, r.Strategy)
+		if r.FinalURL != "" && r.FinalURL != r.URL {
+			theme.HiBlack("Tested %s", r.FinalURL)
+		}
+		s := r.Scores
+		theme.Printf("Performance %s   SEO %s   Accessibility %s   Best practices %s\n",
+			lighthouseScore(s.Performance), lighthouseScore(s.SEO), lighthouseScore(s.Accessibility), lighthouseScore(s.BestPractices))
+		theme.Println()
+
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"Metric", "Lab", "Field (p75)", "Field Rating"},
+			r.Metrics, func(m pagespeed.Metric) []string {
+				return []string{m.Name, formatVital(m.Name, m.Lab), formatVital(m.Name, m.Field), fieldCategoryLabel(m.FieldCategory)}
+			}); err != nil {
+			return err
+		}
+		switch r.FieldSource {
+		case pagespeed.FieldOrigin:
+			theme.HiBlack("ℹ Field data is for the whole origin; this URL has too little Chrome traffic of its own.")
+		case "":
+			theme.HiBlack("ℹ No Chrome UX Report field data for this URL or its origin.")
+		}
+
+		if len(r.Opportunities) > 0 {
+			theme.Println()
+			theme.Cyan("Top opportunities:")
+			for _, o := range r.Opportunities {
+				theme.Printf("  • %s — save ~%s\n", o.Title, formatVital("", &o.SavingsMs))
+			}
+		}
+	}
+	return nil
+}
+
+func lighthouseScore(score *int) string {
+	switch {
+	case score == nil:
+		return "-"
+	case *score >= 90:
+		return theme.GreenString("%d", *score)
+	case *score >= 50:
+		return theme.YellowString("%d", *score)
+	default:
+		return theme.RedString("%d", *score)
+	}
+}
+
+// formatVital formats a metric value: CLS is unitless, everything else is
+// milliseconds, shown in seconds from one second up.
+func formatVital(name string, v *float64) string {
+	switch {
+	case v == nil:
+		return "-"
+	case name == "CLS":
+		return fmt.Sprintf("%.2f", *v)
+	case *v >= 1000:
+		return fmt.Sprintf("%.1f s", *v/1000)
+	default:
+		return fmt.Sprintf("%.0f ms", *v)
+	}
+}
+
+func fieldCategoryLabel(category string) string {
+	switch category {
+	case "FAST":
+		return theme.GreenString("%s", "good")
+	case "AVERAGE":
+		return theme.YellowString("%s", "needs improvement")
+	case "SLOW":
+		return theme.RedString("%s", "poor")
+	default:
+		return ""
+	}
+}
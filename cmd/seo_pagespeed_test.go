@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatVital(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	assert.Equal(t, "-", formatVital("LCP", nil))
+	assert.Equal(t, "0.12", formatVital("CLS", v(0.123)))
+	assert.Equal(t, "180 ms", formatVital("INP", v(180.4)))
+	assert.Equal(t, "2.9 s", formatVital("LCP", v(2900)))
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/seo"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	seoRobotsSite    string
+	seoRobotsConfig  string
+	seoRobotsURLs    []string
+	seoRobotsCrawler string
+	seoRobotsSkipGSC bool
+	seoRobotsFormat  string
+)
+
+// errRobotsBlocking signals that robots.txt blocks a tested URL or the whole
+// site; `seo robots` exits with diagcmd.ExitIssues.
+var errRobotsBlocking = errors.New("robots.txt blocks priority URLs")
+
+var seoRobotsCmd = &cobra.Command{
+	Use:   "robots",
+	Short: "Audit robots.txt against sitemaps and priority URLs",
+	Long: `Download and parse the site's robots.txt, list its rules, and check it
+against what the config and Search Console expect:
+
+  - every Sitemap: directive should be a sitemap submitted in Search Console
+  - every priority URL (search_console.url_inspection.priority_urls, plus
+    --url) should be crawlable
+
+Rules are evaluated the way Google does: the crawler's most specific
+user-agent group applies, the longest matching path wins, allow wins a tie,
+and '*' / '$' wildcards are honoured. A 4xx robots.txt means no restrictions;
+a 5xx means Google stops crawling the whole site.
+
+The site comes from --site, else search_console.site_url in --config.
+Submitted sitemaps are read from that Search Console property
+(--skip-gsc to skip).
+
+Exits 4 when a tested URL is blocked or the whole site is disallowed.
+
+Examples:
+  ga4 seo robots --site https://example.com --url https://example.com/pricing
+  ga4 seo robots --config configs/mysite.yaml
+  ga4 seo robots --config configs/mysite.yaml --user-agent Googlebot-Image --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runSEORobots(cmd, args)
+		if errors.Is(err, errRobotsBlocking) {
+			return exitWith(cmd, diagcmd.ExitIssues)
+		}
+		return err
+	},
+}
+
+func init() {
+	seoCmd.AddCommand(seoRobotsCmd)
+	seoRobotsCmd.Flags().StringVarP(&seoRobotsSite, "site", "s", "", "Site to audit (https://example.com or sc-domain:example.com)")
+	seoRobotsCmd.Flags().StringVarP(&seoRobotsConfig, "config", "c", "", "Path to configuration file (site URL, priority URLs)")
+	seoRobotsCmd.Flags().StringArrayVarP(&seoRobotsURLs, "url", "u", nil, "Extra URL to test against the rules (repeatable)")
+	seoRobotsCmd.Flags().StringVar(&seoRobotsCrawler, "user-agent", seo.DefaultCrawler, "Crawler token to evaluate the rules for")
+	seoRobotsCmd.Flags().BoolVar(&seoRobotsSkipGSC, "skip-gsc", false, "Do not compare sitemap directives with Search Console")
+	output.FormatVar(seoRobotsCmd.Flags(), &seoRobotsFormat, "f", output.FormatTable, output.FormatJSON)
+}
+
+// robotsSitemapCheck compares one sitemap between robots.txt and Search Console.
+type robotsSitemapCheck struct {
+	URL       string `json:"url"`
+	InRobots  bool   `json:"in_robots"`
+	Submitted bool   `json:"submitted"`
+}
+
+// robotsAuditResult is the `seo robots` output.
+type robotsAuditResult struct {
+	Site     string               `json:"site"`
+	Crawler  string               `json:"crawler"`
+	Robots   *seo.Robots          `json:"robots"`
+	Sitemaps []robotsSitemapCheck `json:"sitemaps,omitempty"`
+	// GSCError is set when submitted sitemaps could not be read.
+	GSCError string              `json:"gsc_error,omitempty"`
+	URLs     []seo.RobotsVerdict `json:"urls,omitempty"`
+	// OtherHost lists tested URLs on a different host, which robots.txt
+	// does not govern.
+	OtherHost []string `json:"other_host,omitempty"`
+}
+
+func runSEORobots(cmd *cobra.Command, args []string) error {
+	site, property := seoRobotsSite, seoRobotsSite
+	urls := append([]string(nil), seoRobotsURLs...)
+	if seoRobotsConfig != "" {
+		cfg, err := config.LoadConfig(seoRobotsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if sc := cfg.SearchConsole; sc != nil {
+			property = sc.SiteURL
+			if site == "" {
+				site = sc.SiteURL
+			}
+			if sc.URLInspection != nil {
+				urls = append(urls, sc.URLInspection.PriorityURLs...)
+			}
+		}
+	}
+	if site == "" {
+		return fmt.Errorf("--site is required (or --config with search_console.site_url)")
+	}
+	origin, err := robotsOrigin(site)
+	if err != nil {
+		return err
+	}
+
+	robots, err := seo.NewRobotsFetcher(15*time.Second, "").Fetch(context.Background(), origin)
+	if err != nil {
+		return err
+	}
+	result := robotsAuditResult{Site: origin, Crawler: seoRobotsCrawler, Robots: robots}
+
+	if !seoRobotsSkipGSC && robots.Fallback == seo.FallbackNone {
+		submitted, err := submittedSitemaps(gscProperty(property, origin))
+		if err != nil {
+			result.GSCError = err.Error()
+		}
+		result.Sitemaps = compareRobotsSitemaps(robots.Sitemaps, submitted)
+	}
+
+	host := strings.TrimPrefix(origin, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || !strings.EqualFold(parsed.Host, host) {
+			result.OtherHost = append(result.OtherHost, u)
+			continue
+		}
+		result.URLs = append(result.URLs, robots.Test(seoRobotsCrawler, u))
+	}
+
+	if seoRobotsFormat == "json" {
+		if err := output.JSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else if err := displayRobotsAudit(result); err != nil {
+		return err
+	}
+
+	if robots.Fallback == seo.FallbackDisallowAll {
+		return errRobotsBlocking
+	}
+	for _, v := range result.URLs {
+		if !v.Allowed {
+			return errRobotsBlocking
+		}
+	}
+	return nil
+}
+
+// robotsOrigin turns a site or Search Console property into the scheme and
+// host robots.txt is served from. A domain property is checked over https.
+func robotsOrigin(site string) (string, error) {
+	if domain, ok := strings.CutPrefix(site, "sc-domain:"); ok {
+		site = "https://" + domain
+	}
+	u, err := url.Parse(site)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid --site %q: want https://example.com or sc-domain:example.com", site)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// gscProperty picks the Search Console property to read sitemaps from: the
+// configured or given property, else the origin as a URL-prefix property.
+func gscProperty(property, origin string) string {
+	if strings.HasPrefix(property, "sc-domain:") || strings.HasSuffix(property, "/") {
+		return property
+	}
+	return origin + "/"
+}
+
+// submittedSitemaps lists the sitemap paths submitted for property.
+func submittedSitemaps(property string) ([]string, error) {
+	if _, err := auth.Resolve(); err != nil {
+		return nil, fmt.Errorf("%w; pass --skip-gsc to skip the comparison", err)
+	}
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	sitemaps, err := client.ListSitemaps(property)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(sitemaps))
+	for _, sm := range sitemaps {
+		out = append(out, sm.Path)
+	}
+	return out, nil
+}
+
+// compareRobotsSitemaps lines up robots.txt Sitemap: directives with the
+// sitemaps submitted in Search Console, directives first.
+func compareRobotsSitemaps(directives, submitted []string) []robotsSitemapCheck {
+	isSubmitted := make(map[string]bool, len(submitted))
+	for _, s := range submitted {
+		isSubmitted[s] = true
+	}
+	inRobots := make(map[string]bool, len(directives))
+	var out []robotsSitemapCheck
+	for _, d := range directives {
+		if inRobots[d] {
+			continue
+		}
+		inRobots[d] = true
+		out = append(out, robotsSitemapCheck{URL: d, InRobots: true, Submitted: isSubmitted[d]})
+	}
+	for _, s := range submitted {
+		if !inRobots[s] {
+			out = append(out, robotsSitemapCheck{URL: s, Submitted: true})
+		}
+	}
+	return out
+}
+
+func displayRobotsAudit(r robotsAuditResult) error {
+	theme.Cyan("═══ robots.txt: %s (as %s) ═══", r.Site, r.Crawler)
+	theme.Printf("Fetched %s: HTTP %d\n", r.Robots.URL, r.Robots.Status)
+	theme.Println()
+
+	switch r.Robots.Fallback {
+	case seo.FallbackAllowAll:
+		theme.Yellow("⚠ No robots.txt (HTTP %d): crawlers treat the whole site as allowed.", r.Robots.Status)
+	case seo.FallbackDisallowAll:
+		theme.Red("✗ robots.txt returned HTTP %d: Google stops crawling the whole site until it is reachable.", r.Robots.Status)
+	default:
+		type ruleRow struct {
+			agents string
+			rule   seo.RobotsRule
+		}
+		var rows []ruleRow
+		for _, g := range r.Robots.Groups {
+			for _, rule := range g.Rules {
+				rows = append(rows, ruleRow{agents: strings.Join(g.UserAgents, ", "), rule: rule})
+			}
+		}
+		if len(rows) == 0 {
+			theme.Green("✓ No allow/disallow rules.")
+		} else if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"User-agent", "Rule", "Path", "Line"},
+			rows, func(row ruleRow) []string {
+				kind := theme.RedString("%s", "disallow")
+				if row.rule.Allow {
+					kind = theme.GreenString("%s", "allow")
+				}
+				return []string{row.agents, kind, row.rule.Path, fmt.Sprintf("%d", row.rule.Line)}
+			}); err != nil {
+			return err
+		}
+		for _, issue := range r.Robots.Issues {
+			theme.HiBlack("ℹ line %d: %s", issue.Line, issue.Message)
+		}
+	}
+
+	if len(r.Sitemaps) > 0 || r.GSCError != "" {
+		theme.Println()
+		theme.Cyan("Sitemaps:")
+		for _, s := range r.Sitemaps {
+			switch {
+			case r.GSCError != "":
+				theme.Printf("   %s\n", s.URL)
+			case s.InRobots && s.Submitted:
+				theme.Green("✓ %s", s.URL)
+			case s.InRobots:
+				theme.Yellow("⚠ %s is in robots.txt but not submitted in Search Console", s.URL)
+			default:
+				theme.HiBlack("ℹ %s is submitted but not listed in robots.txt", s.URL)
+			}
+		}
+		if r.GSCError != "" {
+			theme.Yellow("⚠ Could not read submitted sitemaps: %s", r.GSCError)
+		}
+	}
+
+	if len(r.URLs) > 0 {
+		theme.Println()
+		theme.Cyan("Tested URLs:")
+		for _, v := range r.URLs {
+			switch {
+			case v.Allowed:
+				theme.Green("✓ %s", v.URL)
+			case v.Rule != nil:
+				theme.Red("✗ %s blocked by line %d (Disallow: %s)", v.URL, v.Rule.Line, v.Rule.Path)
+			default:
+				theme.Red("✗ %s blocked", v.URL)
+			}
+		}
+	}
+	for _, u := range r.OtherHost {
+		theme.HiBlack("ℹ %s is on another host; its own robots.txt applies", u)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobotsOrigin(t *testing.T) {
+	cases := map[string]string{
+		"https://example.com":         "https://example.com",
+		"https://example.com/blog/":   "https://example.com",
+		"sc-domain:example.com":       "https://example.com",
+		"http://localhost:8080/x?y=1": "http://localhost:8080",
+	}
+	for in, want := range cases {
+		got, err := robotsOrigin(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := robotsOrigin("example.com")
+	assert.Error(t, err)
+}
+
+func TestGSCProperty(t *testing.T) {
+	assert.Equal(t, "sc-domain:example.com", gscProperty("sc-domain:example.com", "https://example.com"))
+	assert.Equal(t, "https://example.com/blog/", gscProperty("https://example.com/blog/", "https://example.com"))
+	assert.Equal(t, "https://example.com/", gscProperty("https://example.com", "https://example.com"))
+}
+
+func TestCompareRobotsSitemaps(t *testing.T) {
+	got := compareRobotsSitemaps(
+		[]string{"https://example.com/sitemap.xml", "https://example.com/old.xml", "https://example.com/sitemap.xml"},
+		[]string{"https://example.com/news.xml", "https://example.com/sitemap.xml"},
+	)
+	assert.Equal(t, []robotsSitemapCheck{
+		{URL: "https://example.com/sitemap.xml", InRobots: true, Submitted: true},
+		{URL: "https://example.com/old.xml", InRobots: true},
+		{URL: "https://example.com/news.xml", Submitted: true},
+	}, got)
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/logging"
+	"github.com/garbarok/ga4-manager/internal/metrics"
+	"github.com/garbarok/ga4-manager/internal/server"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// serveTokenEnv is read when --token is not given, so the token never has to
+// appear in the process list.
+const serveTokenEnv = "GA4_SERVE_TOKEN"
+
+var (
+	servePort           int
+	serveHost           string
+	serveToken          string
+	serveInsecureNoAuth bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP API server exposing GA4 and GSC operations",
+	Long: `Start a long-running HTTP server that exposes ga4-manager operations as
+JSON endpoints, so dashboards can integrate without spawning a CLI process per
+request. One GA4 client and one GSC client are shared across all requests:
+rate limits and the GSC daily quota tracker apply to the whole process.
+
+Endpoints:
+  GET  /healthz                          liveness (no auth)
+  GET  /properties/{id}/conversions      list conversion events
+  GET  /properties/{id}/dimensions       list custom dimensions
+  GET  /properties/{id}/metrics          list custom metrics
+  POST /setup/plan                       body: project YAML; returns what setup would create
+  GET  /gsc/quota                        GSC quota used today
+  GET  /gsc/{site}/analytics             Search Analytics (site URL-escaped)
+       ?start_date=&end_date= | ?days=28, &dimensions=query,page, &limit=1000
+  GET  /metrics                          Prometheus metrics: API calls per Google
+                                         service, error counts by status code, GSC
+                                         quota gauges, route durations
+
+Authentication: every endpoint except /healthz requires
+  Authorization: Bearer <token>
+The token comes from --token or the ` + serveTokenEnv + ` environment variable.
+
+Examples:
+  ` + serveTokenEnv + `=s3cret ga4 serve --port 8080
+  curl -H "Authorization: Bearer s3cret" localhost:8080/properties/123456789/conversions
+  curl -H "Authorization: Bearer s3cret" "localhost:8080/gsc/sc-domain:example.com/analytics?days=7"`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Interface to bind (use 0.0.0.0 to listen on all interfaces)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required on API requests (default $"+serveTokenEnv+")")
+	serveCmd.Flags().BoolVar(&serveInsecureNoAuth, "insecure-no-auth", false, "Allow starting without a token (local development only)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	token := serveToken
+	if token == "" {
+		token = os.Getenv(serveTokenEnv)
+	}
+	if token == "" && !serveInsecureNoAuth {
+		return fmt.Errorf("no API token: set --token or %s (or pass --insecure-no-auth for local development)", serveTokenEnv)
+	}
+
+	clientCfg := config.ServerClientConfig()
+	// The server logs its requests to stdout as JSON at info level unless
+	// --log-level / --log-format are given.
+	level, format := "info", logging.FormatJSON
+	if cmd.Flags().Changed("log-level") {
+		level = logLevel
+	}
+	if cmd.Flags().Changed("log-format") {
+		format = logFormat
+	}
+	logger, err := logging.New(os.Stdout, level, format)
+	if err != nil {
+		return err
+	}
+
+	// The server outlives any change made in the GA4 UI, so its lists are
+	// never cached.
+	ga4Client, err := ga4.NewClient(ga4.WithConfig(clientCfg), ga4.WithCache(ga4.CacheConfig{Disabled: true}), ga4.WithLogger(logger))
+	if err != nil {
+		return fmt.Errorf("failed to create GA4 client: %w", err)
+	}
+	defer ga4Client.Close()
+
+	gscClient, err := gsc.NewClient(gsc.WithConfig(clientCfg), gsc.WithoutDeadline(), gsc.WithLogger(logger))
+	if err != nil {
+		return fmt.Errorf("failed to create GSC client: %w", err)
+	}
+	defer func() { _ = gscClient.Close() }()
+
+	registerQuotaGauges(metrics.Default, gscClient)
+
+	srv := &http.Server{
+		Addr: net.JoinHostPort(serveHost, strconv.Itoa(servePort)),
+		Handler: server.New(server.Options{
+			GA4:     ga4Client,
+			GSC:     gscClient,
+			Token:   token,
+			Logger:  logger,
+			Metrics: metrics.Default,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	green := theme.Color(color.FgGreen).SprintFunc()
+	theme.Fprintf(os.Stderr, "%s Listening on http://%s\n", green("✓"), srv.Addr)
+	if token == "" {
+		yellow := theme.Color(color.FgYellow).SprintFunc()
+		theme.Fprintf(os.Stderr, "%s Authentication disabled (--insecure-no-auth)\n", yellow("⚠️"))
+	}
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	theme.Fprintln(os.Stderr, "Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	return nil
+}
+
+// registerQuotaGauges exposes the shared GSC quota tracker so Operators can
+// alert before the daily budget runs out (the client refuses calls at 95%).
+func registerQuotaGauges(reg *metrics.Registry, client *gsc.Client) {
+	reg.RegisterGauge("ga4manager_gsc_quota_used", "GSC API requests counted against today's quota.", func() float64 {
+		used, _, _ := client.GetQuotaStatus()
+		return float64(used)
+	})
+	reg.RegisterGauge("ga4manager_gsc_quota_limit", "GSC daily API quota.", func() float64 {
+		_, limit, _ := client.GetQuotaStatus()
+		return float64(limit)
+	})
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
 	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/output"
 	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +20,12 @@ var (
 	setupAll    bool
 	configPath  string
 	setupDryRun bool
+	setupOnly   []string
+	setupSkip   []string
+	setupUpdate bool
+	setupTarget string
+
+	setupConcurrency int
 )
 
 var setupCmd = &cobra.Command{
@@ -27,12 +35,37 @@ var setupCmd = &cobra.Command{
 
 The setup command provides a unified workflow for:
 - Creating GA4 conversions, dimensions, and metrics
+- Creating calculated metrics once the custom metrics they use exist
+- Creating or updating custom channel groups
+- Applying data retention and enhanced measurement settings
 - Submitting sitemaps to Google Search Console
 - Configuring URL monitoring and search analytics
 - Pre-flight validation of credentials and permissions
 - Rollback on errors
 
-Supports GA4-only, GSC-only, or combined configurations.`,
+Supports GA4-only, GSC-only, or combined configurations.
+
+--only and --skip select parts of the pipeline (conversions, dimensions,
+metrics, calculated_metrics, channel_groups, data_retention,
+enhanced_measurement, sitemaps) so a re-run touches only what changed.
+Out-of-scope resources are listed as ignored. The config can set defaults
+under setup.only / setup.skip; a flag replaces the matching list.
+
+Conversions, dimensions and metrics that already exist are left as they are,
+even when their counting method, display name, description or units differ.
+--update patches them to match the config instead, after listing the changes.
+The config can choose per resource with on_conflict: skip, update or error
+(fail before changing anything); setup.on_conflict sets the default, which
+--update replaces. A dimension or metric's scope cannot be changed.
+
+Conversions, dimensions and metrics are created several at a time (4 by
+default, at most 10, within the API rate limit); the output stays in config
+order. --concurrency or setup.concurrency in the config changes the number,
+and --concurrency 1 creates them one by one.
+
+A config with a properties list is set up on each property in turn, with a
+summary table at the end; a failure stops the properties after it. --property
+sets up one of them.`,
 	Example: `  # Setup from configuration file (RECOMMENDED)
   ga4 setup --config configs/my-ecommerce.yaml
 
@@ -43,7 +76,19 @@ Supports GA4-only, GSC-only, or combined configurations.`,
   ga4 setup --all
 
   # Setup using config file by name (looks in configs/ and configs/examples/)
-  ga4 setup --project basic-ecommerce`,
+  ga4 setup --project basic-ecommerce
+
+  # Re-run only the dimensions, leaving everything else untouched
+  ga4 setup --config configs/my-blog.yaml --only dimensions
+
+  # Everything except sitemap submission
+  ga4 setup --config configs/my-blog.yaml --skip sitemaps
+
+  # Patch existing conversions, dimensions and metrics to match the config
+  ga4 setup --config configs/my-blog.yaml --update --dry-run
+
+  # Set up only the staging entry of a multi-property config
+  ga4 setup --config configs/brand.yaml --property staging`,
 	RunE: runSetup,
 }
 
@@ -53,72 +98,150 @@ func init() {
 	setupCmd.Flags().BoolVarP(&setupAll, "all", "a", false, "Setup all projects")
 	setupCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (e.g., configs/my-project.yaml)")
 	setupCmd.Flags().BoolVar(&setupDryRun, "dry-run", false, "Preview changes without applying them")
+	setupCmd.Flags().StringSliceVar(&setupOnly, "only", nil, "Set up only these resources: conversions, dimensions, metrics, calculated_metrics, channel_groups, data_retention, enhanced_measurement, sitemaps")
+	setupCmd.Flags().StringSliceVar(&setupSkip, "skip", nil, "Skip these resources: conversions, dimensions, metrics, calculated_metrics, channel_groups, data_retention, enhanced_measurement, sitemaps")
+	setupCmd.Flags().StringVar(&setupTarget, "property", "", "Set up only this entry of a multi-property config's properties list")
+	setupCmd.Flags().BoolVar(&setupUpdate, "update", false, "Update existing conversions, dimensions and metrics that differ from the config")
+	setupCmd.Flags().IntVar(&setupConcurrency, "concurrency", 0, fmt.Sprintf("Conversions, dimensions and metrics to create at once, 1-%d (default setup.concurrency or %d)", config.MaxSetupConcurrency, config.DefaultSetupConcurrency))
 }
 
 // runSetup is the Cobra RunE handler — reads flag variables and delegates to executeSetup.
 func runSetup(cmd *cobra.Command, args []string) error {
-	return executeSetup(configPath, projectName, setupAll, setupDryRun)
+	return executeSetup(configPath, projectName, setupTarget, setupAll, setupDryRun, setupUpdate, setupConcurrency, setupOnly, setupSkip)
 }
 
 // executeSetup performs the setup with explicit parameters, avoiding reliance on global flag state.
-func executeSetup(cfgPath, projName string, all, dryRun bool) error {
+// Non-empty only/skip lists replace the config's setup selectors, and update
+// replaces setup.on_conflict, and a non-zero concurrency setup.concurrency.
+// A non-empty property selects one entry of a multi-property config.
+func executeSetup(cfgPath, projName, property string, all, dryRun, update bool, concurrency int, only, skip []string) error {
+	if _, err := config.NewResourceScope(only, skip); err != nil {
+		return fmt.Errorf("invalid --%w", err)
+	}
+	if err := config.ValidateSetupConcurrency("--concurrency", concurrency); err != nil {
+		return err
+	}
+
 	// Load configuration
 	configs, paths, err := loadProjectConfigs(cfgPath, projName, all)
 	if err != nil {
 		return err
 	}
+	if configs, paths, err = selectProperty(configs, paths, property); err != nil {
+		return err
+	}
+	for _, cfg := range configs {
+		if cfg.Setup != nil && cfg.Setup.RequireSignedPlan && !dryRun {
+			return fmt.Errorf("config %q sets setup.require_signed_plan: create a plan with `ga4 plan --sign` and apply it with `ga4 apply`", cfg.Project.Name)
+		}
+		applySetupSelectors(cfg, only, skip)
+		if update {
+			if cfg.Setup == nil {
+				cfg.Setup = &config.SetupConfig{}
+			}
+			cfg.Setup.OnConflict = config.OnConflictUpdate
+		}
+		if concurrency > 0 {
+			if cfg.Setup == nil {
+				cfg.Setup = &config.SetupConfig{}
+			}
+			cfg.Setup.Concurrency = concurrency
+		}
+	}
+	return runSetupConfigs(configs, paths, dryRun)
+}
 
-	// Create logger
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: slog.LevelWarn, // Only show warnings and errors during setup
-	}))
+// runSetupConfigs runs the setup orchestrator for each loaded config. With
+// several configs it ends with a summary table; a failure stops the run, so
+// a staging property listed first gates production.
+func runSetupConfigs(configs []*config.ProjectConfig, paths []string, dryRun bool) error {
+	// Only warnings and errors unless --log-level says otherwise
+	logger := cliLogger
 
-	// Setup each configuration
+	results := make([]setupResult, len(configs))
+	var failed error
 	for i, cfg := range configs {
-		cfgFilePath := paths[i]
-
-		// Create clients
-		var ga4Client *ga4.Client
-		var gscClient *gsc.Client
+		results[i].cfg = cfg
+		if failed != nil {
+			results[i].result = "not run"
+			continue
+		}
+		if err := runSetupConfig(cfg, paths[i], dryRun, logger); err != nil {
+			results[i].result = "failed: " + err.Error()
+			failed = err
+			continue
+		}
+		results[i].result = "ok"
+		if dryRun {
+			results[i].result = "ok (dry-run)"
+		}
 
-		// Create GA4 client if needed
-		if cfg.HasAnalytics() {
-			ga4Client, err = newGA4Client()
-			if err != nil {
-				return err
-			}
-			defer ga4Client.Close()
+		// Add spacing between multiple setups
+		if i < len(configs)-1 {
+			theme.Println()
+			theme.Println("═══════════════════════════════════════════════")
+			theme.Println()
 		}
+	}
 
-		// Create GSC client if needed
-		if cfg.HasSearchConsole() {
-			gscClient, err = gsc.NewClient()
-			if err != nil {
-				return fmt.Errorf("failed to create GSC client: %w", err)
-			}
-			defer func() {
-				if err := gscClient.Close(); err != nil {
-					logger.Warn("failed to close GSC client", slog.String("error", err.Error()))
+	if len(configs) > 1 {
+		theme.Println()
+		theme.Cyan("═══ Setup summary ═══")
+		if err := output.Render(theme.NewWriter(os.Stdout), output.FormatTable,
+			[]string{"Project", "GA4 Property", "GSC Site", "Result"},
+			results, func(r setupResult) []string {
+				site := ""
+				if r.cfg.HasSearchConsole() {
+					site = r.cfg.SearchConsole.SiteURL
 				}
-			}()
+				return []string{r.cfg.Project.Name, r.cfg.GetPropertyID(), site, r.result}
+			}); err != nil {
+			return err
 		}
+	}
+	return failed
+}
 
-		// Create and execute orchestrator
-		orchestrator := setup.NewSetupOrchestrator(cfg, cfgFilePath, ga4Client, gscClient, logger, dryRun)
+// setupResult is one row of the summary table runSetupConfigs ends with.
+type setupResult struct {
+	cfg    *config.ProjectConfig
+	result string
+}
+
+// runSetupConfig runs the setup orchestrator for one config.
+func runSetupConfig(cfg *config.ProjectConfig, cfgFilePath string, dryRun bool, logger *slog.Logger) error {
+	// Create clients. They stay untyped nil interfaces for a service the
+	// config does not use, which the orchestrator checks for.
+	var ga4Client ga4.GA4Admin
+	var gscClient gsc.SearchConsole
 
-		if err := orchestrator.Execute(); err != nil {
+	// Create GA4 client if needed
+	if cfg.HasAnalytics() {
+		client, err := newGA4Client(cfg)
+		if err != nil {
 			return err
 		}
+		defer client.Close()
+		ga4Client = client
+	}
 
-		// Add spacing between multiple setups
-		if i < len(configs)-1 {
-			fmt.Println()
-			fmt.Println("═══════════════════════════════════════════════")
-			fmt.Println()
+	// Create GSC client if needed
+	if cfg.HasSearchConsole() {
+		client, err := gsc.NewClient(gscClientOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to create GSC client: %w", err)
 		}
+		defer func() {
+			if err := client.Close(); err != nil {
+				logger.Warn("failed to close GSC client", slog.String("error", err.Error()))
+			}
+		}()
+		gscClient = client
 	}
 
-	return nil
+	// Create and execute orchestrator
+	orchestrator := setup.NewSetupOrchestrator(cfg, cfgFilePath, ga4Client, gscClient, logger, dryRun)
+	return orchestrator.Execute()
 }
 
 // handleSetupAction handles the "Setup Projects" menu action in interactive mode.
@@ -128,7 +251,7 @@ func handleSetupAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
@@ -137,21 +260,47 @@ func handleSetupAction() {
 
 	if projectPath == "--all" {
 		all = true
-		fmt.Println("\n⚙️  Running setup for all projects...")
+		theme.Println("\n⚙️  Running setup for all projects...")
 	} else {
 		cfgPath = projectPath
-		fmt.Printf("\n⚙️  Running setup for %s...\n", projectPath)
+		theme.Printf("\n⚙️  Running setup for %s...\n", projectPath)
 	}
-	fmt.Println()
+	theme.Println()
 
-	if err := executeSetup(cfgPath, "", all, false); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error running setup: %v\n", err)
+	if err := executeSetup(cfgPath, "", "", all, false, false, 0, nil, nil); err != nil {
+		theme.Fprintf(os.Stderr, "\n❌ Error running setup: %v\n", err)
+	}
+}
+
+// applySetupSelectors overrides the config's setup.only / setup.skip with
+// the flag values that were given.
+func applySetupSelectors(cfg *config.ProjectConfig, only, skip []string) {
+	if len(only) == 0 && len(skip) == 0 {
+		return
+	}
+	if cfg.Setup == nil {
+		cfg.Setup = &config.SetupConfig{}
+	}
+	if len(only) > 0 {
+		cfg.Setup.Only = only
+	}
+	if len(skip) > 0 {
+		cfg.Setup.Skip = skip
 	}
 }
 
-// loadProjectConfigs loads ProjectConfig(s) based on command flags
+// loadProjectConfigs loads ProjectConfig(s) based on command flags, with
+// multi-property configs expanded to one config per property.
 // Returns configs and their paths for reference in orchestrator
 func loadProjectConfigs(configPath, projectName string, loadAll bool) ([]*config.ProjectConfig, []string, error) {
+	configs, paths, err := loadProjectConfigFiles(configPath, projectName, loadAll)
+	if err != nil {
+		return nil, nil, err
+	}
+	return expandProperties(configs, paths)
+}
+
+func loadProjectConfigFiles(configPath, projectName string, loadAll bool) ([]*config.ProjectConfig, []string, error) {
 	// Priority 1: Load from config file path
 	if configPath != "" {
 		cfg, err := config.LoadConfig(configPath)
@@ -161,38 +310,28 @@ func loadProjectConfigs(configPath, projectName string, loadAll bool) ([]*config
 		return []*config.ProjectConfig{cfg}, []string{configPath}, nil
 	}
 
+	registry := config.DefaultRegistry()
+
 	// Priority 2: Load all available configs (for --all flag)
 	if loadAll {
-		availableConfigs, err := config.ListAvailableConfigs()
-		if err != nil || len(availableConfigs) == 0 {
-			return nil, nil, fmt.Errorf("no config files found in configs/ or configs/examples/")
-		}
-
-		var configs []*config.ProjectConfig
-		var paths []string
-		for _, name := range availableConfigs {
-			cfg, err := config.LoadConfigByName(name)
-			if err != nil {
-				continue // Skip configs that fail to load
-			}
-			configs = append(configs, cfg)
-			paths = append(paths, fmt.Sprintf("configs/%s.yaml", name))
-		}
-
+		configs, paths, _ := registry.LoadAll() // Skip configs that fail to load
 		if len(configs) == 0 {
-			return nil, nil, fmt.Errorf("no valid config files found")
+			return nil, nil, fmt.Errorf("no valid config files found in %s/ or %s/examples/", registry.Dir, registry.Dir)
 		}
-
 		return configs, paths, nil
 	}
 
 	// Priority 3: Load by project name from config files
 	if projectName != "" {
-		cfg, err := config.LoadConfigByName(projectName)
+		path, err := registry.Path(projectName)
 		if err != nil {
 			return nil, nil, fmt.Errorf("config file not found: %s (use --config to specify a YAML config file)", projectName)
 		}
-		return []*config.ProjectConfig{cfg}, []string{fmt.Sprintf("configs/%s.yaml", projectName)}, nil
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return []*config.ProjectConfig{cfg}, []string{path}, nil
 	}
 
 	return nil, nil, fmt.Errorf("specify --project <name>, --config <path>, or --all")
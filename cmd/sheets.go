@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/sheets"
+)
+
+// sheetsRowWriter is the part of *sheets.Writer the report commands use.
+type sheetsRowWriter interface {
+	Write(ctx context.Context, columns []string, rows [][]any) error
+}
+
+// newSheetsWriter returns the writer --output sheets:// writes through,
+// authenticated like the GA4 and Search Console clients.
+var newSheetsWriter = func(t sheets.Target, mode string) (tableWriter, error) {
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	w, err := sheets.NewWriter(context.Background(), t, mode, creds.ClientOption())
+	if err != nil {
+		return nil, err
+	}
+	return &sheetsTableWriter{w: w}, nil
+}
+
+// sheetsTableWriter writes rows to a sheet tab.
+type sheetsTableWriter struct {
+	w sheetsRowWriter
+}
+
+func (s *sheetsTableWriter) Write(rows tableRows) error {
+	return s.w.Write(context.Background(), rows.Columns, rows.Values)
+}
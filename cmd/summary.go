@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/summary"
+)
+
+const summaryRowLimit = 25000
+
+var (
+	summaryConfig string
+	summaryFormat string
+	summaryOutput string
+	summaryTop    int
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Stakeholder summaries combining GA4 and Search Console",
+}
+
+var summaryWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Write the weekly executive summary as markdown or HTML",
+	Long: `Compare the last 7 days (ending yesterday) with the 7 days before them and
+write one report for stakeholders:
+
+  At a glance          GA4 sessions and key events, Search Console clicks,
+                       impressions, CTR and average position, with changes
+  Top movers           the queries and pages whose clicks changed most
+  New indexing issues  URLs that broke since the last inspection saved with
+                       ` + "`ga4 gsc monitor run --save`" + ` before this week
+  Markers              markers added with ` + "`ga4 marker add`" + ` and Google
+                       algorithm updates in either week
+
+The GA4 section needs analytics.property_id in the config, the Search
+Console sections search_console.site_url; a section without its source is
+left out.
+
+Examples:
+  ga4 summary weekly --config configs/mysite.yaml > weekly.md
+  ga4 summary weekly --config configs/mysite.yaml --format html -o weekly.html
+  ga4 summary weekly --config configs/mysite.yaml --top 10 --format json`,
+	Args: cobra.NoArgs,
+	RunE: summaryWeeklyRunE,
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+	summaryCmd.AddCommand(summaryWeeklyCmd)
+	summaryWeeklyCmd.Flags().StringVarP(&summaryConfig, "config", "c", "", "Path to configuration file (required)")
+	output.FormatVar(summaryWeeklyCmd.Flags(), &summaryFormat, "f", output.FormatMarkdown, output.FormatHTML, output.FormatJSON)
+	summaryWeeklyCmd.Flags().StringVarP(&summaryOutput, "output", "o", "", "Write the report to this file instead of stdout")
+	summaryWeeklyCmd.Flags().IntVar(&summaryTop, "top", 5, "Queries and pages to list as top movers")
+	_ = summaryWeeklyCmd.MarkFlagRequired("config")
+}
+
+// summaryGA4 is the part of the GA4 client the summary reads.
+type summaryGA4 interface {
+	MetricTotals(propertyID, startDate, endDate string, metricNames ...string) (map[string]float64, error)
+}
+
+var summaryGA4Factory = func(cfg *config.ProjectConfig) (summaryGA4, func(), error) {
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, client.Close, nil
+}
+
+var summaryGSCFactory = func() (gsc.SearchAPI, func(), error) {
+	client, err := gsc.NewClient(gscClientOptions()...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, func() { _ = client.Close() }, nil
+}
+
+func summaryWeeklyRunE(cmd *cobra.Command, _ []string) error {
+	return exitWith(cmd, runSummaryWeekly(summaryParams{
+		ConfigPath: summaryConfig,
+		Format:     summaryFormat,
+		Output:     summaryOutput,
+		Top:        summaryTop,
+		GA4:        summaryGA4Factory,
+		GSC:        summaryGSCFactory,
+		StateDir:   gscstate.ResolveStateDir(historyStateDir),
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+		Now:        time.Now().UTC(),
+	}))
+}
+
+type summaryParams struct {
+	ConfigPath string
+	Format     string
+	Output     string
+	Top        int
+	GA4        func(*config.ProjectConfig) (summaryGA4, func(), error)
+	GSC        func() (gsc.SearchAPI, func(), error)
+	StateDir   string
+	Stdout     io.Writer
+	Stderr     io.Writer
+	Now        time.Time
+}
+
+func runSummaryWeekly(p summaryParams) int {
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to load config: %v", err)
+	}
+	propertyID := cfg.GetPropertyID()
+	var site string
+	if cfg.SearchConsole != nil {
+		site = cfg.SearchConsole.SiteURL
+	}
+	if propertyID == "" && site == "" {
+		return diagcmd.FailWith(p.Stderr, "%s has neither analytics.property_id nor search_console.site_url", p.ConfigPath)
+	}
+
+	current, prior := summary.Windows(p.Now)
+	report := summary.Weekly{
+		Project:     cfg.Project.Name,
+		Site:        site,
+		GeneratedAt: p.Now,
+		Current:     current,
+		Prior:       prior,
+	}
+
+	if propertyID != "" {
+		client, cleanup, err := p.GA4(cfg)
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "failed to create GA4 client: %v", err)
+		}
+		report.Analytics, err = summaryAnalytics(client, propertyID, current, prior)
+		cleanup()
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "%v", err)
+		}
+	}
+
+	if site != "" {
+		client, cleanup, err := p.GSC()
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "failed to create GSC client: %v", err)
+		}
+		err = summarySearch(client, site, current, prior, p.Top, &report)
+		cleanup()
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "%v", err)
+		}
+		report.Indexing, err = summaryIndexing(p.StateDir, site, p.Now)
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "failed to read inspection history: %v", err)
+		}
+		report.Markers, err = reportMarkers(p.StateDir, site, prior.Start, current.End)
+		if err != nil {
+			return diagcmd.FailWith(p.Stderr, "failed to read markers: %v", err)
+		}
+	}
+
+	out, closeOutput, err := openOutput(p.Output, p.Format)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+	if p.Output == "" {
+		out = p.Stdout
+	}
+	switch p.Format {
+	case output.FormatJSON:
+		err = output.JSON(out, report)
+	case output.FormatHTML:
+		err = summary.HTML(out, report)
+	default:
+		err = summary.Markdown(out, report)
+	}
+	if closeErr := closeOutput(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to write summary: %v", err)
+	}
+	return diagcmd.ExitClean
+}
+
+// summaryAnalytics reads the GA4 sessions and key events of both windows.
+func summaryAnalytics(client summaryGA4, propertyID string, current, prior summary.Window) (*summary.Analytics, error) {
+	metrics := []string{"sessions", "keyEvents"}
+	cur, err := client.MetricTotals(propertyID, current.Start, current.End, metrics...)
+	if err != nil {
+		return nil, fmt.Errorf("GA4 report for this week failed: %w", err)
+	}
+	prev, err := client.MetricTotals(propertyID, prior.Start, prior.End, metrics...)
+	if err != nil {
+		return nil, fmt.Errorf("GA4 report for last week failed: %w", err)
+	}
+	return &summary.Analytics{
+		PropertyID: propertyID,
+		Sessions:   summary.Change{Current: cur["sessions"], Prior: prev["sessions"]},
+		KeyEvents:  summary.Change{Current: cur["keyEvents"], Prior: prev["keyEvents"]},
+	}, nil
+}
+
+// summarySearch fills in the Search Console totals and top movers.
+func summarySearch(client gsc.SearchAPI, site string, current, prior summary.Window, top int, report *summary.Weekly) error {
+	query := func(w summary.Window, dimension string) (*gsc.SearchAnalyticsReport, error) {
+		q := &gsc.SearchAnalyticsQuery{
+			SiteURL:   site,
+			StartDate: w.Start,
+			EndDate:   w.End,
+			RowLimit:  summaryRowLimit,
+			DataState: "final",
+		}
+		if dimension != "" {
+			q.Dimensions = []string{dimension}
+		}
+		r, err := client.QuerySearchAnalytics(q)
+		if err != nil {
+			return nil, fmt.Errorf("search analytics for %s to %s failed: %w", w.Start, w.End, err)
+		}
+		return r, nil
+	}
+
+	cur, err := query(current, "")
+	if err != nil {
+		return err
+	}
+	prev, err := query(prior, "")
+	if err != nil {
+		return err
+	}
+	report.Search = summary.SearchTotals(cur.Aggregates, prev.Aggregates)
+
+	for _, d := range []struct {
+		dimension string
+		movers    *[]summary.Mover
+	}{
+		{"query", &report.TopQueries},
+		{"page", &report.TopPages},
+	} {
+		cur, err := query(current, d.dimension)
+		if err != nil {
+			return err
+		}
+		prev, err := query(prior, d.dimension)
+		if err != nil {
+			return err
+		}
+		*d.movers = summary.TopMovers(cur.Rows, prev.Rows, top)
+	}
+	return nil
+}
+
+// summaryIndexing compares the latest `gsc monitor run --save` with the last
+// one saved before the current week.
+func summaryIndexing(stateDir, site string, now time.Time) (*summary.Indexing, error) {
+	ctx := context.Background()
+	history := store.New(stateDir)
+	q := store.Query{
+		Kind:  store.KindInspection,
+		Site:  site,
+		Match: map[string]string{"source": monitorSource},
+	}
+	latest, err := history.LastRun(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) == 0 {
+		return &summary.Indexing{Note: "No inspections saved yet: run `ga4 gsc monitor run --save` to track indexing."}, nil
+	}
+	ix := &summary.Indexing{Latest: latest[0].RecordedAt}
+
+	weekStart := now.AddDate(0, 0, -summary.Days)
+	if ix.Latest.Before(weekStart) {
+		ix.Note = fmt.Sprintf("No inspection saved this week; the latest is from %s.", ix.Latest.Format("2006-01-02"))
+		return ix, nil
+	}
+	q.Until = weekStart
+	baseline, err := history.LastRun(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(baseline) == 0 {
+		ix.Note = "No inspection saved before this week to compare with."
+		return ix, nil
+	}
+	ix.Baseline = baseline[0].RecordedAt
+	ix.NewIssues = gsc.CompareInspections(inspectionSnapshots(baseline), inspectionSnapshots(latest)).NewIssues
+	return ix, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/summary"
+)
+
+// fakeSummaryGA4 answers MetricTotals per start date.
+type fakeSummaryGA4 struct {
+	totals map[string]map[string]float64
+	err    error
+}
+
+func (f *fakeSummaryGA4) MetricTotals(_, startDate, _ string, _ ...string) (map[string]float64, error) {
+	return f.totals[startDate], f.err
+}
+
+// fakeSummaryGSC answers Search Analytics per start date and dimension ("" for
+// the totals).
+type fakeSummaryGSC struct {
+	reports map[string]*gsc.SearchAnalyticsReport
+}
+
+func (f *fakeSummaryGSC) QuerySearchAnalytics(q *gsc.SearchAnalyticsQuery) (*gsc.SearchAnalyticsReport, error) {
+	key := q.StartDate
+	if len(q.Dimensions) > 0 {
+		key += "/" + q.Dimensions[0]
+	}
+	if r, ok := f.reports[key]; ok {
+		return r, nil
+	}
+	return &gsc.SearchAnalyticsReport{}, nil
+}
+
+func writeSummaryConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+const summaryTestConfig = `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+search_console:
+  site_url: sc-domain:example.com
+`
+
+func TestRunSummaryWeekly_CombinesGA4AndSearchConsole(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	current, prior := summary.Windows(now)
+	site := "sc-domain:example.com"
+
+	stateDir := t.TempDir()
+	st := store.New(stateDir)
+	inspection := func(at time.Time, status string, issues string) []store.Record {
+		return []store.Record{{
+			RecordedAt: at,
+			Dimensions: map[string]string{"page": "https://example.com/a", "source": monitorSource},
+			State:      map[string]string{"index_status": status, "issues": issues},
+		}}
+	}
+	require.NoError(t, st.Append(context.Background(), store.KindInspection, site, inspection(now.AddDate(0, 0, -9), "PASS", "")))
+	require.NoError(t, st.Append(context.Background(), store.KindInspection, site, inspection(now.AddDate(0, 0, -1), "FAIL", "robots_txt")))
+	release, err := markers.Add(context.Background(), st, site, markers.Marker{Date: current.Start, Label: "release 2.3"})
+	require.NoError(t, err)
+
+	ga := &fakeSummaryGA4{totals: map[string]map[string]float64{
+		current.Start: {"sessions": 1200, "keyEvents": 30},
+		prior.Start:   {"sessions": 1000, "keyEvents": 40},
+	}}
+	sc := &fakeSummaryGSC{reports: map[string]*gsc.SearchAnalyticsReport{
+		current.Start:            {Aggregates: gsc.SearchAnalyticsAggregate{TotalClicks: 90, TotalImpressions: 3000, AveragePosition: 9}},
+		prior.Start:              {Aggregates: gsc.SearchAnalyticsAggregate{TotalClicks: 100, TotalImpressions: 2500, AveragePosition: 8}},
+		current.Start + "/query": {Rows: []gsc.SearchAnalyticsRow{{Keys: []string{"compress png"}, Clicks: 5}}},
+		prior.Start + "/query":   {Rows: []gsc.SearchAnalyticsRow{{Keys: []string{"compress png"}, Clicks: 20}}},
+	}}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runSummaryWeekly(summaryParams{
+		ConfigPath: writeSummaryConfig(t, summaryTestConfig),
+		Format:     "json",
+		Top:        5,
+		GA4:        func(*config.ProjectConfig) (summaryGA4, func(), error) { return ga, func() {}, nil },
+		GSC:        func() (gsc.SearchAPI, func(), error) { return sc, func() {}, nil },
+		StateDir:   stateDir,
+		Stdout:     stdout,
+		Stderr:     stderr,
+		Now:        now,
+	})
+	require.Equal(t, diagcmd.ExitClean, code, stderr.String())
+
+	var got summary.Weekly
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+	assert.Equal(t, "Example", got.Project)
+	require.NotNil(t, got.Analytics)
+	assert.Equal(t, summary.Change{Current: 1200, Prior: 1000}, got.Analytics.Sessions)
+	require.NotNil(t, got.Search)
+	assert.Equal(t, summary.Change{Current: 90, Prior: 100}, got.Search.Clicks)
+	require.Len(t, got.TopQueries, 1)
+	assert.Equal(t, "compress png", got.TopQueries[0].Key)
+	assert.Empty(t, got.TopPages)
+
+	require.NotNil(t, got.Indexing)
+	require.Len(t, got.Indexing.NewIssues, 1)
+	assert.True(t, got.Indexing.NewIssues[0].Deindexed)
+	assert.Equal(t, []string{"robots_txt"}, got.Indexing.NewIssues[0].Issues)
+	assert.Equal(t, []markers.Marker{release}, got.Markers)
+}
+
+func TestRunSummaryWeekly_WritesHTMLWithoutSearchConsole(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "weekly.html")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runSummaryWeekly(summaryParams{
+		ConfigPath: writeSummaryConfig(t, "project:\n  name: Example\nanalytics:\n  property_id: \"123456789\"\n"),
+		Format:     "html",
+		Output:     out,
+		GA4:        func(*config.ProjectConfig) (summaryGA4, func(), error) { return &fakeSummaryGA4{}, func() {}, nil },
+		GSC: func() (gsc.SearchAPI, func(), error) {
+			t.Fatal("no Search Console site, no client")
+			return nil, nil, nil
+		},
+		StateDir: t.TempDir(),
+		Stdout:   stdout,
+		Stderr:   stderr,
+		Now:      time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	})
+	require.Equal(t, diagcmd.ExitClean, code, stderr.String())
+	assert.Empty(t, stdout.String())
+
+	page, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<td>Sessions</td>")
+	assert.NotContains(t, string(page), "Top movers")
+}
+
+func TestRunSummaryWeekly_GA4ErrorFails(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	code := runSummaryWeekly(summaryParams{
+		ConfigPath: writeSummaryConfig(t, summaryTestConfig),
+		Format:     "markdown",
+		GA4: func(*config.ProjectConfig) (summaryGA4, func(), error) {
+			return &fakeSummaryGA4{err: errors.New("boom")}, func() {}, nil
+		},
+		Stdout: &bytes.Buffer{},
+		Stderr: stderr,
+		Now:    time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), "GA4 report for this week failed: boom")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// themeName is the --theme flag; empty means $GA4_THEME or the default.
+var themeName string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Output theme: "+strings.Join(theme.Names(), ", ")+" (default $"+theme.EnvVar+" or default)")
+	cobra.OnInitialize(applyTheme)
+}
+
+// applyTheme activates the theme once flags are parsed, before any command
+// prints.
+func applyTheme() {
+	if err := theme.Set(theme.Resolve(themeName)); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
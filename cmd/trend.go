@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	gscstate "github.com/garbarok/ga4-manager/internal/gsc/state"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/store"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+var (
+	trendSite     string
+	trendConfig   string
+	trendMetric   string
+	trendQuery    string
+	trendPage     string
+	trendDays     int
+	trendFormat   string
+	trendStateDir string
+)
+
+// trendMetrics are the analytics metrics a trend can plot.
+var trendMetrics = []string{"position", "clicks", "impressions", "ctr"}
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Plot a saved Search Console metric over time",
+	Long: `Read analytics runs saved with ` + "`ga4 gsc analytics run --save`" + ` from the local
+history store and show one metric over time as a sparkline and table.
+
+Each saved run becomes one point. When a run holds several rows for the
+query (e.g. one per landing page), clicks and impressions are summed, CTR is
+recomputed and position is averaged weighted by impressions, matching how
+Search Console aggregates.
+
+Markers added with ` + "`ga4 marker add`" + ` and Google algorithm updates are shown
+against the first run whose period covers them, so a change can be matched
+with a deployment or a core update.
+
+Metrics: position (lower is better), clicks, impressions, ctr
+
+Examples:
+  ga4 trend --config configs/mysite.yaml --metric position --query "image compressor" --days 180
+  ga4 trend --site sc-domain:example.com --metric clicks --page https://example.com/pricing
+  ga4 trend --config configs/mysite.yaml --query "image compressor" --format csv > trend.csv`,
+	RunE: runTrend,
+}
+
+func init() {
+	rootCmd.AddCommand(trendCmd)
+	trendCmd.Flags().StringVarP(&trendSite, "site", "s", "", "Site URL (sc-domain:example.com or https://example.com/)")
+	trendCmd.Flags().StringVarP(&trendConfig, "config", "c", "", "Read the site from search_console.site_url in this config")
+	trendCmd.Flags().StringVarP(&trendMetric, "metric", "m", "position", "Metric to plot: position, clicks, impressions, or ctr")
+	trendCmd.Flags().StringVarP(&trendQuery, "query", "q", "", "Search query to follow")
+	trendCmd.Flags().StringVarP(&trendPage, "page", "p", "", "Landing page URL to follow")
+	trendCmd.Flags().IntVarP(&trendDays, "days", "d", 90, "Look back this many days of saved runs (0 = all)")
+	output.FormatVar(trendCmd.Flags(), &trendFormat, "f", output.FormatTable, output.FormatCSV, output.FormatJSON)
+	trendCmd.Flags().StringVar(&trendStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+	trendCmd.MarkFlagsOneRequired("query", "page")
+}
+
+// trendPoint is one saved run aggregated to a single value.
+type trendPoint struct {
+	RecordedAt  time.Time `json:"recorded_at"`
+	StartDate   string    `json:"start_date,omitempty"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Value       float64   `json:"value"`
+	Clicks      float64   `json:"clicks"`
+	Impressions float64   `json:"impressions"`
+	// Markers are those that fall in the days this run added to the series.
+	Markers []markers.Marker `json:"markers,omitempty"`
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	if !isTrendMetric(trendMetric) {
+		return fmt.Errorf("invalid --metric %q (valid: position, clicks, impressions, ctr)", trendMetric)
+	}
+	if trendDays < 0 {
+		return fmt.Errorf("--days must not be negative, got %d", trendDays)
+	}
+	site, err := siteFromFlags(trendSite, trendConfig)
+	if err != nil {
+		return err
+	}
+
+	q := store.Query{Kind: store.KindAnalytics, Site: site, Match: map[string]string{}}
+	if trendQuery != "" {
+		q.Match["query"] = trendQuery
+	}
+	if trendPage != "" {
+		q.Match["page"] = trendPage
+	}
+	if trendDays > 0 {
+		q.Since = time.Now().AddDate(0, 0, -trendDays)
+	}
+	history := store.New(gscstate.ResolveStateDir(trendStateDir))
+	records, err := history.Query(context.Background(), q)
+	if err != nil {
+		return err
+	}
+	points := trendSeries(records, trendMetric)
+	ms, err := allMarkers(gscstate.ResolveStateDir(trendStateDir), site)
+	if err != nil {
+		return err
+	}
+	markTrendPoints(points, ms)
+
+	switch trendFormat {
+	case "json":
+		if points == nil {
+			points = []trendPoint{}
+		}
+		return output.JSON(os.Stdout, points)
+	case output.FormatCSV:
+		return output.Render(os.Stdout, output.FormatCSV, trendColumns(), points, trendCSVRow)
+	}
+
+	subject := trendSubject()
+	if len(points) == 0 {
+		theme.Yellow("No saved analytics runs for %s on %s. Run `ga4 gsc analytics run --save` first.", subject, site)
+		return nil
+	}
+
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+		if trendMetric == "position" {
+			// Draw rank so that "up" means better.
+			values[i] = -p.Value
+		}
+	}
+	first, last := points[0].Value, points[len(points)-1].Value
+	theme.Cyan("═══ %s · %s ═══", trendMetric, subject)
+	theme.Printf("%s  %s → %s over %d run(s)\n", output.Sparkline(values, theme.Current() == theme.PlainASCII),
+		formatTrendValue(first), formatTrendValue(last), len(points))
+	if trendMetric == "position" {
+		theme.HiBlack("(sparkline is inverted: higher means a better position)")
+	}
+	theme.Println()
+	return output.Render(theme.NewWriter(os.Stdout), output.FormatTable, trendColumns(), points, trendTableRow)
+}
+
+// trendSeries folds records into one point per saved run, oldest first.
+func trendSeries(records []store.Record, metric string) []trendPoint {
+	type acc struct {
+		point       trendPoint
+		weightedPos float64
+	}
+	byRun := make(map[time.Time]*acc)
+	for _, r := range records {
+		a, ok := byRun[r.RecordedAt]
+		if !ok {
+			a = &acc{point: trendPoint{RecordedAt: r.RecordedAt, StartDate: r.StartDate, EndDate: r.EndDate}}
+			byRun[r.RecordedAt] = a
+		}
+		imp := r.Metrics["impressions"]
+		a.point.Clicks += r.Metrics["clicks"]
+		a.point.Impressions += imp
+		a.weightedPos += r.Metrics["position"] * imp
+	}
+
+	points := make([]trendPoint, 0, len(byRun))
+	for _, a := range byRun {
+		p := a.point
+		switch metric {
+		case "clicks":
+			p.Value = p.Clicks
+		case "impressions":
+			p.Value = p.Impressions
+		case "ctr":
+			p.Value = ratio(p.Clicks, p.Impressions)
+		default:
+			p.Value = ratio(a.weightedPos, p.Impressions)
+		}
+		if math.IsNaN(p.Value) {
+			continue // no impressions in this run: nothing to plot
+		}
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].RecordedAt.Before(points[j].RecordedAt) })
+	return points
+}
+
+// markTrendPoints attaches markers to the points whose period covers them.
+// The first point covers its whole period, each later one only the days
+// after the latest end before it, so overlapping saved periods do not
+// repeat a marker on every run.
+func markTrendPoints(points []trendPoint, ms []markers.Marker) {
+	covered := ""
+	for i := range points {
+		p := &points[i]
+		if p.EndDate == "" {
+			continue
+		}
+		for _, m := range ms {
+			if covered == "" && m.Overlaps(p.StartDate, p.EndDate) || covered != "" && m.Last() > covered && m.Date <= p.EndDate {
+				p.Markers = append(p.Markers, m)
+			}
+		}
+		if p.EndDate > covered {
+			covered = p.EndDate
+		}
+	}
+}
+
+func ratio(num, den float64) float64 {
+	if den == 0 {
+		return math.NaN()
+	}
+	return num / den
+}
+
+func isTrendMetric(m string) bool {
+	for _, v := range trendMetrics {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+func trendSubject() string {
+	switch {
+	case trendQuery != "" && trendPage != "":
+		return fmt.Sprintf("%q on %s", trendQuery, trendPage)
+	case trendQuery != "":
+		return strconv.Quote(trendQuery)
+	default:
+		return trendPage
+	}
+}
+
+func formatTrendValue(v float64) string {
+	switch {
+	case trendMetric == "ctr":
+		return fmt.Sprintf("%.2f%%", v*100)
+	case trendMetric == "position":
+		return fmt.Sprintf("%.1f", v)
+	default:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+}
+
+func trendColumns() []string {
+	return []string{"Saved", "Period Start", "Period End", trendMetric, "Markers"}
+}
+
+func trendTableRow(p trendPoint) []string {
+	return []string{p.RecordedAt.Local().Format("2006-01-02 15:04"), p.StartDate, p.EndDate, formatTrendValue(p.Value), markerLabels(p.Markers)}
+}
+
+func trendCSVRow(p trendPoint) []string {
+	return []string{p.RecordedAt.UTC().Format(time.RFC3339), p.StartDate, p.EndDate, strconv.FormatFloat(p.Value, 'f', -1, 64), markerLabels(p.Markers)}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestTrendSeries_AggregatesRowsPerRun(t *testing.T) {
+	run1 := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	run2 := time.Date(2026, 5, 8, 6, 0, 0, 0, time.UTC)
+	row := func(at time.Time, clicks, imp, pos float64) store.Record {
+		return store.Record{RecordedAt: at, Metrics: map[string]float64{"clicks": clicks, "impressions": imp, "position": pos}}
+	}
+	records := []store.Record{
+		row(run2, 10, 100, 4),
+		row(run1, 5, 100, 10),
+		row(run1, 5, 300, 6), // second landing page in the same run
+	}
+
+	pos := trendSeries(records, "position")
+	require.Len(t, pos, 2)
+	assert.Equal(t, run1, pos[0].RecordedAt, "oldest first")
+	assert.InDelta(t, 7.0, pos[0].Value, 1e-9, "impression-weighted: (10*100+6*300)/400")
+	assert.InDelta(t, 4.0, pos[1].Value, 1e-9)
+
+	clicks := trendSeries(records, "clicks")
+	assert.Equal(t, 10.0, clicks[0].Value)
+
+	ctr := trendSeries(records, "ctr")
+	assert.InDelta(t, 10.0/400, ctr[0].Value, 1e-9)
+}
+
+func TestTrendSeries_SkipsRunsWithoutImpressions(t *testing.T) {
+	records := []store.Record{{RecordedAt: time.Now(), Metrics: map[string]float64{"position": 3}}}
+	assert.Empty(t, trendSeries(records, "position"))
+}
+
+func TestMarkTrendPoints_MarksTheRunThatFirstCoversEachMarker(t *testing.T) {
+	points := []trendPoint{
+		{StartDate: "2026-04-03", EndDate: "2026-04-30"},
+		{StartDate: "2026-04-10", EndDate: "2026-05-07"},
+		{StartDate: "2026-04-17", EndDate: "2026-05-14"},
+	}
+	migration := markers.Marker{ID: "a", Date: "2026-04-20", Label: "site migration"}
+	update := markers.Marker{ID: "b", Date: "2026-05-06", End: "2026-05-10", Label: "core update"}
+	old := markers.Marker{ID: "c", Date: "2026-03-01", Label: "too old"}
+
+	markTrendPoints(points, []markers.Marker{old, migration, update})
+
+	assert.Equal(t, []markers.Marker{migration}, points[0].Markers)
+	assert.Equal(t, []markers.Marker{update}, points[1].Markers)
+	assert.Equal(t, []markers.Marker{update}, points[2].Markers, "still running after the previous period")
+	assert.Equal(t, "core update", trendTableRow(points[2])[4])
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/theme"
+	"github.com/garbarok/ga4-manager/internal/tui"
+)
+
+var (
+	tuiConfig string
+	tuiDays   int
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen dashboard of a project's property, clicks, issues and quota",
+	Long: `Open a terminal dashboard for one config with four panes:
+
+  1 Property          data streams, conversions, custom dimensions and metrics
+  2 Search clicks     daily clicks sparkline over --days
+  3 Indexing issues   URLs not indexed in the last saved ` + "`gsc monitor run --save`" + `
+  4 Inspection quota  URL inspections used today
+
+Move between panes with tab or the arrow keys (or 1-4), press enter to drill
+down into a pane, esc to come back, r to reload and q to quit.
+
+The dashboard spends no URL inspection quota: issues come from the history
+store, clicks from one Search Analytics query. Without --config a project is
+picked from the config directory.
+
+Examples:
+  ga4 tui --config configs/mysite.yaml
+  ga4 tui --config configs/mysite.yaml --days 90`,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().StringVarP(&tuiConfig, "config", "c", "", "Path to configuration file (default: pick one)")
+	tuiCmd.Flags().IntVarP(&tuiDays, "days", "d", 28, "Days of search clicks to chart")
+	tuiCmd.Flags().StringVar(&historyStateDir, "state-dir", "", "Override the state directory (default .ga4-state/)")
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if tuiDays < 1 || tuiDays > 480 {
+		return fmt.Errorf("--days must be between 1 and 480, got %d", tuiDays)
+	}
+	path := tuiConfig
+	if path == "" {
+		selected, err := tui.RunProjectSelector()
+		if errors.Is(err, tui.ErrBackToMenu) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if selected == "--all" {
+			return fmt.Errorf("the dashboard shows one project; pick one or pass --config")
+		}
+		path = selected
+	}
+	return runDashboard(path, tuiDays)
+}
+
+// handleDashboardAction handles the "Dashboard" menu action in interactive mode.
+func handleDashboardAction() {
+	projectPath, err := tui.RunProjectSelector()
+	if err != nil {
+		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
+			return
+		}
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		return
+	}
+	if projectPath == "--all" {
+		theme.Fprintln(os.Stderr, "The dashboard shows one project at a time; pick one.")
+		return
+	}
+	if err := runDashboard(projectPath, tuiDays); err != nil {
+		theme.Fprintf(os.Stderr, "\n❌ Error running dashboard: %v\n", err)
+	}
+}
+
+// runDashboard opens the dashboard for the config at path. The clients are
+// created once and reused by every refresh.
+func runDashboard(path string, days int) error {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.HasAnalytics() && !cfg.HasSearchConsole() {
+		return fmt.Errorf("%s configures neither analytics nor search_console", path)
+	}
+
+	// The clients stay untyped nil interfaces for a service the config does
+	// not use, which loadDashboard checks for.
+	var ga4Client ga4.GA4Admin
+	if cfg.HasAnalytics() {
+		client, err := newGA4Client(cfg)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		ga4Client = client
+	}
+	var gscClient gsc.SearchConsole
+	if cfg.HasSearchConsole() {
+		client, err := gsc.NewClient(gscClientOptions()...)
+		if err != nil {
+			return fmt.Errorf("failed to create GSC client: %w", err)
+		}
+		defer func() { _ = client.Close() }()
+		gscClient = client
+	}
+
+	return tui.RunDashboard(func() tui.DashboardData {
+		return loadDashboard(cfg, ga4Client, gscClient, days)
+	})
+}
+
+// loadDashboard reads everything the dashboard shows. Failures are kept per
+// pane so one missing permission does not blank the whole screen.
+func loadDashboard(cfg *config.ProjectConfig, ga4Client ga4.GA4Admin, gscClient gsc.SearchConsole, days int) tui.DashboardData {
+	d := tui.DashboardData{Project: cfg.Project.Name, LoadedAt: time.Now()}
+
+	if ga4Client != nil {
+		d.PropertyID = cfg.GetPropertyID()
+		d.PropertyErr = loadPropertySummary(&d, ga4Client)
+	}
+
+	if gscClient != nil {
+		site := cfg.SearchConsole.SiteURL
+		d.SiteURL = site
+
+		start, end := gsc.BuildDateRange(days)
+		report, err := gscClient.QuerySearchAnalytics(&gsc.SearchAnalyticsQuery{
+			SiteURL:    site,
+			StartDate:  start,
+			EndDate:    end,
+			Dimensions: []string{"date"},
+			RowLimit:   days,
+		})
+		if err != nil {
+			d.ClicksErr = err
+		} else {
+			for _, row := range report.Rows {
+				d.Clicks = append(d.Clicks, tui.DailyClicks{
+					Date:        row.Keys[0],
+					Clicks:      row.Clicks,
+					Impressions: row.Impressions,
+					Position:    row.Position,
+				})
+			}
+		}
+
+		snapshots, asOf, err := loadMonitorBaseline(site)
+		d.IssuesAsOf, d.IssuesErr, d.Inspected = asOf, err, len(snapshots)
+		for _, s := range snapshots {
+			if s.IndexStatus != "PASS" || len(s.Issues) > 0 {
+				d.Issues = append(d.Issues, tui.IndexingIssue{
+					URL:           s.URL,
+					IndexStatus:   s.IndexStatus,
+					CoverageState: s.CoverageState,
+					Issues:        s.Issues,
+				})
+			}
+		}
+
+		d.QuotaUsed, d.QuotaLimit, d.QuotaDate = gscClient.GetQuotaStatus()
+	}
+	return d
+}
+
+// loadPropertySummary lists the property's streams and custom definitions
+// into d.
+func loadPropertySummary(d *tui.DashboardData, client ga4.GA4Admin) error {
+	streams, err := client.ListDataStreams(d.PropertyID)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		name := strings.TrimSuffix(strings.TrimPrefix(s.Type, "DATA_STREAM_TYPE_"), "_DATA_STREAM")
+		if s.WebStreamData != nil && s.WebStreamData.DefaultUri != "" {
+			name += " " + s.WebStreamData.DefaultUri
+		} else if s.DisplayName != "" {
+			name += " " + s.DisplayName
+		}
+		d.Streams = append(d.Streams, name)
+	}
+
+	conversions, err := client.ListConversions(d.PropertyID)
+	if err != nil {
+		return err
+	}
+	for _, c := range conversions {
+		d.Conversions = append(d.Conversions, fmt.Sprintf("%s (%s)", c.EventName, c.CountingMethod))
+	}
+
+	dimensions, err := client.ListDimensions(d.PropertyID)
+	if err != nil {
+		return err
+	}
+	for _, dim := range dimensions {
+		d.Dimensions = append(d.Dimensions, fmt.Sprintf("%s · %s (%s)", dim.ParameterName, dim.DisplayName, dim.Scope))
+	}
+
+	metrics, err := client.ListCustomMetrics(d.PropertyID)
+	if err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		d.Metrics = append(d.Metrics, fmt.Sprintf("%s · %s (%s)", m.ParameterName, m.DisplayName, m.MeasurementUnit))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4/ga4fake"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/gscfake"
+)
+
+func TestLoadDashboard(t *testing.T) {
+	historyStateDir = t.TempDir()
+	t.Cleanup(func() { historyStateDir = "" })
+	cfg := &config.ProjectConfig{
+		Project:       config.ProjectInfo{Name: "Example"},
+		Analytics:     &config.AnalyticsConfig{PropertyID: "123"},
+		SearchConsole: &config.SearchConsoleConfig{SiteURL: "sc-domain:example.com"},
+	}
+	ga4Fake := &ga4fake.Admin{
+		DataStreams: []*admin.GoogleAnalyticsAdminV1alphaDataStream{{
+			Type:          "WEB_DATA_STREAM",
+			WebStreamData: &admin.GoogleAnalyticsAdminV1alphaDataStreamWebStreamData{DefaultUri: "https://example.com"},
+		}},
+		Conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"}},
+	}
+	gscFake := &gscfake.SearchConsole{
+		QuotaUsed: 40,
+		Analytics: &gsc.SearchAnalyticsReport{Rows: []gsc.SearchAnalyticsRow{
+			{Keys: []string{"2026-10-01"}, Clicks: 12, Impressions: 300, Position: 8.5},
+		}},
+	}
+
+	d := loadDashboard(cfg, ga4Fake, gscFake, 28)
+
+	assert.Equal(t, "Example", d.Project)
+	require.NoError(t, d.PropertyErr)
+	assert.Equal(t, []string{"WEB https://example.com"}, d.Streams)
+	assert.Equal(t, []string{"purchase (ONCE_PER_EVENT)"}, d.Conversions)
+	require.Len(t, d.Clicks, 1)
+	assert.Equal(t, int64(12), d.Clicks[0].Clicks)
+	assert.Equal(t, []string{"date"}, gscFake.Called("QuerySearchAnalytics"))
+	assert.True(t, d.IssuesAsOf.IsZero(), "no monitor run was saved")
+	assert.Equal(t, 40, d.QuotaUsed)
+	assert.Empty(t, gscFake.Called("InspectURL"), "the dashboard spends no inspection quota")
+}
+
+func TestLoadDashboard_PaneErrors(t *testing.T) {
+	historyStateDir = t.TempDir()
+	t.Cleanup(func() { historyStateDir = "" })
+	cfg := &config.ProjectConfig{
+		Analytics:     &config.AnalyticsConfig{PropertyID: "123"},
+		SearchConsole: &config.SearchConsoleConfig{SiteURL: "sc-domain:example.com"},
+	}
+	ga4Fake := &ga4fake.Admin{Errs: map[string]error{"ListDataStreams": errors.New("permission denied")}}
+	gscFake := &gscfake.SearchConsole{Errs: map[string]error{"QuerySearchAnalytics": errors.New("quota exceeded")}}
+
+	d := loadDashboard(cfg, ga4Fake, gscFake, 28)
+
+	assert.EqualError(t, d.PropertyErr, "permission denied")
+	assert.EqualError(t, d.ClicksErr, "quota exceeded")
+	assert.NoError(t, d.IssuesErr, "the other panes still load")
+	assert.Equal(t, gsc.DefaultDailyLimit, d.QuotaLimit)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/theme"
 	"github.com/garbarok/ga4-manager/internal/tui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -37,7 +38,7 @@ var (
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
-	validateCmd.Flags().BoolVar(&validateAll, "all", false, "Validate all config files in configs/ directory")
+	validateCmd.Flags().BoolVar(&validateAll, "all", false, "Validate all config files in the config directory")
 	validateCmd.Flags().BoolVarP(&validateVerbose, "verbose", "v", false, "Show detailed validation results")
 }
 
@@ -48,21 +49,22 @@ func runValidate(cmd *cobra.Command, args []string) error {
 
 // executeValidate performs validation with explicit parameters, avoiding reliance on global flag state.
 func executeValidate(all, verbose bool, args []string) error {
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
 
-	fmt.Println("🔍 GA4 Config Validator")
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Println()
+	theme.Println("🔍 GA4 Config Validator")
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Println()
 
 	var filesToValidate []string
 
 	if all {
-		// Find all YAML files in configs/
-		paths := []string{"configs/examples", "configs"}
+		// Find all YAML files in the config directory
+		dir := config.ConfigDir()
+		paths := []string{filepath.Join(dir, "examples"), dir}
 		for _, dir := range paths {
 			if entries, err := os.ReadDir(dir); err == nil {
 				for _, entry := range entries {
@@ -74,7 +76,7 @@ func executeValidate(all, verbose bool, args []string) error {
 		}
 
 		if len(filesToValidate) == 0 {
-			return fmt.Errorf("no YAML config files found in configs/ directory")
+			return fmt.Errorf("no YAML config files found in %s/ directory", dir)
 		}
 	} else if len(args) > 0 {
 		filesToValidate = []string{args[0]}
@@ -87,12 +89,12 @@ func executeValidate(all, verbose bool, args []string) error {
 	invalidFiles := 0
 
 	for _, filePath := range filesToValidate {
-		fmt.Printf("📄 Validating: %s\n", cyan(filePath))
-		fmt.Println("───────────────────────────────────────────────")
+		theme.Printf("📄 Validating: %s\n", cyan(filePath))
+		theme.Println("───────────────────────────────────────────────")
 
 		// Check if file exists
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			fmt.Printf("%s File not found\n\n", red("✗"))
+			theme.Printf("%s File not found\n\n", red("✗"))
 			invalidFiles++
 			continue
 		}
@@ -100,45 +102,45 @@ func executeValidate(all, verbose bool, args []string) error {
 		// Read file
 		data, err := os.ReadFile(filePath)
 		if err != nil {
-			fmt.Printf("%s Failed to read file: %v\n\n", red("✗"), err)
+			theme.Printf("%s Failed to read file: %v\n\n", red("✗"), err)
 			invalidFiles++
 			continue
 		}
 
 		// Step 1: Validate YAML syntax
-		fmt.Printf("%s Checking YAML syntax...", blue("  →"))
+		theme.Printf("%s Checking YAML syntax...", blue("  →"))
 		var rawYAML interface{}
 		if err := yaml.Unmarshal(data, &rawYAML); err != nil {
-			fmt.Printf(" %s\n", red("FAILED"))
+			theme.Printf(" %s\n", red("FAILED"))
 			printYAMLError(err, string(data))
 			invalidFiles++
-			fmt.Println()
+			theme.Println()
 			continue
 		}
-		fmt.Printf(" %s\n", green("OK"))
+		theme.Printf(" %s\n", green("OK"))
 
 		// Step 2: Validate config structure
-		fmt.Printf("%s Checking config structure...", blue("  →"))
+		theme.Printf("%s Checking config structure...", blue("  →"))
 		cfg, err := config.LoadConfig(filePath)
 		if err != nil {
-			fmt.Printf(" %s\n", red("FAILED"))
-			fmt.Printf("    %s\n", err)
+			theme.Printf(" %s\n", red("FAILED"))
+			theme.Printf("    %s\n", err)
 			invalidFiles++
-			fmt.Println()
+			theme.Println()
 			continue
 		}
-		fmt.Printf(" %s\n", green("OK"))
+		theme.Printf(" %s\n", green("OK"))
 
 		// Step 3: Check tier limits
-		fmt.Printf("%s Checking tier limits...", blue("  →"))
+		theme.Printf("%s Checking tier limits...", blue("  →"))
 		warnings := config.ValidateTierLimits(cfg)
 		if len(warnings) > 0 {
-			fmt.Printf(" %s\n", yellow("WARNINGS"))
+			theme.Printf(" %s\n", yellow("WARNINGS"))
 			for _, warning := range warnings {
-				fmt.Printf("    %s %s\n", yellow("⚠"), warning)
+				theme.Printf("    %s %s\n", yellow("⚠"), warning)
 			}
 		} else {
-			fmt.Printf(" %s\n", green("OK"))
+			theme.Printf(" %s\n", green("OK"))
 		}
 
 		// Step 4: Show config summary
@@ -149,30 +151,30 @@ func executeValidate(all, verbose bool, args []string) error {
 			}
 			limits := config.GetTierLimits(tier)
 
-			fmt.Printf("\n%s Configuration Summary:\n", blue("  ℹ"))
-			fmt.Printf("    Project: %s\n", cfg.Project.Name)
-			fmt.Printf("    Property ID: %s\n", cfg.GA4.PropertyID)
-			fmt.Printf("    Tier: %s\n", config.GetTierName(tier))
-			fmt.Printf("    Conversions: %d / %d limit\n", len(cfg.Conversions), limits.Conversions)
-			fmt.Printf("    Dimensions: %d / %d limit\n", len(cfg.Dimensions), limits.CustomDimensions)
-			fmt.Printf("    Metrics: %d / %d limit\n", len(cfg.Metrics), limits.CustomMetrics)
-			fmt.Printf("    Calculated Metrics: %d\n", len(cfg.CalculatedMetrics))
-			fmt.Printf("    Audiences: %d\n", len(cfg.Audiences))
+			theme.Printf("\n%s Configuration Summary:\n", blue("  ℹ"))
+			theme.Printf("    Project: %s\n", cfg.Project.Name)
+			theme.Printf("    Property ID: %s\n", cfg.GA4.PropertyID)
+			theme.Printf("    Tier: %s\n", config.GetTierName(tier))
+			theme.Printf("    Conversions: %d / %d limit\n", len(cfg.Conversions), limits.Conversions)
+			theme.Printf("    Dimensions: %d / %d limit\n", len(cfg.Dimensions), limits.CustomDimensions)
+			theme.Printf("    Metrics: %d / %d limit\n", len(cfg.Metrics), limits.CustomMetrics)
+			theme.Printf("    Calculated Metrics: %d\n", len(cfg.CalculatedMetrics))
+			theme.Printf("    Audiences: %d\n", len(cfg.Audiences))
 			if len(cfg.Cleanup.ConversionsToRemove) > 0 || len(cfg.Cleanup.DimensionsToRemove) > 0 {
-				fmt.Printf("    Cleanup Items: %d conversions, %d dimensions\n",
+				theme.Printf("    Cleanup Items: %d conversions, %d dimensions\n",
 					len(cfg.Cleanup.ConversionsToRemove),
 					len(cfg.Cleanup.DimensionsToRemove))
 			}
-			fmt.Println()
+			theme.Println()
 		}
 
-		fmt.Printf("%s %s\n\n", green("✓"), green("Valid configuration"))
+		theme.Printf("%s %s\n\n", green("✓"), green("Valid configuration"))
 		validFiles++
 	}
 
 	// Summary
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Printf("Validation Results: %d total, %s valid, %s invalid\n",
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Printf("Validation Results: %d total, %s valid, %s invalid\n",
 		totalFiles,
 		green(fmt.Sprintf("%d", validFiles)),
 		func() string {
@@ -181,21 +183,21 @@ func executeValidate(all, verbose bool, args []string) error {
 			}
 			return fmt.Sprintf("%d", invalidFiles)
 		}())
-	fmt.Println()
+	theme.Println()
 
 	if invalidFiles > 0 {
-		fmt.Printf("%s Some files have validation errors\n", yellow("⚠"))
-		fmt.Println("\nTips for fixing YAML errors:")
-		fmt.Println("  • Use 2 spaces for indentation (not tabs)")
-		fmt.Println("  • Ensure consistent indentation throughout")
-		fmt.Println("  • Quote values with special characters")
-		fmt.Println("  • Use yamllint or a YAML validator in your editor")
-		fmt.Println()
+		theme.Printf("%s Some files have validation errors\n", yellow("⚠"))
+		theme.Println("\nTips for fixing YAML errors:")
+		theme.Println("  • Use 2 spaces for indentation (not tabs)")
+		theme.Println("  • Ensure consistent indentation throughout")
+		theme.Println("  • Quote values with special characters")
+		theme.Println("  • Use yamllint or a YAML validator in your editor")
+		theme.Println()
 		return fmt.Errorf("validation failed")
 	}
 
-	fmt.Printf("%s All configuration files are valid!\n", green("✅"))
-	fmt.Println()
+	theme.Printf("%s All configuration files are valid!\n", green("✅"))
+	theme.Println()
 
 	return nil
 }
@@ -207,7 +209,7 @@ func handleValidateAction() {
 		if err == tui.ErrBackToMenu || err.Error() == "no project selected" {
 			return
 		}
-		fmt.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
+		theme.Fprintf(os.Stderr, "Error selecting project: %v\n", err)
 		return
 	}
 
@@ -216,15 +218,15 @@ func handleValidateAction() {
 
 	if projectPath == "--all" {
 		all = true
-		fmt.Println("\n✅ Validating all configurations...")
+		theme.Println("\n✅ Validating all configurations...")
 	} else {
 		args = []string{projectPath}
-		fmt.Printf("\n✅ Validating %s...\n", projectPath)
+		theme.Printf("\n✅ Validating %s...\n", projectPath)
 	}
-	fmt.Println()
+	theme.Println()
 
 	if err := executeValidate(all, false, args); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error running validate: %v\n", err)
+		theme.Fprintf(os.Stderr, "\n❌ Error running validate: %v\n", err)
 	}
 }
 
@@ -236,7 +238,7 @@ func printYAMLError(err error, content string) {
 	// Try to extract line number from error
 	var lineNum int
 	if _, err := fmt.Sscanf(errStr, "yaml: line %d:", &lineNum); err == nil {
-		fmt.Printf("\n    Error at line %d:\n", lineNum)
+		theme.Printf("\n    Error at line %d:\n", lineNum)
 		if lineNum > 0 && lineNum <= len(lines) {
 			// Show context (2 lines before and after)
 			start := max(0, lineNum-3)
@@ -245,22 +247,22 @@ func printYAMLError(err error, content string) {
 			for i := start; i < end; i++ {
 				prefix := fmt.Sprintf("%4d | ", i+1)
 				if i+1 == lineNum {
-					fmt.Printf("    → %s%s\n", color.RedString(prefix), lines[i])
+					theme.Printf("    → %s%s\n", theme.RedString("%s", prefix), lines[i])
 				} else {
-					fmt.Printf("      %s%s\n", prefix, lines[i])
+					theme.Printf("      %s%s\n", prefix, lines[i])
 				}
 			}
 		}
-		fmt.Println()
+		theme.Println()
 	}
 
-	fmt.Printf("    Full error: %v\n", err)
-	fmt.Println()
-	fmt.Println("    Common YAML issues:")
-	fmt.Println("    • Mixed tabs and spaces (use 2 spaces for indentation)")
-	fmt.Println("    • Missing colon after key")
-	fmt.Println("    • Incorrect list syntax (should start with '- ')")
-	fmt.Println("    • Unquoted special characters (: { } [ ] , & * # ? | - < > = ! % @)")
+	theme.Printf("    Full error: %v\n", err)
+	theme.Println()
+	theme.Println("    Common YAML issues:")
+	theme.Println("    • Mixed tabs and spaces (use 2 spaces for indentation)")
+	theme.Println("    • Missing colon after key")
+	theme.Println("    • Incorrect list syntax (should start with '- ')")
+	theme.Println("    • Unquoted special characters (: { } [ ] , & * # ? | - < > = ! % @)")
 }
 
 func max(a, b int) int {
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// configVars is the repeatable --set flag: NAME=value config variables.
+var configVars []string
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&configVars, "set", nil, "Config variable for ${NAME} references, NAME=value (repeatable; overrides vars: and the environment)")
+	cobra.OnInitialize(applyConfigVars)
+}
+
+// applyConfigVars hands the --set values to the config loader once flags are
+// parsed, before any command loads a config.
+func applyConfigVars() {
+	if len(configVars) == 0 {
+		return
+	}
+	vars := make(map[string]string, len(configVars))
+	for _, s := range configVars {
+		key, value, err := config.ParseVariable(s)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		vars[key] = value
+	}
+	config.SetVariables(vars)
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+	"github.com/garbarok/ga4-manager/internal/output"
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// Per-item outcomes of ga4 verify events.
+const (
+	verifyReceiving = "receiving"
+	verifyNoData    = "no_data"
+	verifyError     = "error" // the Data API could not report on it
+)
+
+// Kinds of configured items ga4 verify events checks.
+const (
+	verifyKindConversion = "conversion"
+	verifyKindDimension  = "dimension"
+)
+
+var (
+	verifyConfig string
+	verifyDays   int
+	verifyFormat string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that the configured tracking receives data",
+}
+
+var verifyEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Report configured conversions and dimensions that receive no data",
+	Long: `Query the GA4 Data API for every conversion (key event) and custom dimension
+in the config and report those with no data over the last --days days
+(including today).
+
+ga4 setup creates the events and dimensions through the Admin API, which
+cannot tell whether the site actually sends them. A conversion with no events
+usually means the tag is missing or fires under another name; a dimension
+with no values means the events do not carry the parameter.
+
+Custom dimensions only collect values from the time they are registered, and
+a new one can take a day or two to appear in the Data API: one the API does
+not know yet is reported as an error.
+
+Exit codes:
+  0  every conversion and dimension received data
+  4  at least one received none, or could not be checked
+  1  command failed: bad flags or config
+  2  Google API error
+  3  quota exhausted
+
+Examples:
+  ga4 verify events --config configs/mysite.yaml
+  ga4 verify events --config configs/mysite.yaml --days 28 --format json`,
+	Args: cobra.NoArgs,
+	RunE: verifyEventsRunE,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.AddCommand(verifyEventsCmd)
+	verifyEventsCmd.Flags().StringVarP(&verifyConfig, "config", "c", "", "Path to configuration file (required)")
+	verifyEventsCmd.Flags().IntVarP(&verifyDays, "days", "d", 7, "Look back this many days, including today")
+	output.FormatVar(verifyEventsCmd.Flags(), &verifyFormat, "f", output.FormatTable, output.FormatJSON)
+	_ = verifyEventsCmd.MarkFlagRequired("config")
+}
+
+// verifyGA4 is the part of the GA4 client ga4 verify events reads.
+type verifyGA4 interface {
+	EventCounts(propertyID, startDate, endDate string, eventNames []string) (map[string]int64, error)
+	DimensionValueCount(propertyID, startDate, endDate, dimension string) (int64, error)
+}
+
+var verifyGA4Factory = func(cfg *config.ProjectConfig) (verifyGA4, func(), error) {
+	client, err := newGA4Client(cfg)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return client, client.Close, nil
+}
+
+// verifyEventRow is one configured conversion or dimension and the events
+// that reached it.
+type verifyEventRow struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Field  string `json:"field"`
+	Events int64  `json:"events"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// verifyEventsOutput is the --format json document.
+type verifyEventsOutput struct {
+	Project    string           `json:"project"`
+	PropertyID string           `json:"property_id"`
+	StartDate  string           `json:"start_date"`
+	EndDate    string           `json:"end_date"`
+	Results    []verifyEventRow `json:"results"`
+}
+
+func verifyEventsRunE(cmd *cobra.Command, _ []string) error {
+	return exitWith(cmd, runVerifyEvents(verifyParams{
+		ConfigPath: verifyConfig,
+		Days:       verifyDays,
+		Format:     verifyFormat,
+		GA4:        verifyGA4Factory,
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+	}))
+}
+
+type verifyParams struct {
+	ConfigPath string
+	Days       int
+	Format     string
+	GA4        func(*config.ProjectConfig) (verifyGA4, func(), error)
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+func runVerifyEvents(p verifyParams) int {
+	if p.Days < 1 {
+		return diagcmd.FailWith(p.Stderr, "invalid --days %d: must be at least 1", p.Days)
+	}
+	cfg, err := config.LoadConfig(p.ConfigPath)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to load config: %v", err)
+	}
+	propertyID := cfg.GetPropertyID()
+	if propertyID == "" {
+		return diagcmd.FailWith(p.Stderr, "no analytics.property_id in %s", p.ConfigPath)
+	}
+	if len(cfg.Conversions) == 0 && len(cfg.Dimensions) == 0 {
+		return diagcmd.FailWith(p.Stderr, "%s has no conversions or dimensions to verify", p.ConfigPath)
+	}
+
+	client, cleanup, err := p.GA4(cfg)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to create GA4 client: %v", err)
+	}
+	defer cleanup()
+
+	out := verifyEventsOutput{
+		Project:    cfg.Project.Name,
+		PropertyID: propertyID,
+		StartDate:  strconv.Itoa(p.Days-1) + "daysAgo",
+		EndDate:    "today",
+	}
+	out.Results, err = verifyEvents(client, cfg, out.StartDate, out.EndDate)
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "%v", err)
+	}
+
+	if p.Format == output.FormatJSON {
+		err = output.JSON(p.Stdout, out)
+	} else {
+		err = displayVerifyEvents(p.Stdout, out, p.Days)
+	}
+	if err != nil {
+		return diagcmd.FailWith(p.Stderr, "failed to render output: %v", err)
+	}
+
+	for _, r := range out.Results {
+		if r.Status != verifyReceiving {
+			return diagcmd.ExitIssues
+		}
+	}
+	return diagcmd.ExitClean
+}
+
+// verifyEvents counts the events of every conversion in one report and the
+// values of each dimension in a report of its own. A dimension the Data API
+// rejects is reported as an error row rather than failing the run.
+func verifyEvents(client verifyGA4, cfg *config.ProjectConfig, start, end string) ([]verifyEventRow, error) {
+	names := make([]string, len(cfg.Conversions))
+	for i, c := range cfg.Conversions {
+		names[i] = c.Name
+	}
+	counts, err := client.EventCounts(cfg.GetPropertyID(), start, end, names)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]verifyEventRow, 0, len(cfg.Conversions)+len(cfg.Dimensions))
+	for _, name := range names {
+		rows = append(rows, verifyRow(verifyKindConversion, name, "eventName", counts[name], nil))
+	}
+	for _, d := range cfg.Dimensions {
+		field := ga4.CustomDimensionAPIName(d.Scope, d.ParameterName)
+		n, err := client.DimensionValueCount(cfg.GetPropertyID(), start, end, field)
+		rows = append(rows, verifyRow(verifyKindDimension, d.ParameterName, field, n, err))
+	}
+	return rows, nil
+}
+
+func verifyRow(kind, name, field string, events int64, err error) verifyEventRow {
+	r := verifyEventRow{Kind: kind, Name: name, Field: field, Events: events, Status: verifyReceiving}
+	switch {
+	case err != nil:
+		r.Status, r.Error = verifyError, err.Error()
+	case events == 0:
+		r.Status = verifyNoData
+	}
+	return r
+}
+
+func displayVerifyEvents(w io.Writer, out verifyEventsOutput, days int) error {
+	theme.Fprintf(w, "%s\n\n", theme.CyanString("═══ Tracking check: %s (property %s), last %d day(s) ═══", out.Project, out.PropertyID, days))
+	if err := output.Render(theme.NewWriter(w), output.FormatTable,
+		[]string{"Kind", "Name", "Field", "Events", "Status"},
+		out.Results, func(r verifyEventRow) []string {
+			return []string{r.Kind, r.Name, r.Field, strconv.FormatInt(r.Events, 10), verifyStatusCell(r)}
+		}); err != nil {
+		return err
+	}
+	theme.Fprintln(w)
+
+	var noData, failed int
+	for _, r := range out.Results {
+		switch r.Status {
+		case verifyNoData:
+			noData++
+		case verifyError:
+			failed++
+			theme.Fprintf(w, "%s\n", theme.RedString("✗ %s: %s", r.Field, r.Error))
+		}
+	}
+	if noData > 0 {
+		theme.Fprintf(w, "%s\n", theme.YellowString("⚠ %d configured event(s) or parameter(s) received no data: check that the site's tags send them under these names.", noData))
+	}
+	if noData == 0 && failed == 0 {
+		theme.Fprintf(w, "%s\n", theme.GreenString("✓ Every conversion and custom dimension is receiving data."))
+	}
+	return nil
+}
+
+func verifyStatusCell(r verifyEventRow) string {
+	switch r.Status {
+	case verifyReceiving:
+		return theme.GreenString("%s", r.Status)
+	case verifyNoData:
+		return theme.RedString("%s", r.Status)
+	default:
+		return theme.YellowString("%s", r.Status)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagcmd"
+)
+
+// fakeVerifyGA4 answers event counts from events and dimension counts from
+// values, failing dimensions listed in errs.
+type fakeVerifyGA4 struct {
+	events map[string]int64
+	values map[string]int64
+	errs   map[string]error
+	start  string
+}
+
+func (f *fakeVerifyGA4) EventCounts(_, startDate, _ string, eventNames []string) (map[string]int64, error) {
+	f.start = startDate
+	counts := map[string]int64{}
+	for _, name := range eventNames {
+		counts[name] = f.events[name]
+	}
+	return counts, nil
+}
+
+func (f *fakeVerifyGA4) DimensionValueCount(_, _, _, dimension string) (int64, error) {
+	return f.values[dimension], f.errs[dimension]
+}
+
+const verifyTestConfig = `project:
+  name: Example
+analytics:
+  property_id: "123456789"
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+  - name: generate_lead
+    counting_method: ONCE_PER_EVENT
+dimensions:
+  - parameter: plan_type
+    display_name: Plan Type
+    scope: EVENT
+  - parameter: customer_tier
+    display_name: Customer Tier
+    scope: USER
+`
+
+func TestRunVerifyEvents_ReportsGapsAndExitsFour(t *testing.T) {
+	fake := &fakeVerifyGA4{
+		events: map[string]int64{"purchase": 12},
+		values: map[string]int64{"customEvent:plan_type": 40},
+		errs:   map[string]error{"customUser:customer_tier": errors.New("Field customUser:customer_tier is not a valid dimension")},
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runVerifyEvents(verifyParams{
+		ConfigPath: writeSummaryConfig(t, verifyTestConfig),
+		Days:       7,
+		Format:     "json",
+		GA4:        func(*config.ProjectConfig) (verifyGA4, func(), error) { return fake, func() {}, nil },
+		Stdout:     stdout,
+		Stderr:     stderr,
+	})
+	require.Equal(t, diagcmd.ExitIssues, code, stderr.String())
+	assert.Equal(t, "6daysAgo", fake.start, "seven days including today")
+
+	var got verifyEventsOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+	assert.Equal(t, "123456789", got.PropertyID)
+	assert.Equal(t, []verifyEventRow{
+		{Kind: verifyKindConversion, Name: "purchase", Field: "eventName", Events: 12, Status: verifyReceiving},
+		{Kind: verifyKindConversion, Name: "generate_lead", Field: "eventName", Status: verifyNoData},
+		{Kind: verifyKindDimension, Name: "plan_type", Field: "customEvent:plan_type", Events: 40, Status: verifyReceiving},
+		{Kind: verifyKindDimension, Name: "customer_tier", Field: "customUser:customer_tier", Status: verifyError,
+			Error: "Field customUser:customer_tier is not a valid dimension"},
+	}, got.Results)
+}
+
+func TestRunVerifyEvents_CleanWhenEverythingReceivesData(t *testing.T) {
+	fake := &fakeVerifyGA4{
+		events: map[string]int64{"purchase": 12, "generate_lead": 3},
+		values: map[string]int64{"customEvent:plan_type": 40, "customUser:customer_tier": 9},
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	code := runVerifyEvents(verifyParams{
+		ConfigPath: writeSummaryConfig(t, verifyTestConfig),
+		Days:       7,
+		Format:     "table",
+		GA4:        func(*config.ProjectConfig) (verifyGA4, func(), error) { return fake, func() {}, nil },
+		Stdout:     stdout,
+		Stderr:     stderr,
+	})
+	require.Equal(t, diagcmd.ExitClean, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Every conversion and custom dimension is receiving data")
+}
+
+func TestRunVerifyEvents_RejectsBadDays(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	code := runVerifyEvents(verifyParams{ConfigPath: "unused.yaml", Days: 0, Stderr: stderr})
+	assert.Equal(t, diagcmd.ExitFailure, code)
+	assert.Contains(t, stderr.String(), "invalid --days 0")
+}
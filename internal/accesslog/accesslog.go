@@ -0,0 +1,161 @@
+// Package accesslog reads web server access logs in the Common and Combined
+// Log Formats and picks out Google's crawlers, so crawl activity the Search
+// Console API does not expose can be measured from the server side.
+package accesslog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Log formats.
+const (
+	// FormatCommon is NCSA Common Log Format: host ident user [time]
+	// "request" status bytes.
+	FormatCommon = "common"
+	// FormatCombined adds "referer" "user-agent"; Apache and nginx default.
+	FormatCombined = "combined"
+)
+
+// timeLayout is the %t timestamp, e.g. 10/Oct/2026:13:55:36 -0700.
+const timeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// maxLineBytes bounds one log line; longer lines are skipped.
+const maxLineBytes = 64 << 10
+
+var (
+	commonLine   = regexp.MustCompile(`^(\S+) \S+ \S+ \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-)`)
+	combinedLine = regexp.MustCompile(`^(\S+) \S+ \S+ \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-) "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)"`)
+)
+
+// Entry is one request from the log.
+type Entry struct {
+	Host      string
+	Time      time.Time
+	Method    string
+	Path      string
+	Status    int
+	Bytes     int64
+	Referer   string
+	UserAgent string
+}
+
+// ValidFormat reports whether format is a supported log format.
+func ValidFormat(format string) bool {
+	return format == FormatCommon || format == FormatCombined
+}
+
+// ParseLine parses one log line.
+func ParseLine(line, format string) (Entry, error) {
+	re := commonLine
+	if format == FormatCombined {
+		re = combinedLine
+	}
+	m := re.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, fmt.Errorf("not a %s log line", format)
+	}
+	t, err := time.Parse(timeLayout, m[2])
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid timestamp %q", m[2])
+	}
+	e := Entry{Host: m[1], Time: t}
+	e.Status, _ = strconv.Atoi(m[4])
+	if m[5] != "-" {
+		e.Bytes, _ = strconv.ParseInt(m[5], 10, 64)
+	}
+	// "GET /path HTTP/1.1"; a malformed request line keeps what it has.
+	fields := strings.Fields(unescape(m[3]))
+	if len(fields) > 0 {
+		e.Method = fields[0]
+	}
+	if len(fields) > 1 {
+		e.Path = fields[1]
+	}
+	if format == FormatCombined {
+		e.Referer, e.UserAgent = unescape(m[6]), unescape(m[7])
+		if e.Referer == "-" {
+			e.Referer = ""
+		}
+	}
+	return e, nil
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+// Scan parses every line of r and calls fn for each entry. Lines that do
+// not parse are counted in skipped, not returned as errors; err is only for
+// a failed read.
+func Scan(r io.Reader, format string, fn func(Entry)) (lines, skipped int, err error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLineBytes)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines++
+		e, perr := ParseLine(line, format)
+		if perr != nil {
+			skipped++
+			continue
+		}
+		fn(e)
+	}
+	return lines, skipped, sc.Err()
+}
+
+// Googlebot types, named as in the Search Console Crawl Stats report.
+const (
+	BotSmartphone = "Smartphone"
+	BotDesktop    = "Desktop"
+	BotImage      = "Image"
+	BotVideo      = "Video"
+	BotNews       = "News"
+	BotAdsBot     = "AdsBot"
+	BotStoreBot   = "StoreBot"
+	BotOther      = "Other agent type"
+)
+
+// googleAgents maps user-agent tokens of Google's crawlers to their type,
+// most specific first.
+var googleAgents = []struct{ token, kind string }{
+	{"Googlebot-Image", BotImage},
+	{"Googlebot-Video", BotVideo},
+	{"Googlebot-News", BotNews},
+	{"AdsBot-Google", BotAdsBot},
+	{"Storebot-Google", BotStoreBot},
+	{"Google-InspectionTool", BotOther},
+	{"GoogleOther", BotOther},
+	{"Mediapartners-Google", BotOther},
+	{"Googlebot", ""},
+}
+
+// GooglebotType returns the crawler type of a Google user agent, or "" when
+// ua is not one. The user agent alone can be spoofed; VerifyGooglebot checks
+// the client address.
+func GooglebotType(ua string) string {
+	for _, a := range googleAgents {
+		if !strings.Contains(ua, a.token) {
+			continue
+		}
+		if a.kind != "" {
+			return a.kind
+		}
+		if strings.Contains(ua, "Mobile") {
+			return BotSmartphone
+		}
+		return BotDesktop
+	}
+	return ""
+}
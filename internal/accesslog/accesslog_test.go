@@ -0,0 +1,196 @@
+package accesslog
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	uaSmartphone = "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	uaDesktop    = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	uaBrowser    = "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0"
+)
+
+func TestParseLine(t *testing.T) {
+	e, err := ParseLine(`66.249.66.1 - - [10/Oct/2026:13:55:36 -0700] "GET /blog/post?ref=a HTTP/1.1" 200 2326 "-" "`+uaDesktop+`"`, FormatCombined)
+	require.NoError(t, err)
+	assert.Equal(t, "66.249.66.1", e.Host)
+	assert.Equal(t, time.Date(2026, 10, 10, 20, 55, 36, 0, time.UTC), e.Time.UTC())
+	assert.Equal(t, "GET", e.Method)
+	assert.Equal(t, "/blog/post?ref=a", e.Path)
+	assert.Equal(t, 200, e.Status)
+	assert.Equal(t, int64(2326), e.Bytes)
+	assert.Empty(t, e.Referer)
+	assert.Equal(t, uaDesktop, e.UserAgent)
+
+	e, err = ParseLine(`10.0.0.1 - frank [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.0" 304 -`, FormatCommon)
+	require.NoError(t, err)
+	assert.Equal(t, 304, e.Status)
+	assert.Zero(t, e.Bytes)
+	assert.Empty(t, e.UserAgent)
+
+	e, err = ParseLine(`10.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET /a\"b HTTP/1.1" 404 12 "https://example.com/" "x \"quoted\""`, FormatCombined)
+	require.NoError(t, err)
+	assert.Equal(t, `/a"b`, e.Path)
+	assert.Equal(t, `x "quoted"`, e.UserAgent)
+
+	_, err = ParseLine(`10.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.1" 200 12`, FormatCombined)
+	assert.Error(t, err, "combined needs referer and user agent")
+}
+
+func TestScan(t *testing.T) {
+	log := strings.Join([]string{
+		`1.1.1.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.1" 200 10`,
+		``,
+		`garbage`,
+		`1.1.1.1 - - [10/Oct/2026:13:55:37 +0000] "GET /a HTTP/1.1" 404 10`,
+	}, "\n")
+	var paths []string
+	lines, skipped, err := Scan(strings.NewReader(log), FormatCommon, func(e Entry) { paths = append(paths, e.Path) })
+	require.NoError(t, err)
+	assert.Equal(t, 3, lines)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, []string{"/", "/a"}, paths)
+}
+
+func TestGooglebotType(t *testing.T) {
+	for ua, want := range map[string]string{
+		uaSmartphone:          BotSmartphone,
+		uaDesktop:             BotDesktop,
+		"Googlebot-Image/1.0": BotImage,
+		"AdsBot-Google (+http://www.google.com/adsbot.html)": BotAdsBot,
+		"Mozilla/5.0 (compatible; GoogleOther)":              BotOther,
+		uaBrowser:                                            "",
+	} {
+		assert.Equal(t, want, GooglebotType(ua), ua)
+	}
+}
+
+func TestCrawlStatsBuilder(t *testing.T) {
+	day := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	b := NewCrawlStatsBuilder()
+	for _, e := range []Entry{
+		{Time: day, Path: "/", Status: 200, Bytes: 1000, UserAgent: uaSmartphone},
+		{Time: day, Path: "/app.js", Status: 200, Bytes: 500, UserAgent: uaSmartphone},
+		{Time: day.Add(24 * time.Hour), Path: "/", Status: 304, UserAgent: uaDesktop},
+		{Time: day.Add(24 * time.Hour), Path: "/gone", Status: 404, Bytes: 100, UserAgent: uaSmartphone},
+	} {
+		assert.True(t, b.Add(e))
+	}
+	assert.False(t, b.Add(Entry{Time: day, Path: "/", Status: 200, UserAgent: uaBrowser}))
+
+	s := b.Stats()
+	assert.Equal(t, 4, s.Requests)
+	assert.Equal(t, int64(1600), s.Bytes)
+	assert.InDelta(t, 400, s.AvgBytes, 1e-9)
+	assert.Equal(t, []Day{{Date: "2026-10-10", Requests: 2, Bytes: 1500}, {Date: "2026-10-11", Requests: 2, Bytes: 100}}, s.Days)
+	assert.Equal(t, Count{Key: "2xx", Requests: 2, Share: 0.5}, s.Responses[0])
+	assert.Equal(t, []Count{{Key: PurposeDiscovery, Requests: 3, Share: 0.75}, {Key: PurposeRefresh, Requests: 1, Share: 0.25}}, s.Purposes)
+	assert.Equal(t, Count{Key: FileHTML, Requests: 3, Share: 0.75}, s.FileTypes[0])
+	assert.Equal(t, Count{Key: BotSmartphone, Requests: 3, Share: 0.75}, s.BotTypes[0])
+}
+
+func TestFileType(t *testing.T) {
+	for p, want := range map[string]string{
+		"/":                FileHTML,
+		"/blog/post":       FileHTML,
+		"/index.html?x=1":  FileHTML,
+		"/img/a.WEBP":      FileImage,
+		"/static/app.js":   FileJavaScript,
+		"/feed.json":       FileJSON,
+		"/robots.txt":      FileOther,
+		"/docs/manual.pdf": FilePDF,
+		"/styles/site.css": FileCSS,
+	} {
+		assert.Equal(t, want, FileType(p), p)
+	}
+}
+
+func TestBudgetBuilder(t *testing.T) {
+	day := time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC)
+	b := NewBudgetBuilder(1)
+	for _, e := range []Entry{
+		{Time: day, Path: "/blog/a", Status: 200},
+		{Time: day, Path: "/blog/a?utm_source=x", Status: 200},
+		{Time: day.Add(24 * time.Hour), Path: "/blog/gone", Status: 404},
+		{Time: day.Add(24 * time.Hour), Path: "/blog/gone", Status: 404},
+		{Time: day.Add(48 * time.Hour), Path: "/shop/?sort=price", Status: 301},
+		{Time: day.Add(48 * time.Hour), Path: "/", Status: 200},
+	} {
+		b.Add(e)
+	}
+
+	r := b.Report(10)
+	assert.Equal(t, 6, r.Requests)
+	assert.Equal(t, 3, r.Days)
+	require.Len(t, r.Directories, 3)
+	blog := r.Directories[0]
+	assert.Equal(t, "/blog/", blog.Directory)
+	assert.Equal(t, 4, blog.Requests)
+	assert.Equal(t, 3, blog.URLs)
+	assert.InDelta(t, 4.0/3, blog.PerDay, 1e-9)
+	assert.Equal(t, 2, blog.NotFound)
+	assert.Equal(t, 1, blog.Parameterized)
+	assert.Equal(t, 2, blog.ClientErrors)
+	assert.Equal(t, day.Add(24*time.Hour), blog.LastCrawl)
+
+	assert.Equal(t, 4, r.WastedRequests)
+	assert.Equal(t, []Count{{Key: WasteNotFound, Requests: 2, Share: 2.0 / 6}, {Key: WasteParameters, Requests: 2, Share: 2.0 / 6}}, r.Wasted)
+	assert.Equal(t, WastedURL{Path: "/blog/gone", Reason: WasteNotFound, Requests: 2, LastStatus: 404}, r.TopWasted[0])
+	assert.Len(t, b.Report(1).TopWasted, 1)
+}
+
+// fakeResolver answers from fixed PTR and A records.
+type fakeResolver struct {
+	ptr   map[string][]string
+	hosts map[string][]string
+}
+
+func (f fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := f.ptr[addr]; ok {
+		return names, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func (f fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestVerifyGooglebot(t *testing.T) {
+	r := fakeResolver{
+		ptr: map[string][]string{
+			"66.249.66.1": {"crawl-66-249-66-1.googlebot.com."},
+			"1.2.3.4":     {"crawl-1-2-3-4.googlebot.com.evil.net."},
+			"5.6.7.8":     {"fake.googlebot.com."},
+		},
+		hosts: map[string][]string{
+			"crawl-66-249-66-1.googlebot.com": {"66.249.66.1"},
+			"fake.googlebot.com":              {"66.249.66.2"},
+		},
+	}
+	ctx := context.Background()
+	for ip, want := range map[string]bool{
+		"66.249.66.1": true,
+		"1.2.3.4":     false, // PTR outside Google's domains
+		"5.6.7.8":     false, // PTR does not resolve back
+		"9.9.9.9":     false, // no PTR record
+	} {
+		ok, err := VerifyGooglebot(ctx, r, ip)
+		require.NoError(t, err, ip)
+		assert.Equal(t, want, ok, ip)
+	}
+
+	verified, failed := VerifyHosts(ctx, r, []string{"66.249.66.1", "9.9.9.9"}, 2)
+	assert.Zero(t, failed)
+	assert.Equal(t, map[string]bool{"66.249.66.1": true, "9.9.9.9": false}, verified)
+}
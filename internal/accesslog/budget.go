@@ -0,0 +1,202 @@
+package accesslog
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+)
+
+// Reasons a crawl request is counted as wasted budget. A request is counted
+// once, under the first reason that applies.
+const (
+	WasteNotFound   = "Not found (404/410)"
+	WasteParameters = "Parameterized URL"
+)
+
+// Directory is the crawl activity under one path prefix.
+type Directory struct {
+	Directory string  `json:"directory"`
+	Requests  int     `json:"requests"`
+	Share     float64 `json:"share"`
+	// URLs is the number of distinct paths, query strings included.
+	URLs int `json:"urls"`
+	// PerDay is Requests over the days the logs span.
+	PerDay        float64   `json:"per_day"`
+	Success       int       `json:"success"`
+	Redirects     int       `json:"redirects"`
+	ClientErrors  int       `json:"client_errors"`
+	ServerErrors  int       `json:"server_errors"`
+	NotFound      int       `json:"not_found"`
+	Parameterized int       `json:"parameterized"`
+	LastCrawl     time.Time `json:"last_crawl"`
+}
+
+// WastedURL is one URL crawlers spent wasted requests on.
+type WastedURL struct {
+	Path       string `json:"path"`
+	Reason     string `json:"reason"`
+	Requests   int    `json:"requests"`
+	LastStatus int    `json:"last_status"`
+}
+
+// BudgetReport is where crawl requests went, by directory and status, and
+// how many were wasted.
+type BudgetReport struct {
+	Requests int       `json:"requests"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// Days is the number of calendar days from From to To.
+	Days           int         `json:"days"`
+	Directories    []Directory `json:"directories"`
+	Statuses       []Count     `json:"statuses"`
+	Wasted         []Count     `json:"wasted"`
+	WastedRequests int         `json:"wasted_requests"`
+	WastedShare    float64     `json:"wasted_share"`
+	// TopWasted lists the URLs with the most wasted requests.
+	TopWasted []WastedURL `json:"top_wasted"`
+}
+
+// BudgetBuilder accumulates crawl requests for a BudgetReport. It counts
+// every entry it is given; filter to crawler requests first.
+type BudgetBuilder struct {
+	depth    int
+	report   BudgetReport
+	dirs     map[string]*Directory
+	dirURLs  map[string]map[string]bool
+	statuses map[string]int
+	wasted   map[string]int
+	urls     map[string]*WastedURL
+}
+
+// NewBudgetBuilder groups paths into directories depth segments deep, as
+// sampling.GroupOf does.
+func NewBudgetBuilder(depth int) *BudgetBuilder {
+	if depth < 1 {
+		depth = sampling.DefaultDepth
+	}
+	return &BudgetBuilder{
+		depth:    depth,
+		dirs:     map[string]*Directory{},
+		dirURLs:  map[string]map[string]bool{},
+		statuses: map[string]int{},
+		wasted:   map[string]int{},
+		urls:     map[string]*WastedURL{},
+	}
+}
+
+// Add counts e.
+func (b *BudgetBuilder) Add(e Entry) {
+	r := &b.report
+	r.Requests++
+	if r.From.IsZero() || e.Time.Before(r.From) {
+		r.From = e.Time
+	}
+	if e.Time.After(r.To) {
+		r.To = e.Time
+	}
+
+	p, query := e.Path, ""
+	if u, err := url.Parse(e.Path); err == nil {
+		p, query = u.Path, u.RawQuery
+	}
+	name := sampling.GroupOf(p, b.depth)
+	d, ok := b.dirs[name]
+	if !ok {
+		d = &Directory{Directory: name}
+		b.dirs[name] = d
+		b.dirURLs[name] = map[string]bool{}
+	}
+	d.Requests++
+	b.dirURLs[name][e.Path] = true
+	if e.Time.After(d.LastCrawl) {
+		d.LastCrawl = e.Time
+	}
+	switch e.Status / 100 {
+	case 2:
+		d.Success++
+	case 3:
+		d.Redirects++
+	case 4:
+		d.ClientErrors++
+	case 5:
+		d.ServerErrors++
+	}
+	b.statuses[strconv.Itoa(e.Status)]++
+
+	reason := ""
+	switch {
+	case e.Status == http.StatusNotFound || e.Status == http.StatusGone:
+		reason = WasteNotFound
+		d.NotFound++
+	case query != "":
+		reason = WasteParameters
+		d.Parameterized++
+	default:
+		return
+	}
+	b.wasted[reason]++
+	w, ok := b.urls[e.Path]
+	if !ok {
+		w = &WastedURL{Path: e.Path}
+		b.urls[e.Path] = w
+	}
+	w.Requests++
+	w.Reason, w.LastStatus = reason, e.Status
+}
+
+// Report returns the accumulated report with the top wasted URLs. Directories
+// and breakdowns are by requests descending.
+func (b *BudgetBuilder) Report(top int) BudgetReport {
+	r := b.report
+	if r.Requests > 0 {
+		from := time.Date(r.From.Year(), r.From.Month(), r.From.Day(), 0, 0, 0, 0, time.UTC)
+		to := time.Date(r.To.Year(), r.To.Month(), r.To.Day(), 0, 0, 0, 0, time.UTC)
+		r.Days = int(to.Sub(from).Hours()/24) + 1
+	}
+
+	r.Directories = make([]Directory, 0, len(b.dirs))
+	for name, d := range b.dirs {
+		d.URLs = len(b.dirURLs[name])
+		if r.Requests > 0 {
+			d.Share = float64(d.Requests) / float64(r.Requests)
+			d.PerDay = float64(d.Requests) / float64(r.Days)
+		}
+		r.Directories = append(r.Directories, *d)
+	}
+	sort.Slice(r.Directories, func(i, j int) bool {
+		a, c := r.Directories[i], r.Directories[j]
+		if a.Requests != c.Requests {
+			return a.Requests > c.Requests
+		}
+		return a.Directory < c.Directory
+	})
+
+	r.Statuses = counts(b.statuses, r.Requests)
+	r.Wasted = counts(b.wasted, r.Requests)
+	for _, c := range r.Wasted {
+		r.WastedRequests += c.Requests
+	}
+	if r.Requests > 0 {
+		r.WastedShare = float64(r.WastedRequests) / float64(r.Requests)
+	}
+
+	r.TopWasted = make([]WastedURL, 0, len(b.urls))
+	for _, w := range b.urls {
+		r.TopWasted = append(r.TopWasted, *w)
+	}
+	sort.Slice(r.TopWasted, func(i, j int) bool {
+		a, c := r.TopWasted[i], r.TopWasted[j]
+		if a.Requests != c.Requests {
+			return a.Requests > c.Requests
+		}
+		return a.Path < c.Path
+	})
+	if top >= 0 && len(r.TopWasted) > top {
+		r.TopWasted = r.TopWasted[:top]
+	}
+	return r
+}
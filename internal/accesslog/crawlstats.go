@@ -0,0 +1,186 @@
+package accesslog
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fetch purposes, as in the Crawl Stats report. Search Console knows
+// whether Google had crawled a URL before; a log only knows its own window,
+// so the first hit on each URL in the window counts as discovery.
+const (
+	PurposeDiscovery = "Discovery"
+	PurposeRefresh   = "Refresh"
+)
+
+// File types, as in the Crawl Stats report.
+const (
+	FileHTML       = "HTML"
+	FileImage      = "Image"
+	FileJavaScript = "JavaScript"
+	FileCSS        = "CSS"
+	FileJSON       = "JSON"
+	FilePDF        = "PDF"
+	FileOther      = "Other file type"
+)
+
+// Count is one breakdown bucket.
+type Count struct {
+	Key      string  `json:"key"`
+	Requests int     `json:"requests"`
+	Share    float64 `json:"share"`
+}
+
+// Day is one day's crawl volume.
+type Day struct {
+	Date     string `json:"date"`
+	Requests int    `json:"requests"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// CrawlStats mirrors the Search Console Crawl Stats report for the
+// requests fed to it.
+type CrawlStats struct {
+	Requests int   `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+	// AvgBytes is the mean response size.
+	AvgBytes float64 `json:"avg_bytes"`
+	Days     []Day   `json:"days"`
+	// Responses is by status class (2xx...) and Statuses by exact code.
+	Responses []Count `json:"responses"`
+	Statuses  []Count `json:"statuses"`
+	FileTypes []Count `json:"file_types"`
+	Purposes  []Count `json:"purposes"`
+	BotTypes  []Count `json:"bot_types"`
+}
+
+// CrawlStatsBuilder accumulates Googlebot requests. Entries must be fed in
+// time order for fetch purposes to be right; the zero value is not usable,
+// call NewCrawlStatsBuilder.
+type CrawlStatsBuilder struct {
+	stats     CrawlStats
+	days      map[string]*Day
+	responses map[string]int
+	statuses  map[string]int
+	files     map[string]int
+	purposes  map[string]int
+	bots      map[string]int
+	seen      map[string]bool
+}
+
+// NewCrawlStatsBuilder returns an empty builder.
+func NewCrawlStatsBuilder() *CrawlStatsBuilder {
+	return &CrawlStatsBuilder{
+		days:      map[string]*Day{},
+		responses: map[string]int{},
+		statuses:  map[string]int{},
+		files:     map[string]int{},
+		purposes:  map[string]int{},
+		bots:      map[string]int{},
+		seen:      map[string]bool{},
+	}
+}
+
+// Add counts e when it is a Googlebot request and reports whether it was.
+func (b *CrawlStatsBuilder) Add(e Entry) bool {
+	bot := GooglebotType(e.UserAgent)
+	if bot == "" {
+		return false
+	}
+	b.stats.Requests++
+	b.stats.Bytes += e.Bytes
+
+	date := e.Time.Format("2006-01-02")
+	d, ok := b.days[date]
+	if !ok {
+		d = &Day{Date: date}
+		b.days[date] = d
+	}
+	d.Requests++
+	d.Bytes += e.Bytes
+
+	b.responses[statusClass(e.Status)]++
+	b.statuses[strconv.Itoa(e.Status)]++
+	b.files[FileType(e.Path)]++
+	b.bots[bot]++
+	if b.seen[e.Path] {
+		b.purposes[PurposeRefresh]++
+	} else {
+		b.seen[e.Path] = true
+		b.purposes[PurposeDiscovery]++
+	}
+	return true
+}
+
+// Stats returns the accumulated report. Days are in date order; breakdowns
+// by requests descending.
+func (b *CrawlStatsBuilder) Stats() CrawlStats {
+	s := b.stats
+	if s.Requests > 0 {
+		s.AvgBytes = float64(s.Bytes) / float64(s.Requests)
+	}
+	s.Days = make([]Day, 0, len(b.days))
+	for _, d := range b.days {
+		s.Days = append(s.Days, *d)
+	}
+	sort.Slice(s.Days, func(i, j int) bool { return s.Days[i].Date < s.Days[j].Date })
+	s.Responses = counts(b.responses, s.Requests)
+	s.Statuses = counts(b.statuses, s.Requests)
+	s.FileTypes = counts(b.files, s.Requests)
+	s.Purposes = counts(b.purposes, s.Requests)
+	s.BotTypes = counts(b.bots, s.Requests)
+	return s
+}
+
+func counts(m map[string]int, total int) []Count {
+	out := make([]Count, 0, len(m))
+	for k, n := range m {
+		c := Count{Key: k, Requests: n}
+		if total > 0 {
+			c.Share = float64(n) / float64(total)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Requests != out[j].Requests {
+			return out[i].Requests > out[j].Requests
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// FileType classifies a request path by extension; extensionless paths are
+// pages.
+func FileType(rawPath string) string {
+	p := rawPath
+	if u, err := url.Parse(rawPath); err == nil {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case "", ".html", ".htm", ".php", ".asp", ".aspx":
+		return FileHTML
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif", ".svg", ".ico":
+		return FileImage
+	case ".js", ".mjs":
+		return FileJavaScript
+	case ".css":
+		return FileCSS
+	case ".json":
+		return FileJSON
+	case ".pdf":
+		return FilePDF
+	default:
+		return FileOther
+	}
+}
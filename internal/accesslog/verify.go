@@ -0,0 +1,103 @@
+package accesslog
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+)
+
+// googleHostSuffixes are the reverse DNS domains of Google's crawlers and
+// fetchers.
+var googleHostSuffixes = []string{".googlebot.com", ".google.com", ".googleusercontent.com"}
+
+// Resolver is the DNS lookups verification needs; *net.Resolver satisfies it.
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// VerifyGooglebot reports whether ip is one of Google's crawlers, the way
+// Google documents it: the PTR record must be a host under googlebot.com,
+// google.com or googleusercontent.com, and that host must resolve back to
+// ip. An address without a PTR record is not an error, just unverified.
+func VerifyGooglebot(ctx context.Context, r Resolver, ip string) (bool, error) {
+	names, err := r.LookupAddr(ctx, ip)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, name := range names {
+		host := strings.TrimSuffix(strings.ToLower(name), ".")
+		if !hasGoogleSuffix(host) {
+			continue
+		}
+		addrs, err := r.LookupHost(ctx, host)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		for _, a := range addrs {
+			if sameIP(a, ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// VerifyHosts runs VerifyGooglebot for each distinct host with up to
+// concurrency lookups in flight. Hosts whose lookup failed are unverified
+// and counted in failed.
+func VerifyHosts(ctx context.Context, r Resolver, hosts []string, concurrency int) (verified map[string]bool, failed int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	verified = make(map[string]bool, len(hosts))
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, h := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(h string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ok, err := VerifyGooglebot(ctx, r, h)
+			mu.Lock()
+			defer mu.Unlock()
+			verified[h] = ok
+			if err != nil {
+				failed++
+			}
+		}(h)
+	}
+	wg.Wait()
+	return verified, failed
+}
+
+func hasGoogleSuffix(host string) bool {
+	for _, s := range googleHostSuffixes {
+		if strings.HasSuffix(host, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	return ipA != nil && ipA.Equal(ipB)
+}
@@ -0,0 +1,165 @@
+// Package algoupdates is a calendar of confirmed Google Search ranking
+// updates, from the Search Status Dashboard, so reports can tell a traffic
+// change that overlaps a core or spam update from one of the site's own.
+//
+// A copy of the calendar ships with the binary. `ga4 marker updates --fetch`
+// downloads a newer one into the state directory, where it takes precedence.
+package algoupdates
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/markers"
+)
+
+// Source is the Marker source of algorithm updates.
+const Source = "google"
+
+// FileName is the downloaded calendar's name in the state directory.
+const FileName = "algorithm-updates.json"
+
+// DefaultURL serves the calendar as kept in this repository.
+const DefaultURL = "https://raw.githubusercontent.com/garbarok/ga4-manager/main/internal/algoupdates/updates.json"
+
+// maxSize bounds a downloaded calendar.
+const maxSize = 1 << 20
+
+//go:embed updates.json
+var builtin []byte
+
+// Update is one rollout, from its first to its last day.
+type Update struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Calendar is the dataset file.
+type Calendar struct {
+	Updated string   `json:"updated"`
+	Source  string   `json:"source,omitempty"`
+	Updates []Update `json:"updates"`
+}
+
+// Marker returns u as a marker, the way reports show it.
+func (u Update) Marker() markers.Marker {
+	return markers.Marker{
+		ID:     strings.ReplaceAll(strings.ToLower(u.Name), " ", "-"),
+		Date:   u.Start,
+		End:    u.End,
+		Label:  u.Name,
+		Source: Source,
+	}
+}
+
+// Markers returns the calendar's updates as markers.
+func (c Calendar) Markers() []markers.Marker {
+	ms := make([]markers.Marker, len(c.Updates))
+	for i, u := range c.Updates {
+		ms[i] = u.Marker()
+	}
+	return ms
+}
+
+// Parse decodes and checks a calendar.
+func Parse(data []byte) (Calendar, error) {
+	var c Calendar
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Calendar{}, fmt.Errorf("invalid algorithm update calendar: %w", err)
+	}
+	if len(c.Updates) == 0 {
+		return Calendar{}, errors.New("invalid algorithm update calendar: no updates")
+	}
+	for _, u := range c.Updates {
+		if u.End == "" {
+			return Calendar{}, fmt.Errorf("invalid algorithm update calendar: %q has no end date", u.Name)
+		}
+		if err := u.Marker().Validate(); err != nil {
+			return Calendar{}, fmt.Errorf("invalid algorithm update calendar: %q: %w", u.Name, err)
+		}
+	}
+	return c, nil
+}
+
+// Builtin returns the calendar shipped with this build.
+func Builtin() Calendar {
+	c, err := Parse(builtin)
+	if err != nil {
+		panic(err) // covered by the package tests
+	}
+	return c
+}
+
+// Load returns the calendar downloaded into stateDir, or the built-in one
+// when none has been. path is the file read, empty for the built-in one.
+func Load(stateDir string) (c Calendar, path string, err error) {
+	path = filepath.Join(stateDir, FileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Builtin(), "", nil
+	}
+	if err != nil {
+		return Calendar{}, "", err
+	}
+	c, err = Parse(data)
+	if err != nil {
+		return Calendar{}, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return c, path, nil
+}
+
+// Fetch downloads the calendar at url and, when it parses, saves it to
+// stateDir for Load.
+func Fetch(ctx context.Context, client *http.Client, url, stateDir string) (Calendar, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Calendar{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Calendar{}, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return Calendar{}, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return Calendar{}, err
+	}
+	tmp, err := os.CreateTemp(stateDir, FileName+".tmp-*")
+	if err != nil {
+		return Calendar{}, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return Calendar{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return Calendar{}, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(stateDir, FileName)); err != nil {
+		return Calendar{}, err
+	}
+	return c, nil
+}
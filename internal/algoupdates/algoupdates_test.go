@@ -0,0 +1,78 @@
+package algoupdates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/markers"
+)
+
+func TestBuiltin(t *testing.T) {
+	c := Builtin()
+	require.NotEmpty(t, c.Updates)
+
+	ms := markers.Within(c.Markers(), "2025-03-20", "2025-03-20")
+	require.Len(t, ms, 1)
+	assert.Equal(t, markers.Marker{
+		ID:     "march-2025-core-update",
+		Date:   "2025-03-13",
+		End:    "2025-03-27",
+		Label:  "March 2025 core update",
+		Source: Source,
+	}, ms[0])
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for name, body := range map[string]string{
+		"not json":   `<html>`,
+		"no updates": `{"updates": []}`,
+		"no end":     `{"updates": [{"name": "x", "start": "2025-01-01"}]}`,
+		"bad date":   `{"updates": [{"name": "x", "start": "2025-13-01", "end": "2025-13-02"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(body))
+			assert.ErrorContains(t, err, "invalid algorithm update calendar")
+		})
+	}
+}
+
+func TestFetchThenLoad(t *testing.T) {
+	body := `{"updated": "2026-10-01", "updates": [{"name": "September 2026 core update", "type": "core", "start": "2026-09-08", "end": "2026-09-24"}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/updates.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	c, path, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, path, "built-in before any fetch")
+	assert.Equal(t, Builtin(), c)
+
+	_, err = Fetch(context.Background(), srv.Client(), srv.URL+"/missing.json", dir)
+	assert.ErrorContains(t, err, "404")
+
+	fetched, err := Fetch(context.Background(), srv.Client(), srv.URL+"/updates.json", dir)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", fetched.Updated)
+
+	c, path, err = Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, FileName), path)
+	assert.Equal(t, fetched, c)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, _, err = Load(dir)
+	assert.ErrorContains(t, err, path)
+}
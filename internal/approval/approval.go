@@ -0,0 +1,212 @@
+// Package approval implements two-person confirmation of destructive batch
+// operations.
+//
+// Instead of answering a "yes" prompt, the person running a batch delete
+// writes the operations it would perform to a pending file. A second person
+// reviews the file and signs it, which appends an approval to it. Only then
+// does the same command, run with --apply, execute the operations, and only
+// if they are still exactly those the file lists.
+//
+// The file is JSON. Its digest covers the command and the operations, and
+// each approval's token covers the digest and the approver, so editing the
+// operations after they were signed, or copying an approval between files,
+// invalidates the approvals. The tokens are checksums, not signatures: they
+// catch mistakes and stale plans, not someone forging an approval with write
+// access to the file.
+package approval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the pending-file version this build reads and writes.
+const SchemaVersion = 1
+
+// Errors returned by Read, Approve and Check.
+var (
+	// ErrTampered means the operations no longer match the file's digest.
+	ErrTampered = errors.New("the operations were edited after the file was written")
+	// ErrSelfApproval means the requester tried to approve their own request.
+	ErrSelfApproval = errors.New("the requester cannot approve their own request")
+	// ErrAlreadyApproved means the approver already signed the file.
+	ErrAlreadyApproved = errors.New("already approved by this approver")
+	// ErrNotApproved means no one but the requester signed the file.
+	ErrNotApproved = errors.New("not approved by a second person yet")
+	// ErrPlanChanged means the operations the command would run now differ
+	// from those that were approved.
+	ErrPlanChanged = errors.New("the operations changed since the file was written")
+	// ErrExecuted means the approved operations already ran once.
+	ErrExecuted = errors.New("the approved operations were already executed")
+)
+
+// Operation is one destructive call of a batch. Property is the GA4
+// property ID; Name identifies the resource to the API (event name, parameter
+// name, channel group resource name) and DisplayName, when set, is how people
+// know it.
+type Operation struct {
+	Property    string `json:"property"`
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// Approval is one signature appended to a pending file.
+type Approval struct {
+	Approver   string    `json:"approver"`
+	ApprovedAt time.Time `json:"approved_at"`
+	Token      string    `json:"token"`
+}
+
+// Pending is the content of a pending-operations file.
+type Pending struct {
+	SchemaVersion int         `json:"schema_version"`
+	Command       string      `json:"command"`
+	RequestedBy   string      `json:"requested_by"`
+	RequestedAt   time.Time   `json:"requested_at"`
+	Operations    []Operation `json:"operations"`
+	Digest        string      `json:"digest"`
+	Approvals     []Approval  `json:"approvals"`
+	ExecutedAt    *time.Time  `json:"executed_at,omitempty"`
+}
+
+// New returns an unsigned request by requestedBy to run ops with command.
+func New(command, requestedBy string, ops []Operation, now time.Time) *Pending {
+	return &Pending{
+		SchemaVersion: SchemaVersion,
+		Command:       command,
+		RequestedBy:   requestedBy,
+		RequestedAt:   now.UTC(),
+		Operations:    ops,
+		Digest:        Digest(command, ops),
+		Approvals:     []Approval{},
+	}
+}
+
+// Digest identifies command and ops: any change to either, including their
+// order, changes it.
+func Digest(command string, ops []Operation) string {
+	data, _ := json.Marshal(struct {
+		Command    string      `json:"command"`
+		Operations []Operation `json:"operations"`
+	}{command, ops})
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// token is the approval token of approver for digest at approvedAt.
+func token(digest, approver string, approvedAt time.Time) string {
+	sum := sha256.Sum256([]byte(digest + "\n" + approver + "\n" + approvedAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Read loads the pending file at path and checks that its operations still
+// match its digest.
+func Read(path string) (*Pending, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("approval: %w", err)
+	}
+	var p Pending
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("approval: parse %s: %w", path, err)
+	}
+	if p.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("approval: %s has schema version %d, this build reads %d", path, p.SchemaVersion, SchemaVersion)
+	}
+	if Digest(p.Command, p.Operations) != p.Digest {
+		return nil, fmt.Errorf("approval: %s: %w", path, ErrTampered)
+	}
+	return &p, nil
+}
+
+// Write saves p to path, creating its directory. The file is replaced
+// whole, so an interrupted write never leaves half a file behind.
+func Write(path string, p *Pending) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("approval: encode: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("approval: create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("approval: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("approval: replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// Approve appends approver's approval to p. The requester cannot approve,
+// and nobody approves twice.
+func (p *Pending) Approve(approver string, now time.Time) error {
+	if approver == "" {
+		return errors.New("approval: approver is empty")
+	}
+	if sameIdentity(approver, p.RequestedBy) {
+		return ErrSelfApproval
+	}
+	if slices.ContainsFunc(p.Approvals, func(a Approval) bool { return sameIdentity(a.Approver, approver) }) {
+		return ErrAlreadyApproved
+	}
+	at := now.UTC()
+	p.Approvals = append(p.Approvals, Approval{Approver: approver, ApprovedAt: at, Token: token(p.Digest, approver, at)})
+	return nil
+}
+
+// Approvers returns who validly approved p, leaving out the requester and
+// approvals whose token does not match.
+func (p *Pending) Approvers() []string {
+	var approvers []string
+	for _, a := range p.Approvals {
+		if sameIdentity(a.Approver, p.RequestedBy) || a.Token != token(p.Digest, a.Approver, a.ApprovedAt) {
+			continue
+		}
+		approvers = append(approvers, a.Approver)
+	}
+	return approvers
+}
+
+// Check reports whether ops may run with command under p: p must be for the
+// same command and operations and be approved by someone other than the
+// requester, and not executed yet.
+func (p *Pending) Check(command string, ops []Operation) error {
+	if p.ExecutedAt != nil {
+		return ErrExecuted
+	}
+	if p.Command != command {
+		return fmt.Errorf("approval: the file is for %q, not %q", p.Command, command)
+	}
+	if Digest(command, ops) != p.Digest {
+		return ErrPlanChanged
+	}
+	if len(p.Approvers()) == 0 {
+		return ErrNotApproved
+	}
+	return nil
+}
+
+// MarkExecuted records that the operations ran, so the approvals cannot be
+// used again.
+func (p *Pending) MarkExecuted(now time.Time) {
+	at := now.UTC()
+	p.ExecutedAt = &at
+}
+
+// sameIdentity compares identities as emails are: ignoring case and
+// surrounding space.
+func sameIdentity(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
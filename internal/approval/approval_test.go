@@ -0,0 +1,73 @@
+package approval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOps = []Operation{
+	{Property: "123", Action: "delete", Resource: "conversion", Name: "old_signup"},
+	{Property: "123", Action: "archive", Resource: "dimension", Name: "legacy_plan"},
+}
+
+func TestPending_WriteApproveCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals", "cleanup.json")
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Write(path, New("cleanup", "alice@example.com", testOps, now)))
+
+	p, err := Read(path)
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.Check("cleanup", testOps), ErrNotApproved)
+
+	assert.ErrorIs(t, p.Approve("Alice@Example.com", now), ErrSelfApproval)
+	require.NoError(t, p.Approve("bob@example.com", now.Add(time.Hour)))
+	assert.ErrorIs(t, p.Approve("bob@example.com", now.Add(2*time.Hour)), ErrAlreadyApproved)
+	require.NoError(t, Write(path, p))
+
+	p, err = Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob@example.com"}, p.Approvers())
+	assert.NoError(t, p.Check("cleanup", testOps))
+	assert.ErrorIs(t, p.Check("cleanup", testOps[:1]), ErrPlanChanged)
+	assert.ErrorContains(t, p.Check("link --unlink channels", testOps), "is for \"cleanup\"")
+
+	p.MarkExecuted(now.Add(3 * time.Hour))
+	require.NoError(t, Write(path, p))
+	p, err = Read(path)
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.Check("cleanup", testOps), ErrExecuted)
+}
+
+func TestRead_RejectsEditedOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleanup.json")
+	p := New("cleanup", "alice@example.com", testOps, time.Now())
+	require.NoError(t, p.Approve("bob@example.com", time.Now()))
+	require.NoError(t, Write(path, p))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), "legacy_plan", "purchase", 1)), 0o644))
+
+	_, err = Read(path)
+	assert.ErrorIs(t, err, ErrTampered)
+}
+
+func TestApprovers_IgnoresForgedTokens(t *testing.T) {
+	p := New("cleanup", "alice@example.com", testOps, time.Now())
+	require.NoError(t, p.Approve("bob@example.com", time.Now()))
+
+	other := New("cleanup", "alice@example.com", testOps[:1], time.Now())
+	other.Approvals = append(other.Approvals, p.Approvals...)
+	assert.Empty(t, other.Approvers(), "an approval copied from another file does not count")
+	assert.ErrorIs(t, other.Check("cleanup", testOps[:1]), ErrNotApproved)
+
+	p.Approvals = append(p.Approvals, Approval{Approver: "alice@example.com", ApprovedAt: time.Now()})
+	assert.Equal(t, []string{"bob@example.com"}, p.Approvers())
+}
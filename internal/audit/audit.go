@@ -124,7 +124,7 @@ func (p *Prober) Probe(ctx context.Context, rawURL string) URLAudit {
 		location := resp.Header.Get("Location")
 		_ = resp.Body.Close()
 
-		if isRedirect(status) && location != "" {
+		if IsRedirect(status) && location != "" {
 			res.Redirected = true
 			res.RedirectChain = append(res.RedirectChain, Hop{URL: current, Status: status, Location: location})
 
@@ -183,7 +183,9 @@ func classify(r *URLAudit) {
 	}
 }
 
-func isRedirect(status int) bool {
+// IsRedirect reports whether status is an HTTP redirect that carries a
+// Location: 301, 302, 303, 307 or 308.
+func IsRedirect(status int) bool {
 	switch status {
 	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
 		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
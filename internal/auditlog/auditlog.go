@@ -0,0 +1,202 @@
+// Package auditlog records every create, update and delete the tool sends to
+// Google, so a team can answer "who changed the counting method on purchase,
+// and when" after the fact.
+//
+// The log is an append-only JSONL file, one Entry per line, under the state
+// directory (<state-dir>/audit.jsonl by default). Entries can also be mirrored
+// into the local history store (internal/store) as KindAudit records, keyed by
+// property or site.
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+// SchemaVersion is the per-line entry version this build reads and writes.
+const SchemaVersion = 1
+
+// FileName is the audit file's name in the state directory.
+const FileName = "audit.jsonl"
+
+// Results of an Entry.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// Entry is one mutating API call. Property is the GA4 property ID or, for
+// Search Console, the site URL. Request is a summary of what was sent,
+// usually the request body.
+type Entry struct {
+	SchemaVersion int             `json:"schema_version"`
+	Time          time.Time       `json:"time"`
+	Service       string          `json:"service"`
+	Operation     string          `json:"operation"`
+	ResourceType  string          `json:"resource_type"`
+	Resource      string          `json:"resource"`
+	Property      string          `json:"property,omitempty"`
+	Actor         string          `json:"actor,omitempty"`
+	Request       json.RawMessage `json:"request,omitempty"`
+	Result        string          `json:"result"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// Option configures a Log.
+type Option func(*Log)
+
+// WithActor names the credential each entry is recorded under. fn is called
+// once, on the first Record, so credentials picked after the Log is created
+// (a --profile, say) are the ones named.
+func WithActor(fn func() string) Option {
+	return func(l *Log) {
+		l.actor = sync.OnceValue(fn)
+	}
+}
+
+// WithHistory also appends every entry to s as a store.KindAudit record.
+func WithHistory(s *store.Store) Option {
+	return func(l *Log) {
+		l.history = s
+	}
+}
+
+// Log appends entries to one audit file. It is safe for concurrent use.
+type Log struct {
+	mu      sync.Mutex
+	path    string
+	actor   func() string
+	history *store.Store
+	now     func() time.Time
+}
+
+// New returns a Log writing to path. The file and its directory are created
+// on the first Record.
+func New(path string, opts ...Option) *Log {
+	l := &Log{path: path, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Path returns the audit file the Log writes to.
+func (l *Log) Path() string { return l.path }
+
+// Record stamps e with the schema version, the time and the actor, unless
+// already set, and appends it to the file as a single write.
+func (l *Log) Record(e Entry) error {
+	e.SchemaVersion = SchemaVersion
+	if e.Time.IsZero() {
+		e.Time = l.now().UTC()
+	}
+	if e.Actor == "" && l.actor != nil {
+		e.Actor = l.actor()
+	}
+	if e.Result == "" {
+		e.Result = ResultOK
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("auditlog: encode entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("auditlog: create directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("auditlog: open %s: %w", l.path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("auditlog: append entry: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("auditlog: close %s: %w", l.path, err)
+	}
+	if l.history != nil {
+		return l.history.Append(context.Background(), store.KindAudit, historySite(e), []store.Record{historyRecord(e)})
+	}
+	return nil
+}
+
+// historySite is the history key of an entry: its property, else its service.
+func historySite(e Entry) string {
+	if e.Property != "" {
+		return e.Property
+	}
+	return e.Service
+}
+
+func historyRecord(e Entry) store.Record {
+	r := store.Record{
+		RecordedAt: e.Time,
+		Dimensions: map[string]string{
+			"service":       e.Service,
+			"operation":     e.Operation,
+			"resource_type": e.ResourceType,
+			"resource":      e.Resource,
+			"actor":         e.Actor,
+		},
+		State: map[string]string{"result": e.Result},
+	}
+	if e.Error != "" {
+		r.State["error"] = e.Error
+	}
+	if len(e.Request) > 0 {
+		r.State["request"] = string(e.Request)
+	}
+	return r
+}
+
+// Request summarises v, a request body, for Entry.Request. A value that does
+// not encode yields nil rather than failing the call it describes.
+func Request(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+var (
+	defaultMu  sync.RWMutex
+	defaultLog *Log
+)
+
+// SetDefault makes l the log Record writes to; nil turns recording off.
+func SetDefault(l *Log) {
+	defaultMu.Lock()
+	defaultLog = l
+	defaultMu.Unlock()
+}
+
+// Default returns the log set with SetDefault, or nil.
+func Default() *Log {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLog
+}
+
+// Record appends e to the default log. It does nothing when no default is
+// set, as in tests and library use.
+func Record(e Entry) error {
+	l := Default()
+	if l == nil {
+		return nil
+	}
+	return l.Record(e)
+}
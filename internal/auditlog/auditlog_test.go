@@ -0,0 +1,114 @@
+package auditlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+func TestLog_RecordThenRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state", FileName)
+	calls := 0
+	l := New(path, WithActor(func() string { calls++; return "ci@example.iam.gserviceaccount.com" }))
+
+	l.now = func() time.Time { return time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, l.Record(Entry{
+		Service: "analyticsadmin", Operation: "update", ResourceType: "conversion", Resource: "purchase",
+		Property: "123", Request: Request(map[string]string{"countingMethod": "ONCE_PER_SESSION"}),
+	}))
+	l.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, l.Record(Entry{
+		Service: "analyticsadmin", Operation: "delete", ResourceType: "dimension", Resource: "plan",
+		Property: "456", Result: ResultError, Error: "permission denied",
+	}))
+
+	all, err := Read(path, Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, SchemaVersion, all[0].SchemaVersion)
+	assert.Equal(t, ResultOK, all[0].Result)
+	assert.Equal(t, "ci@example.iam.gserviceaccount.com", all[1].Actor)
+	assert.JSONEq(t, `{"countingMethod":"ONCE_PER_SESSION"}`, string(all[0].Request))
+	assert.Equal(t, 1, calls, "the actor is resolved once")
+
+	since, err := Read(path, Filter{Since: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	assert.Equal(t, "plan", since[0].Resource)
+
+	byType, err := Read(path, Filter{ResourceType: "conversion", Property: "123"})
+	require.NoError(t, err)
+	require.Len(t, byType, 1)
+	assert.Equal(t, "purchase", byType[0].Resource)
+}
+
+func TestLog_MirrorsToHistory(t *testing.T) {
+	dir := t.TempDir()
+	history := store.New(dir)
+	l := New(filepath.Join(dir, FileName), WithHistory(history))
+
+	require.NoError(t, l.Record(Entry{
+		Service: "analyticsadmin", Operation: "create", ResourceType: "conversion", Resource: "sign_up", Property: "123",
+	}))
+
+	got, err := history.Query(context.Background(), store.Query{Kind: store.KindAudit, Site: "123"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "sign_up", got[0].Dimensions["resource"])
+	assert.Equal(t, ResultOK, got[0].State["result"])
+}
+
+func TestRead_MissingFileAndBadVersion(t *testing.T) {
+	dir := t.TempDir()
+	got, err := Read(filepath.Join(dir, FileName), Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	path := filepath.Join(dir, "future.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema_version":99}`+"\n"), 0o644))
+	_, err = Read(path, Filter{})
+	assert.ErrorIs(t, err, ErrSchemaVersionMismatch)
+}
+
+func TestDefault_RecordIsNoOpWhenUnset(t *testing.T) {
+	SetDefault(nil)
+	assert.NoError(t, Record(Entry{Operation: "create"}))
+
+	path := filepath.Join(t.TempDir(), FileName)
+	SetDefault(New(path))
+	t.Cleanup(func() { SetDefault(nil) })
+	require.NoError(t, Record(Entry{Operation: "create", ResourceType: "conversion", Resource: "sign_up"}))
+	got, err := Read(path, Filter{})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", time.Date(2026, 6, 8, 12, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"36h", time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{"2026-06-01", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, now)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+	for _, bad := range []string{"", "xd", "-1d", "soon"} {
+		_, err := ParseSince(bad, now)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,103 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSchemaVersionMismatch is returned by Read when a line carries a
+// schema_version this build does not understand.
+var ErrSchemaVersionMismatch = errors.New("auditlog: schema version mismatch")
+
+// Filter selects entries. Since drops entries recorded before it when
+// non-zero; Property, ResourceType and Resource keep only entries with that
+// exact value when set.
+type Filter struct {
+	Since        time.Time
+	Property     string
+	ResourceType string
+	Resource     string
+}
+
+func (f Filter) matches(e Entry) bool {
+	switch {
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case f.Property != "" && e.Property != f.Property:
+		return false
+	case f.ResourceType != "" && e.ResourceType != f.ResourceType:
+		return false
+	case f.Resource != "" && e.Resource != f.Resource:
+		return false
+	}
+	return true
+}
+
+// Read returns the entries of the audit file at path that f selects, in the
+// order they were recorded. A missing file yields no entries, not an error.
+func Read(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("auditlog: open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var out []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("auditlog: parse %s line %d: %w", path, line, err)
+		}
+		if e.SchemaVersion != SchemaVersion {
+			return nil, fmt.Errorf("%w: got %d, want %d (file: %s line %d)",
+				ErrSchemaVersionMismatch, e.SchemaVersion, SchemaVersion, path, line)
+		}
+		if f.matches(e) {
+			out = append(out, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("auditlog: read %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// ParseSince turns a --since value into the time it names, relative to now:
+// a duration with a d (days) or w (weeks) unit such as "7d" or "2w", any
+// time.ParseDuration value such as "36h", or a YYYY-MM-DD date.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return t, nil
+	}
+	for unit, days := range map[string]int{"d": 1, "w": 7} {
+		if n, ok := strings.CutSuffix(s, unit); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return time.Time{}, fmt.Errorf("invalid --since %q: want e.g. 7d, 2w, 24h or 2024-01-31", s)
+			}
+			return now.AddDate(0, 0, -v*days), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since %q: want e.g. 7d, 2w, 24h or 2024-01-31", s)
+	}
+	return now.Add(-d), nil
+}
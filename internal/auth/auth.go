@@ -0,0 +1,163 @@
+// Package auth resolves the Google credentials the API clients use and
+// implements `ga4 auth login`, the installed-app OAuth flow for site owners
+// who cannot create a service account.
+//
+// A service account key named by GOOGLE_APPLICATION_CREDENTIALS always wins.
+// Without one, the user credentials cached by `ga4 auth login` are used. They
+// are stored as an "authorized_user" credentials file, the format gcloud
+// writes for application default credentials, so the Google client libraries
+// refresh the token themselves.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+// CredentialsEnvVar names the service account key variable that takes
+// precedence over a cached login.
+const CredentialsEnvVar = "GOOGLE_APPLICATION_CREDENTIALS"
+
+// Scopes are requested by `ga4 auth login`: GA4 admin and reporting, Search
+// Console, read-only Tag Manager, and BigQuery and Sheets (for --output bq://
+// and sheets://).
+var Scopes = []string{
+	"https://www.googleapis.com/auth/analytics.edit",
+	"https://www.googleapis.com/auth/analytics.readonly",
+	"https://www.googleapis.com/auth/webmasters",
+	"https://www.googleapis.com/auth/tagmanager.readonly",
+	"https://www.googleapis.com/auth/bigquery",
+	"https://www.googleapis.com/auth/spreadsheets",
+}
+
+// ErrNoCredentials is returned when neither a service account key nor a
+// cached login is available.
+var ErrNoCredentials = errors.New("no Google credentials: set " + CredentialsEnvVar + " to a service account key or run `ga4 auth login`")
+
+// Dir returns the directory ga4-manager keeps credentials in:
+// $XDG_CONFIG_HOME/ga4-manager, or ~/.config/ga4-manager.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ga4-manager"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "ga4-manager"), nil
+}
+
+// TokenPath returns the file `ga4 auth login` caches user credentials in:
+// the active user profile's (see UseProfile), else credentials.json in Dir.
+func TokenPath() (string, error) {
+	if tokenPath != "" {
+		return tokenPath, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.json"), nil
+}
+
+// UserCredentials is an "authorized_user" credentials file: the OAuth client
+// the login went through and the refresh token it returned.
+type UserCredentials struct {
+	Type         string `json:"type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+	Account      string `json:"account,omitempty"`
+}
+
+// SaveToken caches the result of a login as user credentials, readable by
+// the current user only.
+func SaveToken(path string, cfg *oauth2.Config, token *oauth2.Token, account string) error {
+	if token.RefreshToken == "" {
+		return fmt.Errorf("the login returned no refresh token; revoke ga4-manager's access in your Google account and log in again")
+	}
+	data, err := json.MarshalIndent(UserCredentials{
+		Type:         "authorized_user",
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RefreshToken: token.RefreshToken,
+		Account:      account,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	return nil
+}
+
+// LoadToken reads cached user credentials.
+func LoadToken(path string) (*UserCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var creds UserCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if creds.Type != "authorized_user" || creds.RefreshToken == "" {
+		return nil, fmt.Errorf("%s does not hold user credentials; run `ga4 auth login` again", path)
+	}
+	return &creds, nil
+}
+
+// Kinds of credential Source.
+const (
+	SourceServiceAccount = "service_account"
+	SourceUser           = "user"
+)
+
+// Source says where the credentials of a client come from.
+type Source struct {
+	Kind string // SourceServiceAccount or SourceUser
+	Path string
+}
+
+// Resolve picks the credentials the API clients use: the service account key
+// of GOOGLE_APPLICATION_CREDENTIALS, else the cached login. It returns
+// ErrNoCredentials when there is neither.
+func Resolve() (Source, error) {
+	if path := os.Getenv(CredentialsEnvVar); path != "" {
+		return Source{Kind: SourceServiceAccount, Path: path}, nil
+	}
+	path, err := TokenPath()
+	if err != nil {
+		return Source{}, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return Source{Kind: SourceUser, Path: path}, nil
+	}
+	return Source{}, ErrNoCredentials
+}
+
+// ClientOption returns the API client option for the credentials.
+func (s Source) ClientOption() option.ClientOption {
+	if s.Kind == SourceUser {
+		return option.WithAuthCredentialsFile(option.AuthorizedUser, s.Path)
+	}
+	return option.WithAuthCredentialsFile(option.ServiceAccount, s.Path)
+}
+
+// String describes the source for logs and status output.
+func (s Source) String() string {
+	if s.Kind == SourceUser {
+		return "user login (" + s.Path + ")"
+	}
+	return "service account key (" + s.Path + ")"
+}
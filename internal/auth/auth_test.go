@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(CredentialsEnvVar, "")
+
+	_, err := Resolve()
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	path, err := TokenPath()
+	require.NoError(t, err)
+	cfg := &oauth2.Config{ClientID: "id", ClientSecret: "secret"}
+	require.NoError(t, SaveToken(path, cfg, &oauth2.Token{RefreshToken: "refresh"}, "owner@example.com"))
+
+	creds, err := Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, Source{Kind: SourceUser, Path: path}, creds)
+	user, err := LoadToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, UserCredentials{Type: "authorized_user", ClientID: "id", ClientSecret: "secret", RefreshToken: "refresh", Account: "owner@example.com"}, *user)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	t.Setenv(CredentialsEnvVar, "/keys/sa.json")
+	creds, err = Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, Source{Kind: SourceServiceAccount, Path: "/keys/sa.json"}, creds)
+}
+
+func TestSaveToken_NoRefreshToken(t *testing.T) {
+	err := SaveToken(filepath.Join(t.TempDir(), "credentials.json"), &oauth2.Config{}, &oauth2.Token{AccessToken: "a"}, "")
+	assert.ErrorContains(t, err, "no refresh token")
+}
+
+func TestLogin(t *testing.T) {
+	claims, _ := json.Marshal(map[string]string{"email": "owner@example.com"})
+	idToken := "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+
+	var exchanged url.Values
+	google := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		exchanged = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":3600,"id_token":%q}`, idToken)
+	}))
+	defer google.Close()
+	secret := fmt.Sprintf(`{"installed":{"client_id":"id","client_secret":"secret","auth_uri":"%s/auth","token_uri":"%s/token","redirect_uris":["http://localhost"]}}`, google.URL, google.URL)
+
+	// The "browser" consents at once and follows the redirect with a code
+	browser := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		assert.Equal(t, "offline", q.Get("access_type"))
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		redirect := q.Get("redirect_uri") + "?code=the-code&state=" + url.QueryEscape(q.Get("state"))
+		go func() {
+			resp, err := http.Get(redirect)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cfg, token, account, err := Login(ctx, LoginOptions{ClientSecret: []byte(secret), OpenBrowser: browser, Out: io.Discard})
+	require.NoError(t, err)
+	assert.Equal(t, "id", cfg.ClientID)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.Equal(t, "owner@example.com", account)
+	assert.Equal(t, "the-code", exchanged.Get("code"))
+	assert.NotEmpty(t, exchanged.Get("code_verifier"))
+}
+
+func TestLogin_Denied(t *testing.T) {
+	secret := `{"installed":{"client_id":"id","client_secret":"secret","auth_uri":"https://accounts.example/auth","token_uri":"https://accounts.example/token","redirect_uris":["http://localhost"]}}`
+	browser := func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?error=access_denied&state=" + url.QueryEscape(q.Get("state")))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _, _, err := Login(ctx, LoginOptions{ClientSecret: []byte(secret), OpenBrowser: browser, Out: io.Discard})
+	assert.ErrorContains(t, err, "authorization denied: access_denied")
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// loginScopes adds the OpenID scopes that name the signed-in account to
+// Scopes.
+var loginScopes = append([]string{"openid", "email"}, Scopes...)
+
+// LoginOptions configure Login.
+type LoginOptions struct {
+	// ClientSecret is the JSON of an OAuth client of type "Desktop app",
+	// downloaded from Google Cloud Console → APIs & Services → Credentials.
+	ClientSecret []byte
+	// OpenBrowser opens the consent page; nil only prints its URL.
+	OpenBrowser func(url string) error
+	// Out receives the instructions for the user.
+	Out io.Writer
+}
+
+// Login runs the installed-app OAuth flow: it serves the redirect on a
+// loopback port, sends the user to Google's consent page and exchanges the
+// returned code, with PKCE, for a token. It returns the OAuth client too, as
+// SaveToken needs it, and the signed-in account's email when Google gave it.
+func Login(ctx context.Context, opts LoginOptions) (*oauth2.Config, *oauth2.Token, string, error) {
+	cfg, err := google.ConfigFromJSON(opts.ClientSecret, loginScopes...)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("invalid OAuth client file: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("cannot listen for the OAuth redirect: %w", err)
+	}
+	cfg.RedirectURL = fmt.Sprintf("http://%s/", listener.Addr())
+
+	state := oauth2.GenerateVerifier()
+	verifier := oauth2.GenerateVerifier()
+	authURL := cfg.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("prompt", "consent"),
+		oauth2.S256ChallengeOption(verifier))
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "Unexpected request.", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s", q.Get("error"))
+			fmt.Fprintln(w, "Authorization was denied. You can close this window.")
+		default:
+			res.code = q.Get("code")
+			fmt.Fprintln(w, "ga4-manager is signed in. You can close this window.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	fmt.Fprintf(opts.Out, "Open this URL in a browser to sign in:\n\n  %s\n\n", authURL)
+	if opts.OpenBrowser != nil {
+		if err := opts.OpenBrowser(authURL); err != nil {
+			fmt.Fprintf(opts.Out, "(could not open a browser: %v)\n", err)
+		}
+	}
+	fmt.Fprintln(opts.Out, "Waiting for the browser to return...")
+
+	var res result
+	select {
+	case res = <-results:
+	case <-ctx.Done():
+		return nil, nil, "", fmt.Errorf("login not completed: %w", ctx.Err())
+	}
+	if res.err != nil {
+		return nil, nil, "", res.err
+	}
+
+	token, err := cfg.Exchange(ctx, res.code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+	return cfg, token, idTokenEmail(token), nil
+}
+
+// idTokenEmail reads the email claim of the ID token that comes with the
+// access token. The token was received straight from Google's token endpoint
+// over TLS, so its signature is not checked; the email is only displayed.
+func idTokenEmail(token *oauth2.Token) string {
+	idToken, _ := token.Extra("id_token").(string)
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return ""
+	}
+	return claims.Email
+}
+
+// OpenBrowser opens url in the desktop's default browser.
+func OpenBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
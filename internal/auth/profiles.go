@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnvVar names the environment variable `--profile` falls back to.
+const ProfileEnvVar = "GA4_PROFILE"
+
+// Profile is a named set of credentials: a service account key or the user
+// login of `ga4 auth profiles add --client-secret`.
+type Profile struct {
+	Type string `yaml:"type"` // SourceServiceAccount or SourceUser
+	Path string `yaml:"path"`
+}
+
+// Profiles is the profiles file, profiles.yaml in Dir. Default names the
+// profile used when none is chosen.
+type Profiles struct {
+	Default  string             `yaml:"default,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+var profileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateProfileName checks that name can name a profile and its token file.
+func ValidateProfileName(name string) error {
+	if !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// ProfilesPath returns the profiles file.
+func ProfilesPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles.yaml"), nil
+}
+
+// ProfileTokenPath returns the file a user profile's login is cached in.
+func ProfileTokenPath(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", name+".json"), nil
+}
+
+// LoadProfiles reads the profiles file; a missing file has no profiles.
+func LoadProfiles() (*Profiles, error) {
+	path, err := ProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	p := &Profiles{Profiles: map[string]Profile{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Profiles == nil {
+		p.Profiles = map[string]Profile{}
+	}
+	return p, nil
+}
+
+// Save writes the profiles file, readable by the current user only.
+func (p *Profiles) Save() error {
+	path, err := ProfilesPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save profiles: %w", err)
+	}
+	return nil
+}
+
+// Names lists the profiles alphabetically.
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeProfile is the profile UseProfile switched to; tokenPath, when set,
+// replaces TokenPath's default with the login of an active user profile.
+var (
+	activeProfile string
+	tokenPath     string
+)
+
+// ActiveProfile returns the profile in use, or "" when none was chosen.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// UseProfile makes the named profile's credentials the ones every client
+// resolves, for the rest of the process. A service account profile sets
+// GOOGLE_APPLICATION_CREDENTIALS, which the rest of the tool reads; a user
+// profile clears it and points the cached login at the profile's.
+func UseProfile(name string) error {
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q (see `ga4 auth profiles`)", name)
+	}
+	switch profile.Type {
+	case SourceServiceAccount:
+		err = os.Setenv(CredentialsEnvVar, profile.Path)
+		tokenPath = ""
+	case SourceUser:
+		err = os.Unsetenv(CredentialsEnvVar)
+		tokenPath = profile.Path
+	default:
+		return fmt.Errorf("profile %q has unknown type %q (want %s or %s)", name, profile.Type, SourceServiceAccount, SourceUser)
+	}
+	if err != nil {
+		return err
+	}
+	activeProfile = name
+	return nil
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestProfiles_SaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	profiles, err := LoadProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, profiles.Profiles)
+
+	profiles.Profiles["client-b"] = Profile{Type: SourceUser, Path: "/cfg/profiles/client-b.json"}
+	profiles.Profiles["client-a"] = Profile{Type: SourceServiceAccount, Path: "/keys/a.json"}
+	profiles.Default = "client-a"
+	require.NoError(t, profiles.Save())
+
+	loaded, err := LoadProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, profiles, loaded)
+	assert.Equal(t, []string{"client-a", "client-b"}, loaded.Names())
+}
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, ValidateProfileName("client-a.prod_2"))
+	for _, name := range []string{"", "../x", "a/b", "-flag", "a b"} {
+		assert.Error(t, ValidateProfileName(name), name)
+	}
+}
+
+func TestUseProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(CredentialsEnvVar, "/keys/exported.json")
+	t.Cleanup(func() { activeProfile, tokenPath = "", "" })
+
+	userPath, err := ProfileTokenPath("client-b")
+	require.NoError(t, err)
+	cfg := &oauth2.Config{ClientID: "id", ClientSecret: "secret"}
+	require.NoError(t, SaveToken(userPath, cfg, &oauth2.Token{RefreshToken: "refresh"}, "b@example.com"))
+	profiles := &Profiles{Profiles: map[string]Profile{
+		"client-a": {Type: SourceServiceAccount, Path: "/keys/a.json"},
+		"client-b": {Type: SourceUser, Path: userPath},
+		"broken":   {Type: "api_key", Path: "/keys/x"},
+	}}
+	require.NoError(t, profiles.Save())
+
+	require.NoError(t, UseProfile("client-b"))
+	creds, err := Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, Source{Kind: SourceUser, Path: userPath}, creds)
+	assert.Equal(t, "client-b", ActiveProfile())
+
+	require.NoError(t, UseProfile("client-a"))
+	creds, err = Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, Source{Kind: SourceServiceAccount, Path: "/keys/a.json"}, creds)
+	assert.Equal(t, "client-a", ActiveProfile())
+
+	assert.ErrorContains(t, UseProfile("missing"), "unknown profile")
+	assert.ErrorContains(t, UseProfile("broken"), "unknown type")
+	assert.Equal(t, "client-a", ActiveProfile())
+}
@@ -0,0 +1,269 @@
+// Package bigquery appends report rows to a BigQuery table, so saved Search
+// Console and GA4 data outlives the 16 months the APIs keep. Rows go in
+// through load jobs rather than streaming inserts: the table is created on
+// the first load with a schema inferred from the rows and partitioned by day
+// on a date column, later loads append, and loads cost nothing.
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+// Scheme prefixes a BigQuery destination: bq://project.dataset.table.
+const Scheme = "bq://"
+
+// BigQuery column types the inferred schemas use.
+const (
+	TypeString    = "STRING"
+	TypeInteger   = "INTEGER"
+	TypeFloat     = "FLOAT"
+	TypeBoolean   = "BOOLEAN"
+	TypeDate      = "DATE"
+	TypeTimestamp = "TIMESTAMP"
+)
+
+// DefaultPollInterval is how often Append checks whether its load job is
+// done.
+const DefaultPollInterval = time.Second
+
+var (
+	projectPattern = regexp.MustCompile(`^([a-z0-9.-]+:)?[a-z][a-z0-9-]*[a-z0-9]$`)
+	namePattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Table identifies a BigQuery table.
+type Table struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// String returns the table as project.dataset.table.
+func (t Table) String() string {
+	return t.Project + "." + t.Dataset + "." + t.Table
+}
+
+// IsURL reports whether s names a BigQuery destination rather than a file.
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// ParseURL parses bq://project.dataset.table.
+func ParseURL(s string) (Table, error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return Table{}, fmt.Errorf("%q is not a BigQuery destination (want %sproject.dataset.table)", s, Scheme)
+	}
+	// Domain-scoped projects ("example.com:project") contain a dot, so the
+	// dataset and table are taken from the right.
+	i := strings.LastIndex(rest, ".")
+	if i < 0 {
+		return Table{}, fmt.Errorf("invalid BigQuery destination %q: want %sproject.dataset.table", s, Scheme)
+	}
+	j := strings.LastIndex(rest[:i], ".")
+	if j < 0 {
+		return Table{}, fmt.Errorf("invalid BigQuery destination %q: want %sproject.dataset.table", s, Scheme)
+	}
+	t := Table{Project: rest[:j], Dataset: rest[j+1 : i], Table: rest[i+1:]}
+	switch {
+	case !projectPattern.MatchString(t.Project):
+		return Table{}, fmt.Errorf("invalid BigQuery project %q in %s", t.Project, s)
+	case !namePattern.MatchString(t.Dataset):
+		return Table{}, fmt.Errorf("invalid BigQuery dataset %q in %s", t.Dataset, s)
+	case !namePattern.MatchString(t.Table):
+		return Table{}, fmt.Errorf("invalid BigQuery table %q in %s", t.Table, s)
+	}
+	return t, nil
+}
+
+// Date is a YYYY-MM-DD value loaded into a DATE column.
+type Date string
+
+// Column is one field of an inferred schema.
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// InferSchema derives the column types from the Go values of rows: strings
+// are STRING, integers INTEGER, floats FLOAT, bools BOOLEAN, Date DATE and
+// time.Time TIMESTAMP. A column whose values are all nil is STRING.
+func InferSchema(columns []string, rows [][]any) ([]Column, error) {
+	schema := make([]Column, len(columns))
+	for i, name := range columns {
+		schema[i] = Column{Name: name, Type: TypeString}
+		for _, row := range rows {
+			if i >= len(row) || row[i] == nil {
+				continue
+			}
+			typ, err := typeOf(row[i])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+			schema[i].Type = typ
+			break
+		}
+	}
+	return schema, nil
+}
+
+func typeOf(v any) (string, error) {
+	switch v.(type) {
+	case string:
+		return TypeString, nil
+	case int, int32, int64:
+		return TypeInteger, nil
+	case float32, float64:
+		return TypeFloat, nil
+	case bool:
+		return TypeBoolean, nil
+	case Date:
+		return TypeDate, nil
+	case time.Time:
+		return TypeTimestamp, nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// loadAPI is the narrow consumer interface over the BigQuery jobs API.
+type loadAPI interface {
+	insertLoad(ctx context.Context, project string, job *bq.Job, data io.Reader) (*bq.Job, error)
+	getJob(ctx context.Context, project, jobID, location string) (*bq.Job, error)
+}
+
+type realLoadAPI struct {
+	svc *bq.Service
+}
+
+func (a *realLoadAPI) insertLoad(ctx context.Context, project string, job *bq.Job, data io.Reader) (*bq.Job, error) {
+	return a.svc.Jobs.Insert(project, job).Media(data).Context(ctx).Do()
+}
+
+func (a *realLoadAPI) getJob(ctx context.Context, project, jobID, location string) (*bq.Job, error) {
+	return a.svc.Jobs.Get(project, jobID).Location(location).Context(ctx).Do()
+}
+
+// Loader appends rows to BigQuery tables.
+type Loader struct {
+	api  loadAPI
+	poll time.Duration
+}
+
+// NewLoader returns a Loader authenticated by opts, typically the resolved
+// credentials' client option.
+func NewLoader(ctx context.Context, opts ...option.ClientOption) (*Loader, error) {
+	svc, err := bq.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery service: %w", err)
+	}
+	return &Loader{api: &realLoadAPI{svc: svc}, poll: DefaultPollInterval}, nil
+}
+
+// Append loads rows, each holding the values of columns in order, into t and
+// waits for the load to finish. The table is created when missing, with the
+// schema InferSchema gives and, when partitionBy names a DATE column,
+// partitioned by day on it; columns a later load adds are added to the
+// table. Nothing is loaded when rows is empty.
+func (l *Loader) Append(ctx context.Context, t Table, columns []string, rows [][]any, partitionBy string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	schema, err := InferSchema(columns, rows)
+	if err != nil {
+		return err
+	}
+	data, err := encodeRows(columns, rows)
+	if err != nil {
+		return err
+	}
+
+	load := &bq.JobConfigurationLoad{
+		DestinationTable:    &bq.TableReference{ProjectId: t.Project, DatasetId: t.Dataset, TableId: t.Table},
+		SourceFormat:        "NEWLINE_DELIMITED_JSON",
+		CreateDisposition:   "CREATE_IF_NEEDED",
+		WriteDisposition:    "WRITE_APPEND",
+		SchemaUpdateOptions: []string{"ALLOW_FIELD_ADDITION"},
+		Schema:              &bq.TableSchema{},
+	}
+	for _, c := range schema {
+		load.Schema.Fields = append(load.Schema.Fields, &bq.TableFieldSchema{Name: c.Name, Type: c.Type, Mode: "NULLABLE"})
+		if c.Name == partitionBy && c.Type == TypeDate {
+			load.TimePartitioning = &bq.TimePartitioning{Type: "DAY", Field: partitionBy}
+		}
+	}
+
+	job, err := l.api.insertLoad(ctx, t.Project, &bq.Job{Configuration: &bq.JobConfiguration{Load: load}}, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to start BigQuery load into %s: %w", t, err)
+	}
+	for {
+		if job.Status != nil && job.Status.State == "DONE" {
+			if job.Status.ErrorResult != nil {
+				return fmt.Errorf("BigQuery load into %s failed: %s", t, jobError(job.Status))
+			}
+			return nil
+		}
+		if job.JobReference == nil {
+			return errors.New("BigQuery returned a load job without a reference")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.poll):
+		}
+		job, err = l.api.getJob(ctx, t.Project, job.JobReference.JobId, job.JobReference.Location)
+		if err != nil {
+			return fmt.Errorf("failed to check BigQuery load into %s: %w", t, err)
+		}
+	}
+}
+
+// encodeRows writes rows as newline-delimited JSON objects. Nil values are
+// left out, which loads as NULL.
+func encodeRows(columns []string, rows [][]any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row has %d values for %d columns", len(row), len(columns))
+		}
+		obj := make(map[string]any, len(columns))
+		for i, v := range row {
+			if v == nil {
+				continue
+			}
+			if ts, ok := v.(time.Time); ok {
+				v = ts.UTC().Format(time.RFC3339Nano)
+			}
+			obj[columns[i]] = v
+		}
+		if err := enc.Encode(obj); err != nil {
+			return nil, fmt.Errorf("failed to encode row: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// jobError joins a failed job's error messages; the error result alone is
+// often just "too many errors".
+func jobError(s *bq.JobStatus) string {
+	msgs := []string{s.ErrorResult.Message}
+	for _, e := range s.Errors {
+		if e.Message != "" && e.Message != s.ErrorResult.Message {
+			msgs = append(msgs, e.Message)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,111 @@
+package bigquery
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestParseURL(t *testing.T) {
+	tbl, err := ParseURL("bq://my-project.seo.search_analytics")
+	require.NoError(t, err)
+	assert.Equal(t, Table{Project: "my-project", Dataset: "seo", Table: "search_analytics"}, tbl)
+	assert.Equal(t, "my-project.seo.search_analytics", tbl.String())
+
+	tbl, err = ParseURL("bq://example.com:my-project.seo.pages")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com:my-project", tbl.Project, "domain-scoped projects keep their dot")
+
+	for _, bad := range []string{"report.csv", "bq://project.table", "bq://Project.seo.pages", "bq://my-project.seo-data.pages"} {
+		_, err := ParseURL(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	schema, err := InferSchema(
+		[]string{"page", "clicks", "ctr", "report_date", "missing"},
+		[][]any{
+			{"https://example.com/", int64(3), 0.1, Date("2026-10-14"), nil},
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{"page", TypeString}, {"clicks", TypeInteger}, {"ctr", TypeFloat}, {"report_date", TypeDate}, {"missing", TypeString},
+	}, schema)
+
+	_, err = InferSchema([]string{"x"}, [][]any{{[]string{"a"}}})
+	assert.ErrorContains(t, err, "column x")
+}
+
+// fakeLoadAPI records the load and reports it done after pending checks.
+type fakeLoadAPI struct {
+	job     *bq.Job
+	data    string
+	pending int
+	result  *bq.ErrorProto
+}
+
+func (f *fakeLoadAPI) insertLoad(_ context.Context, _ string, job *bq.Job, data io.Reader) (*bq.Job, error) {
+	b, _ := io.ReadAll(data)
+	f.job, f.data = job, string(b)
+	return f.status(), nil
+}
+
+func (f *fakeLoadAPI) getJob(context.Context, string, string, string) (*bq.Job, error) {
+	f.pending--
+	return f.status(), nil
+}
+
+func (f *fakeLoadAPI) status() *bq.Job {
+	j := &bq.Job{JobReference: &bq.JobReference{JobId: "job1", Location: "EU"}, Status: &bq.JobStatus{State: "RUNNING"}}
+	if f.pending <= 0 {
+		j.Status = &bq.JobStatus{State: "DONE", ErrorResult: f.result}
+	}
+	return j
+}
+
+func TestAppend(t *testing.T) {
+	fake := &fakeLoadAPI{pending: 2}
+	l := &Loader{api: fake, poll: time.Millisecond}
+	tbl := Table{Project: "my-project", Dataset: "seo", Table: "pages"}
+
+	err := l.Append(context.Background(), tbl,
+		[]string{"report_date", "page", "clicks"},
+		[][]any{{Date("2026-10-14"), "/a", int64(3)}, {Date("2026-10-14"), nil, int64(0)}},
+		"report_date")
+	require.NoError(t, err)
+	assert.Equal(t, 0, fake.pending, "waits for the job to finish")
+
+	load := fake.job.Configuration.Load
+	assert.Equal(t, "WRITE_APPEND", load.WriteDisposition)
+	assert.Equal(t, "CREATE_IF_NEEDED", load.CreateDisposition)
+	assert.Equal(t, &bq.TimePartitioning{Type: "DAY", Field: "report_date"}, load.TimePartitioning)
+	require.Len(t, load.Schema.Fields, 3)
+	assert.Equal(t, TypeInteger, load.Schema.Fields[2].Type)
+	assert.Equal(t,
+		`{"clicks":3,"page":"/a","report_date":"2026-10-14"}`+"\n"+`{"clicks":0,"report_date":"2026-10-14"}`+"\n",
+		fake.data)
+}
+
+func TestAppend_JobError(t *testing.T) {
+	fake := &fakeLoadAPI{result: &bq.ErrorProto{Message: "Access Denied: Table my-project:seo.pages"}}
+	l := &Loader{api: fake, poll: time.Millisecond}
+	err := l.Append(context.Background(), Table{"my-project", "seo", "pages"}, []string{"a"}, [][]any{{"x"}}, "")
+	assert.ErrorContains(t, err, "Access Denied")
+	assert.True(t, strings.Contains(err.Error(), "my-project.seo.pages"))
+	assert.Nil(t, fake.job.Configuration.Load.TimePartitioning, "no partition column")
+}
+
+func TestAppend_NoRows(t *testing.T) {
+	fake := &fakeLoadAPI{}
+	l := &Loader{api: fake}
+	require.NoError(t, l.Append(context.Background(), Table{"my-project", "seo", "pages"}, []string{"a"}, nil, ""))
+	assert.Nil(t, fake.job)
+}
@@ -0,0 +1,396 @@
+// Package cassette records the HTTP exchanges of the Google API clients once
+// and replays them in tests, so setup, diff and reporting can be tested end
+// to end without credentials or quota.
+//
+// A test opens a cassette with New and passes its Client to the API client
+// (ga4.WithHTTPClient, gsc.WithHTTPClient). By default the cassette replays
+// testdata/cassettes/<name>.json and fails any request it has no response
+// for. With GA4_RECORD=1 the requests go to the live APIs with the
+// credentials the CLI would use, and the exchanges are saved when the test
+// ends:
+//
+//	GA4_RECORD=1 GA4_RECORD_PROPERTY_ID=123456789 go test ./internal/setup -run Replay
+//
+// Only the method, URL and body of requests are kept, so no credentials are
+// written, and Redactions swap the real property IDs and sites for the
+// placeholders the tests use, and values that change from run to run (the
+// dates of a report) for stable ones.
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/option"
+	"google.golang.org/api/searchconsole/v1"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+)
+
+// RecordEnvVar switches cassettes to recording when set to 1.
+const RecordEnvVar = "GA4_RECORD"
+
+// Interaction is one recorded request and the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the part of a request a replay is matched on. Body is the JSON
+// request body, if any.
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is a recorded response. A body that is not JSON is kept as a JSON
+// string.
+type Response struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// Redaction replaces a value with Placeholder in what is recorded, along
+// with its URL-escaped forms (a site URL in a request path).
+//
+// With Env, the value is that environment variable's (a real property ID,
+// site URL or account), only known when recording: tests use the
+// placeholder, and requests are sent with the real value. With Value, the
+// value is one the test computes each run, such as today's date range: it is
+// replaced in requests before they are matched, and put back in responses.
+type Redaction struct {
+	Env         string
+	Value       string
+	Placeholder string
+}
+
+// Cassette is an http.RoundTripper replaying, or recording, the interactions
+// of one cassette file. Identical requests are answered with their recorded
+// responses in order.
+type Cassette struct {
+	name      string
+	path      string
+	recording bool
+	inner     http.RoundTripper
+	// secrets and values hold value → placeholder pairs of the Env and
+	// Value redactions; secrets only when recording.
+	secrets []string
+	values  []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New opens testdata/cassettes/<name>.json of the calling test's package. In
+// replay mode a missing file fails the test; in record mode every Env
+// redaction must have its environment variable set, and the file is written
+// when the test ends.
+func New(t testing.TB, name string, redactions ...Redaction) *Cassette {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+	if os.Getenv(RecordEnvVar) != "1" {
+		c, err := Load(path, redactions...)
+		if err != nil {
+			t.Fatalf("%v (record it with %s=1)", err, RecordEnvVar)
+		}
+		return c
+	}
+
+	inner, err := liveTransport()
+	if err != nil {
+		t.Fatalf("cassette %s: %v", name, err)
+	}
+	c, err := newRecorder(path, inner, redactions...)
+	if err != nil {
+		t.Fatalf("cassette %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		if err := c.Save(); err != nil {
+			t.Errorf("cassette %s: %v", name, err)
+		}
+	})
+	return c
+}
+
+// liveTransport authenticates requests with the credentials the API clients
+// resolve.
+func liveTransport() (http.RoundTripper, error) {
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return htransport.NewTransport(context.Background(), http.DefaultTransport,
+		creds.ClientOption(),
+		option.WithScopes(admin.AnalyticsEditScope, searchconsole.WebmastersScope))
+}
+
+// Load reads a cassette file for replay. Env redactions are ignored: the
+// requests already carry their placeholders.
+func Load(path string, redactions ...Redaction) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	c := &Cassette{
+		name:         strings.TrimSuffix(filepath.Base(path), ".json"),
+		path:         path,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+	for _, r := range redactions {
+		if r.Env == "" {
+			c.values = append(c.values, replacementPairs(r.Value, r.Placeholder)...)
+		}
+	}
+	return c, nil
+}
+
+// newRecorder returns a cassette that sends requests through inner and
+// records them to path.
+func newRecorder(path string, inner http.RoundTripper, redactions ...Redaction) (*Cassette, error) {
+	c := &Cassette{
+		name:      strings.TrimSuffix(filepath.Base(path), ".json"),
+		path:      path,
+		recording: true,
+		inner:     inner,
+	}
+	for _, r := range redactions {
+		if r.Env == "" {
+			c.values = append(c.values, replacementPairs(r.Value, r.Placeholder)...)
+			continue
+		}
+		secret := os.Getenv(r.Env)
+		if secret == "" {
+			return nil, fmt.Errorf("set %s to record", r.Env)
+		}
+		c.secrets = append(c.secrets, replacementPairs(secret, r.Placeholder)...)
+	}
+	return c, nil
+}
+
+// replacementPairs returns the value → placeholder pairs replacing value and
+// its URL-escaped forms, longest first.
+func replacementPairs(value, placeholder string) []string {
+	var pairs []string
+	if escaped := url.PathEscape(value); escaped != value {
+		pairs = append(pairs, escaped, url.PathEscape(placeholder))
+	}
+	if escaped := url.QueryEscape(value); escaped != value && escaped != url.PathEscape(value) {
+		pairs = append(pairs, escaped, url.QueryEscape(placeholder))
+	}
+	return append(pairs, value, placeholder)
+}
+
+// Client returns an HTTP client using the cassette.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Unused returns the recorded requests that were not replayed, for tests
+// checking that a run made every call it was recorded making.
+func (c *Cassette) Unused() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Request
+	for i, used := range c.used {
+		if !used {
+			out = append(out, c.interactions[i].Request)
+		}
+	}
+	return out
+}
+
+// Save writes the recorded interactions. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if !c.recording {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip answers req from the cassette, or records it.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.recording {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	reqURL, reqBody := c.redact(req.URL.String()), c.redact(string(body))
+	key := matchKey(req.Method, reqURL, []byte(reqBody))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, in := range c.interactions {
+		if c.used[i] || matchKey(in.Request.Method, in.Request.URL, in.Request.Body) != key {
+			continue
+		}
+		c.used[i] = true
+		respBody := c.restoreValues(string(responseBody(in.Response.Body)))
+		return response(req, in.Response.Status, in.Response.ContentType, []byte(respBody)), nil
+	}
+	msg := fmt.Sprintf("cassette %s: no recorded response for %s %s", c.name, req.Method, reqURL)
+	if reqBody != "" {
+		msg += " " + reqBody
+	}
+	return nil, errors.New(msg)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	live, err := url.Parse(c.restoreSecrets(req.URL.String()))
+	if err != nil {
+		return nil, err
+	}
+	liveBody := []byte(c.restoreSecrets(string(body)))
+	out := req.Clone(req.Context())
+	out.URL = live
+	out.Host = live.Host
+	out.Body = io.NopCloser(bytes.NewReader(liveBody))
+	out.ContentLength = int64(len(liveBody))
+
+	resp, err := c.inner.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	redacted := []byte(c.redact(string(respBody)))
+	contentType := resp.Header.Get("Content-Type")
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    c.redact(req.URL.String()),
+			Body:   rawJSON([]byte(c.redact(string(body)))),
+		},
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: contentType,
+			Body:        rawJSON(redacted),
+		},
+	})
+	c.mu.Unlock()
+	return response(req, resp.StatusCode, contentType, []byte(c.restoreValues(string(redacted)))), nil
+}
+
+// redact replaces the redacted values in s with their placeholders.
+func (c *Cassette) redact(s string) string {
+	return strings.NewReplacer(slices.Concat(c.secrets, c.values)...).Replace(s)
+}
+
+// restoreSecrets puts the real values of Env redactions back in s.
+func (c *Cassette) restoreSecrets(s string) string {
+	return strings.NewReplacer(reversePairs(c.secrets)...).Replace(s)
+}
+
+// restoreValues puts the values of Value redactions back in s.
+func (c *Cassette) restoreValues(s string) string {
+	return strings.NewReplacer(reversePairs(c.values)...).Replace(s)
+}
+
+func reversePairs(pairs []string) []string {
+	out := make([]string, len(pairs))
+	for i := 0; i < len(pairs); i += 2 {
+		out[i], out[i+1] = pairs[i+1], pairs[i]
+	}
+	return out
+}
+
+func response(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// matchKey identifies a request regardless of query parameter order and
+// JSON body formatting.
+func matchKey(method, rawURL string, body []byte) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		u.RawQuery = u.Query().Encode()
+		rawURL = u.String()
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return method + " " + rawURL + " " + string(body)
+}
+
+// rawJSON keeps a JSON body as is and wraps any other in a JSON string.
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// responseBody undoes rawJSON.
+func responseBody(raw json.RawMessage) []byte {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+	return raw
+}
@@ -0,0 +1,134 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, hc *http.Client, url string) (int, string, error) {
+	t.Helper()
+	resp, err := hc.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body), nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.URL.String())
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			_, _ = w.Write(body)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "properties/987654321/customDimensions/1"}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "cassettes", "dims.json")
+	t.Setenv("TEST_PROPERTY_ID", "987654321")
+	rec, err := newRecorder(path, http.DefaultTransport, Redaction{Env: "TEST_PROPERTY_ID", Placeholder: "PROPERTY_ID"})
+	require.NoError(t, err)
+	listURL := srv.URL + "/v1/properties/PROPERTY_ID/customDimensions?b=2&a=1"
+
+	status, body, err := get(t, rec.Client(), listURL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"name": "properties/PROPERTY_ID/customDimensions/1"}`, body, "responses are redacted")
+	resp, err := rec.Client().Post(srv.URL+"/v1/properties/PROPERTY_ID/customDimensions", "application/json",
+		strings.NewReader(`{"parameterName": "author"}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, []string{
+		"/v1/properties/987654321/customDimensions?b=2&a=1",
+		"/v1/properties/987654321/customDimensions",
+	}, seen, "requests go out with the real value")
+
+	require.NoError(t, rec.Save())
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "987654321")
+
+	replay, err := Load(path)
+	require.NoError(t, err)
+	status, body, err = get(t, replay.Client(), srv.URL+"/v1/properties/PROPERTY_ID/customDimensions?a=1&b=2")
+	require.NoError(t, err, "query parameter order does not matter")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"name": "properties/PROPERTY_ID/customDimensions/1"}`, body)
+
+	resp, err = replay.Client().Post(srv.URL+"/v1/properties/PROPERTY_ID/customDimensions", "application/json",
+		strings.NewReader(`{ "parameterName":"author" }`))
+	require.NoError(t, err, "JSON formatting does not matter")
+	_ = resp.Body.Close()
+
+	_, _, err = get(t, replay.Client(), listURL)
+	require.Error(t, err, "each recorded response is replayed once")
+	assert.Contains(t, err.Error(), "cassette dims: no recorded response for GET")
+	assert.Len(t, seen, 2, "replay never reaches the server")
+}
+
+func TestReplay_NonJSONBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {
+    "request": {"method": "GET", "url": "https://example.com/robots.txt"},
+    "response": {"status": 404, "content_type": "text/plain", "body": "not found"}
+  }
+]`), 0o644))
+	c, err := Load(path)
+	require.NoError(t, err)
+
+	status, body, err := get(t, c.Client(), "https://example.com/robots.txt")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not found", body)
+}
+
+func TestRedact_EscapedForms(t *testing.T) {
+	t.Setenv("TEST_SITE_URL", "sc-domain:real.com")
+	c, err := newRecorder("site.json", nil, Redaction{Env: "TEST_SITE_URL", Placeholder: "sc-domain:example.com"})
+	require.NoError(t, err)
+	recorded := "/webmasters/v3/sites/sc-domain%3Aexample.com/sitemaps {\"siteUrl\":\"sc-domain:example.com\"}"
+	live := "/webmasters/v3/sites/sc-domain%3Areal.com/sitemaps {\"siteUrl\":\"sc-domain:real.com\"}"
+
+	assert.Equal(t, recorded, c.redact(live))
+	assert.Equal(t, live, c.restoreSecrets(recorded))
+
+	_, err = newRecorder("site.json", nil, Redaction{Env: "TEST_UNSET", Placeholder: "x"})
+	assert.EqualError(t, err, "set TEST_UNSET to record")
+}
+
+func TestReplay_ValueRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+  {
+    "request": {"method": "POST", "url": "https://example.com/query", "body": {"startDate": "START"}},
+    "response": {"status": 200, "body": {"period": "START"}}
+  }
+]`), 0o644))
+	c, err := Load(path, Redaction{Value: "2026-09-18", Placeholder: "START"})
+	require.NoError(t, err)
+
+	resp, err := c.Client().Post("https://example.com/query", "application/json", strings.NewReader(`{"startDate": "2026-09-18"}`))
+
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"period": "2026-09-18"}`, string(body))
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppendDefinitions adds the conversions, custom dimensions and metrics,
+// calculated metrics and channel groups of add to the config file at path.
+// They merge like an include (see includeListKeys): an entry with the key of
+// an existing one replaces it, others are appended. The rest of the file is
+// kept, comments included, but re-indented with two spaces.
+func AppendDefinitions(path string, add *ProjectConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	root := documentRoot(&doc)
+	if root == nil {
+		return fmt.Errorf("%s is not a YAML mapping", path)
+	}
+
+	var additions yaml.Node
+	if err := additions.Encode(struct {
+		Conversions       []ConversionConfig       `yaml:"conversions,omitempty"`
+		Dimensions        []DimensionConfig        `yaml:"dimensions,omitempty"`
+		Metrics           []MetricConfig           `yaml:"metrics,omitempty"`
+		CalculatedMetrics []CalculatedMetricConfig `yaml:"calculated_metrics,omitempty"`
+		ChannelGroups     []ChannelGroupConfig     `yaml:"channel_groups,omitempty"`
+	}{add.Conversions, add.Dimensions, add.Metrics, add.CalculatedMetrics, add.ChannelGroups}); err != nil {
+		return fmt.Errorf("encode definitions: %w", err)
+	}
+	mergeMapping(root, &additions, true)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendDefinitions(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"prod.yaml": `# Production property
+project:
+  name: Example
+analytics:
+  property_id: "222"
+conversions:
+  - name: purchase # revenue
+    counting_method: ONCE_PER_EVENT
+`})
+	path := filepath.Join(dir, "prod.yaml")
+
+	require.NoError(t, AppendDefinitions(path, &ProjectConfig{
+		Conversions: []ConversionConfig{{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION"}},
+		Dimensions:  []DimensionConfig{{ParameterName: "plan_type", DisplayName: "Plan Type", Scope: "EVENT"}},
+	}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Production property\n")
+	assert.Contains(t, string(data), "- name: purchase # revenue\n")
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Example", cfg.Project.Name)
+	require.Len(t, cfg.Conversions, 2)
+	assert.Equal(t, "purchase", cfg.Conversions[0].Name)
+	assert.Equal(t, "sign_up", cfg.Conversions[1].Name)
+	require.Len(t, cfg.Dimensions, 1)
+	assert.Equal(t, "plan_type", cfg.Dimensions[0].ParameterName)
+}
@@ -12,6 +12,9 @@ type ClientConfig struct {
 	// Timeouts controls various timeout settings
 	Timeouts TimeoutConfig
 
+	// Retry controls how transient API errors are retried
+	Retry RetryConfig
+
 	// Logging controls logging behavior
 	Logging LoggingConfig
 }
@@ -35,6 +38,32 @@ type TimeoutConfig struct {
 
 	// ContextTimeout is the timeout for the overall client context
 	ContextTimeout time.Duration
+
+	// Operations overrides RequestTimeout per API verb: create, list, get,
+	// update, delete (GA4 + GSC), query and inspect (GSC only).
+	Operations map[string]time.Duration
+}
+
+// For returns the timeout for an API verb, falling back to RequestTimeout.
+func (t TimeoutConfig) For(verb string) time.Duration {
+	if d, ok := t.Operations[verb]; ok && d > 0 {
+		return d
+	}
+	return t.RequestTimeout
+}
+
+// RetryConfig holds retry configuration for transient API errors (429 and
+// 5xx). A Retry-After header from the API overrides the computed delay.
+type RetryConfig struct {
+	// MaxRetries is how many times a failed request is retried; 0 disables
+	MaxRetries int
+
+	// BaseDelay is the wait before the first retry, doubled for each further
+	// retry and jittered
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed wait between retries
+	MaxDelay time.Duration
 }
 
 // LoggingConfig holds logging configuration
@@ -60,6 +89,11 @@ func DefaultClientConfig() *ClientConfig {
 			RequestTimeout: 30 * time.Second, // 30 seconds per request
 			ContextTimeout: 5 * time.Minute,  // 5 minutes total
 		},
+		Retry: RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  time.Second,
+			MaxDelay:   30 * time.Second,
+		},
 		Logging: LoggingConfig{
 			Level:     "info",
 			Format:    "text",
@@ -79,6 +113,11 @@ func ProductionClientConfig() *ClientConfig {
 			RequestTimeout: 30 * time.Second,
 			ContextTimeout: 10 * time.Minute,
 		},
+		Retry: RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  time.Second,
+			MaxDelay:   30 * time.Second,
+		},
 		Logging: LoggingConfig{
 			Level:     "warn", // Less verbose in production
 			Format:    "json", // Structured logs for production
@@ -98,6 +137,11 @@ func DevelopmentClientConfig() *ClientConfig {
 			RequestTimeout: 60 * time.Second, // Longer timeout for debugging
 			ContextTimeout: 15 * time.Minute,
 		},
+		Retry: RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  time.Second,
+			MaxDelay:   30 * time.Second,
+		},
 		Logging: LoggingConfig{
 			Level:     "debug", // Verbose logging for development
 			Format:    "text",  // Human-readable logs
@@ -105,3 +149,29 @@ func DevelopmentClientConfig() *ClientConfig {
 		},
 	}
 }
+
+// ServerClientConfig returns the configuration used by `ga4 serve`. The client
+// lives as long as the server process, so there is no overall context deadline
+// (ContextTimeout 0); each API request is still bounded by RequestTimeout.
+func ServerClientConfig() *ClientConfig {
+	return &ClientConfig{
+		RateLimiting: RateLimitConfig{
+			RequestsPerSecond: 5.0, // Shared by every HTTP caller
+			Burst:             10,
+		},
+		Timeouts: TimeoutConfig{
+			RequestTimeout: 30 * time.Second,
+			ContextTimeout: 0,
+		},
+		Retry: RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  time.Second,
+			MaxDelay:   30 * time.Second,
+		},
+		Logging: LoggingConfig{
+			Level:     "info",
+			Format:    "json", // Structured logs for log shippers
+			AddSource: false,
+		},
+	}
+}
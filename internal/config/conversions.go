@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/garbarok/ga4-manager/internal/validation"
+)
+
+// valueBearingEvents are GA4 recommended events whose conversions are
+// normally reported as revenue or lead value. Without a value on the event or
+// a default value on the conversion they count as zero in ROAS reporting.
+var valueBearingEvents = map[string]bool{
+	"purchase":           true,
+	"in_app_purchase":    true,
+	"generate_lead":      true,
+	"qualify_lead":       true,
+	"close_convert_lead": true,
+	"working_lead":       true,
+	"sign_up":            true,
+}
+
+// IsValueBearingEvent reports whether conversions of eventName are expected
+// to carry a value: a recommended purchase/lead event, or one marked
+// value_required in the config.
+func (pc *ProjectConfig) IsValueBearingEvent(eventName string) bool {
+	if valueBearingEvents[eventName] {
+		return true
+	}
+	for _, conv := range pc.Conversions {
+		if conv.Name == eventName {
+			return conv.ValueRequired
+		}
+	}
+	return false
+}
+
+// DefaultCurrency returns the property-level currency, preferring the
+// analytics block over the legacy ga4 block.
+func (pc *ProjectConfig) DefaultCurrency() string {
+	if pc.Analytics != nil && pc.Analytics.Currency != "" {
+		return pc.Analytics.Currency
+	}
+	return pc.GA4.Currency
+}
+
+// ConversionCurrency returns the currency for conv's default value: its own
+// currency, else the property default.
+func (pc *ProjectConfig) ConversionCurrency(conv ConversionConfig) string {
+	if conv.Currency != "" {
+		return conv.Currency
+	}
+	return pc.DefaultCurrency()
+}
+
+// validateConversionValues checks default_value/currency on conversions. GA4
+// rejects a default value without an ISO 4217 currency, so a value with no
+// currency anywhere in the config is an error rather than a failed API call.
+func validateConversionValues(pc *ProjectConfig) error {
+	if cur := pc.GA4.Currency; cur != "" && !validation.CurrencyCodeRegex.MatchString(cur) {
+		return fmt.Errorf("ga4.currency %q must be a 3-letter ISO 4217 code (e.g. EUR)", cur)
+	}
+	if pc.Analytics != nil {
+		if cur := pc.Analytics.Currency; cur != "" && !validation.CurrencyCodeRegex.MatchString(cur) {
+			return fmt.Errorf("analytics.currency %q must be a 3-letter ISO 4217 code (e.g. EUR)", cur)
+		}
+	}
+	for i, conv := range pc.Conversions {
+		if conv.Currency != "" && !validation.CurrencyCodeRegex.MatchString(conv.Currency) {
+			return fmt.Errorf("conversions[%d].currency %q must be a 3-letter ISO 4217 code (e.g. EUR)", i, conv.Currency)
+		}
+		if conv.DefaultValue == nil {
+			if conv.Currency != "" {
+				return fmt.Errorf("conversions[%d].currency is set without default_value", i)
+			}
+			continue
+		}
+		if *conv.DefaultValue < 0 {
+			return fmt.Errorf("conversions[%d].default_value must not be negative", i)
+		}
+		if pc.ConversionCurrency(conv) == "" {
+			return fmt.Errorf("conversions[%d].default_value needs a currency (set conversions[%d].currency or analytics.currency)", i, i)
+		}
+	}
+	return nil
+}
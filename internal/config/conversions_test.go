@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestValidateConversionValues(t *testing.T) {
+	base := func(conv ConversionConfig) *ProjectConfig {
+		conv.Name = "generate_lead"
+		conv.CountingMethod = "ONCE_PER_EVENT"
+		return &ProjectConfig{
+			Project:     ProjectInfo{Name: "Test"},
+			GA4:         GA4Config{PropertyID: "123"},
+			Conversions: []ConversionConfig{conv},
+		}
+	}
+
+	assert.NoError(t, validateConfig(base(ConversionConfig{})))
+	assert.NoError(t, validateConfig(base(ConversionConfig{DefaultValue: floatPtr(25), Currency: "EUR"})))
+
+	pc := base(ConversionConfig{DefaultValue: floatPtr(25)})
+	err := validateConfig(pc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a currency")
+
+	pc.GA4.Currency = "USD"
+	assert.NoError(t, validateConfig(pc))
+	assert.Equal(t, "USD", pc.ConversionCurrency(pc.Conversions[0]))
+
+	pc.GA4.Currency = "usd"
+	assert.ErrorContains(t, validateConfig(pc), "ga4.currency")
+
+	assert.ErrorContains(t, validateConfig(base(ConversionConfig{Currency: "EUR"})), "without default_value")
+	assert.ErrorContains(t, validateConfig(base(ConversionConfig{DefaultValue: floatPtr(-1), Currency: "EUR"})), "negative")
+}
+
+func TestIsValueBearingEvent(t *testing.T) {
+	pc := &ProjectConfig{Conversions: []ConversionConfig{
+		{Name: "demo_booked", ValueRequired: true},
+		{Name: "newsletter_signup"},
+	}}
+	assert.True(t, pc.IsValueBearingEvent("purchase"))
+	assert.True(t, pc.IsValueBearingEvent("demo_booked"))
+	assert.False(t, pc.IsValueBearingEvent("newsletter_signup"))
+	assert.False(t, pc.IsValueBearingEvent("scroll"))
+}
+
+func TestDefaultCurrency_AnalyticsOverridesLegacy(t *testing.T) {
+	pc := &ProjectConfig{GA4: GA4Config{Currency: "USD"}, Analytics: &AnalyticsConfig{Currency: "EUR"}}
+	assert.Equal(t, "EUR", pc.DefaultCurrency())
+	assert.Equal(t, "GBP", pc.ConversionCurrency(ConversionConfig{Currency: "GBP"}))
+}
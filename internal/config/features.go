@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature names an Admin API surface that only exists in analyticsadmin
+// v1alpha. Google changes these without the deprecation window the stable
+// surfaces get, so each one is gated behind a per-property flag:
+//
+//	analytics:
+//	  property_id: "123456789"
+//	  features:
+//	    audiences: true
+//	    channel_groups: false
+type Feature string
+
+const (
+	FeatureChannelGroups    Feature = "channel_groups"
+	FeatureAudiences        Feature = "audiences"
+	FeatureSubproperties    Feature = "subproperties"
+	FeatureExpandedDataSets Feature = "expanded_data_sets"
+)
+
+// FeatureInfo describes a gated feature and its default state.
+type FeatureInfo struct {
+	Name        Feature
+	Description string
+	// Default is the state used when the config does not mention the feature.
+	// Channel groups predate the flags and stay on so existing configs keep
+	// working; everything added behind a flag starts off.
+	Default bool
+}
+
+var knownFeatures = []FeatureInfo{
+	{Name: FeatureChannelGroups, Description: "Custom channel groups (ga4 link --service channels)", Default: true},
+	{Name: FeatureAudiences, Description: "Audience read/write through the Admin API", Default: false},
+	{Name: FeatureSubproperties, Description: "Subproperties and subproperty event filters (GA4 360)", Default: false},
+	{Name: FeatureExpandedDataSets, Description: "Expanded data sets (GA4 360)", Default: false},
+}
+
+// KnownFeatures returns every gated feature in display order.
+func KnownFeatures() []FeatureInfo {
+	out := make([]FeatureInfo, len(knownFeatures))
+	copy(out, knownFeatures)
+	return out
+}
+
+// LookupFeature returns the feature registered under name.
+func LookupFeature(name string) (FeatureInfo, bool) {
+	for _, f := range knownFeatures {
+		if string(f.Name) == name {
+			return f, true
+		}
+	}
+	return FeatureInfo{}, false
+}
+
+// FeatureSet is the explicit feature configuration of one property. Features
+// it does not mention fall back to their registered default.
+type FeatureSet map[Feature]bool
+
+// Enabled reports whether f is switched on for the property.
+func (s FeatureSet) Enabled(f Feature) bool {
+	if on, ok := s[f]; ok {
+		return on
+	}
+	info, _ := LookupFeature(string(f))
+	return info.Default
+}
+
+// Explicit reports whether the config sets f rather than inheriting the default.
+func (s FeatureSet) Explicit(f Feature) bool {
+	_, ok := s[f]
+	return ok
+}
+
+// Features returns the property's feature flags. The legacy ga4 block is read
+// first so the analytics block wins when both set the same flag.
+func (pc *ProjectConfig) Features() FeatureSet {
+	set := FeatureSet{}
+	for name, on := range pc.GA4.Features {
+		set[Feature(name)] = on
+	}
+	if pc.Analytics != nil {
+		for name, on := range pc.Analytics.Features {
+			set[Feature(name)] = on
+		}
+	}
+	return set
+}
+
+// validateFeatures rejects flag names that are not registered, so a typo does
+// not silently leave a feature at its default.
+func validateFeatures(block string, features map[string]bool) error {
+	var unknown []string
+	for name := range features {
+		if _, ok := LookupFeature(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	names := make([]string, len(knownFeatures))
+	for i, f := range knownFeatures {
+		names[i] = string(f.Name)
+	}
+	return fmt.Errorf("%s.features: unknown feature %s (known: %s)",
+		block, strings.Join(unknown, ", "), strings.Join(names, ", "))
+}
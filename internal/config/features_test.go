@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureSetDefaults(t *testing.T) {
+	var set FeatureSet
+	assert.True(t, set.Enabled(FeatureChannelGroups), "channel groups predate the flags and default on")
+	assert.False(t, set.Enabled(FeatureAudiences))
+	assert.False(t, set.Enabled(FeatureExpandedDataSets))
+	assert.False(t, set.Explicit(FeatureAudiences))
+}
+
+func TestProjectConfigFeatures_AnalyticsOverridesLegacy(t *testing.T) {
+	pc := &ProjectConfig{
+		GA4:       GA4Config{Features: map[string]bool{"audiences": true, "channel_groups": false}},
+		Analytics: &AnalyticsConfig{Features: map[string]bool{"audiences": false}},
+	}
+	set := pc.Features()
+	assert.False(t, set.Enabled(FeatureAudiences))
+	assert.False(t, set.Enabled(FeatureChannelGroups))
+	assert.True(t, set.Explicit(FeatureChannelGroups))
+}
+
+func TestValidateConfig_UnknownFeature(t *testing.T) {
+	pc := &ProjectConfig{
+		Project:   ProjectInfo{Name: "Test"},
+		Analytics: &AnalyticsConfig{PropertyID: "123", Features: map[string]bool{"audience": true}},
+	}
+	err := validateConfig(pc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "analytics.features: unknown feature audience")
+
+	pc.Analytics.Features = map[string]bool{"audiences": true}
+	assert.NoError(t, validateConfig(pc))
+}
+
+// Channel groups in a config that switches them off would be silently
+// skipped or fail halfway through setup; the config is rejected instead.
+func TestValidateConfig_ChannelGroupsFeatureOff(t *testing.T) {
+	pc := &ProjectConfig{
+		Project:   ProjectInfo{Name: "Test"},
+		Analytics: &AnalyticsConfig{PropertyID: "123", Features: map[string]bool{"channel_groups": false}},
+		ChannelGroups: []ChannelGroupConfig{{
+			DisplayName: "Marketing Channels",
+			Rules:       []ChannelRuleConfig{{DisplayName: "Email", Expression: "sessionMedium == 'email'"}},
+		}},
+	}
+	err := validateConfig(pc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "analytics.features.channel_groups is false")
+
+	pc.Analytics.Features = nil
+	assert.NoError(t, validateConfig(pc))
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A config file may include fragments, so definitions shared between
+// projects live in one library:
+//
+//	include:
+//	  - lib/seo-conversions.yaml
+//	  - lib/core-web-vitals-dimensions.yaml
+//	project:
+//	  name: My Site
+//
+// Paths are relative to the including file, and fragments may include
+// further fragments. The fragments are merged in order, then the including
+// file on top of them:
+//   - mappings merge key by key;
+//   - the top-level lists in includeListKeys merge by their key: an entry
+//     replaces the earlier one with the same key in place, others are
+//     appended;
+//   - anything else, including other lists, is replaced by the later file.
+//
+// A file that includes itself, directly or not, is an error. Variables are
+// substituted after merging, so a fragment may use the project's vars.
+
+// includeListKeys maps each top-level list merged entry by entry to the
+// field that identifies an entry.
+var includeListKeys = map[string]string{
+	"conversions":        "name",
+	"dimensions":         "parameter",
+	"metrics":            "parameter",
+	"calculated_metrics": "name",
+	"channel_groups":     "display_name",
+	"audiences":          "name",
+	"content_groups":     "name",
+	"properties":         "name",
+}
+
+// resolveIncludes replaces the include directive of doc, parsed from path,
+// with the merged fragments it names.
+func resolveIncludes(doc *yaml.Node, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	return expandIncludes(doc, abs, []string{abs})
+}
+
+// expandIncludes resolves the includes of doc, the file at path; stack holds
+// the files being included, path last, to detect cycles.
+func expandIncludes(doc *yaml.Node, path string, stack []string) error {
+	root := documentRoot(doc)
+	if root == nil {
+		return nil
+	}
+	includes, err := takeIncludes(root)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(includes) == 0 {
+		return nil
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, inc := range includes {
+		incPath := filepath.Clean(inc.Value)
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(path), incPath)
+		}
+		for i, p := range stack {
+			if p == incPath {
+				return fmt.Errorf("include cycle: %s", strings.Join(append(stack[i:], incPath), " -> "))
+			}
+		}
+		data, err := os.ReadFile(incPath)
+		if err != nil {
+			return fmt.Errorf("%s: line %d: failed to read include: %w", path, inc.Line, err)
+		}
+		var frag yaml.Node
+		if err := yaml.Unmarshal(data, &frag); err != nil {
+			return fmt.Errorf("%s: failed to parse YAML: %w", incPath, err)
+		}
+		if err := expandIncludes(&frag, incPath, append(stack[:len(stack):len(stack)], incPath)); err != nil {
+			return err
+		}
+		fragRoot := documentRoot(&frag)
+		if fragRoot == nil {
+			if frag.Kind != 0 {
+				return fmt.Errorf("%s: an included file must be a mapping of config sections", incPath)
+			}
+			continue
+		}
+		mergeMapping(merged, fragRoot, true)
+	}
+	mergeMapping(merged, root, true)
+	*root = *merged
+	return nil
+}
+
+// documentRoot returns the top-level mapping of doc, or nil.
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// takeIncludes removes the include key from root and returns its paths.
+func takeIncludes(root *yaml.Node) ([]*yaml.Node, error) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "include" {
+			continue
+		}
+		value := root.Content[i+1]
+		root.Content = append(root.Content[:i:i], root.Content[i+2:]...)
+
+		paths := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			paths = value.Content
+		}
+		for _, p := range paths {
+			if p.Kind != yaml.ScalarNode || p.Value == "" {
+				return nil, fmt.Errorf("line %d: include must be a file path or a list of file paths", p.Line)
+			}
+		}
+		return paths, nil
+	}
+	return nil, nil
+}
+
+// mergeMapping merges src into dst, src winning. top marks the document
+// root, whose lists in includeListKeys merge entry by entry.
+func mergeMapping(dst, src *yaml.Node, top bool) {
+	index := make(map[string]int, len(dst.Content)/2)
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		index[dst.Content[i].Value] = i + 1
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		j, ok := index[key.Value]
+		if !ok {
+			index[key.Value] = len(dst.Content) + 1
+			dst.Content = append(dst.Content, key, value)
+			continue
+		}
+		existing := dst.Content[j]
+		switch {
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeMapping(existing, value, false)
+		case top && existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode && includeListKeys[key.Value] != "":
+			existing.Content = mergeSequence(existing.Content, value.Content, includeListKeys[key.Value])
+		default:
+			dst.Content[j] = value
+		}
+	}
+}
+
+// mergeSequence merges the entries of src into dst by the value of their
+// field key, as mergeByKey does for decoded lists.
+func mergeSequence(dst, src []*yaml.Node, key string) []*yaml.Node {
+	return mergeByKey(dst, src, func(n *yaml.Node) string {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == key {
+					return n.Content[i+1].Value
+				}
+			}
+		}
+		return fmt.Sprintf("%p", n) // no key: never replaces another entry
+	})
+}
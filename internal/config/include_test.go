@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestLoadConfig_Include(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"lib/seo-conversions.yaml": `
+include: base.yaml
+conversions:
+  - name: newsletter_signup
+    counting_method: ONCE_PER_EVENT
+  - name: contact_form
+    counting_method: ONCE_PER_EVENT
+`,
+		"lib/base.yaml": `
+ga4:
+  currency: EUR
+  tier: standard
+conversions:
+  - name: contact_form
+    counting_method: ONCE_PER_SESSION
+`,
+		"lib/web-vitals.yaml": `
+dimensions:
+  - parameter: metric_rating
+    display_name: Web Vital Rating
+    scope: EVENT
+search_console:
+  site_url: ${site}
+`,
+		"site.yaml": `
+include:
+  - lib/seo-conversions.yaml
+  - lib/web-vitals.yaml
+vars:
+  site: https://example.com/
+project:
+  name: Site
+ga4:
+  property_id: "123456789"
+conversions:
+  - name: newsletter_signup
+    counting_method: ONCE_PER_SESSION
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+dimensions:
+  - parameter: author
+    display_name: Author
+    scope: EVENT
+`,
+	})
+
+	pc, err := LoadConfig(filepath.Join(dir, "site.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "123456789", pc.GetPropertyID())
+	assert.Equal(t, "EUR", pc.GA4.Currency, "mappings merge key by key")
+
+	var conversions []string
+	for _, c := range pc.Conversions {
+		conversions = append(conversions, c.Name+":"+c.CountingMethod)
+	}
+	assert.Equal(t, []string{
+		"contact_form:ONCE_PER_EVENT",
+		"newsletter_signup:ONCE_PER_SESSION",
+		"purchase:ONCE_PER_EVENT",
+	}, conversions, "later files replace entries in place and append new ones")
+
+	require.Len(t, pc.Dimensions, 2)
+	assert.Equal(t, "metric_rating", pc.Dimensions[0].ParameterName)
+	assert.Equal(t, "https://example.com/", pc.SearchConsole.SiteURL, "fragments use the project's vars")
+}
+
+func TestLoadConfig_IncludeCycle(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.yaml":     "include: lib/b.yaml\nproject:\n  name: A\n",
+		"lib/b.yaml": "include: ../a.yaml\n",
+	})
+	_, err := LoadConfig(filepath.Join(dir, "a.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle: ")
+	assert.Contains(t, err.Error(), filepath.Join("lib", "b.yaml")+" -> "+filepath.Join(dir, "a.yaml"))
+}
+
+func TestLoadConfig_IncludeErrors(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"missing.yaml": "include: nope.yaml\nproject:\n  name: A\n",
+		"bad.yaml":     "include:\n  - {path: x.yaml}\nproject:\n  name: A\n",
+	})
+	_, err := LoadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "line 1: failed to read include")
+
+	_, err = LoadConfig(filepath.Join(dir, "bad.yaml"))
+	assert.ErrorContains(t, err, "include must be a file path or a list of file paths")
+
+	_, err = ParseConfig([]byte("include: /etc/passwd\nproject:\n  name: A\n"))
+	assert.ErrorContains(t, err, "include is only supported in config files")
+}
+
+func TestLoadConfig_IncludeExampleLibrary(t *testing.T) {
+	lib, err := filepath.Abs(filepath.Join("..", "..", "configs", "examples", "lib", "core-web-vitals-dimensions.yaml"))
+	require.NoError(t, err)
+	dir := writeConfigFiles(t, map[string]string{
+		"site.yaml": "include: " + lib + "\nproject:\n  name: A\nga4:\n  property_id: \"123456789\"\n",
+	})
+
+	pc, err := LoadConfig(filepath.Join(dir, "site.yaml"))
+	require.NoError(t, err)
+	assert.Len(t, pc.Dimensions, 3)
+	assert.Len(t, pc.Metrics, 1)
+}
@@ -1,6 +1,9 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // GA4Tier represents a GA4 account tier
 type GA4Tier string
@@ -10,9 +13,12 @@ const (
 	Tier360      GA4Tier = "360"      // Paid tier
 )
 
-// TierLimits defines the limits for each GA4 tier
+// TierLimits defines the limits for each GA4 tier. Custom dimensions are
+// limited per scope: CustomDimensions counts the event-scoped ones.
 type TierLimits struct {
 	CustomDimensions int
+	UserDimensions   int
+	ItemDimensions   int
 	CustomMetrics    int
 	Conversions      int
 }
@@ -23,6 +29,8 @@ func GetTierLimits(tier string) TierLimits {
 	case Tier360:
 		return TierLimits{
 			CustomDimensions: 125,
+			UserDimensions:   100,
+			ItemDimensions:   25,
 			CustomMetrics:    125,
 			Conversions:      50,
 		}
@@ -30,6 +38,8 @@ func GetTierLimits(tier string) TierLimits {
 		// Default to standard (free) tier
 		return TierLimits{
 			CustomDimensions: 50,
+			UserDimensions:   25,
+			ItemDimensions:   10,
 			CustomMetrics:    50,
 			Conversions:      30,
 		}
@@ -37,12 +47,55 @@ func GetTierLimits(tier string) TierLimits {
 		// Unknown tier, default to standard
 		return TierLimits{
 			CustomDimensions: 50,
+			UserDimensions:   25,
+			ItemDimensions:   10,
 			CustomMetrics:    50,
 			Conversions:      30,
 		}
 	}
 }
 
+// DimensionLimit returns the custom dimension limit of a scope; an empty or
+// unknown scope counts as EVENT.
+func (l TierLimits) DimensionLimit(scope string) int {
+	switch scope {
+	case "USER":
+		return l.UserDimensions
+	case "ITEM":
+		return l.ItemDimensions
+	default:
+		return l.CustomDimensions
+	}
+}
+
+// DimensionScopes lists the custom dimension scopes in the order limits are
+// reported.
+func DimensionScopes() []string {
+	return []string{"EVENT", "USER", "ITEM"}
+}
+
+// DimensionScope maps the scope of a dimension, as configured or as the API
+// reports it, onto DimensionScopes.
+func DimensionScope(scope string) string {
+	if scope == "USER" || scope == "ITEM" {
+		return scope
+	}
+	return "EVENT"
+}
+
+// PriorityRank orders priorities for tier limits: high before medium before
+// everything else, which counts as low.
+func PriorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 0
+	case "medium":
+		return 1
+	default:
+		return 2
+	}
+}
+
 // ValidateTierLimits checks if a config exceeds tier limits
 func ValidateTierLimits(cfg *ProjectConfig) []string {
 	var warnings []string
@@ -57,23 +110,29 @@ func ValidateTierLimits(cfg *ProjectConfig) []string {
 	// Check conversions
 	if len(cfg.Conversions) > limits.Conversions {
 		warnings = append(warnings, fmt.Sprintf(
-			"Config has %d conversions but %s tier limit is %d. Excess conversions will fail to create.",
+			"Config has %d conversions but %s tier limit is %d. Setup leaves out the lowest priority ones.",
 			len(cfg.Conversions), tier, limits.Conversions,
 		))
 	}
 
-	// Check dimensions
-	if len(cfg.Dimensions) > limits.CustomDimensions {
-		warnings = append(warnings, fmt.Sprintf(
-			"Config has %d custom dimensions but %s tier limit is %d. Excess dimensions will fail to create.",
-			len(cfg.Dimensions), tier, limits.CustomDimensions,
-		))
+	// Check dimensions, per scope
+	dimensions := make(map[string]int)
+	for _, dim := range cfg.Dimensions {
+		dimensions[DimensionScope(dim.Scope)]++
+	}
+	for _, scope := range DimensionScopes() {
+		if dimensions[scope] > limits.DimensionLimit(scope) {
+			warnings = append(warnings, fmt.Sprintf(
+				"Config has %d %s-scoped custom dimensions but %s tier limit is %d. Setup leaves out the lowest priority ones.",
+				dimensions[scope], strings.ToLower(scope), tier, limits.DimensionLimit(scope),
+			))
+		}
 	}
 
 	// Check metrics
 	if len(cfg.Metrics) > limits.CustomMetrics {
 		warnings = append(warnings, fmt.Sprintf(
-			"Config has %d custom metrics but %s tier limit is %d. Excess metrics will fail to create.",
+			"Config has %d custom metrics but %s tier limit is %d. Setup leaves out the lowest priority ones.",
 			len(cfg.Metrics), tier, limits.CustomMetrics,
 		))
 	}
@@ -3,13 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// LoadConfig loads a project configuration from a YAML file
+// LoadConfig loads a project configuration from a YAML file, with the
+// fragments it includes (see include.go). ${NAME} references are filled
+// from --set values, the vars block and the environment (see vars.go).
 func LoadConfig(path string) (*ProjectConfig, error) {
 	// Read file
 	data, err := os.ReadFile(path)
@@ -17,11 +19,43 @@ func LoadConfig(path string) (*ProjectConfig, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	return parseConfig(data, path, &variables{set: setVariables, env: true})
+}
+
+// ParseConfig parses and validates a project configuration from raw YAML.
+// Used by callers that receive a config over the wire instead of from disk,
+// so ${NAME} references resolve from the config's vars block only: the
+// environment is never read. Nor are local files, so include is rejected.
+func ParseConfig(data []byte) (*ProjectConfig, error) {
+	return parseConfig(data, "", &variables{})
+}
+
+// parseConfig parses data, read from path, or from the wire when path is
+// empty.
+func parseConfig(data []byte, path string, vars *variables) (*ProjectConfig, error) {
 	// Parse YAML
-	var config ProjectConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	if path != "" {
+		if err := resolveIncludes(&doc, path); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	} else if root := documentRoot(&doc); root != nil {
+		if includes, err := takeIncludes(root); err != nil || len(includes) > 0 {
+			return nil, fmt.Errorf("invalid config: include is only supported in config files")
+		}
+	}
+	if err := substituteVariables(&doc, vars); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	var config ProjectConfig
+	if doc.Kind != 0 {
+		if err := doc.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
 
 	// Validate config
 	if err := validateConfig(&config); err != nil {
@@ -31,61 +65,15 @@ func LoadConfig(path string) (*ProjectConfig, error) {
 	return &config, nil
 }
 
-// LoadConfigByName loads a config from the configs/examples directory by name
+// LoadConfigByName loads a config of the config directory by name
 // For example: "my-project" loads "configs/examples/my-project.yaml" or "configs/my-project.yaml"
 func LoadConfigByName(name string) (*ProjectConfig, error) {
-	// Try configs/examples/{name}.yaml
-	path := filepath.Join("configs", "examples", name+".yaml")
-	if _, err := os.Stat(path); err == nil {
-		return LoadConfig(path)
-	}
-
-	// Try configs/{name}.yaml
-	path = filepath.Join("configs", name+".yaml")
-	if _, err := os.Stat(path); err == nil {
-		return LoadConfig(path)
-	}
-
-	return nil, fmt.Errorf("config not found: %s (looked in configs/ and configs/examples/)", name)
+	return DefaultRegistry().Load(name)
 }
 
 // ListAvailableConfigs returns a list of available config files
 func ListAvailableConfigs() ([]string, error) {
-	var configs []string
-
-	// Check configs/examples/
-	examplesPath := filepath.Join("configs", "examples")
-	if entries, err := os.ReadDir(examplesPath); err == nil {
-		for _, entry := range entries {
-			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
-				name := strings.TrimSuffix(entry.Name(), ".yaml")
-				configs = append(configs, name)
-			}
-		}
-	}
-
-	// Check configs/
-	configsPath := "configs"
-	if entries, err := os.ReadDir(configsPath); err == nil {
-		for _, entry := range entries {
-			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
-				name := strings.TrimSuffix(entry.Name(), ".yaml")
-				// Don't duplicate if already found in examples
-				found := false
-				for _, existing := range configs {
-					if existing == name {
-						found = true
-						break
-					}
-				}
-				if !found {
-					configs = append(configs, name)
-				}
-			}
-		}
-	}
-
-	return configs, nil
+	return DefaultRegistry().Names()
 }
 
 // validateConfig validates a ProjectConfig
@@ -95,14 +83,72 @@ func validateConfig(config *ProjectConfig) error {
 		return fmt.Errorf("project.name is required")
 	}
 
+	// A multi-property config is valid when each property's config is
+	if config.IsMultiProperty() {
+		return validateProperties(config)
+	}
+
 	// Validate GA4 config (only if GA4 features are configured)
 	hasGA4Config := config.GA4.PropertyID != "" || len(config.Conversions) > 0 ||
 		len(config.Dimensions) > 0 || len(config.Metrics) > 0
 
-	if hasGA4Config && config.GA4.PropertyID == "" {
+	if hasGA4Config && config.GetPropertyID() == "" {
 		return fmt.Errorf("ga4.property_id is required when using GA4 features")
 	}
 
+	// Validate feature flags
+	if err := validateFeatures("ga4", config.GA4.Features); err != nil {
+		return err
+	}
+	if config.Analytics != nil {
+		if err := validateFeatures("analytics", config.Analytics.Features); err != nil {
+			return err
+		}
+	}
+
+	// Validate setup selectors
+	if config.Setup != nil {
+		if _, err := NewResourceScope(config.Setup.Only, config.Setup.Skip); err != nil {
+			return fmt.Errorf("setup.%w", err)
+		}
+		if err := validateConflictMode("setup.on_conflict", config.Setup.OnConflict); err != nil {
+			return err
+		}
+		if err := ValidateSetupConcurrency("setup.concurrency", config.Setup.Concurrency); err != nil {
+			return err
+		}
+	}
+	for i, conv := range config.Conversions {
+		if err := validateConflictMode(fmt.Sprintf("conversions[%d].on_conflict", i), conv.OnConflict); err != nil {
+			return err
+		}
+	}
+	for i, dim := range config.Dimensions {
+		if err := validateConflictMode(fmt.Sprintf("dimensions[%d].on_conflict", i), dim.OnConflict); err != nil {
+			return err
+		}
+	}
+	for i, metric := range config.Metrics {
+		if err := validateConflictMode(fmt.Sprintf("metrics[%d].on_conflict", i), metric.OnConflict); err != nil {
+			return err
+		}
+	}
+
+	// Validate Tag Manager container
+	if tm := config.TagManager; tm != nil {
+		for _, f := range []struct{ field, id string }{{"account_id", tm.AccountID}, {"container_id", tm.ContainerID}} {
+			if f.id == "" {
+				return fmt.Errorf("tag_manager.%s is required", f.field)
+			}
+			if !isDigits(f.id) {
+				return fmt.Errorf("tag_manager.%s must be the numeric ID from the GTM URL, got %q", f.field, f.id)
+			}
+		}
+		if tm.WorkspaceID != "" && !isDigits(tm.WorkspaceID) {
+			return fmt.Errorf("tag_manager.workspace_id must be numeric, got %q", tm.WorkspaceID)
+		}
+	}
+
 	// Validate conversions
 	for i, conv := range config.Conversions {
 		if conv.Name == "" {
@@ -112,6 +158,9 @@ func validateConfig(config *ProjectConfig) error {
 			return fmt.Errorf("conversions[%d].counting_method must be ONCE_PER_SESSION or ONCE_PER_EVENT", i)
 		}
 	}
+	if err := validateConversionValues(config); err != nil {
+		return err
+	}
 
 	// Validate dimensions
 	for i, dim := range config.Dimensions {
@@ -152,6 +201,62 @@ func validateConfig(config *ProjectConfig) error {
 		if calc.Formula == "" {
 			return fmt.Errorf("calculated_metrics[%d].formula is required", i)
 		}
+		if strings.Trim(calc.ID, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_") != "" {
+			return fmt.Errorf("calculated_metrics[%d].id may only contain letters, digits and underscores", i)
+		}
+	}
+
+	// Validate channel groups; rule expressions are parsed in setup preflight
+	if len(config.ChannelGroups) > 0 && !config.Features().Enabled(FeatureChannelGroups) {
+		return fmt.Errorf("channel_groups are configured but analytics.features.channel_groups is false; remove them or enable the feature")
+	}
+	groupNames := make(map[string]bool, len(config.ChannelGroups))
+	for i, g := range config.ChannelGroups {
+		if g.DisplayName == "" {
+			return fmt.Errorf("channel_groups[%d].display_name is required", i)
+		}
+		if groupNames[g.DisplayName] {
+			return fmt.Errorf("channel_groups[%d].display_name %q is used twice", i, g.DisplayName)
+		}
+		groupNames[g.DisplayName] = true
+		if len(g.Rules) == 0 {
+			return fmt.Errorf("channel_groups[%d].rules needs at least one rule", i)
+		}
+		for j, r := range g.Rules {
+			if r.DisplayName == "" {
+				return fmt.Errorf("channel_groups[%d].rules[%d].display_name is required", i, j)
+			}
+			if r.Expression == "" {
+				return fmt.Errorf("channel_groups[%d].rules[%d].expression is required", i, j)
+			}
+		}
+	}
+
+	// Validate content groups
+	contentGroupNames := make(map[string]bool, len(config.ContentGroups))
+	for i, g := range config.ContentGroups {
+		if g.Name == "" {
+			return fmt.Errorf("content_groups[%d].name is required", i)
+		}
+		if contentGroupNames[g.Name] {
+			return fmt.Errorf("content_groups[%d].name %q is used twice", i, g.Name)
+		}
+		contentGroupNames[g.Name] = true
+		if (g.Prefix == "") == (g.Pattern == "") {
+			return fmt.Errorf("content_groups[%d] needs exactly one of prefix or pattern", i)
+		}
+		if g.Pattern != "" {
+			if _, err := regexp.Compile(g.Pattern); err != nil {
+				return fmt.Errorf("content_groups[%d].pattern is not a valid regex: %w", i, err)
+			}
+		}
+	}
+
+	// Validate insight rules; conditions are parsed by ga4 insights run
+	if config.Insights != nil {
+		if err := validateInsightsConfig(config.Insights); err != nil {
+			return err
+		}
 	}
 
 	// Validate data retention
@@ -178,6 +283,42 @@ func validateConfig(config *ProjectConfig) error {
 	return nil
 }
 
+// validateInsightsConfig validates the insights section.
+func validateInsightsConfig(in *InsightsConfig) error {
+	names := make(map[string]bool, len(in.Rules))
+	for i, r := range in.Rules {
+		if r.Name == "" {
+			return fmt.Errorf("insights.rules[%d].name is required", i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("insights.rules[%d].name %q is used twice", i, r.Name)
+		}
+		names[r.Name] = true
+		if r.When == "" {
+			return fmt.Errorf("insights.rules[%d].when is required", i)
+		}
+		switch r.By {
+		case "", InsightByTotal, InsightByPage, InsightByQuery, InsightByContentGroup:
+		default:
+			return fmt.Errorf("insights.rules[%d].by must be one of: total, page, query, content_group", i)
+		}
+		switch r.Severity {
+		case "", "high", "medium", "low":
+		default:
+			return fmt.Errorf("insights.rules[%d].severity must be one of: high, medium, low", i)
+		}
+		if r.Days < 0 || r.Days > 240 {
+			return fmt.Errorf("insights.rules[%d].days must be between 1 and 240", i)
+		}
+	}
+	for i, n := range in.Notify {
+		if (n.Webhook == "") == (n.File == "") {
+			return fmt.Errorf("insights.notify[%d] needs exactly one of webhook or file", i)
+		}
+	}
+	return nil
+}
+
 // validateSearchConsoleConfig validates Search Console configuration
 func validateSearchConsoleConfig(sc *SearchConsoleConfig) error {
 	// Validate site URL
@@ -202,6 +343,20 @@ func validateSearchConsoleConfig(sc *SearchConsoleConfig) error {
 		}
 	}
 
+	seen := make(map[string]bool, len(sc.Sitemaps))
+	for i, sm := range sc.Sitemaps {
+		if sm.URL == "" {
+			return fmt.Errorf("sitemaps[%d].url is required", i)
+		}
+		if seen[sm.URL] {
+			return fmt.Errorf("sitemaps[%d].url %s is listed twice", i, sm.URL)
+		}
+		seen[sm.URL] = true
+		if sm.Remove && sm.AutoSubmit {
+			return fmt.Errorf("sitemaps[%d] sets both remove and auto_submit: %s", i, sm.URL)
+		}
+	}
+
 	// Validate URL inspection config
 	if sc.URLInspection != nil {
 		for i, url := range sc.URLInspection.PriorityURLs {
@@ -219,9 +374,43 @@ func validateSearchConsoleConfig(sc *SearchConsoleConfig) error {
 			if pattern.Pattern == "" {
 				return fmt.Errorf("url_inspection.patterns[%d].pattern is required", i)
 			}
+			if pattern.Sample < 0 {
+				return fmt.Errorf("url_inspection.patterns[%d].sample must not be negative", i)
+			}
+		}
+
+		for i, alt := range sc.URLInspection.ExpectedAlternates {
+			if alt.Pattern == "" {
+				return fmt.Errorf("url_inspection.expected_alternates[%d].pattern is required", i)
+			}
+			switch alt.Kind {
+			case "language", "mobile", "amp", "other":
+			default:
+				return fmt.Errorf("url_inspection.expected_alternates[%d].kind %q is invalid (want language, mobile, amp or other)", i, alt.Kind)
+			}
+		}
+	}
+
+	for name, curve := range sc.CTRBenchmarks {
+		for bucket, ctr := range curve {
+			if ctr <= 0 || ctr > 1 {
+				return fmt.Errorf("ctr_benchmarks.%s.%s must be a CTR in (0, 1], got %g", name, bucket, ctr)
+			}
 		}
 	}
 
 	return nil
 }
 
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PropertyConfig is one entry of a multi-property config's properties list:
+// a GA4 property and/or Search Console site the config's shared resources
+// are set up on, such as staging and production, or each site of a brand
+// portfolio.
+//
+//	properties:
+//	  - name: staging
+//	    analytics: { property_id: "111111111" }
+//	  - name: production
+//	    analytics: { property_id: "222222222", tier: "360" }
+//	    search_console: { site_url: "https://example.com" }
+type PropertyConfig struct {
+	// Name tells the properties apart; it is appended to the project name.
+	Name string `yaml:"name"`
+
+	// Analytics and SearchConsole override the shared blocks field by field.
+	Analytics     *AnalyticsConfig     `yaml:"analytics,omitempty"`
+	SearchConsole *SearchConsoleConfig `yaml:"search_console,omitempty"`
+
+	// Conversions, dimensions and metrics are merged into the shared lists:
+	// an entry with the same event or parameter name replaces the shared
+	// one, others are added.
+	Conversions []ConversionConfig `yaml:"conversions,omitempty"`
+	Dimensions  []DimensionConfig  `yaml:"dimensions,omitempty"`
+	Metrics     []MetricConfig     `yaml:"metrics,omitempty"`
+
+	// The remaining blocks replace the shared ones.
+	DataRetention       *DataRetentionConfig       `yaml:"data_retention,omitempty"`
+	EnhancedMeasurement *EnhancedMeasurementConfig `yaml:"enhanced_measurement,omitempty"`
+	Setup               *SetupConfig               `yaml:"setup,omitempty"`
+	TagManager          *TagManagerConfig          `yaml:"tag_manager,omitempty"`
+}
+
+// IsMultiProperty reports whether the config lists properties.
+func (pc *ProjectConfig) IsMultiProperty() bool {
+	return len(pc.Properties) > 0
+}
+
+// ExpandProperties returns one config per entry of the properties list,
+// each with the shared definitions and that entry's overrides applied, and
+// PropertyName set. A config without properties is returned as it is.
+func (pc *ProjectConfig) ExpandProperties() ([]*ProjectConfig, error) {
+	if !pc.IsMultiProperty() {
+		return []*ProjectConfig{pc}, nil
+	}
+	shared := *pc
+	shared.Properties = nil
+	data, err := yaml.Marshal(&shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy shared config: %w", err)
+	}
+
+	expanded := make([]*ProjectConfig, 0, len(pc.Properties))
+	for _, p := range pc.Properties {
+		var cfg ProjectConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to copy shared config: %w", err)
+		}
+		cfg.applyProperty(p)
+		expanded = append(expanded, &cfg)
+	}
+	return expanded, nil
+}
+
+// applyProperty layers the overrides of p on a copy of the shared config.
+func (pc *ProjectConfig) applyProperty(p PropertyConfig) {
+	pc.Project.Name = fmt.Sprintf("%s (%s)", pc.Project.Name, p.Name)
+	pc.PropertyName = p.Name
+
+	if p.Analytics != nil {
+		analytics := pc.GA4
+		if pc.Analytics != nil {
+			analytics = *pc.Analytics
+		}
+		mergeAnalytics(&analytics, p.Analytics)
+		pc.Analytics = &analytics
+		pc.GA4 = GA4Config{}
+	}
+	if p.SearchConsole != nil {
+		sc := SearchConsoleConfig{}
+		if pc.SearchConsole != nil {
+			sc = *pc.SearchConsole
+		}
+		mergeSearchConsole(&sc, p.SearchConsole)
+		pc.SearchConsole = &sc
+	}
+
+	pc.Conversions = mergeByKey(pc.Conversions, p.Conversions, func(c ConversionConfig) string { return c.Name })
+	pc.Dimensions = mergeByKey(pc.Dimensions, p.Dimensions, func(d DimensionConfig) string { return d.ParameterName })
+	pc.Metrics = mergeByKey(pc.Metrics, p.Metrics, func(m MetricConfig) string { return m.ParameterName })
+
+	if p.DataRetention != nil {
+		pc.DataRetention = p.DataRetention
+	}
+	if p.EnhancedMeasurement != nil {
+		pc.EnhancedMeasurement = p.EnhancedMeasurement
+	}
+	if p.Setup != nil {
+		pc.Setup = p.Setup
+	}
+	if p.TagManager != nil {
+		pc.TagManager = p.TagManager
+	}
+}
+
+func mergeAnalytics(dst, src *AnalyticsConfig) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&dst.PropertyID, src.PropertyID},
+		{&dst.MeasurementID, src.MeasurementID},
+		{&dst.DataStreamID, src.DataStreamID},
+		{&dst.Tier, src.Tier},
+		{&dst.Currency, src.Currency},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if len(src.Features) > 0 {
+		features := make(map[string]bool, len(dst.Features)+len(src.Features))
+		for k, v := range dst.Features {
+			features[k] = v
+		}
+		for k, v := range src.Features {
+			features[k] = v
+		}
+		dst.Features = features
+	}
+}
+
+func mergeSearchConsole(dst, src *SearchConsoleConfig) {
+	if src.SiteURL != "" {
+		dst.SiteURL = src.SiteURL
+	}
+	if len(src.Sitemaps) > 0 {
+		dst.Sitemaps = src.Sitemaps
+	}
+	if src.URLInspection != nil {
+		dst.URLInspection = src.URLInspection
+	}
+	if src.SearchAnalytics != nil {
+		dst.SearchAnalytics = src.SearchAnalytics
+	}
+	if len(src.CTRBenchmarks) > 0 {
+		dst.CTRBenchmarks = src.CTRBenchmarks
+	}
+}
+
+// mergeByKey replaces the shared entries that overrides has a key for, in
+// place, and appends the other overrides in their order.
+func mergeByKey[T any](shared, overrides []T, key func(T) string) []T {
+	if len(overrides) == 0 {
+		return shared
+	}
+	index := make(map[string]int, len(shared))
+	for i, s := range shared {
+		index[key(s)] = i
+	}
+	merged := append([]T(nil), shared...)
+	for _, o := range overrides {
+		if i, ok := index[key(o)]; ok {
+			merged[i] = o
+			continue
+		}
+		index[key(o)] = len(merged)
+		merged = append(merged, o)
+	}
+	return merged
+}
+
+// validateProperties checks the properties list and validates each expanded
+// config in place of the shared one, which may lack property IDs.
+func validateProperties(pc *ProjectConfig) error {
+	names := make(map[string]bool, len(pc.Properties))
+	for i, p := range pc.Properties {
+		if p.Name == "" {
+			return fmt.Errorf("properties[%d].name is required", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("properties[%d].name %q is used twice", i, p.Name)
+		}
+		names[p.Name] = true
+	}
+	expanded, err := pc.ExpandProperties()
+	if err != nil {
+		return err
+	}
+	for i, cfg := range expanded {
+		if !cfg.HasAnalytics() && !cfg.HasSearchConsole() {
+			return fmt.Errorf("properties[%d] (%s) has no GA4 property or Search Console site", i, cfg.PropertyName)
+		}
+		if err := validateConfig(cfg); err != nil {
+			return fmt.Errorf("properties[%d] (%s): %w", i, cfg.PropertyName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multiPropertyYAML = `
+project:
+  name: Brand
+ga4:
+  property_id: "100000000"
+  currency: EUR
+search_console:
+  site_url: https://staging.example.com/
+  sitemaps:
+    - url: https://staging.example.com/sitemap.xml
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+  - name: sign_up
+    counting_method: ONCE_PER_SESSION
+properties:
+  - name: staging
+    analytics:
+      property_id: "111111111"
+  - name: production
+    analytics:
+      property_id: "222222222"
+      tier: "360"
+    search_console:
+      site_url: https://example.com/
+    conversions:
+      - name: purchase
+        counting_method: ONCE_PER_SESSION
+      - name: newsletter
+        counting_method: ONCE_PER_EVENT
+`
+
+func TestExpandProperties(t *testing.T) {
+	pc, err := ParseConfig([]byte(multiPropertyYAML))
+	require.NoError(t, err)
+
+	expanded, err := pc.ExpandProperties()
+	require.NoError(t, err)
+	require.Len(t, expanded, 2)
+
+	staging, production := expanded[0], expanded[1]
+	assert.Equal(t, "Brand (staging)", staging.Project.Name)
+	assert.Equal(t, "staging", staging.PropertyName)
+	assert.Equal(t, "111111111", staging.GetPropertyID())
+	assert.Equal(t, "EUR", staging.Analytics.Currency, "shared analytics fields are kept")
+	assert.Equal(t, "https://staging.example.com/", staging.SearchConsole.SiteURL)
+	assert.Equal(t, "ONCE_PER_EVENT", staging.Conversions[0].CountingMethod)
+
+	assert.Equal(t, "222222222", production.GetPropertyID())
+	assert.Equal(t, "360", production.Analytics.Tier)
+	assert.Equal(t, "https://example.com/", production.SearchConsole.SiteURL)
+	assert.Len(t, production.SearchConsole.Sitemaps, 1, "unset overrides keep the shared value")
+	require.Len(t, production.Conversions, 3)
+	assert.Equal(t, "ONCE_PER_SESSION", production.Conversions[0].CountingMethod, "same name replaces in place")
+	assert.Equal(t, "newsletter", production.Conversions[2].Name)
+
+	assert.Len(t, pc.Conversions, 2, "the shared config is not modified")
+	assert.Empty(t, production.Properties)
+}
+
+func TestExpandProperties_SingleProperty(t *testing.T) {
+	pc := &ProjectConfig{Project: ProjectInfo{Name: "Test"}}
+	expanded, err := pc.ExpandProperties()
+	require.NoError(t, err)
+	assert.Equal(t, []*ProjectConfig{pc}, expanded)
+}
+
+func TestValidateConfig_Properties(t *testing.T) {
+	pc, err := ParseConfig([]byte(multiPropertyYAML))
+	require.NoError(t, err)
+
+	pc.Properties[1].Name = "staging"
+	assert.ErrorContains(t, validateConfig(pc), `properties[1].name "staging" is used twice`)
+
+	pc.Properties[1].Name = "production"
+	pc.Properties[1].Conversions[0].CountingMethod = "ALWAYS"
+	assert.ErrorContains(t, validateConfig(pc), "properties[1] (production): conversions[0].counting_method")
+}
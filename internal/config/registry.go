@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultConfigDir is the directory project configs are looked up in by
+// name unless SetConfigDir chose another.
+const DefaultConfigDir = "configs"
+
+// ConfigDirEnvVar names the environment variable `--config-dir` falls back
+// to.
+const ConfigDirEnvVar = "GA4_CONFIG_DIR"
+
+var configDir = DefaultConfigDir
+
+// SetConfigDir sets the directory DefaultRegistry looks in; empty restores
+// DefaultConfigDir.
+func SetConfigDir(dir string) {
+	if dir == "" {
+		dir = DefaultConfigDir
+	}
+	configDir = dir
+}
+
+// ConfigDir returns the directory project configs live in.
+func ConfigDir() string {
+	return configDir
+}
+
+// Registry finds the project configs of a directory by name: a name is the
+// file name without .yaml, looked up in the examples subdirectory first and
+// then in the directory itself.
+type Registry struct {
+	Dir string
+}
+
+// DefaultRegistry returns the registry of ConfigDir.
+func DefaultRegistry() *Registry {
+	return &Registry{Dir: configDir}
+}
+
+func (r *Registry) dirs() []string {
+	return []string{filepath.Join(r.Dir, "examples"), r.Dir}
+}
+
+// Names lists the configs in the registry, examples first, each once.
+func (r *Registry) Names() ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, dir := range r.dirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		var found []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
+				name := strings.TrimSuffix(entry.Name(), ".yaml")
+				if !seen[name] {
+					seen[name] = true
+					found = append(found, name)
+				}
+			}
+		}
+		sort.Strings(found)
+		names = append(names, found...)
+	}
+	return names, nil
+}
+
+// Path returns the file of the config called name.
+func (r *Registry) Path(name string) (string, error) {
+	for _, dir := range r.dirs() {
+		path := filepath.Join(dir, name+".yaml")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("config not found: %s (looked in %s/ and %s/)", name, r.Dir, filepath.Join(r.Dir, "examples"))
+}
+
+// Load loads the config called name.
+func (r *Registry) Load(name string) (*ProjectConfig, error) {
+	path, err := r.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfig(path)
+}
+
+// LoadAll loads every config in the registry and returns them with their
+// files. A config that fails to load is left out and reported in failed.
+func (r *Registry) LoadAll() (configs []*ProjectConfig, paths []string, failed []error) {
+	names, _ := r.Names()
+	for _, name := range names {
+		path, err := r.Path(name)
+		if err == nil {
+			var cfg *ProjectConfig
+			if cfg, err = LoadConfig(path); err == nil {
+				configs = append(configs, cfg)
+				paths = append(paths, path)
+				continue
+			}
+		}
+		failed = append(failed, fmt.Errorf("%s: %w", name, err))
+	}
+	return configs, paths, failed
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"examples/blog.yaml": "project:\n  name: Example Blog\n",
+		"examples/shop.yaml": "project:\n  name: Example Shop\n",
+		"shop.yaml":          "project:\n  name: My Shop\n",
+		"app.yaml":           "project:\n  name: My App\n",
+		"broken.yaml":        "project: [\n",
+		"README.md":          "not a config",
+	})
+	r := &Registry{Dir: dir}
+
+	names, err := r.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blog", "shop", "app", "broken"}, names)
+
+	path, err := r.Path("shop")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "examples", "shop.yaml"), path)
+
+	cfg, err := r.Load("app")
+	require.NoError(t, err)
+	assert.Equal(t, "My App", cfg.Project.Name)
+
+	_, err = r.Load("missing")
+	assert.ErrorContains(t, err, "config not found: missing")
+
+	configs, paths, failed := r.LoadAll()
+	require.Len(t, configs, 3)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "examples", "blog.yaml"),
+		filepath.Join(dir, "examples", "shop.yaml"),
+		filepath.Join(dir, "app.yaml"),
+	}, paths)
+	require.Len(t, failed, 1)
+	assert.ErrorContains(t, failed[0], "broken")
+}
+
+func TestSetConfigDir(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"site.yaml": "project:\n  name: Site\n",
+	})
+	SetConfigDir(dir)
+	t.Cleanup(func() { SetConfigDir("") })
+
+	assert.Equal(t, dir, ConfigDir())
+	cfg, err := LoadConfigByName("site")
+	require.NoError(t, err)
+	assert.Equal(t, "Site", cfg.Project.Name)
+
+	SetConfigDir("")
+	assert.Equal(t, DefaultConfigDir, ConfigDir())
+}
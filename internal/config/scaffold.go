@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Site types `ga4 config init` has a starter config for.
+const (
+	SiteTypeEcommerce = "ecommerce"
+	SiteTypeBlog      = "blog"
+	SiteTypeSaaS      = "saas"
+	SiteTypeSPA       = "spa"
+)
+
+// SiteTypes lists the site types Scaffold accepts.
+func SiteTypes() []string {
+	return []string{SiteTypeEcommerce, SiteTypeBlog, SiteTypeSaaS, SiteTypeSPA}
+}
+
+// ScaffoldOptions are the answers a starter config is built from. At least
+// one of PropertyID and SiteURL is required.
+type ScaffoldOptions struct {
+	Name       string
+	PropertyID string
+	SiteURL    string // URL prefix or sc-domain: property
+	SiteType   string // one of SiteTypes
+	Tier       string // "standard" (default) or "360"
+}
+
+// starter holds the resources suggested for a site type. Low priority
+// resources are only scaffolded for GA4 360, whose limits leave room for
+// them.
+type starter struct {
+	conversions []ConversionConfig
+	dimensions  []DimensionConfig
+	metrics     []MetricConfig
+	enhanced    EnhancedMeasurementConfig
+}
+
+var starters = map[string]starter{
+	SiteTypeEcommerce: {
+		conversions: []ConversionConfig{
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT", Description: "Order completed", Priority: "high"},
+			{Name: "begin_checkout", CountingMethod: "ONCE_PER_SESSION", Description: "Checkout started", Priority: "high"},
+			{Name: "add_to_cart", CountingMethod: "ONCE_PER_EVENT", Description: "Product added to cart", Priority: "medium"},
+			{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION", Description: "Customer account created", Priority: "medium"},
+			{Name: "add_to_wishlist", CountingMethod: "ONCE_PER_EVENT", Description: "Product added to wishlist", Priority: "low"},
+		},
+		dimensions: []DimensionConfig{
+			{ParameterName: "product_category", DisplayName: "Product Category", Scope: "EVENT", Priority: "high"},
+			{ParameterName: "payment_method", DisplayName: "Payment Method", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "customer_type", DisplayName: "Customer Type", Description: "new or returning", Scope: "USER", Priority: "medium"},
+			{ParameterName: "coupon_code", DisplayName: "Coupon Code", Scope: "EVENT", Priority: "low"},
+		},
+		metrics: []MetricConfig{
+			{ParameterName: "shipping_cost", DisplayName: "Shipping Cost", MeasurementUnit: "CURRENCY", RestrictedMetricType: "COST_DATA", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "discount_amount", DisplayName: "Discount Amount", MeasurementUnit: "CURRENCY", RestrictedMetricType: "COST_DATA", Scope: "EVENT", Priority: "low"},
+		},
+		enhanced: EnhancedMeasurementConfig{PageViews: true, Scrolls: true, OutboundClicks: true, SiteSearch: true, FileDownloads: true, FormInteractions: true},
+	},
+	SiteTypeBlog: {
+		conversions: []ConversionConfig{
+			{Name: "newsletter_signup", CountingMethod: "ONCE_PER_SESSION", Description: "Newsletter subscription", Priority: "high"},
+			{Name: "article_read", CountingMethod: "ONCE_PER_EVENT", Description: "Article read to the end", Priority: "medium"},
+			{Name: "share", CountingMethod: "ONCE_PER_EVENT", Description: "Content shared", Priority: "low"},
+		},
+		dimensions: []DimensionConfig{
+			{ParameterName: "article_category", DisplayName: "Article Category", Scope: "EVENT", Priority: "high"},
+			{ParameterName: "author_name", DisplayName: "Author Name", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "content_type", DisplayName: "Content Type", Description: "article, video or podcast", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "publish_year", DisplayName: "Publish Year", Scope: "EVENT", Priority: "low"},
+		},
+		metrics: []MetricConfig{
+			{ParameterName: "reading_time", DisplayName: "Reading Time", MeasurementUnit: "SECONDS", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "word_count", DisplayName: "Word Count", MeasurementUnit: "STANDARD", Scope: "EVENT", Priority: "low"},
+		},
+		enhanced: EnhancedMeasurementConfig{PageViews: true, Scrolls: true, OutboundClicks: true, SiteSearch: true, VideoEngagement: true},
+	},
+	SiteTypeSaaS: {
+		conversions: []ConversionConfig{
+			{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION", Description: "Account created", Priority: "high"},
+			{Name: "generate_lead", CountingMethod: "ONCE_PER_SESSION", Description: "Demo or sales contact requested", Priority: "high"},
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT", Description: "Subscription started or upgraded", Priority: "high"},
+			{Name: "trial_start", CountingMethod: "ONCE_PER_SESSION", Description: "Free trial started", Priority: "medium"},
+			{Name: "feature_activated", CountingMethod: "ONCE_PER_EVENT", Description: "Key feature used for the first time", Priority: "low"},
+		},
+		dimensions: []DimensionConfig{
+			{ParameterName: "plan_name", DisplayName: "Plan Name", Scope: "USER", Priority: "high"},
+			{ParameterName: "feature_name", DisplayName: "Feature Name", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "signup_method", DisplayName: "Signup Method", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "company_size", DisplayName: "Company Size", Scope: "USER", Priority: "low"},
+		},
+		metrics: []MetricConfig{
+			{ParameterName: "seats", DisplayName: "Seats", MeasurementUnit: "STANDARD", Scope: "EVENT", Priority: "medium"},
+		},
+		enhanced: EnhancedMeasurementConfig{PageViews: true, Scrolls: true, OutboundClicks: true, SiteSearch: true, VideoEngagement: true, FileDownloads: true, PageChanges: true, FormInteractions: true},
+	},
+	SiteTypeSPA: {
+		conversions: []ConversionConfig{
+			{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION", Description: "Account created", Priority: "high"},
+			{Name: "generate_lead", CountingMethod: "ONCE_PER_SESSION", Description: "Contact form sent", Priority: "medium"},
+		},
+		dimensions: []DimensionConfig{
+			{ParameterName: "route_name", DisplayName: "Route Name", Description: "Client-side route of the view", Scope: "EVENT", Priority: "high"},
+			{ParameterName: "app_version", DisplayName: "App Version", Scope: "EVENT", Priority: "medium"},
+			{ParameterName: "ui_theme", DisplayName: "UI Theme", Scope: "USER", Priority: "low"},
+		},
+		metrics: []MetricConfig{
+			{ParameterName: "load_time", DisplayName: "View Load Time", MeasurementUnit: "MILLISECONDS", Scope: "EVENT", Priority: "medium"},
+		},
+		enhanced: EnhancedMeasurementConfig{PageViews: true, Scrolls: true, OutboundClicks: true, PageChanges: true, FormInteractions: true},
+	},
+}
+
+// Scaffold builds a starter config for a site type, with the resources that
+// suit it and the property's tier, ready to be written out and edited.
+func Scaffold(opts ScaffoldOptions) (*ProjectConfig, error) {
+	s, ok := starters[opts.SiteType]
+	if !ok {
+		return nil, fmt.Errorf("unknown site type %q (want one of: %s)", opts.SiteType, strings.Join(SiteTypes(), ", "))
+	}
+	if opts.PropertyID == "" && opts.SiteURL == "" {
+		return nil, fmt.Errorf("a GA4 property ID or a Search Console site URL is required")
+	}
+	tier := opts.Tier
+	if tier == "" {
+		tier = string(TierStandard)
+	}
+	if tier != string(TierStandard) && tier != string(Tier360) {
+		return nil, fmt.Errorf("unknown tier %q (want standard or 360)", opts.Tier)
+	}
+
+	pc := &ProjectConfig{Project: ProjectInfo{
+		Name:        opts.Name,
+		Description: fmt.Sprintf("Starter %s config generated by ga4 config init", opts.SiteType),
+	}}
+	if opts.PropertyID != "" {
+		limits := GetTierLimits(tier)
+		retention := "FOURTEEN_MONTHS"
+		if tier == string(Tier360) {
+			retention = "FIFTY_MONTHS"
+		}
+		enhanced := s.enhanced
+		pc.GA4 = GA4Config{PropertyID: opts.PropertyID, Tier: tier}
+		pc.Conversions = FilterConversionsByPriority(forTier(s.conversions, tier, func(c ConversionConfig) string { return c.Priority }), limits.Conversions)
+		pc.Dimensions = FilterDimensionsByPriority(forTier(s.dimensions, tier, func(d DimensionConfig) string { return d.Priority }), limits.CustomDimensions)
+		pc.Metrics = FilterMetricsByPriority(forTier(s.metrics, tier, func(m MetricConfig) string { return m.Priority }), limits.CustomMetrics)
+		pc.DataRetention = &DataRetentionConfig{EventDataRetention: retention, ResetUserDataOnNewActivity: true}
+		pc.EnhancedMeasurement = &enhanced
+	}
+	if opts.SiteURL != "" {
+		site := opts.SiteURL
+		sc := &SearchConsoleConfig{SiteURL: site}
+		if !strings.HasPrefix(site, "sc-domain:") {
+			if !strings.HasSuffix(site, "/") {
+				site += "/"
+			}
+			sc.SiteURL = site
+			sc.Sitemaps = []SitemapConfig{{URL: site + "sitemap.xml", AutoSubmit: true}}
+		}
+		pc.SearchConsole = sc
+	}
+
+	if err := validateConfig(pc); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+// forTier drops the low priority resources unless the tier is GA4 360.
+func forTier[T any](items []T, tier string, priority func(T) string) []T {
+	if tier == string(Tier360) {
+		return items
+	}
+	var kept []T
+	for _, item := range items {
+		if priority(item) != "low" {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
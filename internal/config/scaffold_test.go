@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	for _, siteType := range SiteTypes() {
+		t.Run(siteType, func(t *testing.T) {
+			standard, err := Scaffold(ScaffoldOptions{Name: "Site", PropertyID: "123456789", SiteURL: "https://example.com", SiteType: siteType})
+			require.NoError(t, err)
+			premium, err := Scaffold(ScaffoldOptions{Name: "Site", PropertyID: "123456789", SiteType: siteType, Tier: "360"})
+			require.NoError(t, err)
+
+			assert.Equal(t, "standard", standard.GA4.Tier)
+			assert.NotEmpty(t, standard.Conversions)
+			assert.Greater(t, len(premium.Dimensions), len(standard.Dimensions), "360 also gets the low priority resources")
+			for _, d := range standard.Dimensions {
+				assert.NotEqual(t, "low", d.Priority)
+			}
+			assert.Equal(t, "https://example.com/", standard.SearchConsole.SiteURL)
+			assert.Equal(t, "https://example.com/sitemap.xml", standard.SearchConsole.Sitemaps[0].URL)
+			assert.Equal(t, "FIFTY_MONTHS", premium.DataRetention.EventDataRetention)
+			assert.Nil(t, premium.SearchConsole)
+		})
+	}
+
+	spa, err := Scaffold(ScaffoldOptions{Name: "App", PropertyID: "1", SiteType: SiteTypeSPA})
+	require.NoError(t, err)
+	assert.True(t, spa.EnhancedMeasurement.PageChanges)
+
+	gscOnly, err := Scaffold(ScaffoldOptions{Name: "Site", SiteURL: "sc-domain:example.com", SiteType: SiteTypeBlog})
+	require.NoError(t, err)
+	assert.False(t, gscOnly.HasAnalytics())
+	assert.Empty(t, gscOnly.SearchConsole.Sitemaps)
+
+	_, err = Scaffold(ScaffoldOptions{Name: "Site", PropertyID: "1", SiteType: "forum"})
+	assert.ErrorContains(t, err, `unknown site type "forum"`)
+	_, err = Scaffold(ScaffoldOptions{Name: "Site", SiteType: SiteTypeBlog})
+	assert.Error(t, err)
+	_, err = Scaffold(ScaffoldOptions{Name: "Site", PropertyID: "1", SiteType: SiteTypeBlog, Tier: "enterprise"})
+	assert.ErrorContains(t, err, `unknown tier "enterprise"`)
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetupResource names a part of the setup pipeline that can be selected on
+// its own, so a re-run touches only what changed:
+//
+//	setup:
+//	  only: [dimensions, metrics]
+//	  skip: [sitemaps]
+type SetupResource string
+
+const (
+	ResourceConversions       SetupResource = "conversions"
+	ResourceDimensions        SetupResource = "dimensions"
+	ResourceMetrics           SetupResource = "metrics"
+	ResourceCalculatedMetrics SetupResource = "calculated_metrics"
+	ResourceSitemaps          SetupResource = "sitemaps"
+	ResourceChannelGroups     SetupResource = "channel_groups"
+	// Property settings
+	ResourceDataRetention       SetupResource = "data_retention"
+	ResourceEnhancedMeasurement SetupResource = "enhanced_measurement"
+)
+
+var setupResources = []SetupResource{ResourceConversions, ResourceDimensions, ResourceMetrics, ResourceCalculatedMetrics, ResourceChannelGroups, ResourceDataRetention, ResourceEnhancedMeasurement, ResourceSitemaps}
+
+// SetupConfig holds the default resource selectors for `ga4 setup`. The
+// --only/--skip flags replace the matching list when given.
+type SetupConfig struct {
+	Only []string `yaml:"only,omitempty"`
+	Skip []string `yaml:"skip,omitempty"`
+	// RequireSignedPlan makes `ga4 setup` refuse to change the property:
+	// changes go through `ga4 apply` with a plan signed by a reviewer.
+	RequireSignedPlan bool `yaml:"require_signed_plan,omitempty"`
+	// OnConflict is the default conflict mode for conversions, dimensions
+	// and metrics that set none; `ga4 setup --update` replaces it.
+	OnConflict string `yaml:"on_conflict,omitempty"`
+	// Concurrency is how many conversions, dimensions or metrics setup
+	// creates at once (default DefaultSetupConcurrency); `ga4 setup
+	// --concurrency` replaces it.
+	Concurrency int `yaml:"concurrency,omitempty"`
+}
+
+// Setup concurrency bounds. The client's rate limiter still caps requests per
+// second; past MaxSetupConcurrency workers would only queue on it.
+const (
+	DefaultSetupConcurrency = 4
+	MaxSetupConcurrency     = 10
+)
+
+// SetupConcurrency returns how many resources setup creates at once.
+func (pc *ProjectConfig) SetupConcurrency() int {
+	if pc.Setup != nil && pc.Setup.Concurrency > 0 {
+		return min(pc.Setup.Concurrency, MaxSetupConcurrency)
+	}
+	return DefaultSetupConcurrency
+}
+
+// ValidateSetupConcurrency checks a setup.concurrency or --concurrency value;
+// 0 means the default.
+func ValidateSetupConcurrency(field string, n int) error {
+	if n < 0 || n > MaxSetupConcurrency {
+		return fmt.Errorf("%s must be between 1 and %d, got %d", field, MaxSetupConcurrency, n)
+	}
+	return nil
+}
+
+// Conflict modes: what setup does with a conversion, dimension or metric
+// that already exists but differs from the config.
+const (
+	OnConflictSkip   = "skip"   // leave it as it is (the default)
+	OnConflictUpdate = "update" // patch it to match the config
+	OnConflictError  = "error"  // fail before anything is changed
+)
+
+// ConflictMode resolves the conflict mode of a resource whose own
+// on_conflict is mode: mode itself, else setup.on_conflict, else skip.
+func (pc *ProjectConfig) ConflictMode(mode string) string {
+	if mode != "" {
+		return mode
+	}
+	if pc.Setup != nil && pc.Setup.OnConflict != "" {
+		return pc.Setup.OnConflict
+	}
+	return OnConflictSkip
+}
+
+func validateConflictMode(field, mode string) error {
+	switch mode {
+	case "", OnConflictSkip, OnConflictUpdate, OnConflictError:
+		return nil
+	}
+	return fmt.Errorf("%s must be skip, update or error, got %q", field, mode)
+}
+
+// ResourceScope decides which setup resources a run includes. The zero value
+// includes everything.
+type ResourceScope struct {
+	only map[SetupResource]bool
+	skip map[SetupResource]bool
+}
+
+// NewResourceScope builds a scope from --only/--skip style lists. An empty
+// only list means every resource; skip wins over only.
+func NewResourceScope(only, skip []string) (ResourceScope, error) {
+	var s ResourceScope
+	var err error
+	if s.only, err = parseSetupResources("only", only); err != nil {
+		return ResourceScope{}, err
+	}
+	if s.skip, err = parseSetupResources("skip", skip); err != nil {
+		return ResourceScope{}, err
+	}
+	return s, nil
+}
+
+// Includes reports whether the run should touch r.
+func (s ResourceScope) Includes(r SetupResource) bool {
+	if s.skip[r] {
+		return false
+	}
+	return len(s.only) == 0 || s.only[r]
+}
+
+// Narrowed reports whether any resource is out of scope.
+func (s ResourceScope) Narrowed() bool {
+	return len(s.only) > 0 || len(s.skip) > 0
+}
+
+// SetupScope returns the resource scope from the config's setup block.
+// Names are checked at load, so an invalid one here means the config was
+// built in code and is treated as selecting nothing extra.
+func (pc *ProjectConfig) SetupScope() ResourceScope {
+	if pc.Setup == nil {
+		return ResourceScope{}
+	}
+	scope, _ := NewResourceScope(pc.Setup.Only, pc.Setup.Skip)
+	return scope
+}
+
+func parseSetupResources(field string, names []string) (map[SetupResource]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	out := make(map[SetupResource]bool, len(names))
+	for _, name := range names {
+		r := SetupResource(strings.ToLower(strings.TrimSpace(name)))
+		if !isSetupResource(r) {
+			known := make([]string, len(setupResources))
+			for i, k := range setupResources {
+				known[i] = string(k)
+			}
+			return nil, fmt.Errorf("%s: unknown setup resource %q (known: %s)", field, name, strings.Join(known, ", "))
+		}
+		out[r] = true
+	}
+	return out, nil
+}
+
+func isSetupResource(r SetupResource) bool {
+	for _, k := range setupResources {
+		if k == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceScope(t *testing.T) {
+	all := ResourceScope{}
+	assert.True(t, all.Includes(ResourceSitemaps))
+	assert.False(t, all.Narrowed())
+
+	only, err := NewResourceScope([]string{"Dimensions", " metrics"}, nil)
+	require.NoError(t, err)
+	assert.True(t, only.Includes(ResourceDimensions))
+	assert.True(t, only.Includes(ResourceMetrics))
+	assert.False(t, only.Includes(ResourceConversions))
+	assert.True(t, only.Narrowed())
+
+	both, err := NewResourceScope([]string{"dimensions", "sitemaps"}, []string{"sitemaps"})
+	require.NoError(t, err)
+	assert.True(t, both.Includes(ResourceDimensions))
+	assert.False(t, both.Includes(ResourceSitemaps), "skip wins over only")
+
+	_, err = NewResourceScope(nil, []string{"audiences"})
+	assert.ErrorContains(t, err, `skip: unknown setup resource "audiences"`)
+}
+
+func TestValidateConfig_SetupSelectors(t *testing.T) {
+	pc := &ProjectConfig{
+		Project: ProjectInfo{Name: "Test"},
+		Setup:   &SetupConfig{Skip: []string{"sitemaps"}},
+	}
+	require.NoError(t, validateConfig(pc))
+	assert.False(t, pc.SetupScope().Includes(ResourceSitemaps))
+
+	pc.Setup.Only = []string{"dimension"}
+	assert.ErrorContains(t, validateConfig(pc), "setup.only")
+}
+
+func TestConflictMode(t *testing.T) {
+	pc := &ProjectConfig{Project: ProjectInfo{Name: "Test"}, GA4: GA4Config{PropertyID: "123456789"}}
+	assert.Equal(t, OnConflictSkip, pc.ConflictMode(""))
+
+	pc.Setup = &SetupConfig{OnConflict: OnConflictUpdate}
+	assert.Equal(t, OnConflictUpdate, pc.ConflictMode(""))
+	assert.Equal(t, OnConflictError, pc.ConflictMode(OnConflictError), "a resource's own mode wins")
+
+	require.NoError(t, validateConfig(pc))
+	pc.Dimensions = []DimensionConfig{{ParameterName: "author", DisplayName: "Author", Scope: "EVENT", OnConflict: "replace"}}
+	assert.ErrorContains(t, validateConfig(pc), "dimensions[0].on_conflict")
+}
+
+func TestSetupConcurrency(t *testing.T) {
+	pc := &ProjectConfig{Project: ProjectInfo{Name: "Test"}}
+	assert.Equal(t, DefaultSetupConcurrency, pc.SetupConcurrency())
+
+	pc.Setup = &SetupConfig{Concurrency: 2}
+	assert.Equal(t, 2, pc.SetupConcurrency())
+	require.NoError(t, validateConfig(pc))
+
+	pc.Setup.Concurrency = 50
+	assert.Equal(t, MaxSetupConcurrency, pc.SetupConcurrency())
+	assert.ErrorContains(t, validateConfig(pc), "setup.concurrency must be between 1 and 10")
+}
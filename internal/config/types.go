@@ -6,6 +6,9 @@ type ProjectConfig struct {
 	// Basic project information
 	Project ProjectInfo `yaml:"project"`
 
+	// Variables for ${NAME} references in the rest of the file (see vars.go)
+	Vars map[string]string `yaml:"vars,omitempty"`
+
 	// Google Analytics 4 configuration (optional - for GA4-only or combined configs)
 	Analytics *AnalyticsConfig `yaml:"analytics,omitempty"`
 
@@ -28,9 +31,22 @@ type ProjectConfig struct {
 	// Calculated metrics (GA4)
 	CalculatedMetrics []CalculatedMetricConfig `yaml:"calculated_metrics,omitempty"`
 
+	// Custom channel groups (GA4)
+	ChannelGroups []ChannelGroupConfig `yaml:"channel_groups,omitempty"`
+
 	// Audiences (GA4 - manual setup - API cannot create these)
 	Audiences []AudienceConfig `yaml:"audiences,omitempty"`
 
+	// Content groups: site sections Search Console pages are aggregated
+	// into (gsc analytics run --group-by content_group)
+	ContentGroups []ContentGroupConfig `yaml:"content_groups,omitempty"`
+
+	// Custom insight rules over Search Console data (ga4 insights run)
+	Insights *InsightsConfig `yaml:"insights,omitempty"`
+
+	// Rules ga4 config lint skips for this project
+	Lint *LintConfig `yaml:"lint,omitempty"`
+
 	// Cleanup configuration (GA4)
 	Cleanup CleanupConfig `yaml:"cleanup,omitempty"`
 
@@ -39,6 +55,19 @@ type ProjectConfig struct {
 
 	// Enhanced measurement settings (GA4)
 	EnhancedMeasurement *EnhancedMeasurementConfig `yaml:"enhanced_measurement,omitempty"`
+
+	// Default --only/--skip resource selectors for ga4 setup
+	Setup *SetupConfig `yaml:"setup,omitempty"`
+
+	// Google Tag Manager container that sends events to this property
+	TagManager *TagManagerConfig `yaml:"tag_manager,omitempty"`
+
+	// Properties the shared definitions above are set up on (see
+	// properties.go); empty for a single-property config
+	Properties []PropertyConfig `yaml:"properties,omitempty"`
+
+	// PropertyName is the properties entry an expanded config was made from
+	PropertyName string `yaml:"-"`
 }
 
 // HasAnalytics returns true if this config includes GA4 analytics setup
@@ -59,6 +88,15 @@ func (pc *ProjectConfig) GetPropertyID() string {
 	return pc.GA4.PropertyID
 }
 
+// GetMeasurementID returns the web stream measurement ID (G-XXXXXXX) from
+// either Analytics or legacy GA4 config
+func (pc *ProjectConfig) GetMeasurementID() string {
+	if pc.Analytics != nil {
+		return pc.Analytics.MeasurementID
+	}
+	return pc.GA4.MeasurementID
+}
+
 // ProjectInfo contains basic project metadata
 type ProjectInfo struct {
 	Name        string `yaml:"name"`
@@ -73,6 +111,11 @@ type AnalyticsConfig struct {
 	MeasurementID string `yaml:"measurement_id,omitempty"`
 	DataStreamID  string `yaml:"data_stream_id,omitempty"`
 	Tier          string `yaml:"tier,omitempty"` // "standard" (free) or "360" (paid)
+	// Currency is the default currency for conversion default values.
+	Currency string `yaml:"currency,omitempty"`
+
+	// Features toggles v1alpha-only Admin API surfaces (see features.go).
+	Features map[string]bool `yaml:"features,omitempty"`
 }
 
 // GA4Config contains GA4-specific identifiers (legacy, use AnalyticsConfig).
@@ -81,6 +124,17 @@ type AnalyticsConfig struct {
 // `analytics`) come from the field tags on ProjectConfig, not from the type.
 type GA4Config = AnalyticsConfig
 
+// TagManagerConfig identifies the GTM container that feeds the GA4 property.
+// The IDs are the numeric ones from the container URL in the GTM UI
+// (tagmanager.google.com/#/container/accounts/<account_id>/containers/<container_id>),
+// not the public GTM-XXXX ID.
+type TagManagerConfig struct {
+	AccountID   string `yaml:"account_id"`
+	ContainerID string `yaml:"container_id"`
+	// WorkspaceID audits a draft workspace instead of the published version.
+	WorkspaceID string `yaml:"workspace_id,omitempty"`
+}
+
 // SearchConsoleConfig contains Google Search Console configuration
 type SearchConsoleConfig struct {
 	// Site URL (must match verified property in GSC)
@@ -94,6 +148,10 @@ type SearchConsoleConfig struct {
 
 	// Search analytics configuration
 	SearchAnalytics *SearchAnalyticsConfig `yaml:"search_analytics,omitempty"`
+
+	// Named position-CTR curves for gsc ctr-curve, each mapping a position
+	// bucket (1, 2, 3, 4-5, 6-10, 11-20) to its expected CTR
+	CTRBenchmarks map[string]map[string]float64 `yaml:"ctr_benchmarks,omitempty"`
 }
 
 // SitemapConfig defines a sitemap to submit to GSC
@@ -101,6 +159,20 @@ type SitemapConfig struct {
 	URL         string `yaml:"url"`
 	AutoSubmit  bool   `yaml:"auto_submit,omitempty"`
 	Description string `yaml:"description,omitempty"`
+	// Remove retires the sitemap: setup deletes it from Search Console if
+	// it is still submitted. The sitemap file itself is left alone.
+	Remove bool `yaml:"remove,omitempty"`
+}
+
+// ActiveSitemaps returns the sitemaps not marked for removal.
+func (sc *SearchConsoleConfig) ActiveSitemaps() []SitemapConfig {
+	var out []SitemapConfig
+	for _, sm := range sc.Sitemaps {
+		if !sm.Remove {
+			out = append(out, sm)
+		}
+	}
+	return out
 }
 
 // URLInspectionConfig defines URLs to monitor for indexing issues
@@ -113,12 +185,29 @@ type URLInspectionConfig struct {
 
 	// Issues to alert on
 	Alerts []string `yaml:"alerts,omitempty"`
+
+	// URLs meant to be alternates of another page (mobile, AMP, language
+	// versions); their alternate-canonical warnings are suppressed
+	ExpectedAlternates []ExpectedAlternateConfig `yaml:"expected_alternates,omitempty"`
 }
 
-// URLPatternConfig defines a URL pattern to monitor
+// URLPatternConfig defines a URL pattern to monitor. Pattern is a path (or a
+// full URL) where "*" matches anything; the URLs it matches are discovered
+// from the configured sitemaps, else from Search Analytics pages.
 type URLPatternConfig struct {
 	Pattern     string `yaml:"pattern"`
 	Description string `yaml:"description,omitempty"`
+	// Sample is how many matching URLs to inspect per run (default 10)
+	Sample int `yaml:"sample,omitempty"`
+}
+
+// ExpectedAlternateConfig declares URLs that are expected to be reported as
+// "Alternate page with proper canonical tag". Pattern is a path (or a full
+// URL, for other hosts such as m.example.com) where "*" matches anything.
+type ExpectedAlternateConfig struct {
+	Pattern     string `yaml:"pattern"`
+	Kind        string `yaml:"kind"` // language, mobile, amp or other
+	Description string `yaml:"description,omitempty"`
 }
 
 // SearchAnalyticsConfig defines search analytics reporting settings
@@ -137,6 +226,19 @@ type SearchAnalyticsConfig struct {
 
 	// Alert thresholds
 	Alerts []SearchAlertConfig `yaml:"alerts,omitempty"`
+
+	// Keywords for the query intent classifier (gsc analytics run --by-intent)
+	Intent *QueryIntentConfig `yaml:"intent,omitempty"`
+}
+
+// QueryIntentConfig extends the built-in query intent keyword lists. A
+// trailing "*" matches any word with that prefix.
+type QueryIntentConfig struct {
+	Navigational  []string `yaml:"navigational,omitempty"` // brand and product names
+	Transactional []string `yaml:"transactional,omitempty"`
+	Informational []string `yaml:"informational,omitempty"`
+	// NoDefaults drops the built-in lists and uses only the ones above.
+	NoDefaults bool `yaml:"no_defaults,omitempty"`
 }
 
 // DateRangeConfig defines a date range for reports
@@ -166,6 +268,21 @@ type ConversionConfig struct {
 	CountingMethod string `yaml:"counting_method"` // ONCE_PER_SESSION or ONCE_PER_EVENT
 	Description    string `yaml:"description,omitempty"`
 	Priority       string `yaml:"priority,omitempty"` // high, medium, low (for tier limits)
+	Owner          string `yaml:"owner,omitempty"`    // who maintains it (measurement plan)
+	// Parameters lists the event parameters the event sends, for the
+	// measurement plan; ga4 setup does not use it.
+	Parameters []string `yaml:"parameters,omitempty"`
+
+	// DefaultValue is applied by GA4 to conversions of this event that arrive
+	// without a value parameter. Currency falls back to analytics.currency.
+	DefaultValue *float64 `yaml:"default_value,omitempty"`
+	Currency     string   `yaml:"currency,omitempty"` // ISO 4217, e.g. EUR
+	// ValueRequired marks an event as feeding value-based (ROAS) reporting,
+	// so a missing default value is reported. Lead and purchase events from
+	// GA4's recommended list are treated as value-bearing without it.
+	ValueRequired bool `yaml:"value_required,omitempty"`
+	// OnConflict overrides setup.on_conflict for this conversion.
+	OnConflict string `yaml:"on_conflict,omitempty"`
 }
 
 // DimensionConfig defines a custom dimension
@@ -175,6 +292,10 @@ type DimensionConfig struct {
 	Description   string `yaml:"description,omitempty"`
 	Scope         string `yaml:"scope"`              // USER or EVENT
 	Priority      string `yaml:"priority,omitempty"` // high, medium, low (for tier limits)
+	Owner         string `yaml:"owner,omitempty"`    // who maintains it (measurement plan)
+	// OnConflict overrides setup.on_conflict for this dimension. The scope
+	// cannot be updated.
+	OnConflict string `yaml:"on_conflict,omitempty"`
 }
 
 // MetricConfig defines a custom metric
@@ -185,26 +306,111 @@ type MetricConfig struct {
 	MeasurementUnit string `yaml:"unit"`               // STANDARD, CURRENCY, DISTANCE, etc.
 	Scope           string `yaml:"scope"`              // EVENT
 	Priority        string `yaml:"priority,omitempty"` // high, medium, low (for tier limits)
+	Owner           string `yaml:"owner,omitempty"`    // who maintains it (measurement plan)
 	// RestrictedMetricType is required for CURRENCY metrics: COST_DATA or REVENUE_DATA.
 	// Defaults to REVENUE_DATA when MeasurementUnit==CURRENCY and this is empty.
 	// Must be empty for non-CURRENCY metrics.
 	RestrictedMetricType string `yaml:"restricted_metric_type,omitempty"`
+	// OnConflict overrides setup.on_conflict for this metric. The scope
+	// cannot be updated.
+	OnConflict string `yaml:"on_conflict,omitempty"`
 }
 
-// CalculatedMetricConfig defines a calculated metric
+// CalculatedMetricConfig defines a calculated metric. Name is the display
+// name; ID, the calculated_metric_id, defaults to Name in snake_case.
 type CalculatedMetricConfig struct {
 	Name        string `yaml:"name"`
+	ID          string `yaml:"id,omitempty"`
 	Formula     string `yaml:"formula"`
 	Description string `yaml:"description,omitempty"`
 	MetricUnit  string `yaml:"metric_unit,omitempty"`
 }
 
+// ContentGroupConfig names a site section by URL path: Prefix ("/blog/")
+// or Pattern, an RE2 regex ("^/(tools|calculators)/"). A page belongs to the
+// first group in config order that matches it.
+type ContentGroupConfig struct {
+	Name    string `yaml:"name"`
+	Prefix  string `yaml:"prefix,omitempty"`
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// Insight rule groupings: what one finding is about.
+const (
+	InsightByTotal        = "total"
+	InsightByPage         = "page"
+	InsightByQuery        = "query"
+	InsightByContentGroup = "content_group"
+)
+
+// InsightsConfig holds the rules `ga4 insights run` evaluates and the sinks
+// its findings are sent to.
+type InsightsConfig struct {
+	Rules  []InsightRuleConfig `yaml:"rules"`
+	Notify []NotifyConfig      `yaml:"notify,omitempty"`
+}
+
+// InsightRuleConfig flags Search Console data when When holds, comparing the
+// last Days days with the Days before them. By sets what one finding is
+// about: the total of the matching rows (default), or each page, query or
+// content group. Pages and Queries narrow the rows; "*" matches anything, as
+// in url_inspection patterns.
+type InsightRuleConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	When        string `yaml:"when"`               // e.g. position_delta > 2
+	By          string `yaml:"by,omitempty"`       // total, page, query or content_group
+	Pages       string `yaml:"pages,omitempty"`    // e.g. /tools/*
+	Queries     string `yaml:"queries,omitempty"`  // e.g. *compressor*
+	Days        int    `yaml:"days,omitempty"`     // default 28
+	Severity    string `yaml:"severity,omitempty"` // high, medium (default) or low
+	// MinImpressions skips groups with fewer impressions in both windows.
+	MinImpressions int64 `yaml:"min_impressions,omitempty"`
+}
+
+// NotifyConfig is a destination for findings: exactly one of Webhook, a URL
+// they are POSTed to as JSON with a Slack-compatible "text" field, or File, a
+// JSONL file they are appended to.
+type NotifyConfig struct {
+	Webhook string `yaml:"webhook,omitempty"`
+	File    string `yaml:"file,omitempty"`
+}
+
+// ChannelGroupConfig defines a custom channel group. Rules are channels,
+// evaluated in order: a session lands in the first one whose expression
+// matches.
+type ChannelGroupConfig struct {
+	DisplayName string              `yaml:"display_name"`
+	Description string              `yaml:"description,omitempty"`
+	Rules       []ChannelRuleConfig `yaml:"rules"`
+}
+
+// ChannelRuleConfig is one channel of a channel group. Expression is a filter
+// such as "sessionSource == 'google' AND sessionMedium IN ('cpc', 'ppc')";
+// OR, NOT, parentheses and the CONTAINS, BEGINS_WITH, ENDS_WITH,
+// MATCHES_REGEX and PARTIAL_REGEX operators are supported too.
+type ChannelRuleConfig struct {
+	DisplayName string `yaml:"display_name"`
+	Expression  string `yaml:"expression"`
+}
+
 // AudienceConfig defines an audience (manual setup only)
 type AudienceConfig struct {
 	Name        string   `yaml:"name"`
 	Description string   `yaml:"description"`
 	Duration    int      `yaml:"duration"`
+	Category    string   `yaml:"category,omitempty"` // SEO, Conversion, Behavioral, etc.
 	Conditions  []string `yaml:"conditions,omitempty"`
+	// Events and Parameters name what the audience is built on, so
+	// ga4 config graph and preflight can check they are defined.
+	Events     []string `yaml:"events,omitempty"`
+	Parameters []string `yaml:"parameters,omitempty"`
+}
+
+// LintConfig tunes ga4 config lint for a project.
+type LintConfig struct {
+	// Disable lists the IDs of rules not to run, such as event-name-case.
+	Disable []string `yaml:"disable,omitempty"`
 }
 
 // CleanupConfig defines items to remove from GA4
@@ -213,6 +419,9 @@ type CleanupConfig struct {
 	DimensionsToRemove  []string `yaml:"dimensions_to_remove,omitempty"`
 	MetricsToRemove     []string `yaml:"metrics_to_remove,omitempty"`
 	Reason              string   `yaml:"reason,omitempty"`
+	// Protected names (or patterns such as ga_*) cleanup --prune never
+	// archives, even though the config does not declare them.
+	Protected []string `yaml:"protected,omitempty"`
 }
 
 // DataRetentionConfig configures data retention
@@ -303,3 +303,121 @@ func TestEnhancedMeasurementConfigValidation(t *testing.T) {
 func unmarshalYAML(data []byte, v any) error {
 	return yaml.Unmarshal(data, v)
 }
+
+func TestValidateConfig_TagManager(t *testing.T) {
+	pc := &ProjectConfig{
+		Project:    ProjectInfo{Name: "Test"},
+		TagManager: &TagManagerConfig{AccountID: "6001234567", ContainerID: "190123456"},
+	}
+	require.NoError(t, validateConfig(pc))
+
+	pc.TagManager.ContainerID = "GTM-ABC123"
+	assert.ErrorContains(t, validateConfig(pc), "tag_manager.container_id must be the numeric ID")
+
+	pc.TagManager.ContainerID = ""
+	assert.ErrorContains(t, validateConfig(pc), "tag_manager.container_id is required")
+}
+
+func TestValidateConfig_ExpectedAlternates(t *testing.T) {
+	pc := &ProjectConfig{
+		Project: ProjectInfo{Name: "Test"},
+		SearchConsole: &SearchConsoleConfig{
+			SiteURL: "sc-domain:example.com",
+			URLInspection: &URLInspectionConfig{
+				ExpectedAlternates: []ExpectedAlternateConfig{{Pattern: "/amp/*", Kind: "amp"}},
+			},
+		},
+	}
+	require.NoError(t, validateConfig(pc))
+
+	pc.SearchConsole.URLInspection.ExpectedAlternates[0].Kind = "print"
+	assert.ErrorContains(t, validateConfig(pc), `expected_alternates[0].kind "print" is invalid`)
+
+	pc.SearchConsole.URLInspection.ExpectedAlternates[0].Pattern = ""
+	assert.ErrorContains(t, validateConfig(pc), "expected_alternates[0].pattern is required")
+}
+
+func TestValidateConfig_Sitemaps(t *testing.T) {
+	pc := &ProjectConfig{
+		Project: ProjectInfo{Name: "Test"},
+		SearchConsole: &SearchConsoleConfig{
+			SiteURL: "sc-domain:example.com",
+			Sitemaps: []SitemapConfig{
+				{URL: "https://example.com/sitemap.xml", AutoSubmit: true},
+				{URL: "https://example.com/old-sitemap.xml", Remove: true},
+			},
+		},
+	}
+	require.NoError(t, validateConfig(pc))
+	assert.Equal(t, []SitemapConfig{{URL: "https://example.com/sitemap.xml", AutoSubmit: true}}, pc.SearchConsole.ActiveSitemaps())
+
+	pc.SearchConsole.Sitemaps[1].AutoSubmit = true
+	assert.ErrorContains(t, validateConfig(pc), "sitemaps[1] sets both remove and auto_submit")
+
+	pc.SearchConsole.Sitemaps[1] = SitemapConfig{URL: "https://example.com/sitemap.xml", Remove: true}
+	assert.ErrorContains(t, validateConfig(pc), "sitemaps[1].url https://example.com/sitemap.xml is listed twice")
+
+	pc.SearchConsole.Sitemaps[1].URL = ""
+	assert.ErrorContains(t, validateConfig(pc), "sitemaps[1].url is required")
+}
+
+func TestValidateConfig_ChannelGroups(t *testing.T) {
+	pc := &ProjectConfig{
+		Project: ProjectInfo{Name: "Test"},
+		ChannelGroups: []ChannelGroupConfig{{
+			DisplayName: "Marketing Channels",
+			Rules:       []ChannelRuleConfig{{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Email'"}},
+		}},
+	}
+	require.NoError(t, validateConfig(pc))
+
+	pc.ChannelGroups = append(pc.ChannelGroups, pc.ChannelGroups[0])
+	assert.ErrorContains(t, validateConfig(pc), `channel_groups[1].display_name "Marketing Channels" is used twice`)
+
+	pc.ChannelGroups = []ChannelGroupConfig{{DisplayName: "Empty"}}
+	assert.ErrorContains(t, validateConfig(pc), "channel_groups[0].rules needs at least one rule")
+
+	pc.ChannelGroups[0].Rules = []ChannelRuleConfig{{DisplayName: "Email"}}
+	assert.ErrorContains(t, validateConfig(pc), "channel_groups[0].rules[0].expression is required")
+}
+
+func TestValidateConfig_ContentGroups(t *testing.T) {
+	pc := &ProjectConfig{
+		Project:       ProjectInfo{Name: "Test"},
+		ContentGroups: []ContentGroupConfig{{Name: "Blog", Prefix: "/blog/"}, {Name: "Tools", Pattern: "^/tools/"}},
+	}
+	require.NoError(t, validateConfig(pc))
+
+	pc.ContentGroups[1].Name = "Blog"
+	assert.ErrorContains(t, validateConfig(pc), `content_groups[1].name "Blog" is used twice`)
+
+	pc.ContentGroups[1] = ContentGroupConfig{Name: "Tools", Prefix: "/tools/", Pattern: "^/tools/"}
+	assert.ErrorContains(t, validateConfig(pc), "content_groups[1] needs exactly one of prefix or pattern")
+
+	pc.ContentGroups[1] = ContentGroupConfig{Name: "Tools", Pattern: "^/(tools"}
+	assert.ErrorContains(t, validateConfig(pc), "content_groups[1].pattern is not a valid regex")
+}
+
+func TestValidateConfig_Insights(t *testing.T) {
+	pc := &ProjectConfig{
+		Project: ProjectInfo{Name: "Test"},
+		Insights: &InsightsConfig{
+			Rules:  []InsightRuleConfig{{Name: "tools", Pages: "/tools/*", When: "position_delta > 2"}},
+			Notify: []NotifyConfig{{File: "insights.jsonl"}},
+		},
+	}
+	require.NoError(t, validateConfig(pc))
+
+	pc.Insights.Rules = append(pc.Insights.Rules, pc.Insights.Rules[0])
+	assert.ErrorContains(t, validateConfig(pc), `insights.rules[1].name "tools" is used twice`)
+
+	pc.Insights.Rules = []InsightRuleConfig{{Name: "tools", When: "clicks_delta < 0", By: "section"}}
+	assert.ErrorContains(t, validateConfig(pc), "insights.rules[0].by must be one of")
+
+	pc.Insights.Rules = []InsightRuleConfig{{Name: "tools"}}
+	assert.ErrorContains(t, validateConfig(pc), "insights.rules[0].when is required")
+
+	pc.Insights.Rules = nil
+	pc.Insights.Notify = []NotifyConfig{{File: "a.jsonl", Webhook: "https://example.com/hook"}}
+	assert.ErrorContains(t, validateConfig(pc), "insights.notify[0] needs exactly one of webhook or file")
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config values may reference variables as ${NAME}, or ${NAME:-default}
+// for a fallback, so property IDs and site URLs need not be hardcoded per
+// environment:
+//
+//	vars:
+//	  property_id: "123456789"
+//	analytics:
+//	  property_id: ${property_id}
+//	search_console:
+//	  site_url: ${SITE_URL:-https://example.com/}
+//
+// A name is looked up in the --set values, then the vars block, then the
+// environment. Values in the vars block may themselves use --set values and
+// the environment. $$ is a literal $. Substitution applies to values only,
+// never to keys or comments.
+
+// setVariables holds the --set values; see SetVariables.
+var setVariables map[string]string
+
+// SetVariables sets the values `--set key=value` gives every config loaded
+// from disk afterwards. They take precedence over the vars block and the
+// environment.
+func SetVariables(vars map[string]string) {
+	setVariables = vars
+}
+
+// ParseVariable splits a key=value --set argument.
+func ParseVariable(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || !isVariableName(key) {
+		return "", "", fmt.Errorf("invalid --set %q: want NAME=value, where NAME is letters, digits and underscores", s)
+	}
+	return key, value, nil
+}
+
+// variables resolves ${NAME} references for one config.
+type variables struct {
+	set  map[string]string
+	vars map[string]string
+	env  bool // consult the environment; off for configs received over the wire
+}
+
+func (v *variables) lookup(name string) (string, bool) {
+	if value, ok := v.set[name]; ok {
+		return value, true
+	}
+	if value, ok := v.vars[name]; ok {
+		return value, true
+	}
+	if v.env {
+		return os.LookupEnv(name)
+	}
+	return "", false
+}
+
+// substituteVariables replaces the ${NAME} references in the values of doc,
+// a parsed YAML document, resolving the top-level vars block first. Every
+// undefined variable is reported, with its line.
+func substituteVariables(doc *yaml.Node, v *variables) error {
+	root := documentRoot(doc)
+	if root == nil {
+		return nil
+	}
+	var undefined []string
+
+	// The vars block sees --set values and the environment, not itself.
+	var varsNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "vars" {
+			varsNode = root.Content[i+1]
+		}
+	}
+	v.vars = nil
+	if varsNode != nil {
+		if varsNode.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: vars must be a mapping of names to values", varsNode.Line)
+		}
+		outer := &variables{set: v.set, env: v.env}
+		v.vars = make(map[string]string, len(varsNode.Content)/2)
+		for i := 0; i+1 < len(varsNode.Content); i += 2 {
+			key, value := varsNode.Content[i], varsNode.Content[i+1]
+			if !isVariableName(key.Value) {
+				return fmt.Errorf("line %d: vars.%s: names may only contain letters, digits and underscores", key.Line, key.Value)
+			}
+			if value.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: vars.%s must be a single value", value.Line, key.Value)
+			}
+			undefined = append(undefined, substituteNode(value, outer)...)
+			v.vars[key.Value] = value.Value
+		}
+	}
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			undefined = append(undefined, substituteNode(n, v)...)
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				walk(c)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i+1] != varsNode {
+					walk(n.Content[i+1])
+				}
+			}
+		}
+	}
+	walk(root)
+
+	if len(undefined) > 0 {
+		sort.Strings(undefined)
+		return fmt.Errorf("undefined variables (define them under vars:, in the environment, or with --set NAME=value):\n  %s",
+			strings.Join(undefined, "\n  "))
+	}
+	return nil
+}
+
+// substituteNode expands the references in a scalar and returns the
+// undefined ones, each as "${NAME} (line N)". A plain scalar loses its tag so
+// the expanded value resolves as a number or bool where the field wants one.
+func substituteNode(n *yaml.Node, v *variables) []string {
+	if !strings.Contains(n.Value, "$") {
+		return nil
+	}
+	var b strings.Builder
+	var undefined []string
+	s := n.Value
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			undefined = append(undefined, fmt.Sprintf("%s (line %d: missing })", s[i:], n.Line))
+			b.WriteString(s[i:])
+			break
+		}
+		ref := s[i+2 : i+end]
+		name, def, hasDefault := strings.Cut(ref, ":-")
+		value, ok := v.lookup(name)
+		switch {
+		case !isVariableName(name):
+			undefined = append(undefined, fmt.Sprintf("${%s} (line %d: not a variable name)", ref, n.Line))
+		case ok:
+			b.WriteString(value)
+		case hasDefault:
+			b.WriteString(def)
+		default:
+			undefined = append(undefined, fmt.Sprintf("${%s} (line %d)", name, n.Line))
+		}
+		i += end
+	}
+	n.Value = b.String()
+	if n.Style == 0 {
+		n.Tag = ""
+	}
+	return undefined
+}
+
+func isVariableName(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	return strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_") == ""
+}
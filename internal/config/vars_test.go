@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const varsYAML = `
+vars:
+  property_id: ${GA4_TEST_PROPERTY:-111111111}
+  site: https://example.com/
+project:
+  name: Vars ${ENVIRONMENT}
+ga4:
+  property_id: ${property_id}
+search_console:
+  site_url: ${site}
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+    value_required: ${REQUIRE_VALUE:-false}
+    description: costs $$5 # ${NOT_SUBSTITUTED}
+`
+
+func TestParseConfig_Variables(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "staging")
+	t.Setenv("GA4_TEST_PROPERTY", "222222222")
+
+	pc, err := parseConfig([]byte(varsYAML), "", &variables{env: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Vars staging", pc.Project.Name)
+	assert.Equal(t, "222222222", pc.GetPropertyID(), "vars may use the environment")
+	assert.Equal(t, "https://example.com/", pc.SearchConsole.SiteURL)
+	assert.False(t, pc.Conversions[0].ValueRequired, "defaults resolve to the field's type")
+	assert.Equal(t, "costs $5", pc.Conversions[0].Description)
+
+	// --set beats the vars block and the environment.
+	pc, err = parseConfig([]byte(varsYAML), "", &variables{env: true, set: map[string]string{
+		"property_id": "333333333", "ENVIRONMENT": "prod", "REQUIRE_VALUE": "true",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "333333333", pc.GetPropertyID())
+	assert.Equal(t, "Vars prod", pc.Project.Name)
+	assert.True(t, pc.Conversions[0].ValueRequired)
+}
+
+func TestParseConfig_UndefinedVariables(t *testing.T) {
+	_, err := ParseConfig([]byte(varsYAML))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "${ENVIRONMENT} (line 6)")
+	assert.NotContains(t, err.Error(), "GA4_TEST_PROPERTY", "a default covers an undefined variable")
+	assert.NotContains(t, err.Error(), "NOT_SUBSTITUTED", "comments are left alone")
+
+	t.Setenv("ENVIRONMENT", "staging")
+	_, err = ParseConfig([]byte(varsYAML))
+	assert.ErrorContains(t, err, "${ENVIRONMENT}", "configs from the wire never read the environment")
+}
+
+func TestParseVariable(t *testing.T) {
+	key, value, err := ParseVariable("property_id=123=456")
+	require.NoError(t, err)
+	assert.Equal(t, "property_id", key)
+	assert.Equal(t, "123=456", value)
+
+	_, _, err = ParseVariable("property-id=1")
+	assert.Error(t, err)
+	_, _, err = ParseVariable("property_id")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWorkspaceFile is the workspace config read from the working
+// directory. Unlike project configs it holds per-checkout CLI preferences,
+// not GA4/GSC resources.
+const DefaultWorkspaceFile = ".ga4.yaml"
+
+// WorkspaceEnv overrides the workspace config path.
+const WorkspaceEnv = "GA4_WORKSPACE"
+
+// WorkspaceConfig holds CLI-level settings shared by every command run from a
+// checkout.
+type WorkspaceConfig struct {
+	// Aliases maps a name to a command line with baked-in flags, e.g.
+	// weekly: "gsc analytics run --config configs/site.yaml --days 7".
+	Aliases map[string]string `yaml:"aliases"`
+}
+
+// LoadWorkspaceConfig reads the workspace config at path, or at $GA4_WORKSPACE
+// / .ga4.yaml when path is empty. A missing default file is not an error and
+// yields an empty config; a missing explicit file is.
+func LoadWorkspaceConfig(path string) (*WorkspaceConfig, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv(WorkspaceEnv)
+		explicit = path != ""
+	}
+	if !explicit {
+		path = DefaultWorkspaceFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return &WorkspaceConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read workspace config: %w", err)
+	}
+
+	var ws WorkspaceConfig
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace config %s: %w", path, err)
+	}
+	for name, line := range ws.Aliases {
+		if name == "" || strings.ContainsFunc(name, unicode.IsSpace) || strings.HasPrefix(name, "-") {
+			return nil, fmt.Errorf("workspace config %s: invalid alias name %q", path, name)
+		}
+		if strings.TrimSpace(line) == "" {
+			return nil, fmt.Errorf("workspace config %s: alias %q is empty", path, name)
+		}
+	}
+	return &ws, nil
+}
+
+// SplitCommandLine splits an alias definition into arguments the way a POSIX
+// shell would for the simple cases aliases need: whitespace separates words,
+// single quotes are literal, double quotes allow \" and \\ escapes, and a
+// backslash outside quotes escapes the next character.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
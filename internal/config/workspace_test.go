@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWorkspaceConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ws.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`aliases:
+  weekly: "gsc analytics run --config configs/site.yaml --days 7"
+`), 0o644))
+
+	ws, err := LoadWorkspaceConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "gsc analytics run --config configs/site.yaml --days 7", ws.Aliases["weekly"])
+}
+
+func TestLoadWorkspaceConfig_MissingDefaultIsEmpty(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(WorkspaceEnv, "")
+
+	ws, err := LoadWorkspaceConfig("")
+	require.NoError(t, err)
+	assert.Empty(t, ws.Aliases)
+
+	_, err = LoadWorkspaceConfig("missing.yaml")
+	assert.Error(t, err, "an explicit path must exist")
+}
+
+func TestLoadWorkspaceConfig_InvalidAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ws.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("aliases:\n  \"my alias\": \"report\"\n"), 0o644))
+
+	_, err := LoadWorkspaceConfig(path)
+	assert.ErrorContains(t, err, "invalid alias name")
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`gsc analytics run --days 7`, []string{"gsc", "analytics", "run", "--days", "7"}},
+		{`history --query "image compressor"`, []string{"history", "--query", "image compressor"}},
+		{`history --query 'it''s'`, []string{"history", "--query", "its"}},
+		{`a "say \"hi\"" b\ c`, []string{"a", `say "hi"`, "b c"}},
+		{`x ""`, []string{"x", ""}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandLine(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := SplitCommandLine(`history --query "open`)
+	assert.ErrorContains(t, err, "unterminated")
+}
@@ -0,0 +1,186 @@
+// Package depgraph links the resources of a config by what depends on what:
+// conversion events send parameters, parameters are collected by custom
+// dimensions and metrics, audiences are built on events and parameters, and
+// calculated metrics on custom metrics. It finds references to resources
+// the config does not define and renders the graph for Graphviz or Mermaid.
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gtm"
+)
+
+// Node kinds.
+const (
+	KindEvent            = "event"
+	KindDimension        = "dimension"
+	KindMetric           = "metric"
+	KindParameter        = "parameter" // referenced, but no dimension or metric
+	KindAudience         = "audience"
+	KindCalculatedMetric = "calculated_metric"
+)
+
+// Node is a resource. Defined is false for one that is only referenced:
+// an audience's event that is no conversion, a parameter that is no custom
+// dimension or metric.
+type Node struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Defined bool   `json:"defined"`
+}
+
+// Edge points from a resource to one that depends on it.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the dependency graph of a config.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	index map[string]int
+	edges map[Edge]bool
+}
+
+// builtInParams are GA4's own event parameters, which need no custom
+// definition and get no node.
+var builtInParams = map[string]bool{
+	"value": true, "currency": true, "transaction_id": true, "items": true,
+	"coupon": true, "tax": true, "shipping": true,
+}
+
+// Build links the resources of cfg. Which parameters an event sends comes
+// from the conversions' parameters lists, and what an audience is built on
+// from its events and parameters lists.
+func Build(cfg *config.ProjectConfig) *Graph {
+	g := &Graph{Nodes: []Node{}, Edges: []Edge{}, index: map[string]int{}, edges: map[Edge]bool{}}
+
+	for _, c := range cfg.Conversions {
+		g.define(KindEvent, c.Name)
+	}
+	for _, d := range cfg.Dimensions {
+		g.define(KindDimension, d.ParameterName)
+	}
+	for _, m := range cfg.Metrics {
+		g.define(KindMetric, m.ParameterName)
+	}
+
+	for _, c := range cfg.Conversions {
+		for _, p := range c.Parameters {
+			if !builtInParams[p] {
+				g.link(nodeID(KindEvent, c.Name), g.param(p))
+			}
+		}
+	}
+
+	for _, a := range cfg.Audiences {
+		audience := g.define(KindAudience, a.Name)
+		for _, e := range a.Events {
+			id := nodeID(KindEvent, e)
+			if _, ok := g.index[id]; !ok {
+				// Events GA4 collects on its own are defined without a
+				// conversion.
+				g.add(Node{ID: id, Kind: KindEvent, Name: e, Defined: gtm.IsAutomaticEvent(e)})
+			}
+			g.link(id, audience)
+		}
+		for _, p := range a.Parameters {
+			if !builtInParams[p] {
+				g.link(g.param(p), audience)
+			}
+		}
+	}
+
+	for _, cm := range cfg.CalculatedMetrics {
+		calculated := g.define(KindCalculatedMetric, cm.Name)
+		refs, _ := ga4.FormulaCustomMetrics(cm.Formula) // syntax is checked elsewhere
+		for _, r := range refs {
+			id := nodeID(KindMetric, r)
+			if _, ok := g.index[id]; !ok {
+				g.add(Node{ID: id, Kind: KindMetric, Name: r})
+			}
+			g.link(id, calculated)
+		}
+	}
+	return g
+}
+
+func nodeID(kind, name string) string {
+	return kind + ":" + name
+}
+
+func (g *Graph) add(n Node) {
+	g.index[n.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+}
+
+// define adds a resource the config defines and returns its ID.
+func (g *Graph) define(kind, name string) string {
+	id := nodeID(kind, name)
+	if i, ok := g.index[id]; ok {
+		g.Nodes[i].Defined = true
+		return id
+	}
+	g.add(Node{ID: id, Kind: kind, Name: name, Defined: true})
+	return id
+}
+
+// param returns the node collecting parameter name: its dimension or
+// metric, else an undefined parameter.
+func (g *Graph) param(name string) string {
+	for _, kind := range []string{KindDimension, KindMetric, KindParameter} {
+		if _, ok := g.index[nodeID(kind, name)]; ok {
+			return nodeID(kind, name)
+		}
+	}
+	id := nodeID(KindParameter, name)
+	g.add(Node{ID: id, Kind: KindParameter, Name: name})
+	return id
+}
+
+func (g *Graph) link(from, to string) {
+	e := Edge{From: from, To: to}
+	if !g.edges[e] {
+		g.edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// Node returns the node with id.
+func (g *Graph) Node(id string) (Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return Node{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Warnings describes each reference to an undefined resource, sorted.
+func (g *Graph) Warnings() []string {
+	var warnings []string
+	for _, e := range g.Edges {
+		from, _ := g.Node(e.From)
+		to, _ := g.Node(e.To)
+		switch {
+		case from.Kind == KindEvent && to.Kind == KindParameter && from.Defined:
+			warnings = append(warnings, fmt.Sprintf("conversion %s sends parameter %s, which is no custom dimension or metric: GA4 will not report it", from.Name, to.Name))
+		case from.Defined:
+			continue
+		case to.Kind == KindAudience && from.Kind == KindEvent:
+			warnings = append(warnings, fmt.Sprintf("audience %q is built on event %s, which is not a conversion in the config", to.Name, from.Name))
+		case to.Kind == KindAudience:
+			warnings = append(warnings, fmt.Sprintf("audience %q uses parameter %s, which is no custom dimension or metric in the config", to.Name, from.Name))
+		case to.Kind == KindCalculatedMetric:
+			warnings = append(warnings, fmt.Sprintf("calculated metric %q references custom metric %s, which is not defined in the config", to.Name, from.Name))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
@@ -0,0 +1,95 @@
+package depgraph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func testConfig() *config.ProjectConfig {
+	return &config.ProjectConfig{
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase", Parameters: []string{"value", "currency", "plan_type", "items_count", "coupon_code"}},
+			{Name: "sign_up", Parameters: []string{"plan_type"}},
+		},
+		Dimensions: []config.DimensionConfig{{ParameterName: "plan_type", DisplayName: "Plan Type", Scope: "EVENT"}},
+		Metrics:    []config.MetricConfig{{ParameterName: "items_count", DisplayName: "Items", Scope: "EVENT"}},
+		Audiences: []config.AudienceConfig{
+			{Name: "Pro buyers", Events: []string{"purchase", "page_view"}, Parameters: []string{"plan_type"}},
+			{Name: "Wishlisters", Events: []string{"add_to_wishlist"}, Parameters: []string{"wishlist_size"}},
+		},
+		CalculatedMetrics: []config.CalculatedMetricConfig{
+			{Name: "Items per user", Formula: "customEvent:items_count / activeUsers"},
+			{Name: "Reading time", Formula: "customEvent:reading_time / sessions"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testConfig())
+
+	var edges []string
+	for _, e := range g.Edges {
+		edges = append(edges, e.From+" -> "+e.To)
+	}
+	assert.Equal(t, []string{
+		"event:purchase -> dimension:plan_type",
+		"event:purchase -> metric:items_count",
+		"event:purchase -> parameter:coupon_code",
+		"event:sign_up -> dimension:plan_type",
+		"event:purchase -> audience:Pro buyers",
+		"event:page_view -> audience:Pro buyers",
+		"dimension:plan_type -> audience:Pro buyers",
+		"event:add_to_wishlist -> audience:Wishlisters",
+		"parameter:wishlist_size -> audience:Wishlisters",
+		"metric:items_count -> calculated_metric:Items per user",
+		"metric:reading_time -> calculated_metric:Reading time",
+	}, edges)
+
+	pageView, ok := g.Node("event:page_view")
+	require.True(t, ok)
+	assert.True(t, pageView.Defined, "collected automatically")
+	readingTime, _ := g.Node("metric:reading_time")
+	assert.False(t, readingTime.Defined)
+}
+
+func TestWarnings(t *testing.T) {
+	assert.Equal(t, []string{
+		`audience "Wishlisters" is built on event add_to_wishlist, which is not a conversion in the config`,
+		`audience "Wishlisters" uses parameter wishlist_size, which is no custom dimension or metric in the config`,
+		`calculated metric "Reading time" references custom metric reading_time, which is not defined in the config`,
+		"conversion purchase sends parameter coupon_code, which is no custom dimension or metric: GA4 will not report it",
+	}, Build(testConfig()).Warnings())
+
+	assert.Empty(t, Build(&config.ProjectConfig{
+		Conversions: []config.ConversionConfig{{Name: "purchase"}},
+		Audiences:   []config.AudienceConfig{{Name: "Buyers", Events: []string{"purchase"}}},
+	}).Warnings())
+}
+
+func TestDOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Build(testConfig()).DOT(&buf))
+	dot := buf.String()
+	assert.Contains(t, dot, "digraph config {\n  rankdir=LR;\n")
+	assert.Contains(t, dot, `  "event:purchase" [label="purchase\nevent", shape=ellipse];`)
+	assert.Contains(t, dot, `  "metric:reading_time" [label="reading_time\nmetric, undefined", shape=box3d, style=dashed, color="#c62828"];`)
+	assert.Contains(t, dot, `  "event:purchase" -> "dimension:plan_type";`)
+}
+
+func TestMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Build(testConfig()).Mermaid(&buf))
+	mermaid := buf.String()
+	assert.Contains(t, mermaid, "flowchart LR\n  n0([\"purchase<br/>event\"])\n")
+	assert.Contains(t, mermaid, `  n2["plan_type<br/>dimension"]`)
+	assert.Contains(t, mermaid, `{{"Pro buyers<br/>audience"}}`)
+	assert.Contains(t, mermaid, `[/"Items per user<br/>calculated metric"/]`)
+	assert.Contains(t, mermaid, `["coupon_code<br/>parameter, undefined"]:::undefined`)
+	assert.Contains(t, mermaid, "  n0 --> n2\n")
+	assert.Equal(t, "#quot;a#quot; #lt;b#gt;<br/>x", mermaidEscape(`"a" <b><br/>x`))
+}
@@ -0,0 +1,90 @@
+package depgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shapes by kind, so the graphs read without a legend.
+var (
+	dotShapes = map[string]string{
+		KindEvent:            "ellipse",
+		KindDimension:        "box",
+		KindMetric:           "box3d",
+		KindParameter:        "box",
+		KindAudience:         "hexagon",
+		KindCalculatedMetric: "parallelogram",
+	}
+	// mermaidShapes are the opening and closing brackets of each shape.
+	mermaidShapes = map[string][2]string{
+		KindEvent:            {"([", "])"},
+		KindDimension:        {"[", "]"},
+		KindMetric:           {"[[", "]]"},
+		KindParameter:        {"[", "]"},
+		KindAudience:         {"{{", "}}"},
+		KindCalculatedMetric: {"[/", "/]"},
+	}
+)
+
+// label is a node's name and kind, on two lines joined by sep.
+func label(n Node, sep string) string {
+	kind := strings.ReplaceAll(n.Kind, "_", " ")
+	if !n.Defined {
+		kind += ", undefined"
+	}
+	return n.Name + sep + kind
+}
+
+// DOT writes g in Graphviz DOT. Undefined resources are dashed and red.
+func (g *Graph) DOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph config {")
+	fmt.Fprintln(b, "  rankdir=LR;")
+	fmt.Fprintln(b, `  node [fontname="Helvetica", fontsize=11];`)
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s, shape=%s", dotQuote(label(n, "\n")), dotShapes[n.Kind])
+		if !n.Defined {
+			attrs += `, style=dashed, color="#c62828"`
+		}
+		fmt.Fprintf(b, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Mermaid writes g as a Mermaid flowchart, which GitHub and GitLab render
+// in markdown. Undefined resources are dashed and red.
+func (g *Graph) Mermaid(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "flowchart LR")
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(b, "  %s%s\"%s\"%s", ids[n.ID], shape[0], mermaidEscape(label(n, "<br/>")), shape[1])
+		if !n.Defined {
+			fmt.Fprint(b, ":::undefined")
+		}
+		fmt.Fprintln(b)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(b, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+	fmt.Fprintln(b, "  classDef undefined stroke:#c62828,stroke-dasharray:5 5")
+	return b.Flush()
+}
+
+// mermaidEscape makes s safe inside a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<br/>", "<br/>", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,109 @@
+// Package exitcode is the process exit status contract of the ga4 CLI, so CI
+// pipelines and the MCP server can tell outcomes apart without parsing
+// messages:
+//
+//	0  OK         the command succeeded and found nothing to report
+//	1  Validation bad flags, config or input, and any unclassified failure
+//	2  API        a Google API call failed
+//	3  Quota      a Google API quota, or the CLI's own daily budget, ran out
+//	4  Alerts     the command succeeded and its checks found issues
+//
+// Commands return errors as usual; Of maps them to a code. A command whose
+// outcome is not an error (Alerts) or that has already reported its error
+// returns one made with New.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// Exit codes. The values are part of the CLI's interface; never renumber.
+const (
+	OK         = 0
+	Validation = 1
+	API        = 2
+	Quota      = 3
+	Alerts     = 4
+)
+
+// quotaReasons are the googleapi error reasons Google uses for exhausted
+// quotas and rate limits.
+var quotaReasons = map[string]bool{
+	"quotaExceeded":         true,
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"dailyLimitExceeded":    true,
+}
+
+// Error carries an exit code. Err is nil when the command has already
+// reported the outcome and only the code is left to return.
+type Error struct {
+	Code int
+	Err  error
+}
+
+// New returns an error that exits with code. err may be nil.
+func New(code int, err error) error {
+	return &Error{Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return "exit status " + strconv.Itoa(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Of returns the exit code for err: the code of an *Error in its chain, or
+// one inferred from the API, quota and network errors it wraps.
+func Of(err error) int {
+	if err == nil {
+		return OK
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	if errors.Is(err, gsc.ErrQuotaExhausted) || errors.Is(err, gsc.ErrQuotaExceeded) {
+		return Quota
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusTooManyRequests {
+			return Quota
+		}
+		for _, item := range apiErr.Errors {
+			if quotaReasons[item.Reason] {
+				return Quota
+			}
+		}
+		return API
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return API
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return API
+	}
+	return Validation
+}
+
+// Silent reports whether err carries only an exit code, with nothing left to
+// print.
+func Silent(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Err == nil
+}
@@ -0,0 +1,52 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, OK},
+		{"plain error", errors.New("invalid --days 0"), Validation},
+		{"explicit code", New(Alerts, nil), Alerts},
+		{"wrapped explicit code", fmt.Errorf("run: %w", New(API, errors.New("boom"))), API},
+		{"api error", fmt.Errorf("list: %w", &googleapi.Error{Code: http.StatusForbidden}), API},
+		{"api 429", &googleapi.Error{Code: http.StatusTooManyRequests}, Quota},
+		{"api quota reason", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, Quota},
+		{"client quota", fmt.Errorf("%w: 1900/2000 inspections used", gsc.ErrQuotaExhausted), Quota},
+		{"estimated quota", fmt.Errorf("%w: 40 needed", gsc.ErrQuotaExceeded), Quota},
+		{"network", &url.Error{Op: "Get", URL: "https://example.com", Err: errors.New("connection refused")}, API},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), API},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Of(tt.err))
+		})
+	}
+}
+
+func TestSilent(t *testing.T) {
+	assert.True(t, Silent(New(Alerts, nil)))
+	assert.False(t, Silent(New(API, errors.New("boom"))))
+	assert.False(t, Silent(errors.New("boom")))
+	assert.False(t, Silent(nil))
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.Equal(t, "exit status 4", New(Alerts, nil).Error())
+	assert.Equal(t, "boom", New(API, errors.New("boom")).Error())
+}
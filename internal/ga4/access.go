@@ -0,0 +1,125 @@
+package ga4
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+// GA4 predefined access roles, as they appear after the "predefinedRoles/"
+// prefix on an access binding.
+const (
+	RoleViewer  = "viewer"
+	RoleAnalyst = "analyst"
+	RoleEditor  = "editor"
+	RoleAdmin   = "admin"
+)
+
+// roleRank orders the predefined roles by privilege. The data-restriction
+// roles (no-cost-data, no-revenue-data) grant nothing on their own and rank 0
+// along with "no access".
+var roleRank = map[string]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleEditor:  3,
+	RoleAdmin:   4,
+}
+
+// RoleRank returns a role's privilege order: 0 for no access or an unknown
+// role, 4 for admin.
+func RoleRank(role string) int {
+	return roleRank[role]
+}
+
+// PropertyAccess is what a principal is known to be able to do on a property.
+// The Admin API only shows access bindings to administrators, so for anyone
+// else the role is bracketed by probes rather than read: AtLeast is the
+// highest role proven, AtMost the highest still possible. The two are equal
+// when the role is known exactly.
+type PropertyAccess struct {
+	User    string `json:"user"`
+	AtLeast string `json:"at_least"`
+	AtMost  string `json:"at_most"`
+	// Bindings lists the user's direct grants as "<parent>: <role>", when
+	// access bindings could be read.
+	Bindings []string `json:"bindings,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// Exact reports whether the role is known rather than bracketed.
+func (a PropertyAccess) Exact() bool {
+	return a.AtLeast == a.AtMost
+}
+
+// PropertyAccess determines user's role on the property. Reading access
+// bindings succeeds only for administrators; when it is refused, a
+// conversions list tells read access from none.
+func (c *Client) PropertyAccess(propertyID, user string) (PropertyAccess, error) {
+	if err := c.ValidatePropertyID(propertyID); err != nil {
+		return PropertyAccess{}, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := c.waitForRateLimit(c.ctx, "PropertyAccess"); err != nil {
+		return PropertyAccess{}, err
+	}
+	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+	access := PropertyAccess{User: user}
+
+	bindings, err := callResult(c, verbList, "access bindings", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaAccessBinding, error) {
+		return c.admin.listAccessBindings(ctx, propertyPath)
+	})
+	if isForbidden(err) {
+		_, err := callResult(c, verbList, "conversions", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+			return c.admin.listConversionEvents(ctx, propertyPath)
+		})
+		switch {
+		case err == nil:
+			access.AtLeast, access.AtMost = RoleViewer, RoleEditor
+			access.Detail = "access bindings are admin-only, so viewer, analyst and editor cannot be told apart"
+		case isForbidden(err):
+			access.Detail = "the credential cannot read the property"
+		default:
+			return PropertyAccess{}, fmt.Errorf("failed to probe property access: %w", err)
+		}
+		return access, nil
+	}
+	if err != nil {
+		return PropertyAccess{}, fmt.Errorf("failed to list access bindings: %w", err)
+	}
+
+	access.AtLeast, access.AtMost = RoleAdmin, RoleAdmin
+	access.Bindings = bindingsFor(propertyPath, bindings, user)
+
+	// Account-level grants are inherited by every property in the account.
+	// A property-only admin cannot list them, which is not an error here.
+	property, err := callResult(c, verbGet, "property", propertyID, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.getProperty(ctx, propertyPath)
+	})
+	if err == nil && strings.HasPrefix(property.Parent, "accounts/") {
+		accountBindings, err := callResult(c, verbList, "access bindings", property.Parent, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaAccessBinding, error) {
+			return c.admin.listAccessBindings(ctx, property.Parent)
+		})
+		if err == nil {
+			access.Bindings = append(access.Bindings, bindingsFor(property.Parent, accountBindings, user)...)
+		}
+	}
+	if len(access.Bindings) == 0 {
+		access.Detail = "no direct binding; admin access is granted through a group"
+	}
+	return access, nil
+}
+
+// bindingsFor returns user's roles among bindings as "<parent>: <role>".
+func bindingsFor(parent string, bindings []*admin.GoogleAnalyticsAdminV1alphaAccessBinding, user string) []string {
+	var out []string
+	for _, b := range bindings {
+		if !strings.EqualFold(b.User, user) {
+			continue
+		}
+		for _, role := range b.Roles {
+			out = append(out, parent+": "+strings.TrimPrefix(role, "predefinedRoles/"))
+		}
+	}
+	return out
+}
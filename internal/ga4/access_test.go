@@ -0,0 +1,82 @@
+package ga4
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/googleapi"
+)
+
+const testSA = "ga4@project.iam.gserviceaccount.com"
+
+func TestPropertyAccess_AdminReadsBindings(t *testing.T) {
+	fake := &fakeAdminAPI{
+		property: &admin.GoogleAnalyticsAdminV1alphaProperty{Parent: "accounts/42"},
+		bindings: map[string][]*admin.GoogleAnalyticsAdminV1alphaAccessBinding{
+			"properties/123456789": {
+				{User: "someone@example.com", Roles: []string{"predefinedRoles/admin"}},
+				{User: "GA4@project.iam.gserviceaccount.com", Roles: []string{"predefinedRoles/admin"}},
+			},
+			"accounts/42": {
+				{User: testSA, Roles: []string{"predefinedRoles/viewer"}},
+			},
+		},
+	}
+	access, err := newTestClient(fake).PropertyAccess("123456789", testSA)
+	require.NoError(t, err)
+
+	assert.True(t, access.Exact())
+	assert.Equal(t, RoleAdmin, access.AtLeast)
+	assert.Equal(t, []string{"properties/123456789: admin", "accounts/42: viewer"}, access.Bindings)
+	assert.Empty(t, access.Detail)
+}
+
+func TestPropertyAccess_AccountBindingsForbidden(t *testing.T) {
+	fake := &fakeAdminAPI{
+		property:        &admin.GoogleAnalyticsAdminV1alphaProperty{Parent: "accounts/42"},
+		listBindingsErr: map[string]error{"accounts/42": &googleapi.Error{Code: http.StatusForbidden}},
+	}
+	access, err := newTestClient(fake).PropertyAccess("123456789", testSA)
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, access.AtMost)
+	assert.Contains(t, access.Detail, "group")
+}
+
+func TestPropertyAccess_NonAdminIsBracketed(t *testing.T) {
+	fake := &fakeAdminAPI{
+		listBindingsErr: map[string]error{"properties/123456789": &googleapi.Error{Code: http.StatusForbidden}},
+	}
+	access, err := newTestClient(fake).PropertyAccess("123456789", testSA)
+	require.NoError(t, err)
+
+	assert.False(t, access.Exact())
+	assert.Equal(t, RoleViewer, access.AtLeast)
+	assert.Equal(t, RoleEditor, access.AtMost)
+	assert.Equal(t, 1, fake.listConvCalls)
+}
+
+func TestPropertyAccess_NoAccess(t *testing.T) {
+	forbidden := &googleapi.Error{Code: http.StatusForbidden}
+	fake := &fakeAdminAPI{
+		listBindingsErr: map[string]error{"properties/123456789": forbidden},
+		listConvErr:     forbidden,
+	}
+	access, err := newTestClient(fake).PropertyAccess("123456789", testSA)
+	require.NoError(t, err)
+	assert.True(t, access.Exact())
+	assert.Equal(t, 0, RoleRank(access.AtMost))
+}
+
+func TestPropertyAccess_OtherErrorsFail(t *testing.T) {
+	fake := &fakeAdminAPI{
+		listBindingsErr: map[string]error{"properties/123456789": &googleapi.Error{Code: http.StatusInternalServerError}},
+	}
+	_, err := newTestClient(fake).PropertyAccess("123456789", testSA)
+	assert.ErrorContains(t, err, "failed to list access bindings")
+
+	_, err = newTestClient(fake).PropertyAccess("bad", testSA)
+	assert.ErrorContains(t, err, "validation failed")
+}
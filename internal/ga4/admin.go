@@ -0,0 +1,50 @@
+package ga4
+
+import (
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// GA4Admin is the slice of *Client that `ga4 setup` drives: the orchestrator
+// creates, updates and rolls back resources through it, and the pre-flight
+// validator reads the property with it. Where adminAPI is the seam under the
+// Client, GA4Admin is the seam above it; tests substitute ga4fake.Admin.
+type GA4Admin interface {
+	// Conversions
+	ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error)
+	CreateConversionFromConfig(propertyID string, conv config.ConversionConfig, currency string) error
+	SetConversionCountingMethod(conv *admin.GoogleAnalyticsAdminV1alphaConversionEvent, countingMethod string) error
+	DeleteConversion(propertyID, eventName string) error
+
+	// Custom dimensions and metrics
+	ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error)
+	CreateDimension(propertyID string, dim config.DimensionConfig) error
+	UpdateDimension(dimensionName string, dim config.DimensionConfig) error
+	RestoreDimension(previous *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error
+	ListCustomMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error)
+	CreateCustomMetric(propertyID string, metric config.MetricConfig) error
+	UpdateCustomMetric(metricName string, metric config.MetricConfig) error
+	RestoreCustomMetric(previous *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error
+
+	// Calculated metrics and channel groups
+	ListPropertyCalculatedMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error)
+	CreateCalculatedMetric(propertyID string, metric config.CalculatedMetricConfig) (string, error)
+	DeleteCalculatedMetric(name string) error
+	ListChannelGroups(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error)
+	CreateChannelGroup(propertyID string, group ChannelGroup) (*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error)
+	UpdateChannelGroup(channelGroupName string, group ChannelGroup) error
+	RestoreChannelGroup(previous *admin.GoogleAnalyticsAdminV1alphaChannelGroup) error
+	DeleteChannelGroup(channelGroupName string) error
+
+	// Property settings
+	GetDataRetention(propertyID string) (*DataRetentionSettings, error)
+	UpdateDataRetention(propertyID string, settings DataRetentionSettings) error
+	GetPropertyEnhancedMeasurement(propertyID string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error)
+	UpdateEnhancedMeasurement(streamName string, settings *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings) error
+	ListDataStreams(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error)
+	PropertyAccess(propertyID, user string) (PropertyAccess, error)
+}
+
+// Compile-time guarantee that *Client satisfies GA4Admin.
+var _ GA4Admin = (*Client)(nil)
@@ -2,6 +2,7 @@ package ga4
 
 import (
 	"context"
+	"strings"
 
 	admin "google.golang.org/api/analyticsadmin/v1alpha"
 )
@@ -21,16 +22,18 @@ type adminAPI interface {
 	createConversionEvent(ctx context.Context, parent string, e *admin.GoogleAnalyticsAdminV1alphaConversionEvent) error
 	listConversionEvents(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error)
 	deleteConversionEvent(ctx context.Context, name string) error
+	patchConversionEvent(ctx context.Context, name string, e *admin.GoogleAnalyticsAdminV1alphaConversionEvent, updateMask string) error
 
 	// CustomDimensions
 	createCustomDimension(ctx context.Context, parent string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error
 	listCustomDimensions(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error)
 	archiveCustomDimension(ctx context.Context, name string) error
+	patchCustomDimension(ctx context.Context, name string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension, updateMask string) error
 
 	// CustomMetrics
 	createCustomMetric(ctx context.Context, parent string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error
 	listCustomMetrics(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error)
-	patchCustomMetric(ctx context.Context, name string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error
+	patchCustomMetric(ctx context.Context, name string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric, updateMask string) error
 	archiveCustomMetric(ctx context.Context, name string) error
 
 	// ChannelGroups
@@ -40,9 +43,15 @@ type adminAPI interface {
 	deleteChannelGroup(ctx context.Context, name string) error
 	getChannelGroup(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error)
 
+	// CalculatedMetrics
+	createCalculatedMetric(ctx context.Context, parent, id string, m *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric) error
+	listCalculatedMetrics(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error)
+	deleteCalculatedMetric(ctx context.Context, name string) error
+
 	// DataStreams + enhanced measurement
 	listDataStreams(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error)
 	getDataStream(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error)
+	createDataStream(ctx context.Context, parent string, s *admin.GoogleAnalyticsAdminV1alphaDataStream) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error)
 	getEnhancedMeasurementSettings(ctx context.Context, settingsPath string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error)
 	updateEnhancedMeasurementSettings(ctx context.Context, settingsPath string, s *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, updateMask string) error
 
@@ -53,26 +62,47 @@ type adminAPI interface {
 	// Properties-level data retention
 	getDataRetentionSettings(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaDataRetentionSettings, error)
 	updateDataRetentionSettings(ctx context.Context, name string, s *admin.GoogleAnalyticsAdminV1alphaDataRetentionSettings, updateMask string) error
+
+	// Alpha-only surfaces, gated by config.Feature flags
+	listAudiences(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaAudience, error)
+	listExpandedDataSets(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet, error)
+	listSubpropertyEventFilters(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter, error)
+
+	// Access bindings (parent is an account or a property) + property lookup
+	listAccessBindings(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaAccessBinding, error)
+	getProperty(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaProperty, error)
+
+	// Property lifecycle (sandbox properties)
+	createProperty(ctx context.Context, p *admin.GoogleAnalyticsAdminV1alphaProperty) (*admin.GoogleAnalyticsAdminV1alphaProperty, error)
+	listProperties(ctx context.Context, filter string) ([]*admin.GoogleAnalyticsAdminV1alphaProperty, error)
+	deleteProperty(ctx context.Context, name string) error
 }
 
 // realAdminAPI is the production adminAPI backed by a live *admin.Service. Every
 // method is a one-line delegation to the SDK's fluent builder, threading the
-// context and any fixed query options (PageSize, UpdateMask) the callers need.
+// context and any fixed query options (UpdateMask) the callers need. List
+// methods follow nextPageToken through every page, pageSize items at a time.
 type realAdminAPI struct {
-	svc *admin.Service
+	svc      *admin.Service
+	pageSize int64
 }
 
+// DefaultPageSize is the page size of Admin API list calls, the largest the
+// API accepts.
+const DefaultPageSize = 200
+
 func (a *realAdminAPI) createConversionEvent(ctx context.Context, parent string, e *admin.GoogleAnalyticsAdminV1alphaConversionEvent) error {
 	_, err := a.svc.Properties.ConversionEvents.Create(parent, e).Context(ctx).Do()
 	return err
 }
 
 func (a *realAdminAPI) listConversionEvents(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
-	resp, err := a.svc.Properties.ConversionEvents.List(parent).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.ConversionEvents, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	err := a.svc.Properties.ConversionEvents.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListConversionEventsResponse) error {
+		out = append(out, resp.ConversionEvents...)
+		return nil
+	})
+	return out, err
 }
 
 func (a *realAdminAPI) deleteConversionEvent(ctx context.Context, name string) error {
@@ -80,17 +110,23 @@ func (a *realAdminAPI) deleteConversionEvent(ctx context.Context, name string) e
 	return err
 }
 
+func (a *realAdminAPI) patchConversionEvent(ctx context.Context, name string, e *admin.GoogleAnalyticsAdminV1alphaConversionEvent, updateMask string) error {
+	_, err := a.svc.Properties.ConversionEvents.Patch(name, e).UpdateMask(updateMask).Context(ctx).Do()
+	return err
+}
+
 func (a *realAdminAPI) createCustomDimension(ctx context.Context, parent string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error {
 	_, err := a.svc.Properties.CustomDimensions.Create(parent, d).Context(ctx).Do()
 	return err
 }
 
 func (a *realAdminAPI) listCustomDimensions(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
-	resp, err := a.svc.Properties.CustomDimensions.List(parent).PageSize(200).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.CustomDimensions, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	err := a.svc.Properties.CustomDimensions.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListCustomDimensionsResponse) error {
+		out = append(out, resp.CustomDimensions...)
+		return nil
+	})
+	return out, err
 }
 
 func (a *realAdminAPI) archiveCustomDimension(ctx context.Context, name string) error {
@@ -98,21 +134,27 @@ func (a *realAdminAPI) archiveCustomDimension(ctx context.Context, name string)
 	return err
 }
 
+func (a *realAdminAPI) patchCustomDimension(ctx context.Context, name string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension, updateMask string) error {
+	_, err := a.svc.Properties.CustomDimensions.Patch(name, d).UpdateMask(updateMask).Context(ctx).Do()
+	return err
+}
+
 func (a *realAdminAPI) createCustomMetric(ctx context.Context, parent string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
 	_, err := a.svc.Properties.CustomMetrics.Create(parent, m).Context(ctx).Do()
 	return err
 }
 
 func (a *realAdminAPI) listCustomMetrics(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
-	resp, err := a.svc.Properties.CustomMetrics.List(parent).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.CustomMetrics, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	err := a.svc.Properties.CustomMetrics.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListCustomMetricsResponse) error {
+		out = append(out, resp.CustomMetrics...)
+		return nil
+	})
+	return out, err
 }
 
-func (a *realAdminAPI) patchCustomMetric(ctx context.Context, name string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
-	_, err := a.svc.Properties.CustomMetrics.Patch(name, m).Context(ctx).Do()
+func (a *realAdminAPI) patchCustomMetric(ctx context.Context, name string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric, updateMask string) error {
+	_, err := a.svc.Properties.CustomMetrics.Patch(name, m).UpdateMask(updateMask).Context(ctx).Do()
 	return err
 }
 
@@ -126,11 +168,12 @@ func (a *realAdminAPI) createChannelGroup(ctx context.Context, parent string, g
 }
 
 func (a *realAdminAPI) listChannelGroups(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
-	resp, err := a.svc.Properties.ChannelGroups.List(parent).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.ChannelGroups, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaChannelGroup
+	err := a.svc.Properties.ChannelGroups.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListChannelGroupsResponse) error {
+		out = append(out, resp.ChannelGroups...)
+		return nil
+	})
+	return out, err
 }
 
 func (a *realAdminAPI) patchChannelGroup(ctx context.Context, name string, g *admin.GoogleAnalyticsAdminV1alphaChannelGroup, updateMask string) error {
@@ -147,18 +190,42 @@ func (a *realAdminAPI) getChannelGroup(ctx context.Context, name string) (*admin
 	return a.svc.Properties.ChannelGroups.Get(name).Context(ctx).Do()
 }
 
+func (a *realAdminAPI) createCalculatedMetric(ctx context.Context, parent, id string, m *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric) error {
+	_, err := a.svc.Properties.CalculatedMetrics.Create(parent, m).CalculatedMetricId(id).Context(ctx).Do()
+	return err
+}
+
+func (a *realAdminAPI) listCalculatedMetrics(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric
+	err := a.svc.Properties.CalculatedMetrics.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListCalculatedMetricsResponse) error {
+		out = append(out, resp.CalculatedMetrics...)
+		return nil
+	})
+	return out, err
+}
+
+func (a *realAdminAPI) deleteCalculatedMetric(ctx context.Context, name string) error {
+	_, err := a.svc.Properties.CalculatedMetrics.Delete(name).Context(ctx).Do()
+	return err
+}
+
 func (a *realAdminAPI) listDataStreams(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
-	resp, err := a.svc.Properties.DataStreams.List(parent).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.DataStreams, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaDataStream
+	err := a.svc.Properties.DataStreams.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListDataStreamsResponse) error {
+		out = append(out, resp.DataStreams...)
+		return nil
+	})
+	return out, err
 }
 
 func (a *realAdminAPI) getDataStream(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
 	return a.svc.Properties.DataStreams.Get(name).Context(ctx).Do()
 }
 
+func (a *realAdminAPI) createDataStream(ctx context.Context, parent string, s *admin.GoogleAnalyticsAdminV1alphaDataStream) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+	return a.svc.Properties.DataStreams.Create(parent, s).Context(ctx).Do()
+}
+
 func (a *realAdminAPI) getEnhancedMeasurementSettings(ctx context.Context, settingsPath string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
 	return a.svc.Properties.DataStreams.GetEnhancedMeasurementSettings(settingsPath).Context(ctx).Do()
 }
@@ -169,11 +236,12 @@ func (a *realAdminAPI) updateEnhancedMeasurementSettings(ctx context.Context, se
 }
 
 func (a *realAdminAPI) listBigQueryLinks(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
-	resp, err := a.svc.Properties.BigQueryLinks.List(parent).Context(ctx).Do()
-	if err != nil {
-		return nil, err
-	}
-	return resp.BigqueryLinks, nil
+	var out []*admin.GoogleAnalyticsAdminV1alphaBigQueryLink
+	err := a.svc.Properties.BigQueryLinks.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListBigQueryLinksResponse) error {
+		out = append(out, resp.BigqueryLinks...)
+		return nil
+	})
+	return out, err
 }
 
 func (a *realAdminAPI) getBigQueryLink(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
@@ -188,3 +256,67 @@ func (a *realAdminAPI) updateDataRetentionSettings(ctx context.Context, name str
 	_, err := a.svc.Properties.UpdateDataRetentionSettings(name, s).UpdateMask(updateMask).Context(ctx).Do()
 	return err
 }
+
+func (a *realAdminAPI) listAudiences(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaAudience, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaAudience
+	err := a.svc.Properties.Audiences.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListAudiencesResponse) error {
+		out = append(out, resp.Audiences...)
+		return nil
+	})
+	return out, err
+}
+
+func (a *realAdminAPI) listExpandedDataSets(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet
+	err := a.svc.Properties.ExpandedDataSets.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListExpandedDataSetsResponse) error {
+		out = append(out, resp.ExpandedDataSets...)
+		return nil
+	})
+	return out, err
+}
+
+func (a *realAdminAPI) listSubpropertyEventFilters(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter
+	err := a.svc.Properties.SubpropertyEventFilters.List(parent).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListSubpropertyEventFiltersResponse) error {
+		out = append(out, resp.SubpropertyEventFilters...)
+		return nil
+	})
+	return out, err
+}
+
+func (a *realAdminAPI) listAccessBindings(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaAccessBinding, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaAccessBinding
+	collect := func(resp *admin.GoogleAnalyticsAdminV1alphaListAccessBindingsResponse) error {
+		out = append(out, resp.AccessBindings...)
+		return nil
+	}
+	var err error
+	if strings.HasPrefix(parent, "accounts/") {
+		err = a.svc.Accounts.AccessBindings.List(parent).PageSize(a.pageSize).Pages(ctx, collect)
+	} else {
+		err = a.svc.Properties.AccessBindings.List(parent).PageSize(a.pageSize).Pages(ctx, collect)
+	}
+	return out, err
+}
+
+func (a *realAdminAPI) getProperty(ctx context.Context, name string) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	return a.svc.Properties.Get(name).Context(ctx).Do()
+}
+
+func (a *realAdminAPI) createProperty(ctx context.Context, p *admin.GoogleAnalyticsAdminV1alphaProperty) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	return a.svc.Properties.Create(p).Context(ctx).Do()
+}
+
+func (a *realAdminAPI) listProperties(ctx context.Context, filter string) ([]*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	var out []*admin.GoogleAnalyticsAdminV1alphaProperty
+	err := a.svc.Properties.List().Filter(filter).PageSize(a.pageSize).Pages(ctx, func(resp *admin.GoogleAnalyticsAdminV1alphaListPropertiesResponse) error {
+		out = append(out, resp.Properties...)
+		return nil
+	})
+	return out, err
+}
+
+func (a *realAdminAPI) deleteProperty(ctx context.Context, name string) error {
+	_, err := a.svc.Properties.Delete(name).Context(ctx).Do()
+	return err
+}
@@ -0,0 +1,154 @@
+package ga4
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/option"
+)
+
+// pagedAdminServer serves every Admin API list endpoint from a collection of
+// total items, honouring pageSize and pageToken like the real API, and counts
+// the requests it answered.
+type pagedAdminServer struct {
+	total    int
+	requests int
+	sizes    []int // pageSize of each request
+}
+
+func (s *pagedAdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	collection := path.Base(r.URL.Path)
+	field := collection
+	if collection == "bigQueryLinks" {
+		field = "bigqueryLinks" // the response field is spelled differently
+	}
+
+	size, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	s.sizes = append(s.sizes, size)
+	if size <= 0 {
+		size = 50
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := min(start+size, s.total)
+
+	items := []map[string]string{}
+	for i := start; i < end; i++ {
+		items = append(items, map[string]string{"name": fmt.Sprintf("%s/%d", collection, i)})
+	}
+	resp := map[string]any{field: items}
+	if end < s.total {
+		resp["nextPageToken"] = strconv.Itoa(end)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newPagedAdminAPI(t *testing.T, srv *pagedAdminServer, pageSize int64) *realAdminAPI {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	svc, err := admin.NewService(context.Background(), option.WithEndpoint(ts.URL+"/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+	return &realAdminAPI{svc: svc, pageSize: pageSize}
+}
+
+func TestRealAdminAPI_ListsFetchEveryPage(t *testing.T) {
+	lists := map[string]func(a *realAdminAPI, ctx context.Context) (int, error){
+		"conversion events": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listConversionEvents(ctx, "properties/1")
+			return len(items), err
+		},
+		"custom dimensions": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listCustomDimensions(ctx, "properties/1")
+			return len(items), err
+		},
+		"custom metrics": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listCustomMetrics(ctx, "properties/1")
+			return len(items), err
+		},
+		"channel groups": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listChannelGroups(ctx, "properties/1")
+			return len(items), err
+		},
+		"calculated metrics": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listCalculatedMetrics(ctx, "properties/1")
+			return len(items), err
+		},
+		"data streams": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listDataStreams(ctx, "properties/1")
+			return len(items), err
+		},
+		"bigquery links": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listBigQueryLinks(ctx, "properties/1")
+			return len(items), err
+		},
+		"audiences": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listAudiences(ctx, "properties/1")
+			return len(items), err
+		},
+		"expanded data sets": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listExpandedDataSets(ctx, "properties/1")
+			return len(items), err
+		},
+		"subproperty event filters": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listSubpropertyEventFilters(ctx, "properties/1")
+			return len(items), err
+		},
+		"property access bindings": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listAccessBindings(ctx, "properties/1")
+			return len(items), err
+		},
+		"account access bindings": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listAccessBindings(ctx, "accounts/1")
+			return len(items), err
+		},
+		"properties": func(a *realAdminAPI, ctx context.Context) (int, error) {
+			items, err := a.listProperties(ctx, "parent:accounts/1")
+			return len(items), err
+		},
+	}
+
+	for name, list := range lists {
+		t.Run(name, func(t *testing.T) {
+			srv := &pagedAdminServer{total: 7}
+			a := newPagedAdminAPI(t, srv, 3)
+
+			n, err := list(a, context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 7, n, "every item across all pages")
+			assert.Equal(t, 3, srv.requests, "pages of 3, 3 and 1")
+			assert.Equal(t, []int{3, 3, 3}, srv.sizes)
+		})
+	}
+}
+
+func TestRealAdminAPI_SinglePageWhenItFits(t *testing.T) {
+	srv := &pagedAdminServer{total: 60}
+	a := newPagedAdminAPI(t, srv, DefaultPageSize)
+
+	convs, err := a.listConversionEvents(context.Background(), "properties/1")
+	require.NoError(t, err)
+	assert.Len(t, convs, 60)
+	assert.Equal(t, 1, srv.requests)
+	assert.Equal(t, []int{DefaultPageSize}, srv.sizes)
+}
+
+func TestWithPageSize_ClampsToTheAPIRange(t *testing.T) {
+	for _, tc := range []struct{ in, want int64 }{
+		{50, 50}, {0, 1}, {-5, 1}, {1000, DefaultPageSize},
+	} {
+		c := &Client{}
+		WithPageSize(tc.in)(c)
+		assert.Equal(t, tc.want, c.pageSize, "WithPageSize(%d)", tc.in)
+	}
+}
@@ -8,19 +8,53 @@ import (
 )
 
 // Audiences cannot be created through the GA4 Admin API, so this package does
-// not manage them. The two helpers below summarise audience configuration for
-// the report/export commands. They currently return empty results until
-// YAML-driven audience configuration lands (TODO #16); the elaborate
-// doc-generation machinery that previously lived here was removed because it
-// only ever operated on an always-empty audience list.
+// not manage them. The helpers below summarise the audiences a project config
+// lists, for the report/export commands and the manual setup in the GA4 UI.
 
-// ListAudiencesByCategory returns audiences grouped by category.
-func ListAudiencesByCategory(_ *config.ProjectConfig) map[string][]config.EnhancedAudience {
-	// TODO(#16): populate from YAML audience configuration.
-	var audiences []config.EnhancedAudience
+// DefaultAudienceCategory is the category of an audience whose config does
+// not give one.
+const DefaultAudienceCategory = "General"
+
+// ConfiguredAudiences returns the audiences of a project config in config
+// order.
+func ConfiguredAudiences(cfg *config.ProjectConfig) []config.EnhancedAudience {
+	if cfg == nil {
+		return nil
+	}
+	audiences := make([]config.EnhancedAudience, 0, len(cfg.Audiences))
+	for _, aud := range cfg.Audiences {
+		category := aud.Category
+		if category == "" {
+			category = DefaultAudienceCategory
+		}
+		audiences = append(audiences, config.EnhancedAudience{
+			Name:               aud.Name,
+			Description:        aud.Description,
+			MembershipDuration: aud.Duration,
+			Category:           category,
+		})
+	}
+	return audiences
+}
+
+// AudienceCategories returns the categories of a project config's audiences
+// in the order they first appear.
+func AudienceCategories(cfg *config.ProjectConfig) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, aud := range ConfiguredAudiences(cfg) {
+		if !seen[aud.Category] {
+			seen[aud.Category] = true
+			categories = append(categories, aud.Category)
+		}
+	}
+	return categories
+}
 
+// ListAudiencesByCategory returns audiences grouped by category.
+func ListAudiencesByCategory(cfg *config.ProjectConfig) map[string][]config.EnhancedAudience {
 	categories := make(map[string][]config.EnhancedAudience)
-	for _, aud := range audiences {
+	for _, aud := range ConfiguredAudiences(cfg) {
 		categories[aud.Category] = append(categories[aud.Category], aud)
 	}
 
@@ -28,20 +62,14 @@ func ListAudiencesByCategory(_ *config.ProjectConfig) map[string][]config.Enhanc
 }
 
 // GetAudienceSummary returns a human-readable summary of all audiences.
-func GetAudienceSummary(_ *config.ProjectConfig) string {
-	// TODO(#16): populate from YAML audience configuration.
-	var audiences []config.EnhancedAudience
-
-	categories := make(map[string]int)
-	for _, aud := range audiences {
-		categories[aud.Category]++
-	}
+func GetAudienceSummary(cfg *config.ProjectConfig) string {
+	byCategory := ListAudiencesByCategory(cfg)
 
 	var summary strings.Builder
-	fmt.Fprintf(&summary, "Total Audiences: %d\n", len(audiences))
+	fmt.Fprintf(&summary, "Total Audiences: %d\n", len(ConfiguredAudiences(cfg)))
 	summary.WriteString("By Category:\n")
-	for category, count := range categories {
-		fmt.Fprintf(&summary, "  - %s: %d\n", category, count)
+	for _, category := range AudienceCategories(cfg) {
+		fmt.Fprintf(&summary, "  - %s: %d\n", category, len(byCategory[category]))
 	}
 
 	return summary.String()
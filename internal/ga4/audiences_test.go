@@ -0,0 +1,27 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestAudiencesFromConfig(t *testing.T) {
+	cfg := &config.ProjectConfig{Audiences: []config.AudienceConfig{
+		{Name: "Cart Abandoners", Duration: 7, Category: "Conversion"},
+		{Name: "Power Users", Duration: 30},
+		{Name: "Buyers", Duration: 90, Category: "Conversion"},
+	}}
+
+	assert.Equal(t, []string{"Conversion", DefaultAudienceCategory}, AudienceCategories(cfg))
+
+	byCategory := ListAudiencesByCategory(cfg)
+	assert.Len(t, byCategory["Conversion"], 2)
+	assert.Equal(t, "Buyers", byCategory["Conversion"][1].Name)
+	assert.Equal(t, 30, byCategory[DefaultAudienceCategory][0].MembershipDuration)
+
+	assert.Equal(t, "Total Audiences: 3\nBy Category:\n  - Conversion: 2\n  - General: 1\n", GetAudienceSummary(cfg))
+	assert.Empty(t, ListAudiencesByCategory(nil))
+}
@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"fmt"
 
 	"google.golang.org/api/analyticsadmin/v1alpha"
@@ -29,7 +30,9 @@ func (c *Client) CreateBigQueryLink(config BigQueryConfig) (*analyticsadmin.Goog
 func (c *Client) ListBigQueryLinks(propertyID string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
 	propertyPath := fmt.Sprintf("properties/%s", propertyID)
 
-	links, err := c.admin.listBigQueryLinks(c.ctx, propertyPath)
+	links, err := callResult(c, verbList, "BigQuery links", propertyID, func(ctx context.Context) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
+		return c.admin.listBigQueryLinks(ctx, propertyPath)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list BigQuery links: %w", err)
 	}
@@ -39,7 +42,9 @@ func (c *Client) ListBigQueryLinks(propertyID string) ([]*analyticsadmin.GoogleA
 
 // GetBigQueryLink retrieves a specific BigQuery link
 func (c *Client) GetBigQueryLink(linkName string) (*analyticsadmin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
-	link, err := c.admin.getBigQueryLink(c.ctx, linkName)
+	link, err := callResult(c, verbGet, "BigQuery link", linkName, func(ctx context.Context) (*analyticsadmin.GoogleAnalyticsAdminV1alphaBigQueryLink, error) {
+		return c.admin.getBigQueryLink(ctx, linkName)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get BigQuery link: %w", err)
 	}
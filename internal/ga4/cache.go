@@ -0,0 +1,188 @@
+package ga4
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig configures the list cache. Setup, preflight and diff each list
+// a property's conversions, dimensions and metrics; with the cache the later
+// lists of a run reuse the first one's answer. Any create, update or delete
+// made through the client empties it.
+type CacheConfig struct {
+	// Disabled turns the cache off: every list call reaches the API.
+	Disabled bool
+
+	// TTL, when positive, also keeps list results on disk for that long, so
+	// consecutive commands share them. Changes made outside this client (in
+	// the GA4 UI or by another run) are not seen until entries expire.
+	TTL time.Duration
+
+	// Dir holds the on-disk entries (default: DefaultCacheDir).
+	Dir string
+}
+
+// DefaultCacheDir returns the on-disk cache directory, ga4-manager under the
+// user cache directory (~/.cache on Linux).
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ga4-manager", "ga4"), nil
+}
+
+// WithCache configures the list cache. Without it the client caches in
+// memory only.
+func WithCache(cfg CacheConfig) ClientOption {
+	return func(c *Client) {
+		if cfg.Disabled {
+			c.cache = nil
+			return
+		}
+		c.cache = newListCache(cfg.TTL, cfg.Dir)
+	}
+}
+
+// listCache holds list results by resource kind and property, in memory and
+// optionally on disk.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[string]any // key -> []T
+	gen     int            // bumped by invalidate
+	ttl     time.Duration  // on-disk lifetime; 0 keeps entries in memory only
+	dir     string
+}
+
+func newListCache(ttl time.Duration, dir string) *listCache {
+	if ttl > 0 && dir == "" {
+		if d, err := DefaultCacheDir(); err == nil {
+			dir = d
+		} else {
+			ttl = 0
+		}
+	}
+	return &listCache{entries: map[string]any{}, ttl: ttl, dir: dir}
+}
+
+// diskEntry is one on-disk cache file.
+type diskEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Items     json.RawMessage `json:"items"`
+}
+
+func cacheKey(kind, propertyID string) string {
+	return propertyID + "-" + strings.ReplaceAll(kind, " ", "_")
+}
+
+// invalidate drops every entry, in memory and on disk.
+func (lc *listCache) invalidate(logger *slog.Logger) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	clear(lc.entries)
+	lc.gen++
+	if lc.ttl > 0 {
+		if err := os.RemoveAll(lc.dir); err != nil {
+			logger.Warn("failed to clear the list cache", slog.String("dir", lc.dir), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// cachedList returns the cached list of kind for propertyID, calling fetch
+// on a miss. A copy of the slice is returned so callers may reorder or
+// append to it; the items themselves are shared.
+func cachedList[T any](c *Client, kind, propertyID string, fetch func() ([]T, error)) ([]T, error) {
+	lc := c.cache
+	if lc == nil {
+		return fetch()
+	}
+	key := cacheKey(kind, propertyID)
+
+	lc.mu.Lock()
+	if items, ok := lc.entries[key].([]T); ok {
+		lc.mu.Unlock()
+		c.logger.Debug("list cache hit", slog.String("kind", kind), slog.String("property_id", propertyID))
+		return append([]T(nil), items...), nil
+	}
+	if items, ok := readDiskEntry[T](lc, key); ok {
+		lc.entries[key] = items
+		lc.mu.Unlock()
+		c.logger.Debug("list cache hit on disk", slog.String("kind", kind), slog.String("property_id", propertyID))
+		return append([]T(nil), items...), nil
+	}
+	gen := lc.gen
+	lc.mu.Unlock()
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.gen != gen {
+		// The property changed while the list was in flight; it may be stale.
+		return items, nil
+	}
+	lc.entries[key] = items
+	if err := writeDiskEntry(lc, key, items); err != nil {
+		c.logger.Warn("failed to write the list cache", slog.String("kind", kind), slog.String("error", err.Error()))
+	}
+	return append([]T(nil), items...), nil
+}
+
+// readDiskEntry returns an unexpired on-disk entry. Unreadable or corrupt
+// entries count as misses. The caller holds lc.mu.
+func readDiskEntry[T any](lc *listCache, key string) ([]T, bool) {
+	if lc.ttl <= 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(lc.dir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var entry diskEntry
+	if json.Unmarshal(data, &entry) != nil || time.Since(entry.FetchedAt) > lc.ttl {
+		return nil, false
+	}
+	var items []T
+	if json.Unmarshal(entry.Items, &items) != nil {
+		return nil, false
+	}
+	return items, true
+}
+
+// writeDiskEntry saves items on disk when the cache has a TTL. The caller
+// holds lc.mu.
+func writeDiskEntry[T any](lc *listCache, key string, items []T) error {
+	if lc.ttl <= 0 {
+		return nil
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(diskEntry{FetchedAt: time.Now(), Items: raw})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(lc.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", lc.dir, err)
+	}
+	path := filepath.Join(lc.dir, key+".json")
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
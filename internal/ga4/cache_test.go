@@ -0,0 +1,98 @@
+package ga4
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+func TestListCache_ReusesUntilAChange(t *testing.T) {
+	fake := &fakeAdminAPI{convList: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{{EventName: "purchase"}}}
+	c := newTestClient(fake)
+	c.cache = newListCache(0, "")
+
+	for range 3 {
+		convs, err := c.ListConversions("123456789")
+		require.NoError(t, err)
+		assert.Len(t, convs, 1)
+	}
+	assert.Equal(t, 1, fake.listConvCalls)
+
+	// Each property and resource kind has its own entry
+	_, err := c.ListConversions("987654321")
+	require.NoError(t, err)
+	_, err = c.ListDimensions("123456789")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.listConvCalls)
+	assert.Equal(t, 1, fake.listDimCalls)
+
+	// A change empties the cache
+	require.NoError(t, c.DeleteConversion("123456789", "purchase"))
+	_, err = c.ListConversions("123456789")
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.listConvCalls)
+}
+
+func TestListCache_ErrorsAreNotCached(t *testing.T) {
+	fake := &fakeAdminAPI{listConvErr: errors.New("backend error")}
+	c := newTestClient(fake)
+	c.cache = newListCache(0, "")
+
+	_, err := c.ListConversions("123456789")
+	require.Error(t, err)
+	fake.listConvErr = nil
+	_, err = c.ListConversions("123456789")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.listConvCalls)
+}
+
+func TestListCache_Disabled(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	WithCache(CacheConfig{Disabled: true})(c)
+
+	for range 2 {
+		_, err := c.ListCustomMetrics("123456789")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, fake.listMetCalls)
+}
+
+func TestListCache_OnDisk(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeAdminAPI{convList: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"}}}
+
+	// A second client, as in the next command, reads the first one's entry
+	first := newTestClient(fake)
+	WithCache(CacheConfig{TTL: time.Minute, Dir: dir})(first)
+	_, err := first.ListConversions("123456789")
+	require.NoError(t, err)
+
+	second := newTestClient(fake)
+	WithCache(CacheConfig{TTL: time.Minute, Dir: dir})(second)
+	convs, err := second.ListConversions("123456789")
+	require.NoError(t, err)
+	require.Len(t, convs, 1)
+	assert.Equal(t, "ONCE_PER_EVENT", convs[0].CountingMethod)
+	assert.Equal(t, 1, fake.listConvCalls)
+
+	// Expired entries are fetched again
+	expired := newTestClient(fake)
+	WithCache(CacheConfig{TTL: time.Nanosecond, Dir: dir})(expired)
+	time.Sleep(time.Millisecond)
+	_, err = expired.ListConversions("123456789")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.listConvCalls)
+
+	// A change through any client clears the disk entries
+	require.NoError(t, second.DeleteConversion("123456789", "purchase"))
+	third := newTestClient(fake)
+	WithCache(CacheConfig{TTL: time.Minute, Dir: dir})(third)
+	_, err = third.ListConversions("123456789")
+	require.NoError(t, err)
+	assert.Greater(t, fake.listConvCalls, 2)
+}
@@ -1,13 +1,20 @@
 package ga4
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+
+	analyticsadmin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/validation"
 )
 
-// CalculatedMetric represents a calculated metric definition
-// Note: Calculated metrics must be created manually in GA4 UI as they are not yet
-// fully supported in the Admin API. This provides documentation and guidance.
+// CalculatedMetric represents a recommended calculated metric. The
+// recommendations are listed by `ga4 report`; calculated metrics declared in
+// a config are created by setup through CreateCalculatedMetric.
 type CalculatedMetric struct {
 	DisplayName string
 	Description string
@@ -76,31 +83,240 @@ func (c *Client) ListCalculatedMetrics(propertyID string) ([]CalculatedMetric, e
 
 // ValidateFormula validates a calculated metric formula
 func (c *Client) ValidateFormula(formula string) error {
-	if formula == "" {
-		return fmt.Errorf("formula cannot be empty")
+	_, err := FormulaCustomMetrics(formula)
+	return err
+}
+
+// SetupDefaultCalculatedMetrics provides information about calculated metrics
+// Since calculated metrics must be created manually in GA4 UI, this returns nil
+// The setup command will display these as recommendations
+func (c *Client) SetupDefaultCalculatedMetrics(propertyID string) error {
+	// Calculated metrics must be created manually in GA4 UI
+	// This function returns nil to indicate the list should be displayed
+	return nil
+}
+
+// MaxFormulaCustomMetrics is the API's limit on distinct custom metrics one
+// formula may reference.
+const MaxFormulaCustomMetrics = 5
+
+// customMetricPrefix marks a custom metric in a formula, as in
+// "customEvent:reading_time / sessions".
+const customMetricPrefix = "customEvent:"
+
+// CalculatedMetricID returns the calculated_metric_id setup creates c under:
+// its id, or its name lower-cased with every run of other characters turned
+// into an underscore ("Revenue per User" becomes "revenue_per_user").
+func CalculatedMetricID(c config.CalculatedMetricConfig) string {
+	if c.ID != "" {
+		return c.ID
 	}
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(c.Name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
 
-	// Basic checks
-	if !strings.Contains(formula, "/") && !strings.Contains(formula, "+") &&
-		!strings.Contains(formula, "-") && !strings.Contains(formula, "*") {
-		return fmt.Errorf("formula must contain at least one operator (+, -, *, /)")
+// FormulaCustomMetrics parses a calculated metric formula and returns the
+// parameter names of the custom metrics it references, in order of first
+// use. Formulas combine metric API names, customEvent:<parameter> references
+// and numbers with + - * / and parentheses.
+func FormulaCustomMetrics(formula string) ([]string, error) {
+	p := &formulaParser{s: formula}
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return nil, fmt.Errorf("formula cannot be empty")
 	}
+	if err := p.expr(); err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("formula %q: unexpected %q at %d", formula, p.s[p.pos], p.pos)
+	}
+	if len(p.refs) > MaxFormulaCustomMetrics {
+		return nil, fmt.Errorf("formula %q references %d custom metrics; the API allows %d", formula, len(p.refs), MaxFormulaCustomMetrics)
+	}
+	return p.refs, nil
+}
+
+// formulaParser checks formula syntax by recursive descent:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = "-" factor | "(" expr ")" | number | metric
+type formulaParser struct {
+	s    string
+	pos  int
+	refs []string
+}
+
+func (p *formulaParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
 
-	// Check for balanced parentheses
-	openCount := strings.Count(formula, "(")
-	closeCount := strings.Count(formula, ")")
-	if openCount != closeCount {
-		return fmt.Errorf("formula has unbalanced parentheses")
+// accept consumes c if it is next.
+func (p *formulaParser) accept(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		p.skipSpace()
+		return true
 	}
+	return false
+}
 
+func (p *formulaParser) expr() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	for p.accept('+') || p.accept('-') {
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// SetupDefaultCalculatedMetrics provides information about calculated metrics
-// Since calculated metrics must be created manually in GA4 UI, this returns nil
-// The setup command will display these as recommendations
-func (c *Client) SetupDefaultCalculatedMetrics(propertyID string) error {
-	// Calculated metrics must be created manually in GA4 UI
-	// This function returns nil to indicate the list should be displayed
+func (p *formulaParser) term() error {
+	if err := p.factor(); err != nil {
+		return err
+	}
+	for p.accept('*') || p.accept('/') {
+		if err := p.factor(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *formulaParser) factor() error {
+	if p.accept('-') {
+		return p.factor()
+	}
+	if p.accept('(') {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if !p.accept(')') {
+			return p.errorf("expected )")
+		}
+		return nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isFormulaByte(p.s[p.pos]) {
+		p.pos++
+	}
+	word := p.s[start:p.pos]
+	p.skipSpace()
+	switch {
+	case word == "":
+		return p.errorf("expected a metric or a number")
+	case word[0] >= '0' && word[0] <= '9' || word[0] == '.':
+		for _, c := range word {
+			if (c < '0' || c > '9') && c != '.' {
+				return fmt.Errorf("formula %q: %q is not a number", p.s, word)
+			}
+		}
+	case strings.HasPrefix(word, customMetricPrefix):
+		param := strings.TrimPrefix(word, customMetricPrefix)
+		if param == "" || strings.Contains(param, ":") {
+			return fmt.Errorf("formula %q: %q is not a custom metric reference", p.s, word)
+		}
+		for _, r := range p.refs {
+			if r == param {
+				return nil
+			}
+		}
+		p.refs = append(p.refs, param)
+	case strings.Contains(word, ":"):
+		return fmt.Errorf("formula %q: %q: only customEvent: references are supported", p.s, word)
+	}
+	return nil
+}
+
+func (p *formulaParser) errorf(msg string) error {
+	if p.pos >= len(p.s) {
+		return fmt.Errorf("formula %q: %s at end of formula", p.s, msg)
+	}
+	return fmt.Errorf("formula %q: %s at %d", p.s, msg, p.pos)
+}
+
+func isFormulaByte(c byte) bool {
+	return c == '_' || c == ':' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// CreateCalculatedMetric creates a calculated metric under
+// CalculatedMetricID(metric) and returns its resource name. The formula must
+// parse; that the custom metrics it references exist is left to the caller.
+func (c *Client) CreateCalculatedMetric(propertyID string, metric config.CalculatedMetricConfig) (string, error) {
+	id := CalculatedMetricID(metric)
+	if _, err := FormulaCustomMetrics(metric.Formula); err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validation.ValidatePropertyID(propertyID); err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validation.ValidateMeasurementUnit(metric.MetricUnit); err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+
+	c.logger.Debug("creating calculated metric",
+		slog.String("property_id", propertyID),
+		slog.String("id", id),
+		slog.String("formula", metric.Formula),
+	)
+
+	unit := metric.MetricUnit
+	if unit == "" {
+		unit = "STANDARD"
+	}
+	calculated := &analyticsadmin.GoogleAnalyticsAdminV1alphaCalculatedMetric{
+		DisplayName: metric.Name,
+		Description: metric.Description,
+		Formula:     metric.Formula,
+		MetricUnit:  unit,
+	}
+	err := c.createResource("calculated metric", propertyID, metric.Name, calculated, func(ctx context.Context, parent string) error {
+		return c.admin.createCalculatedMetric(ctx, parent, id, calculated)
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("properties/%s/calculatedMetrics/%s", propertyID, id), nil
+}
+
+// ListPropertyCalculatedMetrics returns the calculated metrics that exist on
+// a property, unlike ListCalculatedMetrics, which returns recommendations.
+func (c *Client) ListPropertyCalculatedMetrics(propertyID string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	return listResource(c, "calculated metric", propertyID, func(ctx context.Context, parent string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+		return c.admin.listCalculatedMetrics(ctx, parent)
+	})
+}
+
+// DeleteCalculatedMetric deletes a calculated metric by resource name
+// (properties/{id}/calculatedMetrics/{metric}).
+func (c *Client) DeleteCalculatedMetric(name string) error {
+	if err := c.waitForRateLimit(c.ctx, "DeleteCalculatedMetric"); err != nil {
+		return err
+	}
+	if err := c.change(verbDelete, "calculated metric", name, "", nil, func(ctx context.Context) error {
+		return c.admin.deleteCalculatedMetric(ctx, name)
+	}); err != nil {
+		c.logger.Error("failed to delete calculated metric",
+			slog.String("name", name),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to delete calculated metric '%s': %w", name, err)
+	}
+	c.logger.Info("calculated metric deleted successfully", slog.String("name", name))
 	return nil
 }
@@ -0,0 +1,87 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatedMetricID(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.CalculatedMetricConfig
+		want string
+	}{
+		{"explicit id", config.CalculatedMetricConfig{ID: "rev_per_user", Name: "Revenue per User"}, "rev_per_user"},
+		{"from name", config.CalculatedMetricConfig{Name: "Revenue per User"}, "revenue_per_user"},
+		{"punctuation collapses", config.CalculatedMetricConfig{Name: "  CTR (organic) %"}, "ctr_organic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculatedMetricID(tt.cfg))
+		})
+	}
+}
+
+func TestFormulaCustomMetrics(t *testing.T) {
+	refs, err := FormulaCustomMetrics("(customEvent:reading_time + customEvent:scroll_depth * 0.5) / sessions - customEvent:reading_time")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reading_time", "scroll_depth"}, refs)
+
+	refs, err = FormulaCustomMetrics("totalRevenue / activeUsers")
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	for _, formula := range []string{
+		"",
+		"sessions /",
+		"(sessions + users",
+		"sessions users",
+		"customEvent: / sessions",
+		"customUser:plan / sessions",
+		"1.2x * sessions",
+		"customEvent:a + customEvent:b + customEvent:c + customEvent:d + customEvent:e + customEvent:f",
+	} {
+		_, err := FormulaCustomMetrics(formula)
+		assert.Error(t, err, formula)
+	}
+}
+
+func TestCreateCalculatedMetric_CallsAPIWithIDAndDefaultUnit(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	name, err := c.CreateCalculatedMetric("123456789", config.CalculatedMetricConfig{
+		Name:    "Reading Time per Session",
+		Formula: "customEvent:reading_time / sessions",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "properties/123456789/calculatedMetrics/reading_time_per_session", name)
+	assert.Equal(t, "properties/123456789", fake.gotCreateCalcParent)
+	assert.Equal(t, "reading_time_per_session", fake.gotCreateCalcID)
+	require.NotNil(t, fake.gotCreateCalc)
+	assert.Equal(t, "Reading Time per Session", fake.gotCreateCalc.DisplayName)
+	assert.Equal(t, "STANDARD", fake.gotCreateCalc.MetricUnit)
+}
+
+func TestCreateCalculatedMetric_InvalidFormulaNotSent(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	_, err := c.CreateCalculatedMetric("123456789", config.CalculatedMetricConfig{Name: "Broken", Formula: "sessions *"})
+
+	require.Error(t, err)
+	assert.Nil(t, fake.gotCreateCalc)
+}
+
+func TestCreateCalculatedMetric_AlreadyExistsSurfacedAsSentinel(t *testing.T) {
+	fake := &fakeAdminAPI{createCalcErr: errAlreadyExists}
+	c := newTestClient(fake)
+
+	_, err := c.CreateCalculatedMetric("123456789", config.CalculatedMetricConfig{Name: "Rate", Formula: "conversions / sessions"})
+
+	require.ErrorIs(t, err, ErrAlreadyExists)
+}
@@ -0,0 +1,496 @@
+package ga4
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+// Channel rule expressions are a small boolean language over session fields:
+//
+//	expr       = or
+//	or         = and { "OR" and }
+//	and        = unary { "AND" unary }
+//	unary      = "NOT" unary | "(" expr ")" | comparison
+//	comparison = field ( "==" | "!=" | "CONTAINS" | "BEGINS_WITH" | "ENDS_WITH"
+//	             | "MATCHES_REGEX" | "PARTIAL_REGEX" ) string
+//	           | field [ "NOT" ] "IN" "(" string { "," string } ")"
+//
+// Keywords are case-insensitive and strings take single or double quotes,
+// with backslash escapes. The API only accepts an AND of ORs of filters or
+// negated filters, so parsed expressions are rewritten into that form.
+
+// maxChannelClauses bounds the AND-of-ORs rewrite, which grows
+// exponentially for an OR of ANDs.
+const maxChannelClauses = 64
+
+// channelMatchTypes maps string operators to API match types.
+var channelMatchTypes = map[string]string{
+	"==":            "EXACT",
+	"CONTAINS":      "CONTAINS",
+	"BEGINS_WITH":   "BEGINS_WITH",
+	"ENDS_WITH":     "ENDS_WITH",
+	"MATCHES_REGEX": "FULL_REGEXP",
+	"PARTIAL_REGEX": "PARTIAL_REGEXP",
+}
+
+type channelTokenKind int
+
+const (
+	tokEOF channelTokenKind = iota
+	tokIdent
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type channelToken struct {
+	kind channelTokenKind
+	text string // strings are unquoted and unescaped
+	pos  int
+}
+
+// tokenizeChannelExpr splits an expression into tokens.
+func tokenizeChannelExpr(s string) ([]channelToken, error) {
+	var tokens []channelToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, channelToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, channelToken{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, channelToken{tokComma, ",", i})
+			i++
+		case c == '=' || c == '!':
+			if i+1 >= len(s) || s[i+1] != '=' {
+				return nil, fmt.Errorf("at %d: expected == or !=", i)
+			}
+			tokens = append(tokens, channelToken{tokOp, s[i : i+2], i})
+			i += 2
+		case c == '\'' || c == '"':
+			start := i
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, fmt.Errorf("at %d: unterminated string", start)
+				}
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					b.WriteByte(s[i])
+					continue
+				}
+				if s[i] == c {
+					break
+				}
+				b.WriteByte(s[i])
+			}
+			tokens = append(tokens, channelToken{tokString, b.String(), start})
+			i++
+		case isIdentByte(c):
+			start := i
+			for i < len(s) && isIdentByte(s[i]) {
+				i++
+			}
+			tokens = append(tokens, channelToken{tokIdent, s[start:i], start})
+		default:
+			return nil, fmt.Errorf("at %d: unexpected %q", i, c)
+		}
+	}
+	return append(tokens, channelToken{tokEOF, "", len(s)}), nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// channelNode is a parsed expression: a filter, or NOT/AND/OR over nodes.
+type channelNode struct {
+	op       string // "", "NOT", "AND" or "OR"
+	children []*channelNode
+	filter   *channelFilter
+}
+
+type channelFilter struct {
+	field     string
+	matchType string   // string filters
+	values    []string // IN filters when len > 0
+	value     string
+}
+
+type channelParser struct {
+	tokens []channelToken
+	pos    int
+}
+
+func (p *channelParser) peek() channelToken { return p.tokens[p.pos] }
+
+func (p *channelParser) next() channelToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword kw and consumes it.
+func (p *channelParser) keyword(kw string) bool {
+	t := p.peek()
+	if t.kind == tokIdent && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *channelParser) parseOr() (*channelNode, error) {
+	return p.parseList("OR", p.parseAnd)
+}
+
+func (p *channelParser) parseAnd() (*channelNode, error) {
+	return p.parseList("AND", p.parseUnary)
+}
+
+func (p *channelParser) parseList(op string, operand func() (*channelNode, error)) (*channelNode, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	n := &channelNode{op: op, children: []*channelNode{first}}
+	for p.keyword(op) {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		n.children = append(n.children, next)
+	}
+	if len(n.children) == 1 {
+		return first, nil
+	}
+	return n, nil
+}
+
+func (p *channelParser) parseUnary() (*channelNode, error) {
+	if p.keyword("NOT") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &channelNode{op: "NOT", children: []*channelNode{x}}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("at %d: expected ) but found %s", t.pos, describeToken(t))
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *channelParser) parseComparison() (*channelNode, error) {
+	field := p.next()
+	if field.kind != tokIdent || isChannelKeyword(field.text) {
+		return nil, fmt.Errorf("at %d: expected a field name but found %s", field.pos, describeToken(field))
+	}
+	f := &channelFilter{field: field.text}
+
+	negate := false
+	op := p.next()
+	if op.kind == tokIdent && strings.EqualFold(op.text, "NOT") {
+		negate = true
+		op = p.next()
+		if op.kind != tokIdent || !strings.EqualFold(op.text, "IN") {
+			return nil, fmt.Errorf("at %d: expected IN after NOT", op.pos)
+		}
+	}
+	if op.kind == tokIdent && strings.EqualFold(op.text, "IN") {
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		f.values = values
+	} else {
+		var matchType string
+		ok := false
+		switch {
+		case op.kind == tokOp && op.text == "!=":
+			matchType, ok, negate = "EXACT", true, true
+		case op.kind == tokOp || op.kind == tokIdent:
+			matchType, ok = channelMatchTypes[strings.ToUpper(op.text)]
+		}
+		if !ok {
+			return nil, fmt.Errorf("at %d: expected an operator after %s but found %s", op.pos, field.text, describeToken(op))
+		}
+		value := p.next()
+		if value.kind != tokString {
+			return nil, fmt.Errorf("at %d: expected a quoted value after %s but found %s", value.pos, op.text, describeToken(value))
+		}
+		f.matchType, f.value = matchType, value.text
+	}
+
+	n := &channelNode{filter: f}
+	if negate {
+		n = &channelNode{op: "NOT", children: []*channelNode{n}}
+	}
+	return n, nil
+}
+
+// parseValues parses the parenthesised value list of IN.
+func (p *channelParser) parseValues() ([]string, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, fmt.Errorf("at %d: expected ( after IN but found %s", t.pos, describeToken(t))
+	}
+	var values []string
+	for {
+		v := p.next()
+		if v.kind != tokString {
+			return nil, fmt.Errorf("at %d: expected a quoted value but found %s", v.pos, describeToken(v))
+		}
+		values = append(values, v.text)
+		switch t := p.next(); t.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, fmt.Errorf("at %d: expected , or ) but found %s", t.pos, describeToken(t))
+		}
+	}
+}
+
+func isChannelKeyword(s string) bool {
+	switch strings.ToUpper(s) {
+	case "AND", "OR", "NOT", "IN":
+		return true
+	}
+	_, ok := channelMatchTypes[strings.ToUpper(s)]
+	return ok
+}
+
+func describeToken(t channelToken) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// channelLiteral is a filter or a negated filter, a leaf of the AND-of-ORs
+// form.
+type channelLiteral struct {
+	filter  *channelFilter
+	negated bool
+}
+
+// channelClauses rewrites n as an AND of OR clauses, pushing NOT down to the
+// filters with De Morgan's laws.
+func channelClauses(n *channelNode, negated bool) ([][]channelLiteral, error) {
+	switch n.op {
+	case "":
+		return [][]channelLiteral{{{filter: n.filter, negated: negated}}}, nil
+	case "NOT":
+		return channelClauses(n.children[0], !negated)
+	}
+	// Under negation AND becomes OR and the reverse.
+	conjunction := (n.op == "AND") != negated
+	var out [][]channelLiteral
+	if conjunction {
+		for _, c := range n.children {
+			clauses, err := channelClauses(c, negated)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, clauses...)
+		}
+	} else {
+		// Distribute: one clause per choice of a clause from each child.
+		out = [][]channelLiteral{{}}
+		for _, c := range n.children {
+			clauses, err := channelClauses(c, negated)
+			if err != nil {
+				return nil, err
+			}
+			var product [][]channelLiteral
+			for _, prefix := range out {
+				for _, clause := range clauses {
+					merged := append(append([]channelLiteral{}, prefix...), clause...)
+					product = append(product, merged)
+				}
+			}
+			out = product
+			if len(out) > maxChannelClauses {
+				break
+			}
+		}
+	}
+	if len(out) > maxChannelClauses {
+		return nil, fmt.Errorf("expression expands to more than %d AND clauses; simplify the ORs of ANDs", maxChannelClauses)
+	}
+	return out, nil
+}
+
+// toAPI builds the API's filter for one filter.
+func (f *channelFilter) toAPI() *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression {
+	filter := &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilter{FieldName: f.field}
+	if len(f.values) > 0 {
+		filter.InListFilter = &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterInListFilter{Values: f.values}
+	} else {
+		filter.StringFilter = &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterStringFilter{
+			MatchType: f.matchType,
+			Value:     f.value,
+		}
+	}
+	return &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{Filter: filter}
+}
+
+// parseChannelExpression parses expression into the API's shape: an
+// and_group of or_groups, each holding filters or not_expressions of a
+// filter.
+func parseChannelExpression(expression string) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, error) {
+	tokens, err := tokenizeChannelExpr(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &channelParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, fmt.Errorf("empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("at %d: expected AND, OR or end of expression but found %s", t.pos, describeToken(t))
+	}
+
+	clauses, err := channelClauses(root, false)
+	if err != nil {
+		return nil, err
+	}
+	and := make([]*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, 0, len(clauses))
+	for _, clause := range clauses {
+		or := make([]*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, 0, len(clause))
+		for _, lit := range clause {
+			expr := lit.filter.toAPI()
+			if lit.negated {
+				expr = &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{NotExpression: expr}
+			}
+			or = append(or, expr)
+		}
+		and = append(and, &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
+			OrGroup: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{FilterExpressions: or},
+		})
+	}
+	return &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
+		AndGroup: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{FilterExpressions: and},
+	}, nil
+}
+
+// FormatChannelExpression writes an API filter expression in the rule
+// expression language, so a channel group read from a property can go into a
+// config. Parsing the result gives back an equivalent filter.
+func FormatChannelExpression(expr *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression) (string, error) {
+	return formatChannelExpr(expr, "")
+}
+
+// formatChannelExpr formats expr as an operand of parent ("AND", "OR" or ""
+// at the top), adding parentheses where precedence needs them.
+func formatChannelExpr(expr *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, parent string) (string, error) {
+	switch {
+	case expr == nil:
+		return "", fmt.Errorf("empty filter expression")
+	case expr.Filter != nil:
+		return formatChannelFilter(expr.Filter, false)
+	case expr.NotExpression != nil:
+		if f := expr.NotExpression.Filter; f != nil {
+			return formatChannelFilter(f, true)
+		}
+		s, err := formatChannelExpr(expr.NotExpression, "NOT")
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + s, nil
+	}
+
+	op, list := "AND", expr.AndGroup
+	if expr.OrGroup != nil {
+		op, list = "OR", expr.OrGroup
+	}
+	if list == nil || len(list.FilterExpressions) == 0 {
+		return "", fmt.Errorf("empty filter expression")
+	}
+	if len(list.FilterExpressions) == 1 {
+		return formatChannelExpr(list.FilterExpressions[0], parent)
+	}
+	parts := make([]string, len(list.FilterExpressions))
+	for i, e := range list.FilterExpressions {
+		s, err := formatChannelExpr(e, op)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = s
+	}
+	s := strings.Join(parts, " "+op+" ")
+	// AND binds tighter than OR, and NOT applies to a single operand.
+	if parent == "NOT" || parent == "AND" && op == "OR" {
+		s = "(" + s + ")"
+	}
+	return s, nil
+}
+
+// formatChannelFilter formats one filter, negated with != or NOT IN where
+// the language has them.
+func formatChannelFilter(f *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilter, negated bool) (string, error) {
+	var s string
+	switch {
+	case f.InListFilter != nil:
+		values := make([]string, len(f.InListFilter.Values))
+		for i, v := range f.InListFilter.Values {
+			values[i] = quoteChannelValue(v)
+		}
+		op := "IN"
+		if negated {
+			op, negated = "NOT IN", false
+		}
+		s = fmt.Sprintf("%s %s (%s)", f.FieldName, op, strings.Join(values, ", "))
+	case f.StringFilter != nil:
+		op := ""
+		for o, matchType := range channelMatchTypes {
+			if matchType == f.StringFilter.MatchType {
+				op = o
+			}
+		}
+		if op == "" {
+			return "", fmt.Errorf("unsupported match type %q on %s", f.StringFilter.MatchType, f.FieldName)
+		}
+		if op == "==" && negated {
+			op, negated = "!=", false
+		}
+		s = fmt.Sprintf("%s %s %s", f.FieldName, op, quoteChannelValue(f.StringFilter.Value))
+	default:
+		return "", fmt.Errorf("filter on %s has no string or in-list condition", f.FieldName)
+	}
+	if negated {
+		s = "NOT (" + s + ")"
+	}
+	return s, nil
+}
+
+// quoteChannelValue single-quotes v with backslash escapes.
+func quoteChannelValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
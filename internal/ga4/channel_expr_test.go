@@ -0,0 +1,145 @@
+package ga4
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeChannelExpr(t *testing.T) {
+	tokens, err := tokenizeChannelExpr(`sessionSource IN ('a', "b\"c") AND x != 'it\'s'`)
+	require.NoError(t, err)
+	var texts []string
+	for _, tok := range tokens {
+		texts = append(texts, tok.text)
+	}
+	assert.Equal(t, []string{"sessionSource", "IN", "(", "a", ",", `b"c`, ")", "AND", "x", "!=", "it's", ""}, texts)
+
+	_, err = tokenizeChannelExpr("x == 'open")
+	assert.ErrorContains(t, err, "at 5: unterminated string")
+	_, err = tokenizeChannelExpr("x = 'a'")
+	assert.ErrorContains(t, err, "at 2: expected == or !=")
+}
+
+// compactExpr renders a parsed expression as its API JSON.
+func compactExpr(t *testing.T, expression string) string {
+	t.Helper()
+	expr, err := parseChannelExpression(expression)
+	require.NoError(t, err)
+	data, err := json.Marshal(expr)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestParseChannelExpression_Operators(t *testing.T) {
+	for op, matchType := range map[string]string{
+		"==":            "EXACT",
+		"contains":      "CONTAINS",
+		"BEGINS_WITH":   "BEGINS_WITH",
+		"ENDS_WITH":     "ENDS_WITH",
+		"MATCHES_REGEX": "FULL_REGEXP",
+		"PARTIAL_REGEX": "PARTIAL_REGEXP",
+	} {
+		assert.JSONEq(t,
+			`{"andGroup":{"filterExpressions":[{"orGroup":{"filterExpressions":[
+				{"filter":{"fieldName":"sessionSource","stringFilter":{"matchType":"`+matchType+`","value":"google"}}}]}}]}}`,
+			compactExpr(t, "sessionSource "+op+" 'google'"), op)
+	}
+}
+
+func TestParseChannelExpression_Boolean(t *testing.T) {
+	source := func(v string) string {
+		return `{"filter":{"fieldName":"sessionSource","stringFilter":{"matchType":"EXACT","value":"` + v + `"}}}`
+	}
+	medium := `{"filter":{"fieldName":"sessionMedium","inListFilter":{"values":["cpc","ppc"]}}}`
+	or := func(exprs ...string) string {
+		out := `{"orGroup":{"filterExpressions":[`
+		for i, e := range exprs {
+			if i > 0 {
+				out += ","
+			}
+			out += e
+		}
+		return out + `]}}`
+	}
+	and := func(clauses ...string) string {
+		out := `{"andGroup":{"filterExpressions":[`
+		for i, c := range clauses {
+			if i > 0 {
+				out += ","
+			}
+			out += c
+		}
+		return out + `]}}`
+	}
+	not := func(e string) string { return `{"notExpression":` + e + `}` }
+
+	// The AND-joined form the parser has always accepted keeps its shape.
+	assert.JSONEq(t, and(or(source("google")), or(medium)),
+		compactExpr(t, "sessionSource == 'google' AND sessionMedium IN ('cpc', 'ppc')"))
+
+	assert.JSONEq(t, and(or(source("google"), source("bing"))),
+		compactExpr(t, "sessionSource == 'google' OR sessionSource == 'bing'"))
+
+	// AND binds tighter than OR; the OR of ANDs is distributed.
+	assert.JSONEq(t, and(or(source("google"), source("bing")), or(medium, source("bing"))),
+		compactExpr(t, "sessionSource == 'google' AND sessionMedium IN ('cpc', 'ppc') OR sessionSource == 'bing'"))
+
+	// NOT is pushed down to the filters.
+	assert.JSONEq(t, and(or(not(source("google"))), or(not(medium))),
+		compactExpr(t, "NOT (sessionSource == 'google' OR sessionMedium IN ('cpc', 'ppc'))"))
+	assert.JSONEq(t, and(or(not(source("google"))), or(not(medium))),
+		compactExpr(t, "sessionSource != 'google' AND sessionMedium NOT IN ('cpc', 'ppc')"))
+	assert.JSONEq(t, and(or(source("google"))), compactExpr(t, "NOT NOT sessionSource == 'google'"))
+}
+
+func TestParseChannelExpression_Errors(t *testing.T) {
+	for expression, want := range map[string]string{
+		"":                                   "empty expression",
+		"sessionSource":                      "expected an operator after sessionSource but found end of expression",
+		"sessionSource == google":            `expected a quoted value after == but found "google"`,
+		"(sessionSource == 'a'":              "expected ) but found end of expression",
+		"sessionSource == 'a' 'b'":           "expected AND, OR or end of expression but found 'b'",
+		"AND == 'a'":                         `expected a field name but found "AND"`,
+		"sessionSource IN ('a' 'b')":         "expected , or ) but found 'b'",
+		"sessionSource NOT == 'a'":           "expected IN after NOT",
+		"sessionSource IN ()":                "expected a quoted value but found \")\"",
+		"sessionMedium MATCHES_REGEX 'a' OR": "expected a field name but found end of expression",
+	} {
+		_, err := parseChannelExpression(expression)
+		assert.ErrorContains(t, err, want, expression)
+	}
+
+	// Each OR of two-filter ANDs doubles the clauses.
+	expression := "a == '1' AND b == '1'"
+	for i := 0; i < 6; i++ {
+		expression += " OR a == '1' AND b == '1'"
+	}
+	_, err := parseChannelExpression(expression)
+	assert.ErrorContains(t, err, "more than 64 AND clauses")
+}
+
+func TestFormatChannelExpression(t *testing.T) {
+	for _, expression := range []string{
+		`sessionDefaultChannelGroup == 'Organic Search'`,
+		`sessionSource IN ('chatgpt.com', 'it\'s') AND sessionMedium != 'cpc'`,
+		`(sessionSource CONTAINS 'ai' OR sessionSource ENDS_WITH '.ai') AND NOT sessionMedium IN ('email')`,
+		`sessionSource MATCHES_REGEX '^a.*' OR sessionMedium PARTIAL_REGEX 'b' OR sessionCampaignName BEGINS_WITH 'c'`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			expr, err := parseChannelExpression(expression)
+			require.NoError(t, err)
+			formatted, err := FormatChannelExpression(expr)
+			require.NoError(t, err)
+			assert.Equal(t, compactExpr(t, expression), compactExpr(t, formatted), "formatted as %s", formatted)
+		})
+	}
+
+	expr, err := parseChannelExpression(`a == '1' AND (b == '2' OR c NOT IN ('3'))`)
+	require.NoError(t, err)
+	formatted, err := FormatChannelExpression(expr)
+	require.NoError(t, err)
+	assert.Equal(t, `a == '1' AND (b == '2' OR c NOT IN ('3'))`, formatted)
+}
@@ -1,11 +1,13 @@
 package ga4
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"regexp"
-	"strings"
 
 	"google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
 )
 
 // ChannelRule represents a rule for channel grouping
@@ -146,108 +148,112 @@ func DefaultChannelGroups() []ChannelGroup {
 	}
 }
 
-// parseChannelGroupFilter parses a simple filter expression string into a structured FilterExpression
-// GA4 API requires: and_group at top level, containing or_group elements, each containing filters
-func parseChannelGroupFilter(expression string) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, error) {
-	parts := strings.Split(expression, " AND ")
-	var orGroupExpressions []*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression
-
-	for _, part := range parts {
-		var filterExpr *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression
-
-		if strings.Contains(part, " IN ") {
-			rIn := regexp.MustCompile(`(\w+)\s+IN\s+\(([^)]+)\)`)
-			inMatches := rIn.FindStringSubmatch(part)
-			if len(inMatches) < 3 {
-				return nil, fmt.Errorf("invalid IN expression part: %s", part)
-			}
-			fieldName := inMatches[1]
-			valuesStr := inMatches[2]
-			var values []string
-			for _, v := range strings.Split(valuesStr, ",") {
-				values = append(values, strings.Trim(strings.TrimSpace(v), "'"))
-			}
-			filterExpr = &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
-				Filter: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilter{
-					FieldName: fieldName,
-					InListFilter: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterInListFilter{
-						Values: values,
-					},
-				},
-			}
-		} else if strings.Contains(part, " == ") {
-			rEq := regexp.MustCompile(`(\w+)\s*==\s*'([^']*)'`)
-			eqMatches := rEq.FindStringSubmatch(part)
-			if len(eqMatches) < 3 {
-				rEqParen := regexp.MustCompile(`(\w+)\s*==\s*\(([^)]*)\)`)
-				eqMatchesParen := rEqParen.FindStringSubmatch(part)
-				if len(eqMatchesParen) < 3 {
-					return nil, fmt.Errorf("invalid == expression part: %s", part)
-				}
-				eqMatches = eqMatchesParen
-			}
-
-			fieldName := eqMatches[1]
-			value := eqMatches[2]
-			filterExpr = &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
-				Filter: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilter{
-					FieldName: fieldName,
-					StringFilter: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterStringFilter{
-						MatchType: "EXACT",
-						Value:     value,
-					},
-				},
-			}
-		} else {
-			return nil, fmt.Errorf("unsupported expression part: %s", part)
+// ChannelGroupsFromConfig converts a config's channel_groups.
+func ChannelGroupsFromConfig(groups []config.ChannelGroupConfig) []ChannelGroup {
+	out := make([]ChannelGroup, 0, len(groups))
+	for _, g := range groups {
+		group := ChannelGroup{DisplayName: g.DisplayName, Description: g.Description}
+		for _, r := range g.Rules {
+			group.Rules = append(group.Rules, ChannelRule{DisplayName: r.DisplayName, Expression: r.Expression})
 		}
-
-		// Wrap each filter in an or_group (even though it's a single filter)
-		// This satisfies GA4's requirement: and_group must only contain or_group
-		orGroupExpressions = append(orGroupExpressions, &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
-			OrGroup: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{
-				FilterExpressions: []*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
-					filterExpr,
-				},
-			},
-		})
+		out = append(out, group)
 	}
+	return out
+}
 
-	if len(orGroupExpressions) == 0 {
-		return nil, fmt.Errorf("no expressions parsed from: %s", expression)
+// ChannelGroupsFor returns the config's channel groups, or the defaults when
+// it declares none.
+func ChannelGroupsFor(cfg *config.ProjectConfig) []ChannelGroup {
+	if len(cfg.ChannelGroups) > 0 {
+		return ChannelGroupsFromConfig(cfg.ChannelGroups)
 	}
+	return DefaultChannelGroups()
+}
 
-	// Always wrap in and_group at top level (even for single expressions)
-	return &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
-		AndGroup: &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{
-			FilterExpressions: orGroupExpressions,
-		},
-	}, nil
+// ValidateChannelExpression reports whether a rule expression can be sent to
+// the API, without calling it.
+func ValidateChannelExpression(expression string) error {
+	_, err := parseChannelGroupFilter(expression)
+	return err
 }
 
-// CreateChannelGroup creates a custom channel group for the property
-func (c *Client) CreateChannelGroup(propertyID string, group ChannelGroup) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
-	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+// parseChannelGroupFilter parses a rule expression into the FilterExpression
+// the API takes. The grammar is documented in channel_expr.go.
+func parseChannelGroupFilter(expression string) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression, error) {
+	expr, err := parseChannelExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expression, err)
+	}
+	return expr, nil
+}
 
-	var groupingRules []*analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule
+// groupingRules parses the rule expressions of group.
+func groupingRules(group ChannelGroup) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule, error) {
+	var rules []*analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule
 	for _, rule := range group.Rules {
 		expression, err := parseChannelGroupFilter(rule.Expression)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse rule '%s': %w", rule.DisplayName, err)
 		}
-		groupingRules = append(groupingRules, &analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule{
+		rules = append(rules, &analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule{
 			DisplayName: rule.DisplayName,
 			Expression:  expression,
 		})
 	}
+	return rules, nil
+}
+
+// ChannelGroupUpToDate reports whether existing already has group's
+// description and rules, so setup can leave it alone.
+func ChannelGroupUpToDate(existing *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, group ChannelGroup) (bool, error) {
+	want, err := groupingRules(group)
+	if err != nil {
+		return false, err
+	}
+	if existing.Description != group.Description || len(existing.GroupingRule) != len(want) {
+		return false, nil
+	}
+	for i, rule := range want {
+		got := existing.GroupingRule[i]
+		if got.DisplayName != rule.DisplayName {
+			return false, nil
+		}
+		a, err := json.Marshal(got.Expression)
+		if err != nil {
+			return false, err
+		}
+		b, err := json.Marshal(rule.Expression)
+		if err != nil {
+			return false, err
+		}
+		if string(a) != string(b) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CreateChannelGroup creates a custom channel group for the property
+func (c *Client) CreateChannelGroup(propertyID string, group ChannelGroup) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	if err := c.requireFeature(propertyID, config.FeatureChannelGroups); err != nil {
+		return nil, err
+	}
+	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+
+	rules, err := groupingRules(group)
+	if err != nil {
+		return nil, err
+	}
 
 	channelGroup := &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup{
 		DisplayName:  group.DisplayName,
 		Description:  group.Description,
-		GroupingRule: groupingRules,
+		GroupingRule: rules,
 	}
 
-	createdGroup, err := c.admin.createChannelGroup(c.ctx, propertyPath, channelGroup)
+	createdGroup, err := changeResult(c, verbCreate, "channel group", group.DisplayName, propertyID, channelGroup, func(ctx context.Context) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+		return c.admin.createChannelGroup(ctx, propertyPath, channelGroup)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create channel group '%s': %w", group.DisplayName, err)
 	}
@@ -257,9 +263,14 @@ func (c *Client) CreateChannelGroup(propertyID string, group ChannelGroup) (*ana
 
 // ListChannelGroups lists all channel groups for a property
 func (c *Client) ListChannelGroups(propertyID string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	if err := c.requireFeature(propertyID, config.FeatureChannelGroups); err != nil {
+		return nil, err
+	}
 	propertyPath := fmt.Sprintf("properties/%s", propertyID)
 
-	groups, err := c.admin.listChannelGroups(c.ctx, propertyPath)
+	groups, err := callResult(c, verbList, "channel groups", propertyID, func(ctx context.Context) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+		return c.admin.listChannelGroups(ctx, propertyPath)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list channel groups: %w", err)
 	}
@@ -269,37 +280,60 @@ func (c *Client) ListChannelGroups(propertyID string) ([]*analyticsadmin.GoogleA
 
 // UpdateChannelGroup updates an existing channel group
 func (c *Client) UpdateChannelGroup(channelGroupName string, group ChannelGroup) error {
-	var groupingRules []*analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule
-	for _, rule := range group.Rules {
-		expression, err := parseChannelGroupFilter(rule.Expression)
-		if err != nil {
-			return fmt.Errorf("failed to parse rule '%s': %w", rule.DisplayName, err)
-		}
-		groupingRules = append(groupingRules, &analyticsadmin.GoogleAnalyticsAdminV1alphaGroupingRule{
-			DisplayName: rule.DisplayName,
-			Expression:  expression,
-		})
+	if err := c.requireFeature(channelGroupName, config.FeatureChannelGroups); err != nil {
+		return err
+	}
+	rules, err := groupingRules(group)
+	if err != nil {
+		return err
 	}
 
 	channelGroup := &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup{
 		Name:         channelGroupName,
 		DisplayName:  group.DisplayName,
 		Description:  group.Description,
-		GroupingRule: groupingRules,
+		GroupingRule: rules,
 	}
 
 	updateMask := "display_name,description,grouping_rule"
 
-	if err := c.admin.patchChannelGroup(c.ctx, channelGroupName, channelGroup, updateMask); err != nil {
+	if err := c.change(verbUpdate, "channel group", channelGroupName, "", channelGroup, func(ctx context.Context) error {
+		return c.admin.patchChannelGroup(ctx, channelGroupName, channelGroup, updateMask)
+	}); err != nil {
 		return fmt.Errorf("failed to update channel group: %w", err)
 	}
 
 	return nil
 }
 
+// RestoreChannelGroup patches a channel group back to a version read
+// earlier, undoing an UpdateChannelGroup.
+func (c *Client) RestoreChannelGroup(previous *analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup) error {
+	if err := c.requireFeature(previous.Name, config.FeatureChannelGroups); err != nil {
+		return err
+	}
+	channelGroup := &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup{
+		Name:         previous.Name,
+		DisplayName:  previous.DisplayName,
+		Description:  previous.Description,
+		GroupingRule: previous.GroupingRule,
+	}
+	if err := c.change(verbUpdate, "channel group", previous.Name, "", channelGroup, func(ctx context.Context) error {
+		return c.admin.patchChannelGroup(ctx, previous.Name, channelGroup, "display_name,description,grouping_rule")
+	}); err != nil {
+		return fmt.Errorf("failed to restore channel group: %w", err)
+	}
+	return nil
+}
+
 // DeleteChannelGroup deletes a channel group
 func (c *Client) DeleteChannelGroup(channelGroupName string) error {
-	if err := c.admin.deleteChannelGroup(c.ctx, channelGroupName); err != nil {
+	if err := c.requireFeature(channelGroupName, config.FeatureChannelGroups); err != nil {
+		return err
+	}
+	if err := c.change(verbDelete, "channel group", channelGroupName, "", nil, func(ctx context.Context) error {
+		return c.admin.deleteChannelGroup(ctx, channelGroupName)
+	}); err != nil {
 		return fmt.Errorf("failed to delete channel group: %w", err)
 	}
 
@@ -308,7 +342,12 @@ func (c *Client) DeleteChannelGroup(channelGroupName string) error {
 
 // GetChannelGroup retrieves a specific channel group
 func (c *Client) GetChannelGroup(channelGroupName string) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
-	group, err := c.admin.getChannelGroup(c.ctx, channelGroupName)
+	if err := c.requireFeature(channelGroupName, config.FeatureChannelGroups); err != nil {
+		return nil, err
+	}
+	group, err := callResult(c, verbGet, "channel group", channelGroupName, func(ctx context.Context) (*analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+		return c.admin.getChannelGroup(ctx, channelGroupName)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get channel group: %w", err)
 	}
@@ -318,8 +357,16 @@ func (c *Client) GetChannelGroup(channelGroupName string) (*analyticsadmin.Googl
 
 // SetupDefaultChannelGroups creates all default channel groups for a property
 func (c *Client) SetupDefaultChannelGroups(propertyID string) error {
-	defaultGroups := DefaultChannelGroups()
-	fmt.Printf("Setting up %d default channel groups for property %s...\n", len(defaultGroups), propertyID)
+	return c.SetupChannelGroups(propertyID, DefaultChannelGroups())
+}
+
+// SetupChannelGroups creates the groups a property does not have yet, by
+// display name; existing ones are left as they are.
+func (c *Client) SetupChannelGroups(propertyID string, groups []ChannelGroup) error {
+	if err := c.requireFeature(propertyID, config.FeatureChannelGroups); err != nil {
+		return err
+	}
+	fmt.Printf("Setting up %d channel groups for property %s...\n", len(groups), propertyID)
 
 	existingGroups, err := c.ListChannelGroups(propertyID)
 	if err != nil {
@@ -330,7 +377,7 @@ func (c *Client) SetupDefaultChannelGroups(propertyID string) error {
 		existingGroupNames[g.DisplayName] = true
 	}
 
-	for _, group := range defaultGroups {
+	for _, group := range groups {
 		if _, exists := existingGroupNames[group.DisplayName]; exists {
 			fmt.Printf("Channel group '%s' already exists, skipping.\n", group.DisplayName)
 			continue
@@ -344,7 +391,7 @@ func (c *Client) SetupDefaultChannelGroups(propertyID string) error {
 			// We continue to try to create the other groups.
 		}
 	}
-	fmt.Println("Finished setting up default channel groups.")
+	fmt.Println("Finished setting up channel groups.")
 	return nil
 }
 
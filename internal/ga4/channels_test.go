@@ -0,0 +1,64 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestValidateChannelExpression(t *testing.T) {
+	assert.NoError(t, ValidateChannelExpression("eachScopeDefaultChannelGroup == 'Email'"))
+	assert.NoError(t, ValidateChannelExpression("eachScopeSource IN ('chatgpt.com', 'perplexity.ai') AND eachScopeMedium == 'referral'"))
+
+	assert.ErrorContains(t, ValidateChannelExpression("eachScopeSource LIKE 'google'"), "expected an operator after eachScopeSource")
+	assert.ErrorContains(t, ValidateChannelExpression("eachScopeSource IN 'google'"), "expected ( after IN")
+}
+
+func TestChannelGroupsFor(t *testing.T) {
+	assert.Equal(t, DefaultChannelGroups(), ChannelGroupsFor(&config.ProjectConfig{}))
+
+	cfg := &config.ProjectConfig{ChannelGroups: []config.ChannelGroupConfig{{
+		DisplayName: "Marketing Channels",
+		Description: "Paid vs owned",
+		Rules:       []config.ChannelRuleConfig{{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Email'"}},
+	}}}
+	groups := ChannelGroupsFor(cfg)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Marketing Channels", groups[0].DisplayName)
+	assert.Equal(t, []ChannelRule{{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Email'"}}, groups[0].Rules)
+}
+
+func TestChannelGroupUpToDate(t *testing.T) {
+	group := ChannelGroup{
+		DisplayName: "Marketing Channels",
+		Description: "Paid vs owned",
+		Rules:       []ChannelRule{{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Email'"}},
+	}
+	rules, err := groupingRules(group)
+	require.NoError(t, err)
+	existing := &analyticsadmin.GoogleAnalyticsAdminV1alphaChannelGroup{
+		DisplayName:  group.DisplayName,
+		Description:  group.Description,
+		GroupingRule: rules,
+	}
+
+	upToDate, err := ChannelGroupUpToDate(existing, group)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	changed := group
+	changed.Rules = []ChannelRule{{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Paid Search'"}}
+	upToDate, err = ChannelGroupUpToDate(existing, changed)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+
+	changed = group
+	changed.Description = "Owned only"
+	upToDate, err = ChannelGroupUpToDate(existing, changed)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+}
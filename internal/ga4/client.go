@@ -4,24 +4,41 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
 	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/analyticsdata/v1beta"
 	"google.golang.org/api/option"
 
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+	"github.com/garbarok/ga4-manager/internal/auth"
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/logging"
+	"github.com/garbarok/ga4-manager/internal/metrics"
+	"github.com/garbarok/ga4-manager/internal/retry"
+	"github.com/garbarok/ga4-manager/internal/timeout"
 	"github.com/garbarok/ga4-manager/internal/validation"
 )
 
 type Client struct {
 	admin       adminAPI
+	data        dataAPI
 	ctx         context.Context
 	cancel      context.CancelFunc
 	rateLimiter *rate.Limiter
 	logger      *slog.Logger
 	config      *config.ClientConfig
+	features    config.FeatureSet
+	cache       *listCache // nil when caching is off
+	pageSize    int64      // items per Admin API list page
+	httpClient  *http.Client
+
+	// propertyFeatures overrides features per property ID.
+	propertyFeatures map[string]config.FeatureSet
 }
 
 // ClientOption is a functional option for configuring the Client
@@ -34,27 +51,42 @@ func WithConfig(cfg *config.ClientConfig) ClientOption {
 	}
 }
 
-// WithLogger sets a custom logger
+// WithLogger sets a custom logger, used instead of the one described by the
+// configuration's Logging section
 func WithLogger(logger *slog.Logger) ClientOption {
 	return func(c *Client) {
 		c.logger = logger
 	}
 }
 
+// WithPageSize sets how many items each Admin API list page holds, 1 to
+// DefaultPageSize. Lists always fetch every page; smaller pages only trade
+// more requests for smaller responses.
+func WithPageSize(n int64) ClientOption {
+	return func(c *Client) {
+		c.pageSize = min(max(n, 1), DefaultPageSize)
+	}
+}
+
+// WithHTTPClient sends the Admin API requests through hc instead of a client
+// authenticated with the resolved credentials; tests pass a cassette
+// replaying recorded responses.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
 // NewClient creates a new GA4 API client with rate limiting and logging
 func NewClient(opts ...ClientOption) (*Client, error) {
 	// Default configuration
 	cfg := config.DefaultClientConfig()
 
-	// Default logger
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-
 	// Create client with defaults
 	client := &Client{
-		config: cfg,
-		logger: logger,
+		config:   cfg,
+		cache:    newListCache(0, ""),
+		pageSize: DefaultPageSize,
 	}
 
 	// Apply options
@@ -62,36 +94,58 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		opt(client)
 	}
 
-	// Update logger based on config
-	client.logger = createLogger(client.config.Logging)
+	// Create the logger from the config unless WithLogger supplied one
+	if client.logger == nil {
+		client.logger = createLogger(client.config.Logging)
+	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), client.config.Timeouts.ContextTimeout)
+	// Create context with timeout. A zero ContextTimeout means no client-wide
+	// deadline (see config.ServerClientConfig); requests are still bounded by
+	// RequestTimeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	if client.config.Timeouts.ContextTimeout > 0 {
+		cancel()
+		ctx, cancel = context.WithTimeout(context.Background(), client.config.Timeouts.ContextTimeout)
+	}
 	client.ctx = ctx
 	client.cancel = cancel
 
-	// Get credentials from environment
-	credsFile := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
-	if credsFile == "" {
-		cancel()
-		return nil, fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS not set")
+	// A service account key from the environment, else a cached user login
+	serviceOpt := option.WithHTTPClient(client.httpClient)
+	credentials := "custom HTTP client"
+	if client.httpClient == nil {
+		creds, err := auth.Resolve()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		serviceOpt = creds.ClientOption()
+		credentials = creds.String()
 	}
 
 	client.logger.Debug("initializing GA4 client",
-		slog.String("credentials_file", credsFile),
+		slog.String("credentials", credentials),
 		slog.Float64("rate_limit", client.config.RateLimiting.RequestsPerSecond),
 		slog.Int("burst", client.config.RateLimiting.Burst),
 	)
 
 	// Create admin service with timeout context
-	adminService, err := admin.NewService(ctx, option.WithAuthCredentialsFile(option.ServiceAccount, credsFile))
+	adminService, err := admin.NewService(ctx, serviceOpt)
 	if err != nil {
 		cancel()
 		client.logger.Error("failed to create admin service", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to create admin service: %w", err)
 	}
 
-	client.admin = &realAdminAPI{svc: adminService}
+	client.admin = &realAdminAPI{svc: adminService, pageSize: client.pageSize}
+
+	dataService, err := analyticsdata.NewService(ctx, serviceOpt)
+	if err != nil {
+		cancel()
+		client.logger.Error("failed to create data service", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to create data service: %w", err)
+	}
+	client.data = &realDataAPI{svc: dataService}
 
 	// Initialize rate limiter
 	client.rateLimiter = rate.NewLimiter(
@@ -180,6 +234,109 @@ func (c *Client) waitForRateLimit(ctx context.Context, operation string) error {
 	return nil
 }
 
+// API verbs used to look up per-operation timeouts (config.TimeoutConfig.For).
+const (
+	verbCreate = "create"
+	verbList   = "list"
+	verbGet    = "get"
+	verbUpdate = "update"
+	verbDelete = "delete"
+)
+
+// call runs a single Admin API request under the timeout configured for verb,
+// inside the client-wide run deadline. If either expires, the returned
+// *timeout.Error names the operation ("create conversion") and resource.
+// Transient failures are retried per c.config.Retry, each attempt with a
+// fresh timeout and logged at debug level (logging.APICall). Every call is
+// counted in metrics.Default, and every call that may change the property
+// empties the list cache.
+func (c *Client) call(verb, kind, resource string, fn func(ctx context.Context) error) error {
+	err := c.callService(metrics.ServiceGA4Admin, verb, kind, resource, fn)
+	if c.cache != nil && verb != verbList && verb != verbGet {
+		c.cache.invalidate(c.logger)
+	}
+	return err
+}
+
+// callService runs a request to one of the GA4 services with the timeout,
+// retries, logging and metrics call describes.
+func (c *Client) callService(service, verb, kind, resource string, fn func(ctx context.Context) error) error {
+	operation := verb + " " + kind
+	err := retry.Do(c.ctx, retry.Policy(c.config.Retry), c.logger, operation, resource, func() error {
+		start := time.Now()
+		err := timeout.Do(c.ctx, operation, resource, c.config.Timeouts.For(verb), fn)
+		logging.APICall(c.logger, service, operation, resource, time.Since(start), err)
+		return err
+	})
+	metrics.Default.ObserveAPICall(service, operation, err)
+	return err
+}
+
+// callResult is call for requests that return a value.
+func callResult[T any](c *Client, verb, kind, resource string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := c.call(verb, kind, resource, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+		return err
+	})
+	return out, err
+}
+
+// change is call for requests that create, update or delete: the outcome is
+// also recorded in the audit log (internal/auditlog) with a summary of
+// request. propertyID may be empty when resource is a full resource name
+// ("properties/123/customMetrics/456"); it is read from there. A failure to
+// write the audit log is logged, never returned: the change has happened.
+func (c *Client) change(verb, kind, resource, propertyID string, request any, fn func(ctx context.Context) error) error {
+	err := c.call(verb, kind, resource, fn)
+	if propertyID == "" {
+		propertyID = propertyOf(resource)
+	}
+	entry := auditlog.Entry{
+		Service:      metrics.ServiceGA4Admin,
+		Operation:    verb,
+		ResourceType: kind,
+		Resource:     resource,
+		Property:     propertyID,
+		Request:      auditlog.Request(request),
+	}
+	if err != nil {
+		entry.Result = auditlog.ResultError
+		entry.Error = err.Error()
+	}
+	if auditErr := auditlog.Record(entry); auditErr != nil {
+		c.logger.Warn("failed to record audit log entry",
+			slog.String("operation", verb+" "+kind),
+			slog.String("resource", resource),
+			slog.String("error", auditErr.Error()),
+		)
+	}
+	return err
+}
+
+// changeResult is change for requests that return a value.
+func changeResult[T any](c *Client, verb, kind, resource, propertyID string, request any, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := c.change(verb, kind, resource, propertyID, request, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+		return err
+	})
+	return out, err
+}
+
+// propertyOf returns the property ID a resource name such as
+// "properties/123/conversionEvents/456" belongs to, or "".
+func propertyOf(name string) string {
+	rest, ok := strings.CutPrefix(name, "properties/")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
+
 // GetLogger returns the client's logger for use in other packages
 func (c *Client) GetLogger() *slog.Logger {
 	return c.logger
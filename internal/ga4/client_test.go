@@ -2,11 +2,19 @@ package ga4
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+	"github.com/garbarok/ga4-manager/internal/auth"
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/timeout"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
 )
 
 // TestNewClient_WithoutCredentials tests that NewClient fails without credentials
@@ -17,14 +25,15 @@ func TestNewClient_WithoutCredentials(t *testing.T) {
 		_ = os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", originalCreds)
 	}()
 
-	// Unset the credential file
+	// Unset the credential file, with no cached login to fall back on
 	_ = os.Unsetenv("GOOGLE_APPLICATION_CREDENTIALS")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 
 	client, err := NewClient()
 
 	assert.Error(t, err)
 	assert.Nil(t, client)
-	assert.Contains(t, err.Error(), "GOOGLE_APPLICATION_CREDENTIALS not set")
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
 }
 
 // TestNewClient_WithInvalidCredentials tests that NewClient fails with invalid credentials file
@@ -121,3 +130,85 @@ func BenchmarkNewClient(b *testing.B) {
 		}
 	}
 }
+
+// hangingAdminAPI blocks conversion creates until the call's context expires,
+// standing in for a hung Admin API request.
+type hangingAdminAPI struct {
+	*fakeAdminAPI
+}
+
+func (h hangingAdminAPI) createConversionEvent(ctx context.Context, _ string, _ *admin.GoogleAnalyticsAdminV1alphaConversionEvent) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// A hung create is cut off by the per-operation timeout and reported with the
+// operation and resource that timed out.
+func TestCall_PerOperationTimeoutNamesResource(t *testing.T) {
+	c := newTestClient(hangingAdminAPI{&fakeAdminAPI{}})
+	c.config.Timeouts.Operations = map[string]time.Duration{"create": 10 * time.Millisecond}
+
+	err := c.CreateConversion("123456789", "purchase", "ONCE_PER_EVENT")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var te *timeout.Error
+	require.ErrorAs(t, err, &te)
+	assert.Equal(t, "create conversion", te.Operation)
+	assert.Equal(t, "purchase", te.Resource)
+	assert.False(t, te.RunDeadline)
+	assert.Contains(t, err.Error(), `create conversion "purchase" timed out after 10ms`)
+}
+
+// When the run-wide deadline expires first, the error says so.
+func TestCall_RunDeadlineReported(t *testing.T) {
+	c := newTestClient(hangingAdminAPI{&fakeAdminAPI{}})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	c.ctx = ctx
+
+	err := c.call(verbCreate, "conversion", "purchase", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var te *timeout.Error
+	require.ErrorAs(t, err, &te)
+	assert.True(t, te.RunDeadline)
+	assert.Equal(t, `run deadline exceeded during create conversion "purchase"`, err.Error())
+}
+
+// Every change is recorded in the audit log with its property, request and
+// outcome; reads are not.
+func TestChange_RecordsAuditEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), auditlog.FileName)
+	auditlog.SetDefault(auditlog.New(path, auditlog.WithActor(func() string { return "ci@example.iam.gserviceaccount.com" })))
+	t.Cleanup(func() { auditlog.SetDefault(nil) })
+
+	fake := &fakeAdminAPI{patchConvErr: errors.New("permission denied")}
+	c := newTestClient(fake)
+	conv := &admin.GoogleAnalyticsAdminV1alphaConversionEvent{Name: "properties/123456/conversionEvents/7", EventName: "purchase"}
+
+	require.NoError(t, c.CreateConversion("123456", "sign_up", "ONCE_PER_EVENT"))
+	_, err := c.ListConversions("123456")
+	require.NoError(t, err)
+	require.Error(t, c.SetConversionCountingMethod(conv, "ONCE_PER_SESSION"))
+
+	entries, err := auditlog.Read(path, auditlog.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "create", entries[0].Operation)
+	assert.Equal(t, "conversion", entries[0].ResourceType)
+	assert.Equal(t, "sign_up", entries[0].Resource)
+	assert.Equal(t, "123456", entries[0].Property)
+	assert.Equal(t, auditlog.ResultOK, entries[0].Result)
+	assert.Equal(t, "ci@example.iam.gserviceaccount.com", entries[0].Actor)
+
+	assert.Equal(t, "update", entries[1].Operation)
+	assert.Equal(t, "purchase", entries[1].Resource)
+	assert.Equal(t, "123456", entries[1].Property, "read from the resource name")
+	assert.JSONEq(t, `{"countingMethod":"ONCE_PER_SESSION"}`, string(entries[1].Request))
+	assert.Equal(t, auditlog.ResultError, entries[1].Result)
+	assert.Contains(t, entries[1].Error, "permission denied")
+}
@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -11,6 +12,13 @@ import (
 )
 
 func (c *Client) CreateConversion(propertyID, eventName, countingMethod string) error {
+	return c.CreateConversionFromConfig(propertyID, config.ConversionConfig{Name: eventName, CountingMethod: countingMethod}, "")
+}
+
+// CreateConversionFromConfig creates the conversion event described by conv,
+// with its default value in currency when conv.DefaultValue is set.
+func (c *Client) CreateConversionFromConfig(propertyID string, conv config.ConversionConfig, currency string) error {
+	eventName, countingMethod := conv.Name, conv.CountingMethod
 	if err := validation.ValidateConversionParams(propertyID, eventName, countingMethod); err != nil {
 		c.logger.Error("validation failed",
 			slog.String("property_id", propertyID),
@@ -20,6 +28,11 @@ func (c *Client) CreateConversion(propertyID, eventName, countingMethod string)
 		)
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if conv.DefaultValue != nil {
+		if err := validation.ValidateCurrencyCode(currency); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
 
 	c.logger.Debug("creating conversion event",
 		slog.String("property_id", propertyID),
@@ -27,34 +40,119 @@ func (c *Client) CreateConversion(propertyID, eventName, countingMethod string)
 		slog.String("counting_method", countingMethod),
 	)
 
-	return c.createResource("conversion", propertyID, eventName, func(parent string) error {
-		conversion := &admin.GoogleAnalyticsAdminV1alphaConversionEvent{
-			EventName:      eventName,
-			CountingMethod: countingMethod,
-		}
-		return c.admin.createConversionEvent(c.ctx, parent, conversion)
+	event := conversionToSDK(conv, currency)
+	return c.createResource("conversion", propertyID, eventName, event, func(ctx context.Context, parent string) error {
+		return c.admin.createConversionEvent(ctx, parent, event)
 	})
 }
 
-func conversionToSDK(conv config.ConversionConfig) *admin.GoogleAnalyticsAdminV1alphaConversionEvent {
-	return &admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+func conversionToSDK(conv config.ConversionConfig, currency string) *admin.GoogleAnalyticsAdminV1alphaConversionEvent {
+	e := &admin.GoogleAnalyticsAdminV1alphaConversionEvent{
 		EventName:      conv.Name,
 		CountingMethod: conv.CountingMethod,
 	}
+	if conv.DefaultValue != nil {
+		e.DefaultConversionValue = &admin.GoogleAnalyticsAdminV1alphaConversionEventDefaultConversionValue{
+			Value:        *conv.DefaultValue,
+			CurrencyCode: currency,
+			// A configured default of 0 is deliberate and must be sent.
+			ForceSendFields: []string{"Value"},
+		}
+	}
+	return e
 }
 
-func (c *Client) SetupConversions(propertyID string, conversions []config.ConversionConfig) error {
+// SetupConversions creates every conversion. A default value is in the
+// conversion's own currency, else in defaultCurrency, the property-level one
+// ProjectConfig.DefaultCurrency returns.
+func (c *Client) SetupConversions(propertyID string, conversions []config.ConversionConfig, defaultCurrency string) error {
 	for _, conv := range conversions {
-		if err := c.CreateConversion(propertyID, conv.Name, conv.CountingMethod); err != nil && !errors.Is(err, ErrAlreadyExists) {
+		currency := conv.Currency
+		if currency == "" {
+			currency = defaultCurrency
+		}
+		if err := c.CreateConversionFromConfig(propertyID, conv, currency); err != nil && !errors.Is(err, ErrAlreadyExists) {
 			return err
 		}
 	}
 	return nil
 }
 
+// SetConversionDefaultValue sets the default value and currency GA4 applies
+// to conversions of eventName that arrive without a value parameter.
+func (c *Client) SetConversionDefaultValue(propertyID, eventName string, value float64, currency string) error {
+	if err := validation.ValidatePropertyID(propertyID); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validation.ValidateCurrencyCode(currency); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	conv, err := c.findConversionByEventName(propertyID, eventName)
+	if err != nil {
+		return fmt.Errorf("failed to find conversion '%s': %w", eventName, err)
+	}
+	if conv == nil {
+		return fmt.Errorf("conversion event '%s' not found in property %s", eventName, propertyID)
+	}
+
+	if err := c.waitForRateLimit(c.ctx, "SetConversionDefaultValue"); err != nil {
+		return err
+	}
+
+	patch := conversionToSDK(config.ConversionConfig{Name: eventName, DefaultValue: &value}, currency)
+	if err := c.change(verbUpdate, "conversion", eventName, propertyID, patch, func(ctx context.Context) error {
+		return c.admin.patchConversionEvent(ctx, conv.Name, patch, "defaultConversionValue")
+	}); err != nil {
+		c.logger.Error("failed to set conversion default value",
+			slog.String("event_name", eventName),
+			slog.String("property_id", propertyID),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to set default value for conversion '%s': %w", eventName, err)
+	}
+
+	c.logger.Info("conversion default value set",
+		slog.String("event_name", eventName),
+		slog.Float64("value", value),
+		slog.String("currency", currency),
+	)
+	return nil
+}
+
+// SetConversionCountingMethod changes how often conv counts per session.
+// conv comes from ListConversions, so a bulk change lists the property once.
+func (c *Client) SetConversionCountingMethod(conv *admin.GoogleAnalyticsAdminV1alphaConversionEvent, countingMethod string) error {
+	if err := validation.ValidateCountingMethod(countingMethod); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := c.waitForRateLimit(c.ctx, "SetConversionCountingMethod"); err != nil {
+		return err
+	}
+
+	patch := &admin.GoogleAnalyticsAdminV1alphaConversionEvent{CountingMethod: countingMethod}
+	if err := c.change(verbUpdate, "conversion", conv.EventName, propertyOf(conv.Name), patch, func(ctx context.Context) error {
+		return c.admin.patchConversionEvent(ctx, conv.Name, patch, "countingMethod")
+	}); err != nil {
+		c.logger.Error("failed to set conversion counting method",
+			slog.String("event_name", conv.EventName),
+			slog.String("counting_method", countingMethod),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to set counting method for conversion '%s': %w", conv.EventName, err)
+	}
+
+	c.logger.Info("conversion counting method set",
+		slog.String("event_name", conv.EventName),
+		slog.String("counting_method", countingMethod),
+	)
+	return nil
+}
+
 func (c *Client) ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
-	return listResource(c, "conversion", propertyID, func(parent string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
-		return c.admin.listConversionEvents(c.ctx, parent)
+	return listResource(c, "conversion", propertyID, func(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+		return c.admin.listConversionEvents(ctx, parent)
 	})
 }
 
@@ -109,7 +207,9 @@ func (c *Client) DeleteConversion(propertyID, eventName string) error {
 		return err
 	}
 
-	if err := c.admin.deleteConversionEvent(c.ctx, conv.Name); err != nil {
+	if err := c.change(verbDelete, "conversion", eventName, propertyID, nil, func(ctx context.Context) error {
+		return c.admin.deleteConversionEvent(ctx, conv.Name)
+	}); err != nil {
 		c.logger.Error("failed to delete conversion",
 			slog.String("event_name", eventName),
 			slog.String("property_id", propertyID),
@@ -153,7 +153,88 @@ func TestConversionToSDK(t *testing.T) {
 		Name:           "purchase",
 		CountingMethod: "ONCE_PER_SESSION",
 		Description:    "Purchase conversion",
-	})
+	}, "")
 	assert.Equal(t, "purchase", sdk.EventName)
 	assert.Equal(t, "ONCE_PER_SESSION", sdk.CountingMethod)
+	assert.Nil(t, sdk.DefaultConversionValue)
+
+	zero := 0.0
+	sdk = conversionToSDK(config.ConversionConfig{Name: "sign_up", DefaultValue: &zero}, "EUR")
+	require.NotNil(t, sdk.DefaultConversionValue)
+	assert.Equal(t, "EUR", sdk.DefaultConversionValue.CurrencyCode)
+	assert.Contains(t, sdk.DefaultConversionValue.ForceSendFields, "Value", "an explicit 0 must be sent")
+}
+
+func TestCreateConversionFromConfig_DefaultValue(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	value := 40.0
+
+	err := c.CreateConversionFromConfig("123456", config.ConversionConfig{
+		Name: "generate_lead", CountingMethod: "ONCE_PER_EVENT", DefaultValue: &value,
+	}, "USD")
+
+	require.NoError(t, err)
+	require.NotNil(t, fake.gotCreateConv.DefaultConversionValue)
+	assert.Equal(t, 40.0, fake.gotCreateConv.DefaultConversionValue.Value)
+	assert.Equal(t, "USD", fake.gotCreateConv.DefaultConversionValue.CurrencyCode)
+
+	err = c.CreateConversionFromConfig("123456", config.ConversionConfig{
+		Name: "generate_lead", CountingMethod: "ONCE_PER_EVENT", DefaultValue: &value,
+	}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currency code")
+	assert.Equal(t, 1, fake.createConvCalls)
+}
+
+// A conversion without its own currency falls back to the property-level one.
+func TestSetupConversions_CurrencyFallback(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	value := 40.0
+
+	err := c.SetupConversions("123456", []config.ConversionConfig{
+		{Name: "generate_lead", CountingMethod: "ONCE_PER_EVENT", DefaultValue: &value},
+	}, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", fake.gotCreateConv.DefaultConversionValue.CurrencyCode)
+
+	err = c.SetupConversions("123456", []config.ConversionConfig{
+		{Name: "purchase", CountingMethod: "ONCE_PER_EVENT", DefaultValue: &value, Currency: "USD"},
+	}, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", fake.gotCreateConv.DefaultConversionValue.CurrencyCode)
+}
+
+func TestSetConversionDefaultValue(t *testing.T) {
+	fake := &fakeAdminAPI{convList: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		{Name: "properties/123456/conversionEvents/9", EventName: "generate_lead"},
+	}}
+	c := newTestClient(fake)
+
+	require.NoError(t, c.SetConversionDefaultValue("123456", "generate_lead", 25, "EUR"))
+	assert.Equal(t, "properties/123456/conversionEvents/9", fake.gotPatchConvName)
+	assert.Equal(t, "defaultConversionValue", fake.gotPatchConvMask)
+	assert.Equal(t, 25.0, fake.gotPatchConv.DefaultConversionValue.Value)
+
+	err := c.SetConversionDefaultValue("123456", "missing_event", 25, "EUR")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Equal(t, 1, fake.patchConvCalls)
+}
+
+func TestSetConversionCountingMethod(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	conv := &admin.GoogleAnalyticsAdminV1alphaConversionEvent{Name: "properties/123456/conversionEvents/7", EventName: "scroll_90", CountingMethod: "ONCE_PER_EVENT"}
+
+	require.NoError(t, c.SetConversionCountingMethod(conv, "ONCE_PER_SESSION"))
+	assert.Equal(t, "properties/123456/conversionEvents/7", fake.gotPatchConvName)
+	assert.Equal(t, "countingMethod", fake.gotPatchConvMask)
+	assert.Equal(t, "ONCE_PER_SESSION", fake.gotPatchConv.CountingMethod)
+
+	err := c.SetConversionCountingMethod(conv, "ONCE_PER_DAY")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid counting method")
+	assert.Equal(t, 1, fake.patchConvCalls)
 }
@@ -0,0 +1,173 @@
+package ga4
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+
+	"github.com/garbarok/ga4-manager/internal/metrics"
+)
+
+// dataAPI is the narrow consumer interface over the GA4 Data API
+// (analyticsdata/v1beta), the reporting counterpart of adminAPI.
+type dataAPI interface {
+	runReport(ctx context.Context, property string, req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, error)
+	runRealtimeReport(ctx context.Context, property string, req *analyticsdata.RunRealtimeReportRequest) (*analyticsdata.RunRealtimeReportResponse, error)
+}
+
+type realDataAPI struct {
+	svc *analyticsdata.Service
+}
+
+func (a *realDataAPI) runReport(ctx context.Context, property string, req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, error) {
+	return a.svc.Properties.RunReport(property, req).Context(ctx).Do()
+}
+
+func (a *realDataAPI) runRealtimeReport(ctx context.Context, property string, req *analyticsdata.RunRealtimeReportRequest) (*analyticsdata.RunRealtimeReportResponse, error) {
+	return a.svc.Properties.RunRealtimeReport(property, req).Context(ctx).Do()
+}
+
+// MetricTotals returns the property's totals of metricNames, Data API names
+// such as "sessions" or "keyEvents", from startDate to endDate inclusive
+// (YYYY-MM-DD). A metric with no data in the range is 0.
+func (c *Client) MetricTotals(propertyID, startDate, endDate string, metricNames ...string) (map[string]float64, error) {
+	if len(metricNames) == 0 {
+		return nil, fmt.Errorf("no metrics requested")
+	}
+	req := &analyticsdata.RunReportRequest{
+		DateRanges: []*analyticsdata.DateRange{{StartDate: startDate, EndDate: endDate}},
+	}
+	for _, name := range metricNames {
+		req.Metrics = append(req.Metrics, &analyticsdata.Metric{Name: name})
+	}
+	resp, err := c.runReport("MetricTotals", propertyID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64, len(metricNames))
+	for _, name := range metricNames {
+		totals[name] = 0
+	}
+	if len(resp.Rows) == 0 {
+		return totals, nil
+	}
+	for i, v := range resp.Rows[0].MetricValues {
+		if i >= len(resp.MetricHeaders) {
+			break
+		}
+		f, err := strconv.ParseFloat(v.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("report metric %s: %w", resp.MetricHeaders[i].Name, err)
+		}
+		totals[resp.MetricHeaders[i].Name] = f
+	}
+	return totals, nil
+}
+
+// EventCounts returns how many times each of eventNames was logged from
+// startDate to endDate. Dates are YYYY-MM-DD or the Data API's relative
+// forms ("7daysAgo", "today"). An event never logged in the range is 0.
+func (c *Client) EventCounts(propertyID, startDate, endDate string, eventNames []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(eventNames))
+	if len(eventNames) == 0 {
+		return counts, nil
+	}
+	for _, name := range eventNames {
+		counts[name] = 0
+	}
+	resp, err := c.runReport("EventCounts", propertyID, &analyticsdata.RunReportRequest{
+		DateRanges: []*analyticsdata.DateRange{{StartDate: startDate, EndDate: endDate}},
+		Dimensions: []*analyticsdata.Dimension{{Name: "eventName"}},
+		Metrics:    []*analyticsdata.Metric{{Name: "eventCount"}},
+		DimensionFilter: &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{
+			FieldName:    "eventName",
+			InListFilter: &analyticsdata.InListFilter{Values: eventNames, CaseSensitive: true},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range resp.Rows {
+		if len(row.DimensionValues) == 0 || len(row.MetricValues) == 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(row.MetricValues[0].Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("report eventCount of %s: %w", row.DimensionValues[0].Value, err)
+		}
+		counts[row.DimensionValues[0].Value] += n
+	}
+	return counts, nil
+}
+
+// notSet is the Data API's value of a dimension an event did not carry.
+const notSet = "(not set)"
+
+// DimensionValueCount returns how many events from startDate to endDate
+// carried a value of dimension, a Data API name such as
+// "customEvent:plan_type" (see CustomDimensionAPIName).
+func (c *Client) DimensionValueCount(propertyID, startDate, endDate, dimension string) (int64, error) {
+	resp, err := c.runReport("DimensionValueCount", propertyID, &analyticsdata.RunReportRequest{
+		DateRanges: []*analyticsdata.DateRange{{StartDate: startDate, EndDate: endDate}},
+		Dimensions: []*analyticsdata.Dimension{{Name: dimension}},
+		Metrics:    []*analyticsdata.Metric{{Name: "eventCount"}},
+		DimensionFilter: &analyticsdata.FilterExpression{NotExpression: &analyticsdata.FilterExpression{
+			Filter: &analyticsdata.Filter{
+				FieldName:    dimension,
+				StringFilter: &analyticsdata.StringFilter{MatchType: "EXACT", Value: notSet},
+			},
+		}},
+		MetricAggregations: []string{"TOTAL"},
+		Limit:              1,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Totals) == 0 || len(resp.Totals[0].MetricValues) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(resp.Totals[0].MetricValues[0].Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("report eventCount of %s: %w", dimension, err)
+	}
+	return n, nil
+}
+
+// CustomDimensionAPIName returns the Data API name of the custom dimension
+// registered for parameter with scope (EVENT, USER or ITEM).
+func CustomDimensionAPIName(scope, parameter string) string {
+	switch strings.ToUpper(scope) {
+	case "USER":
+		return "customUser:" + parameter
+	case "ITEM":
+		return "customItem:" + parameter
+	default:
+		return "customEvent:" + parameter
+	}
+}
+
+// runReport runs one Data API report against the property, rate limited
+// and retried like the Admin API calls. op names the call in logs.
+func (c *Client) runReport(op, propertyID string, req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, error) {
+	if err := c.ValidatePropertyID(propertyID); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := c.waitForRateLimit(c.ctx, op); err != nil {
+		return nil, err
+	}
+
+	var resp *analyticsdata.RunReportResponse
+	err := c.callService(metrics.ServiceGA4Data, verbGet, "report", propertyID, func(ctx context.Context) error {
+		var err error
+		resp, err = c.data.runReport(ctx, "properties/"+propertyID, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run report for property %s: %w", propertyID, err)
+	}
+	return resp, nil
+}
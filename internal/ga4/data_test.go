@@ -0,0 +1,101 @@
+package ga4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+type fakeDataAPI struct {
+	property     string
+	req          *analyticsdata.RunReportRequest
+	resp         *analyticsdata.RunReportResponse
+	realtimeReq  *analyticsdata.RunRealtimeReportRequest
+	realtimeResp *analyticsdata.RunRealtimeReportResponse
+}
+
+func (f *fakeDataAPI) runReport(_ context.Context, property string, req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, error) {
+	f.property, f.req = property, req
+	return f.resp, nil
+}
+
+func (f *fakeDataAPI) runRealtimeReport(_ context.Context, property string, req *analyticsdata.RunRealtimeReportRequest) (*analyticsdata.RunRealtimeReportResponse, error) {
+	f.property, f.realtimeReq = property, req
+	return f.realtimeResp, nil
+}
+
+func TestMetricTotals(t *testing.T) {
+	fake := &fakeDataAPI{resp: &analyticsdata.RunReportResponse{
+		MetricHeaders: []*analyticsdata.MetricHeader{{Name: "sessions"}, {Name: "keyEvents"}},
+		Rows:          []*analyticsdata.Row{{MetricValues: []*analyticsdata.MetricValue{{Value: "1520"}, {Value: "37"}}}},
+	}}
+	client := newTestClient(&fakeAdminAPI{})
+	client.data = fake
+
+	totals, err := client.MetricTotals("123456789", "2026-10-08", "2026-10-14", "sessions", "keyEvents")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"sessions": 1520, "keyEvents": 37}, totals)
+	assert.Equal(t, "properties/123456789", fake.property)
+	require.Len(t, fake.req.DateRanges, 1)
+	assert.Equal(t, "2026-10-08", fake.req.DateRanges[0].StartDate)
+	assert.Equal(t, "2026-10-14", fake.req.DateRanges[0].EndDate)
+}
+
+func TestMetricTotals_NoRowsIsZero(t *testing.T) {
+	client := newTestClient(&fakeAdminAPI{})
+	client.data = &fakeDataAPI{resp: &analyticsdata.RunReportResponse{}}
+
+	totals, err := client.MetricTotals("123456789", "2026-10-08", "2026-10-14", "sessions")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"sessions": 0}, totals)
+}
+
+func TestMetricTotals_InvalidProperty(t *testing.T) {
+	_, err := newTestClient(&fakeAdminAPI{}).MetricTotals("abc", "2026-10-08", "2026-10-14", "sessions")
+	assert.Error(t, err)
+}
+
+func TestEventCounts(t *testing.T) {
+	fake := &fakeDataAPI{resp: &analyticsdata.RunReportResponse{
+		Rows: []*analyticsdata.Row{{
+			DimensionValues: []*analyticsdata.DimensionValue{{Value: "purchase"}},
+			MetricValues:    []*analyticsdata.MetricValue{{Value: "42"}},
+		}},
+	}}
+	client := newTestClient(&fakeAdminAPI{})
+	client.data = fake
+
+	counts, err := client.EventCounts("123456789", "7daysAgo", "today", []string{"purchase", "generate_lead"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"purchase": 42, "generate_lead": 0}, counts)
+	require.NotNil(t, fake.req.DimensionFilter.Filter.InListFilter)
+	assert.Equal(t, []string{"purchase", "generate_lead"}, fake.req.DimensionFilter.Filter.InListFilter.Values)
+}
+
+func TestDimensionValueCount(t *testing.T) {
+	fake := &fakeDataAPI{resp: &analyticsdata.RunReportResponse{
+		Totals: []*analyticsdata.Row{{MetricValues: []*analyticsdata.MetricValue{{Value: "1377"}}}},
+	}}
+	client := newTestClient(&fakeAdminAPI{})
+	client.data = fake
+
+	n, err := client.DimensionValueCount("123456789", "7daysAgo", "today", "customEvent:plan_type")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1377), n)
+	assert.Equal(t, "customEvent:plan_type", fake.req.Dimensions[0].Name)
+	assert.Equal(t, "(not set)", fake.req.DimensionFilter.NotExpression.Filter.StringFilter.Value)
+
+	fake.resp = &analyticsdata.RunReportResponse{}
+	n, err = client.DimensionValueCount("123456789", "7daysAgo", "today", "customEvent:plan_type")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestCustomDimensionAPIName(t *testing.T) {
+	assert.Equal(t, "customEvent:plan_type", CustomDimensionAPIName("EVENT", "plan_type"))
+	assert.Equal(t, "customUser:tier", CustomDimensionAPIName("USER", "tier"))
+	assert.Equal(t, "customItem:color", CustomDimensionAPIName("ITEM", "color"))
+}
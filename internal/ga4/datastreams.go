@@ -1,10 +1,13 @@
 package ga4
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
 )
 
 // DataStream represents a GA4 data stream
@@ -36,7 +39,9 @@ type EnhancedMeasurement struct {
 func (c *Client) ListDataStreams(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
 	parent := fmt.Sprintf("properties/%s", propertyID)
 
-	streams, err := c.admin.listDataStreams(c.ctx, parent)
+	streams, err := callResult(c, verbList, "data streams", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+		return c.admin.listDataStreams(ctx, parent)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list data streams: %w", err)
 	}
@@ -46,7 +51,9 @@ func (c *Client) ListDataStreams(propertyID string) ([]*admin.GoogleAnalyticsAdm
 
 // GetDataStream retrieves a specific data stream
 func (c *Client) GetDataStream(streamName string) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
-	stream, err := c.admin.getDataStream(c.ctx, streamName)
+	stream, err := callResult(c, verbGet, "data stream", streamName, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+		return c.admin.getDataStream(ctx, streamName)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data stream: %w", err)
 	}
@@ -67,12 +74,42 @@ func (c *Client) GetWebDataStreamByProperty(propertyID string) (*admin.GoogleAna
 		}
 	}
 
-	return nil, fmt.Errorf("no web data stream found for property %s", propertyID)
+	return nil, fmt.Errorf("%w for property %s", ErrNoWebStream, propertyID)
+}
+
+// GetPropertyEnhancedMeasurement retrieves the enhanced measurement settings
+// of the property's first web data stream. Their Name carries the stream.
+func (c *Client) GetPropertyEnhancedMeasurement(propertyID string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
+	stream, err := c.GetWebDataStreamByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetEnhancedMeasurementSettings(stream.Name)
+}
+
+// EnhancedMeasurementFromConfig returns current with the toggles of cfg
+// applied; the search and URI query parameters are kept. GA4 measures page
+// views whenever enhanced measurement is on and cannot turn them off alone,
+// so the stream-wide switch is on unless every toggle in cfg is false.
+func EnhancedMeasurementFromConfig(current *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, cfg *config.EnhancedMeasurementConfig) *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings {
+	desired := *current
+	desired.ScrollsEnabled = cfg.Scrolls
+	desired.OutboundClicksEnabled = cfg.OutboundClicks
+	desired.SiteSearchEnabled = cfg.SiteSearch
+	desired.VideoEngagementEnabled = cfg.VideoEngagement
+	desired.FileDownloadsEnabled = cfg.FileDownloads
+	desired.PageChangesEnabled = cfg.PageChanges
+	desired.FormInteractionsEnabled = cfg.FormInteractions
+	desired.StreamEnabled = cfg.PageViews || cfg.Scrolls || cfg.OutboundClicks || cfg.SiteSearch ||
+		cfg.VideoEngagement || cfg.FileDownloads || cfg.PageChanges || cfg.FormInteractions
+	return &desired
 }
 
 // GetEnhancedMeasurementSettings retrieves enhanced measurement settings for a data stream
 func (c *Client) GetEnhancedMeasurementSettings(streamName string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
-	settings, err := c.admin.getEnhancedMeasurementSettings(c.ctx, fmt.Sprintf("%s/enhancedMeasurementSettings", streamName))
+	settings, err := callResult(c, verbGet, "enhanced measurement settings", streamName, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
+		return c.admin.getEnhancedMeasurementSettings(ctx, fmt.Sprintf("%s/enhancedMeasurementSettings", streamName))
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get enhanced measurement settings: %w", err)
 	}
@@ -84,9 +121,11 @@ func (c *Client) GetEnhancedMeasurementSettings(streamName string) (*admin.Googl
 func (c *Client) UpdateEnhancedMeasurement(streamName string, settings *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings) error {
 	settingsPath := fmt.Sprintf("%s/enhancedMeasurementSettings", streamName)
 
-	updateMask := "scrollsEnabled,outboundClicksEnabled,siteSearchEnabled,videoEngagementEnabled,fileDownloadsEnabled,pageChangesEnabled,formInteractionsEnabled,searchQueryParameter,uriQueryParameter"
+	updateMask := "streamEnabled,scrollsEnabled,outboundClicksEnabled,siteSearchEnabled,videoEngagementEnabled,fileDownloadsEnabled,pageChangesEnabled,formInteractionsEnabled,searchQueryParameter,uriQueryParameter"
 
-	if err := c.admin.updateEnhancedMeasurementSettings(c.ctx, settingsPath, settings, updateMask); err != nil {
+	if err := c.change(verbUpdate, "enhanced measurement settings", streamName, "", settings, func(ctx context.Context) error {
+		return c.admin.updateEnhancedMeasurementSettings(ctx, settingsPath, settings, updateMask)
+	}); err != nil {
 		return fmt.Errorf("failed to update enhanced measurement: %w", err)
 	}
 
@@ -101,6 +140,7 @@ func (c *Client) EnableAllEnhancedMeasurement(propertyID string) error {
 	}
 
 	settings := &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{
+		StreamEnabled:           true,
 		ScrollsEnabled:          true,
 		OutboundClicksEnabled:   true,
 		SiteSearchEnabled:       true,
@@ -123,6 +163,7 @@ func (c *Client) ConfigureEnhancedMeasurementForSEO(propertyID string) error {
 	}
 
 	settings := &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{
+		StreamEnabled:           true,
 		ScrollsEnabled:          true,  // Track scroll depth for engagement
 		OutboundClicksEnabled:   true,  // Track external links (important for SEO)
 		SiteSearchEnabled:       true,  // Track internal searches
@@ -0,0 +1,30 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestEnhancedMeasurementFromConfig(t *testing.T) {
+	current := &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{
+		Name:                   "properties/1/dataStreams/2/enhancedMeasurementSettings",
+		StreamEnabled:          true,
+		VideoEngagementEnabled: true,
+		SearchQueryParameter:   "q,s",
+	}
+
+	desired := EnhancedMeasurementFromConfig(current, &config.EnhancedMeasurementConfig{PageViews: true, Scrolls: true})
+
+	assert.True(t, desired.StreamEnabled)
+	assert.True(t, desired.ScrollsEnabled)
+	assert.False(t, desired.VideoEngagementEnabled)
+	assert.Equal(t, "q,s", desired.SearchQueryParameter, "query parameters are kept")
+	assert.True(t, current.VideoEngagementEnabled, "current is not modified")
+
+	off := EnhancedMeasurementFromConfig(current, &config.EnhancedMeasurementConfig{})
+	assert.False(t, off.StreamEnabled)
+}
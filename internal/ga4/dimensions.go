@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -29,8 +30,9 @@ func (c *Client) CreateDimension(propertyID string, dim config.DimensionConfig)
 		slog.String("scope", dim.Scope),
 	)
 
-	return c.createResource("dimension", propertyID, dim.DisplayName, func(parent string) error {
-		return c.admin.createCustomDimension(c.ctx, parent, dimToSDK(dim))
+	customDimension := dimToSDK(dim)
+	return c.createResource("dimension", propertyID, dim.DisplayName, customDimension, func(ctx context.Context, parent string) error {
+		return c.admin.createCustomDimension(ctx, parent, customDimension)
 	})
 }
 
@@ -53,8 +55,8 @@ func (c *Client) SetupDimensions(propertyID string, dims []config.DimensionConfi
 }
 
 func (c *Client) ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
-	return listResource(c, "dimension", propertyID, func(parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
-		return c.admin.listCustomDimensions(c.ctx, parent)
+	return listResource(c, "dimension", propertyID, func(ctx context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+		return c.admin.listCustomDimensions(ctx, parent)
 	})
 }
 
@@ -110,7 +112,9 @@ func (c *Client) DeleteDimension(propertyID, parameterName string) error {
 		return err
 	}
 
-	if err := c.admin.archiveCustomDimension(c.ctx, dim.Name); err != nil {
+	if err := c.change(verbDelete, "dimension", parameterName, propertyID, nil, func(ctx context.Context) error {
+		return c.admin.archiveCustomDimension(ctx, dim.Name)
+	}); err != nil {
 		c.logger.Error("failed to archive dimension",
 			slog.String("parameter_name", parameterName),
 			slog.String("property_id", propertyID),
@@ -126,3 +130,33 @@ func (c *Client) DeleteDimension(propertyID, parameterName string) error {
 
 	return nil
 }
+
+// UpdateDimension updates the display name and description of an existing
+// custom dimension to match dim; the parameter name and scope are fixed at
+// creation.
+func (c *Client) UpdateDimension(dimensionName string, dim config.DimensionConfig) error {
+	return c.patchDimension(dimensionName, dimToSDK(dim))
+}
+
+// RestoreDimension patches a custom dimension back to previous, a copy read
+// before an update.
+func (c *Client) RestoreDimension(previous *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error {
+	return c.patchDimension(previous.Name, previous)
+}
+
+func (c *Client) patchDimension(dimensionName string, dim *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error {
+	if err := c.waitForRateLimit(c.ctx, "UpdateDimension"); err != nil {
+		return err
+	}
+	if err := c.change(verbUpdate, "dimension", dimensionName, "", dim, func(ctx context.Context) error {
+		return c.admin.patchCustomDimension(ctx, dimensionName, dim, "displayName,description")
+	}); err != nil {
+		c.logger.Error("failed to update dimension",
+			slog.String("dimension_name", dimensionName),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to update dimension '%s': %w", dimensionName, err)
+	}
+	c.logger.Info("dimension updated successfully", slog.String("dimension_name", dimensionName))
+	return nil
+}
@@ -127,6 +127,18 @@ func TestDeleteDimension_FoundArchivesByResourceName(t *testing.T) {
 	assert.Equal(t, "properties/123456789/customDimensions/d1", fake.gotArchiveDimName)
 }
 
+func TestUpdateDimension_PatchesMutableFields(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	err := c.UpdateDimension("properties/123456789/customDimensions/d1", sampleDimension())
+
+	require.NoError(t, err)
+	assert.Equal(t, "properties/123456789/customDimensions/d1", fake.gotPatchDimName)
+	assert.Equal(t, "displayName,description", fake.gotPatchDimMask)
+	assert.Equal(t, "User Type", fake.gotPatchDim.DisplayName)
+}
+
 func TestDeleteDimension_NotFound(t *testing.T) {
 	fake := &fakeAdminAPI{dimList: nil}
 	c := newTestClient(fake)
@@ -2,7 +2,10 @@ package ga4
 
 import (
 	"errors"
+	"net/http"
 	"strings"
+
+	"google.golang.org/api/googleapi"
 )
 
 // ErrAlreadyExists is returned (wrapped) by Create* methods when the GA4 API
@@ -10,6 +13,10 @@ import (
 // skippable conflict or a failure; it must never be reported as a creation.
 var ErrAlreadyExists = errors.New("resource already exists")
 
+// ErrNoWebStream is returned (wrapped) when a property has no web data stream,
+// so there are no enhanced measurement settings to read or change.
+var ErrNoWebStream = errors.New("no web data stream found")
+
 // errMsgAlreadyExists is the error message substring returned by the GA4 API
 // when a resource already exists. Centralised here so that if the API changes
 // its wording only this constant needs updating.
@@ -29,3 +36,10 @@ func isAlreadyExistsError(err error) bool {
 	msg := err.Error()
 	return strings.Contains(msg, errMsgAlreadyExists) || strings.Contains(msg, errMsgAlreadyExistsGRPC)
 }
+
+// isForbidden reports whether err is the API's 403 PERMISSION_DENIED, which
+// is how GA4 answers a call the credential's role does not allow.
+func isForbidden(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
@@ -21,8 +21,9 @@ var errAlreadyExists = errors.New("resource already exists")
 // Client's logic be exercised without a live Google Analytics Admin API.
 //
 // Only the conversion/dimension/metric operations carry capture + injection
-// fields (those are what the symmetric-core tests cover); the remaining
-// operations are inert stubs present only to satisfy the interface.
+// fields (those are what the symmetric-core tests cover), plus error injection
+// on the list calls DetectCapabilities probes; the remaining operations are
+// inert stubs present only to satisfy the interface.
 type fakeAdminAPI struct {
 	// ConversionEvents
 	convList            []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
@@ -35,6 +36,11 @@ type fakeAdminAPI struct {
 	gotCreateConvParent string
 	gotCreateConv       *admin.GoogleAnalyticsAdminV1alphaConversionEvent
 	gotDeleteConvName   string
+	patchConvErr        error
+	patchConvCalls      int
+	gotPatchConvName    string
+	gotPatchConv        *admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	gotPatchConvMask    string
 
 	// CustomDimensions
 	dimList            []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
@@ -47,6 +53,9 @@ type fakeAdminAPI struct {
 	gotCreateDimParent string
 	gotCreateDim       *admin.GoogleAnalyticsAdminV1alphaCustomDimension
 	gotArchiveDimName  string
+	gotPatchDimName    string
+	gotPatchDim        *admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	gotPatchDimMask    string
 
 	// CustomMetrics
 	metList            []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
@@ -59,6 +68,38 @@ type fakeAdminAPI struct {
 	gotCreateMetParent string
 	gotCreateMet       *admin.GoogleAnalyticsAdminV1alphaCustomMetric
 	gotArchiveMetName  string
+	gotPatchMetName    string
+	gotPatchMet        *admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	gotPatchMetMask    string
+
+	// CalculatedMetrics
+	calcList            []*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric
+	createCalcErr       error
+	gotCreateCalcParent string
+	gotCreateCalcID     string
+	gotCreateCalc       *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric
+	gotDeleteCalcName   string
+
+	// Capability probes: errors returned by the list calls DetectCapabilities uses
+	listChanErr   error
+	listAudErr    error
+	listEDSErr    error
+	listSubErr    error
+	listAudCalls  int
+	listChanCalls int
+
+	// Access bindings, keyed by parent (accounts/… or properties/…)
+	bindings        map[string][]*admin.GoogleAnalyticsAdminV1alphaAccessBinding
+	listBindingsErr map[string]error
+	property        *admin.GoogleAnalyticsAdminV1alphaProperty
+
+	// Data streams and property lifecycle
+	streams               []*admin.GoogleAnalyticsAdminV1alphaDataStream
+	gotCreateStream       *admin.GoogleAnalyticsAdminV1alphaDataStream
+	propList              []*admin.GoogleAnalyticsAdminV1alphaProperty
+	gotCreateProperty     *admin.GoogleAnalyticsAdminV1alphaProperty
+	gotListPropsFilter    string
+	gotDeletePropertyName string
 }
 
 // --- ConversionEvents ---
@@ -84,6 +125,14 @@ func (f *fakeAdminAPI) deleteConversionEvent(_ context.Context, name string) err
 	return f.deleteConvErr
 }
 
+func (f *fakeAdminAPI) patchConversionEvent(_ context.Context, name string, e *admin.GoogleAnalyticsAdminV1alphaConversionEvent, updateMask string) error {
+	f.patchConvCalls++
+	f.gotPatchConvName = name
+	f.gotPatchConv = e
+	f.gotPatchConvMask = updateMask
+	return f.patchConvErr
+}
+
 // --- CustomDimensions ---
 
 func (f *fakeAdminAPI) createCustomDimension(_ context.Context, parent string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error {
@@ -107,6 +156,11 @@ func (f *fakeAdminAPI) archiveCustomDimension(_ context.Context, name string) er
 	return f.archiveDimErr
 }
 
+func (f *fakeAdminAPI) patchCustomDimension(_ context.Context, name string, d *admin.GoogleAnalyticsAdminV1alphaCustomDimension, updateMask string) error {
+	f.gotPatchDimName, f.gotPatchDim, f.gotPatchDimMask = name, d, updateMask
+	return nil
+}
+
 // --- CustomMetrics ---
 
 func (f *fakeAdminAPI) createCustomMetric(_ context.Context, parent string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
@@ -124,7 +178,8 @@ func (f *fakeAdminAPI) listCustomMetrics(_ context.Context, _ string) ([]*admin.
 	return f.metList, nil
 }
 
-func (f *fakeAdminAPI) patchCustomMetric(_ context.Context, _ string, _ *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
+func (f *fakeAdminAPI) patchCustomMetric(_ context.Context, name string, m *admin.GoogleAnalyticsAdminV1alphaCustomMetric, updateMask string) error {
+	f.gotPatchMetName, f.gotPatchMet, f.gotPatchMetMask = name, m, updateMask
 	return nil
 }
 
@@ -140,7 +195,8 @@ func (f *fakeAdminAPI) createChannelGroup(context.Context, string, *admin.Google
 	return nil, nil
 }
 func (f *fakeAdminAPI) listChannelGroups(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
-	return nil, nil
+	f.listChanCalls++
+	return nil, f.listChanErr
 }
 func (f *fakeAdminAPI) patchChannelGroup(context.Context, string, *admin.GoogleAnalyticsAdminV1alphaChannelGroup, string) error {
 	return nil
@@ -149,12 +205,32 @@ func (f *fakeAdminAPI) deleteChannelGroup(context.Context, string) error { retur
 func (f *fakeAdminAPI) getChannelGroup(context.Context, string) (*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
 	return nil, nil
 }
+func (f *fakeAdminAPI) createCalculatedMetric(_ context.Context, parent, id string, m *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric) error {
+	f.gotCreateCalcParent, f.gotCreateCalcID, f.gotCreateCalc = parent, id, m
+	return f.createCalcErr
+}
+func (f *fakeAdminAPI) listCalculatedMetrics(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	return f.calcList, nil
+}
+func (f *fakeAdminAPI) deleteCalculatedMetric(_ context.Context, name string) error {
+	f.gotDeleteCalcName = name
+	return nil
+}
 func (f *fakeAdminAPI) listDataStreams(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
-	return nil, nil
+	return f.streams, nil
 }
 func (f *fakeAdminAPI) getDataStream(context.Context, string) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
 	return nil, nil
 }
+func (f *fakeAdminAPI) createDataStream(_ context.Context, parent string, s *admin.GoogleAnalyticsAdminV1alphaDataStream) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+	f.gotCreateStream = s
+	created := *s
+	created.Name = parent + "/dataStreams/555"
+	if s.WebStreamData != nil {
+		created.WebStreamData = &admin.GoogleAnalyticsAdminV1alphaDataStreamWebStreamData{DefaultUri: s.WebStreamData.DefaultUri, MeasurementId: "G-SANDBOX1"}
+	}
+	return &created, nil
+}
 func (f *fakeAdminAPI) getEnhancedMeasurementSettings(context.Context, string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
 	return nil, nil
 }
@@ -174,15 +250,59 @@ func (f *fakeAdminAPI) updateDataRetentionSettings(context.Context, string, *adm
 	return nil
 }
 
+func (f *fakeAdminAPI) listAudiences(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaAudience, error) {
+	f.listAudCalls++
+	return nil, f.listAudErr
+}
+func (f *fakeAdminAPI) listExpandedDataSets(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet, error) {
+	return nil, f.listEDSErr
+}
+func (f *fakeAdminAPI) listSubpropertyEventFilters(context.Context, string) ([]*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter, error) {
+	return nil, f.listSubErr
+}
+
+func (f *fakeAdminAPI) listAccessBindings(_ context.Context, parent string) ([]*admin.GoogleAnalyticsAdminV1alphaAccessBinding, error) {
+	if err := f.listBindingsErr[parent]; err != nil {
+		return nil, err
+	}
+	return f.bindings[parent], nil
+}
+
+func (f *fakeAdminAPI) getProperty(context.Context, string) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	if f.property == nil {
+		return &admin.GoogleAnalyticsAdminV1alphaProperty{}, nil
+	}
+	return f.property, nil
+}
+
+// --- Property lifecycle ---
+
+func (f *fakeAdminAPI) createProperty(_ context.Context, p *admin.GoogleAnalyticsAdminV1alphaProperty) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	f.gotCreateProperty = p
+	created := *p
+	created.Name = "properties/999"
+	return &created, nil
+}
+func (f *fakeAdminAPI) listProperties(_ context.Context, filter string) ([]*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	f.gotListPropsFilter = filter
+	return f.propList, nil
+}
+func (f *fakeAdminAPI) deleteProperty(_ context.Context, name string) error {
+	f.gotDeletePropertyName = name
+	return nil
+}
+
 // newTestClient builds a Client backed by the given fake adminAPI, with an
-// unlimited rate limiter and a discard logger, so methods run instantly and
-// silently in tests.
+// unlimited rate limiter, a discard logger and no retries, so methods run
+// instantly and silently in tests.
 func newTestClient(api adminAPI) *Client {
+	cfg := config.DefaultClientConfig()
+	cfg.Retry.MaxRetries = 0
 	return &Client{
 		admin:       api,
 		ctx:         context.Background(),
 		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
 		rateLimiter: rate.NewLimiter(rate.Inf, 1),
-		config:      config.DefaultClientConfig(),
+		config:      cfg,
 	}
 }
@@ -0,0 +1,218 @@
+package ga4
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// ErrFeatureDisabled is returned (wrapped) when a method needs a v1alpha
+// surface that the property's config has not enabled.
+var ErrFeatureDisabled = errors.New("feature disabled")
+
+// WithFeatures sets the feature flags of every property the client touches.
+// Without it the client uses the registered defaults (see
+// config.KnownFeatures).
+func WithFeatures(features config.FeatureSet) ClientOption {
+	return func(c *Client) {
+		c.features = features
+	}
+}
+
+// WithPropertyFeatures sets the feature flags of one property, taking
+// precedence over WithFeatures, so a client shared by several configs gates
+// each property by its own config.
+func WithPropertyFeatures(propertyID string, features config.FeatureSet) ClientOption {
+	return func(c *Client) {
+		if c.propertyFeatures == nil {
+			c.propertyFeatures = make(map[string]config.FeatureSet)
+		}
+		c.propertyFeatures[propertyID] = features
+	}
+}
+
+// featuresFor returns the feature flags of a property, given its ID or the
+// name of one of its resources (properties/123/channelGroups/456).
+func (c *Client) featuresFor(property string) config.FeatureSet {
+	id := strings.TrimPrefix(property, "properties/")
+	id, _, _ = strings.Cut(id, "/")
+	if features, ok := c.propertyFeatures[id]; ok {
+		return features
+	}
+	return c.features
+}
+
+// requireFeature fails fast, before any API call, when f is switched off for
+// property (an ID or a resource name).
+func (c *Client) requireFeature(property string, f config.Feature) error {
+	if c.featuresFor(property).Enabled(f) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is an alpha Admin API surface; set analytics.features.%s: true in the config to use it", ErrFeatureDisabled, f, f)
+}
+
+// CapabilityStatus is the outcome of probing a feature's API surface.
+type CapabilityStatus string
+
+const (
+	// CapabilityAvailable means the probe succeeded.
+	CapabilityAvailable CapabilityStatus = "available"
+	// CapabilityUnavailable means the endpoint is gone or not implemented (404/501),
+	// which is how v1alpha removals and renames usually surface.
+	CapabilityUnavailable CapabilityStatus = "unavailable"
+	// CapabilityForbidden means the credentials lack access (403).
+	CapabilityForbidden CapabilityStatus = "forbidden"
+	// CapabilityUnsupported means the property cannot use the surface (400),
+	// e.g. 360-only features on a standard property.
+	CapabilityUnsupported CapabilityStatus = "unsupported"
+	// CapabilityError is any other failure (timeouts, 5xx, quota).
+	CapabilityError CapabilityStatus = "error"
+)
+
+// Capability pairs a feature's configured state with what the API reported.
+type Capability struct {
+	Feature  config.Feature   `json:"feature"`
+	Enabled  bool             `json:"enabled"`
+	Explicit bool             `json:"explicit"`
+	Status   CapabilityStatus `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+}
+
+// Usable reports whether the feature is both switched on and answered by the API.
+func (c Capability) Usable() bool {
+	return c.Enabled && c.Status == CapabilityAvailable
+}
+
+// Mismatch reports whether the flag is on but the API cannot serve it.
+func (c Capability) Mismatch() bool {
+	return c.Enabled && c.Status != CapabilityAvailable
+}
+
+// DetectCapabilities probes each gated feature with a read-only list call and
+// reports what the property actually supports. Probes run regardless of the
+// flags — that is the point — but never write anything.
+func (c *Client) DetectCapabilities(propertyID string) []Capability {
+	parent := fmt.Sprintf("properties/%s", propertyID)
+
+	probes := map[config.Feature]func(ctx context.Context) error{
+		config.FeatureChannelGroups: func(ctx context.Context) error {
+			_, err := c.admin.listChannelGroups(ctx, parent)
+			return err
+		},
+		config.FeatureAudiences: func(ctx context.Context) error {
+			_, err := c.admin.listAudiences(ctx, parent)
+			return err
+		},
+		config.FeatureSubproperties: func(ctx context.Context) error {
+			_, err := c.admin.listSubpropertyEventFilters(ctx, parent)
+			return err
+		},
+		config.FeatureExpandedDataSets: func(ctx context.Context) error {
+			_, err := c.admin.listExpandedDataSets(ctx, parent)
+			return err
+		},
+	}
+
+	features := c.featuresFor(propertyID)
+	var out []Capability
+	for _, info := range config.KnownFeatures() {
+		capability := Capability{
+			Feature:  info.Name,
+			Enabled:  features.Enabled(info.Name),
+			Explicit: features.Explicit(info.Name),
+		}
+		probe, ok := probes[info.Name]
+		if !ok {
+			capability.Status = CapabilityError
+			capability.Detail = "no probe registered"
+			out = append(out, capability)
+			continue
+		}
+		err := c.call(verbList, "capability "+string(info.Name), propertyID, probe)
+		capability.Status, capability.Detail = classifyCapabilityError(err)
+		out = append(out, capability)
+	}
+	return out
+}
+
+// classifyCapabilityError maps a probe error onto a CapabilityStatus.
+func classifyCapabilityError(err error) (CapabilityStatus, string) {
+	if err == nil {
+		return CapabilityAvailable, ""
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return CapabilityError, err.Error()
+	}
+	detail := apiErr.Message
+	if detail == "" {
+		detail = http.StatusText(apiErr.Code)
+	}
+	switch apiErr.Code {
+	case http.StatusNotFound, http.StatusNotImplemented:
+		return CapabilityUnavailable, detail
+	case http.StatusForbidden:
+		return CapabilityForbidden, detail
+	case http.StatusBadRequest:
+		return CapabilityUnsupported, detail
+	default:
+		return CapabilityError, detail
+	}
+}
+
+// ListAudiences lists the property's audiences. Gated by FeatureAudiences.
+func (c *Client) ListAudiences(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaAudience, error) {
+	if err := c.requireFeature(propertyID, config.FeatureAudiences); err != nil {
+		return nil, err
+	}
+	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+
+	audiences, err := callResult(c, verbList, "audiences", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaAudience, error) {
+		return c.admin.listAudiences(ctx, propertyPath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audiences: %w", err)
+	}
+	return audiences, nil
+}
+
+// ListExpandedDataSets lists the property's expanded data sets. Gated by
+// FeatureExpandedDataSets.
+func (c *Client) ListExpandedDataSets(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet, error) {
+	if err := c.requireFeature(propertyID, config.FeatureExpandedDataSets); err != nil {
+		return nil, err
+	}
+	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+
+	sets, err := callResult(c, verbList, "expanded data sets", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaExpandedDataSet, error) {
+		return c.admin.listExpandedDataSets(ctx, propertyPath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expanded data sets: %w", err)
+	}
+	return sets, nil
+}
+
+// ListSubpropertyEventFilters lists the event filters that feed the property's
+// subproperties. Gated by FeatureSubproperties.
+func (c *Client) ListSubpropertyEventFilters(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter, error) {
+	if err := c.requireFeature(propertyID, config.FeatureSubproperties); err != nil {
+		return nil, err
+	}
+	propertyPath := fmt.Sprintf("properties/%s", propertyID)
+
+	filters, err := callResult(c, verbList, "subproperty event filters", propertyID, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaSubpropertyEventFilter, error) {
+		return c.admin.listSubpropertyEventFilters(ctx, propertyPath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subproperty event filters: %w", err)
+	}
+	return filters, nil
+}
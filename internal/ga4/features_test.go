@@ -0,0 +1,97 @@
+package ga4
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+// A disabled alpha surface fails before touching the API.
+func TestListAudiences_DisabledByDefault(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	_, err := c.ListAudiences("123456789")
+
+	require.ErrorIs(t, err, ErrFeatureDisabled)
+	assert.Contains(t, err.Error(), "analytics.features.audiences")
+	assert.Equal(t, 0, fake.listAudCalls)
+}
+
+func TestListAudiences_EnabledCallsAPI(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	c.features = config.FeatureSet{config.FeatureAudiences: true}
+
+	_, err := c.ListAudiences("123456789")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.listAudCalls)
+}
+
+// Channel groups default on, so existing configs keep working; an explicit
+// false switches them off.
+func TestChannelGroups_GatedByFlag(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	_, err := c.ListChannelGroups("123456789")
+	require.NoError(t, err)
+
+	c.features = config.FeatureSet{config.FeatureChannelGroups: false}
+	_, err = c.ListChannelGroups("123456789")
+	require.ErrorIs(t, err, ErrFeatureDisabled)
+	assert.Equal(t, 1, fake.listChanCalls)
+}
+
+// A client shared by several configs gates each property by its own flags,
+// whether the method takes the property ID or a resource name.
+func TestChannelGroups_GatedPerProperty(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+	WithPropertyFeatures("111", config.FeatureSet{config.FeatureChannelGroups: false})(c)
+
+	_, err := c.ListChannelGroups("222")
+	require.NoError(t, err)
+
+	_, err = c.ListChannelGroups("111")
+	require.ErrorIs(t, err, ErrFeatureDisabled)
+	require.ErrorIs(t, c.DeleteChannelGroup("properties/111/channelGroups/9"), ErrFeatureDisabled)
+	assert.Equal(t, 1, fake.listChanCalls)
+}
+
+func TestDetectCapabilities_ClassifiesProbeErrors(t *testing.T) {
+	fake := &fakeAdminAPI{
+		listAudErr: &googleapi.Error{Code: http.StatusNotFound, Message: "method not found"},
+		listEDSErr: &googleapi.Error{Code: http.StatusBadRequest, Message: "requires GA4 360"},
+		listSubErr: errors.New("connection reset"),
+	}
+	c := newTestClient(fake)
+	c.features = config.FeatureSet{config.FeatureAudiences: true}
+
+	caps := c.DetectCapabilities("123456789")
+
+	got := map[config.Feature]Capability{}
+	for _, cp := range caps {
+		got[cp.Feature] = cp
+	}
+	require.Len(t, got, len(config.KnownFeatures()))
+
+	assert.Equal(t, CapabilityAvailable, got[config.FeatureChannelGroups].Status)
+	assert.True(t, got[config.FeatureChannelGroups].Usable())
+
+	aud := got[config.FeatureAudiences]
+	assert.Equal(t, CapabilityUnavailable, aud.Status)
+	assert.Equal(t, "method not found", aud.Detail)
+	assert.True(t, aud.Explicit)
+	assert.True(t, aud.Mismatch(), "enabled but not served by the API")
+
+	assert.Equal(t, CapabilityUnsupported, got[config.FeatureExpandedDataSets].Status)
+	assert.False(t, got[config.FeatureExpandedDataSets].Mismatch(), "disabled features are never a mismatch")
+	assert.Equal(t, CapabilityError, got[config.FeatureSubproperties].Status)
+}
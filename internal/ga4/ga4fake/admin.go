@@ -0,0 +1,519 @@
+// Package ga4fake provides an in-memory ga4.GA4Admin for tests of code that
+// drives a GA4 property (the setup orchestrator, the pre-flight validator and
+// the cmd handlers built on them).
+//
+// The fake is written by hand, not generated: it keeps state the way the
+// Admin API does, so tests assert on outcomes rather than on expected calls.
+package ga4fake
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// Admin is a fake GA4 property. The exported fields are its state: seed them
+// before the test and inspect them afterwards. Creates, updates and deletes
+// apply to that state the way the Admin API would, and a create for a
+// resource that already exists fails with ga4.ErrAlreadyExists.
+//
+// Errs makes a method fail, keyed by method name (e.g. "CreateDimension"),
+// before it touches the state. Every call is recorded; see Calls. Admin is
+// safe for concurrent use, since the orchestrator applies steps in parallel.
+type Admin struct {
+	Conversions         []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	Dimensions          []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	Metrics             []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	CalculatedMetrics   []*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric
+	ChannelGroups       []*admin.GoogleAnalyticsAdminV1alphaChannelGroup
+	DataStreams         []*admin.GoogleAnalyticsAdminV1alphaDataStream
+	Retention           ga4.DataRetentionSettings
+	EnhancedMeasurement map[string]*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings
+	// Access is returned by PropertyAccess; users not listed get an empty
+	// PropertyAccess for that user.
+	Access map[string]ga4.PropertyAccess
+
+	Errs map[string]error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// Compile-time guarantee that *Admin satisfies ga4.GA4Admin.
+var _ ga4.GA4Admin = (*Admin)(nil)
+
+// Calls returns the calls made so far as "Method arg", e.g.
+// "CreateDimension author", in the order they were made.
+func (a *Admin) Calls() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.calls)
+}
+
+// Called returns the arguments of the calls made to method, sorted so tests
+// do not depend on the order parallel steps ran in.
+func (a *Admin) Called(method string) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var args []string
+	for _, c := range a.calls {
+		if m, arg, _ := strings.Cut(c, " "); m == method {
+			args = append(args, arg)
+		}
+	}
+	slices.Sort(args)
+	return args
+}
+
+// call records a call and returns its injected error, if any. The caller
+// holds a.mu.
+func (a *Admin) call(method, arg string) error {
+	a.calls = append(a.calls, method+" "+arg)
+	return a.Errs[method]
+}
+
+func alreadyExists(kind, name string) error {
+	return fmt.Errorf("%w: %s %s", ga4.ErrAlreadyExists, kind, name)
+}
+
+func notFound(kind, name string) error {
+	return fmt.Errorf("%s %s not found", kind, name)
+}
+
+// ListConversions returns the property's conversion events.
+func (a *Admin) ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListConversions", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.Conversions), nil
+}
+
+// CreateConversionFromConfig adds a conversion event.
+func (a *Admin) CreateConversionFromConfig(propertyID string, conv config.ConversionConfig, currency string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("CreateConversionFromConfig", conv.Name); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(a.Conversions, func(c *admin.GoogleAnalyticsAdminV1alphaConversionEvent) bool { return c.EventName == conv.Name }) {
+		return alreadyExists("conversion", conv.Name)
+	}
+	a.Conversions = append(a.Conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		Name:           fmt.Sprintf("properties/%s/conversionEvents/%s", propertyID, conv.Name),
+		EventName:      conv.Name,
+		CountingMethod: conv.CountingMethod,
+	})
+	return nil
+}
+
+// SetConversionCountingMethod changes an existing conversion's counting
+// method.
+func (a *Admin) SetConversionCountingMethod(conv *admin.GoogleAnalyticsAdminV1alphaConversionEvent, countingMethod string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("SetConversionCountingMethod", conv.EventName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Conversions, func(c *admin.GoogleAnalyticsAdminV1alphaConversionEvent) bool { return c.EventName == conv.EventName })
+	if i < 0 {
+		return notFound("conversion", conv.EventName)
+	}
+	updated := *a.Conversions[i]
+	updated.CountingMethod = countingMethod
+	a.Conversions[i] = &updated
+	return nil
+}
+
+// DeleteConversion removes a conversion event.
+func (a *Admin) DeleteConversion(propertyID, eventName string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("DeleteConversion", eventName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Conversions, func(c *admin.GoogleAnalyticsAdminV1alphaConversionEvent) bool { return c.EventName == eventName })
+	if i < 0 {
+		return notFound("conversion", eventName)
+	}
+	a.Conversions = slices.Delete(a.Conversions, i, i+1)
+	return nil
+}
+
+// ListDimensions returns the property's custom dimensions.
+func (a *Admin) ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListDimensions", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.Dimensions), nil
+}
+
+// CreateDimension adds a custom dimension.
+func (a *Admin) CreateDimension(propertyID string, dim config.DimensionConfig) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("CreateDimension", dim.ParameterName); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(a.Dimensions, func(d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) bool {
+		return d.ParameterName == dim.ParameterName
+	}) {
+		return alreadyExists("dimension", dim.ParameterName)
+	}
+	a.Dimensions = append(a.Dimensions, &admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+		Name:          fmt.Sprintf("properties/%s/customDimensions/%s", propertyID, dim.ParameterName),
+		ParameterName: dim.ParameterName,
+		DisplayName:   dim.DisplayName,
+		Description:   dim.Description,
+		Scope:         dim.Scope,
+	})
+	return nil
+}
+
+// UpdateDimension changes a custom dimension's display name and description.
+func (a *Admin) UpdateDimension(dimensionName string, dim config.DimensionConfig) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("UpdateDimension", dim.ParameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Dimensions, func(d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) bool { return d.Name == dimensionName })
+	if i < 0 {
+		return notFound("dimension", dimensionName)
+	}
+	updated := *a.Dimensions[i]
+	updated.DisplayName = dim.DisplayName
+	updated.Description = dim.Description
+	a.Dimensions[i] = &updated
+	return nil
+}
+
+// RestoreDimension puts back a custom dimension as it was before an update.
+func (a *Admin) RestoreDimension(previous *admin.GoogleAnalyticsAdminV1alphaCustomDimension) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("RestoreDimension", previous.ParameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Dimensions, func(d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) bool { return d.Name == previous.Name })
+	if i < 0 {
+		return notFound("dimension", previous.Name)
+	}
+	a.Dimensions[i] = previous
+	return nil
+}
+
+// DeleteDimension archives the custom dimension of parameterName, which
+// drops it from the list as archiving does.
+func (a *Admin) DeleteDimension(propertyID, parameterName string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("DeleteDimension", parameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Dimensions, func(d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) bool {
+		return d.ParameterName == parameterName
+	})
+	if i < 0 {
+		return notFound("dimension", parameterName)
+	}
+	a.Dimensions = slices.Delete(a.Dimensions, i, i+1)
+	return nil
+}
+
+// ListCustomMetrics returns the property's custom metrics.
+func (a *Admin) ListCustomMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListCustomMetrics", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.Metrics), nil
+}
+
+// CreateCustomMetric adds a custom metric.
+func (a *Admin) CreateCustomMetric(propertyID string, metric config.MetricConfig) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("CreateCustomMetric", metric.ParameterName); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(a.Metrics, func(m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) bool {
+		return m.ParameterName == metric.ParameterName
+	}) {
+		return alreadyExists("metric", metric.ParameterName)
+	}
+	a.Metrics = append(a.Metrics, &admin.GoogleAnalyticsAdminV1alphaCustomMetric{
+		Name:            fmt.Sprintf("properties/%s/customMetrics/%s", propertyID, metric.ParameterName),
+		ParameterName:   metric.ParameterName,
+		DisplayName:     metric.DisplayName,
+		MeasurementUnit: metric.MeasurementUnit,
+		Scope:           metric.Scope,
+	})
+	return nil
+}
+
+// UpdateCustomMetric changes a custom metric's display name and measurement
+// unit.
+func (a *Admin) UpdateCustomMetric(metricName string, metric config.MetricConfig) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("UpdateCustomMetric", metric.ParameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Metrics, func(m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) bool { return m.Name == metricName })
+	if i < 0 {
+		return notFound("metric", metricName)
+	}
+	updated := *a.Metrics[i]
+	updated.DisplayName = metric.DisplayName
+	updated.MeasurementUnit = metric.MeasurementUnit
+	a.Metrics[i] = &updated
+	return nil
+}
+
+// RestoreCustomMetric puts back a custom metric as it was before an update.
+func (a *Admin) RestoreCustomMetric(previous *admin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("RestoreCustomMetric", previous.ParameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Metrics, func(m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) bool { return m.Name == previous.Name })
+	if i < 0 {
+		return notFound("metric", previous.Name)
+	}
+	a.Metrics[i] = previous
+	return nil
+}
+
+// DeleteMetric archives the custom metric of parameterName.
+func (a *Admin) DeleteMetric(propertyID, parameterName string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("DeleteMetric", parameterName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.Metrics, func(m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) bool { return m.ParameterName == parameterName })
+	if i < 0 {
+		return notFound("metric", parameterName)
+	}
+	a.Metrics = slices.Delete(a.Metrics, i, i+1)
+	return nil
+}
+
+// ListPropertyCalculatedMetrics returns the property's calculated metrics.
+func (a *Admin) ListPropertyCalculatedMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListPropertyCalculatedMetrics", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.CalculatedMetrics), nil
+}
+
+// CreateCalculatedMetric adds a calculated metric and returns its resource
+// name.
+func (a *Admin) CreateCalculatedMetric(propertyID string, metric config.CalculatedMetricConfig) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("CreateCalculatedMetric", metric.ID); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("properties/%s/calculatedMetrics/%s", propertyID, metric.ID)
+	if slices.ContainsFunc(a.CalculatedMetrics, func(m *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric) bool { return m.Name == name }) {
+		return "", alreadyExists("calculated metric", metric.ID)
+	}
+	a.CalculatedMetrics = append(a.CalculatedMetrics, &admin.GoogleAnalyticsAdminV1alphaCalculatedMetric{
+		Name:        name,
+		DisplayName: metric.Name,
+		Description: metric.Description,
+		Formula:     metric.Formula,
+		MetricUnit:  metric.MetricUnit,
+	})
+	return name, nil
+}
+
+// DeleteCalculatedMetric removes a calculated metric.
+func (a *Admin) DeleteCalculatedMetric(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("DeleteCalculatedMetric", name); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.CalculatedMetrics, func(m *admin.GoogleAnalyticsAdminV1alphaCalculatedMetric) bool { return m.Name == name })
+	if i < 0 {
+		return notFound("calculated metric", name)
+	}
+	a.CalculatedMetrics = slices.Delete(a.CalculatedMetrics, i, i+1)
+	return nil
+}
+
+// ListChannelGroups returns the property's channel groups.
+func (a *Admin) ListChannelGroups(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListChannelGroups", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.ChannelGroups), nil
+}
+
+// CreateChannelGroup adds a channel group. Rules are not modelled.
+func (a *Admin) CreateChannelGroup(propertyID string, group ga4.ChannelGroup) (*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("CreateChannelGroup", group.DisplayName); err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(a.ChannelGroups, func(g *admin.GoogleAnalyticsAdminV1alphaChannelGroup) bool { return g.DisplayName == group.DisplayName }) {
+		return nil, alreadyExists("channel group", group.DisplayName)
+	}
+	created := &admin.GoogleAnalyticsAdminV1alphaChannelGroup{
+		Name:        fmt.Sprintf("properties/%s/channelGroups/%d", propertyID, len(a.ChannelGroups)+1),
+		DisplayName: group.DisplayName,
+		Description: group.Description,
+	}
+	a.ChannelGroups = append(a.ChannelGroups, created)
+	return created, nil
+}
+
+// UpdateChannelGroup changes a channel group's display name and description.
+func (a *Admin) UpdateChannelGroup(channelGroupName string, group ga4.ChannelGroup) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("UpdateChannelGroup", channelGroupName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.ChannelGroups, func(g *admin.GoogleAnalyticsAdminV1alphaChannelGroup) bool { return g.Name == channelGroupName })
+	if i < 0 {
+		return notFound("channel group", channelGroupName)
+	}
+	updated := *a.ChannelGroups[i]
+	updated.DisplayName = group.DisplayName
+	updated.Description = group.Description
+	a.ChannelGroups[i] = &updated
+	return nil
+}
+
+// RestoreChannelGroup puts back a channel group as it was before an update.
+func (a *Admin) RestoreChannelGroup(previous *admin.GoogleAnalyticsAdminV1alphaChannelGroup) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("RestoreChannelGroup", previous.Name); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.ChannelGroups, func(g *admin.GoogleAnalyticsAdminV1alphaChannelGroup) bool { return g.Name == previous.Name })
+	if i < 0 {
+		return notFound("channel group", previous.Name)
+	}
+	a.ChannelGroups[i] = previous
+	return nil
+}
+
+// DeleteChannelGroup removes a channel group.
+func (a *Admin) DeleteChannelGroup(channelGroupName string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("DeleteChannelGroup", channelGroupName); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(a.ChannelGroups, func(g *admin.GoogleAnalyticsAdminV1alphaChannelGroup) bool { return g.Name == channelGroupName })
+	if i < 0 {
+		return notFound("channel group", channelGroupName)
+	}
+	a.ChannelGroups = slices.Delete(a.ChannelGroups, i, i+1)
+	return nil
+}
+
+// GetDataRetention returns the property's data retention settings.
+func (a *Admin) GetDataRetention(propertyID string) (*ga4.DataRetentionSettings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("GetDataRetention", propertyID); err != nil {
+		return nil, err
+	}
+	settings := a.Retention
+	return &settings, nil
+}
+
+// UpdateDataRetention replaces the property's data retention settings.
+func (a *Admin) UpdateDataRetention(propertyID string, settings ga4.DataRetentionSettings) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("UpdateDataRetention", propertyID); err != nil {
+		return err
+	}
+	a.Retention = settings
+	return nil
+}
+
+// GetPropertyEnhancedMeasurement returns the enhanced measurement settings of
+// the property's first web data stream, failing with ga4.ErrNoWebStream when
+// it has none.
+func (a *Admin) GetPropertyEnhancedMeasurement(propertyID string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("GetPropertyEnhancedMeasurement", propertyID); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(a.DataStreams, func(s *admin.GoogleAnalyticsAdminV1alphaDataStream) bool { return s.Type == "WEB_DATA_STREAM" })
+	if i < 0 {
+		return nil, fmt.Errorf("%w for property %s", ga4.ErrNoWebStream, propertyID)
+	}
+	stream := a.DataStreams[i].Name
+	settings := a.EnhancedMeasurement[stream]
+	if settings == nil {
+		settings = &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{}
+	}
+	out := *settings
+	out.Name = stream + "/enhancedMeasurementSettings"
+	return &out, nil
+}
+
+// UpdateEnhancedMeasurement replaces a data stream's enhanced measurement
+// settings.
+func (a *Admin) UpdateEnhancedMeasurement(streamName string, settings *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("UpdateEnhancedMeasurement", streamName); err != nil {
+		return err
+	}
+	if a.EnhancedMeasurement == nil {
+		a.EnhancedMeasurement = make(map[string]*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings)
+	}
+	a.EnhancedMeasurement[streamName] = settings
+	return nil
+}
+
+// ListDataStreams returns the property's data streams.
+func (a *Admin) ListDataStreams(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("ListDataStreams", propertyID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(a.DataStreams), nil
+}
+
+// PropertyAccess returns Access[user].
+func (a *Admin) PropertyAccess(propertyID, user string) (ga4.PropertyAccess, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.call("PropertyAccess", user); err != nil {
+		return ga4.PropertyAccess{}, err
+	}
+	if access, ok := a.Access[user]; ok {
+		return access, nil
+	}
+	return ga4.PropertyAccess{User: user}, nil
+}
@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -31,8 +32,9 @@ func (c *Client) CreateCustomMetric(propertyID string, metric config.MetricConfi
 		slog.String("scope", metric.Scope),
 	)
 
-	return c.createResource("custom metric", propertyID, metric.DisplayName, func(parent string) error {
-		return c.admin.createCustomMetric(c.ctx, parent, metricToSDK(metric))
+	customMetric := metricToSDK(metric)
+	return c.createResource("custom metric", propertyID, metric.DisplayName, customMetric, func(ctx context.Context, parent string) error {
+		return c.admin.createCustomMetric(ctx, parent, customMetric)
 	})
 }
 
@@ -60,8 +62,8 @@ func metricToSDK(metric config.MetricConfig) *analyticsadmin.GoogleAnalyticsAdmi
 
 // ListCustomMetrics returns all custom metrics for a property
 func (c *Client) ListCustomMetrics(propertyID string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
-	return listResource(c, "custom metric", propertyID, func(parent string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
-		return c.admin.listCustomMetrics(c.ctx, parent)
+	return listResource(c, "custom metric", propertyID, func(ctx context.Context, parent string) ([]*analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+		return c.admin.listCustomMetrics(ctx, parent)
 	})
 }
 
@@ -75,8 +77,23 @@ func (c *Client) SetupCustomMetrics(propertyID string, metrics []config.MetricCo
 	return nil
 }
 
-// UpdateCustomMetric updates an existing custom metric
+// customMetricUpdateMask lists the custom metric fields the API lets a patch
+// change; the parameter name and scope are fixed at creation.
+const customMetricUpdateMask = "displayName,description,measurementUnit,restrictedMetricType"
+
+// UpdateCustomMetric updates the display name, description, unit and
+// restricted type of an existing custom metric to match metric.
 func (c *Client) UpdateCustomMetric(metricName string, metric config.MetricConfig) error {
+	return c.patchCustomMetric(metricName, metricToSDK(metric))
+}
+
+// RestoreCustomMetric patches a custom metric back to previous, a copy read
+// before an update.
+func (c *Client) RestoreCustomMetric(previous *analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
+	return c.patchCustomMetric(previous.Name, previous)
+}
+
+func (c *Client) patchCustomMetric(metricName string, customMetric *analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric) error {
 	// Wait for rate limit
 	if err := c.waitForRateLimit(c.ctx, "UpdateCustomMetric"); err != nil {
 		return err
@@ -84,15 +101,12 @@ func (c *Client) UpdateCustomMetric(metricName string, metric config.MetricConfi
 
 	c.logger.Debug("updating custom metric",
 		slog.String("metric_name", metricName),
-		slog.String("display_name", metric.DisplayName),
+		slog.String("display_name", customMetric.DisplayName),
 	)
 
-	customMetric := &analyticsadmin.GoogleAnalyticsAdminV1alphaCustomMetric{
-		DisplayName: metric.DisplayName,
-		Description: metric.Description,
-	}
-
-	if err := c.admin.patchCustomMetric(c.ctx, metricName, customMetric); err != nil {
+	if err := c.change(verbUpdate, "custom metric", metricName, "", customMetric, func(ctx context.Context) error {
+		return c.admin.patchCustomMetric(ctx, metricName, customMetric, customMetricUpdateMask)
+	}); err != nil {
 		c.logger.Error("failed to update custom metric",
 			slog.String("metric_name", metricName),
 			slog.String("error", err.Error()),
@@ -118,7 +132,9 @@ func (c *Client) ArchiveCustomMetric(metricName string) error {
 		slog.String("metric_name", metricName),
 	)
 
-	if err := c.admin.archiveCustomMetric(c.ctx, metricName); err != nil {
+	if err := c.change(verbDelete, "custom metric", metricName, "", nil, func(ctx context.Context) error {
+		return c.admin.archiveCustomMetric(ctx, metricName)
+	}); err != nil {
 		c.logger.Error("failed to archive custom metric",
 			slog.String("metric_name", metricName),
 			slog.String("error", err.Error()),
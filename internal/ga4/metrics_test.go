@@ -142,6 +142,20 @@ func TestDeleteMetric_FoundArchivesByResourceName(t *testing.T) {
 	assert.Equal(t, "properties/123456789/customMetrics/m1", fake.gotArchiveMetName)
 }
 
+func TestUpdateCustomMetric_PatchesMutableFields(t *testing.T) {
+	fake := &fakeAdminAPI{}
+	c := newTestClient(fake)
+
+	err := c.UpdateCustomMetric("properties/123456789/customMetrics/m1", config.MetricConfig{
+		ParameterName: "order_value", DisplayName: "Order Value", MeasurementUnit: "CURRENCY", Scope: "EVENT",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "properties/123456789/customMetrics/m1", fake.gotPatchMetName)
+	assert.Equal(t, "displayName,description,measurementUnit,restrictedMetricType", fake.gotPatchMetMask)
+	assert.Equal(t, []string{"REVENUE_DATA"}, fake.gotPatchMet.RestrictedMetricType)
+}
+
 func TestDeleteMetric_NotFound(t *testing.T) {
 	fake := &fakeAdminAPI{metList: nil}
 	c := newTestClient(fake)
@@ -0,0 +1,124 @@
+package ga4
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+
+	"github.com/garbarok/ga4-manager/internal/metrics"
+)
+
+// RealtimeWindow is how many minutes back the Realtime API reports.
+const RealtimeWindow = 30
+
+// RealtimeQuery selects realtime events. Dimensions are extra Realtime API
+// dimensions to break events down by (unifiedScreenName, country,
+// customUser:<param>...); Filters keep only events whose dimension equals
+// the value; Events, when set, keeps only those event names. MinutesAgo is
+// how far back to read, at most RealtimeWindow-1.
+type RealtimeQuery struct {
+	Dimensions []string
+	Filters    map[string]string
+	Events     []string
+	MinutesAgo int
+}
+
+// RealtimeRow is the number of times an event was logged in one minute with
+// one combination of the query's dimensions.
+type RealtimeRow struct {
+	MinutesAgo int
+	EventName  string
+	Dimensions map[string]string
+	Count      int64
+}
+
+// RealtimeEvents returns the property's events of the last q.MinutesAgo
+// minutes from the Realtime API, per minute and event name.
+func (c *Client) RealtimeEvents(propertyID string, q RealtimeQuery) ([]RealtimeRow, error) {
+	if err := c.ValidatePropertyID(propertyID); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if q.MinutesAgo < 0 || q.MinutesAgo >= RealtimeWindow {
+		return nil, fmt.Errorf("realtime reports cover the last %d minutes, not %d", RealtimeWindow, q.MinutesAgo)
+	}
+	if err := c.waitForRateLimit(c.ctx, "RealtimeEvents"); err != nil {
+		return nil, err
+	}
+
+	req := &analyticsdata.RunRealtimeReportRequest{
+		Dimensions:   []*analyticsdata.Dimension{{Name: "minutesAgo"}, {Name: "eventName"}},
+		Metrics:      []*analyticsdata.Metric{{Name: "eventCount"}},
+		MinuteRanges: []*analyticsdata.MinuteRange{{StartMinutesAgo: int64(q.MinutesAgo), EndMinutesAgo: 0, ForceSendFields: []string{"EndMinutesAgo"}}},
+	}
+	for _, d := range q.Dimensions {
+		req.Dimensions = append(req.Dimensions, &analyticsdata.Dimension{Name: d})
+	}
+	req.DimensionFilter = realtimeFilter(q)
+
+	var resp *analyticsdata.RunRealtimeReportResponse
+	err := c.callService(metrics.ServiceGA4Data, verbGet, "realtime report", propertyID, func(ctx context.Context) error {
+		var err error
+		resp, err = c.data.runRealtimeReport(ctx, "properties/"+propertyID, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run realtime report for property %s: %w", propertyID, err)
+	}
+
+	rows := make([]RealtimeRow, 0, len(resp.Rows))
+	for _, r := range resp.Rows {
+		if len(r.DimensionValues) < 2+len(q.Dimensions) || len(r.MetricValues) == 0 {
+			continue
+		}
+		minutes, err := strconv.Atoi(r.DimensionValues[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("realtime minutesAgo %q: %w", r.DimensionValues[0].Value, err)
+		}
+		count, err := strconv.ParseInt(r.MetricValues[0].Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("realtime eventCount %q: %w", r.MetricValues[0].Value, err)
+		}
+		row := RealtimeRow{MinutesAgo: minutes, EventName: r.DimensionValues[1].Value, Count: count}
+		if len(q.Dimensions) > 0 {
+			row.Dimensions = make(map[string]string, len(q.Dimensions))
+			for i, d := range q.Dimensions {
+				row.Dimensions[d] = r.DimensionValues[2+i].Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// realtimeFilter ANDs the query's event and dimension filters, or returns
+// nil when there are none.
+func realtimeFilter(q RealtimeQuery) *analyticsdata.FilterExpression {
+	var exprs []*analyticsdata.FilterExpression
+	if len(q.Events) > 0 {
+		exprs = append(exprs, &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{
+			FieldName:    "eventName",
+			InListFilter: &analyticsdata.InListFilter{Values: q.Events, CaseSensitive: true},
+		}})
+	}
+	fields := make([]string, 0, len(q.Filters))
+	for f := range q.Filters {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		exprs = append(exprs, &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{
+			FieldName:    f,
+			StringFilter: &analyticsdata.StringFilter{MatchType: "EXACT", Value: q.Filters[f]},
+		}})
+	}
+	switch len(exprs) {
+	case 0:
+		return nil
+	case 1:
+		return exprs[0]
+	}
+	return &analyticsdata.FilterExpression{AndGroup: &analyticsdata.FilterExpressionList{Expressions: exprs}}
+}
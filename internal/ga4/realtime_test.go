@@ -0,0 +1,50 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestRealtimeEvents(t *testing.T) {
+	fake := &fakeDataAPI{realtimeResp: &analyticsdata.RunRealtimeReportResponse{
+		Rows: []*analyticsdata.Row{{
+			DimensionValues: []*analyticsdata.DimensionValue{{Value: "02"}, {Value: "sign_up"}, {Value: "Pricing"}},
+			MetricValues:    []*analyticsdata.MetricValue{{Value: "3"}},
+		}},
+	}}
+	client := newTestClient(&fakeAdminAPI{})
+	client.data = fake
+
+	rows, err := client.RealtimeEvents("123456789", RealtimeQuery{
+		Dimensions: []string{"unifiedScreenName"},
+		Filters:    map[string]string{"platform": "web", "customUser:debug_id": "dev-42"},
+		Events:     []string{"sign_up"},
+		MinutesAgo: 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []RealtimeRow{{
+		MinutesAgo: 2,
+		EventName:  "sign_up",
+		Dimensions: map[string]string{"unifiedScreenName": "Pricing"},
+		Count:      3,
+	}}, rows)
+
+	req := fake.realtimeReq
+	assert.Equal(t, "properties/123456789", fake.property)
+	assert.Equal(t, int64(5), req.MinuteRanges[0].StartMinutesAgo)
+	require.NotNil(t, req.DimensionFilter.AndGroup)
+	exprs := req.DimensionFilter.AndGroup.Expressions
+	require.Len(t, exprs, 3)
+	assert.Equal(t, "eventName", exprs[0].Filter.FieldName)
+	assert.Equal(t, "customUser:debug_id", exprs[1].Filter.FieldName)
+	assert.Equal(t, "dev-42", exprs[1].Filter.StringFilter.Value)
+	assert.Equal(t, "platform", exprs[2].Filter.FieldName)
+}
+
+func TestRealtimeEvents_RejectsWindowOutOfRange(t *testing.T) {
+	_, err := newTestClient(&fakeAdminAPI{}).RealtimeEvents("123456789", RealtimeQuery{MinutesAgo: RealtimeWindow})
+	assert.ErrorContains(t, err, "last 30 minutes")
+}
@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -21,14 +22,17 @@ import (
 // e.g. display names are unique across dimensions AND metrics — not just from
 // re-running setup). The caller is responsible for input validation and any
 // descriptive pre-call logging; do performs the actual Properties.<X>.Create
-// call against parent.
-func (c *Client) createResource(kind, propertyID, name string, do func(parent string) error) error {
+// call against parent, under the per-operation timeout carried by ctx; request
+// is the body it sends, recorded in the audit log.
+func (c *Client) createResource(kind, propertyID, name string, request any, do func(ctx context.Context, parent string) error) error {
 	if err := c.waitForRateLimit(c.ctx, "Create "+kind); err != nil {
 		return err
 	}
 
 	parent := fmt.Sprintf("properties/%s", propertyID)
-	err := do(parent)
+	err := c.change(verbCreate, kind, name, propertyID, request, func(ctx context.Context) error {
+		return do(ctx, parent)
+	})
 	switch {
 	case err == nil:
 		c.logger.Info(kind+" created successfully",
@@ -51,8 +55,9 @@ func (c *Client) createResource(kind, propertyID, name string, do func(parent st
 
 // listResource performs a rate-limited list of a GA4 resource collection after
 // validating the property ID. do performs the actual Properties.<X>.List call
-// and extracts the typed slice from the response.
-func listResource[T any](c *Client, kind, propertyID string, do func(parent string) ([]T, error)) ([]T, error) {
+// and extracts the typed slice from the response. Results come from the list
+// cache when it has them.
+func listResource[T any](c *Client, kind, propertyID string, do func(ctx context.Context, parent string) ([]T, error)) ([]T, error) {
 	if err := validation.ValidatePropertyID(propertyID); err != nil {
 		c.logger.Error("invalid property ID",
 			slog.String("property_id", propertyID),
@@ -60,6 +65,13 @@ func listResource[T any](c *Client, kind, propertyID string, do func(parent stri
 		)
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	return cachedList(c, kind, propertyID, func() ([]T, error) {
+		return fetchResource(c, kind, propertyID, do)
+	})
+}
+
+// fetchResource is listResource without the cache.
+func fetchResource[T any](c *Client, kind, propertyID string, do func(ctx context.Context, parent string) ([]T, error)) ([]T, error) {
 
 	if err := c.waitForRateLimit(c.ctx, "List "+kind); err != nil {
 		return nil, err
@@ -68,7 +80,9 @@ func listResource[T any](c *Client, kind, propertyID string, do func(parent stri
 	parent := fmt.Sprintf("properties/%s", propertyID)
 	c.logger.Debug("listing "+kind+"s", slog.String("property_id", propertyID))
 
-	items, err := do(parent)
+	items, err := callResult(c, verbList, kind+"s", propertyID, func(ctx context.Context) ([]T, error) {
+		return do(ctx, parent)
+	})
 	if err != nil {
 		c.logger.Error("failed to list "+kind+"s",
 			slog.String("property_id", propertyID),
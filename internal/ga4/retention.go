@@ -1,6 +1,7 @@
 package ga4
 
 import (
+	"context"
 	"fmt"
 
 	admin "google.golang.org/api/analyticsadmin/v1alpha"
@@ -14,8 +15,6 @@ type DataRetentionSettings struct {
 
 // SetDataRetention configures data retention settings for a property
 func (c *Client) SetDataRetention(propertyID string, months int, resetOnNewActivity bool) error {
-	settingsPath := fmt.Sprintf("properties/%s/dataRetentionSettings", propertyID)
-
 	// Determine retention duration string
 	var retentionDuration string
 	switch months {
@@ -33,15 +32,24 @@ func (c *Client) SetDataRetention(propertyID string, months int, resetOnNewActiv
 		return fmt.Errorf("invalid retention duration: must be 2, 14, 26, 38, or 50 months")
 	}
 
-	// Update data retention settings
-	settings := &admin.GoogleAnalyticsAdminV1alphaDataRetentionSettings{
+	return c.UpdateDataRetention(propertyID, DataRetentionSettings{
 		EventDataRetention:         retentionDuration,
 		ResetUserDataOnNewActivity: resetOnNewActivity,
-	}
+	})
+}
 
+// UpdateDataRetention replaces both data retention settings of a property.
+func (c *Client) UpdateDataRetention(propertyID string, settings DataRetentionSettings) error {
+	settingsPath := fmt.Sprintf("properties/%s/dataRetentionSettings", propertyID)
 	updateMask := "eventDataRetention,resetUserDataOnNewActivity"
 
-	if err := c.admin.updateDataRetentionSettings(c.ctx, settingsPath, settings, updateMask); err != nil {
+	body := &admin.GoogleAnalyticsAdminV1alphaDataRetentionSettings{
+		EventDataRetention:         settings.EventDataRetention,
+		ResetUserDataOnNewActivity: settings.ResetUserDataOnNewActivity,
+	}
+	if err := c.change(verbUpdate, "data retention settings", propertyID, propertyID, body, func(ctx context.Context) error {
+		return c.admin.updateDataRetentionSettings(ctx, settingsPath, body, updateMask)
+	}); err != nil {
 		return fmt.Errorf("failed to update data retention: %w", err)
 	}
 
@@ -52,7 +60,9 @@ func (c *Client) SetDataRetention(propertyID string, months int, resetOnNewActiv
 func (c *Client) GetDataRetention(propertyID string) (*DataRetentionSettings, error) {
 	settingsPath := fmt.Sprintf("properties/%s/dataRetentionSettings", propertyID)
 
-	settings, err := c.admin.getDataRetentionSettings(c.ctx, settingsPath)
+	settings, err := callResult(c, verbGet, "data retention settings", propertyID, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaDataRetentionSettings, error) {
+		return c.admin.getDataRetentionSettings(ctx, settingsPath)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data retention settings: %w", err)
 	}
@@ -78,7 +88,9 @@ func (c *Client) DisableUserDataRetention(propertyID string) error {
 
 	updateMask := "resetUserDataOnNewActivity"
 
-	if err := c.admin.updateDataRetentionSettings(c.ctx, settingsPath, settings, updateMask); err != nil {
+	if err := c.change(verbUpdate, "data retention settings", propertyID, propertyID, settings, func(ctx context.Context) error {
+		return c.admin.updateDataRetentionSettings(ctx, settingsPath, settings, updateMask)
+	}); err != nil {
 		return fmt.Errorf("failed to disable user data retention: %w", err)
 	}
 
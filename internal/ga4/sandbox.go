@@ -0,0 +1,204 @@
+package ga4
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+// SandboxLabel prefixes the display name of every property CreateSandbox
+// makes. GA4 properties have no labels, so the display name is the only
+// marker DeleteSandbox and ListSandboxes can go by.
+const SandboxLabel = "[ga4-manager sandbox]"
+
+// Sandbox is a throwaway property cloned from a source property, with the
+// web stream setup needs to rehearse enhanced measurement and tag checks.
+type Sandbox struct {
+	PropertyID    string
+	DisplayName   string
+	Account       string // accounts/{id}
+	DataStreamID  string // empty when the source has no web stream
+	MeasurementID string
+	CreateTime    string
+}
+
+// SandboxDisplayName is the display name CreateSandbox gives a sandbox of
+// project: the label, the project and the UTC creation time.
+func SandboxDisplayName(project string, now time.Time) string {
+	return fmt.Sprintf("%s %s %s", SandboxLabel, project, now.UTC().Format("2006-01-02 15:04"))
+}
+
+// IsSandbox reports whether a property was made by CreateSandbox.
+func IsSandbox(p *admin.GoogleAnalyticsAdminV1alphaProperty) bool {
+	return strings.HasPrefix(p.DisplayName, SandboxLabel)
+}
+
+// CreateSandbox creates a property for project in the account of the source
+// property, with its time zone, currency and industry, and a web stream for
+// the source's first web stream URI. Accounts cap the number of properties,
+// so sandboxes should be removed with DeleteSandbox once done with.
+func (c *Client) CreateSandbox(sourcePropertyID, project string) (*Sandbox, error) {
+	if err := c.ValidatePropertyID(sourcePropertyID); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	sourcePath := fmt.Sprintf("properties/%s", sourcePropertyID)
+	if err := c.waitForRateLimit(c.ctx, "CreateSandbox"); err != nil {
+		return nil, err
+	}
+	source, err := callResult(c, verbGet, "property", sourcePropertyID, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.getProperty(ctx, sourcePath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source property: %w", err)
+	}
+	if !strings.HasPrefix(source.Parent, "accounts/") {
+		return nil, fmt.Errorf("source property %s is not under an account (parent %q)", sourcePropertyID, source.Parent)
+	}
+	streams, err := c.ListDataStreams(sourcePropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.waitForRateLimit(c.ctx, "CreateSandbox"); err != nil {
+		return nil, err
+	}
+	displayName := SandboxDisplayName(project, time.Now())
+	newProperty := &admin.GoogleAnalyticsAdminV1alphaProperty{
+		Parent:           source.Parent,
+		DisplayName:      displayName,
+		TimeZone:         source.TimeZone,
+		CurrencyCode:     source.CurrencyCode,
+		IndustryCategory: source.IndustryCategory,
+		PropertyType:     "PROPERTY_TYPE_ORDINARY",
+	}
+	property, err := changeResult(c, verbCreate, "property", displayName, "", newProperty, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.createProperty(ctx, newProperty)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox property: %w", err)
+	}
+	sandbox := &Sandbox{
+		PropertyID:  strings.TrimPrefix(property.Name, "properties/"),
+		DisplayName: property.DisplayName,
+		Account:     property.Parent,
+		CreateTime:  property.CreateTime,
+	}
+	c.logger.Info("sandbox property created",
+		slog.String("property_id", sandbox.PropertyID),
+		slog.String("source_property_id", sourcePropertyID),
+	)
+
+	for _, s := range streams {
+		if s.Type != "WEB_DATA_STREAM" || s.WebStreamData == nil {
+			continue
+		}
+		if err := c.waitForRateLimit(c.ctx, "CreateSandbox"); err != nil {
+			return sandbox, err
+		}
+		newStream := &admin.GoogleAnalyticsAdminV1alphaDataStream{
+			Type:          "WEB_DATA_STREAM",
+			DisplayName:   s.DisplayName,
+			WebStreamData: &admin.GoogleAnalyticsAdminV1alphaDataStreamWebStreamData{DefaultUri: s.WebStreamData.DefaultUri},
+		}
+		stream, err := changeResult(c, verbCreate, "data stream", s.DisplayName, sandbox.PropertyID, newStream, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaDataStream, error) {
+			return c.admin.createDataStream(ctx, property.Name, newStream)
+		})
+		if err != nil {
+			return sandbox, fmt.Errorf("failed to create sandbox web stream: %w", err)
+		}
+		sandbox.DataStreamID = stream.Name[strings.LastIndex(stream.Name, "/")+1:]
+		if stream.WebStreamData != nil {
+			sandbox.MeasurementID = stream.WebStreamData.MeasurementId
+		}
+		break
+	}
+	return sandbox, nil
+}
+
+// PropertyAccount returns the account (accounts/{id}) a property belongs to.
+func (c *Client) PropertyAccount(propertyID string) (string, error) {
+	property, err := c.GetProperty(propertyID)
+	if err != nil {
+		return "", err
+	}
+	return property.Parent, nil
+}
+
+// GetProperty reads a property: its display name, account, time zone and
+// currency.
+func (c *Client) GetProperty(propertyID string) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	if err := c.ValidatePropertyID(propertyID); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := c.waitForRateLimit(c.ctx, "GetProperty"); err != nil {
+		return nil, err
+	}
+	property, err := callResult(c, verbGet, "property", propertyID, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.getProperty(ctx, "properties/"+propertyID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read property %s: %w", propertyID, err)
+	}
+	return property, nil
+}
+
+// ListSandboxes returns the sandbox properties in an account (accounts/{id}
+// or the bare id). Properties already in the trash are not listed.
+func (c *Client) ListSandboxes(account string) ([]*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+	if !strings.HasPrefix(account, "accounts/") {
+		account = "accounts/" + account
+	}
+	if err := c.waitForRateLimit(c.ctx, "ListSandboxes"); err != nil {
+		return nil, err
+	}
+	properties, err := callResult(c, verbList, "properties", account, func(ctx context.Context) ([]*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.listProperties(ctx, "parent:"+account)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+	var sandboxes []*admin.GoogleAnalyticsAdminV1alphaProperty
+	for _, p := range properties {
+		if IsSandbox(p) {
+			sandboxes = append(sandboxes, p)
+		}
+	}
+	return sandboxes, nil
+}
+
+// DeleteSandbox moves a sandbox property to the trash, where GA4 keeps it for
+// 35 days. It refuses any property whose display name lacks SandboxLabel, so
+// a mistyped ID cannot delete a real property.
+func (c *Client) DeleteSandbox(propertyID string) error {
+	if err := c.ValidatePropertyID(propertyID); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	name := fmt.Sprintf("properties/%s", propertyID)
+	if err := c.waitForRateLimit(c.ctx, "DeleteSandbox"); err != nil {
+		return err
+	}
+	property, err := callResult(c, verbGet, "property", propertyID, func(ctx context.Context) (*admin.GoogleAnalyticsAdminV1alphaProperty, error) {
+		return c.admin.getProperty(ctx, name)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read property %s: %w", propertyID, err)
+	}
+	if !IsSandbox(property) {
+		return fmt.Errorf("property %s (%q) is not a sandbox; refusing to delete it", propertyID, property.DisplayName)
+	}
+
+	if err := c.waitForRateLimit(c.ctx, "DeleteSandbox"); err != nil {
+		return err
+	}
+	if err := c.change(verbDelete, "property", name, propertyID, nil, func(ctx context.Context) error {
+		return c.admin.deleteProperty(ctx, name)
+	}); err != nil {
+		return fmt.Errorf("failed to delete property %s: %w", propertyID, err)
+	}
+	c.logger.Info("sandbox property deleted", slog.String("property_id", propertyID))
+	return nil
+}
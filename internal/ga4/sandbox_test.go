@@ -0,0 +1,77 @@
+package ga4
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+)
+
+func TestCreateSandbox_ClonesSourcePropertyAndWebStream(t *testing.T) {
+	fake := &fakeAdminAPI{
+		property: &admin.GoogleAnalyticsAdminV1alphaProperty{
+			Name:         "properties/123456789",
+			Parent:       "accounts/42",
+			TimeZone:     "Europe/Madrid",
+			CurrencyCode: "EUR",
+		},
+		streams: []*admin.GoogleAnalyticsAdminV1alphaDataStream{
+			{Type: "ANDROID_APP_DATA_STREAM", DisplayName: "App"},
+			{Type: "WEB_DATA_STREAM", DisplayName: "Web", WebStreamData: &admin.GoogleAnalyticsAdminV1alphaDataStreamWebStreamData{DefaultUri: "https://example.com"}},
+		},
+	}
+	c := newTestClient(fake)
+
+	sandbox, err := c.CreateSandbox("123456789", "my-blog")
+
+	require.NoError(t, err)
+	require.NotNil(t, fake.gotCreateProperty)
+	assert.Equal(t, "accounts/42", fake.gotCreateProperty.Parent)
+	assert.Equal(t, "Europe/Madrid", fake.gotCreateProperty.TimeZone)
+	assert.Equal(t, "EUR", fake.gotCreateProperty.CurrencyCode)
+	assert.True(t, strings.HasPrefix(fake.gotCreateProperty.DisplayName, SandboxLabel+" my-blog "))
+	require.NotNil(t, fake.gotCreateStream)
+	assert.Equal(t, "https://example.com", fake.gotCreateStream.WebStreamData.DefaultUri)
+	assert.Equal(t, "999", sandbox.PropertyID)
+	assert.Equal(t, "555", sandbox.DataStreamID)
+	assert.Equal(t, "G-SANDBOX1", sandbox.MeasurementID)
+}
+
+func TestDeleteSandbox_RefusesUnlabelledProperty(t *testing.T) {
+	fake := &fakeAdminAPI{property: &admin.GoogleAnalyticsAdminV1alphaProperty{DisplayName: "Production"}}
+	c := newTestClient(fake)
+
+	err := c.DeleteSandbox("123456789")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a sandbox")
+	assert.Empty(t, fake.gotDeletePropertyName)
+}
+
+func TestDeleteSandbox_DeletesLabelledProperty(t *testing.T) {
+	fake := &fakeAdminAPI{property: &admin.GoogleAnalyticsAdminV1alphaProperty{
+		DisplayName: SandboxDisplayName("my-blog", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)),
+	}}
+	c := newTestClient(fake)
+
+	require.NoError(t, c.DeleteSandbox("123456789"))
+	assert.Equal(t, "properties/123456789", fake.gotDeletePropertyName)
+}
+
+func TestListSandboxes_FiltersByLabel(t *testing.T) {
+	fake := &fakeAdminAPI{propList: []*admin.GoogleAnalyticsAdminV1alphaProperty{
+		{Name: "properties/1", DisplayName: "Production"},
+		{Name: "properties/2", DisplayName: SandboxLabel + " my-blog 2026-03-01 10:00"},
+	}}
+	c := newTestClient(fake)
+
+	sandboxes, err := c.ListSandboxes("42")
+
+	require.NoError(t, err)
+	assert.Equal(t, "parent:accounts/42", fake.gotListPropsFilter)
+	require.Len(t, sandboxes, 1)
+	assert.Equal(t, "properties/2", sandboxes[0].Name)
+}
@@ -1,6 +1,7 @@
 package gsc
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -67,6 +68,10 @@ const maxRowsPerPage = 25000
 // count of any normal property.
 const maxTotalRows = 100000
 
+// MaxRowLimit is the largest SearchAnalyticsQuery.RowLimit QuerySearchAnalytics
+// accepts.
+const MaxRowLimit = maxTotalRows
+
 // ValidDimensions lists all valid Search Console dimensions
 var ValidDimensions = map[string]bool{
 	"query":            true,
@@ -128,41 +133,17 @@ func (c *Client) QuerySearchAnalytics(query *SearchAnalyticsQuery) (*SearchAnaly
 			pageSize = maxRowsPerPage
 		}
 
-		// Each page is a separate API call: account for quota and rate limit per page.
-		if err := c.useQuota(); err != nil {
-			return nil, fmt.Errorf("quota check failed: %w", err)
-		}
-		if err := c.waitForRateLimit("QuerySearchAnalytics"); err != nil {
-			return nil, fmt.Errorf("rate limit wait failed: %w", err)
-		}
-
-		request := &searchconsole.SearchAnalyticsQueryRequest{
-			StartDate:             query.StartDate,
-			EndDate:               query.EndDate,
-			Dimensions:            query.Dimensions,
-			RowLimit:              int64(pageSize),
-			StartRow:              startRow,
-			DataState:             query.DataState,
-			DimensionFilterGroups: filterGroups,
-		}
-
-		response, err := c.service.Searchanalytics.Query(query.SiteURL, request).Context(c.ctx).Do()
+		rows, err := c.querySearchAnalyticsPage(query, filterGroups, startRow, pageSize)
 		if err != nil {
-			c.logger.Error("search analytics query failed",
-				"site_url", query.SiteURL,
-				"start_row", startRow,
-				"error", err,
-			)
-			return nil, fmt.Errorf("search analytics query failed for %s: %w", query.SiteURL, err)
+			return nil, err
 		}
-
-		aggregated.Rows = append(aggregated.Rows, response.Rows...)
+		aggregated.Rows = append(aggregated.Rows, rows...)
 
 		// A short page means there are no more rows to fetch.
-		if len(response.Rows) < pageSize {
+		if len(rows) < pageSize {
 			break
 		}
-		startRow += int64(len(response.Rows))
+		startRow += int64(len(rows))
 	}
 
 	c.logger.Info("search analytics query completed",
@@ -176,6 +157,146 @@ func (c *Client) QuerySearchAnalytics(query *SearchAnalyticsQuery) (*SearchAnaly
 	return report, nil
 }
 
+// querySearchAnalyticsPage fetches up to pageSize rows of query starting at
+// startRow. Each page is a separate API call, so quota and rate limit are
+// accounted per page.
+func (c *Client) querySearchAnalyticsPage(query *SearchAnalyticsQuery, filterGroups []*searchconsole.ApiDimensionFilterGroup, startRow int64, pageSize int) ([]*searchconsole.ApiDataRow, error) {
+	if err := c.useQuota(query.SiteURL); err != nil {
+		return nil, fmt.Errorf("quota check failed: %w", err)
+	}
+	if err := c.waitForRateLimit("QuerySearchAnalytics"); err != nil {
+		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	request := &searchconsole.SearchAnalyticsQueryRequest{
+		StartDate:             query.StartDate,
+		EndDate:               query.EndDate,
+		Dimensions:            query.Dimensions,
+		RowLimit:              int64(pageSize),
+		StartRow:              startRow,
+		DataState:             query.DataState,
+		DimensionFilterGroups: filterGroups,
+	}
+
+	var response *searchconsole.SearchAnalyticsQueryResponse
+	err := c.call("query", "search analytics", query.SiteURL, func(ctx context.Context) error {
+		var err error
+		response, err = c.service.Searchanalytics.Query(query.SiteURL, request).Context(ctx).Do()
+		return err
+	})
+	if err != nil {
+		c.logger.Error("search analytics query failed",
+			"site_url", query.SiteURL,
+			"start_row", startRow,
+			"error", err,
+		)
+		return nil, fmt.Errorf("search analytics query failed for %s: %w", query.SiteURL, err)
+	}
+	return response.Rows, nil
+}
+
+// SearchAnalyticsStats summarises a streamed query once every page is in.
+type SearchAnalyticsStats struct {
+	Rows       int                      // Rows handed to the callback
+	Pages      int                      // API requests made, one quota unit each
+	Aggregates SearchAnalyticsAggregate // Aggregated totals over all rows
+	QuotaUsed  int                      // GSC API quota used by the client after the last page
+}
+
+// StreamSearchAnalytics runs query without a row cap. It pages with StartRow
+// in 25,000-row pages until the API returns an empty page and hands each
+// page's rows to fn as soon as they arrive, so callers can write them out
+// without holding the whole result in memory. query.RowLimit is ignored.
+// Every page is charged against the daily quota like the pages of
+// QuerySearchAnalytics; an error from fn stops the paging and is returned.
+func (c *Client) StreamSearchAnalytics(query *SearchAnalyticsQuery, fn func(rows []SearchAnalyticsRow) error) (*SearchAnalyticsStats, error) {
+	paged := *query
+	paged.RowLimit = maxRowsPerPage
+	if err := c.validateSearchQuery(&paged); err != nil {
+		return nil, fmt.Errorf("invalid search query: %w", err)
+	}
+
+	var filterGroups []*searchconsole.ApiDimensionFilterGroup
+	if len(paged.Filters) > 0 {
+		filterGroups = []*searchconsole.ApiDimensionFilterGroup{{Filters: paged.Filters}}
+	}
+
+	c.logger.Info("streaming search analytics",
+		"site_url", paged.SiteURL,
+		"date_range", fmt.Sprintf("%s to %s", paged.StartDate, paged.EndDate),
+	)
+
+	stats := &SearchAnalyticsStats{}
+	var totals rowTotals
+	for {
+		apiRows, err := c.querySearchAnalyticsPage(&paged, filterGroups, int64(stats.Rows), maxRowsPerPage)
+		if err != nil {
+			return stats, err
+		}
+		stats.Pages++
+		if len(apiRows) == 0 {
+			break
+		}
+
+		rows := make([]SearchAnalyticsRow, 0, len(apiRows))
+		for _, apiRow := range apiRows {
+			row := convertSearchAnalyticsRow(apiRow)
+			totals.add(row)
+			rows = append(rows, row)
+		}
+		stats.Rows += len(rows)
+		if err := fn(rows); err != nil {
+			return stats, err
+		}
+		c.logger.Debug("search analytics page streamed", "site_url", paged.SiteURL, "page", stats.Pages, "rows", stats.Rows)
+	}
+
+	stats.Aggregates = totals.aggregate(stats.Rows)
+	stats.QuotaUsed, _, _ = c.GetQuotaStatus()
+	c.logger.Info("search analytics stream completed",
+		"site_url", paged.SiteURL,
+		"rows_returned", stats.Rows,
+		"pages", stats.Pages,
+	)
+	return stats, nil
+}
+
+func convertSearchAnalyticsRow(apiRow *searchconsole.ApiDataRow) SearchAnalyticsRow {
+	return SearchAnalyticsRow{
+		Keys:        apiRow.Keys,
+		Clicks:      int64(apiRow.Clicks),
+		Impressions: int64(apiRow.Impressions),
+		CTR:         apiRow.Ctr,
+		Position:    apiRow.Position,
+	}
+}
+
+// rowTotals accumulates the sums behind SearchAnalyticsAggregate.
+type rowTotals struct {
+	clicks, impressions int64
+	ctr, position       float64
+}
+
+func (t *rowTotals) add(row SearchAnalyticsRow) {
+	t.clicks += row.Clicks
+	t.impressions += row.Impressions
+	t.ctr += row.CTR
+	t.position += row.Position
+}
+
+// aggregate returns the totals over n rows, CTR and position as plain means.
+func (t *rowTotals) aggregate(n int) SearchAnalyticsAggregate {
+	if n == 0 {
+		return SearchAnalyticsAggregate{}
+	}
+	return SearchAnalyticsAggregate{
+		TotalClicks:      t.clicks,
+		TotalImpressions: t.impressions,
+		AverageCTR:       t.ctr / float64(n),
+		AveragePosition:  t.position / float64(n),
+	}
+}
+
 // transformSearchAnalyticsResponse converts the API response to our report format
 func (c *Client) transformSearchAnalyticsResponse(query *SearchAnalyticsQuery, response *searchconsole.SearchAnalyticsQueryResponse) *SearchAnalyticsReport {
 	report := &SearchAnalyticsReport{
@@ -192,39 +313,16 @@ func (c *Client) transformSearchAnalyticsResponse(query *SearchAnalyticsQuery, r
 		},
 	}
 
-	// Transform each row
-	var totalClicks, totalImpressions int64
-	var totalCTR, totalPosition float64
-
+	// Transform each row, accumulating the aggregates
+	var totals rowTotals
 	for _, apiRow := range response.Rows {
-		row := SearchAnalyticsRow{
-			Keys:        apiRow.Keys,
-			Clicks:      int64(apiRow.Clicks),
-			Impressions: int64(apiRow.Impressions),
-			CTR:         apiRow.Ctr,
-			Position:    apiRow.Position,
-		}
-
+		row := convertSearchAnalyticsRow(apiRow)
 		report.Rows = append(report.Rows, row)
-
-		// Accumulate for aggregates
-		totalClicks += row.Clicks
-		totalImpressions += row.Impressions
-		totalCTR += row.CTR
-		totalPosition += row.Position
+		totals.add(row)
 	}
 
 	report.TotalRows = len(report.Rows)
-
-	// Calculate aggregates
-	if report.TotalRows > 0 {
-		report.Aggregates = SearchAnalyticsAggregate{
-			TotalClicks:      totalClicks,
-			TotalImpressions: totalImpressions,
-			AverageCTR:       totalCTR / float64(report.TotalRows),
-			AveragePosition:  totalPosition / float64(report.TotalRows),
-		}
-	}
+	report.Aggregates = totals.aggregate(report.TotalRows)
 
 	report.QuotaUsed, _, _ = c.GetQuotaStatus()
 	return report
@@ -247,6 +345,20 @@ func BuildDateRange(days int) (startDate, endDate string) {
 	return startDate, endDate
 }
 
+// DateRangeDays returns how many days the inclusive range startDate to
+// endDate (YYYY-MM-DD) spans, or 0 when either date does not parse.
+func DateRangeDays(startDate, endDate string) int {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
 // BuildDateRangeExact creates start and end dates for specific dates
 // Useful for custom date ranges
 func BuildDateRangeExact(startDate, endDate time.Time) (string, string) {
@@ -391,6 +503,9 @@ func (c *Client) validateSearchQuery(query *SearchAnalyticsQuery) error {
 		if filter.Expression == "" {
 			return fmt.Errorf("filter %d: expression is required", i)
 		}
+		if err := validateFilter(filter); err != nil {
+			return fmt.Errorf("filter %d: %w", i, err)
+		}
 	}
 
 	// Set default data state if not provided
@@ -0,0 +1,44 @@
+package gsc
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/cassette"
+)
+
+func TestQuerySearchAnalytics_Replay(t *testing.T) {
+	// The API only keeps 16 months of data, so the query covers the last
+	// four weeks whenever the test runs.
+	start, end := BuildDateRange(28)
+	c := cassette.New(t, "search_analytics",
+		cassette.Redaction{Env: "GA4_RECORD_SITE_URL", Placeholder: "sc-domain:example.com"},
+		cassette.Redaction{Value: start, Placeholder: "START_DATE"},
+		cassette.Redaction{Value: end, Placeholder: "END_DATE"},
+	)
+	client, err := NewClient(
+		WithHTTPClient(c.Client()),
+		WithQuotaFile(""),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	report, err := client.QuerySearchAnalytics(&SearchAnalyticsQuery{
+		SiteURL:    "sc-domain:example.com",
+		StartDate:  start,
+		EndDate:    end,
+		Dimensions: []string{"query"},
+		RowLimit:   3,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, c.Unused())
+	require.Len(t, report.Rows, 3)
+	assert.Equal(t, []string{"ga4 setup"}, report.Rows[0].Keys)
+	assert.Equal(t, int64(120+45+12), report.Aggregates.TotalClicks)
+}
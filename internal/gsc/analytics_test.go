@@ -0,0 +1,110 @@
+package gsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/searchconsole/v1"
+)
+
+// newPagedSearchClient returns a client whose Search Analytics endpoint
+// serves total rows, honouring startRow and rowLimit, and records the
+// startRow of each request.
+func newPagedSearchClient(t *testing.T, total int) (*Client, *[]int64) {
+	t.Helper()
+	var starts []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req searchconsole.SearchAnalyticsQueryRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		starts = append(starts, req.StartRow)
+
+		resp := searchconsole.SearchAnalyticsQueryResponse{}
+		for i := req.StartRow; i < min(req.StartRow+req.RowLimit, int64(total)); i++ {
+			resp.Rows = append(resp.Rows, &searchconsole.ApiDataRow{Keys: []string{"q"}, Clicks: 1, Impressions: 10, Ctr: 0.1, Position: 2})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	service, err := searchconsole.NewService(context.Background(), option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+	return &Client{
+		service:      service,
+		rateLimiter:  rate.NewLimiter(rate.Inf, 1),
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ctx:          context.Background(),
+		timeout:      10 * time.Second,
+		quotaTracker: newQuotaTracker("inspections", DefaultDailyLimit),
+	}, &starts
+}
+
+func streamQuery() *SearchAnalyticsQuery {
+	start, end := BuildDateRange(7)
+	return &SearchAnalyticsQuery{SiteURL: "sc-domain:example.com", StartDate: start, EndDate: end, Dimensions: []string{"query"}}
+}
+
+func TestStreamSearchAnalytics_PagesUntilEmpty(t *testing.T) {
+	c, starts := newPagedSearchClient(t, 2*maxRowsPerPage+10)
+
+	var pages []int
+	stats, err := c.StreamSearchAnalytics(streamQuery(), func(rows []SearchAnalyticsRow) error {
+		pages = append(pages, len(rows))
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{maxRowsPerPage, maxRowsPerPage, 10}, pages, "rows are handed over page by page")
+	assert.Equal(t, []int64{0, maxRowsPerPage, 2 * maxRowsPerPage, 2*maxRowsPerPage + 10}, *starts,
+		"a short page is not the end; paging stops at the empty one")
+	assert.Equal(t, 2*maxRowsPerPage+10, stats.Rows)
+	assert.Equal(t, 4, stats.Pages)
+	assert.Equal(t, 4, stats.QuotaUsed, "every page, the empty one included, is charged")
+	assert.Equal(t, int64(stats.Rows), stats.Aggregates.TotalClicks)
+	assert.InDelta(t, 2.0, stats.Aggregates.AveragePosition, 1e-9)
+}
+
+func TestStreamSearchAnalytics_CallbackErrorStops(t *testing.T) {
+	c, starts := newPagedSearchClient(t, 3*maxRowsPerPage)
+	wantErr := errors.New("disk full")
+
+	stats, err := c.StreamSearchAnalytics(streamQuery(), func([]SearchAnalyticsRow) error { return wantErr })
+	assert.ErrorIs(t, err, wantErr)
+	assert.Len(t, *starts, 1)
+	assert.Equal(t, 1, stats.Pages)
+}
+
+func TestStreamSearchAnalytics_EmptyResult(t *testing.T) {
+	c, _ := newPagedSearchClient(t, 0)
+
+	called := false
+	stats, err := c.StreamSearchAnalytics(streamQuery(), func([]SearchAnalyticsRow) error {
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, 0, stats.Rows)
+	assert.Equal(t, 1, stats.Pages)
+}
+
+func TestDateRangeDays(t *testing.T) {
+	assert.Equal(t, 1, DateRangeDays("2025-03-03", "2025-03-03"))
+	assert.Equal(t, 7, DateRangeDays("2025-03-03", "2025-03-09"))
+	assert.Equal(t, 31, DateRangeDays("2025-03-01", "2025-03-31"))
+	assert.Equal(t, 0, DateRangeDays("03/03/2025", "2025-03-09"))
+
+	start, end := BuildDateRange(28)
+	assert.Equal(t, 28, DateRangeDays(start, end))
+}
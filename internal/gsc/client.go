@@ -2,25 +2,69 @@ package gsc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
+	"google.golang.org/api/indexing/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/searchconsole/v1"
 
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+	"github.com/garbarok/ga4-manager/internal/auth"
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/logging"
+	"github.com/garbarok/ga4-manager/internal/metrics"
+	"github.com/garbarok/ga4-manager/internal/retry"
+	"github.com/garbarok/ga4-manager/internal/timeout"
 )
 
-// QuotaTracker tracks daily API quota usage
+// QuotaTracker tracks daily API quota usage per property. It is safe for
+// concurrent use so a single Client can be shared across goroutines (e.g. by
+// `ga4 serve`). With a quota file the counts are shared with every other
+// ga4 process on the machine, so separate cron jobs draw from one budget.
 type QuotaTracker struct {
-	currentDate       time.Time // Date of current quota period
-	inspectionCount   int       // Number of inspections today
-	dailyLimit        int       // Maximum inspections per day (2,000 for GSC)
-	warningThreshold  int       // Warn at this count (1,500 = 75%)
-	criticalThreshold int       // Error at this count (1,900 = 95%)
+	mu                sync.Mutex
+	unit              string         // What is counted, for messages ("inspections")
+	currentDate       time.Time      // Date of current quota period
+	counts            map[string]int // Billable calls today per property
+	lastKey           string         // Property of the latest call, reported by status
+	dailyLimit        int            // Maximum calls per day (2,000 for GSC)
+	warningThreshold  int            // Warn at this count (1,500 = 75%)
+	criticalThreshold int            // Error at this count (1,900 = 95%)
+	file              *quotaFile     // Shared counts; nil keeps them in memory
+}
+
+// ErrQuotaExhausted is returned (wrapped) once a daily quota reaches its
+// critical threshold; no further calls are made against it until tomorrow.
+var ErrQuotaExhausted = errors.New("daily quota critical threshold reached")
+
+// DefaultDailyLimit is the Search Console API quota the client tracks per
+// day: 2,000 URL inspections per property.
+const DefaultDailyLimit = 2000
+
+// newQuotaTracker returns a tracker warning at 75% and refusing calls at 95%
+// of dailyLimit.
+func newQuotaTracker(unit string, dailyLimit int) *QuotaTracker {
+	return &QuotaTracker{
+		unit:              unit,
+		currentDate:       time.Now(),
+		counts:            map[string]int{},
+		dailyLimit:        dailyLimit,
+		warningThreshold:  dailyLimit * 75 / 100,
+		criticalThreshold: QuotaBudget(dailyLimit),
+	}
+}
+
+// QuotaBudget is how many calls a day's quota of dailyLimit allows before the
+// tracker refuses further calls (95%).
+func QuotaBudget(dailyLimit int) int {
+	return dailyLimit * 95 / 100
 }
 
 // Client wraps the Google Search Console API service with rate limiting and logging
@@ -31,14 +75,31 @@ type Client struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	timeout      time.Duration
+	opTimeouts   map[string]time.Duration
+	retry        retry.Policy
 	quotaTracker *QuotaTracker
+
+	// Indexing API (urlNotifications.publish) has its own per-project quota,
+	// tracked separately from inspections. The service is created on first use.
+	indexingOnce    sync.Once
+	indexingService *indexing.Service
+	indexingErr     error
+	indexingQuota   *QuotaTracker
+
+	// quotaFile shares both trackers' counts with other processes; "" keeps
+	// them in memory.
+	quotaFile string
+
+	// httpClient, when set, replaces the authenticated client of the
+	// Search Console and Indexing services.
+	httpClient *http.Client
 }
 
 // ClientOption is a functional option for configuring the Client
 type ClientOption func(*Client) error
 
-// NewClient creates a new GSC client with the given options
-// Requires GOOGLE_APPLICATION_CREDENTIALS environment variable to be set
+// NewClient creates a new GSC client with the given options, authenticated
+// with the credentials auth.Resolve picks
 func NewClient(opts ...ClientOption) (*Client, error) {
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -48,33 +109,39 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		ctx:     ctx,
 		cancel:  cancel,
 		timeout: 30 * time.Second,
+		retry:   retry.Policy(config.DefaultClientConfig().Retry),
 		// Default rate limiter: 600 requests/minute per property (10 RPS)
 		// GSC API limits: 2,000/day, 600/min per property
 		rateLimiter: rate.NewLimiter(rate.Limit(10.0), 20),
 		logger:      slog.Default(),
 		// Initialize quota tracker with GSC daily limits
-		quotaTracker: &QuotaTracker{
-			currentDate:       time.Now(),
-			inspectionCount:   0,
-			dailyLimit:        2000, // GSC API limit
-			warningThreshold:  1500, // 75% of daily limit
-			criticalThreshold: 1900, // 95% of daily limit
-		},
+		quotaTracker:  newQuotaTracker("inspections", DefaultDailyLimit),
+		indexingQuota: newQuotaTracker("URL notifications", DefaultIndexingDailyLimit),
+	}
+
+	if path, err := DefaultQuotaFile(); err == nil {
+		client.quotaFile = path
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		if err := opt(client); err != nil {
-			cancel()
+			client.cancel()
 			return nil, fmt.Errorf("failed to apply client option: %w", err)
 		}
 	}
+	if client.quotaFile != "" {
+		client.quotaTracker.file = &quotaFile{path: client.quotaFile}
+		client.indexingQuota.file = &quotaFile{path: client.quotaFile}
+	}
 
 	// Initialize Search Console service with required scopes
 	// Request full access scope for Search Console
-	service, err := searchconsole.NewService(ctx, option.WithScopes(searchconsole.WebmastersScope))
+	// Use client.ctx: options such as WithConfig/WithoutDeadline may have
+	// replaced (and cancelled) the initial context.
+	service, err := searchconsole.NewService(client.ctx, client.serviceOptions(searchconsole.WebmastersScope)...)
 	if err != nil {
-		cancel()
+		client.cancel()
 		return nil, fmt.Errorf("failed to create Search Console service: %w", err)
 	}
 
@@ -84,6 +151,22 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	return client, nil
 }
 
+// serviceOptions authenticates an API service with scope: through
+// httpClient when set, else with the credentials auth.Resolve picks (the
+// service account key of GOOGLE_APPLICATION_CREDENTIALS, or the cached
+// `ga4 auth login` of the active profile). Without either the library falls
+// back to application default credentials.
+func (c *Client) serviceOptions(scope string) []option.ClientOption {
+	if c.httpClient != nil {
+		return []option.ClientOption{option.WithHTTPClient(c.httpClient)}
+	}
+	opts := []option.ClientOption{option.WithScopes(scope)}
+	if creds, err := auth.Resolve(); err == nil {
+		opts = append(opts, creds.ClientOption())
+	}
+	return opts
+}
+
 // WithConfig applies a configuration to the client
 func WithConfig(cfg *config.ClientConfig) ClientOption {
 	return func(c *Client) error {
@@ -100,14 +183,10 @@ func WithConfig(cfg *config.ClientConfig) ClientOption {
 		}
 
 		// Apply timeout config
-		if cfg.Timeouts.RequestTimeout > 0 {
-			c.timeout = cfg.Timeouts.RequestTimeout
-		}
-		if cfg.Timeouts.ContextTimeout > 0 {
-			// Recreate context with new timeout
-			c.cancel()
-			c.ctx, c.cancel = context.WithTimeout(context.Background(), cfg.Timeouts.ContextTimeout)
+		if err := WithTimeouts(cfg.Timeouts)(c); err != nil {
+			return err
 		}
+		c.retry = retry.Policy(cfg.Retry)
 
 		// Apply logging config
 		if cfg.Logging.Level != "" {
@@ -142,6 +221,77 @@ func WithConfig(cfg *config.ClientConfig) ClientOption {
 	}
 }
 
+// WithTimeouts applies only the timeout part of a client configuration: the
+// per-request default, per-verb overrides, and the overall run deadline. Unlike
+// WithConfig it leaves the logger alone, so CLI commands whose stdout must stay
+// machine-readable can use it.
+func WithTimeouts(t config.TimeoutConfig) ClientOption {
+	return func(c *Client) error {
+		if t.RequestTimeout > 0 {
+			c.timeout = t.RequestTimeout
+		}
+		if len(t.Operations) > 0 {
+			c.opTimeouts = t.Operations
+		}
+		if t.ContextTimeout > 0 {
+			// Recreate context with new timeout
+			c.cancel()
+			c.ctx, c.cancel = context.WithTimeout(context.Background(), t.ContextTimeout)
+		}
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the client, e.g. the CLI's shared one. Like
+// WithTimeouts it is not overridden by WithConfig applied before it.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		if logger != nil {
+			c.logger = logger
+		}
+		return nil
+	}
+}
+
+// WithRetry sets how transient API errors (429, 5xx) are retried. Like
+// WithTimeouts it leaves the logger alone.
+func WithRetry(r config.RetryConfig) ClientOption {
+	return func(c *Client) error {
+		c.retry = retry.Policy(r)
+		return nil
+	}
+}
+
+// WithQuotaFile sets the file quota counts are shared in with other
+// processes (see DefaultQuotaFile); "" keeps them to this client.
+func WithQuotaFile(path string) ClientOption {
+	return func(c *Client) error {
+		c.quotaFile = path
+		return nil
+	}
+}
+
+// WithoutDeadline replaces the client-wide context (5 minutes by default) with
+// one that only ends on Close. Long-running callers such as `ga4 serve` share a
+// single client for the lifetime of the process.
+func WithoutDeadline() ClientOption {
+	return func(c *Client) error {
+		c.cancel()
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		return nil
+	}
+}
+
+// WithHTTPClient sends the API requests through hc instead of a client
+// authenticated with the resolved credentials; tests pass a cassette
+// replaying recorded responses.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) error {
+		c.httpClient = hc
+		return nil
+	}
+}
+
 // WithCredentials sets custom credentials for the client
 func WithCredentials(credentialsJSON string) ClientOption {
 	return func(c *Client) error {
@@ -180,6 +330,53 @@ func (c *Client) waitForRateLimit(operation string) error {
 	return nil
 }
 
+// call runs a single Search Console request under the timeout configured for
+// verb, inside the client-wide run deadline. If either expires, the returned
+// *timeout.Error names the operation and resource that ran out of time.
+// Transient failures are retried per the client's retry policy, each attempt
+// logged at debug level (logging.APICall). Every call is counted in
+// metrics.Default.
+func (c *Client) call(verb, kind, resource string, fn func(ctx context.Context) error) error {
+	d := c.timeout
+	if op, ok := c.opTimeouts[verb]; ok && op > 0 {
+		d = op
+	}
+	operation := verb + " " + kind
+	err := retry.Do(c.ctx, c.retry, c.logger, operation, resource, func() error {
+		start := time.Now()
+		err := timeout.Do(c.ctx, operation, resource, d, fn)
+		logging.APICall(c.logger, metrics.ServiceSearchConsole, operation, resource, time.Since(start), err)
+		return err
+	})
+	metrics.Default.ObserveAPICall(metrics.ServiceSearchConsole, operation, err)
+	return err
+}
+
+// change is call for requests that change the site, such as a sitemap
+// submission: the outcome is also recorded in the audit log
+// (internal/auditlog). A failure to write it is logged, never returned.
+func (c *Client) change(verb, kind, resource, siteURL string, fn func(ctx context.Context) error) error {
+	err := c.call(verb, kind, resource, fn)
+	entry := auditlog.Entry{
+		Service:      metrics.ServiceSearchConsole,
+		Operation:    verb,
+		ResourceType: kind,
+		Resource:     resource,
+		Property:     siteURL,
+	}
+	if err != nil {
+		entry.Result = auditlog.ResultError
+		entry.Error = err.Error()
+	}
+	if auditErr := auditlog.Record(entry); auditErr != nil {
+		c.logger.Warn("failed to record audit log entry",
+			"operation", verb+" "+kind,
+			"resource", resource,
+			"error", auditErr)
+	}
+	return err
+}
+
 // Service returns the underlying Search Console service for advanced usage
 func (c *Client) Service() *searchconsole.Service {
 	return c.service
@@ -195,62 +392,137 @@ func (c *Client) Context() context.Context {
 	return c.ctx
 }
 
-// useQuota atomically checks the daily quota and increments the counter if the
-// operation is allowed. Callers must call this once per billable API request;
-// they no longer need a separate increment call.
+// useQuota atomically checks the daily quota of site and increments the
+// counter if the operation is allowed. Callers must call this once per
+// billable API request; they no longer need a separate increment call.
 //
 // Returns an error if the critical threshold (95 %) has been reached, which
 // prevents the operation from proceeding. A warning is logged (but no error
 // returned) when the warning threshold (75 %) is crossed.
-func (c *Client) useQuota() error {
-	// Reset counter when the calendar day rolls over.
+func (c *Client) useQuota(site string) error {
+	return c.quotaTracker.use(c.logger, site)
+}
+
+// use is useQuota for an arbitrary tracker. key names the property the call
+// is charged to; "" for quotas that are not per property.
+func (q *QuotaTracker) use(logger *slog.Logger, key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.lastKey = key
+	return q.update(logger, key, func(count int) (int, error) {
+		// Block at critical threshold (95 %).
+		if count >= q.criticalThreshold {
+			logger.Error("daily quota critical threshold reached",
+				"unit", q.unit,
+				"property", key,
+				"count", count,
+				"limit", q.dailyLimit,
+				"threshold", q.criticalThreshold)
+			return count, fmt.Errorf("%w: %d/%d %s used (%.0f%%). Please wait until tomorrow to continue",
+				ErrQuotaExhausted,
+				count,
+				q.dailyLimit,
+				q.unit,
+				float64(count)/float64(q.dailyLimit)*100)
+		}
+
+		// Warn at warning threshold (75 %) but allow the operation.
+		if count >= q.warningThreshold {
+			logger.Warn("daily quota warning threshold reached",
+				"unit", q.unit,
+				"property", key,
+				"count", count,
+				"limit", q.dailyLimit,
+				"threshold", q.warningThreshold,
+				"remaining", q.dailyLimit-count)
+		}
+
+		// Increment immediately so every allowed call is counted regardless
+		// of whether the downstream API call succeeds or fails.
+		count++
+		logger.Debug("daily quota incremented",
+			"unit", q.unit,
+			"property", key,
+			"count", count,
+			"limit", q.dailyLimit,
+			"remaining", q.dailyLimit-count)
+		return count, nil
+	})
+}
+
+// update applies fn to today's count for key, in the quota file when there
+// is one. The caller holds q.mu.
+func (q *QuotaTracker) update(logger *slog.Logger, key string, fn func(count int) (int, error)) error {
 	now := time.Now()
-	if !isSameDay(c.quotaTracker.currentDate, now) {
-		c.logger.Info("resetting daily quota counter",
-			"previous_date", c.quotaTracker.currentDate.Format("2006-01-02"),
-			"new_date", now.Format("2006-01-02"),
-			"previous_count", c.quotaTracker.inspectionCount)
-		c.quotaTracker.currentDate = now
-		c.quotaTracker.inspectionCount = 0
+	if q.file != nil {
+		return q.file.update(now, q.unit, key, fn)
 	}
 
-	// Block at critical threshold (95 %).
-	if c.quotaTracker.inspectionCount >= c.quotaTracker.criticalThreshold {
-		c.logger.Error("daily quota critical threshold reached",
-			"count", c.quotaTracker.inspectionCount,
-			"limit", c.quotaTracker.dailyLimit,
-			"threshold", c.quotaTracker.criticalThreshold)
-		return fmt.Errorf("daily quota critical threshold reached: %d/%d inspections used (%.0f%%). Please wait until tomorrow to continue",
-			c.quotaTracker.inspectionCount,
-			c.quotaTracker.dailyLimit,
-			float64(c.quotaTracker.inspectionCount)/float64(c.quotaTracker.dailyLimit)*100)
+	// Reset counters when the calendar day rolls over.
+	if !isSameDay(q.currentDate, now) {
+		logger.Info("resetting daily quota counter",
+			"unit", q.unit,
+			"previous_date", q.currentDate.Format("2006-01-02"),
+			"new_date", now.Format("2006-01-02"),
+			"previous_count", q.counts)
+		q.currentDate = now
+		q.counts = map[string]int{}
 	}
+	count, err := fn(q.counts[key])
+	q.counts[key] = count
+	return err
+}
 
-	// Warn at warning threshold (75 %) but allow the operation.
-	if c.quotaTracker.inspectionCount >= c.quotaTracker.warningThreshold {
-		c.logger.Warn("daily quota warning threshold reached",
-			"count", c.quotaTracker.inspectionCount,
-			"limit", c.quotaTracker.dailyLimit,
-			"threshold", c.quotaTracker.warningThreshold,
-			"remaining", c.quotaTracker.dailyLimit-c.quotaTracker.inspectionCount)
+// used returns today's count for the property of the latest call or, before
+// any call, for the busiest property today: the conservative figure for
+// budgeting work whose property is not known yet. The caller holds q.mu.
+func (q *QuotaTracker) used() int {
+	now := time.Now()
+	counts := q.counts
+	if q.file != nil {
+		var err error
+		if counts, err = q.file.read(now, q.unit); err != nil {
+			// An unreadable file is reported by the next use; until then the
+			// counts of this process are the best estimate.
+			counts = q.counts
+		}
+	} else if !isSameDay(q.currentDate, now) {
+		return 0
+	}
+	if count, ok := counts[q.lastKey]; ok || q.lastKey != "" {
+		return count
+	}
+	busiest := 0
+	for _, count := range counts {
+		busiest = max(busiest, count)
 	}
+	return busiest
+}
 
-	// Increment immediately so every allowed call is counted regardless of
-	// whether the downstream API call succeeds or fails.
-	c.quotaTracker.inspectionCount++
-	c.logger.Debug("daily quota incremented",
-		"count", c.quotaTracker.inspectionCount,
-		"limit", c.quotaTracker.dailyLimit,
-		"remaining", c.quotaTracker.dailyLimit-c.quotaTracker.inspectionCount)
+// status returns the tracker's usage for today.
+func (q *QuotaTracker) status() (used int, limit int, date string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used(), q.dailyLimit, time.Now().Format("2006-01-02")
+}
 
-	return nil
+// remaining returns how many more calls use allows today.
+func (q *QuotaTracker) remaining() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return max(q.criticalThreshold-q.used(), 0)
+}
+
+// RemainingQuota returns how many more quota-tracked calls (inspections and
+// search analytics pages) the client will make today before refusing.
+func (c *Client) RemainingQuota() int {
+	return c.quotaTracker.remaining()
 }
 
 // GetQuotaStatus returns the current quota usage status
 func (c *Client) GetQuotaStatus() (used int, limit int, date string) {
-	return c.quotaTracker.inspectionCount,
-		c.quotaTracker.dailyLimit,
-		c.quotaTracker.currentDate.Format("2006-01-02")
+	return c.quotaTracker.status()
 }
 
 // isSameDay checks if two times are on the same calendar day (ignoring time)
@@ -0,0 +1,77 @@
+package gsc
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/auditlog"
+)
+
+// roundTripFunc answers API requests in tests.
+type roundTripFunc func(*http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r), nil }
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// Adding a site and publishing a URL notification change Search Console, so
+// both are recorded in the audit log like sitemap submissions.
+func TestChanges_RecordAuditEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), auditlog.FileName)
+	auditlog.SetDefault(auditlog.New(path, auditlog.WithActor(func() string { return "ci@example.iam.gserviceaccount.com" })))
+	t.Cleanup(func() { auditlog.SetDefault(nil) })
+
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) *http.Response {
+		switch {
+		case r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/sites/"):
+			return jsonResponse(http.StatusNoContent, "")
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/sites/"):
+			return jsonResponse(http.StatusOK, `{"siteUrl":"https://example.com/","permissionLevel":"siteUnverifiedUser"}`)
+		case strings.Contains(r.URL.Path, "urlNotifications:publish"):
+			return jsonResponse(http.StatusForbidden, `{"error":{"code":403,"message":"Permission denied. Failed to verify the URL ownership."}}`)
+		}
+		return jsonResponse(http.StatusNotFound, `{}`)
+	})}
+	client, err := NewClient(
+		WithHTTPClient(hc),
+		WithQuotaFile(""),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	_, err = client.AddSite("https://example.com/")
+	require.NoError(t, err)
+	_, err = client.PublishURLNotification("https://example.com/jobs/1", false)
+	require.Error(t, err)
+
+	entries, err := auditlog.Read(path, auditlog.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "the permission read after adding is not recorded")
+
+	assert.Equal(t, "create", entries[0].Operation)
+	assert.Equal(t, "site", entries[0].ResourceType)
+	assert.Equal(t, "https://example.com/", entries[0].Resource)
+	assert.Equal(t, "https://example.com/", entries[0].Property)
+	assert.Equal(t, auditlog.ResultOK, entries[0].Result)
+	assert.Equal(t, "ci@example.iam.gserviceaccount.com", entries[0].Actor)
+
+	assert.Equal(t, "publish", entries[1].Operation)
+	assert.Equal(t, "url notification", entries[1].ResourceType)
+	assert.Equal(t, "https://example.com/jobs/1", entries[1].Resource)
+	assert.Equal(t, auditlog.ResultError, entries[1].Result)
+	assert.Contains(t, entries[1].Error, "Permission denied")
+}
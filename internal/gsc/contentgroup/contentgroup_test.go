@@ -0,0 +1,62 @@
+package contentgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	g, err := New([]Rule{
+		{Name: "Guides", Prefix: "/blog/guides/"},
+		{Name: "Blog", Prefix: "/blog/"},
+		{Name: "Tools", Pattern: `^/(tools|calculators)/`},
+	})
+	require.NoError(t, err)
+	for page, want := range map[string]string{
+		"https://example.com/blog/guides/tents": "Guides",
+		"https://example.com/blog/news?page=2":  "Blog",
+		"https://example.com/calculators/bmi":   "Tools",
+		"https://example.com/tools/":            "Tools",
+		"/blog/":                                "Blog",
+		"https://example.com":                   Other,
+		"https://example.com/pricing":           Other,
+	} {
+		assert.Equal(t, want, g.Group(page), page)
+	}
+	assert.Equal(t, Other, (&Grouper{}).Group("/blog/"))
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	for name, rule := range map[string]Rule{
+		"no name":     {Prefix: "/blog/"},
+		"no matcher":  {Name: "Blog"},
+		"both":        {Name: "Blog", Prefix: "/blog/", Pattern: "^/blog/"},
+		"bad pattern": {Name: "Blog", Pattern: "(?<=x)"},
+	} {
+		_, err := New([]Rule{rule})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	g, err := New([]Rule{{Name: "Tools", Prefix: "/tools/"}, {Name: "Blog", Prefix: "/blog/"}, {Name: "Docs", Prefix: "/docs/"}})
+	require.NoError(t, err)
+	got := g.Summarise([]Row{
+		{Page: "https://example.com/", Clicks: 10, Impressions: 50, Position: 1},
+		{Page: "https://example.com/blog/a", Clicks: 20, Impressions: 100, Position: 4},
+		{Page: "https://example.com/blog/b", Clicks: 10, Impressions: 300, Position: 12},
+		{Page: "https://example.com/tools/x", Clicks: 60, Impressions: 100, Position: 2},
+	})
+	require.Len(t, got, 3, "Docs has no pages")
+	assert.Equal(t, []string{"Tools", "Blog", Other}, []string{got[0].Group, got[1].Group, got[2].Group})
+
+	blog := got[1]
+	assert.Equal(t, 2, blog.Pages)
+	assert.Equal(t, int64(30), blog.Clicks)
+	assert.Equal(t, int64(400), blog.Impressions)
+	assert.InDelta(t, 0.075, blog.CTR, 1e-9)
+	assert.InDelta(t, 10, blog.Position, 1e-9)
+	assert.InDelta(t, 0.3, blog.ClickShare, 1e-9)
+}
@@ -62,12 +62,17 @@ type PageCoverage struct {
 // GetIndexCoverageReport generates an index coverage report by querying Search Analytics
 // This provides an estimate of indexed pages based on search performance data
 func (c *Client) GetIndexCoverageReport(siteURL string, days int) (*IndexCoverageReport, error) {
+	startDate, endDate := BuildDateRange(days)
+	return c.GetIndexCoverageReportRange(siteURL, startDate, endDate)
+}
+
+// GetIndexCoverageReportRange is GetIndexCoverageReport for an explicit date
+// range (YYYY-MM-DD, both inclusive)
+func (c *Client) GetIndexCoverageReportRange(siteURL, startDate, endDate string) (*IndexCoverageReport, error) {
 	c.logger.Info("generating index coverage report",
 		"site_url", siteURL,
-		"days", days)
-
-	// Build date range
-	startDate, endDate := BuildDateRange(days)
+		"start_date", startDate,
+		"end_date", endDate)
 
 	// Query Search Analytics with page dimension to get all pages with search data
 	query := &SearchAnalyticsQuery{
@@ -103,7 +108,14 @@ func (c *Client) GetIndexCoverageReportFiltered(siteURL string, days int, status
 	if err != nil {
 		return nil, err
 	}
+	FilterCoverageReport(coverage, status, topIssuesLimit)
+	return coverage, nil
+}
 
+// FilterCoverageReport narrows a report in place to pages with the given
+// status ("all" or "" keeps every page) and at most topIssuesLimit issues
+// (0 keeps all).
+func FilterCoverageReport(coverage *IndexCoverageReport, status string, topIssuesLimit int) {
 	// Filter pages by status if not "all"
 	if status != "all" && status != "" {
 		filteredPages := make([]PageCoverage, 0)
@@ -119,8 +131,6 @@ func (c *Client) GetIndexCoverageReportFiltered(siteURL string, days int, status
 	if topIssuesLimit > 0 && len(coverage.TopIssues) > topIssuesLimit {
 		coverage.TopIssues = coverage.TopIssues[:topIssuesLimit]
 	}
-
-	return coverage, nil
 }
 
 // transformToCoverageReport converts a Search Analytics report into a coverage report
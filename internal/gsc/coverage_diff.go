@@ -0,0 +1,82 @@
+package gsc
+
+import "sort"
+
+// CoverageChange is a page whose estimated coverage status differs between two
+// coverage reports.
+type CoverageChange struct {
+	URL                 string `json:"url"`
+	PreviousStatus      string `json:"previous_status"`
+	CurrentStatus       string `json:"current_status"`
+	PreviousImpressions int64  `json:"previous_impressions"`
+	CurrentImpressions  int64  `json:"current_impressions"`
+}
+
+// CoverageDiff compares the per-page coverage of two runs. Appeared and
+// Disappeared pages keep the PageCoverage keys of the report they come from.
+type CoverageDiff struct {
+	Regressed   []CoverageChange `json:"regressed"`   // indexed → low_impressions / no_impressions
+	Recovered   []CoverageChange `json:"recovered"`   // low_impressions / no_impressions → indexed
+	Appeared    []PageCoverage   `json:"appeared"`    // pages only in the current run
+	Disappeared []PageCoverage   `json:"disappeared"` // pages only in the previous run
+	// Partial is set when either run's page sample was truncated, so pages
+	// outside the sample may be misreported as appeared or disappeared.
+	Partial bool `json:"partial"`
+}
+
+// HasRegressions reports whether anything got worse: a page dropped out of the
+// indexed band or vanished from Search Analytics entirely.
+func (d *CoverageDiff) HasRegressions() bool {
+	return len(d.Regressed) > 0 || len(d.Disappeared) > 0
+}
+
+// CompareCoverage diffs the page samples of a previous and current coverage
+// report. Both reports must be unfiltered (state "all") or pages hidden by
+// the filter show up as disappeared. Results are sorted by URL.
+func CompareCoverage(previous, current *IndexCoverageReport) *CoverageDiff {
+	diff := &CoverageDiff{
+		Regressed:   []CoverageChange{},
+		Recovered:   []CoverageChange{},
+		Appeared:    []PageCoverage{},
+		Disappeared: []PageCoverage{},
+		Partial:     previous.TotalPages > len(previous.PagesSample) || current.TotalPages > len(current.PagesSample),
+	}
+
+	before := make(map[string]PageCoverage, len(previous.PagesSample))
+	for _, p := range previous.PagesSample {
+		before[p.URL] = p
+	}
+	seen := make(map[string]bool, len(current.PagesSample))
+	for _, cur := range current.PagesSample {
+		seen[cur.URL] = true
+		prev, ok := before[cur.URL]
+		if !ok {
+			diff.Appeared = append(diff.Appeared, cur)
+			continue
+		}
+		change := CoverageChange{
+			URL:                 cur.URL,
+			PreviousStatus:      prev.Status,
+			CurrentStatus:       cur.Status,
+			PreviousImpressions: prev.Impressions,
+			CurrentImpressions:  cur.Impressions,
+		}
+		switch {
+		case prev.Status == "indexed" && cur.Status != "indexed":
+			diff.Regressed = append(diff.Regressed, change)
+		case prev.Status != "indexed" && cur.Status == "indexed":
+			diff.Recovered = append(diff.Recovered, change)
+		}
+	}
+	for _, p := range previous.PagesSample {
+		if !seen[p.URL] {
+			diff.Disappeared = append(diff.Disappeared, p)
+		}
+	}
+
+	sort.Slice(diff.Regressed, func(i, j int) bool { return diff.Regressed[i].URL < diff.Regressed[j].URL })
+	sort.Slice(diff.Recovered, func(i, j int) bool { return diff.Recovered[i].URL < diff.Recovered[j].URL })
+	sort.Slice(diff.Appeared, func(i, j int) bool { return diff.Appeared[i].URL < diff.Appeared[j].URL })
+	sort.Slice(diff.Disappeared, func(i, j int) bool { return diff.Disappeared[i].URL < diff.Disappeared[j].URL })
+	return diff
+}
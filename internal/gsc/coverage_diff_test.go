@@ -0,0 +1,73 @@
+package gsc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCoverage(t *testing.T) {
+	previous := &IndexCoverageReport{TotalPages: 4, PagesSample: []PageCoverage{
+		{URL: "/stable", Status: "indexed", Impressions: 100},
+		{URL: "/dropped", Status: "indexed", Impressions: 80},
+		{URL: "/back", Status: "low_impressions", Impressions: 3},
+		{URL: "/gone", Status: "indexed", Impressions: 40},
+	}}
+	current := &IndexCoverageReport{TotalPages: 4, PagesSample: []PageCoverage{
+		{URL: "/stable", Status: "indexed", Impressions: 120},
+		{URL: "/dropped", Status: "low_impressions", Impressions: 2},
+		{URL: "/back", Status: "indexed", Impressions: 50},
+		{URL: "/new", Status: "low_impressions", Impressions: 1},
+	}}
+
+	diff := CompareCoverage(previous, current)
+
+	require.Len(t, diff.Regressed, 1)
+	assert.Equal(t, CoverageChange{URL: "/dropped", PreviousStatus: "indexed", CurrentStatus: "low_impressions", PreviousImpressions: 80, CurrentImpressions: 2}, diff.Regressed[0])
+	require.Len(t, diff.Recovered, 1)
+	assert.Equal(t, "/back", diff.Recovered[0].URL)
+	require.Len(t, diff.Appeared, 1)
+	assert.Equal(t, "/new", diff.Appeared[0].URL)
+	require.Len(t, diff.Disappeared, 1)
+	assert.Equal(t, "/gone", diff.Disappeared[0].URL)
+	assert.True(t, diff.HasRegressions())
+	assert.False(t, diff.Partial)
+}
+
+func TestCompareCoverage_NoChangeAndPartial(t *testing.T) {
+	pages := []PageCoverage{{URL: "/a", Status: "indexed", Impressions: 10}}
+	diff := CompareCoverage(&IndexCoverageReport{TotalPages: 1, PagesSample: pages}, &IndexCoverageReport{TotalPages: 5000, PagesSample: pages})
+
+	assert.False(t, diff.HasRegressions())
+	assert.True(t, diff.Partial, "current sample is truncated")
+	assert.NotNil(t, diff.Appeared, "empty slices, not null, in JSON")
+}
+
+func TestFilterCoverageReport(t *testing.T) {
+	report := &IndexCoverageReport{
+		PagesSample: []PageCoverage{{URL: "/a", Status: "indexed"}, {URL: "/b", Status: "low_impressions"}},
+		TopIssues:   []IssueCount{{Issue: "Indexed"}, {Issue: "Low impressions (< 10)"}},
+	}
+	FilterCoverageReport(report, "low_impressions", 1)
+	require.Len(t, report.PagesSample, 1)
+	assert.Equal(t, "/b", report.PagesSample[0].URL)
+	assert.Len(t, report.TopIssues, 1)
+}
+
+func TestCoverageDiff_JSONKeys(t *testing.T) {
+	diff := &CoverageDiff{
+		Regressed: []CoverageChange{{URL: "/dropped", PreviousStatus: "indexed", CurrentStatus: "low_impressions", PreviousImpressions: 80, CurrentImpressions: 2}},
+		Partial:   true,
+	}
+	data, err := json.Marshal(diff)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"regressed": [{"url": "/dropped", "previous_status": "indexed", "current_status": "low_impressions", "previous_impressions": 80, "current_impressions": 2}],
+		"recovered": null,
+		"appeared": null,
+		"disappeared": null,
+		"partial": true
+	}`, string(data))
+}
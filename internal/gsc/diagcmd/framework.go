@@ -2,7 +2,7 @@
 //
 // Every command under `ga4 gsc <diagnostic>` follows the same framework shape:
 // a JSON envelope with {command, site, generated_at, results, quota_used};
-// --format table|json; exit codes 0 (clean) / 2 (issues detected) / 1 (failure);
+// --format table|json; exit codes 0 (clean) / 4 (issues detected) / 1–3 (failure);
 // "silent on all-green" stdout aside from the quota footer. This package owns
 // those concerns so per-command code is reduced to the predicate-specific
 // glue: build the query, apply the predicate, supply the text-row renderer.
@@ -12,44 +12,49 @@
 package diagcmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	"github.com/garbarok/ga4-manager/internal/config"
-	"github.com/garbarok/ga4-manager/internal/render"
+	"github.com/garbarok/ga4-manager/internal/exitcode"
+	"github.com/garbarok/ga4-manager/internal/markers"
+	"github.com/garbarok/ga4-manager/internal/output"
 )
 
 // Output format names, exported so command files reference these instead of
 // duplicating string literals. The diagnostic CLI accepts the table format
-// (human-readable, tab-aligned) and json (structured envelope for MCP /
+// (human-readable, column-aligned) and json (structured envelope for MCP /
 // scripts). Other rendering formats (csv, markdown) are available via the
-// underlying internal/render module but not exposed on diagnostic commands —
+// underlying internal/output package but not exposed on diagnostic commands —
 // the JSON envelope is the canonical machine-readable shape here.
 const (
-	FormatTable = render.FormatTable
-	FormatJSON  = "json"
+	FormatTable = output.FormatTable
+	FormatJSON  = output.FormatJSON
 )
 
-// Framework exit codes. The CLI convention is fixed: anything that crosses
-// these values is a framework change, not a per-command decision.
+// Framework exit codes, the diagnostic subset of the CLI's exit code
+// contract (see internal/exitcode). Failures are refined by exitcode.Of:
+// an API error exits 2 and an exhausted quota 3.
 const (
-	ExitClean   = 0
-	ExitFailure = 1
-	ExitIssues  = 2
+	ExitClean   = exitcode.OK
+	ExitFailure = exitcode.Validation
+	ExitIssues  = exitcode.Alerts
 )
 
 // Envelope is the JSON output shape every diagnostic command emits under
 // --format json and that the matching MCP tool consumes. The Results slice
 // is typed: each command supplies its own row type as the type parameter.
+// Commands comparing periods set Markers to the site's markers (see
+// `ga4 marker`) falling in them.
 type Envelope[T any] struct {
-	Command     string `json:"command"`
-	Site        string `json:"site"`
-	GeneratedAt string `json:"generated_at"`
-	Results     []T    `json:"results"`
-	QuotaUsed   int    `json:"quota_used"`
+	Command     string           `json:"command"`
+	Site        string           `json:"site"`
+	GeneratedAt string           `json:"generated_at"`
+	Results     []T              `json:"results"`
+	Markers     []markers.Marker `json:"markers,omitempty"`
+	QuotaUsed   int              `json:"quota_used"`
 }
 
 // NewEnvelope returns an envelope with GeneratedAt formatted as RFC3339 and
@@ -73,29 +78,33 @@ func NewEnvelope[T any](command, site string, now time.Time, results []T, quotaU
 //
 // In table mode, an empty Results slice prints only the framework's
 // `quota used: N` footer (the "silent on all-green" convention). When
-// Results is non-empty, the table is rendered via internal/render and the
-// final footer line `quota used: N` is appended.
+// Results is non-empty, the table is rendered via internal/output, followed
+// by a `marker:` line per marker and the final footer line `quota used: N`.
 func Render[T any](w io.Writer, env Envelope[T], format string, columns []string, rowFn func(T) []string) error {
 	if format == FormatJSON {
-		enc := json.NewEncoder(w)
-		enc.SetIndent("", "  ")
-		return enc.Encode(env)
+		return output.JSON(w, env)
 	}
 
 	if len(env.Results) > 0 {
-		if err := render.Render(w, render.FormatTable, columns, env.Results, rowFn); err != nil {
+		if err := output.Render(w, output.FormatTable, columns, env.Results, rowFn); err != nil {
 			return err
 		}
+		for _, m := range env.Markers {
+			if _, err := fmt.Fprintf(w, "marker: %s\n", m); err != nil {
+				return err
+			}
+		}
 	}
 	_, err := fmt.Fprintf(w, "quota used: %d\n", env.QuotaUsed)
 	return err
 }
 
-// ExitCode maps an outcome to the framework exit convention: any error → 1,
-// otherwise non-empty results → 2 (issues detected) and empty → 0 (clean).
+// ExitCode maps an outcome to the framework exit convention: an error → its
+// exitcode.Of code, otherwise non-empty results → 4 (issues detected) and
+// empty → 0 (clean).
 func ExitCode(err error, hasResults bool) int {
 	if err != nil {
-		return ExitFailure
+		return exitcode.Of(err)
 	}
 	if hasResults {
 		return ExitIssues
@@ -104,12 +113,18 @@ func ExitCode(err error, hasResults bool) int {
 }
 
 // FailWith writes a printf-formatted message followed by a newline to w and
-// returns ExitFailure. Per-command runners use this as the one-liner for any
-// path that needs to surface an error and exit 1. The write error is
-// intentionally ignored — there is no meaningful recovery if stderr itself
-// cannot be written to.
+// returns the exit code of the first error among args (exitcode.Of), or
+// ExitFailure when there is none. Per-command runners use this as the
+// one-liner for any path that needs to surface an error and exit. The write
+// error is intentionally ignored — there is no meaningful recovery if stderr
+// itself cannot be written to.
 func FailWith(w io.Writer, format string, args ...any) int {
 	_, _ = fmt.Fprintf(w, format+"\n", args...)
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			return exitcode.Of(err)
+		}
+	}
 	return ExitFailure
 }
 
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"testing"
 	"time"
+
+	"github.com/garbarok/ga4-manager/internal/markers"
 )
 
 type row struct {
@@ -89,6 +91,31 @@ func TestRenderTextEmptyResultsPrintsOnlyFooter(t *testing.T) {
 	}
 }
 
+func TestRenderTextListsMarkersAboveFooter(t *testing.T) {
+	env := sampleEnvelope()
+	env.Markers = []markers.Marker{{ID: "3f9a1c02", Date: "2026-05-20", Label: "site migration"}}
+	var buf bytes.Buffer
+	err := Render(&buf, env, FormatTable,
+		[]string{"name", "score"},
+		func(r row) []string { return []string{r.Name, strconv.Itoa(r.Score)} },
+	)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "marker: 2026-05-20 site migration\nquota used: 3\n") {
+		t.Errorf("marker line missing or misplaced: %q", buf.String())
+	}
+
+	env.Results = []row{}
+	buf.Reset()
+	if err := Render(&buf, env, FormatTable, nil, nil); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if buf.String() != "quota used: 3\n" {
+		t.Errorf("markers printed without results: %q", buf.String())
+	}
+}
+
 func TestExitCodeMapping(t *testing.T) {
 	cases := []struct {
 		err        error
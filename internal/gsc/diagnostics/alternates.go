@@ -0,0 +1,250 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// Coverage state of a page Google treats as an alternate of the canonical it
+// declares. The URL Inspection API reports the human-readable label; the
+// enum spelling is accepted too.
+const (
+	alternateCoverageState     = "Alternate page with proper canonical tag"
+	alternateCoverageStateEnum = "ALTERNATE_PAGE_WITH_PROPER_CANONICAL_TAG"
+	alternateIssueType         = "ALTERNATE_CANONICAL"
+)
+
+// Kinds of expected alternate.
+const (
+	AlternateLanguage = "language"
+	AlternateMobile   = "mobile"
+	AlternateAMP      = "amp"
+	AlternateOther    = "other"
+)
+
+// AlternateKinds lists the valid ExpectedAlternate kinds.
+var AlternateKinds = []string{AlternateLanguage, AlternateMobile, AlternateAMP, AlternateOther}
+
+// IsAlternate reports whether r is an alternate page with a proper canonical
+// tag.
+func IsAlternate(r gsc.URLInspectionResult) bool {
+	return strings.EqualFold(r.CoverageState, alternateCoverageState) || r.CoverageState == alternateCoverageStateEnum
+}
+
+// ExpectedAlternate declares URLs that are meant to be alternates, such as
+// m-dot pages, AMP copies or language versions canonicalised to a main one.
+// Pattern is matched against the full URL when it contains "://" and
+// against the path otherwise; "*" matches any run of characters.
+type ExpectedAlternate struct {
+	Pattern string
+	Kind    string
+}
+
+// Matches reports whether rawURL falls under the pattern.
+func (e ExpectedAlternate) Matches(rawURL string) bool {
+	return matchURLPattern(e.Pattern, rawURL)
+}
+
+// matchURLPattern matches rawURL against pattern: the full URL when pattern
+// contains "://", the path (and query) otherwise. "*" matches any run of
+// characters.
+func matchURLPattern(pattern, rawURL string) bool {
+	subject := rawURL
+	if !strings.Contains(pattern, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return false
+		}
+		subject = u.EscapedPath()
+		if u.RawQuery != "" {
+			subject += "?" + u.RawQuery
+		}
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(subject)
+}
+
+// matchExpected returns the first rule matching rawURL.
+func matchExpected(rawURL string, expected []ExpectedAlternate) (ExpectedAlternate, bool) {
+	for _, e := range expected {
+		if e.Matches(rawURL) {
+			return e, true
+		}
+	}
+	return ExpectedAlternate{}, false
+}
+
+// SuppressExpectedAlternates drops the alternate-canonical warning from the
+// results of URLs covered by an expected rule. Results are modified in place.
+func SuppressExpectedAlternates(results []gsc.URLInspectionResult, expected []ExpectedAlternate) {
+	for i := range results {
+		if _, ok := matchExpected(results[i].URL, expected); !ok {
+			continue
+		}
+		kept := results[i].IndexingIssues[:0]
+		for _, issue := range results[i].IndexingIssues {
+			if issue.IssueType != alternateIssueType {
+				kept = append(kept, issue)
+			}
+		}
+		results[i].IndexingIssues = kept
+	}
+}
+
+// PageTraffic is one page's Search Analytics totals.
+type PageTraffic struct {
+	Clicks      int64 `json:"clicks"`
+	Impressions int64 `json:"impressions"`
+}
+
+// Alternate is one inspected alternate URL within an AlternateGroup.
+type Alternate struct {
+	URL string `json:"url"`
+	// Kind is the kind of the expected rule the URL matched; empty when
+	// unexpected.
+	Kind     string `json:"kind,omitempty"`
+	Expected bool   `json:"expected"`
+	PageTraffic
+}
+
+// AlternateGroup is a canonical URL with the inspected alternates that point
+// at it, and whatever is wrong with the pair.
+type AlternateGroup struct {
+	Canonical string `json:"canonical"`
+	// CanonicalInspected is false when no inspection of the canonical was
+	// supplied; CanonicalIndexed and CanonicalCoverage are then unknown.
+	CanonicalInspected bool   `json:"canonical_inspected"`
+	CanonicalIndexed   bool   `json:"canonical_indexed"`
+	CanonicalCoverage  string `json:"canonical_coverage_state,omitempty"`
+	PageTraffic
+	Alternates []Alternate `json:"alternates"`
+	Problems   []string    `json:"problems,omitempty"`
+}
+
+// OK reports whether the group has nothing to act on.
+func (g AlternateGroup) OK() bool { return len(g.Problems) == 0 }
+
+// canonicalOf returns the canonical an alternate defers to: Google's choice,
+// else the declared one.
+func canonicalOf(r gsc.URLInspectionResult) string {
+	if r.GoogleCanonical != "" {
+		return r.GoogleCanonical
+	}
+	return r.UserCanonical
+}
+
+// UninspectedCanonicals returns the canonicals of the alternates in results
+// that have no inspection result of their own, in first-seen order.
+func UninspectedCanonicals(results []gsc.URLInspectionResult) []string {
+	inspected := make(map[string]bool, len(results))
+	for _, r := range results {
+		inspected[r.URL] = true
+	}
+	var out []string
+	for _, r := range results {
+		c := canonicalOf(r)
+		if !IsAlternate(r) || c == "" || inspected[c] {
+			continue
+		}
+		inspected[c] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// AlternateGroups groups the alternate pages in results under their
+// canonical and checks each canonical is indexed and takes the traffic:
+// it must have impressions, and at least as many as its alternates
+// together. Alternates matching no expected rule are reported too.
+//
+// traffic is keyed by page URL; pages missing from it had no impressions.
+// Groups with problems come first, then by alternates' impressions
+// descending and canonical URL.
+func AlternateGroups(results []gsc.URLInspectionResult, expected []ExpectedAlternate, traffic map[string]PageTraffic) []AlternateGroup {
+	byURL := make(map[string]gsc.URLInspectionResult, len(results))
+	for _, r := range results {
+		byURL[r.URL] = r
+	}
+
+	groups := make(map[string]*AlternateGroup)
+	var order []string
+	for _, r := range results {
+		c := canonicalOf(r)
+		if !IsAlternate(r) || c == "" {
+			continue
+		}
+		g, ok := groups[c]
+		if !ok {
+			g = &AlternateGroup{Canonical: c, PageTraffic: traffic[c]}
+			if cr, ok := byURL[c]; ok {
+				g.CanonicalInspected = true
+				g.CanonicalIndexed = cr.IndexStatus == "PASS"
+				g.CanonicalCoverage = cr.CoverageState
+			}
+			groups[c] = g
+			order = append(order, c)
+		}
+		alt := Alternate{URL: r.URL, PageTraffic: traffic[r.URL]}
+		if e, ok := matchExpected(r.URL, expected); ok {
+			alt.Expected, alt.Kind = true, e.Kind
+		}
+		g.Alternates = append(g.Alternates, alt)
+	}
+
+	out := make([]AlternateGroup, 0, len(order))
+	for _, c := range order {
+		g := groups[c]
+		g.Problems = alternateProblems(g)
+		out = append(out, *g)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].OK() != out[j].OK() {
+			return !out[i].OK()
+		}
+		ai, aj := out[i].alternateImpressions(), out[j].alternateImpressions()
+		if ai != aj {
+			return ai > aj
+		}
+		return out[i].Canonical < out[j].Canonical
+	})
+	return out
+}
+
+func (g *AlternateGroup) alternateImpressions() int64 {
+	var n int64
+	for _, a := range g.Alternates {
+		n += a.Impressions
+	}
+	return n
+}
+
+func alternateProblems(g *AlternateGroup) []string {
+	var problems []string
+	switch {
+	case !g.CanonicalInspected:
+		problems = append(problems, "canonical was not inspected")
+	case !g.CanonicalIndexed:
+		state := g.CanonicalCoverage
+		if state == "" {
+			state = "unknown"
+		}
+		problems = append(problems, fmt.Sprintf("canonical is not indexed (%s)", state))
+	}
+	if alt := g.alternateImpressions(); g.Impressions == 0 {
+		problems = append(problems, "canonical has no impressions")
+	} else if alt > g.Impressions {
+		problems = append(problems, fmt.Sprintf("alternates have more impressions than the canonical (%d vs %d)", alt, g.Impressions))
+	}
+	for _, a := range g.Alternates {
+		if !a.Expected {
+			problems = append(problems, fmt.Sprintf("unexpected alternate %s", a.URL))
+		}
+	}
+	return problems
+}
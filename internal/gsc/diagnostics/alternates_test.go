@@ -0,0 +1,116 @@
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func alternateResult(url, canonical string) gsc.URLInspectionResult {
+	return gsc.URLInspectionResult{
+		URL:             url,
+		IndexStatus:     "NEUTRAL",
+		CoverageState:   "Alternate page with proper canonical tag",
+		GoogleCanonical: canonical,
+		UserCanonical:   canonical,
+		IndexingIssues:  []gsc.IndexingIssue{{Severity: "WARNING", IssueType: "ALTERNATE_CANONICAL"}},
+	}
+}
+
+func TestExpectedAlternateMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"/amp/*", "https://example.com/amp/post", true},
+		{"/amp/*", "https://example.com/blog/amp/post", false},
+		{"*/amp", "https://example.com/blog/post/amp", true},
+		{"https://m.example.com/*", "https://m.example.com/post", true},
+		{"https://m.example.com/*", "https://example.com/post", false},
+		{"/post?amp=1", "https://example.com/post?amp=1", true},
+		{"/es/*", "https://example.com/es", false},
+	}
+	for _, tt := range tests {
+		if got := (ExpectedAlternate{Pattern: tt.pattern}).Matches(tt.url); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSuppressExpectedAlternates(t *testing.T) {
+	results := []gsc.URLInspectionResult{
+		alternateResult("https://example.com/amp/a", "https://example.com/a"),
+		alternateResult("https://example.com/b-copy", "https://example.com/b"),
+	}
+	SuppressExpectedAlternates(results, []ExpectedAlternate{{Pattern: "/amp/*", Kind: AlternateAMP}})
+	if len(results[0].IndexingIssues) != 0 {
+		t.Errorf("expected alternate kept issues %v", results[0].IndexingIssues)
+	}
+	if len(results[1].IndexingIssues) != 1 {
+		t.Errorf("unexpected alternate lost its warning")
+	}
+}
+
+func TestAlternateGroups(t *testing.T) {
+	indexed := func(url string) gsc.URLInspectionResult {
+		return gsc.URLInspectionResult{URL: url, IndexStatus: "PASS", CoverageState: "Submitted and indexed"}
+	}
+	results := []gsc.URLInspectionResult{
+		alternateResult("https://example.com/amp/a", "https://example.com/a"),
+		alternateResult("https://m.example.com/a", "https://example.com/a"),
+		indexed("https://example.com/a"),
+		alternateResult("https://example.com/amp/b", "https://example.com/b"),
+		{URL: "https://example.com/b", IndexStatus: "NEUTRAL", CoverageState: "Crawled - currently not indexed"},
+		alternateResult("https://example.com/c-old", "https://example.com/c"),
+	}
+	expected := []ExpectedAlternate{
+		{Pattern: "/amp/*", Kind: AlternateAMP},
+		{Pattern: "https://m.example.com/*", Kind: AlternateMobile},
+	}
+	traffic := map[string]PageTraffic{
+		"https://example.com/a":     {Clicks: 40, Impressions: 900},
+		"https://example.com/amp/a": {Clicks: 5, Impressions: 100},
+		"https://example.com/b":     {Clicks: 1, Impressions: 10},
+		"https://example.com/amp/b": {Clicks: 3, Impressions: 50},
+	}
+
+	if got, want := UninspectedCanonicals(results), []string{"https://example.com/c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("UninspectedCanonicals = %v, want %v", got, want)
+	}
+
+	groups := AlternateGroups(results, expected, traffic)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3", len(groups))
+	}
+
+	b, c, a := groups[0], groups[1], groups[2]
+	if b.Canonical != "https://example.com/b" || c.Canonical != "https://example.com/c" || a.Canonical != "https://example.com/a" {
+		t.Fatalf("order = %s, %s, %s", b.Canonical, c.Canonical, a.Canonical)
+	}
+
+	if !a.OK() {
+		t.Errorf("healthy group has problems: %v", a.Problems)
+	}
+	if len(a.Alternates) != 2 || a.Alternates[1].Kind != AlternateMobile || !a.Alternates[1].Expected {
+		t.Errorf("group a alternates = %+v", a.Alternates)
+	}
+
+	wantB := []string{
+		"canonical is not indexed (Crawled - currently not indexed)",
+		"alternates have more impressions than the canonical (50 vs 10)",
+	}
+	if !reflect.DeepEqual(b.Problems, wantB) {
+		t.Errorf("group b problems = %v, want %v", b.Problems, wantB)
+	}
+
+	wantC := []string{
+		"canonical was not inspected",
+		"canonical has no impressions",
+		"unexpected alternate https://example.com/c-old",
+	}
+	if !reflect.DeepEqual(c.Problems, wantC) {
+		t.Errorf("group c problems = %v, want %v", c.Problems, wantC)
+	}
+}
@@ -0,0 +1,117 @@
+package diagnostics
+
+import (
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// Kinds of canonical mismatch.
+const (
+	// CanonicalOverridden: the page declares a canonical and Google picked
+	// another one.
+	CanonicalOverridden = "overridden"
+	// CanonicalUndeclared: the page declares no canonical and Google
+	// canonicalised it to another URL, i.e. treats it as a duplicate.
+	CanonicalUndeclared = "undeclared"
+)
+
+// CanonicalMismatch is an inspected URL whose Google-selected canonical is not
+// the one the site intended. A page that declares another URL as canonical
+// and has Google agree is an intended alternate, not a mismatch.
+type CanonicalMismatch struct {
+	URL             string `json:"url"`
+	Kind            string `json:"kind"`
+	UserCanonical   string `json:"user_canonical,omitempty"`
+	GoogleCanonical string `json:"google_canonical"`
+	IndexStatus     string `json:"index_status"`
+	CoverageState   string `json:"coverage_state,omitempty"`
+	PageTraffic
+}
+
+// CanonicalMismatchOf classifies r, reporting false when Google's canonical
+// is the intended one or unknown.
+func CanonicalMismatchOf(r gsc.URLInspectionResult) (CanonicalMismatch, bool) {
+	m := CanonicalMismatch{
+		URL:             r.URL,
+		UserCanonical:   r.UserCanonical,
+		GoogleCanonical: r.GoogleCanonical,
+		IndexStatus:     r.IndexStatus,
+		CoverageState:   r.CoverageState,
+	}
+	switch {
+	case r.GoogleCanonical == "":
+		return m, false
+	case r.UserCanonical != "":
+		if r.GoogleCanonical == r.UserCanonical {
+			return m, false
+		}
+		m.Kind = CanonicalOverridden
+	default:
+		if r.GoogleCanonical == r.URL {
+			return m, false
+		}
+		m.Kind = CanonicalUndeclared
+	}
+	return m, true
+}
+
+// CanonicalGroup is the canonical audit of one URL pattern.
+type CanonicalGroup struct {
+	Pattern    string              `json:"pattern"`
+	Inspected  int                 `json:"inspected"`
+	Mismatches []CanonicalMismatch `json:"mismatches"`
+	// PageTraffic totals the mismatched URLs' traffic: what the pattern
+	// stands to lose to the canonicals Google picked instead.
+	PageTraffic
+}
+
+// CanonicalAudit finds the canonical mismatches in results and groups them by
+// groupOf(URL). Groups are ordered by mismatches, then their impressions;
+// mismatches within a group by impressions, then URL. Groups without a
+// mismatch are kept for their inspected count.
+func CanonicalAudit(results []gsc.URLInspectionResult, traffic map[string]PageTraffic, groupOf func(string) string) []CanonicalGroup {
+	byPattern := make(map[string]*CanonicalGroup)
+	var order []string
+	for _, r := range results {
+		pattern := groupOf(r.URL)
+		g := byPattern[pattern]
+		if g == nil {
+			g = &CanonicalGroup{Pattern: pattern, Mismatches: []CanonicalMismatch{}}
+			byPattern[pattern] = g
+			order = append(order, pattern)
+		}
+		g.Inspected++
+		m, ok := CanonicalMismatchOf(r)
+		if !ok {
+			continue
+		}
+		m.PageTraffic = traffic[r.URL]
+		g.Mismatches = append(g.Mismatches, m)
+		g.Clicks += m.Clicks
+		g.Impressions += m.Impressions
+	}
+
+	out := make([]CanonicalGroup, 0, len(order))
+	for _, pattern := range order {
+		g := byPattern[pattern]
+		sort.Slice(g.Mismatches, func(i, j int) bool {
+			a, b := g.Mismatches[i], g.Mismatches[j]
+			if a.Impressions != b.Impressions {
+				return a.Impressions > b.Impressions
+			}
+			return a.URL < b.URL
+		})
+		out = append(out, *g)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].Mismatches) != len(out[j].Mismatches) {
+			return len(out[i].Mismatches) > len(out[j].Mismatches)
+		}
+		if out[i].Impressions != out[j].Impressions {
+			return out[i].Impressions > out[j].Impressions
+		}
+		return out[i].Pattern < out[j].Pattern
+	})
+	return out
+}
@@ -0,0 +1,72 @@
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestCanonicalMismatchOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   gsc.URLInspectionResult
+		wantKind string
+		wantOK   bool
+	}{
+		{"self-canonical", gsc.URLInspectionResult{URL: "/a", UserCanonical: "/a", GoogleCanonical: "/a"}, "", false},
+		{"intended alternate", gsc.URLInspectionResult{URL: "/amp/a", UserCanonical: "/a", GoogleCanonical: "/a"}, "", false},
+		{"overridden", gsc.URLInspectionResult{URL: "/a", UserCanonical: "/a", GoogleCanonical: "/a?ref=1"}, CanonicalOverridden, true},
+		{"undeclared duplicate", gsc.URLInspectionResult{URL: "/a?page=2", GoogleCanonical: "/a"}, CanonicalUndeclared, true},
+		{"undeclared self", gsc.URLInspectionResult{URL: "/a", GoogleCanonical: "/a"}, "", false},
+		{"never crawled", gsc.URLInspectionResult{URL: "/a", UserCanonical: "/b"}, "", false},
+	}
+	for _, tt := range tests {
+		m, ok := CanonicalMismatchOf(tt.result)
+		if ok != tt.wantOK || m.Kind != tt.wantKind {
+			t.Errorf("%s: CanonicalMismatchOf = (%q, %v), want (%q, %v)", tt.name, m.Kind, ok, tt.wantKind, tt.wantOK)
+		}
+	}
+}
+
+func TestCanonicalAudit(t *testing.T) {
+	results := []gsc.URLInspectionResult{
+		{URL: "/blog/a", UserCanonical: "/blog/a", GoogleCanonical: "/blog/a"},
+		{URL: "/blog/b", UserCanonical: "/blog/b", GoogleCanonical: "/blog/a", IndexStatus: "NEUTRAL"},
+		{URL: "/shop/x", UserCanonical: "/shop/x", GoogleCanonical: "/shop/y", IndexStatus: "NEUTRAL"},
+		{URL: "/shop/z", GoogleCanonical: "/shop/y", IndexStatus: "NEUTRAL"},
+		{URL: "/about", UserCanonical: "/about", GoogleCanonical: "/about", IndexStatus: "PASS"},
+	}
+	traffic := map[string]PageTraffic{
+		"/blog/b": {Clicks: 4, Impressions: 400},
+		"/shop/x": {Clicks: 1, Impressions: 50},
+		"/shop/z": {Clicks: 2, Impressions: 80},
+	}
+	groupOf := func(u string) string {
+		if len(u) > 6 {
+			return u[:6]
+		}
+		return "/"
+	}
+
+	got := CanonicalAudit(results, traffic, groupOf)
+	want := []CanonicalGroup{
+		{
+			Pattern: "/shop/", Inspected: 2, PageTraffic: PageTraffic{Clicks: 3, Impressions: 130},
+			Mismatches: []CanonicalMismatch{
+				{URL: "/shop/z", Kind: CanonicalUndeclared, GoogleCanonical: "/shop/y", IndexStatus: "NEUTRAL", PageTraffic: PageTraffic{Clicks: 2, Impressions: 80}},
+				{URL: "/shop/x", Kind: CanonicalOverridden, UserCanonical: "/shop/x", GoogleCanonical: "/shop/y", IndexStatus: "NEUTRAL", PageTraffic: PageTraffic{Clicks: 1, Impressions: 50}},
+			},
+		},
+		{
+			Pattern: "/blog/", Inspected: 2, PageTraffic: PageTraffic{Clicks: 4, Impressions: 400},
+			Mismatches: []CanonicalMismatch{
+				{URL: "/blog/b", Kind: CanonicalOverridden, UserCanonical: "/blog/b", GoogleCanonical: "/blog/a", IndexStatus: "NEUTRAL", PageTraffic: PageTraffic{Clicks: 4, Impressions: 400}},
+			},
+		},
+		{Pattern: "/", Inspected: 1, Mismatches: []CanonicalMismatch{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CanonicalAudit =\n%+v\nwant\n%+v", got, want)
+	}
+}
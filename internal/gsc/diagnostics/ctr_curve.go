@@ -0,0 +1,253 @@
+package diagnostics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// PositionBucket is one band of the position-CTR curve. Rows are placed by
+// round(Position), the same convention Opportunity uses, so an average
+// position of 1.4 counts as position 1 and 5.5 as 6.
+type PositionBucket struct {
+	Label string
+	Min   int
+	Max   int
+}
+
+// CTRCurveBuckets are the bands the curve is measured in. The top three
+// positions each get their own band because their CTRs differ the most;
+// further down the page the curve flattens and positions are grouped.
+var CTRCurveBuckets = []PositionBucket{
+	{Label: "1", Min: 1, Max: 1},
+	{Label: "2", Min: 2, Max: 2},
+	{Label: "3", Min: 3, Max: 3},
+	{Label: "4-5", Min: 4, Max: 5},
+	{Label: "6-10", Min: 6, Max: 10},
+	{Label: "11-20", Min: 11, Max: 20},
+}
+
+// IndustryBenchmark is the name of the built-in benchmark curve.
+const IndustryBenchmark = "industry"
+
+// industryCTRCurve is the built-in benchmark, from the same public datasets
+// as baselineCTRByBucket (Advanced Web Ranking, Backlinko, Sistrix
+// 2023-2024 averages), averaged over each band. Like the baseline it is
+// not vertical-specific: operators who know their niche's curve configure
+// their own under search_console.ctr_benchmarks.
+var industryCTRCurve = map[string]float64{
+	"1":     0.275,
+	"2":     0.155,
+	"3":     0.105,
+	"4-5":   0.070,
+	"6-10":  0.040,
+	"11-20": 0.015,
+}
+
+// CTRCurve maps a bucket label to its expected CTR, as a ratio.
+type CTRCurve map[string]float64
+
+// BenchmarkCurve returns the curve named name: one of configured, or the
+// built-in IndustryBenchmark. A configured curve may leave bands out; those
+// take the industry value. A configured "industry" curve replaces the
+// built-in one.
+func BenchmarkCurve(name string, configured map[string]map[string]float64) (CTRCurve, error) {
+	values, ok := configured[name]
+	if !ok && name != IndustryBenchmark {
+		names := []string{IndustryBenchmark}
+		for n := range configured {
+			if n != IndustryBenchmark {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names[1:])
+		return nil, fmt.Errorf("unknown CTR benchmark %q (want %s)", name, strings.Join(names, ", "))
+	}
+
+	curve := make(CTRCurve, len(CTRCurveBuckets))
+	for label, ctr := range industryCTRCurve {
+		curve[label] = ctr
+	}
+	for label, ctr := range values {
+		if _, ok := curve[label]; !ok {
+			return nil, fmt.Errorf("CTR benchmark %q: unknown position bucket %q (want %s)", name, label, bucketLabels())
+		}
+		if ctr <= 0 || ctr > 1 {
+			return nil, fmt.Errorf("CTR benchmark %q: bucket %s CTR %g must be in (0, 1]", name, label, ctr)
+		}
+		curve[label] = ctr
+	}
+	return curve, nil
+}
+
+func bucketLabels() string {
+	labels := make([]string, len(CTRCurveBuckets))
+	for i, b := range CTRCurveBuckets {
+		labels[i] = b.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// bucketFor returns the band that position falls in, or false past 20.
+func bucketFor(position float64) (PositionBucket, bool) {
+	rank := int(math.Round(position))
+	for _, b := range CTRCurveBuckets {
+		if rank >= b.Min && rank <= b.Max {
+			return b, true
+		}
+	}
+	return PositionBucket{}, false
+}
+
+// CTRCurvePage is a page that ranks in an underperforming band and converts
+// below its benchmark there. Its numbers cover only the page's queries in
+// that band.
+type CTRCurvePage struct {
+	Page        string
+	Queries     int
+	Clicks      int64
+	Impressions int64
+	CTR         float64
+	// Position is the page's impression-weighted average position in the
+	// band.
+	Position float64
+	// PotentialClicks is how many more clicks the page would get at the
+	// benchmark CTR: max(0, round(impressions * (benchmark − ctr))).
+	PotentialClicks int64
+}
+
+// CTRCurveResult is the site's CTR in one position band against the
+// benchmark curve.
+type CTRCurveResult struct {
+	Bucket       string
+	Queries      int
+	Clicks       int64
+	Impressions  int64
+	CTR          float64
+	BenchmarkCTR float64
+	// Ratio is CTR / BenchmarkCTR: 1 matches the benchmark, 0.5 gets half
+	// the clicks the benchmark expects.
+	Ratio float64
+	// Underperforming reports Ratio < 1 − tolerance on a band with enough
+	// impressions to judge.
+	Underperforming bool
+	// PotentialClicks is the band's click gain at the benchmark CTR.
+	PotentialClicks int64
+	// Pages lists the band's pages converting below the benchmark, most
+	// potential clicks first. Empty unless Underperforming.
+	Pages []CTRCurvePage
+}
+
+// CTRCurveAnalysis places (query, page) rows in the CTRCurveBuckets bands
+// and compares each band's CTR — total clicks over total impressions, so
+// high-volume queries weigh more — against curve.
+//
+// A band is underperforming when its CTR is more than tolerance below the
+// benchmark (tolerance 0.2: below 80% of it) and it has at least
+// minImpressions impressions; a band with fewer is reported but not judged,
+// because a handful of impressions says nothing about the snippet. The
+// pages of an underperforming band are the ones whose own CTR in the band
+// is below the benchmark: their titles and meta descriptions are the ones
+// to rewrite.
+//
+// Results follow CTRCurveBuckets order and skip bands without rows. Rows
+// ranked below 20, without impressions, or whose Keys are not [query, page]
+// are ignored.
+func CTRCurveAnalysis(rows []gsc.SearchAnalyticsRow, curve CTRCurve, tolerance float64, minImpressions int64) []CTRCurveResult {
+	type pageTotals struct {
+		queries     int
+		clicks      int64
+		impressions int64
+		weightedPos float64
+	}
+	type bandTotals struct {
+		queries     int
+		clicks      int64
+		impressions int64
+		pages       map[string]*pageTotals
+	}
+
+	bands := make(map[string]*bandTotals, len(CTRCurveBuckets))
+	for _, row := range rows {
+		if len(row.Keys) != 2 || row.Keys[1] == "" || row.Impressions <= 0 {
+			continue
+		}
+		bucket, ok := bucketFor(row.Position)
+		if !ok {
+			continue
+		}
+		band := bands[bucket.Label]
+		if band == nil {
+			band = &bandTotals{pages: make(map[string]*pageTotals)}
+			bands[bucket.Label] = band
+		}
+		band.queries++
+		band.clicks += row.Clicks
+		band.impressions += row.Impressions
+
+		page := band.pages[row.Keys[1]]
+		if page == nil {
+			page = &pageTotals{}
+			band.pages[row.Keys[1]] = page
+		}
+		page.queries++
+		page.clicks += row.Clicks
+		page.impressions += row.Impressions
+		page.weightedPos += row.Position * float64(row.Impressions)
+	}
+
+	results := make([]CTRCurveResult, 0, len(bands))
+	for _, bucket := range CTRCurveBuckets {
+		band, ok := bands[bucket.Label]
+		if !ok {
+			continue
+		}
+		benchmark := curve[bucket.Label]
+		ctr := float64(band.clicks) / float64(band.impressions)
+		result := CTRCurveResult{
+			Bucket:          bucket.Label,
+			Queries:         band.queries,
+			Clicks:          band.clicks,
+			Impressions:     band.impressions,
+			CTR:             ctr,
+			BenchmarkCTR:    benchmark,
+			PotentialClicks: potentialClicks(band.impressions, benchmark, ctr),
+		}
+		if benchmark > 0 {
+			result.Ratio = ctr / benchmark
+			result.Underperforming = band.impressions >= minImpressions && result.Ratio < 1-tolerance
+		}
+		if result.Underperforming {
+			for url, p := range band.pages {
+				pageCTR := float64(p.clicks) / float64(p.impressions)
+				if pageCTR >= benchmark {
+					continue
+				}
+				result.Pages = append(result.Pages, CTRCurvePage{
+					Page:            url,
+					Queries:         p.queries,
+					Clicks:          p.clicks,
+					Impressions:     p.impressions,
+					CTR:             pageCTR,
+					Position:        p.weightedPos / float64(p.impressions),
+					PotentialClicks: potentialClicks(p.impressions, benchmark, pageCTR),
+				})
+			}
+			sort.Slice(result.Pages, func(i, j int) bool {
+				if result.Pages[i].PotentialClicks != result.Pages[j].PotentialClicks {
+					return result.Pages[i].PotentialClicks > result.Pages[j].PotentialClicks
+				}
+				return result.Pages[i].Page < result.Pages[j].Page
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func potentialClicks(impressions int64, benchmark, ctr float64) int64 {
+	return max(0, int64(math.Round(float64(impressions)*(benchmark-ctr))))
+}
@@ -0,0 +1,127 @@
+package diagnostics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func curveRow(query, page string, position float64, clicks, impressions int64) gsc.SearchAnalyticsRow {
+	return gsc.SearchAnalyticsRow{
+		Keys:        []string{query, page},
+		Clicks:      clicks,
+		Impressions: impressions,
+		CTR:         float64(clicks) / float64(impressions),
+		Position:    position,
+	}
+}
+
+func TestCTRCurveAnalysis_Buckets(t *testing.T) {
+	rows := []gsc.SearchAnalyticsRow{
+		curveRow("a", "https://example.com/a", 1.4, 30, 100),
+		curveRow("b", "https://example.com/b", 1.6, 15, 100),
+		curveRow("c", "https://example.com/c", 4.6, 7, 100),
+		curveRow("d", "https://example.com/d", 5.4, 7, 100),
+		curveRow("e", "https://example.com/e", 10.4, 4, 100),
+		curveRow("f", "https://example.com/f", 20.6, 1, 100),
+	}
+	got := CTRCurveAnalysis(rows, industryCTRCurve, 0.2, 0)
+
+	want := []string{"1", "2", "4-5", "6-10"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d buckets, want %d: %+v", len(got), len(want), got)
+	}
+	for i, label := range want {
+		if got[i].Bucket != label {
+			t.Errorf("bucket %d = %q, want %q", i, got[i].Bucket, label)
+		}
+	}
+	if got[2].Queries != 2 || got[2].Impressions != 200 || got[2].Clicks != 14 {
+		t.Errorf("4-5 bucket totals = %+v", got[2])
+	}
+}
+
+func TestCTRCurveAnalysis_UnderperformingBucketListsPages(t *testing.T) {
+	rows := []gsc.SearchAnalyticsRow{
+		// Bucket 1: benchmark 27.5%, site 10% overall.
+		curveRow("q1", "https://example.com/a", 1.0, 5, 100),
+		curveRow("q2", "https://example.com/a", 1.2, 5, 100),
+		curveRow("q3", "https://example.com/b", 1.0, 10, 200),
+		// Converts above the benchmark: not listed.
+		curveRow("q4", "https://example.com/c", 1.0, 40, 100),
+	}
+	got := CTRCurveAnalysis(rows, industryCTRCurve, 0.2, 100)
+	if len(got) != 1 {
+		t.Fatalf("got %d buckets, want 1", len(got))
+	}
+	b := got[0]
+	if !b.Underperforming {
+		t.Fatalf("bucket 1 not flagged: ctr %.3f ratio %.3f", b.CTR, b.Ratio)
+	}
+	if math.Abs(b.CTR-0.12) > 1e-9 {
+		t.Errorf("CTR = %v, want 0.12 (60 clicks / 500 impressions)", b.CTR)
+	}
+	if b.PotentialClicks != 78 {
+		t.Errorf("PotentialClicks = %d, want 78", b.PotentialClicks)
+	}
+	if len(b.Pages) != 2 {
+		t.Fatalf("pages = %+v, want /a and /b", b.Pages)
+	}
+	// /b: 200 impressions at 5% → 45 potential; /a: 200 at 5%, same.
+	// Equal potential falls back to URL order.
+	if b.Pages[0].Page != "https://example.com/a" || b.Pages[1].Page != "https://example.com/b" {
+		t.Errorf("page order = %s, %s", b.Pages[0].Page, b.Pages[1].Page)
+	}
+	if b.Pages[0].Queries != 2 || math.Abs(b.Pages[0].Position-1.1) > 1e-9 {
+		t.Errorf("page /a = %+v, want 2 queries at position 1.1", b.Pages[0])
+	}
+}
+
+func TestCTRCurveAnalysis_ToleranceAndMinImpressions(t *testing.T) {
+	// Bucket 6-10: benchmark 4%, site 3.5% — 12.5% below.
+	rows := []gsc.SearchAnalyticsRow{curveRow("q", "https://example.com/a", 8, 35, 1000)}
+
+	if got := CTRCurveAnalysis(rows, industryCTRCurve, 0.2, 0); got[0].Underperforming {
+		t.Error("12.5% below the benchmark flagged at 20% tolerance")
+	}
+	if got := CTRCurveAnalysis(rows, industryCTRCurve, 0.1, 0); !got[0].Underperforming {
+		t.Error("12.5% below the benchmark not flagged at 10% tolerance")
+	}
+	got := CTRCurveAnalysis(rows, industryCTRCurve, 0.1, 5000)
+	if got[0].Underperforming || len(got[0].Pages) != 0 {
+		t.Error("bucket below --min-impressions flagged")
+	}
+}
+
+func TestBenchmarkCurve(t *testing.T) {
+	configured := map[string]map[string]float64{
+		"ecommerce": {"1": 0.2, "6-10": 0.03},
+	}
+
+	curve, err := BenchmarkCurve("ecommerce", configured)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve["1"] != 0.2 || curve["6-10"] != 0.03 {
+		t.Errorf("configured values not applied: %v", curve)
+	}
+	if curve["2"] != industryCTRCurve["2"] {
+		t.Errorf("missing bucket 2 = %v, want the industry value", curve["2"])
+	}
+
+	if curve, err := BenchmarkCurve(IndustryBenchmark, nil); err != nil || curve["1"] != industryCTRCurve["1"] {
+		t.Errorf("industry curve = %v, %v", curve, err)
+	}
+
+	if _, err := BenchmarkCurve("travel", configured); err == nil || !strings.Contains(err.Error(), "industry, ecommerce") {
+		t.Errorf("unknown benchmark error = %v", err)
+	}
+	if _, err := BenchmarkCurve("bad", map[string]map[string]float64{"bad": {"7": 0.1}}); err == nil {
+		t.Error("unknown bucket accepted")
+	}
+	if _, err := BenchmarkCurve("bad", map[string]map[string]float64{"bad": {"1": 1.5}}); err == nil {
+		t.Error("CTR above 1 accepted")
+	}
+}
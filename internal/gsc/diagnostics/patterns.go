@@ -0,0 +1,165 @@
+package diagnostics
+
+import (
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/sitemap"
+)
+
+// DefaultPatternSample is how many URLs of a pattern are inspected when the
+// pattern does not set its own sample size.
+const DefaultPatternSample = 10
+
+// URLPattern is a monitored section of the site, such as "/blog/*". Pattern
+// is matched like ExpectedAlternate.Pattern.
+type URLPattern struct {
+	Pattern     string
+	Description string
+	// Sample is how many matching URLs to inspect per run; 0 means
+	// DefaultPatternSample.
+	Sample int
+}
+
+// Matches reports whether rawURL falls under the pattern.
+func (p URLPattern) Matches(rawURL string) bool {
+	return matchURLPattern(p.Pattern, rawURL)
+}
+
+func (p URLPattern) sampleSize() int {
+	if p.Sample > 0 {
+		return p.Sample
+	}
+	return DefaultPatternSample
+}
+
+// PatternSample is the URLs picked for inspection from one pattern.
+type PatternSample struct {
+	URLPattern
+	// Matched is how many discovered URLs the pattern matched.
+	Matched int
+	// URLs is the sample: up to Sample matching URLs spread evenly through
+	// the sorted matches, so every subsection is represented and consecutive
+	// runs inspect the same pages while the URL set is unchanged.
+	URLs []string
+}
+
+// ExpandPatterns matches the discovered URLs (from sitemaps or the Search
+// Analytics page dimension) against each pattern and samples the matches.
+// A URL may fall under several patterns; it is sampled by each of them.
+func ExpandPatterns(patterns []URLPattern, urls []string) []PatternSample {
+	sorted := append([]string(nil), urls...)
+	sort.Strings(sorted)
+
+	samples := make([]PatternSample, 0, len(patterns))
+	for _, p := range patterns {
+		var matched []string
+		for i, u := range sorted {
+			if i > 0 && u == sorted[i-1] {
+				continue
+			}
+			if p.Matches(u) {
+				matched = append(matched, u)
+			}
+		}
+		samples = append(samples, PatternSample{
+			URLPattern: p,
+			Matched:    len(matched),
+			URLs:       sitemap.SpreadSample(matched, p.sampleSize()),
+		})
+	}
+	return samples
+}
+
+// SampledURLs returns the URLs of every sample, without duplicates, in
+// pattern order.
+func SampledURLs(samples []PatternSample) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range samples {
+		for _, u := range s.URLs {
+			if !seen[u] {
+				seen[u] = true
+				out = append(out, u)
+			}
+		}
+	}
+	return out
+}
+
+// CoverageCount is how many inspected URLs of a pattern are in one coverage
+// state.
+type CoverageCount struct {
+	State string `json:"state"`
+	URLs  int    `json:"urls"`
+}
+
+// PatternHealth is the aggregate inspection outcome of one pattern's sample.
+type PatternHealth struct {
+	Pattern     string `json:"pattern"`
+	Description string `json:"description,omitempty"`
+	Matched     int    `json:"matched"`
+	Inspected   int    `json:"inspected"`
+	Indexed     int    `json:"indexed"`
+	Partial     int    `json:"partial"`
+	NotIndexed  int    `json:"not_indexed"`
+	WithIssues  int    `json:"with_issues"`
+	// IndexedShare is Indexed / Inspected; 0 when nothing was inspected.
+	IndexedShare float64 `json:"indexed_share"`
+	// NotIndexedStates breaks down the sampled URLs that are not indexed by
+	// coverage state, most common first: the reasons the section is
+	// missing from the index.
+	NotIndexedStates []CoverageCount `json:"not_indexed_states,omitempty"`
+}
+
+// PatternHealthReport aggregates the inspection results of each sample. URLs
+// of a sample without a result are not counted as inspected.
+func PatternHealthReport(samples []PatternSample, results []gsc.URLInspectionResult) []PatternHealth {
+	byURL := make(map[string]gsc.URLInspectionResult, len(results))
+	for _, r := range results {
+		byURL[r.URL] = r
+	}
+
+	report := make([]PatternHealth, 0, len(samples))
+	for _, s := range samples {
+		h := PatternHealth{Pattern: s.Pattern, Description: s.Description, Matched: s.Matched}
+		states := make(map[string]int)
+		for _, u := range s.URLs {
+			r, ok := byURL[u]
+			if !ok {
+				continue
+			}
+			h.Inspected++
+			switch r.IndexStatus {
+			case "PASS":
+				h.Indexed++
+			case "PARTIAL":
+				h.Partial++
+			default:
+				h.NotIndexed++
+				state := r.CoverageState
+				if state == "" {
+					state = "(unknown)"
+				}
+				states[state]++
+			}
+			if len(r.IndexingIssues) > 0 {
+				h.WithIssues++
+			}
+		}
+		if h.Inspected > 0 {
+			h.IndexedShare = float64(h.Indexed) / float64(h.Inspected)
+		}
+		for state, n := range states {
+			h.NotIndexedStates = append(h.NotIndexedStates, CoverageCount{State: state, URLs: n})
+		}
+		sort.Slice(h.NotIndexedStates, func(i, j int) bool {
+			if h.NotIndexedStates[i].URLs != h.NotIndexedStates[j].URLs {
+				return h.NotIndexedStates[i].URLs > h.NotIndexedStates[j].URLs
+			}
+			return h.NotIndexedStates[i].State < h.NotIndexedStates[j].State
+		})
+		report = append(report, h)
+	}
+	return report
+}
@@ -0,0 +1,80 @@
+package diagnostics
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestExpandPatterns(t *testing.T) {
+	var urls []string
+	for i := 0; i < 20; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/blog/post-%02d", i))
+	}
+	urls = append(urls, "https://example.com/docs/a", "https://example.com/docs/b", "https://example.com/docs/a")
+
+	samples := ExpandPatterns([]URLPattern{
+		{Pattern: "/blog/*", Sample: 4},
+		{Pattern: "/docs/*"},
+		{Pattern: "/shop/*"},
+	}, urls)
+
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want 3", len(samples))
+	}
+	blog := samples[0]
+	if blog.Matched != 20 {
+		t.Errorf("blog matched %d, want 20", blog.Matched)
+	}
+	wantBlog := []string{
+		"https://example.com/blog/post-00",
+		"https://example.com/blog/post-05",
+		"https://example.com/blog/post-10",
+		"https://example.com/blog/post-15",
+	}
+	if !reflect.DeepEqual(blog.URLs, wantBlog) {
+		t.Errorf("blog sample = %v, want %v", blog.URLs, wantBlog)
+	}
+	if docs := samples[1]; docs.Matched != 2 || len(docs.URLs) != 2 {
+		t.Errorf("docs = %d matched, %v; want duplicates dropped", docs.Matched, docs.URLs)
+	}
+	if shop := samples[2]; shop.Matched != 0 || len(shop.URLs) != 0 {
+		t.Errorf("shop = %+v, want no matches", shop)
+	}
+	if got := len(SampledURLs(samples)); got != 6 {
+		t.Errorf("SampledURLs returned %d URLs, want 6", got)
+	}
+}
+
+func TestPatternHealthReport(t *testing.T) {
+	samples := []PatternSample{{
+		URLPattern: URLPattern{Pattern: "/blog/*", Description: "Blog"},
+		Matched:    40,
+		URLs:       []string{"https://example.com/blog/a", "https://example.com/blog/b", "https://example.com/blog/c", "https://example.com/blog/d", "https://example.com/blog/e"},
+	}}
+	results := []gsc.URLInspectionResult{
+		{URL: "https://example.com/blog/a", IndexStatus: "PASS"},
+		{URL: "https://example.com/blog/b", IndexStatus: "PASS", IndexingIssues: []gsc.IndexingIssue{{Severity: "WARNING"}}},
+		{URL: "https://example.com/blog/c", IndexStatus: "NEUTRAL", CoverageState: "Crawled - currently not indexed"},
+		{URL: "https://example.com/blog/d", IndexStatus: "FAIL", CoverageState: "Crawled - currently not indexed"},
+		{URL: "https://example.com/other", IndexStatus: "PASS"},
+	}
+
+	got := PatternHealthReport(samples, results)
+	want := []PatternHealth{{
+		Pattern:          "/blog/*",
+		Description:      "Blog",
+		Matched:          40,
+		Inspected:        4,
+		Indexed:          2,
+		NotIndexed:       2,
+		WithIssues:       1,
+		IndexedShare:     0.5,
+		NotIndexedStates: []CoverageCount{{State: "Crawled - currently not indexed", URLs: 2}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternHealthReport = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+package diagnostics
+
+import (
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// RichResultIssueCount is how many items of a structured-data type share one
+// issue.
+type RichResultIssueCount struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Items    int    `json:"items"`
+}
+
+// RichResultTypeSummary aggregates one structured-data type (Recipe,
+// Breadcrumbs, FAQ…) across a set of inspected URLs.
+type RichResultTypeSummary struct {
+	Type string `json:"type"`
+	// URLs is how many inspected URLs carry the type; FailingURLs how many
+	// of them have an item with an error, which keeps the page out of rich
+	// results for the type.
+	URLs        int `json:"urls"`
+	FailingURLs int `json:"failing_urls"`
+	Items       int `json:"items"`
+	// ItemsWithErrors counts items with at least one error;
+	// ItemsWithWarnings items with warnings only.
+	ItemsWithErrors   int `json:"items_with_errors"`
+	ItemsWithWarnings int `json:"items_with_warnings"`
+	// Issues lists the type's issues by how many items have them, errors
+	// before warnings on a tie.
+	Issues []RichResultIssueCount `json:"issues,omitempty"`
+}
+
+// SummariseRichResults aggregates the detected rich result items of results
+// by type, the types failing most first: by items with errors, then items
+// with warnings, then name. An item issue without a severity counts as a
+// warning.
+func SummariseRichResults(results []gsc.URLInspectionResult) []RichResultTypeSummary {
+	type issueKey struct{ severity, message string }
+	type totals struct {
+		summary RichResultTypeSummary
+		issues  map[issueKey]int
+	}
+
+	byType := make(map[string]*totals)
+	for _, r := range results {
+		seen := make(map[string]bool)
+		failing := make(map[string]bool)
+		for _, item := range r.RichResultItems {
+			t := byType[item.Type]
+			if t == nil {
+				t = &totals{summary: RichResultTypeSummary{Type: item.Type}, issues: make(map[issueKey]int)}
+				byType[item.Type] = t
+			}
+			if !seen[item.Type] {
+				seen[item.Type] = true
+				t.summary.URLs++
+			}
+			t.summary.Items++
+
+			var hasError bool
+			for _, issue := range ItemIssues(item) {
+				if issue.Severity == "ERROR" {
+					hasError = true
+				}
+				t.issues[issueKey{issue.Severity, issue.Message}]++
+			}
+			switch {
+			case hasError:
+				t.summary.ItemsWithErrors++
+				if !failing[item.Type] {
+					failing[item.Type] = true
+					t.summary.FailingURLs++
+				}
+			case len(item.Issues) > 0 || len(item.IssueDetails) > 0:
+				t.summary.ItemsWithWarnings++
+			}
+		}
+	}
+
+	out := make([]RichResultTypeSummary, 0, len(byType))
+	for _, t := range byType {
+		for k, n := range t.issues {
+			t.summary.Issues = append(t.summary.Issues, RichResultIssueCount{Severity: k.severity, Message: k.message, Items: n})
+		}
+		sort.Slice(t.summary.Issues, func(i, j int) bool {
+			a, b := t.summary.Issues[i], t.summary.Issues[j]
+			if a.Items != b.Items {
+				return a.Items > b.Items
+			}
+			if a.Severity != b.Severity {
+				return a.Severity == "ERROR"
+			}
+			return a.Message < b.Message
+		})
+		out = append(out, t.summary)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ItemsWithErrors != out[j].ItemsWithErrors {
+			return out[i].ItemsWithErrors > out[j].ItemsWithErrors
+		}
+		if out[i].ItemsWithWarnings != out[j].ItemsWithWarnings {
+			return out[i].ItemsWithWarnings > out[j].ItemsWithWarnings
+		}
+		return out[i].Type < out[j].Type
+	})
+	return out
+}
+
+// ItemIssues returns an item's issues with severity, falling back to the
+// plain messages (as warnings) for results without IssueDetails.
+func ItemIssues(item gsc.RichResultItem) []gsc.RichResultIssue {
+	if len(item.IssueDetails) > 0 || len(item.Issues) == 0 {
+		return item.IssueDetails
+	}
+	out := make([]gsc.RichResultIssue, len(item.Issues))
+	for i, msg := range item.Issues {
+		out[i] = gsc.RichResultIssue{Severity: "WARNING", Message: msg}
+	}
+	return out
+}
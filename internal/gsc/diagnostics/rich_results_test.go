@@ -0,0 +1,45 @@
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+func TestSummariseRichResults(t *testing.T) {
+	missingImage := gsc.RichResultIssue{Severity: "ERROR", Message: "Missing field \"image\""}
+	missingRating := gsc.RichResultIssue{Severity: "WARNING", Message: "Missing field \"aggregateRating\""}
+	results := []gsc.URLInspectionResult{
+		{URL: "/a", RichResultItems: []gsc.RichResultItem{
+			{Type: "Recipe", IssueDetails: []gsc.RichResultIssue{missingImage, missingRating}},
+			{Type: "Recipe", IssueDetails: []gsc.RichResultIssue{missingImage}},
+			{Type: "Breadcrumbs"},
+		}},
+		{URL: "/b", RichResultItems: []gsc.RichResultItem{
+			{Type: "Recipe", IssueDetails: []gsc.RichResultIssue{missingRating}},
+			{Type: "Breadcrumbs"},
+			{Type: "FAQ", Issues: []string{"Duplicate field \"FAQPage\""}},
+		}},
+		{URL: "/c"},
+	}
+
+	got := SummariseRichResults(results)
+	want := []RichResultTypeSummary{
+		{
+			Type: "Recipe", URLs: 2, FailingURLs: 1, Items: 3, ItemsWithErrors: 2, ItemsWithWarnings: 1,
+			Issues: []RichResultIssueCount{
+				{Severity: "ERROR", Message: "Missing field \"image\"", Items: 2},
+				{Severity: "WARNING", Message: "Missing field \"aggregateRating\"", Items: 2},
+			},
+		},
+		{
+			Type: "FAQ", URLs: 1, Items: 1, ItemsWithWarnings: 1,
+			Issues: []RichResultIssueCount{{Severity: "WARNING", Message: "Duplicate field \"FAQPage\"", Items: 1}},
+		},
+		{Type: "Breadcrumbs", URLs: 2, Items: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SummariseRichResults =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package gsc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// APIs a Step can draw on. Both count against the client's daily quota.
+const (
+	APIURLInspection   = "URL Inspection"
+	APISearchAnalytics = "Search Analytics"
+)
+
+// ErrQuotaExceeded is returned by Estimate.Check when a run would need more
+// calls than the quota has left today.
+var ErrQuotaExceeded = errors.New("estimated API calls exceed the remaining daily quota")
+
+// Step is one stage of a run and the quota-tracked calls it is expected to
+// make.
+type Step struct {
+	Name  string `json:"name"`
+	API   string `json:"api"`
+	Calls int    `json:"calls"`
+	// Basis says what Calls was derived from, e.g. "120 URLs".
+	Basis string `json:"basis"`
+}
+
+// InspectionStep estimates inspecting urls URLs: one call each.
+func InspectionStep(name string, urls int) Step {
+	return Step{Name: name, API: APIURLInspection, Calls: urls, Basis: fmt.Sprintf("%d URLs", urls)}
+}
+
+// SearchAnalyticsStep estimates q: QuerySearchAnalytics makes one call per
+// page of up to 25,000 rows. The row count is only known afterwards, so this
+// is the upper bound RowLimit allows, tightened for queries grouped by date
+// alone, which return at most one row per day.
+func SearchAnalyticsStep(name string, q *SearchAnalyticsQuery) Step {
+	rows := q.RowLimit
+	if rows <= 0 {
+		rows = 1
+	}
+	basis := fmt.Sprintf("up to %d rows", rows)
+	if slices.Equal(q.Dimensions, []string{"date"}) {
+		if days, ok := dateRangeDays(q.StartDate, q.EndDate); ok && days < rows {
+			rows = days
+			basis = fmt.Sprintf("%d days", days)
+		}
+	}
+	pages := (rows + maxRowsPerPage - 1) / maxRowsPerPage
+	if pages > 1 {
+		basis += fmt.Sprintf(", %d pages", pages)
+	}
+	return Step{Name: name, API: APISearchAnalytics, Calls: pages, Basis: basis}
+}
+
+func dateRangeDays(start, end string) (int, bool) {
+	s, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return 0, false
+	}
+	e, err := time.Parse("2006-01-02", end)
+	if err != nil || e.Before(s) {
+		return 0, false
+	}
+	return int(e.Sub(s).Hours()/24) + 1, true
+}
+
+// Estimate totals the steps of a run against the quota left today.
+type Estimate struct {
+	Steps     []Step `json:"steps"`
+	Calls     int    `json:"calls"`
+	Remaining int    `json:"remaining"`
+}
+
+// NewEstimate totals steps against remaining calls.
+func NewEstimate(remaining int, steps ...Step) Estimate {
+	e := Estimate{Steps: steps, Remaining: remaining}
+	for _, s := range steps {
+		e.Calls += s.Calls
+	}
+	return e
+}
+
+// Estimate totals steps against the client's remaining quota.
+func (c *Client) Estimate(steps ...Step) Estimate {
+	return NewEstimate(c.RemainingQuota(), steps...)
+}
+
+// Check returns ErrQuotaExceeded when the run would run out of quota partway
+// through.
+func (e Estimate) Check() error {
+	if e.Calls > e.Remaining {
+		return fmt.Errorf("%w: %d needed, %d left today", ErrQuotaExceeded, e.Calls, e.Remaining)
+	}
+	return nil
+}
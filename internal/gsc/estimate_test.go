@@ -0,0 +1,45 @@
+package gsc
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAnalyticsStep(t *testing.T) {
+	s := SearchAnalyticsStep("pages", &SearchAnalyticsQuery{Dimensions: []string{"page"}, RowLimit: MaxRowLimit})
+	assert.Equal(t, 4, s.Calls)
+	assert.Equal(t, "up to 100000 rows, 4 pages", s.Basis)
+
+	s = SearchAnalyticsStep("pages", &SearchAnalyticsQuery{Dimensions: []string{"page"}, RowLimit: 25000})
+	assert.Equal(t, 1, s.Calls)
+
+	s = SearchAnalyticsStep("trend", &SearchAnalyticsQuery{
+		Dimensions: []string{"date"}, RowLimit: MaxRowLimit, StartDate: "2026-01-01", EndDate: "2026-01-28",
+	})
+	assert.Equal(t, 1, s.Calls)
+	assert.Equal(t, "28 days", s.Basis)
+}
+
+func TestEstimateCheck(t *testing.T) {
+	e := NewEstimate(100, InspectionStep("inspect", 60), Step{Name: "traffic", Calls: 4})
+	assert.Equal(t, 64, e.Calls)
+	require.NoError(t, e.Check())
+
+	e = NewEstimate(100, InspectionStep("inspect", 101))
+	err := e.Check()
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	assert.Contains(t, err.Error(), "101 needed, 100 left today")
+}
+
+func TestClientRemainingQuota(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &Client{logger: logger, quotaTracker: newQuotaTracker("inspections", DefaultDailyLimit)}
+	assert.Equal(t, 1900, c.RemainingQuota())
+	require.NoError(t, c.useQuota("sc-domain:example.com"))
+	assert.Equal(t, 1899, c.Estimate().Remaining)
+}
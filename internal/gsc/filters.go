@@ -0,0 +1,118 @@
+package gsc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/searchconsole/v1"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// operatorIn is the config-only operator matching any of Expressions. The
+// API has no such operator; it is sent as an anchored includingRegex.
+const operatorIn = "in"
+
+// ParseFilter parses a command-line filter of the form
+// "DIMENSION OPERATOR EXPRESSION", e.g. "page contains /blog/" or
+// "query includingRegex ^how (to|do)". The expression is everything after the
+// operator, spaces included. Operators are matched case-insensitively
+// against ValidFilterOperators.
+func ParseFilter(s string) (*searchconsole.ApiDimensionFilter, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid filter %q: want DIMENSION OPERATOR EXPRESSION, e.g. \"page contains /blog/\"", s)
+	}
+	operator, err := normalizeOperator(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", s, err)
+	}
+	// Keep the expression's own spacing, trailing spaces included: cut the
+	// dimension and operator off the front of the string.
+	rest := strings.TrimLeft(s, " \t")
+	for _, f := range fields[:2] {
+		rest = strings.TrimLeft(strings.TrimPrefix(rest, f), " \t")
+	}
+	filter := CreateFilter(fields[0], operator, rest)
+	if err := validateFilter(filter); err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", s, err)
+	}
+	return filter, nil
+}
+
+// FilterFromConfig converts a search_analytics.filters entry. Besides the API
+// operators it accepts "in" with a list of expressions, which matches a
+// dimension value equal to any of them.
+func FilterFromConfig(fc config.SearchFilterConfig) (*searchconsole.ApiDimensionFilter, error) {
+	if strings.EqualFold(fc.Operator, operatorIn) {
+		if len(fc.Expressions) == 0 {
+			return nil, fmt.Errorf("filter on %s: operator in needs expressions", fc.Dimension)
+		}
+		quoted := make([]string, len(fc.Expressions))
+		for i, e := range fc.Expressions {
+			quoted[i] = regexp.QuoteMeta(e)
+		}
+		filter := CreateFilter(fc.Dimension, "includingRegex", "^("+strings.Join(quoted, "|")+")$")
+		return filter, validateFilter(filter)
+	}
+	operator, err := normalizeOperator(fc.Operator)
+	if err != nil {
+		return nil, fmt.Errorf("filter on %s: %w", fc.Dimension, err)
+	}
+	filter := CreateFilter(fc.Dimension, operator, fc.Expression)
+	if err := validateFilter(filter); err != nil {
+		return nil, fmt.Errorf("filter on %s: %w", fc.Dimension, err)
+	}
+	return filter, nil
+}
+
+// FiltersFromConfig converts every search_analytics.filters entry.
+func FiltersFromConfig(fcs []config.SearchFilterConfig) ([]*searchconsole.ApiDimensionFilter, error) {
+	filters := make([]*searchconsole.ApiDimensionFilter, 0, len(fcs))
+	for _, fc := range fcs {
+		f, err := FilterFromConfig(fc)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// normalizeOperator returns the API spelling of op, e.g. notContains for
+// "notcontains".
+func normalizeOperator(op string) (string, error) {
+	for valid := range ValidFilterOperators {
+		if strings.EqualFold(op, valid) {
+			return valid, nil
+		}
+	}
+	return "", ValidateFilterOperator(op)
+}
+
+// validateFilter checks one filter the way validateSearchQuery does, and that
+// a regex operator's expression compiles. Search Console uses RE2 syntax, as
+// Go's regexp does.
+func validateFilter(f *searchconsole.ApiDimensionFilter) error {
+	if !ValidDimensions[f.Dimension] {
+		return fmt.Errorf("invalid dimension '%s'", f.Dimension)
+	}
+	if err := ValidateFilterOperator(f.Operator); err != nil {
+		return err
+	}
+	if f.Expression == "" {
+		return fmt.Errorf("expression is required")
+	}
+	if f.Operator == "includingRegex" || f.Operator == "excludingRegex" {
+		if _, err := regexp.Compile(f.Expression); err != nil {
+			return fmt.Errorf("invalid regex '%s': %w", f.Expression, err)
+		}
+	}
+	return nil
+}
+
+// FormatFilter renders f the way ParseFilter reads it.
+func FormatFilter(f *searchconsole.ApiDimensionFilter) string {
+	return f.Dimension + " " + f.Operator + " " + f.Expression
+}
@@ -0,0 +1,65 @@
+package gsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		in                   string
+		dim, op, expr, error string
+	}{
+		{in: "page contains /blog/", dim: "page", op: "contains", expr: "/blog/"},
+		{in: "country equals esp", dim: "country", op: "equals", expr: "esp"},
+		{in: "query notcontains free", dim: "query", op: "notContains", expr: "free"},
+		{in: "query includingRegex ^how (to|do) ", dim: "query", op: "includingRegex", expr: "^how (to|do) "},
+		{in: "  query  equals  image  compressor", dim: "query", op: "equals", expr: "image  compressor"},
+		{in: "page contains", error: "want DIMENSION OPERATOR EXPRESSION"},
+		{in: "page startsWith /blog", error: "invalid filter operator"},
+		{in: "title contains x", error: "invalid dimension"},
+		{in: "query excludingRegex (unclosed", error: "invalid regex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			f, err := ParseFilter(tt.in)
+			if tt.error != "" {
+				assert.ErrorContains(t, err, tt.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.dim, f.Dimension)
+			assert.Equal(t, tt.op, f.Operator)
+			assert.Equal(t, tt.expr, f.Expression)
+		})
+	}
+}
+
+func TestFilterFromConfig(t *testing.T) {
+	f, err := FilterFromConfig(config.SearchFilterConfig{Dimension: "device", Operator: "EQUALS", Expression: "MOBILE"})
+	require.NoError(t, err)
+	assert.Equal(t, "device equals MOBILE", FormatFilter(f))
+
+	f, err = FilterFromConfig(config.SearchFilterConfig{Dimension: "country", Operator: "in", Expressions: []string{"esp", "a.b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "includingRegex", f.Operator)
+	assert.Equal(t, `^(esp|a\.b)$`, f.Expression)
+
+	_, err = FilterFromConfig(config.SearchFilterConfig{Dimension: "country", Operator: "in"})
+	assert.ErrorContains(t, err, "needs expressions")
+
+	filters, err := FiltersFromConfig(nil)
+	require.NoError(t, err)
+	assert.Empty(t, filters)
+}
+
+func TestValidateSearchQuery_RejectsBadRegex(t *testing.T) {
+	q := streamQuery()
+	q.RowLimit = 10
+	q.Filters = append(q.Filters, CreateFilter("page", "includingRegex", "[a-"))
+	assert.ErrorContains(t, (&Client{}).validateSearchQuery(q), "invalid regex")
+}
@@ -0,0 +1,219 @@
+// Package gscfake provides an in-memory gsc.SearchConsole for tests of code
+// that drives a Search Console property (the setup orchestrator, the
+// pre-flight validator and `gsc monitor run`).
+//
+// The fake is written by hand, not generated: it keeps state the way Search
+// Console does, so tests assert on outcomes rather than on expected calls.
+package gscfake
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// SearchConsole is a fake Search Console property. The exported fields are
+// its state: seed them before the test and inspect them afterwards.
+// Submitting and deleting sitemaps changes Sitemaps; every inspection is
+// charged against QuotaLimit.
+//
+// Errs makes a method fail, keyed by method name (e.g. "SubmitSitemap"),
+// before it touches the state. Every call is recorded; see Calls.
+// SearchConsole is safe for concurrent use.
+type SearchConsole struct {
+	Sitemaps []gsc.SitemapInfo
+	// Inspections holds the result InspectURL returns per URL. URLs not
+	// listed inspect as indexed.
+	Inspections map[string]gsc.URLInspectionResult
+	// Analytics is returned by every QuerySearchAnalytics call; nil returns
+	// an empty report.
+	Analytics *gsc.SearchAnalyticsReport
+
+	// QuotaUsed counts inspections made today. QuotaLimit defaults to
+	// gsc.DefaultDailyLimit.
+	QuotaUsed  int
+	QuotaLimit int
+
+	Errs map[string]error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// Compile-time guarantee that *SearchConsole satisfies gsc.SearchConsole.
+var _ gsc.SearchConsole = (*SearchConsole)(nil)
+
+// Calls returns the calls made so far as "Method arg", e.g.
+// "SubmitSitemap https://example.com/sitemap.xml", in the order they were
+// made.
+func (f *SearchConsole) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Called returns the arguments of the calls made to method, sorted.
+func (f *SearchConsole) Called(method string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var args []string
+	for _, c := range f.calls {
+		if m, arg, _ := strings.Cut(c, " "); m == method {
+			args = append(args, arg)
+		}
+	}
+	slices.Sort(args)
+	return args
+}
+
+// call records a call and returns its injected error, if any. The caller
+// holds f.mu.
+func (f *SearchConsole) call(method, arg string) error {
+	f.calls = append(f.calls, method+" "+arg)
+	return f.Errs[method]
+}
+
+func (f *SearchConsole) limit() int {
+	if f.QuotaLimit > 0 {
+		return f.QuotaLimit
+	}
+	return gsc.DefaultDailyLimit
+}
+
+// QuerySearchAnalytics returns Analytics for the query's site.
+func (f *SearchConsole) QuerySearchAnalytics(query *gsc.SearchAnalyticsQuery) (*gsc.SearchAnalyticsReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("QuerySearchAnalytics", strings.Join(query.Dimensions, ",")); err != nil {
+		return nil, err
+	}
+	report := gsc.SearchAnalyticsReport{}
+	if f.Analytics != nil {
+		report = *f.Analytics
+	}
+	report.SiteURL = query.SiteURL
+	report.QuotaUsed = f.QuotaUsed
+	return &report, nil
+}
+
+// InspectURL returns the URL's entry in Inspections, charging one call
+// against the quota.
+func (f *SearchConsole) InspectURL(siteURL, inspectURL string) (*gsc.URLInspectionResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inspect(inspectURL)
+}
+
+// inspect is InspectURL with f.mu held.
+func (f *SearchConsole) inspect(inspectURL string) (*gsc.URLInspectionResult, error) {
+	if err := f.call("InspectURL", inspectURL); err != nil {
+		return nil, err
+	}
+	if f.QuotaUsed >= f.limit() {
+		return nil, fmt.Errorf("daily quota of %d inspections exhausted", f.limit())
+	}
+	f.QuotaUsed++
+	result, ok := f.Inspections[inspectURL]
+	if !ok {
+		result = gsc.URLInspectionResult{
+			IndexStatus:   "PASS",
+			CoverageState: "Submitted and indexed",
+		}
+	}
+	result.URL = inspectURL
+	result.InspectionURL = inspectURL
+	return &result, nil
+}
+
+// InspectMultipleURLs inspects each URL in turn, stopping at the first
+// failure.
+func (f *SearchConsole) InspectMultipleURLs(siteURL string, inspectURLs []string) ([]gsc.URLInspectionResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("InspectMultipleURLs", siteURL); err != nil {
+		return nil, err
+	}
+	results := make([]gsc.URLInspectionResult, 0, len(inspectURLs))
+	for i, u := range inspectURLs {
+		result, err := f.inspect(u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect URL %s (at %d/%d): %w", u, i+1, len(inspectURLs), err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// Estimate totals steps against the quota left.
+func (f *SearchConsole) Estimate(steps ...gsc.Step) gsc.Estimate {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return gsc.NewEstimate(max(f.limit()-f.QuotaUsed, 0), steps...)
+}
+
+// GetQuotaStatus reports QuotaUsed against the limit for today.
+func (f *SearchConsole) GetQuotaStatus() (used int, limit int, date string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.QuotaUsed, f.limit(), time.Now().Format("2006-01-02")
+}
+
+// ListSitemaps returns the submitted sitemaps.
+func (f *SearchConsole) ListSitemaps(siteURL string) ([]gsc.SitemapInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListSitemaps", siteURL); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Sitemaps), nil
+}
+
+// GetSitemap returns one submitted sitemap.
+func (f *SearchConsole) GetSitemap(siteURL, sitemapURL string) (*gsc.SitemapInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetSitemap", sitemapURL); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(f.Sitemaps, func(s gsc.SitemapInfo) bool { return s.Path == sitemapURL })
+	if i < 0 {
+		return nil, fmt.Errorf("sitemap %s not found", sitemapURL)
+	}
+	sitemap := f.Sitemaps[i]
+	return &sitemap, nil
+}
+
+// SubmitSitemap adds a sitemap, or marks an existing one pending again.
+func (f *SearchConsole) SubmitSitemap(siteURL, sitemapURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("SubmitSitemap", sitemapURL); err != nil {
+		return err
+	}
+	submitted := gsc.SitemapInfo{Path: sitemapURL, LastSubmitted: time.Now().Format(time.RFC3339), IsPending: true}
+	if i := slices.IndexFunc(f.Sitemaps, func(s gsc.SitemapInfo) bool { return s.Path == sitemapURL }); i >= 0 {
+		f.Sitemaps[i] = submitted
+		return nil
+	}
+	f.Sitemaps = append(f.Sitemaps, submitted)
+	return nil
+}
+
+// DeleteSitemap removes a sitemap.
+func (f *SearchConsole) DeleteSitemap(siteURL, sitemapURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DeleteSitemap", sitemapURL); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.Sitemaps, func(s gsc.SitemapInfo) bool { return s.Path == sitemapURL })
+	if i < 0 {
+		return fmt.Errorf("sitemap %s not found", sitemapURL)
+	}
+	f.Sitemaps = slices.Delete(f.Sitemaps, i, i+1)
+	return nil
+}
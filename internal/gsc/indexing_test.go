@@ -0,0 +1,71 @@
+package gsc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/indexing/v3"
+)
+
+func TestQuotaTracker_RefusesAtCriticalThreshold(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	q := newQuotaTracker("URL notifications", 20)
+	assert.Equal(t, 15, q.warningThreshold)
+	assert.Equal(t, 19, q.criticalThreshold)
+
+	for range 19 {
+		require.NoError(t, q.use(logger, ""))
+	}
+	err := q.use(logger, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "19/20 URL notifications used")
+}
+
+// Publishing never draws down the inspection quota, and vice versa.
+func TestIndexingQuota_SeparateFromInspection(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &Client{
+		logger:        logger,
+		quotaTracker:  newQuotaTracker("inspections", 2000),
+		indexingQuota: newQuotaTracker("URL notifications", DefaultIndexingDailyLimit),
+	}
+	require.NoError(t, WithIndexingDailyLimit(1000)(c))
+	require.NoError(t, c.indexingQuota.use(logger, ""))
+
+	used, limit, _ := c.GetIndexingQuotaStatus()
+	assert.Equal(t, 1, used)
+	assert.Equal(t, 1000, limit)
+	inspections, _, _ := c.GetQuotaStatus()
+	assert.Zero(t, inspections)
+
+	assert.Error(t, WithIndexingDailyLimit(0)(c))
+}
+
+// Concurrent publishers on a shared client get the one Indexing service.
+func TestIndexing_CreatedOnceUnderConcurrency(t *testing.T) {
+	c := &Client{ctx: context.Background(), httpClient: http.DefaultClient}
+
+	var wg sync.WaitGroup
+	services := make([]*indexing.Service, 8)
+	for i := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc, err := c.indexing()
+			assert.NoError(t, err)
+			services[i] = svc
+		}()
+	}
+	wg.Wait()
+
+	require.NotNil(t, services[0])
+	for _, svc := range services {
+		assert.Same(t, services[0], svc)
+	}
+}
@@ -1,6 +1,7 @@
 package gsc
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -30,7 +31,26 @@ type URLInspectionResult struct {
 	RichResultsIssues []string
 	RichResultTypes   []string         // e.g., ["Recipe", "FAQ", "Breadcrumb"]
 	RichResultItems   []RichResultItem // Individual detected items with details
-	IndexingIssues    []IndexingIssue
+	// AMP is the inspection of the page's AMP version, nil when the page is
+	// not AMP and does not link to one. Its issues are also in IndexingIssues.
+	AMP            *AMPResult
+	IndexingIssues []IndexingIssue
+}
+
+// AMPResult is the AMP analysis of an inspected URL
+type AMPResult struct {
+	URL            string // AMP URL inspected (the linked AMP version of a desktop page)
+	Status         string // PASS, FAIL, NEUTRAL
+	IndexingState  string // AMP_INDEXING_ALLOWED, BLOCKED_DUE_TO_NOINDEX, etc.
+	PageFetchState string // SUCCESSFUL, NOT_FOUND, SERVER_ERROR, etc.
+	LastCrawlTime  string
+	Issues         []AMPIssue
+}
+
+// AMPIssue is one AMP validation issue
+type AMPIssue struct {
+	Severity string // ERROR, WARNING
+	Message  string
 }
 
 // RichResultItem represents a detected rich result item
@@ -38,6 +58,14 @@ type RichResultItem struct {
 	Type   string   // Rich result type (e.g., "Recipe", "FAQ")
 	Name   string   // Item name (e.g., "Chocolate Cake Recipe")
 	Issues []string // Validation issues for this specific item
+	// IssueDetails holds the same issues with their severity
+	IssueDetails []RichResultIssue
+}
+
+// RichResultIssue is one validation issue of a rich result item
+type RichResultIssue struct {
+	Severity string // ERROR, WARNING
+	Message  string
 }
 
 // IndexingIssue represents a specific indexing problem
@@ -60,7 +88,7 @@ func (c *Client) InspectURL(siteURL, inspectURL string) (*URLInspectionResult, e
 	}
 
 	// Check daily quota and increment counter atomically before making API call.
-	if err := c.useQuota(); err != nil {
+	if err := c.useQuota(siteURL); err != nil {
 		return nil, err
 	}
 
@@ -79,7 +107,12 @@ func (c *Client) InspectURL(siteURL, inspectURL string) (*URLInspectionResult, e
 	}
 
 	// Call the API
-	response, err := c.service.UrlInspection.Index.Inspect(request).Do()
+	var response *searchconsole.InspectUrlIndexResponse
+	err := c.call("inspect", "url", inspectURL, func(ctx context.Context) error {
+		var err error
+		response, err = c.service.UrlInspection.Index.Inspect(request).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		c.logger.Error("failed to inspect URL",
 			"site_url", siteURL,
@@ -239,9 +272,10 @@ func transformInspectionResponse(response *searchconsole.InspectUrlIndexResponse
 					for _, richItem := range detectedItem.Items {
 						// Create a RichResultItem for each detected item
 						item := RichResultItem{
-							Type:   detectedItem.RichResultType,
-							Name:   richItem.Name,
-							Issues: make([]string, 0),
+							Type:         detectedItem.RichResultType,
+							Name:         richItem.Name,
+							Issues:       make([]string, 0),
+							IssueDetails: make([]RichResultIssue, 0),
 						}
 
 						// Extract issues for this specific item
@@ -249,6 +283,10 @@ func transformInspectionResponse(response *searchconsole.InspectUrlIndexResponse
 							for _, issue := range richItem.Issues {
 								// Add to item-specific issues
 								item.Issues = append(item.Issues, issue.IssueMessage)
+								item.IssueDetails = append(item.IssueDetails, RichResultIssue{
+									Severity: severityFromString(issue.Severity),
+									Message:  issue.IssueMessage,
+								})
 
 								// Also add to legacy flat issues array (for backward compatibility)
 								result.RichResultsIssues = append(result.RichResultsIssues, issue.IssueMessage)
@@ -270,6 +308,29 @@ func transformInspectionResponse(response *searchconsole.InspectUrlIndexResponse
 		}
 	}
 
+	// AMP
+	if ampResult := inspectionResult.AmpResult; ampResult != nil {
+		result.AMP = &AMPResult{
+			URL:            ampResult.AmpUrl,
+			Status:         ampResult.AmpIndexStatusVerdict,
+			IndexingState:  ampResult.IndexingState,
+			PageFetchState: ampResult.PageFetchState,
+			LastCrawlTime:  ampResult.LastCrawlTime,
+			Issues:         make([]AMPIssue, 0),
+		}
+		for _, issue := range ampResult.Issues {
+			result.AMP.Issues = append(result.AMP.Issues, AMPIssue{
+				Severity: severityFromString(issue.Severity),
+				Message:  issue.IssueMessage,
+			})
+			result.IndexingIssues = append(result.IndexingIssues, IndexingIssue{
+				Severity:  severityFromString(issue.Severity),
+				Message:   fmt.Sprintf("AMP issue: %s", issue.IssueMessage),
+				IssueType: "AMP",
+			})
+		}
+	}
+
 	return result
 }
 
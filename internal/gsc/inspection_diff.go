@@ -0,0 +1,146 @@
+package gsc
+
+import (
+	"sort"
+)
+
+// InspectionSnapshot is the part of a URL inspection that CompareInspections
+// tracks between runs.
+type InspectionSnapshot struct {
+	URL           string
+	IndexStatus   string
+	CoverageState string
+	// Issues are the distinct indexing issue types, sorted.
+	Issues []string
+	// IssuesKnown is false for a saved run that did not record issue
+	// types; only the index status of such a URL is compared.
+	IssuesKnown bool
+}
+
+// SnapshotOf reduces an inspection result to a snapshot.
+func SnapshotOf(r URLInspectionResult) InspectionSnapshot {
+	return InspectionSnapshot{
+		URL:           r.URL,
+		IndexStatus:   r.IndexStatus,
+		CoverageState: r.CoverageState,
+		Issues:        IssueTypes(r.IndexingIssues),
+		IssuesKnown:   true,
+	}
+}
+
+// IssueTypes returns the distinct issue types of issues, sorted.
+func IssueTypes(issues []IndexingIssue) []string {
+	seen := make(map[string]bool, len(issues))
+	out := make([]string, 0, len(issues))
+	for _, i := range issues {
+		if !seen[i.IssueType] {
+			seen[i.IssueType] = true
+			out = append(out, i.IssueType)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// InspectionTransition is a URL whose state changed between two runs.
+type InspectionTransition struct {
+	URL              string `json:"url"`
+	PreviousStatus   string `json:"previous_status,omitempty"`
+	CurrentStatus    string `json:"current_status"`
+	PreviousCoverage string `json:"previous_coverage,omitempty"`
+	CurrentCoverage  string `json:"current_coverage"`
+	// Issues are the issue types that appeared (in NewIssues) or cleared
+	// (in Resolved) since the previous run.
+	Issues []string `json:"issues,omitempty"`
+	// Deindexed / Reindexed report the URL dropping out of or returning to
+	// the index (verdict PASS).
+	Deindexed bool `json:"deindexed,omitempty"`
+	Reindexed bool `json:"reindexed,omitempty"`
+	// FirstSeen marks a URL the previous run did not inspect; everything
+	// wrong with it counts as new.
+	FirstSeen bool `json:"first_seen,omitempty"`
+}
+
+// InspectionDiff is what changed between two inspection runs of the same
+// site. A URL can be in both lists when one issue appeared and another
+// cleared.
+type InspectionDiff struct {
+	NewIssues []InspectionTransition `json:"new_issues"`
+	Resolved  []InspectionTransition `json:"resolved"`
+	// Unchanged counts the current URLs in neither list.
+	Unchanged int `json:"unchanged"`
+}
+
+// HasNewIssues reports whether anything got worse.
+func (d *InspectionDiff) HasNewIssues() bool {
+	return len(d.NewIssues) > 0
+}
+
+// CompareInspections diffs the current run against the previous one. URLs
+// only in the previous run are ignored: the monitored set changes when
+// priority URLs or pattern samples do, and a URL no longer inspected has not
+// recovered. Results are sorted by URL.
+func CompareInspections(previous, current []InspectionSnapshot) *InspectionDiff {
+	diff := &InspectionDiff{NewIssues: []InspectionTransition{}, Resolved: []InspectionTransition{}}
+
+	before := make(map[string]InspectionSnapshot, len(previous))
+	for _, p := range previous {
+		before[p.URL] = p
+	}
+	for _, cur := range current {
+		prev, ok := before[cur.URL]
+		base := InspectionTransition{
+			URL:              cur.URL,
+			PreviousStatus:   prev.IndexStatus,
+			CurrentStatus:    cur.IndexStatus,
+			PreviousCoverage: prev.CoverageState,
+			CurrentCoverage:  cur.CoverageState,
+		}
+
+		worse, better := base, base
+		if !ok {
+			worse.FirstSeen = true
+			worse.Issues = cur.Issues
+			worse.Deindexed = cur.IndexStatus != "PASS"
+		} else {
+			worse.Deindexed = prev.IndexStatus == "PASS" && cur.IndexStatus != "PASS"
+			better.Reindexed = prev.IndexStatus != "PASS" && cur.IndexStatus == "PASS"
+			if prev.IssuesKnown {
+				worse.Issues = subtract(cur.Issues, prev.Issues)
+				better.Issues = subtract(prev.Issues, cur.Issues)
+			}
+		}
+
+		changed := false
+		if worse.Deindexed || len(worse.Issues) > 0 {
+			diff.NewIssues = append(diff.NewIssues, worse)
+			changed = true
+		}
+		if better.Reindexed || len(better.Issues) > 0 {
+			diff.Resolved = append(diff.Resolved, better)
+			changed = true
+		}
+		if !changed {
+			diff.Unchanged++
+		}
+	}
+
+	sort.Slice(diff.NewIssues, func(i, j int) bool { return diff.NewIssues[i].URL < diff.NewIssues[j].URL })
+	sort.Slice(diff.Resolved, func(i, j int) bool { return diff.Resolved[i].URL < diff.Resolved[j].URL })
+	return diff
+}
+
+// subtract returns the elements of a not in b.
+func subtract(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !in[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
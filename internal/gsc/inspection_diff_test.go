@@ -0,0 +1,58 @@
+package gsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareInspections(t *testing.T) {
+	previous := []InspectionSnapshot{
+		{URL: "/stable", IndexStatus: "PASS", Issues: []string{"REDIRECT"}, IssuesKnown: true},
+		{URL: "/dropped", IndexStatus: "PASS", CoverageState: "Submitted and indexed", IssuesKnown: true},
+		{URL: "/fixed", IndexStatus: "FAIL", CoverageState: "Not found (404)", Issues: []string{"NOT_FOUND"}, IssuesKnown: true},
+		{URL: "/swapped", IndexStatus: "PASS", Issues: []string{"ROBOTS_TXT"}, IssuesKnown: true},
+		{URL: "/legacy", IndexStatus: "PASS"},
+		{URL: "/gone", IndexStatus: "FAIL", Issues: []string{"SERVER_ERROR"}, IssuesKnown: true},
+	}
+	current := []InspectionSnapshot{
+		{URL: "/stable", IndexStatus: "PASS", Issues: []string{"REDIRECT"}, IssuesKnown: true},
+		{URL: "/dropped", IndexStatus: "FAIL", CoverageState: "Crawled - currently not indexed", IssuesKnown: true},
+		{URL: "/fixed", IndexStatus: "PASS", CoverageState: "Submitted and indexed", IssuesKnown: true},
+		{URL: "/swapped", IndexStatus: "PASS", Issues: []string{"REDIRECT"}, IssuesKnown: true},
+		{URL: "/legacy", IndexStatus: "PASS", Issues: []string{"REDIRECT"}, IssuesKnown: true},
+		{URL: "/new", IndexStatus: "FAIL", Issues: []string{"NOT_FOUND"}, IssuesKnown: true},
+	}
+
+	diff := CompareInspections(previous, current)
+
+	require.Len(t, diff.NewIssues, 3)
+	assert.Equal(t, InspectionTransition{
+		URL:              "/dropped",
+		PreviousStatus:   "PASS",
+		CurrentStatus:    "FAIL",
+		PreviousCoverage: "Submitted and indexed",
+		CurrentCoverage:  "Crawled - currently not indexed",
+		Deindexed:        true,
+	}, diff.NewIssues[0])
+	assert.Equal(t, "/new", diff.NewIssues[1].URL)
+	assert.True(t, diff.NewIssues[1].FirstSeen)
+	assert.Equal(t, []string{"NOT_FOUND"}, diff.NewIssues[1].Issues)
+	assert.Equal(t, "/swapped", diff.NewIssues[2].URL)
+	assert.Equal(t, []string{"REDIRECT"}, diff.NewIssues[2].Issues)
+
+	require.Len(t, diff.Resolved, 2)
+	assert.Equal(t, "/fixed", diff.Resolved[0].URL)
+	assert.True(t, diff.Resolved[0].Reindexed)
+	assert.Equal(t, []string{"NOT_FOUND"}, diff.Resolved[0].Issues)
+	assert.Equal(t, []string{"ROBOTS_TXT"}, diff.Resolved[1].Issues)
+
+	assert.Equal(t, 2, diff.Unchanged, "/stable, and /legacy whose saved run has no issue types")
+	assert.True(t, diff.HasNewIssues())
+}
+
+func TestIssueTypes(t *testing.T) {
+	got := IssueTypes([]IndexingIssue{{IssueType: "REDIRECT"}, {IssueType: "NOT_FOUND"}, {IssueType: "REDIRECT"}})
+	assert.Equal(t, []string{"NOT_FOUND", "REDIRECT"}, got)
+}
@@ -0,0 +1,55 @@
+package gsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/searchconsole/v1"
+)
+
+func TestTransformInspectionResponse_AMP(t *testing.T) {
+	response := &searchconsole.InspectUrlIndexResponse{
+		InspectionResult: &searchconsole.UrlInspectionResult{
+			IndexStatusResult: &searchconsole.IndexStatusInspectionResult{Verdict: "PASS"},
+			AmpResult: &searchconsole.AmpInspectionResult{
+				AmpUrl:                "https://example.com/amp/post",
+				AmpIndexStatusVerdict: "FAIL",
+				IndexingState:         "AMP_INDEXING_ALLOWED",
+				PageFetchState:        "SUCCESSFUL",
+				Issues: []*searchconsole.AmpIssue{
+					{IssueMessage: "Referenced AMP URL is not an AMP", Severity: "ERROR"},
+					{IssueMessage: "Custom JavaScript is not allowed", Severity: "SEVERITY_UNSPECIFIED"},
+				},
+			},
+		},
+	}
+
+	result := transformInspectionResponse(response, "https://example.com/post")
+
+	require.NotNil(t, result.AMP)
+	assert.Equal(t, "https://example.com/amp/post", result.AMP.URL)
+	assert.Equal(t, "FAIL", result.AMP.Status)
+	assert.Equal(t, "SUCCESSFUL", result.AMP.PageFetchState)
+	assert.Equal(t, []AMPIssue{
+		{Severity: "ERROR", Message: "Referenced AMP URL is not an AMP"},
+		{Severity: "WARNING", Message: "Custom JavaScript is not allowed"},
+	}, result.AMP.Issues)
+	assert.Equal(t, []IndexingIssue{
+		{Severity: "ERROR", Message: "AMP issue: Referenced AMP URL is not an AMP", IssueType: "AMP"},
+		{Severity: "WARNING", Message: "AMP issue: Custom JavaScript is not allowed", IssueType: "AMP"},
+	}, result.IndexingIssues)
+}
+
+func TestTransformInspectionResponse_NoAMP(t *testing.T) {
+	response := &searchconsole.InspectUrlIndexResponse{
+		InspectionResult: &searchconsole.UrlInspectionResult{
+			IndexStatusResult: &searchconsole.IndexStatusInspectionResult{Verdict: "PASS"},
+		},
+	}
+
+	result := transformInspectionResponse(response, "https://example.com/post")
+
+	assert.Nil(t, result.AMP)
+	assert.Empty(t, result.IndexingIssues)
+}
@@ -0,0 +1,204 @@
+// Package intent classifies search queries by the searcher's goal, so
+// Search Analytics can be aggregated the way content strategies are planned:
+// navigational queries look for a specific site or page, transactional ones
+// want to buy or sign up, and informational ones want an answer.
+//
+// Classification is rule based. Each intent has a keyword list; a keyword
+// matches when its words appear consecutively in the query as whole words,
+// and a trailing "*" matches any word with that prefix ("cheap*" matches
+// "cheapest"). When keywords of several intents match, navigational wins
+// over transactional, and transactional over informational: "acme pricing"
+// is someone looking for Acme, and "how to buy a tent" is a buyer.
+package intent
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Intents, in precedence order.
+const (
+	Navigational  = "navigational"
+	Transactional = "transactional"
+	Informational = "informational"
+	// Unclassified is for queries no keyword matches.
+	Unclassified = "unclassified"
+)
+
+// order is the precedence (and report order) of the intents.
+var order = []string{Navigational, Transactional, Informational, Unclassified}
+
+// Default keyword lists. Navigational defaults only cover generic account
+// pages; brand names belong in the config.
+var (
+	DefaultNavigational = []string{"login", "log in", "sign in", "signin", "account", "official site", "website", "homepage", "contact"}
+
+	DefaultTransactional = []string{
+		"buy", "price", "prices", "pricing", "cost", "cheap*", "discount*", "coupon*", "deal", "deals",
+		"order", "purchase", "shop", "sale", "for sale", "subscribe", "subscription", "free trial",
+		"download", "booking", "hire", "quote", "near me",
+	}
+
+	DefaultInformational = []string{
+		"how", "what", "why", "when", "where", "who", "which", "can", "does", "is", "are",
+		"guide", "tutorial", "tips", "example*", "ideas", "meaning", "definition", "vs", "versus",
+		"best", "review*", "compare", "comparison", "learn", "explained",
+	}
+)
+
+// Rules are the keyword lists of a Classifier.
+type Rules struct {
+	Navigational  []string
+	Transactional []string
+	Informational []string
+}
+
+// DefaultRules returns the built-in keyword lists.
+func DefaultRules() Rules {
+	return Rules{
+		Navigational:  append([]string(nil), DefaultNavigational...),
+		Transactional: append([]string(nil), DefaultTransactional...),
+		Informational: append([]string(nil), DefaultInformational...),
+	}
+}
+
+// Merge returns r with extra's keywords appended.
+func (r Rules) Merge(extra Rules) Rules {
+	return Rules{
+		Navigational:  append(append([]string(nil), r.Navigational...), extra.Navigational...),
+		Transactional: append(append([]string(nil), r.Transactional...), extra.Transactional...),
+		Informational: append(append([]string(nil), r.Informational...), extra.Informational...),
+	}
+}
+
+// keyword is one parsed keyword: its words, the last optionally a prefix.
+type keyword struct {
+	words  []string
+	prefix bool
+}
+
+// Classifier assigns an intent to a query. The zero value classifies every
+// query as Unclassified; call New.
+type Classifier struct {
+	rules map[string][]keyword
+}
+
+// New builds a Classifier from rules. Keywords are case-insensitive; empty
+// ones are ignored.
+func New(rules Rules) *Classifier {
+	c := &Classifier{rules: map[string][]keyword{}}
+	for intent, list := range map[string][]string{
+		Navigational:  rules.Navigational,
+		Transactional: rules.Transactional,
+		Informational: rules.Informational,
+	} {
+		for _, k := range list {
+			k = strings.TrimSpace(k)
+			prefix := strings.HasSuffix(k, "*")
+			words := tokens(strings.TrimSuffix(k, "*"))
+			if len(words) == 0 {
+				continue
+			}
+			c.rules[intent] = append(c.rules[intent], keyword{words: words, prefix: prefix})
+		}
+	}
+	return c
+}
+
+// Classify returns the intent of query.
+func (c *Classifier) Classify(query string) string {
+	words := tokens(query)
+	for _, intent := range order[:3] {
+		for _, k := range c.rules[intent] {
+			if k.matches(words) {
+				return intent
+			}
+		}
+	}
+	return Unclassified
+}
+
+func (k keyword) matches(words []string) bool {
+	n := len(k.words)
+	for i := 0; i+n <= len(words); i++ {
+		ok := true
+		for j, w := range k.words {
+			last := j == n-1
+			if words[i+j] != w && !(last && k.prefix && strings.HasPrefix(words[i+j], w)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tokens lowercases s and splits it into words of letters and digits.
+// Apostrophes are dropped so "what's" is one word.
+func tokens(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "'", "")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Row is one query's Search Analytics metrics.
+type Row struct {
+	Query       string
+	Clicks      int64
+	Impressions int64
+	Position    float64
+}
+
+// Summary aggregates the queries of one intent. Position is weighted by
+// impressions, as Search Console does.
+type Summary struct {
+	Intent      string  `json:"intent"`
+	Queries     int     `json:"queries"`
+	Clicks      int64   `json:"clicks"`
+	Impressions int64   `json:"impressions"`
+	CTR         float64 `json:"ctr"`
+	Position    float64 `json:"position"`
+	ClickShare  float64 `json:"click_share"`
+}
+
+// Summarise classifies each row and aggregates by intent, in precedence
+// order. Intents without queries are omitted.
+func (c *Classifier) Summarise(rows []Row) []Summary {
+	byIntent := make(map[string]*Summary)
+	weighted := make(map[string]float64)
+	var totalClicks int64
+	for _, r := range rows {
+		intent := c.Classify(r.Query)
+		s, ok := byIntent[intent]
+		if !ok {
+			s = &Summary{Intent: intent}
+			byIntent[intent] = s
+		}
+		s.Queries++
+		s.Clicks += r.Clicks
+		s.Impressions += r.Impressions
+		weighted[intent] += r.Position * float64(r.Impressions)
+		totalClicks += r.Clicks
+	}
+
+	out := make([]Summary, 0, len(byIntent))
+	for _, intent := range order {
+		s, ok := byIntent[intent]
+		if !ok {
+			continue
+		}
+		if s.Impressions > 0 {
+			s.CTR = float64(s.Clicks) / float64(s.Impressions)
+			s.Position = weighted[intent] / float64(s.Impressions)
+		}
+		if totalClicks > 0 {
+			s.ClickShare = float64(s.Clicks) / float64(totalClicks)
+		}
+		out = append(out, *s)
+	}
+	return out
+}
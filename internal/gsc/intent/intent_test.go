@@ -0,0 +1,52 @@
+package intent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	c := New(DefaultRules().Merge(Rules{Navigational: []string{"Acme", "acme cloud"}}))
+	for query, want := range map[string]string{
+		"acme pricing":                Navigational,
+		"ACME login":                  Navigational,
+		"how to buy a tent":           Transactional,
+		"cheapest tents":              Transactional,
+		"plumber near me":             Transactional,
+		"what's the best tent":        Informational,
+		"tent vs tarp":                Informational,
+		"tent reviews":                Informational,
+		"tents":                       Unclassified,
+		"shopping tips":               Informational,
+		"acmeish tents":               Unclassified,
+		"  discounted   camping gear": Transactional,
+	} {
+		assert.Equal(t, want, c.Classify(query), query)
+	}
+}
+
+func TestClassify_NoRules(t *testing.T) {
+	assert.Equal(t, Unclassified, New(Rules{Informational: []string{"", "*"}}).Classify("how to"))
+}
+
+func TestSummarise(t *testing.T) {
+	c := New(Rules{Navigational: []string{"acme"}, Informational: []string{"how"}})
+	got := c.Summarise([]Row{
+		{Query: "how to pitch a tent", Clicks: 10, Impressions: 100, Position: 4},
+		{Query: "acme", Clicks: 30, Impressions: 40, Position: 1},
+		{Query: "how tents work", Clicks: 0, Impressions: 300, Position: 12},
+		{Query: "tents", Clicks: 0, Impressions: 0, Position: 0},
+	})
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{Navigational, Informational, Unclassified}, []string{got[0].Intent, got[1].Intent, got[2].Intent})
+
+	info := got[1]
+	assert.Equal(t, 2, info.Queries)
+	assert.Equal(t, int64(10), info.Clicks)
+	assert.InDelta(t, 0.025, info.CTR, 1e-9)
+	assert.InDelta(t, 10, info.Position, 1e-9)
+	assert.InDelta(t, 0.25, info.ClickShare, 1e-9)
+	assert.Zero(t, got[2].Position)
+}
@@ -1,17 +1,41 @@
 package gsc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"google.golang.org/api/searchconsole/v1"
+)
+
+// Search Console permission levels, as returned by the sites API.
+const (
+	PermissionUnverified = "siteUnverifiedUser"
+	PermissionRestricted = "siteRestrictedUser"
+	PermissionFull       = "siteFullUser"
+	PermissionOwner      = "siteOwner"
 )
 
+// permissionRank orders the permission levels by privilege.
+var permissionRank = map[string]int{
+	PermissionRestricted: 1,
+	PermissionFull:       2,
+	PermissionOwner:      3,
+}
+
+// PermissionRank returns a permission level's privilege order: 0 for no
+// access or an unverified user, 3 for siteOwner.
+func PermissionRank(level string) int {
+	return permissionRank[level]
+}
+
 // writePermissionLevels are the GSC permission levels that allow write
 // operations such as submitting or deleting sitemaps. siteRestrictedUser and
 // below are read-only for these operations.
 var writePermissionLevels = map[string]bool{
-	"siteOwner":    true,
-	"siteFullUser": true,
+	PermissionOwner: true,
+	PermissionFull:  true,
 }
 
 // SitePermission describes the authenticated principal's access to a property.
@@ -19,6 +43,26 @@ type SitePermission struct {
 	SiteURL         string `json:"site_url"`
 	PermissionLevel string `json:"permission_level"`
 	CanWrite        bool   `json:"can_write"`
+	// Verified reports whether ownership of the property has been verified
+	// for the principal. An unverified property is listed, but none of its
+	// data can be read.
+	Verified bool `json:"verified"`
+}
+
+// newSitePermission builds a SitePermission from a sites API entry.
+func newSitePermission(site *searchconsole.WmxSite) SitePermission {
+	return SitePermission{
+		SiteURL:         site.SiteUrl,
+		PermissionLevel: site.PermissionLevel,
+		CanWrite:        CanWritePermission(site.PermissionLevel),
+		Verified:        IsVerifiedPermission(site.PermissionLevel),
+	}
+}
+
+// IsVerifiedPermission reports whether a GSC permission level belongs to a
+// verified user of the property.
+func IsVerifiedPermission(level string) bool {
+	return PermissionRank(level) > 0
 }
 
 // CanWritePermission reports whether a GSC permission level allows write
@@ -33,15 +77,17 @@ func (c *Client) GetSitePermission(siteURL string) (*SitePermission, error) {
 	if err := c.waitForRateLimit("GetSitePermission"); err != nil {
 		return nil, err
 	}
-	site, err := c.service.Sites.Get(siteURL).Context(c.ctx).Do()
+	var site *searchconsole.WmxSite
+	err := c.call("get", "site", siteURL, func(ctx context.Context) error {
+		var err error
+		site, err = c.service.Sites.Get(siteURL).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get site permission for %s: %w", siteURL, err)
 	}
-	return &SitePermission{
-		SiteURL:         site.SiteUrl,
-		PermissionLevel: site.PermissionLevel,
-		CanWrite:        CanWritePermission(site.PermissionLevel),
-	}, nil
+	perm := newSitePermission(site)
+	return &perm, nil
 }
 
 // ListSitePermissions returns every property the authenticated principal can
@@ -50,21 +96,48 @@ func (c *Client) ListSitePermissions() ([]SitePermission, error) {
 	if err := c.waitForRateLimit("ListSitePermissions"); err != nil {
 		return nil, err
 	}
-	resp, err := c.service.Sites.List().Context(c.ctx).Do()
+	var resp *searchconsole.SitesListResponse
+	err := c.call("list", "sites", "", func(ctx context.Context) error {
+		var err error
+		resp, err = c.service.Sites.List().Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sites: %w", err)
 	}
 	perms := make([]SitePermission, 0, len(resp.SiteEntry))
 	for _, s := range resp.SiteEntry {
-		perms = append(perms, SitePermission{
-			SiteURL:         s.SiteUrl,
-			PermissionLevel: s.PermissionLevel,
-			CanWrite:        CanWritePermission(s.PermissionLevel),
-		})
+		perms = append(perms, newSitePermission(s))
 	}
 	return perms, nil
 }
 
+// AddSite adds a property to the authenticated principal's Search Console
+// account via sites.add and returns its permission afterwards. Adding does
+// not verify ownership: until the property is verified (in the Search
+// Console UI, or by an owner adding the principal as a user) it is listed
+// as siteUnverifiedUser. Adding a property that is already present is a
+// no-op.
+func (c *Client) AddSite(siteURL string) (*SitePermission, error) {
+	if err := validateSiteURL(siteURL); err != nil {
+		return nil, err
+	}
+	if err := c.waitForRateLimit("AddSite"); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("adding site", "site_url", siteURL)
+
+	err := c.change("create", "site", siteURL, siteURL, func(ctx context.Context) error {
+		return c.service.Sites.Add(siteURL).Context(ctx).Do()
+	})
+	if err != nil {
+		c.logger.Error("failed to add site", "site_url", siteURL, "error", err)
+		return nil, fmt.Errorf("failed to add site %s: %w", siteURL, err)
+	}
+	return c.GetSitePermission(siteURL)
+}
+
 // ServiceAccountIdentity is the principal behind GOOGLE_APPLICATION_CREDENTIALS.
 type ServiceAccountIdentity struct {
 	ClientEmail    string `json:"client_email"`
@@ -0,0 +1,95 @@
+package gsc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/searchconsole/v1"
+)
+
+// newSitesClient returns a client whose sites endpoint adds properties to
+// an in-memory account; added properties are unverified unless listed in
+// verified.
+func newSitesClient(t *testing.T, verified map[string]string) (*Client, *[]string) {
+	t.Helper()
+	var methods []string
+	added := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		site := r.URL.Path[len("/webmasters/v3/sites/"):]
+		switch r.Method {
+		case http.MethodPut:
+			added[site] = true
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			level, ok := verified[site]
+			if !ok && added[site] {
+				level = PermissionUnverified
+			}
+			if level == "" {
+				http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(searchconsole.WmxSite{SiteUrl: site, PermissionLevel: level})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	service, err := searchconsole.NewService(context.Background(), option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+	return &Client{
+		service:      service,
+		rateLimiter:  rate.NewLimiter(rate.Inf, 1),
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ctx:          context.Background(),
+		timeout:      10 * time.Second,
+		quotaTracker: newQuotaTracker("inspections", DefaultDailyLimit),
+	}, &methods
+}
+
+func TestAddSite(t *testing.T) {
+	t.Run("new property is unverified", func(t *testing.T) {
+		client, methods := newSitesClient(t, nil)
+		perm, err := client.AddSite("sc-domain:example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []string{http.MethodPut, http.MethodGet}, *methods)
+		assert.Equal(t, "sc-domain:example.com", perm.SiteURL)
+		assert.Equal(t, PermissionUnverified, perm.PermissionLevel)
+		assert.False(t, perm.Verified)
+		assert.False(t, perm.CanWrite)
+	})
+
+	t.Run("property already shared with the account", func(t *testing.T) {
+		client, _ := newSitesClient(t, map[string]string{"https://example.com/": PermissionFull})
+		perm, err := client.AddSite("https://example.com/")
+		require.NoError(t, err)
+		assert.True(t, perm.Verified)
+		assert.True(t, perm.CanWrite)
+	})
+
+	t.Run("invalid site URL is rejected before calling the API", func(t *testing.T) {
+		client, methods := newSitesClient(t, nil)
+		_, err := client.AddSite("https://example.com")
+		require.Error(t, err)
+		assert.Empty(t, *methods)
+	})
+}
+
+func TestIsVerifiedPermission(t *testing.T) {
+	assert.True(t, IsVerifiedPermission(PermissionOwner))
+	assert.True(t, IsVerifiedPermission(PermissionFull))
+	assert.True(t, IsVerifiedPermission(PermissionRestricted))
+	assert.False(t, IsVerifiedPermission(PermissionUnverified))
+	assert.False(t, IsVerifiedPermission(""))
+}
@@ -0,0 +1,258 @@
+// Package publishing measures how quickly newly published pages start
+// earning search traffic, as a KPI for a site's publishing pipeline.
+//
+// For each page it finds the first day with an impression (the page is
+// indexed and ranking by then), the first day with a click, and its totals
+// over the 28 days after publication. Time to index is the number of days
+// from publication to first impression; Search Console does not expose the
+// indexing date itself, so this is an upper bound.
+//
+// The package is pure: callers read published dates (a CSV or sitemap
+// <lastmod>) and daily page traffic, and pass them to Measure.
+package publishing
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+)
+
+// Window is the number of days after publication whose traffic is totalled.
+const Window = 28
+
+const dateLayout = "2006-01-02"
+
+// Page is a URL and the day it was published.
+type Page struct {
+	URL       string
+	Published time.Time
+}
+
+// Day is one day of Search Analytics totals for a page.
+type Day struct {
+	Date        time.Time
+	Clicks      int64
+	Impressions int64
+}
+
+// Impact is the search performance of one published page.
+type Impact struct {
+	URL             string `json:"url"`
+	Group           string `json:"group"`
+	Published       string `json:"published"`
+	FirstImpression string `json:"first_impression,omitempty"`
+	FirstClick      string `json:"first_click,omitempty"`
+	// DaysToIndex and DaysToClick are nil until the first impression or
+	// click happens.
+	DaysToIndex *int  `json:"days_to_index,omitempty"`
+	DaysToClick *int  `json:"days_to_click,omitempty"`
+	Clicks      int64 `json:"clicks_28d"`
+	Impressions int64 `json:"impressions_28d"`
+	// Complete is false while the 28-day window is still open, so Clicks and
+	// Impressions are partial.
+	Complete bool `json:"complete"`
+}
+
+// GroupSummary aggregates the pages of one path prefix.
+type GroupSummary struct {
+	Name    string `json:"name"`
+	Pages   int    `json:"pages"`
+	Indexed int    `json:"indexed"`
+	Clicked int    `json:"clicked"`
+	// MedianDaysToIndex is over indexed pages only; nil when none are.
+	MedianDaysToIndex *float64 `json:"median_days_to_index,omitempty"`
+	MedianDaysToClick *float64 `json:"median_days_to_click,omitempty"`
+	Clicks            int64    `json:"clicks_28d"`
+	Impressions       int64    `json:"impressions_28d"`
+}
+
+// Report is the publishing impact of a set of pages.
+type Report struct {
+	Pages   []Impact       `json:"pages"`
+	Groups  []GroupSummary `json:"groups"`
+	Overall GroupSummary   `json:"overall"`
+}
+
+// Measure computes the impact of each page from its daily traffic, keyed by
+// URL. Days before publication are ignored, so a republished URL is measured
+// from its new date. end is the last day with data; a page's window is
+// complete once it covers 28 days up to end. Pages are grouped by path
+// prefix at depth (see sampling.GroupOf) and listed newest first.
+func Measure(pages []Page, daily map[string][]Day, end time.Time, depth int) Report {
+	end = day(end)
+	r := Report{Pages: make([]Impact, 0, len(pages)), Overall: GroupSummary{Name: "all"}}
+	for _, p := range pages {
+		published := day(p.Published)
+		im := Impact{
+			URL:       p.URL,
+			Group:     sampling.GroupOf(p.URL, depth),
+			Published: published.Format(dateLayout),
+			Complete:  !published.AddDate(0, 0, Window-1).After(end),
+		}
+		days := append([]Day(nil), daily[p.URL]...)
+		sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+		cutoff := published.AddDate(0, 0, Window)
+		for _, d := range days {
+			date := day(d.Date)
+			if date.Before(published) {
+				continue
+			}
+			if d.Impressions > 0 && im.DaysToIndex == nil {
+				im.FirstImpression = date.Format(dateLayout)
+				im.DaysToIndex = daysBetween(published, date)
+			}
+			if d.Clicks > 0 && im.DaysToClick == nil {
+				im.FirstClick = date.Format(dateLayout)
+				im.DaysToClick = daysBetween(published, date)
+			}
+			if date.Before(cutoff) {
+				im.Clicks += d.Clicks
+				im.Impressions += d.Impressions
+			}
+		}
+		r.Pages = append(r.Pages, im)
+	}
+	sort.SliceStable(r.Pages, func(i, j int) bool {
+		if r.Pages[i].Published != r.Pages[j].Published {
+			return r.Pages[i].Published > r.Pages[j].Published
+		}
+		return r.Pages[i].URL < r.Pages[j].URL
+	})
+
+	groups := make(map[string][]Impact)
+	for _, im := range r.Pages {
+		groups[im.Group] = append(groups[im.Group], im)
+	}
+	for name, impacts := range groups {
+		r.Groups = append(r.Groups, summarise(name, impacts))
+	}
+	sort.Slice(r.Groups, func(i, j int) bool {
+		if r.Groups[i].Pages != r.Groups[j].Pages {
+			return r.Groups[i].Pages > r.Groups[j].Pages
+		}
+		return r.Groups[i].Name < r.Groups[j].Name
+	})
+	r.Overall = summarise("all", r.Pages)
+	return r
+}
+
+func summarise(name string, impacts []Impact) GroupSummary {
+	g := GroupSummary{Name: name, Pages: len(impacts)}
+	var toIndex, toClick []int
+	for _, im := range impacts {
+		g.Clicks += im.Clicks
+		g.Impressions += im.Impressions
+		if im.DaysToIndex != nil {
+			g.Indexed++
+			toIndex = append(toIndex, *im.DaysToIndex)
+		}
+		if im.DaysToClick != nil {
+			g.Clicked++
+			toClick = append(toClick, *im.DaysToClick)
+		}
+	}
+	g.MedianDaysToIndex = median(toIndex)
+	g.MedianDaysToClick = median(toClick)
+	return g
+}
+
+func median(values []int) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Ints(values)
+	mid := len(values) / 2
+	m := float64(values[mid])
+	if len(values)%2 == 0 {
+		m = float64(values[mid-1]+values[mid]) / 2
+	}
+	return &m
+}
+
+// day truncates t to its calendar date in t's location, as UTC midnight.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) *int {
+	n := int(to.Sub(from).Hours() / 24)
+	return &n
+}
+
+// Since returns the pages published on or after from, newest first.
+func Since(pages []Page, from time.Time) []Page {
+	from = day(from)
+	var out []Page
+	for _, p := range pages {
+		if !day(p.Published).Before(from) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Published.After(out[j].Published) })
+	return out
+}
+
+// ReadCSV reads url,published rows. A header row is skipped when its second
+// column is not a date. Dates are YYYY-MM-DD or RFC 3339. A URL listed twice
+// keeps its latest date.
+func ReadCSV(r io.Reader) ([]Page, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var pages []Page
+	index := make(map[string]int)
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+			continue
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("line %d: want url,published, got %d column(s)", line, len(rec))
+		}
+		loc, date := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		published, err := parseDate(date)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if u, err := url.Parse(loc); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("line %d: %q is not an absolute URL", line, loc)
+		}
+		if i, ok := index[loc]; ok {
+			if published.After(pages[i].Published) {
+				pages[i].Published = published
+			}
+			continue
+		}
+		index[loc] = len(pages)
+		pages = append(pages, Page{URL: loc, Published: published})
+	}
+	return pages, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+}
@@ -0,0 +1,111 @@
+package publishing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestMeasure(t *testing.T) {
+	pages := []Page{
+		{URL: "https://example.com/blog/a", Published: date("2026-05-01")},
+		{URL: "https://example.com/blog/b", Published: date("2026-05-10")},
+		{URL: "https://example.com/blog/c", Published: date("2026-05-20")},
+		{URL: "https://example.com/docs/x", Published: date("2026-06-01")},
+	}
+	daily := map[string][]Day{
+		"https://example.com/blog/a": {
+			{Date: date("2026-05-30"), Clicks: 5, Impressions: 50}, // outside the 28 days
+			{Date: date("2026-05-03"), Impressions: 10},
+			{Date: date("2026-04-20"), Clicks: 9, Impressions: 90}, // before publication
+			{Date: date("2026-05-06"), Clicks: 2, Impressions: 20},
+		},
+		"https://example.com/blog/b": {{Date: date("2026-05-17"), Impressions: 4}},
+		"https://example.com/docs/x": {{Date: date("2026-06-02"), Clicks: 1, Impressions: 3}},
+	}
+
+	r := Measure(pages, daily, date("2026-06-05"), 1)
+
+	require.Len(t, r.Pages, 4)
+	assert.Equal(t, "https://example.com/docs/x", r.Pages[0].URL, "newest first")
+	a := r.Pages[3]
+	assert.Equal(t, "2026-05-03", a.FirstImpression)
+	assert.Equal(t, "2026-05-06", a.FirstClick)
+	assert.Equal(t, 2, *a.DaysToIndex)
+	assert.Equal(t, 5, *a.DaysToClick)
+	assert.Equal(t, int64(2), a.Clicks)
+	assert.Equal(t, int64(30), a.Impressions)
+	assert.True(t, a.Complete)
+	assert.False(t, r.Pages[0].Complete)
+	assert.Nil(t, r.Pages[1].DaysToIndex, "blog/c has no traffic yet")
+
+	require.Len(t, r.Groups, 2)
+	blog := r.Groups[0]
+	assert.Equal(t, "/blog/", blog.Name)
+	assert.Equal(t, 3, blog.Pages)
+	assert.Equal(t, 2, blog.Indexed)
+	assert.Equal(t, 1, blog.Clicked)
+	assert.InDelta(t, 4.5, *blog.MedianDaysToIndex, 1e-9)
+	assert.InDelta(t, 5, *blog.MedianDaysToClick, 1e-9)
+
+	assert.Equal(t, 4, r.Overall.Pages)
+	assert.Equal(t, 3, r.Overall.Indexed)
+	assert.InDelta(t, 2, *r.Overall.MedianDaysToIndex, 1e-9)
+	assert.Equal(t, int64(3), r.Overall.Clicks)
+}
+
+func TestMeasure_NothingIndexed(t *testing.T) {
+	r := Measure([]Page{{URL: "https://example.com/new", Published: date("2026-06-01")}}, nil, date("2026-06-05"), 1)
+	assert.Zero(t, r.Overall.Indexed)
+	assert.Nil(t, r.Overall.MedianDaysToIndex)
+}
+
+func TestSince(t *testing.T) {
+	pages := []Page{
+		{URL: "https://example.com/old", Published: date("2026-01-01")},
+		{URL: "https://example.com/a", Published: date("2026-05-01")},
+		{URL: "https://example.com/b", Published: date("2026-05-03").Add(15 * time.Hour)},
+	}
+	got := Since(pages, date("2026-05-01").Add(12*time.Hour))
+	require.Len(t, got, 2)
+	assert.Equal(t, "https://example.com/b", got[0].URL)
+	assert.Equal(t, "https://example.com/a", got[1].URL)
+}
+
+func TestReadCSV(t *testing.T) {
+	in := `url,published
+https://example.com/a, 2026-05-01
+https://example.com/b,2026-05-02T09:30:00+02:00
+
+https://example.com/a,2026-05-04
+`
+	pages, err := ReadCSV(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, Page{URL: "https://example.com/a", Published: date("2026-05-04")}, pages[0])
+	assert.Equal(t, "2026-05-02", pages[1].Published.Format(dateLayout))
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	for name, in := range map[string]string{
+		"one column":   "https://example.com/a\n",
+		"bad date":     "https://example.com/a,2026-05-01\nhttps://example.com/b,May 2\n",
+		"relative URL": "/a,2026-05-01\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(in))
+			assert.Error(t, err)
+		})
+	}
+}
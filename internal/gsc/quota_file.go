@@ -0,0 +1,164 @@
+package gsc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// QuotaFileEnvVar overrides where quota counts are shared between processes;
+// "off" keeps them per process.
+const QuotaFileEnvVar = "GA4_QUOTA_FILE"
+
+// DefaultQuotaFile returns the file quota counts are shared in: $GA4_QUOTA_FILE,
+// else gsc-quota.json under $XDG_STATE_HOME/ga4-manager or
+// ~/.local/state/ga4-manager. It returns "" when sharing is turned off.
+func DefaultQuotaFile() (string, error) {
+	if path := os.Getenv(QuotaFileEnvVar); path != "" {
+		if path == "off" {
+			return "", nil
+		}
+		return path, nil
+	}
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate the home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "ga4-manager", "gsc-quota.json"), nil
+}
+
+// quotaState is the quota file: counts by date, unit and property. Only
+// today's date is kept.
+type quotaState map[string]map[string]map[string]int
+
+// Lock file timings: how long update waits for another process, and when a
+// lock left behind by a crashed one is broken.
+const (
+	quotaLockWait  = 10 * time.Second
+	quotaLockStale = 30 * time.Second
+)
+
+// quotaFile keeps quota counts in a JSON file shared by every process. Writes
+// go through a temp file and rename, so readers never see a partial file;
+// read-modify-write cycles are serialised by a lock file, which works the
+// same on every platform.
+type quotaFile struct {
+	path string
+}
+
+// update applies fn to today's count of unit for key under the lock and
+// saves the result. fn's error is returned after the new count is saved.
+func (f *quotaFile) update(now time.Time, unit, key string, fn func(count int) (int, error)) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := f.load()
+	if err != nil {
+		return err
+	}
+	date := now.Format("2006-01-02")
+	today := state[date]
+	if today == nil {
+		today = map[string]map[string]int{}
+	}
+	if today[unit] == nil {
+		today[unit] = map[string]int{}
+	}
+
+	before := today[unit][key]
+	count, fnErr := fn(before)
+	if count != before {
+		today[unit][key] = count
+		if err := f.save(quotaState{date: today}); err != nil {
+			return err
+		}
+	}
+	return fnErr
+}
+
+// read returns today's counts of unit by property.
+func (f *quotaFile) read(now time.Time, unit string) (map[string]int, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	return state[now.Format("2006-01-02")][unit], nil
+}
+
+func (f *quotaFile) load() (quotaState, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return quotaState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota file: %w", err)
+	}
+	state := quotaState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("quota file %s is corrupt (delete it to reset today's counts): %w", f.path, err)
+	}
+	return state, nil
+}
+
+func (f *quotaFile) save(state quotaState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".gsc-quota-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write quota file: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write quota file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write quota file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write quota file: %w", err)
+	}
+	return nil
+}
+
+// lock takes the lock file next to the quota file, waiting for another
+// process to release it, and returns the function that releases it.
+func (f *quotaFile) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(f.path), err)
+	}
+	lockPath := f.path + ".lock"
+	deadline := time.Now().Add(quotaLockWait)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = lock.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock quota file: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > quotaLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("quota file %s is locked by another ga4 process (delete the .lock file if none is running)", f.path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
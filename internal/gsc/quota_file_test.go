@@ -0,0 +1,112 @@
+package gsc
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedTracker returns a tracker counting in path, as a separate process
+// would.
+func sharedTracker(path string, dailyLimit int) *QuotaTracker {
+	q := newQuotaTracker("inspections", dailyLimit)
+	q.file = &quotaFile{path: path}
+	return q
+}
+
+// Two processes (here two trackers) draw on one budget per property.
+func TestQuotaFile_SharedAcrossTrackers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "state", "gsc-quota.json")
+	cronA, cronB := sharedTracker(path, 20), sharedTracker(path, 20)
+
+	for range 10 {
+		require.NoError(t, cronA.use(logger, "sc-domain:a.com"))
+	}
+	used, _, _ := cronB.status()
+	assert.Equal(t, 10, used, "before any call, the busiest property today")
+
+	for range 9 {
+		require.NoError(t, cronB.use(logger, "sc-domain:a.com"))
+	}
+	err := cronA.use(logger, "sc-domain:a.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "19/20 inspections used")
+
+	// Another property has its own quota
+	require.NoError(t, cronB.use(logger, "sc-domain:b.com"))
+	used, _, _ = cronB.status()
+	assert.Equal(t, 1, used)
+	assert.Equal(t, 0, cronA.remaining())
+}
+
+func TestQuotaFile_KeepsOnlyToday(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "gsc-quota.json")
+	stale, err := json.Marshal(quotaState{"2020-01-01": {"inspections": {"sc-domain:a.com": 1899}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, stale, 0o600))
+
+	q := sharedTracker(path, DefaultDailyLimit)
+	require.NoError(t, q.use(logger, "sc-domain:a.com"))
+
+	var state quotaState
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &state))
+	assert.Equal(t, quotaState{time.Now().Format("2006-01-02"): {"inspections": {"sc-domain:a.com": 1}}}, state)
+}
+
+func TestQuotaFile_ConcurrentUpdates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "gsc-quota.json")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		q := sharedTracker(path, DefaultDailyLimit)
+		wg.Go(func() {
+			for range 25 {
+				assert.NoError(t, q.use(logger, "sc-domain:a.com"))
+			}
+		})
+	}
+	wg.Wait()
+
+	used, _, _ := sharedTracker(path, DefaultDailyLimit).status()
+	assert.Equal(t, 100, used)
+}
+
+func TestQuotaFile_BreaksStaleLock(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "gsc-quota.json")
+	require.NoError(t, os.WriteFile(path+".lock", nil, 0o600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path+".lock", old, old))
+
+	require.NoError(t, sharedTracker(path, DefaultDailyLimit).use(logger, "sc-domain:a.com"))
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestDefaultQuotaFile(t *testing.T) {
+	t.Setenv(QuotaFileEnvVar, "")
+	t.Setenv("XDG_STATE_HOME", "/state")
+	path, err := DefaultQuotaFile()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/state", "ga4-manager", "gsc-quota.json"), path)
+
+	t.Setenv(QuotaFileEnvVar, "/tmp/q.json")
+	path, _ = DefaultQuotaFile()
+	assert.Equal(t, "/tmp/q.json", path)
+
+	t.Setenv(QuotaFileEnvVar, "off")
+	path, _ = DefaultQuotaFile()
+	assert.Empty(t, path)
+}
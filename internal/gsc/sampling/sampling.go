@@ -0,0 +1,453 @@
+// Package sampling plans which URLs of a large site to inspect when the daily
+// URL Inspection quota (2,000 per property) cannot cover every page.
+//
+// Pages are grouped by path prefix, the inspection budget is allocated across
+// groups in proportion to their search traffic, and URLs within a group are
+// drawn in a stable pseudo-random order so consecutive runs track the same
+// pages. The resulting stratified sample supports coverage estimates with a
+// stated margin of error; see Plan.MarginOfError and Estimate.
+//
+// The package is pure: it performs no I/O and does not depend on the GSC
+// client. Callers fetch page traffic, persist the Plan (as JSON) and feed
+// inspection outcomes back into Estimate.
+package sampling
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LargeSiteThreshold is the page count above which sampling is recommended.
+// It matches the Search Analytics page size: below it one request returns
+// every page and a census is usually affordable over a few days.
+const LargeSiteThreshold = 25000
+
+// z95 is the two-sided z-score for a 95% confidence interval.
+const z95 = 1.96
+
+// Defaults for Options.
+const (
+	DefaultBudget      = 500
+	DefaultDepth       = 1
+	DefaultMinPerGroup = 5
+	MetricImpressions  = "impressions"
+	MetricClicks       = "clicks"
+)
+
+// ErrNoPages is returned when there is nothing to sample.
+var ErrNoPages = errors.New("sampling: no pages with search traffic")
+
+// Page is one URL and its search traffic over the planning window.
+type Page struct {
+	URL         string
+	Clicks      int64
+	Impressions int64
+}
+
+// Options controls how a Plan is built. Zero values take the defaults above.
+type Options struct {
+	// Budget is the number of URLs to inspect per run.
+	Budget int
+	// Depth is how many path segments form a group ("/blog/2024/x" at depth
+	// 1 is "/blog/", at depth 2 "/blog/2024/").
+	Depth int
+	// MinPerGroup is the floor each group gets before proportional
+	// allocation, so small groups still yield an estimate.
+	MinPerGroup int
+	// Metric is the traffic measure used for allocation: impressions or clicks.
+	Metric string
+	// Seed fixes the within-group draw order. It defaults to the site, so
+	// plans for the same site pick the same URLs.
+	Seed string
+}
+
+func (o Options) withDefaults(site string) Options {
+	if o.Budget <= 0 {
+		o.Budget = DefaultBudget
+	}
+	if o.Depth <= 0 {
+		o.Depth = DefaultDepth
+	}
+	if o.MinPerGroup <= 0 {
+		o.MinPerGroup = DefaultMinPerGroup
+	}
+	if o.Metric == "" {
+		o.Metric = MetricImpressions
+	}
+	if o.Seed == "" {
+		o.Seed = site
+	}
+	return o
+}
+
+// Group is one stratum of the plan.
+type Group struct {
+	Name         string   `json:"name"`
+	Pages        int      `json:"pages"`
+	Traffic      int64    `json:"traffic"`
+	TrafficShare float64  `json:"traffic_share"`
+	Allocated    int      `json:"allocated"`
+	URLs         []string `json:"urls"`
+	// MarginOfError is the worst-case (p = 0.5) half-width of the 95%
+	// confidence interval for the share of this group's pages in any given
+	// coverage state, with finite-population correction. 0 for a census.
+	MarginOfError float64 `json:"margin_of_error"`
+}
+
+// Plan is a persisted sample. Same-site re-plans keep URLs from the previous
+// plan where they are still eligible, so history follows the same pages.
+type Plan struct {
+	Site        string    `json:"site"`
+	CreatedAt   time.Time `json:"created_at"`
+	StartDate   string    `json:"start_date,omitempty"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Metric      string    `json:"metric"`
+	Depth       int       `json:"depth"`
+	Budget      int       `json:"budget"`
+	Seed        string    `json:"seed"`
+	TotalPages  int       `json:"total_pages"`
+	SampledURLs int       `json:"sampled_urls"`
+	Retained    int       `json:"retained"`
+	Groups      []Group   `json:"groups"`
+	// MarginOfError is the worst-case 95% half-width for a site-wide share,
+	// combining the groups by page count (stratified estimator).
+	MarginOfError float64 `json:"margin_of_error"`
+}
+
+// URLs returns every sampled URL, group by group.
+func (p *Plan) URLs() []string {
+	out := make([]string, 0, p.SampledURLs)
+	for _, g := range p.Groups {
+		out = append(out, g.URLs...)
+	}
+	return out
+}
+
+// GroupOf returns the group name for rawURL at the given depth.
+func GroupOf(rawURL string, depth int) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return "/"
+	}
+	// Unless the path ends in "/", the last segment is the page itself.
+	if !strings.HasSuffix(path, "/") {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) == 0 {
+		return "/"
+	}
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	return "/" + strings.Join(segments, "/") + "/"
+}
+
+// Build plans a sample of pages for site. previous, if non-nil, is the last
+// persisted plan; its URLs are kept first in each group so the sample is
+// stable across runs.
+func Build(site string, pages []Page, opts Options, previous *Plan) (*Plan, error) {
+	opts = opts.withDefaults(site)
+	if opts.Metric != MetricImpressions && opts.Metric != MetricClicks {
+		return nil, fmt.Errorf("sampling: unknown metric %q (want %s or %s)", opts.Metric, MetricImpressions, MetricClicks)
+	}
+	if len(pages) == 0 {
+		return nil, ErrNoPages
+	}
+
+	type bucket struct {
+		name    string
+		traffic int64
+		urls    []string
+	}
+	byName := map[string]*bucket{}
+	seen := map[string]bool{}
+	for _, p := range pages {
+		if seen[p.URL] {
+			continue
+		}
+		seen[p.URL] = true
+		name := GroupOf(p.URL, opts.Depth)
+		b := byName[name]
+		if b == nil {
+			b = &bucket{name: name}
+			byName[name] = b
+		}
+		b.traffic += traffic(p, opts.Metric)
+		b.urls = append(b.urls, p.URL)
+	}
+
+	buckets := make([]*bucket, 0, len(byName))
+	var totalTraffic int64
+	for _, b := range byName {
+		buckets = append(buckets, b)
+		totalTraffic += b.traffic
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].traffic != buckets[j].traffic {
+			return buckets[i].traffic > buckets[j].traffic
+		}
+		return buckets[i].name < buckets[j].name
+	})
+
+	sizes := make([]int, len(buckets))
+	weights := make([]float64, len(buckets))
+	for i, b := range buckets {
+		sizes[i] = len(b.urls)
+		weights[i] = float64(b.traffic)
+	}
+	alloc := allocate(sizes, weights, opts.Budget, opts.MinPerGroup)
+
+	kept := map[string]bool{}
+	if previous != nil && previous.Site == site {
+		for _, u := range previous.URLs() {
+			kept[u] = true
+		}
+	}
+
+	plan := &Plan{
+		Site:       site,
+		CreatedAt:  time.Now().UTC(),
+		Metric:     opts.Metric,
+		Depth:      opts.Depth,
+		Budget:     opts.Budget,
+		Seed:       opts.Seed,
+		TotalPages: len(seen),
+		Groups:     make([]Group, len(buckets)),
+	}
+	for i, b := range buckets {
+		urls := drawOrder(b.urls, opts.Seed, kept)[:alloc[i]]
+		for _, u := range urls {
+			if kept[u] {
+				plan.Retained++
+			}
+		}
+		share := 0.0
+		if totalTraffic > 0 {
+			share = float64(b.traffic) / float64(totalTraffic)
+		}
+		plan.Groups[i] = Group{
+			Name:          b.name,
+			Pages:         len(b.urls),
+			Traffic:       b.traffic,
+			TrafficShare:  share,
+			Allocated:     alloc[i],
+			URLs:          urls,
+			MarginOfError: marginOfError(0.5, alloc[i], len(b.urls)),
+		}
+		plan.SampledURLs += alloc[i]
+	}
+	plan.MarginOfError = stratifiedMargin(plan.Groups, nil)
+	return plan, nil
+}
+
+func traffic(p Page, metric string) int64 {
+	if metric == MetricClicks {
+		return p.Clicks
+	}
+	return p.Impressions
+}
+
+// allocate splits budget across groups: each group first gets min(floor,
+// size), the rest is shared in proportion to weight by largest remainder,
+// and capacity a full group cannot use is handed to the others.
+func allocate(sizes []int, weights []float64, budget, floor int) []int {
+	alloc := make([]int, len(sizes))
+	remaining := budget
+	for i, size := range sizes {
+		n := min(floor, size, remaining)
+		alloc[i] = n
+		remaining -= n
+	}
+
+	for remaining > 0 {
+		var open []int
+		var totalWeight float64
+		for i, size := range sizes {
+			if alloc[i] < size {
+				open = append(open, i)
+				totalWeight += weights[i]
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+
+		type share struct {
+			idx  int
+			frac float64
+		}
+		var shares []share
+		given := 0
+		for _, i := range open {
+			exact := float64(remaining) / float64(len(open))
+			if totalWeight > 0 {
+				exact = float64(remaining) * weights[i] / totalWeight
+			}
+			whole := min(int(exact), sizes[i]-alloc[i])
+			alloc[i] += whole
+			given += whole
+			if alloc[i] < sizes[i] {
+				shares = append(shares, share{i, exact - math.Floor(exact)})
+			}
+		}
+		sort.SliceStable(shares, func(a, b int) bool { return shares[a].frac > shares[b].frac })
+		left := remaining - given
+		for _, s := range shares {
+			if left == 0 {
+				break
+			}
+			alloc[s.idx]++
+			given++
+			left--
+		}
+		if given == 0 {
+			break
+		}
+		remaining -= given
+	}
+	return alloc
+}
+
+// drawOrder orders urls with previously sampled ones first, then by a hash of
+// seed+url: a uniform random order that is identical on every run.
+func drawOrder(urls []string, seed string, kept map[string]bool) []string {
+	out := append([]string(nil), urls...)
+	key := func(u string) uint64 {
+		sum := sha256.Sum256([]byte(seed + "\x00" + u))
+		return binary.BigEndian.Uint64(sum[:8])
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ki, kj := kept[out[i]], kept[out[j]]
+		if ki != kj {
+			return ki
+		}
+		return key(out[i]) < key(out[j])
+	})
+	return out
+}
+
+// marginOfError is the 95% half-width for a proportion p estimated from n of
+// N pages, with finite-population correction.
+func marginOfError(p float64, n, N int) float64 {
+	if n == 0 {
+		return 1
+	}
+	if n >= N {
+		return 0
+	}
+	fpc := float64(N-n) / float64(N-1)
+	return z95 * math.Sqrt(p*(1-p)/float64(n)*fpc)
+}
+
+// stratifiedMargin combines per-group variances weighted by page count. p
+// holds each group's observed proportion; nil means worst case (0.5).
+func stratifiedMargin(groups []Group, p []float64) float64 {
+	total := 0
+	for _, g := range groups {
+		total += g.Pages
+	}
+	if total == 0 {
+		return 1
+	}
+	var variance float64
+	for i, g := range groups {
+		if g.Allocated == 0 {
+			// An unsampled group contributes its full weight as uncertainty.
+			w := float64(g.Pages) / float64(total)
+			variance += w * w * 0.25 / (z95 * z95)
+			continue
+		}
+		if g.Allocated >= g.Pages {
+			continue
+		}
+		ph := 0.5
+		if p != nil {
+			ph = p[i]
+		}
+		w := float64(g.Pages) / float64(total)
+		fpc := float64(g.Pages-g.Allocated) / float64(g.Pages-1)
+		variance += w * w * ph * (1 - ph) / float64(g.Allocated) * fpc
+	}
+	return z95 * math.Sqrt(variance)
+}
+
+// GroupEstimate is the observed share of a group's sampled pages that met
+// the predicate passed to Estimate.
+type GroupEstimate struct {
+	Name          string  `json:"name"`
+	Pages         int     `json:"pages"`
+	Inspected     int     `json:"inspected"`
+	Matched       int     `json:"matched"`
+	Share         float64 `json:"share"`
+	MarginOfError float64 `json:"margin_of_error"`
+}
+
+// SiteEstimate extrapolates a sample outcome to every page in the plan.
+type SiteEstimate struct {
+	Share          float64         `json:"share"`
+	MarginOfError  float64         `json:"margin_of_error"`
+	EstimatedPages int             `json:"estimated_pages"`
+	TotalPages     int             `json:"total_pages"`
+	Groups         []GroupEstimate `json:"groups"`
+}
+
+// Estimate extrapolates outcomes (URL → matched, e.g. "indexed") to the whole
+// site. URLs missing from outcomes (inspection failed or skipped) are left out
+// of their group's sample size, which widens the margin accordingly.
+//
+// The margin uses each group's observed proportion, floored at 1/(n+1) from
+// either end so a group where every page matched does not claim certainty.
+func Estimate(plan *Plan, outcomes map[string]bool) SiteEstimate {
+	est := SiteEstimate{TotalPages: plan.TotalPages}
+	observed := make([]Group, len(plan.Groups))
+	props := make([]float64, len(plan.Groups))
+	for i, g := range plan.Groups {
+		ge := GroupEstimate{Name: g.Name, Pages: g.Pages}
+		for _, u := range g.URLs {
+			matched, ok := outcomes[u]
+			if !ok {
+				continue
+			}
+			ge.Inspected++
+			if matched {
+				ge.Matched++
+			}
+		}
+		p := 0.0
+		if ge.Inspected > 0 {
+			ge.Share = float64(ge.Matched) / float64(ge.Inspected)
+			floor := 1 / float64(ge.Inspected+1)
+			p = math.Min(math.Max(ge.Share, floor), 1-floor)
+		}
+		ge.MarginOfError = marginOfError(p, ge.Inspected, g.Pages)
+		if ge.Inspected == 0 {
+			ge.MarginOfError = 1
+		}
+		props[i] = p
+		observed[i] = g
+		observed[i].Allocated = ge.Inspected
+		est.Groups = append(est.Groups, ge)
+		if plan.TotalPages > 0 {
+			share := ge.Share
+			if ge.Inspected == 0 {
+				// Nothing observed: take the midpoint; the margin covers 0–100%.
+				share = 0.5
+			}
+			est.Share += share * float64(g.Pages) / float64(plan.TotalPages)
+		}
+	}
+	est.MarginOfError = stratifiedMargin(observed, props)
+	est.EstimatedPages = int(math.Round(est.Share * float64(plan.TotalPages)))
+	return est
+}
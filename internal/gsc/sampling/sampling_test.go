@@ -0,0 +1,106 @@
+package sampling
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pagesIn(dir string, n int, impressions int64) []Page {
+	out := make([]Page, n)
+	for i := range out {
+		out[i] = Page{URL: fmt.Sprintf("https://example.com%s%d", dir, i), Impressions: impressions}
+	}
+	return out
+}
+
+func TestGroupOf(t *testing.T) {
+	assert.Equal(t, "/", GroupOf("https://example.com/", 1))
+	assert.Equal(t, "/", GroupOf("https://example.com/about", 1))
+	assert.Equal(t, "/blog/", GroupOf("https://example.com/blog/post", 1))
+	assert.Equal(t, "/blog/", GroupOf("https://example.com/blog/", 1))
+	assert.Equal(t, "/blog/", GroupOf("https://example.com/blog/2024/post", 1))
+	assert.Equal(t, "/blog/2024/", GroupOf("https://example.com/blog/2024/post", 2))
+}
+
+func TestBuild_AllocatesProportionallyToTraffic(t *testing.T) {
+	var pages []Page
+	pages = append(pages, pagesIn("/blog/", 1000, 90)...) // 90% of traffic
+	pages = append(pages, pagesIn("/docs/", 1000, 9)...)  // ~9%
+	pages = append(pages, pagesIn("/tags/", 1000, 1)...)  // ~1%
+
+	plan, err := Build("sc-domain:example.com", pages, Options{Budget: 200, MinPerGroup: 5}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, plan.Groups, 3)
+	assert.Equal(t, 200, plan.SampledURLs)
+	assert.Equal(t, 3000, plan.TotalPages)
+	assert.Equal(t, "/blog/", plan.Groups[0].Name)
+	assert.Greater(t, plan.Groups[0].Allocated, plan.Groups[1].Allocated)
+	assert.Greater(t, plan.Groups[1].Allocated, plan.Groups[2].Allocated)
+	assert.GreaterOrEqual(t, plan.Groups[2].Allocated, 5, "floor keeps small groups estimable")
+	for _, g := range plan.Groups {
+		assert.Len(t, g.URLs, g.Allocated)
+		assert.Greater(t, g.MarginOfError, 0.0)
+	}
+	assert.Greater(t, plan.MarginOfError, 0.0)
+	assert.Less(t, plan.MarginOfError, 0.2)
+}
+
+func TestBuild_CensusWhenBudgetCoversSite(t *testing.T) {
+	pages := append(pagesIn("/a/", 3, 10), pagesIn("/b/", 2, 1)...)
+	plan, err := Build("s", pages, Options{Budget: 100}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, plan.SampledURLs)
+	assert.Zero(t, plan.MarginOfError)
+}
+
+func TestBuild_StableAcrossRuns(t *testing.T) {
+	pages := pagesIn("/p/", 500, 10)
+	first, err := Build("s", pages, Options{Budget: 50}, nil)
+	require.NoError(t, err)
+
+	// A fresh draw with the same seed picks the same URLs.
+	again, err := Build("s", pages, Options{Budget: 50}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.URLs(), again.URLs())
+
+	// New pages appear and the budget grows: every prior URL is retained.
+	grown := append(pagesIn("/q/", 500, 10), pages...)
+	next, err := Build("s", grown, Options{Budget: 120}, first)
+	require.NoError(t, err)
+	assert.Equal(t, 50, next.Retained)
+	assert.Subset(t, next.URLs(), first.URLs())
+}
+
+func TestBuild_Errors(t *testing.T) {
+	_, err := Build("s", nil, Options{}, nil)
+	assert.ErrorIs(t, err, ErrNoPages)
+	_, err = Build("s", pagesIn("/", 1, 1), Options{Metric: "position"}, nil)
+	assert.Error(t, err)
+}
+
+func TestEstimate(t *testing.T) {
+	pages := append(pagesIn("/a/", 1000, 10), pagesIn("/b/", 1000, 10)...)
+	plan, err := Build("s", pages, Options{Budget: 100}, nil)
+	require.NoError(t, err)
+
+	outcomes := map[string]bool{}
+	for _, g := range plan.Groups {
+		for _, u := range g.URLs {
+			outcomes[u] = g.Name == "/a/" // /a/ fully indexed, /b/ not at all
+		}
+	}
+	est := Estimate(plan, outcomes)
+
+	assert.InDelta(t, 0.5, est.Share, 1e-9)
+	assert.Equal(t, 1000, est.EstimatedPages)
+	assert.Greater(t, est.MarginOfError, 0.0, "all-or-nothing groups still carry uncertainty")
+	require.Len(t, est.Groups, 2)
+
+	// Missing outcomes shrink the sample and widen the margin.
+	delete(outcomes, plan.Groups[0].URLs[0])
+	assert.Greater(t, Estimate(plan, outcomes).Groups[0].MarginOfError, est.Groups[0].MarginOfError)
+}
@@ -23,9 +23,35 @@ type InspectAPI interface {
 	InspectURL(siteURL, inspectURL string) (*URLInspectionResult, error)
 }
 
-// Compile-time guarantee that *Client satisfies both diagnostic-side
+// QuotaEstimator is implemented by API implementations that track the daily
+// quota. Commands check an estimate before starting a run; fakes that do not
+// implement it are not limited.
+type QuotaEstimator interface {
+	Estimate(steps ...Step) Estimate
+}
+
+// SearchConsole is the slice of *Client that `ga4 setup` (sitemap submission
+// and pre-flight checks) and `gsc monitor run` drive: Search Analytics, URL
+// Inspection, sitemaps and the inspection quota. Tests substitute
+// gscfake.SearchConsole.
+type SearchConsole interface {
+	SearchAPI
+	InspectAPI
+	QuotaEstimator
+	InspectMultipleURLs(siteURL string, inspectURLs []string) ([]URLInspectionResult, error)
+	GetQuotaStatus() (used int, limit int, date string)
+
+	ListSitemaps(siteURL string) ([]SitemapInfo, error)
+	GetSitemap(siteURL, sitemapURL string) (*SitemapInfo, error)
+	SubmitSitemap(siteURL, sitemapURL string) error
+	DeleteSitemap(siteURL, sitemapURL string) error
+}
+
+// Compile-time guarantee that *Client satisfies the diagnostic-side
 // interfaces.
 var (
-	_ SearchAPI  = (*Client)(nil)
-	_ InspectAPI = (*Client)(nil)
+	_ SearchAPI      = (*Client)(nil)
+	_ InspectAPI     = (*Client)(nil)
+	_ QuotaEstimator = (*Client)(nil)
+	_ SearchConsole  = (*Client)(nil)
 )
@@ -0,0 +1,111 @@
+package sitemap
+
+import (
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/gsc/sampling"
+)
+
+// Traffic is a page's Search Analytics totals for the audited window.
+type Traffic struct {
+	Clicks      int64
+	Impressions int64
+}
+
+// GapGroup is the submitted-vs-seen breakdown for one path prefix.
+type GapGroup struct {
+	Name            string  `json:"name"`
+	URLs            int     `json:"urls"`
+	WithImpressions int     `json:"with_impressions"`
+	WithClicks      int     `json:"with_clicks"`
+	Share           float64 `json:"share"`
+}
+
+// Gap compares sitemap URLs with the pages Search Analytics reports. A URL
+// with impressions is certainly indexed; one without may be unindexed or just
+// not ranking, which is what sampling Unseen with URL inspection separates.
+type Gap struct {
+	SitemapURLs     int        `json:"sitemap_urls"`
+	WithImpressions int        `json:"with_impressions"`
+	WithClicks      int        `json:"with_clicks"`
+	Share           float64    `json:"share"`
+	NotInSitemap    int        `json:"not_in_sitemap"`
+	Groups          []GapGroup `json:"groups"`
+	// Unseen lists sitemap URLs with no impressions, sorted.
+	Unseen []string `json:"-"`
+}
+
+// MeasureGap reports which of urls have impressions in traffic, overall and
+// per path prefix at depth (see sampling.GroupOf). Groups are ordered by
+// size, largest first.
+func MeasureGap(urls []string, traffic map[string]Traffic, depth int) Gap {
+	g := Gap{SitemapURLs: len(urls)}
+	groups := make(map[string]*GapGroup)
+	inSitemap := make(map[string]bool, len(urls))
+
+	for _, u := range urls {
+		inSitemap[u] = true
+		name := sampling.GroupOf(u, depth)
+		grp, ok := groups[name]
+		if !ok {
+			grp = &GapGroup{Name: name}
+			groups[name] = grp
+		}
+		grp.URLs++
+
+		t := traffic[u]
+		if t.Impressions == 0 {
+			g.Unseen = append(g.Unseen, u)
+			continue
+		}
+		g.WithImpressions++
+		grp.WithImpressions++
+		if t.Clicks > 0 {
+			g.WithClicks++
+			grp.WithClicks++
+		}
+	}
+	for page, t := range traffic {
+		if t.Impressions > 0 && !inSitemap[page] {
+			g.NotInSitemap++
+		}
+	}
+
+	g.Share = share(g.WithImpressions, g.SitemapURLs)
+	for _, grp := range groups {
+		grp.Share = share(grp.WithImpressions, grp.URLs)
+		g.Groups = append(g.Groups, *grp)
+	}
+	sort.Slice(g.Groups, func(i, j int) bool {
+		if g.Groups[i].URLs != g.Groups[j].URLs {
+			return g.Groups[i].URLs > g.Groups[j].URLs
+		}
+		return g.Groups[i].Name < g.Groups[j].Name
+	})
+	sort.Strings(g.Unseen)
+	return g
+}
+
+// SpreadSample picks n URLs evenly spaced through urls, so a sample of a
+// sorted list covers every section rather than the first one alphabetically.
+func SpreadSample(urls []string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	if n >= len(urls) {
+		return append([]string(nil), urls...)
+	}
+	out := make([]string, 0, n)
+	step := float64(len(urls)) / float64(n)
+	for i := 0; i < n; i++ {
+		out = append(out, urls[int(float64(i)*step)])
+	}
+	return out
+}
+
+func share(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
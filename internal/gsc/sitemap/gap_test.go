@@ -0,0 +1,43 @@
+package sitemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureGap(t *testing.T) {
+	urls := []string{
+		"https://example.com/blog/a",
+		"https://example.com/blog/b",
+		"https://example.com/blog/c",
+		"https://example.com/shop/x",
+	}
+	traffic := map[string]Traffic{
+		"https://example.com/blog/a":  {Clicks: 3, Impressions: 100},
+		"https://example.com/blog/b":  {Impressions: 10},
+		"https://example.com/landing": {Impressions: 50},
+		"https://example.com/zero":    {},
+	}
+
+	g := MeasureGap(urls, traffic, 1)
+
+	assert.Equal(t, 4, g.SitemapURLs)
+	assert.Equal(t, 2, g.WithImpressions)
+	assert.Equal(t, 1, g.WithClicks)
+	assert.InDelta(t, 0.5, g.Share, 1e-9)
+	assert.Equal(t, 1, g.NotInSitemap)
+	assert.Equal(t, []string{"https://example.com/blog/c", "https://example.com/shop/x"}, g.Unseen)
+
+	require.Len(t, g.Groups, 2)
+	assert.Equal(t, GapGroup{Name: "/blog/", URLs: 3, WithImpressions: 2, WithClicks: 1, Share: 2.0 / 3}, g.Groups[0])
+	assert.Equal(t, "/shop/", g.Groups[1].Name)
+}
+
+func TestSpreadSample(t *testing.T) {
+	urls := []string{"a", "b", "c", "d", "e", "f"}
+	assert.Equal(t, []string{"a", "c", "e"}, SpreadSample(urls, 3))
+	assert.Equal(t, urls, SpreadSample(urls, 10))
+	assert.Nil(t, SpreadSample(urls, 0))
+}
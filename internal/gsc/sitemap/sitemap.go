@@ -0,0 +1,462 @@
+// Package sitemap fetches, parses and validates XML sitemaps before they are
+// submitted to Search Console.
+//
+// Search Console accepts a submission for any URL and only reports problems
+// hours later, once Googlebot has downloaded the file. This package checks
+// the same rules up front: well-formed <urlset>/<sitemapindex> documents,
+// absolute <loc> URLs inside the property, W3C datetime <lastmod> values and
+// the 50,000-URL / 50 MB per-file limits.
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Limits from the sitemaps.org protocol, which Google enforces per file.
+const (
+	MaxURLsPerFile  = 50000
+	MaxBytesPerFile = 50 << 20 // 50 MiB, uncompressed
+	MaxLocLength    = 2048
+)
+
+// DefaultMaxDocuments bounds how many child sitemaps of an index are fetched.
+const DefaultMaxDocuments = 500
+
+// Document kinds.
+const (
+	KindURLSet = "urlset"
+	KindIndex  = "sitemapindex"
+)
+
+// Issue severities. Errors make Google reject the file or the entry; warnings
+// are accepted but usually a mistake.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ErrNotSitemap is returned when the root document is neither a <urlset> nor
+// a <sitemapindex>.
+var ErrNotSitemap = errors.New("not a sitemap or sitemap index")
+
+// ErrTooLarge is returned by fetch when a file is over the 50 MB
+// uncompressed limit; it is reported as an issue of its own rather than as
+// the parse error the cut-off body would cause.
+var ErrTooLarge = errors.New("sitemap exceeds the 50 MB uncompressed size limit")
+
+// Issue is one validation finding. Loc is empty for file-level issues.
+type Issue struct {
+	Severity string `json:"severity"`
+	Sitemap  string `json:"sitemap"`
+	Loc      string `json:"loc,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Document summarises one fetched sitemap file.
+type Document struct {
+	URL      string `json:"url"`
+	Kind     string `json:"kind,omitempty"`
+	Bytes    int    `json:"bytes"`
+	Gzipped  bool   `json:"gzipped,omitempty"`
+	Entries  int    `json:"entries"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+}
+
+// Report is the result of validating a sitemap and, for an index, each of
+// its children.
+type Report struct {
+	Sitemap   string     `json:"sitemap"`
+	Site      string     `json:"site"`
+	Kind      string     `json:"kind"`
+	TotalURLs int        `json:"total_urls"`
+	Documents []Document `json:"documents"`
+	Issues    []Issue    `json:"issues,omitempty"`
+	// URLs is every valid <loc> across all urlset documents, de-duplicated.
+	URLs []string `json:"-"`
+	// LastMods holds the parsed <lastmod> of each URL in URLs that has a
+	// valid one.
+	LastMods map[string]time.Time `json:"-"`
+}
+
+// Errors returns the number of error-severity issues.
+func (r *Report) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of warning-severity issues.
+func (r *Report) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *Report) count(severity string) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Validator fetches sitemaps over HTTP. The zero value is not usable; call
+// NewValidator.
+type Validator struct {
+	client       *http.Client
+	userAgent    string
+	maxDocuments int
+	maxBytes     int
+	now          func() time.Time
+}
+
+// NewValidator builds a Validator with the given per-request timeout and
+// User-Agent. An empty userAgent is sent as Go's default.
+func NewValidator(timeout time.Duration, userAgent string) *Validator {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Validator{
+		client:       &http.Client{Timeout: timeout},
+		userAgent:    userAgent,
+		maxDocuments: DefaultMaxDocuments,
+		maxBytes:     MaxBytesPerFile,
+		now:          time.Now,
+	}
+}
+
+// Validate fetches sitemapURL and checks it against the rules for site (a
+// Search Console property: sc-domain:example.com or https://example.com/).
+// A <sitemapindex> has each child fetched and validated in turn.
+//
+// Problems with the content are reported as Issues. An error is returned only
+// when the root document cannot be fetched or is not a sitemap at all.
+func (v *Validator) Validate(ctx context.Context, site, sitemapURL string) (*Report, error) {
+	scope, err := newPropertyScope(site)
+	if err != nil {
+		return nil, err
+	}
+
+	body, gzipped, err := v.fetch(ctx, sitemapURL)
+	if errors.Is(err, ErrTooLarge) {
+		r := &Report{Sitemap: sitemapURL, Site: site}
+		r.Documents = append(r.Documents, Document{URL: sitemapURL, Gzipped: gzipped, Errors: 1})
+		r.Issues = append(r.Issues, v.tooLarge(sitemapURL))
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	root, err := parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sitemapURL, err)
+	}
+
+	r := &Report{Sitemap: sitemapURL, Site: site, Kind: root.kind}
+	seen := make(map[string]struct{})
+	if !scope.contains(sitemapURL) {
+		r.Issues = append(r.Issues, Issue{Severity: SeverityError, Sitemap: sitemapURL,
+			Message: fmt.Sprintf("sitemap is outside the property %s; Search Console will not accept it", site)})
+	}
+
+	v.checkDocument(r, sitemapURL, body, gzipped, root, scope, seen)
+	if root.kind == KindURLSet {
+		return r, nil
+	}
+
+	children := root.locs
+	if len(children) > v.maxDocuments {
+		r.Issues = append(r.Issues, Issue{Severity: SeverityWarning, Sitemap: sitemapURL,
+			Message: fmt.Sprintf("index lists %d sitemaps; only the first %d were checked", len(children), v.maxDocuments)})
+		children = children[:v.maxDocuments]
+	}
+	for _, child := range children {
+		if err := validateLoc(child.loc); err != nil {
+			continue // already reported against the index
+		}
+		childBody, childGzipped, err := v.fetch(ctx, child.loc)
+		if errors.Is(err, ErrTooLarge) {
+			r.Documents = append(r.Documents, Document{URL: child.loc, Gzipped: childGzipped, Errors: 1})
+			r.Issues = append(r.Issues, v.tooLarge(child.loc))
+			continue
+		}
+		if err != nil {
+			r.Documents = append(r.Documents, Document{URL: child.loc, Errors: 1})
+			r.Issues = append(r.Issues, Issue{Severity: SeverityError, Sitemap: child.loc, Message: err.Error()})
+			continue
+		}
+		doc, err := parse(childBody)
+		if err != nil {
+			r.Documents = append(r.Documents, Document{URL: child.loc, Bytes: len(childBody), Gzipped: childGzipped, Errors: 1})
+			r.Issues = append(r.Issues, Issue{Severity: SeverityError, Sitemap: child.loc, Message: err.Error()})
+			continue
+		}
+		if doc.kind == KindIndex {
+			r.Documents = append(r.Documents, Document{URL: child.loc, Kind: doc.kind, Bytes: len(childBody), Gzipped: childGzipped, Entries: len(doc.locs), Errors: 1})
+			r.Issues = append(r.Issues, Issue{Severity: SeverityError, Sitemap: child.loc,
+				Message: "sitemap index nested inside another index; Google does not follow nested indexes"})
+			continue
+		}
+		v.checkDocument(r, child.loc, childBody, childGzipped, doc, scope, seen)
+	}
+	return r, nil
+}
+
+// tooLarge is the issue of a file over the size limit, which is not parsed.
+func (v *Validator) tooLarge(docURL string) Issue {
+	return Issue{Severity: SeverityError, Sitemap: docURL,
+		Message: fmt.Sprintf("file is over %d bytes uncompressed, the 50 MB sitemap limit; Google ignores it — split it and use a sitemap index", v.maxBytes)}
+}
+
+// checkDocument validates one parsed document and appends its summary and
+// issues to r. For a urlset, valid locs are added to r.URLs.
+func (v *Validator) checkDocument(r *Report, docURL string, body []byte, gzipped bool, doc *parsed, scope propertyScope, seen map[string]struct{}) {
+	before := len(r.Issues)
+	add := func(severity, loc, format string, args ...any) {
+		r.Issues = append(r.Issues, Issue{Severity: severity, Sitemap: docURL, Loc: loc, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(doc.locs) > MaxURLsPerFile {
+		add(SeverityError, "", "file lists %d entries; the limit is %d per file — split it and use a sitemap index", len(doc.locs), MaxURLsPerFile)
+	}
+	if len(doc.locs) == 0 {
+		add(SeverityWarning, "", "%s has no entries", doc.kind)
+	}
+
+	now := v.now()
+	for _, e := range doc.locs {
+		if err := validateLoc(e.loc); err != nil {
+			add(SeverityError, e.loc, "%v", err)
+			continue
+		}
+		if !scope.contains(e.loc) {
+			add(SeverityError, e.loc, "URL is outside the property %s", r.Site)
+		}
+		var lastmod time.Time
+		if e.lastmod != "" {
+			t, err := ParseLastmod(e.lastmod)
+			switch {
+			case err != nil:
+				add(SeverityError, e.loc, "lastmod %q is not a W3C datetime (YYYY-MM-DD or YYYY-MM-DDThh:mm:ss+hh:mm)", e.lastmod)
+			case t.After(now.Add(24 * time.Hour)):
+				add(SeverityWarning, e.loc, "lastmod %s is in the future", e.lastmod)
+			default:
+				lastmod = t
+			}
+		}
+		if doc.kind != KindURLSet {
+			continue
+		}
+		if _, dup := seen[e.loc]; dup {
+			add(SeverityWarning, e.loc, "duplicate URL")
+			continue
+		}
+		seen[e.loc] = struct{}{}
+		r.URLs = append(r.URLs, e.loc)
+		if !lastmod.IsZero() {
+			if r.LastMods == nil {
+				r.LastMods = make(map[string]time.Time)
+			}
+			r.LastMods[e.loc] = lastmod
+		}
+	}
+	if doc.kind == KindURLSet {
+		r.TotalURLs += len(doc.locs)
+	}
+
+	d := Document{URL: docURL, Kind: doc.kind, Bytes: len(body), Gzipped: gzipped, Entries: len(doc.locs)}
+	for _, i := range r.Issues[before:] {
+		if i.Severity == SeverityError {
+			d.Errors++
+		} else {
+			d.Warnings++
+		}
+	}
+	r.Documents = append(r.Documents, d)
+}
+
+// --- Parsing ---------------------------------------------------------------
+
+type entry struct {
+	loc     string
+	lastmod string
+}
+
+type parsed struct {
+	kind string
+	locs []entry
+}
+
+type xmlEntry struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod"`
+}
+
+type xmlDocument struct {
+	XMLName  xml.Name
+	URLs     []xmlEntry `xml:"url"`
+	Sitemaps []xmlEntry `xml:"sitemap"`
+}
+
+// parse decodes a urlset or sitemapindex document.
+func parse(body []byte) (*parsed, error) {
+	var doc xmlDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+	var raw []xmlEntry
+	switch doc.XMLName.Local {
+	case KindURLSet:
+		raw = doc.URLs
+	case KindIndex:
+		raw = doc.Sitemaps
+	default:
+		return nil, fmt.Errorf("%w (root element <%s>)", ErrNotSitemap, doc.XMLName.Local)
+	}
+	p := &parsed{kind: doc.XMLName.Local, locs: make([]entry, 0, len(raw))}
+	for _, e := range raw {
+		p.locs = append(p.locs, entry{loc: strings.TrimSpace(e.Loc), lastmod: strings.TrimSpace(e.Lastmod)})
+	}
+	return p, nil
+}
+
+// validateLoc checks that loc is an absolute http(s) URL within the length
+// limit.
+func validateLoc(loc string) error {
+	if loc == "" {
+		return errors.New("empty <loc>")
+	}
+	if len(loc) > MaxLocLength {
+		return fmt.Errorf("URL is %d characters; the limit is %d", len(loc), MaxLocLength)
+	}
+	u, err := url.Parse(loc)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("URL must be absolute with an http or https scheme")
+	}
+	if u.Host == "" {
+		return errors.New("URL has no host")
+	}
+	return nil
+}
+
+// lastmodLayouts are the W3C datetime profiles sitemaps.org allows, most
+// specific first.
+var lastmodLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// ParseLastmod parses a <lastmod> value in any W3C datetime profile.
+func ParseLastmod(s string) (time.Time, error) {
+	for _, layout := range lastmodLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid lastmod %q", s)
+}
+
+// --- Property scope --------------------------------------------------------
+
+// propertyScope decides whether a URL belongs to a Search Console property.
+type propertyScope struct {
+	domain string // sc-domain: properties; matches the host and subdomains
+	prefix string // URL-prefix properties
+}
+
+func newPropertyScope(site string) (propertyScope, error) {
+	if d, ok := strings.CutPrefix(site, "sc-domain:"); ok {
+		if d == "" {
+			return propertyScope{}, fmt.Errorf("domain property must include a domain: %s", site)
+		}
+		return propertyScope{domain: strings.ToLower(d)}, nil
+	}
+	u, err := url.Parse(site)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return propertyScope{}, fmt.Errorf("invalid site %q (want sc-domain:example.com or https://example.com/)", site)
+	}
+	return propertyScope{prefix: site}, nil
+}
+
+// InProperty reports whether rawURL falls within the Search Console property
+// site.
+func InProperty(site, rawURL string) bool {
+	scope, err := newPropertyScope(site)
+	return err == nil && scope.contains(rawURL)
+}
+
+func (s propertyScope) contains(rawURL string) bool {
+	if s.prefix != "" {
+		return strings.HasPrefix(rawURL, s.prefix)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == s.domain || strings.HasSuffix(host, "."+s.domain)
+}
+
+// --- Fetching --------------------------------------------------------------
+
+// fetch downloads rawURL, transparently decompressing gzip. It reads at most
+// one byte past the size limit, so oversized files are detected without
+// buffering them whole, and returns ErrTooLarge for them before any parsing.
+func (v *Validator) fetch(ctx context.Context, rawURL string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if v.userAgent != "" {
+		req.Header.Set("User-Agent", v.userAgent)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("fetch %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, int64(v.maxBytes)+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("read %s: %w", rawURL, err)
+	}
+	gzipped := len(raw) >= 2 && raw[0] == 0x1f && raw[1] == 0x8b
+	if len(raw) > v.maxBytes {
+		// A compressed file this big is over the limit uncompressed too.
+		return nil, gzipped, fmt.Errorf("%s: %w", rawURL, ErrTooLarge)
+	}
+	if !gzipped {
+		return raw, false, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, true, fmt.Errorf("decompress %s: %w", rawURL, err)
+	}
+	body, err := io.ReadAll(io.LimitReader(zr, int64(v.maxBytes)+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("decompress %s: %w", rawURL, err)
+	}
+	if len(body) > v.maxBytes {
+		return nil, true, fmt.Errorf("%s: %w", rawURL, ErrTooLarge)
+	}
+	return body, true, nil
+}
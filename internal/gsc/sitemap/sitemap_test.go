@@ -0,0 +1,229 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator() *Validator {
+	v := NewValidator(5*time.Second, "")
+	v.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return v
+}
+
+func urlset(entries ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		strings.Join(entries, "") + `</urlset>`
+}
+
+func urlEntry(loc, lastmod string) string {
+	if lastmod == "" {
+		return "<url><loc>" + loc + "</loc></url>"
+	}
+	return "<url><loc>" + loc + "</loc><lastmod>" + lastmod + "</lastmod></url>"
+}
+
+func TestValidate_URLSet(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, urlset(
+			urlEntry(srv.URL+"/a", "2026-05-01"),
+			urlEntry(srv.URL+"/b", "2026-05-01T10:00:00+02:00"),
+			urlEntry(srv.URL+"/b", ""),
+			urlEntry("https://elsewhere.example/c", ""),
+			urlEntry("/relative", ""),
+			urlEntry(srv.URL+"/d", "01/05/2026"),
+			urlEntry(srv.URL+"/e", "2027-01-01"),
+		))
+	}))
+	defer srv.Close()
+
+	r, err := newTestValidator().Validate(context.Background(), srv.URL+"/", srv.URL+"/sitemap.xml")
+	require.NoError(t, err)
+
+	assert.Equal(t, KindURLSet, r.Kind)
+	assert.Equal(t, 7, r.TotalURLs)
+	require.Len(t, r.Documents, 1)
+	assert.Equal(t, 3, r.Errors(), "out of property, relative loc, bad lastmod")
+	assert.Equal(t, 2, r.Warnings(), "duplicate and future lastmod")
+
+	messages := make(map[string]string)
+	for _, i := range r.Issues {
+		messages[i.Loc] = i.Message
+	}
+	assert.Contains(t, messages["https://elsewhere.example/c"], "outside the property")
+	assert.Contains(t, messages["/relative"], "absolute")
+	assert.Contains(t, messages[srv.URL+"/d"], "not a W3C datetime")
+	assert.Contains(t, messages[srv.URL+"/e"], "future")
+	assert.NotContains(t, r.URLs, "/relative")
+	assert.Equal(t, []string{srv.URL + "/a", srv.URL + "/b"}, keys(r.LastMods))
+}
+
+func keys(m map[string]time.Time) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestValidate_IndexWithGzipAndBrokenChildren(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/sitemap_index.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>%[1]s/posts.xml.gz</loc><lastmod>2026-05-01</lastmod></sitemap>
+<sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+<sitemap><loc>%[1]s/nested.xml</loc></sitemap>
+</sitemapindex>`, srv.URL)
+	})
+	mux.HandleFunc("/posts.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(urlset(urlEntry(srv.URL+"/p1", ""), urlEntry(srv.URL+"/p2", ""))))
+		_ = zw.Close()
+		_, _ = w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/nested.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<sitemapindex><sitemap><loc>x</loc></sitemap></sitemapindex>`)
+	})
+
+	r, err := newTestValidator().Validate(context.Background(), srv.URL+"/", srv.URL+"/sitemap_index.xml")
+	require.NoError(t, err)
+
+	assert.Equal(t, KindIndex, r.Kind)
+	assert.Equal(t, 2, r.TotalURLs)
+	assert.Equal(t, []string{srv.URL + "/p1", srv.URL + "/p2"}, r.URLs)
+	require.Len(t, r.Documents, 4)
+	assert.True(t, r.Documents[1].Gzipped)
+	assert.Equal(t, 2, r.Errors(), "missing child and nested index")
+}
+
+func TestValidate_TooManyURLs(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		b.WriteString(`<urlset>`)
+		for i := 0; i <= MaxURLsPerFile; i++ {
+			fmt.Fprintf(&b, "<url><loc>%s/%d</loc></url>", srv.URL, i)
+		}
+		b.WriteString(`</urlset>`)
+		_, _ = fmt.Fprint(w, b.String())
+	}))
+	defer srv.Close()
+
+	r, err := newTestValidator().Validate(context.Background(), srv.URL+"/", srv.URL+"/sitemap.xml")
+	require.NoError(t, err)
+	require.Equal(t, 1, r.Errors())
+	assert.Contains(t, r.Issues[0].Message, "limit is 50000")
+}
+
+// A file over the size limit is reported as such, not as the invalid XML
+// its cut-off body would parse as.
+func TestValidate_OverSizeLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	big := func() string {
+		var entries []string
+		for i := range 100 {
+			entries = append(entries, urlEntry(fmt.Sprintf("%s/page-%d", srv.URL, i), ""))
+		}
+		return urlset(entries...)
+	}
+	mux.HandleFunc("/big.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, big())
+	})
+	mux.HandleFunc("/big.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(big()))
+		_ = zw.Close()
+	})
+	mux.HandleFunc("/index.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%[1]s/big.xml.gz</loc></sitemap><sitemap><loc>%[1]s/small.xml</loc></sitemap></sitemapindex>`, srv.URL)
+	})
+	mux.HandleFunc("/small.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, urlset(urlEntry(srv.URL+"/p1", "")))
+	})
+
+	v := newTestValidator()
+	v.maxBytes = 1024
+
+	r, err := v.Validate(context.Background(), srv.URL+"/", srv.URL+"/big.xml")
+	require.NoError(t, err)
+	require.Len(t, r.Issues, 1)
+	assert.Contains(t, r.Issues[0].Message, "50 MB sitemap limit")
+	assert.NotContains(t, r.Issues[0].Message, "XML")
+
+	r, err = v.Validate(context.Background(), srv.URL+"/", srv.URL+"/index.xml")
+	require.NoError(t, err)
+	assert.Equal(t, 1, r.Errors())
+	assert.Equal(t, srv.URL+"/big.xml.gz", r.Issues[0].Sitemap)
+	assert.Contains(t, r.Issues[0].Message, "50 MB sitemap limit")
+	assert.True(t, r.Documents[1].Gzipped)
+	assert.Equal(t, []string{srv.URL + "/p1"}, r.URLs)
+}
+
+func TestValidate_RootFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/html" {
+			_, _ = fmt.Fprint(w, `<html><body>not found</body></html>`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	v := newTestValidator()
+	_, err := v.Validate(context.Background(), srv.URL+"/", srv.URL+"/missing.xml")
+	assert.ErrorContains(t, err, "HTTP 404")
+
+	_, err = v.Validate(context.Background(), srv.URL+"/", srv.URL+"/html")
+	assert.ErrorIs(t, err, ErrNotSitemap)
+
+	_, err = v.Validate(context.Background(), "example.com", srv.URL+"/html")
+	assert.ErrorContains(t, err, "invalid site")
+}
+
+func TestParseLastmod(t *testing.T) {
+	for _, ok := range []string{"2026", "2026-05", "2026-05-01", "2026-05-01T10:00Z", "2026-05-01T10:00:00+02:00", "2026-05-01T10:00:00.123Z"} {
+		_, err := ParseLastmod(ok)
+		assert.NoError(t, err, ok)
+	}
+	for _, bad := range []string{"", "yesterday", "2026-13-01", "2026-05-01 10:00:00", "2026-05-01T10:00:00"} {
+		_, err := ParseLastmod(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestInProperty(t *testing.T) {
+	cases := []struct {
+		site, url string
+		want      bool
+	}{
+		{"sc-domain:example.com", "https://example.com/a", true},
+		{"sc-domain:example.com", "http://blog.example.com/a", true},
+		{"sc-domain:example.com", "https://notexample.com/a", false},
+		{"https://example.com/", "https://example.com/a", true},
+		{"https://example.com/", "http://example.com/a", false},
+		{"https://example.com/blog/", "https://example.com/shop", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, InProperty(c.site, c.url), "%s in %s", c.url, c.site)
+	}
+}
@@ -1,9 +1,12 @@
 package gsc
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
+
+	"google.golang.org/api/searchconsole/v1"
 )
 
 // SitemapInfo contains information about a sitemap
@@ -39,7 +42,12 @@ func (c *Client) ListSitemaps(siteURL string) ([]SitemapInfo, error) {
 
 	c.logger.Info("listing sitemaps", "site_url", siteURL)
 
-	sitemapsListResponse, err := c.service.Sitemaps.List(siteURL).Do()
+	var sitemapsListResponse *searchconsole.SitemapsListResponse
+	err := c.call("list", "sitemaps", siteURL, func(ctx context.Context) error {
+		var err error
+		sitemapsListResponse, err = c.service.Sitemaps.List(siteURL).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		c.logger.Error("failed to list sitemaps",
 			"site_url", siteURL,
@@ -98,7 +106,12 @@ func (c *Client) GetSitemap(siteURL, sitemapURL string) (*SitemapInfo, error) {
 
 	c.logger.Info("getting sitemap", "site_url", siteURL, "sitemap_url", sitemapURL)
 
-	sm, err := c.service.Sitemaps.Get(siteURL, sitemapURL).Do()
+	var sm *searchconsole.WmxSitemap
+	err := c.call("get", "sitemap", sitemapURL, func(ctx context.Context) error {
+		var err error
+		sm, err = c.service.Sitemaps.Get(siteURL, sitemapURL).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		c.logger.Error("failed to get sitemap",
 			"site_url", siteURL,
@@ -152,7 +165,9 @@ func (c *Client) SubmitSitemap(siteURL, sitemapURL string) error {
 
 	c.logger.Info("submitting sitemap", "site_url", siteURL, "sitemap_url", sitemapURL)
 
-	err := c.service.Sitemaps.Submit(siteURL, sitemapURL).Do()
+	err := c.change("create", "sitemap", sitemapURL, siteURL, func(ctx context.Context) error {
+		return c.service.Sitemaps.Submit(siteURL, sitemapURL).Context(ctx).Do()
+	})
 	if err != nil {
 		c.logger.Error("failed to submit sitemap",
 			"site_url", siteURL,
@@ -184,7 +199,9 @@ func (c *Client) DeleteSitemap(siteURL, sitemapURL string) error {
 
 	c.logger.Info("deleting sitemap", "site_url", siteURL, "sitemap_url", sitemapURL)
 
-	err := c.service.Sitemaps.Delete(siteURL, sitemapURL).Do()
+	err := c.change("delete", "sitemap", sitemapURL, siteURL, func(ctx context.Context) error {
+		return c.service.Sitemaps.Delete(siteURL, sitemapURL).Context(ctx).Do()
+	})
 	if err != nil {
 		c.logger.Error("failed to delete sitemap",
 			"site_url", siteURL,
@@ -0,0 +1,139 @@
+package gtm
+
+import "sort"
+
+// Event statuses reported by Audit.
+const (
+	StatusWired     = "wired"      // an active GA4 event tag sends it
+	StatusAutomatic = "automatic"  // collected by the Google tag or enhanced measurement
+	StatusDynamic   = "dynamic"    // possibly sent by a tag whose event name is a variable
+	StatusPaused    = "paused"     // only paused tags send it
+	StatusNoTrigger = "no_trigger" // only tags without firing triggers send it
+	StatusMissing   = "missing"    // no tag sends it
+)
+
+// Event sources: where an expected event was declared.
+const (
+	SourceConfig   = "config"
+	SourceProperty = "property"
+)
+
+// automaticEvents are sent without an event tag: by the Google tag itself
+// or by GA4 enhanced measurement.
+var automaticEvents = map[string]bool{
+	"page_view": true, "first_visit": true, "session_start": true, "user_engagement": true,
+	"scroll": true, "click": true, "file_download": true, "view_search_results": true,
+	"video_start": true, "video_progress": true, "video_complete": true,
+	"form_start": true, "form_submit": true,
+}
+
+// IsAutomaticEvent reports whether GA4 collects name without an event tag,
+// through the Google tag or enhanced measurement.
+func IsAutomaticEvent(name string) bool {
+	return automaticEvents[name]
+}
+
+// ExpectedEvent is an event GA4 is set up to receive, such as a conversion.
+type ExpectedEvent struct {
+	Name    string   `json:"name"`
+	Sources []string `json:"sources"`
+}
+
+// EventCheck is the audit verdict for one expected event.
+type EventCheck struct {
+	ExpectedEvent
+	Status string   `json:"status"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// AuditReport is the result of Audit.
+type AuditReport struct {
+	Container string       `json:"container"`
+	Source    string       `json:"source"`
+	Events    []EventCheck `json:"events"`
+	// Unexpected lists active GA4 event tags whose (constant) event name is
+	// not among the expected events: tracked, but not a conversion.
+	Unexpected []Tag `json:"unexpected,omitempty"`
+}
+
+// Problems returns the number of events no active tag sends.
+func (r *AuditReport) Problems() int {
+	n := 0
+	for _, e := range r.Events {
+		switch e.Status {
+		case StatusMissing, StatusPaused, StatusNoTrigger:
+			n++
+		}
+	}
+	return n
+}
+
+// Audit checks that each expected event is sent by a GA4 event tag in the
+// container. Event names written as constant variables are resolved first.
+func Audit(c *Container, expected []ExpectedEvent) *AuditReport {
+	report := &AuditReport{Container: c.Path, Source: c.Source, Events: []EventCheck{}}
+
+	byEvent := make(map[string][]Tag)
+	var dynamic []Tag
+	for _, t := range c.Tags {
+		if t.Type != TagTypeGA4Event {
+			continue
+		}
+		name, isDynamic := c.Resolve(t.EventName)
+		if isDynamic {
+			if !t.Paused && len(t.FiringTriggers) > 0 {
+				dynamic = append(dynamic, t)
+			}
+			continue
+		}
+		byEvent[name] = append(byEvent[name], t)
+	}
+
+	want := make(map[string]bool, len(expected))
+	for _, e := range expected {
+		want[e.Name] = true
+		check := EventCheck{ExpectedEvent: e, Status: StatusMissing}
+		tags := byEvent[e.Name]
+		for _, t := range tags {
+			check.Tags = append(check.Tags, t.Name)
+		}
+		switch {
+		case hasTag(tags, func(t Tag) bool { return !t.Paused && len(t.FiringTriggers) > 0 }):
+			check.Status = StatusWired
+		case automaticEvents[e.Name]:
+			check.Status = StatusAutomatic
+		case hasTag(tags, func(t Tag) bool { return t.Paused }):
+			check.Status = StatusPaused
+		case len(tags) > 0:
+			check.Status = StatusNoTrigger
+		case len(dynamic) > 0:
+			check.Status = StatusDynamic
+			for _, t := range dynamic {
+				check.Tags = append(check.Tags, t.Name)
+			}
+		}
+		report.Events = append(report.Events, check)
+	}
+
+	for name, tags := range byEvent {
+		if want[name] {
+			continue
+		}
+		for _, t := range tags {
+			if !t.Paused && len(t.FiringTriggers) > 0 {
+				report.Unexpected = append(report.Unexpected, t)
+			}
+		}
+	}
+	sort.Slice(report.Unexpected, func(i, j int) bool { return report.Unexpected[i].Name < report.Unexpected[j].Name })
+	return report
+}
+
+func hasTag(tags []Tag, pred func(Tag) bool) bool {
+	for _, t := range tags {
+		if pred(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package gtm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	c := &Container{
+		Path: "accounts/1/containers/2",
+		Tags: []Tag{
+			{Name: "GA4 - purchase", Type: TagTypeGA4Event, EventName: "purchase", FiringTriggers: []string{"1"}},
+			{Name: "GA4 - lead", Type: TagTypeGA4Event, EventName: "{{Lead event}}", FiringTriggers: []string{"2"}},
+			{Name: "GA4 - sign_up (old)", Type: TagTypeGA4Event, EventName: "sign_up", Paused: true, FiringTriggers: []string{"3"}},
+			{Name: "GA4 - newsletter", Type: TagTypeGA4Event, EventName: "newsletter"},
+			{Name: "GA4 - share", Type: TagTypeGA4Event, EventName: "share", FiringTriggers: []string{"4"}},
+			{Name: "Meta pixel", Type: "html", FiringTriggers: []string{"1"}},
+		},
+		Variables: []Variable{{Name: "Lead event", Type: "c", Value: "generate_lead"}},
+	}
+	expected := []ExpectedEvent{
+		{Name: "purchase", Sources: []string{SourceConfig, SourceProperty}},
+		{Name: "generate_lead", Sources: []string{SourceConfig}},
+		{Name: "sign_up", Sources: []string{SourceConfig}},
+		{Name: "newsletter", Sources: []string{SourceProperty}},
+		{Name: "file_download", Sources: []string{SourceProperty}},
+		{Name: "begin_checkout", Sources: []string{SourceConfig}},
+	}
+
+	r := Audit(c, expected)
+	status := make(map[string]string)
+	for _, e := range r.Events {
+		status[e.Name] = e.Status
+	}
+	assert.Equal(t, map[string]string{
+		"purchase":       StatusWired,
+		"generate_lead":  StatusWired,
+		"sign_up":        StatusPaused,
+		"newsletter":     StatusNoTrigger,
+		"file_download":  StatusAutomatic,
+		"begin_checkout": StatusMissing,
+	}, status)
+	assert.Equal(t, 3, r.Problems())
+	require.Len(t, r.Unexpected, 1)
+	assert.Equal(t, "GA4 - share", r.Unexpected[0].Name)
+}
+
+func TestAudit_DynamicEventName(t *testing.T) {
+	c := &Container{Tags: []Tag{
+		{Name: "GA4 - dataLayer events", Type: TagTypeGA4Event, EventName: "{{Event}}", FiringTriggers: []string{"1"}},
+	}}
+	r := Audit(c, []ExpectedEvent{{Name: "purchase", Sources: []string{SourceConfig}}})
+	require.Len(t, r.Events, 1)
+	assert.Equal(t, StatusDynamic, r.Events[0].Status)
+	assert.Equal(t, []string{"GA4 - dataLayer events"}, r.Events[0].Tags)
+	assert.Zero(t, r.Problems())
+}
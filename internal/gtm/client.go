@@ -0,0 +1,239 @@
+// Package gtm reads Google Tag Manager containers through the Tag Manager
+// API (v2) and checks them against what GA4 expects to receive: which
+// conversions have an event tag, and which GA4 measurement ID the container
+// sends to.
+//
+// Access is read-only. The credential needs at least Read permission on the
+// container in the GTM UI (Admin → User Management).
+package gtm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+)
+
+// Tag types the audit understands.
+const (
+	TagTypeGA4Event  = "gaawe"   // GA4 Event tag
+	TagTypeGA4Config = "gaawc"   // legacy GA4 Configuration tag
+	TagTypeGoogleTag = "googtag" // Google tag, the successor of gaawc
+)
+
+// Tag is the part of a GTM tag the checks use. EventName and MeasurementID
+// are as written in the container, before variables are resolved.
+type Tag struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	EventName      string   `json:"event_name,omitempty"`
+	MeasurementID  string   `json:"measurement_id,omitempty"`
+	Paused         bool     `json:"paused,omitempty"`
+	FiringTriggers []string `json:"firing_triggers,omitempty"`
+}
+
+// Trigger is a GTM trigger.
+type Trigger struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Variable is a user-defined GTM variable. Value is set for constants only.
+type Variable struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+// Container is a snapshot of a container's tags, triggers and variables,
+// from either the published version or a workspace draft.
+type Container struct {
+	Path      string     `json:"path"`
+	Source    string     `json:"source"`
+	Tags      []Tag      `json:"tags"`
+	Triggers  []Trigger  `json:"triggers"`
+	Variables []Variable `json:"variables"`
+}
+
+// containerAPI is the narrow seam over the Tag Manager SDK, so Container can
+// be tested with a fake.
+type containerAPI interface {
+	liveVersion(ctx context.Context, containerPath string) (*tagmanager.ContainerVersion, error)
+	workspace(ctx context.Context, workspacePath string) (*tagmanager.ContainerVersion, error)
+}
+
+// Client reads GTM containers.
+type Client struct {
+	api containerAPI
+}
+
+// NewClient creates a read-only Tag Manager client authenticated with creds,
+// the source auth.Resolve picks, so it runs as the same identity as the GA4
+// and Search Console clients. opts are passed to the SDK after them.
+func NewClient(ctx context.Context, creds auth.Source, opts ...option.ClientOption) (*Client, error) {
+	opts = append([]option.ClientOption{option.WithScopes(tagmanager.TagmanagerReadonlyScope), creds.ClientOption()}, opts...)
+	service, err := tagmanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Tag Manager service: %w", err)
+	}
+	return &Client{api: realContainerAPI{service}}, nil
+}
+
+// Container reads a container. An empty workspaceID reads the published
+// (live) version, which is what visitors actually run.
+func (c *Client) Container(ctx context.Context, accountID, containerID, workspaceID string) (*Container, error) {
+	path := fmt.Sprintf("accounts/%s/containers/%s", accountID, containerID)
+	var (
+		version *tagmanager.ContainerVersion
+		source  string
+		err     error
+	)
+	if workspaceID == "" {
+		source = "live version"
+		version, err = c.api.liveVersion(ctx, path)
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%s has no published version (or is not visible to this credential); pass a workspace to audit the draft: %w", path, err)
+		}
+	} else {
+		source = "workspace " + workspaceID
+		version, err = c.api.workspace(ctx, path+"/workspaces/"+workspaceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GTM container %s: %w", path, err)
+	}
+	return newContainer(path, source, version), nil
+}
+
+func newContainer(path, source string, v *tagmanager.ContainerVersion) *Container {
+	c := &Container{Path: path, Source: source, Tags: []Tag{}, Triggers: []Trigger{}, Variables: []Variable{}}
+	for _, t := range v.Tag {
+		c.Tags = append(c.Tags, Tag{
+			ID:             t.TagId,
+			Name:           t.Name,
+			Type:           t.Type,
+			EventName:      paramValue(t.Parameter, "eventName"),
+			MeasurementID:  tagMeasurementID(t),
+			Paused:         t.Paused,
+			FiringTriggers: t.FiringTriggerId,
+		})
+	}
+	for _, t := range v.Trigger {
+		c.Triggers = append(c.Triggers, Trigger{ID: t.TriggerId, Name: t.Name, Type: t.Type})
+	}
+	for _, v := range v.Variable {
+		variable := Variable{Name: v.Name, Type: v.Type}
+		if v.Type == "c" {
+			variable.Value = paramValue(v.Parameter, "value")
+		}
+		c.Variables = append(c.Variables, variable)
+	}
+	return c
+}
+
+// tagMeasurementID returns the GA4 measurement ID a tag sends to: tagId on a
+// Google tag, measurementId on a configuration tag, and the override on an
+// event tag (which otherwise inherits its configuration tag's).
+func tagMeasurementID(t *tagmanager.Tag) string {
+	switch t.Type {
+	case TagTypeGoogleTag:
+		return paramValue(t.Parameter, "tagId")
+	case TagTypeGA4Config:
+		return paramValue(t.Parameter, "measurementId")
+	case TagTypeGA4Event:
+		return paramValue(t.Parameter, "measurementIdOverride")
+	}
+	return ""
+}
+
+func paramValue(params []*tagmanager.Parameter, key string) string {
+	for _, p := range params {
+		if p.Key == key {
+			return strings.TrimSpace(p.Value)
+		}
+	}
+	return ""
+}
+
+// Resolve substitutes constant variables in a tag field such as
+// "{{GA4 ID}}". dynamic is true when the value still depends on a
+// non-constant variable (e.g. {{Event}}), so it is only known at run time.
+func (c *Container) Resolve(value string) (resolved string, dynamic bool) {
+	constants := make(map[string]string)
+	for _, v := range c.Variables {
+		if v.Type == "c" {
+			constants[v.Name] = v.Value
+		}
+	}
+	var b strings.Builder
+	rest := value
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(rest[:start])
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		if v, ok := constants[name]; ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(rest[start : start+end+2])
+			dynamic = true
+		}
+		rest = rest[start+end+2:]
+	}
+	b.WriteString(rest)
+	return b.String(), dynamic
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// realContainerAPI delegates to the Tag Manager SDK.
+type realContainerAPI struct {
+	service *tagmanager.Service
+}
+
+func (r realContainerAPI) liveVersion(ctx context.Context, containerPath string) (*tagmanager.ContainerVersion, error) {
+	return r.service.Accounts.Containers.Versions.Live(containerPath).Context(ctx).Do()
+}
+
+// workspace assembles a ContainerVersion from a workspace's tag, trigger and
+// variable listings; workspaces have no single "get everything" call.
+func (r realContainerAPI) workspace(ctx context.Context, workspacePath string) (*tagmanager.ContainerVersion, error) {
+	ws := r.service.Accounts.Containers.Workspaces
+	v := &tagmanager.ContainerVersion{}
+	if err := ws.Tags.List(workspacePath).Pages(ctx, func(resp *tagmanager.ListTagsResponse) error {
+		v.Tag = append(v.Tag, resp.Tag...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := ws.Triggers.List(workspacePath).Pages(ctx, func(resp *tagmanager.ListTriggersResponse) error {
+		v.Trigger = append(v.Trigger, resp.Trigger...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := ws.Variables.List(workspacePath).Pages(ctx, func(resp *tagmanager.ListVariablesResponse) error {
+		v.Variable = append(v.Variable, resp.Variable...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,86 @@
+package gtm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// fakeContainerAPI returns canned container versions and records the paths
+// it was asked for.
+type fakeContainerAPI struct {
+	live, ws      *tagmanager.ContainerVersion
+	liveErr       error
+	gotPath       string
+	workspaceCall bool
+}
+
+func (f *fakeContainerAPI) liveVersion(_ context.Context, path string) (*tagmanager.ContainerVersion, error) {
+	f.gotPath = path
+	return f.live, f.liveErr
+}
+
+func (f *fakeContainerAPI) workspace(_ context.Context, path string) (*tagmanager.ContainerVersion, error) {
+	f.gotPath, f.workspaceCall = path, true
+	return f.ws, nil
+}
+
+func param(key, value string) *tagmanager.Parameter {
+	return &tagmanager.Parameter{Key: key, Type: "template", Value: value}
+}
+
+func TestClient_Container_Live(t *testing.T) {
+	fake := &fakeContainerAPI{live: &tagmanager.ContainerVersion{
+		Tag: []*tagmanager.Tag{
+			{TagId: "1", Name: "Google tag", Type: TagTypeGoogleTag, Parameter: []*tagmanager.Parameter{param("tagId", "G-ABC123")}, FiringTriggerId: []string{"2147479553"}},
+			{TagId: "2", Name: "GA4 - sign_up", Type: TagTypeGA4Event, Parameter: []*tagmanager.Parameter{param("eventName", "sign_up")}, Paused: true},
+		},
+		Trigger:  []*tagmanager.Trigger{{TriggerId: "7", Name: "CE - sign_up", Type: "customEvent"}},
+		Variable: []*tagmanager.Variable{{Name: "GA4 ID", Type: "c", Parameter: []*tagmanager.Parameter{param("value", "G-ABC123")}}},
+	}}
+	c, err := (&Client{api: fake}).Container(context.Background(), "6001", "9002", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "accounts/6001/containers/9002", fake.gotPath)
+	assert.Equal(t, "live version", c.Source)
+	assert.Equal(t, "G-ABC123", c.Tags[0].MeasurementID)
+	assert.Equal(t, "sign_up", c.Tags[1].EventName)
+	assert.True(t, c.Tags[1].Paused)
+	assert.Equal(t, []Variable{{Name: "GA4 ID", Type: "c", Value: "G-ABC123"}}, c.Variables)
+}
+
+func TestClient_Container_Workspace(t *testing.T) {
+	fake := &fakeContainerAPI{ws: &tagmanager.ContainerVersion{}}
+	c, err := (&Client{api: fake}).Container(context.Background(), "6001", "9002", "12")
+	require.NoError(t, err)
+	assert.True(t, fake.workspaceCall)
+	assert.Equal(t, "accounts/6001/containers/9002/workspaces/12", fake.gotPath)
+	assert.Equal(t, "workspace 12", c.Source)
+}
+
+func TestClient_Container_Unpublished(t *testing.T) {
+	fake := &fakeContainerAPI{liveErr: &googleapi.Error{Code: http.StatusNotFound}}
+	_, err := (&Client{api: fake}).Container(context.Background(), "6001", "9002", "")
+	assert.ErrorContains(t, err, "no published version")
+}
+
+func TestContainer_Resolve(t *testing.T) {
+	c := &Container{Variables: []Variable{{Name: "Prefix", Type: "c", Value: "lead"}, {Name: "Page Path", Type: "v"}}}
+
+	got, dynamic := c.Resolve("{{Prefix}}_submit")
+	assert.Equal(t, "lead_submit", got)
+	assert.False(t, dynamic)
+
+	got, dynamic = c.Resolve("{{Event}}")
+	assert.Equal(t, "{{Event}}", got)
+	assert.True(t, dynamic)
+
+	got, dynamic = c.Resolve("plain")
+	assert.Equal(t, "plain", got)
+	assert.False(t, dynamic)
+}
@@ -0,0 +1,178 @@
+package gtm
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// ExportFormatVersion is the version of the container export format GTM
+// writes and imports (Admin → Export Container / Import Container).
+const ExportFormatVersion = 2
+
+// allPagesTriggerID is GTM's built-in All Pages trigger, which every
+// container has under this ID.
+const allPagesTriggerID = "2147479553"
+
+// measurementIDVariable is the constant variable the generated tags send to.
+const measurementIDVariable = "GA4 Measurement ID"
+
+// Export is a container export file. Enum values are written in the
+// upper case of GTM's exports (TEMPLATE, CUSTOM_EVENT), not the API's.
+type Export struct {
+	ExportFormatVersion int                          `json:"exportFormatVersion"`
+	ExportTime          string                       `json:"exportTime"`
+	ContainerVersion    *tagmanager.ContainerVersion `json:"containerVersion"`
+}
+
+// ExportEvent is an event to send with a GA4 Event tag, and the parameters
+// to read from the dataLayer for it.
+type ExportEvent struct {
+	Name   string
+	Params []string
+}
+
+// ExportSpec describes the container to generate: a Google tag for
+// MeasurementID on every page and, per event, a custom event trigger on
+// dataLayer pushes of that name and a GA4 Event tag forwarding the event's
+// parameters and the user properties.
+type ExportSpec struct {
+	Name           string
+	MeasurementID  string
+	Events         []ExportEvent
+	UserProperties []string
+}
+
+// NewExport builds the container export for spec. Importing it into an
+// existing container with the merge option adds the generated tags,
+// triggers and variables; name conflicts are resolved by GTM.
+func NewExport(spec ExportSpec, now time.Time) *Export {
+	g := &exportBuilder{variables: map[string]bool{}}
+	g.constant(measurementIDVariable, spec.MeasurementID)
+	measurementID := "{{" + measurementIDVariable + "}}"
+
+	g.version.Tag = append(g.version.Tag, &tagmanager.Tag{
+		TagId:           g.nextID(),
+		Name:            "Google tag",
+		Type:            TagTypeGoogleTag,
+		Parameter:       []*tagmanager.Parameter{template("tagId", measurementID)},
+		FiringTriggerId: []string{allPagesTriggerID},
+		TagFiringOption: "ONCE_PER_EVENT",
+	})
+
+	var userProperties []*tagmanager.Parameter
+	for _, name := range spec.UserProperties {
+		userProperties = append(userProperties, &tagmanager.Parameter{Type: "MAP", Map: []*tagmanager.Parameter{
+			template("name", name),
+			template("value", g.dataLayerVariable(name)),
+		}})
+	}
+
+	for _, e := range spec.Events {
+		triggerID := g.nextID()
+		g.version.Trigger = append(g.version.Trigger, &tagmanager.Trigger{
+			TriggerId: triggerID,
+			Name:      "Event - " + e.Name,
+			Type:      "CUSTOM_EVENT",
+			CustomEventFilter: []*tagmanager.Condition{{
+				Type: "EQUALS",
+				Parameter: []*tagmanager.Parameter{
+					template("arg0", "{{_event}}"),
+					template("arg1", e.Name),
+				},
+			}},
+		})
+
+		params := []*tagmanager.Parameter{
+			template("eventName", e.Name),
+			template("measurementIdOverride", measurementID),
+		}
+		if len(e.Params) > 0 {
+			table := &tagmanager.Parameter{Type: "LIST", Key: "eventSettingsTable"}
+			for _, p := range e.Params {
+				table.List = append(table.List, &tagmanager.Parameter{Type: "MAP", Map: []*tagmanager.Parameter{
+					template("parameter", p),
+					template("parameterValue", g.dataLayerVariable(p)),
+				}})
+			}
+			params = append(params, table)
+		}
+		if len(userProperties) > 0 {
+			params = append(params, &tagmanager.Parameter{Type: "LIST", Key: "userProperties", List: userProperties})
+		}
+		g.version.Tag = append(g.version.Tag, &tagmanager.Tag{
+			TagId:           g.nextID(),
+			Name:            "GA4 Event - " + e.Name,
+			Type:            TagTypeGA4Event,
+			Parameter:       params,
+			FiringTriggerId: []string{triggerID},
+			TagFiringOption: "ONCE_PER_EVENT",
+		})
+	}
+
+	g.version.Path = "accounts/0/containers/0/versions/0"
+	g.version.AccountId = "0"
+	g.version.ContainerId = "0"
+	g.version.ContainerVersionId = "0"
+	g.version.Container = &tagmanager.Container{
+		AccountId:    "0",
+		ContainerId:  "0",
+		Name:         spec.Name,
+		UsageContext: []string{"WEB"},
+	}
+	// {{_event}} in the triggers is the built-in Event variable.
+	g.version.BuiltInVariable = []*tagmanager.BuiltInVariable{{Type: "EVENT", Name: "Event"}}
+
+	return &Export{
+		ExportFormatVersion: ExportFormatVersion,
+		ExportTime:          now.UTC().Format("2006-01-02 15:04:05"),
+		ContainerVersion:    &g.version,
+	}
+}
+
+// exportBuilder numbers the generated entities and creates each dataLayer
+// variable once.
+type exportBuilder struct {
+	version   tagmanager.ContainerVersion
+	id        int
+	variables map[string]bool
+}
+
+func (g *exportBuilder) nextID() string {
+	g.id++
+	return strconv.Itoa(g.id)
+}
+
+func (g *exportBuilder) constant(name, value string) {
+	g.version.Variable = append(g.version.Variable, &tagmanager.Variable{
+		VariableId: g.nextID(),
+		Name:       name,
+		Type:       "c",
+		Parameter:  []*tagmanager.Parameter{template("value", value)},
+	})
+}
+
+// dataLayerVariable returns a reference to the dataLayer variable reading
+// key, creating it on first use.
+func (g *exportBuilder) dataLayerVariable(key string) string {
+	name := "DLV - " + key
+	if !g.variables[name] {
+		g.variables[name] = true
+		g.version.Variable = append(g.version.Variable, &tagmanager.Variable{
+			VariableId: g.nextID(),
+			Name:       name,
+			Type:       "v",
+			Parameter: []*tagmanager.Parameter{
+				{Type: "INTEGER", Key: "dataLayerVersion", Value: "2"},
+				{Type: "BOOLEAN", Key: "setDefaultValue", Value: "false"},
+				template("name", key),
+			},
+		})
+	}
+	return "{{" + name + "}}"
+}
+
+func template(key, value string) *tagmanager.Parameter {
+	return &tagmanager.Parameter{Type: "TEMPLATE", Key: key, Value: value}
+}
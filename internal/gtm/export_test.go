@@ -0,0 +1,102 @@
+package gtm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExport_PassesAudit(t *testing.T) {
+	export := NewExport(ExportSpec{
+		Name:          "Example",
+		MeasurementID: "G-TEST123",
+		Events: []ExportEvent{
+			{Name: "purchase", Params: []string{"plan_type", "value", "currency"}},
+			{Name: "generate_lead", Params: []string{"plan_type"}},
+		},
+		UserProperties: []string{"customer_tier"},
+	}, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+
+	assert.Equal(t, ExportFormatVersion, export.ExportFormatVersion)
+	assert.Equal(t, "2026-10-16 09:30:00", export.ExportTime)
+
+	c := newContainer("generated", "export", export.ContainerVersion)
+	r := Audit(c, []ExpectedEvent{{Name: "purchase"}, {Name: "generate_lead"}})
+	assert.Zero(t, r.Problems())
+	assert.Empty(t, r.Unexpected)
+
+	link := CheckLinkage(c, []string{"G-TEST123"})
+	assert.True(t, link.Linked(), "tags send to the constant variable's measurement ID")
+
+	// One dataLayer variable per key, however many tags read it.
+	var names []string
+	for _, v := range c.Variables {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{measurementIDVariable, "DLV - customer_tier", "DLV - plan_type", "DLV - value", "DLV - currency"}, names)
+}
+
+func TestNewExport_JSON(t *testing.T) {
+	export := NewExport(ExportSpec{
+		Name:          "Example",
+		MeasurementID: "G-TEST123",
+		Events:        []ExportEvent{{Name: "purchase", Params: []string{"value"}}},
+	}, time.Now())
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+
+	var doc struct {
+		ContainerVersion struct {
+			Tag []struct {
+				Name            string   `json:"name"`
+				Type            string   `json:"type"`
+				FiringTriggerID []string `json:"firingTriggerId"`
+				Parameter       []struct {
+					Type  string `json:"type"`
+					Key   string `json:"key"`
+					Value string `json:"value"`
+					List  []struct {
+						Map []struct {
+							Key   string `json:"key"`
+							Value string `json:"value"`
+						} `json:"map"`
+					} `json:"list"`
+				} `json:"parameter"`
+			} `json:"tag"`
+			Trigger []struct {
+				TriggerID         string `json:"triggerId"`
+				Type              string `json:"type"`
+				CustomEventFilter []struct {
+					Type      string `json:"type"`
+					Parameter []struct {
+						Key   string `json:"key"`
+						Value string `json:"value"`
+					} `json:"parameter"`
+				} `json:"customEventFilter"`
+			} `json:"trigger"`
+		} `json:"containerVersion"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	tags := doc.ContainerVersion.Tag
+	require.Len(t, tags, 2)
+	assert.Equal(t, TagTypeGoogleTag, tags[0].Type)
+	assert.Equal(t, []string{allPagesTriggerID}, tags[0].FiringTriggerID)
+
+	event := tags[1]
+	assert.Equal(t, "GA4 Event - purchase", event.Name)
+	assert.Equal(t, "TEMPLATE", event.Parameter[0].Type)
+	assert.Equal(t, "purchase", event.Parameter[0].Value)
+	require.Equal(t, "eventSettingsTable", event.Parameter[2].Key)
+	assert.Equal(t, "value", event.Parameter[2].List[0].Map[0].Value)
+	assert.Equal(t, "{{DLV - value}}", event.Parameter[2].List[0].Map[1].Value)
+
+	trigger := doc.ContainerVersion.Trigger[0]
+	assert.Equal(t, "CUSTOM_EVENT", trigger.Type)
+	assert.Equal(t, []string{trigger.TriggerID}, event.FiringTriggerID)
+	assert.Equal(t, "EQUALS", trigger.CustomEventFilter[0].Type)
+	assert.Equal(t, "purchase", trigger.CustomEventFilter[0].Parameter[1].Value)
+}
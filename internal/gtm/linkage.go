@@ -0,0 +1,86 @@
+package gtm
+
+import "strings"
+
+// TagTarget is an active tag that sends hits to a GA4 measurement ID.
+// MeasurementID has constant variables resolved; Dynamic means it still
+// depends on a run-time variable (a lookup table, say) or is a GT- Google
+// tag ID whose destinations are configured outside the container.
+type TagTarget struct {
+	Tag           string `json:"tag"`
+	Type          string `json:"type"`
+	MeasurementID string `json:"measurement_id"`
+	Dynamic       bool   `json:"dynamic,omitempty"`
+}
+
+// Linkage compares the measurement IDs a container sends to with those of
+// the property's web streams.
+type Linkage struct {
+	Container string      `json:"container"`
+	Want      []string    `json:"want"`
+	Targets   []TagTarget `json:"targets"`
+}
+
+// CheckLinkage collects the GA4 destinations of the container's active
+// Google tags, GA4 configuration tags and event tags with a measurement ID
+// override. Google tags for other products (AW- for Ads) are ignored.
+func CheckLinkage(c *Container, want []string) *Linkage {
+	l := &Linkage{Container: c.Path, Want: want, Targets: []TagTarget{}}
+	for _, t := range c.Tags {
+		if t.Paused || len(t.FiringTriggers) == 0 || t.MeasurementID == "" {
+			continue
+		}
+		id, dynamic := c.Resolve(t.MeasurementID)
+		id = strings.ToUpper(strings.TrimSpace(id))
+		switch {
+		case dynamic, strings.HasPrefix(id, "GT-"):
+			l.Targets = append(l.Targets, TagTarget{Tag: t.Name, Type: t.Type, MeasurementID: id, Dynamic: true})
+		case strings.HasPrefix(id, "G-"):
+			l.Targets = append(l.Targets, TagTarget{Tag: t.Name, Type: t.Type, MeasurementID: id})
+		}
+	}
+	return l
+}
+
+// Linked reports whether some tag sends to one of the wanted IDs.
+func (l *Linkage) Linked() bool {
+	for _, t := range l.Targets {
+		if !t.Dynamic && l.wants(t.MeasurementID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mismatched returns the tags that send to a GA4 measurement ID that is not
+// one of the property's streams: usually a tag still pointing at an old
+// property.
+func (l *Linkage) Mismatched() []TagTarget {
+	var out []TagTarget
+	for _, t := range l.Targets {
+		if !t.Dynamic && !l.wants(t.MeasurementID) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Unverifiable returns the tags whose destination is only known at run time.
+func (l *Linkage) Unverifiable() []TagTarget {
+	var out []TagTarget
+	for _, t := range l.Targets {
+		if t.Dynamic {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (l *Linkage) wants(id string) bool {
+	for _, w := range l.Want {
+		if strings.EqualFold(w, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package gtm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLinkage(t *testing.T) {
+	c := &Container{
+		Path: "accounts/1/containers/2",
+		Tags: []Tag{
+			{Name: "Google tag", Type: TagTypeGoogleTag, MeasurementID: "{{GA4 ID}}", FiringTriggers: []string{"1"}},
+			{Name: "GA4 config (legacy)", Type: TagTypeGA4Config, MeasurementID: "g-old999", FiringTriggers: []string{"1"}},
+			{Name: "Ads tag", Type: TagTypeGoogleTag, MeasurementID: "AW-123", FiringTriggers: []string{"1"}},
+			{Name: "Paused config", Type: TagTypeGA4Config, MeasurementID: "G-PAUSED", Paused: true, FiringTriggers: []string{"1"}},
+			{Name: "Event override", Type: TagTypeGA4Event, MeasurementID: "{{Lookup - GA4 ID}}", FiringTriggers: []string{"1"}},
+		},
+		Variables: []Variable{{Name: "GA4 ID", Type: "c", Value: "G-NEW123"}, {Name: "Lookup - GA4 ID", Type: "smm"}},
+	}
+
+	l := CheckLinkage(c, []string{"G-NEW123"})
+	require.Len(t, l.Targets, 3)
+	assert.True(t, l.Linked())
+	assert.Equal(t, []TagTarget{{Tag: "GA4 config (legacy)", Type: TagTypeGA4Config, MeasurementID: "G-OLD999"}}, l.Mismatched())
+	require.Len(t, l.Unverifiable(), 1)
+	assert.Equal(t, "Event override", l.Unverifiable()[0].Tag)
+
+	l = CheckLinkage(c, []string{"G-OTHER"})
+	assert.False(t, l.Linked())
+	assert.Len(t, l.Mismatched(), 2)
+}
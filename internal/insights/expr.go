@@ -0,0 +1,376 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule conditions are a small expression language over the metrics of one
+// group (see Vars):
+//
+//	expr       = or
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | comparison
+//	comparison = sum ( ">" | ">=" | "<" | "<=" | "==" | "!=" ) sum
+//	           | "(" expr ")"
+//	sum        = term { ( "+" | "-" ) term }
+//	term       = factor { ( "*" | "/" ) factor }
+//	factor     = number [ "%" ] | variable | "-" factor | "(" sum ")"
+//
+// Keywords are case-insensitive; "20%" is 0.2. A comparison involving a
+// value that does not exist, such as clicks_change when there were no prior
+// clicks, is false.
+
+type exprTokenKind int
+
+const (
+	exprEOF exprTokenKind = iota
+	exprIdent
+	exprNumber
+	exprOp
+	exprLParen
+	exprRParen
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// tokenizeExpr splits a condition into tokens.
+func tokenizeExpr(s string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, exprToken{kind: exprLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, exprToken{kind: exprRParen, text: ")", pos: i})
+			i++
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			tokens = append(tokens, exprToken{kind: exprOp, text: string(c), pos: i})
+			i++
+		case c == '<' || c == '>' || c == '=' || c == '!':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("at %d: expected == or !=", i)
+			}
+			tokens = append(tokens, exprToken{kind: exprOp, text: op, pos: i})
+			i += len(op)
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+				i++
+			}
+			n, err := strconv.ParseFloat(s[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("at %d: invalid number %q", start, s[start:i])
+			}
+			if i < len(s) && s[i] == '%' {
+				n /= 100
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprNumber, text: s[start:i], num: n, pos: start})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(s) && (s[i] == '_' || s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z' || s[i] >= '0' && s[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprIdent, text: s[start:i], pos: start})
+		default:
+			return nil, fmt.Errorf("at %d: unexpected %q", i, c)
+		}
+	}
+	return append(tokens, exprToken{kind: exprEOF, pos: len(s)}), nil
+}
+
+// exprNode is a parsed expression. Boolean nodes (and, or, not and the
+// comparisons) have boolean set; the others evaluate to numbers.
+type exprNode struct {
+	op       string // "and", "or", "not", a comparison or arithmetic operator, "num" or "var"
+	boolean  bool
+	children []*exprNode
+	num      float64
+	name     string
+}
+
+// Expr is a compiled rule condition.
+type Expr struct {
+	src  string
+	root *exprNode
+	vars []string
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string { return e.src }
+
+// Vars returns the variables the expression reads, sorted.
+func (e *Expr) Vars() []string { return e.vars }
+
+// Compile parses a condition. Every variable must be one of Vars.
+func Compile(src string) (*Expr, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, vars: map[string]bool{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != exprEOF {
+		return nil, fmt.Errorf("at %d: unexpected %q", t.pos, t.text)
+	}
+	if !root.boolean {
+		return nil, fmt.Errorf("the condition must compare values, e.g. position_delta > 2")
+	}
+	vars := make([]string, 0, len(p.vars))
+	for v := range p.vars {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return &Expr{src: src, root: root, vars: vars}, nil
+}
+
+// Eval evaluates the expression against vars. A missing or NaN variable
+// makes every comparison that reads it false.
+func (e *Expr) Eval(vars map[string]float64) bool {
+	return evalBool(e.root, vars)
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	vars   map[string]bool
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.pos] }
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.pos]
+	if t.kind != exprEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == exprIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (*exprNode, error) {
+	return p.parseLogical("or", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (*exprNode, error) {
+	return p.parseLogical("and", p.parseUnary)
+}
+
+func (p *exprParser) parseLogical(op string, operand func() (*exprNode, error)) (*exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.keyword(op) {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if !left.boolean || !right.boolean {
+			return nil, fmt.Errorf("at %d: %q needs a comparison on each side", pos, op)
+		}
+		left = &exprNode{op: op, boolean: true, children: []*exprNode{left, right}}
+	}
+}
+
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	pos := p.peek().pos
+	if p.keyword("not") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if !operand.boolean {
+			return nil, fmt.Errorf("at %d: \"not\" needs a comparison", pos)
+		}
+		return &exprNode{op: "not", boolean: true, children: []*exprNode{operand}}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (*exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if left.boolean {
+		// A parenthesised condition.
+		return left, nil
+	}
+	t := p.peek()
+	switch t.text {
+	case ">", ">=", "<", "<=", "==", "!=":
+		if t.kind != exprOp {
+			break
+		}
+		p.next()
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if right.boolean {
+			return nil, fmt.Errorf("at %d: cannot compare a condition", t.pos)
+		}
+		return &exprNode{op: t.text, boolean: true, children: []*exprNode{left, right}}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseSum() (*exprNode, error) {
+	return p.parseArithmetic("+-", p.parseTerm)
+}
+
+func (p *exprParser) parseTerm() (*exprNode, error) {
+	return p.parseArithmetic("*/", p.parseFactor)
+}
+
+func (p *exprParser) parseArithmetic(ops string, operand func() (*exprNode, error)) (*exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != exprOp || !strings.Contains(ops, t.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if left.boolean || right.boolean {
+			return nil, fmt.Errorf("at %d: %q needs numbers on each side", t.pos, t.text)
+		}
+		left = &exprNode{op: t.text, children: []*exprNode{left, right}}
+	}
+}
+
+func (p *exprParser) parseFactor() (*exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case exprNumber:
+		return &exprNode{op: "num", num: t.num}, nil
+	case exprIdent:
+		if !isVar(t.text) {
+			return nil, fmt.Errorf("at %d: unknown variable %q (want one of %s)", t.pos, t.text, strings.Join(Vars(), ", "))
+		}
+		p.vars[t.text] = true
+		return &exprNode{op: "var", name: t.text}, nil
+	case exprOp:
+		if t.text == "-" {
+			operand, err := p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+			if operand.boolean {
+				return nil, fmt.Errorf("at %d: cannot negate a condition", t.pos)
+			}
+			return &exprNode{op: "neg", children: []*exprNode{operand}}, nil
+		}
+	case exprLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != exprRParen {
+			return nil, fmt.Errorf("at %d: expected )", c.pos)
+		}
+		return inner, nil
+	case exprEOF:
+		return nil, fmt.Errorf("at %d: unexpected end of condition", t.pos)
+	}
+	return nil, fmt.Errorf("at %d: unexpected %q", t.pos, t.text)
+}
+
+func evalBool(n *exprNode, vars map[string]float64) bool {
+	switch n.op {
+	case "and":
+		return evalBool(n.children[0], vars) && evalBool(n.children[1], vars)
+	case "or":
+		return evalBool(n.children[0], vars) || evalBool(n.children[1], vars)
+	case "not":
+		return !evalBool(n.children[0], vars)
+	}
+	a, b := evalNum(n.children[0], vars), evalNum(n.children[1], vars)
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return false
+	}
+	switch n.op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
+
+func evalNum(n *exprNode, vars map[string]float64) float64 {
+	switch n.op {
+	case "num":
+		return n.num
+	case "var":
+		v, ok := vars[n.name]
+		if !ok {
+			return math.NaN()
+		}
+		return v
+	case "neg":
+		return -evalNum(n.children[0], vars)
+	}
+	a, b := evalNum(n.children[0], vars), evalNum(n.children[1], vars)
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			return math.NaN()
+		}
+		return a / b
+	}
+	return math.NaN()
+}
@@ -0,0 +1,58 @@
+package insights
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile_Eval(t *testing.T) {
+	vars := map[string]float64{"position_delta": 2.5, "clicks_change": -0.3, "impressions_prior": 800, "ctr": 0.02}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"position_delta > 2", true},
+		{"position_delta >= 3", false},
+		{"clicks_change <= -20%", true},
+		{"clicks_change <= -20% and impressions_prior >= 1000", false},
+		{"clicks_change <= -20% or impressions_prior >= 1000", true},
+		{"NOT (position_delta > 2)", false},
+		{"ctr * 100 < 3", true},
+		{"position_delta - 1 / 2 == 2", true},
+		{"-position_delta < 0", true},
+		{"(position_delta > 2) and (ctr != 0)", true},
+		{"clicks_delta < 0", false}, // missing variables never compare true
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, e.Eval(vars), tt.expr)
+	}
+}
+
+func TestCompile_Vars(t *testing.T) {
+	e, err := Compile("position_delta > 2 and clicks_change < -10% and position_delta < 10")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clicks_change", "position_delta"}, e.Vars())
+	assert.Equal(t, "position_delta > 2 and clicks_change < -10% and position_delta < 10", e.String())
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"":                             "unexpected end",
+		"position_delta":               "must compare",
+		"rank > 2":                     `unknown variable "rank"`,
+		"position_delta = 2":           "expected == or !=",
+		"position_delta > 2 and 3":     "needs a comparison",
+		"(position_delta > 2) + 1 > 0": "needs numbers",
+		"position_delta > (2":          "expected )",
+		"position_delta > 2 2":         "unexpected",
+		"position_delta > 2 # x":       "unexpected",
+	} {
+		_, err := Compile(expr)
+		require.Error(t, err, expr)
+		assert.Contains(t, err.Error(), want, expr)
+	}
+}
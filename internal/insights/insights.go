@@ -0,0 +1,324 @@
+// Package insights evaluates the config's insights: rules against Search
+// Console data. A rule narrows the Search Analytics rows of two consecutive
+// windows to some pages or queries, aggregates them (in total or per page,
+// query or content group) and flags each group its condition holds for,
+// such as "average position of pages under /tools/ worsened by more than 2
+// over 28 days".
+//
+// It generalises the fixed thresholds of the gsc diagnostics: the predicate
+// is an expression (see Compile) over the variables of Vars.
+package insights
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/contentgroup"
+	"github.com/garbarok/ga4-manager/internal/gsc/diagnostics"
+)
+
+// DefaultDays is the window length of a rule that does not set days.
+const DefaultDays = 28
+
+// Severities, most urgent first.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+var severityRank = map[string]int{SeverityHigh: 0, SeverityMedium: 1, SeverityLow: 2}
+
+// metricNames are the Search Analytics metrics a condition can read; each
+// also comes with the _prior, _delta and _change suffixes.
+var metricNames = []string{"clicks", "impressions", "ctr", "position"}
+
+// Vars returns the variables a condition can use: each metric's current
+// value, its value over the prior window (_prior), the difference
+// (_delta, current − prior) and the relative change (_change, delta / prior;
+// -20% is -0.2). A higher position is a worse one, so position_delta > 0 is
+// a drop.
+func Vars() []string {
+	vars := make([]string, 0, len(metricNames)*4)
+	for _, m := range metricNames {
+		vars = append(vars, m, m+"_prior", m+"_delta", m+"_change")
+	}
+	return vars
+}
+
+func isVar(name string) bool {
+	for _, v := range Vars() {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Metrics are the aggregated Search Analytics numbers of one window.
+type Metrics struct {
+	Clicks      int64   `json:"clicks"`
+	Impressions int64   `json:"impressions"`
+	CTR         float64 `json:"ctr"`
+	Position    float64 `json:"position"`
+}
+
+func (m Metrics) values() [4]float64 {
+	return [4]float64{float64(m.Clicks), float64(m.Impressions), m.CTR, m.Position}
+}
+
+// Group is one thing a rule judges: the total of the matching rows, or one
+// page, query or content group.
+type Group struct {
+	Subject string
+	Current Metrics
+	Prior   Metrics
+}
+
+// Vars returns the values of the condition variables for g.
+func (g Group) Vars() map[string]float64 {
+	vars := make(map[string]float64, len(metricNames)*4)
+	cur, prior := g.Current.values(), g.Prior.values()
+	for i, m := range metricNames {
+		vars[m] = cur[i]
+		vars[m+"_prior"] = prior[i]
+		vars[m+"_delta"] = cur[i] - prior[i]
+		if prior[i] != 0 {
+			vars[m+"_change"] = (cur[i] - prior[i]) / prior[i]
+		}
+	}
+	return vars
+}
+
+// Rule is a compiled insights rule.
+type Rule struct {
+	config.InsightRuleConfig
+	When  *Expr
+	query *regexp.Regexp
+}
+
+// CompileRules compiles the rules of cfg, defaulting by, days and severity.
+func CompileRules(cfg []config.InsightRuleConfig) ([]Rule, error) {
+	rules := make([]Rule, 0, len(cfg))
+	for _, rc := range cfg {
+		when, err := Compile(rc.When)
+		if err != nil {
+			return nil, fmt.Errorf("insight %q: invalid when: %w", rc.Name, err)
+		}
+		r := Rule{InsightRuleConfig: rc, When: when}
+		if r.By == "" {
+			r.By = config.InsightByTotal
+		}
+		if r.Days == 0 {
+			r.Days = DefaultDays
+		}
+		if r.Severity == "" {
+			r.Severity = SeverityMedium
+		}
+		if rc.Queries != "" {
+			r.query = regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(rc.Queries), `\*`, ".*") + "$")
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Dimensions returns the Search Analytics dimensions the rule's rows need,
+// in the order Rows keys them.
+func (r Rule) Dimensions() []string {
+	var dims []string
+	if r.query != nil || r.By == config.InsightByQuery {
+		dims = append(dims, "query")
+	}
+	if r.Pages != "" || r.By == config.InsightByPage || r.By == config.InsightByContentGroup {
+		dims = append(dims, "page")
+	}
+	return dims
+}
+
+// Groups aggregates the rows of both windows, queried with Dimensions, into
+// the groups the rule judges. grouper assigns pages to content groups for
+// by: content_group.
+func (r Rule) Groups(current, prior []gsc.SearchAnalyticsRow, grouper *contentgroup.Grouper) []Group {
+	byKey := map[string]*groupTotals{}
+	var order []string
+	add := func(rows []gsc.SearchAnalyticsRow, isPrior bool) {
+		for _, row := range rows {
+			key, ok := r.subject(row, grouper)
+			if !ok {
+				continue
+			}
+			t, seen := byKey[key]
+			if !seen {
+				t = &groupTotals{}
+				byKey[key] = t
+				order = append(order, key)
+			}
+			if isPrior {
+				t.prior.add(row)
+			} else {
+				t.current.add(row)
+			}
+		}
+	}
+	add(current, false)
+	add(prior, true)
+
+	sort.Strings(order)
+	groups := make([]Group, 0, len(order))
+	for _, key := range order {
+		t := byKey[key]
+		g := Group{Subject: key, Current: t.current.metrics(), Prior: t.prior.metrics()}
+		if max(g.Current.Impressions, g.Prior.Impressions) < r.MinImpressions {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// subject returns what row is counted under, or false when the rule's
+// filters drop it.
+func (r Rule) subject(row gsc.SearchAnalyticsRow, grouper *contentgroup.Grouper) (string, bool) {
+	var query, page string
+	i := 0
+	for _, d := range r.Dimensions() {
+		if i >= len(row.Keys) {
+			return "", false
+		}
+		switch d {
+		case "query":
+			query = row.Keys[i]
+		case "page":
+			page = row.Keys[i]
+		}
+		i++
+	}
+	if r.query != nil && !r.query.MatchString(query) {
+		return "", false
+	}
+	if r.Pages != "" && !(diagnostics.URLPattern{Pattern: r.Pages}).Matches(page) {
+		return "", false
+	}
+	switch r.By {
+	case config.InsightByPage:
+		return page, true
+	case config.InsightByQuery:
+		return query, true
+	case config.InsightByContentGroup:
+		if grouper == nil {
+			return contentgroup.Other, true
+		}
+		return grouper.Group(page), true
+	}
+	return r.totalSubject(), true
+}
+
+// totalSubject names the total of a rule's rows.
+func (r Rule) totalSubject() string {
+	var parts []string
+	if r.Pages != "" {
+		parts = append(parts, "pages "+r.Pages)
+	}
+	if r.Queries != "" {
+		parts = append(parts, "queries "+r.Queries)
+	}
+	if len(parts) == 0 {
+		return "all pages"
+	}
+	return strings.Join(parts, ", ")
+}
+
+type groupTotals struct {
+	current, prior windowTotals
+}
+
+// windowTotals sums rows; position is weighted by impressions, as Search
+// Console does.
+type windowTotals struct {
+	clicks, impressions int64
+	weightedPosition    float64
+}
+
+func (t *windowTotals) add(row gsc.SearchAnalyticsRow) {
+	t.clicks += row.Clicks
+	t.impressions += row.Impressions
+	t.weightedPosition += row.Position * float64(row.Impressions)
+}
+
+func (t windowTotals) metrics() Metrics {
+	m := Metrics{Clicks: t.clicks, Impressions: t.impressions}
+	if t.impressions > 0 {
+		m.CTR = float64(t.clicks) / float64(t.impressions)
+		m.Position = t.weightedPosition / float64(t.impressions)
+	}
+	return m
+}
+
+// Finding is a group a rule's condition held for.
+type Finding struct {
+	Rule        string             `json:"rule"`
+	Severity    string             `json:"severity"`
+	Subject     string             `json:"subject"`
+	Description string             `json:"description,omitempty"`
+	When        string             `json:"when"`
+	Days        int                `json:"days"`
+	Current     Metrics            `json:"current"`
+	Prior       Metrics            `json:"prior"`
+	Values      map[string]float64 `json:"values"`
+	ClicksLost  int64              `json:"clicks_lost"`
+}
+
+// Evaluate returns a finding for each group the rule's condition holds for.
+// Values holds the variables the condition read.
+func (r Rule) Evaluate(groups []Group) []Finding {
+	var findings []Finding
+	for _, g := range groups {
+		vars := g.Vars()
+		if !r.When.Eval(vars) {
+			continue
+		}
+		values := make(map[string]float64, len(r.When.Vars()))
+		for _, v := range r.When.Vars() {
+			if x, ok := vars[v]; ok && !math.IsNaN(x) {
+				values[v] = x
+			}
+		}
+		findings = append(findings, Finding{
+			Rule:        r.Name,
+			Severity:    r.Severity,
+			Subject:     g.Subject,
+			Description: r.Description,
+			When:        r.When.String(),
+			Days:        r.Days,
+			Current:     g.Current,
+			Prior:       g.Prior,
+			Values:      values,
+			ClicksLost:  max(g.Prior.Clicks-g.Current.Clicks, 0),
+		})
+	}
+	return findings
+}
+
+// Prioritize orders findings most urgent first: by severity, then by clicks
+// lost, then by rule and subject.
+func Prioritize(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if a.ClicksLost != b.ClicksLost {
+			return a.ClicksLost > b.ClicksLost
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Subject < b.Subject
+	})
+}
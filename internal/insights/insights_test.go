@@ -0,0 +1,105 @@
+package insights
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/contentgroup"
+)
+
+func row(clicks, impressions int64, position float64, keys ...string) gsc.SearchAnalyticsRow {
+	return gsc.SearchAnalyticsRow{Keys: keys, Clicks: clicks, Impressions: impressions, Position: position}
+}
+
+func compileOne(t *testing.T, rc config.InsightRuleConfig) Rule {
+	t.Helper()
+	rules, err := CompileRules([]config.InsightRuleConfig{rc})
+	require.NoError(t, err)
+	return rules[0]
+}
+
+func TestCompileRules_Defaults(t *testing.T) {
+	r := compileOne(t, config.InsightRuleConfig{Name: "drop", When: "clicks_change < -20%"})
+	assert.Equal(t, config.InsightByTotal, r.By)
+	assert.Equal(t, DefaultDays, r.Days)
+	assert.Equal(t, SeverityMedium, r.Severity)
+	assert.Empty(t, r.Dimensions())
+
+	_, err := CompileRules([]config.InsightRuleConfig{{Name: "bad", When: "rank > 2"}})
+	assert.ErrorContains(t, err, `insight "bad": invalid when`)
+}
+
+// The position of pages under /tools/ is aggregated, weighted by
+// impressions, and compared with the prior window.
+func TestRule_TotalOfMatchingPages(t *testing.T) {
+	r := compileOne(t, config.InsightRuleConfig{Name: "tools-position", Pages: "/tools/*", When: "position_delta > 2", Severity: SeverityHigh})
+	assert.Equal(t, []string{"page"}, r.Dimensions())
+
+	current := []gsc.SearchAnalyticsRow{
+		row(10, 100, 8, "https://example.com/tools/a"),
+		row(5, 300, 12, "https://example.com/tools/b/c"),
+		row(90, 1000, 1, "https://example.com/blog/x"),
+	}
+	prior := []gsc.SearchAnalyticsRow{
+		row(20, 100, 4, "https://example.com/tools/a"),
+		row(15, 300, 6, "https://example.com/tools/b/c"),
+	}
+	groups := r.Groups(current, prior, nil)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "pages /tools/*", groups[0].Subject)
+	assert.InDelta(t, 11, groups[0].Current.Position, 1e-9)
+	assert.InDelta(t, 5.5, groups[0].Prior.Position, 1e-9)
+
+	findings := r.Evaluate(groups)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityHigh, findings[0].Severity)
+	assert.Equal(t, int64(20), findings[0].ClicksLost)
+	assert.Equal(t, map[string]float64{"position_delta": 5.5}, findings[0].Values)
+}
+
+func TestRule_ByQueryWithFilters(t *testing.T) {
+	r := compileOne(t, config.InsightRuleConfig{Name: "q", By: config.InsightByQuery, Queries: "*compress*", When: "clicks_change <= -50%", MinImpressions: 50})
+	assert.Equal(t, []string{"query"}, r.Dimensions())
+
+	current := []gsc.SearchAnalyticsRow{row(2, 100, 3, "image compressor"), row(1, 10, 3, "compress pdf"), row(50, 100, 1, "png to webp")}
+	prior := []gsc.SearchAnalyticsRow{row(10, 100, 3, "image compressor"), row(10, 20, 3, "compress pdf"), row(100, 100, 1, "png to webp")}
+
+	groups := r.Groups(current, prior, nil)
+	require.Len(t, groups, 1, "png to webp is filtered out, compress pdf is below min_impressions")
+	findings := r.Evaluate(groups)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "image compressor", findings[0].Subject)
+	assert.InDelta(t, -0.8, findings[0].Values["clicks_change"], 1e-9)
+}
+
+func TestRule_ByContentGroup(t *testing.T) {
+	grouper, err := contentgroup.New([]contentgroup.Rule{{Name: "blog", Prefix: "/blog/"}})
+	require.NoError(t, err)
+	r := compileOne(t, config.InsightRuleConfig{Name: "sections", By: config.InsightByContentGroup, When: "clicks_delta < 0"})
+
+	current := []gsc.SearchAnalyticsRow{row(1, 10, 5, "https://example.com/blog/a"), row(5, 10, 5, "https://example.com/pricing")}
+	prior := []gsc.SearchAnalyticsRow{row(4, 10, 5, "https://example.com/blog/a"), row(5, 10, 5, "https://example.com/pricing")}
+
+	findings := r.Evaluate(r.Groups(current, prior, grouper))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "blog", findings[0].Subject)
+}
+
+func TestPrioritize(t *testing.T) {
+	findings := []Finding{
+		{Rule: "b", Severity: SeverityLow, ClicksLost: 500},
+		{Rule: "a", Severity: SeverityMedium, ClicksLost: 10},
+		{Rule: "c", Severity: SeverityHigh, ClicksLost: 1},
+		{Rule: "d", Severity: SeverityMedium, ClicksLost: 90},
+	}
+	Prioritize(findings)
+	var order []string
+	for _, f := range findings {
+		order = append(order, f.Rule)
+	}
+	assert.Equal(t, []string{"c", "d", "a", "b"}, order)
+}
@@ -0,0 +1,263 @@
+// Package lint checks a config against GA4 and Search Console best
+// practices that the loader does not enforce: the file is valid, but GA4
+// will reject, hide or misreport part of it. Each rule has an ID a project
+// can disable in its lint: section.
+package lint
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/depgraph"
+)
+
+// Severities, most serious first.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+var severityRank = map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// MaxMembershipDays is the longest audience membership duration GA4 allows.
+const MaxMembershipDays = 540
+
+// UnusedDimensionsShare is the share of custom dimensions that may go unused
+// before unused-dimensions fires.
+const UnusedDimensionsShare = 0.25
+
+// Rule is a check over the whole config.
+type Rule struct {
+	ID          string
+	Severity    string
+	Description string
+	check       func(cfg *config.ProjectConfig) []Finding
+}
+
+// Finding is one problem a rule found. Resource names what it is about,
+// such as "conversion Sign_Up".
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+// Rules lists every rule, in the order they run.
+func Rules() []Rule {
+	return []Rule{
+		{"event-name-case", SeverityWarning, "event names are snake_case", checkEventNameCase},
+		{"reserved-event-name", SeverityError, "custom events do not use names or prefixes GA4 reserves", checkReservedEventNames},
+		{"unused-dimensions", SeverityInfo, "at most 25% of custom dimensions are sent by no conversion and used by no audience", checkUnusedDimensions},
+		{"audience-duration", SeverityError, "audience membership lasts at most 540 days", checkAudienceDuration},
+		{"sitemap-domain", SeverityError, "sitemaps are on the Search Console property's domain", checkSitemapDomain},
+	}
+}
+
+// Run applies every rule of Rules except those in disabled and returns the
+// findings, most serious first. An unknown ID in disabled is an error, so a
+// typo does not silently keep a rule on.
+func Run(cfg *config.ProjectConfig, disabled []string) ([]Finding, error) {
+	rules := Rules()
+	skip := make(map[string]bool, len(disabled))
+	for _, id := range disabled {
+		known := false
+		for _, r := range rules {
+			known = known || r.ID == id
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		skip[id] = true
+	}
+
+	findings := []Finding{}
+	for _, r := range rules {
+		if skip[r.ID] {
+			continue
+		}
+		for _, f := range r.check(cfg) {
+			f.Rule, f.Severity = r.ID, r.Severity
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+	})
+	return findings, nil
+}
+
+// Disabled returns the rule IDs cfg's lint: section disables.
+func Disabled(cfg *config.ProjectConfig) []string {
+	if cfg.Lint == nil {
+		return nil
+	}
+	return cfg.Lint.Disable
+}
+
+// AtLeast reports whether severity is as serious as threshold or more.
+func AtLeast(severity, threshold string) bool {
+	return severityRank[severity] <= severityRank[threshold]
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// eventNames returns the events the config names: conversions, then the
+// events audiences are built on, each once.
+func eventNames(cfg *config.ProjectConfig) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, c := range cfg.Conversions {
+		add(c.Name)
+	}
+	for _, a := range cfg.Audiences {
+		for _, e := range a.Events {
+			add(e)
+		}
+	}
+	return names
+}
+
+func checkEventNameCase(cfg *config.ProjectConfig) []Finding {
+	var findings []Finding
+	for _, name := range eventNames(cfg) {
+		if !snakeCase.MatchString(name) {
+			findings = append(findings, Finding{
+				Resource: "event " + name,
+				Message:  fmt.Sprintf("%s is not snake_case; GA4's own events are, and names are case-sensitive in reports", name),
+			})
+		}
+	}
+	return findings
+}
+
+// reservedEvents are the event names GA4 collects itself and rejects from
+// custom events.
+var reservedEvents = map[string]bool{
+	"ad_activeview": true, "ad_click": true, "ad_exposure": true, "ad_impression": true,
+	"ad_query": true, "ad_reward": true, "adunit_exposure": true, "app_background": true,
+	"app_clear_data": true, "app_exception": true, "app_remove": true, "app_store_refund": true,
+	"app_store_subscription_cancel": true, "app_store_subscription_convert": true,
+	"app_store_subscription_renew": true, "app_update": true, "app_upgrade": true,
+	"dynamic_link_app_open": true, "dynamic_link_app_update": true, "dynamic_link_first_open": true,
+	"error": true, "first_open": true, "first_visit": true, "in_app_purchase": true,
+	"notification_dismiss": true, "notification_foreground": true, "notification_open": true,
+	"notification_receive": true, "os_update": true, "session_start": true,
+	"session_start_with_rollout": true, "user_engagement": true,
+}
+
+var reservedPrefixes = []string{"google_", "ga_", "firebase_"}
+
+func checkReservedEventNames(cfg *config.ProjectConfig) []Finding {
+	var findings []Finding
+	for _, c := range cfg.Conversions {
+		if reservedEvents[c.Name] {
+			findings = append(findings, Finding{
+				Resource: "conversion " + c.Name,
+				Message:  fmt.Sprintf("%s is reserved by GA4, which collects it automatically; a custom event of that name is dropped", c.Name),
+			})
+			continue
+		}
+		for _, prefix := range reservedPrefixes {
+			if strings.HasPrefix(c.Name, prefix) {
+				findings = append(findings, Finding{
+					Resource: "conversion " + c.Name,
+					Message:  fmt.Sprintf("%s starts with the reserved prefix %s; GA4 drops events named like this", c.Name, prefix),
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// checkUnusedDimensions flags the dimensions that no conversion sends and no
+// audience is built on once they exceed UnusedDimensionsShare of all custom
+// dimensions. Only the config's parameters lists say what is used, so the
+// rule stays quiet for a config that has none.
+func checkUnusedDimensions(cfg *config.ProjectConfig) []Finding {
+	if len(cfg.Dimensions) == 0 {
+		return nil
+	}
+	g := depgraph.Build(cfg)
+	used := map[string]bool{}
+	for _, e := range g.Edges {
+		used[e.From], used[e.To] = true, true
+	}
+	if len(g.Edges) == 0 {
+		return nil
+	}
+
+	var unused []string
+	for _, d := range cfg.Dimensions {
+		if !used[depgraph.KindDimension+":"+d.ParameterName] {
+			unused = append(unused, d.ParameterName)
+		}
+	}
+	if float64(len(unused)) <= UnusedDimensionsShare*float64(len(cfg.Dimensions)) {
+		return nil
+	}
+	return []Finding{{
+		Resource: "dimensions",
+		Message: fmt.Sprintf("%d of %d custom dimensions are sent by no conversion and used by no audience: %s",
+			len(unused), len(cfg.Dimensions), strings.Join(unused, ", ")),
+	}}
+}
+
+func checkAudienceDuration(cfg *config.ProjectConfig) []Finding {
+	var findings []Finding
+	for _, a := range cfg.Audiences {
+		if a.Duration > MaxMembershipDays {
+			findings = append(findings, Finding{
+				Resource: fmt.Sprintf("audience %q", a.Name),
+				Message:  fmt.Sprintf("membership duration of %d days exceeds GA4's maximum of %d", a.Duration, MaxMembershipDays),
+			})
+		}
+	}
+	return findings
+}
+
+// checkSitemapDomain flags sitemaps Search Console will not accept for the
+// property: outside the URL prefix of a URL-prefix property, or on a host
+// outside the domain of a domain property.
+func checkSitemapDomain(cfg *config.ProjectConfig) []Finding {
+	sc := cfg.SearchConsole
+	if sc == nil || sc.SiteURL == "" {
+		return nil
+	}
+	domain, isDomain := strings.CutPrefix(sc.SiteURL, "sc-domain:")
+	domain = strings.ToLower(domain)
+
+	var findings []Finding
+	for _, sm := range sc.ActiveSitemaps() {
+		var ok bool
+		if isDomain {
+			u, err := url.Parse(sm.URL)
+			host := ""
+			if err == nil {
+				host = strings.ToLower(u.Hostname())
+			}
+			ok = host == domain || strings.HasSuffix(host, "."+domain)
+		} else {
+			ok = strings.HasPrefix(sm.URL, sc.SiteURL)
+		}
+		if !ok {
+			findings = append(findings, Finding{
+				Resource: "sitemap " + sm.URL,
+				Message:  fmt.Sprintf("not on the property %s; Search Console rejects sitemaps outside it", sc.SiteURL),
+			})
+		}
+	}
+	return findings
+}
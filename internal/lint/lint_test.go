@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func testConfig() *config.ProjectConfig {
+	return &config.ProjectConfig{
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase", Parameters: []string{"value", "plan_type"}},
+			{Name: "signUp"},
+			{Name: "session_start"},
+			{Name: "ga_click"},
+		},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "plan_type"},
+			{ParameterName: "author"},
+			{ParameterName: "category"},
+		},
+		Audiences: []config.AudienceConfig{
+			{Name: "Buyers", Duration: 30, Events: []string{"purchase"}},
+			{Name: "Forever", Duration: 541},
+		},
+		SearchConsole: &config.SearchConsoleConfig{
+			SiteURL: "https://example.com/",
+			Sitemaps: []config.SitemapConfig{
+				{URL: "https://example.com/sitemap.xml"},
+				{URL: "https://cdn.example.net/sitemap.xml"},
+				{URL: "https://old.example.org/sitemap.xml", Remove: true},
+			},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	findings, err := Run(testConfig(), nil)
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Severity+" "+f.Rule+" "+f.Resource)
+	}
+	assert.Equal(t, []string{
+		"error reserved-event-name conversion session_start",
+		"error reserved-event-name conversion ga_click",
+		`error audience-duration audience "Forever"`,
+		"error sitemap-domain sitemap https://cdn.example.net/sitemap.xml",
+		"warning event-name-case event signUp",
+		"info unused-dimensions dimensions",
+	}, got)
+	assert.Equal(t, "2 of 3 custom dimensions are sent by no conversion and used by no audience: author, category", findings[5].Message)
+}
+
+func TestRun_Disabled(t *testing.T) {
+	findings, err := Run(testConfig(), []string{"reserved-event-name", "audience-duration", "sitemap-domain", "unused-dimensions"})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "event-name-case", findings[0].Rule)
+
+	_, err = Run(testConfig(), []string{"event-case"})
+	assert.EqualError(t, err, `unknown lint rule "event-case"`)
+}
+
+func TestUnusedDimensions_QuietWithoutParameterLists(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Conversions: []config.ConversionConfig{{Name: "purchase"}},
+		Dimensions:  []config.DimensionConfig{{ParameterName: "author"}},
+	}
+	assert.Empty(t, checkUnusedDimensions(cfg))
+
+	cfg.Conversions[0].Parameters = []string{"author"}
+	cfg.Dimensions = append(cfg.Dimensions, config.DimensionConfig{ParameterName: "category"}, config.DimensionConfig{ParameterName: "genre"}, config.DimensionConfig{ParameterName: "tag"})
+	cfg.Conversions = append(cfg.Conversions, config.ConversionConfig{Name: "share", Parameters: []string{"category", "genre"}})
+	assert.Empty(t, checkUnusedDimensions(cfg), "one unused of four is not over 25%")
+}
+
+func TestSitemapDomain_DomainProperty(t *testing.T) {
+	cfg := &config.ProjectConfig{SearchConsole: &config.SearchConsoleConfig{
+		SiteURL: "sc-domain:Example.com",
+		Sitemaps: []config.SitemapConfig{
+			{URL: "https://example.com/sitemap.xml"},
+			{URL: "https://blog.example.com/sitemap.xml"},
+			{URL: "https://notexample.com/sitemap.xml"},
+		},
+	}}
+	findings := checkSitemapDomain(cfg)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "sitemap https://notexample.com/sitemap.xml", findings[0].Resource)
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.True(t, AtLeast(SeverityError, SeverityWarning))
+	assert.True(t, AtLeast(SeverityWarning, SeverityWarning))
+	assert.False(t, AtLeast(SeverityInfo, SeverityWarning))
+}
@@ -0,0 +1,92 @@
+// Package logging builds the slog logger the CLI shares between its commands
+// and the GA4 and GSC clients, and writes the debug-level summaries of API
+// calls used to troubleshoot permission (403) and quota (429) errors.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Levels lists the accepted level names, most verbose first.
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// Formats lists the accepted format names.
+var Formats = []string{FormatText, FormatJSON}
+
+// ParseLevel converts a level name; "warning" is accepted for "warn".
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q: must be one of %s", name, strings.Join(Levels, ", "))
+}
+
+// New returns a logger writing records at level and above to w in format.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q: must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+// APICall logs one API request attempt at debug level: the operation
+// ("create conversion"), the resource, how long it took and, when it failed,
+// the HTTP status and Google's error reason (e.g. 403 insufficientPermissions,
+// 429 rateLimitExceeded). Retried attempts are logged one by one.
+func APICall(logger *slog.Logger, service, operation, resource string, elapsed time.Duration, err error) {
+	if logger == nil || !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		slog.String("service", service),
+		slog.String("operation", operation),
+		slog.String("resource", resource),
+		slog.Duration("elapsed", elapsed),
+	}
+	if err == nil {
+		logger.Debug("API call succeeded", attrs...)
+		return
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status", apiErr.Code))
+		if len(apiErr.Errors) > 0 && apiErr.Errors[0].Reason != "" {
+			attrs = append(attrs, slog.String("reason", apiErr.Errors[0].Reason))
+		}
+		if apiErr.Message != "" {
+			attrs = append(attrs, slog.String("message", apiErr.Message))
+		}
+	} else {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	logger.Debug("API call failed", attrs...)
+}
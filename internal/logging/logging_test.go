@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn,
+		"warning": slog.LevelWarn, "error": slog.LevelError,
+	} {
+		got, err := ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := ParseLevel("verbose")
+	assert.ErrorContains(t, err, "debug, info, warn, error")
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", FormatJSON)
+	require.NoError(t, err)
+	logger.Info("hidden")
+	logger.Warn("shown", "k", "v")
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "shown", rec["msg"])
+	assert.Equal(t, "v", rec["k"])
+
+	_, err = New(&buf, "info", "yaml")
+	assert.ErrorContains(t, err, "text, json")
+}
+
+func TestAPICall(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug", FormatJSON)
+	require.NoError(t, err)
+
+	apiErr := &googleapi.Error{
+		Code:    403,
+		Message: "User does not have sufficient permission",
+		Errors:  []googleapi.ErrorItem{{Reason: "insufficientPermissions"}},
+	}
+	APICall(logger, "searchconsole", "inspect url", "https://example.com/", 120*time.Millisecond, apiErr)
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "API call failed", rec["msg"])
+	assert.Equal(t, "inspect url", rec["operation"])
+	assert.Equal(t, float64(403), rec["status"])
+	assert.Equal(t, "insufficientPermissions", rec["reason"])
+
+	buf.Reset()
+	APICall(logger, "ga4admin", "list conversion", "properties/1", time.Millisecond, errors.New("boom"))
+	assert.Contains(t, buf.String(), `"error":"boom"`)
+
+	buf.Reset()
+	quiet, err := New(&buf, "info", FormatText)
+	require.NoError(t, err)
+	APICall(quiet, "ga4admin", "list conversion", "properties/1", time.Millisecond, nil)
+	assert.Empty(t, buf.String())
+}
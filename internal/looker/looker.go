@@ -0,0 +1,105 @@
+// Package looker shapes reports for Looker Studio. A report becomes a CSV
+// with stable snake_case column names and unformatted values, plus a JSON
+// schema manifest giving each column's Looker Studio type, concept and
+// default aggregation. Whether the CSV reaches Looker Studio through Google
+// Sheets, BigQuery or a file upload, the data source fields are set up once
+// from the manifest and keep working as scheduled runs replace the data.
+package looker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format is the --format value of the Looker Studio export.
+const Format = "looker"
+
+// SchemaVersion is the manifest layout version, bumped on incompatible
+// changes.
+const SchemaVersion = 1
+
+// Looker Studio field types used by the exports.
+const (
+	TypeText    = "TEXT"
+	TypeURL     = "URL"
+	TypeNumber  = "NUMBER"
+	TypePercent = "PERCENT"
+	TypeDate    = "YEAR_MONTH_DAY"
+)
+
+// Concepts: what a field is to Looker Studio.
+const (
+	ConceptDimension = "DIMENSION"
+	ConceptMetric    = "METRIC"
+)
+
+// Default aggregations of metrics.
+const (
+	AggregationSum = "SUM"
+	AggregationAvg = "AVG"
+)
+
+// Field describes one CSV column.
+type Field struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Type        string `json:"type"`
+	Concept     string `json:"concept"`
+	Aggregation string `json:"aggregation,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Manifest describes an export: where the data came from and its columns, in
+// CSV order.
+type Manifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	Source        string    `json:"source"`
+	Site          string    `json:"site,omitempty"`
+	StartDate     string    `json:"start_date,omitempty"`
+	EndDate       string    `json:"end_date,omitempty"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Data          string    `json:"data,omitempty"`
+	Fields        []Field   `json:"fields"`
+}
+
+// Columns returns the CSV header: the field names in order.
+func (m Manifest) Columns() []string {
+	columns := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		columns[i] = f.Name
+	}
+	return columns
+}
+
+// WriteManifest writes m as indented JSON to path.
+func WriteManifest(path string, m Manifest) error {
+	m.SchemaVersion = SchemaVersion
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ManifestPath is where the manifest of the export written to path goes by
+// default: report.csv gets report.schema.json next to it.
+func ManifestPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".schema.json"
+}
+
+// Date converts a YYYY-MM-DD date to YEAR_MONTH_DAY (YYYYMMDD), the form
+// Looker Studio parses without a custom format. Anything else is returned
+// unchanged.
+func Date(s string) string {
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return s
+	}
+	return strings.ReplaceAll(s, "-", "")
+}
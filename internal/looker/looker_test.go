@@ -0,0 +1,17 @@
+package looker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDate(t *testing.T) {
+	assert.Equal(t, "20261009", Date("2026-10-09"))
+	assert.Equal(t, "yesterday", Date("yesterday"), "anything else is left alone")
+}
+
+func TestManifestPath(t *testing.T) {
+	assert.Equal(t, "out/report.schema.json", ManifestPath("out/report.csv"))
+	assert.Equal(t, "report.schema.json", ManifestPath("report"))
+}
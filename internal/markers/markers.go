@@ -0,0 +1,187 @@
+// Package markers keeps dated annotations (a deployment, a site migration, an
+// algorithm update) per site, so reports that follow or compare periods can
+// show what happened in them. GA4 has no annotations of its own.
+//
+// Markers are records of kind store.KindMarker in the local history store.
+// Removing a marker appends a tombstone rather than rewriting the file, in
+// keeping with the store's append-only history.
+package markers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+// DateLayout is the format of Marker dates.
+const DateLayout = "2006-01-02"
+
+// ErrNotFound is returned by Remove when the site has no marker with the ID.
+var ErrNotFound = errors.New("markers: no such marker")
+
+// Marker is an event on a day or, when End is set, over the days from Date
+// to End inclusive. Source names where it came from when it was not added
+// by hand.
+type Marker struct {
+	ID     string `json:"id"`
+	Date   string `json:"date"`
+	End    string `json:"end,omitempty"`
+	Label  string `json:"label"`
+	Source string `json:"source,omitempty"`
+}
+
+// Last returns the last day of the marker.
+func (m Marker) Last() string {
+	if m.End != "" {
+		return m.End
+	}
+	return m.Date
+}
+
+// Overlaps reports whether any day of the marker falls in start to end,
+// inclusive. Dates compare as strings since they are all YYYY-MM-DD.
+func (m Marker) Overlaps(start, end string) bool {
+	return m.Date <= end && m.Last() >= start
+}
+
+// Span is the marker's date, or its first and last day.
+func (m Marker) Span() string {
+	if m.End == "" || m.End == m.Date {
+		return m.Date
+	}
+	return m.Date + " to " + m.End
+}
+
+// String is the span and label, as shown under reports.
+func (m Marker) String() string {
+	return m.Span() + " " + m.Label
+}
+
+// Validate checks the dates and label.
+func (m Marker) Validate() error {
+	if strings.TrimSpace(m.Label) == "" {
+		return errors.New("marker label is empty")
+	}
+	start, err := time.Parse(DateLayout, m.Date)
+	if err != nil {
+		return fmt.Errorf("invalid marker date %q: want YYYY-MM-DD", m.Date)
+	}
+	if m.End == "" {
+		return nil
+	}
+	end, err := time.Parse(DateLayout, m.End)
+	if err != nil {
+		return fmt.Errorf("invalid marker end %q: want YYYY-MM-DD", m.End)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("marker end %s is before its date %s", m.End, m.Date)
+	}
+	return nil
+}
+
+// Add saves m for site and returns it with its ID, a short hash of the
+// marker and the time it was added.
+func Add(ctx context.Context, s *store.Store, site string, m Marker) (Marker, error) {
+	if err := m.Validate(); err != nil {
+		return Marker{}, err
+	}
+	now := time.Now().UTC()
+	sum := sha256.Sum256([]byte(strings.Join([]string{m.Date, m.End, m.Label, m.Source, strconv.FormatInt(now.UnixNano(), 10)}, "\x00")))
+	m.ID = hex.EncodeToString(sum[:4])
+
+	dims := map[string]string{"id": m.ID, "label": m.Label}
+	if m.Source != "" {
+		dims["source"] = m.Source
+	}
+	err := s.Append(ctx, store.KindMarker, site, []store.Record{{
+		RecordedAt: now,
+		StartDate:  m.Date,
+		EndDate:    m.End,
+		Dimensions: dims,
+	}})
+	if err != nil {
+		return Marker{}, err
+	}
+	return m, nil
+}
+
+// Remove deletes the marker with the given ID from site.
+func Remove(ctx context.Context, s *store.Store, site, id string) error {
+	ms, err := List(ctx, s, site)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, m := range ms {
+		found = found || m.ID == id
+	}
+	if !found {
+		return fmt.Errorf("%w %q for %s", ErrNotFound, id, site)
+	}
+	return s.Append(ctx, store.KindMarker, site, []store.Record{{
+		Dimensions: map[string]string{"id": id},
+		State:      map[string]string{"removed": "true"},
+	}})
+}
+
+// List returns the site's markers, by date then label.
+func List(ctx context.Context, s *store.Store, site string) ([]Marker, error) {
+	records, err := s.Query(ctx, store.Query{Kind: store.KindMarker, Site: site})
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]Marker{}
+	for _, r := range records {
+		id := r.Dimensions["id"]
+		if r.State["removed"] == "true" {
+			delete(byID, id)
+			continue
+		}
+		byID[id] = Marker{
+			ID:     id,
+			Date:   r.StartDate,
+			End:    r.EndDate,
+			Label:  r.Dimensions["label"],
+			Source: r.Dimensions["source"],
+		}
+	}
+	ms := make([]Marker, 0, len(byID))
+	for _, m := range byID {
+		ms = append(ms, m)
+	}
+	Sort(ms)
+	return ms, nil
+}
+
+// Sort orders markers by date, then end, then label.
+func Sort(ms []Marker) {
+	sort.Slice(ms, func(i, j int) bool {
+		a, b := ms[i], ms[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Last() != b.Last() {
+			return a.Last() < b.Last()
+		}
+		return a.Label < b.Label
+	})
+}
+
+// Within returns the markers overlapping start to end, inclusive.
+func Within(ms []Marker, start, end string) []Marker {
+	var out []Marker
+	for _, m := range ms {
+		if m.Overlaps(start, end) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
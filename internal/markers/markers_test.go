@@ -0,0 +1,75 @@
+package markers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/store"
+)
+
+const site = "sc-domain:example.com"
+
+func TestAddListRemove(t *testing.T) {
+	s := store.New(t.TempDir())
+	ctx := context.Background()
+
+	migration, err := Add(ctx, s, site, Marker{Date: "2025-02-07", Label: "site migration"})
+	require.NoError(t, err)
+	assert.Len(t, migration.ID, 8)
+	update, err := Add(ctx, s, site, Marker{Date: "2025-01-20", End: "2025-02-03", Label: "core update", Source: "google"})
+	require.NoError(t, err)
+	_, err = Add(ctx, s, "sc-domain:other.com", Marker{Date: "2025-02-01", Label: "elsewhere"})
+	require.NoError(t, err)
+
+	got, err := List(ctx, s, site)
+	require.NoError(t, err)
+	assert.Equal(t, []Marker{update, migration}, got)
+
+	require.NoError(t, Remove(ctx, s, site, update.ID))
+	got, err = List(ctx, s, site)
+	require.NoError(t, err)
+	assert.Equal(t, []Marker{migration}, got)
+
+	err = Remove(ctx, s, site, update.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		m       Marker
+		wantErr string
+	}{
+		"day":        {m: Marker{Date: "2025-02-07", Label: "deploy"}},
+		"range":      {m: Marker{Date: "2025-02-07", End: "2025-02-09", Label: "deploy"}},
+		"no label":   {m: Marker{Date: "2025-02-07", Label: " "}, wantErr: "label is empty"},
+		"bad date":   {m: Marker{Date: "07/02/2025", Label: "deploy"}, wantErr: `invalid marker date "07/02/2025"`},
+		"bad end":    {m: Marker{Date: "2025-02-07", End: "soon", Label: "deploy"}, wantErr: `invalid marker end "soon"`},
+		"end before": {m: Marker{Date: "2025-02-07", End: "2025-02-01", Label: "deploy"}, wantErr: "is before its date"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.m.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	ms := []Marker{
+		{ID: "a", Date: "2025-01-20", End: "2025-02-03", Label: "core update"},
+		{ID: "b", Date: "2025-02-07", Label: "site migration"},
+		{ID: "c", Date: "2025-03-01", Label: "redesign"},
+	}
+	assert.Equal(t, ms[:2], Within(ms, "2025-02-01", "2025-02-07"))
+	assert.Equal(t, ms[1:2], Within(ms, "2025-02-04", "2025-02-28"))
+	assert.Empty(t, Within(ms, "2025-02-08", "2025-02-28"))
+
+	assert.Equal(t, "2025-01-20 to 2025-02-03 core update", ms[0].String())
+	assert.Equal(t, "2025-02-07 site migration", ms[1].String())
+}
@@ -0,0 +1,197 @@
+// Package metrics keeps process-wide counters for Google API usage and serves
+// them in the Prometheus text exposition format, so Operators running
+// `ga4 serve` can alarm on quota exhaustion before batch jobs fail.
+//
+// It deliberately implements the small subset of the format it needs (counters,
+// gauges, and sum/count summaries) instead of pulling in the Prometheus client
+// library.
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/timeout"
+)
+
+// Google service label values.
+const (
+	ServiceGA4Admin      = "analyticsadmin"
+	ServiceGA4Data       = "analyticsdata"
+	ServiceSearchConsole = "searchconsole"
+)
+
+// Default is the registry the GA4 and GSC clients record into.
+var Default = NewRegistry()
+
+type apiCallKey struct {
+	service   string
+	operation string
+}
+
+type apiErrorKey struct {
+	service string
+	code    string
+}
+
+type summary struct {
+	count uint64
+	sum   float64
+}
+
+type gauge struct {
+	help string
+	fn   func() float64
+}
+
+// Registry holds every metric. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.Mutex
+	apiCalls  map[apiCallKey]uint64
+	apiErrors map[apiErrorKey]uint64
+	requests  map[string]*summary
+	gauges    map[string]gauge
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		apiCalls:  make(map[apiCallKey]uint64),
+		apiErrors: make(map[apiErrorKey]uint64),
+		requests:  make(map[string]*summary),
+		gauges:    make(map[string]gauge),
+	}
+}
+
+// ObserveAPICall counts one Google API request and, if it failed, one error
+// labelled with its HTTP status code (or "timeout"/"unknown").
+func (r *Registry) ObserveAPICall(service, operation string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiCalls[apiCallKey{service, operation}]++
+	if err != nil {
+		r.apiErrors[apiErrorKey{service, ErrorCode(err)}]++
+	}
+}
+
+// ObserveRequest records how long a served report/endpoint took.
+func (r *Registry) ObserveRequest(route string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.requests[route]
+	if !ok {
+		s = &summary{}
+		r.requests[route] = s
+	}
+	s.count++
+	s.sum += d.Seconds()
+}
+
+// RegisterGauge exposes a value sampled at scrape time, e.g. quota used.
+// name must be a valid Prometheus metric name.
+func (r *Registry) RegisterGauge(name, help string, fn func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gauges[name] = gauge{help: help, fn: fn}
+}
+
+// ErrorCode classifies an API error for the error-rate label.
+func ErrorCode(err error) string {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.Code)
+	}
+	var te *timeout.Error
+	if errors.As(err, &te) {
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// WriteText writes every metric in Prometheus text format, sorted so output
+// is stable between scrapes.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	calls := make([]string, 0, len(r.apiCalls))
+	for k, v := range r.apiCalls {
+		calls = append(calls, fmt.Sprintf("ga4manager_api_calls_total{service=%s,operation=%s} %d", quote(k.service), quote(k.operation), v))
+	}
+	errs := make([]string, 0, len(r.apiErrors))
+	for k, v := range r.apiErrors {
+		errs = append(errs, fmt.Sprintf("ga4manager_api_errors_total{service=%s,code=%s} %d", quote(k.service), quote(k.code), v))
+	}
+	reqs := make([]string, 0, 2*len(r.requests))
+	for route, s := range r.requests {
+		reqs = append(reqs,
+			fmt.Sprintf("ga4manager_request_duration_seconds_sum{route=%s} %s", quote(route), formatFloat(s.sum)),
+			fmt.Sprintf("ga4manager_request_duration_seconds_count{route=%s} %d", quote(route), s.count))
+	}
+	gaugeNames := make([]string, 0, len(r.gauges))
+	gauges := make(map[string]gauge, len(r.gauges))
+	for name, g := range r.gauges {
+		gaugeNames = append(gaugeNames, name)
+		gauges[name] = g
+	}
+	r.mu.Unlock()
+
+	sort.Strings(calls)
+	sort.Strings(errs)
+	sort.Strings(reqs)
+	sort.Strings(gaugeNames)
+
+	var b strings.Builder
+	b.WriteString("# HELP ga4manager_api_calls_total Google API requests issued, by service and operation.\n")
+	b.WriteString("# TYPE ga4manager_api_calls_total counter\n")
+	writeLines(&b, calls)
+	b.WriteString("# HELP ga4manager_api_errors_total Failed Google API requests, by service and HTTP status code.\n")
+	b.WriteString("# TYPE ga4manager_api_errors_total counter\n")
+	writeLines(&b, errs)
+	b.WriteString("# HELP ga4manager_request_duration_seconds Time spent serving each API route.\n")
+	b.WriteString("# TYPE ga4manager_request_duration_seconds summary\n")
+	writeLines(&b, reqs)
+	// Gauge callbacks run outside the lock: they may take their own locks
+	// (e.g. the GSC quota tracker).
+	for _, name := range gaugeNames {
+		g := gauges[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, g.help, name, name, formatFloat(g.fn()))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler serves the registry at a scrape endpoint.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+func writeLines(b *strings.Builder, lines []string) {
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+}
+
+// quote renders a label value with the escaping the text format requires.
+func quote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+
+	"github.com/garbarok/ga4-manager/internal/timeout"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "429", ErrorCode(fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusTooManyRequests})))
+	assert.Equal(t, "timeout", ErrorCode(&timeout.Error{Operation: "list conversions"}))
+	assert.Equal(t, "unknown", ErrorCode(errors.New("boom")))
+}
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveAPICall(ServiceGA4Admin, "list conversions", nil)
+	r.ObserveAPICall(ServiceGA4Admin, "list conversions", nil)
+	r.ObserveAPICall(ServiceSearchConsole, "inspect url", &googleapi.Error{Code: 403})
+	r.ObserveRequest("GET /gsc/quota", 1500*time.Millisecond)
+	r.RegisterGauge("ga4manager_gsc_quota_used", "Quota used.", func() float64 { return 42 })
+
+	var b strings.Builder
+	require.NoError(t, r.WriteText(&b))
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE ga4manager_api_calls_total counter\n")
+	assert.Contains(t, out, `ga4manager_api_calls_total{service="analyticsadmin",operation="list conversions"} 2`)
+	assert.Contains(t, out, `ga4manager_api_calls_total{service="searchconsole",operation="inspect url"} 1`)
+	assert.Contains(t, out, `ga4manager_api_errors_total{service="searchconsole",code="403"} 1`)
+	assert.Contains(t, out, `ga4manager_request_duration_seconds_sum{route="GET /gsc/quota"} 1.5`)
+	assert.Contains(t, out, `ga4manager_request_duration_seconds_count{route="GET /gsc/quota"} 1`)
+	assert.Contains(t, out, "# TYPE ga4manager_gsc_quota_used gauge\nga4manager_gsc_quota_used 42\n")
+}
+
+func TestQuoteEscapesLabelValues(t *testing.T) {
+	assert.Equal(t, `"a\"b\\c\nd"`, quote("a\"b\\c\nd"))
+}
@@ -0,0 +1,284 @@
+// Package mp sends events to a GA4 web data stream through the Measurement
+// Protocol, so a property's setup can be checked end to end: the event
+// arrives, counts as a conversion and fills its custom dimensions.
+//
+// See https://developers.google.com/analytics/devguides/collection/protocol/ga4.
+package mp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEndpoint is the Measurement Protocol collection endpoint; the
+// validation server is at the same path under /debug.
+const DefaultEndpoint = "https://www.google-analytics.com/mp/collect"
+
+// MaxEvents is the Measurement Protocol's limit of events per request.
+const MaxEvents = 25
+
+// eventName is the Measurement Protocol's rule for event and parameter
+// names: a letter, then letters, digits and underscores, up to 40.
+var eventName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,39}$`)
+
+// reservedPrefixes may not start an event or parameter name.
+var reservedPrefixes = []string{"google_", "ga_", "firebase_"}
+
+// Event is one event and its parameters.
+type Event struct {
+	Name   string         `json:"name" yaml:"name"`
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Payload is a request body: events of one client, with optional user
+// properties.
+type Payload struct {
+	ClientID       string         `json:"client_id" yaml:"client_id"`
+	UserID         string         `json:"user_id,omitempty" yaml:"user_id,omitempty"`
+	UserProperties map[string]any `json:"-" yaml:"user_properties,omitempty"`
+	Events         []Event        `json:"events" yaml:"events"`
+}
+
+// Batch is the YAML file `ga4 mp send --file` reads: a payload, and the
+// measurement ID to send it to unless given on the command line.
+type Batch struct {
+	MeasurementID string `yaml:"measurement_id,omitempty"`
+	Payload       `yaml:",inline"`
+}
+
+// LoadBatch reads a batch file.
+func LoadBatch(path string) (Batch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Batch{}, err
+	}
+	var b Batch
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return Batch{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(b.Events) == 0 {
+		return Batch{}, fmt.Errorf("%s has no events", path)
+	}
+	return b, nil
+}
+
+// MarshalJSON wraps user properties in {"value": ...} as the protocol
+// expects.
+func (p Payload) MarshalJSON() ([]byte, error) {
+	type plain Payload
+	out := struct {
+		plain
+		UserProperties map[string]map[string]any `json:"user_properties,omitempty"`
+	}{plain: plain(p)}
+	if len(p.UserProperties) > 0 {
+		out.UserProperties = make(map[string]map[string]any, len(p.UserProperties))
+		for k, v := range p.UserProperties {
+			out.UserProperties[k] = map[string]any{"value": v}
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Validate checks what the collection endpoint would silently drop: names,
+// a missing client ID, an empty payload.
+func (p Payload) Validate() error {
+	if p.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+	if len(p.Events) == 0 {
+		return errors.New("no events to send")
+	}
+	for _, e := range p.Events {
+		if err := validName("event", e.Name); err != nil {
+			return err
+		}
+		for k := range e.Params {
+			if err := validName("parameter", k); err != nil {
+				return fmt.Errorf("event %s: %w", e.Name, err)
+			}
+		}
+	}
+	for k := range p.UserProperties {
+		if err := validName("user property", k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validName(kind, name string) error {
+	if !eventName.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q: start with a letter, then letters, digits or underscores, at most 40", kind, name)
+	}
+	for _, prefix := range reservedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("invalid %s name %q: the %s prefix is reserved", kind, name, prefix)
+		}
+	}
+	return nil
+}
+
+// ParseParams parses key=value pairs. Values that read as numbers or
+// booleans are sent as such, so value=9.99 is numeric as GA4 expects.
+func ParseParams(pairs []string) (map[string]any, error) {
+	params := make(map[string]any, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid parameter %q: want name=value", p)
+		}
+		params[k] = parseValue(v)
+	}
+	return params, nil
+}
+
+func parseValue(v string) any {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(v); err == nil && (v == "true" || v == "false") {
+		return b
+	}
+	return v
+}
+
+// ValidationMessage is a problem the validation server found.
+type ValidationMessage struct {
+	FieldPath      string `json:"fieldPath"`
+	Description    string `json:"description"`
+	ValidationCode string `json:"validationCode"`
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithEndpoint sends to another collection URL, such as the EU endpoint
+// https://region1.google-analytics.com/mp/collect.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) { c.endpoint = endpoint }
+}
+
+// WithHTTPClient sends through client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) { c.http = client }
+}
+
+// Client sends to one web data stream.
+type Client struct {
+	measurementID string
+	secret        string
+	endpoint      string
+	http          *http.Client
+}
+
+// New returns a Client for the stream with measurementID (G-XXXXXXX),
+// authenticated by a Measurement Protocol API secret created on the stream.
+func New(measurementID, secret string, opts ...Option) *Client {
+	c := &Client{measurementID: measurementID, secret: secret, endpoint: DefaultEndpoint, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Send posts p, in requests of at most MaxEvents events. The collection
+// endpoint accepts anything well-formed without saying whether it was
+// recorded: use Validate first to catch mistakes.
+func (c *Client) Send(ctx context.Context, p Payload) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	for start := 0; start < len(p.Events); start += MaxEvents {
+		chunk := p
+		chunk.Events = p.Events[start:min(start+MaxEvents, len(p.Events))]
+		if _, err := c.post(ctx, c.endpoint, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate sends p to the validation server, which records nothing, and
+// returns the problems it reports.
+func (c *Client) Validate(ctx context.Context, p Payload) ([]ValidationMessage, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/debug" + u.Path
+
+	var messages []ValidationMessage
+	for start := 0; start < len(p.Events); start += MaxEvents {
+		chunk := p
+		chunk.Events = p.Events[start:min(start+MaxEvents, len(p.Events))]
+		body, err := c.post(ctx, u.String(), chunk)
+		if err != nil {
+			return nil, err
+		}
+		var resp struct {
+			ValidationMessages []ValidationMessage `json:"validationMessages"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("invalid validation server response: %w", err)
+		}
+		messages = append(messages, resp.ValidationMessages...)
+	}
+	return messages, nil
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, p Payload) ([]byte, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode events: %w", err)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("measurement_id", c.measurementID)
+	q.Set("api_secret", c.secret)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// The URL carries the API secret: report the host only.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("failed to send to %s: %w", u.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("HTTP %d from %s: %s", resp.StatusCode, u.Host, bytes.TrimSpace(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,142 @@
+package mp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParams(t *testing.T) {
+	params, err := ParseParams([]string{"value=9.99", "quantity=2", "currency=EUR", "debug_mode=true", "coupon="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"value": 9.99, "quantity": int64(2), "currency": "EUR", "debug_mode": true, "coupon": ""}, params)
+
+	_, err = ParseParams([]string{"value"})
+	assert.ErrorContains(t, err, `invalid parameter "value"`)
+}
+
+func TestPayload_Validate(t *testing.T) {
+	ok := Payload{ClientID: "1.2", Events: []Event{{Name: "purchase", Params: map[string]any{"value": 1}}}}
+	require.NoError(t, ok.Validate())
+
+	for name, p := range map[string]Payload{
+		"no client":     {Events: ok.Events},
+		"no events":     {ClientID: "1.2"},
+		"bad name":      {ClientID: "1.2", Events: []Event{{Name: "sign-up"}}},
+		"reserved":      {ClientID: "1.2", Events: []Event{{Name: "ga_purchase"}}},
+		"bad param":     {ClientID: "1.2", Events: []Event{{Name: "purchase", Params: map[string]any{"1value": 1}}}},
+		"bad user prop": {ClientID: "1.2", Events: ok.Events, UserProperties: map[string]any{"firebase_x": 1}},
+	} {
+		assert.Error(t, p.Validate(), name)
+	}
+}
+
+func TestPayload_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Payload{
+		ClientID:       "1.2",
+		UserID:         "u1",
+		UserProperties: map[string]any{"plan": "pro"},
+		Events:         []Event{{Name: "purchase", Params: map[string]any{"value": 9.99}}},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"client_id":"1.2","user_id":"u1","user_properties":{"plan":{"value":"pro"}},"events":[{"name":"purchase","params":{"value":9.99}}]}`, string(data))
+}
+
+func TestClient_SendBatches(t *testing.T) {
+	var requests []Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/mp/collect", r.URL.Path)
+		assert.Equal(t, "G-TEST", r.URL.Query().Get("measurement_id"))
+		assert.Equal(t, "s3cret", r.URL.Query().Get("api_secret"))
+		var p struct {
+			ClientID string  `json:"client_id"`
+			Events   []Event `json:"events"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		requests = append(requests, Payload{ClientID: p.ClientID, Events: p.Events})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	events := make([]Event, MaxEvents+3)
+	for i := range events {
+		events[i] = Event{Name: fmt.Sprintf("event_%d", i)}
+	}
+	c := New("G-TEST", "s3cret", WithEndpoint(srv.URL+"/mp/collect"))
+	require.NoError(t, c.Send(context.Background(), Payload{ClientID: "1.2", Events: events}))
+
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[0].Events, MaxEvents)
+	assert.Len(t, requests[1].Events, 3)
+	assert.Equal(t, "1.2", requests[1].ClientID)
+}
+
+func TestClient_SendErrorHidesSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := New("G-TEST", "s3cret", WithEndpoint(srv.URL+"/mp/collect"))
+	err := c.Send(context.Background(), Payload{ClientID: "1.2", Events: []Event{{Name: "purchase"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 400")
+	assert.NotContains(t, err.Error(), "s3cret")
+
+	srv.Close()
+	err = c.Send(context.Background(), Payload{ClientID: "1.2", Events: []Event{{Name: "purchase"}}})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cret")
+}
+
+func TestClient_Validate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/debug/mp/collect", r.URL.Path)
+		_, _ = io.WriteString(w, `{"validationMessages":[{"fieldPath":"events","description":"Event param [value] has unexpected type","validationCode":"VALUE_INVALID"}]}`)
+	}))
+	defer srv.Close()
+
+	c := New("G-TEST", "s3cret", WithEndpoint(srv.URL+"/mp/collect"))
+	messages, err := c.Validate(context.Background(), Payload{ClientID: "1.2", Events: []Event{{Name: "purchase"}}})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "VALUE_INVALID", messages[0].ValidationCode)
+}
+
+func TestLoadBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`measurement_id: G-TEST
+client_id: "555.1"
+user_properties:
+  plan: pro
+events:
+  - name: sign_up
+    params:
+      method: email
+  - name: purchase
+    params:
+      value: 9.99
+      currency: EUR
+`), 0o644))
+
+	b, err := LoadBatch(path)
+	require.NoError(t, err)
+	assert.Equal(t, "G-TEST", b.MeasurementID)
+	assert.Equal(t, "555.1", b.ClientID)
+	assert.Equal(t, map[string]any{"plan": "pro"}, b.UserProperties)
+	require.Len(t, b.Events, 2)
+	assert.Equal(t, 9.99, b.Events[1].Params["value"])
+
+	require.NoError(t, os.WriteFile(path, []byte("client_id: x\n"), 0o644))
+	_, err = LoadBatch(path)
+	assert.ErrorContains(t, err, "has no events")
+}
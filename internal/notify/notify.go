@@ -0,0 +1,143 @@
+// Package notify delivers command results to the sinks a config lists under
+// notify: webhooks, which receive a JSON document with a Slack-compatible
+// "text" field, and local JSONL files.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// DefaultTimeout bounds one webhook delivery.
+const DefaultTimeout = 15 * time.Second
+
+// Message is what a sink receives. Text is the human-readable summary chat
+// webhooks display; Payload carries the structured result.
+type Message struct {
+	Title   string    `json:"title"`
+	Text    string    `json:"text"`
+	SentAt  time.Time `json:"sent_at"`
+	Payload any       `json:"payload,omitempty"`
+}
+
+// Sink is a destination for messages.
+type Sink interface {
+	Send(ctx context.Context, m Message) error
+	// String describes the sink for output, without secrets.
+	String() string
+}
+
+// FromConfig returns the sinks cfg lists, in order.
+func FromConfig(cfg []config.NotifyConfig) []Sink {
+	sinks := make([]Sink, 0, len(cfg))
+	for _, c := range cfg {
+		if c.Webhook != "" {
+			sinks = append(sinks, Webhook(c.Webhook, nil))
+		} else {
+			sinks = append(sinks, File(c.File))
+		}
+	}
+	return sinks
+}
+
+// SendAll sends m to every sink, stamping SentAt if unset. Delivery goes on
+// after a sink fails; the failures are joined.
+func SendAll(ctx context.Context, sinks []Sink, m Message) error {
+	if m.SentAt.IsZero() {
+		m.SentAt = time.Now().UTC()
+	}
+	var errs []error
+	for _, s := range sinks {
+		if err := s.Send(ctx, m); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// Webhook returns a sink POSTing each message as JSON to rawURL. A nil client
+// uses one with DefaultTimeout.
+func Webhook(rawURL string, client *http.Client) Sink {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &webhookSink{url: rawURL, client: client}
+}
+
+// String shows only the host: webhook paths are often the secret.
+func (w *webhookSink) String() string {
+	u, err := url.Parse(w.url)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return "webhook " + u.Scheme + "://" + u.Host
+}
+
+func (w *webhookSink) Send(ctx context.Context, m Message) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+type fileSink struct {
+	path string
+}
+
+// File returns a sink appending each message as one JSON line to path,
+// creating it and its directory.
+func File(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (f *fileSink) String() string { return "file " + f.path }
+
+func (f *fileSink) Send(_ context.Context, m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestSendAll_WebhookAndFile(t *testing.T) {
+	var got Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "out", "insights.jsonl")
+	sinks := FromConfig([]config.NotifyConfig{{Webhook: srv.URL + "/hooks/secret"}, {File: path}})
+	require.Len(t, sinks, 2)
+	assert.NotContains(t, sinks[0].String(), "secret")
+
+	m := Message{Title: "2 insights", Text: "tools-position: pages /tools/*", Payload: map[string]int{"findings": 2}}
+	require.NoError(t, SendAll(context.Background(), sinks, m))
+	require.NoError(t, SendAll(context.Background(), sinks[1:], m))
+
+	assert.Equal(t, "2 insights", got.Title)
+	assert.False(t, got.SentAt.IsZero())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+}
+
+func TestSendAll_ReportsEveryFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "insights.jsonl")
+
+	err := SendAll(context.Background(), []Sink{Webhook(srv.URL, nil), File(path)}, Message{Title: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 403: invalid_token")
+	_, statErr := os.Stat(path)
+	assert.NoError(t, statErr, "the file sink still received the message")
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// FormatJSON selects a command's own JSON document, written with JSON.
+const FormatJSON = "json"
+
+// FormatHTML selects a command's own standalone HTML page, for reports meant
+// to be shared rather than read in a terminal.
+const FormatHTML = "html"
+
+// JSON writes v as indented JSON, the encoding of every --format json.
+func JSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Validate checks that format is one of formats.
+func Validate(format string, formats ...string) error {
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("%w: %q (want %s)", ErrUnknownFormat, format, list(formats))
+	}
+	return nil
+}
+
+// FormatVar registers --format on fs, accepting only formats; the first is
+// the default. A value outside formats fails flag parsing with the list of
+// valid ones, so commands need no format check of their own. shorthand may
+// be "".
+func FormatVar(fs *pflag.FlagSet, p *string, shorthand string, formats ...string) {
+	*p = formats[0]
+	fs.VarP(&formatValue{p: p, formats: formats}, "format", shorthand, "Output format: "+list(formats))
+}
+
+// formatValue is a string flag limited to a set of formats.
+type formatValue struct {
+	p       *string
+	formats []string
+}
+
+func (f *formatValue) String() string { return *f.p }
+
+func (f *formatValue) Set(s string) error {
+	if err := Validate(s, f.formats...); err != nil {
+		return fmt.Errorf("want %s", list(f.formats))
+	}
+	*f.p = s
+	return nil
+}
+
+func (f *formatValue) Type() string { return "string" }
+
+// list joins formats for messages: "table or json", "table, json, or csv".
+func list(formats []string) string {
+	switch len(formats) {
+	case 1:
+		return formats[0]
+	case 2:
+		return formats[0] + " or " + formats[1]
+	}
+	return strings.Join(formats[:len(formats)-1], ", ") + ", or " + formats[len(formats)-1]
+}
+
+// Formats returns the formats the --format flag f accepts, or nil when f was
+// not registered by FormatVar. Shell completion offers them.
+func Formats(f *pflag.Flag) []string {
+	if v, ok := f.Value.(*formatValue); ok {
+		return slices.Clone(v.formats)
+	}
+	return nil
+}
+
+// UseFormat switches the --format flag that FormatVar registered on fs to
+// format, unless the command does not accept format or --format was given on
+// the command line. It reports whether --format is now format.
+func UseFormat(fs *pflag.FlagSet, format string) bool {
+	f := fs.Lookup("format")
+	if f == nil {
+		return false
+	}
+	v, ok := f.Value.(*formatValue)
+	if !ok {
+		return false
+	}
+	if !f.Changed && slices.Contains(v.formats, format) {
+		*v.p = format
+	}
+	return *v.p == format
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestFormatVarDefaultsToFirstFormat(t *testing.T) {
+	var format string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	FormatVar(fs, &format, "f", FormatTable, FormatJSON, FormatCSV)
+
+	if format != FormatTable {
+		t.Errorf("default = %q, want %q", format, FormatTable)
+	}
+	if usage := fs.Lookup("format").Usage; usage != "Output format: table, json, or csv" {
+		t.Errorf("usage = %q", usage)
+	}
+	if err := fs.Parse([]string{"-f", "csv"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if format != FormatCSV {
+		t.Errorf("format = %q, want csv", format)
+	}
+}
+
+func TestFormatVarRejectsUnlistedFormat(t *testing.T) {
+	var format string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	FormatVar(fs, &format, "", FormatTable, FormatJSON)
+
+	err := fs.Parse([]string{"--format", "yaml"})
+	if err == nil || !strings.Contains(err.Error(), "want table or json") {
+		t.Errorf("Parse error = %v, want one listing table or json", err)
+	}
+	if format != FormatTable {
+		t.Errorf("format = %q after a rejected value, want table", format)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("json", FormatTable, FormatJSON); err != nil {
+		t.Errorf("Validate(json) = %v", err)
+	}
+	err := Validate("xml", FormatTable, FormatJSON)
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Validate(xml) = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestJSONIndentsTwoSpaces(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSON(&buf, map[string]int{"rows": 2}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if got, want := buf.String(), "{\n  \"rows\": 2\n}\n"; got != want {
+		t.Errorf("JSON = %q, want %q", got, want)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	var format, other string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	FormatVar(fs, &format, "", FormatTable, FormatJSON, FormatCSV)
+	fs.StringVar(&other, "log-format", "text", "")
+
+	if got := Formats(fs.Lookup("format")); strings.Join(got, ",") != "table,json,csv" {
+		t.Errorf("Formats(--format) = %v", got)
+	}
+	if got := Formats(fs.Lookup("log-format")); got != nil {
+		t.Errorf("Formats(--log-format) = %v, want nil", got)
+	}
+}
+
+func TestUseFormat(t *testing.T) {
+	var format string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	FormatVar(fs, &format, "", FormatTable, FormatJSON)
+	if !UseFormat(fs, FormatJSON) || format != FormatJSON {
+		t.Errorf("UseFormat(json) left format %q", format)
+	}
+
+	var tableOnly string
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	FormatVar(fs, &tableOnly, "", FormatTable, FormatCSV)
+	if UseFormat(fs, FormatJSON) || tableOnly != FormatTable {
+		t.Errorf("UseFormat(json) on a table/csv flag = %q", tableOnly)
+	}
+
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	FormatVar(fs, &format, "", FormatTable, FormatJSON)
+	if err := fs.Parse([]string{"--format", "table"}); err != nil {
+		t.Fatal(err)
+	}
+	if UseFormat(fs, FormatJSON) || format != FormatTable {
+		t.Errorf("UseFormat overrode an explicit --format table: %q", format)
+	}
+}
@@ -0,0 +1,262 @@
+// Package output is the shared report output layer for ga4-manager CLI
+// commands: format names and the --format flag, tabular renderers, streaming
+// exports and the JSON envelope encoder.
+//
+// Tabular data goes through a Renderer, one per format: table (aligned plain
+// text), csv (RFC 4180), or markdown (pipe-table). The generic Render accepts
+// a slice of typed rows plus a projection function that flattens each row
+// into string cells. JSON documents are not tabulated: each command owns its
+// envelope shape, because downstream consumers expect command-specific fields
+// (aggregates, metadata, quota footers) that no general renderer should
+// impose. JSON only fixes how they are encoded.
+//
+// Exports too large to buffer go through ReportWriter instead, which streams
+// csv or ndjson rows as the caller produces them. ndjson carries rows only, so
+// it does not compete with the command-specific JSON envelopes.
+//
+// Titles and summary footers stay in the caller. Cells may carry colour
+// codes; the table renderer aligns on their visible width and drops them
+// under NO_COLOR, and the file formats always drop them, so csv and markdown
+// are safe for redirection, pipelines, and CI logs.
+package output
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// Canonical format names. No aliases — exactly one string per format.
+const (
+	FormatTable    = "table"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// ErrUnknownFormat is returned when a format string is not one the caller
+// accepts: for Render, FormatTable, FormatCSV, or FormatMarkdown.
+var ErrUnknownFormat = errors.New("output: unknown format")
+
+// Renderer writes a header and rows of cells in one format.
+type Renderer interface {
+	Render(w io.Writer, columns []string, rows [][]string) error
+}
+
+// Options adjust the table format. Truncation only applies there: csv and
+// markdown are read by tools and people who need the full values.
+type Options struct {
+	// MaxWidth truncates cells wider than this many characters, ending
+	// them with "...". 0 keeps cells whole.
+	MaxWidth int
+
+	// Widths overrides MaxWidth per column, keyed by column header.
+	Widths map[string]int
+}
+
+// width returns the truncation width of column.
+func (o Options) width(column string) int {
+	if w, ok := o.Widths[column]; ok {
+		return w
+	}
+	return o.MaxWidth
+}
+
+// NewRenderer returns the Renderer for format.
+func NewRenderer(format string, opts Options) (Renderer, error) {
+	switch format {
+	case FormatTable:
+		return tableRenderer{opts: opts}, nil
+	case FormatCSV:
+		return csvRenderer{}, nil
+	case FormatMarkdown:
+		return markdownRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (want %s)", ErrUnknownFormat, format, list([]string{FormatTable, FormatCSV, FormatMarkdown}))
+	}
+}
+
+// Render writes the rows to w in the requested format.
+//
+// columns is the header row; rowFn projects each row to its cells. The
+// projection must return a slice of the same length as columns — Render
+// returns an error if the projection length differs, rather than emitting a
+// ragged table.
+//
+// An empty rows slice still emits the header (and an empty CSV/markdown
+// structure) so downstream parsers see a well-formed document. Callers that
+// want "silent on empty" should check len(rows) before calling Render.
+func Render[T any](
+	w io.Writer,
+	format string,
+	columns []string,
+	rows []T,
+	rowFn func(T) []string,
+) error {
+	return RenderWith(w, format, Options{}, columns, rows, rowFn)
+}
+
+// RenderWith is Render with table options, such as column truncation.
+func RenderWith[T any](
+	w io.Writer,
+	format string,
+	opts Options,
+	columns []string,
+	rows []T,
+	rowFn func(T) []string,
+) error {
+	r, err := NewRenderer(format, opts)
+	if err != nil {
+		return err
+	}
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = rowFn(row)
+		if err := assertArity(len(cells[i]), len(columns)); err != nil {
+			return err
+		}
+	}
+	return r.Render(w, columns, cells)
+}
+
+// tableRenderer aligns columns two spaces apart, measuring cells by their
+// visible width so coloured cells line up with plain ones.
+type tableRenderer struct {
+	opts Options
+}
+
+func (t tableRenderer) Render(w io.Writer, columns []string, rows [][]string) error {
+	plain := NoColor()
+	all := make([][]string, 0, len(rows)+1)
+	for _, row := range append([][]string{columns}, rows...) {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			if plain {
+				c = StripANSI(c)
+			}
+			if i < len(columns) {
+				c = truncate(c, t.opts.width(columns[i]))
+			}
+			cells[i] = c
+		}
+		all = append(all, cells)
+	}
+
+	widths := make([]int, len(columns))
+	for _, cells := range all {
+		for i, c := range cells {
+			widths[i] = max(widths[i], visibleWidth(c))
+		}
+	}
+
+	var b strings.Builder
+	for _, cells := range all {
+		b.Reset()
+		for i, c := range cells {
+			b.WriteString(c)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(c)+2))
+			}
+		}
+		b.WriteByte('\n')
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type csvRenderer struct{}
+
+func (csvRenderer) Render(w io.Writer, columns []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, cells := range rows {
+		if err := cw.Write(stripAll(cells)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(w io.Writer, columns []string, rows [][]string) error {
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(columns, " | ")); err != nil {
+		return err
+	}
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(sep, " | ")); err != nil {
+		return err
+	}
+	for _, cells := range rows {
+		escaped := make([]string, len(cells))
+		for i, c := range stripAll(cells) {
+			escaped[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NoColor reports whether output must be free of colour codes: NO_COLOR is
+// set (https://no-color.org), or colour is off for the process because
+// stdout is not a terminal or the plain-ascii theme is active.
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != "" || color.NoColor
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// StripANSI removes colour codes from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+func stripAll(cells []string) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		out[i] = StripANSI(c)
+	}
+	return out
+}
+
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(StripANSI(s))
+}
+
+// truncate shortens s to n characters, the last three being "...". Cells
+// with colour codes are left whole rather than cut inside a code.
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n || strings.Contains(s, "\x1b") {
+		return s
+	}
+	if n <= 3 {
+		return string([]rune(s)[:n])
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
+
+func assertArity(got, want int) error {
+	if got != want {
+		return fmt.Errorf("output: projection returned %d cells, want %d", got, want)
+	}
+	return nil
+}
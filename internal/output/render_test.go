@@ -1,4 +1,4 @@
-package render
+package output
 
 import (
 	"bytes"
@@ -27,7 +27,7 @@ func sampleRows() []row {
 
 var sampleColumns = []string{"name", "score"}
 
-func TestRenderTableEmitsTabwriterAligned(t *testing.T) {
+func TestRenderTableAlignsColumns(t *testing.T) {
 	var buf bytes.Buffer
 	if err := Render(&buf, FormatTable, sampleColumns, sampleRows(), projectRow); err != nil {
 		t.Fatalf("Render: %v", err)
@@ -132,3 +132,88 @@ func TestRenderArityMismatchReturnsError(t *testing.T) {
 		t.Errorf("error should mention projection arity: %v", err)
 	}
 }
+
+func TestRenderTableAlignsOnVisibleWidth(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	rows := [][]string{{"\x1b[32mok\x1b[0m", "1"}, {"broken", "2"}}
+	var buf bytes.Buffer
+	if err := (tableRenderer{}).Render(&buf, []string{"status", "n"}, rows); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	col := strings.Index(lines[0], "n")
+	for _, line := range lines[1:] {
+		if got := strings.LastIndex(StripANSI(line), " ") + 1; got != col {
+			t.Errorf("second column at %d, want %d\n%s", got, col, buf.String())
+		}
+	}
+}
+
+func TestRenderTableNoColorStripsCodes(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	if err := (tableRenderer{}).Render(&buf, []string{"status"}, [][]string{{"\x1b[31mbroken\x1b[0m"}}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("NO_COLOR output kept colour codes: %q", buf.String())
+	}
+}
+
+func TestRenderFileFormatsStripCodes(t *testing.T) {
+	rows := []row{{name: "\x1b[33mwarn\x1b[0m", score: 1}}
+	for _, format := range []string{FormatCSV, FormatMarkdown} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, sampleColumns, rows, projectRow); err != nil {
+			t.Fatalf("Render %s: %v", format, err)
+		}
+		if strings.Contains(buf.String(), "\x1b") || !strings.Contains(buf.String(), "warn") {
+			t.Errorf("%s output = %q, want plain cells", format, buf.String())
+		}
+	}
+}
+
+func TestRenderWithTruncatesTableCells(t *testing.T) {
+	rows := []row{{name: "abcdefghijklmnop", score: 123456}}
+	var buf bytes.Buffer
+	opts := Options{MaxWidth: 4, Widths: map[string]int{"name": 8}}
+	if err := RenderWith(&buf, FormatTable, opts, sampleColumns, rows, projectRow); err != nil {
+		t.Fatalf("RenderWith: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "abcde...") || strings.Contains(out, "abcdef") {
+		t.Errorf("name not truncated to 8 by Widths:\n%s", out)
+	}
+	if !strings.Contains(out, "1...") {
+		t.Errorf("score not truncated to MaxWidth 4:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := RenderWith(&buf, FormatCSV, opts, sampleColumns, rows, projectRow); err != nil {
+		t.Fatalf("RenderWith csv: %v", err)
+	}
+	if !strings.Contains(buf.String(), "abcdefghijklmnop,123456") {
+		t.Errorf("csv cells were truncated: %q", buf.String())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"too long", 6, "too..."},
+		{"héllo wörld", 8, "héllo..."},
+		{"abc", 2, "ab"},
+		{"keep", 0, "keep"},
+		{"\x1b[31mcoloured text\x1b[0m", 5, "\x1b[31mcoloured text\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+import "math"
+
+// sparkRamps are the glyphs used to draw a sparkline, lowest to highest.
+var (
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	sparkASCII  = []rune("_.-:=+*#")
+)
+
+// Sparkline draws values as a one-line chart scaled between their minimum and
+// maximum. ascii selects a plain-ASCII ramp for terminals without Unicode.
+// A flat series renders at mid height; NaN values render as a space.
+func Sparkline(values []float64, ascii bool) string {
+	ramp := sparkBlocks
+	if ascii {
+		ramp = sparkASCII
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	out := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			out[i] = ' '
+		case hi == lo:
+			out[i] = ramp[len(ramp)/2]
+		default:
+			idx := int(math.Round((v - lo) / (hi - lo) * float64(len(ramp)-1)))
+			out[i] = ramp[idx]
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,24 @@
+package output
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparklineScalesMinToMax(t *testing.T) {
+	if got, want := Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8}, false), "▁▂▃▄▅▆▇█"; got != want {
+		t.Errorf("Sparkline = %q, want %q", got, want)
+	}
+	if got, want := Sparkline([]float64{0, 10}, true), "_#"; got != want {
+		t.Errorf("ASCII Sparkline = %q, want %q", got, want)
+	}
+}
+
+func TestSparklineFlatAndMissing(t *testing.T) {
+	if got, want := Sparkline([]float64{3, 3, math.NaN(), 3}, true), "== ="; got != want {
+		t.Errorf("Sparkline = %q, want %q", got, want)
+	}
+	if got := Sparkline(nil, false); got != "" {
+		t.Errorf("Sparkline(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FormatNDJSON is newline-delimited JSON: one object per row, no envelope.
+// Only ReportWriter produces it; Render's formats need every row up front.
+const FormatNDJSON = "ndjson"
+
+// ReportWriter streams rows to an io.Writer as they become available, so an
+// export of any size is written without holding it in memory. Each Write is
+// flushed before it returns: a reader at the other end of a pipe sees every
+// batch as soon as it is written.
+type ReportWriter[T any] interface {
+	// Write appends rows to the output.
+	Write(rows ...T) error
+	// Close flushes what is left. It does not close the underlying writer.
+	Close() error
+}
+
+// NewReportWriter returns a streaming writer for format, FormatCSV or
+// FormatNDJSON. The CSV header comes from columns and is written at once, so
+// an export without rows is still well formed; rowFn projects each row to its
+// cells. NDJSON encodes the rows themselves and ignores both.
+func NewReportWriter[T any](w io.Writer, format string, columns []string, rowFn func(T) []string) (ReportWriter[T], error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return nil, err
+		}
+		return &csvReportWriter[T]{cw: cw, width: len(columns), rowFn: rowFn}, nil
+	case FormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonReportWriter[T]{bw: bw, enc: json.NewEncoder(bw)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (want %s or %s)", ErrUnknownFormat, format, FormatCSV, FormatNDJSON)
+	}
+}
+
+type csvReportWriter[T any] struct {
+	cw    *csv.Writer
+	width int
+	rowFn func(T) []string
+}
+
+func (c *csvReportWriter[T]) Write(rows ...T) error {
+	for _, r := range rows {
+		cells := c.rowFn(r)
+		if err := assertArity(len(cells), c.width); err != nil {
+			return err
+		}
+		if err := c.cw.Write(stripAll(cells)); err != nil {
+			return err
+		}
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func (c *csvReportWriter[T]) Close() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+type ndjsonReportWriter[T any] struct {
+	bw  *bufio.Writer
+	enc *json.Encoder
+}
+
+func (n *ndjsonReportWriter[T]) Write(rows ...T) error {
+	for _, r := range rows {
+		if err := n.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return n.bw.Flush()
+}
+
+func (n *ndjsonReportWriter[T]) Close() error {
+	return n.bw.Flush()
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type jsonRow struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+func TestReportWriterCSVFlushesEachWrite(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewReportWriter(&buf, FormatCSV, sampleColumns, projectRow)
+	if err != nil {
+		t.Fatalf("NewReportWriter: %v", err)
+	}
+	if got := buf.String(); got != "name,score\n" {
+		t.Errorf("header not written up front, got %q", got)
+	}
+
+	rows := sampleRows()
+	if err := rw.Write(rows[0], rows[1]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "name,score\nalpha,1\nbeta,22\n" {
+		t.Errorf("first batch not flushed, got %q", got)
+	}
+	if err := rw.Write(rows[2]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "gamma|escape,333\n") {
+		t.Errorf("second batch missing\n%s", buf.String())
+	}
+}
+
+func TestReportWriterCSVArityMismatch(t *testing.T) {
+	rw, err := NewReportWriter(&bytes.Buffer{}, FormatCSV, []string{"only"}, projectRow)
+	if err != nil {
+		t.Fatalf("NewReportWriter: %v", err)
+	}
+	if err := rw.Write(sampleRows()[0]); err == nil {
+		t.Error("expected an arity error")
+	}
+}
+
+func TestReportWriterNDJSONOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewReportWriter[jsonRow](&buf, FormatNDJSON, nil, nil)
+	if err != nil {
+		t.Fatalf("NewReportWriter: %v", err)
+	}
+	if err := rw.Write(jsonRow{"alpha", 1}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != `{"name":"alpha","score":1}`+"\n" {
+		t.Errorf("row not flushed on Write, got %q", got)
+	}
+	if err := rw.Write(jsonRow{"beta", 22}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 lines, got %d\n%s", len(lines), buf.String())
+	}
+}
+
+func TestReportWriterUnknownFormat(t *testing.T) {
+	_, err := NewReportWriter(&bytes.Buffer{}, FormatMarkdown, sampleColumns, projectRow)
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+// Package pagespeed runs Lighthouse lab audits through the PageSpeed Insights
+// API (v5) and sets them beside the Chrome UX Report field data PSI returns
+// for the same URL.
+//
+// Lab data is one simulated load on Google's servers; field data is the 75th
+// percentile of real Chrome users over the last 28 days. The two often
+// disagree, which is why they are reported side by side.
+//
+// The API works without credentials at a low shared quota. An API key with
+// the PageSpeed Insights API enabled raises it to 25,000 requests per day.
+package pagespeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/pagespeedonline/v5"
+)
+
+// Strategies PSI can emulate.
+const (
+	StrategyMobile  = "mobile"
+	StrategyDesktop = "desktop"
+)
+
+// DefaultRequestsPerMinute keeps well under PSI's per-user limit of 400
+// requests per 100 seconds.
+const DefaultRequestsPerMinute = 60
+
+// Field data sources, from most to least specific.
+const (
+	FieldURL    = "url"
+	FieldOrigin = "origin"
+)
+
+// categories are the Lighthouse categories requested on every run.
+var categories = []string{"performance", "seo", "accessibility", "best-practices"}
+
+// Scores are Lighthouse category scores from 0 to 100; nil when the category
+// could not be scored.
+type Scores struct {
+	Performance   *int `json:"performance"`
+	SEO           *int `json:"seo"`
+	Accessibility *int `json:"accessibility"`
+	BestPractices *int `json:"best_practices"`
+}
+
+// Metric is one web vital measured in the lab, in the field, or both. Values
+// are milliseconds except CLS, which is unitless.
+type Metric struct {
+	Name          string   `json:"name"`
+	Lab           *float64 `json:"lab,omitempty"`
+	Field         *float64 `json:"field_p75,omitempty"`
+	FieldCategory string   `json:"field_category,omitempty"`
+}
+
+// Opportunity is a Lighthouse suggestion with its estimated saving.
+type Opportunity struct {
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	SavingsMs float64 `json:"savings_ms"`
+	Display   string  `json:"display,omitempty"`
+}
+
+// Result is one PSI run.
+type Result struct {
+	URL      string `json:"url"`
+	FinalURL string `json:"final_url,omitempty"`
+	Strategy string `json:"strategy"`
+	Fetched  string `json:"fetched,omitempty"`
+	Scores   Scores `json:"scores"`
+	// FieldSource is FieldURL or FieldOrigin when CrUX had data, else empty.
+	FieldSource   string        `json:"field_source,omitempty"`
+	FieldCategory string        `json:"field_category,omitempty"`
+	Metrics       []Metric      `json:"metrics"`
+	Opportunities []Opportunity `json:"opportunities"`
+}
+
+// vital maps a metric to its Lighthouse audit and CrUX metric IDs. scale
+// converts the CrUX percentile to the unit of the lab value.
+type vital struct {
+	name, audit, crux string
+	scale             float64
+}
+
+var vitals = []vital{
+	{"LCP", "largest-contentful-paint", "LARGEST_CONTENTFUL_PAINT_MS", 1},
+	{"INP", "", "INTERACTION_TO_NEXT_PAINT", 1},
+	{"CLS", "cumulative-layout-shift", "CUMULATIVE_LAYOUT_SHIFT_SCORE", 0.01},
+	{"FCP", "first-contentful-paint", "FIRST_CONTENTFUL_PAINT_MS", 1},
+	{"TTFB", "server-response-time", "EXPERIMENTAL_TIME_TO_FIRST_BYTE", 1},
+	{"TBT", "total-blocking-time", "", 1},
+}
+
+// runAPI is the narrow seam over the PSI SDK, so Client can be tested with a
+// fake.
+type runAPI interface {
+	run(ctx context.Context, url, strategy string) (*pagespeedonline.PagespeedApiPagespeedResponseV5, error)
+}
+
+type realRunAPI struct {
+	service *pagespeedonline.Service
+}
+
+func (r realRunAPI) run(ctx context.Context, url, strategy string) (*pagespeedonline.PagespeedApiPagespeedResponseV5, error) {
+	return r.service.Pagespeedapi.Runpagespeed(url).Strategy(strategy).Category(categories...).Context(ctx).Do()
+}
+
+// Client runs PSI audits, spacing requests to stay inside the quota.
+type Client struct {
+	api     runAPI
+	limiter *rate.Limiter
+}
+
+// NewClient creates a PSI client. apiKey may be empty to use the anonymous
+// quota. perMinute caps the request rate (DefaultRequestsPerMinute when 0).
+func NewClient(ctx context.Context, apiKey string, perMinute int) (*Client, error) {
+	opt := option.WithoutAuthentication()
+	if apiKey != "" {
+		opt = option.WithAPIKey(apiKey)
+	}
+	service, err := pagespeedonline.NewService(ctx, opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PageSpeed Insights service: %w", err)
+	}
+	return &Client{api: realRunAPI{service}, limiter: newLimiter(perMinute)}, nil
+}
+
+func newLimiter(perMinute int) *rate.Limiter {
+	if perMinute <= 0 {
+		perMinute = DefaultRequestsPerMinute
+	}
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), 1)
+}
+
+// Run audits url with the given strategy. Opportunities are sorted by
+// estimated saving, largest first.
+func (c *Client) Run(ctx context.Context, url, strategy string) (*Result, error) {
+	if strategy != StrategyMobile && strategy != StrategyDesktop {
+		return nil, fmt.Errorf("invalid strategy %q (want %s or %s)", strategy, StrategyMobile, StrategyDesktop)
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := c.api.run(ctx, url, strategy)
+	if err != nil {
+		return nil, fmt.Errorf("PageSpeed Insights failed for %s: %w", url, err)
+	}
+	lh := resp.LighthouseResult
+	if lh == nil {
+		return nil, fmt.Errorf("PageSpeed Insights returned no Lighthouse result for %s", url)
+	}
+	if lh.RuntimeError != nil && lh.RuntimeError.Code != "" && lh.RuntimeError.Code != "NO_ERROR" {
+		return nil, fmt.Errorf("lighthouse could not load %s: %s (%s)", url, lh.RuntimeError.Message, lh.RuntimeError.Code)
+	}
+	return toResult(url, strategy, resp), nil
+}
+
+func toResult(url, strategy string, resp *pagespeedonline.PagespeedApiPagespeedResponseV5) *Result {
+	lh := resp.LighthouseResult
+	r := &Result{URL: url, FinalURL: lh.FinalUrl, Strategy: strategy, Fetched: lh.FetchTime, Opportunities: []Opportunity{}}
+	if c := lh.Categories; c != nil {
+		r.Scores = Scores{
+			Performance:   categoryScore(c.Performance),
+			SEO:           categoryScore(c.Seo),
+			Accessibility: categoryScore(c.Accessibility),
+			BestPractices: categoryScore(c.BestPractices),
+		}
+	}
+
+	field := resp.LoadingExperience
+	r.FieldSource = FieldURL
+	if field == nil || len(field.Metrics) == 0 || field.OriginFallback {
+		field, r.FieldSource = resp.OriginLoadingExperience, FieldOrigin
+	}
+	if field == nil || len(field.Metrics) == 0 {
+		field, r.FieldSource = nil, ""
+	} else {
+		r.FieldCategory = field.OverallCategory
+	}
+
+	for _, v := range vitals {
+		m := Metric{Name: v.name}
+		if a, ok := lh.Audits[v.audit]; ok && v.audit != "" && a.ScoreDisplayMode != "error" {
+			lab := a.NumericValue
+			m.Lab = &lab
+		}
+		if fm, ok := fieldMetric(field, v.crux); ok {
+			p75 := float64(fm.Percentile) * v.scale
+			m.Field, m.FieldCategory = &p75, fm.Category
+		}
+		if m.Lab != nil || m.Field != nil {
+			r.Metrics = append(r.Metrics, m)
+		}
+	}
+
+	for id, a := range lh.Audits {
+		if score, ok := a.Score.(float64); !ok || score >= 0.9 {
+			continue
+		}
+		if savings := auditSavings(a); savings > 0 {
+			r.Opportunities = append(r.Opportunities, Opportunity{ID: id, Title: a.Title, SavingsMs: savings, Display: a.DisplayValue})
+		}
+	}
+	sort.Slice(r.Opportunities, func(i, j int) bool {
+		if r.Opportunities[i].SavingsMs != r.Opportunities[j].SavingsMs {
+			return r.Opportunities[i].SavingsMs > r.Opportunities[j].SavingsMs
+		}
+		return r.Opportunities[i].ID < r.Opportunities[j].ID
+	})
+	return r
+}
+
+func categoryScore(c *pagespeedonline.LighthouseCategoryV5) *int {
+	if c == nil {
+		return nil
+	}
+	score, ok := c.Score.(float64)
+	if !ok {
+		return nil
+	}
+	n := int(score*100 + 0.5)
+	return &n
+}
+
+func fieldMetric(field *pagespeedonline.PagespeedApiLoadingExperienceV5, id string) (pagespeedonline.UserPageLoadMetricV5, bool) {
+	if field == nil || id == "" {
+		return pagespeedonline.UserPageLoadMetricV5{}, false
+	}
+	m, ok := field.Metrics[id]
+	return m, ok
+}
+
+// auditSavings is the estimated time saved by fixing an audit: the
+// opportunity's overallSavingsMs, or for newer insight audits the largest
+// LCP, FCP or TBT saving.
+func auditSavings(a pagespeedonline.LighthouseAuditResultV5) float64 {
+	var details struct {
+		Type             string  `json:"type"`
+		OverallSavingsMs float64 `json:"overallSavingsMs"`
+	}
+	if len(a.Details) > 0 && json.Unmarshal(a.Details, &details) == nil && details.Type == "opportunity" && details.OverallSavingsMs > 0 {
+		return details.OverallSavingsMs
+	}
+	if s := a.MetricSavings; s != nil {
+		return max(s.LCP, s.FCP, s.TBT)
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package pagespeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/pagespeedonline/v5"
+)
+
+// fakeRunAPI returns a canned response and records the strategy asked for.
+type fakeRunAPI struct {
+	resp        *pagespeedonline.PagespeedApiPagespeedResponseV5
+	err         error
+	gotStrategy string
+}
+
+func (f *fakeRunAPI) run(_ context.Context, _, strategy string) (*pagespeedonline.PagespeedApiPagespeedResponseV5, error) {
+	f.gotStrategy = strategy
+	return f.resp, f.err
+}
+
+func newTestClient(f *fakeRunAPI) *Client {
+	return &Client{api: f, limiter: rate.NewLimiter(rate.Inf, 1)}
+}
+
+func response() *pagespeedonline.PagespeedApiPagespeedResponseV5 {
+	return &pagespeedonline.PagespeedApiPagespeedResponseV5{
+		LighthouseResult: &pagespeedonline.LighthouseResultV5{
+			FinalUrl: "https://example.com/",
+			Categories: &pagespeedonline.Categories{
+				Performance:   &pagespeedonline.LighthouseCategoryV5{Score: 0.456},
+				Seo:           &pagespeedonline.LighthouseCategoryV5{Score: 1.0},
+				Accessibility: &pagespeedonline.LighthouseCategoryV5{Score: nil},
+			},
+			Audits: map[string]pagespeedonline.LighthouseAuditResultV5{
+				"largest-contentful-paint": {NumericValue: 4200, Score: 0.3},
+				"cumulative-layout-shift":  {NumericValue: 0.02, Score: 1.0},
+				"total-blocking-time":      {NumericValue: 350, Score: 0.6},
+				"render-blocking-resources": {
+					Title:   "Eliminate render-blocking resources",
+					Score:   0.2,
+					Details: []byte(`{"type":"opportunity","overallSavingsMs":900}`),
+				},
+				"lcp-discovery-insight": {
+					Title:         "LCP request discovery",
+					Score:         0.0,
+					MetricSavings: &pagespeedonline.MetricSavings{LCP: 1500},
+				},
+				"unused-css-rules": {
+					Title:   "Reduce unused CSS",
+					Score:   1.0,
+					Details: []byte(`{"type":"opportunity","overallSavingsMs":50}`),
+				},
+			},
+		},
+		LoadingExperience: &pagespeedonline.PagespeedApiLoadingExperienceV5{OriginFallback: true},
+		OriginLoadingExperience: &pagespeedonline.PagespeedApiLoadingExperienceV5{
+			OverallCategory: "AVERAGE",
+			Metrics: map[string]pagespeedonline.UserPageLoadMetricV5{
+				"LARGEST_CONTENTFUL_PAINT_MS":   {Percentile: 2900, Category: "AVERAGE"},
+				"INTERACTION_TO_NEXT_PAINT":     {Percentile: 180, Category: "FAST"},
+				"CUMULATIVE_LAYOUT_SHIFT_SCORE": {Percentile: 12, Category: "AVERAGE"},
+			},
+		},
+	}
+}
+
+func TestClient_Run(t *testing.T) {
+	fake := &fakeRunAPI{resp: response()}
+	r, err := newTestClient(fake).Run(context.Background(), "https://example.com", StrategyDesktop)
+	require.NoError(t, err)
+
+	assert.Equal(t, StrategyDesktop, fake.gotStrategy)
+	assert.Equal(t, 46, *r.Scores.Performance)
+	assert.Equal(t, 100, *r.Scores.SEO)
+	assert.Nil(t, r.Scores.Accessibility)
+	assert.Nil(t, r.Scores.BestPractices)
+
+	assert.Equal(t, FieldOrigin, r.FieldSource, "URL data fell back to the origin")
+	assert.Equal(t, "AVERAGE", r.FieldCategory)
+	metrics := make(map[string]Metric)
+	for _, m := range r.Metrics {
+		metrics[m.Name] = m
+	}
+	assert.Len(t, metrics, 4, "FCP and TTFB have neither lab nor field data")
+	assert.InDelta(t, 4200, *metrics["LCP"].Lab, 1e-9)
+	assert.InDelta(t, 2900, *metrics["LCP"].Field, 1e-9)
+	assert.Nil(t, metrics["INP"].Lab)
+	assert.Equal(t, "FAST", metrics["INP"].FieldCategory)
+	assert.InDelta(t, 0.12, *metrics["CLS"].Field, 1e-9)
+	assert.Nil(t, metrics["TBT"].Field)
+
+	require.Len(t, r.Opportunities, 2)
+	assert.Equal(t, "lcp-discovery-insight", r.Opportunities[0].ID)
+	assert.Equal(t, "render-blocking-resources", r.Opportunities[1].ID)
+	assert.InDelta(t, 900, r.Opportunities[1].SavingsMs, 1e-9)
+}
+
+func TestClient_Run_NoFieldData(t *testing.T) {
+	resp := response()
+	resp.OriginLoadingExperience = nil
+	r, err := newTestClient(&fakeRunAPI{resp: resp}).Run(context.Background(), "https://example.com", StrategyMobile)
+	require.NoError(t, err)
+	assert.Empty(t, r.FieldSource)
+	for _, m := range r.Metrics {
+		assert.Nil(t, m.Field, m.Name)
+	}
+}
+
+func TestClient_Run_Errors(t *testing.T) {
+	_, err := newTestClient(&fakeRunAPI{}).Run(context.Background(), "https://example.com", "tablet")
+	assert.ErrorContains(t, err, "invalid strategy")
+
+	_, err = newTestClient(&fakeRunAPI{err: errors.New("quota exceeded")}).Run(context.Background(), "https://example.com", StrategyMobile)
+	assert.ErrorContains(t, err, "quota exceeded")
+
+	resp := response()
+	resp.LighthouseResult.RuntimeError = &pagespeedonline.RuntimeError{Code: "FAILED_DOCUMENT_REQUEST", Message: "could not load"}
+	_, err = newTestClient(&fakeRunAPI{resp: resp}).Run(context.Background(), "https://example.com", StrategyMobile)
+	assert.ErrorContains(t, err, "FAILED_DOCUMENT_REQUEST")
+}
@@ -0,0 +1,309 @@
+// Package parquet writes flat tables as Apache Parquet files, so large
+// exports load straight into DuckDB, Spark or pandas with their column types
+// instead of being re-parsed from CSV. It covers what the reports need: a
+// flat schema of string, int64, double, boolean, date and timestamp columns,
+// row groups flushed as rows stream in, and Snappy, gzip or no compression.
+// The file format itself is left to github.com/parquet-go/parquet-go; this
+// package maps report values onto its schema and rows.
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// Format is the --format value of the Parquet export.
+const Format = "parquet"
+
+// Compression codecs.
+const (
+	CompressionSnappy = "snappy"
+	CompressionGzip   = "gzip"
+	CompressionNone   = "none"
+)
+
+// Compressions lists the codecs, default first.
+func Compressions() []string {
+	return []string{CompressionSnappy, CompressionGzip, CompressionNone}
+}
+
+// DefaultRowGroupRows is how many rows a row group holds before it is
+// written out; a writer keeps at most one row group in memory.
+const DefaultRowGroupRows = 100_000
+
+// Type is a column type.
+type Type int
+
+// Column types. Date columns hold days (INT32 DATE), timestamps microseconds
+// in UTC (INT64 TIMESTAMP).
+const (
+	String Type = iota
+	Int64
+	Double
+	Boolean
+	Date
+	Timestamp
+)
+
+// Column is one field of the schema.
+type Column struct {
+	Name string
+	Type Type
+	// Optional columns accept nil values; others reject them.
+	Optional bool
+}
+
+// node returns the schema node of a column of type t.
+func (t Type) node() pq.Node {
+	switch t {
+	case Int64:
+		return pq.Int(64)
+	case Double:
+		return pq.Leaf(pq.DoubleType)
+	case Boolean:
+		return pq.Leaf(pq.BooleanType)
+	case Date:
+		return pq.Date()
+	case Timestamp:
+		return pq.Timestamp(pq.Microsecond)
+	default:
+		return pq.String()
+	}
+}
+
+// group is the root of the schema. pq.Group orders its fields by name; the
+// file keeps the column order of the report instead.
+type group struct {
+	pq.Group
+	fields []pq.Field
+}
+
+func (g group) Fields() []pq.Field { return g.fields }
+
+// field is a named column of group. Values are written as rows, never
+// read from Go structs, so Value is not used.
+type field struct {
+	pq.Node
+	name string
+}
+
+func (f field) Name() string                           { return f.name }
+func (f field) Value(base reflect.Value) reflect.Value { return reflect.Value{} }
+
+func schema(columns []Column) *pq.Schema {
+	g := group{Group: pq.Group{}}
+	for _, col := range columns {
+		n := col.Type.node()
+		if col.Optional {
+			n = pq.Optional(n)
+		}
+		g.Group[col.Name] = n
+		g.fields = append(g.fields, field{Node: n, name: col.Name})
+	}
+	return pq.NewSchema("schema", g)
+}
+
+// Writer writes rows to w as a Parquet file. Rows are buffered into row
+// groups of RowGroupRows; Close writes the last one and the footer. A file is
+// only valid once Close returns.
+type Writer struct {
+	// RowGroupRows caps the rows of a row group.
+	RowGroupRows int
+
+	pw      *pq.Writer
+	columns []Column
+	rows    []pq.Row
+	err     error
+}
+
+// NewWriter returns a Writer of columns to w, compressed with compression,
+// one of Compressions.
+func NewWriter(w io.Writer, columns []Column, compression string) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("parquet: no columns")
+	}
+	var codec compress.Codec
+	switch compression {
+	case CompressionSnappy:
+		codec = &pq.Snappy
+	case CompressionGzip:
+		codec = &pq.Gzip
+	case CompressionNone:
+		codec = &pq.Uncompressed
+	default:
+		return nil, fmt.Errorf("parquet: unknown compression %q", compression)
+	}
+	return &Writer{
+		RowGroupRows: DefaultRowGroupRows,
+		pw:           pq.NewWriter(w, schema(columns), pq.Compression(codec)),
+		columns:      columns,
+	}, nil
+}
+
+// Write buffers rows, each holding one value per column: string; int, int32
+// or int64; float32 or float64; bool; a YYYY-MM-DD string (of any string
+// type) or time.Time for dates; time.Time for timestamps. nil is a null.
+func (w *Writer) Write(rows ...[]any) error {
+	if w.err != nil {
+		return w.err
+	}
+	for _, values := range rows {
+		row, err := w.row(values)
+		if err != nil {
+			return err
+		}
+		w.rows = append(w.rows, row)
+		if len(w.rows) >= w.RowGroupRows {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the footer. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.rows) > 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	if err := w.pw.Close(); err != nil {
+		w.err = fmt.Errorf("parquet: %w", err)
+	}
+	return w.err
+}
+
+// flush writes the buffered rows as a row group.
+func (w *Writer) flush() error {
+	if _, err := w.pw.WriteRows(w.rows); err != nil {
+		w.err = fmt.Errorf("parquet: %w", err)
+		return w.err
+	}
+	if err := w.pw.Flush(); err != nil {
+		w.err = fmt.Errorf("parquet: %w", err)
+		return w.err
+	}
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// row converts one row of values to the Parquet row of the schema.
+func (w *Writer) row(values []any) (pq.Row, error) {
+	if len(values) != len(w.columns) {
+		return nil, fmt.Errorf("parquet: row has %d values for %d columns", len(values), len(w.columns))
+	}
+	row := make(pq.Row, len(values))
+	for i, col := range w.columns {
+		if values[i] == nil {
+			if !col.Optional {
+				return nil, fmt.Errorf("parquet: null in required column %s", col.Name)
+			}
+			row[i] = pq.NullValue().Level(0, 0, i)
+			continue
+		}
+		v, err := valueOf(col.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("parquet: column %s: %w", col.Name, err)
+		}
+		definition := 0
+		if col.Optional {
+			definition = 1
+		}
+		row[i] = v.Level(0, definition, i)
+	}
+	return row, nil
+}
+
+// valueOf returns v as a value of a column of type t.
+func valueOf(t Type, v any) (pq.Value, error) {
+	switch t {
+	case String:
+		s, ok := stringOf(v)
+		if !ok {
+			return pq.Value{}, fmt.Errorf("want a string, got %T", v)
+		}
+		return pq.ByteArrayValue([]byte(s)), nil
+	case Int64:
+		switch n := v.(type) {
+		case int:
+			return pq.Int64Value(int64(n)), nil
+		case int32:
+			return pq.Int64Value(int64(n)), nil
+		case int64:
+			return pq.Int64Value(n), nil
+		}
+		return pq.Value{}, fmt.Errorf("want an integer, got %T", v)
+	case Double:
+		switch f := v.(type) {
+		case float64:
+			return pq.DoubleValue(f), nil
+		case float32:
+			return pq.DoubleValue(float64(f)), nil
+		}
+		return pq.Value{}, fmt.Errorf("want a float, got %T", v)
+	case Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return pq.Value{}, fmt.Errorf("want a bool, got %T", v)
+		}
+		return pq.BooleanValue(b), nil
+	case Date:
+		day, err := dateOf(v)
+		if err != nil {
+			return pq.Value{}, err
+		}
+		return pq.Int32Value(day), nil
+	case Timestamp:
+		ts, ok := v.(time.Time)
+		if !ok {
+			return pq.Value{}, fmt.Errorf("want a time.Time, got %T", v)
+		}
+		return pq.Int64Value(ts.UnixMicro()), nil
+	}
+	return pq.Value{}, fmt.Errorf("unsupported column type %d", t)
+}
+
+// stringOf returns v as a string when its kind is string, so named string
+// types such as dates from other packages are accepted.
+func stringOf(v any) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.String {
+		return "", false
+	}
+	return rv.String(), true
+}
+
+// dateOf returns the days since the Unix epoch of a YYYY-MM-DD string or a
+// time.Time.
+func dateOf(v any) (int32, error) {
+	t, ok := v.(time.Time)
+	if !ok {
+		s, isString := stringOf(v)
+		if !isString {
+			return 0, fmt.Errorf("want a date, got %T", v)
+		}
+		var err error
+		if t, err = time.Parse("2006-01-02", s); err != nil {
+			return 0, fmt.Errorf("invalid date %q", s)
+		}
+	}
+	y, m, d := t.Date()
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	if days < math.MinInt32 || days > math.MaxInt32 {
+		return 0, fmt.Errorf("date %s out of range", t.Format("2006-01-02"))
+	}
+	return int32(days), nil
+}
@@ -0,0 +1,140 @@
+package parquet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests read files back with parquet-go's reader, which does not share
+// this package's code.
+
+func openFile(t *testing.T, data []byte) *pq.File {
+	t.Helper()
+	f, err := pq.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return f
+}
+
+func readRows(t *testing.T, f *pq.File) []pq.Row {
+	t.Helper()
+	r := pq.NewReader(f)
+	defer r.Close()
+	rows := make([]pq.Row, f.NumRows())
+	n, err := r.ReadRows(rows)
+	if err != io.EOF {
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(rows), n)
+	return rows
+}
+
+func TestWriter_SchemaAndValues(t *testing.T) {
+	columns := []Column{
+		{Name: "query", Type: String},
+		{Name: "report_date", Type: Date},
+		{Name: "clicks", Type: Int64},
+		{Name: "ctr", Type: Double},
+		{Name: "note", Type: String, Optional: true},
+		{Name: "exported_at", Type: Timestamp},
+		{Name: "branded", Type: Boolean},
+	}
+	type day string
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	codecs := map[string]format.CompressionCodec{
+		CompressionSnappy: format.Snappy,
+		CompressionGzip:   format.Gzip,
+		CompressionNone:   format.Uncompressed,
+	}
+
+	for _, compression := range Compressions() {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, columns, compression)
+			require.NoError(t, err)
+			w.RowGroupRows = 2
+			require.NoError(t, w.Write(
+				[]any{"compress png", day("2026-10-14"), int64(12), 0.25, nil, at, true},
+				[]any{"resize jpg", "1970-01-02", 3, 0.5, "x", at, false},
+				[]any{"webp", "2026-10-14", int64(1), float32(0.125), nil, at, true},
+			))
+			require.NoError(t, w.Close())
+
+			f := openFile(t, buf.Bytes())
+			assert.Equal(t, int64(3), f.NumRows())
+
+			schema := f.Metadata().Schema
+			require.Len(t, schema, len(columns)+1)
+			for i, col := range columns {
+				assert.Equal(t, col.Name, schema[i+1].Name, "columns keep their order")
+			}
+			date := schema[2]
+			assert.Equal(t, format.Int32, date.Type.V)
+			assert.Equal(t, format.Required, date.RepetitionType.V)
+			assert.IsType(t, &format.DateType{}, date.LogicalType.Value)
+			assert.Equal(t, format.Optional, schema[5].RepetitionType.V)
+			ts, ok := schema[6].LogicalType.Value.(*format.TimestampType)
+			require.True(t, ok)
+			assert.True(t, ts.IsAdjustedToUTC)
+			assert.IsType(t, &format.MicroSeconds{}, ts.Unit.Value)
+			assert.Equal(t, format.Int64, schema[3].Type.V)
+			assert.Equal(t, format.Double, schema[4].Type.V)
+
+			groups := f.Metadata().RowGroups
+			require.Len(t, groups, 2)
+			assert.Equal(t, int64(2), groups[0].NumRows)
+			assert.Equal(t, int64(1), groups[1].NumRows)
+			assert.Equal(t, codecs[compression], groups[0].Columns[0].MetaData.Codec)
+
+			rows := readRows(t, f)
+			assert.Equal(t, "compress png", string(rows[0][0].ByteArray()))
+			assert.Equal(t, int32(20740), rows[0][1].Int32())
+			assert.Equal(t, int32(1), rows[1][1].Int32())
+			assert.Equal(t, []int64{12, 3, 1}, []int64{rows[0][2].Int64(), rows[1][2].Int64(), rows[2][2].Int64()})
+			assert.Equal(t, []float64{0.25, 0.5, 0.125}, []float64{rows[0][3].Double(), rows[1][3].Double(), rows[2][3].Double()})
+			assert.True(t, rows[0][4].IsNull())
+			assert.Equal(t, "x", string(rows[1][4].ByteArray()))
+			assert.Equal(t, at.UnixMicro(), rows[2][5].Int64())
+			assert.Equal(t, []bool{true, false, true}, []bool{rows[0][6].Boolean(), rows[1][6].Boolean(), rows[2][6].Boolean()})
+		})
+	}
+}
+
+func TestWriter_EmptyFileIsValid(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "page", Type: String}}, CompressionSnappy)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	f := openFile(t, buf.Bytes())
+	assert.Equal(t, int64(0), f.NumRows())
+	assert.Empty(t, f.Metadata().RowGroups)
+}
+
+func TestWriter_RejectsBadValues(t *testing.T) {
+	for name, row := range map[string][]any{
+		"null in required": {nil, int64(1)},
+		"wrong type":       {"a", "1"},
+		"bad date":         {"a", int64(1)},
+		"short row":        {"a"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			columns := []Column{{Name: "page", Type: String}, {Name: "clicks", Type: Int64}}
+			if name == "bad date" {
+				columns[1] = Column{Name: "day", Type: Date}
+			}
+			w, err := NewWriter(io.Discard, columns, CompressionNone)
+			require.NoError(t, err)
+			assert.Error(t, w.Write(row))
+		})
+	}
+
+	_, err := NewWriter(io.Discard, []Column{{Name: "page"}}, "lz4")
+	assert.ErrorContains(t, err, `unknown compression "lz4"`)
+}
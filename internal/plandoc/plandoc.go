@@ -0,0 +1,215 @@
+// Package plandoc renders a config as a measurement plan: the document,
+// usually a spreadsheet kept by hand, listing every tracked event, its
+// parameters, the custom dimensions and metrics they feed, and the
+// audiences and calculated metrics that depend on them.
+package plandoc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// Parameter kinds.
+const (
+	KindDimension = "dimension"
+	KindMetric    = "metric"
+	KindBuiltIn   = "built-in" // value, currency and other GA4 parameters
+)
+
+// Plan is a measurement plan.
+type Plan struct {
+	Project       string      `json:"project"`
+	PropertyID    string      `json:"property_id,omitempty"`
+	MeasurementID string      `json:"measurement_id,omitempty"`
+	GeneratedAt   time.Time   `json:"generated_at"`
+	Events        []Event     `json:"events"`
+	Parameters    []Parameter `json:"parameters"`
+	Audiences     []Audience  `json:"audiences"`
+}
+
+// Event is a conversion event.
+type Event struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	CountingMethod string   `json:"counting_method,omitempty"`
+	Value          string   `json:"value,omitempty"`
+	Parameters     []string `json:"parameters,omitempty"`
+	Audiences      []string `json:"audiences,omitempty"`
+	Priority       string   `json:"priority,omitempty"`
+	Owner          string   `json:"owner,omitempty"`
+}
+
+// Parameter is an event parameter or user property and what it is
+// registered as. Unregistered is true for a parameter an event lists that
+// no custom dimension or metric collects, so GA4 drops it from reports.
+type Parameter struct {
+	Name              string   `json:"name"`
+	Kind              string   `json:"kind,omitempty"`
+	Scope             string   `json:"scope,omitempty"`
+	DisplayName       string   `json:"display_name,omitempty"`
+	Description       string   `json:"description,omitempty"`
+	Unit              string   `json:"unit,omitempty"`
+	Events            []string `json:"events,omitempty"`
+	Audiences         []string `json:"audiences,omitempty"`
+	CalculatedMetrics []string `json:"calculated_metrics,omitempty"`
+	Priority          string   `json:"priority,omitempty"`
+	Owner             string   `json:"owner,omitempty"`
+	Unregistered      bool     `json:"unregistered,omitempty"`
+}
+
+// Audience is an audience and the events and parameters its definition
+// mentions.
+type Audience struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Duration    int      `json:"duration_days,omitempty"`
+	Conditions  []string `json:"conditions,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Parameters  []string `json:"parameters,omitempty"`
+}
+
+// Build derives the plan from cfg. Which event sends which parameter comes
+// from the conversions' parameters lists. Audiences depend on the events
+// and parameters they list or name in their conditions or description,
+// calculated metrics on those named in their formula.
+func Build(cfg *config.ProjectConfig, now time.Time) *Plan {
+	p := &Plan{
+		Project:       cfg.Project.Name,
+		PropertyID:    cfg.GetPropertyID(),
+		MeasurementID: cfg.GetMeasurementID(),
+		GeneratedAt:   now,
+		Events:        []Event{},
+		Parameters:    []Parameter{},
+		Audiences:     []Audience{},
+	}
+
+	params := map[string]*Parameter{}
+	var order []string
+	param := func(name string) *Parameter {
+		if params[name] == nil {
+			params[name] = &Parameter{Name: name}
+			order = append(order, name)
+		}
+		return params[name]
+	}
+	for _, d := range cfg.Dimensions {
+		pm := param(d.ParameterName)
+		pm.Kind, pm.Scope, pm.DisplayName, pm.Description = KindDimension, strings.ToUpper(d.Scope), d.DisplayName, d.Description
+		pm.Priority, pm.Owner = d.Priority, d.Owner
+	}
+	for _, m := range cfg.Metrics {
+		pm := param(m.ParameterName)
+		pm.Kind, pm.Scope, pm.DisplayName, pm.Description = KindMetric, strings.ToUpper(m.Scope), m.DisplayName, m.Description
+		pm.Unit, pm.Priority, pm.Owner = m.MeasurementUnit, m.Priority, m.Owner
+	}
+	registered := len(order)
+
+	for _, c := range cfg.Conversions {
+		e := Event{
+			Name:           c.Name,
+			Description:    c.Description,
+			CountingMethod: c.CountingMethod,
+			Value:          eventValue(cfg, c),
+			Parameters:     c.Parameters,
+			Priority:       c.Priority,
+			Owner:          c.Owner,
+		}
+		for _, name := range c.Parameters {
+			pm := param(name)
+			pm.Events = append(pm.Events, c.Name)
+		}
+		p.Events = append(p.Events, e)
+	}
+	for _, name := range order[registered:] {
+		if isValueParam(name) {
+			params[name].Kind = KindBuiltIn
+		} else {
+			params[name].Unregistered = true
+		}
+	}
+
+	for _, a := range cfg.Audiences {
+		aud := Audience{Name: a.Name, Description: a.Description, Category: a.Category, Duration: a.Duration, Conditions: a.Conditions}
+		text := strings.Join(append([]string{a.Description}, a.Conditions...), "\n")
+		for i := range p.Events {
+			if slices.Contains(a.Events, p.Events[i].Name) || mentions(text, p.Events[i].Name) {
+				aud.Events = append(aud.Events, p.Events[i].Name)
+				p.Events[i].Audiences = append(p.Events[i].Audiences, a.Name)
+			}
+		}
+		for _, name := range order {
+			if slices.Contains(a.Parameters, name) || mentions(text, name) {
+				aud.Parameters = append(aud.Parameters, name)
+				params[name].Audiences = append(params[name].Audiences, a.Name)
+			}
+		}
+		p.Audiences = append(p.Audiences, aud)
+	}
+	for _, cm := range cfg.CalculatedMetrics {
+		for _, name := range order {
+			if mentions(cm.Formula, name) {
+				params[name].CalculatedMetrics = append(params[name].CalculatedMetrics, cm.Name)
+			}
+		}
+	}
+
+	for _, name := range order {
+		p.Parameters = append(p.Parameters, *params[name])
+	}
+	sort.SliceStable(p.Parameters, func(i, j int) bool {
+		return scopeRank(p.Parameters[i]) < scopeRank(p.Parameters[j])
+	})
+	return p
+}
+
+// eventValue describes the value a conversion carries.
+func eventValue(cfg *config.ProjectConfig, c config.ConversionConfig) string {
+	if c.DefaultValue != nil {
+		return fmt.Sprintf("default %s %s", strconv.FormatFloat(*c.DefaultValue, 'f', -1, 64), cfg.ConversionCurrency(c))
+	}
+	if cfg.IsValueBearingEvent(c.Name) {
+		return "required"
+	}
+	return ""
+}
+
+// isValueParam reports whether name is one of GA4's built-in value
+// parameters, which need no custom definition.
+func isValueParam(name string) bool {
+	switch name {
+	case "value", "currency", "transaction_id", "items", "coupon", "tax", "shipping":
+		return true
+	}
+	return false
+}
+
+// scopeRank orders parameters: event scope, user scope, item scope, then
+// built-in and unregistered ones.
+func scopeRank(p Parameter) int {
+	switch {
+	case p.Kind == KindBuiltIn || p.Unregistered:
+		return 3
+	case p.Scope == "USER":
+		return 1
+	case p.Scope == "ITEM":
+		return 2
+	}
+	return 0
+}
+
+// mentions reports whether text names name as a whole word: purchase
+// matches "purchase >= 3" but not "purchase_count".
+func mentions(text, name string) bool {
+	if name == "" {
+		return false
+	}
+	return regexp.MustCompile(`(^|[^A-Za-z0-9_])` + regexp.QuoteMeta(name) + `($|[^A-Za-z0-9_])`).MatchString(text)
+}
@@ -0,0 +1,97 @@
+package plandoc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func testConfig() *config.ProjectConfig {
+	forty := 40.0
+	return &config.ProjectConfig{
+		Project:   config.ProjectInfo{Name: "Example"},
+		Analytics: &config.AnalyticsConfig{PropertyID: "123", MeasurementID: "G-TEST", Currency: "EUR"},
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT", Parameters: []string{"value", "currency", "plan_type", "coupon_code"}, Owner: "checkout team", Priority: "high"},
+			{Name: "book_demo", DefaultValue: &forty, Parameters: []string{"plan_type"}},
+			{Name: "newsletter_signup"},
+		},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "customer_tier", DisplayName: "Customer Tier", Scope: "USER", Owner: "crm"},
+			{ParameterName: "plan_type", DisplayName: "Plan Type", Scope: "event"},
+		},
+		Metrics: []config.MetricConfig{{ParameterName: "items_count", DisplayName: "Items", MeasurementUnit: "STANDARD", Scope: "EVENT"}},
+		CalculatedMetrics: []config.CalculatedMetricConfig{
+			{Name: "Items per purchase", Formula: "{customEvent:items_count} / {keyEvents:purchase}"},
+		},
+		Audiences: []config.AudienceConfig{
+			{Name: "Pro buyers", Duration: 30, Conditions: []string{"purchase with plan_type = pro"}},
+			{Name: "Heavy carts", Description: "items_count_total above 10"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	p := Build(testConfig(), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+
+	require.Len(t, p.Events, 3)
+	assert.Equal(t, Event{
+		Name: "purchase", CountingMethod: "ONCE_PER_EVENT", Value: "required",
+		Parameters: []string{"value", "currency", "plan_type", "coupon_code"},
+		Audiences:  []string{"Pro buyers"}, Priority: "high", Owner: "checkout team",
+	}, p.Events[0])
+	assert.Equal(t, "default 40 EUR", p.Events[1].Value)
+	assert.Empty(t, p.Events[2].Value)
+
+	byName := map[string]Parameter{}
+	var order []string
+	for _, pm := range p.Parameters {
+		byName[pm.Name] = pm
+		order = append(order, pm.Name)
+	}
+	assert.Equal(t, []string{"plan_type", "items_count", "customer_tier", "value", "currency", "coupon_code"}, order,
+		"event scope, user scope, then built-in and unregistered")
+	assert.Equal(t, []string{"purchase", "book_demo"}, byName["plan_type"].Events)
+	assert.Equal(t, []string{"Pro buyers"}, byName["plan_type"].Audiences)
+	assert.Equal(t, "EVENT", byName["plan_type"].Scope)
+	assert.Equal(t, []string{"Items per purchase"}, byName["items_count"].CalculatedMetrics)
+	assert.Empty(t, byName["items_count"].Audiences, "items_count_total is another name")
+	assert.Equal(t, KindBuiltIn, byName["value"].Kind)
+	assert.True(t, byName["coupon_code"].Unregistered)
+
+	assert.Equal(t, []string{"purchase"}, p.Audiences[0].Events)
+	assert.Equal(t, []string{"plan_type"}, p.Audiences[0].Parameters)
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Markdown(&buf, Build(testConfig(), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))))
+	md := buf.String()
+	assert.Contains(t, md, "# Measurement plan: Example\n\nGA4 property: 123. Web stream: G-TEST.\n")
+	assert.Contains(t, md, "| purchase | — | ONCE_PER_EVENT | required | value, currency, plan_type, coupon_code | Pro buyers | high | checkout team |\n")
+	assert.Contains(t, md, "| items_count | metric: Items (STANDARD) | EVENT | — | — | Items per purchase | — | — |\n")
+	assert.Contains(t, md, "| customer_tier | dimension: Customer Tier | USER | — | — | — | — | crm |\n")
+	assert.Contains(t, md, "so not reported: coupon_code.\n")
+	assert.Contains(t, md, "| Pro buyers | — | 30 days | purchase with plan_type = pro | purchase | plan_type |\n")
+}
+
+func TestHTML_EscapesConfigText(t *testing.T) {
+	cfg := testConfig()
+	cfg.Audiences[0].Conditions = []string{"<script>alert(1)</script>"}
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, Build(cfg, time.Now())))
+	assert.Contains(t, buf.String(), "<h2>Parameters</h2>")
+	assert.NotContains(t, buf.String(), "<script>alert")
+}
+
+func TestMarkdown_EmptyConfig(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Markdown(&buf, Build(&config.ProjectConfig{}, time.Now())))
+	assert.Contains(t, buf.String(), "No conversions in the config.")
+	assert.Contains(t, buf.String(), "No audiences in the config.")
+}
@@ -0,0 +1,204 @@
+package plandoc
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// none fills empty cells.
+const none = "—"
+
+// view is a Plan formatted for the templates.
+type view struct {
+	Title         string
+	PropertyID    string
+	MeasurementID string
+	GeneratedAt   string
+	Events        table
+	Parameters    table
+	Audiences     table
+	Unregistered  []string
+}
+
+// table is a section's header row and rows.
+type table struct {
+	Head []string
+	Rows [][]string
+}
+
+func newView(p *Plan) view {
+	v := view{
+		Title:         "Measurement plan",
+		PropertyID:    p.PropertyID,
+		MeasurementID: p.MeasurementID,
+		GeneratedAt:   p.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"),
+		Events:        table{Head: []string{"Event", "Description", "Counting", "Value", "Parameters", "Audiences", "Priority", "Owner"}},
+		Parameters:    table{Head: []string{"Parameter", "Registered as", "Scope", "Description", "Sent with", "Used by", "Priority", "Owner"}},
+		Audiences:     table{Head: []string{"Audience", "Category", "Duration", "Definition", "Events", "Parameters"}},
+	}
+	if p.Project != "" {
+		v.Title += ": " + p.Project
+	}
+	for _, e := range p.Events {
+		v.Events.Rows = append(v.Events.Rows, []string{
+			e.Name, e.Description, e.CountingMethod, e.Value,
+			list(e.Parameters), list(e.Audiences), e.Priority, e.Owner,
+		})
+	}
+	for _, pm := range p.Parameters {
+		v.Parameters.Rows = append(v.Parameters.Rows, []string{
+			pm.Name, registeredAs(pm), pm.Scope, pm.Description,
+			list(pm.Events), list(slices.Concat(pm.Audiences, pm.CalculatedMetrics)),
+			pm.Priority, pm.Owner,
+		})
+		if pm.Unregistered {
+			v.Unregistered = append(v.Unregistered, pm.Name)
+		}
+	}
+	for _, a := range p.Audiences {
+		duration := ""
+		if a.Duration > 0 {
+			duration = strconv.Itoa(a.Duration) + " days"
+		}
+		v.Audiences.Rows = append(v.Audiences.Rows, []string{
+			a.Name, a.Category, duration, definition(a),
+			list(a.Events), list(a.Parameters),
+		})
+	}
+	for _, t := range []table{v.Events, v.Parameters, v.Audiences} {
+		for _, row := range t.Rows {
+			for i := range row {
+				if row[i] == "" {
+					row[i] = none
+				}
+			}
+		}
+	}
+	return v
+}
+
+func registeredAs(p Parameter) string {
+	switch {
+	case p.Unregistered:
+		return "not registered"
+	case p.Kind == KindBuiltIn:
+		return KindBuiltIn
+	case p.Kind == KindMetric && p.Unit != "":
+		return "metric: " + p.DisplayName + " (" + p.Unit + ")"
+	}
+	return p.Kind + ": " + p.DisplayName
+}
+
+// definition is an audience's description followed by its conditions.
+func definition(a Audience) string {
+	parts := a.Conditions
+	if a.Description != "" {
+		parts = append([]string{a.Description}, parts...)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func list(items []string) string {
+	return strings.Join(items, ", ")
+}
+
+// cell escapes a markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+var markdownTemplate = template.Must(template.New("plan.md").Funcs(template.FuncMap{
+	"cell": cell,
+}).Parse(`{{define "table"}}
+|{{range .Head}} {{.}} |{{end}}
+|{{range .Head}} --- |{{end}}
+{{range .Rows}}|{{range .}} {{cell .}} |{{end}}
+{{end}}{{end}}# {{.Title}}
+{{if .PropertyID}}
+GA4 property: {{.PropertyID}}.{{if .MeasurementID}} Web stream: {{.MeasurementID}}.{{end}}
+{{end}}
+## Events
+{{if .Events.Rows}}{{template "table" .Events}}{{else}}
+No conversions in the config.
+{{end}}
+## Parameters
+{{if .Parameters.Rows}}{{template "table" .Parameters}}{{else}}
+No custom dimensions or metrics in the config.
+{{end}}{{if .Unregistered}}
+Not registered as custom dimensions or metrics, so not reported: {{range $i, $p := .Unregistered}}{{if $i}}, {{end}}{{$p}}{{end}}.
+{{end}}
+## Audiences
+{{if .Audiences.Rows}}{{template "table" .Audiences}}{{else}}
+No audiences in the config.
+{{end}}
+_Generated {{.GeneratedAt}} by ga4 generate plan. Edit the config, not this file._
+`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("plan.html").Parse(`{{define "table"}}
+<table>
+<tr>{{range .Head}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; color: #1f2933; max-width: 1200px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { font-size: 1.6rem; margin-bottom: .25rem; }
+h2 { font-size: 1.15rem; margin-top: 2rem; border-bottom: 1px solid #e4e7eb; padding-bottom: .25rem; }
+.property { color: #616e7c; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; font-size: .9rem; }
+th, td { padding: .4rem .6rem; border-bottom: 1px solid #e4e7eb; text-align: left; vertical-align: top; }
+td:first-child { font-family: ui-monospace, Menlo, monospace; }
+th { color: #616e7c; font-weight: 600; }
+.bad { color: #c62828; }
+footer { color: #9aa5b1; font-size: .8rem; margin-top: 2.5rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .PropertyID}}
+<p class="property">GA4 property: {{.PropertyID}}.{{if .MeasurementID}} Web stream: {{.MeasurementID}}.{{end}}</p>
+{{- end}}
+
+<h2>Events</h2>
+{{- if .Events.Rows}}{{template "table" .Events}}{{else}}
+<p>No conversions in the config.</p>
+{{- end}}
+
+<h2>Parameters</h2>
+{{- if .Parameters.Rows}}{{template "table" .Parameters}}{{else}}
+<p>No custom dimensions or metrics in the config.</p>
+{{- end}}
+{{- if .Unregistered}}
+<p class="bad">Not registered as custom dimensions or metrics, so not reported: {{range $i, $p := .Unregistered}}{{if $i}}, {{end}}{{$p}}{{end}}.</p>
+{{- end}}
+
+<h2>Audiences</h2>
+{{- if .Audiences.Rows}}{{template "table" .Audiences}}{{else}}
+<p>No audiences in the config.</p>
+{{- end}}
+
+<footer>Generated {{.GeneratedAt}} by ga4 generate plan. Edit the config, not this file.</footer>
+</body>
+</html>
+`))
+
+// Markdown writes p as a markdown document.
+func Markdown(w io.Writer, p *Plan) error {
+	return markdownTemplate.Execute(w, newView(p))
+}
+
+// HTML writes p as a standalone HTML page.
+func HTML(w io.Writer, p *Plan) error {
+	return htmlTemplate.Execute(w, newView(p))
+}
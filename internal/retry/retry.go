@@ -0,0 +1,116 @@
+// Package retry re-runs API calls that failed with a transient error: rate
+// limiting (429) and server errors (500, 502, 503, 504). Waits grow
+// exponentially with jitter, and a Retry-After header sent by Google takes
+// precedence. The GA4 and GSC clients route every request through Do.
+package retry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Policy says how often and how patiently a call is retried.
+type Policy struct {
+	MaxRetries int           // retries after the first attempt; 0 disables
+	BaseDelay  time.Duration // wait before the first retry, doubled for each further one
+	MaxDelay   time.Duration // cap on the computed wait (not on Retry-After)
+}
+
+// Transient reports whether err is worth retrying and, when the server said
+// so, how long to wait first.
+func Transient(err error) (bool, time.Duration) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false, 0
+	}
+	switch apiErr.Code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, retryAfter(apiErr.Header.Get("Retry-After"), time.Now())
+	}
+	return false, 0
+}
+
+// retryAfter parses a Retry-After header: delay seconds or an HTTP date.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// backoff is the wait before retry n (1-based): BaseDelay·2^(n-1) capped at
+// MaxDelay, then drawn from its upper half so clients hitting the same quota
+// do not retry in lockstep.
+func (p Policy) backoff(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// sleep waits d or until ctx ends; tests replace it.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Do runs fn, retrying transient failures according to p. Every retry is
+// logged as a warning naming operation and resource. A wait that would
+// outlast ctx's deadline is not started: the last error is returned at once.
+func Do(ctx context.Context, p Policy, logger *slog.Logger, operation, resource string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > p.MaxRetries {
+			return err
+		}
+		ok, wait := Transient(err)
+		if !ok {
+			return err
+		}
+		if wait == 0 {
+			wait = p.backoff(attempt)
+		}
+		if deadline, has := ctx.Deadline(); has && time.Until(deadline) < wait {
+			return err
+		}
+
+		logger.Warn("retrying API call",
+			slog.String("operation", operation),
+			slog.String("resource", resource),
+			slog.Int("attempt", attempt),
+			slog.Int("max_retries", p.MaxRetries),
+			slog.Duration("delay", wait),
+			slog.String("error", err.Error()),
+		)
+		if sleep(ctx, wait) != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// recordSleeps replaces sleep with one that returns at once and records the
+// requested waits.
+func recordSleeps(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &waits
+}
+
+// failing returns fn failing with errs in turn, then succeeding.
+func failing(calls *int, errs ...error) func() error {
+	return func() error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestDo_RetriesTransientErrors(t *testing.T) {
+	waits := recordSleeps(t)
+	p := Policy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+
+	var calls int
+	err := Do(context.Background(), p, discard, "list conversions", "properties/1", failing(&calls,
+		&googleapi.Error{Code: http.StatusServiceUnavailable},
+		fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusTooManyRequests}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.Len(t, *waits, 2)
+	assert.True(t, (*waits)[0] >= 500*time.Millisecond && (*waits)[0] <= time.Second, (*waits)[0])
+	assert.True(t, (*waits)[1] >= time.Second && (*waits)[1] <= 2*time.Second, (*waits)[1])
+}
+
+func TestDo_GivesUp(t *testing.T) {
+	recordSleeps(t)
+	unavailable := &googleapi.Error{Code: http.StatusServiceUnavailable}
+
+	var calls int
+	err := Do(context.Background(), Policy{MaxRetries: 2, BaseDelay: time.Millisecond}, discard, "op", "", failing(&calls, unavailable, unavailable, unavailable, unavailable))
+	assert.Same(t, unavailable, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	forbidden := &googleapi.Error{Code: http.StatusForbidden}
+	err = Do(context.Background(), Policy{MaxRetries: 2}, discard, "op", "", failing(&calls, forbidden))
+	assert.Same(t, forbidden, err)
+	assert.Equal(t, 1, calls, "permanent errors are not retried")
+
+	calls = 0
+	err = Do(context.Background(), Policy{}, discard, "op", "", failing(&calls, unavailable))
+	assert.Same(t, unavailable, err)
+	assert.Equal(t, 1, calls, "MaxRetries 0 disables retries")
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	waits := recordSleeps(t)
+	limited := &googleapi.Error{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"7"}}}
+
+	var calls int
+	err := Do(context.Background(), Policy{MaxRetries: 1, BaseDelay: time.Second, MaxDelay: 2 * time.Second}, discard, "op", "", failing(&calls, limited))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+}
+
+func TestDo_StopsBeforeRunDeadline(t *testing.T) {
+	waits := recordSleeps(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	limited := &googleapi.Error{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}}
+
+	var calls int
+	err := Do(ctx, Policy{MaxRetries: 3}, discard, "op", "", failing(&calls, limited))
+	assert.Same(t, limited, err)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, *waits)
+}
+
+func TestTransient(t *testing.T) {
+	for code, want := range map[int]bool{429: true, 500: true, 502: true, 503: true, 504: true, 400: false, 403: false, 404: false, 409: false} {
+		got, _ := Transient(&googleapi.Error{Code: code})
+		assert.Equal(t, want, got, code)
+	}
+	got, _ := Transient(errors.New("dial tcp: connection refused"))
+	assert.False(t, got)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30*time.Second, retryAfter("30", now))
+	assert.Equal(t, 90*time.Second, retryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, retryAfter("", now))
+	assert.Zero(t, retryAfter("soon", now))
+	assert.Zero(t, retryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+	for n := 1; n <= 10; n++ {
+		d := p.backoff(n)
+		assert.True(t, d <= 4*time.Second, "retry %d waited %s", n, d)
+	}
+	assert.True(t, p.backoff(10) >= 2*time.Second)
+}
@@ -0,0 +1,325 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+)
+
+// DeepPageDepth is the click depth beyond which a page counts as deep: more
+// than four clicks from the start page, where crawlers visit less often.
+const DeepPageDepth = 4
+
+// CrawledPage is one URL the crawl fetched.
+type CrawledPage struct {
+	URL    string `json:"url"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Depth is the minimum number of clicks from the start page.
+	Depth   int `json:"depth"`
+	Inlinks int `json:"inlinks"`
+	// Blocked is set when robots.txt disallows the URL; it was not fetched.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// Broken reports whether fetching the page failed or returned 4xx/5xx.
+func (p CrawledPage) Broken() bool {
+	return !p.Blocked && (p.Error != "" || p.Status >= 400)
+}
+
+// BrokenLink is a broken internal URL and the pages that link to it.
+type BrokenLink struct {
+	URL     string   `json:"url"`
+	Status  int      `json:"status,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Sources []string `json:"sources"`
+}
+
+// LinkGraph is the internal link graph of one origin.
+type LinkGraph struct {
+	Start string        `json:"start"`
+	Pages []CrawledPage `json:"pages"`
+	// Truncated is set when the crawl stopped at its page limit with links
+	// still unvisited.
+	Truncated bool `json:"truncated,omitempty"`
+	// Links maps each page to the distinct internal URLs it links to.
+	Links map[string][]string `json:"-"`
+}
+
+// Deep returns the pages more than depth clicks from the start page,
+// deepest first.
+func (g *LinkGraph) Deep(depth int) []CrawledPage {
+	var out []CrawledPage
+	for _, p := range g.Pages {
+		if p.Depth > depth && !p.Broken() {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Depth > out[j].Depth })
+	return out
+}
+
+// BrokenLinks returns every broken internal URL with its linking pages.
+func (g *LinkGraph) BrokenLinks() []BrokenLink {
+	sources := make(map[string][]string)
+	for from, targets := range g.Links {
+		for _, to := range targets {
+			sources[to] = append(sources[to], from)
+		}
+	}
+	var out []BrokenLink
+	for _, p := range g.Pages {
+		if !p.Broken() {
+			continue
+		}
+		s := sources[p.URL]
+		sort.Strings(s)
+		out = append(out, BrokenLink{URL: p.URL, Status: p.Status, Error: p.Error, Sources: s})
+	}
+	return out
+}
+
+// Orphans returns the URLs in known that the crawl never reached through an
+// internal link, in the order given. URLs on another origin are ignored.
+func (g *LinkGraph) Orphans(known []string) []string {
+	origin := originOf(g.Start)
+	linked := make(map[string]bool, len(g.Pages))
+	for _, p := range g.Pages {
+		linked[p.URL] = true
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, k := range known {
+		u, ok := normalizeLink(k)
+		if !ok || originOf(u) != origin || linked[u] || seen[u] {
+			continue
+		}
+		seen[u] = true
+		out = append(out, u)
+	}
+	return out
+}
+
+// Crawler walks the internal links of one origin breadth first. The zero
+// value is not usable; call NewCrawler.
+type Crawler struct {
+	client      *http.Client
+	userAgent   string
+	maxPages    int
+	concurrency int
+}
+
+// NewCrawler builds a Crawler that fetches at most maxPages URLs with
+// concurrency requests in flight. An empty userAgent is sent as Go's
+// default.
+func NewCrawler(timeout time.Duration, userAgent string, maxPages, concurrency int) *Crawler {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Crawler{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent:   userAgent,
+		maxPages:    maxPages,
+		concurrency: concurrency,
+	}
+}
+
+// Crawl fetches start and every same-origin page reachable from it through
+// <a href> links and redirects, level by level so each page's depth is its
+// shortest click path. When robots is not nil, URLs it disallows for
+// crawler are recorded as blocked and not fetched. Fetch failures are
+// recorded on the page; the error is only for a bad start URL.
+func (c *Crawler) Crawl(ctx context.Context, start string, robots *Robots, crawler string) (*LinkGraph, error) {
+	startURL, ok := normalizeLink(start)
+	if !ok {
+		return nil, fmt.Errorf("invalid start URL %q: want an absolute http(s) URL", start)
+	}
+	origin := originOf(startURL)
+	g := &LinkGraph{Start: startURL, Links: make(map[string][]string)}
+	index := map[string]int{startURL: 0}
+	g.Pages = append(g.Pages, CrawledPage{URL: startURL})
+
+	level := []string{startURL}
+	for depth := 0; len(level) > 0; depth++ {
+		results := make([]fetchResult, len(level))
+		sem := make(chan struct{}, c.concurrency)
+		var wg sync.WaitGroup
+		for i, u := range level {
+			if robots != nil && !robots.Test(crawler, u).Allowed {
+				results[i] = fetchResult{blocked: true}
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, u string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = c.fetch(ctx, u)
+			}(i, u)
+		}
+		wg.Wait()
+
+		var next []string
+		for i, u := range level {
+			r := results[i]
+			p := &g.Pages[index[u]]
+			p.Status, p.Blocked = r.status, r.blocked
+			if r.err != nil {
+				p.Error = r.err.Error()
+			}
+			seen := make(map[string]bool)
+			for _, link := range r.links {
+				if originOf(link) != origin || seen[link] || link == u {
+					continue
+				}
+				seen[link] = true
+				g.Links[u] = append(g.Links[u], link)
+				if j, ok := index[link]; ok {
+					g.Pages[j].Inlinks++
+					continue
+				}
+				if len(g.Pages) >= c.maxPages {
+					g.Truncated = true
+					continue
+				}
+				index[link] = len(g.Pages)
+				g.Pages = append(g.Pages, CrawledPage{URL: link, Depth: depth + 1, Inlinks: 1})
+				next = append(next, link)
+			}
+		}
+		level = next
+	}
+	return g, nil
+}
+
+type fetchResult struct {
+	status  int
+	err     error
+	blocked bool
+	links   []string
+}
+
+// fetch gets one URL and returns its outgoing links: the Location of a
+// redirect, or the <a href> targets of an HTML page.
+func (c *Crawler) fetch(ctx context.Context, rawURL string) fetchResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fetchResult{err: err}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fetchResult{err: err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	r := fetchResult{status: resp.StatusCode}
+	base, _ := url.Parse(rawURL)
+	if audit.IsRedirect(resp.StatusCode) {
+		if loc, err := url.Parse(resp.Header.Get("Location")); err == nil {
+			if link, ok := normalizeLink(base.ResolveReference(loc).String()); ok {
+				r.links = []string{link}
+			}
+		}
+		return r
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode != http.StatusOK || (mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return r
+	}
+	r.links = extractLinks(io.LimitReader(resp.Body, MaxPageBytes), base)
+	return r
+}
+
+// extractLinks returns the absolute http(s) targets of the document's
+// <a href> elements, resolved against <base href> when present. Links
+// marked rel=nofollow are skipped, as Googlebot does not follow them for
+// discovery.
+func extractLinks(r io.Reader, base *url.URL) []string {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil
+	}
+	var hrefs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if b, err := url.Parse(strings.TrimSpace(attr(n, "href"))); err == nil && attr(n, "href") != "" {
+					base = base.ResolveReference(b)
+				}
+			case "a":
+				rel := strings.Fields(strings.ToLower(attr(n, "rel")))
+				if hasAttr(n, "href") && !slices.Contains(rel, "nofollow") {
+					hrefs = append(hrefs, strings.TrimSpace(attr(n, "href")))
+				}
+			}
+		}
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			walk(ch)
+		}
+	}
+	walk(doc)
+
+	var links []string
+	for _, h := range hrefs {
+		ref, err := url.Parse(h)
+		if err != nil {
+			continue
+		}
+		if link, ok := normalizeLink(base.ResolveReference(ref).String()); ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// normalizeLink drops the fragment, lowercases scheme and host and gives an
+// empty path "/". It reports false for anything but an absolute http(s) URL.
+func normalizeLink(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment, u.RawFragment = "", ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), true
+}
+
+func originOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,135 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkSite serves pages whose bodies link to the given paths.
+func linkSite(t *testing.T, pages map[string][]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		case "/file.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = fmt.Fprint(w, `<a href="/never">not html</a>`)
+			return
+		}
+		links, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		var b strings.Builder
+		b.WriteString("<html><body>")
+		for _, l := range links {
+			fmt.Fprintf(&b, "%s\n", l)
+		}
+		b.WriteString("</body></html>")
+		_, _ = fmt.Fprint(w, b.String())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func a(href string) string { return `<a href="` + href + `">x</a>` }
+
+func pageByURL(g *LinkGraph) map[string]CrawledPage {
+	out := make(map[string]CrawledPage)
+	for _, p := range g.Pages {
+		out[p.URL] = p
+	}
+	return out
+}
+
+func TestCrawl(t *testing.T) {
+	srv := linkSite(t, map[string][]string{
+		"/":        {a("/a"), a("/b#top"), a("https://elsewhere.example/"), a("mailto:x@example.com"), `<a href="/private" rel="nofollow">x</a>`},
+		"/a":       {a("/b"), a("/c"), a("/missing"), a("/old")},
+		"/b":       {a("/a"), a("/file.pdf")},
+		"/c":       {a("/c/d")},
+		"/c/d":     {a("/c/d/e")},
+		"/c/d/e":   {a("/c/d/e/f")},
+		"/c/d/e/f": {a("/")},
+		"/new":     {},
+	})
+
+	g, err := NewCrawler(5*time.Second, "", 100, 3).Crawl(context.Background(), srv.URL, nil, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, srv.URL+"/", g.Start)
+	assert.False(t, g.Truncated)
+	pages := pageByURL(g)
+	assert.Len(t, pages, 11)
+	assert.NotContains(t, pages, srv.URL+"/private", "nofollow is not followed")
+	assert.NotContains(t, pages, srv.URL+"/never", "links in non-HTML are ignored")
+
+	assert.Equal(t, 1, pages[srv.URL+"/b"].Depth)
+	assert.Equal(t, 2, pages[srv.URL+"/b"].Inlinks)
+	assert.Equal(t, 1, pages[srv.URL+"/"].Inlinks)
+	assert.Equal(t, 3, pages[srv.URL+"/new"].Depth, "reached through the redirect")
+	assert.Equal(t, http.StatusMovedPermanently, pages[srv.URL+"/old"].Status)
+
+	deep := g.Deep(DeepPageDepth)
+	require.Len(t, deep, 1)
+	assert.Equal(t, srv.URL+"/c/d/e/f", deep[0].URL)
+	assert.Equal(t, 5, deep[0].Depth)
+
+	broken := g.BrokenLinks()
+	require.Len(t, broken, 1)
+	assert.Equal(t, BrokenLink{URL: srv.URL + "/missing", Status: http.StatusNotFound, Sources: []string{srv.URL + "/a"}}, broken[0])
+
+	orphans := g.Orphans([]string{srv.URL + "/a", srv.URL + "/landing", srv.URL + "/landing#x", "https://elsewhere.example/z"})
+	assert.Equal(t, []string{srv.URL + "/landing"}, orphans)
+}
+
+func TestCrawl_MaxPagesAndRobots(t *testing.T) {
+	srv := linkSite(t, map[string][]string{
+		"/":  {a("/a"), a("/admin/"), a("/b"), a("/c")},
+		"/a": {},
+		"/b": {},
+	})
+	robots := ParseRobots(strings.NewReader("User-agent: *\nDisallow: /admin/\n"))
+
+	g, err := NewCrawler(5*time.Second, "", 3, 1).Crawl(context.Background(), srv.URL+"/", robots, "Googlebot")
+	require.NoError(t, err)
+	assert.True(t, g.Truncated)
+	pages := pageByURL(g)
+	assert.Len(t, pages, 3)
+	assert.True(t, pages[srv.URL+"/admin/"].Blocked)
+	assert.Zero(t, pages[srv.URL+"/admin/"].Status)
+	assert.Empty(t, g.BrokenLinks(), "blocked pages are not broken")
+}
+
+func TestCrawl_InvalidStart(t *testing.T) {
+	_, err := NewCrawler(time.Second, "", 10, 1).Crawl(context.Background(), "example.com", nil, "")
+	assert.ErrorContains(t, err, "invalid start URL")
+}
+
+func TestNormalizeLink(t *testing.T) {
+	for in, want := range map[string]string{
+		"HTTPS://Example.COM":        "https://example.com/",
+		"https://example.com/a#frag": "https://example.com/a",
+		"https://example.com/a?b=1":  "https://example.com/a?b=1",
+	} {
+		got, ok := normalizeLink(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"/relative", "mailto:x@example.com", "ftp://example.com/"} {
+		_, ok := normalizeLink(in)
+		assert.False(t, ok, in)
+	}
+}
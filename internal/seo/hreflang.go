@@ -0,0 +1,193 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// HreflangPage is one page fetched for the hreflang check.
+type HreflangPage struct {
+	URL      string `json:"url"`
+	FinalURL string `json:"final_url,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// Lang is the <html lang> attribute.
+	Lang       string      `json:"lang,omitempty"`
+	Alternates []Alternate `json:"hreflang,omitempty"`
+}
+
+// HreflangFinding is a problem between the pages of an hreflang set.
+type HreflangFinding struct {
+	URL string `json:"url"`
+	Issue
+}
+
+// FetchHreflang audits urls, then the alternates they declare that were not
+// among them, and so on until no new alternate turns up or maxPages pages
+// have been fetched. The result is keyed by normalized URL; truncated is
+// set when alternates were left unfetched.
+func FetchHreflang(ctx context.Context, auditor *PageAuditor, urls []string, maxPages, concurrency int) (pages map[string]*HreflangPage, truncated bool) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	pages = make(map[string]*HreflangPage)
+	var queue []string
+	enqueue := func(raw string) {
+		u, ok := normalizeLink(raw)
+		if !ok || pages[u] != nil {
+			return
+		}
+		if len(pages) >= maxPages {
+			truncated = true
+			return
+		}
+		pages[u] = &HreflangPage{URL: u}
+		queue = append(queue, u)
+	}
+	for _, u := range urls {
+		enqueue(u)
+	}
+
+	for len(queue) > 0 {
+		batch := queue
+		queue = nil
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for _, u := range batch {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(hp *HreflangPage) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				p, err := auditor.Audit(ctx, hp.URL)
+				if err != nil {
+					hp.Error = err.Error()
+					return
+				}
+				hp.FinalURL, hp.Status, hp.Lang, hp.Alternates = p.FinalURL, p.Status, p.Lang, p.Alternates
+			}(pages[u])
+		}
+		wg.Wait()
+		for _, u := range batch {
+			for _, alt := range pages[u].Alternates {
+				if isAbsoluteURL(alt.Href) {
+					enqueue(alt.Href)
+				}
+			}
+		}
+	}
+	return pages, truncated
+}
+
+// CheckHreflangSet checks the hreflang annotations of pages against each
+// other. Every alternate must answer 200 without redirecting and link back
+// with a return tag, each annotated page should declare an x-default, and
+// the hreflang language should agree with the target's <html lang> and any
+// language folder in its URL. Annotations whose target is not in pages are
+// not checked. Findings are ordered by URL.
+func CheckHreflangSet(pages map[string]*HreflangPage) []HreflangFinding {
+	keys := make([]string, 0, len(pages))
+	for k := range pages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []HreflangFinding
+	// Language mismatches are reported once per target and code, against
+	// the target.
+	mismatched := make(map[string]bool)
+	for _, k := range keys {
+		p := pages[k]
+		if len(p.Alternates) == 0 || p.Error != "" || p.Status != http.StatusOK || p.FinalURL != "" {
+			continue
+		}
+		add := func(rule, severity, format string, args ...any) {
+			out = append(out, HreflangFinding{URL: p.URL, Issue: Issue{Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)}})
+		}
+
+		xDefault := false
+		for _, alt := range p.Alternates {
+			lang := strings.ToLower(alt.Lang)
+			if lang == "x-default" {
+				xDefault = true
+			}
+			target, ok := normalizeLink(alt.Href)
+			if !ok {
+				continue
+			}
+			t := pages[target]
+			if t == nil {
+				continue
+			}
+			switch {
+			case t.Error != "":
+				add("hreflang_target", SeverityError, "hreflang %s target %s could not be fetched: %s", alt.Lang, alt.Href, t.Error)
+				continue
+			case t.FinalURL != "":
+				add("hreflang_target", SeverityError, "hreflang %s target %s redirects to %s; annotate the final URL", alt.Lang, alt.Href, t.FinalURL)
+				continue
+			case t.Status != http.StatusOK:
+				add("hreflang_target", SeverityError, "hreflang %s target %s returns HTTP %d", alt.Lang, alt.Href, t.Status)
+				continue
+			}
+
+			if target != k && !linksBack(t, k) {
+				add("hreflang_return", SeverityError, "%s (hreflang %s) has no return tag to this page", alt.Href, alt.Lang)
+			}
+			if lang == "x-default" || !validHreflang(lang) || mismatched[target+" "+lang] {
+				continue
+			}
+			mismatched[target+" "+lang] = true
+			if t.Lang != "" && primaryLang(t.Lang) != primaryLang(lang) {
+				out = append(out, HreflangFinding{URL: t.URL, Issue: Issue{Rule: "hreflang_language", Severity: SeverityWarning,
+					Message: fmt.Sprintf("annotated as hreflang %s but <html lang> is %q", alt.Lang, t.Lang)}})
+			}
+			if folder := urlLanguage(t.URL); folder != "" && primaryLang(folder) != primaryLang(lang) {
+				out = append(out, HreflangFinding{URL: t.URL, Issue: Issue{Rule: "hreflang_language", Severity: SeverityWarning,
+					Message: fmt.Sprintf("annotated as hreflang %s but sits in the /%s/ folder", alt.Lang, folder)}})
+			}
+		}
+		if !xDefault {
+			add("hreflang_x_default", SeverityWarning, "hreflang set has no x-default")
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].URL < out[j].URL })
+	return out
+}
+
+// linksBack reports whether t declares an alternate pointing at the
+// normalized URL u.
+func linksBack(t *HreflangPage, u string) bool {
+	for _, alt := range t.Alternates {
+		if href, ok := normalizeLink(alt.Href); ok && href == u {
+			return true
+		}
+	}
+	return false
+}
+
+func primaryLang(code string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(code), "-")
+	return lang
+}
+
+// urlLanguage returns the first path segment of rawURL when it looks like a
+// language or language-region code ("/es/", "/en-gb/"), else "". Script
+// subtags are not considered: "/my-blog/" is a slug, not Burmese.
+func urlLanguage(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	seg, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	seg = strings.ToLower(strings.ReplaceAll(seg, "_", "-"))
+	if (len(seg) != 2 && (len(seg) != 5 || seg[2] != '-')) || !validHreflang(seg) {
+		return ""
+	}
+	return seg
+}
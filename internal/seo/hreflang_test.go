@@ -0,0 +1,90 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hreflangSite serves pages with the given <html lang> and hreflang
+// annotations; "{base}" in an href is replaced with the server URL.
+func hreflangSite(t *testing.T, pages map[string]struct {
+	lang string
+	alts [][2]string
+}) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/fr/", http.StatusMovedPermanently)
+			return
+		}
+		p, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, `<html lang="%s"><head><title>t</title>`, p.lang)
+		for _, alt := range p.alts {
+			fmt.Fprintf(&b, `<link rel="alternate" hreflang="%s" href="%s">`, alt[0], strings.ReplaceAll(alt[1], "{base}", srv.URL))
+		}
+		b.WriteString("</head><body></body></html>")
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, b.String())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHreflangSet(t *testing.T) {
+	type page = struct {
+		lang string
+		alts [][2]string
+	}
+	srv := hreflangSite(t, map[string]page{
+		"/en/": {"en", [][2]string{{"en", "{base}/en/"}, {"es", "{base}/es/"}, {"de", "{base}/de/"}, {"fr", "{base}/moved"}, {"it", "{base}/it/"}, {"x-default", "{base}/en/"}}},
+		"/es/": {"es", [][2]string{{"es", "{base}/es/"}, {"en", "{base}/en/"}, {"x-default", "{base}/en/"}}},
+		"/de/": {"en", [][2]string{{"de", "{base}/de/"}}},
+		"/fr/": {"fr", nil},
+	})
+
+	pages, truncated := FetchHreflang(context.Background(), NewPageAuditor(5*time.Second, ""), []string{srv.URL + "/en/"}, 10, 2)
+	assert.False(t, truncated)
+	require.Len(t, pages, 5, "alternates are fetched too")
+
+	var got []string
+	for _, f := range CheckHreflangSet(pages) {
+		got = append(got, strings.TrimPrefix(f.URL, srv.URL)+" "+f.Rule+": "+strings.ReplaceAll(f.Message, srv.URL, ""))
+	}
+	assert.Equal(t, []string{
+		`/de/ hreflang_language: annotated as hreflang de but <html lang> is "en"`,
+		`/de/ hreflang_x_default: hreflang set has no x-default`,
+		`/en/ hreflang_return: /de/ (hreflang de) has no return tag to this page`,
+		`/en/ hreflang_target: hreflang fr target /moved redirects to /fr/; annotate the final URL`,
+		`/en/ hreflang_target: hreflang it target /it/ returns HTTP 404`,
+	}, got)
+
+	_, truncated = FetchHreflang(context.Background(), NewPageAuditor(5*time.Second, ""), []string{srv.URL + "/en/"}, 2, 1)
+	assert.True(t, truncated)
+}
+
+func TestURLLanguage(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://example.com/es/post":   "es",
+		"https://example.com/en_GB/":    "en-gb",
+		"https://example.com/my-blog/":  "",
+		"https://example.com/blog/":     "",
+		"https://example.com/":          "",
+		"https://example.com/zh-hant/x": "",
+	} {
+		assert.Equal(t, want, urlLanguage(in), in)
+	}
+}
@@ -0,0 +1,472 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+)
+
+// MaxPageBytes caps how much of a page is read. Google indexes the first
+// 15 MB of an HTML file.
+const MaxPageBytes = 15 << 20
+
+// Issue severities. Errors keep a page out of the index or misreport it;
+// warnings cost visibility; info items are worth a look.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Length guidance for snippets, in characters. Google truncates by pixel
+// width, so these are the usual safe ranges rather than hard limits.
+const (
+	TitleMinLength       = 30
+	TitleMaxLength       = 60
+	DescriptionMinLength = 70
+	DescriptionMaxLength = 160
+)
+
+// Issue is one rule finding on a page.
+type Issue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Alternate is one <link rel="alternate" hreflang> annotation.
+type Alternate struct {
+	Lang string `json:"hreflang"`
+	Href string `json:"href"`
+}
+
+// Page is what the audit extracted from a fetched page, and the issues found.
+type Page struct {
+	URL              string            `json:"url"`
+	FinalURL         string            `json:"final_url,omitempty"`
+	Status           int               `json:"status"`
+	Redirects        *RedirectChain    `json:"redirects,omitempty"`
+	MetaRefresh      string            `json:"meta_refresh,omitempty"`
+	Lang             string            `json:"lang,omitempty"`
+	Titles           []string          `json:"titles,omitempty"`
+	Description      string            `json:"meta_description,omitempty"`
+	Canonicals       []string          `json:"canonicals,omitempty"`
+	H1               []string          `json:"h1,omitempty"`
+	Robots           []string          `json:"robots,omitempty"`
+	Alternates       []Alternate       `json:"hreflang,omitempty"`
+	OpenGraph        map[string]string `json:"open_graph,omitempty"`
+	Images           int               `json:"images"`
+	ImagesMissingAlt int               `json:"images_missing_alt"`
+	Issues           []Issue           `json:"issues"`
+}
+
+// Errors returns the number of error-severity issues.
+func (p *Page) Errors() int {
+	n := 0
+	for _, i := range p.Issues {
+		if i.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// PageAuditor fetches and audits pages. Redirects are followed by hand, up
+// to MaxRedirectHops, so each hop can be checked. The zero value is not
+// usable; call NewPageAuditor.
+type PageAuditor struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewPageAuditor builds a PageAuditor with the given per-request timeout and
+// User-Agent. An empty userAgent is sent as Go's default.
+func NewPageAuditor(timeout time.Duration, userAgent string) *PageAuditor {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &PageAuditor{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: userAgent,
+	}
+}
+
+// Audit fetches rawURL, following HTTP and meta refresh redirects, and
+// checks the final page. An error means no response at all; a non-200 page
+// or a broken redirect chain is reported as an issue.
+func (a *PageAuditor) Audit(ctx context.Context, rawURL string) (*Page, error) {
+	chain := &RedirectChain{}
+	seen := map[string]bool{}
+	current := rawURL
+	var page *Page
+	for {
+		seen[current] = true
+		resp, err := a.get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		status := resp.StatusCode
+
+		var next string
+		isMetaRefresh := false
+		if audit.IsRedirect(status) {
+			_ = resp.Body.Close()
+			page = &Page{}
+			next = resp.Header.Get("Location")
+		} else {
+			page, err = ParsePage(io.LimitReader(resp.Body, MaxPageBytes))
+			_ = resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", current, err)
+			}
+			for _, v := range resp.Header.Values("X-Robots-Tag") {
+				page.Robots = append(page.Robots, v)
+			}
+			next, isMetaRefresh = page.MetaRefresh, page.MetaRefresh != ""
+		}
+		page.Status = status
+
+		if next != "" {
+			base, _ := url.Parse(current)
+			ref, err := url.Parse(next)
+			if err != nil {
+				if !isMetaRefresh {
+					return nil, fmt.Errorf("%s redirects to an unparseable Location %q", current, next)
+				}
+				next = ""
+			} else {
+				next = base.ResolveReference(ref).String()
+			}
+		}
+		if next == "" || (isMetaRefresh && sameURL(next, current)) {
+			break
+		}
+		if len(chain.Hops) == MaxRedirectHops {
+			chain.Truncated = true
+			break
+		}
+		chain.Hops = append(chain.Hops, Hop{Hop: audit.Hop{URL: current, Status: status, Location: next}, MetaRefresh: isMetaRefresh})
+		if seen[next] {
+			chain.Loop = true
+			break
+		}
+		current = next
+	}
+
+	page.URL = rawURL
+	if current != rawURL && !chain.Broken() {
+		page.FinalURL = current
+	}
+	if len(chain.Hops) > 0 {
+		page.Redirects = chain
+	}
+	page.Issues = CheckPage(page)
+	return page, nil
+}
+
+func (a *PageAuditor) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if a.userAgent != "" {
+		req.Header.Set("User-Agent", a.userAgent)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	return resp, nil
+}
+
+// ParsePage extracts the SEO-relevant elements of an HTML document. URL,
+// Status and Issues are left for the caller.
+func ParsePage(r io.Reader) (*Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &Page{OpenGraph: map[string]string{}}
+	var walk func(n *html.Node, inHead bool)
+	walk = func(n *html.Node, inHead bool) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				p.Lang = strings.TrimSpace(attr(n, "lang"))
+			case "head":
+				inHead = true
+			case "title":
+				if inHead {
+					p.Titles = append(p.Titles, strings.TrimSpace(textContent(n)))
+				}
+			case "meta":
+				name := strings.ToLower(attr(n, "name"))
+				property := strings.ToLower(attr(n, "property"))
+				content := strings.TrimSpace(attr(n, "content"))
+				switch {
+				case name == "description":
+					p.Description = content
+				case name == "robots" || name == "googlebot":
+					p.Robots = append(p.Robots, content)
+				case strings.EqualFold(attr(n, "http-equiv"), "refresh"):
+					p.MetaRefresh = metaRefreshURL(content)
+				case strings.HasPrefix(property, "og:"):
+					p.OpenGraph[property] = content
+				}
+			case "link":
+				rels := strings.Fields(strings.ToLower(attr(n, "rel")))
+				for _, rel := range rels {
+					switch rel {
+					case "canonical":
+						p.Canonicals = append(p.Canonicals, strings.TrimSpace(attr(n, "href")))
+					case "alternate":
+						if lang := attr(n, "hreflang"); lang != "" {
+							p.Alternates = append(p.Alternates, Alternate{Lang: strings.TrimSpace(lang), Href: strings.TrimSpace(attr(n, "href"))})
+						}
+					}
+				}
+			case "h1":
+				p.H1 = append(p.H1, strings.Join(strings.Fields(textContent(n)), " "))
+			case "img":
+				p.Images++
+				if !hasAttr(n, "alt") {
+					p.ImagesMissingAlt++
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inHead)
+		}
+	}
+	walk(doc, false)
+	return p, nil
+}
+
+// CheckPage applies the on-page rules to p.
+func CheckPage(p *Page) []Issue {
+	issues := []Issue{}
+	add := func(rule, severity, format string, args ...any) {
+		issues = append(issues, Issue{Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	checkRedirects(p, add)
+	if p.Redirects.Broken() {
+		return issues
+	}
+	base := p.URL
+	if p.FinalURL != "" {
+		base = p.FinalURL
+	}
+	if p.Status != 0 && p.Status != http.StatusOK {
+		add("status", SeverityError, "returns HTTP %d", p.Status)
+	}
+
+	switch {
+	case len(p.Titles) == 0 || p.Titles[0] == "":
+		add("title", SeverityError, "no <title>")
+	case len(p.Titles) > 1:
+		add("title", SeverityWarning, "%d <title> elements; Google uses one", len(p.Titles))
+	}
+	if len(p.Titles) > 0 && p.Titles[0] != "" {
+		switch n := runeLen(p.Titles[0]); {
+		case n < TitleMinLength:
+			add("title", SeverityWarning, "title is %d characters; aim for %d–%d", n, TitleMinLength, TitleMaxLength)
+		case n > TitleMaxLength:
+			add("title", SeverityWarning, "title is %d characters and may be truncated after ~%d", n, TitleMaxLength)
+		}
+	}
+
+	switch n := runeLen(p.Description); {
+	case n == 0:
+		add("meta_description", SeverityWarning, "no meta description; Google will pick a snippet from the page")
+	case n < DescriptionMinLength:
+		add("meta_description", SeverityWarning, "meta description is %d characters; aim for %d–%d", n, DescriptionMinLength, DescriptionMaxLength)
+	case n > DescriptionMaxLength:
+		add("meta_description", SeverityWarning, "meta description is %d characters and may be truncated after ~%d", n, DescriptionMaxLength)
+	}
+
+	switch len(p.Canonicals) {
+	case 0:
+		add("canonical", SeverityWarning, "no rel=canonical")
+	case 1:
+		canonical := p.Canonicals[0]
+		if !isAbsoluteURL(canonical) {
+			add("canonical", SeverityWarning, "canonical %q is not an absolute URL", canonical)
+		} else if base != "" && !sameURL(canonical, base) {
+			add("canonical", SeverityInfo, "canonicalised to %s", canonical)
+		}
+	default:
+		add("canonical", SeverityError, "%d rel=canonical links; Google ignores them all", len(p.Canonicals))
+	}
+
+	switch len(p.H1) {
+	case 0:
+		add("h1", SeverityWarning, "no <h1>")
+	case 1:
+	default:
+		add("h1", SeverityInfo, "%d <h1> elements", len(p.H1))
+	}
+
+	for _, directive := range robotsDirectives(p.Robots) {
+		switch directive {
+		case "noindex", "none":
+			add("robots", SeverityError, "robots %s: the page is excluded from the index", directive)
+		case "nofollow":
+			add("robots", SeverityWarning, "robots nofollow: links on the page are not followed")
+		}
+	}
+
+	checkHreflang(p, base, add)
+
+	for _, tag := range []string{"og:title", "og:description", "og:image"} {
+		if p.OpenGraph[tag] == "" {
+			add("open_graph", SeverityInfo, "no %s", tag)
+		}
+	}
+	if img := p.OpenGraph["og:image"]; img != "" && !isAbsoluteURL(img) {
+		add("open_graph", SeverityWarning, "og:image %q is not an absolute URL", img)
+	}
+
+	if p.ImagesMissingAlt > 0 {
+		add("image_alt", SeverityWarning, "%d of %d images have no alt attribute", p.ImagesMissingAlt, p.Images)
+	}
+	return issues
+}
+
+// checkHreflang validates hreflang annotations: language codes, absolute
+// hrefs, a self-reference and an x-default.
+func checkHreflang(p *Page, base string, add func(rule, severity, format string, args ...any)) {
+	if len(p.Alternates) == 0 {
+		return
+	}
+	seen := make(map[string]bool)
+	self, xDefault := false, false
+	for _, alt := range p.Alternates {
+		lang := strings.ToLower(alt.Lang)
+		if seen[lang] {
+			add("hreflang", SeverityWarning, "hreflang %q is declared more than once", alt.Lang)
+		}
+		seen[lang] = true
+		if lang == "x-default" {
+			xDefault = true
+		} else if !validHreflang(lang) {
+			add("hreflang", SeverityError, "hreflang %q is not a valid language(-region) code", alt.Lang)
+		}
+		if !isAbsoluteURL(alt.Href) {
+			add("hreflang", SeverityError, "hreflang %s href %q is not an absolute URL", alt.Lang, alt.Href)
+		} else if base != "" && sameURL(alt.Href, base) {
+			self = true
+		}
+	}
+	if !self {
+		add("hreflang", SeverityWarning, "hreflang set does not reference this page")
+	}
+	if !xDefault {
+		add("hreflang", SeverityInfo, "no x-default hreflang")
+	}
+}
+
+// validHreflang accepts ISO 639-1 language codes with an optional ISO 3166-1
+// alpha-2 region or ISO 15924 script (en, en-gb, zh-hant).
+func validHreflang(code string) bool {
+	lang, rest, hasRegion := strings.Cut(code, "-")
+	if len(lang) != 2 || !isLetters(lang) {
+		return false
+	}
+	if !hasRegion {
+		return true
+	}
+	return (len(rest) == 2 || len(rest) == 4) && isLetters(rest)
+}
+
+// robotsDirectives splits meta robots / X-Robots-Tag values into lowercase
+// directives. A "googlebot: noindex" header prefix is dropped.
+func robotsDirectives(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if agent, rest, ok := strings.Cut(v, ":"); ok && !strings.Contains(agent, ",") && !strings.Contains(agent, " ") {
+			v = rest
+		}
+		for _, d := range strings.Split(v, ",") {
+			if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+				out = append(out, d)
+			}
+		}
+	}
+	return out
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// sameURL compares two absolute URLs ignoring host case and the fragment.
+func sameURL(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	ua.Fragment, ub.Fragment = "", ""
+	ua.Host, ub.Host = strings.ToLower(ua.Host), strings.ToLower(ub.Host)
+	return ua.String() == ub.String()
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
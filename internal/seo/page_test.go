@@ -0,0 +1,125 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goodPage = `<!doctype html><html><head>
+<title>Compress images online without losing quality</title>
+<meta name="description" content="Shrink JPEG, PNG and WebP files in the browser. No upload, no sign-up, and results you can download straight away.">
+<link rel="canonical" href="%[1]s/">
+<link rel="alternate" hreflang="en" href="%[1]s/">
+<link rel="alternate" hreflang="es-ES" href="%[1]s/es/">
+<link rel="alternate" hreflang="x-default" href="%[1]s/">
+<meta property="og:title" content="Compress images">
+<meta property="og:description" content="Shrink images">
+<meta property="og:image" content="%[1]s/og.png">
+</head><body>
+<h1>Compress <em>images</em></h1>
+<img src="a.png" alt="Example"><img src="spacer.gif" alt="">
+</body></html>`
+
+func parse(t *testing.T, doc string) *Page {
+	t.Helper()
+	p, err := ParsePage(strings.NewReader(doc))
+	require.NoError(t, err)
+	return p
+}
+
+func rules(issues []Issue) map[string][]string {
+	out := make(map[string][]string)
+	for _, i := range issues {
+		out[i.Rule] = append(out[i.Rule], i.Severity+": "+i.Message)
+	}
+	return out
+}
+
+func TestParsePage(t *testing.T) {
+	p := parse(t, fmt.Sprintf(goodPage, "https://example.com"))
+
+	assert.Equal(t, []string{"Compress images online without losing quality"}, p.Titles)
+	assert.Equal(t, []string{"https://example.com/"}, p.Canonicals)
+	assert.Equal(t, []string{"Compress images"}, p.H1)
+	assert.Len(t, p.Alternates, 3)
+	assert.Equal(t, "https://example.com/og.png", p.OpenGraph["og:image"])
+	assert.Equal(t, 2, p.Images)
+	assert.Equal(t, 0, p.ImagesMissingAlt, "empty alt marks a decorative image")
+}
+
+func TestCheckPage_Clean(t *testing.T) {
+	p := parse(t, fmt.Sprintf(goodPage, "https://example.com"))
+	p.URL, p.Status = "https://example.com/", http.StatusOK
+	assert.Empty(t, CheckPage(p))
+}
+
+func TestCheckPage_Problems(t *testing.T) {
+	p := parse(t, `<html><head>
+<title>Short</title><title>Second</title>
+<meta name="robots" content="noindex, nofollow">
+<link rel="canonical" href="/a"><link rel="canonical" href="/b">
+<link rel="alternate" hreflang="english" href="/en/">
+<meta property="og:image" content="/og.png">
+</head><body><h1>A</h1><h1>B</h1><img src="x.png"><img src="y.png" alt="y"></body></html>`)
+	p.URL, p.Status = "https://example.com/page", http.StatusNotFound
+
+	got := rules(CheckPage(p))
+	assert.Equal(t, []string{"error: returns HTTP 404"}, got["status"])
+	assert.Len(t, got["title"], 2, "two titles, and the first is short")
+	assert.Contains(t, got["meta_description"][0], "no meta description")
+	assert.Contains(t, got["canonical"][0], "error: 2 rel=canonical")
+	assert.Equal(t, []string{"info: 2 <h1> elements"}, got["h1"])
+	assert.Equal(t, []string{
+		"error: robots noindex: the page is excluded from the index",
+		"warning: robots nofollow: links on the page are not followed",
+	}, got["robots"])
+	assert.Len(t, got["hreflang"], 4, "bad code, relative href, no self-reference, no x-default")
+	assert.Contains(t, got["open_graph"], `warning: og:image "/og.png" is not an absolute URL`)
+	assert.Equal(t, []string{"warning: 1 of 2 images have no alt attribute"}, got["image_alt"])
+}
+
+func TestValidHreflang(t *testing.T) {
+	for _, ok := range []string{"en", "en-gb", "zh-hant", "pt-br"} {
+		assert.True(t, validHreflang(ok), ok)
+	}
+	for _, bad := range []string{"eng", "en_gb", "en-usa", "uk-", "e1"} {
+		assert.False(t, validHreflang(bad), bad)
+	}
+}
+
+func TestRobotsDirectives(t *testing.T) {
+	assert.Equal(t, []string{"noindex", "follow"}, robotsDirectives([]string{"NoIndex, follow"}))
+	assert.Equal(t, []string{"noindex"}, robotsDirectives([]string{"googlebot: noindex"}))
+}
+
+func TestPageAuditor_Audit(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("X-Robots-Tag", "noindex")
+		_, _ = fmt.Fprintf(w, goodPage, srv.URL)
+	}))
+	defer srv.Close()
+
+	p, err := NewPageAuditor(5*time.Second, "").Audit(context.Background(), srv.URL+"/old")
+	require.NoError(t, err)
+
+	assert.Equal(t, srv.URL+"/", p.FinalURL)
+	assert.Equal(t, http.StatusOK, p.Status)
+	got := rules(p.Issues)
+	assert.Len(t, got["redirect"], 1)
+	assert.Equal(t, []string{"error: robots noindex: the page is excluded from the index"}, got["robots"])
+	assert.Empty(t, got["canonical"], "canonical matches the final URL")
+	assert.Equal(t, 1, p.Errors())
+}
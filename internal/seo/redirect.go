@@ -0,0 +1,138 @@
+package seo
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+)
+
+// MaxRedirectHops is how many redirects an audit follows before giving up.
+const MaxRedirectHops = 5
+
+// LongChainHops is the longest chain that is not flagged. Every hop costs
+// crawl budget and a round trip, and Google may stop following long chains.
+const LongChainHops = 3
+
+// Hop is one redirect, as the audit package's prober records it. MetaRefresh
+// marks an HTML <meta http-equiv="refresh"> redirect, which answers 200.
+type Hop struct {
+	audit.Hop
+	MetaRefresh bool `json:"meta_refresh,omitempty"`
+}
+
+// RedirectChain is the path from the requested URL to the audited page.
+// Loop is set when a hop points back at a URL already in the chain, and
+// Truncated when the chain was still redirecting after MaxRedirectHops; in
+// both cases no final page was reached.
+type RedirectChain struct {
+	Hops      []Hop `json:"hops"`
+	Loop      bool  `json:"loop,omitempty"`
+	Truncated bool  `json:"truncated,omitempty"`
+}
+
+// Broken reports whether the chain never reached a final page.
+func (c *RedirectChain) Broken() bool {
+	return c != nil && (c.Loop || c.Truncated)
+}
+
+// checkRedirects reports problems with the page's redirect chain. It is
+// called before the content rules, which are skipped for a broken chain.
+func checkRedirects(p *Page, add func(rule, severity, format string, args ...any)) {
+	c := p.Redirects
+	switch {
+	case c != nil && c.Loop:
+		urls := make([]string, 0, len(c.Hops)+1)
+		for _, h := range c.Hops {
+			urls = append(urls, h.URL)
+		}
+		add("redirect", SeverityError, "redirect loop: %s → %s", strings.Join(urls, " → "), c.Hops[len(c.Hops)-1].Location)
+	case c != nil && c.Truncated:
+		add("redirect", SeverityError, "still redirecting after %d hops (last: %s); crawlers give up on chains this long", len(c.Hops), c.Hops[len(c.Hops)-1].Location)
+	case p.FinalURL != "":
+		add("redirect", SeverityInfo, "redirects to %s; the checks apply to that page", p.FinalURL)
+	}
+	if c == nil {
+		return
+	}
+
+	if n := len(c.Hops); n > LongChainHops && !c.Truncated {
+		add("redirect", SeverityWarning, "%d-hop redirect chain; link to %s directly", n, c.Hops[n-1].Location)
+	}
+	for _, h := range c.Hops {
+		switch {
+		case h.MetaRefresh:
+			add("redirect", SeverityWarning, "meta refresh from %s to %s; use an HTTP 301", h.URL, h.Location)
+		case isTemporaryRedirect(h.Status) && isCanonicalisation(h.URL, h.Location):
+			add("redirect", SeverityWarning, "%d (temporary) redirect from %s to %s normalises the URL; use a 301 so signals move to the target", h.Status, h.URL, h.Location)
+		}
+		if from, to := registrableDomain(h.URL), registrableDomain(h.Location); from != to {
+			add("redirect", SeverityWarning, "hop from %s to %s crosses domains (%s → %s)", h.URL, h.Location, from, to)
+		}
+	}
+}
+
+func isTemporaryRedirect(status int) bool {
+	return status == http.StatusFound || status == http.StatusTemporaryRedirect
+}
+
+// isCanonicalisation reports whether a redirect only normalises the URL:
+// http to https, a www or host-case change, or a trailing-slash or path-case
+// change. Those moves are permanent by nature, so a temporary status is
+// almost always a mistake.
+func isCanonicalisation(from, to string) bool {
+	f, errF := url.Parse(from)
+	t, errT := url.Parse(to)
+	if errF != nil || errT != nil {
+		return false
+	}
+	if f.RawQuery != t.RawQuery {
+		return false
+	}
+	if f.Scheme != t.Scheme && !(f.Scheme == "http" && t.Scheme == "https") {
+		return false
+	}
+	fHost := strings.TrimPrefix(strings.ToLower(f.Host), "www.")
+	tHost := strings.TrimPrefix(strings.ToLower(t.Host), "www.")
+	if fHost != tHost {
+		return false
+	}
+	fPath := strings.ToLower(strings.TrimSuffix(f.Path, "/"))
+	tPath := strings.ToLower(strings.TrimSuffix(t.Path, "/"))
+	return fPath == tPath
+}
+
+// registrableDomain returns the eTLD+1 of rawURL's host (example.co.uk for
+// www.example.co.uk), or the lowercased host when it has none, such as an
+// IP address or localhost.
+func registrableDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
+
+// metaRefreshURL extracts the target of a meta refresh content value such
+// as `0; url=/new`. A refresh without a URL reloads the page and returns "".
+func metaRefreshURL(content string) string {
+	_, rest, ok := strings.Cut(content, ";")
+	if !ok {
+		_, rest, ok = strings.Cut(content, ",")
+		if !ok {
+			return ""
+		}
+	}
+	rest = strings.TrimSpace(rest)
+	if key, value, ok := strings.Cut(rest, "="); ok && strings.EqualFold(strings.TrimSpace(key), "url") {
+		rest = strings.TrimSpace(value)
+	}
+	return strings.Trim(rest, `"'`)
+}
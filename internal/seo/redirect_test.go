@@ -0,0 +1,126 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/audit"
+)
+
+func redirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/temp":
+			http.Redirect(w, r, "/temp/", http.StatusFound)
+		case "/temp/":
+			http.Redirect(w, r, "/refresh", http.StatusMovedPermanently)
+		case "/refresh":
+			_, _ = fmt.Fprint(w, `<html><head><meta http-equiv="refresh" content="0; URL='/final'"></head></html>`)
+		case "/final":
+			_, _ = fmt.Fprintf(w, goodPage, srv.URL)
+		case "/loop-a":
+			http.Redirect(w, r, "/loop-b", http.StatusMovedPermanently)
+		case "/loop-b":
+			http.Redirect(w, r, "/loop-a", http.StatusMovedPermanently)
+		default:
+			var n int
+			if _, err := fmt.Sscanf(r.URL.Path, "/hop/%d", &n); err == nil {
+				http.Redirect(w, r, fmt.Sprintf("/hop/%d", n+1), http.StatusMovedPermanently)
+				return
+			}
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPageAuditor_RedirectChain(t *testing.T) {
+	srv := redirectServer(t)
+	p, err := NewPageAuditor(5*time.Second, "").Audit(context.Background(), srv.URL+"/temp")
+	require.NoError(t, err)
+
+	require.NotNil(t, p.Redirects)
+	assert.Equal(t, []Hop{
+		{Hop: audit.Hop{URL: srv.URL + "/temp", Status: http.StatusFound, Location: srv.URL + "/temp/"}},
+		{Hop: audit.Hop{URL: srv.URL + "/temp/", Status: http.StatusMovedPermanently, Location: srv.URL + "/refresh"}},
+		{Hop: audit.Hop{URL: srv.URL + "/refresh", Status: http.StatusOK, Location: srv.URL + "/final"}, MetaRefresh: true},
+	}, p.Redirects.Hops)
+	assert.Equal(t, srv.URL+"/final", p.FinalURL)
+	assert.Equal(t, http.StatusOK, p.Status)
+
+	got := rules(p.Issues)["redirect"]
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0], "info: redirects to")
+	assert.Contains(t, got[1], "302 (temporary) redirect")
+	assert.Contains(t, got[2], "meta refresh from")
+	assert.Equal(t, 0, p.Errors())
+}
+
+func TestPageAuditor_RedirectLoop(t *testing.T) {
+	srv := redirectServer(t)
+	p, err := NewPageAuditor(5*time.Second, "").Audit(context.Background(), srv.URL+"/loop-a")
+	require.NoError(t, err)
+
+	assert.True(t, p.Redirects.Loop)
+	assert.Len(t, p.Redirects.Hops, 2)
+	assert.Empty(t, p.FinalURL)
+	require.Len(t, p.Issues, 1, "content rules are skipped without a final page")
+	assert.Equal(t, SeverityError, p.Issues[0].Severity)
+	assert.Contains(t, p.Issues[0].Message, "redirect loop")
+}
+
+func TestPageAuditor_RedirectLimit(t *testing.T) {
+	srv := redirectServer(t)
+	p, err := NewPageAuditor(5*time.Second, "").Audit(context.Background(), srv.URL+"/hop/1")
+	require.NoError(t, err)
+
+	assert.True(t, p.Redirects.Truncated)
+	assert.Len(t, p.Redirects.Hops, MaxRedirectHops)
+	assert.Equal(t, []string{"error: still redirecting after 5 hops (last: " + srv.URL + "/hop/6); crawlers give up on chains this long"},
+		rules(p.Issues)["redirect"])
+}
+
+func TestCheckRedirects(t *testing.T) {
+	p := &Page{
+		URL:      "http://example.com/a",
+		FinalURL: "https://shop.example.net/a",
+		Redirects: &RedirectChain{Hops: []Hop{
+			{Hop: audit.Hop{URL: "http://example.com/a", Status: 307, Location: "https://example.com/a"}},
+			{Hop: audit.Hop{URL: "https://example.com/a", Status: 301, Location: "https://www.example.com/a"}},
+			{Hop: audit.Hop{URL: "https://www.example.com/a", Status: 302, Location: "https://www.example.com/login?next=/a"}},
+			{Hop: audit.Hop{URL: "https://www.example.com/login?next=/a", Status: 301, Location: "https://shop.example.net/a"}},
+		}},
+	}
+	var got []string
+	checkRedirects(p, func(rule, severity, format string, args ...any) {
+		got = append(got, severity+": "+fmt.Sprintf(format, args...))
+	})
+	assert.Equal(t, []string{
+		"info: redirects to https://shop.example.net/a; the checks apply to that page",
+		"warning: 4-hop redirect chain; link to https://shop.example.net/a directly",
+		"warning: 307 (temporary) redirect from http://example.com/a to https://example.com/a normalises the URL; use a 301 so signals move to the target",
+		"warning: hop from https://www.example.com/login?next=/a to https://shop.example.net/a crosses domains (example.com → example.net)",
+	}, got)
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	assert.Equal(t, "example.co.uk", registrableDomain("https://www.Example.co.uk/x"))
+	assert.Equal(t, "example.com", registrableDomain("https://a.b.example.com"))
+	assert.Equal(t, "127.0.0.1", registrableDomain("http://127.0.0.1:8080/"))
+}
+
+func TestMetaRefreshURL(t *testing.T) {
+	assert.Equal(t, "/new", metaRefreshURL("0; url=/new"))
+	assert.Equal(t, "https://example.com/", metaRefreshURL(`5;URL="https://example.com/"`))
+	assert.Equal(t, "", metaRefreshURL("30"))
+}
@@ -0,0 +1,291 @@
+// Package seo checks the on-site signals that decide what search engines may
+// crawl and how they read a page, from the same vantage point as a crawler:
+// plain HTTP fetches of robots.txt and of the pages themselves.
+package seo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxRobotsBytes is how much of robots.txt Google reads; rules past it are
+// ignored.
+const MaxRobotsBytes = 500 << 10
+
+// DefaultCrawler is the product token rules are evaluated for.
+const DefaultCrawler = "Googlebot"
+
+// What a crawler does when robots.txt cannot be read. Google treats a 4xx as
+// "no robots.txt" and a 5xx (or 429) as the whole site being disallowed until
+// the file is reachable again.
+const (
+	FallbackNone        = ""
+	FallbackAllowAll    = "allow_all"
+	FallbackDisallowAll = "disallow_all"
+)
+
+// RobotsRule is one allow or disallow line.
+type RobotsRule struct {
+	Allow bool   `json:"allow"`
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+}
+
+// RobotsGroup is a set of rules shared by one or more user agents.
+type RobotsGroup struct {
+	UserAgents []string     `json:"user_agents"`
+	Rules      []RobotsRule `json:"rules"`
+}
+
+// RobotsIssue is a line crawlers will ignore.
+type RobotsIssue struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Robots is a parsed robots.txt.
+type Robots struct {
+	URL      string        `json:"url,omitempty"`
+	Status   int           `json:"status,omitempty"`
+	Fallback string        `json:"fallback,omitempty"`
+	Groups   []RobotsGroup `json:"groups"`
+	Sitemaps []string      `json:"sitemaps"`
+	Issues   []RobotsIssue `json:"issues,omitempty"`
+}
+
+// RobotsVerdict is the outcome of testing one URL.
+type RobotsVerdict struct {
+	URL     string `json:"url"`
+	Allowed bool   `json:"allowed"`
+	// Rule is the deciding rule; nil when no rule matched (or a fallback
+	// applied).
+	Rule *RobotsRule `json:"rule,omitempty"`
+	// UserAgent is the group the crawler was matched to; empty when no group
+	// applies.
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// ParseRobots parses robots.txt the way Google does: groups start at one or
+// more consecutive user-agent lines, directive names are case-insensitive,
+// and anything after '#' is a comment. Sitemap lines apply to the whole file
+// wherever they appear.
+func ParseRobots(r io.Reader) *Robots {
+	robots := &Robots{}
+	var current *RobotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, MaxRobotsBytes))
+	scanner.Buffer(make([]byte, 0, 64<<10), MaxRobotsBytes)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			robots.Issues = append(robots.Issues, RobotsIssue{Line: n, Message: fmt.Sprintf("no ':' separator in %q", line)})
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				robots.Groups = append(robots.Groups, RobotsGroup{})
+				current = &robots.Groups[len(robots.Groups)-1]
+			}
+			current.UserAgents = append(current.UserAgents, value)
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil {
+				robots.Issues = append(robots.Issues, RobotsIssue{Line: n, Message: key + " before any user-agent line"})
+				continue
+			}
+			if value == "" {
+				// An empty disallow allows everything; an empty allow is a no-op.
+				continue
+			}
+			current.Rules = append(current.Rules, RobotsRule{Allow: key == "allow", Path: value, Line: n})
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		case "crawl-delay", "host", "clean-param":
+			inAgents = false
+			robots.Issues = append(robots.Issues, RobotsIssue{Line: n, Message: key + " is ignored by Google"})
+		default:
+			robots.Issues = append(robots.Issues, RobotsIssue{Line: n, Message: fmt.Sprintf("unknown directive %q", key)})
+		}
+	}
+	return robots
+}
+
+// Test reports whether crawler may fetch rawURL. Of the rules in the
+// crawler's group, the one with the longest matching path wins, and allow
+// wins a tie.
+func (r *Robots) Test(crawler, rawURL string) RobotsVerdict {
+	v := RobotsVerdict{URL: rawURL, Allowed: true}
+	switch r.Fallback {
+	case FallbackAllowAll:
+		return v
+	case FallbackDisallowAll:
+		v.Allowed = false
+		return v
+	}
+
+	path := "/"
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+	}
+
+	agent, rules := r.rulesFor(crawler)
+	v.UserAgent = agent
+	var best *RobotsRule
+	for i := range rules {
+		rule := &rules[i]
+		if !matchRobotsPath(rule.Path, path) {
+			continue
+		}
+		if best == nil || len(rule.Path) > len(best.Path) || (len(rule.Path) == len(best.Path) && rule.Allow && !best.Allow) {
+			best = rule
+		}
+	}
+	if best != nil {
+		v.Rule = best
+		v.Allowed = best.Allow
+	}
+	return v
+}
+
+// rulesFor merges the rules of every group for the most specific user agent
+// matching crawler. A group named "googlebot" covers "Googlebot-Image" when
+// no group names it; "*" covers everyone else.
+func (r *Robots) rulesFor(crawler string) (string, []RobotsRule) {
+	crawler = strings.ToLower(crawler)
+	best := ""
+	for _, g := range r.Groups {
+		for _, ua := range g.UserAgents {
+			ua = strings.ToLower(ua)
+			if ua == "*" {
+				if best == "" {
+					best = ua
+				}
+				continue
+			}
+			if (ua == crawler || strings.HasPrefix(crawler, ua+"-")) && (best == "" || best == "*" || len(ua) > len(best)) {
+				best = ua
+			}
+		}
+	}
+	if best == "" {
+		return "", nil
+	}
+	var rules []RobotsRule
+	for _, g := range r.Groups {
+		for _, ua := range g.UserAgents {
+			if strings.EqualFold(ua, best) {
+				rules = append(rules, g.Rules...)
+				break
+			}
+		}
+	}
+	return best, rules
+}
+
+// matchRobotsPath matches path against a robots.txt pattern: a prefix match
+// where '*' matches any run of characters and a trailing '$' anchors the end.
+func matchRobotsPath(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+	// Leftmost matches for the middle pieces leave the most room for the last.
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// RobotsFetcher downloads robots.txt. The zero value is not usable; call
+// NewRobotsFetcher.
+type RobotsFetcher struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewRobotsFetcher builds a RobotsFetcher with the given per-request timeout
+// and User-Agent. An empty userAgent is sent as Go's default.
+func NewRobotsFetcher(timeout time.Duration, userAgent string) *RobotsFetcher {
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return &RobotsFetcher{client: &http.Client{Timeout: timeout}, userAgent: userAgent}
+}
+
+// Fetch downloads and parses /robots.txt for origin (scheme and host; any
+// path is dropped). A 4xx or 5xx is not an error: the result carries the
+// Fallback a crawler would apply. An error means no response at all.
+func (f *RobotsFetcher) Fetch(ctx context.Context, origin string) (*Robots, error) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid site %q: want an absolute URL such as https://example.com", origin)
+	}
+	robotsURL := u.Scheme + "://" + u.Host + "/robots.txt"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", robotsURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var robots *Robots
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		robots = ParseRobots(resp.Body)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		robots = &Robots{Fallback: FallbackDisallowAll}
+	default:
+		robots = &Robots{Fallback: FallbackAllowAll}
+	}
+	robots.URL, robots.Status = robotsURL, resp.StatusCode
+	return robots, nil
+}
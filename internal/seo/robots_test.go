@@ -0,0 +1,128 @@
+package seo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRobots = `# example
+User-agent: *
+Disallow: /admin/
+Disallow: /*.pdf$
+Allow: /admin/public/
+Crawl-delay: 10
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /search
+Allow: /search/about
+
+Sitemap: https://example.com/sitemap.xml
+user-agent: googlebot-image
+disallow: /
+bogus line
+`
+
+func TestParseRobots(t *testing.T) {
+	r := ParseRobots(strings.NewReader(testRobots))
+
+	require.Len(t, r.Groups, 3)
+	assert.Equal(t, []string{"Googlebot", "Bingbot"}, r.Groups[1].UserAgents)
+	assert.Equal(t, RobotsRule{Allow: true, Path: "/admin/public/", Line: 5}, r.Groups[0].Rules[2])
+	assert.Equal(t, []string{"https://example.com/sitemap.xml"}, r.Sitemaps)
+	require.Len(t, r.Issues, 2)
+	assert.Contains(t, r.Issues[0].Message, "crawl-delay")
+	assert.Equal(t, 16, r.Issues[1].Line)
+}
+
+func TestRobotsTest(t *testing.T) {
+	r := ParseRobots(strings.NewReader(testRobots))
+	cases := []struct {
+		crawler, url string
+		allowed      bool
+		agent        string
+	}{
+		{"Googlebot", "https://example.com/admin/x", true, "googlebot"},
+		{"Googlebot", "https://example.com/search?q=1", false, "googlebot"},
+		{"Googlebot", "https://example.com/search/about", true, "googlebot"},
+		{"Googlebot-Image", "https://example.com/logo.png", false, "googlebot-image"},
+		{"Googlebot-News", "https://example.com/search", false, "googlebot"},
+		{"DuckDuckBot", "https://example.com/admin/x", false, "*"},
+		{"DuckDuckBot", "https://example.com/admin/public/x", true, "*"},
+		{"DuckDuckBot", "https://example.com/docs/a.pdf", false, "*"},
+		{"DuckDuckBot", "https://example.com/docs/a.pdf?v=2", true, "*"},
+		{"DuckDuckBot", "https://example.com", true, "*"},
+	}
+	for _, c := range cases {
+		v := r.Test(c.crawler, c.url)
+		assert.Equal(t, c.allowed, v.Allowed, "%s %s", c.crawler, c.url)
+		assert.Equal(t, c.agent, v.UserAgent, "%s %s", c.crawler, c.url)
+	}
+
+	none := ParseRobots(strings.NewReader("Sitemap: https://example.com/s.xml\n"))
+	v := none.Test("Googlebot", "https://example.com/anything")
+	assert.True(t, v.Allowed)
+	assert.Nil(t, v.Rule)
+}
+
+func TestMatchRobotsPath(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/", "/anything", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish", false},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/folder/index.php?x=1", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/index.php", true},
+		{"/fish*.php", "/fishheads/catfish.php", true},
+		{"/a*b*c$", "/a-b-b-c", true},
+		{"/a*b*c$", "/a-b-c-d", false},
+		{"/$", "/", true},
+		{"/$", "/x", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, matchRobotsPath(c.pattern, c.path), "%s vs %s", c.pattern, c.path)
+	}
+}
+
+func TestRobotsFetcher_Fetch(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/robots.txt", r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /\n")
+	}))
+	defer srv.Close()
+	f := NewRobotsFetcher(5*time.Second, "")
+
+	r, err := f.Fetch(context.Background(), srv.URL+"/some/page")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/robots.txt", r.URL)
+	assert.False(t, r.Test("Googlebot", srv.URL+"/").Allowed)
+
+	status = http.StatusNotFound
+	r, err = f.Fetch(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, FallbackAllowAll, r.Fallback)
+	assert.True(t, r.Test("Googlebot", srv.URL+"/").Allowed)
+
+	status = http.StatusServiceUnavailable
+	r, err = f.Fetch(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, FallbackDisallowAll, r.Fallback)
+	assert.False(t, r.Test("Googlebot", srv.URL+"/").Allowed)
+
+	_, err = f.Fetch(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "invalid site")
+}
@@ -0,0 +1,342 @@
+// Package server exposes read-mostly GA4 and GSC operations over HTTP so
+// dashboards can integrate with ga4-manager without spawning a CLI process per
+// request. It is the engine behind `ga4 serve`.
+//
+// The server shares one GA4 client and one GSC client across all requests, so
+// rate limiting and the GSC daily quota tracker apply to the process as a
+// whole, exactly as they would for a single CLI run.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/metrics"
+	"github.com/garbarok/ga4-manager/internal/setup"
+	"github.com/garbarok/ga4-manager/internal/validation"
+)
+
+const (
+	// maxBodyBytes bounds POST bodies (YAML configs are a few KB).
+	maxBodyBytes = 1 << 20
+
+	defaultAnalyticsDays  = 28
+	defaultAnalyticsLimit = 1000
+)
+
+// GA4API is the slice of *ga4.Client the server reads from.
+type GA4API interface {
+	setup.ResourceLister
+}
+
+// GSCAPI is the slice of *gsc.Client the server reads from. GetQuotaStatus
+// reports the shared tracker so callers can see the budget left for the day.
+type GSCAPI interface {
+	gsc.SearchAPI
+	GetQuotaStatus() (used int, limit int, date string)
+}
+
+// Options configures a Server. GA4 and GSC may be nil, in which case the
+// corresponding endpoints answer 503. When Metrics is set, route durations are
+// recorded into it and it is served at GET /metrics.
+type Options struct {
+	GA4     GA4API
+	GSC     GSCAPI
+	Token   string
+	Logger  *slog.Logger
+	Metrics *metrics.Registry
+}
+
+// Server routes HTTP requests to the shared clients.
+type Server struct {
+	ga4     GA4API
+	gsc     GSCAPI
+	token   string
+	logger  *slog.Logger
+	metrics *metrics.Registry
+	mux     *http.ServeMux
+}
+
+// New builds a Server. An empty token disables authentication; `ga4 serve`
+// refuses to start that way unless the Operator opts in explicitly.
+func New(opts Options) *Server {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	s := &Server{
+		ga4:     opts.GA4,
+		gsc:     opts.GSC,
+		token:   opts.Token,
+		logger:  logger,
+		metrics: opts.Metrics,
+		mux:     http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.handle("GET /properties/{id}/conversions", s.handleConversions)
+	s.handle("GET /properties/{id}/dimensions", s.handleDimensions)
+	s.handle("GET /properties/{id}/metrics", s.handleMetrics)
+	s.handle("POST /setup/plan", s.handleSetupPlan)
+	s.handle("GET /gsc/quota", s.handleGSCQuota)
+	s.handle("GET /gsc/{site}/analytics", s.handleGSCAnalytics)
+	if s.metrics != nil {
+		s.mux.Handle("GET /metrics", s.auth(s.metrics.Handler().ServeHTTP))
+	}
+}
+
+// handle registers an authenticated route whose duration is recorded under
+// its pattern (not the concrete path, to keep label cardinality bounded).
+func (s *Server) handle(pattern string, h http.HandlerFunc) {
+	if s.metrics != nil {
+		next := h
+		h = func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next(w, r)
+			s.metrics.ObserveRequest(pattern, time.Since(start))
+		}
+	}
+	s.mux.Handle(pattern, s.auth(h))
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// auth enforces `Authorization: Bearer <token>` when a token is configured.
+func (s *Server) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "ok",
+		"ga4":    s.ga4 != nil,
+		"gsc":    s.gsc != nil,
+	})
+}
+
+// propertyID extracts and validates the {id} path segment.
+func (s *Server) propertyID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if s.ga4 == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("GA4 client not configured"))
+		return "", false
+	}
+	id := r.PathValue("id")
+	if err := validation.ValidatePropertyID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return "", false
+	}
+	return id, true
+}
+
+func (s *Server) handleConversions(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.propertyID(w, r)
+	if !ok {
+		return
+	}
+	items, err := s.ga4.ListConversions(id)
+	if err != nil {
+		s.upstreamError(w, "list conversions", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listResponse(id, nonNil(items)))
+}
+
+func (s *Server) handleDimensions(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.propertyID(w, r)
+	if !ok {
+		return
+	}
+	items, err := s.ga4.ListDimensions(id)
+	if err != nil {
+		s.upstreamError(w, "list dimensions", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listResponse(id, nonNil(items)))
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.propertyID(w, r)
+	if !ok {
+		return
+	}
+	items, err := s.ga4.ListCustomMetrics(id)
+	if err != nil {
+		s.upstreamError(w, "list metrics", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listResponse(id, nonNil(items)))
+}
+
+// handleSetupPlan accepts a project config (YAML) as the request body and
+// returns what `ga4 setup` would create on its Property. Nothing is written.
+func (s *Server) handleSetupPlan(w http.ResponseWriter, r *http.Request) {
+	if s.ga4 == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("GA4 client not configured"))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("failed to read body: %w", err))
+		return
+	}
+	cfg, err := config.ParseConfig(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !cfg.HasAnalytics() {
+		writeError(w, http.StatusBadRequest, errors.New("config has no analytics section"))
+		return
+	}
+	plan, err := setup.BuildPlan(cfg, s.ga4)
+	if err != nil {
+		s.upstreamError(w, "build setup plan", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+func (s *Server) handleGSCQuota(w http.ResponseWriter, _ *http.Request) {
+	if s.gsc == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("GSC client not configured"))
+		return
+	}
+	used, limit, date := s.gsc.GetQuotaStatus()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"used":  used,
+		"limit": limit,
+		"date":  date,
+	})
+}
+
+// handleGSCAnalytics runs a Search Analytics query. {site} must be URL-escaped
+// when it is a URL-prefix property (https%3A%2F%2Fexample.com%2F).
+//
+// Query parameters: start_date/end_date (YYYY-MM-DD) or days (default 28),
+// dimensions (comma-separated, default "query"), limit (default 1000).
+func (s *Server) handleGSCAnalytics(w http.ResponseWriter, r *http.Request) {
+	if s.gsc == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("GSC client not configured"))
+		return
+	}
+	query, err := analyticsQueryFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := s.gsc.QuerySearchAnalytics(query)
+	if err != nil {
+		s.upstreamError(w, "query search analytics", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func analyticsQueryFromRequest(r *http.Request) (*gsc.SearchAnalyticsQuery, error) {
+	q := r.URL.Query()
+
+	startDate, endDate := q.Get("start_date"), q.Get("end_date")
+	if startDate == "" && endDate == "" {
+		days, err := intParam(q.Get("days"), defaultAnalyticsDays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid days: %w", err)
+		}
+		startDate, endDate = gsc.BuildDateRange(days)
+	} else if err := gsc.ValidateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	dimensions := []string{"query"}
+	if raw := q.Get("dimensions"); raw != "" {
+		dimensions = strings.Split(raw, ",")
+	}
+	if err := gsc.ValidateDimensions(dimensions); err != nil {
+		return nil, err
+	}
+
+	limit, err := intParam(q.Get("limit"), defaultAnalyticsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit: %w", err)
+	}
+
+	return &gsc.SearchAnalyticsQuery{
+		SiteURL:    r.PathValue("site"),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Dimensions: dimensions,
+		RowLimit:   limit,
+	}, nil
+}
+
+func intParam(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("must be positive, got %d", n)
+	}
+	return n, nil
+}
+
+// upstreamError logs a failed Google API call and answers 502.
+func (s *Server) upstreamError(w http.ResponseWriter, operation string, err error) {
+	s.logger.Error("upstream call failed", "operation", operation, "error", err)
+	writeError(w, http.StatusBadGateway, fmt.Errorf("failed to %s: %w", operation, err))
+}
+
+func listResponse[T any](propertyID string, items []T) map[string]any {
+	return map[string]any{
+		"property_id": propertyID,
+		"count":       len(items),
+		"items":       items,
+	}
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/metrics"
+)
+
+type fakeGA4 struct {
+	conversions []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	dimensions  []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	metrics     []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	err         error
+	gotProperty string
+}
+
+func (f *fakeGA4) ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+	f.gotProperty = propertyID
+	return f.conversions, f.err
+}
+
+func (f *fakeGA4) ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	return f.dimensions, f.err
+}
+
+func (f *fakeGA4) ListCustomMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	return f.metrics, f.err
+}
+
+type fakeGSC struct {
+	gotQuery *gsc.SearchAnalyticsQuery
+}
+
+func (f *fakeGSC) QuerySearchAnalytics(q *gsc.SearchAnalyticsQuery) (*gsc.SearchAnalyticsReport, error) {
+	f.gotQuery = q
+	return &gsc.SearchAnalyticsReport{SiteURL: q.SiteURL, QuotaUsed: 1}, nil
+}
+
+func (f *fakeGSC) GetQuotaStatus() (int, int, string) {
+	return 12, 2000, "2026-06-05"
+}
+
+func do(t *testing.T, s *Server, method, target, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth(t *testing.T) {
+	s := New(Options{GA4: &fakeGA4{}, Token: "s3cret"})
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, s, "GET", "/properties/123456789/conversions", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(t, s, "GET", "/properties/123456789/conversions", "wrong", "").Code)
+	assert.Equal(t, http.StatusOK, do(t, s, "GET", "/properties/123456789/conversions", "s3cret", "").Code)
+	// Liveness is unauthenticated so load balancers can probe it.
+	assert.Equal(t, http.StatusOK, do(t, s, "GET", "/healthz", "", "").Code)
+}
+
+func TestConversions(t *testing.T) {
+	ga := &fakeGA4{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+	}}
+	s := New(Options{GA4: ga})
+
+	rec := do(t, s, "GET", "/properties/123456789/conversions", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "123456789", ga.gotProperty)
+
+	var got struct {
+		PropertyID string `json:"property_id"`
+		Count      int    `json:"count"`
+		Items      []struct {
+			EventName string `json:"eventName"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, 1, got.Count)
+	assert.Equal(t, "purchase", got.Items[0].EventName)
+}
+
+func TestConversions_InvalidPropertyAndUpstreamError(t *testing.T) {
+	s := New(Options{GA4: &fakeGA4{err: errors.New("boom")}})
+
+	assert.Equal(t, http.StatusBadRequest, do(t, s, "GET", "/properties/abc/conversions", "", "").Code)
+
+	rec := do(t, s, "GET", "/properties/123456789/conversions", "", "")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "boom")
+}
+
+func TestMissingClientIs503(t *testing.T) {
+	s := New(Options{})
+	assert.Equal(t, http.StatusServiceUnavailable, do(t, s, "GET", "/properties/123456789/metrics", "", "").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, do(t, s, "GET", "/gsc/quota", "", "").Code)
+}
+
+func TestSetupPlan(t *testing.T) {
+	ga := &fakeGA4{
+		conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{{EventName: "purchase"}},
+	}
+	s := New(Options{GA4: ga})
+	body := `project:
+  name: example
+ga4:
+  property_id: "123456789"
+conversions:
+  - name: purchase
+    counting_method: ONCE_PER_EVENT
+  - name: sign_up
+    counting_method: ONCE_PER_SESSION
+`
+	rec := do(t, s, "POST", "/setup/plan", "", body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var got struct {
+		PropertyID  string `json:"property_id"`
+		Conversions []struct {
+			Name   string `json:"name"`
+			Action string `json:"action"`
+		} `json:"conversions"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "123456789", got.PropertyID)
+	require.Len(t, got.Conversions, 2)
+	assert.Equal(t, "exists", got.Conversions[0].Action)
+	assert.Equal(t, "create", got.Conversions[1].Action)
+}
+
+func TestSetupPlan_InvalidConfig(t *testing.T) {
+	s := New(Options{GA4: &fakeGA4{}})
+	rec := do(t, s, "POST", "/setup/plan", "", "project: {}\n")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "project.name is required")
+}
+
+func TestGSCAnalytics(t *testing.T) {
+	fake := &fakeGSC{}
+	s := New(Options{GSC: fake})
+
+	rec := do(t, s, "GET", "/gsc/https%3A%2F%2Fexample.com%2F/analytics?start_date=2026-05-01&end_date=2026-05-28&dimensions=query,page&limit=50", "", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotNil(t, fake.gotQuery)
+	assert.Equal(t, "https://example.com/", fake.gotQuery.SiteURL)
+	assert.Equal(t, []string{"query", "page"}, fake.gotQuery.Dimensions)
+	assert.Equal(t, 50, fake.gotQuery.RowLimit)
+	assert.Equal(t, "2026-05-01", fake.gotQuery.StartDate)
+}
+
+func TestGSCAnalytics_BadParams(t *testing.T) {
+	s := New(Options{GSC: &fakeGSC{}})
+	assert.Equal(t, http.StatusBadRequest, do(t, s, "GET", "/gsc/sc-domain:example.com/analytics?limit=-1", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, s, "GET", "/gsc/sc-domain:example.com/analytics?dimensions=bogus", "", "").Code)
+}
+
+func TestGSCQuota(t *testing.T) {
+	s := New(Options{GSC: &fakeGSC{}})
+	rec := do(t, s, "GET", "/gsc/quota", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"used": 12`)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	reg := metrics.NewRegistry()
+	s := New(Options{GSC: &fakeGSC{}, Token: "s3cret", Metrics: reg})
+
+	require.Equal(t, http.StatusOK, do(t, s, "GET", "/gsc/quota", "s3cret", "").Code)
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, s, "GET", "/metrics", "", "").Code)
+	rec := do(t, s, "GET", "/metrics", "s3cret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `ga4manager_request_duration_seconds_count{route="GET /gsc/quota"} 1`)
+}
@@ -0,0 +1,103 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// ArchiveSnapshotVersion is the version of the ArchiveSnapshot document.
+const ArchiveSnapshotVersion = 1
+
+// ArchiveSnapshot holds the full definitions of the custom dimensions and
+// metrics a bulk archive removed, as the Admin API returned them. GA4 cannot
+// unarchive a definition; the snapshot is what recreates it, or at least
+// records what it was.
+type ArchiveSnapshot struct {
+	SchemaVersion int                                                 `json:"schema_version"`
+	PropertyID    string                                              `json:"property_id"`
+	TakenAt       time.Time                                           `json:"taken_at"`
+	Dimensions    []*admin.GoogleAnalyticsAdminV1alphaCustomDimension `json:"dimensions,omitempty"`
+	Metrics       []*admin.GoogleAnalyticsAdminV1alphaCustomMetric    `json:"metrics,omitempty"`
+}
+
+// SaveArchiveSnapshot writes s to path, creating its directory. An existing
+// file is never overwritten: it may be the only record of an earlier archive.
+func SaveArchiveSnapshot(path string, s *ArchiveSnapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadArchiveSnapshot reads a snapshot SaveArchiveSnapshot wrote.
+func LoadArchiveSnapshot(path string) (*ArchiveSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var s ArchiveSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s is not an archive snapshot: %w", path, err)
+	}
+	if s.SchemaVersion != ArchiveSnapshotVersion {
+		return nil, fmt.Errorf("%s has snapshot schema version %d, want %d", path, s.SchemaVersion, ArchiveSnapshotVersion)
+	}
+	return &s, nil
+}
+
+// DimensionConfigs returns the snapshot's dimensions as config entries.
+func (s *ArchiveSnapshot) DimensionConfigs() []config.DimensionConfig {
+	dims := make([]config.DimensionConfig, 0, len(s.Dimensions))
+	for _, d := range s.Dimensions {
+		dims = append(dims, dimensionConfig(d))
+	}
+	return dims
+}
+
+// MetricConfigs returns the snapshot's metrics as config entries.
+func (s *ArchiveSnapshot) MetricConfigs() []config.MetricConfig {
+	metrics := make([]config.MetricConfig, 0, len(s.Metrics))
+	for _, m := range s.Metrics {
+		metrics = append(metrics, metricConfig(m))
+	}
+	return metrics
+}
+
+func dimensionConfig(d *admin.GoogleAnalyticsAdminV1alphaCustomDimension) config.DimensionConfig {
+	return config.DimensionConfig{
+		ParameterName: d.ParameterName,
+		DisplayName:   d.DisplayName,
+		Description:   d.Description,
+		Scope:         d.Scope,
+	}
+}
+
+func metricConfig(m *admin.GoogleAnalyticsAdminV1alphaCustomMetric) config.MetricConfig {
+	return config.MetricConfig{
+		ParameterName:        m.ParameterName,
+		DisplayName:          m.DisplayName,
+		Description:          m.Description,
+		MeasurementUnit:      m.MeasurementUnit,
+		Scope:                m.Scope,
+		RestrictedMetricType: restrictedType(m.RestrictedMetricType),
+	}
+}
@@ -0,0 +1,55 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestArchiveSnapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archives", "123-metrics.json")
+	snap := &ArchiveSnapshot{
+		SchemaVersion: ArchiveSnapshotVersion,
+		PropertyID:    "123",
+		TakenAt:       time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Metrics: []*admin.GoogleAnalyticsAdminV1alphaCustomMetric{{
+			Name:                 "properties/123/customMetrics/9",
+			ParameterName:        "ad_cost",
+			DisplayName:          "Ad Cost",
+			MeasurementUnit:      "CURRENCY",
+			Scope:                "EVENT",
+			RestrictedMetricType: []string{"COST_DATA"},
+		}},
+	}
+
+	require.NoError(t, SaveArchiveSnapshot(path, snap))
+	assert.ErrorContains(t, SaveArchiveSnapshot(path, snap), "failed to write snapshot", "an earlier snapshot is never overwritten")
+
+	loaded, err := LoadArchiveSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, snap, loaded)
+	assert.Equal(t, []config.MetricConfig{{
+		ParameterName:        "ad_cost",
+		DisplayName:          "Ad Cost",
+		MeasurementUnit:      "CURRENCY",
+		Scope:                "EVENT",
+		RestrictedMetricType: "COST_DATA",
+	}}, loaded.MetricConfigs())
+	assert.Empty(t, loaded.DimensionConfigs())
+}
+
+func TestLoadArchiveSnapshot_Version(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema_version": 2}`), 0o644))
+
+	_, err := LoadArchiveSnapshot(path)
+
+	assert.ErrorContains(t, err, "snapshot schema version 2, want 1")
+}
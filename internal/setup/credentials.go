@@ -0,0 +1,102 @@
+package setup
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+	"google.golang.org/api/searchconsole/v1"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// ServiceAccountKey is what preflight reads from a service account key file.
+type ServiceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ParseServiceAccountKey checks that data is a service account key with a
+// usable private key, catching OAuth client secrets, user credentials and
+// truncated or hand-edited files before any API call.
+func ParseServiceAccountKey(data []byte) (*ServiceAccountKey, error) {
+	var key ServiceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("credentials file is not valid JSON: %w", err)
+	}
+	if key.Type != "service_account" {
+		if key.Type == "" {
+			return nil, fmt.Errorf("credentials file is not a service account key (no \"type\" field)")
+		}
+		return nil, fmt.Errorf("credentials file is a %q credential, not a service account key", key.Type)
+	}
+
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"client_email", key.ClientEmail},
+		{"private_key_id", key.PrivateKeyID},
+		{"private_key", key.PrivateKey},
+		{"token_uri", key.TokenURI},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("service account key is missing %s", strings.Join(missing, ", "))
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("service account key: private_key is not PEM encoded")
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("service account key: private_key cannot be parsed: %w", err)
+		}
+	}
+	return &key, nil
+}
+
+// requiredScopes returns the OAuth scopes setup needs for cfg: Analytics
+// edit for GA4 writes, Webmasters for sitemap submission.
+func requiredScopes(cfg *config.ProjectConfig) []string {
+	var scopes []string
+	if cfg.HasAnalytics() {
+		scopes = append(scopes, admin.AnalyticsEditScope)
+	}
+	if cfg.HasSearchConsole() {
+		scopes = append(scopes, searchconsole.WebmastersScope)
+	}
+	return scopes
+}
+
+// mintServiceAccountToken exchanges a service account key for an access
+// token with scopes. It fails when the key was deleted or disabled, or the
+// service account no longer exists.
+func mintServiceAccountToken(ctx context.Context, key []byte, scopes []string) error {
+	jwt, err := google.JWTConfigFromJSON(key, scopes...)
+	if err != nil {
+		return err
+	}
+	_, err = jwt.TokenSource(ctx).Token()
+	return err
+}
+
+// scopeNames shortens OAuth scope URLs for display: "analytics.edit".
+func scopeNames(scopes []string) string {
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = strings.TrimPrefix(s, "https://www.googleapis.com/auth/")
+	}
+	return strings.Join(names, ", ")
+}
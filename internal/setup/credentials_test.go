@@ -0,0 +1,116 @@
+package setup
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+func serviceAccountKeyJSON(t *testing.T, edit func(map[string]string)) []byte {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	key := map[string]string{
+		"type":           "service_account",
+		"project_id":     "my-project",
+		"private_key_id": "abc123",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "ga4@my-project.iam.gserviceaccount.com",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	}
+	if edit != nil {
+		edit(key)
+	}
+	data, err := json.Marshal(key)
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseServiceAccountKey(t *testing.T) {
+	key, err := ParseServiceAccountKey(serviceAccountKeyJSON(t, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "ga4@my-project.iam.gserviceaccount.com", key.ClientEmail)
+
+	for name, tc := range map[string]struct {
+		data []byte
+		want string
+	}{
+		"not json":      {[]byte("{"), "not valid JSON"},
+		"oauth client":  {[]byte(`{"installed": {"client_id": "x"}}`), `no "type" field`},
+		"user":          {serviceAccountKeyJSON(t, func(k map[string]string) { k["type"] = "authorized_user" }), `"authorized_user" credential`},
+		"missing email": {serviceAccountKeyJSON(t, func(k map[string]string) { k["client_email"] = "" }), "missing client_email"},
+		"bad pem":       {serviceAccountKeyJSON(t, func(k map[string]string) { k["private_key"] = "secret" }), "not PEM encoded"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseServiceAccountKey(tc.data)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, serviceAccountKeyJSON(t, nil), 0o600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+
+	cfg := &config.ProjectConfig{
+		GA4:           config.GA4Config{PropertyID: "123456789"},
+		SearchConsole: &config.SearchConsoleConfig{SiteURL: "sc-domain:example.com"},
+	}
+	pv := NewPreflightValidator(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var scopes []string
+	pv.mintToken = func(_ context.Context, _ []byte, s []string) error {
+		scopes = s
+		return nil
+	}
+	result := pv.CheckCredentials()
+	assert.Equal(t, ValidationPassed, result.Status)
+	assert.Contains(t, result.Details, "token minted for analytics.edit, webmasters")
+	assert.Len(t, scopes, 2)
+	assert.Equal(t, "ga4@my-project.iam.gserviceaccount.com", pv.principal)
+
+	pv.mintToken = func(context.Context, []byte, []string) error {
+		return errors.New("invalid_grant: Invalid JWT Signature")
+	}
+	result = pv.CheckCredentials()
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.ErrorContains(t, result.Error, "cannot get a token for ga4@my-project.iam.gserviceaccount.com")
+}
+
+func TestGA4RoleResult(t *testing.T) {
+	user := "ga4@my-project.iam.gserviceaccount.com"
+
+	editor := ga4RoleResult(ValidationResult{}, ga4.PropertyAccess{User: user, AtLeast: ga4.RoleAdmin, AtMost: ga4.RoleAdmin})
+	assert.Equal(t, ValidationPassed, editor.Status)
+	assert.Equal(t, "role: admin", editor.Details)
+
+	bracketed := ga4RoleResult(ValidationResult{}, ga4.PropertyAccess{User: user, AtLeast: ga4.RoleViewer, AtMost: ga4.RoleEditor, Detail: "access bindings are admin-only"})
+	assert.Equal(t, ValidationPassed, bracketed.Status)
+	assert.Contains(t, bracketed.Details, "viewer to editor")
+
+	viewer := ga4RoleResult(ValidationResult{}, ga4.PropertyAccess{User: user, AtLeast: ga4.RoleViewer, AtMost: ga4.RoleViewer})
+	assert.Equal(t, ValidationWarning, viewer.Status)
+	assert.Contains(t, viewer.Warning, "is viewer on the property")
+
+	none := ga4RoleResult(ValidationResult{}, ga4.PropertyAccess{User: user})
+	assert.Equal(t, ValidationWarning, none.Status)
+	assert.Contains(t, none.Warning, "has no access")
+}
@@ -0,0 +1,420 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// DiffSchemaVersion is the version of the Diff document. It changes only when
+// a field is removed or changes meaning; new fields keep the version.
+// docs/PLAN_DIFF.md documents the schema.
+const DiffSchemaVersion = 1
+
+// Resource types in a Diff.
+const (
+	DiffResourceConversion       = "conversion"
+	DiffResourceDimension        = "dimension"
+	DiffResourceMetric           = "metric"
+	DiffResourceCalculatedMetric = "calculated_metric"
+	DiffResourceChannelGroup     = "channel_group"
+	// Property settings: one change each, named after the settings resource.
+	DiffResourceDataRetention       = "data_retention"
+	DiffResourceEnhancedMeasurement = "enhanced_measurement"
+)
+
+// Diff actions. Setup updates channel groups and property settings, and the
+// conversions, dimensions and metrics whose on_conflict is update; any other
+// difference is drift, reported but left alone. Dropped marks a new resource
+// setup leaves out to stay within the tier limits (FitTierLimits).
+const (
+	DiffActionCreate  = "create"
+	DiffActionUpdate  = "update"
+	DiffActionNoop    = "noop"
+	DiffActionDrift   = "drift"
+	DiffActionIgnored = "ignored"
+	DiffActionDropped = "dropped"
+)
+
+// DiffLister is the read-only slice of the GA4 client BuildDiff needs.
+type DiffLister interface {
+	ResourceLister
+	ListPropertyCalculatedMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error)
+	ListChannelGroups(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error)
+	GetDataRetention(propertyID string) (*ga4.DataRetentionSettings, error)
+	GetPropertyEnhancedMeasurement(propertyID string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error)
+}
+
+// Change is one configured resource, its state on the Property (Before, nil
+// when it does not exist or was not listed) and its state in the config
+// (After).
+type Change struct {
+	Resource string         `json:"resource"`
+	Name     string         `json:"name"`
+	Action   string         `json:"action"`
+	Before   map[string]any `json:"before"`
+	After    map[string]any `json:"after"`
+}
+
+// Diff is the machine-readable form of `ga4 plan`: every resource setup
+// would touch, with before and after states, for approval tools to render.
+type Diff struct {
+	SchemaVersion int    `json:"schema_version"`
+	Project       string `json:"project"`
+	PropertyID    string `json:"property_id"`
+	// Summary counts the changes per action.
+	Summary map[string]int `json:"summary"`
+	Changes []Change       `json:"changes"`
+}
+
+// Pending reports whether applying the diff would change the Property.
+func (d *Diff) Pending() bool {
+	return d.Summary[DiffActionCreate]+d.Summary[DiffActionUpdate] > 0
+}
+
+// BuildDiff lists the Property's resources and compares each configured
+// conversion, dimension, metric, calculated metric and channel group with
+// what exists, matching on the keys SetupGA4 uses (event name, parameter
+// name, calculated metric id, display name). Configured data retention and
+// enhanced measurement are compared with the current settings; enhanced
+// measurement is left out when the property has no web stream.
+// Resources outside the setup scope are ignored without being listed. New
+// conversions, dimensions and metrics that FitTierLimits leaves out are
+// dropped rather than created, as setup does.
+func BuildDiff(cfg *config.ProjectConfig, lister DiffLister) (*Diff, error) {
+	propertyID := cfg.GetPropertyID()
+	if propertyID == "" {
+		return nil, fmt.Errorf("config %q has no GA4 property_id", cfg.Project.Name)
+	}
+	scope := cfg.SetupScope()
+	listed := newListedResources(lister)
+	fitted := *cfg
+	dropped, err := FitTierLimits(&fitted, listed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tier limits: %w", err)
+	}
+	isDropped := make(map[string]bool)
+	for _, r := range dropped {
+		isDropped[r.Resource+" "+r.Name] = true
+	}
+	d := &Diff{
+		SchemaVersion: DiffSchemaVersion,
+		Project:       cfg.Project.Name,
+		PropertyID:    propertyID,
+		Summary:       map[string]int{},
+		Changes:       []Change{},
+	}
+
+	if len(cfg.Conversions) > 0 {
+		existing := map[string]map[string]any{}
+		if scope.Includes(config.ResourceConversions) {
+			list, err := listed.ListConversions(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list conversions: %w", err)
+			}
+			for _, c := range list {
+				existing[c.EventName] = conversionState(c.EventName, c.CountingMethod)
+			}
+		}
+		for _, c := range cfg.Conversions {
+			if isDropped[DiffResourceConversion+" "+c.Name] {
+				d.addDropped(DiffResourceConversion, c.Name, conversionState(c.Name, c.CountingMethod))
+				continue
+			}
+			d.addPatchable(scope.Includes(config.ResourceConversions), cfg.ConflictMode(c.OnConflict), DiffResourceConversion, c.Name,
+				existing[c.Name], conversionState(c.Name, c.CountingMethod))
+		}
+	}
+
+	if len(cfg.Dimensions) > 0 {
+		existing := map[string]map[string]any{}
+		if scope.Includes(config.ResourceDimensions) {
+			list, err := listed.ListDimensions(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list dimensions: %w", err)
+			}
+			for _, dim := range list {
+				existing[dim.ParameterName] = dimensionState(dim.ParameterName, dim.DisplayName, dim.Description, dim.Scope)
+			}
+		}
+		for _, dim := range cfg.Dimensions {
+			if isDropped[DiffResourceDimension+" "+dim.ParameterName] {
+				d.addDropped(DiffResourceDimension, dim.ParameterName, dimensionState(dim.ParameterName, dim.DisplayName, dim.Description, dim.Scope))
+				continue
+			}
+			d.addPatchable(scope.Includes(config.ResourceDimensions), cfg.ConflictMode(dim.OnConflict), DiffResourceDimension, dim.ParameterName,
+				existing[dim.ParameterName], dimensionState(dim.ParameterName, dim.DisplayName, dim.Description, dim.Scope))
+		}
+	}
+
+	if len(cfg.Metrics) > 0 {
+		existing := map[string]map[string]any{}
+		if scope.Includes(config.ResourceMetrics) {
+			list, err := listed.ListCustomMetrics(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list metrics: %w", err)
+			}
+			for _, m := range list {
+				existing[m.ParameterName] = metricState(m.ParameterName, m.DisplayName, m.Description, m.MeasurementUnit, m.Scope, restrictedType(m.RestrictedMetricType))
+			}
+		}
+		for _, m := range cfg.Metrics {
+			if isDropped[DiffResourceMetric+" "+m.ParameterName] {
+				d.addDropped(DiffResourceMetric, m.ParameterName, configMetricState(m))
+				continue
+			}
+			d.addPatchable(scope.Includes(config.ResourceMetrics), cfg.ConflictMode(m.OnConflict), DiffResourceMetric, m.ParameterName,
+				existing[m.ParameterName], configMetricState(m))
+		}
+	}
+
+	if len(cfg.CalculatedMetrics) > 0 {
+		existing := map[string]map[string]any{}
+		if scope.Includes(config.ResourceCalculatedMetrics) {
+			list, err := lister.ListPropertyCalculatedMetrics(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list calculated metrics: %w", err)
+			}
+			for _, c := range list {
+				existing[c.CalculatedMetricId] = calculatedMetricState(c.CalculatedMetricId, c.DisplayName, c.Description, c.Formula, c.MetricUnit)
+			}
+		}
+		for _, c := range cfg.CalculatedMetrics {
+			id, unit := ga4.CalculatedMetricID(c), c.MetricUnit
+			if unit == "" {
+				unit = "STANDARD"
+			}
+			d.add(scope.Includes(config.ResourceCalculatedMetrics), DiffResourceCalculatedMetric, id,
+				existing[id], calculatedMetricState(id, c.Name, c.Description, c.Formula, unit), false)
+		}
+	}
+
+	if len(cfg.ChannelGroups) > 0 {
+		if err := d.addChannelGroups(cfg, lister, scope.Includes(config.ResourceChannelGroups)); err != nil {
+			return nil, err
+		}
+	}
+
+	if r := cfg.DataRetention; r != nil {
+		after := dataRetentionState(ga4.DataRetentionSettings{EventDataRetention: r.EventDataRetention, ResetUserDataOnNewActivity: r.ResetUserDataOnNewActivity})
+		if !scope.Includes(config.ResourceDataRetention) {
+			d.add(false, DiffResourceDataRetention, "dataRetentionSettings", nil, after, false)
+		} else {
+			current, err := lister.GetDataRetention(propertyID)
+			if err != nil {
+				return nil, err
+			}
+			before := dataRetentionState(*current)
+			d.add(true, DiffResourceDataRetention, "dataRetentionSettings", before, after, sameState(before, after))
+		}
+	}
+
+	if em := cfg.EnhancedMeasurement; em != nil {
+		if !scope.Includes(config.ResourceEnhancedMeasurement) {
+			d.add(false, DiffResourceEnhancedMeasurement, "enhancedMeasurementSettings", nil,
+				enhancedMeasurementState(ga4.EnhancedMeasurementFromConfig(&admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{}, em)), false)
+		} else {
+			current, err := lister.GetPropertyEnhancedMeasurement(propertyID)
+			switch {
+			case errors.Is(err, ga4.ErrNoWebStream):
+				// Nothing to compare on an app-only property.
+			case err != nil:
+				return nil, err
+			default:
+				before := enhancedMeasurementState(current)
+				after := enhancedMeasurementState(ga4.EnhancedMeasurementFromConfig(current, em))
+				d.add(true, DiffResourceEnhancedMeasurement, "enhancedMeasurementSettings", before, after, sameState(before, after))
+			}
+		}
+	}
+	return d, nil
+}
+
+// addChannelGroups compares channel groups with ga4.ChannelGroupUpToDate, the
+// check setup uses to decide on an update. Before lists the existing rule
+// names only: the API returns parsed filters, not the config's expressions.
+func (d *Diff) addChannelGroups(cfg *config.ProjectConfig, lister DiffLister, inScope bool) error {
+	existing := map[string]*admin.GoogleAnalyticsAdminV1alphaChannelGroup{}
+	if inScope {
+		list, err := lister.ListChannelGroups(d.PropertyID)
+		if err != nil {
+			return fmt.Errorf("failed to list channel groups: %w", err)
+		}
+		for _, g := range list {
+			if !g.SystemDefined {
+				existing[g.DisplayName] = g
+			}
+		}
+	}
+	for _, group := range ga4.ChannelGroupsFromConfig(cfg.ChannelGroups) {
+		rules := make([]map[string]any, 0, len(group.Rules))
+		for _, r := range group.Rules {
+			rules = append(rules, map[string]any{"display_name": r.DisplayName, "expression": r.Expression})
+		}
+		after := map[string]any{"display_name": group.DisplayName, "description": group.Description, "rules": rules}
+
+		var before map[string]any
+		upToDate := false
+		if g, ok := existing[group.DisplayName]; ok {
+			names := make([]map[string]any, 0, len(g.GroupingRule))
+			for _, r := range g.GroupingRule {
+				names = append(names, map[string]any{"display_name": r.DisplayName})
+			}
+			before = map[string]any{"display_name": g.DisplayName, "description": g.Description, "rules": names}
+			var err error
+			if upToDate, err = ga4.ChannelGroupUpToDate(g, group); err != nil {
+				return fmt.Errorf("channel group %s: %w", group.DisplayName, err)
+			}
+		}
+		d.add(inScope, DiffResourceChannelGroup, group.DisplayName, before, after, upToDate)
+	}
+	return nil
+}
+
+// add records a change. Resources setup updates (channel groups and property
+// settings) pass upToDate; for the others before and after are compared field
+// by field.
+func (d *Diff) add(inScope bool, resource, name string, before, after map[string]any, upToDate bool) {
+	c := Change{Resource: resource, Name: name, Before: before, After: after}
+	switch {
+	case !inScope:
+		c.Action = DiffActionIgnored
+	case before == nil:
+		c.Action = DiffActionCreate
+	case updatable(resource) && upToDate:
+		c.Action = DiffActionNoop
+	case updatable(resource):
+		c.Action = DiffActionUpdate
+	case sameState(before, after):
+		c.Action = DiffActionNoop
+	default:
+		c.Action = DiffActionDrift
+	}
+	d.Summary[c.Action]++
+	d.Changes = append(d.Changes, c)
+}
+
+// addPatchable records a conversion, dimension or metric. Setup patches an
+// existing one that differs when its conflict mode is update, unless the
+// scope differs: the API cannot change it, so that stays drift.
+func (d *Diff) addPatchable(inScope bool, mode, resource, name string, before, after map[string]any) {
+	if inScope && before != nil && mode == config.OnConflictUpdate && patchable(before, after) {
+		d.Summary[DiffActionUpdate]++
+		d.Changes = append(d.Changes, Change{Resource: resource, Name: name, Action: DiffActionUpdate, Before: before, After: after})
+		return
+	}
+	d.add(inScope, resource, name, before, after, false)
+}
+
+// addDropped records a new resource setup leaves out to stay within the tier
+// limits.
+func (d *Diff) addDropped(resource, name string, after map[string]any) {
+	d.Summary[DiffActionDropped]++
+	d.Changes = append(d.Changes, Change{Resource: resource, Name: name, Action: DiffActionDropped, After: after})
+}
+
+// patchable reports whether an existing resource differs from the config
+// only in fields setup can patch.
+func patchable(before, after map[string]any) bool {
+	return !sameState(before, after) && before["scope"] == after["scope"]
+}
+
+// updatable reports whether setup brings an existing resource in line with
+// the config rather than leaving it alone.
+func updatable(resource string) bool {
+	switch resource {
+	case DiffResourceChannelGroup, DiffResourceDataRetention, DiffResourceEnhancedMeasurement:
+		return true
+	}
+	return false
+}
+
+func sameState(before, after map[string]any) bool {
+	return len(changedKeys(before, after)) == 0
+}
+
+// changedKeys lists, sorted, the fields of after that before differs in.
+func changedKeys(before, after map[string]any) []string {
+	var keys []string
+	for k, v := range after {
+		if before[k] != v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func conversionState(eventName, countingMethod string) map[string]any {
+	return map[string]any{"event_name": eventName, "counting_method": countingMethod}
+}
+
+func dimensionState(parameter, displayName, description, scope string) map[string]any {
+	return map[string]any{"parameter": parameter, "display_name": displayName, "description": description, "scope": scope}
+}
+
+func metricState(parameter, displayName, description, unit, scope, restricted string) map[string]any {
+	return map[string]any{
+		"parameter":              parameter,
+		"display_name":           displayName,
+		"description":            description,
+		"unit":                   unit,
+		"scope":                  scope,
+		"restricted_metric_type": restricted,
+	}
+}
+
+// configMetricState is the diff state of a configured metric. Currency
+// metrics default to the revenue restriction, as CreateCustomMetric does.
+func configMetricState(m config.MetricConfig) map[string]any {
+	restricted := m.RestrictedMetricType
+	if restricted == "" && m.MeasurementUnit == "CURRENCY" {
+		restricted = "REVENUE_DATA"
+	}
+	return metricState(m.ParameterName, m.DisplayName, m.Description, m.MeasurementUnit, m.Scope, restricted)
+}
+
+func calculatedMetricState(id, displayName, description, formula, unit string) map[string]any {
+	return map[string]any{
+		"id":           id,
+		"display_name": displayName,
+		"description":  description,
+		"formula":      formula,
+		"metric_unit":  unit,
+	}
+}
+
+// dataRetentionState is the diff state of data retention settings.
+func dataRetentionState(s ga4.DataRetentionSettings) map[string]any {
+	return map[string]any{
+		"event_data_retention":            s.EventDataRetention,
+		"reset_user_data_on_new_activity": s.ResetUserDataOnNewActivity,
+	}
+}
+
+// enhancedMeasurementState is the diff state of the enhanced measurement
+// settings setup manages; query parameters are not among them.
+func enhancedMeasurementState(s *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings) map[string]any {
+	return map[string]any{
+		"stream_enabled":    s.StreamEnabled,
+		"scrolls":           s.ScrollsEnabled,
+		"outbound_clicks":   s.OutboundClicksEnabled,
+		"site_search":       s.SiteSearchEnabled,
+		"video_engagement":  s.VideoEngagementEnabled,
+		"file_downloads":    s.FileDownloadsEnabled,
+		"page_changes":      s.PageChangesEnabled,
+		"form_interactions": s.FormInteractionsEnabled,
+	}
+}
+
+func restrictedType(types []string) string {
+	if len(types) == 0 {
+		return ""
+	}
+	return types[0]
+}
@@ -0,0 +1,204 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// fakeDiffLister serves fixed resources for BuildDiff.
+type fakeDiffLister struct {
+	fakeLister
+	dimensions []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	metrics    []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	calculated []*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric
+	groups     []*admin.GoogleAnalyticsAdminV1alphaChannelGroup
+	retention  *ga4.DataRetentionSettings
+	em         *admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings
+}
+
+func (f *fakeDiffLister) ListDimensions(string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	f.calls = append(f.calls, "dimensions")
+	return f.dimensions, nil
+}
+
+func (f *fakeDiffLister) ListCustomMetrics(string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	f.calls = append(f.calls, "metrics")
+	return f.metrics, nil
+}
+
+func (f *fakeDiffLister) ListPropertyCalculatedMetrics(string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	f.calls = append(f.calls, "calculated_metrics")
+	return f.calculated, nil
+}
+
+func (f *fakeDiffLister) ListChannelGroups(string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	f.calls = append(f.calls, "channel_groups")
+	return f.groups, nil
+}
+
+func (f *fakeDiffLister) GetDataRetention(string) (*ga4.DataRetentionSettings, error) {
+	f.calls = append(f.calls, "data_retention")
+	return f.retention, nil
+}
+
+func (f *fakeDiffLister) GetPropertyEnhancedMeasurement(string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
+	f.calls = append(f.calls, "enhanced_measurement")
+	if f.em == nil {
+		return nil, ga4.ErrNoWebStream
+	}
+	return f.em, nil
+}
+
+func TestBuildDiff(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project: config.ProjectInfo{Name: "Test"},
+		GA4:     config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+			{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION"},
+		},
+		Dimensions: []config.DimensionConfig{{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"}},
+		Metrics:    []config.MetricConfig{{ParameterName: "reading_time", DisplayName: "Reading Time", MeasurementUnit: "SECONDS", Scope: "EVENT"}},
+		ChannelGroups: []config.ChannelGroupConfig{{
+			DisplayName: "Marketing Channels",
+			Rules:       []config.ChannelRuleConfig{{DisplayName: "Email", Expression: "sessionMedium == 'email'"}},
+		}},
+		Setup: &config.SetupConfig{Skip: []string{"metrics"}},
+	}
+	lister := &fakeDiffLister{
+		fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+			{EventName: "purchase", CountingMethod: "ONCE_PER_SESSION"},
+		}},
+		dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+			{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"},
+		},
+		groups: []*admin.GoogleAnalyticsAdminV1alphaChannelGroup{
+			{DisplayName: "Marketing Channels", GroupingRule: []*admin.GoogleAnalyticsAdminV1alphaGroupingRule{{DisplayName: "Email"}}},
+		},
+	}
+
+	d, err := BuildDiff(cfg, lister)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"conversions", "dimensions", "channel_groups"}, lister.calls, "out-of-scope resources are not listed")
+	actions := map[string]string{}
+	for _, c := range d.Changes {
+		actions[c.Resource+"/"+c.Name] = c.Action
+	}
+	assert.Equal(t, map[string]string{
+		"conversion/purchase":              DiffActionDrift,
+		"conversion/sign_up":               DiffActionCreate,
+		"dimension/author":                 DiffActionNoop,
+		"metric/reading_time":              DiffActionIgnored,
+		"channel_group/Marketing Channels": DiffActionUpdate,
+	}, actions)
+	assert.Equal(t, map[string]int{DiffActionDrift: 1, DiffActionCreate: 1, DiffActionNoop: 1, DiffActionIgnored: 1, DiffActionUpdate: 1}, d.Summary)
+	assert.True(t, d.Pending())
+
+	data, err := json.Marshal(d.Changes[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"resource":"conversion","name":"sign_up","action":"create","before":null,
+		"after":{"event_name":"sign_up","counting_method":"ONCE_PER_SESSION"}}`, string(data))
+}
+
+func TestBuildDiff_OnConflictUpdate(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Test"},
+		GA4:         config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "purchase", CountingMethod: "ONCE_PER_EVENT"}},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "author", DisplayName: "Post Author", Scope: "EVENT"},
+			{ParameterName: "plan", DisplayName: "Plan", Scope: "USER"},
+			{ParameterName: "topic", DisplayName: "Topic", Scope: "EVENT", OnConflict: config.OnConflictSkip},
+		},
+		Setup: &config.SetupConfig{OnConflict: config.OnConflictUpdate},
+	}
+	lister := &fakeDiffLister{
+		fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+			{EventName: "purchase", CountingMethod: "ONCE_PER_SESSION"},
+		}},
+		dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+			{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"},
+			{ParameterName: "plan", DisplayName: "Plan", Scope: "EVENT"},
+			{ParameterName: "topic", DisplayName: "Topics", Scope: "EVENT"},
+		},
+	}
+
+	d, err := BuildDiff(cfg, lister)
+	require.NoError(t, err)
+
+	actions := map[string]string{}
+	for _, c := range d.Changes {
+		actions[c.Resource+"/"+c.Name] = c.Action
+	}
+	assert.Equal(t, map[string]string{
+		"conversion/purchase": DiffActionUpdate,
+		"dimension/author":    DiffActionUpdate,
+		"dimension/plan":      DiffActionDrift, // the scope cannot be patched
+		"dimension/topic":     DiffActionDrift,
+	}, actions)
+}
+
+func TestBuildDiff_TierLimits(t *testing.T) {
+	lister := &fakeDiffLister{}
+	for i := range 29 {
+		lister.conversions = append(lister.conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	cfg := &config.ProjectConfig{
+		Project: config.ProjectInfo{Name: "Test"},
+		GA4:     config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{
+			{Name: "share", CountingMethod: "ONCE_PER_EVENT"},
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT", Priority: "high"},
+		},
+	}
+
+	d, err := BuildDiff(cfg, lister)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"conversions"}, lister.calls, "conversions are listed once")
+	assert.Equal(t, []Change{
+		{Resource: DiffResourceConversion, Name: "share", Action: DiffActionDropped,
+			After: map[string]any{"event_name": "share", "counting_method": "ONCE_PER_EVENT"}},
+		{Resource: DiffResourceConversion, Name: "purchase", Action: DiffActionCreate,
+			After: map[string]any{"event_name": "purchase", "counting_method": "ONCE_PER_EVENT"}},
+	}, d.Changes)
+	assert.Equal(t, map[string]int{DiffActionDropped: 1, DiffActionCreate: 1}, d.Summary)
+	assert.Len(t, cfg.Conversions, 2, "the config is left alone")
+}
+
+func TestBuildDiff_PropertySettings(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project:             config.ProjectInfo{Name: "Test"},
+		GA4:                 config.GA4Config{PropertyID: "123"},
+		DataRetention:       &config.DataRetentionConfig{EventDataRetention: "FOURTEEN_MONTHS", ResetUserDataOnNewActivity: true},
+		EnhancedMeasurement: &config.EnhancedMeasurementConfig{PageViews: true, Scrolls: true},
+	}
+	lister := &fakeDiffLister{
+		retention: &ga4.DataRetentionSettings{EventDataRetention: "TWO_MONTHS", ResetUserDataOnNewActivity: true},
+		em:        &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{StreamEnabled: true, ScrollsEnabled: true},
+	}
+
+	d, err := BuildDiff(cfg, lister)
+	require.NoError(t, err)
+
+	require.Len(t, d.Changes, 2)
+	assert.Equal(t, DiffActionUpdate, d.Changes[0].Action)
+	assert.Equal(t, "TWO_MONTHS", d.Changes[0].Before["event_data_retention"])
+	assert.Equal(t, "FOURTEEN_MONTHS", d.Changes[0].After["event_data_retention"])
+	assert.Equal(t, DiffActionNoop, d.Changes[1].Action)
+
+	// An app-only property has no enhanced measurement to compare.
+	lister.em = nil
+	d, err = BuildDiff(cfg, lister)
+	require.NoError(t, err)
+	assert.Len(t, d.Changes, 1)
+}
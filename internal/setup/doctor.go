@@ -0,0 +1,412 @@
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/garbarok/ga4-manager/internal/auth"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// Clock skew thresholds. Google rejects a service account's signed token
+// request when the clock is more than five minutes off.
+const (
+	clockSkewWarning = 30 * time.Second
+	clockSkewFailure = 5 * time.Minute
+)
+
+// doctorEndpoints are the Google hosts the CLI talks to.
+var doctorEndpoints = []string{
+	"https://oauth2.googleapis.com/",
+	"https://analyticsadmin.googleapis.com/",
+	"https://analyticsdata.googleapis.com/",
+	"https://searchconsole.googleapis.com/",
+}
+
+// apiProbe is a cheap read that tells whether an API is enabled in the
+// credential's Google Cloud project.
+type apiProbe struct {
+	Name    string
+	Service string // e.g. "analyticsadmin.googleapis.com"
+	URL     string
+}
+
+// Doctor runs `ga4 doctor`: the environment checks that need no config
+// (network, clock, credential scopes and API enablement) and, given a
+// config, the pre-flight checks setup runs, without setting anything up.
+type Doctor struct {
+	config    *config.ProjectConfig
+	validator *PreflightValidator
+	logger    *slog.Logger
+	ctx       context.Context
+
+	endpoints []string
+	probes    []apiProbe
+	http      *http.Client
+	now       func() time.Time
+
+	// grantedScopes returns the scopes of an access token minted with
+	// scopes; authClient returns an HTTP client authorised with them.
+	grantedScopes func(ctx context.Context, scopes []string) ([]string, error)
+	authClient    func(ctx context.Context, scopes []string) (*http.Client, error)
+
+	// serverTime is the Date of the first response CheckNetwork got.
+	serverTime time.Time
+}
+
+// NewDoctor creates a doctor. cfg may be nil, which runs only the checks that
+// need no config; the clients may be nil as for NewPreflightValidator.
+func NewDoctor(cfg *config.ProjectConfig, ga4Client ga4.GA4Admin, gscClient gsc.SearchConsole, logger *slog.Logger) *Doctor {
+	validatorCfg := cfg
+	if validatorCfg == nil {
+		validatorCfg = &config.ProjectConfig{}
+	}
+	propertyID := "0"
+	if cfg != nil && cfg.GetPropertyID() != "" {
+		propertyID = cfg.GetPropertyID()
+	}
+	return &Doctor{
+		config:    cfg,
+		validator: NewPreflightValidator(validatorCfg, ga4Client, gscClient, logger),
+		logger:    logger,
+		ctx:       context.Background(),
+		endpoints: doctorEndpoints,
+		probes: []apiProbe{
+			{Name: "Admin API", Service: "analyticsadmin.googleapis.com", URL: "https://analyticsadmin.googleapis.com/v1beta/accountSummaries?pageSize=1"},
+			{Name: "Data API", Service: "analyticsdata.googleapis.com", URL: "https://analyticsdata.googleapis.com/v1beta/properties/" + propertyID + "/metadata"},
+			{Name: "Search Console API", Service: "searchconsole.googleapis.com", URL: "https://searchconsole.googleapis.com/webmasters/v3/sites"},
+		},
+		http:          &http.Client{Timeout: 10 * time.Second},
+		now:           time.Now,
+		grantedScopes: tokenScopes,
+		authClient:    authorizedClient,
+	}
+}
+
+// RunAll runs every check. Without credentials the scope and API checks are
+// skipped.
+func (d *Doctor) RunAll() []ValidationResult {
+	results := []ValidationResult{d.CheckNetwork(), d.CheckClockSkew()}
+
+	var creds ValidationResult
+	if d.config != nil {
+		preflight, _ := d.validator.ValidateAll()
+		results = append(results, preflight...)
+		creds = preflight[0]
+	} else {
+		creds = d.validator.CheckCredentials()
+		results = append(results, creds)
+	}
+
+	if creds.Status == ValidationFailed {
+		results = append(results, ValidationResult{
+			Name:        "Credential scopes",
+			Description: "Check the OAuth scopes the credential is granted",
+			Status:      ValidationSkipped,
+			Details:     "needs working credentials",
+		})
+		for _, p := range d.enabledProbes() {
+			results = append(results, ValidationResult{
+				Name:        p.Name,
+				Description: "Check the " + p.Name + " is enabled",
+				Status:      ValidationSkipped,
+				Details:     "needs working credentials",
+			})
+		}
+		return results
+	}
+	results = append(results, d.CheckScopes())
+	return append(results, d.CheckAPIs()...)
+}
+
+// CheckNetwork checks that every Google host answers over HTTPS. Any HTTP
+// response counts, whatever its status.
+func (d *Doctor) CheckNetwork() ValidationResult {
+	result := ValidationResult{
+		Name:        "Network",
+		Description: "Check the Google API hosts are reachable",
+		Status:      ValidationPassed,
+	}
+
+	var unreachable []string
+	for _, endpoint := range d.endpoints {
+		req, err := http.NewRequestWithContext(d.ctx, http.MethodHead, endpoint, nil)
+		if err != nil {
+			return failed(result, err, "")
+		}
+		resp, err := d.http.Do(req)
+		if err != nil {
+			d.logger.Debug("host unreachable", "endpoint", endpoint, "error", err)
+			unreachable = append(unreachable, hostOf(endpoint))
+			continue
+		}
+		_ = resp.Body.Close()
+		if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil && d.serverTime.IsZero() {
+			d.serverTime = date
+		}
+	}
+
+	if len(unreachable) > 0 {
+		return failed(result, fmt.Errorf("cannot reach %s", strings.Join(unreachable, ", ")),
+			"Check the connection, and that the proxy (HTTPS_PROXY) and firewall allow HTTPS to *.googleapis.com")
+	}
+	result.Details = fmt.Sprintf("%d hosts reachable", len(d.endpoints))
+	return result
+}
+
+// CheckClockSkew compares the local clock with the Date of a Google response
+// from CheckNetwork, which must run first.
+func (d *Doctor) CheckClockSkew() ValidationResult {
+	result := ValidationResult{
+		Name:        "Clock",
+		Description: "Check the system clock agrees with Google's",
+		Status:      ValidationPassed,
+	}
+	if d.serverTime.IsZero() {
+		result.Status = ValidationSkipped
+		result.Details = "no Google host answered"
+		return result
+	}
+
+	// Date has a one-second resolution
+	skew := d.now().Sub(d.serverTime).Round(time.Second)
+	abs := max(skew, -skew)
+	const fix = "Sync the system clock with NTP (e.g. `sudo timedatectl set-ntp true`, or turn on automatic time)"
+	switch {
+	case abs > clockSkewFailure:
+		return failed(result, fmt.Errorf("clock is %s off Google's; token requests will be rejected", skew), fix)
+	case abs > clockSkewWarning:
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("clock is %s off Google's. %s", skew, fix)
+	default:
+		result.Details = fmt.Sprintf("within %s of Google's", max(abs, time.Second))
+	}
+	return result
+}
+
+// CheckScopes checks that a token minted with the credential carries every
+// scope the config needs, or every scope the CLI uses without a config. A
+// service account gets what it asks for; a login only what was consented to.
+func (d *Doctor) CheckScopes() ValidationResult {
+	result := ValidationResult{
+		Name:        "Credential scopes",
+		Description: "Check the OAuth scopes the credential is granted",
+		Status:      ValidationPassed,
+	}
+	want := d.scopes()
+	granted, err := d.grantedScopes(d.ctx, want)
+	if err != nil {
+		return failed(result, fmt.Errorf("cannot get a token: %w", err),
+			"Run `ga4 auth login` again, or create a new key for the service account")
+	}
+
+	var missing []string
+	for _, s := range want {
+		if !slices.Contains(granted, s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return failed(result, fmt.Errorf("token lacks %s", scopeNames(missing)),
+			"Run `ga4 auth login` again and allow every permission it asks for")
+	}
+	result.Details = scopeNames(want)
+	return result
+}
+
+// CheckAPIs probes each API the config uses, every one without a config, and
+// reports those disabled in the credential's Google Cloud project. Only a
+// "service disabled" error fails a probe: any other answer, even a 403 or a
+// 404, comes from an enabled API.
+func (d *Doctor) CheckAPIs() []ValidationResult {
+	probes := d.enabledProbes()
+	client, err := d.authClient(d.ctx, d.scopes())
+	if err != nil {
+		results := make([]ValidationResult, len(probes))
+		for i, p := range probes {
+			results[i] = failed(ValidationResult{Name: p.Name, Description: "Check the " + p.Name + " is enabled"}, err, "")
+		}
+		return results
+	}
+
+	var results []ValidationResult
+	for _, p := range probes {
+		results = append(results, d.probeAPI(client, p))
+	}
+	return results
+}
+
+func (d *Doctor) probeAPI(client *http.Client, p apiProbe) ValidationResult {
+	result := ValidationResult{
+		Name:        p.Name,
+		Description: "Check the " + p.Name + " is enabled",
+		Status:      ValidationPassed,
+		Details:     p.Service,
+	}
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return failed(result, err, "")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return failed(result, err, "")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	err = googleapi.CheckResponse(resp)
+	var apiErr *googleapi.Error
+	switch {
+	case err == nil:
+		return result
+	case !errors.As(err, &apiErr):
+		return failed(result, err, "")
+	case apiErr.Code == http.StatusUnauthorized:
+		return failed(result, fmt.Errorf("credential rejected: %s", apiErr.Message),
+			"Run `ga4 auth login` again, or create a new key for the service account")
+	}
+	if activation, disabled := serviceDisabled(apiErr); disabled {
+		if activation == "" {
+			activation = "https://console.cloud.google.com/apis/library/" + p.Service
+		}
+		return failed(result, fmt.Errorf("%s is not enabled in the credential's Google Cloud project", p.Service),
+			"Enable it at "+activation+", then wait a few minutes")
+	}
+	d.logger.Debug("API probe answered", "api", p.Name, "status", apiErr.Code, "message", apiErr.Message)
+	return result
+}
+
+// serviceDisabled reports whether err says the API is disabled, with the
+// console URL that enables it when Google gives one.
+func serviceDisabled(err *googleapi.Error) (activationURL string, disabled bool) {
+	for _, item := range err.Errors {
+		if item.Reason == "accessNotConfigured" || item.Reason == "SERVICE_DISABLED" {
+			disabled = true
+		}
+	}
+	for _, detail := range err.Details {
+		info, ok := detail.(map[string]any)
+		if !ok || info["reason"] != "SERVICE_DISABLED" {
+			continue
+		}
+		disabled = true
+		if metadata, ok := info["metadata"].(map[string]any); ok {
+			activationURL, _ = metadata["activationUrl"].(string)
+		}
+	}
+	return activationURL, disabled
+}
+
+// enabledProbes returns the probes of the APIs the config uses.
+func (d *Doctor) enabledProbes() []apiProbe {
+	if d.config == nil {
+		return d.probes
+	}
+	var probes []apiProbe
+	for _, p := range d.probes {
+		if p.Service == "searchconsole.googleapis.com" && d.config.HasSearchConsole() ||
+			p.Service != "searchconsole.googleapis.com" && d.config.HasAnalytics() {
+			probes = append(probes, p)
+		}
+	}
+	return probes
+}
+
+// scopes returns the scopes the config needs, adding read-only Analytics
+// for reports; without a config, those of `ga4 auth login`.
+func (d *Doctor) scopes() []string {
+	if d.config == nil {
+		return auth.Scopes
+	}
+	scopes := requiredScopes(d.config)
+	if d.config.HasAnalytics() {
+		scopes = append(scopes, "https://www.googleapis.com/auth/analytics.readonly")
+	}
+	if d.config.TagManager != nil {
+		scopes = append(scopes, "https://www.googleapis.com/auth/tagmanager.readonly")
+	}
+	return scopes
+}
+
+// tokenScopes mints an access token with the resolved credentials and asks
+// Google's tokeninfo endpoint which scopes it carries.
+func tokenScopes(ctx context.Context, scopes []string) ([]string, error) {
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(creds.Path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := google.CredentialsFromJSON(ctx, data, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.TokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://oauth2.googleapis.com/tokeninfo?access_token="+url.QueryEscape(token.AccessToken), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tokeninfo returned %s", resp.Status)
+	}
+	var info struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("cannot read tokeninfo: %w", err)
+	}
+	return strings.Fields(info.Scope), nil
+}
+
+// authorizedClient returns an HTTP client that authenticates with the
+// resolved credentials.
+func authorizedClient(ctx context.Context, scopes []string) (*http.Client, error) {
+	creds, err := auth.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	client, _, err := htransport.NewClient(ctx, creds.ClientOption(), option.WithScopes(scopes...))
+	return client, err
+}
+
+// failed marks result failed with err and what to do about it.
+func failed(result ValidationResult, err error, fix string) ValidationResult {
+	result.Status = ValidationFailed
+	result.Error = err
+	result.Details = fix
+	return result
+}
+
+func hostOf(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return endpoint
+}
@@ -0,0 +1,138 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// newTestDoctor returns a doctor whose endpoints and API probes all point at
+// srv.
+func newTestDoctor(cfg *config.ProjectConfig, srv *httptest.Server) *Doctor {
+	d := NewDoctor(cfg, nil, nil, discardLogger())
+	d.endpoints = []string{srv.URL + "/"}
+	for i := range d.probes {
+		d.probes[i].URL = srv.URL + "/" + d.probes[i].Service
+	}
+	d.http = srv.Client()
+	d.authClient = func(context.Context, []string) (*http.Client, error) { return srv.Client(), nil }
+	return d
+}
+
+func TestDoctor_NetworkAndClock(t *testing.T) {
+	serverTime := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   ValidationStatus
+	}{
+		{"in sync", 2 * time.Second, ValidationPassed},
+		{"drifting", -time.Minute, ValidationWarning},
+		{"too far off", 6 * time.Minute, ValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDoctor(nil, srv)
+			d.now = func() time.Time { return serverTime.Add(tt.offset) }
+
+			assert.Equal(t, ValidationPassed, d.CheckNetwork().Status, "a 404 still proves the host is reachable")
+			assert.Equal(t, tt.want, d.CheckClockSkew().Status)
+		})
+	}
+}
+
+func TestDoctor_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	d := newTestDoctor(nil, srv)
+	srv.Close()
+
+	result := d.CheckNetwork()
+
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.Contains(t, result.Error.Error(), "cannot reach 127.0.0.1:")
+	assert.Contains(t, result.Details, "HTTPS_PROXY")
+	assert.Equal(t, ValidationSkipped, d.CheckClockSkew().Status)
+}
+
+func TestDoctor_CheckScopes(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Analytics:     &config.AnalyticsConfig{PropertyID: "123"},
+		SearchConsole: &config.SearchConsoleConfig{SiteURL: "sc-domain:example.com"},
+	}
+	d := NewDoctor(cfg, nil, nil, discardLogger())
+	d.grantedScopes = func(_ context.Context, scopes []string) ([]string, error) {
+		return []string{
+			"https://www.googleapis.com/auth/analytics.edit",
+			"https://www.googleapis.com/auth/analytics.readonly",
+		}, nil
+	}
+
+	result := d.CheckScopes()
+
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.EqualError(t, result.Error, "token lacks webmasters")
+	assert.Contains(t, result.Details, "ga4 auth login")
+
+	d.grantedScopes = func(context.Context, []string) ([]string, error) { return nil, errors.New("invalid_grant") }
+	result = d.CheckScopes()
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.EqualError(t, result.Error, "cannot get a token: invalid_grant")
+}
+
+func TestDoctor_CheckAPIs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/analyticsadmin.googleapis.com":
+			_, _ = w.Write([]byte(`{"accountSummaries": []}`))
+		case "/analyticsdata.googleapis.com":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"code": 403, "status": "PERMISSION_DENIED",
+				"message": "Google Analytics Data API has not been used in project 42 before or it is disabled.",
+				"details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "SERVICE_DISABLED",
+					"metadata": {"activationUrl": "https://console.developers.google.com/apis/api/analyticsdata.googleapis.com/overview?project=42"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "User does not have sufficient permission",
+				"errors": [{"reason": "forbidden"}]}}`))
+		}
+	}))
+	defer srv.Close()
+
+	results := newTestDoctor(nil, srv).CheckAPIs()
+
+	require.Len(t, results, 3)
+	assert.Equal(t, ValidationPassed, results[0].Status)
+	assert.Equal(t, "Data API", results[1].Name)
+	assert.Equal(t, ValidationFailed, results[1].Status)
+	assert.EqualError(t, results[1].Error, "analyticsdata.googleapis.com is not enabled in the credential's Google Cloud project")
+	assert.Contains(t, results[1].Details, "https://console.developers.google.com/apis/api/analyticsdata.googleapis.com/overview?project=42")
+	assert.Equal(t, ValidationPassed, results[2].Status, "a permission error comes from an enabled API")
+}
+
+func TestDoctor_CheckAPIs_OnlyConfiguredServices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	cfg := &config.ProjectConfig{SearchConsole: &config.SearchConsoleConfig{SiteURL: "sc-domain:example.com"}}
+
+	results := newTestDoctor(cfg, srv).CheckAPIs()
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "Search Console API", results[0].Name)
+}
@@ -0,0 +1,156 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+// SitemapLister is the slice of the GSC client ImportConfig needs.
+type SitemapLister interface {
+	ListSitemaps(siteURL string) ([]gsc.SitemapInfo, error)
+}
+
+// ImportOptions says what ImportConfig reads. Sitemaps is only used with a
+// SiteURL.
+type ImportOptions struct {
+	Name       string
+	PropertyID string
+	SiteURL    string
+	Sitemaps   SitemapLister
+}
+
+// ImportConfig reads a live property, and optionally its Search Console
+// site, into a project config: the conversions, custom dimensions and
+// metrics, calculated metrics, custom channel groups, data retention and
+// enhanced measurement of the property, and the sitemaps submitted for the
+// site. Running setup with the result changes nothing.
+func ImportConfig(opts ImportOptions, lister DiffLister) (*config.ProjectConfig, error) {
+	if opts.PropertyID == "" && opts.SiteURL == "" {
+		return nil, fmt.Errorf("a GA4 property ID or a Search Console site URL is required")
+	}
+	cfg := &config.ProjectConfig{Project: config.ProjectInfo{Name: opts.Name}}
+	if opts.PropertyID != "" {
+		if err := importProperty(cfg, opts.PropertyID, lister); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SiteURL != "" {
+		sc := &config.SearchConsoleConfig{SiteURL: opts.SiteURL}
+		if opts.Sitemaps != nil {
+			sitemaps, err := opts.Sitemaps.ListSitemaps(opts.SiteURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list sitemaps: %w", err)
+			}
+			for _, s := range sitemaps {
+				sc.Sitemaps = append(sc.Sitemaps, config.SitemapConfig{URL: s.Path})
+			}
+		}
+		cfg.SearchConsole = sc
+	}
+	return cfg, nil
+}
+
+func importProperty(cfg *config.ProjectConfig, propertyID string, lister DiffLister) error {
+	cfg.GA4.PropertyID = propertyID
+
+	conversions, err := lister.ListConversions(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list conversions: %w", err)
+	}
+	for _, c := range conversions {
+		conv := config.ConversionConfig{Name: c.EventName, CountingMethod: c.CountingMethod}
+		if conv.CountingMethod == "" || strings.HasSuffix(conv.CountingMethod, "_UNSPECIFIED") {
+			conv.CountingMethod = "ONCE_PER_EVENT"
+		}
+		if v := c.DefaultConversionValue; v != nil {
+			value := v.Value
+			conv.DefaultValue, conv.Currency = &value, v.CurrencyCode
+		}
+		cfg.Conversions = append(cfg.Conversions, conv)
+	}
+
+	dimensions, err := lister.ListDimensions(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list dimensions: %w", err)
+	}
+	for _, d := range dimensions {
+		cfg.Dimensions = append(cfg.Dimensions, dimensionConfig(d))
+	}
+
+	metrics, err := lister.ListCustomMetrics(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list metrics: %w", err)
+	}
+	for _, m := range metrics {
+		cfg.Metrics = append(cfg.Metrics, metricConfig(m))
+	}
+
+	calculated, err := lister.ListPropertyCalculatedMetrics(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list calculated metrics: %w", err)
+	}
+	for _, c := range calculated {
+		metric := config.CalculatedMetricConfig{Name: c.DisplayName, Formula: c.Formula, Description: c.Description}
+		if c.MetricUnit != "STANDARD" {
+			metric.MetricUnit = c.MetricUnit
+		}
+		// The id is only written when the name does not already give it.
+		if ga4.CalculatedMetricID(metric) != c.CalculatedMetricId {
+			metric.ID = c.CalculatedMetricId
+		}
+		cfg.CalculatedMetrics = append(cfg.CalculatedMetrics, metric)
+	}
+
+	groups, err := lister.ListChannelGroups(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to list channel groups: %w", err)
+	}
+	for _, g := range groups {
+		if g.SystemDefined {
+			continue
+		}
+		group := config.ChannelGroupConfig{DisplayName: g.DisplayName, Description: g.Description}
+		for _, r := range g.GroupingRule {
+			expression, err := ga4.FormatChannelExpression(r.Expression)
+			if err != nil {
+				return fmt.Errorf("channel group %s, rule %s: %w", g.DisplayName, r.DisplayName, err)
+			}
+			group.Rules = append(group.Rules, config.ChannelRuleConfig{DisplayName: r.DisplayName, Expression: expression})
+		}
+		cfg.ChannelGroups = append(cfg.ChannelGroups, group)
+	}
+
+	retention, err := lister.GetDataRetention(propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to read data retention: %w", err)
+	}
+	cfg.DataRetention = &config.DataRetentionConfig{
+		EventDataRetention:         retention.EventDataRetention,
+		ResetUserDataOnNewActivity: retention.ResetUserDataOnNewActivity,
+	}
+
+	em, err := lister.GetPropertyEnhancedMeasurement(propertyID)
+	switch {
+	case errors.Is(err, ga4.ErrNoWebStream):
+		// An app-only property has no enhanced measurement.
+	case err != nil:
+		return fmt.Errorf("failed to read enhanced measurement: %w", err)
+	default:
+		cfg.EnhancedMeasurement = &config.EnhancedMeasurementConfig{
+			PageViews:        em.StreamEnabled,
+			Scrolls:          em.ScrollsEnabled,
+			OutboundClicks:   em.OutboundClicksEnabled,
+			SiteSearch:       em.SiteSearchEnabled,
+			VideoEngagement:  em.VideoEngagementEnabled,
+			FileDownloads:    em.FileDownloadsEnabled,
+			PageChanges:      em.PageChangesEnabled,
+			FormInteractions: em.FormInteractionsEnabled,
+		}
+	}
+	return nil
+}
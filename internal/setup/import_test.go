@@ -0,0 +1,77 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/ga4"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+)
+
+type fakeSitemapLister []gsc.SitemapInfo
+
+func (f fakeSitemapLister) ListSitemaps(string) ([]gsc.SitemapInfo, error) { return f, nil }
+
+func TestImportConfig(t *testing.T) {
+	emailRule := &admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{
+		AndGroup: &admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{FilterExpressions: []*admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{{
+			OrGroup: &admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpressionList{FilterExpressions: []*admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterExpression{{
+				Filter: &admin.GoogleAnalyticsAdminV1alphaChannelGroupFilter{
+					FieldName:    "sessionMedium",
+					StringFilter: &admin.GoogleAnalyticsAdminV1alphaChannelGroupFilterStringFilter{MatchType: "EXACT", Value: "email"},
+				},
+			}}},
+		}}},
+	}
+	lister := &fakeDiffLister{
+		fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+			{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT",
+				DefaultConversionValue: &admin.GoogleAnalyticsAdminV1alphaConversionEventDefaultConversionValue{Value: 10, CurrencyCode: "EUR"}},
+			{EventName: "sign_up", CountingMethod: "ONCE_PER_SESSION"},
+		}},
+		dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"}},
+		metrics: []*admin.GoogleAnalyticsAdminV1alphaCustomMetric{{
+			ParameterName: "revenue_net", DisplayName: "Net Revenue", MeasurementUnit: "CURRENCY", Scope: "EVENT",
+			RestrictedMetricType: []string{"REVENUE_DATA"},
+		}},
+		calculated: []*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric{
+			{CalculatedMetricId: "revenue_per_user", DisplayName: "Revenue per user", Formula: "totalRevenue / totalUsers", MetricUnit: "STANDARD"},
+			{CalculatedMetricId: "aov", DisplayName: "Average order value", Formula: "totalRevenue / transactions", MetricUnit: "CURRENCY"},
+		},
+		groups: []*admin.GoogleAnalyticsAdminV1alphaChannelGroup{
+			{DisplayName: "Default Channel Group", SystemDefined: true},
+			{DisplayName: "Marketing", GroupingRule: []*admin.GoogleAnalyticsAdminV1alphaGroupingRule{{DisplayName: "Email", Expression: emailRule}}},
+		},
+		retention: &ga4.DataRetentionSettings{EventDataRetention: "FOURTEEN_MONTHS", ResetUserDataOnNewActivity: true},
+		em:        &admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings{StreamEnabled: true, ScrollsEnabled: true},
+	}
+
+	cfg, err := ImportConfig(ImportOptions{
+		Name: "Imported", PropertyID: "123", SiteURL: "https://example.com/",
+		Sitemaps: fakeSitemapLister{{Path: "https://example.com/sitemap.xml"}},
+	}, lister)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Conversions, 2)
+	assert.Equal(t, 10.0, *cfg.Conversions[0].DefaultValue)
+	assert.Equal(t, "EUR", cfg.Conversions[0].Currency)
+	assert.Equal(t, "REVENUE_DATA", cfg.Metrics[0].RestrictedMetricType)
+	assert.Empty(t, cfg.CalculatedMetrics[0].ID, "the id follows from the name")
+	assert.Empty(t, cfg.CalculatedMetrics[0].MetricUnit)
+	assert.Equal(t, "aov", cfg.CalculatedMetrics[1].ID)
+	require.Len(t, cfg.ChannelGroups, 1, "system channel groups are left out")
+	assert.Equal(t, "sessionMedium == 'email'", cfg.ChannelGroups[0].Rules[0].Expression)
+	assert.True(t, cfg.EnhancedMeasurement.PageViews)
+	assert.Equal(t, "https://example.com/sitemap.xml", cfg.SearchConsole.Sitemaps[0].URL)
+
+	// Setup would change nothing on the property the config came from.
+	diff, err := BuildDiff(cfg, lister)
+	require.NoError(t, err)
+	for _, c := range diff.Changes {
+		assert.Equal(t, DiffActionNoop, c.Action, "%s %s", c.Resource, c.Name)
+	}
+	assert.Len(t, diff.Changes, 9)
+}
@@ -0,0 +1,186 @@
+package setup
+
+import (
+	"fmt"
+	"sort"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// DroppedResource is a configured resource setup leaves out because creating
+// it would take the property past its tier's limits.
+type DroppedResource struct {
+	Resource string // DiffResourceConversion, DiffResourceDimension or DiffResourceMetric
+	Name     string
+	Priority string
+}
+
+// FitTierLimits keeps setup within the limits of the config's GA4 tier. It
+// counts the conversions, custom dimensions (per scope) and custom metrics on
+// the property plus the ones cfg would create; where a count goes past its
+// limit, the lowest priority resources still to be created are removed from
+// cfg and returned, so setup skips them instead of failing halfway. Resources
+// already on the property are never dropped, and resource types outside the
+// setup scope are not checked.
+func FitTierLimits(cfg *config.ProjectConfig, lister ResourceLister) ([]DroppedResource, error) {
+	propertyID := cfg.GetPropertyID()
+	limits := config.GetTierLimits(cfg.GA4.Tier)
+	scope := cfg.SetupScope()
+	var dropped []DroppedResource
+
+	if scope.Includes(config.ResourceConversions) && len(cfg.Conversions) > 0 {
+		existing, err := lister.ListConversions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("list conversions: %w", err)
+		}
+		names := make(map[string]bool)
+		for _, c := range existing {
+			names[c.EventName] = true
+		}
+		var out []config.ConversionConfig
+		cfg.Conversions, out = fitLimit(cfg.Conversions,
+			func(c config.ConversionConfig) bool { return names[c.Name] },
+			func(config.ConversionConfig) string { return "" },
+			map[string]int{"": limits.Conversions - len(existing)},
+			func(c config.ConversionConfig) string { return c.Priority })
+		for _, c := range out {
+			dropped = append(dropped, DroppedResource{Resource: DiffResourceConversion, Name: c.Name, Priority: c.Priority})
+		}
+	}
+
+	if scope.Includes(config.ResourceDimensions) && len(cfg.Dimensions) > 0 {
+		existing, err := lister.ListDimensions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("list dimensions: %w", err)
+		}
+		params := make(map[string]bool)
+		room := make(map[string]int)
+		for _, s := range config.DimensionScopes() {
+			room[s] = limits.DimensionLimit(s)
+		}
+		for _, d := range existing {
+			params[d.ParameterName] = true
+			room[config.DimensionScope(d.Scope)]--
+		}
+		var out []config.DimensionConfig
+		cfg.Dimensions, out = fitLimit(cfg.Dimensions,
+			func(d config.DimensionConfig) bool { return params[d.ParameterName] },
+			func(d config.DimensionConfig) string { return config.DimensionScope(d.Scope) },
+			room,
+			func(d config.DimensionConfig) string { return d.Priority })
+		for _, d := range out {
+			dropped = append(dropped, DroppedResource{Resource: DiffResourceDimension, Name: d.ParameterName, Priority: d.Priority})
+		}
+	}
+
+	if scope.Includes(config.ResourceMetrics) && len(cfg.Metrics) > 0 {
+		existing, err := lister.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("list metrics: %w", err)
+		}
+		params := make(map[string]bool)
+		for _, m := range existing {
+			params[m.ParameterName] = true
+		}
+		var out []config.MetricConfig
+		cfg.Metrics, out = fitLimit(cfg.Metrics,
+			func(m config.MetricConfig) bool { return params[m.ParameterName] },
+			func(config.MetricConfig) string { return "" },
+			map[string]int{"": limits.CustomMetrics - len(existing)},
+			func(m config.MetricConfig) string { return m.Priority })
+		for _, m := range out {
+			dropped = append(dropped, DroppedResource{Resource: DiffResourceMetric, Name: m.ParameterName, Priority: m.Priority})
+		}
+	}
+
+	return dropped, nil
+}
+
+// fitLimit splits items into those kept and those dropped so that the new
+// ones (not exists) of each bucket fit its room. Within a bucket the highest
+// priority items are kept, in config order within a priority; both results
+// keep config order.
+func fitLimit[T any](items []T, exists func(T) bool, bucket func(T) string, room map[string]int, priority func(T) string) (kept, dropped []T) {
+	candidates := make(map[string][]int)
+	for i, item := range items {
+		if !exists(item) {
+			b := bucket(item)
+			candidates[b] = append(candidates[b], i)
+		}
+	}
+
+	drop := make(map[int]bool)
+	for b, indexes := range candidates {
+		n := max(room[b], 0)
+		if len(indexes) <= n {
+			continue
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return config.PriorityRank(priority(items[indexes[i]])) < config.PriorityRank(priority(items[indexes[j]]))
+		})
+		for _, i := range indexes[n:] {
+			drop[i] = true
+		}
+	}
+	if len(drop) == 0 {
+		return items, nil
+	}
+
+	for i, item := range items {
+		if drop[i] {
+			dropped = append(dropped, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	return kept, dropped
+}
+
+// listedResources remembers what a ResourceLister returned, so BuildDiff
+// lists each resource type once for both the tier limits and the diff.
+type listedResources struct {
+	lister      ResourceLister
+	conversions []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	dimensions  []*admin.GoogleAnalyticsAdminV1alphaCustomDimension
+	metrics     []*admin.GoogleAnalyticsAdminV1alphaCustomMetric
+	listed      map[config.SetupResource]bool
+}
+
+func newListedResources(lister ResourceLister) *listedResources {
+	return &listedResources{lister: lister, listed: make(map[config.SetupResource]bool)}
+}
+
+func (l *listedResources) ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+	if !l.listed[config.ResourceConversions] {
+		list, err := l.lister.ListConversions(propertyID)
+		if err != nil {
+			return nil, err
+		}
+		l.conversions, l.listed[config.ResourceConversions] = list, true
+	}
+	return l.conversions, nil
+}
+
+func (l *listedResources) ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	if !l.listed[config.ResourceDimensions] {
+		list, err := l.lister.ListDimensions(propertyID)
+		if err != nil {
+			return nil, err
+		}
+		l.dimensions, l.listed[config.ResourceDimensions] = list, true
+	}
+	return l.dimensions, nil
+}
+
+func (l *listedResources) ListCustomMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	if !l.listed[config.ResourceMetrics] {
+		list, err := l.lister.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, err
+		}
+		l.metrics, l.listed[config.ResourceMetrics] = list, true
+	}
+	return l.metrics, nil
+}
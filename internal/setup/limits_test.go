@@ -0,0 +1,108 @@
+package setup
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4/ga4fake"
+)
+
+func TestFitTierLimits(t *testing.T) {
+	lister := &fakeDiffLister{}
+	for i := range 29 {
+		lister.conversions = append(lister.conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	for i := range 24 {
+		lister.dimensions = append(lister.dimensions, &admin.GoogleAnalyticsAdminV1alphaCustomDimension{ParameterName: fmt.Sprintf("user_%d", i), Scope: "USER"})
+	}
+	cfg := &config.ProjectConfig{
+		GA4: config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{
+			{Name: "event_0"},
+			{Name: "share"},
+			{Name: "newsletter_signup", Priority: "medium"},
+			{Name: "purchase", Priority: "high"},
+		},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "ui_theme", Scope: "USER", Priority: "low"},
+			{ParameterName: "plan_name", Scope: "USER", Priority: "high"},
+			{ParameterName: "author", Scope: "EVENT", Priority: "low"},
+		},
+		Metrics: []config.MetricConfig{{ParameterName: "reading_time"}},
+	}
+
+	dropped, err := FitTierLimits(cfg, lister)
+	require.NoError(t, err)
+
+	assert.Equal(t, []DroppedResource{
+		{Resource: DiffResourceConversion, Name: "share"},
+		{Resource: DiffResourceConversion, Name: "newsletter_signup", Priority: "medium"},
+		{Resource: DiffResourceDimension, Name: "ui_theme", Priority: "low"},
+	}, dropped)
+	assert.Equal(t, []config.ConversionConfig{{Name: "event_0"}, {Name: "purchase", Priority: "high"}}, cfg.Conversions)
+	assert.Equal(t, []config.DimensionConfig{
+		{ParameterName: "plan_name", Scope: "USER", Priority: "high"},
+		{ParameterName: "author", Scope: "EVENT", Priority: "low"},
+	}, cfg.Dimensions)
+	assert.Len(t, cfg.Metrics, 1)
+}
+
+func TestFitTierLimits_360(t *testing.T) {
+	lister := &fakeDiffLister{}
+	for i := range 29 {
+		lister.conversions = append(lister.conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	cfg := &config.ProjectConfig{
+		GA4:         config.GA4Config{PropertyID: "123", Tier: "360"},
+		Conversions: []config.ConversionConfig{{Name: "share"}, {Name: "purchase"}},
+	}
+
+	dropped, err := FitTierLimits(cfg, lister)
+	require.NoError(t, err)
+	assert.Empty(t, dropped)
+	assert.Len(t, cfg.Conversions, 2)
+}
+
+func TestTierLimitsWarning(t *testing.T) {
+	warning := tierLimitsWarning("GA4 Standard (Free)", []DroppedResource{
+		{Resource: DiffResourceDimension, Name: "ui_theme", Priority: "low"},
+		{Resource: DiffResourceConversion, Name: "share"},
+	})
+	assert.Contains(t, warning, "2 resources would go past the GA4 Standard (Free) limits")
+	assert.Contains(t, warning, "dimension ui_theme (low priority)")
+	assert.Contains(t, warning, "conversion share (no priority)")
+}
+
+func TestCheckTierLimits_LeavesConfigAlone(t *testing.T) {
+	fake := &ga4fake.Admin{}
+	for i := range 30 {
+		fake.Conversions = append(fake.Conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	cfg := &config.ProjectConfig{
+		GA4:         config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "share"}, {Name: "purchase", Priority: "high"}},
+	}
+	pv := NewPreflightValidator(cfg, fake, nil, discardLogger())
+
+	result := pv.CheckTierLimits()
+	assert.Equal(t, ValidationWarning, result.Status)
+	assert.Contains(t, result.Warning, "2 resources would go past")
+	assert.Len(t, cfg.Conversions, 2)
+}
+
+func TestOrchestratorFitTierLimits(t *testing.T) {
+	fake := &ga4fake.Admin{}
+	for i := range 29 {
+		fake.Conversions = append(fake.Conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	cfg := setupConfig()
+	so := NewSetupOrchestrator(cfg, "", fake, nil, discardLogger(), true)
+
+	so.fitTierLimits()
+	assert.Equal(t, []config.ConversionConfig{{Name: "purchase", CountingMethod: "ONCE_PER_EVENT"}}, cfg.Conversions)
+}
@@ -0,0 +1,214 @@
+package setup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// liveRefresh is how often the live display redraws.
+const liveRefresh = 100 * time.Millisecond
+
+// spinnerFrames animate each call in flight; plain-ascii gets ASCII ones.
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiSpinnerFrames = []string{"|", "/", "-", "\\"}
+)
+
+// liveOutput reports whether setup can redraw progress in place: stdout is
+// a terminal and neither --quiet nor --json silenced it. Otherwise the
+// per-resource lines are the progress, as in a log.
+func liveOutput() bool {
+	return !theme.Quiet() && (isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()))
+}
+
+// liveProgress draws, under the lines a setup section prints, a spinner for
+// every API call in flight and a status line: calls done out of the
+// section's total, elapsed time and an ETA from the latency of the calls so
+// far. The block is erased before each outcome is printed and drawn again
+// after it, so outcomes scroll above it as in a plain run.
+type liveProgress struct {
+	w       io.Writer
+	label   string
+	total   int
+	workers int
+	now     func() time.Time
+
+	mu       sync.Mutex
+	start    time.Time
+	done     int
+	latency  time.Duration // summed over finished calls
+	inFlight map[int]liveCall
+	frame    int
+	drawn    int // lines of the block on screen
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// liveCall is an API call in flight.
+type liveCall struct {
+	name  string
+	start time.Time
+}
+
+// newLiveProgress starts drawing the progress of total calls, workers at a
+// time, on w. Call close when the section is done.
+func newLiveProgress(w io.Writer, label string, total, workers int) *liveProgress {
+	p := &liveProgress{
+		w:        w,
+		label:    label,
+		total:    total,
+		workers:  max(workers, 1),
+		now:      time.Now,
+		start:    time.Now(),
+		inFlight: make(map[int]liveCall),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go p.loop()
+	return p
+}
+
+func (p *liveProgress) loop() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(liveRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			p.redraw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// begin records that step i started its API call for name.
+func (p *liveProgress) begin(i int, name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[i] = liveCall{name: name, start: p.now()}
+}
+
+// finish records that step i's API call returned.
+func (p *liveProgress) finish(i int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.inFlight[i]; ok {
+		p.latency += p.now().Sub(c.start)
+		p.done++
+		delete(p.inFlight, i)
+	}
+}
+
+// print runs f, which prints an outcome, with the block out of its way.
+func (p *liveProgress) print(f func()) {
+	if p == nil {
+		f()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.erase()
+	f()
+	p.draw()
+}
+
+// close stops redrawing and erases the block.
+func (p *liveProgress) close() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.erase()
+}
+
+// redraw replaces the block on screen. The caller holds p.mu.
+func (p *liveProgress) redraw() {
+	p.erase()
+	p.draw()
+}
+
+func (p *liveProgress) draw() {
+	lines := p.render()
+	_, _ = io.WriteString(p.w, strings.Join(lines, "\n")+"\n")
+	p.drawn = len(lines)
+}
+
+// erase moves the cursor up over the block and clears to the end of the
+// screen.
+func (p *liveProgress) erase() {
+	if p.drawn > 0 {
+		_, _ = fmt.Fprintf(p.w, "\x1b[%dA\x1b[J", p.drawn)
+		p.drawn = 0
+	}
+}
+
+// render returns the block: one line per call in flight, oldest first, then
+// the status line. The caller holds p.mu.
+func (p *liveProgress) render() []string {
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+	cyan := theme.Color(color.FgCyan).SprintFunc()
+	frames := spinnerFrames
+	if theme.Current() == theme.PlainASCII {
+		frames = asciiSpinnerFrames
+	}
+	now := p.now()
+
+	calls := make([]int, 0, len(p.inFlight))
+	for i := range p.inFlight {
+		calls = append(calls, i)
+	}
+	slices.Sort(calls)
+	lines := make([]string, 0, len(calls)+1)
+	for n, i := range calls {
+		c := p.inFlight[i]
+		frame := frames[(p.frame+n)%len(frames)]
+		lines = append(lines, fmt.Sprintf("  %s %s %s", cyan(frame), c.name, gray(formatSeconds(now.Sub(c.start)))))
+	}
+
+	status := fmt.Sprintf("  %s %d/%d done, %d in flight, %s elapsed", p.label, p.done, p.total, len(p.inFlight), formatSeconds(now.Sub(p.start)))
+	if eta, ok := p.eta(); ok {
+		status += ", ETA " + formatSeconds(eta)
+	}
+	return append(lines, gray(status))
+}
+
+// eta estimates the time left from the mean latency of finished calls: the
+// calls left run workers at a time. There is no estimate before the first
+// call returns.
+func (p *liveProgress) eta() (time.Duration, bool) {
+	if p.done == 0 {
+		return 0, false
+	}
+	mean := p.latency / time.Duration(p.done)
+	left := p.total - p.done
+	waves := (left + p.workers - 1) / p.workers
+	return mean * time.Duration(waves), true
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
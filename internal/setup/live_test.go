@@ -0,0 +1,82 @@
+package setup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stoppedLive returns a liveProgress on w without its redraw loop, on a
+// clock the test moves.
+func stoppedLive(w *bytes.Buffer, total, workers int) (*liveProgress, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	return &liveProgress{
+		w:        w,
+		label:    "Custom dimensions",
+		total:    total,
+		workers:  workers,
+		now:      func() time.Time { return now },
+		start:    now,
+		inFlight: make(map[int]liveCall),
+	}, &now
+}
+
+func TestLiveProgress_Render(t *testing.T) {
+	p, now := stoppedLive(&bytes.Buffer{}, 10, 2)
+
+	p.begin(0, "Author")
+	p.begin(1, "Category")
+	*now = now.Add(2 * time.Second)
+	p.finish(0)
+	p.begin(2, "Word Count")
+	*now = now.Add(500 * time.Millisecond)
+
+	lines := p.render()
+
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "Category 2.5s")
+	assert.Contains(t, lines[1], "Word Count 0.5s")
+	// 9 calls left, 2 at a time, 2s each
+	assert.Contains(t, lines[2], "Custom dimensions 1/10 done, 2 in flight, 2.5s elapsed, ETA 10.0s")
+}
+
+func TestLiveProgress_NoETABeforeFirstCall(t *testing.T) {
+	p, _ := stoppedLive(&bytes.Buffer{}, 3, 1)
+	p.begin(0, "Author")
+
+	lines := p.render()
+
+	assert.NotContains(t, lines[len(lines)-1], "ETA")
+}
+
+func TestLiveProgress_PrintErasesBlock(t *testing.T) {
+	var out bytes.Buffer
+	p, _ := stoppedLive(&out, 2, 1)
+	p.begin(0, "Author")
+	p.redraw()
+
+	p.print(func() { out.WriteString("  ✓ Author\n") })
+
+	// Two lines were drawn (a call and the status); they are cleared
+	// before the outcome and drawn again after it.
+	s := out.String()
+	i := strings.Index(s, "\x1b[2A\x1b[J  ✓ Author\n")
+	require.GreaterOrEqual(t, i, 0, "%q", s)
+	assert.Contains(t, s[i:], "Custom dimensions 0/2 done")
+}
+
+func TestLiveProgress_Nil(t *testing.T) {
+	var p *liveProgress
+	printed := false
+
+	p.begin(0, "Author")
+	p.finish(0)
+	p.print(func() { printed = true })
+	p.close()
+
+	assert.True(t, printed, "without a live display outcomes print as usual")
+}
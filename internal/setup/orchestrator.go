@@ -4,32 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/fatih/color"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
 	"github.com/garbarok/ga4-manager/internal/config"
 	"github.com/garbarok/ga4-manager/internal/ga4"
 	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // SetupOrchestrator coordinates the entire setup process
 type SetupOrchestrator struct {
 	config     *config.ProjectConfig
 	configPath string
-	ga4Client  *ga4.Client
-	gscClient  *gsc.Client
+	ga4Client  ga4.GA4Admin
+	gscClient  gsc.SearchConsole
 	validator  *PreflightValidator
 	progress   *ProgressTracker
 	rollback   *RollbackManager
 	logger     *slog.Logger
 	dryRun     bool
+	scope      config.ResourceScope
+
+	// live redraws the API calls in flight; off when stdout is not a
+	// terminal or --quiet/--json is set.
+	live bool
 }
 
-// NewSetupOrchestrator creates a new setup orchestrator
+// NewSetupOrchestrator creates a new setup orchestrator. Either client may be
+// nil (an untyped nil) when the config does not use that service.
 func NewSetupOrchestrator(
 	cfg *config.ProjectConfig,
 	configPath string,
-	ga4Client *ga4.Client,
-	gscClient *gsc.Client,
+	ga4Client ga4.GA4Admin,
+	gscClient gsc.SearchConsole,
 	logger *slog.Logger,
 	dryRun bool,
 ) *SetupOrchestrator {
@@ -47,21 +57,26 @@ func NewSetupOrchestrator(
 		rollback:   rollbackMgr,
 		logger:     logger,
 		dryRun:     dryRun,
+		scope:      cfg.SetupScope(),
+		live:       liveOutput(),
 	}
 }
 
 // Execute runs the entire setup process
 func (so *SetupOrchestrator) Execute() error {
-	blue := color.New(color.FgBlue).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
 
 	// Print header
-	fmt.Println()
-	fmt.Println("🚀 GA4 Manager - Unified Setup")
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Println()
+	theme.Println()
+	theme.Println("🚀 GA4 Manager - Unified Setup")
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Println()
 
 	if so.dryRun {
-		fmt.Printf("%s Dry-run mode enabled - no changes will be applied\n\n", blue("ℹ️"))
+		theme.Printf("%s Dry-run mode enabled - no changes will be applied\n\n", blue("ℹ️"))
+	}
+	if so.scope.Narrowed() {
+		theme.Printf("%s Out-of-scope resources are ignored (--only/--skip or setup: in the config)\n\n", blue("ℹ️"))
 	}
 
 	// Step 1: Pre-flight validation
@@ -69,7 +84,12 @@ func (so *SetupOrchestrator) Execute() error {
 		return err
 	}
 
-	// Step 2: Add setup steps to tracker
+	// Step 2: Leave out new resources past the tier limits
+	if so.config.HasAnalytics() {
+		so.fitTierLimits()
+	}
+
+	// Step 3: Add setup steps to tracker
 	if so.config.HasAnalytics() {
 		so.progress.AddStep("GA4 Setup", "Configure Google Analytics 4 property")
 	}
@@ -77,7 +97,7 @@ func (so *SetupOrchestrator) Execute() error {
 		so.progress.AddStep("GSC Setup", "Configure Google Search Console property")
 	}
 
-	// Step 3: Execute GA4 setup
+	// Step 4: Execute GA4 setup
 	if so.config.HasAnalytics() {
 		so.progress.StartStep("GA4 Setup")
 		if err := so.SetupGA4(); err != nil {
@@ -88,7 +108,7 @@ func (so *SetupOrchestrator) Execute() error {
 			len(so.config.Conversions), len(so.config.Dimensions), len(so.config.Metrics)))
 	}
 
-	// Step 4: Execute GSC setup
+	// Step 5: Execute GSC setup
 	if so.config.HasSearchConsole() {
 		so.progress.StartStep("GSC Setup")
 		if err := so.SetupGSC(); err != nil {
@@ -96,25 +116,25 @@ func (so *SetupOrchestrator) Execute() error {
 			return so.handleError("GSC setup failed", err)
 		}
 
-		sitemapCount := len(so.config.SearchConsole.Sitemaps)
+		sitemapCount := len(so.config.SearchConsole.ActiveSitemaps())
 		so.progress.CompleteStep("GSC Setup", fmt.Sprintf("%d sitemaps submitted", sitemapCount))
 	}
 
-	// Step 5: Finish and display summary
+	// Step 6: Finish and display summary
 	so.progress.Finish()
 
-	fmt.Println()
-	fmt.Println(so.progress.GenerateSummary())
+	theme.Println()
+	theme.Println(so.progress.GenerateSummary())
 
 	if !so.dryRun {
 		so.printNextSteps()
 	} else {
-		fmt.Println()
-		fmt.Printf("%s Dry-run complete! No changes were applied.\n", blue("ℹ️"))
-		fmt.Println()
-		fmt.Println("Run without --dry-run to apply changes:")
-		fmt.Printf("  ./ga4 setup --config %s\n", so.configPath)
-		fmt.Println()
+		theme.Println()
+		theme.Printf("%s Dry-run complete! No changes were applied.\n", blue("ℹ️"))
+		theme.Println()
+		theme.Println("Run without --dry-run to apply changes:")
+		theme.Printf("  ./ga4 setup --config %s\n", so.configPath)
+		theme.Println()
 	}
 
 	return nil
@@ -122,19 +142,70 @@ func (so *SetupOrchestrator) Execute() error {
 
 // RunPreflight executes pre-flight validation
 func (so *SetupOrchestrator) RunPreflight() error {
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	gray := color.New(color.FgHiBlack).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
 
-	fmt.Printf("%s Pre-flight Validation\n", blue("📋"))
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Printf("%s Pre-flight Validation\n", blue("📋"))
+	theme.Println("───────────────────────────────────────────────")
 
 	// Run all validation checks
 	results, err := so.validator.ValidateAll()
+	PrintValidationResults(results)
+
+	if err != nil {
+		theme.Println()
+		return fmt.Errorf("pre-flight validation failed: %w", err)
+	}
+
+	// Detect conflicts
+	theme.Println()
+	conflicts, err := so.validator.DetectConflicts()
+	if err != nil {
+		return fmt.Errorf("conflict detection failed: %w", err)
+	}
+
+	if len(conflicts) > 0 {
+		theme.Printf("%s Detected existing resources:\n", yellow("⚠️"))
+		for _, conflict := range conflicts {
+			theme.Printf("  %s %s: %s\n", gray("○"), conflict.ResourceType, conflict.ResourceName)
+		}
+	}
+
+	theme.Println()
+	return nil
+}
+
+// fitTierLimits removes from the config the new resources that would take the
+// property past its tier's limits, the ones the Tier Limits check reported.
+// When the existing resources cannot be counted, setup goes on unfitted, as
+// the check warned.
+func (so *SetupOrchestrator) fitTierLimits() {
+	if so.ga4Client == nil {
+		return
+	}
+	dropped, err := FitTierLimits(so.config, so.ga4Client)
+	if err != nil {
+		so.logger.Warn("tier limits not applied", "error", err)
+		return
+	}
+	for _, d := range dropped {
+		so.logger.Warn("resource over tier limit left out", "resource", d.Resource, "name", d.Name, "priority", d.Priority)
+	}
+	if len(dropped) > 0 {
+		yellow := theme.Color(color.FgYellow).SprintFunc()
+		theme.Printf("%s Left out %d resources over the %s limits\n\n", yellow("⚠️"), len(dropped), config.GetTierName(so.config.GA4.Tier))
+	}
+}
+
+// PrintValidationResults prints one line per check, with its warning, or its
+// error and what to do about it.
+func PrintValidationResults(results []ValidationResult) {
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
 
-	// Display results
 	for _, result := range results {
 		var statusIcon string
 		switch result.Status {
@@ -148,45 +219,23 @@ func (so *SetupOrchestrator) RunPreflight() error {
 			statusIcon = gray("○")
 		}
 
-		fmt.Printf("  %s %s", statusIcon, result.Name)
+		theme.Printf("  %s %s", statusIcon, result.Name)
 		if result.Details != "" {
-			fmt.Printf(" %s", gray(fmt.Sprintf("(%s)", result.Details)))
+			theme.Printf(" %s", gray(fmt.Sprintf("(%s)", result.Details)))
 		}
-		fmt.Println()
+		theme.Println()
 
 		if result.Warning != "" {
-			fmt.Printf("    %s %s\n", yellow("⚠️"), result.Warning)
+			theme.Printf("    %s %s\n", yellow("⚠️"), result.Warning)
 		}
 
 		if result.Error != nil {
-			fmt.Printf("    %s %s\n", red("Error:"), result.Error.Error())
+			theme.Printf("    %s %s\n", red("Error:"), result.Error.Error())
 			if result.Details != "" {
-				fmt.Printf("    %s\n", gray(result.Details))
+				theme.Printf("    %s\n", gray(result.Details))
 			}
 		}
 	}
-
-	if err != nil {
-		fmt.Println()
-		return fmt.Errorf("pre-flight validation failed: %w", err)
-	}
-
-	// Detect conflicts
-	fmt.Println()
-	conflicts, err := so.validator.DetectConflicts()
-	if err != nil {
-		return fmt.Errorf("conflict detection failed: %w", err)
-	}
-
-	if len(conflicts) > 0 {
-		fmt.Printf("%s Detected existing resources (will skip):\n", yellow("⚠️"))
-		for _, conflict := range conflicts {
-			fmt.Printf("  %s %s: %s\n", gray("○"), conflict.ResourceType, conflict.ResourceName)
-		}
-	}
-
-	fmt.Println()
-	return nil
 }
 
 // SetupGA4 configures Google Analytics 4
@@ -196,181 +245,802 @@ func (so *SetupOrchestrator) SetupGA4() error {
 		return nil
 	}
 
-	green := color.New(color.FgGreen).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
 
 	propertyID := so.config.GetPropertyID()
 
-	fmt.Println()
-	fmt.Printf("[1/2] %s Google Analytics 4 Setup\n", blue("📊"))
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Printf("[1/2] %s Google Analytics 4 Setup\n", blue("📊"))
+	theme.Println("───────────────────────────────────────────────")
 
 	// Get existing resources to detect duplicates
-	existingConversions, err := so.ga4Client.ListConversions(propertyID)
-	if err != nil {
-		so.logger.Warn("failed to list existing conversions", "error", err)
-	}
-	conversionMap := make(map[string]bool)
-	for _, conv := range existingConversions {
-		conversionMap[conv.EventName] = true
+	conversionMap := make(map[string]*admin.GoogleAnalyticsAdminV1alphaConversionEvent)
+	if so.scope.Includes(config.ResourceConversions) {
+		existingConversions, err := so.ga4Client.ListConversions(propertyID)
+		if err != nil {
+			so.logger.Warn("failed to list existing conversions", "error", err)
+		}
+		for _, conv := range existingConversions {
+			conversionMap[conv.EventName] = conv
+		}
 	}
 
-	existingDimensions, err := so.ga4Client.ListDimensions(propertyID)
-	if err != nil {
-		so.logger.Warn("failed to list existing dimensions", "error", err)
-	}
-	dimensionMap := make(map[string]bool)
-	for _, dim := range existingDimensions {
-		dimensionMap[dim.ParameterName] = true
+	dimensionMap := make(map[string]*admin.GoogleAnalyticsAdminV1alphaCustomDimension)
+	if so.scope.Includes(config.ResourceDimensions) {
+		existingDimensions, err := so.ga4Client.ListDimensions(propertyID)
+		if err != nil {
+			so.logger.Warn("failed to list existing dimensions", "error", err)
+		}
+		for _, dim := range existingDimensions {
+			dimensionMap[dim.ParameterName] = dim
+		}
 	}
 
-	existingMetrics, err := so.ga4Client.ListCustomMetrics(propertyID)
-	if err != nil {
-		so.logger.Warn("failed to list existing metrics", "error", err)
+	metricMap := make(map[string]*admin.GoogleAnalyticsAdminV1alphaCustomMetric)
+	if so.scope.Includes(config.ResourceMetrics) {
+		existingMetrics, err := so.ga4Client.ListCustomMetrics(propertyID)
+		if err != nil {
+			so.logger.Warn("failed to list existing metrics", "error", err)
+		}
+		for _, metric := range existingMetrics {
+			metricMap[metric.ParameterName] = metric
+		}
 	}
-	metricMap := make(map[string]bool)
-	for _, metric := range existingMetrics {
-		metricMap[metric.ParameterName] = true
+
+	// Review existing resources that differ before changing anything, so
+	// on_conflict: error stops the run with the property untouched.
+	conflicts := so.existingConflicts(conversionMap, dimensionMap, metricMap)
+	if err := reviewConflicts(conflicts); err != nil {
+		return err
 	}
 
+	// Create or update conversions, dimensions and metrics several at a
+	// time; outcomes are still printed in config order.
+	workers := so.config.SetupConcurrency()
+
 	// Setup conversions
-	fmt.Printf("\n%s Creating conversions...\n", "🎯")
+	theme.Printf("\n%s Creating conversions...\n", "🎯")
 	createdCount := 0
+	updatedCount := 0
 	skippedCount := 0
+	ignoredCount := 0
 
+	steps := make([]step, 0, len(so.config.Conversions))
 	for _, conv := range so.config.Conversions {
-		if conversionMap[conv.Name] {
-			fmt.Printf("  %s %s %s\n", yellow("○"), conv.Name, blue("(already exists, skipping)"))
-			skippedCount++
+		if !so.scope.Includes(config.ResourceConversions) {
+			steps = append(steps, step{report: func(error) error {
+				theme.Printf("  %s %s %s\n", gray("○"), conv.Name, gray("(ignored: out of scope)"))
+				ignoredCount++
+				return nil
+			}})
 			continue
 		}
-
-		if so.dryRun {
-			fmt.Printf("  %s %s (counting: %s)\n", blue("○"), conv.Name, conv.CountingMethod)
-			createdCount++
-		} else {
-			err := so.ga4Client.CreateConversion(propertyID, conv.Name, conv.CountingMethod)
-			if errors.Is(err, ga4.ErrAlreadyExists) {
-				fmt.Printf("  %s %s %s\n", yellow("○"), conv.Name, blue("(conflict: already exists, skipping)"))
-				skippedCount++
+		if existing, ok := conversionMap[conv.Name]; ok {
+			if !conflicts.update(DiffResourceConversion, conv.Name) {
+				steps = append(steps, step{report: func(error) error {
+					theme.Printf("  %s %s %s\n", yellow("○"), conv.Name, blue("(already exists, skipping)"))
+					skippedCount++
+					return nil
+				}})
 				continue
 			}
-			if err != nil {
-				fmt.Printf("  %s %s: %s\n", red("✗"), conv.Name, err)
-				return fmt.Errorf("create conversion %s: %w", conv.Name, err)
+			var apply func() error
+			if !so.dryRun {
+				apply = func() error {
+					return so.ga4Client.SetConversionCountingMethod(existing, conv.CountingMethod)
+				}
 			}
+			steps = append(steps, step{name: conv.Name, apply: apply, report: func(err error) error {
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), conv.Name, err)
+					return fmt.Errorf("update conversion %s: %w", conv.Name, err)
+				}
+				if !so.dryRun {
+					so.rollback.Register(RollbackOperation{
+						Type:        "conversion",
+						ResourceID:  existing.Name,
+						PropertyID:  propertyID,
+						Description: fmt.Sprintf("Restore conversion counting method: %s", conv.Name),
+						Rollback: func() error {
+							return so.ga4Client.SetConversionCountingMethod(existing, existing.CountingMethod)
+						},
+					})
+				}
+				printUpdated(conv.Name, so.dryRun)
+				updatedCount++
+				return nil
+			}})
+			continue
+		}
 
-			// Register rollback
-			convName := conv.Name
-			so.rollback.Register(RollbackOperation{
-				Type:        "conversion",
-				ResourceID:  convName,
-				PropertyID:  propertyID,
-				Description: fmt.Sprintf("Delete conversion: %s", convName),
-				Rollback: func() error {
-					return so.ga4Client.DeleteConversion(propertyID, convName)
-				},
-			})
-
-			fmt.Printf("  %s %s\n", green("✓"), conv.Name)
-			createdCount++
+		if so.dryRun {
+			steps = append(steps, step{report: func(error) error {
+				if conv.DefaultValue != nil {
+					theme.Printf("  %s %s (counting: %s, default value: %.2f %s)\n", blue("○"), conv.Name, conv.CountingMethod, *conv.DefaultValue, so.config.ConversionCurrency(conv))
+				} else {
+					theme.Printf("  %s %s (counting: %s)\n", blue("○"), conv.Name, conv.CountingMethod)
+				}
+				createdCount++
+				return nil
+			}})
+			continue
 		}
-	}
+		steps = append(steps, step{
+			name: conv.Name,
+			apply: func() error {
+				return so.ga4Client.CreateConversionFromConfig(propertyID, conv, so.config.ConversionCurrency(conv))
+			},
+			report: func(err error) error {
+				if errors.Is(err, ga4.ErrAlreadyExists) {
+					theme.Printf("  %s %s %s\n", yellow("○"), conv.Name, blue("(conflict: already exists, skipping)"))
+					skippedCount++
+					return nil
+				}
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), conv.Name, err)
+					return fmt.Errorf("create conversion %s: %w", conv.Name, err)
+				}
 
-	if createdCount > 0 || skippedCount > 0 {
-		fmt.Printf("  Created: %d, Skipped: %d\n", createdCount, skippedCount)
+				// Register rollback
+				convName := conv.Name
+				so.rollback.Register(RollbackOperation{
+					Type:        "conversion",
+					ResourceID:  convName,
+					PropertyID:  propertyID,
+					Description: fmt.Sprintf("Delete conversion: %s", convName),
+					Rollback: func() error {
+						return so.ga4Client.DeleteConversion(propertyID, convName)
+					},
+				})
+
+				theme.Printf("  %s %s\n", green("✓"), conv.Name)
+				createdCount++
+				return nil
+			},
+		})
 	}
+	if err := so.runSteps("Conversions", steps, workers); err != nil {
+		return err
+	}
+
+	printSetupUpdateCounts(createdCount, updatedCount, skippedCount, ignoredCount)
 
 	// Setup dimensions
-	fmt.Printf("\n%s Creating custom dimensions...\n", "📊")
+	theme.Printf("\n%s Creating custom dimensions...\n", "📊")
 	createdCount = 0
+	updatedCount = 0
 	skippedCount = 0
+	ignoredCount = 0
 
+	steps = make([]step, 0, len(so.config.Dimensions))
 	for _, dim := range so.config.Dimensions {
-		if dimensionMap[dim.ParameterName] {
-			fmt.Printf("  %s %s %s\n", yellow("○"), dim.DisplayName, blue("(already exists, skipping)"))
-			skippedCount++
+		if !so.scope.Includes(config.ResourceDimensions) {
+			steps = append(steps, step{report: func(error) error {
+				theme.Printf("  %s %s %s\n", gray("○"), dim.DisplayName, gray("(ignored: out of scope)"))
+				ignoredCount++
+				return nil
+			}})
+			continue
+		}
+		if existing, ok := dimensionMap[dim.ParameterName]; ok {
+			if !conflicts.update(DiffResourceDimension, dim.ParameterName) {
+				steps = append(steps, step{report: func(error) error {
+					theme.Printf("  %s %s %s\n", yellow("○"), dim.DisplayName, blue("(already exists, skipping)"))
+					skippedCount++
+					return nil
+				}})
+				continue
+			}
+			var apply func() error
+			if !so.dryRun {
+				apply = func() error {
+					return so.ga4Client.UpdateDimension(existing.Name, dim)
+				}
+			}
+			steps = append(steps, step{name: dim.DisplayName, apply: apply, report: func(err error) error {
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), dim.DisplayName, err)
+					return fmt.Errorf("update dimension %s: %w", dim.DisplayName, err)
+				}
+				if !so.dryRun {
+					so.rollback.Register(RollbackOperation{
+						Type:        "dimension",
+						ResourceID:  existing.Name,
+						PropertyID:  propertyID,
+						Description: fmt.Sprintf("Restore dimension: %s", existing.DisplayName),
+						Rollback: func() error {
+							return so.ga4Client.RestoreDimension(existing)
+						},
+					})
+				}
+				printUpdated(dim.DisplayName, so.dryRun)
+				updatedCount++
+				return nil
+			}})
 			continue
 		}
 
 		if so.dryRun {
-			fmt.Printf("  %s %s (param: %s, scope: %s)\n", blue("○"), dim.DisplayName, dim.ParameterName, dim.Scope)
-			createdCount++
-		} else {
-			err := so.ga4Client.CreateDimension(propertyID, dim)
-			if errors.Is(err, ga4.ErrAlreadyExists) {
-				fmt.Printf("  %s %s %s\n", yellow("○"), dim.DisplayName, blue("(conflict: already exists, skipping)"))
-				skippedCount++
+			steps = append(steps, step{report: func(error) error {
+				theme.Printf("  %s %s (param: %s, scope: %s)\n", blue("○"), dim.DisplayName, dim.ParameterName, dim.Scope)
+				createdCount++
+				return nil
+			}})
+			continue
+		}
+		steps = append(steps, step{
+			name: dim.DisplayName,
+			apply: func() error {
+				return so.ga4Client.CreateDimension(propertyID, dim)
+			},
+			report: func(err error) error {
+				if errors.Is(err, ga4.ErrAlreadyExists) {
+					theme.Printf("  %s %s %s\n", yellow("○"), dim.DisplayName, blue("(conflict: already exists, skipping)"))
+					skippedCount++
+					return nil
+				}
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), dim.DisplayName, err)
+					return fmt.Errorf("create dimension %s: %w", dim.DisplayName, err)
+				}
+
+				// Note: We don't register rollback for dimensions because archiving them
+				// doesn't free up the parameter name (GA4 limitation)
+
+				theme.Printf("  %s %s\n", green("✓"), dim.DisplayName)
+				createdCount++
+				return nil
+			},
+		})
+	}
+	if err := so.runSteps("Custom dimensions", steps, workers); err != nil {
+		return err
+	}
+
+	printSetupUpdateCounts(createdCount, updatedCount, skippedCount, ignoredCount)
+
+	// Setup metrics
+	theme.Printf("\n%s Creating custom metrics...\n", "📈")
+	createdCount = 0
+	updatedCount = 0
+	skippedCount = 0
+	ignoredCount = 0
+
+	steps = make([]step, 0, len(so.config.Metrics))
+	for _, metric := range so.config.Metrics {
+		if !so.scope.Includes(config.ResourceMetrics) {
+			steps = append(steps, step{report: func(error) error {
+				theme.Printf("  %s %s %s\n", gray("○"), metric.DisplayName, gray("(ignored: out of scope)"))
+				ignoredCount++
+				return nil
+			}})
+			continue
+		}
+		if existing, ok := metricMap[metric.ParameterName]; ok {
+			if !conflicts.update(DiffResourceMetric, metric.ParameterName) {
+				steps = append(steps, step{report: func(error) error {
+					theme.Printf("  %s %s %s\n", yellow("○"), metric.DisplayName, blue("(already exists, skipping)"))
+					skippedCount++
+					return nil
+				}})
 				continue
 			}
-			if err != nil {
-				fmt.Printf("  %s %s: %s\n", red("✗"), dim.DisplayName, err)
-				return fmt.Errorf("create dimension %s: %w", dim.DisplayName, err)
+			var apply func() error
+			if !so.dryRun {
+				apply = func() error {
+					return so.ga4Client.UpdateCustomMetric(existing.Name, metric)
+				}
 			}
+			steps = append(steps, step{name: metric.DisplayName, apply: apply, report: func(err error) error {
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), metric.DisplayName, err)
+					return fmt.Errorf("update metric %s: %w", metric.DisplayName, err)
+				}
+				if !so.dryRun {
+					so.rollback.Register(RollbackOperation{
+						Type:        "metric",
+						ResourceID:  existing.Name,
+						PropertyID:  propertyID,
+						Description: fmt.Sprintf("Restore metric: %s", existing.DisplayName),
+						Rollback: func() error {
+							return so.ga4Client.RestoreCustomMetric(existing)
+						},
+					})
+				}
+				printUpdated(metric.DisplayName, so.dryRun)
+				updatedCount++
+				return nil
+			}})
+			continue
+		}
 
-			// Note: We don't register rollback for dimensions because archiving them
-			// doesn't free up the parameter name (GA4 limitation)
+		if so.dryRun {
+			steps = append(steps, step{report: func(error) error {
+				theme.Printf("  %s %s (param: %s, scope: %s, unit: %s)\n",
+					blue("○"), metric.DisplayName, metric.ParameterName, metric.Scope, metric.MeasurementUnit)
+				createdCount++
+				return nil
+			}})
+			continue
+		}
+		steps = append(steps, step{
+			name: metric.DisplayName,
+			apply: func() error {
+				return so.ga4Client.CreateCustomMetric(propertyID, metric)
+			},
+			report: func(err error) error {
+				if errors.Is(err, ga4.ErrAlreadyExists) {
+					theme.Printf("  %s %s %s\n", yellow("○"), metric.DisplayName, blue("(conflict: already exists, skipping)"))
+					skippedCount++
+					return nil
+				}
+				if err != nil {
+					theme.Printf("  %s %s: %s\n", red("✗"), metric.DisplayName, err)
+					return fmt.Errorf("create metric %s: %w", metric.DisplayName, err)
+				}
 
-			fmt.Printf("  %s %s\n", green("✓"), dim.DisplayName)
-			createdCount++
+				theme.Printf("  %s %s\n", green("✓"), metric.DisplayName)
+				createdCount++
+				return nil
+			},
+		})
+	}
+	if err := so.runSteps("Custom metrics", steps, workers); err != nil {
+		return err
+	}
+
+	printSetupUpdateCounts(createdCount, updatedCount, skippedCount, ignoredCount)
+
+	// Setup calculated metrics, after the custom metrics their formulas use
+	if len(so.config.CalculatedMetrics) > 0 {
+		if err := so.setupCalculatedMetrics(propertyID); err != nil {
+			return err
 		}
 	}
 
-	if createdCount > 0 || skippedCount > 0 {
-		fmt.Printf("  Created: %d, Skipped: %d\n", createdCount, skippedCount)
+	// Setup channel groups
+	if len(so.config.ChannelGroups) > 0 {
+		if err := so.setupChannelGroups(propertyID); err != nil {
+			return err
+		}
 	}
 
-	// Setup metrics
-	fmt.Printf("\n%s Creating custom metrics...\n", "📈")
-	createdCount = 0
-	skippedCount = 0
+	// Apply property settings
+	if so.config.DataRetention != nil {
+		if err := so.setupDataRetention(propertyID); err != nil {
+			return err
+		}
+	}
+	if so.config.EnhancedMeasurement != nil {
+		if err := so.setupEnhancedMeasurement(propertyID); err != nil {
+			return err
+		}
+	}
+
+	// Show guidance for manual tasks
+	if len(so.config.Audiences) > 0 {
+		theme.Printf("\n%s Audiences (manual setup required):\n", yellow("👥"))
+		for _, aud := range so.config.Audiences {
+			theme.Printf("  %s %s\n", yellow("○"), aud.Name)
+		}
+		theme.Printf("  %s Audiences must be created manually in GA4 UI\n", blue("ℹ️"))
+	}
 
+	return nil
+}
+
+// resourceConflict is an existing conversion, dimension or metric that
+// differs from the config, with the conflict mode that decides its fate.
+type resourceConflict struct {
+	resource string
+	name     string
+	mode     string
+	before   map[string]any
+	after    map[string]any
+}
+
+type resourceConflicts []resourceConflict
+
+// update reports whether setup patches the named resource.
+func (cs resourceConflicts) update(resource, name string) bool {
+	for _, c := range cs {
+		if c.resource == resource && c.name == name {
+			return c.mode == config.OnConflictUpdate
+		}
+	}
+	return false
+}
+
+// existingConflicts compares the in-scope conversions, dimensions and
+// metrics that already exist with the config, using the diff's states.
+func (so *SetupOrchestrator) existingConflicts(
+	conversions map[string]*admin.GoogleAnalyticsAdminV1alphaConversionEvent,
+	dimensions map[string]*admin.GoogleAnalyticsAdminV1alphaCustomDimension,
+	metrics map[string]*admin.GoogleAnalyticsAdminV1alphaCustomMetric,
+) resourceConflicts {
+	var conflicts resourceConflicts
+	add := func(resource, name, mode string, before, after map[string]any) {
+		if !sameState(before, after) {
+			conflicts = append(conflicts, resourceConflict{resource: resource, name: name, mode: so.config.ConflictMode(mode), before: before, after: after})
+		}
+	}
+	for _, conv := range so.config.Conversions {
+		if c, ok := conversions[conv.Name]; ok {
+			add(DiffResourceConversion, conv.Name, conv.OnConflict,
+				conversionState(c.EventName, c.CountingMethod), conversionState(conv.Name, conv.CountingMethod))
+		}
+	}
+	for _, dim := range so.config.Dimensions {
+		if d, ok := dimensions[dim.ParameterName]; ok {
+			add(DiffResourceDimension, dim.ParameterName, dim.OnConflict,
+				dimensionState(d.ParameterName, d.DisplayName, d.Description, d.Scope),
+				dimensionState(dim.ParameterName, dim.DisplayName, dim.Description, dim.Scope))
+		}
+	}
 	for _, metric := range so.config.Metrics {
-		if metricMap[metric.ParameterName] {
-			fmt.Printf("  %s %s %s\n", yellow("○"), metric.DisplayName, blue("(already exists, skipping)"))
+		if m, ok := metrics[metric.ParameterName]; ok {
+			add(DiffResourceMetric, metric.ParameterName, metric.OnConflict,
+				metricState(m.ParameterName, m.DisplayName, m.Description, m.MeasurementUnit, m.Scope, restrictedType(m.RestrictedMetricType)),
+				configMetricState(metric))
+		}
+	}
+	return conflicts
+}
+
+// reviewConflicts lists each conflict's differing fields and what setup does
+// about it. It fails when a conflict's mode is error, or when one set to
+// update differs in scope, which the API cannot change.
+func reviewConflicts(conflicts resourceConflicts) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+
+	theme.Printf("\n%s Existing resources that differ from the config:\n", yellow("⚠️"))
+	var failed []string
+	for _, c := range conflicts {
+		var note string
+		switch {
+		case c.mode == config.OnConflictError:
+			failed = append(failed, fmt.Sprintf("%s %s differs from the config (on_conflict: error)", c.resource, c.name))
+			note = red("error")
+		case c.mode == config.OnConflictUpdate && !patchable(c.before, c.after):
+			failed = append(failed, fmt.Sprintf("%s %s: scope %v cannot be changed to %v", c.resource, c.name, c.before["scope"], c.after["scope"]))
+			note = red("scope cannot be updated")
+		case c.mode == config.OnConflictUpdate:
+			note = blue("update")
+		default:
+			note = gray("skip")
+		}
+		theme.Printf("  %s %s %s (%s)\n", yellow("~"), c.resource, c.name, note)
+		for _, k := range changedKeys(c.before, c.after) {
+			theme.Printf("      %s: %v → %v\n", k, c.before[k], c.after[k])
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("existing resources conflict with the config:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// printUpdated reports an existing resource patched to match the config.
+func printUpdated(name string, dryRun bool) {
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	if dryRun {
+		theme.Printf("  %s %s (update)\n", blue("○"), name)
+		return
+	}
+	theme.Printf("  %s %s %s\n", theme.Color(color.FgGreen).Sprint("✓"), name, blue("(updated)"))
+}
+
+// setupCalculatedMetrics creates the config's calculated metrics. One that
+// exists under the same id or display name is skipped. A formula may only
+// reference custom metrics that exist on the property or that the metrics
+// step creates in this run. Created metrics are deleted on rollback.
+func (so *SetupOrchestrator) setupCalculatedMetrics(propertyID string) error {
+	green := theme.Color(color.FgGreen).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+
+	theme.Printf("\n%s Creating calculated metrics...\n", "🧮")
+	if !so.scope.Includes(config.ResourceCalculatedMetrics) {
+		for _, calc := range so.config.CalculatedMetrics {
+			theme.Printf("  %s %s %s\n", gray("○"), calc.Name, gray("(ignored: out of scope)"))
+		}
+		printSetupCounts("Created", 0, 0, len(so.config.CalculatedMetrics))
+		return nil
+	}
+
+	existing, err := so.ga4Client.ListPropertyCalculatedMetrics(propertyID)
+	if err != nil {
+		return fmt.Errorf("list calculated metrics: %w", err)
+	}
+	existingIDs := make(map[string]bool)
+	existingNames := make(map[string]bool)
+	for _, calc := range existing {
+		existingIDs[calc.CalculatedMetricId] = true
+		existingNames[calc.DisplayName] = true
+	}
+
+	customMetrics, err := so.ga4Client.ListCustomMetrics(propertyID)
+	if err != nil {
+		return fmt.Errorf("list custom metrics: %w", err)
+	}
+	available := make(map[string]bool)
+	for _, m := range customMetrics {
+		available[m.ParameterName] = true
+	}
+	if so.scope.Includes(config.ResourceMetrics) {
+		for _, m := range so.config.Metrics {
+			available[m.ParameterName] = true
+		}
+	}
+
+	createdCount, skippedCount := 0, 0
+	for _, calc := range so.config.CalculatedMetrics {
+		id := ga4.CalculatedMetricID(calc)
+		if existingIDs[id] || existingNames[calc.Name] {
+			theme.Printf("  %s %s %s\n", yellow("○"), calc.Name, blue("(already exists, skipping)"))
 			skippedCount++
 			continue
 		}
 
+		refs, err := ga4.FormulaCustomMetrics(calc.Formula)
+		if err != nil {
+			theme.Printf("  %s %s: %s\n", red("✗"), calc.Name, err)
+			return fmt.Errorf("calculated metric %s: %w", calc.Name, err)
+		}
+		for _, ref := range refs {
+			if !available[ref] {
+				theme.Printf("  %s %s: custom metric %s does not exist\n", red("✗"), calc.Name, ref)
+				return fmt.Errorf("calculated metric %s: formula references custom metric %q, which is neither on the property nor in metrics", calc.Name, ref)
+			}
+		}
+
 		if so.dryRun {
-			fmt.Printf("  %s %s (param: %s, scope: %s, unit: %s)\n",
-				blue("○"), metric.DisplayName, metric.ParameterName, metric.Scope, metric.MeasurementUnit)
+			theme.Printf("  %s %s (id: %s, formula: %s)\n", blue("○"), calc.Name, id, calc.Formula)
 			createdCount++
-		} else {
-			err := so.ga4Client.CreateCustomMetric(propertyID, metric)
-			if errors.Is(err, ga4.ErrAlreadyExists) {
-				fmt.Printf("  %s %s %s\n", yellow("○"), metric.DisplayName, blue("(conflict: already exists, skipping)"))
+			continue
+		}
+
+		name, err := so.ga4Client.CreateCalculatedMetric(propertyID, calc)
+		if errors.Is(err, ga4.ErrAlreadyExists) {
+			theme.Printf("  %s %s %s\n", yellow("○"), calc.Name, blue("(conflict: already exists, skipping)"))
+			skippedCount++
+			continue
+		}
+		if err != nil {
+			theme.Printf("  %s %s: %s\n", red("✗"), calc.Name, err)
+			return fmt.Errorf("create calculated metric %s: %w", calc.Name, err)
+		}
+		so.rollback.Register(RollbackOperation{
+			Type:        "calculated_metric",
+			ResourceID:  name,
+			PropertyID:  propertyID,
+			Description: fmt.Sprintf("Delete calculated metric: %s", calc.Name),
+			Rollback: func() error {
+				return so.ga4Client.DeleteCalculatedMetric(name)
+			},
+		})
+		theme.Printf("  %s %s\n", green("✓"), calc.Name)
+		createdCount++
+	}
+
+	printSetupCounts("Created", createdCount, skippedCount, 0)
+	return nil
+}
+
+// setupChannelGroups creates the config's channel groups and updates the ones
+// that exist under the same display name but differ. Both are registered for
+// rollback: created groups are deleted, updated ones restored.
+func (so *SetupOrchestrator) setupChannelGroups(propertyID string) error {
+	green := theme.Color(color.FgGreen).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+
+	theme.Printf("\n%s Creating channel groups...\n", "📡")
+	groups := ga4.ChannelGroupsFromConfig(so.config.ChannelGroups)
+	if !so.scope.Includes(config.ResourceChannelGroups) {
+		for _, group := range groups {
+			theme.Printf("  %s %s %s\n", gray("○"), group.DisplayName, gray("(ignored: out of scope)"))
+		}
+		printSetupCounts("Created", 0, 0, len(groups))
+		return nil
+	}
+
+	existingGroups, err := so.ga4Client.ListChannelGroups(propertyID)
+	if err != nil {
+		return fmt.Errorf("list channel groups: %w", err)
+	}
+	existing := make(map[string]*admin.GoogleAnalyticsAdminV1alphaChannelGroup)
+	for _, g := range existingGroups {
+		if !g.SystemDefined {
+			existing[g.DisplayName] = g
+		}
+	}
+
+	createdCount, updatedCount, skippedCount := 0, 0, 0
+	for _, group := range groups {
+		previous, exists := existing[group.DisplayName]
+		if exists {
+			upToDate, err := ga4.ChannelGroupUpToDate(previous, group)
+			if err != nil {
+				theme.Printf("  %s %s: %s\n", red("✗"), group.DisplayName, err)
+				return fmt.Errorf("compare channel group %s: %w", group.DisplayName, err)
+			}
+			if upToDate {
+				theme.Printf("  %s %s %s\n", yellow("○"), group.DisplayName, blue("(up to date, skipping)"))
 				skippedCount++
 				continue
 			}
-			if err != nil {
-				fmt.Printf("  %s %s: %s\n", red("✗"), metric.DisplayName, err)
-				return fmt.Errorf("create metric %s: %w", metric.DisplayName, err)
+		}
+
+		if so.dryRun {
+			action := "create"
+			if exists {
+				action = "update"
+				updatedCount++
+			} else {
+				createdCount++
 			}
+			theme.Printf("  %s %s (%d rules, %s)\n", blue("○"), group.DisplayName, len(group.Rules), action)
+			continue
+		}
 
-			fmt.Printf("  %s %s\n", green("✓"), metric.DisplayName)
-			createdCount++
+		if exists {
+			if err := so.ga4Client.UpdateChannelGroup(previous.Name, group); err != nil {
+				theme.Printf("  %s %s: %s\n", red("✗"), group.DisplayName, err)
+				return fmt.Errorf("update channel group %s: %w", group.DisplayName, err)
+			}
+			so.rollback.Register(RollbackOperation{
+				Type:        "channel_group",
+				ResourceID:  previous.Name,
+				PropertyID:  propertyID,
+				Description: fmt.Sprintf("Restore channel group: %s", group.DisplayName),
+				Rollback: func() error {
+					return so.ga4Client.RestoreChannelGroup(previous)
+				},
+			})
+			theme.Printf("  %s %s %s\n", green("✓"), group.DisplayName, blue("(updated)"))
+			updatedCount++
+			continue
 		}
+
+		created, err := so.ga4Client.CreateChannelGroup(propertyID, group)
+		if err != nil {
+			theme.Printf("  %s %s: %s\n", red("✗"), group.DisplayName, err)
+			return fmt.Errorf("create channel group %s: %w", group.DisplayName, err)
+		}
+		so.rollback.Register(RollbackOperation{
+			Type:        "channel_group",
+			ResourceID:  created.Name,
+			PropertyID:  propertyID,
+			Description: fmt.Sprintf("Delete channel group: %s", group.DisplayName),
+			Rollback: func() error {
+				return so.ga4Client.DeleteChannelGroup(created.Name)
+			},
+		})
+		theme.Printf("  %s %s\n", green("✓"), group.DisplayName)
+		createdCount++
 	}
 
-	if createdCount > 0 || skippedCount > 0 {
-		fmt.Printf("  Created: %d, Skipped: %d\n", createdCount, skippedCount)
+	printSetupUpdateCounts(createdCount, updatedCount, skippedCount, 0)
+	return nil
+}
+
+// setupDataRetention applies the config's data retention settings when they
+// differ from the property's. The previous settings are restored on rollback.
+func (so *SetupOrchestrator) setupDataRetention(propertyID string) error {
+	theme.Printf("\n%s Applying data retention...\n", "🗄️")
+	r := so.config.DataRetention
+	desired := ga4.DataRetentionSettings{EventDataRetention: r.EventDataRetention, ResetUserDataOnNewActivity: r.ResetUserDataOnNewActivity}
+	if !so.scope.Includes(config.ResourceDataRetention) {
+		printSettingsIgnored("Data retention")
+		return nil
 	}
 
-	// Show guidance for manual tasks
-	if len(so.config.Audiences) > 0 {
-		fmt.Printf("\n%s Audiences (manual setup required):\n", yellow("👥"))
-		for _, aud := range so.config.Audiences {
-			fmt.Printf("  %s %s\n", yellow("○"), aud.Name)
-		}
-		fmt.Printf("  %s Audiences must be created manually in GA4 UI\n", blue("ℹ️"))
+	current, err := so.ga4Client.GetDataRetention(propertyID)
+	if err != nil {
+		return fmt.Errorf("get data retention: %w", err)
+	}
+	if !printSettingChanges("Data retention", dataRetentionState(*current), dataRetentionState(desired), so.dryRun) || so.dryRun {
+		return nil
 	}
 
+	if err := so.ga4Client.UpdateDataRetention(propertyID, desired); err != nil {
+		return fmt.Errorf("update data retention: %w", err)
+	}
+	previous := *current
+	so.rollback.Register(RollbackOperation{
+		Type:        "data_retention",
+		ResourceID:  fmt.Sprintf("properties/%s/dataRetentionSettings", propertyID),
+		PropertyID:  propertyID,
+		Description: "Restore data retention settings",
+		Rollback: func() error {
+			return so.ga4Client.UpdateDataRetention(propertyID, previous)
+		},
+	})
+	theme.Printf("  %s Data retention updated\n", theme.Color(color.FgGreen).Sprint("✓"))
 	return nil
 }
 
+// setupEnhancedMeasurement applies the config's enhanced measurement toggles
+// to the property's web stream. A property without a web stream is skipped
+// with a warning. The previous settings are restored on rollback.
+func (so *SetupOrchestrator) setupEnhancedMeasurement(propertyID string) error {
+	theme.Printf("\n%s Applying enhanced measurement...\n", "📐")
+	if !so.scope.Includes(config.ResourceEnhancedMeasurement) {
+		printSettingsIgnored("Enhanced measurement")
+		return nil
+	}
+
+	current, err := so.ga4Client.GetPropertyEnhancedMeasurement(propertyID)
+	if errors.Is(err, ga4.ErrNoWebStream) {
+		theme.Printf("  %s No web data stream; enhanced measurement not applied\n", theme.Color(color.FgYellow).Sprint("⚠"))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get enhanced measurement: %w", err)
+	}
+	desired := ga4.EnhancedMeasurementFromConfig(current, so.config.EnhancedMeasurement)
+	if !printSettingChanges("Enhanced measurement", enhancedMeasurementState(current), enhancedMeasurementState(desired), so.dryRun) || so.dryRun {
+		return nil
+	}
+
+	stream := strings.TrimSuffix(current.Name, "/enhancedMeasurementSettings")
+	if err := so.ga4Client.UpdateEnhancedMeasurement(stream, desired); err != nil {
+		return fmt.Errorf("update enhanced measurement: %w", err)
+	}
+	so.rollback.Register(RollbackOperation{
+		Type:        "enhanced_measurement",
+		ResourceID:  current.Name,
+		PropertyID:  propertyID,
+		Description: "Restore enhanced measurement settings",
+		Rollback: func() error {
+			return so.ga4Client.UpdateEnhancedMeasurement(stream, current)
+		},
+	})
+	theme.Printf("  %s Enhanced measurement updated\n", theme.Color(color.FgGreen).Sprint("✓"))
+	return nil
+}
+
+// printSettingChanges lists the settings that differ between before and
+// after as "field: old → new" and reports whether any do.
+func printSettingChanges(what string, before, after map[string]any, dryRun bool) bool {
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+
+	keys := changedKeys(before, after)
+	if len(keys) == 0 {
+		theme.Printf("  %s %s %s\n", yellow("○"), what, blue("(up to date, skipping)"))
+		return false
+	}
+	marker := yellow("~")
+	if dryRun {
+		marker = blue("○")
+	}
+	for _, k := range keys {
+		theme.Printf("  %s %s: %v → %v\n", marker, k, before[k], after[k])
+	}
+	return true
+}
+
+func printSettingsIgnored(what string) {
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+	theme.Printf("  %s %s %s\n", gray("○"), what, gray("(ignored: out of scope)"))
+}
+
 // SetupGSC configures Google Search Console
 func (so *SetupOrchestrator) SetupGSC() error {
 	if so.gscClient == nil {
@@ -378,51 +1048,60 @@ func (so *SetupOrchestrator) SetupGSC() error {
 		return nil
 	}
 
-	green := color.New(color.FgGreen).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
 
 	gsc := so.config.SearchConsole
 	siteURL := gsc.SiteURL
 
-	fmt.Println()
-	fmt.Printf("[2/2] %s Google Search Console Setup\n", blue("🔍"))
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Printf("[2/2] %s Google Search Console Setup\n", blue("🔍"))
+	theme.Println("───────────────────────────────────────────────")
 
 	// Get existing sitemaps to detect duplicates
-	existingSitemaps, _ := so.gscClient.ListSitemaps(siteURL)
 	sitemapMap := make(map[string]bool)
-	for _, sitemap := range existingSitemaps {
-		sitemapMap[sitemap.Path] = true
+	if so.scope.Includes(config.ResourceSitemaps) {
+		existingSitemaps, _ := so.gscClient.ListSitemaps(siteURL)
+		for _, sitemap := range existingSitemaps {
+			sitemapMap[sitemap.Path] = true
+		}
 	}
 
 	// Submit sitemaps
-	if len(gsc.Sitemaps) > 0 {
-		fmt.Printf("\n%s Submitting sitemaps...\n", "🗺️")
+	if active := gsc.ActiveSitemaps(); len(active) > 0 {
+		theme.Printf("\n%s Submitting sitemaps...\n", "🗺️")
 
 		submittedCount := 0
 		skippedCount := 0
+		ignoredCount := 0
 
-		for _, sitemap := range gsc.Sitemaps {
+		for _, sitemap := range active {
+			if !so.scope.Includes(config.ResourceSitemaps) {
+				theme.Printf("  %s %s %s\n", gray("○"), sitemap.URL, gray("(ignored: out of scope)"))
+				ignoredCount++
+				continue
+			}
 			if !sitemap.AutoSubmit {
-				fmt.Printf("  %s %s %s\n", yellow("○"), sitemap.URL, blue("(auto_submit: false, skipping)"))
+				theme.Printf("  %s %s %s\n", yellow("○"), sitemap.URL, blue("(auto_submit: false, skipping)"))
 				continue
 			}
 
 			if sitemapMap[sitemap.URL] {
-				fmt.Printf("  %s %s %s\n", yellow("○"), sitemap.URL, blue("(already submitted, skipping)"))
+				theme.Printf("  %s %s %s\n", yellow("○"), sitemap.URL, blue("(already submitted, skipping)"))
 				skippedCount++
 				continue
 			}
 
 			if so.dryRun {
-				fmt.Printf("  %s %s\n", blue("○"), sitemap.URL)
+				theme.Printf("  %s %s\n", blue("○"), sitemap.URL)
 				submittedCount++
 			} else {
 				err := so.gscClient.SubmitSitemap(siteURL, sitemap.URL)
 				if err != nil {
-					fmt.Printf("  %s %s: %s\n", red("✗"), sitemap.URL, err)
+					theme.Printf("  %s %s: %s\n", red("✗"), sitemap.URL, err)
 					return fmt.Errorf("submit sitemap %s: %w", sitemap.URL, err)
 				}
 
@@ -438,42 +1117,134 @@ func (so *SetupOrchestrator) SetupGSC() error {
 					},
 				})
 
-				fmt.Printf("  %s %s\n", green("✓"), sitemap.URL)
+				theme.Printf("  %s %s\n", green("✓"), sitemap.URL)
 				submittedCount++
 			}
 		}
 
-		if submittedCount > 0 || skippedCount > 0 {
-			fmt.Printf("  Submitted: %d, Skipped: %d\n", submittedCount, skippedCount)
-		}
+		printSetupCounts("Submitted", submittedCount, skippedCount, ignoredCount)
+	}
+
+	if err := so.removeSitemaps(siteURL, sitemapMap); err != nil {
+		return err
 	}
 
 	// Show URL monitoring configuration
 	if gsc.URLInspection != nil && len(gsc.URLInspection.PriorityURLs) > 0 {
-		fmt.Printf("\n%s URL Monitoring configured\n", "🔍")
-		fmt.Printf("  Priority URLs: %d\n", len(gsc.URLInspection.PriorityURLs))
+		theme.Printf("\n%s URL Monitoring configured\n", "🔍")
+		theme.Printf("  Priority URLs: %d\n", len(gsc.URLInspection.PriorityURLs))
 		if !so.dryRun {
-			fmt.Printf("  Run: ./ga4 gsc monitor run --config %s\n", so.configPath)
+			theme.Printf("  Run: ./ga4 gsc monitor run --config %s\n", so.configPath)
 		}
 	}
 
 	// Show search analytics configuration
 	if gsc.SearchAnalytics != nil {
-		fmt.Printf("\n%s Search Analytics configured\n", "📊")
+		theme.Printf("\n%s Search Analytics configured\n", "📊")
 		if gsc.SearchAnalytics.DateRange != nil {
-			fmt.Printf("  Date range: Last %d days\n", gsc.SearchAnalytics.DateRange.Days)
+			theme.Printf("  Date range: Last %d days\n", gsc.SearchAnalytics.DateRange.Days)
 		}
 		if len(gsc.SearchAnalytics.Dimensions) > 0 {
-			fmt.Printf("  Dimensions: %v\n", gsc.SearchAnalytics.Dimensions)
+			theme.Printf("  Dimensions: %v\n", gsc.SearchAnalytics.Dimensions)
 		}
 		if !so.dryRun {
-			fmt.Printf("  Run: ./ga4 gsc analytics run --config %s\n", so.configPath)
+			theme.Printf("  Run: ./ga4 gsc analytics run --config %s\n", so.configPath)
 		}
 	}
 
 	return nil
 }
 
+// removeSitemaps deletes the sitemaps marked remove: true that are still
+// submitted; existing holds the submitted sitemap paths. A deleted sitemap
+// is resubmitted on rollback.
+func (so *SetupOrchestrator) removeSitemaps(siteURL string, existing map[string]bool) error {
+	var retired []config.SitemapConfig
+	for _, sitemap := range so.config.SearchConsole.Sitemaps {
+		if sitemap.Remove {
+			retired = append(retired, sitemap)
+		}
+	}
+	if len(retired) == 0 {
+		return nil
+	}
+
+	green := theme.Color(color.FgGreen).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
+
+	theme.Printf("\n%s Removing retired sitemaps...\n", "🗑️")
+
+	removedCount := 0
+	skippedCount := 0
+	ignoredCount := 0
+
+	for _, sitemap := range retired {
+		if !so.scope.Includes(config.ResourceSitemaps) {
+			theme.Printf("  %s %s %s\n", gray("○"), sitemap.URL, gray("(ignored: out of scope)"))
+			ignoredCount++
+			continue
+		}
+		if !existing[sitemap.URL] {
+			theme.Printf("  %s %s %s\n", yellow("○"), sitemap.URL, blue("(not submitted, skipping)"))
+			skippedCount++
+			continue
+		}
+
+		if so.dryRun {
+			theme.Printf("  %s %s %s\n", blue("○"), sitemap.URL, blue("(would delete)"))
+			removedCount++
+			continue
+		}
+
+		if err := so.gscClient.DeleteSitemap(siteURL, sitemap.URL); err != nil {
+			theme.Printf("  %s %s: %s\n", red("✗"), sitemap.URL, err)
+			return fmt.Errorf("delete sitemap %s: %w", sitemap.URL, err)
+		}
+
+		sitemapURL := sitemap.URL
+		so.rollback.Register(RollbackOperation{
+			Type:        "sitemap",
+			ResourceID:  sitemapURL,
+			PropertyID:  siteURL,
+			Description: fmt.Sprintf("Resubmit sitemap: %s", sitemapURL),
+			Rollback: func() error {
+				return so.gscClient.SubmitSitemap(siteURL, sitemapURL)
+			},
+		})
+
+		theme.Printf("  %s %s %s\n", green("✓"), sitemap.URL, gray("(deleted)"))
+		removedCount++
+	}
+
+	printSetupCounts("Removed", removedCount, skippedCount, ignoredCount)
+	return nil
+}
+
+// printSetupUpdateCounts is printSetupCounts for sections that also update
+// existing resources.
+func printSetupUpdateCounts(created, updated, skipped, ignored int) {
+	if updated == 0 {
+		printSetupCounts("Created", created, skipped, ignored)
+		return
+	}
+	theme.Printf("  Created: %d, Updated: %d, Skipped: %d\n", created, updated, skipped)
+}
+
+// printSetupCounts prints a section's tally line, if anything happened in it.
+func printSetupCounts(verb string, done, skipped, ignored int) {
+	if done == 0 && skipped == 0 && ignored == 0 {
+		return
+	}
+	if ignored > 0 {
+		theme.Printf("  %s: %d, Skipped: %d, Ignored: %d\n", verb, done, skipped, ignored)
+		return
+	}
+	theme.Printf("  %s: %d, Skipped: %d\n", verb, done, skipped)
+}
+
 // handleError handles setup errors with optional rollback
 func (so *SetupOrchestrator) handleError(message string, err error) error {
 	if so.dryRun {
@@ -492,35 +1263,35 @@ func (so *SetupOrchestrator) handleError(message string, err error) error {
 
 // printNextSteps prints next steps after successful setup
 func (so *SetupOrchestrator) printNextSteps() {
-	blue := color.New(color.FgBlue).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
 
-	fmt.Println()
-	fmt.Println("Next steps:")
+	theme.Println()
+	theme.Println("Next steps:")
 
 	stepNum := 1
 
 	if so.config.HasAnalytics() {
-		fmt.Printf("%d. Verify GA4 setup: https://analytics.google.com\n", stepNum)
+		theme.Printf("%d. Verify GA4 setup: https://analytics.google.com\n", stepNum)
 		stepNum++
 	}
 
 	if so.config.HasSearchConsole() {
 		if so.config.SearchConsole.URLInspection != nil && len(so.config.SearchConsole.URLInspection.PriorityURLs) > 0 {
-			fmt.Printf("%d. Run URL monitoring: %s\n", stepNum, blue(fmt.Sprintf("./ga4 gsc monitor run --config %s", so.configPath)))
+			theme.Printf("%d. Run URL monitoring: %s\n", stepNum, blue(fmt.Sprintf("./ga4 gsc monitor run --config %s", so.configPath)))
 			stepNum++
 		}
 
 		if so.config.SearchConsole.SearchAnalytics != nil {
-			fmt.Printf("%d. Check search analytics: %s\n", stepNum, blue(fmt.Sprintf("./ga4 gsc analytics run --config %s", so.configPath)))
+			theme.Printf("%d. Check search analytics: %s\n", stepNum, blue(fmt.Sprintf("./ga4 gsc analytics run --config %s", so.configPath)))
 			stepNum++
 		}
 	}
 
 	if so.config.HasAnalytics() {
-		fmt.Printf("%d. Implement event tracking in your app\n", stepNum)
+		theme.Printf("%d. Implement event tracking in your app\n", stepNum)
 		stepNum++
-		fmt.Printf("%d. Test events in GA4 DebugView\n", stepNum)
+		theme.Printf("%d. Test events in GA4 DebugView\n", stepNum)
 	}
 
-	fmt.Println()
+	theme.Println()
 }
@@ -0,0 +1,154 @@
+package setup
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4/ga4fake"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/gscfake"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// setupConfig declares two conversions, a dimension and a metric on property
+// 123, and two sitemaps.
+func setupConfig() *config.ProjectConfig {
+	return &config.ProjectConfig{
+		Analytics: &config.AnalyticsConfig{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+			{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION"},
+		},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"},
+		},
+		Metrics: []config.MetricConfig{
+			{ParameterName: "word_count", DisplayName: "Word Count", MeasurementUnit: "STANDARD", Scope: "EVENT"},
+		},
+		SearchConsole: &config.SearchConsoleConfig{
+			SiteURL: "sc-domain:example.com",
+			Sitemaps: []config.SitemapConfig{
+				{URL: "https://example.com/sitemap.xml", AutoSubmit: true},
+				{URL: "https://example.com/news.xml", AutoSubmit: true},
+			},
+		},
+	}
+}
+
+// existingPurchase is the config's purchase conversion, already on the
+// property.
+func existingPurchase() *admin.GoogleAnalyticsAdminV1alphaConversionEvent {
+	return &admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		Name:           "properties/123/conversionEvents/purchase",
+		EventName:      "purchase",
+		CountingMethod: "ONCE_PER_EVENT",
+	}
+}
+
+func TestSetupGA4_CreatesMissingSkipsExisting(t *testing.T) {
+	fake := &ga4fake.Admin{Conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{existingPurchase()}}
+	so := NewSetupOrchestrator(setupConfig(), "config.yaml", fake, nil, discardLogger(), false)
+
+	require.NoError(t, so.SetupGA4())
+
+	assert.Equal(t, []string{"sign_up"}, fake.Called("CreateConversionFromConfig"))
+	assert.Equal(t, []string{"author"}, fake.Called("CreateDimension"))
+	assert.Equal(t, []string{"word_count"}, fake.Called("CreateCustomMetric"))
+	assert.Empty(t, fake.Called("SetConversionCountingMethod"), "an unchanged conversion is left alone")
+	require.Len(t, fake.Conversions, 2)
+	assert.Equal(t, "ONCE_PER_SESSION", fake.Conversions[1].CountingMethod)
+
+	// Rolling back deletes the conversion this run created, and only it.
+	require.NoError(t, so.rollback.ExecuteAll())
+	assert.Equal(t, []string{"sign_up"}, fake.Called("DeleteConversion"))
+	assert.Equal(t, []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{existingPurchase()}, fake.Conversions)
+}
+
+func TestSetupGA4_DryRunWritesNothing(t *testing.T) {
+	fake := &ga4fake.Admin{}
+	so := NewSetupOrchestrator(setupConfig(), "config.yaml", fake, nil, discardLogger(), true)
+
+	require.NoError(t, so.SetupGA4())
+
+	assert.Equal(t, []string{
+		"ListConversions 123",
+		"ListDimensions 123",
+		"ListCustomMetrics 123",
+	}, fake.Calls())
+	assert.False(t, so.rollback.HasOperations())
+}
+
+func TestSetupGA4_UpdatesConflictsSetToUpdate(t *testing.T) {
+	cfg := setupConfig()
+	cfg.Conversions[0].CountingMethod = "ONCE_PER_SESSION"
+	cfg.Conversions[0].OnConflict = config.OnConflictUpdate
+	cfg.Dimensions[0].OnConflict = config.OnConflictUpdate
+	fake := &ga4fake.Admin{
+		Conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{existingPurchase()},
+		Dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+			{Name: "properties/123/customDimensions/author", ParameterName: "author", DisplayName: "Writer", Scope: "EVENT"},
+		},
+	}
+	so := NewSetupOrchestrator(cfg, "config.yaml", fake, nil, discardLogger(), false)
+
+	require.NoError(t, so.SetupGA4())
+
+	assert.Equal(t, []string{"purchase"}, fake.Called("SetConversionCountingMethod"))
+	assert.Equal(t, []string{"author"}, fake.Called("UpdateDimension"))
+	assert.Equal(t, "ONCE_PER_SESSION", fake.Conversions[0].CountingMethod)
+	assert.Equal(t, "Author", fake.Dimensions[0].DisplayName)
+
+	require.NoError(t, so.rollback.ExecuteAll())
+	assert.Equal(t, "ONCE_PER_EVENT", fake.Conversions[0].CountingMethod)
+	assert.Equal(t, "Writer", fake.Dimensions[0].DisplayName)
+}
+
+func TestSetupGA4_ConflictSetToErrorStopsBeforeWriting(t *testing.T) {
+	cfg := setupConfig()
+	cfg.Conversions[0].CountingMethod = "ONCE_PER_SESSION"
+	cfg.Conversions[0].OnConflict = config.OnConflictError
+	fake := &ga4fake.Admin{Conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{existingPurchase()}}
+	so := NewSetupOrchestrator(cfg, "config.yaml", fake, nil, discardLogger(), false)
+
+	err := so.SetupGA4()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conversion purchase differs from the config (on_conflict: error)")
+	assert.Empty(t, fake.Called("CreateConversionFromConfig"))
+	assert.Empty(t, fake.Called("CreateDimension"))
+}
+
+func TestSetupGA4_CreateFailure(t *testing.T) {
+	fake := &ga4fake.Admin{Errs: map[string]error{"CreateDimension": errors.New("permission denied")}}
+	so := NewSetupOrchestrator(setupConfig(), "config.yaml", fake, nil, discardLogger(), false)
+
+	err := so.SetupGA4()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create dimension Author: permission denied")
+	assert.Empty(t, fake.Called("CreateCustomMetric"), "metrics are not attempted after dimensions fail")
+}
+
+func TestSetupGSC_SubmitsNewSitemaps(t *testing.T) {
+	fake := &gscfake.SearchConsole{Sitemaps: []gsc.SitemapInfo{{Path: "https://example.com/sitemap.xml"}}}
+	so := NewSetupOrchestrator(setupConfig(), "config.yaml", nil, fake, discardLogger(), false)
+
+	require.NoError(t, so.SetupGSC())
+
+	assert.Equal(t, []string{"https://example.com/news.xml"}, fake.Called("SubmitSitemap"))
+	require.Len(t, fake.Sitemaps, 2)
+
+	require.NoError(t, so.rollback.ExecuteAll())
+	assert.Equal(t, []string{"https://example.com/news.xml"}, fake.Called("DeleteSitemap"))
+	assert.Len(t, fake.Sitemaps, 1)
+}
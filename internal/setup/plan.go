@@ -0,0 +1,127 @@
+package setup
+
+import (
+	"fmt"
+
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// Plan actions for a single resource.
+const (
+	PlanActionCreate  = "create"
+	PlanActionExists  = "exists"
+	PlanActionIgnored = "ignored" // out of the run's --only/--skip scope
+)
+
+// ResourceLister is the read-only slice of the GA4 client BuildPlan needs.
+// *ga4.Client satisfies it; tests substitute a fake.
+type ResourceLister interface {
+	ListConversions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error)
+	ListDimensions(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error)
+	ListCustomMetrics(propertyID string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error)
+}
+
+// PlanItem is one configured resource and what setup would do with it.
+type PlanItem struct {
+	Name   string `json:"name"`
+	Action string `json:"action"` // create | exists | ignored
+}
+
+// Plan describes what `ga4 setup` would change on a Property without
+// changing anything. It is the machine-readable counterpart of --dry-run.
+type Plan struct {
+	Project     string     `json:"project"`
+	PropertyID  string     `json:"property_id"`
+	Conversions []PlanItem `json:"conversions"`
+	Dimensions  []PlanItem `json:"dimensions"`
+	Metrics     []PlanItem `json:"metrics"`
+}
+
+// ToCreate returns the number of resources the plan would create.
+func (p *Plan) ToCreate() int {
+	n := 0
+	for _, items := range [][]PlanItem{p.Conversions, p.Dimensions, p.Metrics} {
+		for _, it := range items {
+			if it.Action == PlanActionCreate {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// BuildPlan lists the Property's existing conversions, dimensions and metrics
+// and classifies every configured resource as create or exists, using the same
+// keys SetupGA4 uses to skip duplicates (event name, parameter name).
+// Resources outside the config's setup scope are marked ignored without
+// being listed.
+func BuildPlan(cfg *config.ProjectConfig, lister ResourceLister) (*Plan, error) {
+	propertyID := cfg.GetPropertyID()
+	if propertyID == "" {
+		return nil, fmt.Errorf("config %q has no GA4 property_id", cfg.Project.Name)
+	}
+	scope := cfg.SetupScope()
+
+	conversionMap := make(map[string]bool)
+	if scope.Includes(config.ResourceConversions) {
+		existingConversions, err := lister.ListConversions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conversions: %w", err)
+		}
+		for _, conv := range existingConversions {
+			conversionMap[conv.EventName] = true
+		}
+	}
+
+	dimensionMap := make(map[string]bool)
+	if scope.Includes(config.ResourceDimensions) {
+		existingDimensions, err := lister.ListDimensions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dimensions: %w", err)
+		}
+		for _, dim := range existingDimensions {
+			dimensionMap[dim.ParameterName] = true
+		}
+	}
+
+	metricMap := make(map[string]bool)
+	if scope.Includes(config.ResourceMetrics) {
+		existingMetrics, err := lister.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics: %w", err)
+		}
+		for _, metric := range existingMetrics {
+			metricMap[metric.ParameterName] = true
+		}
+	}
+
+	plan := &Plan{
+		Project:     cfg.Project.Name,
+		PropertyID:  propertyID,
+		Conversions: make([]PlanItem, 0, len(cfg.Conversions)),
+		Dimensions:  make([]PlanItem, 0, len(cfg.Dimensions)),
+		Metrics:     make([]PlanItem, 0, len(cfg.Metrics)),
+	}
+	for _, conv := range cfg.Conversions {
+		plan.Conversions = append(plan.Conversions, planItem(conv.Name, conversionMap, scope.Includes(config.ResourceConversions)))
+	}
+	for _, dim := range cfg.Dimensions {
+		plan.Dimensions = append(plan.Dimensions, planItem(dim.ParameterName, dimensionMap, scope.Includes(config.ResourceDimensions)))
+	}
+	for _, metric := range cfg.Metrics {
+		plan.Metrics = append(plan.Metrics, planItem(metric.ParameterName, metricMap, scope.Includes(config.ResourceMetrics)))
+	}
+	return plan, nil
+}
+
+func planItem(name string, existing map[string]bool, inScope bool) PlanItem {
+	if !inScope {
+		return PlanItem{Name: name, Action: PlanActionIgnored}
+	}
+	if existing[name] {
+		return PlanItem{Name: name, Action: PlanActionExists}
+	}
+	return PlanItem{Name: name, Action: PlanActionCreate}
+}
@@ -0,0 +1,53 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// fakeLister is a ResourceLister that counts which lists were requested.
+type fakeLister struct {
+	conversions []*admin.GoogleAnalyticsAdminV1alphaConversionEvent
+	calls       []string
+}
+
+func (f *fakeLister) ListConversions(string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+	f.calls = append(f.calls, "conversions")
+	return f.conversions, nil
+}
+
+func (f *fakeLister) ListDimensions(string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	f.calls = append(f.calls, "dimensions")
+	return nil, nil
+}
+
+func (f *fakeLister) ListCustomMetrics(string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	f.calls = append(f.calls, "metrics")
+	return nil, nil
+}
+
+func TestBuildPlan_Scope(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Test"},
+		GA4:         config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "purchase"}, {Name: "sign_up"}},
+		Dimensions:  []config.DimensionConfig{{ParameterName: "author"}},
+		Metrics:     []config.MetricConfig{{ParameterName: "reading_time"}},
+		Setup:       &config.SetupConfig{Skip: []string{"dimensions", "metrics"}},
+	}
+	lister := &fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{{EventName: "purchase"}}}
+
+	plan, err := BuildPlan(cfg, lister)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"conversions"}, lister.calls, "out-of-scope resources are not listed")
+	assert.Equal(t, []PlanItem{{Name: "purchase", Action: PlanActionExists}, {Name: "sign_up", Action: PlanActionCreate}}, plan.Conversions)
+	assert.Equal(t, []PlanItem{{Name: "author", Action: PlanActionIgnored}}, plan.Dimensions)
+	assert.Equal(t, []PlanItem{{Name: "reading_time", Action: PlanActionIgnored}}, plan.Metrics)
+	assert.Equal(t, 1, plan.ToCreate())
+}
@@ -8,9 +8,12 @@ import (
 	"os"
 	"strings"
 
+	"github.com/garbarok/ga4-manager/internal/auth"
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/depgraph"
 	"github.com/garbarok/ga4-manager/internal/ga4"
 	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gtm"
 	"github.com/garbarok/ga4-manager/internal/validation"
 )
 
@@ -34,6 +37,21 @@ const (
 	ValidationSkipped
 )
 
+// String returns the status as JSON output spells it: "passed", "warning",
+// "failed" or "skipped".
+func (s ValidationStatus) String() string {
+	switch s {
+	case ValidationPassed:
+		return "passed"
+	case ValidationWarning:
+		return "warning"
+	case ValidationFailed:
+		return "failed"
+	default:
+		return "skipped"
+	}
+}
+
 // ConflictWarning represents a resource that already exists
 type ConflictWarning struct {
 	ResourceType string // "conversion", "dimension", "metric", "sitemap"
@@ -45,26 +63,54 @@ type ConflictWarning struct {
 // PreflightValidator validates configuration and environment before setup
 type PreflightValidator struct {
 	config    *config.ProjectConfig
-	ga4Client *ga4.Client
-	gscClient *gsc.Client
+	ga4Client ga4.GA4Admin
+	gscClient gsc.SearchConsole
 	logger    *slog.Logger
 	ctx       context.Context
+
+	// gtmContainer reads the config's tag_manager container; nil when none
+	// is configured.
+	gtmContainer func(ctx context.Context) (*gtm.Container, error)
+
+	// mintToken proves the service account key can get a token with the
+	// scopes setup needs.
+	mintToken func(ctx context.Context, key []byte, scopes []string) error
+
+	// principal is the service account key's client_email, or the account
+	// of a cached login, once CheckCredentials has read it.
+	principal string
 }
 
-// NewPreflightValidator creates a new pre-flight validator
+// NewPreflightValidator creates a new pre-flight validator. Either client may
+// be nil (an untyped nil) when the config does not use that service.
 func NewPreflightValidator(
 	cfg *config.ProjectConfig,
-	ga4Client *ga4.Client,
-	gscClient *gsc.Client,
+	ga4Client ga4.GA4Admin,
+	gscClient gsc.SearchConsole,
 	logger *slog.Logger,
 ) *PreflightValidator {
-	return &PreflightValidator{
+	pv := &PreflightValidator{
 		config:    cfg,
 		ga4Client: ga4Client,
 		gscClient: gscClient,
 		logger:    logger,
 		ctx:       context.Background(),
+		mintToken: mintServiceAccountToken,
+	}
+	if tm := cfg.TagManager; tm != nil {
+		pv.gtmContainer = func(ctx context.Context) (*gtm.Container, error) {
+			creds, err := auth.Resolve()
+			if err != nil {
+				return nil, err
+			}
+			client, err := gtm.NewClient(ctx, creds)
+			if err != nil {
+				return nil, err
+			}
+			return client.Container(ctx, tm.AccountID, tm.ContainerID, tm.WorkspaceID)
+		}
 	}
+	return pv
 }
 
 // ValidateAll runs all pre-flight checks
@@ -80,7 +126,13 @@ func (pv *PreflightValidator) ValidateAll() ([]ValidationResult, error) {
 	// 3. GA4 checks (if configured)
 	if pv.config.HasAnalytics() {
 		results = append(results, pv.CheckGA4Access())
+		results = append(results, pv.CheckGA4Role())
 		results = append(results, pv.ValidateGA4Resources())
+		results = append(results, pv.CheckDependencies())
+		results = append(results, pv.CheckTierLimits())
+		if pv.config.TagManager != nil {
+			results = append(results, pv.CheckGTMLinkage())
+		}
 	}
 
 	// 4. GSC checks (if configured)
@@ -108,12 +160,28 @@ func (pv *PreflightValidator) CheckCredentials() ValidationResult {
 		Status:      ValidationPassed,
 	}
 
-	// Check GOOGLE_APPLICATION_CREDENTIALS environment variable
+	// Check GOOGLE_APPLICATION_CREDENTIALS environment variable, or a cached
+	// `ga4 auth login` without it
 	credsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	if credsPath == "" {
-		result.Status = ValidationFailed
-		result.Error = fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS not set")
-		result.Details = "Set environment variable: export GOOGLE_APPLICATION_CREDENTIALS=/path/to/credentials.json"
+		creds, err := auth.Resolve()
+		if err != nil {
+			result.Status = ValidationFailed
+			result.Error = fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS not set and no `ga4 auth login`")
+			result.Details = "Set environment variable: export GOOGLE_APPLICATION_CREDENTIALS=/path/to/credentials.json, or run: ga4 auth login"
+			return result
+		}
+		user, err := auth.LoadToken(creds.Path)
+		if err != nil {
+			result.Status = ValidationFailed
+			result.Error = err
+			return result
+		}
+		pv.principal = user.Account
+		result.Details = "Using " + creds.String()
+		if user.Account != "" {
+			result.Details += ": " + user.Account
+		}
 		return result
 	}
 
@@ -125,8 +193,37 @@ func (pv *PreflightValidator) CheckCredentials() ValidationResult {
 		return result
 	}
 
-	result.Details = fmt.Sprintf("Using credentials: %s", credsPath)
-	pv.logger.Debug("credentials check passed", "path", credsPath)
+	data, err := os.ReadFile(credsPath)
+	if err != nil {
+		result.Status = ValidationFailed
+		result.Error = fmt.Errorf("cannot read credentials file: %w", err)
+		return result
+	}
+	key, err := ParseServiceAccountKey(data)
+	if err != nil {
+		result.Status = ValidationFailed
+		result.Error = err
+		result.Details = "Download a JSON key from IAM & Admin → Service Accounts → Keys in Google Cloud Console"
+		return result
+	}
+	pv.principal = key.ClientEmail
+
+	// A deleted or disabled key still parses; only the token endpoint can tell
+	scopes := requiredScopes(pv.config)
+	if len(scopes) > 0 {
+		if err := pv.mintToken(pv.ctx, data, scopes); err != nil {
+			result.Status = ValidationFailed
+			result.Error = fmt.Errorf("cannot get a token for %s (%s): %w", key.ClientEmail, scopeNames(scopes), err)
+			result.Details = "The key may have been deleted or disabled, or the service account removed; create a new key for it"
+			return result
+		}
+	}
+
+	result.Details = fmt.Sprintf("Using credentials: %s (%s)", credsPath, key.ClientEmail)
+	if len(scopes) > 0 {
+		result.Details += fmt.Sprintf(", token minted for %s", scopeNames(scopes))
+	}
+	pv.logger.Debug("credentials check passed", "path", credsPath, "client_email", key.ClientEmail)
 	return result
 }
 
@@ -218,6 +315,52 @@ func (pv *PreflightValidator) CheckGA4Access() ValidationResult {
 	return result
 }
 
+// CheckGA4Role reads the credential's role on the property through the
+// Admin API. Setup creates and updates resources, which takes the editor
+// role; a lower role is reported here instead of as a 403 halfway through.
+func (pv *PreflightValidator) CheckGA4Role() ValidationResult {
+	result := ValidationResult{
+		Name:        "GA4 Role",
+		Description: "Check the credential can edit the GA4 property",
+		Status:      ValidationPassed,
+	}
+
+	if pv.ga4Client == nil || pv.principal == "" {
+		result.Status = ValidationSkipped
+		result.Details = "GA4 client or credential account unknown"
+		return result
+	}
+
+	access, err := pv.ga4Client.PropertyAccess(pv.config.GetPropertyID(), pv.principal)
+	if err != nil {
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("could not determine the credential's role: %v", err)
+		return result
+	}
+	return ga4RoleResult(result, access)
+}
+
+// ga4RoleResult judges a credential's property access against the
+// editor role setup needs.
+func ga4RoleResult(result ValidationResult, access ga4.PropertyAccess) ValidationResult {
+	switch {
+	case access.AtMost == "":
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("%s has no access to the property; every GA4 call will fail with 403", access.User)
+	case ga4.RoleRank(access.AtMost) < ga4.RoleRank(ga4.RoleEditor):
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("%s is %s on the property; creating and updating resources needs editor and will fail with 403. Grant editor in GA4 Admin → Property access management", access.User, access.AtMost)
+	case access.Exact():
+		result.Details = "role: " + access.AtLeast
+	default:
+		result.Details = fmt.Sprintf("role: %s to %s", access.AtLeast, access.AtMost)
+		if access.Detail != "" {
+			result.Details += "; " + access.Detail
+		}
+	}
+	return result
+}
+
 // ValidateGA4Resources validates GA4 resource definitions
 func (pv *PreflightValidator) ValidateGA4Resources() ValidationResult {
 	result := ValidationResult{
@@ -281,6 +424,32 @@ func (pv *PreflightValidator) ValidateGA4Resources() ValidationResult {
 		}
 	}
 
+	// Validate calculated metrics; whether referenced custom metrics exist on
+	// the property is only known at setup time
+	calcIDs := make(map[string]bool)
+	for _, calc := range pv.config.CalculatedMetrics {
+		if _, err := ga4.FormulaCustomMetrics(calc.Formula); err != nil {
+			errors = append(errors, fmt.Sprintf("calculated metric %s: %v", calc.Name, err))
+		}
+		if err := validation.ValidateMeasurementUnit(calc.MetricUnit); err != nil {
+			errors = append(errors, fmt.Sprintf("calculated metric %s metric_unit: %v", calc.Name, err))
+		}
+		id := ga4.CalculatedMetricID(calc)
+		if calcIDs[id] {
+			errors = append(errors, fmt.Sprintf("calculated metric %s: id %q is used twice", calc.Name, id))
+		}
+		calcIDs[id] = true
+	}
+
+	// Validate channel group rules with the parser setup sends them through
+	for _, group := range pv.config.ChannelGroups {
+		for _, rule := range group.Rules {
+			if err := ga4.ValidateChannelExpression(rule.Expression); err != nil {
+				errors = append(errors, fmt.Sprintf("channel group %s rule %s: %v", group.DisplayName, rule.DisplayName, err))
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		result.Status = ValidationFailed
 		result.Error = fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
@@ -289,6 +458,150 @@ func (pv *PreflightValidator) ValidateGA4Resources() ValidationResult {
 
 	result.Details = fmt.Sprintf("%d conversions, %d dimensions, %d metrics",
 		len(pv.config.Conversions), len(pv.config.Dimensions), len(pv.config.Metrics))
+	if n := len(pv.config.ChannelGroups); n > 0 {
+		result.Details += fmt.Sprintf(", %d channel groups", n)
+	}
+	return result
+}
+
+// CheckDependencies warns about references between config resources that
+// point at nothing: an audience built on an event that is no conversion, a
+// calculated metric on a custom metric the config does not define. Both
+// may exist on the property already, so they do not fail setup.
+func (pv *PreflightValidator) CheckDependencies() ValidationResult {
+	result := ValidationResult{
+		Name:        "Dependencies",
+		Description: "Check references between events, parameters, audiences and calculated metrics",
+		Status:      ValidationPassed,
+	}
+	g := depgraph.Build(pv.config)
+	if warnings := g.Warnings(); len(warnings) > 0 {
+		result.Status = ValidationWarning
+		result.Warning = strings.Join(warnings, "\n      ")
+		return result
+	}
+	result.Details = fmt.Sprintf("%d references resolved", len(g.Edges))
+	return result
+}
+
+// CheckTierLimits reports the resources FitTierLimits would leave out of
+// setup to stay within the limits of the config's GA4 tier. It only reports:
+// the fitting runs on a copy, and setup applies it as a step of its own.
+func (pv *PreflightValidator) CheckTierLimits() ValidationResult {
+	tier := config.GetTierName(pv.config.GA4.Tier)
+	result := ValidationResult{
+		Name:        "Tier Limits",
+		Description: "Check new resources fit within the GA4 tier limits",
+		Status:      ValidationPassed,
+	}
+
+	if pv.ga4Client == nil {
+		result.Status = ValidationSkipped
+		result.Details = "GA4 client not initialised"
+		return result
+	}
+
+	fitted := *pv.config
+	dropped, err := FitTierLimits(&fitted, pv.ga4Client)
+	if err != nil {
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("could not count existing resources, limits not checked: %v", err)
+		return result
+	}
+	if len(dropped) == 0 {
+		result.Details = "within " + tier + " limits"
+		return result
+	}
+
+	result.Status = ValidationWarning
+	result.Warning = tierLimitsWarning(tier, dropped)
+	return result
+}
+
+// tierLimitsWarning lists the resources FitTierLimits left out.
+func tierLimitsWarning(tier string, dropped []DroppedResource) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d resources would go past the %s limits and will be left out:", len(dropped), tier)
+	for _, d := range dropped {
+		priority := d.Priority
+		if priority == "" {
+			priority = "no"
+		}
+		fmt.Fprintf(&b, "\n      - %s %s (%s priority)", d.Resource, d.Name, priority)
+	}
+	b.WriteString("\n      Raise their priority or remove others to keep them.")
+	return b.String()
+}
+
+// CheckGTMLinkage verifies that the configured GTM container sends to this
+// property's web stream, catching a Google tag still pointing at an old
+// property. Mismatches are warnings: they do not affect setup itself.
+func (pv *PreflightValidator) CheckGTMLinkage() ValidationResult {
+	result := ValidationResult{
+		Name:        "GTM Linkage",
+		Description: "Verify the GTM container sends to this property",
+		Status:      ValidationPassed,
+	}
+
+	if pv.ga4Client == nil || pv.gtmContainer == nil {
+		result.Status = ValidationSkipped
+		result.Details = "GA4 client or tag_manager container not configured"
+		return result
+	}
+
+	propertyID := pv.config.GetPropertyID()
+	streams, err := pv.ga4Client.ListDataStreams(propertyID)
+	if err != nil {
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("cannot list data streams: %v", err)
+		return result
+	}
+	var want []string
+	for _, s := range streams {
+		if s.WebStreamData != nil && s.WebStreamData.MeasurementId != "" {
+			want = append(want, s.WebStreamData.MeasurementId)
+		}
+	}
+	if len(want) == 0 {
+		result.Status = ValidationSkipped
+		result.Details = fmt.Sprintf("Property %s has no web data stream", propertyID)
+		return result
+	}
+
+	container, err := pv.gtmContainer(pv.ctx)
+	if err != nil {
+		result.Status = ValidationWarning
+		result.Warning = fmt.Sprintf("cannot read GTM container: %v", err)
+		result.Details = "Add the service account to the container with Read permission (GTM → Admin → User Management)"
+		return result
+	}
+	return gtmLinkageResult(result, gtm.CheckLinkage(container, want))
+}
+
+// gtmLinkageResult turns a linkage check into a preflight result.
+func gtmLinkageResult(result ValidationResult, l *gtm.Linkage) ValidationResult {
+	want := strings.Join(l.Want, ", ")
+	var problems []string
+	for _, t := range l.Mismatched() {
+		problems = append(problems, fmt.Sprintf("tag %q sends to %s, not this property's web stream (%s)", t.Tag, t.MeasurementID, want))
+	}
+	switch {
+	case len(l.Targets) == 0:
+		problems = append(problems, fmt.Sprintf("no active Google tag or GA4 configuration tag in %s", l.Container))
+	case !l.Linked() && len(l.Mismatched()) == 0:
+		var tags []string
+		for _, t := range l.Unverifiable() {
+			tags = append(tags, fmt.Sprintf("%q (%s)", t.Tag, t.MeasurementID))
+		}
+		problems = append(problems, "measurement ID set at run time, cannot verify: "+strings.Join(tags, ", "))
+	}
+
+	if len(problems) > 0 {
+		result.Status = ValidationWarning
+		result.Warning = strings.Join(problems, "; ")
+		return result
+	}
+	result.Details = fmt.Sprintf("%s sends to %s", l.Container, want)
 	return result
 }
 
@@ -427,74 +740,81 @@ func (pv *PreflightValidator) CheckGSCQuota() ValidationResult {
 // DetectConflicts checks for existing resources that would conflict
 func (pv *PreflightValidator) DetectConflicts() ([]ConflictWarning, error) {
 	conflicts := []ConflictWarning{}
+	scope := pv.config.SetupScope()
 
 	// Check GA4 conflicts
 	if pv.config.HasAnalytics() && pv.ga4Client != nil {
 		propertyID := pv.config.GetPropertyID()
 
 		// Check existing conversions
-		existingConversions, err := pv.ga4Client.ListConversions(propertyID)
-		if err != nil {
-			return nil, fmt.Errorf("list conversions: %w", err)
-		}
+		if scope.Includes(config.ResourceConversions) {
+			existingConversions, err := pv.ga4Client.ListConversions(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("list conversions: %w", err)
+			}
 
-		conversionMap := make(map[string]bool)
-		for _, conv := range existingConversions {
-			conversionMap[conv.EventName] = true
-		}
+			conversionMap := make(map[string]bool)
+			for _, conv := range existingConversions {
+				conversionMap[conv.EventName] = true
+			}
 
-		for _, conv := range pv.config.Conversions {
-			if conversionMap[conv.Name] {
-				conflicts = append(conflicts, ConflictWarning{
-					ResourceType: "conversion",
-					ResourceName: conv.Name,
-					Message:      fmt.Sprintf("Conversion '%s' already exists", conv.Name),
-					Action:       "skip",
-				})
+			for _, conv := range pv.config.Conversions {
+				if conversionMap[conv.Name] {
+					conflicts = append(conflicts, ConflictWarning{
+						ResourceType: "conversion",
+						ResourceName: conv.Name,
+						Message:      fmt.Sprintf("Conversion '%s' already exists", conv.Name),
+						Action:       "skip",
+					})
+				}
 			}
 		}
 
 		// Check existing dimensions
-		existingDimensions, err := pv.ga4Client.ListDimensions(propertyID)
-		if err != nil {
-			return nil, fmt.Errorf("list dimensions: %w", err)
-		}
+		if scope.Includes(config.ResourceDimensions) {
+			existingDimensions, err := pv.ga4Client.ListDimensions(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("list dimensions: %w", err)
+			}
 
-		dimensionMap := make(map[string]bool)
-		for _, dim := range existingDimensions {
-			dimensionMap[dim.ParameterName] = true
-		}
+			dimensionMap := make(map[string]bool)
+			for _, dim := range existingDimensions {
+				dimensionMap[dim.ParameterName] = true
+			}
 
-		for _, dim := range pv.config.Dimensions {
-			if dimensionMap[dim.ParameterName] {
-				conflicts = append(conflicts, ConflictWarning{
-					ResourceType: "dimension",
-					ResourceName: dim.DisplayName,
-					Message:      fmt.Sprintf("Dimension '%s' (param: %s) already exists", dim.DisplayName, dim.ParameterName),
-					Action:       "skip",
-				})
+			for _, dim := range pv.config.Dimensions {
+				if dimensionMap[dim.ParameterName] {
+					conflicts = append(conflicts, ConflictWarning{
+						ResourceType: "dimension",
+						ResourceName: dim.DisplayName,
+						Message:      fmt.Sprintf("Dimension '%s' (param: %s) already exists", dim.DisplayName, dim.ParameterName),
+						Action:       "skip",
+					})
+				}
 			}
 		}
 
 		// Check existing metrics
-		existingMetrics, err := pv.ga4Client.ListCustomMetrics(propertyID)
-		if err != nil {
-			return nil, fmt.Errorf("list metrics: %w", err)
-		}
+		if scope.Includes(config.ResourceMetrics) {
+			existingMetrics, err := pv.ga4Client.ListCustomMetrics(propertyID)
+			if err != nil {
+				return nil, fmt.Errorf("list metrics: %w", err)
+			}
 
-		metricMap := make(map[string]bool)
-		for _, metric := range existingMetrics {
-			metricMap[metric.ParameterName] = true
-		}
+			metricMap := make(map[string]bool)
+			for _, metric := range existingMetrics {
+				metricMap[metric.ParameterName] = true
+			}
 
-		for _, metric := range pv.config.Metrics {
-			if metricMap[metric.ParameterName] {
-				conflicts = append(conflicts, ConflictWarning{
-					ResourceType: "metric",
-					ResourceName: metric.DisplayName,
-					Message:      fmt.Sprintf("Metric '%s' (param: %s) already exists", metric.DisplayName, metric.ParameterName),
-					Action:       "skip",
-				})
+			for _, metric := range pv.config.Metrics {
+				if metricMap[metric.ParameterName] {
+					conflicts = append(conflicts, ConflictWarning{
+						ResourceType: "metric",
+						ResourceName: metric.DisplayName,
+						Message:      fmt.Sprintf("Metric '%s' (param: %s) already exists", metric.DisplayName, metric.ParameterName),
+						Action:       "skip",
+					})
+				}
 			}
 		}
 	}
@@ -504,24 +824,26 @@ func (pv *PreflightValidator) DetectConflicts() ([]ConflictWarning, error) {
 		siteURL := pv.config.SearchConsole.SiteURL
 
 		// Check existing sitemaps
-		existingSitemaps, err := pv.gscClient.ListSitemaps(siteURL)
-		if err != nil {
-			return nil, fmt.Errorf("list sitemaps: %w", err)
-		}
+		if scope.Includes(config.ResourceSitemaps) {
+			existingSitemaps, err := pv.gscClient.ListSitemaps(siteURL)
+			if err != nil {
+				return nil, fmt.Errorf("list sitemaps: %w", err)
+			}
 
-		sitemapMap := make(map[string]bool)
-		for _, sitemap := range existingSitemaps {
-			sitemapMap[sitemap.Path] = true
-		}
+			sitemapMap := make(map[string]bool)
+			for _, sitemap := range existingSitemaps {
+				sitemapMap[sitemap.Path] = true
+			}
 
-		for _, sitemap := range pv.config.SearchConsole.Sitemaps {
-			if sitemapMap[sitemap.URL] {
-				conflicts = append(conflicts, ConflictWarning{
-					ResourceType: "sitemap",
-					ResourceName: sitemap.URL,
-					Message:      fmt.Sprintf("Sitemap '%s' already submitted", sitemap.URL),
-					Action:       "skip",
-				})
+			for _, sitemap := range pv.config.SearchConsole.ActiveSitemaps() {
+				if sitemapMap[sitemap.URL] {
+					conflicts = append(conflicts, ConflictWarning{
+						ResourceType: "sitemap",
+						ResourceName: sitemap.URL,
+						Message:      fmt.Sprintf("Sitemap '%s' already submitted", sitemap.URL),
+						Action:       "skip",
+					})
+				}
 			}
 		}
 	}
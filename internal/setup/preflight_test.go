@@ -1,12 +1,19 @@
 package setup
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
 
 	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4/ga4fake"
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/gsc/gscfake"
+	"github.com/garbarok/ga4-manager/internal/gtm"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
 )
 
 // GA4 display names live in one namespace across dimensions AND metrics, so a
@@ -44,3 +51,126 @@ func TestValidateGA4Resources_UniqueDisplayNamesPass(t *testing.T) {
 
 	assert.Equal(t, ValidationPassed, result.Status)
 }
+
+func TestCheckDependencies(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Conversions: []config.ConversionConfig{{Name: "purchase"}},
+		Metrics: []config.MetricConfig{
+			{ParameterName: "word_count", DisplayName: "Word Count", MeasurementUnit: "STANDARD", Scope: "EVENT"},
+		},
+		Audiences: []config.AudienceConfig{{Name: "Buyers", Events: []string{"purchase"}}},
+		CalculatedMetrics: []config.CalculatedMetricConfig{
+			{Name: "Words per session", Formula: "customEvent:word_count / sessions"},
+		},
+	}
+	pv := NewPreflightValidator(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	result := pv.CheckDependencies()
+	assert.Equal(t, ValidationPassed, result.Status)
+	assert.Equal(t, "2 references resolved", result.Details)
+
+	cfg.Audiences = append(cfg.Audiences, config.AudienceConfig{Name: "Leads", Events: []string{"generate_lead"}})
+	cfg.CalculatedMetrics[0].Formula = "customEvent:read_time / sessions"
+	result = pv.CheckDependencies()
+	assert.Equal(t, ValidationWarning, result.Status)
+	assert.Contains(t, result.Warning, `audience "Leads" is built on event generate_lead, which is not a conversion`)
+	assert.Contains(t, result.Warning, `calculated metric "Words per session" references custom metric read_time`)
+}
+
+func TestGTMLinkageResult(t *testing.T) {
+	container := func(ids ...string) *gtm.Container {
+		c := &gtm.Container{Path: "accounts/1/containers/2"}
+		for _, id := range ids {
+			c.Tags = append(c.Tags, gtm.Tag{Name: "Google tag " + id, Type: gtm.TagTypeGoogleTag, MeasurementID: id, FiringTriggers: []string{"1"}})
+		}
+		return c
+	}
+	want := []string{"G-NEW123"}
+
+	ok := gtmLinkageResult(ValidationResult{}, gtm.CheckLinkage(container("G-NEW123"), want))
+	assert.Equal(t, ValidationPassed, ok.Status)
+	assert.Contains(t, ok.Details, "sends to G-NEW123")
+
+	stale := gtmLinkageResult(ValidationResult{}, gtm.CheckLinkage(container("G-OLD999"), want))
+	assert.Equal(t, ValidationWarning, stale.Status)
+	assert.Contains(t, stale.Warning, `tag "Google tag G-OLD999" sends to G-OLD999, not this property's web stream (G-NEW123)`)
+
+	none := gtmLinkageResult(ValidationResult{}, gtm.CheckLinkage(container(), want))
+	assert.Contains(t, none.Warning, "no active Google tag")
+
+	dynamic := gtmLinkageResult(ValidationResult{}, gtm.CheckLinkage(container("{{Lookup}}"), want))
+	assert.Equal(t, ValidationWarning, dynamic.Status)
+	assert.Contains(t, dynamic.Warning, "cannot verify")
+}
+
+func TestCheckGTMLinkage_SkippedWithoutClients(t *testing.T) {
+	cfg := &config.ProjectConfig{TagManager: &config.TagManagerConfig{AccountID: "1", ContainerID: "2"}}
+	pv := NewPreflightValidator(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Equal(t, ValidationSkipped, pv.CheckGTMLinkage().Status)
+}
+
+func TestValidateGA4Resources_InvalidChannelExpression(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		ChannelGroups: []config.ChannelGroupConfig{{
+			DisplayName: "Marketing Channels",
+			Rules: []config.ChannelRuleConfig{
+				{DisplayName: "Email", Expression: "eachScopeDefaultChannelGroup == 'Email'"},
+				{DisplayName: "Google", Expression: "eachScopeSource LIKE 'google'"},
+			},
+		}},
+	}
+	pv := NewPreflightValidator(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result := pv.ValidateGA4Resources()
+
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.Contains(t, result.Error.Error(), "channel group Marketing Channels rule Google: invalid expression")
+}
+
+func TestValidateGA4Resources_InvalidCalculatedMetric(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		CalculatedMetrics: []config.CalculatedMetricConfig{
+			{Name: "Reading Time", Formula: "customEvent:reading_time / (sessions"},
+			{Name: "Rate", ID: "rate", Formula: "conversions / sessions"},
+			{Name: "Rate Again", ID: "rate", Formula: "conversions / users"},
+		},
+	}
+	pv := NewPreflightValidator(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result := pv.ValidateGA4Resources()
+
+	assert.Equal(t, ValidationFailed, result.Status)
+	assert.Contains(t, result.Error.Error(), "calculated metric Reading Time: formula")
+	assert.Contains(t, result.Error.Error(), `calculated metric Rate Again: id "rate" is used twice`)
+}
+
+func TestDetectConflicts(t *testing.T) {
+	cfg := setupConfig()
+	ga4Fake := &ga4fake.Admin{
+		Conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{existingPurchase()},
+		Metrics: []*admin.GoogleAnalyticsAdminV1alphaCustomMetric{
+			{Name: "properties/123/customMetrics/word_count", ParameterName: "word_count", DisplayName: "Word Count"},
+		},
+	}
+	gscFake := &gscfake.SearchConsole{Sitemaps: []gsc.SitemapInfo{{Path: "https://example.com/news.xml"}}}
+	pv := NewPreflightValidator(cfg, ga4Fake, gscFake, discardLogger())
+
+	conflicts, err := pv.DetectConflicts()
+
+	require.NoError(t, err)
+	var got []string
+	for _, c := range conflicts {
+		got = append(got, c.ResourceType+" "+c.ResourceName)
+	}
+	assert.Equal(t, []string{"conversion purchase", "metric Word Count", "sitemap https://example.com/news.xml"}, got)
+	assert.Empty(t, ga4Fake.Called("CreateConversionFromConfig"), "detection only reads")
+}
+
+func TestDetectConflicts_ListFailure(t *testing.T) {
+	ga4Fake := &ga4fake.Admin{Errs: map[string]error{"ListDimensions": errors.New("permission denied")}}
+	pv := NewPreflightValidator(setupConfig(), ga4Fake, nil, discardLogger())
+
+	_, err := pv.DetectConflicts()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "list dimensions: permission denied")
+}
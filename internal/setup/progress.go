@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // StepStatus represents the status of a setup step
@@ -226,11 +228,11 @@ func (pt *ProgressTracker) RenderProgress() string {
 
 	var sb strings.Builder
 
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	gray := color.New(color.FgHiBlack).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
+	gray := theme.Color(color.FgHiBlack).SprintFunc()
 
 	for i, step := range pt.steps {
 		// Step header
@@ -294,9 +296,9 @@ func (pt *ProgressTracker) GenerateSummary() string {
 
 	var sb strings.Builder
 
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
 
 	// Count step statuses
 	completed := 0
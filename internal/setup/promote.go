@@ -0,0 +1,144 @@
+package setup
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// Promotion actions.
+const (
+	// PromoteActionCopy marks a resource the source property has and the
+	// target neither has nor configures; promote copies it.
+	PromoteActionCopy = "copy"
+	// PromoteActionDiffers marks a resource both properties have with
+	// different definitions. It is listed for review but not copied: changing
+	// an existing definition is up to the target's config.
+	PromoteActionDiffers = "differs"
+)
+
+// Promotion is what a staging property has that production lacks. Before is
+// the target property's state of a resource (nil when it has none), After
+// the source property's.
+type Promotion struct {
+	From    string   `json:"from_property_id"`
+	To      string   `json:"to_property_id"`
+	Changes []Change `json:"changes"`
+	// Copy holds the config entries of the resources to copy, taken from the
+	// source config where it defines them and from the source property
+	// otherwise.
+	Copy config.ProjectConfig `json:"-"`
+}
+
+// Pending reports whether the promotion copies anything.
+func (p *Promotion) Pending() bool {
+	for _, c := range p.Changes {
+		if c.Action == PromoteActionCopy {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildPromotion compares the live properties of from and to: their
+// conversions, custom dimensions and metrics, calculated metrics and custom
+// channel groups. Resources only the from property has are copied unless
+// to's config already defines them, which its next setup creates anyway.
+func BuildPromotion(from, to *config.ProjectConfig, lister DiffLister) (*Promotion, error) {
+	fromID, toID := from.GetPropertyID(), to.GetPropertyID()
+	if fromID == "" || toID == "" {
+		return nil, fmt.Errorf("both configs need a GA4 property_id")
+	}
+	if fromID == toID {
+		return nil, fmt.Errorf("both configs are for property %s", fromID)
+	}
+	source, err := ImportConfig(ImportOptions{Name: from.Project.Name, PropertyID: fromID}, lister)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", fromID, err)
+	}
+	target, err := ImportConfig(ImportOptions{Name: to.Project.Name, PropertyID: toID}, lister)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", toID, err)
+	}
+
+	p := &Promotion{From: fromID, To: toID, Changes: []Change{}}
+
+	p.Copy.Conversions = promote(p, DiffResourceConversion,
+		source.Conversions, target.Conversions, from.Conversions, to.Conversions,
+		func(c config.ConversionConfig) string { return c.Name },
+		func(c config.ConversionConfig) map[string]any { return conversionState(c.Name, c.CountingMethod) })
+	p.Copy.Dimensions = promote(p, DiffResourceDimension,
+		source.Dimensions, target.Dimensions, from.Dimensions, to.Dimensions,
+		func(d config.DimensionConfig) string { return d.ParameterName },
+		func(d config.DimensionConfig) map[string]any {
+			return dimensionState(d.ParameterName, d.DisplayName, d.Description, d.Scope)
+		})
+	p.Copy.Metrics = promote(p, DiffResourceMetric,
+		source.Metrics, target.Metrics, from.Metrics, to.Metrics,
+		func(m config.MetricConfig) string { return m.ParameterName },
+		configMetricState)
+	p.Copy.CalculatedMetrics = promote(p, DiffResourceCalculatedMetric,
+		source.CalculatedMetrics, target.CalculatedMetrics, from.CalculatedMetrics, to.CalculatedMetrics,
+		ga4.CalculatedMetricID,
+		func(c config.CalculatedMetricConfig) map[string]any {
+			unit := c.MetricUnit
+			if unit == "" {
+				unit = "STANDARD"
+			}
+			return calculatedMetricState(ga4.CalculatedMetricID(c), c.Name, c.Description, c.Formula, unit)
+		})
+	p.Copy.ChannelGroups = promote(p, DiffResourceChannelGroup,
+		source.ChannelGroups, target.ChannelGroups, from.ChannelGroups, to.ChannelGroups,
+		func(g config.ChannelGroupConfig) string { return g.DisplayName },
+		channelGroupState)
+	return p, nil
+}
+
+// promote records the resources of one type and returns those to copy.
+// source and target are what the two properties hold, fromCfg and toCfg what
+// their configs define.
+func promote[T any](p *Promotion, resource string, source, target, fromCfg, toCfg []T, key func(T) string, state func(T) map[string]any) []T {
+	live := make(map[string]T, len(target))
+	for _, t := range target {
+		live[key(t)] = t
+	}
+	configured := make(map[string]bool, len(toCfg))
+	for _, t := range toCfg {
+		configured[key(t)] = true
+	}
+	defined := make(map[string]T, len(fromCfg))
+	for _, f := range fromCfg {
+		defined[key(f)] = f
+	}
+
+	var copied []T
+	for _, s := range source {
+		name := key(s)
+		after := state(s)
+		if t, ok := live[name]; ok {
+			if before := state(t); !reflect.DeepEqual(before, after) {
+				p.Changes = append(p.Changes, Change{Resource: resource, Name: name, Action: PromoteActionDiffers, Before: before, After: after})
+			}
+			continue
+		}
+		if configured[name] {
+			continue
+		}
+		p.Changes = append(p.Changes, Change{Resource: resource, Name: name, Action: PromoteActionCopy, After: after})
+		if d, ok := defined[name]; ok {
+			s = d
+		}
+		copied = append(copied, s)
+	}
+	return copied
+}
+
+func channelGroupState(g config.ChannelGroupConfig) map[string]any {
+	rules := make([]map[string]any, 0, len(g.Rules))
+	for _, r := range g.Rules {
+		rules = append(rules, map[string]any{"display_name": r.DisplayName, "expression": r.Expression})
+	}
+	return map[string]any{"display_name": g.DisplayName, "description": g.Description, "rules": rules}
+}
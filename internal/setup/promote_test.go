@@ -0,0 +1,112 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// propertyListers serves each property from its own fakeDiffLister.
+type propertyListers map[string]*fakeDiffLister
+
+func (p propertyListers) ListConversions(id string) ([]*admin.GoogleAnalyticsAdminV1alphaConversionEvent, error) {
+	return p[id].ListConversions(id)
+}
+
+func (p propertyListers) ListDimensions(id string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomDimension, error) {
+	return p[id].ListDimensions(id)
+}
+
+func (p propertyListers) ListCustomMetrics(id string) ([]*admin.GoogleAnalyticsAdminV1alphaCustomMetric, error) {
+	return p[id].ListCustomMetrics(id)
+}
+
+func (p propertyListers) ListPropertyCalculatedMetrics(id string) ([]*admin.GoogleAnalyticsAdminV1alphaCalculatedMetric, error) {
+	return p[id].ListPropertyCalculatedMetrics(id)
+}
+
+func (p propertyListers) ListChannelGroups(id string) ([]*admin.GoogleAnalyticsAdminV1alphaChannelGroup, error) {
+	return p[id].ListChannelGroups(id)
+}
+
+func (p propertyListers) GetDataRetention(id string) (*ga4.DataRetentionSettings, error) {
+	return p[id].GetDataRetention(id)
+}
+
+func (p propertyListers) GetPropertyEnhancedMeasurement(id string) (*admin.GoogleAnalyticsAdminV1alphaEnhancedMeasurementSettings, error) {
+	return p[id].GetPropertyEnhancedMeasurement(id)
+}
+
+func TestBuildPromotion(t *testing.T) {
+	retention := &ga4.DataRetentionSettings{EventDataRetention: "FOURTEEN_MONTHS"}
+	lister := propertyListers{
+		"111": {
+			fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+				{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+				{EventName: "sign_up", CountingMethod: "ONCE_PER_SESSION"},
+				{EventName: "demo_booked", CountingMethod: "ONCE_PER_EVENT"},
+			}},
+			dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+				{ParameterName: "plan_type", DisplayName: "Plan Type", Scope: "EVENT"},
+				{ParameterName: "author", DisplayName: "Writer", Scope: "EVENT"},
+			},
+			groups: []*admin.GoogleAnalyticsAdminV1alphaChannelGroup{
+				{DisplayName: "Default Channel Group", SystemDefined: true},
+				{DisplayName: "Marketing", Description: "Paid and owned"},
+			},
+			retention: retention,
+		},
+		"222": {
+			fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+				{EventName: "purchase", CountingMethod: "ONCE_PER_EVENT"},
+			}},
+			dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+				{ParameterName: "author", DisplayName: "Author", Scope: "EVENT"},
+			},
+			retention: retention,
+		},
+	}
+	defaultValue := 50.0
+	staging := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Staging"},
+		GA4:         config.GA4Config{PropertyID: "111"},
+		Conversions: []config.ConversionConfig{{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION", DefaultValue: &defaultValue, Owner: "growth"}},
+	}
+	production := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Production"},
+		GA4:         config.GA4Config{PropertyID: "222"},
+		Conversions: []config.ConversionConfig{{Name: "demo_booked", CountingMethod: "ONCE_PER_EVENT"}},
+	}
+
+	p, err := BuildPromotion(staging, production, lister)
+	require.NoError(t, err)
+	assert.Equal(t, "111", p.From)
+	assert.Equal(t, "222", p.To)
+	assert.True(t, p.Pending())
+
+	var got []string
+	for _, c := range p.Changes {
+		got = append(got, c.Action+" "+c.Resource+" "+c.Name)
+	}
+	assert.Equal(t, []string{
+		"copy conversion sign_up",
+		"copy dimension plan_type",
+		"differs dimension author",
+		"copy channel_group Marketing",
+	}, got, "demo_booked is already in production's config")
+
+	require.Len(t, p.Copy.Conversions, 1)
+	assert.Equal(t, "growth", p.Copy.Conversions[0].Owner, "staging's config entry is copied")
+	assert.Equal(t, 50.0, *p.Copy.Conversions[0].DefaultValue)
+	require.Len(t, p.Copy.Dimensions, 1)
+	assert.Equal(t, "Plan Type", p.Copy.Dimensions[0].DisplayName, "taken from the property")
+	require.Len(t, p.Copy.ChannelGroups, 1)
+
+	_, err = BuildPromotion(staging, staging, lister)
+	assert.EqualError(t, err, "both configs are for property 111")
+}
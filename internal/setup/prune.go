@@ -0,0 +1,129 @@
+package setup
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// Prune actions. A protected resource is reported but never archived.
+const (
+	DiffActionPrune     = "prune"
+	DiffActionProtected = "protected"
+)
+
+// DefaultProtected are never pruned, whatever the config says: GA4 marks
+// purchase a key event on every web property and revenue reports rely on it.
+var DefaultProtected = []string{"purchase"}
+
+// BuildPrune lists the Property's conversions, dimensions and metrics and
+// returns, as changes with no After state, those the config does not declare:
+// the drift `ga4 cleanup --prune` archives. Resources are matched on the keys
+// SetupGA4 uses (event name, parameter name). A resource whose name matches
+// one of protected (path.Match patterns, such as "ga_*") or DefaultProtected
+// gets DiffActionProtected instead of DiffActionPrune. Resource types outside
+// the setup scope are not listed.
+func BuildPrune(cfg *config.ProjectConfig, lister ResourceLister, protected []string) (*Diff, error) {
+	propertyID := cfg.GetPropertyID()
+	if propertyID == "" {
+		return nil, fmt.Errorf("config %q has no GA4 property_id", cfg.Project.Name)
+	}
+	for _, p := range protected {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid protected pattern %q: %w", p, err)
+		}
+	}
+	protected = slices.Concat(protected, DefaultProtected)
+	scope := cfg.SetupScope()
+	d := &Diff{
+		SchemaVersion: DiffSchemaVersion,
+		Project:       cfg.Project.Name,
+		PropertyID:    propertyID,
+		Summary:       map[string]int{},
+		Changes:       []Change{},
+	}
+
+	if scope.Includes(config.ResourceConversions) {
+		declared := map[string]bool{}
+		for _, c := range cfg.Conversions {
+			declared[c.Name] = true
+		}
+		list, err := lister.ListConversions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conversions: %w", err)
+		}
+		var changes []Change
+		for _, c := range list {
+			if !declared[c.EventName] {
+				changes = append(changes, Change{Resource: DiffResourceConversion, Name: c.EventName, Before: conversionState(c.EventName, c.CountingMethod)})
+			}
+		}
+		d.addPrunes(changes, protected)
+	}
+
+	if scope.Includes(config.ResourceDimensions) {
+		declared := map[string]bool{}
+		for _, dim := range cfg.Dimensions {
+			declared[dim.ParameterName] = true
+		}
+		list, err := lister.ListDimensions(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dimensions: %w", err)
+		}
+		var changes []Change
+		for _, dim := range list {
+			if !declared[dim.ParameterName] {
+				changes = append(changes, Change{Resource: DiffResourceDimension, Name: dim.ParameterName,
+					Before: dimensionState(dim.ParameterName, dim.DisplayName, dim.Description, dim.Scope)})
+			}
+		}
+		d.addPrunes(changes, protected)
+	}
+
+	if scope.Includes(config.ResourceMetrics) {
+		declared := map[string]bool{}
+		for _, m := range cfg.Metrics {
+			declared[m.ParameterName] = true
+		}
+		list, err := lister.ListCustomMetrics(propertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list metrics: %w", err)
+		}
+		var changes []Change
+		for _, m := range list {
+			if !declared[m.ParameterName] {
+				changes = append(changes, Change{Resource: DiffResourceMetric, Name: m.ParameterName,
+					Before: metricState(m.ParameterName, m.DisplayName, m.Description, m.MeasurementUnit, m.Scope, restrictedType(m.RestrictedMetricType))})
+			}
+		}
+		d.addPrunes(changes, protected)
+	}
+	return d, nil
+}
+
+// addPrunes records changes sorted by name, each pruned unless protected.
+func (d *Diff) addPrunes(changes []Change, protected []string) {
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	for _, c := range changes {
+		c.Action = DiffActionPrune
+		if isProtected(c.Name, protected) {
+			c.Action = DiffActionProtected
+		}
+		d.Summary[c.Action]++
+		d.Changes = append(d.Changes, c)
+	}
+}
+
+// isProtected reports whether name matches one of the patterns, which
+// BuildPrune has validated.
+func isProtected(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
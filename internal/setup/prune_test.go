@@ -0,0 +1,63 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func TestBuildPrune(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Test"},
+		GA4:         config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "sign_up"}},
+		Dimensions:  []config.DimensionConfig{{ParameterName: "author"}},
+	}
+	lister := &fakeDiffLister{
+		fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+			{EventName: "sign_up"}, {EventName: "purchase"}, {EventName: "old_lead", CountingMethod: "ONCE_PER_EVENT"},
+		}},
+		dimensions: []*admin.GoogleAnalyticsAdminV1alphaCustomDimension{
+			{ParameterName: "word_count"}, {ParameterName: "author"}, {ParameterName: "ga_session_tag"},
+		},
+		metrics: []*admin.GoogleAnalyticsAdminV1alphaCustomMetric{{ParameterName: "reading_time"}},
+	}
+
+	d, err := BuildPrune(cfg, lister, []string{"ga_*"})
+	require.NoError(t, err)
+
+	var got []string
+	for _, c := range d.Changes {
+		got = append(got, c.Resource+" "+c.Name+" "+c.Action)
+	}
+	assert.Equal(t, []string{
+		"conversion old_lead prune",
+		"conversion purchase protected",
+		"dimension ga_session_tag protected",
+		"dimension word_count prune",
+		"metric reading_time prune",
+	}, got)
+	assert.Equal(t, map[string]int{DiffActionPrune: 3, DiffActionProtected: 2}, d.Summary)
+	assert.Equal(t, "ONCE_PER_EVENT", d.Changes[0].Before["counting_method"])
+	assert.Nil(t, d.Changes[0].After)
+}
+
+func TestBuildPrune_Scope(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Project: config.ProjectInfo{Name: "Test"},
+		GA4:     config.GA4Config{PropertyID: "123"},
+		Setup:   &config.SetupConfig{Only: []string{"dimensions"}},
+	}
+	lister := &fakeDiffLister{}
+
+	_, err := BuildPrune(cfg, lister, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dimensions"}, lister.calls, "out-of-scope resources are never pruned")
+
+	_, err = BuildPrune(cfg, lister, []string{"[bad"})
+	assert.ErrorContains(t, err, `invalid protected pattern "[bad"`)
+}
@@ -0,0 +1,77 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/cassette"
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/ga4"
+)
+
+// propertyRedaction stands in for the property a cassette was recorded
+// against.
+var propertyRedaction = cassette.Redaction{Env: "GA4_RECORD_PROPERTY_ID", Placeholder: "123"}
+
+// replayGA4Client returns a GA4 client replaying the named cassette.
+func replayGA4Client(t *testing.T, name string) (*ga4.Client, *cassette.Cassette) {
+	t.Helper()
+	c := cassette.New(t, name, propertyRedaction)
+	client, err := ga4.NewClient(ga4.WithHTTPClient(c.Client()), ga4.WithLogger(discardLogger()))
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, c
+}
+
+// The property has the purchase conversion; setup creates the rest.
+func TestSetupGA4_Replay(t *testing.T) {
+	client, c := replayGA4Client(t, "setup_ga4")
+	so := NewSetupOrchestrator(setupConfig(), "config.yaml", client, nil, discardLogger(), false)
+
+	require.NoError(t, so.SetupGA4())
+
+	assert.Empty(t, c.Unused())
+	ops := so.rollback.GetOperations()
+	require.Len(t, ops, 1)
+	assert.Equal(t, "Delete conversion: sign_up", ops[0].Description)
+}
+
+func TestSetupGA4_ReplayPermissionDenied(t *testing.T) {
+	cfg := setupConfig()
+	cfg.Conversions = cfg.Conversions[:1]
+	client, _ := replayGA4Client(t, "setup_ga4_permission_denied")
+	so := NewSetupOrchestrator(cfg, "config.yaml", client, nil, discardLogger(), false)
+
+	err := so.SetupGA4()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create conversion purchase")
+	assert.Contains(t, err.Error(), "The caller does not have permission")
+	assert.False(t, so.rollback.HasOperations())
+}
+
+// The property has drifted: purchase counts per session and author is
+// named Writer.
+func TestBuildDiff_Replay(t *testing.T) {
+	cfg := setupConfig()
+	cfg.DataRetention = &config.DataRetentionConfig{EventDataRetention: "FOURTEEN_MONTHS", ResetUserDataOnNewActivity: true}
+	client, c := replayGA4Client(t, "diff")
+
+	d, err := BuildDiff(cfg, client)
+
+	require.NoError(t, err)
+	assert.Empty(t, c.Unused())
+	actions := map[string]string{}
+	for _, change := range d.Changes {
+		actions[change.Resource+"/"+change.Name] = change.Action
+	}
+	assert.Equal(t, map[string]string{
+		"conversion/purchase":                  DiffActionDrift,
+		"conversion/sign_up":                   DiffActionCreate,
+		"dimension/author":                     DiffActionDrift,
+		"metric/word_count":                    DiffActionNoop,
+		"data_retention/dataRetentionSettings": DiffActionUpdate,
+	}, actions)
+}
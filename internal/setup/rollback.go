@@ -7,11 +7,13 @@ import (
 	"sync"
 
 	"github.com/fatih/color"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // RollbackOperation represents a single operation that can be rolled back
 type RollbackOperation struct {
-	Type        string // "conversion", "dimension", "metric", "sitemap"
+	Type        string // "conversion", "dimension", "metric", "calculated_metric", "channel_group", "data_retention", "enhanced_measurement", "sitemap"
 	ResourceID  string
 	PropertyID  string // GA4 property ID or GSC site URL
 	Rollback    func() error
@@ -76,14 +78,14 @@ func (rm *RollbackManager) ExecuteAll() error {
 		return nil
 	}
 
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
+	green := theme.Color(color.FgGreen).SprintFunc()
+	red := theme.Color(color.FgRed).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
 
-	fmt.Println()
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Printf("%s Rolling back changes...\n", yellow("⏮️"))
-	fmt.Println("───────────────────────────────────────────────")
+	theme.Println()
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Printf("%s Rolling back changes...\n", yellow("⏮️"))
+	theme.Println("───────────────────────────────────────────────")
 
 	successCount := 0
 	failCount := 0
@@ -93,10 +95,10 @@ func (rm *RollbackManager) ExecuteAll() error {
 	for i := len(rm.operations) - 1; i >= 0; i-- {
 		op := rm.operations[i]
 
-		fmt.Printf("  Rolling back %s: %s... ", op.Type, op.ResourceID)
+		theme.Printf("  Rolling back %s: %s... ", op.Type, op.ResourceID)
 
 		if err := op.Rollback(); err != nil {
-			fmt.Printf("%s\n", red("✗"))
+			theme.Printf("%s\n", red("✗"))
 			failCount++
 			errors = append(errors, fmt.Sprintf("%s %s: %v", op.Type, op.ResourceID, err))
 			rm.logger.Error("rollback failed",
@@ -104,7 +106,7 @@ func (rm *RollbackManager) ExecuteAll() error {
 				"resource", op.ResourceID,
 				"error", err)
 		} else {
-			fmt.Printf("%s\n", green("✓"))
+			theme.Printf("%s\n", green("✓"))
 			successCount++
 			rm.logger.Debug("rollback successful",
 				"type", op.Type,
@@ -112,18 +114,18 @@ func (rm *RollbackManager) ExecuteAll() error {
 		}
 	}
 
-	fmt.Println()
-	fmt.Printf("Rollback complete: %s %d succeeded", green("✓"), successCount)
+	theme.Println()
+	theme.Printf("Rollback complete: %s %d succeeded", green("✓"), successCount)
 	if failCount > 0 {
-		fmt.Printf(", %s %d failed", red("✗"), failCount)
+		theme.Printf(", %s %d failed", red("✗"), failCount)
 	}
-	fmt.Println()
+	theme.Println()
 
 	if len(errors) > 0 {
-		fmt.Println()
-		fmt.Printf("%s Some rollback operations failed:\n", yellow("⚠️"))
+		theme.Println()
+		theme.Printf("%s Some rollback operations failed:\n", yellow("⚠️"))
 		for _, err := range errors {
-			fmt.Printf("  - %s\n", err)
+			theme.Printf("  - %s\n", err)
 		}
 		return fmt.Errorf("%d rollback operations failed", failCount)
 	}
@@ -171,16 +173,16 @@ func (rm *RollbackManager) PromptForRollback() bool {
 		return false
 	}
 
-	yellow := color.New(color.FgYellow).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
+	yellow := theme.Color(color.FgYellow).SprintFunc()
+	blue := theme.Color(color.FgBlue).SprintFunc()
 
-	fmt.Println()
-	fmt.Println("═══════════════════════════════════════════════")
-	fmt.Printf("%s Setup failed!\n", yellow("⚠️"))
-	fmt.Println()
-	fmt.Println(rm.GenerateSummary())
-	fmt.Println()
-	fmt.Printf("%s Do you want to rollback the changes? (y/N): ", blue("?"))
+	theme.Println()
+	theme.Println("═══════════════════════════════════════════════")
+	theme.Printf("%s Setup failed!\n", yellow("⚠️"))
+	theme.Println()
+	theme.Println(rm.GenerateSummary())
+	theme.Println()
+	theme.Printf("%s Do you want to rollback the changes? (y/N): ", blue("?"))
 
 	var response string
 	_, _ = fmt.Scanln(&response)
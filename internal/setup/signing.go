@@ -0,0 +1,272 @@
+package setup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// SignatureAlgorithm is the only algorithm plans are signed with.
+const SignatureAlgorithm = "ed25519"
+
+// signedPlanDomain prefixes the signed bytes so a plan signature cannot be
+// replayed as a signature over anything else.
+const signedPlanDomain = "ga4-manager plan v1\n"
+
+// Errors returned when a plan cannot be applied.
+var (
+	ErrUnsignedPlan = errors.New("plan is not signed")
+	ErrBadSignature = errors.New("plan signature does not verify")
+	ErrStalePlan    = errors.New("plan no longer matches the property")
+)
+
+// PlanSignature is an Ed25519 signature over a plan. KeyID identifies the
+// public key that verifies it.
+type PlanSignature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	Value     string `json:"value"` // base64
+}
+
+// SignedPlan is the file `ga4 plan --sign` writes and `ga4 apply` reads: the
+// plan diff, the digest of the config it was built from, and a signature over
+// both. Plan is kept as raw JSON so it verifies byte for byte.
+type SignedPlan struct {
+	Plan         json.RawMessage `json:"plan"`
+	ConfigSHA256 string          `json:"config_sha256"`
+	Signature    *PlanSignature  `json:"signature,omitempty"`
+}
+
+// ConfigDigest is the SHA-256 of the config's canonical YAML, so formatting
+// and comments do not change it but any setting does.
+func ConfigDigest(cfg *config.ProjectConfig) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encode config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// NewSignedPlan wraps d for cfg without a signature.
+func NewSignedPlan(d *Diff, cfg *config.ProjectConfig) (*SignedPlan, error) {
+	plan, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("encode plan: %w", err)
+	}
+	digest, err := ConfigDigest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedPlan{Plan: plan, ConfigSHA256: digest}, nil
+}
+
+// ParseSignedPlan reads a signed plan, or a bare diff from `ga4 plan
+// --format json`, which comes back unsigned and without a config digest.
+func ParseSignedPlan(data []byte) (*SignedPlan, error) {
+	var p SignedPlan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	if len(p.Plan) == 0 {
+		p = SignedPlan{Plan: data}
+	}
+	if _, err := p.Diff(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Diff decodes the plan.
+func (p *SignedPlan) Diff() (*Diff, error) {
+	var d Diff
+	if err := json.Unmarshal(p.Plan, &d); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	if d.SchemaVersion != DiffSchemaVersion {
+		return nil, fmt.Errorf("plan schema_version %d is not supported (want %d)", d.SchemaVersion, DiffSchemaVersion)
+	}
+	return &d, nil
+}
+
+// Sign signs the plan with key, replacing any previous signature.
+func (p *SignedPlan) Sign(key ed25519.PrivateKey) error {
+	msg, err := p.message()
+	if err != nil {
+		return err
+	}
+	p.Signature = &PlanSignature{
+		Algorithm: SignatureAlgorithm,
+		KeyID:     KeyID(key.Public().(ed25519.PublicKey)),
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, msg)),
+	}
+	return nil
+}
+
+// Verify checks the signature against the trusted keys.
+func (p *SignedPlan) Verify(trusted []ed25519.PublicKey) error {
+	if p.Signature == nil {
+		return ErrUnsignedPlan
+	}
+	if p.Signature.Algorithm != SignatureAlgorithm {
+		return fmt.Errorf("%w: algorithm %q is not %s", ErrBadSignature, p.Signature.Algorithm, SignatureAlgorithm)
+	}
+	sig, err := base64.StdEncoding.DecodeString(p.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	msg, err := p.message()
+	if err != nil {
+		return err
+	}
+	for _, key := range trusted {
+		if ed25519.Verify(key, msg, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w with any trusted key (signed by %s)", ErrBadSignature, p.Signature.KeyID)
+}
+
+// message is the signed bytes: the domain, the config digest and the compact
+// plan. Compacting makes the signature survive re-indentation of the file.
+func (p *SignedPlan) message() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(signedPlanDomain)
+	buf.WriteString(p.ConfigSHA256)
+	buf.WriteByte('\n')
+	if err := json.Compact(&buf, p.Plan); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CheckCurrent reports ErrStalePlan when live, the diff built now, would
+// apply different changes than the plan, or leave out different resources to
+// stay within the tier limits: the property or the config changed after the
+// plan was reviewed.
+func (p *SignedPlan) CheckCurrent(live *Diff, cfg *config.ProjectConfig) error {
+	planned, err := p.Diff()
+	if err != nil {
+		return err
+	}
+	if planned.Project != live.Project || planned.PropertyID != live.PropertyID {
+		return fmt.Errorf("%w: plan is for %s (property %s), config is %s (property %s)",
+			ErrStalePlan, planned.Project, planned.PropertyID, live.Project, live.PropertyID)
+	}
+	if p.ConfigSHA256 != "" {
+		digest, err := ConfigDigest(cfg)
+		if err != nil {
+			return err
+		}
+		if digest != p.ConfigSHA256 {
+			return fmt.Errorf("%w: config changed since the plan was made", ErrStalePlan)
+		}
+	}
+	want, got := pendingChanges(planned), pendingChanges(live)
+	for _, key := range sortedKeys(got) {
+		if after, ok := want[key]; !ok || after != got[key] {
+			return fmt.Errorf("%w: %s is not in the plan", ErrStalePlan, key)
+		}
+	}
+	for _, key := range sortedKeys(want) {
+		if _, ok := got[key]; !ok {
+			return fmt.Errorf("%w: %s is planned but no longer needed", ErrStalePlan, key)
+		}
+	}
+	return nil
+}
+
+// pendingChanges keys the creates, updates and drops of d by "action
+// resource name" to the JSON of their after state.
+func pendingChanges(d *Diff) map[string]string {
+	out := map[string]string{}
+	for _, c := range d.Changes {
+		if c.Action != DiffActionCreate && c.Action != DiffActionUpdate && c.Action != DiffActionDropped {
+			continue
+		}
+		after, _ := json.Marshal(c.After)
+		out[fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)] = string(after)
+	}
+	return out
+}
+
+// KeyID is a short fingerprint of a public key: the first 8 bytes of its
+// SHA-256, in hex.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadPrivateKey reads an Ed25519 private key in PKCS #8 PEM, the format
+// `openssl genpkey -algorithm ed25519` writes.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", path)
+	}
+	return ed, nil
+}
+
+// LoadPublicKeys reads Ed25519 public keys in PKIX PEM, the format
+// `openssl pkey -pubout` writes.
+func LoadPublicKeys(paths []string) ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0, len(paths))
+	for _, path := range paths {
+		block, err := readPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		ed, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an Ed25519 public key", path)
+		}
+		keys = append(keys, ed)
+	}
+	return keys, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
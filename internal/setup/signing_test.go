@@ -0,0 +1,102 @@
+package setup
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admin "google.golang.org/api/analyticsadmin/v1alpha"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func testPlan(t *testing.T) (*Diff, *config.ProjectConfig) {
+	t.Helper()
+	cfg := &config.ProjectConfig{
+		Project:     config.ProjectInfo{Name: "Test"},
+		GA4:         config.GA4Config{PropertyID: "123"},
+		Conversions: []config.ConversionConfig{{Name: "sign_up", CountingMethod: "ONCE_PER_SESSION"}},
+	}
+	d, err := BuildDiff(cfg, &fakeDiffLister{})
+	require.NoError(t, err)
+	return d, cfg
+}
+
+func TestSignedPlan_SignVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	d, cfg := testPlan(t)
+
+	signed, err := NewSignedPlan(d, cfg)
+	require.NoError(t, err)
+	assert.ErrorIs(t, signed.Verify([]ed25519.PublicKey{pub}), ErrUnsignedPlan)
+
+	require.NoError(t, signed.Sign(priv))
+	assert.Equal(t, KeyID(pub), signed.Signature.KeyID)
+
+	// The signature survives the indented file round trip.
+	data, err := json.MarshalIndent(signed, "", "  ")
+	require.NoError(t, err)
+	parsed, err := ParseSignedPlan(data)
+	require.NoError(t, err)
+	require.NoError(t, parsed.Verify([]ed25519.PublicKey{other, pub}))
+	assert.ErrorIs(t, parsed.Verify([]ed25519.PublicKey{other}), ErrBadSignature)
+
+	parsed.ConfigSHA256 = "0000"
+	assert.ErrorIs(t, parsed.Verify([]ed25519.PublicKey{pub}), ErrBadSignature, "the config digest is signed")
+}
+
+func TestParseSignedPlan_BareDiff(t *testing.T) {
+	d, _ := testPlan(t)
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+
+	p, err := ParseSignedPlan(data)
+	require.NoError(t, err)
+	assert.Nil(t, p.Signature)
+	assert.Empty(t, p.ConfigSHA256)
+
+	_, err = ParseSignedPlan([]byte(`{"schema_version": 9}`))
+	assert.ErrorContains(t, err, "schema_version 9 is not supported")
+}
+
+func TestSignedPlan_CheckCurrent(t *testing.T) {
+	d, cfg := testPlan(t)
+	signed, err := NewSignedPlan(d, cfg)
+	require.NoError(t, err)
+	require.NoError(t, signed.CheckCurrent(d, cfg))
+
+	// A conversion added to the config after review.
+	cfg.Conversions = append(cfg.Conversions, config.ConversionConfig{Name: "purchase", CountingMethod: "ONCE_PER_EVENT"})
+	live, err := BuildDiff(cfg, &fakeDiffLister{})
+	require.NoError(t, err)
+	err = signed.CheckCurrent(live, cfg)
+	assert.True(t, errors.Is(err, ErrStalePlan))
+	assert.ErrorContains(t, err, "config changed")
+
+	// Same config, but the property already has the planned conversion.
+	bare := &SignedPlan{Plan: signed.Plan}
+	cfg.Conversions = cfg.Conversions[:1]
+	lister := &fakeDiffLister{fakeLister: fakeLister{conversions: []*admin.GoogleAnalyticsAdminV1alphaConversionEvent{
+		{EventName: "sign_up", CountingMethod: "ONCE_PER_SESSION"},
+	}}}
+	live, err = BuildDiff(cfg, lister)
+	require.NoError(t, err)
+	assert.ErrorContains(t, bare.CheckCurrent(live, cfg), "create conversion sign_up is planned but no longer needed")
+
+	// Same config, but the property filled up and sign_up no longer fits.
+	lister = &fakeDiffLister{}
+	for i := range 30 {
+		lister.conversions = append(lister.conversions, &admin.GoogleAnalyticsAdminV1alphaConversionEvent{EventName: fmt.Sprintf("event_%d", i)})
+	}
+	live, err = BuildDiff(cfg, lister)
+	require.NoError(t, err)
+	assert.ErrorContains(t, bare.CheckCurrent(live, cfg), "dropped conversion sign_up is not in the plan")
+}
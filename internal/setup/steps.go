@@ -0,0 +1,96 @@
+package setup
+
+import (
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// step is one resource of a setup section. apply makes its API call and may
+// run on another goroutine; it is nil when there is nothing to call (a
+// dry-run, a skipped or ignored resource). report prints the outcome and
+// records rollbacks; it always runs on the caller's goroutine, in order.
+// name labels the call in the live display.
+type step struct {
+	name   string
+	apply  func() error
+	report func(err error) error
+}
+
+// stepResult is what became of a step's apply.
+type stepResult struct {
+	err error
+	ran bool // false when runSteps stopped before the step's turn
+}
+
+// runSteps runs the apply calls of steps with at most workers in flight and
+// reports each step as soon as it and every step before it are done, so the
+// progress display reads the same as a sequential run. Once a report fails
+// no further apply starts; the ones already running finish and are still
+// reported, so their rollbacks are registered, and the first error is
+// returned. Steps without an API call are not reported after a failure.
+//
+// live, when not nil, shows the calls in flight while they run.
+func runSteps(steps []step, workers int, live *liveProgress) error {
+	results := make([]chan stepResult, len(steps))
+	for i := range results {
+		results[i] = make(chan stepResult, 1)
+	}
+
+	var stopped atomic.Bool
+	var g errgroup.Group
+	g.SetLimit(max(workers, 1))
+	go func() {
+		for i, s := range steps {
+			if s.apply == nil {
+				results[i] <- stepResult{ran: true}
+				continue
+			}
+			g.Go(func() error {
+				if stopped.Load() {
+					results[i] <- stepResult{}
+					return nil
+				}
+				live.begin(i, s.name)
+				err := s.apply()
+				live.finish(i)
+				results[i] <- stepResult{err: err, ran: true}
+				return nil
+			})
+		}
+		_ = g.Wait()
+	}()
+
+	var failed error
+	for i, s := range steps {
+		res := <-results[i]
+		if !res.ran || (failed != nil && s.apply == nil) {
+			continue
+		}
+		var err error
+		live.print(func() { err = s.report(res.err) })
+		if err != nil && failed == nil {
+			failed = err
+			stopped.Store(true)
+		}
+	}
+	return failed
+}
+
+// runSteps runs a section's steps, showing the calls in flight live when
+// the output allows it.
+func (so *SetupOrchestrator) runSteps(section string, steps []step, workers int) error {
+	calls := 0
+	for _, s := range steps {
+		if s.apply != nil {
+			calls++
+		}
+	}
+	var live *liveProgress
+	if so.live && calls > 0 {
+		live = newLiveProgress(os.Stdout, section, calls, workers)
+		defer live.close()
+	}
+	return runSteps(steps, workers, live)
+}
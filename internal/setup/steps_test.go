@@ -0,0 +1,85 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSteps_ReportsInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, peak int
+	var reported []int
+	steps := make([]step, 20)
+	for i := range steps {
+		steps[i].report = func(err error) error {
+			require.NoError(t, err)
+			reported = append(reported, i)
+			return nil
+		}
+		if i%5 == 0 {
+			continue // no API call, like a skipped resource
+		}
+		steps[i].apply = func() error {
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			// Later steps finish first
+			time.Sleep(time.Duration(20-i) * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	require.NoError(t, runSteps(steps, 4, nil))
+	want := make([]int, 20)
+	for i := range want {
+		want[i] = i
+	}
+	assert.Equal(t, want, reported)
+	assert.LessOrEqual(t, peak, 4)
+	assert.Greater(t, peak, 1)
+}
+
+func TestRunSteps_StopsAfterFailure(t *testing.T) {
+	var applied atomic.Int32
+	var reported []string
+	steps := make([]step, 50)
+	for i := range steps {
+		steps[i] = step{
+			apply: func() error {
+				applied.Add(1)
+				time.Sleep(time.Millisecond) // an API round trip
+				if i == 2 {
+					return errors.New("quota exceeded")
+				}
+				return nil
+			},
+			report: func(err error) error {
+				if err != nil {
+					reported = append(reported, fmt.Sprintf("%d failed", i))
+					return fmt.Errorf("create %d: %w", i, err)
+				}
+				reported = append(reported, fmt.Sprintf("%d ok", i))
+				return nil
+			},
+		}
+	}
+
+	err := runSteps(steps, 1, nil)
+	require.EqualError(t, err, "create 2: quota exceeded")
+	assert.Equal(t, []string{"0 ok", "1 ok", "2 failed"}, reported[:3])
+	// Steps already started when the failure was reported still complete
+	// and are reported; nothing after them starts.
+	assert.Less(t, applied.Load(), int32(50))
+	assert.Len(t, reported, int(applied.Load()))
+}
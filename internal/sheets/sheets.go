@@ -0,0 +1,256 @@
+// Package sheets writes report rows to a tab of a Google Sheet, for teams
+// that keep their reporting in Sheets. The tab is created when missing and
+// gets a header row; later writes either append below the rows already there
+// or replace them.
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	sh "google.golang.org/api/sheets/v4"
+)
+
+// Scheme prefixes a Sheets destination: sheets://<spreadsheetId>/<tab>.
+const Scheme = "sheets://"
+
+// Write modes.
+const (
+	ModeAppend  = "append"
+	ModeReplace = "replace"
+)
+
+// Modes lists the write modes, default first.
+func Modes() []string {
+	return []string{ModeAppend, ModeReplace}
+}
+
+// batchRows caps the rows sent in one append request, keeping requests well
+// under the API's payload limit on --all-rows pages.
+const batchRows = 10000
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Target identifies a tab of a spreadsheet.
+type Target struct {
+	SpreadsheetID string
+	Tab           string
+}
+
+// String returns the target as sheets://<spreadsheetId>/<tab>.
+func (t Target) String() string {
+	return Scheme + t.SpreadsheetID + "/" + t.Tab
+}
+
+// IsURL reports whether s names a Sheets destination rather than a file.
+func IsURL(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// ParseURL parses sheets://<spreadsheetId>/<tab>. The tab may be
+// percent-encoded ("Search%20Analytics").
+func ParseURL(s string) (Target, error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return Target{}, fmt.Errorf("%q is not a Sheets destination (want %s<spreadsheetId>/<tab>)", s, Scheme)
+	}
+	id, tab, ok := strings.Cut(rest, "/")
+	if !ok || tab == "" {
+		return Target{}, fmt.Errorf("invalid Sheets destination %q: want %s<spreadsheetId>/<tab>", s, Scheme)
+	}
+	if !idPattern.MatchString(id) {
+		return Target{}, fmt.Errorf("invalid spreadsheet ID %q in %s", id, s)
+	}
+	tab, err := url.PathUnescape(tab)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid tab name in %s: %w", s, err)
+	}
+	return Target{SpreadsheetID: id, Tab: tab}, nil
+}
+
+// sheetsAPI is the narrow consumer interface over the Sheets API.
+type sheetsAPI interface {
+	tabs(ctx context.Context, spreadsheetID string) ([]string, error)
+	addTab(ctx context.Context, spreadsheetID, title string) error
+	get(ctx context.Context, spreadsheetID, rng string) ([][]any, error)
+	clear(ctx context.Context, spreadsheetID, rng string) error
+	append(ctx context.Context, spreadsheetID, rng string, values [][]any) error
+}
+
+type realSheetsAPI struct {
+	svc *sh.Service
+}
+
+func (a *realSheetsAPI) tabs(ctx context.Context, spreadsheetID string) ([]string, error) {
+	s, err := a.svc.Spreadsheets.Get(spreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(s.Sheets))
+	for _, tab := range s.Sheets {
+		if tab.Properties != nil {
+			titles = append(titles, tab.Properties.Title)
+		}
+	}
+	return titles, nil
+}
+
+func (a *realSheetsAPI) addTab(ctx context.Context, spreadsheetID, title string) error {
+	_, err := a.svc.Spreadsheets.BatchUpdate(spreadsheetID, &sh.BatchUpdateSpreadsheetRequest{
+		Requests: []*sh.Request{{AddSheet: &sh.AddSheetRequest{Properties: &sh.SheetProperties{Title: title}}}},
+	}).Context(ctx).Do()
+	return err
+}
+
+func (a *realSheetsAPI) get(ctx context.Context, spreadsheetID, rng string) ([][]any, error) {
+	vr, err := a.svc.Spreadsheets.Values.Get(spreadsheetID, rng).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return vr.Values, nil
+}
+
+func (a *realSheetsAPI) clear(ctx context.Context, spreadsheetID, rng string) error {
+	_, err := a.svc.Spreadsheets.Values.Clear(spreadsheetID, rng, &sh.ClearValuesRequest{}).Context(ctx).Do()
+	return err
+}
+
+// append writes values RAW: a query such as "=IMPORTXML(…)" stays text
+// instead of becoming a formula, and numbers stay numbers.
+func (a *realSheetsAPI) append(ctx context.Context, spreadsheetID, rng string, values [][]any) error {
+	_, err := a.svc.Spreadsheets.Values.Append(spreadsheetID, rng, &sh.ValueRange{Values: values}).
+		ValueInputOption("RAW").InsertDataOption("INSERT_ROWS").Context(ctx).Do()
+	return err
+}
+
+// Writer writes rows to one tab. The first Write sets the tab up: creates it
+// when missing, clears it in replace mode and writes the header row when the
+// tab is empty. Every Write then appends, so a report streamed in pages
+// through several Writes ends up whole in either mode.
+type Writer struct {
+	api    sheetsAPI
+	target Target
+	mode   string
+	ready  bool
+}
+
+// NewWriter returns a Writer to t in mode, authenticated by opts, typically
+// the resolved credentials' client option.
+func NewWriter(ctx context.Context, t Target, mode string, opts ...option.ClientOption) (*Writer, error) {
+	if !slices.Contains(Modes(), mode) {
+		return nil, fmt.Errorf("invalid Sheets write mode %q (want %s)", mode, strings.Join(Modes(), " or "))
+	}
+	svc, err := sh.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Sheets service: %w", err)
+	}
+	return &Writer{api: &realSheetsAPI{svc: svc}, target: t, mode: mode}, nil
+}
+
+// Write appends rows, each holding the values of columns in order. Appending
+// to a tab whose header row differs from columns fails, rather than mixing
+// columns; replace mode starts the tab over instead.
+func (w *Writer) Write(ctx context.Context, columns []string, rows [][]any) error {
+	if !w.ready {
+		header, err := w.setUp(ctx, columns)
+		if err != nil {
+			return err
+		}
+		w.ready = true
+		if header {
+			rows = append([][]any{toCells(columns)}, rows...)
+		}
+	}
+	for start := 0; start < len(rows); start += batchRows {
+		end := min(start+batchRows, len(rows))
+		values := make([][]any, 0, end-start)
+		for _, row := range rows[start:end] {
+			values = append(values, cells(row))
+		}
+		if err := w.api.append(ctx, w.target.SpreadsheetID, w.rangeOf("A1"), values); err != nil {
+			return fmt.Errorf("failed to append to %s: %w", w.target, err)
+		}
+	}
+	return nil
+}
+
+// setUp prepares the tab for the first write and reports whether it needs
+// the header row.
+func (w *Writer) setUp(ctx context.Context, columns []string) (bool, error) {
+	titles, err := w.api.tabs(ctx, w.target.SpreadsheetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read spreadsheet %s: %w", w.target.SpreadsheetID, err)
+	}
+	if !slices.Contains(titles, w.target.Tab) {
+		if err := w.api.addTab(ctx, w.target.SpreadsheetID, w.target.Tab); err != nil {
+			return false, fmt.Errorf("failed to create tab %q: %w", w.target.Tab, err)
+		}
+		return true, nil
+	}
+	if w.mode == ModeReplace {
+		if err := w.api.clear(ctx, w.target.SpreadsheetID, w.rangeOf("")); err != nil {
+			return false, fmt.Errorf("failed to clear %s: %w", w.target, err)
+		}
+		return true, nil
+	}
+
+	existing, err := w.api.get(ctx, w.target.SpreadsheetID, w.rangeOf("1:1"))
+	if err != nil {
+		return false, fmt.Errorf("failed to read the header of %s: %w", w.target, err)
+	}
+	if len(existing) == 0 || len(existing[0]) == 0 {
+		return true, nil
+	}
+	header := make([]string, len(existing[0]))
+	for i, v := range existing[0] {
+		header[i] = fmt.Sprint(v)
+	}
+	if !slices.Equal(header, columns) {
+		return false, fmt.Errorf("%s has columns %s, not %s: append to another tab or replace it",
+			w.target, strings.Join(header, ","), strings.Join(columns, ","))
+	}
+	return false, nil
+}
+
+// rangeOf returns an A1 range on the tab; the quoted title allows spaces and
+// punctuation. Empty cells means the whole tab.
+func (w *Writer) rangeOf(cells string) string {
+	rng := "'" + strings.ReplaceAll(w.target.Tab, "'", "''") + "'"
+	if cells != "" {
+		rng += "!" + cells
+	}
+	return rng
+}
+
+func toCells(columns []string) []any {
+	row := make([]any, len(columns))
+	for i, c := range columns {
+		row[i] = c
+	}
+	return row
+}
+
+// cells converts Go values to cell values: nil is an empty cell, times are
+// RFC 3339 in UTC, and other non-primitive values (dates) their string form.
+func cells(row []any) []any {
+	out := make([]any, len(row))
+	for i, v := range row {
+		switch v := v.(type) {
+		case nil:
+			out[i] = ""
+		case time.Time:
+			out[i] = v.UTC().Format(time.RFC3339)
+		case string, bool, int, int32, int64, float32, float64:
+			out[i] = v
+		default:
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	target, err := ParseURL("sheets://1AbC-d_9/Search%20Analytics")
+	require.NoError(t, err)
+	assert.Equal(t, Target{SpreadsheetID: "1AbC-d_9", Tab: "Search Analytics"}, target)
+
+	for _, bad := range []string{"report.csv", "sheets://1AbC", "sheets://1AbC/", "sheets://bad id/tab"} {
+		_, err := ParseURL(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// fakeSheetsAPI keeps one spreadsheet's tabs in memory.
+type fakeSheetsAPI struct {
+	tabValues map[string][][]any
+	ranges    []string
+	err       error
+}
+
+func (f *fakeSheetsAPI) tabs(context.Context, string) ([]string, error) {
+	var titles []string
+	for title := range f.tabValues {
+		titles = append(titles, title)
+	}
+	return titles, nil
+}
+
+func (f *fakeSheetsAPI) addTab(_ context.Context, _, title string) error {
+	f.tabValues[title] = nil
+	return nil
+}
+
+func (f *fakeSheetsAPI) get(_ context.Context, _, rng string) ([][]any, error) {
+	f.ranges = append(f.ranges, rng)
+	values := f.tabValues["Data"]
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values[:1], nil
+}
+
+func (f *fakeSheetsAPI) clear(_ context.Context, _, rng string) error {
+	f.ranges = append(f.ranges, rng)
+	f.tabValues["Data"] = nil
+	return nil
+}
+
+func (f *fakeSheetsAPI) append(_ context.Context, _, rng string, values [][]any) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.ranges = append(f.ranges, rng)
+	f.tabValues["Data"] = append(f.tabValues["Data"], values...)
+	return nil
+}
+
+func newTestWriter(api *fakeSheetsAPI, mode string) *Writer {
+	return &Writer{api: api, target: Target{SpreadsheetID: "id", Tab: "Data"}, mode: mode}
+}
+
+func TestWriter_CreatesTabWithHeader(t *testing.T) {
+	api := &fakeSheetsAPI{tabValues: map[string][][]any{}}
+	w := newTestWriter(api, ModeAppend)
+
+	day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write(context.Background(), []string{"page", "clicks", "at", "note"}, [][]any{{"/a", int64(3), day, nil}}))
+	require.NoError(t, w.Write(context.Background(), []string{"page", "clicks", "at", "note"}, [][]any{{"/b", int64(1), day, nil}}))
+
+	assert.Equal(t, [][]any{
+		{"page", "clicks", "at", "note"},
+		{"/a", int64(3), "2026-10-16T09:00:00Z", ""},
+		{"/b", int64(1), "2026-10-16T09:00:00Z", ""},
+	}, api.tabValues["Data"])
+	assert.Equal(t, []string{"'Data'!A1", "'Data'!A1"}, api.ranges)
+}
+
+func TestWriter_AppendsBelowMatchingHeader(t *testing.T) {
+	api := &fakeSheetsAPI{tabValues: map[string][][]any{"Data": {{"page", "clicks"}, {"/a", int64(3)}}}}
+
+	require.NoError(t, newTestWriter(api, ModeAppend).Write(context.Background(), []string{"page", "clicks"}, [][]any{{"/b", int64(1)}}))
+	assert.Equal(t, [][]any{{"page", "clicks"}, {"/a", int64(3)}, {"/b", int64(1)}}, api.tabValues["Data"])
+}
+
+func TestWriter_RefusesToAppendOtherColumns(t *testing.T) {
+	api := &fakeSheetsAPI{tabValues: map[string][][]any{"Data": {{"query", "clicks"}}}}
+
+	err := newTestWriter(api, ModeAppend).Write(context.Background(), []string{"page", "clicks"}, [][]any{{"/b", int64(1)}})
+	assert.ErrorContains(t, err, "has columns query,clicks, not page,clicks")
+}
+
+func TestWriter_ReplaceClearsOnce(t *testing.T) {
+	api := &fakeSheetsAPI{tabValues: map[string][][]any{"Data": {{"query", "clicks"}, {"old", int64(9)}}}}
+	w := newTestWriter(api, ModeReplace)
+
+	require.NoError(t, w.Write(context.Background(), []string{"page"}, [][]any{{"/a"}}))
+	require.NoError(t, w.Write(context.Background(), []string{"page"}, [][]any{{"/b"}}))
+	assert.Equal(t, [][]any{{"page"}, {"/a"}, {"/b"}}, api.tabValues["Data"])
+	assert.Equal(t, "'Data'", api.ranges[0])
+}
+
+func TestWriter_AppendError(t *testing.T) {
+	api := &fakeSheetsAPI{tabValues: map[string][][]any{}, err: errors.New("quota")}
+
+	err := newTestWriter(api, ModeAppend).Write(context.Background(), []string{"page"}, [][]any{{"/a"}})
+	assert.EqualError(t, err, "failed to append to sheets://id/Data: quota")
+}
@@ -0,0 +1,393 @@
+// Package store keeps a local history of GSC report rows, URL inspection
+// results and other dated records, so coverage-state and position changes can
+// be examined beyond the 16 months Search Console itself retains.
+//
+// History lives in one SQLite database, <state-dir>/history.db, opened with
+// modernc.org/sqlite (pure Go, no cgo). Records are indexed by kind, site and
+// time, and their dimensions by key and value, so looking up one page or
+// query reads only its own rows. The schema is created and upgraded by the
+// migrations below, tracked in PRAGMA user_version. See
+// docs/adr/0006-local-history-store.md.
+//
+// Concurrent writers, such as cron runs that overlap, are safe: every batch
+// is one transaction that takes the write lock up front, and a writer waits
+// up to busyTimeout for the lock instead of failing. Readers wait the same
+// way while a batch commits. The default rollback journal is kept: WAL would
+// let readers run during a commit, but switching a file to it races with
+// writers that open the database at the same time.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// SchemaVersion is the database schema version this build reads and writes,
+// the number of migrations.
+const SchemaVersion = 1
+
+// dbFile is the history database, relative to the state directory.
+const dbFile = "history.db"
+
+// busyTimeout is how long a writer waits for another to commit.
+const busyTimeout = 10 * time.Second
+
+// migrations[i] upgrades the schema from version i to i+1.
+var migrations = [SchemaVersion]string{
+	`CREATE TABLE records (
+		id          INTEGER PRIMARY KEY,
+		kind        TEXT    NOT NULL,
+		site        TEXT    NOT NULL,
+		recorded_at INTEGER NOT NULL, -- Unix nanoseconds
+		start_date  TEXT    NOT NULL DEFAULT '',
+		end_date    TEXT    NOT NULL DEFAULT '',
+		dimensions  TEXT,             -- JSON objects
+		metrics     TEXT,
+		state       TEXT
+	);
+	CREATE INDEX records_by_site_time ON records (kind, site, recorded_at);
+	CREATE TABLE record_dimensions (
+		record_id INTEGER NOT NULL REFERENCES records (id) ON DELETE CASCADE,
+		key       TEXT    NOT NULL,
+		value     TEXT    NOT NULL,
+		PRIMARY KEY (record_id, key)
+	) WITHOUT ROWID;
+	CREATE INDEX record_dimensions_by_value ON record_dimensions (key, value, record_id);`,
+}
+
+// Record kinds.
+const (
+	KindAnalytics  = "analytics"  // one Search Analytics row
+	KindInspection = "inspection" // one URL Inspection result
+	KindCoverage   = "coverage"   // one page of an index coverage estimate
+	KindAudit      = "audit"      // one mutating API call (see internal/auditlog)
+	KindMarker     = "marker"     // one dated annotation (see internal/markers)
+)
+
+// ErrSchemaVersionMismatch is returned when the history database was written
+// by a newer build, with a schema this build does not understand.
+var ErrSchemaVersionMismatch = errors.New("store: schema version mismatch")
+
+// ErrInvalidKey is returned when kind or site is empty.
+var ErrInvalidKey = errors.New("store: kind and site must be non-empty")
+
+// Record is one saved observation. Dimensions identify what was observed
+// (query, page, country… for analytics; page for inspections), Metrics hold
+// numeric values and State holds categorical ones such as coverage_state.
+type Record struct {
+	SchemaVersion int                `json:"schema_version"`
+	Kind          string             `json:"kind"`
+	Site          string             `json:"site"`
+	RecordedAt    time.Time          `json:"recorded_at"`
+	StartDate     string             `json:"start_date,omitempty"`
+	EndDate       string             `json:"end_date,omitempty"`
+	Dimensions    map[string]string  `json:"dimensions,omitempty"`
+	Metrics       map[string]float64 `json:"metrics,omitempty"`
+	State         map[string]string  `json:"state,omitempty"`
+}
+
+// Query selects records. Kind and Site are required; Match keeps only records
+// whose dimensions contain every given key/value pair, Since drops records
+// saved before it and Until drops records saved after it, each when non-zero.
+type Query struct {
+	Kind  string
+	Site  string
+	Match map[string]string
+	Since time.Time
+	Until time.Time
+}
+
+// Store appends to and reads the history database under one state directory.
+// Each call opens the database and closes it before returning, so a Store
+// needs no closing and several processes can share the file.
+type Store struct {
+	path string
+	now  func() time.Time
+}
+
+// New returns a Store rooted at stateDir (see state.ResolveStateDir). The
+// database is created on the first Append.
+func New(stateDir string) *Store {
+	return &Store{path: filepath.Join(stateDir, dbFile), now: time.Now}
+}
+
+// open opens the database and brings its schema up to date. Unless create is
+// set, a missing database is not created: open returns nil and no error.
+func (s *Store) open(ctx context.Context, create bool) (*sql.DB, error) {
+	if _, err := os.Stat(s.path); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		if !create {
+			return nil, nil
+		}
+		if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+			return nil, fmt.Errorf("store: create state directory: %w", err)
+		}
+	}
+	dsn := fmt.Sprintf("%s?_txlock=immediate&_pragma=busy_timeout(%d)&_pragma=foreign_keys(1)",
+		s.path, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", s.path, err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store: %s: %w", s.path, err)
+	}
+	return db, nil
+}
+
+// migrate applies the migrations the database has not had yet, in one
+// transaction. The version is read again under the write lock, so two
+// processes opening a new database do not both create it.
+func migrate(ctx context.Context, db *sql.DB) error {
+	version, err := schemaVersion(ctx, db)
+	if err != nil || version == SchemaVersion {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if version, err = schemaVersion(ctx, tx); err != nil {
+		return err
+	}
+	for v := version; v < SchemaVersion; v++ {
+		if _, err := tx.ExecContext(ctx, migrations[v]); err != nil {
+			return fmt.Errorf("migrate to version %d: %w", v+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// schemaVersion reads the database's version, rejecting a newer schema.
+func schemaVersion(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}) (int, error) {
+	var version int
+	if err := q.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if version > SchemaVersion {
+		return 0, fmt.Errorf("%w: the database is at version %d, this build reads %d", ErrSchemaVersionMismatch, version, SchemaVersion)
+	}
+	return version, nil
+}
+
+// Append saves records of one kind for one site, stamping each without a
+// RecordedAt with the current time. The batch is one transaction: readers see
+// all of it or none of it, and concurrent batches do not interleave.
+func (s *Store) Append(ctx context.Context, kind, site string, records []Record) error {
+	if kind == "" || site == "" {
+		return ErrInvalidKey
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	db, err := s.open(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	insert, err := tx.PrepareContext(ctx, `INSERT INTO records
+		(kind, site, recorded_at, start_date, end_date, dimensions, metrics, state)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	insertDim, err := tx.PrepareContext(ctx, `INSERT INTO record_dimensions (record_id, key, value) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+
+	now := s.now().UTC()
+	for _, r := range records {
+		at := r.RecordedAt
+		if at.IsZero() {
+			at = now
+		}
+		dims, err := encodeJSON(r.Dimensions)
+		if err != nil {
+			return err
+		}
+		metrics, err := encodeJSON(r.Metrics)
+		if err != nil {
+			return err
+		}
+		state, err := encodeJSON(r.State)
+		if err != nil {
+			return err
+		}
+		res, err := insert.ExecContext(ctx, kind, site, at.UnixNano(), r.StartDate, r.EndDate, dims, metrics, state)
+		if err != nil {
+			return fmt.Errorf("store: append record: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("store: append record: %w", err)
+		}
+		for k, v := range r.Dimensions {
+			if _, err := insertDim.ExecContext(ctx, id, k, v); err != nil {
+				return fmt.Errorf("store: append record: %w", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: commit: %w", err)
+	}
+	return nil
+}
+
+// Query returns the matching records, oldest first and in the order they
+// were appended. A site with no history yields an empty slice, not an error.
+func (s *Store) Query(ctx context.Context, q Query) ([]Record, error) {
+	if q.Kind == "" || q.Site == "" {
+		return nil, ErrInvalidKey
+	}
+	db, err := s.open(ctx, false)
+	if err != nil || db == nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	where, args := q.where()
+	rows, err := db.QueryContext(ctx, `SELECT recorded_at, start_date, end_date, dimensions, metrics, state
+		FROM records WHERE `+where+` ORDER BY recorded_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Record
+	for rows.Next() {
+		r := Record{SchemaVersion: SchemaVersion, Kind: q.Kind, Site: q.Site}
+		var at int64
+		var dims, metrics, state sql.NullString
+		if err := rows.Scan(&at, &r.StartDate, &r.EndDate, &dims, &metrics, &state); err != nil {
+			return nil, fmt.Errorf("store: query: %w", err)
+		}
+		r.RecordedAt = time.Unix(0, at).UTC()
+		if err := decodeJSON(dims, &r.Dimensions); err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(metrics, &r.Metrics); err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(state, &r.State); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: query: %w", err)
+	}
+	return out, nil
+}
+
+// where returns the SQL condition selecting q's records, and its arguments.
+// Each Match pair is a lookup in the dimension index.
+func (q Query) where() (string, []any) {
+	conds := []string{"kind = ?", "site = ?"}
+	args := []any{q.Kind, q.Site}
+	if !q.Since.IsZero() {
+		conds = append(conds, "recorded_at >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		conds = append(conds, "recorded_at <= ?")
+		args = append(args, q.Until.UnixNano())
+	}
+	keys := make([]string, 0, len(q.Match))
+	for k := range q.Match {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		conds = append(conds, "id IN (SELECT record_id FROM record_dimensions WHERE key = ? AND value = ?)")
+		args = append(args, k, q.Match[k])
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// LatestRun returns the records of the most recent Append for (kind, site),
+// or nil when nothing has been saved yet.
+func (s *Store) LatestRun(ctx context.Context, kind, site string) ([]Record, error) {
+	return s.RunAsOf(ctx, kind, site, time.Time{})
+}
+
+// RunAsOf returns the records of the last Append for (kind, site) saved at
+// or before at, or nil when there is none. A zero at means the latest run.
+func (s *Store) RunAsOf(ctx context.Context, kind, site string, at time.Time) ([]Record, error) {
+	return s.LastRun(ctx, Query{Kind: kind, Site: site, Until: at})
+}
+
+// LastRun returns the records of the last Append among those q selects, or
+// nil when there is none. Match narrows it to the runs of one command when
+// several save the same kind.
+func (s *Store) LastRun(ctx context.Context, q Query) ([]Record, error) {
+	if q.Kind == "" || q.Site == "" {
+		return nil, ErrInvalidKey
+	}
+	db, err := s.open(ctx, false)
+	if err != nil || db == nil {
+		return nil, err
+	}
+	where, args := q.where()
+	var last sql.NullInt64
+	err = db.QueryRowContext(ctx, `SELECT MAX(recorded_at) FROM records WHERE `+where, args...).Scan(&last)
+	_ = db.Close()
+	if err != nil {
+		return nil, fmt.Errorf("store: query: %w", err)
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	q.Since = time.Unix(0, last.Int64)
+	q.Until = q.Since
+	return s.Query(ctx, q)
+}
+
+// encodeJSON returns v as a JSON column value, NULL when v is empty.
+func encodeJSON[M ~map[string]V, V any](v M) (any, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("store: encode record: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeJSON(col sql.NullString, v any) error {
+	if !col.Valid {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(col.String), v); err != nil {
+		return fmt.Errorf("store: decode record: %w", err)
+	}
+	return nil
+}
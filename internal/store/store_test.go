@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestStore_AppendThenQuery(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+	site := "sc-domain:example.com"
+
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindAnalytics, site, []Record{
+		{Dimensions: map[string]string{"query": "image compressor"}, Metrics: map[string]float64{"position": 8.2}},
+		{Dimensions: map[string]string{"query": "png to webp"}, Metrics: map[string]float64{"position": 3}},
+	}))
+	s.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindAnalytics, site, []Record{
+		{Dimensions: map[string]string{"query": "image compressor"}, Metrics: map[string]float64{"position": 5.1}},
+	}))
+
+	got, err := s.Query(ctx, Query{Kind: KindAnalytics, Site: site, Match: map[string]string{"query": "image compressor"}})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 8.2, got[0].Metrics["position"])
+	assert.Equal(t, 5.1, got[1].Metrics["position"])
+	assert.Equal(t, SchemaVersion, got[0].SchemaVersion)
+	assert.Equal(t, site, got[0].Site)
+
+	since, err := s.Query(ctx, Query{Kind: KindAnalytics, Site: site, Since: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Len(t, since, 1)
+}
+
+func TestStore_Query_NoHistory(t *testing.T) {
+	got, err := New(t.TempDir()).Query(context.Background(), Query{Kind: KindInspection, Site: "sc-domain:example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestStore_Query_SchemaVersionMismatch(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+	require.NoError(t, s.Append(context.Background(), KindInspection, "sc-domain:example.com", []Record{{}}))
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, dbFile))
+	require.NoError(t, err)
+	_, err = db.Exec("PRAGMA user_version = 99")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = s.Query(context.Background(), Query{Kind: KindInspection, Site: "sc-domain:example.com"})
+	assert.ErrorIs(t, err, ErrSchemaVersionMismatch)
+}
+
+// Overlapping writers, each with its own connection as separate cron runs
+// would have, all succeed and their batches do not interleave.
+func TestStore_ConcurrentAppends(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	site := "sc-domain:example.com"
+	const writers, batches, size = 6, 10, 20
+
+	var g errgroup.Group
+	for w := range writers {
+		g.Go(func() error {
+			s := New(dir)
+			for b := range batches {
+				batch := make([]Record, size)
+				for i := range batch {
+					batch[i] = Record{Dimensions: map[string]string{"batch": fmt.Sprintf("%d-%d", w, b)}}
+				}
+				if err := s.Append(ctx, KindAnalytics, site, batch); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	require.NoError(t, g.Wait())
+
+	all, err := New(dir).Query(ctx, Query{Kind: KindAnalytics, Site: site})
+	require.NoError(t, err)
+	require.Len(t, all, writers*batches*size)
+	for i := 0; i < len(all); i += size {
+		for _, r := range all[i : i+size] {
+			assert.Equal(t, all[i].Dimensions["batch"], r.Dimensions["batch"])
+		}
+	}
+}
+
+// Lookups by site, time and dimension are served by the indexes, not by a
+// scan of the whole history.
+func TestQuery_UsesIndexes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, New(dir).Append(context.Background(), KindAnalytics, "sc-domain:example.com", []Record{{}}))
+	db, err := sql.Open("sqlite", filepath.Join(dir, dbFile))
+	require.NoError(t, err)
+	defer db.Close()
+
+	where, args := Query{
+		Kind: KindAnalytics, Site: "sc-domain:example.com",
+		Match: map[string]string{"query": "image compressor"},
+		Since: time.Now(),
+	}.where()
+	rows, err := db.Query("EXPLAIN QUERY PLAN SELECT * FROM records WHERE "+where, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		require.NoError(t, rows.Scan(&id, &parent, &unused, &detail))
+		plan = append(plan, detail)
+	}
+	require.NoError(t, rows.Err())
+	joined := strings.Join(plan, "\n")
+	assert.NotContains(t, joined, "SCAN records")
+	assert.Contains(t, joined, "record_dimensions_by_value")
+}
+
+func TestStore_InvalidKey(t *testing.T) {
+	s := New(t.TempDir())
+	assert.ErrorIs(t, s.Append(context.Background(), "", "sc-domain:example.com", []Record{{}}), ErrInvalidKey)
+	_, err := s.Query(context.Background(), Query{Kind: KindAnalytics})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestStore_LatestRun(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+	site := "sc-domain:example.com"
+
+	latest, err := s.LatestRun(ctx, KindCoverage, site)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindCoverage, site, []Record{{Dimensions: map[string]string{"page": "/old"}}}))
+	s.now = func() time.Time { return time.Date(2026, 5, 8, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindCoverage, site, []Record{
+		{Dimensions: map[string]string{"page": "/a"}},
+		{Dimensions: map[string]string{"page": "/b"}},
+	}))
+
+	latest, err = s.LatestRun(ctx, KindCoverage, site)
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Equal(t, "/a", latest[0].Dimensions["page"])
+}
+
+func TestStore_RunAsOf(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+	site := "sc-domain:example.com"
+
+	day := func(d int) time.Time { return time.Date(2026, 5, d, 0, 0, 0, 0, time.UTC) }
+	for _, d := range []int{1, 8} {
+		s.now = func() time.Time { return day(d) }
+		require.NoError(t, s.Append(ctx, KindCoverage, site, []Record{{Dimensions: map[string]string{"page": fmt.Sprintf("/day-%d", d)}}}))
+	}
+
+	run, err := s.RunAsOf(ctx, KindCoverage, site, day(7))
+	require.NoError(t, err)
+	require.Len(t, run, 1)
+	assert.Equal(t, "/day-1", run[0].Dimensions["page"])
+
+	run, err = s.RunAsOf(ctx, KindCoverage, site, day(8))
+	require.NoError(t, err)
+	assert.Equal(t, "/day-8", run[0].Dimensions["page"], "a run saved exactly at the cut-off counts")
+
+	run, err = s.RunAsOf(ctx, KindCoverage, site, day(1).Add(-time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
+
+func TestStore_LastRun_Match(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+	site := "sc-domain:example.com"
+
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindInspection, site, []Record{
+		{Dimensions: map[string]string{"page": "/a", "source": "monitor"}},
+		{Dimensions: map[string]string{"page": "/b", "source": "monitor"}},
+	}))
+	s.now = func() time.Time { return time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.Append(ctx, KindInspection, site, []Record{{Dimensions: map[string]string{"page": "/a"}}}))
+
+	run, err := s.LastRun(ctx, Query{Kind: KindInspection, Site: site, Match: map[string]string{"source": "monitor"}})
+	require.NoError(t, err)
+	assert.Len(t, run, 2, "the later run of another command is skipped")
+}
@@ -0,0 +1,381 @@
+package summary
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/markers"
+)
+
+// Trend classes: whether a change is good news, bad news or neither. For
+// position, a fall is good.
+const (
+	trendGood = "good"
+	trendBad  = "bad"
+	trendFlat = "flat"
+)
+
+// metricRow is a formatted row of the "at a glance" table.
+type metricRow struct {
+	Label   string
+	Current string
+	Prior   string
+	Change  string
+	Trend   string
+}
+
+// moverRow is a formatted row of a top movers table.
+type moverRow struct {
+	Key     string
+	Current string
+	Prior   string
+	Change  string
+	Trend   string
+}
+
+// issueRow is a formatted new indexing issue.
+type issueRow struct {
+	URL    string
+	Detail string
+}
+
+// view is Weekly formatted for the templates.
+type view struct {
+	Title       string
+	Period      string
+	Site        string
+	PropertyID  string
+	GeneratedAt string
+	Metrics     []metricRow
+	Queries     []moverRow
+	Pages       []moverRow
+	HasSearch   bool
+	Indexing    *Indexing
+	Issues      []issueRow
+	IssuesSince string
+	Markers     []markers.Marker
+}
+
+func newView(s Weekly) view {
+	v := view{
+		Title:       "Weekly summary",
+		Period:      s.Current.Start + " to " + s.Current.End + ", compared with " + s.Prior.Start + " to " + s.Prior.End,
+		Site:        s.Site,
+		GeneratedAt: s.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"),
+		HasSearch:   s.Search != nil,
+		Indexing:    s.Indexing,
+		Markers:     s.Markers,
+	}
+	if s.Project != "" {
+		v.Title += ": " + s.Project
+	}
+	if a := s.Analytics; a != nil {
+		v.PropertyID = a.PropertyID
+		v.Metrics = append(v.Metrics,
+			countRow("Sessions", a.Sessions),
+			countRow("Key events", a.KeyEvents),
+		)
+	}
+	if sc := s.Search; sc != nil {
+		v.Metrics = append(v.Metrics,
+			countRow("Search clicks", sc.Clicks),
+			countRow("Search impressions", sc.Impressions),
+			metricRow{
+				Label:   "Search CTR",
+				Current: formatPercent(sc.CTR.Current),
+				Prior:   formatPercent(sc.CTR.Prior),
+				Change:  signed(sc.CTR.Delta()*100, 2) + " pp",
+				Trend:   trend(sc.CTR.Delta(), false),
+			},
+			metricRow{
+				Label:   "Average position",
+				Current: strconv.FormatFloat(sc.Position.Current, 'f', 1, 64),
+				Prior:   strconv.FormatFloat(sc.Position.Prior, 'f', 1, 64),
+				Change:  signed(sc.Position.Delta(), 1),
+				Trend:   trend(sc.Position.Delta(), true),
+			},
+		)
+	}
+	v.Queries = moverRows(s.TopQueries)
+	v.Pages = moverRows(s.TopPages)
+	if ix := s.Indexing; ix != nil {
+		if !ix.Baseline.IsZero() {
+			v.IssuesSince = ix.Baseline.UTC().Format("2006-01-02")
+		}
+		for _, t := range ix.NewIssues {
+			v.Issues = append(v.Issues, issueRow{URL: t.URL, Detail: issueDetail(t)})
+		}
+	}
+	return v
+}
+
+func countRow(label string, c Change) metricRow {
+	return metricRow{
+		Label:   label,
+		Current: formatCount(c.Current),
+		Prior:   formatCount(c.Prior),
+		Change:  formatChange(c),
+		Trend:   trend(c.Delta(), false),
+	}
+}
+
+func moverRows(movers []Mover) []moverRow {
+	rows := make([]moverRow, 0, len(movers))
+	for _, m := range movers {
+		rows = append(rows, moverRow{
+			Key:     m.Key,
+			Current: formatCount(m.Clicks.Current),
+			Prior:   formatCount(m.Clicks.Prior),
+			Change:  signed(m.Clicks.Delta(), 0) + " (" + formatChange(m.Clicks) + ")",
+			Trend:   trend(m.Clicks.Delta(), false),
+		})
+	}
+	return rows
+}
+
+// issueDetail says what went wrong with a URL: dropped out of the index,
+// the issue types that appeared, or both.
+func issueDetail(t gsc.InspectionTransition) string {
+	var parts []string
+	if t.Deindexed {
+		parts = append(parts, "dropped out of the index")
+	}
+	if len(t.Issues) > 0 {
+		parts = append(parts, strings.Join(t.Issues, ", "))
+	}
+	if t.FirstSeen {
+		parts = append(parts, "first inspected this week")
+	}
+	return strings.Join(parts, "; ")
+}
+
+func trend(delta float64, lowerIsBetter bool) string {
+	switch {
+	case delta == 0:
+		return trendFlat
+	case (delta < 0) == lowerIsBetter:
+		return trendGood
+	default:
+		return trendBad
+	}
+}
+
+// formatChange is the relative change, "new" when the prior window had
+// nothing.
+func formatChange(c Change) string {
+	p, ok := c.Percent()
+	switch {
+	case ok:
+		return signed(p*100, 1) + "%"
+	case c.Current == 0:
+		return "0%"
+	default:
+		return "new"
+	}
+}
+
+// signed formats v with an explicit sign; a value that rounds to zero has
+// none.
+func signed(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.Trim(s, "-0.") == "" {
+		return strings.TrimPrefix(s, "-")
+	}
+	if v > 0 {
+		return "+" + s
+	}
+	return s
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v*100, 'f', 2, 64) + "%"
+}
+
+// formatCount rounds v and groups its digits by thousands: 12,345.
+func formatCount(v float64) string {
+	n := strconv.FormatInt(int64(math.Round(math.Abs(v))), 10)
+	var b strings.Builder
+	if v <= -0.5 {
+		b.WriteByte('-')
+	}
+	for i, r := range n {
+		if i > 0 && (len(n)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// arrow marks a trend in markdown, which has no colour.
+func arrow(t string) string {
+	switch t {
+	case trendGood:
+		return "▲ "
+	case trendBad:
+		return "▼ "
+	default:
+		return ""
+	}
+}
+
+// cell escapes a markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+var markdownTemplate = template.Must(template.New("weekly.md").Funcs(template.FuncMap{
+	"arrow": arrow,
+	"cell":  cell,
+}).Parse(`# {{.Title}}
+
+{{.Period}}.
+{{- if .Site}} Search Console: {{.Site}}.{{end}}
+{{- if .PropertyID}} GA4 property: {{.PropertyID}}.{{end}}
+
+## At a glance
+
+| Metric | This week | Last week | Change |
+| --- | ---: | ---: | ---: |
+{{range .Metrics}}| {{.Label}} | {{.Current}} | {{.Prior}} | {{arrow .Trend}}{{.Change}} |
+{{end}}
+{{- if .HasSearch}}
+## Top movers: queries
+{{if .Queries}}
+| Query | Clicks | Last week | Change |
+| --- | ---: | ---: | ---: |
+{{range .Queries}}| {{cell .Key}} | {{.Current}} | {{.Prior}} | {{arrow .Trend}}{{.Change}} |
+{{end}}{{else}}
+No query gained or lost clicks.
+{{end}}
+## Top movers: pages
+{{if .Pages}}
+| Page | Clicks | Last week | Change |
+| --- | ---: | ---: | ---: |
+{{range .Pages}}| {{cell .Key}} | {{.Current}} | {{.Prior}} | {{arrow .Trend}}{{.Change}} |
+{{end}}{{else}}
+No page gained or lost clicks.
+{{end}}{{end}}
+{{- with .Indexing}}
+## New indexing issues
+{{if .Note}}
+{{.Note}}
+{{else if $.Issues}}
+Since the inspection of {{$.IssuesSince}}:
+
+{{range $.Issues}}- {{.URL}}: {{.Detail}}
+{{end}}{{else}}
+None since the inspection of {{$.IssuesSince}}.
+{{end}}{{end}}
+{{- if .Markers}}
+## Markers
+
+{{range .Markers}}- {{.Span}}: {{.Label}}
+{{end}}{{end}}
+_Generated {{.GeneratedAt}} by ga4 summary weekly._
+`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("weekly.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; color: #1f2933; max-width: 860px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { font-size: 1.6rem; margin-bottom: .25rem; }
+h2 { font-size: 1.15rem; margin-top: 2rem; border-bottom: 1px solid #e4e7eb; padding-bottom: .25rem; }
+.period { color: #616e7c; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; font-size: .95rem; }
+th, td { padding: .4rem .6rem; border-bottom: 1px solid #e4e7eb; text-align: right; }
+th:first-child, td:first-child { text-align: left; word-break: break-all; }
+th { color: #616e7c; font-weight: 600; }
+.good { color: #0e7c3a; }
+.bad { color: #c62828; }
+.flat { color: #616e7c; }
+footer { color: #9aa5b1; font-size: .8rem; margin-top: 2.5rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="period">{{.Period}}.{{if .Site}} Search Console: {{.Site}}.{{end}}{{if .PropertyID}} GA4 property: {{.PropertyID}}.{{end}}</p>
+
+<h2>At a glance</h2>
+<table>
+<tr><th>Metric</th><th>This week</th><th>Last week</th><th>Change</th></tr>
+{{- range .Metrics}}
+<tr><td>{{.Label}}</td><td>{{.Current}}</td><td>{{.Prior}}</td><td class="{{.Trend}}">{{.Change}}</td></tr>
+{{- end}}
+</table>
+{{- if .HasSearch}}
+
+<h2>Top movers: queries</h2>
+{{- if .Queries}}
+<table>
+<tr><th>Query</th><th>Clicks</th><th>Last week</th><th>Change</th></tr>
+{{- range .Queries}}
+<tr><td>{{.Key}}</td><td>{{.Current}}</td><td>{{.Prior}}</td><td class="{{.Trend}}">{{.Change}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No query gained or lost clicks.</p>
+{{- end}}
+
+<h2>Top movers: pages</h2>
+{{- if .Pages}}
+<table>
+<tr><th>Page</th><th>Clicks</th><th>Last week</th><th>Change</th></tr>
+{{- range .Pages}}
+<tr><td>{{.Key}}</td><td>{{.Current}}</td><td>{{.Prior}}</td><td class="{{.Trend}}">{{.Change}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No page gained or lost clicks.</p>
+{{- end}}
+{{- end}}
+{{- with .Indexing}}
+
+<h2>New indexing issues</h2>
+{{- if .Note}}
+<p>{{.Note}}</p>
+{{- else if $.Issues}}
+<p>Since the inspection of {{$.IssuesSince}}:</p>
+<ul>
+{{- range $.Issues}}
+<li><span class="bad">{{.URL}}</span>: {{.Detail}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p class="good">None since the inspection of {{$.IssuesSince}}.</p>
+{{- end}}
+{{- end}}
+{{- if .Markers}}
+
+<h2>Markers</h2>
+<ul>
+{{- range .Markers}}
+<li>{{.Span}}: {{.Label}}</li>
+{{- end}}
+</ul>
+{{- end}}
+
+<footer>Generated {{.GeneratedAt}} by ga4 summary weekly.</footer>
+</body>
+</html>
+`))
+
+// Markdown writes s as a markdown document.
+func Markdown(w io.Writer, s Weekly) error {
+	return markdownTemplate.Execute(w, newView(s))
+}
+
+// HTML writes s as a standalone HTML page with inline styles, ready to
+// attach to an email or drop on a shared drive.
+func HTML(w io.Writer, s Weekly) error {
+	return htmlTemplate.Execute(w, newView(s))
+}
@@ -0,0 +1,171 @@
+// Package summary builds the weekly executive summary: GA4 sessions and key
+// events, Search Console clicks and impressions, the queries and pages that
+// moved most and the indexing issues that appeared, this week against the
+// week before. It renders as markdown or a standalone HTML page for readers
+// who never open a terminal.
+package summary
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/markers"
+)
+
+// Days is the length of each compared window.
+const Days = 7
+
+// Window is an inclusive date range, YYYY-MM-DD.
+type Window struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Change is one metric in the current and prior window.
+type Change struct {
+	Current float64 `json:"current"`
+	Prior   float64 `json:"prior"`
+}
+
+// Delta is current − prior.
+func (c Change) Delta() float64 {
+	return c.Current - c.Prior
+}
+
+// Percent is the relative change, 0.1 for +10%. ok is false when there is no
+// prior value to compare with.
+func (c Change) Percent() (p float64, ok bool) {
+	if c.Prior == 0 {
+		return 0, false
+	}
+	return c.Delta() / c.Prior, true
+}
+
+// Analytics is the GA4 part of the summary.
+type Analytics struct {
+	PropertyID string `json:"property_id"`
+	Sessions   Change `json:"sessions"`
+	KeyEvents  Change `json:"key_events"`
+}
+
+// Search is the Search Console part of the summary. A lower position is
+// better.
+type Search struct {
+	Clicks      Change `json:"clicks"`
+	Impressions Change `json:"impressions"`
+	CTR         Change `json:"ctr"`
+	Position    Change `json:"position"`
+}
+
+// Mover is a query or page whose clicks changed.
+type Mover struct {
+	Key         string `json:"key"`
+	Clicks      Change `json:"clicks"`
+	Impressions Change `json:"impressions"`
+}
+
+// Indexing lists the URLs `gsc monitor` found newly broken: the latest saved
+// run against the last one saved before the current window. Note explains
+// why there is nothing to compare when Latest or Baseline is zero.
+type Indexing struct {
+	Latest    time.Time                  `json:"latest,omitzero"`
+	Baseline  time.Time                  `json:"baseline,omitzero"`
+	NewIssues []gsc.InspectionTransition `json:"new_issues"`
+	Note      string                     `json:"note,omitempty"`
+}
+
+// Weekly is the whole summary. A section is nil when the config does not
+// set up its source (no GA4 property, no Search Console site).
+type Weekly struct {
+	Project     string     `json:"project"`
+	Site        string     `json:"site,omitempty"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Current     Window     `json:"current"`
+	Prior       Window     `json:"prior"`
+	Analytics   *Analytics `json:"analytics,omitempty"`
+	Search      *Search    `json:"search,omitempty"`
+	TopQueries  []Mover    `json:"top_queries,omitempty"`
+	TopPages    []Mover    `json:"top_pages,omitempty"`
+	Indexing    *Indexing  `json:"indexing,omitempty"`
+	// Markers are the site's markers (see `ga4 marker`) in either week.
+	Markers []markers.Marker `json:"markers,omitempty"`
+}
+
+// Windows returns the current week, the Days days ending yesterday (the
+// last day Search Console has settled data for), and the week before it.
+func Windows(now time.Time) (current, prior Window) {
+	const layout = "2006-01-02"
+	end := now.AddDate(0, 0, -1)
+	start := end.AddDate(0, 0, -(Days - 1))
+	priorEnd := start.AddDate(0, 0, -1)
+	priorStart := priorEnd.AddDate(0, 0, -(Days - 1))
+	return Window{start.Format(layout), end.Format(layout)},
+		Window{priorStart.Format(layout), priorEnd.Format(layout)}
+}
+
+// SearchTotals builds Search from the current and prior window totals. CTR
+// is recomputed from clicks and impressions.
+func SearchTotals(current, prior gsc.SearchAnalyticsAggregate) *Search {
+	ctr := func(a gsc.SearchAnalyticsAggregate) float64 {
+		if a.TotalImpressions == 0 {
+			return 0
+		}
+		return float64(a.TotalClicks) / float64(a.TotalImpressions)
+	}
+	return &Search{
+		Clicks:      Change{float64(current.TotalClicks), float64(prior.TotalClicks)},
+		Impressions: Change{float64(current.TotalImpressions), float64(prior.TotalImpressions)},
+		CTR:         Change{ctr(current), ctr(prior)},
+		Position:    Change{current.AveragePosition, prior.AveragePosition},
+	}
+}
+
+// TopMovers joins single-dimension rows of the two windows and returns the
+// top keys by the size of their clicks change, gains and losses alike. A key
+// in only one window counts as 0 in the other. Ties go to the key with more
+// current clicks, then alphabetically.
+func TopMovers(current, prior []gsc.SearchAnalyticsRow, top int) []Mover {
+	byKey := make(map[string]*Mover)
+	add := func(rows []gsc.SearchAnalyticsRow, set func(m *Mover, r gsc.SearchAnalyticsRow)) {
+		for _, r := range rows {
+			if len(r.Keys) == 0 {
+				continue
+			}
+			m, ok := byKey[r.Keys[0]]
+			if !ok {
+				m = &Mover{Key: r.Keys[0]}
+				byKey[r.Keys[0]] = m
+			}
+			set(m, r)
+		}
+	}
+	add(current, func(m *Mover, r gsc.SearchAnalyticsRow) {
+		m.Clicks.Current, m.Impressions.Current = float64(r.Clicks), float64(r.Impressions)
+	})
+	add(prior, func(m *Mover, r gsc.SearchAnalyticsRow) {
+		m.Clicks.Prior, m.Impressions.Prior = float64(r.Clicks), float64(r.Impressions)
+	})
+
+	movers := make([]Mover, 0, len(byKey))
+	for _, m := range byKey {
+		if m.Clicks.Delta() != 0 {
+			movers = append(movers, *m)
+		}
+	}
+	sort.Slice(movers, func(i, j int) bool {
+		di, dj := math.Abs(movers[i].Clicks.Delta()), math.Abs(movers[j].Clicks.Delta())
+		if di != dj {
+			return di > dj
+		}
+		if movers[i].Clicks.Current != movers[j].Clicks.Current {
+			return movers[i].Clicks.Current > movers[j].Clicks.Current
+		}
+		return movers[i].Key < movers[j].Key
+	})
+	if top > 0 && len(movers) > top {
+		movers = movers[:top]
+	}
+	return movers
+}
@@ -0,0 +1,110 @@
+package summary
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/gsc"
+	"github.com/garbarok/ga4-manager/internal/markers"
+)
+
+func TestWindows(t *testing.T) {
+	current, prior := Windows(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	assert.Equal(t, Window{"2026-10-09", "2026-10-15"}, current)
+	assert.Equal(t, Window{"2026-10-02", "2026-10-08"}, prior)
+}
+
+func TestChange_Percent(t *testing.T) {
+	p, ok := Change{Current: 110, Prior: 100}.Percent()
+	require.True(t, ok)
+	assert.InDelta(t, 0.1, p, 1e-9)
+
+	_, ok = Change{Current: 5}.Percent()
+	assert.False(t, ok)
+}
+
+func TestSearchTotals_RecomputesCTR(t *testing.T) {
+	s := SearchTotals(
+		gsc.SearchAnalyticsAggregate{TotalClicks: 50, TotalImpressions: 1000, AveragePosition: 8},
+		gsc.SearchAnalyticsAggregate{},
+	)
+	assert.Equal(t, Change{Current: 0.05}, s.CTR)
+	assert.Equal(t, Change{Current: 8}, s.Position)
+}
+
+func TestTopMovers(t *testing.T) {
+	current := []gsc.SearchAnalyticsRow{
+		{Keys: []string{"steady"}, Clicks: 10},
+		{Keys: []string{"up"}, Clicks: 40},
+		{Keys: []string{"new"}, Clicks: 12},
+	}
+	prior := []gsc.SearchAnalyticsRow{
+		{Keys: []string{"steady"}, Clicks: 10},
+		{Keys: []string{"up"}, Clicks: 15},
+		{Keys: []string{"gone"}, Clicks: 30},
+	}
+
+	movers := TopMovers(current, prior, 2)
+	require.Len(t, movers, 2)
+	assert.Equal(t, "gone", movers[0].Key)
+	assert.Equal(t, Change{Prior: 30}, movers[0].Clicks)
+	assert.Equal(t, "up", movers[1].Key)
+
+	all := TopMovers(current, prior, 0)
+	assert.Len(t, all, 3, "unchanged keys are not movers")
+}
+
+func TestMarkdown(t *testing.T) {
+	s := Weekly{
+		Project:     "Example",
+		Site:        "sc-domain:example.com",
+		GeneratedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		Current:     Window{"2026-10-09", "2026-10-15"},
+		Prior:       Window{"2026-10-02", "2026-10-08"},
+		Analytics:   &Analytics{PropertyID: "123456789", Sessions: Change{Current: 12500, Prior: 10000}},
+		Search: &Search{
+			Clicks:   Change{Current: 800, Prior: 1000},
+			Position: Change{Current: 7.5, Prior: 8.25},
+		},
+		TopQueries: []Mover{{Key: "a | b", Clicks: Change{Current: 5, Prior: 25}}},
+		Indexing: &Indexing{
+			Baseline:  time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC),
+			NewIssues: []gsc.InspectionTransition{{URL: "https://example.com/x", Deindexed: true}},
+		},
+		Markers: []markers.Marker{{ID: "3f9a1c02", Date: "2026-10-06", End: "2026-10-10", Label: "core update"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Markdown(&buf, s))
+	out := buf.String()
+
+	assert.Contains(t, out, "# Weekly summary: Example")
+	assert.Contains(t, out, "| Sessions | 12,500 | 10,000 | ▲ +25.0% |")
+	assert.Contains(t, out, "| Search clicks | 800 | 1,000 | ▼ -20.0% |")
+	assert.Contains(t, out, "| Average position | 7.5 | 8.2 | ▲ -0.8 |", "a lower position is good news")
+	assert.Contains(t, out, `| a \| b | 5 | 25 | ▼ -20 (-80.0%) |`)
+	assert.Contains(t, out, "No page gained or lost clicks.")
+	assert.Contains(t, out, "- https://example.com/x: dropped out of the index")
+	assert.Contains(t, out, "## Markers\n\n- 2026-10-06 to 2026-10-10: core update\n")
+}
+
+func TestHTML_EscapesAndOmitsMissingSections(t *testing.T) {
+	s := Weekly{
+		Current:   Window{"2026-10-09", "2026-10-15"},
+		Prior:     Window{"2026-10-02", "2026-10-08"},
+		Analytics: &Analytics{PropertyID: "123456789", KeyEvents: Change{Current: 3}},
+		Project:   "<Example>",
+	}
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, s))
+	out := buf.String()
+
+	assert.Contains(t, out, "<title>Weekly summary: &lt;Example&gt;</title>")
+	assert.Contains(t, out, `<td>Key events</td><td>3</td><td>0</td><td class="good">new</td>`)
+	assert.NotContains(t, out, "Top movers")
+	assert.NotContains(t, out, "indexing")
+	assert.NotContains(t, out, "Markers")
+}
@@ -0,0 +1,387 @@
+// Package theme controls how human-facing CLI output looks: the colour palette
+// and whether emoji/glyph status markers are printed as-is or replaced with
+// text labels. Three themes exist:
+//
+//	default        the original palette and emoji markers
+//	high-contrast  colourblind-safe palette (no red/green pairing), bold, text labels
+//	plain-ascii    no colour, text labels, ASCII punctuation and no pictographs,
+//	               for logs and terminals without Unicode support
+//
+// Commands print through this package (theme.Printf, theme.Color, theme.Red…)
+// instead of fmt/color directly so one switch covers every command.
+// Machine-readable output (JSON, CSV, diagnostic envelopes) must not go
+// through it.
+//
+// Only decorations are themed: the format strings of Printf and the colour
+// helpers, and arguments that are nothing but a marker (a coloured "✓").
+// Other arguments and everything written through NewWriter are report data
+// (queries, URLs, page titles) and are printed as they are.
+package theme
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// Theme names.
+const (
+	Default      = "default"
+	HighContrast = "high-contrast"
+	PlainASCII   = "plain-ascii"
+)
+
+// EnvVar selects the theme when --theme is not given.
+const EnvVar = "GA4_THEME"
+
+// Names lists the accepted theme names.
+func Names() []string {
+	return []string{Default, HighContrast, PlainASCII}
+}
+
+// labels maps status glyphs to the text label printed by the non-default
+// themes. Longer sequences (with variation selectors) come first so the
+// replacer matches them before their bare prefix.
+var labels = []string{
+	"✅", "[OK]",
+	"✔️", "[OK]",
+	"✔", "[OK]",
+	"✓", "[OK]",
+	"❌", "[FAIL]",
+	"✘", "[FAIL]",
+	"✗", "[FAIL]",
+	"⚠️", "[WARN]",
+	"⚠", "[WARN]",
+	"ℹ️", "[INFO]",
+	"ℹ", "[INFO]",
+	"⏭️", "[SKIP]",
+	"○", "[SKIP]",
+	"●", "*",
+	"⏳", "[..]",
+	"🔄", "[..]",
+}
+
+// asciiOnly maps the remaining typographic characters to ASCII for plain-ascii.
+var asciiOnly = []string{
+	"═", "=",
+	"─", "-",
+	"━", "-",
+	"│", "|",
+	"┃", "|",
+	"└", "`",
+	"├", "|",
+	"→", "->",
+	"←", "<-",
+	"↑", "^",
+	"↓", "v",
+	"•", "*",
+	"…", "...",
+	"—", "-",
+	"–", "-",
+	"“", `"`,
+	"”", `"`,
+	"‘", "'",
+	"’", "'",
+	"≥", ">=",
+	"≤", "<=",
+	"×", "x",
+}
+
+// palette remaps the semantic colours used across the CLI. Only attributes
+// present in the map are changed; everything else passes through.
+type palette map[color.Attribute][]color.Attribute
+
+// colourblindPalette avoids the red/green pairing (Okabe-Ito inspired: blue
+// for success, magenta for failure, yellow for warnings) and boosts contrast.
+var colourblindPalette = palette{
+	color.FgGreen:   {color.FgHiBlue, color.Bold},
+	color.FgRed:     {color.FgHiMagenta, color.Bold},
+	color.FgYellow:  {color.FgHiYellow, color.Bold},
+	color.FgCyan:    {color.FgHiCyan, color.Bold},
+	color.FgBlue:    {color.FgHiWhite, color.Bold},
+	color.FgHiBlack: {color.FgWhite},
+}
+
+var (
+	mu       sync.RWMutex
+	current  = Default
+	replacer *strings.Replacer // nil for the default theme
+	// noColor is color.NoColor as it was before plain-ascii forced it on,
+	// restored when another theme is set.
+	noColor bool
+	quiet   atomic.Bool
+)
+
+// Current returns the active theme name.
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Resolve picks the theme from the flag value, then $GA4_THEME, then the
+// default. NO_COLOR (https://no-color.org) is honoured by the colour library
+// regardless of theme.
+func Resolve(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return env
+	}
+	return Default
+}
+
+// Set activates a theme for the rest of the process.
+func Set(name string) error {
+	var r *strings.Replacer
+	switch name {
+	case Default:
+	case HighContrast:
+		r = strings.NewReplacer(labels...)
+	case PlainASCII:
+		r = strings.NewReplacer(append(append([]string{}, labels...), asciiOnly...)...)
+	default:
+		return fmt.Errorf("unknown theme %q (valid: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	mu.Lock()
+	if current != PlainASCII {
+		noColor = color.NoColor
+	}
+	color.NoColor = noColor || name == PlainASCII
+	current = name
+	replacer = r
+	mu.Unlock()
+
+	// fatih/color's package helpers (color.Red(…), c.Println) write here.
+	// Only code prints through them, so their text is themed whole.
+	color.Output = decoratedWriter{w: os.Stdout}
+	color.Error = decoratedWriter{w: os.Stderr}
+	return nil
+}
+
+// Text applies the active theme's marker replacement to s. Under the default
+// theme it returns s unchanged.
+func Text(s string) string {
+	mu.RLock()
+	r, name := replacer, current
+	mu.RUnlock()
+	if r == nil {
+		return s
+	}
+	s = r.Replace(s)
+	if name == PlainASCII {
+		s = stripSymbols(s)
+	}
+	return s
+}
+
+// stripSymbols drops decorative emoji and pictographs left after label
+// replacement, along with the single space that usually follows them. Letters
+// and digits are kept even when non-ASCII so report data (queries, page
+// titles) is never altered.
+func stripSymbols(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	skipSpace := false
+	for _, r := range s {
+		if isDecoration(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isDecoration(r rune) bool {
+	switch {
+	case r <= unicode.MaxASCII:
+		return false
+	case r == '\uFE0F', r == '\u200D': // variation selector, zero-width joiner
+		return true
+	}
+	return unicode.Is(unicode.So, r)
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// isMarker reports whether s, without its colour codes, is only decoration:
+// symbols, punctuation and spaces, with at least one non-ASCII symbol.
+func isMarker(s string) bool {
+	marker := false
+	for _, r := range ansiEscape.ReplaceAllString(s, "") {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return false
+		case r > unicode.MaxASCII:
+			marker = true
+		}
+	}
+	return marker
+}
+
+// markers returns a with Text applied to the arguments that are markers,
+// leaving report data alone.
+func markers(a []any) []any {
+	out := make([]any, len(a))
+	for i, v := range a {
+		if s, ok := v.(string); ok && isMarker(s) {
+			v = Text(s)
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// sprintf formats like fmt.Sprintf, theming the format and marker arguments.
+func sprintf(format string, a ...any) string {
+	return fmt.Sprintf(Text(format), markers(a)...)
+}
+
+// writer writes report data as it is, to io.Discard under SetQuiet.
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter wraps w for report data, such as the tables of output.Render.
+// Text written to it is not themed, so data that happens to contain a marker
+// or a typographic character is kept; theme the decorations of a table where
+// its cells are made, with the String helpers or Text. Like the print
+// helpers, it drops stdout under SetQuiet.
+func NewWriter(w io.Writer) io.Writer {
+	return writer{w: w}
+}
+
+func (t writer) Write(p []byte) (int, error) {
+	return target(t.w).Write(p)
+}
+
+// decoratedWriter applies Text to everything written through it.
+type decoratedWriter struct {
+	w io.Writer
+}
+
+func (t decoratedWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(target(t.w), Text(string(p))); err != nil {
+		return 0, err
+	}
+	// Report the caller's byte count: the themed text may differ in length.
+	return len(p), nil
+}
+
+// Color returns a colour for the given attributes, remapped through the active
+// palette. Use it wherever color.New was used for human-facing output.
+func Color(attrs ...color.Attribute) *color.Color {
+	if Current() == HighContrast {
+		mapped := make([]color.Attribute, 0, len(attrs)+1)
+		for _, a := range attrs {
+			if repl, ok := colourblindPalette[a]; ok {
+				mapped = append(mapped, repl...)
+				continue
+			}
+			mapped = append(mapped, a)
+		}
+		attrs = mapped
+	}
+	return color.New(attrs...)
+}
+
+// Printf, Println, Print, Fprintf and Fprintln mirror fmt but apply the theme.
+// Printf and Fprintf theme the format and the marker arguments. Print,
+// Println and Fprintln theme all of their text, so pass report data to them
+// through a format, or already themed with the String helpers.
+
+func Printf(format string, a ...any) {
+	_, _ = io.WriteString(target(os.Stdout), sprintf(format, a...))
+}
+
+func Println(a ...any) {
+	_, _ = io.WriteString(target(os.Stdout), Text(fmt.Sprintln(a...)))
+}
+
+func Print(a ...any) {
+	_, _ = io.WriteString(target(os.Stdout), Text(fmt.Sprint(a...)))
+}
+
+func Fprintf(w io.Writer, format string, a ...any) {
+	_, _ = io.WriteString(target(w), sprintf(format, a...))
+}
+
+func Fprintln(w io.Writer, a ...any) {
+	_, _ = io.WriteString(target(w), Text(fmt.Sprintln(a...)))
+}
+
+// SetQuiet drops everything written through the theme to stdout: the print
+// helpers, writers from NewWriter(os.Stdout) and fatih/color's package
+// helpers. Other writers, stderr included, still get their output.
+func SetQuiet(q bool) {
+	quiet.Store(q)
+}
+
+// Quiet reports whether SetQuiet is on.
+func Quiet() bool {
+	return quiet.Load()
+}
+
+// target is w, or io.Discard when w is stdout and quiet mode is on.
+func target(w io.Writer) io.Writer {
+	if quiet.Load() && w == io.Writer(os.Stdout) {
+		return io.Discard
+	}
+	return w
+}
+
+// Red, Green, Yellow, Cyan, Blue, White and HiBlack mirror the fatih/color
+// helpers of the same name (newline appended when missing) using the themed
+// palette. Like Printf, they theme the format and the marker arguments.
+
+func Red(format string, a ...any)     { printLine(color.FgRed, format, a...) }
+func Green(format string, a ...any)   { printLine(color.FgGreen, format, a...) }
+func Yellow(format string, a ...any)  { printLine(color.FgYellow, format, a...) }
+func Cyan(format string, a ...any)    { printLine(color.FgCyan, format, a...) }
+func Blue(format string, a ...any)    { printLine(color.FgBlue, format, a...) }
+func White(format string, a ...any)   { printLine(color.FgWhite, format, a...) }
+func HiBlack(format string, a ...any) { printLine(color.FgHiBlack, format, a...) }
+
+func printLine(attr color.Attribute, format string, a ...any) {
+	if !strings.HasSuffix(format, "\n") {
+		format += "\n"
+	}
+	c := Color(attr)
+	if len(a) == 0 {
+		_, _ = io.WriteString(target(os.Stdout), c.Sprint(Text(format)))
+		return
+	}
+	_, _ = io.WriteString(target(os.Stdout), c.Sprint(sprintf(format, a...)))
+}
+
+// RedString, GreenString, YellowString, CyanString, BlueString and
+// HiBlackString mirror the fatih/color Sprintf helpers with the themed
+// palette, theming the format and the marker arguments.
+
+func RedString(format string, a ...any) string    { return sprintColor(color.FgRed, format, a...) }
+func GreenString(format string, a ...any) string  { return sprintColor(color.FgGreen, format, a...) }
+func YellowString(format string, a ...any) string { return sprintColor(color.FgYellow, format, a...) }
+func CyanString(format string, a ...any) string   { return sprintColor(color.FgCyan, format, a...) }
+func BlueString(format string, a ...any) string   { return sprintColor(color.FgBlue, format, a...) }
+func HiBlackString(format string, a ...any) string {
+	return sprintColor(color.FgHiBlack, format, a...)
+}
+
+func sprintColor(attr color.Attribute, format string, a ...any) string {
+	return Color(attr).Sprint(sprintf(format, a...))
+}
@@ -0,0 +1,108 @@
+package theme
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTheme(t *testing.T, name string) {
+	t.Helper()
+	noColor := color.NoColor
+	require.NoError(t, Set(name))
+	t.Cleanup(func() {
+		_ = Set(Default)
+		color.NoColor = noColor
+	})
+}
+
+func TestText_Default(t *testing.T) {
+	useTheme(t, Default)
+	assert.Equal(t, "✓ Created 🎯 purchase", Text("✓ Created 🎯 purchase"))
+}
+
+func TestText_HighContrast(t *testing.T) {
+	useTheme(t, HighContrast)
+	assert.Equal(t, "[OK] Created", Text("✓ Created"))
+	assert.Equal(t, "[WARN]  Quota at 80%", Text("⚠️  Quota at 80%"))
+	assert.Equal(t, "[FAIL] Failed", Text("❌ Failed"))
+	// Box drawing is kept: the terminal supports Unicode, only colour and
+	// markers change.
+	assert.Equal(t, "═══", Text("═══"))
+}
+
+func TestText_PlainASCII(t *testing.T) {
+	useTheme(t, PlainASCII)
+	assert.True(t, color.NoColor)
+	assert.Equal(t, "[OK] Created purchase", Text("✓ Created 🎯 purchase"))
+	assert.Equal(t, "=== Setup -> done...", Text("═══ Setup → done…"))
+	assert.Equal(t, "Summary", Text("📊 Summary"))
+	// Report data keeps its letters.
+	assert.Equal(t, "cómo instalar", Text("cómo instalar"))
+}
+
+func TestSet_Unknown(t *testing.T) {
+	err := Set("neon")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plain-ascii")
+	assert.Equal(t, Default, Current())
+}
+
+func TestColor_HighContrastRemapsRedGreen(t *testing.T) {
+	useTheme(t, HighContrast)
+	assert.True(t, Color(color.FgGreen).Equals(color.New(color.FgHiBlue, color.Bold)))
+	assert.True(t, Color(color.FgRed, color.Bold).Equals(color.New(color.FgHiMagenta, color.Bold, color.Bold)))
+}
+
+func TestSet_DefaultRestoresColour(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+	color.NoColor = false
+
+	require.NoError(t, Set(PlainASCII))
+	assert.True(t, color.NoColor)
+	require.NoError(t, Set(Default))
+	assert.False(t, color.NoColor, "plain-ascii turned colour off, not the terminal")
+}
+
+func TestWriter_KeepsData(t *testing.T) {
+	useTheme(t, PlainASCII)
+	var buf bytes.Buffer
+	n, err := NewWriter(&buf).Write([]byte("Quotes “smart” — ✓ done\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("Quotes “smart” — ✓ done\n"), n)
+	assert.Equal(t, "Quotes “smart” — ✓ done\n", buf.String())
+}
+
+func TestFprintf_ThemesDecorationsOnly(t *testing.T) {
+	useTheme(t, PlainASCII)
+	var buf bytes.Buffer
+	Fprintf(&buf, "%s %s → %s\n", "✓", "Café — menu", "…")
+	assert.Equal(t, "[OK] Café — menu -> ...\n", buf.String())
+	assert.Equal(t, "[FAIL] a → b", RedString("✗ %s", "a → b"))
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(EnvVar, PlainASCII)
+	assert.Equal(t, HighContrast, Resolve(HighContrast))
+	assert.Equal(t, PlainASCII, Resolve(""))
+	t.Setenv(EnvVar, "")
+	assert.Equal(t, Default, Resolve(""))
+}
+
+func TestSetQuiet_DropsStdoutOnly(t *testing.T) {
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+
+	assert.Equal(t, io.Discard, target(os.Stdout))
+	assert.Equal(t, io.Writer(os.Stderr), target(os.Stderr))
+
+	var buf bytes.Buffer
+	Fprintln(&buf, "kept")
+	assert.Equal(t, "kept\n", buf.String())
+}
@@ -0,0 +1,64 @@
+// Package timeout enforces per-operation API deadlines inside a run-wide
+// deadline and reports which resource ran out of time. Both the GA4 and GSC
+// clients route their SDK calls through Do so a hung request can never stall a
+// scheduled run past --timeout.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Error reports a resource whose API call did not finish in time. It unwraps to
+// context.DeadlineExceeded so callers can test with errors.Is.
+type Error struct {
+	Operation string        // e.g. "create conversion", "inspect url"
+	Resource  string        // e.g. event name, parameter name, URL
+	Timeout   time.Duration // the budget that was exceeded
+	// RunDeadline is true when the overall run deadline (--timeout) expired,
+	// false when only this operation's own timeout did.
+	RunDeadline bool
+}
+
+func (e *Error) Error() string {
+	target := e.Operation
+	if e.Resource != "" {
+		target = fmt.Sprintf("%s %q", e.Operation, e.Resource)
+	}
+	if e.RunDeadline {
+		return fmt.Sprintf("run deadline exceeded during %s", target)
+	}
+	return fmt.Sprintf("%s timed out after %s", target, e.Timeout)
+}
+
+func (e *Error) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Do runs fn with a context bounded by d (ignored when d <= 0) and by parent.
+// When the call fails because either deadline expired, the SDK error is
+// replaced by an *Error naming operation and resource; every other error is
+// returned unchanged.
+func Do(parent context.Context, operation, resource string, d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if d > 0 {
+		ctx, cancel = context.WithTimeout(parent, d)
+	}
+	defer cancel()
+
+	err := fn(ctx)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return &Error{
+		Operation:   operation,
+		Resource:    resource,
+		Timeout:     d,
+		RunDeadline: errors.Is(parent.Err(), context.DeadlineExceeded),
+	}
+}
@@ -0,0 +1,316 @@
+// Package tracking generates the site side of a GA4 config: the gtag.js
+// snippet for the measurement ID and typed helpers for every conversion
+// event and custom dimension, so instrumentation code is regenerated from
+// the same YAML ga4 setup applies instead of drifting from it.
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+// Frameworks the snippet is generated for.
+const (
+	FrameworkNextJS = "nextjs"
+	FrameworkAstro  = "astro"
+	FrameworkPlain  = "plain"
+)
+
+// Languages of the helpers.
+const (
+	LangTS = "ts"
+	LangJS = "js"
+)
+
+// PlaceholderMeasurementID is written when the config has no
+// analytics.measurement_id.
+const PlaceholderMeasurementID = "G-XXXXXXXXXX"
+
+// Frameworks returns the supported frameworks.
+func Frameworks() []string {
+	return []string{FrameworkNextJS, FrameworkAstro, FrameworkPlain}
+}
+
+// DefaultLang is the helpers' language for framework: TypeScript, except
+// for a plain site, which loads the file as a module without a build step.
+func DefaultLang(framework string) string {
+	if framework == FrameworkPlain {
+		return LangJS
+	}
+	return LangTS
+}
+
+// File is a generated file, at a path relative to the project root.
+type File struct {
+	Path    string
+	Content string
+}
+
+// param is a custom dimension or metric parameter.
+type param struct {
+	Name    string
+	Type    string // string or number
+	Comment string
+}
+
+// conversion is a helper function sending a conversion event.
+type conversion struct {
+	Event    string
+	Func     string
+	Comment  string
+	Value    bool   // takes value and currency
+	Currency string // default currency, if any
+}
+
+// model is what the templates render.
+type model struct {
+	Source         string
+	MeasurementID  string
+	TS             bool
+	EventParams    []param
+	UserProperties []param
+	Conversions    []conversion
+	HelpersImport  string
+}
+
+// Generate returns the files for framework in lang. source names the config
+// in the generated header.
+func Generate(cfg *config.ProjectConfig, source, framework, lang string) ([]File, error) {
+	if !slices.Contains(Frameworks(), framework) {
+		return nil, fmt.Errorf("unknown framework %q (want %s)", framework, strings.Join(Frameworks(), ", "))
+	}
+	if lang != LangTS && lang != LangJS {
+		return nil, fmt.Errorf("unknown language %q (want ts or js)", lang)
+	}
+	m := newModel(cfg, source, lang)
+
+	var helpers, snippet string
+	switch framework {
+	case FrameworkNextJS:
+		helpers = "lib/analytics." + lang
+		snippet = "components/GoogleAnalytics." + lang + "x"
+		m.HelpersImport = "../lib/analytics"
+	case FrameworkAstro:
+		helpers = "src/lib/analytics." + lang
+		snippet = "src/components/GoogleAnalytics.astro"
+	case FrameworkPlain:
+		helpers = "analytics." + lang
+		snippet = "gtag.html"
+	}
+
+	files := make([]File, 0, 2)
+	for _, f := range []struct{ path, tmpl string }{{snippet, framework}, {helpers, "helpers"}} {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, f.tmpl, m); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", f.path, err)
+		}
+		files = append(files, File{Path: f.path, Content: buf.String()})
+	}
+	return files, nil
+}
+
+func newModel(cfg *config.ProjectConfig, source, lang string) model {
+	m := model{Source: source, MeasurementID: cfg.GetMeasurementID(), TS: lang == LangTS}
+	if m.MeasurementID == "" {
+		m.MeasurementID = PlaceholderMeasurementID
+	}
+	for _, d := range cfg.Dimensions {
+		p := param{Name: d.ParameterName, Type: "string", Comment: describe(d.DisplayName, d.Description)}
+		if strings.EqualFold(d.Scope, "USER") {
+			m.UserProperties = append(m.UserProperties, p)
+		} else {
+			m.EventParams = append(m.EventParams, p)
+		}
+	}
+	for _, mt := range cfg.Metrics {
+		m.EventParams = append(m.EventParams, param{Name: mt.ParameterName, Type: "number", Comment: describe(mt.DisplayName, mt.Description)})
+	}
+	for _, c := range cfg.Conversions {
+		conv := conversion{Event: c.Name, Func: "track" + pascalCase(c.Name), Comment: describe(c.Name, c.Description)}
+		if cfg.IsValueBearingEvent(c.Name) || c.DefaultValue != nil {
+			conv.Value = true
+			conv.Currency = cfg.ConversionCurrency(c)
+		}
+		m.Conversions = append(m.Conversions, conv)
+	}
+	return m
+}
+
+// describe is the doc comment of a helper or field: a name and the
+// config's description, on one line and unable to close the comment.
+func describe(name, description string) string {
+	if description != "" {
+		name += ": " + strings.Join(strings.Fields(description), " ")
+	}
+	return strings.ReplaceAll(name, "*/", "* /")
+}
+
+// pascalCase turns an event name such as generate_lead into GenerateLead.
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+var templates = template.Must(template.New("").Parse(helpersTemplate + nextjsTemplate + astroTemplate + plainTemplate))
+
+const gtagInit = `window.dataLayer = window.dataLayer || [];
+function gtag(){dataLayer.push(arguments);}
+gtag('js', new Date());
+gtag('config', '{{.MeasurementID}}');`
+
+const helpersTemplate = `{{define "helpers"}}// Generated by ga4 generate tracking from {{.Source}}.
+// Do not edit: change the config and regenerate.
+{{- if .TS}}
+
+declare global {
+  interface Window {
+    dataLayer: unknown[];
+  }
+}
+{{- end}}
+
+export const GA_MEASUREMENT_ID = '{{.MeasurementID}}';
+{{if .TS}}
+/** Custom dimension and metric parameters registered on the property. */
+export interface EventParams {
+{{- range .EventParams}}
+  /** {{.Comment}} */
+  {{.Name}}?: {{.Type}};
+{{- end}}
+}
+
+/** Revenue or lead value of a conversion. */
+export interface ValueParams {
+  value?: number;
+  currency?: string;
+}
+{{- if .UserProperties}}
+
+/** User-scoped custom dimensions. */
+export interface UserProperties {
+{{- range .UserProperties}}
+  /** {{.Comment}} */
+  {{.Name}}?: {{.Type}};
+{{- end}}
+}
+{{- end}}
+
+function gtag(..._args: unknown[]): void {
+  if (typeof window === 'undefined') return;
+  window.dataLayer = window.dataLayer || [];
+  // gtag.js reads the arguments object, not an array.
+  // eslint-disable-next-line prefer-rest-params
+  window.dataLayer.push(arguments);
+}
+{{- else}}
+/**
+ * Custom dimension and metric parameters registered on the property.
+ * @typedef {Object} EventParams
+{{- range .EventParams}}
+ * @property {{"{"}}{{.Type}}{{"}"}} [{{.Name}}] {{.Comment}}
+{{- end}}
+ */
+
+/**
+ * Revenue or lead value of a conversion.
+ * @typedef {Object} ValueParams
+ * @property {number} [value]
+ * @property {string} [currency]
+ */
+{{- if .UserProperties}}
+
+/**
+ * User-scoped custom dimensions.
+ * @typedef {Object} UserProperties
+{{- range .UserProperties}}
+ * @property {{"{"}}{{.Type}}{{"}"}} [{{.Name}}] {{.Comment}}
+{{- end}}
+ */
+{{- end}}
+
+function gtag() {
+  if (typeof window === 'undefined') return;
+  window.dataLayer = window.dataLayer || [];
+  // gtag.js reads the arguments object, not an array.
+  window.dataLayer.push(arguments);
+}
+{{- end}}
+{{range .Conversions}}
+{{- if $.TS}}
+/** {{.Comment}} */
+export function {{.Func}}(params: EventParams{{if .Value}} & ValueParams{{end}} = {}): void {
+{{- else}}
+/**
+ * {{.Comment}}
+ * @param {EventParams{{if .Value}} & ValueParams{{end}}} [params]
+ */
+export function {{.Func}}(params = {}) {
+{{- end}}
+{{- if .Currency}}
+  gtag('event', '{{.Event}}', { currency: '{{.Currency}}', ...params });
+{{- else}}
+  gtag('event', '{{.Event}}', params);
+{{- end}}
+}
+{{end}}
+{{- if .UserProperties}}
+{{- if .TS}}
+/** Sets user-scoped custom dimensions for the events that follow. */
+export function setUserProperties(properties: UserProperties): void {
+{{- else}}
+/**
+ * Sets user-scoped custom dimensions for the events that follow.
+ * @param {UserProperties} properties
+ */
+export function setUserProperties(properties) {
+{{- end}}
+  gtag('set', 'user_properties', properties);
+}
+{{end}}
+{{- end}}`
+
+const nextjsTemplate = `{{define "nextjs"}}// Generated by ga4 generate tracking from {{.Source}}.
+// Render <GoogleAnalytics /> once, in the root layout.
+import Script from 'next/script';
+import { GA_MEASUREMENT_ID } from '{{.HelpersImport}}';
+
+export function GoogleAnalytics() {
+  return (
+    <>
+      <Script src={` + "`https://www.googletagmanager.com/gtag/js?id=${GA_MEASUREMENT_ID}`" + `} strategy="afterInteractive" />
+      <Script id="ga4-init" strategy="afterInteractive">
+        {` + "`" + gtagInit + "`" + `}
+      </Script>
+    </>
+  );
+}
+{{end}}`
+
+const astroTemplate = `{{define "astro"}}---
+// Generated by ga4 generate tracking from {{.Source}}.
+// Include <GoogleAnalytics /> in the <head> of the base layout.
+---
+<script is:inline async src="https://www.googletagmanager.com/gtag/js?id={{.MeasurementID}}"></script>
+<script is:inline>
+` + gtagInit + `
+</script>
+{{end}}`
+
+const plainTemplate = `{{define "plain"}}<!-- Generated by ga4 generate tracking from {{.Source}}. -->
+<!-- Paste right after <head> on every page. -->
+<script async src="https://www.googletagmanager.com/gtag/js?id={{.MeasurementID}}"></script>
+<script>
+` + gtagInit + `
+</script>
+{{end}}`
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+)
+
+func testConfig() *config.ProjectConfig {
+	forty := 40.0
+	return &config.ProjectConfig{
+		Analytics: &config.AnalyticsConfig{MeasurementID: "G-TEST123", Currency: "EUR"},
+		Conversions: []config.ConversionConfig{
+			{Name: "purchase"},
+			{Name: "newsletter_signup", Description: "Footer form */ submitted"},
+			{Name: "book_demo", DefaultValue: &forty},
+		},
+		Dimensions: []config.DimensionConfig{
+			{ParameterName: "plan_type", DisplayName: "Plan Type", Scope: "EVENT"},
+			{ParameterName: "customer_tier", DisplayName: "Customer Tier", Scope: "USER"},
+		},
+		Metrics: []config.MetricConfig{{ParameterName: "items_count", DisplayName: "Items", Scope: "EVENT"}},
+	}
+}
+
+func TestGenerate_NextJSTypeScript(t *testing.T) {
+	files, err := Generate(testConfig(), "configs/site.yaml", FrameworkNextJS, LangTS)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, "components/GoogleAnalytics.tsx", files[0].Path)
+	assert.Contains(t, files[0].Content, "import { GA_MEASUREMENT_ID } from '../lib/analytics';")
+	assert.Contains(t, files[0].Content, "gtag('config', 'G-TEST123');")
+
+	assert.Equal(t, "lib/analytics.ts", files[1].Path)
+	helpers := files[1].Content
+	assert.Contains(t, helpers, "from configs/site.yaml")
+	assert.Contains(t, helpers, "export const GA_MEASUREMENT_ID = 'G-TEST123';")
+	assert.Contains(t, helpers, "  plan_type?: string;\n")
+	assert.Contains(t, helpers, "  items_count?: number;\n")
+	assert.Contains(t, helpers, "export interface UserProperties {\n  /** Customer Tier */\n  customer_tier?: string;\n}")
+	assert.Contains(t, helpers, "export function trackPurchase(params: EventParams & ValueParams = {}): void {\n  gtag('event', 'purchase', { currency: 'EUR', ...params });\n}")
+	assert.Contains(t, helpers, "export function trackNewsletterSignup(params: EventParams = {}): void {\n  gtag('event', 'newsletter_signup', params);\n}")
+	assert.Contains(t, helpers, "/** newsletter_signup: Footer form * / submitted */")
+	assert.Contains(t, helpers, "export function trackBookDemo(params: EventParams & ValueParams = {})")
+	assert.Contains(t, helpers, "gtag('set', 'user_properties', properties);")
+}
+
+func TestGenerate_PlainJavaScript(t *testing.T) {
+	cfg := testConfig()
+	cfg.Analytics.MeasurementID = ""
+	cfg.Dimensions = cfg.Dimensions[:1]
+
+	files, err := Generate(cfg, "site.yaml", FrameworkPlain, DefaultLang(FrameworkPlain))
+	require.NoError(t, err)
+	assert.Equal(t, "gtag.html", files[0].Path)
+	assert.Contains(t, files[0].Content, `<script async src="https://www.googletagmanager.com/gtag/js?id=`+PlaceholderMeasurementID+`"></script>`)
+
+	assert.Equal(t, "analytics.js", files[1].Path)
+	helpers := files[1].Content
+	assert.Contains(t, helpers, " * @property {string} [plan_type] Plan Type\n")
+	assert.Contains(t, helpers, " * @param {EventParams & ValueParams} [params]\n */\nexport function trackPurchase(params = {}) {")
+	assert.NotContains(t, helpers, "interface")
+	assert.NotContains(t, helpers, "setUserProperties", "no user-scoped dimensions")
+}
+
+func TestGenerate_Astro(t *testing.T) {
+	files, err := Generate(testConfig(), "site.yaml", FrameworkAstro, DefaultLang(FrameworkAstro))
+	require.NoError(t, err)
+	assert.Equal(t, "src/components/GoogleAnalytics.astro", files[0].Path)
+	assert.Contains(t, files[0].Content, "<script is:inline>\nwindow.dataLayer")
+	assert.Equal(t, "src/lib/analytics.ts", files[1].Path)
+}
+
+func TestGenerate_RejectsUnknownFrameworkAndLang(t *testing.T) {
+	_, err := Generate(testConfig(), "site.yaml", "gatsby", LangTS)
+	assert.ErrorContains(t, err, `unknown framework "gatsby"`)
+	_, err = Generate(testConfig(), "site.yaml", FrameworkAstro, "coffee")
+	assert.ErrorContains(t, err, `unknown language "coffee"`)
+}
+
+func TestPascalCase(t *testing.T) {
+	assert.Equal(t, "GenerateLead", pascalCase("generate_lead"))
+	assert.Equal(t, "Purchase", pascalCase("purchase"))
+	assert.Equal(t, "FormStart2", pascalCase("form__start_2"))
+}
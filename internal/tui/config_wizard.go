@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
+)
+
+// ProjectAnswers holds the answers to the config wizard. Fields set before
+// RunConfigWizard are offered as defaults.
+type ProjectAnswers struct {
+	Name       string
+	PropertyID string
+	SiteURL    string
+	SiteType   string
+	Tier       string
+	OutputPath string
+}
+
+// RunConfigWizard asks for what a starter project config needs. siteTypes
+// are the choices for the site type.
+func RunConfigWizard(answers *ProjectAnswers, siteTypes []string) error {
+	welcomeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#6C5CE7")).
+		Padding(1, 2).
+		Margin(1, 0)
+	theme.Println(welcomeStyle.Render("📝 GA4 Manager Config Wizard\n\nLet's scaffold a config for your site!"))
+
+	typeOptions := make([]huh.Option[string], len(siteTypes))
+	for i, t := range siteTypes {
+		typeOptions[i] = huh.NewOption(t, t)
+	}
+	if answers.SiteType == "" && len(siteTypes) > 0 {
+		answers.SiteType = siteTypes[0]
+	}
+	if answers.Tier == "" {
+		answers.Tier = "standard"
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Project name").
+				Placeholder("My Site").
+				Value(&answers.Name).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("project name cannot be empty")
+					}
+					return nil
+				}),
+
+			huh.NewInput().
+				Title("GA4 property ID").
+				Description("Numeric ID from GA4 Admin > Property details (leave empty for Search Console only)").
+				Placeholder("123456789").
+				Value(&answers.PropertyID).
+				Validate(validatePropertyID),
+
+			huh.NewInput().
+				Title("Search Console site URL").
+				Description("https://example.com/ or sc-domain:example.com (leave empty for GA4 only)").
+				Placeholder("https://example.com/").
+				Value(&answers.SiteURL).
+				Validate(func(s string) error {
+					if s == "" && answers.PropertyID == "" {
+						return fmt.Errorf("enter a GA4 property ID, a site URL, or both")
+					}
+					return validateSiteURL(s)
+				}),
+		),
+
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Site type").
+				Description("Picks the starter conversions, dimensions and enhanced measurement").
+				Options(typeOptions...).
+				Value(&answers.SiteType),
+
+			huh.NewSelect[string]().
+				Title("GA4 tier").
+				Description("GA4 360 leaves room for more custom definitions").
+				Options(
+					huh.NewOption("Standard (free)", "standard"),
+					huh.NewOption("GA4 360", "360"),
+				).
+				Value(&answers.Tier),
+
+			huh.NewInput().
+				Title("Write config to").
+				Placeholder("configs/my-site.yaml").
+				Value(&answers.OutputPath),
+		),
+	)
+
+	return form.Run()
+}
+
+func validatePropertyID(s string) error {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("property ID must be numeric (not the G- measurement ID)")
+		}
+	}
+	return nil
+}
+
+func validateSiteURL(s string) error {
+	switch {
+	case s == "":
+		return nil
+	case strings.HasPrefix(s, "sc-domain:"):
+		if s == "sc-domain:" {
+			return fmt.Errorf("add the domain after sc-domain:")
+		}
+		return nil
+	case strings.HasPrefix(s, "https://"), strings.HasPrefix(s, "http://"):
+		return nil
+	}
+	return fmt.Errorf("site URL must start with https://, http:// or sc-domain:")
+}
@@ -0,0 +1,415 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DashboardData is what the dashboard shows for one project. The command
+// loads it; a pane whose data failed to load shows the error instead.
+type DashboardData struct {
+	Project string
+
+	// Property summary
+	PropertyID  string
+	Streams     []string // "WEB_DATA_STREAM example.com"
+	Conversions []string
+	Dimensions  []string
+	Metrics     []string
+	PropertyErr error
+
+	// Search Console clicks, oldest day first
+	SiteURL   string
+	Clicks    []DailyClicks
+	ClicksErr error
+
+	// Indexing issues of the last saved `gsc monitor run`; IssuesAsOf is
+	// zero when no run was saved.
+	Issues     []IndexingIssue
+	Inspected  int
+	IssuesAsOf time.Time
+	IssuesErr  error
+
+	// URL Inspection quota of the local tracker
+	QuotaUsed  int
+	QuotaLimit int
+	QuotaDate  string
+
+	LoadedAt time.Time
+}
+
+// DailyClicks is one day of Search Console performance.
+type DailyClicks struct {
+	Date        string
+	Clicks      int64
+	Impressions int64
+	Position    float64
+}
+
+// IndexingIssue is a monitored URL that is not indexed or has issues.
+type IndexingIssue struct {
+	URL           string
+	IndexStatus   string
+	CoverageState string
+	Issues        []string
+}
+
+// dashboardPane identifies a pane, in reading order of the 2×2 grid.
+type dashboardPane int
+
+const (
+	paneProperty dashboardPane = iota
+	paneClicks
+	paneIssues
+	paneQuota
+	paneCount
+)
+
+var paneTitles = [paneCount]string{"Property", "Search clicks", "Indexing issues", "Inspection quota"}
+
+// dashboardLoadedMsg carries freshly loaded data.
+type dashboardLoadedMsg DashboardData
+
+// DashboardModel is the Bubble Tea model of `ga4 tui`: four panes, one of
+// them focused, and a drill-down view of the focused pane.
+type DashboardModel struct {
+	load    func() DashboardData
+	data    DashboardData
+	loading bool
+
+	focus  dashboardPane
+	detail bool
+	scroll int
+
+	width  int
+	height int
+}
+
+// NewDashboardModel creates a dashboard that gets its data from load, on
+// start and on every refresh. load runs outside the UI loop.
+func NewDashboardModel(load func() DashboardData) DashboardModel {
+	return DashboardModel{load: load, loading: true, width: 100, height: 30}
+}
+
+// Init starts the first load.
+func (m DashboardModel) Init() tea.Cmd {
+	return m.loadCmd()
+}
+
+func (m DashboardModel) loadCmd() tea.Cmd {
+	load := m.load
+	return func() tea.Msg { return dashboardLoadedMsg(load()) }
+}
+
+// Update handles messages
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case dashboardLoadedMsg:
+		m.data = DashboardData(msg)
+		m.loading = false
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.detail {
+			return m.updateDetail(msg)
+		}
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "tab", "n":
+			m.focus = (m.focus + 1) % paneCount
+		case "shift+tab", "p":
+			m.focus = (m.focus + paneCount - 1) % paneCount
+		case "left", "h", "right", "l":
+			m.focus ^= 1
+		case "up", "k", "down", "j":
+			m.focus ^= 2
+		case "1", "2", "3", "4":
+			m.focus = dashboardPane(msg.String()[0] - '1')
+		case "enter", " ":
+			m.detail, m.scroll = true, 0
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.loadCmd()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m DashboardModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace", "enter":
+		m.detail = false
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j":
+		m.scroll = min(m.scroll+1, max(len(m.detailLines())-m.detailHeight(), 0))
+	case "pgup":
+		m.scroll = max(m.scroll-m.detailHeight(), 0)
+	case "pgdown":
+		m.scroll = min(m.scroll+m.detailHeight(), max(len(m.detailLines())-m.detailHeight(), 0))
+	}
+	return m, nil
+}
+
+// View renders the UI
+func (m DashboardModel) View() string {
+	header := titleStyle.Padding(0, 2).Render(m.headerText())
+	if m.detail {
+		return header + "\n" + m.detailView() + "\n" +
+			helpStyle.Render("↑/k ↓/j scroll • pgup/pgdown page • esc back • q quit")
+	}
+
+	paneWidth := max((m.width-4)/2, 30)
+	panes := make([]string, paneCount)
+	for p := range paneCount {
+		style := paneStyle.Width(paneWidth).Height(8)
+		if p == m.focus {
+			style = style.BorderForeground(accentColor)
+		}
+		title := paneTitleStyle.Render(fmt.Sprintf("%d %s", p+1, paneTitles[p]))
+		panes[p] = style.Render(title + "\n" + strings.Join(m.paneLines(p, paneWidth-2), "\n"))
+	}
+	grid := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, panes[paneProperty], panes[paneClicks]),
+		lipgloss.JoinHorizontal(lipgloss.Top, panes[paneIssues], panes[paneQuota]),
+	)
+	return header + "\n" + grid + "\n" +
+		helpStyle.Render("tab/arrows move • 1-4 jump • enter drill down • r refresh • q quit")
+}
+
+func (m DashboardModel) headerText() string {
+	text := "GA4 Manager dashboard"
+	if m.data.Project != "" {
+		text += " · " + m.data.Project
+	}
+	switch {
+	case m.loading:
+		text += " · loading…"
+	case !m.data.LoadedAt.IsZero():
+		text += " · " + m.data.LoadedAt.Format("15:04:05")
+	}
+	return text
+}
+
+// paneLines is the summary a pane shows in the grid.
+func (m DashboardModel) paneLines(p dashboardPane, width int) []string {
+	d := m.data
+	if m.loading && d.LoadedAt.IsZero() {
+		return []string{dimStyle.Render("loading…")}
+	}
+	switch p {
+	case paneProperty:
+		if d.PropertyID == "" {
+			return []string{dimStyle.Render("no analytics property in the config")}
+		}
+		if d.PropertyErr != nil {
+			return errorLines(d.PropertyErr, width)
+		}
+		return []string{
+			"properties/" + d.PropertyID,
+			fmt.Sprintf("%d data streams", len(d.Streams)),
+			fmt.Sprintf("%d conversions", len(d.Conversions)),
+			fmt.Sprintf("%d custom dimensions", len(d.Dimensions)),
+			fmt.Sprintf("%d custom metrics", len(d.Metrics)),
+		}
+
+	case paneClicks:
+		if d.SiteURL == "" {
+			return []string{dimStyle.Render("no search_console.site_url in the config")}
+		}
+		if d.ClicksErr != nil {
+			return errorLines(d.ClicksErr, width)
+		}
+		if len(d.Clicks) == 0 {
+			return []string{dimStyle.Render("no clicks in the period")}
+		}
+		clicks, impressions := clickTotals(d.Clicks)
+		values := make([]int64, len(d.Clicks))
+		for i, c := range d.Clicks {
+			values[i] = c.Clicks
+		}
+		return []string{
+			d.SiteURL,
+			accentStyle.Render(Sparkline(values, width)),
+			fmt.Sprintf("%s → %s", d.Clicks[0].Date, d.Clicks[len(d.Clicks)-1].Date),
+			fmt.Sprintf("%d clicks, %d impressions", clicks, impressions),
+		}
+
+	case paneIssues:
+		if d.SiteURL == "" {
+			return []string{dimStyle.Render("no search_console.site_url in the config")}
+		}
+		if d.IssuesErr != nil {
+			return errorLines(d.IssuesErr, width)
+		}
+		if d.IssuesAsOf.IsZero() {
+			return []string{dimStyle.Render("no saved monitor run"), dimStyle.Render("run: ga4 gsc monitor run --save")}
+		}
+		lines := []string{fmt.Sprintf("%d of %d URLs with issues", len(d.Issues), d.Inspected)}
+		for i, issue := range d.Issues {
+			if i == 4 {
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("… %d more", len(d.Issues)-i)))
+				break
+			}
+			lines = append(lines, truncate(issue.URL, width))
+		}
+		return append(lines, dimStyle.Render("as of "+d.IssuesAsOf.Format("2006-01-02 15:04")))
+
+	default:
+		if d.QuotaLimit == 0 {
+			return []string{dimStyle.Render("no search_console.site_url in the config")}
+		}
+		return []string{
+			fmt.Sprintf("%d / %d inspections", d.QuotaUsed, d.QuotaLimit),
+			quotaBar(d.QuotaUsed, d.QuotaLimit, width),
+			fmt.Sprintf("%d left on %s", max(d.QuotaLimit-d.QuotaUsed, 0), d.QuotaDate),
+		}
+	}
+}
+
+// detailLines is the drill-down of the focused pane.
+func (m DashboardModel) detailLines() []string {
+	d := m.data
+	var lines []string
+	switch m.focus {
+	case paneProperty:
+		if d.PropertyErr != nil || d.PropertyID == "" {
+			return m.paneLines(paneProperty, m.width)
+		}
+		section := func(title string, items []string) {
+			lines = append(lines, paneTitleStyle.Render(fmt.Sprintf("%s (%d)", title, len(items))))
+			for _, item := range items {
+				lines = append(lines, "  "+item)
+			}
+			lines = append(lines, "")
+		}
+		section("Data streams", d.Streams)
+		section("Conversions", d.Conversions)
+		section("Custom dimensions", d.Dimensions)
+		section("Custom metrics", d.Metrics)
+
+	case paneClicks:
+		if d.ClicksErr != nil || len(d.Clicks) == 0 {
+			return m.paneLines(paneClicks, m.width)
+		}
+		values := make([]int64, len(d.Clicks))
+		for i, c := range d.Clicks {
+			values[i] = c.Clicks
+		}
+		lines = append(lines, accentStyle.Render(Sparkline(values, m.width-4)), "",
+			paneTitleStyle.Render(fmt.Sprintf("%-12s %10s %12s %9s", "Date", "Clicks", "Impressions", "Position")))
+		for i := len(d.Clicks) - 1; i >= 0; i-- {
+			c := d.Clicks[i]
+			lines = append(lines, fmt.Sprintf("%-12s %10d %12d %9.1f", c.Date, c.Clicks, c.Impressions, c.Position))
+		}
+
+	case paneIssues:
+		if d.IssuesErr != nil || len(d.Issues) == 0 {
+			return m.paneLines(paneIssues, m.width)
+		}
+		for _, issue := range d.Issues {
+			lines = append(lines, issue.URL,
+				dimStyle.Render(fmt.Sprintf("  %s · %s", issue.IndexStatus, issue.CoverageState)))
+			if len(issue.Issues) > 0 {
+				lines = append(lines, dimStyle.Render("  "+strings.Join(issue.Issues, ", ")))
+			}
+		}
+
+	default:
+		lines = append(m.paneLines(paneQuota, m.width-4), "",
+			dimStyle.Render("Search Console allows a fixed number of URL inspections per site per day."),
+			dimStyle.Render("The count is kept locally and resets at midnight Pacific time."))
+	}
+	return lines
+}
+
+func (m DashboardModel) detailHeight() int {
+	return max(m.height-6, 5)
+}
+
+func (m DashboardModel) detailView() string {
+	lines := m.detailLines()
+	end := min(m.scroll+m.detailHeight(), len(lines))
+	title := paneTitleStyle.Render(paneTitles[m.focus])
+	if len(lines) > m.detailHeight() {
+		title += dimStyle.Render(fmt.Sprintf("  %d–%d of %d", m.scroll+1, end, len(lines)))
+	}
+	return title + "\n" + strings.Join(lines[min(m.scroll, end):end], "\n")
+}
+
+// sparkBlocks are the eight bar heights of a sparkline.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a one-line bar chart, scaled between their
+// minimum and maximum. Only the last width values are drawn.
+func Sparkline(values []int64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) * int64(len(sparkBlocks)-1) / (hi - lo))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// quotaBar draws used out of limit as a bar width cells wide.
+func quotaBar(used, limit, width int) string {
+	width = max(width, 1)
+	filled := min(used*width/max(limit, 1), width)
+	style := accentStyle
+	if used >= limit {
+		style = errorStyle
+	}
+	return style.Render(strings.Repeat("█", filled)) + dimStyle.Render(strings.Repeat("░", width-filled))
+}
+
+func clickTotals(days []DailyClicks) (clicks, impressions int64) {
+	for _, d := range days {
+		clicks += d.Clicks
+		impressions += d.Impressions
+	}
+	return clicks, impressions
+}
+
+func errorLines(err error, width int) []string {
+	return []string{errorStyle.Render("✗ " + truncate(err.Error(), width-2))}
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width < 2 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// RunDashboard runs the dashboard full screen until the user quits.
+func RunDashboard(load func() DashboardData) error {
+	_, err := tea.NewProgram(NewDashboardModel(load), tea.WithAltScreen()).Run()
+	return err
+}
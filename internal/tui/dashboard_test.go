@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", Sparkline([]int64{0, 5, 10}, 10))
+	assert.Equal(t, "▁▁", Sparkline([]int64{3, 3}, 10), "a flat series stays at the bottom")
+	assert.Equal(t, "▁█", Sparkline([]int64{100, 0, 10}, 2), "only the last width values are drawn")
+	assert.Empty(t, Sparkline(nil, 10))
+}
+
+// press sends key presses to m and returns the resulting model.
+func press(t *testing.T, m DashboardModel, keys ...string) DashboardModel {
+	t.Helper()
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		next, _ := m.Update(msg)
+		m = next.(DashboardModel)
+	}
+	return m
+}
+
+func loadedDashboard(t *testing.T, data DashboardData) DashboardModel {
+	t.Helper()
+	m := NewDashboardModel(func() DashboardData { return data })
+	msg := m.Init()()
+	next, _ := m.Update(msg)
+	return next.(DashboardModel)
+}
+
+func TestDashboard_Navigation(t *testing.T) {
+	m := loadedDashboard(t, DashboardData{LoadedAt: time.Now()})
+	require.False(t, m.loading)
+
+	assert.Equal(t, paneClicks, press(t, m, "tab").focus)
+	assert.Equal(t, paneIssues, press(t, m, "j").focus, "down moves to the pane below")
+	assert.Equal(t, paneQuota, press(t, m, "l", "j").focus)
+	assert.Equal(t, paneProperty, press(t, m, "tab", "tab", "tab", "tab").focus, "tab wraps around")
+	assert.Equal(t, paneIssues, press(t, m, "3").focus)
+
+	m = press(t, m, "2", "enter")
+	assert.True(t, m.detail)
+	m = press(t, m, "esc")
+	assert.False(t, m.detail)
+	assert.Equal(t, paneClicks, m.focus, "closing the drill-down keeps the focus")
+}
+
+func TestDashboard_Panes(t *testing.T) {
+	m := loadedDashboard(t, DashboardData{
+		PropertyID:  "123",
+		PropertyErr: errors.New("permission denied"),
+		SiteURL:     "sc-domain:example.com",
+		Clicks: []DailyClicks{
+			{Date: "2026-10-01", Clicks: 10, Impressions: 100},
+			{Date: "2026-10-02", Clicks: 30, Impressions: 300},
+		},
+		Issues:     []IndexingIssue{{URL: "https://example.com/a", IndexStatus: "FAIL", CoverageState: "Not found (404)"}},
+		Inspected:  12,
+		IssuesAsOf: time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC),
+		QuotaUsed:  1500,
+		QuotaLimit: 2000,
+		QuotaDate:  "2026-10-16",
+		LoadedAt:   time.Now(),
+	})
+
+	assert.Contains(t, m.paneLines(paneProperty, 40)[0], "permission denied", "a failed pane shows its error")
+	assert.Contains(t, m.paneLines(paneClicks, 40), "40 clicks, 400 impressions")
+	assert.Contains(t, m.paneLines(paneIssues, 40), "1 of 12 URLs with issues")
+	assert.Contains(t, m.paneLines(paneQuota, 40), "500 left on 2026-10-16")
+
+	m = press(t, m, "2", "enter")
+	lines := m.detailLines()
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[3], "2026-10-02", "the drill-down lists the latest day first")
+}
@@ -38,6 +38,12 @@ func NewMenuModel(version string) MenuModel {
 			Icon:        "📊",
 			Action:      "report",
 		},
+		{
+			Title:       "Dashboard",
+			Description: "Property, search clicks, indexing issues and quota at a glance",
+			Icon:        "📈",
+			Action:      "dashboard",
+		},
 		{
 			Title:       "Export Reports",
 			Description: "Export reports to JSON, CSV, or Markdown",
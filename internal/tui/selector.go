@@ -10,6 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"gopkg.in/yaml.v3"
+
+	"github.com/garbarok/ga4-manager/internal/config"
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // ProjectItem represents a selectable project configuration
@@ -54,7 +57,7 @@ func NewProjectSelector() (*ProjectSelectorModel, error) {
 	}
 
 	if len(items) == 0 {
-		return nil, fmt.Errorf("no project configurations found in configs/ directory")
+		return nil, fmt.Errorf("no project configurations found in %s/ directory", config.ConfigDir())
 	}
 
 	// Create list
@@ -120,7 +123,7 @@ func (m ProjectSelectorModel) GetChoice() string {
 	return m.choice
 }
 
-// scanProjects scans the configs/ directory for project YAML files
+// scanProjects scans the config directory for project YAML files
 func scanProjects() ([]list.Item, error) {
 	var items []list.Item
 
@@ -139,7 +142,7 @@ func scanProjects() ([]list.Item, error) {
 	})
 
 	// Check if configs directory exists
-	configsDir := "configs"
+	configsDir := config.ConfigDir()
 	if _, err := os.Stat(configsDir); os.IsNotExist(err) {
 		return items, nil // Return just the "All" option if no configs dir
 	}
@@ -164,7 +167,7 @@ func scanProjects() ([]list.Item, error) {
 		project, err := parseProjectFile(path)
 		if err != nil {
 			// Skip files that can't be parsed, but log the error
-			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+			theme.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
 			return nil
 		}
 
@@ -34,4 +34,18 @@ var (
 	helpStyle = lipgloss.NewStyle().
 			Foreground(dimColor).
 			Padding(1, 0)
+
+	// Dashboard styles
+	paneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(borderColor).
+			Padding(0, 1)
+
+	paneTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(primaryColor)
+
+	accentStyle = lipgloss.NewStyle().Foreground(accentColor)
+	dimStyle    = lipgloss.NewStyle().Foreground(dimColor)
+	errorStyle  = lipgloss.NewStyle().Foreground(primaryColor)
 )
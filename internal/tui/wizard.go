@@ -7,6 +7,8 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/garbarok/ga4-manager/internal/theme"
 )
 
 // CredentialConfig holds the credential configuration
@@ -31,7 +33,7 @@ func RunSetupWizard() (*CredentialConfig, error) {
 		Margin(1, 0)
 
 	welcome := welcomeStyle.Render("🚀 GA4 Manager Setup Wizard\n\nLet's configure your Google Cloud credentials!")
-	fmt.Println(welcome)
+	theme.Println(welcome)
 
 	// Create the form
 	form := huh.NewForm(
@@ -170,37 +172,37 @@ func PrintShellInstructions(config *CredentialConfig) {
 		Foreground(lipgloss.Color("#95A3A4")).
 		Italic(true)
 
-	fmt.Println(headerStyle.Render("\n📝 Shell Configuration"))
+	theme.Println(headerStyle.Render("\n📝 Shell Configuration"))
 
 	switch config.ShellType {
 	case "bash":
-		fmt.Println(tipStyle.Render("Add these lines to ~/.bashrc or ~/.bash_profile:"))
-		fmt.Println(codeStyle.Render(fmt.Sprintf(`
+		theme.Println(tipStyle.Render("Add these lines to ~/.bashrc or ~/.bash_profile:"))
+		theme.Println(codeStyle.Render(fmt.Sprintf(`
 export GOOGLE_APPLICATION_CREDENTIALS="%s"
 export GOOGLE_CLOUD_PROJECT="%s"
 `, absPath, config.ProjectID)))
-		fmt.Println(tipStyle.Render("Then run: source ~/.bashrc"))
+		theme.Println(tipStyle.Render("Then run: source ~/.bashrc"))
 
 	case "zsh":
-		fmt.Println(tipStyle.Render("Add these lines to ~/.zshrc:"))
-		fmt.Println(codeStyle.Render(fmt.Sprintf(`
+		theme.Println(tipStyle.Render("Add these lines to ~/.zshrc:"))
+		theme.Println(codeStyle.Render(fmt.Sprintf(`
 export GOOGLE_APPLICATION_CREDENTIALS="%s"
 export GOOGLE_CLOUD_PROJECT="%s"
 `, absPath, config.ProjectID)))
-		fmt.Println(tipStyle.Render("Then run: source ~/.zshrc"))
+		theme.Println(tipStyle.Render("Then run: source ~/.zshrc"))
 
 	case "fish":
-		fmt.Println(tipStyle.Render("Add these lines to ~/.config/fish/config.fish:"))
-		fmt.Println(codeStyle.Render(fmt.Sprintf(`
+		theme.Println(tipStyle.Render("Add these lines to ~/.config/fish/config.fish:"))
+		theme.Println(codeStyle.Render(fmt.Sprintf(`
 set -gx GOOGLE_APPLICATION_CREDENTIALS %s
 set -gx GOOGLE_CLOUD_PROJECT %s
 `, absPath, config.ProjectID)))
-		fmt.Println(tipStyle.Render("Then run: source ~/.config/fish/config.fish"))
+		theme.Println(tipStyle.Render("Then run: source ~/.config/fish/config.fish"))
 	}
 
-	fmt.Println(headerStyle.Render("\n✅ Next Steps"))
-	fmt.Println("  1. Add the environment variables to your shell config (see above)")
-	fmt.Println("  2. Source your shell config or restart your terminal")
-	fmt.Println("  3. Run: ga4 validate --all")
-	fmt.Println("  4. Start using: ga4")
+	theme.Println(headerStyle.Render("\n✅ Next Steps"))
+	theme.Println("  1. Add the environment variables to your shell config (see above)")
+	theme.Println("  2. Source your shell config or restart your terminal")
+	theme.Println("  3. Run: ga4 validate --all")
+	theme.Println("  4. Start using: ga4")
 }
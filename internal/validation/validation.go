@@ -22,6 +22,9 @@ var EventNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,39}$`)
 // ParameterNameRegex matches valid GA4 parameter name format
 var ParameterNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,39}$`)
 
+// CurrencyCodeRegex matches an ISO 4217 currency code (three uppercase letters)
+var CurrencyCodeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
 // ValidatePropertyID validates a GA4 property ID
 func ValidatePropertyID(propertyID string) error {
 	if propertyID == "" {
@@ -135,6 +138,17 @@ func ValidateCountingMethod(method string) error {
 	return nil
 }
 
+// ValidateCurrencyCode validates an ISO 4217 currency code such as EUR
+func ValidateCurrencyCode(code string) error {
+	if code == "" {
+		return fmt.Errorf("currency code cannot be empty")
+	}
+	if !CurrencyCodeRegex.MatchString(code) {
+		return fmt.Errorf("invalid currency code: %s (must be a 3-letter ISO 4217 code such as EUR)", code)
+	}
+	return nil
+}
+
 // ValidateScope validates a GA4 dimension scope
 func ValidateScope(scope string) error {
 	scope = normalizeInput(scope)
@@ -126,6 +126,28 @@ func TestValidateCountingMethod(t *testing.T) {
 	}
 }
 
+func TestValidateCurrencyCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantError bool
+	}{
+		{"Valid EUR", "EUR", false},
+		{"Lowercase", "eur", true},
+		{"Too long", "EURO", true},
+		{"Empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCurrencyCode(tt.code)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateCurrencyCode() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
 func TestValidateScope(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -75,13 +75,15 @@ export function native<S extends z.ZodType>(def: {
   return { kind: 'native', ...def } as NativeToolSpec
 }
 
-// Framework convention (see CONTEXT.md, docs/BACKLOG.md "Implementation notes"):
+// CLI exit code contract (see internal/exitcode and the README):
 //   exit 0 — clean run, no findings
-//   exit 1 — command failed (API error, malformed config, etc.)
-//   exit 2 — success with findings (e.g. cannibalising queries detected)
+//   exit 1 — validation error (bad flags, malformed config, etc.)
+//   exit 2 — Google API error
+//   exit 3 — quota exhausted
+//   exit 4 — success with findings (e.g. cannibalising queries detected)
 //
-// Both 0 and 2 are success at the MCP dispatch layer; the parsed JSON
-// envelope tells the caller whether findings exist. Treating 2 as failure
+// Both 0 and 4 are success at the MCP dispatch layer; the parsed JSON
+// envelope tells the caller whether findings exist. Treating 4 as failure
 // would swallow stdout — the very report the tool exists to surface.
-const SUCCESS_EXIT_CODES = new Set([0, 2])
+const SUCCESS_EXIT_CODES = new Set([0, 4])
 export const isSuccessExit = (code: number): boolean => SUCCESS_EXIT_CODES.has(code)
@@ -1,5 +1,10 @@
 import { describe, it, expect } from 'vitest'
-import { computeTrafficDiff, type GscRow } from './compute-traffic-diff.js'
+import {
+  computeSegmentDeltas,
+  computeTrafficDiff,
+  findSegmentDivergences,
+  type GscRow,
+} from './compute-traffic-diff.js'
 
 function row(
   url: string,
@@ -340,3 +345,58 @@ describe('computeTrafficDiff', () => {
     expect(summary.urls_only_in_b).toBe(1)
   })
 })
+
+describe('computeSegmentDeltas', () => {
+  it('joins segments from both periods, counting a missing one as zero', () => {
+    const deltas = computeSegmentDeltas(
+      [row('MOBILE', 100, 2000, 0.05, 5), row('DESKTOP', 50, 1000, 0.05, 3)],
+      [row('MOBILE', 60, 1500, 0.04, 6), row('TABLET', 5, 100, 0.05, 4)],
+    )
+    expect(deltas.map((d) => d.segment)).toEqual(['MOBILE', 'DESKTOP', 'TABLET'])
+    expect(deltas[0]).toMatchObject({
+      clicks_delta: -40,
+      clicks_pct: -40,
+      impressions_delta: -500,
+      ctr_a: 0.05,
+      ctr_b: 0.04,
+      position_delta: 1,
+    })
+    expect(deltas[1]).toMatchObject({ clicks_b: 0, clicks_pct: -100, ctr_b: 0 })
+    expect(deltas[2]).toMatchObject({ clicks_a: 0, clicks_pct: 0 })
+  })
+})
+
+describe('findSegmentDivergences', () => {
+  it('flags a device that dropped while the others held steady', () => {
+    const deltas = computeSegmentDeltas(
+      [row('MOBILE', 100), row('DESKTOP', 100), row('TABLET', 10)],
+      [row('MOBILE', 60), row('DESKTOP', 102), row('TABLET', 10)],
+    )
+    const divergences = findSegmentDivergences(deltas, 'device')
+    expect(divergences).toHaveLength(1)
+    expect(divergences[0]).toMatchObject({ segment: 'MOBILE', clicks_pct: -40, rest_clicks_pct: 1.8 })
+    expect(divergences[0].message).toContain(
+      'mobile clicks fell 40% while desktop, tablet held steady (+1.8%)',
+    )
+    expect(divergences[0].message).toContain('Core Web Vitals')
+  })
+
+  it('does not flag a drop shared by every segment', () => {
+    const deltas = computeSegmentDeltas(
+      [row('MOBILE', 100), row('DESKTOP', 100)],
+      [row('MOBILE', 60), row('DESKTOP', 70)],
+    )
+    expect(findSegmentDivergences(deltas, 'device')).toEqual([])
+  })
+
+  it('names other countries and ignores segments below min clicks', () => {
+    const deltas = computeSegmentDeltas(
+      [row('esp', 200), row('mex', 100), row('arg', 5)],
+      [row('esp', 100), row('mex', 130), row('arg', 1)],
+    )
+    const divergences = findSegmentDivergences(deltas, 'country', 10)
+    expect(divergences.map((d) => d.segment)).toEqual(['esp'])
+    expect(divergences[0].message).toContain('esp clicks fell 50% while other countries grew')
+    expect(divergences[0].message).toContain('hreflang')
+  })
+})
@@ -194,3 +194,157 @@ export function computeTrafficDiff(
     normalize_mode_used: normalize,
   }
 }
+
+// ============================================================================
+// Segment deltas (device / country)
+// ============================================================================
+
+export type SegmentDimension = 'device' | 'country'
+
+export interface SegmentDelta {
+  segment: string
+  clicks_a: number
+  clicks_b: number
+  clicks_delta: number
+  clicks_pct: number
+  impressions_a: number
+  impressions_b: number
+  impressions_delta: number
+  ctr_a: number
+  ctr_b: number
+  ctr_delta: number
+  position_a: number
+  position_b: number
+  position_delta: number
+}
+
+export interface SegmentDivergence {
+  segment: string
+  clicks_pct: number
+  /** Click change of every other segment combined. */
+  rest_clicks_pct: number
+  message: string
+}
+
+/** A segment diverges when its clicks fall at least this much (percent)... */
+export const SEGMENT_DROP_PCT = -20
+/** ...while the other segments combined moved no further down than this. */
+export const SEGMENT_STEADY_PCT = -5
+
+interface SegmentTotals {
+  clicks: number
+  impressions: number
+  weightedPosition: number
+}
+
+function totalsBySegment(rows: GscRow[]): Map<string, SegmentTotals> {
+  const map = new Map<string, SegmentTotals>()
+  for (const row of rows) {
+    const segment = row.keys[0] ?? ''
+    const t = map.get(segment) ?? { clicks: 0, impressions: 0, weightedPosition: 0 }
+    t.clicks += row.clicks
+    t.impressions += row.impressions
+    t.weightedPosition += row.position * row.impressions
+    map.set(segment, t)
+  }
+  return map
+}
+
+function pct(a: number, b: number): number {
+  return a > 0 ? Math.round(((b - a) / a) * 1000) / 10 : 0
+}
+
+/**
+ * Full-outer-join two GSC result sets queried by a single segment dimension
+ * (device or country) and compute per-segment deltas. A segment missing from
+ * one period counts as zero there. CTR is clicks over impressions and position
+ * is impression-weighted, as Search Console aggregates them. Segments are
+ * ordered by period A clicks, largest first.
+ */
+export function computeSegmentDeltas(rowsA: GscRow[], rowsB: GscRow[]): SegmentDelta[] {
+  const mapA = totalsBySegment(rowsA)
+  const mapB = totalsBySegment(rowsB)
+  const empty: SegmentTotals = { clicks: 0, impressions: 0, weightedPosition: 0 }
+
+  const segments = new Set([...mapA.keys(), ...mapB.keys()])
+  const deltas: SegmentDelta[] = []
+  for (const segment of segments) {
+    const a = mapA.get(segment) ?? empty
+    const b = mapB.get(segment) ?? empty
+    const ctr_a = a.impressions > 0 ? a.clicks / a.impressions : 0
+    const ctr_b = b.impressions > 0 ? b.clicks / b.impressions : 0
+    const position_a = a.impressions > 0 ? a.weightedPosition / a.impressions : 0
+    const position_b = b.impressions > 0 ? b.weightedPosition / b.impressions : 0
+    deltas.push({
+      segment,
+      clicks_a: a.clicks,
+      clicks_b: b.clicks,
+      clicks_delta: b.clicks - a.clicks,
+      clicks_pct: pct(a.clicks, b.clicks),
+      impressions_a: a.impressions,
+      impressions_b: b.impressions,
+      impressions_delta: b.impressions - a.impressions,
+      ctr_a: Math.round(ctr_a * 10000) / 10000,
+      ctr_b: Math.round(ctr_b * 10000) / 10000,
+      ctr_delta: Math.round((ctr_b - ctr_a) * 10000) / 10000,
+      position_a: Math.round(position_a * 10) / 10,
+      position_b: Math.round(position_b * 10) / 10,
+      position_delta: Math.round((position_b - position_a) * 10) / 10,
+    })
+  }
+  deltas.sort((x, y) => y.clicks_a - x.clicks_a || x.segment.localeCompare(y.segment))
+  return deltas
+}
+
+const divergenceHints: Record<SegmentDimension, string> = {
+  device:
+    'points to a Core Web Vitals or mobile UX regression on that device rather than a ranking loss',
+  country:
+    'points to a localisation, hreflang or regional SERP change rather than a site-wide ranking loss',
+}
+
+/**
+ * Find segments whose clicks fell by SEGMENT_DROP_PCT or more while every
+ * other segment combined held steady (fell no further than
+ * SEGMENT_STEADY_PCT, or grew), e.g. "mobile clicks fell 40% while desktop,
+ * tablet held steady (+2%)". A drop shared by all segments is a ranking or
+ * demand change and is not reported. Segments with fewer than minClicksA
+ * period A clicks are ignored as noise.
+ */
+export function findSegmentDivergences(
+  deltas: SegmentDelta[],
+  dimension: SegmentDimension,
+  minClicksA = 0,
+): SegmentDivergence[] {
+  const totalA = deltas.reduce((sum, d) => sum + d.clicks_a, 0)
+  const totalB = deltas.reduce((sum, d) => sum + d.clicks_b, 0)
+
+  const divergences: SegmentDivergence[] = []
+  for (const d of deltas) {
+    if (d.clicks_a === 0 || d.clicks_a < minClicksA || d.clicks_pct > SEGMENT_DROP_PCT) continue
+    const restA = totalA - d.clicks_a
+    const restB = totalB - d.clicks_b
+    if (restA === 0) continue
+    const rest_clicks_pct = pct(restA, restB)
+    if (rest_clicks_pct < SEGMENT_STEADY_PCT) continue
+
+    const others =
+      dimension === 'device'
+        ? deltas
+            .filter((o) => o.segment !== d.segment && o.clicks_a > 0)
+            .map((o) => o.segment.toLowerCase())
+            .join(', ')
+        : 'other countries'
+    const held = Math.abs(rest_clicks_pct) <= -SEGMENT_STEADY_PCT ? 'held steady' : 'grew'
+    const sign = rest_clicks_pct >= 0 ? '+' : ''
+    divergences.push({
+      segment: d.segment,
+      clicks_pct: d.clicks_pct,
+      rest_clicks_pct,
+      message:
+        `${d.segment.toLowerCase()} clicks fell ${Math.abs(d.clicks_pct)}% while ${others} ` +
+        `${held} (${sign}${rest_clicks_pct}%); ${divergenceHints[dimension]}`,
+    })
+  }
+  return divergences
+}
@@ -100,7 +100,7 @@ export function parseHealthOutput(stdout: string): HealthOutput {
 export const gscHealthTool = {
   name: 'gsc_health',
   description:
-    "Weekly index-health report. Inspects every URL declared under search_console.url_inspection.priority_urls in the config file, diffs each URL's coverage state against the prior snapshot stored at .ga4-state/health.<site>.json (per ADR-0005), and surfaces regressions, recoveries, and first-time baselines. Designed to run on a weekly cron so noindex bugs, canonical mismatches, mobile-usability regressions, and rich-result failures are caught within days. Silent on all-green: zero regressions → empty results array; ≥1 regression → results populated and the underlying CLI exits 2. Each result carries the field-level diff plus the full current state of the URL so an LLM consumer can triage without re-inspecting. Quota cost: one URL Inspection request per priority URL per run.",
+    "Weekly index-health report. Inspects every URL declared under search_console.url_inspection.priority_urls in the config file, diffs each URL's coverage state against the prior snapshot stored at .ga4-state/health.<site>.json (per ADR-0005), and surfaces regressions, recoveries, and first-time baselines. Designed to run on a weekly cron so noindex bugs, canonical mismatches, mobile-usability regressions, and rich-result failures are caught within days. Silent on all-green: zero regressions → empty results array; ≥1 regression → results populated and the underlying CLI exits 4. Each result carries the field-level diff plus the full current state of the URL so an LLM consumer can triage without re-inspecting. Quota cost: one URL Inspection request per priority URL per run.",
   inputSchema: {
     type: 'object',
     required: ['config'],
@@ -472,6 +472,90 @@ describe('runGscTrafficCompare', () => {
   })
 })
 
+// ============================================================================
+// segment_by
+// ============================================================================
+
+describe('runGscTrafficCompare — segment_by', () => {
+  beforeEach(() => {
+    vi.resetAllMocks()
+  })
+
+  // Answers page queries with one unchanged page, and device queries with
+  // the rows for the period the request's startDate falls in.
+  function segmentFetch(deviceA: unknown[], deviceB: unknown[]) {
+    return vi.fn().mockImplementation((_url: string, options: RequestInit) => {
+      const body = JSON.parse(options.body as string) as { startDate: string; dimensions: string[] }
+      const periodA = body.startDate === baseInput.period_a.start
+      const rows =
+        body.dimensions[0] === 'device'
+          ? periodA
+            ? deviceA
+            : deviceB
+          : [{ keys: ['/page-a'], clicks: 100, impressions: 2000, ctr: 0.05, position: 3 }]
+      return Promise.resolve({ ok: true, json: () => Promise.resolve({ rows }) })
+    })
+  }
+
+  it('adds device deltas and divergences', async () => {
+    const mockFetch = segmentFetch(
+      [
+        { keys: ['MOBILE'], clicks: 100, impressions: 2000, ctr: 0.05, position: 5 },
+        { keys: ['DESKTOP'], clicks: 100, impressions: 1000, ctr: 0.1, position: 3 },
+      ],
+      [
+        { keys: ['MOBILE'], clicks: 60, impressions: 2000, ctr: 0.03, position: 5 },
+        { keys: ['DESKTOP'], clicks: 99, impressions: 1000, ctr: 0.099, position: 3 },
+      ],
+    )
+    vi.stubGlobal('fetch', mockFetch)
+
+    const input = gscTrafficCompareInputSchema.parse({ ...baseInput, segment_by: 'device' })
+    const result = await runGscTrafficCompare(input)
+
+    expect(mockFetch).toHaveBeenCalledTimes(4)
+    expect(result.success).toBe(true)
+    if (result.success) {
+      expect(result.segments?.dimension).toBe('device')
+      expect(result.segments?.rows.map((r) => r.segment)).toEqual(['DESKTOP', 'MOBILE'])
+      expect(result.segments?.divergences).toHaveLength(1)
+      expect(result.segments?.divergences[0].message).toContain('mobile clicks fell 40% while desktop held steady')
+    }
+  })
+
+  it('omits segments without segment_by', async () => {
+    const mockFetch = segmentFetch([], [])
+    vi.stubGlobal('fetch', mockFetch)
+
+    const result = await runGscTrafficCompare(gscTrafficCompareInputSchema.parse(baseInput))
+
+    expect(mockFetch).toHaveBeenCalledTimes(2)
+    expect(result.success).toBe(true)
+    if (result.success) {
+      expect(result).not.toHaveProperty('segments')
+    }
+  })
+
+  it('keeps the per-URL result with a warning when a segment query fails', async () => {
+    vi.stubGlobal(
+      'fetch',
+      vi.fn()
+        .mockResolvedValueOnce({ ok: true, json: () => Promise.resolve({ rows: [] }) })
+        .mockResolvedValueOnce({ ok: true, json: () => Promise.resolve({ rows: [] }) })
+        .mockResolvedValue({ ok: false, status: 500, text: () => Promise.resolve('Server Error') }),
+    )
+
+    const input = gscTrafficCompareInputSchema.parse({ ...baseInput, segment_by: 'country' })
+    const result = await runGscTrafficCompare(input)
+
+    expect(result.success).toBe(true)
+    if (result.success) {
+      expect(result.segments).toBeUndefined()
+      expect(result.warnings.some((w) => w.startsWith('country comparison skipped'))).toBe(true)
+    }
+  })
+})
+
 // ============================================================================
 // Date validation + warnings
 // ============================================================================
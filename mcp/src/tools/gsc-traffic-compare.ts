@@ -10,15 +10,20 @@ import {
 } from '../utils/errors.js'
 import { normalizeGscSite } from '../utils/url-normalize.js'
 import {
+  computeSegmentDeltas,
   computeTrafficDiff,
+  findSegmentDivergences,
   type GscRow,
+  type SegmentDelta,
+  type SegmentDimension,
+  type SegmentDivergence,
   type TrafficDiff,
   type TrafficDiffSummary,
   type TrafficTail,
 } from './compute-traffic-diff.js'
 
 // Re-export for consumers that need the diff types
-export type { TrafficDiff, TrafficDiffSummary, TrafficTail }
+export type { SegmentDelta, SegmentDivergence, TrafficDiff, TrafficDiffSummary, TrafficTail }
 
 // ============================================================================
 // Input Schema
@@ -84,6 +89,12 @@ export const gscTrafficCompareInputSchema = z.object({
     .describe(
       'URL normalization before inner-join: "none" (no change), "minimal" (strip trailing slash, lowercase host, default), "aggressive" (minimal + drop www., force https://, drop query string)',
     ),
+  segment_by: z
+    .enum(['device', 'country'])
+    .optional()
+    .describe(
+      'Also compare totals per device or country and flag segments that dropped while the rest held steady (2 extra GSC requests)',
+    ),
 })
 
 export type GscTrafficCompareInput = z.infer<typeof gscTrafficCompareInputSchema>
@@ -106,6 +117,11 @@ export type GscTrafficCompareResult =
       gains_tail: TrafficTail
       unchanged: number
       normalize_mode_used: string
+      segments?: {
+        dimension: SegmentDimension
+        rows: SegmentDelta[]
+        divergences: SegmentDivergence[]
+      }
     }
   | ToolFailureResult
 
@@ -180,8 +196,18 @@ function periodDays(start: string, end: string): number {
 export async function runGscTrafficCompare(
   input: GscTrafficCompareInput,
 ): Promise<GscTrafficCompareResult> {
-  const { site: rawSite, period_a, period_b, dimensions, fetch_limit, output_limit, min_clicks_a, sort_by, normalize } =
-    input
+  const {
+    site: rawSite,
+    period_a,
+    period_b,
+    dimensions,
+    fetch_limit,
+    output_limit,
+    min_clicks_a,
+    sort_by,
+    normalize,
+    segment_by,
+  } = input
 
   // ── Input validation ────────────────────────────────────────────────────
 
@@ -290,6 +316,32 @@ export async function runGscTrafficCompare(
       output_limit,
     })
 
+  // ── Segment totals (device / country) ─────────────────────────────────────
+  // Queried on the segment dimension alone so the totals are exact rather
+  // than summed from a row-limited per-URL breakdown. A failure here keeps
+  // the per-URL result and is reported as a warning.
+
+  let segments: { dimension: SegmentDimension; rows: SegmentDelta[]; divergences: SegmentDivergence[] } | undefined
+  if (segment_by !== undefined) {
+    const [segA, segB] = await Promise.allSettled([
+      querySearchAnalytics(site, period_a.start, period_a.end, [segment_by], fetch_limit),
+      querySearchAnalytics(site, period_b.start, period_b.end, [segment_by], fetch_limit),
+    ])
+    if (segA.status === 'fulfilled' && segB.status === 'fulfilled') {
+      const rows = computeSegmentDeltas(segA.value, segB.value)
+      segments = {
+        dimension: segment_by,
+        rows: rows.slice(0, output_limit),
+        divergences: findSegmentDivergences(rows, segment_by, min_clicks_a),
+      }
+    } else {
+      const reason = segA.status === 'rejected' ? segA.reason : (segB as PromiseRejectedResult).reason
+      warnings.push(
+        `${segment_by} comparison skipped: ${reason instanceof Error ? reason.message : String(reason)}`,
+      )
+    }
+  }
+
   return {
     success: true,
     warnings,
@@ -303,6 +355,7 @@ export async function runGscTrafficCompare(
     gains_tail,
     unchanged,
     normalize_mode_used,
+    ...(segments !== undefined ? { segments } : {}),
   }
 }
 
@@ -321,7 +374,9 @@ export const gscTrafficCompareTool = {
     'sort_by (clicks_abs|clicks_pct|impressions_abs, default clicks_abs), ' +
     'min_clicks_a (filter low-traffic URLs before sort, default 0). ' +
     'Tail summaries (drops_tail, gains_tail) report count + total_clicks_delta + 5-row sample for rows beyond output_limit. ' +
-    'Makes 2 GSC requests per call (one per period). ' +
+    'segment_by (device|country) adds per-segment totals and divergences such as ' +
+    '"mobile clicks fell 40% while desktop held steady", which point to a Core Web Vitals or mobile UX regression rather than a ranking loss. ' +
+    'Makes 2 GSC requests per call (one per period), 4 with segment_by. ' +
     'GSC quota is 2000 requests/day.',
   inputSchema: {
     type: 'object',
@@ -391,6 +446,12 @@ export const gscTrafficCompareTool = {
           'URL normalization mode before inner-join: "none" (no change), "minimal" (strip trailing slash + lowercase host, default), "aggressive" (minimal + drop www. + force https:// + drop query string)',
         default: 'minimal',
       },
+      segment_by: {
+        type: 'string',
+        enum: ['device', 'country'],
+        description:
+          'Also compare totals per device or country and flag segments whose clicks fell 20%+ while the rest held steady (2 extra GSC requests)',
+      },
     },
   },
   annotations: { title: 'Compare Search Console traffic', readOnlyHint: true },